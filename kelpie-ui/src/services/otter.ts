@@ -77,6 +77,21 @@ export interface Proposal {
   quorum_met: boolean;
 }
 
+export type OtterEventType =
+  | 'proposal.created'
+  | 'proposal.closed'
+  | 'vote.cast'
+  | 'rule.adopted'
+  | 'member.revoked'
+  | 'memory.created'
+  | 'plugin.message';
+
+export interface OtterEvent {
+  type: OtterEventType;
+  timestamp: string;
+  data?: any;
+}
+
 export const otterService = {
   // Bootstrap auth for instances with no passphrase
   async bootstrapAuth(): Promise<boolean> {
@@ -138,11 +153,18 @@ export const otterService = {
   },
 
   // Governance
-  async getRules(): Promise<Record<string, Rule>> {
+  // Active rules keyed by raft ID, then scope
+  async getRules(): Promise<Record<string, Record<string, Rule>>> {
     const response = await api.get('/api/v1/governance/rules');
     return response.data;
   },
 
+  // Active rules of one raft, keyed by scope
+  async getRaftRules(raftId: string): Promise<Record<string, Rule>> {
+    const response = await api.get(`/api/v1/governance/rules?raft_id=${encodeURIComponent(raftId)}`);
+    return response.data;
+  },
+
   async proposeRule(scope: string, body: string, proposedBy: string, baseRuleId?: string): Promise<Proposal> {
     const response = await api.post('/api/v1/governance/rules', {
       scope,
@@ -166,6 +188,29 @@ export const otterService = {
     return response.data;
   },
 
+  // Real-time events (returns a function that closes the stream)
+  subscribeToEvents(onEvent: (event: OtterEvent) => void, types: OtterEventType[] = []): () => void {
+    const url = new URL('/api/v1/events', API_URL);
+    url.protocol = url.protocol === 'https:' ? 'wss:' : 'ws:';
+    const token = localStorage.getItem(AUTH_TOKEN_KEY);
+    if (token) {
+      url.searchParams.set('token', token);
+    }
+    if (types.length > 0) {
+      url.searchParams.set('types', types.join(','));
+    }
+
+    const socket = new WebSocket(url.toString());
+    socket.onmessage = (message) => {
+      try {
+        onEvent(JSON.parse(message.data));
+      } catch {
+        // Ignore malformed frames
+      }
+    };
+    return () => socket.close();
+  },
+
   // Health check
   async healthCheck(): Promise<boolean> {
     try {
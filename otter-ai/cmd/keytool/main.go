@@ -1,11 +1,14 @@
 package main
 
 import (
+	"context"
 	"encoding/hex"
 	"fmt"
 	"os"
+	"time"
 
 	"otter-ai/internal/governance"
+	"otter-ai/internal/vectordb"
 )
 
 func main() {
@@ -16,6 +19,12 @@ func main() {
 		fmt.Println("  generate <data-dir>    Generate new key pair")
 		fmt.Println("  show <data-dir>        Show public key")
 		fmt.Println("  export <data-dir>      Export public key as hex")
+		fmt.Println("  invite <data-dir> <otter-id> <raft-id> [ttl] [invitee-id]")
+		fmt.Println("                         Sign an invite token to a raft")
+		fmt.Println("  rotate <data-dir> <otter-id>")
+		fmt.Println("                         Replace the signing key, vouched for by the old one")
+		fmt.Println("  verify-audit <data-dir> [db-path]")
+		fmt.Println("                         Verify the audit log's hash chain and signatures")
 		os.Exit(1)
 	}
 
@@ -46,6 +55,46 @@ func main() {
 		dataDir := os.Args[2]
 		exportPublicKey(dataDir)
 
+	case "invite":
+		if len(os.Args) < 5 {
+			fmt.Println("Usage: keytool invite <data-dir> <otter-id> <raft-id> [ttl] [invitee-id]")
+			os.Exit(1)
+		}
+		var ttl time.Duration
+		if len(os.Args) > 5 {
+			var err error
+			if ttl, err = time.ParseDuration(os.Args[5]); err != nil {
+				fmt.Printf("Invalid ttl %q: %v\n", os.Args[5], err)
+				os.Exit(1)
+			}
+		}
+		var inviteeID string
+		if len(os.Args) > 6 {
+			inviteeID = os.Args[6]
+		}
+		createInvite(os.Args[2], os.Args[3], os.Args[4], inviteeID, ttl)
+
+	case "rotate":
+		if len(os.Args) < 4 {
+			fmt.Println("Usage: keytool rotate <data-dir> <otter-id>")
+			os.Exit(1)
+		}
+		rotateKeys(os.Args[2], os.Args[3])
+
+	case "verify-audit":
+		if len(os.Args) < 3 {
+			fmt.Println("Usage: keytool verify-audit <data-dir> [db-path]")
+			os.Exit(1)
+		}
+		dbPath := os.Getenv("OTTER_DB_PATH")
+		if len(os.Args) > 3 {
+			dbPath = os.Args[3]
+		}
+		if dbPath == "" {
+			dbPath = "/data/otter.db"
+		}
+		verifyAudit(os.Args[2], dbPath)
+
 	default:
 		fmt.Printf("Unknown command: %s\n", command)
 		os.Exit(1)
@@ -61,7 +110,8 @@ func generateKeys(dataDir string) {
 
 	fmt.Println("✓ New key pair generated")
 	fmt.Printf("Public Key: %s\n", governance.ExportPublicKey(cs))
-	fmt.Printf("Stored in: %s/otter.key\n", dataDir)
+	fmt.Printf("Signing Key: %s\n", governance.ExportSigningPublicKey(cs))
+	fmt.Printf("Stored in: %s/%s and %s/%s\n", dataDir, governance.KeyFile, dataDir, governance.SigningKeyFile)
 }
 
 func showPublicKey(dataDir string) {
@@ -76,6 +126,9 @@ func showPublicKey(dataDir string) {
 	fmt.Println(hex.EncodeToString(pubKeyBytes))
 	fmt.Println("")
 	fmt.Printf("Length: %d bytes\n", len(pubKeyBytes))
+	fmt.Println("")
+	fmt.Println("Signing Key (Ed25519, hex):")
+	fmt.Println(governance.ExportSigningPublicKey(cs))
 }
 
 func exportPublicKey(dataDir string) {
@@ -87,3 +140,66 @@ func exportPublicKey(dataDir string) {
 
 	fmt.Println(governance.ExportPublicKey(cs))
 }
+
+func createInvite(dataDir, otterID, raftID, inviteeID string, ttl time.Duration) {
+	cs, err := governance.LoadOrGenerateKeys(dataDir)
+	if err != nil {
+		fmt.Printf("Error loading keys: %v\n", err)
+		os.Exit(1)
+	}
+
+	invite, err := governance.NewInvite(cs, raftID, otterID, inviteeID, ttl)
+	if err != nil {
+		fmt.Printf("Error creating invite: %v\n", err)
+		os.Exit(1)
+	}
+	token, err := invite.Token()
+	if err != nil {
+		fmt.Printf("Error encoding invite: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Invite to raft %s from %s, expires %s\n", raftID, otterID, invite.ExpiresAt.Format(time.RFC3339))
+	fmt.Println(token)
+}
+
+func rotateKeys(dataDir, otterID string) {
+	cs, rollover, err := governance.RotateKeys(dataDir, otterID)
+	if err != nil {
+		fmt.Printf("Error rotating keys: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("✓ Signing key rotated")
+	fmt.Printf("Old Signing Key: %s\n", hex.EncodeToString(rollover.OldKey))
+	fmt.Printf("New Signing Key: %s\n", governance.ExportSigningPublicKey(cs))
+	fmt.Printf("Rollover stored in: %s/%s\n", dataDir, governance.RolloverFile)
+	fmt.Println("Restart the otter to announce the new key to its rafts.")
+}
+
+func verifyAudit(dataDir, dbPath string) {
+	cs, err := governance.LoadOrGenerateKeys(dataDir)
+	if err != nil {
+		fmt.Printf("Error loading keys: %v\n", err)
+		os.Exit(1)
+	}
+	db, err := vectordb.NewSQLiteVectorDB(dbPath)
+	if err != nil {
+		fmt.Printf("Error opening database: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	report, err := governance.VerifyAuditLog(context.Background(), db.GetDB(), cs.GetSigningPublicKey())
+	if err != nil {
+		fmt.Printf("Error reading audit log: %v\n", err)
+		os.Exit(1)
+	}
+	if !report.Valid {
+		fmt.Printf("✗ %s\n", report)
+		db.Close()
+		os.Exit(1)
+	}
+	fmt.Printf("✓ %s\n", report)
+	fmt.Printf("Signing Key: %s\n", report.SignerKey)
+}
@@ -0,0 +1,458 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"otter-ai/internal/agent"
+	"otter-ai/internal/api"
+	"otter-ai/internal/backup"
+	"otter-ai/internal/config"
+	"otter-ai/internal/connectors"
+	"otter-ai/internal/events"
+	"otter-ai/internal/governance"
+	"otter-ai/internal/llm"
+	"otter-ai/internal/llm/usage"
+	"otter-ai/internal/logging"
+	"otter-ai/internal/memory"
+	"otter-ai/internal/plugins"
+	"otter-ai/internal/prompts"
+	"otter-ai/internal/shutdown"
+	"otter-ai/internal/tracing"
+	"otter-ai/internal/vectordb"
+	"otter-ai/internal/version"
+)
+
+func main() {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Structured logger injected into the subsystems; the standard log
+	// package and slog's top-level functions write through it too
+	logger, err := logging.New(os.Stderr, cfg.Logging)
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+	slog.SetDefault(logger)
+	logger.Info("starting Otter-AI", "version", version.Version, "otter_id", cfg.Raft.ID)
+
+	// Export traces over OTLP when enabled
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, cfg.Raft.ID)
+	if err != nil {
+		fatal(logger, "failed to set up tracing", err)
+	}
+
+	// Initialize vector database
+	vdb, err := vectordb.New(vectordb.Backend(cfg.VectorBackend), cfg.DBPath, vectordb.Options{
+		SQLite: vectordb.SQLiteConfig{
+			BusyTimeout:         cfg.SQLite.BusyTimeout,
+			DisableWAL:          !cfg.SQLite.WAL,
+			BatchWindow:         cfg.SQLite.BatchWindow,
+			BatchSize:           cfg.SQLite.BatchSize,
+			MaintenanceInterval: cfg.SQLite.MaintenanceInterval,
+		},
+		LanceDB: vectordb.LanceDBConfig{
+			URL:      cfg.LanceDB.URL,
+			APIKey:   cfg.LanceDB.APIKey,
+			Database: cfg.LanceDB.Database,
+		},
+		Qdrant: vectordb.QdrantConfig{
+			URL:              cfg.Qdrant.URL,
+			APIKey:           cfg.Qdrant.APIKey,
+			CollectionPrefix: cfg.Qdrant.CollectionPrefix,
+		},
+		Memory: vectordb.MemoryConfig{SnapshotPath: cfg.VectorSnapshot},
+	})
+	if err != nil {
+		fatal(logger, "failed to initialize vector database", err)
+	}
+
+	// Event bus for real-time UI updates
+	eventBus := events.NewBus()
+
+	// Initialize memory layer
+	mem := memory.New(vdb)
+	mem.SetEventBus(eventBus)
+	mem.SetLogger(logger.With("component", "memory"))
+
+	// Initialize governance
+	bootstrapFile := cfg.Raft.BootstrapFile
+	if bootstrapFile == "" {
+		bootstrapFile = filepath.Join(cfg.Raft.DataDir, governance.DefaultBootstrapFile)
+		if _, err := os.Stat(bootstrapFile); err != nil {
+			bootstrapFile = ""
+		}
+	}
+	var bootstrapRules []governance.BootstrapRule
+	if bootstrapFile != "" {
+		bootstrapRules, err = governance.LoadBootstrapRules(bootstrapFile)
+		if err != nil {
+			fatal(logger, "failed to load bootstrap rules", err)
+		}
+	}
+
+	govConfig := governance.RaftConfig{
+		ID:                cfg.Raft.ID,
+		Type:              governance.RaftType(cfg.Raft.Type),
+		BindAddr:          cfg.Raft.BindAddr,
+		AdvertiseAddr:     cfg.Raft.AdvertiseAddr,
+		PeerEndpoint:      cfg.Raft.PeerEndpoint,
+		DataDir:           cfg.Raft.DataDir,
+		VotingPeriod:      cfg.Raft.VotingPeriod,
+		HeartbeatInterval: cfg.Raft.HeartbeatInterval,
+		HeartbeatGrace:    cfg.Raft.HeartbeatGrace,
+		LeaveRules:        governance.LeaveRules(cfg.Raft.LeaveRules),
+		VotingPolicy: governance.VotingPolicy{
+			Quorum:        cfg.Raft.VotingQuorum,
+			Majority:      cfg.Raft.VotingMajority,
+			SuperMajority: cfg.Raft.VotingSuperMajority,
+			TieBreak:      governance.TieBreak(cfg.Raft.VotingTieBreak),
+		},
+		SuspensionQuorum: cfg.Raft.SuspensionQuorum,
+		SuspensionWindow: cfg.Raft.SuspensionWindow,
+		Enforcement:      governance.EnforcementMode(cfg.Raft.RuleEnforcement),
+		BootstrapRules:   bootstrapRules,
+		Logger:           logger.With("component", "governance"),
+	}
+	if cfg.Chaos.Enabled {
+		govConfig.Chaos = &governance.ChaosConfig{
+			DropRate:      cfg.Chaos.DropRate,
+			DuplicateRate: cfg.Chaos.DuplicateRate,
+			ReorderRate:   cfg.Chaos.ReorderRate,
+			MinDelay:      cfg.Chaos.MinDelay,
+			MaxDelay:      cfg.Chaos.MaxDelay,
+			Partitioned:   cfg.Chaos.Partitioned,
+			Seed:          cfg.Chaos.Seed,
+		}
+	}
+
+	gov, err := governance.New(govConfig, mem)
+	if err != nil {
+		fatal(logger, "failed to initialize governance", err)
+	}
+
+	// Initialize LLM provider
+	llmProvider, err := llm.NewProvider(cfg.LLM)
+	if err != nil {
+		fatal(logger, "failed to initialize LLM provider", err)
+	}
+	embedder, err := llm.NewEmbeddingProvider(cfg.LLM, cfg.Embedding)
+	if err != nil {
+		fatal(logger, "failed to initialize embedding provider", err)
+	}
+
+	// Count tokens per day, provider and purpose, within the daily budget
+	var usageDB *sql.DB
+	if local, ok := vdb.(interface{ GetDB() *sql.DB }); ok {
+		usageDB = local.GetDB()
+	}
+	usageTracker, err := usage.NewTracker(context.Background(), usageDB, cfg.Usage)
+	if err != nil {
+		fatal(logger, "failed to initialize LLM usage tracking", err)
+	}
+	llmProvider = usage.Wrap(llmProvider, usageTracker)
+	embedder = usage.WrapEmbedder(embedder, usageTracker)
+	gov.SetLLMProvider(llmProvider)
+	gov.SetEmbedder(embedder)
+
+	promptTemplates, err := prompts.Load(cfg.LLM.PromptDir)
+	if err != nil {
+		fatal(logger, "failed to load prompt templates", err)
+	}
+	gov.SetPrompts(promptTemplates)
+
+	// Vectors from different embedding models can't be searched together
+	if dimension, err := llm.Dimension(context.Background(), embedder); err != nil {
+		logger.Warn("embedding dimension not checked against stored memories", "error", err)
+	} else if err := mem.CheckEmbeddingModel(context.Background(), llm.EmbeddingModel(cfg.LLM, cfg.Embedding), dimension); err != nil {
+		fatal(logger, "embedding provider is incompatible with stored memories", err)
+	}
+	gov.SetEventBus(eventBus)
+
+	// Initialize plugin manager
+	pluginMgr := plugins.NewManager(cfg.Plugins)
+	pluginMgr.SetEventBus(eventBus)
+	pluginMgr.SetLogger(logger.With("component", "plugins"))
+	if cfg.Plugins.WebhooksFile != "" {
+		endpoints, err := plugins.LoadWebhookEndpoints(cfg.Plugins.WebhooksFile)
+		if err != nil {
+			fatal(logger, "failed to load webhooks", err)
+		}
+		webhooks, err := plugins.NewWebhookPlugin(endpoints)
+		if err != nil {
+			fatal(logger, "invalid webhooks", err)
+		}
+		pluginMgr.Register(webhooks)
+	}
+
+	// Chat hooks for external policy services
+	var hooks []agent.Hook
+	for _, url := range cfg.Hooks.BeforeMessage {
+		hooks = append(hooks, agent.NewHTTPHook(agent.HookBeforeMessage, url, cfg.Hooks.Token, cfg.Hooks.Timeout))
+	}
+	for _, url := range cfg.Hooks.BeforeResponse {
+		hooks = append(hooks, agent.NewHTTPHook(agent.HookBeforeResponse, url, cfg.Hooks.Token, cfg.Hooks.Timeout))
+	}
+
+	mem.SetRetentionPolicy(memory.RetentionPolicy{
+		HalfLife:      cfg.Retention.HalfLife,
+		Floor:         cfg.Retention.Floor,
+		Demote:        cfg.Retention.Demote,
+		Reinforcement: cfg.Retention.Reinforcement,
+		MinAge:        cfg.Retention.MinAge,
+	})
+	mem.SetDedupPolicy(memory.DedupPolicy{
+		Threshold: cfg.Dedup.Threshold,
+		Window:    cfg.Dedup.Window,
+		Action:    memory.DedupAction(cfg.Dedup.Action),
+		Bump:      cfg.Dedup.Bump,
+	})
+
+	var onboarding *agent.OnboardingWorkflow
+	if cfg.Onboarding.Enabled {
+		onboarding, err = agent.NewOnboardingWorkflow(cfg.Onboarding.TemplateFile, cfg.Onboarding.Steps)
+		if err != nil {
+			fatal(logger, "failed to load onboarding workflow", err)
+		}
+	}
+
+	var personalitySeed []agent.TraitSeed
+	if cfg.Personality.SeedFile != "" {
+		personalitySeed, err = agent.LoadPersonalitySeed(cfg.Personality.SeedFile)
+		if err != nil {
+			fatal(logger, "failed to load personality seed", err)
+		}
+	}
+
+	var channelProfiles []agent.ChannelProfile
+	if cfg.Plugins.ProfilesFile != "" {
+		channelProfiles, err = agent.LoadChannelProfiles(cfg.Plugins.ProfilesFile)
+		if err != nil {
+			fatal(logger, "failed to load channel profiles", err)
+		}
+	}
+
+	var sources []connectors.Connector
+	for _, source := range cfg.Connectors.Sources {
+		connector, err := connectors.New(source, cfg.Connectors.GitHubToken, nil)
+		if err != nil {
+			fatal(logger, "failed to create connector", err)
+		}
+		sources = append(sources, connector)
+	}
+
+	musingPromptLimit := cfg.Musing.PromptLimit
+	if musingPromptLimit == 0 {
+		musingPromptLimit = -1 // the agent treats zero as "use the default"
+	}
+
+	var reranker llm.Reranker
+	if cfg.Retrieval.Rerank == string(agent.RerankCrossEncoder) {
+		reranker = llm.NewCrossEncoder(cfg.Retrieval.RerankEndpoint, cfg.Retrieval.RerankAPIKey)
+	}
+
+	// Create agent
+	ag := agent.New(agent.Config{
+		Memory:       mem,
+		Governance:   gov,
+		LLM:          llmProvider,
+		Embedder:     embedder,
+		Usage:        usageTracker,
+		Prompts:      promptTemplates,
+		Plugins:      pluginMgr,
+		Hooks:        hooks,
+		HookFailOpen: cfg.Hooks.FailOpen,
+		ContextWindow: agent.ContextWindowConfig{
+			Tokens:      cfg.LLM.ContextWindow,
+			ReplyTokens: llm.NewProfiles(cfg.LLM.Profiles)[llm.ProfileChat].MaxTokens,
+		},
+		Consolidation: agent.ConsolidationConfig{
+			Interval:        cfg.Consolidation.Interval,
+			MinAge:          cfg.Consolidation.MinAge,
+			MaxImportance:   cfg.Consolidation.MaxImportance,
+			Similarity:      cfg.Consolidation.Similarity,
+			MinClusterSize:  cfg.Consolidation.MinClusterSize,
+			MaxClusterSize:  cfg.Consolidation.MaxClusterSize,
+			BatchSize:       cfg.Consolidation.BatchSize,
+			DeleteOriginals: cfg.Consolidation.DeleteOriginals,
+		},
+		RetentionInterval: cfg.Retention.Interval,
+		PurgeInterval:     cfg.Retention.PurgeInterval,
+		RecoveryWindow:    cfg.Retention.RecoveryWindow,
+		Voters:            cfg.Plugins.Voters,
+		Musing: agent.MusingConfig{
+			Interval:     cfg.Musing.Interval,
+			MemoryWindow: cfg.Musing.MemoryWindow,
+			MinMemories:  cfg.Musing.MinMemories,
+			Timeout:      cfg.Musing.Timeout,
+			PromptLimit:  musingPromptLimit,
+		},
+		Intent: agent.IntentConfig{
+			Mode:      agent.IntentMode(cfg.Intent.Mode),
+			Threshold: cfg.Intent.Threshold,
+			Margin:    cfg.Intent.Margin,
+		},
+		Retrieval: agent.RetrievalConfig{
+			MinScore:         cfg.Retrieval.MinScore,
+			DedupeSimilarity: cfg.Retrieval.DedupeSimilarity,
+			Candidates:       cfg.Retrieval.Candidates,
+			Rerank:           agent.RerankMode(cfg.Retrieval.Rerank),
+			Reranker:         reranker,
+			FactBoost:        cfg.Retrieval.FactBoost,
+		},
+		Extraction: agent.ExtractionConfig{
+			Enabled:    cfg.Extraction.Enabled,
+			MaxFacts:   cfg.Extraction.MaxFacts,
+			Importance: cfg.Extraction.Importance,
+		},
+		ChannelProfiles: channelProfiles,
+		Scheduler: agent.SchedulerConfig{
+			Interval:        cfg.Scheduler.Interval,
+			MaxAttempts:     cfg.Scheduler.MaxAttempts,
+			DigestTarget:    cfg.Scheduler.DigestTarget,
+			DigestAt:        cfg.Scheduler.DigestAt,
+			DeadlineTarget:  cfg.Scheduler.DeadlineTarget,
+			DeadlineWarning: cfg.Scheduler.DeadlineWarning,
+		},
+		Onboarding: onboarding,
+		Contacts:   cfg.Onboarding.Contacts,
+		Events:     eventBus,
+		Personality: agent.PersonalityConfig{
+			Seed:       personalitySeed,
+			Interval:   cfg.Personality.Interval,
+			DriftRate:  float32(cfg.Personality.DriftRate),
+			RuleWeight: cfg.Personality.RuleWeight,
+		},
+		Ingestion: agent.IngestionConfig{
+			Connectors: sources,
+			Interval:   cfg.Connectors.Interval,
+			MaxItems:   cfg.Connectors.MaxItems,
+		},
+		Mode: agent.Mode{
+			ReadOnly:    cfg.ReadOnly,
+			Maintenance: cfg.MaintenanceMode,
+			Reason:      "configuration",
+		},
+		Logger: logger.With("component", "agent"),
+	})
+
+	// Plugins start once the agent can answer the messages they receive
+	if err := pluginMgr.LoadAll(context.Background()); err != nil {
+		logger.Warn("failed to load some plugins", "error", err)
+	}
+	if err := pluginMgr.StartBus(context.Background()); err != nil {
+		fatal(logger, "failed to connect to the plugin bus", err)
+	}
+
+	seedCtx, seedCancel := context.WithTimeout(context.Background(), agent.PersonalityTimeout)
+	if err := ag.SeedPersonality(seedCtx); err != nil {
+		logger.Warn("failed to seed personality", "error", err)
+	}
+	seedCancel()
+
+	// Start API server
+	server := api.NewServer(cfg.API, ag)
+	server.SetEventBus(eventBus)
+	server.SetLogger(logger.With("component", "api"))
+	server.SetBackupSource(backup.Source{
+		OtterID: cfg.Raft.ID,
+		DB:      usageDB,
+		DBPath:  cfg.DBPath,
+		DataDir: cfg.Raft.DataDir,
+		// .env is read from the working directory by config.Load
+		ConfigFiles: []string{".env", cfg.File, cfg.Raft.BootstrapFile, cfg.Onboarding.TemplateFile, cfg.Personality.SeedFile, cfg.Plugins.ProfilesFile},
+	})
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		if err := server.Start(); err != nil {
+			fatal(logger, "API server failed", err)
+		}
+	}()
+
+	// SIGHUP reloads the configuration's rate limits, log level and prompts
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	go func() {
+		current := cfg
+		for range hupCh {
+			next, err := config.Load()
+			if err != nil {
+				logger.Error("failed to reload configuration; keeping the current one", "error", err)
+				continue
+			}
+			reloadConfig(logger, current, next, server, ag, gov)
+			current = next
+		}
+	}()
+
+	logger.Info("Otter-AI is running")
+
+	<-sigCh
+	logger.Info("shutting down Otter-AI", "timeout", cfg.ShutdownTimeout)
+
+	// Intake stops first and the database closes last, once the work in
+	// flight and the governance state have been written to it
+	stop := shutdown.New(logger.With("component", "shutdown"))
+	stop.Add("api", server.Shutdown)
+	stop.Add("agent drain", ag.Drain)
+	stop.Add("agent", ag.Shutdown)
+	stop.Add("plugins", pluginMgr.UnloadAll)
+	stop.Add("governance", gov.Shutdown)
+	stop.Add("tracing", shutdownTracing)
+	stop.Add("vector database", func(context.Context) error {
+		// Also waits for queued writes and saves the memory backend's snapshot
+		return vdb.Close()
+	})
+	if err := stop.Run(cfg.ShutdownTimeout); err != nil {
+		logger.Warn("Otter-AI did not shut down cleanly", "error", err)
+	}
+
+	logger.Info("Otter-AI stopped")
+}
+
+// reloadConfig applies the reloadable settings of a reloaded configuration
+// and warns about changes that wait for a restart
+func reloadConfig(logger *slog.Logger, cfg, next *config.Config, server *api.Server, ag *agent.Agent, gov *governance.Governance) {
+	if err := logging.SetLevel(logger, next.Logging.Level); err != nil {
+		logger.Error("failed to reload log level", "error", err)
+	}
+	server.Reload(next.API)
+	if next.LLM.PromptDir != "" || cfg.LLM.PromptDir != "" {
+		promptTemplates, err := prompts.Load(next.LLM.PromptDir)
+		if err != nil {
+			logger.Error("failed to reload prompt templates; keeping the current ones", "error", err)
+		} else {
+			ag.SetPrompts(promptTemplates)
+			gov.SetPrompts(promptTemplates)
+		}
+	}
+	if next.ReadOnly != cfg.ReadOnly || next.MaintenanceMode != cfg.MaintenanceMode {
+		ag.SetMode(context.Background(), agent.Mode{
+			ReadOnly:    next.ReadOnly,
+			Maintenance: next.MaintenanceMode,
+			Reason:      "configuration reload",
+		})
+	}
+	if sections := cfg.RestartRequired(next); len(sections) > 0 {
+		logger.Warn("configuration changes need a restart to apply", "sections", sections)
+	}
+	logger.Info("reloaded configuration", "file", next.File)
+}
+
+// fatal logs an error that stops the otter from starting, then exits
+func fatal(logger *slog.Logger, msg string, err error) {
+	logger.Error(msg, "error", err)
+	os.Exit(1)
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"otter-ai/internal/backup"
+)
+
+// BackupRequestTimeout bounds a backup download, which includes snapshotting
+// the otter's database
+const BackupRequestTimeout = 10 * time.Minute
+
+func runBackup(args []string) int {
+	fs := flag.NewFlagSet("backup", flag.ContinueOnError)
+	token := fs.String("token", os.Getenv("OTTERCTL_TOKEN"), "Admin bearer token (env OTTERCTL_TOKEN)")
+	passphrase := fs.String("passphrase", os.Getenv("OTTERCTL_PASSPHRASE"), "Passphrase used to obtain a token (env OTTERCTL_PASSPHRASE)")
+	archivePassphrase := fs.String("archive-passphrase", os.Getenv("OTTERCTL_ARCHIVE_PASSPHRASE"),
+		fmt.Sprintf("Passphrase the archive is encrypted with, at least %d characters (env OTTERCTL_ARCHIVE_PASSPHRASE)", backup.MinPassphraseLength))
+	output := fs.String("o", "", "Archive file to write (default: the name the otter suggests, in the current directory)")
+	timeout := fs.Duration("timeout", BackupRequestTimeout, "Request timeout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 || *archivePassphrase == "" {
+		fmt.Println("Usage: otterctl backup [flags] -archive-passphrase <passphrase> <otter-url>")
+		fs.PrintDefaults()
+		return 1
+	}
+
+	ctx := context.Background()
+	client := &fleetClient{http: &http.Client{Timeout: *timeout}, token: *token, passphrase: *passphrase}
+	base := normalizeURL(fs.Arg(0))
+	if client.token == "" && client.passphrase != "" {
+		t, err := client.authenticate(ctx, base)
+		if err != nil {
+			fmt.Printf("Error authenticating: %v\n", err)
+			return 1
+		}
+		client.token = t
+	}
+
+	body, _ := json.Marshal(map[string]string{"passphrase": *archivePassphrase})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, base+"/api/v1/admin/backup", bytes.NewReader(body))
+	if err != nil {
+		fmt.Printf("Error creating request: %v\n", err)
+		return 1
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if client.token != "" {
+		req.Header.Set("Authorization", "Bearer "+client.token)
+	}
+	resp, err := client.http.Do(req)
+	if err != nil {
+		fmt.Printf("Error requesting backup: %v\n", err)
+		return 1
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, FleetMaxResponse))
+		fmt.Printf("Backup failed (%d): %s\n", resp.StatusCode, bytes.TrimSpace(msg))
+		return 1
+	}
+
+	path := *output
+	if path == "" {
+		path = "otter" + backup.FileExtension
+		if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+			path = filepath.Base(params["filename"])
+		}
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		fmt.Printf("Error creating archive file: %v\n", err)
+		return 1
+	}
+	n, err := io.Copy(f, resp.Body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		fmt.Printf("Error writing archive: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("✓ Backup written to %s (%d bytes)\n", path, n)
+	return 0
+}
+
+func runRestore(args []string) int {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	archivePassphrase := fs.String("archive-passphrase", os.Getenv("OTTERCTL_ARCHIVE_PASSPHRASE"), "Passphrase the archive was encrypted with (env OTTERCTL_ARCHIVE_PASSPHRASE)")
+	dataDir := fs.String("data-dir", "/data/raft", "Empty raft data directory to restore keys into (OTTER_RAFT_DATA_DIR)")
+	dbPath := fs.String("db", "/data/otter.db", "Database file to create (OTTER_DB_PATH)")
+	configDir := fs.String("config-dir", "", "Directory for the archived configuration files (default: <data-dir>/config)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 || *archivePassphrase == "" {
+		fmt.Println("Usage: otterctl restore [flags] -archive-passphrase <passphrase> <archive>")
+		fs.PrintDefaults()
+		return 1
+	}
+	if *configDir == "" {
+		*configDir = filepath.Join(*dataDir, "config")
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		fmt.Printf("Error opening archive: %v\n", err)
+		return 1
+	}
+	defer f.Close()
+
+	manifest, err := backup.Restore(f, *archivePassphrase, backup.Target{DataDir: *dataDir, DBPath: *dbPath, ConfigDir: *configDir})
+	if err != nil {
+		fmt.Printf("Error restoring backup: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("✓ Restored otter %s (version %s, backed up %s)\n", manifest.OtterID, manifest.OtterVersion, manifest.CreatedAt.Format(time.RFC3339))
+	if manifest.Database {
+		fmt.Printf("Database: %s\n", *dbPath)
+	}
+	fmt.Printf("Data directory: %s (%d files)\n", *dataDir, len(manifest.DataFiles))
+	names := make([]string, 0, len(manifest.ConfigFiles))
+	for name := range manifest.ConfigFiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("Config: %s (was %s)\n", filepath.Join(*configDir, name), manifest.ConfigFiles[name])
+	}
+	fmt.Printf("Start the otter with OTTER_RAFT_DATA_DIR=%s and OTTER_DB_PATH=%s.\n", *dataDir, *dbPath)
+	return 0
+}
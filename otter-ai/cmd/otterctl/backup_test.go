@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"otter-ai/internal/api"
+	"otter-ai/internal/backup"
+)
+
+func TestBackupAndRestore(t *testing.T) {
+	const archivePassphrase = "correct horse battery"
+	dataDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dataDir, "otter.key"), []byte("key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/admin/backup" || r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var req api.BackupRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Passphrase != archivePassphrase {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Disposition", `attachment; filename="otter-1.otterbak"`)
+		if _, err := backup.Create(context.Background(), w, backup.Source{OtterID: "otter-1", DataDir: dataDir}, req.Passphrase); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "otter-1.otterbak")
+	if code := runBackup([]string{"-token", "wrong", "-archive-passphrase", archivePassphrase, "-o", archive, srv.URL}); code != 1 {
+		t.Errorf("backup with a bad token exited %d, want 1", code)
+	}
+	if code := runBackup([]string{"-token", "secret", "-archive-passphrase", archivePassphrase, "-o", archive, srv.URL}); code != 0 {
+		t.Fatalf("backup exited %d", code)
+	}
+
+	restored := filepath.Join(dir, "restored")
+	args := []string{"-archive-passphrase", archivePassphrase, "-data-dir", restored, "-db", filepath.Join(dir, "otter.db"), archive}
+	if code := runRestore(args); code != 0 {
+		t.Fatalf("restore exited %d", code)
+	}
+	if got, _ := os.ReadFile(filepath.Join(restored, "otter.key")); string(got) != "key" {
+		t.Errorf("restored key = %q", got)
+	}
+	if code := runRestore(args); code != 1 {
+		t.Errorf("restoring over a restored otter exited %d, want 1", code)
+	}
+}
@@ -0,0 +1,393 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"otter-ai/internal/config"
+	"otter-ai/internal/llm"
+	"otter-ai/internal/memory"
+	"otter-ai/internal/vectordb"
+)
+
+// Embedding evaluation defaults
+const (
+	EvalDefaultK         = 5
+	EvalDefaultCorpus    = 1000
+	EvalDefaultSynthetic = 50
+	EvalMinQueryWords    = 3
+)
+
+// EvalPair is a labeled query and the memories that should be retrieved
+// for it
+type EvalPair struct {
+	Query    string   `json:"query"`
+	Expected []string `json:"expected"` // Memory IDs
+}
+
+// EvalResult scores one embedding configuration
+type EvalResult struct {
+	Name       string  `json:"name"`
+	Provider   string  `json:"provider"`
+	Model      string  `json:"model"`
+	Dimensions int     `json:"dimensions"`
+	Recall     float64 `json:"recall_at_k"` // Mean share of expected memories in the top k
+	MRR        float64 `json:"mrr"`         // Mean reciprocal rank of the first expected memory
+	Hits       int     `json:"hits"`        // Queries with at least one expected memory in the top k
+	Errors     int     `json:"errors"`      // Memories and queries that could not be embedded
+	DurationMS int64   `json:"duration_ms"`
+}
+
+// EvalReport compares embedding configurations on the same pairs
+type EvalReport struct {
+	GeneratedAt time.Time    `json:"generated_at"`
+	Corpus      int          `json:"corpus"` // Memories retrieved from
+	Pairs       int          `json:"pairs"`
+	Synthetic   bool         `json:"synthetic"`
+	K           int          `json:"k"`
+	Results     []EvalResult `json:"results"`
+}
+
+// errCorpusFull stops reading memories once the corpus limit is reached
+var errCorpusFull = errors.New("corpus full")
+
+// evalConfig is a named embedding configuration under evaluation
+type evalConfig struct {
+	name     string
+	cfg      config.LLMConfig
+	embedder embedder
+}
+
+// embedder is the part of llm.Provider the evaluation needs
+type embedder interface {
+	Embed(ctx context.Context, text string) ([]float32, error)
+}
+
+func runEmbedEval(args []string) int {
+	fs := flag.NewFlagSet("embedeval", flag.ContinueOnError)
+	dbPath := fs.String("db", envOr("OTTER_DB_PATH", "/data/otter.db"), "SQLite database holding the memories (env OTTER_DB_PATH)")
+	specA := fs.String("a", "", "Configuration A as key=value pairs: provider, endpoint, model, api_key (defaults from OTTER_LLM_*)")
+	specB := fs.String("b", "", "Configuration B, in the same form as -a")
+	pairsFile := fs.String("pairs", "", "JSONL file of {\"query\": ..., \"expected\": [memory IDs]}; synthetic pairs are generated when empty")
+	synthetic := fs.Int("synthetic", EvalDefaultSynthetic, "Number of synthetic pairs to generate from stored memories")
+	seed := fs.Int64("seed", 1, "Seed for synthetic pair generation")
+	savePairs := fs.String("save-pairs", "", "Write the pairs used to this JSONL file")
+	limit := fs.Int("limit", EvalDefaultCorpus, "Most memories to retrieve from")
+	k := fs.Int("k", EvalDefaultK, "Cutoff for recall@k")
+	asJSON := fs.Bool("json", false, "Print the report as JSON")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *specB == "" || *k < 1 {
+		fmt.Println("Usage: otterctl embedeval [flags] -b <configuration>")
+		fs.PrintDefaults()
+		return 1
+	}
+
+	var configs []evalConfig
+	for i, spec := range []string{*specA, *specB} {
+		cfg, err := parseEmbeddingSpec(spec)
+		if err != nil {
+			fmt.Printf("Error in configuration %c: %v\n", 'A'+i, err)
+			return 1
+		}
+		provider, err := llm.NewProvider(cfg)
+		if err != nil {
+			fmt.Printf("Error in configuration %c: %v\n", 'A'+i, err)
+			return 1
+		}
+		configs = append(configs, evalConfig{name: string(rune('A' + i)), cfg: cfg, embedder: provider})
+	}
+
+	ctx := context.Background()
+	corpus, err := loadCorpus(ctx, *dbPath, *limit)
+	if err != nil {
+		fmt.Printf("Error reading memories: %v\n", err)
+		return 1
+	}
+
+	var pairs []EvalPair
+	if *pairsFile != "" {
+		pairs, err = readPairs(*pairsFile)
+	} else {
+		pairs = syntheticPairs(corpus, *synthetic, *seed)
+	}
+	if err != nil {
+		fmt.Printf("Error reading pairs: %v\n", err)
+		return 1
+	}
+	if len(pairs) == 0 {
+		fmt.Println("No evaluation pairs: store more memories or pass -pairs")
+		return 1
+	}
+	if *savePairs != "" {
+		if err := writePairs(*savePairs, pairs); err != nil {
+			fmt.Printf("Error saving pairs: %v\n", err)
+			return 1
+		}
+	}
+
+	report := EvalReport{
+		GeneratedAt: time.Now().UTC(),
+		Corpus:      len(corpus),
+		Pairs:       len(pairs),
+		Synthetic:   *pairsFile == "",
+		K:           *k,
+	}
+	for _, c := range configs {
+		report.Results = append(report.Results, evaluate(ctx, c, corpus, pairs, *k))
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			fmt.Printf("Error encoding report: %v\n", err)
+			return 1
+		}
+	} else {
+		printEvalReport(os.Stdout, report)
+	}
+	return 0
+}
+
+// envOr returns an environment variable, or fallback when it is unset
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// parseEmbeddingSpec parses "provider=ollama,model=nomic-embed-text" into an
+// LLM configuration, taking unset keys from the OTTER_LLM_* environment
+func parseEmbeddingSpec(spec string) (config.LLMConfig, error) {
+	cfg := config.LLMConfig{
+		Provider:       envOr("OTTER_LLM_PROVIDER", "openwebui"),
+		Endpoint:       envOr("OTTER_LLM_ENDPOINT", "http://localhost:11434"),
+		Model:          envOr("OTTER_LLM_MODEL", "llama2"),
+		EmbeddingModel: os.Getenv("OTTER_LLM_EMBEDDING_MODEL"),
+		APIKey:         os.Getenv("OTTER_LLM_API_KEY"),
+	}
+	for _, field := range strings.Split(spec, ",") {
+		if strings.TrimSpace(field) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return cfg, fmt.Errorf("expected key=value, got %q", field)
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "provider":
+			cfg.Provider = value
+		case "endpoint":
+			cfg.Endpoint = value
+		case "model":
+			cfg.EmbeddingModel = value
+		case "api_key":
+			cfg.APIKey = value
+		default:
+			return cfg, fmt.Errorf("unknown key %q", key)
+		}
+	}
+	return cfg, nil
+}
+
+// loadCorpus reads up to limit long-term memories
+func loadCorpus(ctx context.Context, dbPath string, limit int) ([]memory.MemoryRecord, error) {
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, err
+	}
+	db, err := vectordb.NewSQLiteVectorDB(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	var corpus []memory.MemoryRecord
+	err = memory.New(db).Each(ctx, memory.MemoryTypeLongTerm, func(record memory.MemoryRecord) error {
+		if len(corpus) >= limit {
+			return errCorpusFull
+		}
+		if strings.TrimSpace(record.Content) != "" {
+			corpus = append(corpus, record)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errCorpusFull) {
+		return nil, err
+	}
+	return corpus, nil
+}
+
+// readPairs reads evaluation pairs from a JSONL file
+func readPairs(path string) ([]EvalPair, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var pairs []EvalPair
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var pair EvalPair
+		if err := json.Unmarshal([]byte(text), &pair); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if pair.Query == "" || len(pair.Expected) == 0 {
+			return nil, fmt.Errorf("line %d: query and expected are required", line)
+		}
+		pairs = append(pairs, pair)
+	}
+	return pairs, scanner.Err()
+}
+
+// writePairs writes evaluation pairs as JSONL
+func writePairs(path string, pairs []EvalPair) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	for _, pair := range pairs {
+		if err := enc.Encode(pair); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// syntheticPairs builds up to n pairs whose query is a random run of about
+// half the words of a stored memory, which is expected to retrieve it.
+// Such queries share vocabulary with their memory, so they favor lexical
+// matching; labeled pairs give a truer picture.
+func syntheticPairs(corpus []memory.MemoryRecord, n int, seed int64) []EvalPair {
+	rng := rand.New(rand.NewSource(seed))
+	var pairs []EvalPair
+	for _, i := range rng.Perm(len(corpus)) {
+		if len(pairs) >= n {
+			break
+		}
+		words := strings.Fields(corpus[i].Content)
+		size := len(words) / 2
+		if size < EvalMinQueryWords {
+			continue
+		}
+		start := rng.Intn(len(words) - size + 1)
+		pairs = append(pairs, EvalPair{
+			Query:    strings.Join(words[start:start+size], " "),
+			Expected: []string{corpus[i].ID},
+		})
+	}
+	return pairs
+}
+
+// evaluate embeds the corpus and every query with one configuration and
+// scores retrieval by cosine similarity
+func evaluate(ctx context.Context, c evalConfig, corpus []memory.MemoryRecord, pairs []EvalPair, k int) EvalResult {
+	start := time.Now()
+	result := EvalResult{Name: c.name, Provider: c.cfg.Provider, Model: c.cfg.EmbeddingModel}
+	if result.Model == "" {
+		result.Model = c.cfg.Model
+	}
+
+	type doc struct {
+		id     string
+		vector []float32
+	}
+	docs := make([]doc, 0, len(corpus))
+	for _, record := range corpus {
+		vector, err := c.embedder.Embed(ctx, record.Content)
+		if err != nil || len(vector) == 0 {
+			result.Errors++
+			continue
+		}
+		result.Dimensions = len(vector)
+		docs = append(docs, doc{id: record.ID, vector: vector})
+	}
+
+	scored := 0
+	for _, pair := range pairs {
+		query, err := c.embedder.Embed(ctx, pair.Query)
+		if err != nil || len(query) == 0 {
+			result.Errors++
+			continue
+		}
+		scored++
+
+		type hit struct {
+			id    string
+			score float64
+		}
+		hits := make([]hit, len(docs))
+		for i, d := range docs {
+			hits[i] = hit{id: d.id, score: vectordb.CosineSimilarity(query, d.vector)}
+		}
+		sort.SliceStable(hits, func(i, j int) bool { return hits[i].score > hits[j].score })
+
+		expected := make(map[string]bool, len(pair.Expected))
+		for _, id := range pair.Expected {
+			expected[id] = true
+		}
+		found := 0
+		for rank, h := range hits {
+			if !expected[h.id] {
+				continue
+			}
+			if found == 0 {
+				result.MRR += 1 / float64(rank+1)
+			}
+			if rank < k {
+				found++
+			}
+		}
+		result.Recall += float64(found) / float64(len(expected))
+		if found > 0 {
+			result.Hits++
+		}
+	}
+
+	if scored > 0 {
+		result.Recall /= float64(scored)
+		result.MRR /= float64(scored)
+	}
+	result.DurationMS = time.Since(start).Milliseconds()
+	return result
+}
+
+// printEvalReport renders the report as a table
+func printEvalReport(w io.Writer, report EvalReport) {
+	kind := "labeled"
+	if report.Synthetic {
+		kind = "synthetic"
+	}
+	fmt.Fprintf(w, "%d %s pairs against %d memories\n\n", report.Pairs, kind, report.Corpus)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "CONFIG\tPROVIDER\tMODEL\tDIMS\tRECALL@%d\tMRR\tHITS\tERRORS\tTIME\n", report.K)
+	for _, r := range report.Results {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%.3f\t%.3f\t%d/%d\t%d\t%s\n",
+			r.Name, r.Provider, r.Model, r.Dimensions, r.Recall, r.MRR, r.Hits, report.Pairs, r.Errors,
+			(time.Duration(r.DurationMS) * time.Millisecond).String())
+	}
+	tw.Flush()
+}
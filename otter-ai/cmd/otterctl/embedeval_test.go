@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"otter-ai/internal/memory"
+)
+
+// wordEmbedder embeds text as hashed word counts
+type wordEmbedder struct{ dims int }
+
+func (e wordEmbedder) Embed(_ context.Context, text string) ([]float32, error) {
+	vector := make([]float32, e.dims)
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		h := fnv.New32a()
+		h.Write([]byte(word))
+		vector[h.Sum32()%uint32(e.dims)]++
+	}
+	return vector, nil
+}
+
+// constantEmbedder maps everything to the same vector, so ranking is arbitrary
+type constantEmbedder struct{}
+
+func (constantEmbedder) Embed(context.Context, string) ([]float32, error) {
+	return []float32{1, 1}, nil
+}
+
+var evalCorpus = []memory.MemoryRecord{
+	{ID: "m1", Content: "the river otter eats crayfish near the dam every morning"},
+	{ID: "m2", Content: "sea otters hold hands while they sleep in kelp forests"},
+	{ID: "m3", Content: "deploys happen on fridays after the release review meeting"},
+	{ID: "m4", Content: "the wifi password is written inside the kitchen drawer"},
+}
+
+func TestEvaluate_ScoresRetrieval(t *testing.T) {
+	pairs := []EvalPair{
+		{Query: "otter eats crayfish", Expected: []string{"m1"}},
+		{Query: "kelp forests sleep", Expected: []string{"m2"}},
+		{Query: "release review fridays", Expected: []string{"m3"}},
+	}
+
+	good := evaluate(context.Background(), evalConfig{name: "A", embedder: wordEmbedder{dims: 256}}, evalCorpus, pairs, 1)
+	if good.Recall != 1 || good.MRR != 1 || good.Hits != 3 || good.Dimensions != 256 {
+		t.Errorf("unexpected result %+v", good)
+	}
+
+	bad := evaluate(context.Background(), evalConfig{name: "B", embedder: constantEmbedder{}}, evalCorpus, pairs, 1)
+	if bad.MRR >= good.MRR {
+		t.Errorf("constant embeddings should score below word embeddings: %+v", bad)
+	}
+	if bad.MRR <= 0 || bad.MRR > 1 || math.IsNaN(bad.MRR) {
+		t.Errorf("MRR out of range: %v", bad.MRR)
+	}
+}
+
+func TestSyntheticPairs(t *testing.T) {
+	corpus := append([]memory.MemoryRecord{{ID: "short", Content: "too short"}}, evalCorpus...)
+	pairs := syntheticPairs(corpus, 10, 1)
+	if len(pairs) != len(evalCorpus) {
+		t.Fatalf("expected one pair per long enough memory, got %d", len(pairs))
+	}
+	for _, pair := range pairs {
+		if pair.Expected[0] == "short" || len(strings.Fields(pair.Query)) < EvalMinQueryWords {
+			t.Errorf("unexpected pair %+v", pair)
+		}
+	}
+
+	again := syntheticPairs(corpus, 10, 1)
+	if again[0].Query != pairs[0].Query {
+		t.Error("the same seed should generate the same pairs")
+	}
+	if got := syntheticPairs(corpus, 2, 1); len(got) != 2 {
+		t.Errorf("expected 2 pairs, got %d", len(got))
+	}
+}
+
+func TestParseEmbeddingSpec(t *testing.T) {
+	t.Setenv("OTTER_LLM_PROVIDER", "ollama")
+	t.Setenv("OTTER_LLM_ENDPOINT", "http://llm:11434")
+
+	cfg, err := parseEmbeddingSpec("model=nomic-embed-text, api_key=secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Provider != "ollama" || cfg.Endpoint != "http://llm:11434" || cfg.EmbeddingModel != "nomic-embed-text" || cfg.APIKey != "secret" {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+
+	for _, bad := range []string{"model", "dims=3"} {
+		if _, err := parseEmbeddingSpec(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestReadAndWritePairs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pairs.jsonl")
+	pairs := []EvalPair{{Query: "q1", Expected: []string{"m1"}}, {Query: "q2", Expected: []string{"m2", "m3"}}}
+	if err := writePairs(path, pairs); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readPairs(path)
+	if err != nil || len(got) != 2 || len(got[1].Expected) != 2 {
+		t.Fatalf("readPairs = %+v, %v", got, err)
+	}
+
+	os.WriteFile(path, []byte(`{"query": "no labels"}`), 0o600)
+	if _, err := readPairs(path); err == nil {
+		t.Error("expected error for a pair without expected memories")
+	}
+}
@@ -0,0 +1,402 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"otter-ai/internal/api"
+)
+
+// Fleet polling configuration
+const (
+	FleetRequestTimeout = 10 * time.Second
+	FleetMaxResponse    = 4 << 20
+)
+
+// OtterReport is the polled state of a single otter
+type OtterReport struct {
+	URL       string              `json:"url"`
+	Healthy   bool                `json:"healthy"`
+	Error     string              `json:"error,omitempty"`
+	LatencyMS int64               `json:"latency_ms"`
+	Status    *api.StatusResponse `json:"status,omitempty"`
+}
+
+// RaftView is one raft as seen across the fleet
+type RaftView struct {
+	RaftID     string            `json:"raft_id"`
+	ReportedBy []string          `json:"reported_by"`     // Otters that report membership
+	Members    []string          `json:"members"`         // Union of members reported
+	Drift      []ScopeDrift      `json:"drift,omitempty"` // Scopes on which reporters disagree
+	Digests    map[string]string `json:"digests"`         // Otter ID -> rules digest
+}
+
+// ScopeDrift records a rule scope whose adopted body differs between otters
+type ScopeDrift struct {
+	Scope        string            `json:"scope"`
+	Fingerprints map[string]string `json:"fingerprints"` // Otter ID -> fingerprint ("" when the scope is missing)
+}
+
+// FleetReport is the combined view of all polled otters
+type FleetReport struct {
+	GeneratedAt time.Time           `json:"generated_at"`
+	Otters      []OtterReport       `json:"otters"`
+	Versions    map[string][]string `json:"versions"` // Version -> otter IDs
+	Rafts       []RaftView          `json:"rafts"`
+}
+
+// fleetClient polls otters over HTTP
+type fleetClient struct {
+	http       *http.Client
+	token      string
+	passphrase string
+}
+
+func runFleet(args []string) int {
+	fs := flag.NewFlagSet("fleet", flag.ContinueOnError)
+	targetsFile := fs.String("targets", "", "File with one otter URL per line")
+	token := fs.String("token", os.Getenv("OTTERCTL_TOKEN"), "Bearer token for every otter (env OTTERCTL_TOKEN)")
+	passphrase := fs.String("passphrase", os.Getenv("OTTERCTL_PASSPHRASE"), "Passphrase used to obtain a token from each otter (env OTTERCTL_PASSPHRASE)")
+	asJSON := fs.Bool("json", false, "Print the report as JSON")
+	timeout := fs.Duration("timeout", FleetRequestTimeout, "Per-request timeout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	targets := fs.Args()
+	if *targetsFile != "" {
+		fileTargets, err := readTargets(*targetsFile)
+		if err != nil {
+			fmt.Printf("Error reading targets: %v\n", err)
+			return 1
+		}
+		targets = append(targets, fileTargets...)
+	}
+	if len(targets) == 0 {
+		fmt.Println("Usage: otterctl fleet [flags] <otter-url>...")
+		fs.PrintDefaults()
+		return 1
+	}
+
+	client := &fleetClient{
+		http:       &http.Client{Timeout: *timeout},
+		token:      *token,
+		passphrase: *passphrase,
+	}
+
+	report := buildFleetReport(client.pollAll(context.Background(), targets))
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			fmt.Printf("Error encoding report: %v\n", err)
+			return 1
+		}
+	} else {
+		printFleetReport(os.Stdout, report)
+	}
+
+	for _, otter := range report.Otters {
+		if !otter.Healthy {
+			return 3
+		}
+	}
+	for _, raft := range report.Rafts {
+		if len(raft.Drift) > 0 {
+			return 4
+		}
+	}
+	return 0
+}
+
+// readTargets reads otter URLs from a file, skipping blanks and # comments
+func readTargets(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var targets []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		targets = append(targets, line)
+	}
+	return targets, scanner.Err()
+}
+
+// pollAll polls every target concurrently, preserving input order
+func (c *fleetClient) pollAll(ctx context.Context, targets []string) []OtterReport {
+	reports := make([]OtterReport, len(targets))
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		go func(i int, target string) {
+			defer wg.Done()
+			reports[i] = c.poll(ctx, target)
+		}(i, target)
+	}
+	wg.Wait()
+	return reports
+}
+
+// poll checks one otter's health and status endpoints
+func (c *fleetClient) poll(ctx context.Context, target string) OtterReport {
+	base := normalizeURL(target)
+	report := OtterReport{URL: base}
+
+	start := time.Now()
+	if err := c.getJSON(ctx, base+"/health", "", nil); err != nil {
+		report.Error = fmt.Sprintf("health: %v", err)
+		return report
+	}
+	report.LatencyMS = time.Since(start).Milliseconds()
+	report.Healthy = true
+
+	token := c.token
+	if token == "" && c.passphrase != "" {
+		t, err := c.authenticate(ctx, base)
+		if err != nil {
+			report.Error = fmt.Sprintf("auth: %v", err)
+			return report
+		}
+		token = t
+	}
+
+	var status api.StatusResponse
+	if err := c.getJSON(ctx, base+"/api/v1/status", token, &status); err != nil {
+		report.Error = fmt.Sprintf("status: %v", err)
+		return report
+	}
+	report.Status = &status
+	return report
+}
+
+// authenticate exchanges the passphrase for a JWT
+func (c *fleetClient) authenticate(ctx context.Context, base string) (string, error) {
+	body, _ := json.Marshal(map[string]string{"passphrase": c.passphrase})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, base+"/api/v1/auth", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var out struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, FleetMaxResponse)).Decode(&out); err != nil {
+		return "", err
+	}
+	return out.Token, nil
+}
+
+// getJSON performs an authenticated GET and decodes the body into out (if non-nil)
+func (c *fleetClient) getJSON(ctx context.Context, url, token string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, FleetMaxResponse)).Decode(out)
+}
+
+func normalizeURL(target string) string {
+	target = strings.TrimSpace(target)
+	if !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://") {
+		target = "http://" + target
+	}
+	return strings.TrimRight(target, "/")
+}
+
+// buildFleetReport combines per-otter reports into a fleet view and detects
+// rule drift between otters that report membership in the same raft.
+func buildFleetReport(otters []OtterReport) *FleetReport {
+	report := &FleetReport{
+		GeneratedAt: time.Now().UTC(),
+		Otters:      otters,
+		Versions:    make(map[string][]string),
+	}
+
+	type raftAccumulator struct {
+		view         *RaftView
+		members      map[string]bool
+		fingerprints map[string]map[string]string // otter ID -> scope -> fingerprint
+	}
+	rafts := make(map[string]*raftAccumulator)
+
+	for _, otter := range otters {
+		if otter.Status == nil {
+			continue
+		}
+		otterID := otter.Status.OtterID
+		if otterID == "" {
+			otterID = otter.URL
+		}
+		report.Versions[otter.Status.Version] = append(report.Versions[otter.Status.Version], otterID)
+
+		for _, raft := range otter.Status.Rafts {
+			acc, ok := rafts[raft.RaftID]
+			if !ok {
+				acc = &raftAccumulator{
+					view:         &RaftView{RaftID: raft.RaftID, Digests: make(map[string]string)},
+					members:      make(map[string]bool),
+					fingerprints: make(map[string]map[string]string),
+				}
+				rafts[raft.RaftID] = acc
+			}
+			acc.view.ReportedBy = append(acc.view.ReportedBy, otterID)
+			acc.view.Digests[otterID] = raft.RulesDigest
+			acc.fingerprints[otterID] = raft.RuleFingerprints
+			for _, member := range raft.Members {
+				acc.members[member.ID] = true
+			}
+		}
+	}
+
+	for _, acc := range rafts {
+		for member := range acc.members {
+			acc.view.Members = append(acc.view.Members, member)
+		}
+		sort.Strings(acc.view.Members)
+		sort.Strings(acc.view.ReportedBy)
+		acc.view.Drift = detectDrift(acc.fingerprints)
+		report.Rafts = append(report.Rafts, *acc.view)
+	}
+	sort.Slice(report.Rafts, func(i, j int) bool {
+		return report.Rafts[i].RaftID < report.Rafts[j].RaftID
+	})
+
+	return report
+}
+
+// detectDrift returns the scopes whose fingerprints differ between otters
+func detectDrift(fingerprints map[string]map[string]string) []ScopeDrift {
+	if len(fingerprints) < 2 {
+		return nil
+	}
+
+	scopes := make(map[string]bool)
+	for _, byScope := range fingerprints {
+		for scope := range byScope {
+			scopes[scope] = true
+		}
+	}
+
+	var drift []ScopeDrift
+	for scope := range scopes {
+		seen := make(map[string]string, len(fingerprints))
+		distinct := make(map[string]bool)
+		for otterID, byScope := range fingerprints {
+			seen[otterID] = byScope[scope]
+			distinct[byScope[scope]] = true
+		}
+		if len(distinct) > 1 {
+			drift = append(drift, ScopeDrift{Scope: scope, Fingerprints: seen})
+		}
+	}
+	sort.Slice(drift, func(i, j int) bool {
+		return drift[i].Scope < drift[j].Scope
+	})
+	return drift
+}
+
+// printFleetReport writes a human-readable report
+func printFleetReport(w io.Writer, report *FleetReport) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "OTTER\tURL\tHEALTH\tVERSION\tUPTIME\tRAFTS")
+	for _, otter := range report.Otters {
+		id, ver, uptime, rafts := "-", "-", "-", "-"
+		health := "down"
+		if otter.Healthy {
+			health = fmt.Sprintf("ok (%dms)", otter.LatencyMS)
+		}
+		if otter.Status != nil {
+			id = otter.Status.OtterID
+			ver = otter.Status.Version
+			uptime = (time.Duration(otter.Status.UptimeSeconds) * time.Second).String()
+			rafts = fmt.Sprintf("%d", len(otter.Status.Rafts))
+		}
+		if otter.Error != "" {
+			health += " - " + otter.Error
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", id, otter.URL, health, ver, uptime, rafts)
+	}
+	tw.Flush()
+
+	if len(report.Versions) > 1 {
+		fmt.Fprintln(w, "\nWarning: fleet is running mixed versions:")
+		for ver, ids := range report.Versions {
+			fmt.Fprintf(w, "  %s: %s\n", ver, strings.Join(ids, ", "))
+		}
+	}
+
+	fmt.Fprintln(w, "\nRaft topology:")
+	for _, raft := range report.Rafts {
+		fmt.Fprintf(w, "  %s\n", raft.RaftID)
+		fmt.Fprintf(w, "    members:     %s\n", strings.Join(raft.Members, ", "))
+		fmt.Fprintf(w, "    reported by: %s\n", strings.Join(raft.ReportedBy, ", "))
+		if len(raft.Drift) == 0 {
+			fmt.Fprintln(w, "    rules:       consistent")
+			continue
+		}
+		fmt.Fprintf(w, "    rules:       DRIFT on %d scope(s)\n", len(raft.Drift))
+		for _, d := range raft.Drift {
+			fmt.Fprintf(w, "      %s:", d.Scope)
+			ids := make([]string, 0, len(d.Fingerprints))
+			for id := range d.Fingerprints {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+			for _, id := range ids {
+				fp := d.Fingerprints[id]
+				if fp == "" {
+					fp = "missing"
+				}
+				fmt.Fprintf(w, " %s=%s", id, fp)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"otter-ai/internal/api"
+	"otter-ai/internal/governance"
+)
+
+func statusFor(id string, fingerprints map[string]string) *api.StatusResponse {
+	return &api.StatusResponse{
+		OtterID: id,
+		Version: "test",
+		Rafts: []governance.RaftSummary{
+			{
+				RaftID:           "raft-1",
+				Members:          []governance.MemberSummary{{ID: id, State: governance.StateActive}},
+				RuleFingerprints: fingerprints,
+			},
+		},
+	}
+}
+
+func TestBuildFleetReport_NoDrift(t *testing.T) {
+	report := buildFleetReport([]OtterReport{
+		{URL: "a", Healthy: true, Status: statusFor("a", map[string]string{"safety": "f1"})},
+		{URL: "b", Healthy: true, Status: statusFor("b", map[string]string{"safety": "f1"})},
+	})
+
+	if len(report.Rafts) != 1 {
+		t.Fatalf("expected 1 raft, got %d", len(report.Rafts))
+	}
+	raft := report.Rafts[0]
+	if len(raft.Members) != 2 || len(raft.ReportedBy) != 2 {
+		t.Errorf("unexpected topology: %+v", raft)
+	}
+	if len(raft.Drift) != 0 {
+		t.Errorf("expected no drift, got %+v", raft.Drift)
+	}
+}
+
+func TestBuildFleetReport_DetectsDrift(t *testing.T) {
+	report := buildFleetReport([]OtterReport{
+		{URL: "a", Healthy: true, Status: statusFor("a", map[string]string{"safety": "f1", "privacy": "p1"})},
+		{URL: "b", Healthy: true, Status: statusFor("b", map[string]string{"safety": "f2"})},
+	})
+
+	drift := report.Rafts[0].Drift
+	if len(drift) != 2 {
+		t.Fatalf("expected drift on 2 scopes, got %+v", drift)
+	}
+	if drift[0].Scope != "privacy" || drift[0].Fingerprints["b"] != "" {
+		t.Errorf("expected privacy missing on b, got %+v", drift[0])
+	}
+	if drift[1].Scope != "safety" {
+		t.Errorf("expected safety drift, got %+v", drift[1])
+	}
+}
+
+func TestBuildFleetReport_SkipsUnreachable(t *testing.T) {
+	report := buildFleetReport([]OtterReport{{URL: "down", Error: "connection refused"}})
+	if len(report.Rafts) != 0 || len(report.Versions) != 0 {
+		t.Errorf("expected empty report, got %+v", report)
+	}
+}
+
+func TestFleetClient_Poll(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			w.Write([]byte(`{"status":"healthy"}`))
+		case "/api/v1/status":
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(w).Encode(statusFor("otter-x", nil))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client := &fleetClient{http: &http.Client{Timeout: time.Second}, token: "secret"}
+	report := client.poll(context.Background(), srv.URL)
+	if !report.Healthy || report.Status == nil || report.Status.OtterID != "otter-x" {
+		t.Errorf("unexpected report: %+v", report)
+	}
+
+	client.token = ""
+	report = client.poll(context.Background(), srv.URL)
+	if report.Status != nil || report.Error == "" {
+		t.Errorf("expected status error without token, got %+v", report)
+	}
+}
+
+func TestNormalizeURL(t *testing.T) {
+	if got := normalizeURL("otter:8080/"); got != "http://otter:8080" {
+		t.Errorf("normalizeURL = %q", got)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+
+	command := os.Args[1]
+
+	switch command {
+	case "fleet":
+		os.Exit(runFleet(os.Args[2:]))
+
+	case "embedeval":
+		os.Exit(runEmbedEval(os.Args[2:]))
+
+	case "backup":
+		os.Exit(runBackup(os.Args[2:]))
+
+	case "restore":
+		os.Exit(runRestore(os.Args[2:]))
+
+	case "reindex":
+		os.Exit(runReindex(os.Args[2:]))
+
+	case "help", "-h", "--help":
+		usage()
+
+	default:
+		fmt.Printf("Unknown command: %s\n", command)
+		usage()
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Println("Usage: otterctl <command> [args]")
+	fmt.Println("")
+	fmt.Println("Commands:")
+	fmt.Println("  fleet [flags] <otter-url>...   Aggregate health, versions, raft topology and rule drift")
+	fmt.Println("  embedeval [flags] -b <config>  Compare retrieval quality of two embedding configurations")
+	fmt.Println("  backup [flags] <otter-url>     Download an encrypted archive of an otter's database, keys and config")
+	fmt.Println("  restore [flags] <archive>      Restore an archive into a fresh data directory")
+	fmt.Println("  reindex [flags]                Re-embed every memory with the configured embedding model")
+	fmt.Println("")
+	fmt.Println("Run 'otterctl <command> -h' for a command's flags.")
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"otter-ai/internal/config"
+	"otter-ai/internal/llm"
+	"otter-ai/internal/memory"
+	"otter-ai/internal/vectordb"
+)
+
+func runReindex(args []string) int {
+	fs := flag.NewFlagSet("reindex", flag.ContinueOnError)
+	dbPath := fs.String("db", envOr("OTTER_DB_PATH", "/data/otter.db"), "SQLite database holding the memories (env OTTER_DB_PATH)")
+	batch := fs.Int("batch", memory.DefaultReindexBatch, "Memories re-embedded per write")
+	force := fs.Bool("force", false, "Re-embed tables already recorded as embedded with the current model")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 || *batch < 1 {
+		fmt.Println("Usage: otterctl reindex [flags]")
+		fmt.Println("Re-embeds every memory with the model configured by OTTER_EMBEDDING_* and OTTER_LLM_*. Stop the otter first.")
+		fs.PrintDefaults()
+		return 1
+	}
+	if _, err := os.Stat(*dbPath); err != nil {
+		fmt.Printf("Error opening database: %v\n", err)
+		return 1
+	}
+
+	chat, embedding := embeddingConfigFromEnv()
+	embedder, err := llm.NewEmbeddingProvider(chat, embedding)
+	if err != nil {
+		fmt.Printf("Error creating embedding provider: %v\n", err)
+		return 1
+	}
+	ctx := context.Background()
+	dimension, err := llm.Dimension(ctx, embedder)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+
+	db, err := vectordb.NewSQLiteVectorDB(*dbPath)
+	if err != nil {
+		fmt.Printf("Error opening database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	model := llm.EmbeddingModel(chat, embedding)
+	fmt.Printf("Re-embedding memories with %s (%d dimensions)\n", model, dimension)
+	result, err := memory.New(db).Reindex(ctx, embedder.Embed, memory.ReindexOptions{
+		Model:     model,
+		Dimension: dimension,
+		BatchSize: *batch,
+		Force:     *force,
+		Progress: func(memoryType memory.MemoryType, done, total int) {
+			fmt.Printf("  %s: %d/%d\n", memoryType, done, total)
+		},
+	})
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		fmt.Println("The otter refuses to start until 'otterctl reindex' completes.")
+		return 1
+	}
+	for _, memoryType := range result.Skipped {
+		fmt.Printf("  %s: already embedded with %s\n", memoryType, model)
+	}
+	total := 0
+	for _, n := range result.ReEmbedded {
+		total += n
+	}
+	fmt.Printf("Re-embedded %d memories\n", total)
+	return 0
+}
+
+// embeddingConfigFromEnv reads the chat and embedding configuration the
+// otter embeds with from the OTTER_LLM_* and OTTER_EMBEDDING_* environment
+func embeddingConfigFromEnv() (config.LLMConfig, config.EmbeddingConfig) {
+	chat, _ := parseEmbeddingSpec("")
+	return chat, config.EmbeddingConfig{
+		Provider: os.Getenv("OTTER_EMBEDDING_PROVIDER"),
+		Model:    os.Getenv("OTTER_EMBEDDING_MODEL"),
+		Endpoint: os.Getenv("OTTER_EMBEDDING_ENDPOINT"),
+		APIKey:   os.Getenv("OTTER_EMBEDDING_API_KEY"),
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"otter-ai/internal/memory"
+	"otter-ai/internal/vectordb"
+)
+
+func TestRunReindex(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"embedding": []float32{0.1, 0.2, 0.3}})
+	}))
+	defer srv.Close()
+	t.Setenv("OTTER_LLM_PROVIDER", "ollama")
+	t.Setenv("OTTER_LLM_ENDPOINT", srv.URL)
+	t.Setenv("OTTER_EMBEDDING_MODEL", "nomic-embed-text")
+
+	dbPath := filepath.Join(t.TempDir(), "otter.db")
+	db, err := vectordb.NewSQLiteVectorDB(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	mem := memory.New(db)
+	if err := mem.CheckEmbeddingModel(ctx, "ollama/all-minilm", 2); err != nil {
+		t.Fatal(err)
+	}
+	record := &memory.MemoryRecord{Type: memory.MemoryTypeLongTerm, Content: "kelp", Embedding: []float32{1, 0}}
+	if err := mem.Store(ctx, record); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	if code := runReindex([]string{"-db", dbPath, "-batch", "10"}); code != 0 {
+		t.Fatalf("reindex exited %d", code)
+	}
+
+	db, err = vectordb.NewSQLiteVectorDB(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	mem = memory.New(db)
+	if err := mem.CheckEmbeddingModel(ctx, "ollama/nomic-embed-text", 3); err != nil {
+		t.Errorf("CheckEmbeddingModel after reindex: %v", err)
+	}
+	got, err := mem.Get(ctx, record.ID, memory.MemoryTypeLongTerm)
+	if err != nil || len(got.Embedding) != 3 {
+		t.Errorf("re-embedded memory = %+v, %v", got, err)
+	}
+}
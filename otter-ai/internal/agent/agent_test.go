@@ -2,6 +2,8 @@ package agent
 
 import (
 	"context"
+	"errors"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -260,7 +262,7 @@ type mockVectorDB struct{}
 func (m *mockVectorDB) Store(_ context.Context, _ string, _ string, _ []float32, _ map[string]interface{}) error {
 	return nil
 }
-func (m *mockVectorDB) Search(_ context.Context, _ string, _ []float32, _ int) ([]vectordb.SearchResult, error) {
+func (m *mockVectorDB) Search(_ context.Context, _ string, _ []float32, _ int, _ vectordb.Filter) ([]vectordb.SearchResult, error) {
 	return nil, nil
 }
 func (m *mockVectorDB) Get(_ context.Context, _ string, _ string) (*vectordb.Record, error) {
@@ -312,6 +314,48 @@ func newTestAgent(llmProv llm.Provider) *Agent {
 	}
 }
 
+// newTestSQLiteMemory returns memory backed by a SQLite store in a
+// temporary directory, closed when the test ends
+func newTestSQLiteMemory(t *testing.T) *memory.Memory {
+	t.Helper()
+	db, err := vectordb.NewSQLiteVectorDB(filepath.Join(t.TempDir(), "otter.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return memory.New(db)
+}
+
+// newTestGovernance returns governance for otter-1 and its solo raft, shut
+// down when the test ends
+func newTestGovernance(t *testing.T) *governance.Governance {
+	t.Helper()
+	gov, err := governance.New(governance.RaftConfig{ID: "otter-1", DataDir: t.TempDir()}, memory.New(&mockVectorDB{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { gov.Shutdown(context.Background()) })
+	return gov
+}
+
+// newTestGovernedAgent builds otter-1's agent from cfg with its governance
+// publishing on cfg.Events. Memory defaults to a SQLite store. The agent is
+// shut down when the test ends.
+func newTestGovernedAgent(t *testing.T, cfg Config) (*Agent, *governance.Governance) {
+	t.Helper()
+	gov := newTestGovernance(t)
+	if cfg.Events != nil {
+		gov.SetEventBus(cfg.Events)
+	}
+	if cfg.Memory == nil {
+		cfg.Memory = newTestSQLiteMemory(t)
+	}
+	cfg.Governance = gov
+	a := New(cfg)
+	t.Cleanup(func() { a.Shutdown(context.Background()) })
+	return a, gov
+}
+
 // --- buildConversationContext ---
 
 func TestBuildConversationContext_Empty(t *testing.T) {
@@ -508,6 +552,65 @@ func TestAgentShutdown(t *testing.T) {
 	}
 }
 
+// blockingLLM holds every completion until released
+type blockingLLM struct {
+	mockLLMProvider
+	started chan struct{}
+	release chan struct{}
+}
+
+func (m *blockingLLM) Complete(ctx context.Context, req *llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	m.started <- struct{}{}
+	<-m.release
+	return &llm.CompletionResponse{Text: "done"}, nil
+}
+
+func TestAgentDrain(t *testing.T) {
+	provider := &blockingLLM{started: make(chan struct{}, 1), release: make(chan struct{})}
+	a := newTestAgent(provider)
+
+	replied := make(chan error, 1)
+	go func() {
+		_, err := a.ProcessMessage(context.Background(), "hello")
+		replied <- err
+	}()
+	<-provider.started
+
+	drained := make(chan error, 1)
+	go func() { drained <- a.Drain(context.Background()) }()
+	time.Sleep(10 * time.Millisecond)
+	select {
+	case err := <-drained:
+		t.Fatalf("Drain returned %v with a message in flight", err)
+	default:
+	}
+	if _, err := a.ProcessMessage(context.Background(), "too late"); !errors.Is(err, ErrShuttingDown) {
+		t.Errorf("ProcessMessage while draining = %v; want ErrShuttingDown", err)
+	}
+
+	close(provider.release)
+	if err := <-replied; err != nil {
+		t.Errorf("in-flight message failed: %v", err)
+	}
+	if err := <-drained; err != nil {
+		t.Errorf("Drain: %v", err)
+	}
+}
+
+func TestAgentDrain_Timeout(t *testing.T) {
+	provider := &blockingLLM{started: make(chan struct{}, 1), release: make(chan struct{})}
+	defer close(provider.release)
+	a := newTestAgent(provider)
+	go a.ProcessMessage(context.Background(), "hello")
+	<-provider.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := a.Drain(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Drain = %v; want the deadline exceeded", err)
+	}
+}
+
 // --- ClearConversation ---
 
 func TestClearConversation(t *testing.T) {
@@ -568,7 +671,7 @@ func TestAgentTools_NoGovernance(t *testing.T) {
 	a := newTestAgent(nil)
 	tools := a.agentTools()
 	for _, tool := range tools {
-		if tool.Name == "propose_rule" || tool.Name == "vote_on_proposal" || tool.Name == "list_governance_state" {
+		if tool.Name == "propose_rule" || tool.Name == "vote_on_proposal" || tool.Name == "list_governance_state" || tool.Name == "leave_raft" {
 			t.Errorf("governance tool %q should not appear without governance", tool.Name)
 		}
 	}
@@ -592,7 +695,7 @@ func TestAgentTools_WithGovernance(t *testing.T) {
 	for _, tool := range tools {
 		names[tool.Name] = true
 	}
-	for _, expected := range []string{"propose_rule", "vote_on_proposal", "list_governance_state"} {
+	for _, expected := range []string{"propose_rule", "vote_on_proposal", "list_governance_state", "leave_raft"} {
 		if !names[expected] {
 			t.Errorf("expected governance tool %q not found", expected)
 		}
@@ -771,6 +874,51 @@ func TestProcessMessage_ConfirmPending(t *testing.T) {
 	}
 }
 
+func TestExecuteTool_LeaveRaft_Confirm(t *testing.T) {
+	a := newTestAgent(&mockLLMProvider{completeResp: "ok"})
+	gov := newTestGovernance(t)
+	a.governance = gov
+
+	result := a.executeTool(context.Background(), llm.ToolCall{Name: "leave_raft", Arguments: map[string]string{"raft_id": "otter-1"}})
+	if result != "Cannot leave this otter's own raft." {
+		t.Errorf("got %q", result)
+	}
+
+	// Leaving waits for confirmation
+	result = a.executeTool(context.Background(), llm.ToolCall{Name: "leave_raft", Arguments: map[string]string{"raft_id": "raft-9"}})
+	if !contains(result, "confirm") {
+		t.Errorf("got %q", result)
+	}
+	if pending := a.getPendingAction(); pending == nil || pending.Action != "leave_raft" || pending.RaftID != "raft-9" {
+		t.Fatalf("pending = %+v", pending)
+	}
+
+	resp, err := a.ProcessMessage(context.Background(), "confirm")
+	if err != nil {
+		t.Fatalf("ProcessMessage: %v", err)
+	}
+	if resp != "I couldn't find raft raft-9." {
+		t.Errorf("got %q", resp)
+	}
+}
+
+func TestBuildGovernanceContext_EvictionProposal(t *testing.T) {
+	a := newTestAgent(&mockLLMProvider{completeResp: "ok"})
+	gov := newTestGovernance(t)
+	a.governance = gov
+
+	ctx := context.Background()
+	joinPeer(t, gov, "otter-1", "otter-2")
+	if _, err := gov.ProposeEviction(ctx, "otter-1", "otter-2", "otter-1", "spamming proposals", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	out := a.buildGovernanceContext(context.Background(), "")
+	if !contains(out, "Evict member: otter-2") || !contains(out, "Reason: spamming proposals") {
+		t.Errorf("eviction proposal missing from context:\n%s", out)
+	}
+}
+
 func TestProcessMessage_CancelPending(t *testing.T) {
 	a := newTestAgent(&mockLLMProvider{completeResp: "ok"})
 	a.setPendingAction(&pendingGovernanceAction{
@@ -859,3 +1007,71 @@ func findSubstring(s, sub string) bool {
 	}
 	return false
 }
+
+func TestFormatDeadline(t *testing.T) {
+	if got := formatDeadline(time.Time{}); got != "no deadline" {
+		t.Errorf("zero deadline = %q", got)
+	}
+	if got := formatDeadline(time.Now().Add(-time.Hour)); !contains(got, "(expired)") {
+		t.Errorf("past deadline = %q", got)
+	}
+	if got := formatDeadline(time.Now().Add(2 * time.Hour)); !contains(got, "(in ") {
+		t.Errorf("future deadline = %q", got)
+	}
+}
+
+// fixedEmbedder returns one vector for every text
+type fixedEmbedder []float32
+
+func (e fixedEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	return e, nil
+}
+
+func (e fixedEmbedder) Name() string { return "fixed" }
+
+func TestEmbed_UsesSeparateEmbedder(t *testing.T) {
+	a := newTestAgent(&mockLLMProvider{embedResp: []float32{0.1}})
+	if vector, _ := a.embed(context.Background(), "kelp"); len(vector) != 1 {
+		t.Errorf("without an embedder got %v, want the LLM's embedding", vector)
+	}
+
+	a.embedder = fixedEmbedder{1, 2, 3}
+	if vector, _ := a.embed(context.Background(), "kelp"); len(vector) != 3 {
+		t.Errorf("got %v, want the embedder's embedding", vector)
+	}
+	if got := a.buildCapabilityManifest(context.Background()).EmbeddingProvider; got != "fixed" {
+		t.Errorf("manifest embedding provider = %q, want fixed", got)
+	}
+}
+
+// joinPeer inducts a freshly keyed otter into a raft through the join
+// challenge, with an invite so it is admitted without a vote
+func joinPeer(t *testing.T, gov *governance.Governance, raftID, requesterID string) {
+	t.Helper()
+	peer, err := governance.NewCryptoSystem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	invite, err := gov.CreateInvite(raftID, requesterID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := invite.Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+	challenge, err := gov.IssueJoinChallenge(raftID, requesterID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := peer.SignJoinChallenge(challenge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gov.RequestJoin(context.Background(), governance.JoinRequest{
+		RaftID: raftID, RequesterID: requesterID, PublicKey: peer.GetPublicKey(), SigningKey: peer.GetSigningPublicKey(),
+		Nonce: challenge.Nonce, ChallengeSignature: sig, Invite: token,
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
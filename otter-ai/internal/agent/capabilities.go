@@ -0,0 +1,209 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"otter-ai/internal/events"
+	"otter-ai/internal/llm"
+	"otter-ai/internal/memory"
+)
+
+// CapabilityRefreshInterval bounds how stale the manifest's memory counts
+// get; plugin, rule and membership changes refresh it at once
+const CapabilityRefreshInterval = 5 * time.Minute
+
+// CapabilityManifest describes what this otter can actually do, generated
+// from its configuration so the LLM doesn't offer actions that aren't
+// enabled
+type CapabilityManifest struct {
+	GeneratedAt       time.Time              `json:"generated_at"`
+	LLMProvider       string                 `json:"llm_provider"`
+	EmbeddingProvider string                 `json:"embedding_provider"`
+	Plugins           []string               `json:"plugins"`              // Chat platforms the otter is connected to
+	Connectors        []string               `json:"connectors,omitempty"` // External sources ingested into memory, as "name (kind)"
+	Tools             []string               `json:"tools"`
+	Memory            MemoryCapabilities     `json:"memory"`
+	Governance        GovernanceCapabilities `json:"governance"`
+	Limitations       []string               `json:"limitations"`
+}
+
+// MemoryCapabilities summarizes what the otter remembers
+type MemoryCapabilities struct {
+	LongTerm int `json:"long_term"`
+	Musings  int `json:"musings"`
+	Archived int `json:"archived"`
+}
+
+// GovernanceCapabilities describes the otter's part in governance
+type GovernanceCapabilities struct {
+	Enabled bool       `json:"enabled"`
+	OtterID string     `json:"otter_id,omitempty"`
+	Rafts   []RaftRole `json:"rafts,omitempty"`
+}
+
+// RaftRole is the otter's role in one raft
+type RaftRole struct {
+	RaftID  string `json:"raft_id"`
+	Role    string `json:"role"` // "solo" for the otter's own raft, "archived" for read-only history, otherwise "member"
+	Members int    `json:"members"`
+	Rules   int    `json:"rules"`
+}
+
+// capabilityState caches the manifest between changes
+type capabilityState struct {
+	mu       sync.Mutex
+	manifest *CapabilityManifest
+	stale    bool
+}
+
+// Capabilities returns the capability manifest, rebuilding it when it was
+// invalidated by a change or is older than CapabilityRefreshInterval
+func (a *Agent) Capabilities(ctx context.Context) *CapabilityManifest {
+	a.capabilities.mu.Lock()
+	defer a.capabilities.mu.Unlock()
+
+	manifest := a.capabilities.manifest
+	if manifest == nil || a.capabilities.stale || time.Since(manifest.GeneratedAt) > CapabilityRefreshInterval {
+		manifest = a.buildCapabilityManifest(ctx)
+		a.capabilities.manifest = manifest
+		a.capabilities.stale = false
+	}
+	return manifest
+}
+
+// invalidateCapabilities makes the next Capabilities call rebuild the manifest
+func (a *Agent) invalidateCapabilities() {
+	a.capabilities.mu.Lock()
+	a.capabilities.stale = true
+	a.capabilities.mu.Unlock()
+}
+
+// startCapabilityListener refreshes the manifest when plugins, rules or
+// raft membership change
+func (a *Agent) startCapabilityListener(bus *events.Bus) {
+	a.listen(bus, func(events.Event) {
+		a.invalidateCapabilities()
+	}, events.PluginsChanged, events.RuleAdopted, events.RuleSuspended, events.RuleReinstated, events.MemberJoined, events.MemberRevoked, events.RaftArchived)
+}
+
+// buildCapabilityManifest inspects the agent's configuration
+func (a *Agent) buildCapabilityManifest(ctx context.Context) *CapabilityManifest {
+	manifest := &CapabilityManifest{
+		GeneratedAt: time.Now(),
+		Plugins:     []string{},
+	}
+	if a.llm != nil {
+		manifest.LLMProvider = a.llm.Name()
+		manifest.EmbeddingProvider = a.llm.Name()
+	}
+	if a.embedder != nil {
+		manifest.EmbeddingProvider = a.embedder.Name()
+	}
+	if a.plugins != nil {
+		manifest.Plugins = a.plugins.Names()
+	}
+	for _, connector := range a.ingestion.Connectors {
+		manifest.Connectors = append(manifest.Connectors, fmt.Sprintf("%s (%s)", connector.Name(), connector.Kind()))
+	}
+	for _, tool := range a.agentTools() {
+		manifest.Tools = append(manifest.Tools, tool.Name)
+	}
+
+	if a.memory != nil {
+		counts := map[memory.MemoryType]*int{
+			memory.MemoryTypeLongTerm: &manifest.Memory.LongTerm,
+			memory.MemoryTypeMusing:   &manifest.Memory.Musings,
+			memory.MemoryTypeArchived: &manifest.Memory.Archived,
+		}
+		for memoryType, count := range counts {
+			n, err := a.memory.Count(ctx, memoryType)
+			if err != nil {
+				a.log().WarnContext(ctx, "failed to count memories", "memory_type", memoryType, "error", err)
+				continue
+			}
+			*count = n
+		}
+	}
+
+	if a.governance != nil {
+		manifest.Governance.Enabled = true
+		manifest.Governance.OtterID = a.governance.GetID()
+		for _, raft := range a.governance.RaftSummaries() {
+			role := "member"
+			if raft.RaftID == manifest.Governance.OtterID {
+				role = "solo"
+			} else if raft.Archived {
+				role = "archived"
+			}
+			manifest.Governance.Rafts = append(manifest.Governance.Rafts, RaftRole{
+				RaftID:  raft.RaftID,
+				Role:    role,
+				Members: len(raft.Members),
+				Rules:   raft.RuleCount,
+			})
+		}
+	}
+
+	manifest.Limitations = manifest.limitations()
+	return manifest
+}
+
+// limitations spells out what the otter must not offer to do
+func (m *CapabilityManifest) limitations() []string {
+	var limits []string
+	if len(m.Plugins) == 0 {
+		limits = append(limits, "No chat platform is connected: you cannot reach anyone on Slack, Discord, Signal, Telegram or elsewhere.")
+	} else {
+		limits = append(limits, fmt.Sprintf("You are connected to %s only; you cannot reach people on other platforms.", strings.Join(m.Plugins, ", ")))
+	}
+	limits = append(limits,
+		"You can only reply in the current conversation. You cannot send messages, emails or notifications to anyone else, or schedule anything for later.",
+		"You cannot browse the web or run code; use only the tools listed.")
+	if !m.Governance.Enabled {
+		limits = append(limits, "Governance is not configured: you cannot propose rules or vote.")
+	}
+	return limits
+}
+
+// buildCapabilityContext renders the manifest for the system prompt. Tools
+// are the ones offered this turn, which session controls may narrow.
+func (a *Agent) buildCapabilityContext(ctx context.Context, tools []llm.ToolDefinition) string {
+	manifest := a.Capabilities(ctx)
+
+	var b strings.Builder
+	b.WriteString("Your capabilities (generated from your configuration; never offer anything beyond them):\n")
+	if len(tools) == 0 {
+		b.WriteString("- Tools: none this conversation\n")
+	} else {
+		names := make([]string, len(tools))
+		for i, tool := range tools {
+			names[i] = tool.Name
+		}
+		fmt.Fprintf(&b, "- Tools: %s\n", strings.Join(names, ", "))
+	}
+	fmt.Fprintf(&b, "- Memory: %d long-term memories, %d musings, %d archived\n",
+		manifest.Memory.LongTerm, manifest.Memory.Musings, manifest.Memory.Archived)
+	if len(manifest.Connectors) > 0 {
+		fmt.Fprintf(&b, "- External sources pulled into your memory periodically: %s\n", strings.Join(manifest.Connectors, ", "))
+	}
+	for _, raft := range manifest.Governance.Rafts {
+		if raft.Role == "solo" {
+			fmt.Fprintf(&b, "- Governance: your own raft, %d rules\n", raft.Rules)
+			continue
+		}
+		if raft.Role == "archived" {
+			continue // Its rules are no longer in force
+		}
+		fmt.Fprintf(&b, "- Governance: member of raft %s with %d members, %d rules\n", raft.RaftID, raft.Members, raft.Rules)
+	}
+	b.WriteString("Limitations:\n")
+	for _, limit := range manifest.Limitations {
+		fmt.Fprintf(&b, "- %s\n", limit)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
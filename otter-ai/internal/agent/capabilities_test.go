@@ -0,0 +1,87 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"otter-ai/internal/config"
+	"otter-ai/internal/events"
+	"otter-ai/internal/memory"
+	"otter-ai/internal/plugins"
+)
+
+func TestCapabilities_Unconfigured(t *testing.T) {
+	a := newTestAgent(&mockLLMProvider{})
+	manifest := a.Capabilities(context.Background())
+
+	if manifest.LLMProvider != "mock" {
+		t.Errorf("LLMProvider = %q, want mock", manifest.LLMProvider)
+	}
+	if len(manifest.Plugins) != 0 || manifest.Governance.Enabled {
+		t.Errorf("expected no plugins or governance, got %+v", manifest)
+	}
+	limits := strings.Join(manifest.Limitations, "\n")
+	if !strings.Contains(limits, "No chat platform is connected") || !strings.Contains(limits, "cannot propose rules") {
+		t.Errorf("limitations should rule out messaging and governance, got:\n%s", limits)
+	}
+}
+
+func TestCapabilities_PluginsAndGovernance(t *testing.T) {
+	gov := newTestGovernance(t)
+	mgr := plugins.NewManager(config.PluginConfig{})
+	mgr.Register(&chatPlugin{})
+
+	a := newTestAgent(&mockLLMProvider{})
+	a.governance = gov
+	a.plugins = mgr
+	manifest := a.Capabilities(context.Background())
+
+	if len(manifest.Plugins) != 1 || manifest.Plugins[0] != "chat" {
+		t.Errorf("Plugins = %v, want [chat]", manifest.Plugins)
+	}
+	if len(manifest.Governance.Rafts) != 1 || manifest.Governance.Rafts[0].Role != "solo" {
+		t.Errorf("Rafts = %+v, want the solo raft", manifest.Governance.Rafts)
+	}
+	if !strings.Contains(strings.Join(manifest.Limitations, "\n"), "connected to chat only") {
+		t.Errorf("limitations should name the connected platforms, got %v", manifest.Limitations)
+	}
+}
+
+func TestCapabilities_RefreshedOnPluginChange(t *testing.T) {
+	bus := events.NewBus()
+	mgr := plugins.NewManager(config.PluginConfig{})
+	mgr.SetEventBus(bus)
+	a := New(Config{Memory: memory.New(&mockVectorDB{}), LLM: &mockLLMProvider{}, Plugins: mgr, Events: bus})
+	defer a.Shutdown(context.Background())
+
+	if plugins := a.Capabilities(context.Background()).Plugins; len(plugins) != 0 {
+		t.Fatalf("Plugins = %v, want none", plugins)
+	}
+	mgr.Register(&chatPlugin{})
+
+	deadline := time.Now().Add(time.Second)
+	for len(a.Capabilities(context.Background()).Plugins) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("manifest was not refreshed after a plugin was registered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestProcessMessage_PromptHasCapabilities(t *testing.T) {
+	rec := &recordingLLM{mockLLMProvider: mockLLMProvider{completeResp: "ok"}}
+	a := newTestAgent(rec)
+
+	if _, err := a.ProcessMessage(context.Background(), "can you ping Sam on slack?"); err != nil {
+		t.Fatalf("ProcessMessage: %v", err)
+	}
+	prompt := rec.last.SystemPrompt
+	if !strings.Contains(prompt, "Your capabilities") || !strings.Contains(prompt, "No chat platform is connected") {
+		t.Errorf("system prompt should carry the capability manifest, got:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "- Tools: search_memories") {
+		t.Errorf("system prompt should list this turn's tools, got:\n%s", prompt)
+	}
+}
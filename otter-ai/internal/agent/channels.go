@@ -0,0 +1,211 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"otter-ai/internal/llm"
+	"otter-ai/internal/plugins"
+)
+
+// MaxChannelPromptLength bounds a channel profile's system prompt addition
+const MaxChannelPromptLength = 4000
+
+// ChannelProfile tailors the agent to messages arriving through one
+// platform, or one channel on it, so the same otter can be formal in Slack
+// and casual on Discord
+type ChannelProfile struct {
+	Platform     string   `yaml:"platform" json:"platform"`
+	Channel      string   `yaml:"channel" json:"channel,omitempty"`             // Empty or "*" covers the whole platform
+	SystemPrompt string   `yaml:"system_prompt" json:"system_prompt,omitempty"` // Added to the chat system prompt
+	Temperature  *float32 `yaml:"temperature" json:"temperature,omitempty"`     // Nil keeps the chat profile's temperature
+	Tools        []string `yaml:"tools" json:"tools,omitempty"`                 // Tools that may be offered; nil allows all
+	MaxTokens    int      `yaml:"max_tokens" json:"max_tokens,omitempty"`       // Reply length limit; zero keeps the chat profile's
+}
+
+// channelProfilesFile is the layout of a channel profiles YAML file or its
+// JSON equivalent
+type channelProfilesFile struct {
+	Profiles []ChannelProfile `yaml:"profiles" json:"profiles"`
+}
+
+// wholePlatform reports whether the profile covers every channel of its
+// platform
+func (p *ChannelProfile) wholePlatform() bool {
+	return p.Channel == "" || p.Channel == "*"
+}
+
+// allowsTool reports whether the profile lets a tool be offered and run
+func (p *ChannelProfile) allowsTool(name string) bool {
+	if p == nil || p.Tools == nil {
+		return true
+	}
+	return containsString(p.Tools, name)
+}
+
+// apply sets the profile's parameters on a chat completion request
+func (p *ChannelProfile) apply(req *llm.CompletionRequest) {
+	if p == nil {
+		return
+	}
+	if p.Temperature != nil {
+		req.Temperature = *p.Temperature
+		req.TemperatureSet = true
+	}
+	if p.MaxTokens > 0 {
+		req.MaxTokens = p.MaxTokens
+	}
+}
+
+// LoadChannelProfiles reads channel profiles from a YAML file of the form
+//
+//	profiles:
+//	  - platform: slack
+//	    system_prompt: Keep a formal, professional tone.
+//	    temperature: 0.3
+//	  - platform: discord
+//	    channel: "1234"
+//	    tools: [search_memories, get_last_memory]
+//	    max_tokens: 300
+//
+// or, when the file name ends in .json, the same layout as JSON
+func LoadChannelProfiles(path string) ([]ChannelProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read channel profiles: %w", err)
+	}
+	var file channelProfilesFile
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &file)
+	} else {
+		err = yaml.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse channel profiles: %w", err)
+	}
+	if err := ValidateChannelProfiles(file.Profiles); err != nil {
+		return nil, err
+	}
+	return file.Profiles, nil
+}
+
+// ValidateChannelProfiles checks every profile names a platform, sets
+// parameters in range and only known tools, and that no platform and
+// channel is given two profiles
+func ValidateChannelProfiles(profiles []ChannelProfile) error {
+	tools := new(Agent).toolHandlers()
+	seen := make(map[string]bool, len(profiles))
+	for i, profile := range profiles {
+		if strings.TrimSpace(profile.Platform) == "" {
+			return fmt.Errorf("channel profile %d needs a platform", i+1)
+		}
+		key := profile.Platform + ":" + profile.Channel
+		if profile.wholePlatform() {
+			key = profile.Platform + ":*"
+		}
+		if seen[key] {
+			return fmt.Errorf("channel %s has more than one profile", key)
+		}
+		seen[key] = true
+
+		if len(profile.SystemPrompt) > MaxChannelPromptLength {
+			return fmt.Errorf("channel profile %s: system_prompt too long (max %d characters)", key, MaxChannelPromptLength)
+		}
+		if t := profile.Temperature; t != nil && (*t < 0 || *t > 2) {
+			return fmt.Errorf("channel profile %s: temperature must be between 0 and 2", key)
+		}
+		if profile.MaxTokens < 0 {
+			return fmt.Errorf("channel profile %s: max_tokens must not be negative", key)
+		}
+		for _, tool := range profile.Tools {
+			if _, ok := tools[tool]; !ok {
+				return fmt.Errorf("channel profile %s: unknown tool %q", key, tool)
+			}
+		}
+	}
+	return nil
+}
+
+// Channel identifies where a chat message arrived from
+type Channel struct {
+	Platform  string
+	ChannelID string
+	ThreadID  string // Thread within the channel, if any
+}
+
+type channelKey struct{}
+
+// WithChannel returns a context carrying the platform and channel a
+// message arrived through, which selects its channel profile
+func WithChannel(ctx context.Context, platform, channelID string) context.Context {
+	return WithThread(ctx, platform, channelID, "")
+}
+
+// WithThread returns a context carrying the platform, channel and thread a
+// message arrived through; replies scheduled from it are posted in the
+// thread
+func WithThread(ctx context.Context, platform, channelID, threadID string) context.Context {
+	return context.WithValue(ctx, channelKey{}, Channel{Platform: platform, ChannelID: channelID, ThreadID: threadID})
+}
+
+// ChannelFromContext returns the channel carried by ctx; the zero value
+// means the message did not arrive through a plugin
+func ChannelFromContext(ctx context.Context) Channel {
+	channel, _ := ctx.Value(channelKey{}).(Channel)
+	return channel
+}
+
+// PluginUserID is the user ID recorded for a platform user, qualified by
+// the platform since IDs are only unique within one
+func PluginUserID(platform, userID string) string {
+	return platform + ":" + userID
+}
+
+// handlePluginMessage answers a message a plugin received from its
+// platform in the session of its thread, applying the channel's profile
+func (a *Agent) handlePluginMessage(ctx context.Context, message *plugins.Message) (string, error) {
+	if a.channelDisabled(message.Platform, message.ChannelID) {
+		a.log().DebugContext(ctx, "ignoring message on a channel disabled by rule", "platform", message.Platform, "channel_id", message.ChannelID)
+		return "", nil
+	}
+	ctx = WithSession(ctx, a.plugins.ThreadSession(message))
+	ctx = WithThread(ctx, message.Platform, message.ChannelID, message.ThreadID)
+	if message.UserID != "" {
+		ctx = WithUser(ctx, PluginUserID(message.Platform, message.UserID))
+	}
+	reply, err := a.ProcessMessage(ctx, message.Content)
+	var blocked *BlockedError
+	if errors.As(err, &blocked) {
+		return blocked.Reply, nil
+	}
+	return reply, err
+}
+
+// channelProfile returns the profile for the channel carried by ctx: the
+// channel's own profile, else its platform's, else nil
+func (a *Agent) channelProfile(ctx context.Context) *ChannelProfile {
+	channel := ChannelFromContext(ctx)
+	if channel.Platform == "" {
+		return nil
+	}
+	var platformWide *ChannelProfile
+	for i := range a.channelProfiles {
+		profile := &a.channelProfiles[i]
+		if profile.Platform != channel.Platform {
+			continue
+		}
+		if profile.wholePlatform() {
+			platformWide = profile
+		} else if profile.Channel == channel.ChannelID {
+			return profile
+		}
+	}
+	return platformWide
+}
@@ -0,0 +1,154 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"otter-ai/internal/config"
+	"otter-ai/internal/llm"
+	"otter-ai/internal/plugins"
+)
+
+func TestLoadChannelProfiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	profiles, err := LoadChannelProfiles(write("profiles.yaml", `profiles:
+  - platform: slack
+    system_prompt: Keep a formal tone.
+    temperature: 0
+  - platform: discord
+    channel: "1234"
+    tools: [search_memories]
+    max_tokens: 300
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(profiles) != 2 || profiles[0].Temperature == nil || *profiles[0].Temperature != 0 || profiles[1].MaxTokens != 300 {
+		t.Errorf("profiles = %+v", profiles)
+	}
+
+	profiles, err = LoadChannelProfiles(write("profiles.json", `{"profiles": [{"platform": "telegram", "channel": "*"}]}`))
+	if err != nil || len(profiles) != 1 || profiles[0].Platform != "telegram" {
+		t.Errorf("JSON profiles = %+v, %v", profiles, err)
+	}
+
+	for name, content := range map[string]string{
+		"no platform": "profiles:\n  - channel: general\n",
+		"duplicate":   "profiles:\n  - platform: slack\n  - platform: slack\n    channel: \"*\"\n",
+		"temperature": "profiles:\n  - platform: slack\n    temperature: 3\n",
+		"max tokens":  "profiles:\n  - platform: slack\n    max_tokens: -1\n",
+		"tool":        "profiles:\n  - platform: slack\n    tools: [launch_rocket]\n",
+	} {
+		if _, err := LoadChannelProfiles(write("bad.yaml", content)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestChannelProfile_Resolution(t *testing.T) {
+	a := newTestAgent(nil)
+	a.channelProfiles = []ChannelProfile{
+		{Platform: "discord", SystemPrompt: "casual"},
+		{Platform: "discord", Channel: "governance", SystemPrompt: "careful"},
+		{Platform: "slack", Channel: "*", SystemPrompt: "formal"},
+	}
+
+	for _, tt := range []struct {
+		platform, channel, want string
+	}{
+		{"discord", "random", "casual"},
+		{"discord", "governance", "careful"},
+		{"slack", "general", "formal"},
+		{"telegram", "chat", ""},
+		{"", "", ""},
+	} {
+		ctx := context.Background()
+		if tt.platform != "" {
+			ctx = WithChannel(ctx, tt.platform, tt.channel)
+		}
+		got := ""
+		if profile := a.channelProfile(ctx); profile != nil {
+			got = profile.SystemPrompt
+		}
+		if got != tt.want {
+			t.Errorf("%s/%s: profile = %q, want %q", tt.platform, tt.channel, got, tt.want)
+		}
+	}
+}
+
+func TestProcessMessage_AppliesChannelProfile(t *testing.T) {
+	provider := &requestRecorder{reply: "Certainly."}
+	a := newTestAgent(provider)
+	temperature := float32(0.2)
+	a.channelProfiles = []ChannelProfile{{
+		Platform:     "slack",
+		SystemPrompt: "Keep a formal, professional tone.",
+		Temperature:  &temperature,
+		Tools:        []string{"search_memories"},
+		MaxTokens:    200,
+	}}
+
+	ctx := WithChannel(context.Background(), "slack", "general")
+	if _, err := a.ProcessMessage(ctx, "hello"); err != nil {
+		t.Fatal(err)
+	}
+	req := provider.requests[len(provider.requests)-1]
+	if !strings.Contains(req.SystemPrompt, "Keep a formal, professional tone.") || !strings.Contains(req.SystemPrompt, "200 tokens") {
+		t.Errorf("system prompt lacks the channel instructions:\n%s", req.SystemPrompt)
+	}
+	if req.Temperature != 0.2 || !req.TemperatureSet || req.MaxTokens != 200 {
+		t.Errorf("request parameters = %v/%v/%d, want the profile's", req.Temperature, req.TemperatureSet, req.MaxTokens)
+	}
+	if names := toolNames(req.Tools); len(names) != 1 || !names["search_memories"] {
+		t.Errorf("tools = %v, want only the profile's", names)
+	}
+	if result := a.executeTool(ctx, llm.ToolCall{Name: "get_health_status"}); !strings.Contains(result, "not available in this channel") {
+		t.Errorf("disallowed tool ran: %q", result)
+	}
+
+	// Messages from elsewhere keep the defaults
+	if _, err := a.ProcessMessage(context.Background(), "hello"); err != nil {
+		t.Fatal(err)
+	}
+	req = provider.requests[len(provider.requests)-1]
+	if strings.Contains(req.SystemPrompt, "CHANNEL INSTRUCTIONS") || req.TemperatureSet || req.MaxTokens != 0 || len(req.Tools) <= 1 {
+		t.Errorf("profile applied outside its channel: %+v", req)
+	}
+}
+
+func TestHandlePluginMessage_SessionPerThread(t *testing.T) {
+	mgr := plugins.NewManager(config.PluginConfig{})
+	a := newTestSessionAgent(&mockLLMProvider{completeResp: "ok"})
+	a.plugins = mgr
+	ctx := context.Background()
+
+	messages := []*plugins.Message{
+		{Platform: "slack", ChannelID: "C1", ThreadID: "t1", Content: "first thread"},
+		{Platform: "slack", ChannelID: "C1", ThreadID: "t2", Content: "second thread"},
+	}
+	for _, message := range messages {
+		if _, err := a.handlePluginMessage(ctx, message); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, message := range messages {
+		history := a.sessions.Get(ctx, mgr.ThreadSession(message)).History.GetRecent(10)
+		if len(history) != 2 || history[0].Content != message.Content {
+			t.Errorf("session %s history = %+v", mgr.ThreadSession(message), history)
+		}
+	}
+	if history := a.conversation.GetRecent(10); len(history) != 0 {
+		t.Errorf("plugin messages reached the default session: %+v", history)
+	}
+}
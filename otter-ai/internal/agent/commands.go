@@ -0,0 +1,176 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"otter-ai/internal/governance"
+	"otter-ai/internal/memory"
+	"otter-ai/internal/prompts"
+)
+
+// CommandHelp lists the chat commands
+const CommandHelp = `Commands:
+/rules - active rules by raft
+/proposals - open proposals, numbered
+/propose [scope:] <rule> - propose a rule (scope defaults to general)
+/vote <number or proposal ID> yes|no|abstain - vote on an open proposal
+/negotiations - negotiated compromises awaiting approval
+/approve <negotiation ID> [new text] - propose a compromise to both rafts, optionally reworded
+/reject <negotiation ID> [reason] - turn a compromise down
+/remind <duration> <message> - send a reminder to this channel later, e.g. /remind 2h check the nets
+/forget <text>|all - permanently erase your memories mentioning the text, or all of them
+/help - this list`
+
+// commandPattern matches a message starting with a slash command
+var commandPattern = regexp.MustCompile(`^/([A-Za-z]+)(?:\s+|$)`)
+
+// parseCommand splits a slash command into its lowercased name and the rest
+// of the message. Paths such as /etc/hosts are not commands.
+func parseCommand(message string) (string, string, bool) {
+	message = strings.TrimSpace(message)
+	match := commandPattern.FindStringSubmatch(message)
+	if match == nil {
+		return "", "", false
+	}
+	return strings.ToLower(match[1]), strings.TrimSpace(message[len(match[0]):]), true
+}
+
+// handleCommand runs a slash command without the LLM, so the same command
+// always does the same thing. Rules and proposals are limited to the turn's
+// scope, and proposing and voting are checked against the rules like the
+// tools they stand for.
+func (a *Agent) handleCommand(ctx context.Context, turn *chatTurn, message string) (string, bool) {
+	name, args, ok := parseCommand(message)
+	if !ok {
+		return "", false
+	}
+	if name == "help" {
+		return CommandHelp, true
+	}
+	if a.governance == nil {
+		switch name {
+		case "rules", "proposals", "propose", "vote", "negotiations", "approve", "reject", "forget":
+			return "Governance system is not configured.", true
+		}
+	}
+
+	scope := ContextOptionsFromContext(ctx).Scope
+	switch name {
+	case "rules":
+		return strings.TrimSpace(a.renderPrompt(ctx, prompts.Rules, governancePromptData{Rafts: a.raftPrompts(scope)})), true
+	case "proposals":
+		data := governancePromptData{Proposals: proposalPrompts(a.governance.GetOpenProposals(), scope)}
+		return strings.TrimSpace(a.renderPrompt(ctx, prompts.Proposals, data)), true
+	case "propose":
+		ruleScope, body := parseProposeArgs(args)
+		if body == "" {
+			return "Usage: /propose [scope:] <rule>", true
+		}
+		return a.runCommandTool(ctx, turn, "propose_rule", map[string]string{"rule_body": body, "scope": ruleScope}), true
+	case "vote":
+		fields := strings.Fields(strings.ToLower(args))
+		if len(fields) != 2 {
+			return "Usage: /vote <number or proposal ID> yes|no|abstain", true
+		}
+		proposal, reply := a.findListedProposal(fields[0], scope)
+		if proposal == nil {
+			return reply, true
+		}
+		return a.runCommandTool(ctx, turn, "vote_on_proposal", map[string]string{"proposal_id": proposal.ProposalID, "vote": fields[1]}), true
+	case "negotiations":
+		return a.negotiationsCommand(), true
+	case "approve":
+		return a.approveCommand(args), true
+	case "reject":
+		return a.rejectCommand(ctx, args), true
+	case "remind":
+		return a.remindCommand(ctx, args), true
+	case "forget":
+		return a.forgetCommand(ctx, args), true
+	}
+	return fmt.Sprintf("Unknown command /%s.\n%s", name, CommandHelp), true
+}
+
+// parseProposeArgs splits "scope: body" into its scope and body. Without a
+// single-word scope before a colon, the whole text is the body.
+func parseProposeArgs(args string) (string, string) {
+	if i := strings.Index(args, ":"); i > 0 && !strings.ContainsAny(args[:i], " \t\n") {
+		return strings.TrimSpace(args[:i]), strings.TrimSpace(args[i+1:])
+	}
+	return "", strings.TrimSpace(args)
+}
+
+// remindCommand schedules a reminder to the channel the message arrived
+// through
+func (a *Agent) remindCommand(ctx context.Context, args string) string {
+	delay, text, _ := strings.Cut(args, " ")
+	d, err := time.ParseDuration(delay)
+	if err != nil || d <= 0 || strings.TrimSpace(text) == "" {
+		return "Usage: /remind <duration> <message>, e.g. /remind 90m stretch"
+	}
+	channel := ChannelFromContext(ctx)
+	if channel.Platform == "" || channel.ChannelID == "" {
+		return "Reminders are sent back to the channel they were asked for in, so they only work through a plugin channel."
+	}
+	msg, err := a.ScheduleMessage(ctx, &memory.ScheduledMessage{
+		Kind:      ScheduleReminder,
+		Platform:  channel.Platform,
+		ChannelID: channel.ChannelID,
+		ThreadID:  channel.ThreadID,
+		Content:   text,
+		DeliverAt: time.Now().Add(d),
+		CreatedBy: SessionFromContext(ctx),
+	})
+	if err != nil {
+		return fmt.Sprintf("Could not schedule the reminder: %v", err)
+	}
+	return fmt.Sprintf("I'll remind you at %s.", msg.DeliverAt.UTC().Format(time.RFC1123))
+}
+
+// findListedProposal finds an open proposal by its number in /proposals or
+// by its ID, or a prefix of it naming only one proposal. When none is found
+// it returns the reply saying why.
+func (a *Agent) findListedProposal(ref, scope string) (*governance.Proposal, string) {
+	listed := listedProposals(a.governance.GetOpenProposals(), scope)
+	if n, err := strconv.Atoi(ref); err == nil {
+		if n < 1 || n > len(listed) {
+			return nil, fmt.Sprintf("There is no open proposal %d; /proposals lists them.", n)
+		}
+		return listed[n-1], ""
+	}
+
+	var found *governance.Proposal
+	for _, p := range listed {
+		if !strings.HasPrefix(strings.ToLower(p.ProposalID), ref) {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Sprintf("More than one open proposal starts with %s; use its number or a longer ID.", ref)
+		}
+		found = p
+	}
+	if found == nil {
+		return nil, fmt.Sprintf("There is no open proposal %s; /proposals lists them.", ref)
+	}
+	return found, ""
+}
+
+// runCommandTool runs the tool a command stands for, unless the rules
+// governing the turn's scope forbid it
+func (a *Agent) runCommandTool(ctx context.Context, turn *chatTurn, tool string, args map[string]string) string {
+	scope := ContextOptionsFromContext(ctx).Scope
+	if violations := a.governance.CheckToolCall(ctx, scope, tool, args); len(violations) > 0 {
+		a.recordViolations(ctx, turn, violations, governance.ViolationBlocked)
+		return fmt.Sprintf("That command was not run because it would break %s.", describeViolations(violations))
+	}
+	result, err := a.toolHandlers()[tool](ctx, args)
+	if err != nil {
+		return fmt.Sprintf("The command failed: %v", err)
+	}
+	return result
+}
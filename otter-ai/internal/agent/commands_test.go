@@ -0,0 +1,176 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"otter-ai/internal/governance"
+	"otter-ai/internal/memory"
+)
+
+func TestParseCommand(t *testing.T) {
+	for _, tt := range []struct {
+		message, name, args string
+		ok                  bool
+	}{
+		{"/rules", "rules", "", true},
+		{"  /Vote 2 yes ", "vote", "2 yes", true},
+		{"/propose safety: wear a lifejacket", "propose", "safety: wear a lifejacket", true},
+		{"/etc/hosts is a file", "", "", false},
+		{"what are the /rules?", "", "", false},
+	} {
+		name, args, ok := parseCommand(tt.message)
+		if name != tt.name || args != tt.args || ok != tt.ok {
+			t.Errorf("parseCommand(%q) = %q, %q, %v", tt.message, name, args, ok)
+		}
+	}
+
+	for args, want := range map[string][2]string{
+		"safety: wear a lifejacket": {"safety", "wear a lifejacket"},
+		"wear a lifejacket":         {"", "wear a lifejacket"},
+		"no shouting: ever":         {"", "no shouting: ever"},
+	} {
+		if scope, body := parseProposeArgs(args); scope != want[0] || body != want[1] {
+			t.Errorf("parseProposeArgs(%q) = %q, %q", args, scope, body)
+		}
+	}
+}
+
+func TestHandleCommand_Governance(t *testing.T) {
+	provider := &requestRecorder{reply: "ok"}
+	a := governedAgent(t, provider, "general", "be kind")
+	ctx := context.Background()
+	send := func(message string) string {
+		t.Helper()
+		resp, err := a.ProcessMessage(ctx, message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	if resp := send("/rules"); !strings.Contains(resp, "Raft otter-1 (own raft, 1 active member):\n    • be kind (scope: general)") {
+		t.Errorf("/rules = %q", resp)
+	}
+	if resp := send("/propose safety: wear a lifejacket"); !strings.Contains(resp, "Rule proposal submitted") || !strings.Contains(resp, "Scope: safety") {
+		t.Errorf("/propose = %q", resp)
+	}
+	if resp := send("/proposals"); !strings.Contains(resp, "  1. Proposal ID:") || !strings.Contains(resp, "Text: wear a lifejacket") {
+		t.Errorf("/proposals = %q", resp)
+	}
+	if resp := send("/vote 2 yes"); !strings.Contains(resp, "no open proposal 2") {
+		t.Errorf("/vote out of range = %q", resp)
+	}
+	if resp := send("/vote 1 yes"); !strings.HasPrefix(resp, "Voted YES") {
+		t.Errorf("/vote = %q", resp)
+	}
+	if resp := send("/proposals"); resp != "OPEN PROPOSALS: None currently open." {
+		t.Errorf("/proposals after the vote = %q", resp)
+	}
+	if resp := send("/dance"); !strings.HasPrefix(resp, "Unknown command /dance.") {
+		t.Errorf("/dance = %q", resp)
+	}
+	if len(provider.requests) != 0 {
+		t.Errorf("commands made %d completions, want none", len(provider.requests))
+	}
+
+	send("/etc/hosts lists host names")
+	if len(provider.requests) != 1 {
+		t.Errorf("a path should reach the LLM")
+	}
+}
+
+func TestHandleCommand_DeniedByRule(t *testing.T) {
+	a := governedAgent(t, &requestRecorder{}, "governance", "deny tool: propose_rule")
+
+	resp, err := a.ProcessMessage(context.Background(), "/propose be loud")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(resp, "That command was not run") {
+		t.Errorf("/propose = %q", resp)
+	}
+	if proposals := a.governance.GetOpenProposals(); len(proposals) != 0 {
+		t.Errorf("denied command ran: %d open proposals", len(proposals))
+	}
+	if audited := violationActions(t, a.governance); len(audited) != 1 {
+		t.Errorf("audited violations = %v", audited)
+	}
+}
+
+func TestHandleCommand_Forget(t *testing.T) {
+	a := newTestConsolidationAgent(t, &mockLLMProvider{})
+	gov, err := governance.New(governance.RaftConfig{ID: "otter-1", DataDir: t.TempDir()}, a.memory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { gov.Shutdown(context.Background()) })
+	a.governance = gov
+
+	ctx := WithUser(context.Background(), "slack:U1")
+	mine := &memory.MemoryRecord{Type: memory.MemoryTypeLongTerm, Content: "Dana likes kelp", Embedding: []float32{1, 0},
+		Metadata: map[string]interface{}{"user_id": "slack:U1"}}
+	if err := a.memory.Store(ctx, mine); err != nil {
+		t.Fatal(err)
+	}
+	theirs := storeTestMemory(t, a, "Sam likes kelp too", []float32{1, 0}, 0, 0.5, false)
+	send := func(message string) string {
+		t.Helper()
+		resp, err := a.ProcessMessage(ctx, message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	if resp := send("/forget"); !strings.HasPrefix(resp, "Usage: /forget") {
+		t.Errorf("/forget = %q", resp)
+	}
+	if resp := send("/forget rocks"); resp != "I don't have any memories matching that." {
+		t.Errorf("/forget rocks = %q", resp)
+	}
+	anonymous, err := a.ProcessMessage(context.Background(), "/forget kelp")
+	if err != nil || !strings.HasPrefix(anonymous, "I don't know who you are") {
+		t.Errorf("/forget kelp without a user = %q, %v; want refused", anonymous, err)
+	}
+	if resp := send("/forget KELP"); !strings.HasPrefix(resp, "This will permanently erase 1 memories") {
+		t.Errorf("/forget KELP = %q", resp)
+	}
+	if _, err := a.memory.Get(ctx, mine.ID, memory.MemoryTypeLongTerm); err != nil {
+		t.Errorf("nothing should be erased before confirming: %v", err)
+	}
+
+	// Only the user who asked, in the session they asked in, can confirm
+	for name, other := range map[string]context.Context{
+		"another user":    WithUser(context.Background(), "slack:U2"),
+		"another session": WithSession(ctx, "elsewhere"),
+	} {
+		if resp, _ := a.ProcessMessage(other, "confirm"); strings.HasPrefix(resp, "Forgotten") {
+			t.Errorf("%s confirmed the erasure: %q", name, resp)
+		}
+	}
+	if _, err := a.memory.Get(ctx, mine.ID, memory.MemoryTypeLongTerm); err != nil {
+		t.Errorf("nothing should be erased before its requester confirms: %v", err)
+	}
+
+	if resp := send("confirm"); !strings.HasPrefix(resp, "Forgotten: 1 memories erased.") {
+		t.Errorf("confirm = %q", resp)
+	}
+	var requesters []string
+	gov.EachAuditEntry(context.Background(), governance.AuditFilter{}, func(entry governance.AuditEntry) error {
+		if entry.Action == governance.AuditMemoryErased {
+			requesters = append(requesters, entry.Actor)
+		}
+		return nil
+	})
+	if len(requesters) != 1 || requesters[0] != "slack:U1" {
+		t.Errorf("tombstones requested by %v, want slack:U1", requesters)
+	}
+	if _, err := a.memory.Get(ctx, mine.ID, memory.MemoryTypeLongTerm); err == nil {
+		t.Error("the user's memory should be erased")
+	}
+	if _, err := a.memory.Get(ctx, theirs.ID, memory.MemoryTypeLongTerm); err != nil {
+		t.Errorf("another user's memory should be kept: %v", err)
+	}
+}
@@ -0,0 +1,226 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"otter-ai/internal/llm"
+	"otter-ai/internal/memory"
+	"otter-ai/internal/prompts"
+	"otter-ai/internal/vectordb"
+)
+
+// ConsolidationTimeout bounds one consolidation run
+const ConsolidationTimeout = 10 * time.Minute
+
+// ConsolidationConfig tunes the job that summarizes clusters of old,
+// low-importance long-term memories
+type ConsolidationConfig struct {
+	Interval        time.Duration // How often the job runs; zero disables it
+	MinAge          time.Duration // Only memories older than this are consolidated
+	MaxImportance   float32       // Only memories at or below this importance are consolidated
+	Similarity      float64       // Minimum cosine similarity to a cluster's first memory
+	MinClusterSize  int           // Smaller clusters are left alone
+	MaxClusterSize  int
+	BatchSize       int  // Most memories considered per run, oldest first
+	DeleteOriginals bool // Delete summarized memories instead of archiving them
+}
+
+// ConsolidationResult reports what one consolidation run did
+type ConsolidationResult struct {
+	Candidates   int      // Memories eligible for consolidation
+	Summaries    []string // IDs of the summary memories stored
+	Consolidated int      // Originals archived or deleted
+}
+
+func (a *Agent) startConsolidationLoop() {
+	a.startPeriodicJob(a.consolidation.Interval, ConsolidationTimeout, a.unlessReadOnly(func(ctx context.Context) {
+		result, err := a.ConsolidateMemories(ctx)
+		if err != nil {
+			a.log().Warn("memory consolidation stopped", "error", err)
+		}
+		if result != nil && len(result.Summaries) > 0 {
+			a.log().Info("consolidated memories", "memories", result.Consolidated, "summaries", len(result.Summaries))
+		}
+	}))
+}
+
+// startPeriodicJob runs job every interval until the agent shuts down. Each
+// run gets a context bounded by timeout and cancelled on shutdown, whose LLM
+// requests wait behind interactive ones.
+func (a *Agent) startPeriodicJob(interval, timeout time.Duration, job func(ctx context.Context)) {
+	a.idleWG.Add(1)
+	go func() {
+		defer a.idleWG.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(llm.WithPriority(context.Background(), llm.PriorityBackground), timeout)
+				go func() {
+					select {
+					case <-a.idleStop:
+						cancel()
+					case <-ctx.Done():
+					}
+				}()
+				job(ctx)
+				cancel()
+			case <-a.idleStop:
+				return
+			}
+		}
+	}()
+}
+
+// ConsolidateMemories clusters old, low-importance long-term memories by
+// embedding similarity, stores an LLM summary of each cluster as a new
+// memory and archives (or deletes) the originals. Pinned and held memories
+// and earlier summaries are never consolidated. The result covers the
+// clusters finished before any error.
+func (a *Agent) ConsolidateMemories(ctx context.Context) (*ConsolidationResult, error) {
+	cfg := a.consolidation
+	cutoff := time.Now().Add(-cfg.MinAge)
+
+	var candidates []memory.MemoryRecord
+	err := a.memory.Each(ctx, memory.MemoryTypeLongTerm, func(record memory.MemoryRecord) error {
+		if _, summary := record.Metadata["consolidated_count"]; summary {
+			return nil
+		}
+		if record.Prunable() && record.Importance <= cfg.MaxImportance &&
+			record.Timestamp.Before(cutoff) && len(record.Embedding) > 0 {
+			candidates = append(candidates, record)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan memories: %w", err)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Timestamp.Before(candidates[j].Timestamp)
+	})
+	if cfg.BatchSize > 0 && len(candidates) > cfg.BatchSize {
+		candidates = candidates[:cfg.BatchSize]
+	}
+
+	result := &ConsolidationResult{Candidates: len(candidates)}
+	for _, cluster := range clusterMemories(candidates, cfg) {
+		summaryID, err := a.consolidateCluster(ctx, cluster)
+		if err != nil {
+			return result, err
+		}
+		result.Summaries = append(result.Summaries, summaryID)
+
+		for i := range cluster {
+			if cfg.DeleteOriginals {
+				err = a.memory.Delete(ctx, cluster[i].ID, memory.MemoryTypeLongTerm)
+			} else {
+				err = a.memory.Archive(ctx, &cluster[i], summaryID)
+			}
+			if err != nil {
+				a.log().WarnContext(ctx, "failed to retire consolidated memory", "memory_id", cluster[i].ID, "error", err)
+				continue
+			}
+			result.Consolidated++
+		}
+	}
+
+	return result, nil
+}
+
+// clusterMemories greedily groups memories, oldest first, with later
+// memories of the same scope that are similar enough to the group's first
+// memory. Groups smaller than MinClusterSize are dropped.
+func clusterMemories(records []memory.MemoryRecord, cfg ConsolidationConfig) [][]memory.MemoryRecord {
+	assigned := make([]bool, len(records))
+	var clusters [][]memory.MemoryRecord
+
+	for i := range records {
+		if assigned[i] {
+			continue
+		}
+		members := []int{i}
+		for j := i + 1; j < len(records); j++ {
+			if cfg.MaxClusterSize > 0 && len(members) >= cfg.MaxClusterSize {
+				break
+			}
+			if assigned[j] || records[j].Scope != records[i].Scope {
+				continue
+			}
+			if vectordb.CosineSimilarity(records[i].Embedding, records[j].Embedding) >= cfg.Similarity {
+				members = append(members, j)
+			}
+		}
+		if len(members) < cfg.MinClusterSize {
+			continue
+		}
+
+		cluster := make([]memory.MemoryRecord, 0, len(members))
+		for _, idx := range members {
+			assigned[idx] = true
+			cluster = append(cluster, records[idx])
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	return clusters
+}
+
+// consolidateCluster summarizes a cluster and stores the summary, dated by
+// its newest memory so it keeps their place in time
+func (a *Agent) consolidateCluster(ctx context.Context, cluster []memory.MemoryRecord) (string, error) {
+	contents := make([]string, 0, len(cluster))
+	importance := float32(0)
+	for _, record := range cluster {
+		contents = append(contents, sanitizeForPrompt(strings.TrimSpace(record.Content)))
+		if record.Importance > importance {
+			importance = record.Importance
+		}
+	}
+	prompt := a.renderPrompt(ctx, prompts.Consolidation, struct{ Memories []string }{contents})
+
+	completion, err := a.llm.Complete(ctx, &llm.CompletionRequest{
+		Prompt:  prompt,
+		Profile: llm.ProfileSummary,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize memories: %w", err)
+	}
+	summary := strings.TrimSpace(completion.Text)
+	if summary == "" {
+		return "", fmt.Errorf("failed to summarize memories: empty summary")
+	}
+
+	embedding, err := a.embed(ctx, summary)
+	if err != nil {
+		return "", fmt.Errorf("failed to embed summary: %w", err)
+	}
+
+	oldest, newest := cluster[0].Timestamp, cluster[len(cluster)-1].Timestamp
+	record := &memory.MemoryRecord{
+		Type:       memory.MemoryTypeLongTerm,
+		Content:    summary,
+		Embedding:  embedding,
+		Timestamp:  newest,
+		Scope:      cluster[0].Scope,
+		Importance: importance,
+		Metadata: map[string]interface{}{
+			"content_source":     memory.SourceAgentGenerated,
+			"consolidated_count": len(cluster),
+			"consolidated_since": oldest.Unix(),
+			"consolidated_until": newest.Unix(),
+		},
+	}
+	if err := a.memory.Store(ctx, record); err != nil {
+		return "", fmt.Errorf("failed to store summary: %w", err)
+	}
+
+	return record.ID, nil
+}
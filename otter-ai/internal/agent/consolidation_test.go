@@ -0,0 +1,157 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"otter-ai/internal/memory"
+)
+
+func newTestConsolidationAgent(t *testing.T, llmProv *mockLLMProvider) *Agent {
+	t.Helper()
+	a := newTestAgent(llmProv)
+	a.memory = newTestSQLiteMemory(t)
+	a.consolidation = ConsolidationConfig{
+		MinAge:         24 * time.Hour,
+		MaxImportance:  0.5,
+		Similarity:     0.9,
+		MinClusterSize: 3,
+		MaxClusterSize: 10,
+	}
+	return a
+}
+
+func storeTestMemory(t *testing.T, a *Agent, content string, embedding []float32, age time.Duration, importance float32, pinned bool) *memory.MemoryRecord {
+	t.Helper()
+	record := &memory.MemoryRecord{
+		Type:       memory.MemoryTypeLongTerm,
+		Content:    content,
+		Embedding:  embedding,
+		Timestamp:  time.Now().Add(-age),
+		Importance: importance,
+		Pinned:     pinned,
+	}
+	if err := a.memory.Store(context.Background(), record); err != nil {
+		t.Fatal(err)
+	}
+	return record
+}
+
+func TestConsolidateMemories_ArchivesCluster(t *testing.T) {
+	a := newTestConsolidationAgent(t, &mockLLMProvider{completeResp: "The user likes otters."})
+	ctx := context.Background()
+	day := 24 * time.Hour
+
+	var originals []*memory.MemoryRecord
+	for i, content := range []string{"otters are great", "I love otters", "otters hold hands", "sea otters"} {
+		originals = append(originals, storeTestMemory(t, a, content, []float32{1, 0.01 * float32(i)}, time.Duration(10-i)*day, 0.5, false))
+	}
+	pinned := storeTestMemory(t, a, "pinned otter fact", []float32{1, 0}, 10*day, 0.5, true)
+	recent := storeTestMemory(t, a, "otters today", []float32{1, 0}, time.Hour, 0.5, false)
+	important := storeTestMemory(t, a, "important otter", []float32{1, 0}, 10*day, 0.9, false)
+	storeTestMemory(t, a, "weather", []float32{0, 1}, 10*day, 0.5, false)
+	storeTestMemory(t, a, "rain", []float32{0, 1}, 10*day, 0.5, false)
+
+	result, err := a.ConsolidateMemories(ctx)
+	if err != nil {
+		t.Fatalf("ConsolidateMemories: %v", err)
+	}
+	if result.Candidates != 6 || len(result.Summaries) != 1 || result.Consolidated != 4 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	summary, err := a.memory.Get(ctx, result.Summaries[0], memory.MemoryTypeLongTerm)
+	if err != nil {
+		t.Fatalf("Get summary: %v", err)
+	}
+	if summary.Content != "The user likes otters." || !summary.Timestamp.Equal(time.Unix(originals[3].Timestamp.Unix(), 0)) {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+	if count, _ := summary.Metadata["consolidated_count"].(float64); count != 4 {
+		t.Errorf("consolidated_count = %v, want 4", summary.Metadata["consolidated_count"])
+	}
+
+	for _, original := range originals {
+		if _, err := a.memory.Get(ctx, original.ID, memory.MemoryTypeLongTerm); err == nil {
+			t.Errorf("memory %q should have left long-term memory", original.Content)
+		}
+		archived, err := a.memory.Get(ctx, original.ID, memory.MemoryTypeArchived)
+		if err != nil {
+			t.Fatalf("archived memory %q: %v", original.Content, err)
+		}
+		if archived.Metadata["consolidated_into"] != summary.ID || archived.Metadata["archived_from"] != "long_term" {
+			t.Errorf("unexpected archive metadata %v", archived.Metadata)
+		}
+	}
+	for _, kept := range []*memory.MemoryRecord{pinned, recent, important} {
+		if _, err := a.memory.Get(ctx, kept.ID, memory.MemoryTypeLongTerm); err != nil {
+			t.Errorf("memory %q should be kept: %v", kept.Content, err)
+		}
+	}
+
+	// Summaries are not consolidated again
+	result, err = a.ConsolidateMemories(ctx)
+	if err != nil || len(result.Summaries) != 0 {
+		t.Errorf("second run = %+v, %v", result, err)
+	}
+}
+
+func TestConsolidateMemories_DeleteOriginals(t *testing.T) {
+	a := newTestConsolidationAgent(t, &mockLLMProvider{completeResp: "summary"})
+	a.consolidation.DeleteOriginals = true
+	ctx := context.Background()
+
+	var originals []*memory.MemoryRecord
+	for i, content := range []string{"a", "b", "c"} {
+		originals = append(originals, storeTestMemory(t, a, content, []float32{1, 0}, time.Duration(48+i)*time.Hour, 0.1, false))
+	}
+
+	if _, err := a.ConsolidateMemories(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for _, original := range originals {
+		if _, err := a.memory.Get(ctx, original.ID, memory.MemoryTypeArchived); err == nil {
+			t.Errorf("memory %q should be deleted, not archived", original.Content)
+		}
+	}
+}
+
+func TestConsolidateMemories_LLMFailureKeepsOriginals(t *testing.T) {
+	a := newTestConsolidationAgent(t, &mockLLMProvider{completeErr: errors.New("down")})
+	ctx := context.Background()
+
+	var originals []*memory.MemoryRecord
+	for _, content := range []string{"a", "b", "c"} {
+		originals = append(originals, storeTestMemory(t, a, content, []float32{1, 0}, 48*time.Hour, 0.1, false))
+	}
+
+	if _, err := a.ConsolidateMemories(ctx); err == nil {
+		t.Fatal("expected error when the LLM is down")
+	}
+	for _, original := range originals {
+		if _, err := a.memory.Get(ctx, original.ID, memory.MemoryTypeLongTerm); err != nil {
+			t.Errorf("memory %q should be kept: %v", original.Content, err)
+		}
+	}
+}
+
+func TestClusterMemories_SeparatesScopes(t *testing.T) {
+	records := []memory.MemoryRecord{
+		{ID: "1", Embedding: []float32{1, 0}, Scope: "work"},
+		{ID: "2", Embedding: []float32{1, 0}, Scope: "home"},
+		{ID: "3", Embedding: []float32{1, 0}, Scope: "work"},
+		{ID: "4", Embedding: []float32{1, 0}, Scope: "home"},
+		{ID: "5", Embedding: []float32{1, 0}, Scope: "work"},
+	}
+	clusters := clusterMemories(records, ConsolidationConfig{Similarity: 0.9, MinClusterSize: 2, MaxClusterSize: 2})
+	if len(clusters) != 2 {
+		t.Fatalf("clusters = %v", clusters)
+	}
+	for _, cluster := range clusters {
+		if len(cluster) != 2 || cluster[0].Scope != cluster[1].Scope {
+			t.Errorf("unexpected cluster %v", cluster)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Context control configuration
+const (
+	MaxContextScopeLength = 64
+	ScopedListWindow      = 50
+)
+
+// memoryTools read from stored memories and are withheld when memory is off
+var memoryTools = map[string]bool{
+	"search_memories":  true,
+	"get_last_memory":  true,
+	"compare_memories": true,
+}
+
+// governanceContextTools expose rules and proposals and are withheld when
+// governance context is off. Proposing and voting stay available.
+var governanceContextTools = map[string]bool{
+	"list_governance_state": true,
+}
+
+// ContextOptions controls what is injected into the prompt for a turn.
+// The zero value injects everything.
+type ContextOptions struct {
+	NoMemory     bool   `json:"no_memory,omitempty"`     // Skip pinned facts and memory retrieval
+	NoGovernance bool   `json:"no_governance,omitempty"` // Skip governance rules and proposals
+	Scope        string `json:"scope,omitempty"`         // Only retrieve memories and rules in this scope
+}
+
+// IsZero reports whether the options leave the prompt unchanged
+func (o ContextOptions) IsZero() bool {
+	return !o.NoMemory && !o.NoGovernance && o.Scope == ""
+}
+
+// Merge narrows o with per-turn options. Switches can only be turned off
+// for a turn; a per-turn scope replaces the session scope.
+func (o ContextOptions) Merge(turn ContextOptions) ContextOptions {
+	merged := o
+	merged.NoMemory = o.NoMemory || turn.NoMemory
+	merged.NoGovernance = o.NoGovernance || turn.NoGovernance
+	if turn.Scope != "" {
+		merged.Scope = turn.Scope
+	}
+	return merged
+}
+
+// Validate checks client-supplied options
+func (o ContextOptions) Validate() error {
+	if len(o.Scope) > MaxContextScopeLength {
+		return fmt.Errorf("scope too long (max %d characters)", MaxContextScopeLength)
+	}
+	if strings.ContainsAny(o.Scope, "\r\n") {
+		return fmt.Errorf("scope must be a single line")
+	}
+	return nil
+}
+
+// describe renders the options as a short human-readable summary
+func (o ContextOptions) describe() string {
+	memoryState, governanceState, scope := "on", "on", "any"
+	if o.NoMemory {
+		memoryState = "off"
+	}
+	if o.NoGovernance {
+		governanceState = "off"
+	}
+	if o.Scope != "" {
+		scope = o.Scope
+	}
+	return fmt.Sprintf("memory: %s, rules: %s, scope: %s", memoryState, governanceState, scope)
+}
+
+// promptNote tells the LLM which context was deliberately withheld
+func (o ContextOptions) promptNote() string {
+	if o.IsZero() {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("Context controls for this turn:\n")
+	if o.NoMemory {
+		b.WriteString("- Memory retrieval is disabled; do not claim to remember past conversations.\n")
+	}
+	if o.NoGovernance {
+		b.WriteString("- Governance rules are not provided for this turn.\n")
+	}
+	if o.Scope != "" {
+		b.WriteString(fmt.Sprintf("- Only memories and rules in scope %q are available.\n", sanitizeForPrompt(o.Scope)))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// allowsTool reports whether a tool may be offered and executed under o
+func (o ContextOptions) allowsTool(name string) bool {
+	if o.NoMemory && memoryTools[name] {
+		return false
+	}
+	if o.NoGovernance && governanceContextTools[name] {
+		return false
+	}
+	// Comparisons summarize recent memories across every scope
+	if o.Scope != "" && name == "compare_memories" {
+		return false
+	}
+	return true
+}
+
+type contextOptionsKey struct{}
+
+// WithContextOptions returns a context carrying per-turn context controls
+func WithContextOptions(ctx context.Context, opts ContextOptions) context.Context {
+	return context.WithValue(ctx, contextOptionsKey{}, opts)
+}
+
+// ContextOptionsFromContext returns the context controls carried by ctx
+func ContextOptionsFromContext(ctx context.Context) ContextOptions {
+	opts, _ := ctx.Value(contextOptionsKey{}).(ContextOptions)
+	return opts
+}
+
+// parseContextCommand recognizes "context ..." chat commands. The returned
+// fields are the words after "context", lowercased except for a scope name;
+// ok is false for other messages.
+func parseContextCommand(message string) ([]string, bool) {
+	fields := strings.Fields(strings.TrimRight(strings.TrimSpace(message), "?.! "))
+	if len(fields) == 0 || strings.ToLower(fields[0]) != "context" {
+		return nil, false
+	}
+	if len(fields) == 1 {
+		return nil, true
+	}
+	args := fields[1:]
+	for i := range args {
+		if i == 1 && strings.ToLower(args[0]) == "scope" {
+			continue
+		}
+		args[i] = strings.ToLower(args[i])
+	}
+	switch args[0] {
+	case "memory", "memories", "rules", "governance", "scope", "reset", "show":
+		return args, true
+	}
+	return nil, false
+}
+
+// handleContextCommands adjusts the session's context controls from chat:
+//
+//	context                      show current settings
+//	context memory on|off        toggle memory retrieval
+//	context rules on|off         toggle governance rules context
+//	context scope <name>|any     restrict retrieval to a scope
+//	context reset                restore the defaults
+func (a *Agent) handleContextCommands(session *Session, message string) (string, bool) {
+	args, ok := parseContextCommand(message)
+	if !ok {
+		return "", false
+	}
+
+	opts := session.ContextOptions()
+	usage := "Usage: context [memory on|off] [rules on|off] [scope <name>|any] [reset]"
+
+	if len(args) == 0 || args[0] == "show" {
+		return fmt.Sprintf("Context for this conversation — %s.", opts.describe()), true
+	}
+
+	switch args[0] {
+	case "reset":
+		opts = ContextOptions{}
+	case "memory", "memories", "rules", "governance":
+		if len(args) != 2 || (args[1] != "on" && args[1] != "off") {
+			return usage, true
+		}
+		off := args[1] == "off"
+		if args[0] == "rules" || args[0] == "governance" {
+			opts.NoGovernance = off
+		} else {
+			opts.NoMemory = off
+		}
+	case "scope":
+		if len(args) != 2 {
+			return usage, true
+		}
+		opts.Scope = args[1]
+		if strings.EqualFold(opts.Scope, "any") || strings.EqualFold(opts.Scope, "all") {
+			opts.Scope = ""
+		}
+		if err := opts.Validate(); err != nil {
+			return fmt.Sprintf("I couldn't set that scope: %v", err), true
+		}
+	}
+
+	session.SetContextOptions(opts)
+	return fmt.Sprintf("Updated context for this conversation — %s.", opts.describe()), true
+}
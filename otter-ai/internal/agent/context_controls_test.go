@@ -0,0 +1,192 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"otter-ai/internal/llm"
+	"otter-ai/internal/memory"
+)
+
+// recordingLLM captures the last completion request it received
+type recordingLLM struct {
+	mockLLMProvider
+	last *llm.CompletionRequest
+}
+
+func (m *recordingLLM) Complete(ctx context.Context, req *llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	m.last = req
+	return m.mockLLMProvider.Complete(ctx, req)
+}
+
+func toolNames(tools []llm.ToolDefinition) map[string]bool {
+	names := make(map[string]bool)
+	for _, tool := range tools {
+		names[tool.Name] = true
+	}
+	return names
+}
+
+// --- ContextOptions ---
+
+func TestContextOptions_Merge(t *testing.T) {
+	session := ContextOptions{NoMemory: true, Scope: "general"}
+
+	merged := session.Merge(ContextOptions{NoGovernance: true, Scope: "safety"})
+	if !merged.NoMemory || !merged.NoGovernance || merged.Scope != "safety" {
+		t.Errorf("unexpected merge result: %+v", merged)
+	}
+
+	if merged := session.Merge(ContextOptions{}); merged != session {
+		t.Errorf("empty turn options should keep session options, got %+v", merged)
+	}
+}
+
+func TestContextOptions_Validate(t *testing.T) {
+	if err := (ContextOptions{Scope: "safety"}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := (ContextOptions{Scope: strings.Repeat("s", MaxContextScopeLength+1)}).Validate(); err == nil {
+		t.Error("expected error for long scope")
+	}
+	if err := (ContextOptions{Scope: "a\nb"}).Validate(); err == nil {
+		t.Error("expected error for multi-line scope")
+	}
+}
+
+func TestFilterTools(t *testing.T) {
+	a := newTestAgent(nil)
+
+	names := toolNames(filterTools(a.agentTools(), ContextOptions{NoMemory: true}, nil))
+	if names["search_memories"] || names["get_last_memory"] {
+		t.Error("memory tools should be withheld")
+	}
+	if !names["get_health_status"] {
+		t.Error("health tool should remain")
+	}
+
+	names = toolNames(filterTools(a.agentTools(), ContextOptions{Scope: "safety"}, nil))
+	if names["compare_memories"] || !names["search_memories"] {
+		t.Errorf("scoped turn should drop only compare_memories, got %v", names)
+	}
+}
+
+func TestExecuteTool_DisabledByContext(t *testing.T) {
+	a := newTestAgent(&mockLLMProvider{})
+	ctx := WithContextOptions(context.Background(), ContextOptions{NoMemory: true})
+
+	result := a.executeTool(ctx, llm.ToolCall{Name: "get_last_memory"})
+	if !strings.Contains(result, "disabled") {
+		t.Errorf("expected disabled message, got %q", result)
+	}
+}
+
+func TestFilterMemoryScope(t *testing.T) {
+	records := []memory.MemoryRecord{{ID: "1", Scope: "safety"}, {ID: "2"}, {ID: "3", Scope: "safety"}}
+
+	if got := filterMemoryScope(records, ""); len(got) != 3 {
+		t.Errorf("empty scope should keep all, got %d", len(got))
+	}
+	got := filterMemoryScope(records, "safety")
+	if len(got) != 2 || got[0].ID != "1" || got[1].ID != "3" {
+		t.Errorf("unexpected filtered records: %+v", got)
+	}
+}
+
+// --- chat commands ---
+
+func TestParseContextCommand(t *testing.T) {
+	cases := []struct {
+		message string
+		want    string
+		ok      bool
+	}{
+		{"context", "", true},
+		{"Context memory OFF", "memory off", true},
+		{"context scope Safety", "scope Safety", true},
+		{"context reset.", "reset", true},
+		{"context is everything", "", false},
+		{"what is the context", "", false},
+	}
+	for _, c := range cases {
+		args, ok := parseContextCommand(c.message)
+		if ok != c.ok || strings.Join(args, " ") != c.want {
+			t.Errorf("parseContextCommand(%q) = %v, %v; want %q, %v", c.message, args, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestHandleContextCommands(t *testing.T) {
+	a := newTestSessionAgent(&mockLLMProvider{completeResp: "ok"})
+	ctx := WithSession(context.Background(), "private")
+
+	for _, cmd := range []string{"context memory off", "context rules off", "context scope safety"} {
+		if _, err := a.ProcessMessage(ctx, cmd); err != nil {
+			t.Fatalf("ProcessMessage(%q): %v", cmd, err)
+		}
+	}
+
+	opts := a.sessions.Get(ctx, "private").ContextOptions()
+	if !opts.NoMemory || !opts.NoGovernance || opts.Scope != "safety" {
+		t.Errorf("unexpected session options: %+v", opts)
+	}
+	if a.session(context.Background()).ContextOptions() != (ContextOptions{}) {
+		t.Error("other sessions should be unaffected")
+	}
+
+	response, _ := a.ProcessMessage(ctx, "context reset")
+	if !strings.Contains(response, "memory: on") {
+		t.Errorf("unexpected reset response: %q", response)
+	}
+	if !a.sessions.Get(ctx, "private").ContextOptions().IsZero() {
+		t.Error("reset should restore defaults")
+	}
+}
+
+func TestHandleContextCommands_Usage(t *testing.T) {
+	a := newTestSessionAgent(&mockLLMProvider{completeResp: "ok"})
+
+	response, _ := a.ProcessMessage(context.Background(), "context memory maybe")
+	if !strings.HasPrefix(response, "Usage:") {
+		t.Errorf("expected usage, got %q", response)
+	}
+}
+
+// --- ProcessMessage ---
+
+func TestProcessMessage_TurnOptionsLimitPrompt(t *testing.T) {
+	rec := &recordingLLM{mockLLMProvider: mockLLMProvider{completeResp: "ok"}}
+	a := newTestAgent(rec)
+
+	ctx := WithContextOptions(context.Background(), ContextOptions{NoMemory: true, Scope: "safety"})
+	if _, err := a.ProcessMessage(ctx, "hello"); err != nil {
+		t.Fatalf("ProcessMessage: %v", err)
+	}
+
+	names := toolNames(rec.last.Tools)
+	if names["search_memories"] || names["compare_memories"] {
+		t.Errorf("memory tools should not be offered, got %v", names)
+	}
+	if !strings.Contains(rec.last.SystemPrompt, "Memory retrieval is disabled") {
+		t.Error("system prompt should note that memory is disabled")
+	}
+
+	if _, err := a.ProcessMessage(context.Background(), "hello again"); err != nil {
+		t.Fatalf("ProcessMessage: %v", err)
+	}
+	if !toolNames(rec.last.Tools)["search_memories"] {
+		t.Error("turn options should not persist to the next turn")
+	}
+}
+
+func TestSessionRecord_PersistsContextOptions(t *testing.T) {
+	session := &Session{ID: "s", History: &ConversationHistory{}}
+	session.SetContextOptions(ContextOptions{NoGovernance: true, Scope: "safety"})
+
+	restored := &Session{ID: "s", History: &ConversationHistory{}}
+	restored.restore(session.record())
+	if got := restored.ContextOptions(); !got.NoGovernance || got.Scope != "safety" {
+		t.Errorf("context options not restored: %+v", got)
+	}
+}
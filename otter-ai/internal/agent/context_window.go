@@ -0,0 +1,172 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"otter-ai/internal/llm"
+	"otter-ai/internal/prompts"
+)
+
+// DefaultReplyTokens is the room kept for the reply when neither the
+// channel profile nor the configuration sets its length
+const DefaultReplyTokens = 500
+
+// toolResultShare is the part (one in toolResultShare) of the room left by
+// the fixed prompt that is held back for tool results when tools are offered
+const toolResultShare = 4
+
+// ContextWindowConfig sizes chat prompts to the model's context window
+type ContextWindowConfig struct {
+	Tokens      int // Tokens the model reads per request; zero leaves prompts unbounded
+	ReplyTokens int // Room kept for the reply; zero keeps DefaultReplyTokens
+}
+
+// promptBudget counts down the tokens left for a prompt, by
+// llm.CountTokens. A nil budget is unbounded.
+type promptBudget struct {
+	left int
+}
+
+// take claims room for text and reports whether it fit
+func (b *promptBudget) take(text string) bool {
+	if b == nil {
+		return true
+	}
+	n := llm.CountTokens(text)
+	if n > b.left {
+		return false
+	}
+	b.left -= n
+	return true
+}
+
+// truncate returns text shortened to the room left, and claims it
+func (b *promptBudget) truncate(text string) string {
+	if b == nil {
+		return text
+	}
+	text = llm.TruncateTokens(text, b.left)
+	b.left -= llm.CountTokens(text)
+	return text
+}
+
+// release gives back room claimed for text
+func (b *promptBudget) release(text string) {
+	if b != nil {
+		b.left += llm.CountTokens(text)
+	}
+}
+
+// budgetedList renders header and, in order, the items that fit as a list.
+// Nothing is rendered when no item fits.
+func budgetedList(budget *promptBudget, header string, items []string) string {
+	if len(items) == 0 || !budget.take(header+"\n") {
+		return ""
+	}
+	var b strings.Builder
+	for _, item := range items {
+		line := "- " + item + "\n"
+		if !budget.take(line) {
+			break
+		}
+		b.WriteString(line)
+	}
+	if b.Len() == 0 {
+		budget.release(header + "\n")
+		return ""
+	}
+	return header + "\n" + b.String() + "\n"
+}
+
+// replyTokens returns the room kept for a chat reply
+func (a *Agent) replyTokens(profile *ChannelProfile) int {
+	if profile != nil && profile.MaxTokens > 0 {
+		return profile.MaxTokens
+	}
+	if a.contextWindow.ReplyTokens > 0 {
+		return a.contextWindow.ReplyTokens
+	}
+	return DefaultReplyTokens
+}
+
+// toolTokens estimates the room tool definitions take in a request
+func toolTokens(tools []llm.ToolDefinition) int {
+	if len(tools) == 0 {
+		return 0
+	}
+	data, err := json.Marshal(tools)
+	if err != nil {
+		return 0
+	}
+	return llm.CountTokens(string(data))
+}
+
+// buildChatSystemPrompt renders the system prompt of a chat turn. With a
+// context window configured, the system prompt, message, offered tools and
+// reply must fit in it: the capability, personality and rules are always
+// included, and pinned facts, then the conversation, then musings get the
+// room they leave. The returned budget is the room left for tool results,
+// nil when prompts are unbounded.
+func (a *Agent) buildChatSystemPrompt(ctx context.Context, session *Session, opts ContextOptions, message string, tools, offered []llm.ToolDefinition) (string, *promptBudget) {
+	fixedBefore := a.buildCapabilityContext(ctx, tools) + a.buildPersonalityContext(ctx)
+	fixedAfter := opts.promptNote()
+
+	var budget *promptBudget
+	if window := a.contextWindow.Tokens; window > 0 {
+		base := llm.CountTokens(a.buildSystemPrompt(ctx, fixedBefore+fixedAfter, opts))
+		used := base + llm.CountTokens(message) + toolTokens(offered) + a.replyTokens(a.channelProfile(ctx))
+		if used > window {
+			a.log().WarnContext(ctx, "chat prompt exceeds the context window before history and memories",
+				"tokens", used, "context_window", window, "system_prompt_tokens", base)
+		}
+		budget = &promptBudget{left: max(window-used, 0)}
+	}
+
+	held := 0
+	if budget != nil && len(offered) > 0 {
+		held = budget.left / toolResultShare
+		budget.left -= held
+	}
+	var pinned, musings string
+	if !opts.NoMemory {
+		pinned = a.buildPinnedContext(ctx, budget)
+	}
+	conversation := a.buildSessionContext(session, budget)
+	if !opts.NoMemory {
+		musings = a.buildMusingContext(ctx, budget)
+	}
+	if budget != nil {
+		budget.left += held
+		a.log().DebugContext(ctx, "fitted chat prompt to the context window",
+			"context_window", a.contextWindow.Tokens, "tokens_left", budget.left)
+	}
+
+	systemPrompt := a.buildSystemPrompt(ctx, fixedBefore+pinned+musings+fixedAfter+conversation, opts)
+	return systemPrompt, budget
+}
+
+// toolFollowupPrompt renders the prompt answering a turn's tool calls. When
+// the results don't all fit the budget the latest are kept, the latest
+// truncated if it alone is too long.
+func (a *Agent) toolFollowupPrompt(ctx context.Context, message string, results []string, budget *promptBudget) string {
+	kept := results
+	if budget != nil {
+		room := &promptBudget{left: budget.left}
+		start := len(results)
+		for start > 0 && room.take(results[start-1]) {
+			start--
+		}
+		kept = results[start:]
+		if len(kept) == 0 && len(results) > 0 {
+			kept = []string{room.truncate(results[len(results)-1])}
+			start = len(results) - 1
+		}
+		if start > 0 {
+			kept = append([]string{fmt.Sprintf("(%d earlier tool results left out for space)\n", start)}, kept...)
+		}
+	}
+	return a.renderPrompt(ctx, prompts.ToolFollowup, struct{ Results, Question string }{strings.Join(kept, ""), message})
+}
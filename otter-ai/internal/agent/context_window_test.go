@@ -0,0 +1,106 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"otter-ai/internal/llm"
+)
+
+func TestBudgetedList(t *testing.T) {
+	items := []string{"kelp is tasty", "rocks crack shells", strings.Repeat("long ", 100)}
+	if got := budgetedList(nil, "Facts:", items); strings.Count(got, "\n- ") != 3 {
+		t.Errorf("unbounded list = %q", got)
+	}
+
+	budget := &promptBudget{left: 20}
+	got := budgetedList(budget, "Facts:", items)
+	if !strings.Contains(got, "kelp is tasty") || !strings.Contains(got, "rocks crack shells") || strings.Contains(got, "long") {
+		t.Errorf("budgeted list = %q", got)
+	}
+	if used := 20 - budget.left; used < llm.CountTokens(got) {
+		t.Errorf("claimed %d tokens for %d rendered", used, llm.CountTokens(got))
+	}
+
+	budget = &promptBudget{left: 3}
+	if got := budgetedList(budget, "Facts:", items); got != "" || budget.left != 3 {
+		t.Errorf("list without room = %q, %d tokens left", got, budget.left)
+	}
+}
+
+func TestBuildSessionContext_KeepsLatestWithinBudget(t *testing.T) {
+	a := newTestSessionAgent(nil)
+	session := a.sessions.Get(context.Background(), "s1")
+	session.setSummary(strings.Repeat("The user talked about rivers. ", 20))
+	for _, msg := range []string{"first question", "first answer", "second question", "second answer"} {
+		session.Add("user", msg)
+	}
+
+	budget := &promptBudget{left: 30}
+	got := a.buildSessionContext(session, budget)
+	if !strings.Contains(got, "second answer") || !strings.Contains(got, "second question") {
+		t.Errorf("latest messages missing: %q", got)
+	}
+	if budget.left < 0 {
+		t.Errorf("budget overdrawn: %d", budget.left)
+	}
+	if full := a.buildSessionContext(session, nil); llm.CountTokens(got) >= llm.CountTokens(full) {
+		t.Error("expected the budgeted context to be shorter")
+	}
+
+	// A message too long for the room is cut short rather than left out
+	session.Add("user", strings.Repeat("very long message ", 100))
+	got = a.buildSessionContext(session, &promptBudget{left: 20})
+	if !strings.Contains(got, "very long message") || !strings.Contains(got, "…") {
+		t.Errorf("expected the latest message truncated: %q", got)
+	}
+	if !strings.Contains(got, "earlier messages left out") {
+		t.Errorf("expected a note about left out messages: %q", got)
+	}
+}
+
+func TestProcessMessage_FitsContextWindow(t *testing.T) {
+	rec := &recordingLLM{mockLLMProvider: mockLLMProvider{completeResp: "ok"}}
+	a := newTestAgent(rec)
+	a.sessions = newSessionManager(nil, a.conversation)
+	for i := 0; i < 6; i++ {
+		a.conversation.Add("user", strings.Repeat("otters like to float on their backs ", 30))
+	}
+
+	if _, err := a.ProcessMessage(context.Background(), "hello"); err != nil {
+		t.Fatalf("ProcessMessage: %v", err)
+	}
+	unbounded := llm.CountTokens(rec.last.SystemPrompt)
+
+	const window = 2000
+	a.contextWindow = ContextWindowConfig{Tokens: window, ReplyTokens: 300}
+	if _, err := a.ProcessMessage(context.Background(), "hello"); err != nil {
+		t.Fatalf("ProcessMessage: %v", err)
+	}
+	tools, _ := json.Marshal(rec.last.Tools)
+	used := llm.CountTokens(rec.last.SystemPrompt) + llm.CountTokens(rec.last.Prompt) + llm.CountTokens(string(tools)) + 300
+	if used > window {
+		t.Errorf("request uses %d tokens of a %d token window", used, window)
+	}
+	if llm.CountTokens(rec.last.SystemPrompt) >= unbounded {
+		t.Error("expected history to be trimmed")
+	}
+	if !strings.Contains(rec.last.SystemPrompt, "Recent conversation") {
+		t.Error("expected some history to fit")
+	}
+}
+
+func TestToolFollowupPrompt_KeepsLatestResults(t *testing.T) {
+	a := newTestAgent(nil)
+	results := []string{"[search_memories]: " + strings.Repeat("old result ", 200) + "\n", "[get_last_memory]: newest\n"}
+
+	got := a.toolFollowupPrompt(context.Background(), "question", results, &promptBudget{left: 50})
+	if strings.Contains(got, "old result") || !strings.Contains(got, "newest") || !strings.Contains(got, "1 earlier tool results left out") {
+		t.Errorf("followup = %q", got)
+	}
+	if got := a.toolFollowupPrompt(context.Background(), "question", results, nil); !strings.Contains(got, "old result") {
+		t.Error("unbounded followup should keep every result")
+	}
+}
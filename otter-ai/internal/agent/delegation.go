@@ -0,0 +1,182 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"otter-ai/internal/errs"
+	"otter-ai/internal/events"
+	"otter-ai/internal/governance"
+	"otter-ai/internal/llm"
+	"otter-ai/internal/memory"
+	"otter-ai/internal/prompts"
+)
+
+// DelegationTimeout bounds casting one delegated vote, including the LLM's
+// judgement
+const DelegationTimeout = 2 * time.Minute
+
+// DelegationMode is how the otter votes on proposals in a scope without
+// being told to
+type DelegationMode string
+
+const (
+	// DelegateOwner leaves the vote to the owner's instruction, which is
+	// also what happens in scopes without a policy
+	DelegateOwner DelegationMode = "owner"
+	// DelegateAbstain always abstains
+	DelegateAbstain DelegationMode = "abstain"
+	// DelegateLLM has the LLM judge the proposal against the otter's
+	// personality
+	DelegateLLM DelegationMode = "llm"
+)
+
+// ErrInvalidDelegation is returned for delegation policies that can't be set
+var ErrInvalidDelegation = errs.New(errs.ErrInvalid, "invalid delegation")
+
+// SetDelegation sets the standing vote policy for rule proposals in scope,
+// which may be a pattern such as "safety/*" or "*"
+func (a *Agent) SetDelegation(ctx context.Context, scope string, mode DelegationMode, setBy string) (*memory.DelegationPolicy, error) {
+	if err := governance.ValidateScope(scope); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDelegation, err)
+	}
+	switch mode {
+	case DelegateOwner, DelegateAbstain, DelegateLLM:
+	default:
+		return nil, fmt.Errorf("%w: mode must be owner, abstain or llm, got %q", ErrInvalidDelegation, mode)
+	}
+
+	policy := &memory.DelegationPolicy{Scope: scope, Mode: string(mode), SetBy: setBy}
+	if err := a.memory.SaveDelegation(ctx, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// Delegations returns the standing vote policies ordered by scope
+func (a *Agent) Delegations(ctx context.Context) ([]memory.DelegationPolicy, error) {
+	return a.memory.ListDelegations(ctx)
+}
+
+// RemoveDelegation removes the policy set for exactly scope
+func (a *Agent) RemoveDelegation(ctx context.Context, scope string) error {
+	return a.memory.DeleteDelegation(ctx, scope)
+}
+
+// delegationFor returns the most specific policy covering a rule scope, or
+// nil when none does
+func (a *Agent) delegationFor(ctx context.Context, scope string) (*memory.DelegationPolicy, error) {
+	policies, err := a.memory.ListDelegations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var best *memory.DelegationPolicy
+	for i := range policies {
+		policy := &policies[i]
+		if !governance.ScopeMatches(policy.Scope, scope) {
+			continue
+		}
+		if best == nil || governance.ScopeSpecificity(policy.Scope) > governance.ScopeSpecificity(best.Scope) {
+			best = policy
+		}
+	}
+	return best, nil
+}
+
+// startDelegationListener votes on new rule proposals as the owner's
+// policies say
+func (a *Agent) startDelegationListener(bus *events.Bus) {
+	a.listen(bus, func(event events.Event) {
+		proposal, ok := event.Data.(governance.ProposalEvent)
+		if !ok {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), DelegationTimeout)
+		defer cancel()
+		a.voteByDelegation(ctx, proposal)
+	}, events.ProposalCreated)
+}
+
+// voteByDelegation casts this otter's vote on an open rule proposal under
+// the policy covering its scope. Proposals this otter made, membership
+// proposals and proposals it can't vote on are left alone.
+func (a *Agent) voteByDelegation(ctx context.Context, proposal governance.ProposalEvent) {
+	self := a.governance.GetID()
+	if proposal.Kind != governance.ProposalKindRule || proposal.Status != governance.ProposalOpen || proposal.ProposedBy == self {
+		return
+	}
+	if proposal.EligibleVoters != nil && !containsString(proposal.EligibleVoters, self) {
+		return
+	}
+
+	policy, err := a.delegationFor(ctx, proposal.Scope)
+	if err != nil {
+		a.log().WarnContext(ctx, "failed to load delegations", "proposal_id", proposal.ProposalID, "error", err)
+		return
+	}
+	if policy == nil || DelegationMode(policy.Mode) == DelegateOwner {
+		return
+	}
+
+	vote, reason := governance.VoteAbstain, ""
+	if DelegationMode(policy.Mode) == DelegateLLM {
+		if vote, reason, err = a.judgeProposal(ctx, proposal); err != nil {
+			a.log().WarnContext(ctx, "failed to judge proposal, leaving the vote to the owner", "proposal_id", proposal.ProposalID, "error", err)
+			return
+		}
+	}
+
+	err = a.governance.CastDelegatedVote(ctx, proposal.ProposalID, vote, governance.DelegatedVoteAudit{
+		Scope:  policy.Scope,
+		Mode:   policy.Mode,
+		Reason: reason,
+	})
+	if err != nil {
+		a.log().WarnContext(ctx, "failed to cast delegated vote", "proposal_id", proposal.ProposalID, "error", err)
+		return
+	}
+	a.log().InfoContext(ctx, "cast delegated vote", "proposal_id", proposal.ProposalID, "vote", vote, "policy_scope", policy.Scope, "mode", policy.Mode)
+}
+
+// judgeProposal asks the LLM how the otter's personality would vote on a
+// rule proposal, and why
+func (a *Agent) judgeProposal(ctx context.Context, proposal governance.ProposalEvent) (governance.VoteType, string, error) {
+	var traits []memory.PersonalityTrait
+	if status, err := a.Personality(ctx); err == nil {
+		traits = status.Traits
+	}
+	prompt := a.renderPrompt(ctx, prompts.DelegatedVote, struct {
+		Traits                            []memory.PersonalityTrait
+		RaftID, Scope, Body, Reason, From string
+	}{traits, proposal.RaftID, proposal.Scope, proposal.Body, proposal.Reason, proposal.ProposedBy})
+
+	resp, err := a.llm.Complete(ctx, &llm.CompletionRequest{
+		Prompt:  prompt,
+		Profile: llm.ProfileClassification,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	answer := strings.TrimSpace(resp.Text)
+	word, reason, _ := strings.Cut(answer, ":")
+	switch governance.VoteType(strings.ToUpper(strings.TrimSpace(word))) {
+	case governance.VoteYes:
+		return governance.VoteYes, strings.TrimSpace(reason), nil
+	case governance.VoteNo:
+		return governance.VoteNo, strings.TrimSpace(reason), nil
+	case governance.VoteAbstain:
+		return governance.VoteAbstain, strings.TrimSpace(reason), nil
+	}
+	return "", "", fmt.Errorf("unexpected vote answer: %q", resp.Text)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"otter-ai/internal/events"
+	"otter-ai/internal/governance"
+	"otter-ai/internal/llm"
+)
+
+// newDelegationAgent returns an agent listening for proposals in a raft it
+// shares with otter-2
+func newDelegationAgent(t *testing.T, provider llm.Provider) (*Agent, *governance.Governance) {
+	t.Helper()
+	a, gov := newTestGovernedAgent(t, Config{LLM: provider, Events: events.NewBus()})
+	joinPeer(t, gov, "otter-1", "otter-2")
+	return a, gov
+}
+
+// delegatedVotes returns the vote.delegated audit entries' data
+func delegatedVotes(t *testing.T, gov *governance.Governance) []governance.DelegatedVoteAudit {
+	t.Helper()
+	var votes []governance.DelegatedVoteAudit
+	gov.EachAuditEntry(context.Background(), governance.AuditFilter{}, func(entry governance.AuditEntry) error {
+		if entry.Action == governance.AuditVoteDelegated {
+			var vote governance.DelegatedVoteAudit
+			if err := json.Unmarshal(entry.Data, &vote); err != nil {
+				t.Fatal(err)
+			}
+			votes = append(votes, vote)
+		}
+		return nil
+	})
+	return votes
+}
+
+func TestSetDelegation(t *testing.T) {
+	a, _ := newDelegationAgent(t, &mockLLMProvider{})
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		scope string
+		mode  DelegationMode
+	}{{"*", DelegateLLM}, {"safety/*", DelegateAbstain}, {"safety/water", DelegateOwner}} {
+		if _, err := a.SetDelegation(ctx, tt.scope, tt.mode, "owner"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := a.SetDelegation(ctx, "safety", "always", "owner"); !errors.Is(err, ErrInvalidDelegation) {
+		t.Errorf("unknown mode: err = %v", err)
+	}
+	if _, err := a.SetDelegation(ctx, "safety/*/x", DelegateLLM, "owner"); !errors.Is(err, ErrInvalidDelegation) {
+		t.Errorf("invalid scope: err = %v", err)
+	}
+
+	for scope, want := range map[string]string{
+		"general":      "*",
+		"safety/land":  "safety/*",
+		"safety/water": "safety/water",
+	} {
+		policy, err := a.delegationFor(ctx, scope)
+		if err != nil || policy == nil || policy.Scope != want {
+			t.Errorf("delegationFor(%q) = %+v, %v, want the %q policy", scope, policy, err, want)
+		}
+	}
+
+	if err := a.RemoveDelegation(ctx, "*"); err != nil {
+		t.Fatal(err)
+	}
+	if policy, _ := a.delegationFor(ctx, "general"); policy != nil {
+		t.Errorf("removed policy still applies: %+v", policy)
+	}
+}
+
+func TestDelegation_VotesOnNewProposals(t *testing.T) {
+	provider := &requestRecorder{reply: "NO: it would make me less curious."}
+	a, gov := newDelegationAgent(t, provider)
+	ctx := context.Background()
+	if _, err := a.SetDelegation(ctx, "safety/*", DelegateAbstain, "owner"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.SetDelegation(ctx, "curiosity", DelegateLLM, "owner"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, rule := range []*governance.Rule{
+		{Scope: "safety/water", Body: "wear a lifejacket", ProposedBy: "otter-2"},
+		{Scope: "curiosity", Body: "ask fewer questions", ProposedBy: "otter-2"},
+		{Scope: "general", Body: "be kind", ProposedBy: "otter-2"},            // No policy
+		{Scope: "safety/land", Body: "look both ways", ProposedBy: "otter-1"}, // Own proposal
+	} {
+		if _, err := gov.ProposeRule(ctx, "otter-1", rule); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(delegatedVotes(t, gov)) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond) // Let any unexpected vote land
+
+	votes := map[string]governance.DelegatedVoteAudit{}
+	for _, vote := range delegatedVotes(t, gov) {
+		votes[vote.Scope] = vote
+	}
+	if len(votes) != 2 {
+		t.Fatalf("delegated votes = %+v, want one per policy", votes)
+	}
+	if v := votes["safety/*"]; v.Vote != governance.VoteAbstain || v.Mode != "abstain" {
+		t.Errorf("safety vote = %+v, want an abstention", v)
+	}
+	if v := votes["curiosity"]; v.Vote != governance.VoteNo || v.Reason != "it would make me less curious." {
+		t.Errorf("curiosity vote = %+v, want the LLM's NO with its reason", v)
+	}
+	if len(provider.requests) != 1 || provider.requests[0].Profile != llm.ProfileClassification {
+		t.Errorf("LLM judgements = %d, want one for the llm policy", len(provider.requests))
+	}
+}
+
+func TestDelegation_UnclearJudgementLeavesVote(t *testing.T) {
+	a, gov := newDelegationAgent(t, &requestRecorder{reply: "Hmm, hard to say."})
+	ctx := context.Background()
+	if _, err := a.SetDelegation(ctx, "*", DelegateLLM, "owner"); err != nil {
+		t.Fatal(err)
+	}
+
+	a.voteByDelegation(ctx, governance.ProposalEvent{
+		ProposalID: "p1", RaftID: "otter-1", Scope: "general", Body: "be kind",
+		ProposedBy: "otter-2", Status: governance.ProposalOpen, Kind: governance.ProposalKindRule,
+	})
+	if votes := delegatedVotes(t, gov); len(votes) != 0 {
+		t.Errorf("delegated votes = %+v, want none when the judgement is unclear", votes)
+	}
+}
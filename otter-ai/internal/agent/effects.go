@@ -0,0 +1,91 @@
+package agent
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"otter-ai/internal/events"
+	"otter-ai/internal/governance"
+	"otter-ai/internal/llm"
+	"otter-ai/internal/memory"
+)
+
+// ruleEffects is the runtime behaviour the active rules' effects set
+type ruleEffects struct {
+	temperature   *float32        // Chat temperature; nil keeps the configured one
+	disabled      map[string]bool // Platforms and "platform:channel"s not answered
+	retentionDays int             // Memory retention half-life; zero keeps the configured one
+}
+
+// effectsState holds the applied rule effects and the configuration they
+// are applied over, which is restored once no rule sets a value
+type effectsState struct {
+	current   atomic.Pointer[ruleEffects]
+	retention *memory.RetentionPolicy // Nil when retention is off; effects don't turn it on
+}
+
+// startEffectsListener applies the active rules' effects now and again
+// whenever rules are adopted, suspended, reinstated or archived away.
+// Overriding a rule drops its effects, reverting what it set.
+func (a *Agent) startEffectsListener(bus *events.Bus) {
+	if policy, ok := a.memory.RetentionPolicy(); ok {
+		a.effects.retention = &policy
+	}
+	a.applyRuleEffects()
+	a.listen(bus, func(events.Event) {
+		a.applyRuleEffects()
+	}, events.RuleAdopted, events.RuleSuspended, events.RuleReinstated, events.MemberLeft, events.RaftArchived)
+}
+
+// applyRuleEffects computes the runtime settings of the active rules'
+// effects and applies them
+func (a *Agent) applyRuleEffects() {
+	effects := &ruleEffects{disabled: make(map[string]bool)}
+	active := a.governance.RuleEffects()
+	for _, effect := range active {
+		switch effect.Key {
+		case governance.EffectChatTemperature:
+			t := effect.Float()
+			effects.temperature = &t
+		case governance.EffectChannelDisabled:
+			effects.disabled[effect.Value] = true
+		case governance.EffectMemoryRetentionDays:
+			effects.retentionDays = effect.Int()
+		}
+	}
+
+	previous := a.effects.current.Swap(effects)
+	if previous == nil || previous.retentionDays != effects.retentionDays {
+		if base := a.effects.retention; base != nil {
+			policy := *base
+			if effects.retentionDays > 0 {
+				policy.HalfLife = time.Duration(effects.retentionDays) * 24 * time.Hour
+			}
+			a.memory.SetRetentionPolicy(policy)
+		}
+	}
+	if previous != nil || len(active) > 0 {
+		a.log().Info("rule effects applied", "effects", len(active))
+	}
+}
+
+// applyChatParameters sets a chat completion request's parameters: the
+// channel profile's, then those set by rule effects, which take precedence
+func (a *Agent) applyChatParameters(ctx context.Context, req *llm.CompletionRequest) {
+	a.channelProfile(ctx).apply(req)
+	if effects := a.effects.current.Load(); effects != nil && effects.temperature != nil {
+		req.Temperature = *effects.temperature
+		req.TemperatureSet = true
+	}
+}
+
+// channelDisabled reports whether a rule effect stops the agent answering
+// a platform channel
+func (a *Agent) channelDisabled(platform, channelID string) bool {
+	effects := a.effects.current.Load()
+	if effects == nil {
+		return false
+	}
+	return effects.disabled[platform] || effects.disabled[platform+":"+channelID]
+}
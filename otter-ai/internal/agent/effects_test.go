@@ -0,0 +1,82 @@
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"otter-ai/internal/events"
+	"otter-ai/internal/governance"
+	"otter-ai/internal/llm"
+	"otter-ai/internal/memory"
+	"otter-ai/internal/plugins"
+)
+
+// adoptSoloRule adopts a rule in the otter's own raft with its vote
+func adoptSoloRule(t *testing.T, gov *governance.Governance, rule *governance.Rule) *governance.Rule {
+	t.Helper()
+	ctx := context.Background()
+	rule.ProposedBy = gov.GetID()
+	proposal, err := gov.ProposeRule(ctx, gov.GetID(), rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gov.CastVote(ctx, proposal.ProposalID, governance.VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	return proposal.Rule
+}
+
+func TestRuleEffects_AppliedOnAdoptionAndRevertedOnOverride(t *testing.T) {
+	gov := newTestGovernance(t)
+	bus := events.NewBus()
+	gov.SetEventBus(bus)
+
+	mem := memory.New(&mockVectorDB{})
+	mem.SetRetentionPolicy(memory.RetentionPolicy{HalfLife: 30 * 24 * time.Hour, Floor: 0.1})
+	a := New(Config{Memory: mem, Governance: gov, LLM: &mockLLMProvider{}, Events: bus})
+	defer a.Shutdown(context.Background())
+
+	waitFor := func(what string, done func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for !done() {
+			if time.Now().After(deadline) {
+				t.Fatal(what)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	halfLife := func() time.Duration {
+		policy, _ := mem.RetentionPolicy()
+		return policy.HalfLife
+	}
+
+	rule := adoptSoloRule(t, gov, &governance.Rule{Scope: "tone", Body: "Keep it calm.\neffect: chat.temperature = 0.2\neffect: channel.disabled = slack:C1\neffect: memory.retention_days = 7"})
+	waitFor("effects were not applied after adoption", func() bool { return a.channelDisabled("slack", "C1") })
+	if got := halfLife(); got != 7*24*time.Hour {
+		t.Errorf("retention half-life = %v, want 7 days", got)
+	}
+	if a.channelDisabled("slack", "C2") || a.channelDisabled("discord", "C1") {
+		t.Error("only slack:C1 should be disabled")
+	}
+	req := &llm.CompletionRequest{}
+	a.applyChatParameters(context.Background(), req)
+	if !req.TemperatureSet || req.Temperature != 0.2 {
+		t.Errorf("temperature = %v (set %v), want 0.2", req.Temperature, req.TemperatureSet)
+	}
+	if reply, err := a.handlePluginMessage(context.Background(), &plugins.Message{Platform: "slack", ChannelID: "C1", Content: "hello"}); err != nil || reply != "" {
+		t.Errorf("disabled channel answered %q, %v", reply, err)
+	}
+
+	adoptSoloRule(t, gov, &governance.Rule{Body: "Keep it calm.", BaseRuleID: rule.RuleID})
+	waitFor("effects were not reverted after the rule was overridden", func() bool { return !a.channelDisabled("slack", "C1") })
+	if got := halfLife(); got != 30*24*time.Hour {
+		t.Errorf("retention half-life = %v, want the configured 30 days back", got)
+	}
+	req = &llm.CompletionRequest{}
+	a.applyChatParameters(context.Background(), req)
+	if req.TemperatureSet {
+		t.Errorf("temperature = %v, want the configured one", req.Temperature)
+	}
+}
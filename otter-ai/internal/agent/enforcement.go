@@ -0,0 +1,114 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"otter-ai/internal/governance"
+	"otter-ai/internal/llm"
+	"otter-ai/internal/prompts"
+)
+
+// Rule enforcement
+const (
+	// MaxRuleRegenerations is how many times a reply that breaks an active
+	// rule is written again before it is withheld
+	MaxRuleRegenerations = 1
+	RuleBlockedReply     = "I can't give that reply without breaking my raft's rules"
+)
+
+// checkToolCall returns the text to hand the LLM in place of the tool's
+// result when the call would break an active rule
+func (a *Agent) checkToolCall(ctx context.Context, turn *chatTurn, call llm.ToolCall) (string, bool) {
+	if a.governance == nil {
+		return "", false
+	}
+	scope := ContextOptionsFromContext(ctx).Scope
+	violations := a.governance.CheckToolCall(ctx, scope, call.Name, call.Arguments)
+	if len(violations) == 0 {
+		return "", false
+	}
+	a.recordViolations(ctx, turn, violations, governance.ViolationBlocked)
+	return fmt.Sprintf("Tool %s was not run because it would break %s. Do not try it again; tell the user it is not allowed.",
+		call.Name, describeViolations(violations)), true
+}
+
+// enforceRules checks a reply against the rules governing the turn's scope.
+// A reply that breaks one is written again with the violations pointed out,
+// up to MaxRuleRegenerations times, and withheld if it still breaks a rule.
+func (a *Agent) enforceRules(ctx context.Context, turn *chatTurn, systemPrompt, message, response string) string {
+	if a.governance == nil {
+		return response
+	}
+	scope := ContextOptionsFromContext(ctx).Scope
+	for attempt := 0; ; attempt++ {
+		violations := a.governance.CheckResponse(ctx, scope, message, response)
+		if len(violations) == 0 {
+			return response
+		}
+		if attempt == MaxRuleRegenerations {
+			a.recordViolations(ctx, turn, violations, governance.ViolationBlocked)
+			return fmt.Sprintf("%s (%s).", RuleBlockedReply, violationScopes(violations))
+		}
+		a.recordViolations(ctx, turn, violations, governance.ViolationRegenerated)
+
+		request := &llm.CompletionRequest{
+			SystemPrompt: systemPrompt,
+			Prompt: a.renderPrompt(ctx, prompts.RuleRegeneration, struct{ Message, Draft, Violations string }{
+				message, response, describeViolations(violations)}),
+			Profile: llm.ProfileChat,
+		}
+		a.applyChatParameters(ctx, request)
+		resp, err := a.llm.Complete(ctx, request)
+		if err != nil || resp == nil || strings.TrimSpace(resp.Text) == "" {
+			a.log().WarnContext(ctx, "failed to regenerate reply that broke a rule", "error", err)
+			a.recordViolations(ctx, turn, violations, governance.ViolationBlocked)
+			return fmt.Sprintf("%s (%s).", RuleBlockedReply, violationScopes(violations))
+		}
+		response = strings.TrimSpace(resp.Text)
+	}
+}
+
+// recordViolations audits violations and keeps them on the turn, so they
+// are stored with the interaction memory
+func (a *Agent) recordViolations(ctx context.Context, turn *chatTurn, violations []governance.RuleViolation, outcome governance.ViolationOutcome) {
+	a.governance.RecordRuleViolations(ctx, violations, outcome)
+	for _, violation := range violations {
+		violation.Outcome = outcome
+		turn.violations = append(turn.violations, violation)
+	}
+}
+
+// describeViolations lists violated rules for a prompt
+func describeViolations(violations []governance.RuleViolation) string {
+	parts := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		parts = append(parts, fmt.Sprintf("the %s rule (%s)", violation.Scope, violation.Reason))
+	}
+	return strings.Join(parts, " and ")
+}
+
+// violationScopes lists the distinct scopes of violated rules
+func violationScopes(violations []governance.RuleViolation) string {
+	seen := make(map[string]bool)
+	var scopes []string
+	for _, violation := range violations {
+		if !seen[violation.Scope] {
+			seen[violation.Scope] = true
+			scopes = append(scopes, violation.Scope)
+		}
+	}
+	sort.Strings(scopes)
+	return strings.Join(scopes, ", ")
+}
+
+// violationMetadata converts a turn's violations for memory metadata
+func (t *chatTurn) violationMetadata() map[string]interface{} {
+	metadata := make(map[string]interface{}, len(t.violations))
+	for _, violation := range t.violations {
+		metadata[violation.RuleID] = fmt.Sprintf("%s %s: %s", violation.Outcome, violation.Target, violation.Reason)
+	}
+	return metadata
+}
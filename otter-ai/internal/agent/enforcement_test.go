@@ -0,0 +1,116 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"otter-ai/internal/governance"
+	"otter-ai/internal/llm"
+)
+
+// scriptedLLM replies with each text in turn, repeating the last
+type scriptedLLM struct {
+	mockLLMProvider
+	replies   []string
+	toolCalls []llm.ToolCall // Returned before the first reply
+	calls     int
+}
+
+func (m *scriptedLLM) Complete(_ context.Context, _ *llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	m.calls++
+	if m.calls == 1 && len(m.toolCalls) > 0 {
+		return &llm.CompletionResponse{ToolCalls: m.toolCalls}, nil
+	}
+	i := m.calls - 1
+	if len(m.toolCalls) > 0 {
+		i--
+	}
+	if i >= len(m.replies) {
+		i = len(m.replies) - 1
+	}
+	return &llm.CompletionResponse{Text: m.replies[i]}, nil
+}
+
+// governedAgent returns an agent whose solo raft has adopted a rule
+func governedAgent(t *testing.T, provider llm.Provider, scope, body string) *Agent {
+	t.Helper()
+	a := newTestAgent(provider)
+	gov := newTestGovernance(t)
+	a.governance = gov
+
+	ctx := context.Background()
+	proposal, err := gov.ProposeRule(ctx, "otter-1", &governance.Rule{Scope: scope, Body: body, ProposedBy: "otter-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gov.CastVote(ctx, proposal.ProposalID, governance.VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func violationActions(t *testing.T, gov *governance.Governance) []string {
+	t.Helper()
+	var outcomes []string
+	gov.EachAuditEntry(context.Background(), governance.AuditFilter{}, func(entry governance.AuditEntry) error {
+		if entry.Action == governance.AuditRuleViolated {
+			outcomes = append(outcomes, string(entry.Data))
+		}
+		return nil
+	})
+	return outcomes
+}
+
+func TestEnforceRules_RegeneratesViolatingReply(t *testing.T) {
+	provider := &scriptedLLM{replies: []string{"The password is hunter2.", "I can't share the password."}}
+	a := governedAgent(t, provider, "secrets", "deny text: hunter2")
+
+	resp, err := a.ProcessMessage(context.Background(), "what is the password?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp != "I can't share the password." {
+		t.Errorf("reply = %q", resp)
+	}
+	if audited := violationActions(t, a.governance); len(audited) != 1 || !contains(audited[0], `"outcome":"regenerated"`) {
+		t.Errorf("audited violations = %v", audited)
+	}
+}
+
+func TestEnforceRules_BlocksPersistentViolation(t *testing.T) {
+	provider := &scriptedLLM{replies: []string{"It is hunter2."}}
+	a := governedAgent(t, provider, "secrets", "deny text: hunter2")
+
+	resp, err := a.ProcessMessage(context.Background(), "what is the password?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp != RuleBlockedReply+" (secrets)." {
+		t.Errorf("reply = %q", resp)
+	}
+	if provider.calls != 1+MaxRuleRegenerations {
+		t.Errorf("LLM called %d times", provider.calls)
+	}
+	audited := violationActions(t, a.governance)
+	if len(audited) != 2 || !contains(audited[1], `"outcome":"blocked"`) {
+		t.Errorf("audited violations = %v", audited)
+	}
+}
+
+func TestCheckToolCall_DeniedTool(t *testing.T) {
+	provider := &scriptedLLM{
+		toolCalls: []llm.ToolCall{{Name: "propose_rule", Arguments: map[string]string{"rule_body": "be loud"}}},
+		replies:   []string{"Proposing rules is not allowed here."},
+	}
+	a := governedAgent(t, provider, "governance", "deny tool: propose_rule")
+
+	if _, err := a.ProcessMessage(context.Background(), "propose that we be loud"); err != nil {
+		t.Fatal(err)
+	}
+	if proposals := a.governance.GetOpenProposals(); len(proposals) != 0 {
+		t.Errorf("denied tool ran: %d open proposals", len(proposals))
+	}
+	if audited := violationActions(t, a.governance); len(audited) != 1 || !contains(audited[0], `"target":"tool_call"`) {
+		t.Errorf("audited violations = %v", audited)
+	}
+}
@@ -0,0 +1,154 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"otter-ai/internal/llm"
+	"otter-ai/internal/memory"
+	"otter-ai/internal/prompts"
+)
+
+// ExtractionTimeout bounds extracting the facts of one interaction
+const ExtractionTimeout = 2 * time.Minute
+
+// Defaults for fact extraction when the configuration doesn't say
+const (
+	DefaultExtractionMaxFacts   = 5
+	DefaultExtractionImportance = 0.8
+	// ExtractedTranscriptImportance is the importance interactions are
+	// stored at once their facts are extracted, so the transcripts fade and
+	// get consolidated before the facts do
+	ExtractedTranscriptImportance = 0.3
+)
+
+// ExtractionConfig tunes the stage that pulls atomic facts and preferences
+// out of each interaction into memories of their own
+type ExtractionConfig struct {
+	Enabled    bool
+	MaxFacts   int     // Most facts kept per interaction; zero uses DefaultExtractionMaxFacts
+	Importance float32 // Importance facts are stored at; zero uses DefaultExtractionImportance
+}
+
+func (c ExtractionConfig) withDefaults() ExtractionConfig {
+	if c.MaxFacts <= 0 {
+		c.MaxFacts = DefaultExtractionMaxFacts
+	}
+	if c.Importance <= 0 {
+		c.Importance = DefaultExtractionImportance
+	}
+	return c
+}
+
+// extractedFact is one line of a fact extraction answer
+type extractedFact struct {
+	Kind    string // memory.FactKindFact or memory.FactKindPreference
+	Content string
+}
+
+// startFactExtraction extracts the facts of a stored interaction in the
+// background, so the reply isn't held up by another completion. The run
+// outlives the chat request but not the agent.
+func (a *Agent) startFactExtraction(ctx context.Context, interaction *memory.MemoryRecord, message, response string) {
+	ctx, cancel := context.WithTimeout(llm.WithPriority(context.WithoutCancel(ctx), llm.PriorityBackground), ExtractionTimeout)
+	a.writes.Add(1)
+	go func() {
+		defer a.writes.Done()
+		defer cancel()
+		go func() {
+			select {
+			case <-a.idleStop:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		facts, err := a.extractFacts(ctx, interaction, message, response)
+		if err != nil {
+			a.log().WarnContext(ctx, "failed to extract facts", "memory_id", interaction.ID, "error", err)
+			return
+		}
+		if len(facts) > 0 {
+			a.log().DebugContext(ctx, "extracted facts", "memory_id", interaction.ID, "facts", len(facts))
+		}
+	}()
+}
+
+// extractFacts asks the LLM for the facts and preferences an interaction
+// states and stores each as a long-term memory linked to the interaction
+// by source_memory_id. Facts inherit the interaction's scope and user.
+func (a *Agent) extractFacts(ctx context.Context, interaction *memory.MemoryRecord, message, response string) ([]*memory.MemoryRecord, error) {
+	cfg := a.extraction.withDefaults()
+	data := struct {
+		Message, Response string
+		MaxFacts          int
+	}{sanitizeForPrompt(message), sanitizeForPrompt(response), cfg.MaxFacts}
+	resp, err := a.llm.Complete(ctx, &llm.CompletionRequest{
+		Prompt:  a.renderPrompt(ctx, prompts.FactExtraction, data),
+		Profile: llm.ProfileSummary,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	extracted := parseExtractedFacts(resp.Text)
+	if len(extracted) > cfg.MaxFacts {
+		extracted = extracted[:cfg.MaxFacts]
+	}
+	facts := make([]*memory.MemoryRecord, 0, len(extracted))
+	for _, fact := range extracted {
+		embedding, err := a.embed(ctx, fact.Content)
+		if err != nil {
+			return facts, fmt.Errorf("failed to embed fact: %w", err)
+		}
+		record := &memory.MemoryRecord{
+			Type:       memory.MemoryTypeLongTerm,
+			Content:    fact.Content,
+			Embedding:  embedding,
+			Importance: cfg.Importance,
+			Scope:      interaction.Scope,
+			Metadata: map[string]interface{}{
+				"content_source":   memory.SourceExtraction,
+				"fact_kind":        fact.Kind,
+				"source_memory_id": interaction.ID,
+			},
+		}
+		if userID, ok := interaction.Metadata["user_id"].(string); ok {
+			record.Metadata["user_id"] = userID
+		}
+		if err := a.memory.Store(ctx, record); err != nil {
+			return facts, fmt.Errorf("failed to store fact: %w", err)
+		}
+		facts = append(facts, record)
+	}
+	return facts, nil
+}
+
+// parseExtractedFacts reads the "- [kind] sentence" lines of a fact
+// extraction answer. Lines without a known kind are facts; "none" and
+// blank lines are skipped, as are repeats.
+func parseExtractedFacts(answer string) []extractedFact {
+	var facts []extractedFact
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(answer, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "-*•"))
+		kind := memory.FactKindFact
+		if rest, ok := strings.CutPrefix(line, "["); ok {
+			if label, content, ok := strings.Cut(rest, "]"); ok {
+				if strings.EqualFold(strings.TrimSpace(label), memory.FactKindPreference) {
+					kind = memory.FactKindPreference
+				}
+				line = strings.TrimSpace(content)
+			}
+		}
+		key := strings.ToLower(strings.Join(strings.Fields(line), " "))
+		if key == "" || strings.Trim(key, `."'*`) == "none" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		facts = append(facts, extractedFact{Kind: kind, Content: line})
+	}
+	return facts
+}
@@ -0,0 +1,100 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"otter-ai/internal/llm"
+	"otter-ai/internal/memory"
+)
+
+// extractionProvider replies to chat with reply and to fact extraction
+// with facts
+type extractionProvider struct {
+	mockLLMProvider
+	reply, facts string
+}
+
+func (m *extractionProvider) Complete(_ context.Context, req *llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	if req.Profile == llm.ProfileSummary {
+		return &llm.CompletionResponse{Text: m.facts}, nil
+	}
+	return &llm.CompletionResponse{Text: m.reply}, nil
+}
+
+func TestParseExtractedFacts(t *testing.T) {
+	facts := parseExtractedFacts("- [fact] The user's otter is called Pebble.\n* [Preference] The user prefers tea over coffee.\n\nThe user lives in Oslo.\n- [fact] the user's otter is  called Pebble.\n")
+	want := []extractedFact{
+		{memory.FactKindFact, "The user's otter is called Pebble."},
+		{memory.FactKindPreference, "The user prefers tea over coffee."},
+		{memory.FactKindFact, "The user lives in Oslo."},
+	}
+	if len(facts) != len(want) {
+		t.Fatalf("facts = %+v", facts)
+	}
+	for i := range want {
+		if facts[i] != want[i] {
+			t.Errorf("fact %d = %+v, want %+v", i, facts[i], want[i])
+		}
+	}
+
+	if facts := parseExtractedFacts("None."); len(facts) != 0 {
+		t.Errorf("none = %+v", facts)
+	}
+}
+
+func TestProcessMessage_ExtractsFacts(t *testing.T) {
+	provider := &extractionProvider{reply: "Noted!", facts: "- [preference] The user prefers tea.\n- [fact] The user has an otter called Pebble."}
+	a := newTestConsolidationAgent(t, nil)
+	a.llm = provider
+	a.extraction = ExtractionConfig{Enabled: true, MaxFacts: 1}
+	ctx := WithUser(context.Background(), "slack:U1")
+
+	if _, err := a.ProcessMessage(ctx, "I like tea, and my otter is called Pebble"); err != nil {
+		t.Fatal(err)
+	}
+	a.writes.Wait()
+
+	var transcript, fact *memory.MemoryRecord
+	a.memory.Each(ctx, memory.MemoryTypeLongTerm, func(record memory.MemoryRecord) error {
+		switch record.Metadata["content_source"] {
+		case memory.SourceInteraction:
+			transcript = &record
+		case memory.SourceExtraction:
+			if fact != nil {
+				t.Errorf("stored more than MaxFacts facts: %q", record.Content)
+			}
+			fact = &record
+		}
+		return nil
+	})
+	if transcript == nil || fact == nil {
+		t.Fatalf("transcript = %+v, fact = %+v", transcript, fact)
+	}
+	if transcript.Importance != ExtractedTranscriptImportance {
+		t.Errorf("transcript importance = %v", transcript.Importance)
+	}
+	if fact.Content != "The user prefers tea." || fact.Importance != DefaultExtractionImportance ||
+		fact.Metadata["fact_kind"] != memory.FactKindPreference || fact.Metadata["source_memory_id"] != transcript.ID ||
+		fact.Metadata["user_id"] != "slack:U1" {
+		t.Errorf("fact = %+v", fact)
+	}
+}
+
+func TestPreferFacts(t *testing.T) {
+	fact := func(id, source string, score float64) memory.MemoryRecord {
+		return memory.MemoryRecord{ID: id, Score: score, Metadata: map[string]interface{}{
+			"content_source": memory.SourceExtraction, "source_memory_id": source,
+		}}
+	}
+	memories := []memory.MemoryRecord{
+		{ID: "transcript", Score: 0.8},
+		{ID: "other", Score: 0.75},
+		fact("tea", "transcript", 0.72),
+	}
+
+	got := preferFacts(memories, 0.05)
+	if len(got) != 2 || got[0].ID != "tea" || got[1].ID != "other" {
+		t.Errorf("preferFacts = %+v", got)
+	}
+}
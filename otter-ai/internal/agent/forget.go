@@ -0,0 +1,127 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"otter-ai/internal/governance"
+	"otter-ai/internal/memory"
+)
+
+// ForgetMemories permanently erases the memories matching the request and
+// records the erasure's tombstone in the governance audit log
+func (a *Agent) ForgetMemories(ctx context.Context, req governance.ErasureRequest) (*governance.Erasure, error) {
+	if a.governance == nil {
+		return nil, fmt.Errorf("governance system is not configured")
+	}
+	erasure, err := a.governance.EraseMemories(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if !req.DryRun && len(erasure.Erased) > 0 {
+		a.invalidatePinned()
+	}
+	return erasure, nil
+}
+
+// pendingForget is a /forget awaiting confirmation by the user who asked
+type pendingForget struct {
+	Criteria    memory.ForgetCriteria
+	RequestedBy string
+	CreatedAt   time.Time
+}
+
+// forgetOwner identifies who staged a /forget: only the same user in the
+// same session can confirm it
+type forgetOwner struct {
+	SessionID string
+	UserID    string
+}
+
+// forgetCommand stages erasing the memories a chat user asks to forget:
+// "/forget all" for everything they said, "/forget <text>" for their
+// memories mentioning the text. Nothing is erased until they confirm.
+func (a *Agent) forgetCommand(ctx context.Context, args string) string {
+	userID := UserFromContext(ctx)
+	criteria := memory.ForgetCriteria{UserID: userID}
+	switch {
+	case args == "":
+		return "Usage: /forget <text> erases memories mentioning the text; /forget all erases everything you told me"
+	case userID == "":
+		return "I don't know who you are in this conversation, so I can't tell which memories are yours."
+	case !strings.EqualFold(args, "all"):
+		criteria.Match = args
+	}
+
+	preview, err := a.ForgetMemories(ctx, governance.ErasureRequest{Criteria: criteria, RequestedBy: userID, DryRun: true})
+	if err != nil {
+		return fmt.Sprintf("I couldn't look for those memories: %v", err)
+	}
+	if len(preview.Erased) == 0 {
+		if len(preview.Held) > 0 {
+			return fmt.Sprintf("The %d matching memories are under legal hold, so I have to keep them.", len(preview.Held))
+		}
+		return "I don't have any memories matching that."
+	}
+
+	a.pendingMu.Lock()
+	if a.pendingForgets == nil {
+		a.pendingForgets = make(map[forgetOwner]*pendingForget)
+	}
+	for owner, pending := range a.pendingForgets {
+		if time.Since(pending.CreatedAt) > PendingActionTTL {
+			delete(a.pendingForgets, owner)
+		}
+	}
+	a.pendingForgets[forgetOwner{SessionID: SessionFromContext(ctx), UserID: userID}] = &pendingForget{
+		Criteria:    criteria,
+		RequestedBy: userID,
+		CreatedAt:   time.Now(),
+	}
+	a.pendingMu.Unlock()
+
+	reply := fmt.Sprintf("This will permanently erase %d memories; it can't be undone.", len(preview.Erased))
+	if len(preview.Held) > 0 {
+		reply += fmt.Sprintf(" %d more are under legal hold and will be kept.", len(preview.Held))
+	}
+	return reply + ` Reply "confirm" to erase them or "cancel" to keep them.`
+}
+
+// takePendingForget removes and returns the /forget the user of ctx staged
+// in its session when the message confirms or cancels it
+func (a *Agent) takePendingForget(ctx context.Context, messageLower string) *pendingForget {
+	if !isConfirmMessage(messageLower) && !isCancelMessage(messageLower) {
+		return nil
+	}
+	owner := forgetOwner{SessionID: SessionFromContext(ctx), UserID: UserFromContext(ctx)}
+	if owner.UserID == "" {
+		return nil
+	}
+
+	a.pendingMu.Lock()
+	defer a.pendingMu.Unlock()
+	pending, ok := a.pendingForgets[owner]
+	if !ok {
+		return nil
+	}
+	delete(a.pendingForgets, owner)
+	if time.Since(pending.CreatedAt) > PendingActionTTL {
+		return nil
+	}
+	return pending
+}
+
+// forgetConfirmed erases the memories of a confirmed /forget
+func (a *Agent) forgetConfirmed(ctx context.Context, pending *pendingForget) string {
+	erasure, err := a.ForgetMemories(ctx, governance.ErasureRequest{
+		Criteria:    pending.Criteria,
+		RequestedBy: pending.RequestedBy,
+		Reason:      "asked in chat",
+	})
+	if err != nil {
+		return fmt.Sprintf("I couldn't erase those memories: %v", err)
+	}
+	return fmt.Sprintf("Forgotten: %d memories erased. (erasure %s)", len(erasure.Erased), erasure.ErasureID)
+}
@@ -0,0 +1,256 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"otter-ai/internal/governance"
+)
+
+// Chat hook configuration
+const (
+	DefaultBlockedReply     = "This message was blocked by policy."
+	HookUnavailableReply    = "This message could not be checked by a required policy service, so it was not processed."
+	MaxHookResponseBytes    = 1 << 20
+	MaxHookAnnotationLength = 1000
+)
+
+// HookStage identifies where in a chat turn a hook runs
+type HookStage string
+
+const (
+	HookBeforeMessage  HookStage = "before_message"  // Before the message is processed; may rewrite the message
+	HookBeforeResponse HookStage = "before_response" // Before the reply is stored and returned; may rewrite the reply
+)
+
+// HookAction is a hook's decision about a turn
+type HookAction string
+
+const (
+	HookAllow  HookAction = "allow"  // Continue unchanged (also the default for an empty action)
+	HookModify HookAction = "modify" // Continue with the returned message or response
+	HookBlock  HookAction = "block"  // Stop the turn and reply with Response
+)
+
+// HookRequest is what a hook sees for one turn
+type HookRequest struct {
+	Stage       HookStage         `json:"stage"`
+	OtterID     string            `json:"otter_id,omitempty"`
+	SessionID   string            `json:"session_id"`
+	Message     string            `json:"message"`
+	Response    string            `json:"response,omitempty"` // before_response only
+	Context     ContextOptions    `json:"context"`
+	Annotations map[string]string `json:"annotations,omitempty"` // Added by earlier hooks in this turn
+	Timestamp   time.Time         `json:"timestamp"`
+}
+
+// HookResult is a hook's reply. Annotations are merged into the turn and
+// stored with the interaction memory.
+type HookResult struct {
+	Action      HookAction        `json:"action"`
+	Message     string            `json:"message,omitempty"`  // Replacement message for modify at before_message
+	Response    string            `json:"response,omitempty"` // Replacement reply for modify, or the reply when blocking
+	Reason      string            `json:"reason,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Hook inspects a chat turn at one stage
+type Hook interface {
+	Name() string
+	Stage() HookStage
+	Run(ctx context.Context, req *HookRequest) (*HookResult, error)
+}
+
+// BlockedError is returned by ProcessMessage when a hook blocks the turn.
+// Reply is the text to show the user instead of a response.
+type BlockedError struct {
+	Hook   string
+	Stage  HookStage
+	Reason string
+	Reply  string
+}
+
+func (e *BlockedError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("blocked by hook %s at %s: %s", e.Hook, e.Stage, e.Reason)
+	}
+	return fmt.Sprintf("blocked by hook %s at %s", e.Hook, e.Stage)
+}
+
+// chatTurn carries hook state through one ProcessMessage call
+type chatTurn struct {
+	message          string
+	annotations      map[string]string
+	responseReviewed bool
+	violations       []governance.RuleViolation // Rules the turn's outputs broke
+}
+
+// runHooks passes the request through every hook registered for its stage,
+// in order. Each hook sees the message and response as rewritten by the
+// hooks before it. A hook error blocks the turn unless the agent fails open.
+func (a *Agent) runHooks(ctx context.Context, turn *chatTurn, req *HookRequest) error {
+	filled := false
+	for _, hook := range a.hooks {
+		if hook.Stage() != req.Stage {
+			continue
+		}
+		if !filled {
+			a.fillHookRequest(ctx, req)
+			filled = true
+		}
+
+		req.Annotations = turn.annotations
+		req.Timestamp = time.Now()
+		result, err := hook.Run(ctx, req)
+		if err != nil {
+			if a.hookFailOpen {
+				a.log().WarnContext(ctx, "chat hook failed, continuing", "hook", hook.Name(), "error", err)
+				continue
+			}
+			a.log().WarnContext(ctx, "chat hook failed, blocking turn", "hook", hook.Name(), "error", err)
+			return &BlockedError{Hook: hook.Name(), Stage: req.Stage, Reason: err.Error(), Reply: HookUnavailableReply}
+		}
+		if result == nil {
+			continue
+		}
+
+		for key, value := range result.Annotations {
+			if turn.annotations == nil {
+				turn.annotations = make(map[string]string)
+			}
+			if len(value) > MaxHookAnnotationLength {
+				value = value[:MaxHookAnnotationLength]
+			}
+			turn.annotations[hook.Name()+"."+key] = value
+		}
+
+		switch result.Action {
+		case "", HookAllow:
+		case HookModify:
+			if req.Stage == HookBeforeMessage && result.Message != "" {
+				req.Message = result.Message
+			}
+			if req.Stage == HookBeforeResponse && result.Response != "" {
+				req.Response = result.Response
+			}
+		case HookBlock:
+			reply := result.Response
+			if reply == "" {
+				reply = DefaultBlockedReply
+			}
+			a.log().InfoContext(ctx, "chat hook blocked turn", "hook", hook.Name(), "stage", req.Stage, "reason", result.Reason)
+			return &BlockedError{Hook: hook.Name(), Stage: req.Stage, Reason: result.Reason, Reply: reply}
+		default:
+			if a.hookFailOpen {
+				a.log().WarnContext(ctx, "chat hook returned unknown action, continuing", "hook", hook.Name(), "action", result.Action)
+				continue
+			}
+			return &BlockedError{Hook: hook.Name(), Stage: req.Stage, Reason: fmt.Sprintf("unknown action %q", result.Action), Reply: HookUnavailableReply}
+		}
+	}
+	return nil
+}
+
+// reviewResponse runs the before_response hooks on a reply and returns the
+// reply to use. It marks the turn so the reply is not reviewed twice.
+func (a *Agent) reviewResponse(ctx context.Context, turn *chatTurn, response string) (string, error) {
+	turn.responseReviewed = true
+	req := &HookRequest{
+		Stage:    HookBeforeResponse,
+		Message:  turn.message,
+		Response: response,
+	}
+	if err := a.runHooks(ctx, turn, req); err != nil {
+		return "", err
+	}
+	return req.Response, nil
+}
+
+// fillHookRequest adds the turn's context to a hook request
+func (a *Agent) fillHookRequest(ctx context.Context, req *HookRequest) {
+	session := a.session(ctx)
+	req.SessionID = session.ID
+	req.Context = session.ContextOptions().Merge(ContextOptionsFromContext(ctx))
+	if a.governance != nil {
+		req.OtterID = a.governance.GetID()
+	}
+}
+
+// HTTPHook calls an external policy service. The service receives the
+// HookRequest as JSON and replies with a HookResult; an empty 2xx body
+// allows the turn.
+type HTTPHook struct {
+	name   string
+	stage  HookStage
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewHTTPHook creates a hook that POSTs to url at the given stage
+func NewHTTPHook(stage HookStage, url, token string, timeout time.Duration) *HTTPHook {
+	return &HTTPHook{
+		name:   url,
+		stage:  stage,
+		url:    url,
+		token:  token,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (h *HTTPHook) Name() string     { return h.name }
+func (h *HTTPHook) Stage() HookStage { return h.stage }
+
+func (h *HTTPHook) Run(ctx context.Context, req *HookRequest) (*HookResult, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal hook request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed creating request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if h.token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+h.token)
+	}
+
+	resp, err := h.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("hook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, MaxHookResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed reading hook response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("hook returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	if len(bytes.TrimSpace(respBody)) == 0 {
+		return nil, nil
+	}
+
+	var result HookResult
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("invalid hook response: %w", err)
+	}
+	return &result, nil
+}
+
+// annotationMetadata converts a turn's annotations for memory metadata
+func (t *chatTurn) annotationMetadata() map[string]interface{} {
+	metadata := make(map[string]interface{}, len(t.annotations))
+	for key, value := range t.annotations {
+		metadata[key] = value
+	}
+	return metadata
+}
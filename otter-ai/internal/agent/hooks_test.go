@@ -0,0 +1,227 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"otter-ai/internal/memory"
+)
+
+// fakeHook returns a fixed result and records the requests it saw
+type fakeHook struct {
+	name   string
+	stage  HookStage
+	result *HookResult
+	err    error
+	seen   []HookRequest
+}
+
+func (h *fakeHook) Name() string     { return h.name }
+func (h *fakeHook) Stage() HookStage { return h.stage }
+func (h *fakeHook) Run(_ context.Context, req *HookRequest) (*HookResult, error) {
+	h.seen = append(h.seen, *req)
+	return h.result, h.err
+}
+
+// recordingVectorDB captures the metadata of stored records
+type recordingVectorDB struct {
+	mockVectorDB
+	metadata []map[string]interface{}
+}
+
+func (m *recordingVectorDB) Store(_ context.Context, _ string, _ string, _ []float32, metadata map[string]interface{}) error {
+	m.metadata = append(m.metadata, metadata)
+	return nil
+}
+
+func newTestHookAgent(llmProv *mockLLMProvider, hooks ...Hook) *Agent {
+	a := newTestSessionAgent(llmProv)
+	a.hooks = hooks
+	return a
+}
+
+func TestProcessMessage_HookModifiesMessage(t *testing.T) {
+	rec := &recordingLLM{mockLLMProvider: mockLLMProvider{completeResp: "ok"}}
+	a := newTestAgent(rec)
+	a.sessions = newSessionManager(nil, a.conversation)
+	a.hooks = []Hook{&fakeHook{name: "redact", stage: HookBeforeMessage,
+		result: &HookResult{Action: HookModify, Message: "my card is [REDACTED]"}}}
+
+	if _, err := a.ProcessMessage(context.Background(), "my card is 4111 1111 1111 1111"); err != nil {
+		t.Fatalf("ProcessMessage: %v", err)
+	}
+
+	if !strings.Contains(rec.last.Prompt, "my card is [REDACTED]") || strings.Contains(rec.last.Prompt, "4111") {
+		t.Errorf("LLM should see only the rewritten message, got %q", rec.last.Prompt)
+	}
+}
+
+func TestProcessMessage_HookModifiesResponse(t *testing.T) {
+	hook := &fakeHook{name: "tone", stage: HookBeforeResponse,
+		result: &HookResult{Action: HookModify, Response: "reviewed reply"}}
+	a := newTestHookAgent(&mockLLMProvider{completeResp: "draft reply"}, hook)
+
+	response, err := a.ProcessMessage(context.Background(), "hello")
+	if err != nil {
+		t.Fatalf("ProcessMessage: %v", err)
+	}
+	if response != "reviewed reply" {
+		t.Errorf("response = %q, want the rewritten reply", response)
+	}
+	if len(hook.seen) != 1 || hook.seen[0].Response != "draft reply" || hook.seen[0].Message != "hello" {
+		t.Errorf("hook should see the draft once, got %+v", hook.seen)
+	}
+
+	history := a.session(context.Background()).History.GetRecent(2)
+	if len(history) != 2 || history[1].Content != "reviewed reply" {
+		t.Errorf("history should hold the reviewed reply, got %+v", history)
+	}
+}
+
+func TestProcessMessage_HookBlocks(t *testing.T) {
+	for _, stage := range []HookStage{HookBeforeMessage, HookBeforeResponse} {
+		llmProv := &recordingLLM{mockLLMProvider: mockLLMProvider{completeResp: "ok"}}
+		a := newTestAgent(llmProv)
+		a.sessions = newSessionManager(nil, a.conversation)
+		a.hooks = []Hook{&fakeHook{name: "policy", stage: stage,
+			result: &HookResult{Action: HookBlock, Reason: "off-topic"}}}
+
+		_, err := a.ProcessMessage(context.Background(), "hello")
+		var blocked *BlockedError
+		if !errors.As(err, &blocked) {
+			t.Fatalf("%s: expected BlockedError, got %v", stage, err)
+		}
+		if blocked.Stage != stage || blocked.Hook != "policy" || blocked.Reply != DefaultBlockedReply {
+			t.Errorf("%s: unexpected BlockedError %+v", stage, blocked)
+		}
+		if stage == HookBeforeMessage && llmProv.last != nil {
+			t.Error("a message blocked before processing should not reach the LLM")
+		}
+		if len(a.conversation.GetRecent(10)) != 0 {
+			t.Errorf("%s: a blocked turn should not be added to history", stage)
+		}
+	}
+}
+
+func TestProcessMessage_HookErrorFailsClosed(t *testing.T) {
+	hook := &fakeHook{name: "down", stage: HookBeforeMessage, err: errors.New("connection refused")}
+	a := newTestHookAgent(&mockLLMProvider{completeResp: "ok"}, hook)
+
+	_, err := a.ProcessMessage(context.Background(), "hello")
+	var blocked *BlockedError
+	if !errors.As(err, &blocked) || blocked.Reply != HookUnavailableReply {
+		t.Fatalf("expected fail-closed BlockedError, got %v", err)
+	}
+
+	a.hookFailOpen = true
+	response, err := a.ProcessMessage(context.Background(), "hello")
+	if err != nil || response != "ok" {
+		t.Errorf("fail-open should continue, got %q, %v", response, err)
+	}
+}
+
+func TestProcessMessage_HookUnknownActionFailsClosed(t *testing.T) {
+	hook := &fakeHook{name: "odd", stage: HookBeforeResponse, result: &HookResult{Action: "escalate"}}
+	a := newTestHookAgent(&mockLLMProvider{completeResp: "ok"}, hook)
+
+	if _, err := a.ProcessMessage(context.Background(), "hello"); err == nil {
+		t.Error("expected unknown action to block")
+	}
+}
+
+func TestProcessMessage_HookAnnotationsStored(t *testing.T) {
+	db := &recordingVectorDB{}
+	first := &fakeHook{name: "classifier", stage: HookBeforeMessage,
+		result: &HookResult{Annotations: map[string]string{"topic": "billing"}}}
+	second := &fakeHook{name: "audit", stage: HookBeforeResponse,
+		result: &HookResult{Action: HookAllow, Annotations: map[string]string{"ticket": "T-1"}}}
+	a := newTestHookAgent(&mockLLMProvider{completeResp: "ok"}, first, second)
+	a.memory = memory.New(db)
+
+	if _, err := a.ProcessMessage(WithSession(context.Background(), "support"), "refund please"); err != nil {
+		t.Fatalf("ProcessMessage: %v", err)
+	}
+
+	if got := second.seen[0].Annotations["classifier.topic"]; got != "billing" {
+		t.Errorf("later hooks should see earlier annotations, got %q", got)
+	}
+	if second.seen[0].SessionID != "support" {
+		t.Errorf("SessionID = %q, want support", second.seen[0].SessionID)
+	}
+
+	if len(db.metadata) == 0 {
+		t.Fatal("expected the interaction to be stored")
+	}
+	annotations, ok := db.metadata[len(db.metadata)-1]["hook_annotations"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected hook_annotations in metadata, got %v", db.metadata[len(db.metadata)-1])
+	}
+	if annotations["classifier.topic"] != "billing" || annotations["audit.ticket"] != "T-1" {
+		t.Errorf("unexpected annotations %v", annotations)
+	}
+}
+
+func TestProcessMessage_NoHooks(t *testing.T) {
+	a := newTestSessionAgent(&mockLLMProvider{completeResp: "ok"})
+
+	response, err := a.ProcessMessage(context.Background(), "hello")
+	if err != nil || response != "ok" {
+		t.Errorf("got %q, %v", response, err)
+	}
+}
+
+// --- HTTPHook ---
+
+func TestHTTPHook_Run(t *testing.T) {
+	var got HookRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		json.NewEncoder(w).Encode(HookResult{Action: HookBlock, Reason: "policy 7"})
+	}))
+	defer server.Close()
+
+	hook := NewHTTPHook(HookBeforeMessage, server.URL, "secret", time.Second)
+	result, err := hook.Run(context.Background(), &HookRequest{Stage: HookBeforeMessage, Message: "hi"})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if result.Action != HookBlock || result.Reason != "policy 7" {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if got.Message != "hi" || got.Stage != HookBeforeMessage {
+		t.Errorf("service received %+v", got)
+	}
+}
+
+func TestHTTPHook_EmptyBodyAllows(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	result, err := NewHTTPHook(HookBeforeResponse, server.URL, "", time.Second).Run(context.Background(), &HookRequest{})
+	if err != nil || result != nil {
+		t.Errorf("expected nil result, got %+v, %v", result, err)
+	}
+}
+
+func TestHTTPHook_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	if _, err := NewHTTPHook(HookBeforeMessage, server.URL, "", time.Second).Run(context.Background(), &HookRequest{}); err == nil {
+		t.Error("expected error for 503")
+	}
+}
@@ -0,0 +1,293 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"otter-ai/internal/connectors"
+	"otter-ai/internal/errs"
+	"otter-ai/internal/governance"
+	"otter-ai/internal/llm"
+	"otter-ai/internal/memory"
+	"otter-ai/internal/prompts"
+)
+
+const (
+	// IngestionTimeout bounds one pull of all connectors
+	IngestionTimeout = 5 * time.Minute
+	// IngestedImportance is the importance of memories ingested from
+	// connectors, below what conversations usually score
+	IngestedImportance = 0.4
+	// IngestionPolicyScope is the rule scope governing which external items
+	// the otter may ingest
+	IngestionPolicyScope = "ingestion"
+)
+
+// ErrConnectorNotFound is returned for a connector that isn't configured
+var ErrConnectorNotFound = errs.New(errs.ErrNotFound, "connector not found")
+
+// IngestionConfig tunes the pulling of external sources into memory
+type IngestionConfig struct {
+	Connectors []connectors.Connector
+	Interval   time.Duration // How often all connectors are pulled; zero disables the loop
+	MaxItems   int           // Most new items ingested per connector per pull; zero means no limit
+}
+
+// IngestionResult reports what one pull of a connector did
+type IngestionResult struct {
+	Connector  string `json:"connector"`
+	Fetched    int    `json:"fetched"`
+	Ingested   int    `json:"ingested"`
+	Duplicates int    `json:"duplicates"` // Already ingested earlier
+	Denied     int    `json:"denied"`     // Refused by the ingestion policy
+	Failed     int    `json:"failed"`     // Left to be retried on the next pull
+}
+
+// ConnectorStatus describes a configured connector and its totals
+type ConnectorStatus struct {
+	Name      string     `json:"name"`
+	Kind      string     `json:"kind"`
+	LastRun   *time.Time `json:"last_run,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+	Ingested  int        `json:"ingested"`
+	Denied    int        `json:"denied"`
+}
+
+// ingestionState tracks connector runs
+type ingestionState struct {
+	mu     sync.Mutex
+	status map[string]*ConnectorStatus
+	// denied remembers items refused under a policy, keyed by source ID, so
+	// they are only judged again once the policy changes
+	denied map[string]string
+}
+
+func (a *Agent) startIngestionLoop() {
+	a.startPeriodicJob(a.ingestion.Interval, IngestionTimeout, a.unlessReadOnly(func(ctx context.Context) {
+		for _, connector := range a.ingestion.Connectors {
+			result, err := a.ingestConnector(ctx, connector)
+			if err != nil {
+				a.log().Warn("connector failed", "connector", connector.Name(), "error", err)
+				continue
+			}
+			if result.Ingested > 0 {
+				a.log().Info("ingested connector items", "connector", connector.Name(), "items", result.Ingested)
+			}
+		}
+	}))
+}
+
+// Connectors returns the configured connectors and what they ingested
+func (a *Agent) Connectors() []ConnectorStatus {
+	a.ingestionState.mu.Lock()
+	defer a.ingestionState.mu.Unlock()
+
+	statuses := make([]ConnectorStatus, 0, len(a.ingestion.Connectors))
+	for _, connector := range a.ingestion.Connectors {
+		status := ConnectorStatus{Name: connector.Name(), Kind: connector.Kind()}
+		if recorded, ok := a.ingestionState.status[connector.Name()]; ok {
+			status = *recorded
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// RunConnector pulls one connector now
+func (a *Agent) RunConnector(ctx context.Context, name string) (*IngestionResult, error) {
+	for _, connector := range a.ingestion.Connectors {
+		if connector.Name() == name {
+			return a.ingestConnector(ctx, connector)
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrConnectorNotFound, name)
+}
+
+// ingestConnector fetches a connector's items and stores the new ones the
+// ingestion policy allows as long-term memories
+func (a *Agent) ingestConnector(ctx context.Context, connector connectors.Connector) (*IngestionResult, error) {
+	result := &IngestionResult{Connector: connector.Name()}
+	items, err := connector.Fetch(ctx)
+	if err == nil {
+		result.Fetched = len(items)
+		err = a.ingestItems(ctx, connector, items, result)
+	}
+	a.recordIngestion(connector, result, err)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (a *Agent) ingestItems(ctx context.Context, connector connectors.Connector, items []connectors.Item, result *IngestionResult) error {
+	rules, policy := a.ingestionPolicy()
+	for _, item := range items {
+		if a.ingestion.MaxItems > 0 && result.Ingested >= a.ingestion.MaxItems {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		sourceID := connector.Name() + ":" + item.ID
+		existing, err := a.memory.ListFiltered(ctx, memory.MemoryTypeLongTerm,
+			memory.SearchFilter{Metadata: map[string]interface{}{"source_id": sourceID}}, 1, 0)
+		if err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			result.Duplicates++
+			continue
+		}
+
+		if len(rules) > 0 {
+			if a.deniedUnder(sourceID, policy) {
+				result.Denied++
+				continue
+			}
+			allowed, err := a.allowedByPolicy(ctx, rules, connector, item)
+			if err != nil {
+				a.log().WarnContext(ctx, "could not check item against the ingestion policy", "source_id", sourceID, "error", err)
+				result.Failed++
+				continue
+			}
+			if !allowed {
+				a.denyUnder(sourceID, policy)
+				result.Denied++
+				continue
+			}
+		}
+
+		if err := a.storeIngestedItem(ctx, connector, sourceID, item); err != nil {
+			a.log().WarnContext(ctx, "failed to store ingested item", "source_id", sourceID, "error", err)
+			result.Failed++
+			continue
+		}
+		result.Ingested++
+	}
+	return nil
+}
+
+// storeIngestedItem embeds an item and stores it with its source
+func (a *Agent) storeIngestedItem(ctx context.Context, connector connectors.Connector, sourceID string, item connectors.Item) error {
+	content := fmt.Sprintf("[%s %s] %s", connector.Kind(), connector.Name(), item.Title)
+	if item.Content != "" {
+		content += "\n" + item.Content
+	}
+	embedding, err := a.embed(ctx, content)
+	if err != nil {
+		return fmt.Errorf("failed to embed: %w", err)
+	}
+
+	metadata := map[string]interface{}{
+		"content_source": memory.SourceConnector,
+		"connector":      connector.Name(),
+		"connector_kind": connector.Kind(),
+		"source_id":      sourceID,
+	}
+	if item.URL != "" {
+		metadata["source_url"] = item.URL
+	}
+	if !item.Published.IsZero() {
+		metadata["source_published"] = item.Published.Unix()
+	}
+	return a.memory.Store(ctx, &memory.MemoryRecord{
+		Type:       memory.MemoryTypeLongTerm,
+		Content:    content,
+		Embedding:  embedding,
+		Metadata:   metadata,
+		Timestamp:  time.Now(),
+		Importance: IngestedImportance,
+	})
+}
+
+// ingestionPolicy returns the active rules in the ingestion scope, and a
+// key that changes whenever they do
+func (a *Agent) ingestionPolicy() ([]*governance.Rule, string) {
+	if a.governance == nil {
+		return nil, ""
+	}
+	var rules []*governance.Rule
+	for _, byScope := range a.governance.GetActiveRules() {
+		if rule, ok := byScope[IngestionPolicyScope]; ok {
+			rules = append(rules, rule)
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].RaftID < rules[j].RaftID })
+
+	keys := make([]string, len(rules))
+	for i, rule := range rules {
+		keys[i] = fmt.Sprintf("%s/%s@%d", rule.RaftID, rule.RuleID, rule.Version)
+	}
+	return rules, strings.Join(keys, ",")
+}
+
+// allowedByPolicy asks the LLM whether the ingestion rules allow an item
+func (a *Agent) allowedByPolicy(ctx context.Context, rules []*governance.Rule, connector connectors.Connector, item connectors.Item) (bool, error) {
+	prompt := a.renderPrompt(ctx, prompts.IngestionPolicy, struct {
+		Rules        []*governance.Rule
+		Kind, Source string
+		Item         connectors.Item
+	}{rules, connector.Kind(), connector.Name(), item})
+
+	resp, err := a.llm.Complete(ctx, &llm.CompletionRequest{
+		Prompt:  prompt,
+		Profile: llm.ProfileClassification,
+	})
+	if err != nil {
+		return false, err
+	}
+	answer := strings.ToUpper(strings.TrimSpace(resp.Text))
+	switch {
+	case strings.HasPrefix(answer, "ALLOW"):
+		return true, nil
+	case strings.HasPrefix(answer, "DENY"):
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected policy answer: %q", resp.Text)
+	}
+}
+
+// deniedUnder reports whether an item was refused under the current policy
+func (a *Agent) deniedUnder(sourceID, policy string) bool {
+	a.ingestionState.mu.Lock()
+	defer a.ingestionState.mu.Unlock()
+	deniedPolicy, ok := a.ingestionState.denied[sourceID]
+	return ok && deniedPolicy == policy
+}
+
+func (a *Agent) denyUnder(sourceID, policy string) {
+	a.ingestionState.mu.Lock()
+	defer a.ingestionState.mu.Unlock()
+	if a.ingestionState.denied == nil {
+		a.ingestionState.denied = make(map[string]string)
+	}
+	a.ingestionState.denied[sourceID] = policy
+}
+
+// recordIngestion updates a connector's status after a pull
+func (a *Agent) recordIngestion(connector connectors.Connector, result *IngestionResult, err error) {
+	a.ingestionState.mu.Lock()
+	defer a.ingestionState.mu.Unlock()
+
+	if a.ingestionState.status == nil {
+		a.ingestionState.status = make(map[string]*ConnectorStatus)
+	}
+	status, ok := a.ingestionState.status[connector.Name()]
+	if !ok {
+		status = &ConnectorStatus{Name: connector.Name(), Kind: connector.Kind()}
+		a.ingestionState.status[connector.Name()] = status
+	}
+	now := time.Now()
+	status.LastRun = &now
+	status.LastError = ""
+	if err != nil {
+		status.LastError = err.Error()
+	}
+	status.Ingested += result.Ingested
+	status.Denied += result.Denied
+}
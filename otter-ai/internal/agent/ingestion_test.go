@@ -0,0 +1,142 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"otter-ai/internal/connectors"
+	"otter-ai/internal/governance"
+	"otter-ai/internal/memory"
+)
+
+// fakeConnector returns fixed items
+type fakeConnector struct {
+	items []connectors.Item
+	err   error
+}
+
+func (c *fakeConnector) Name() string { return "news" }
+func (c *fakeConnector) Kind() string { return connectors.KindRSS }
+func (c *fakeConnector) Fetch(context.Context) ([]connectors.Item, error) {
+	return c.items, c.err
+}
+
+func newTestIngestionAgent(t *testing.T, llmProv *mockLLMProvider, connector connectors.Connector) *Agent {
+	t.Helper()
+	a := newTestAgent(llmProv)
+	a.memory = newTestSQLiteMemory(t)
+	a.ingestion = IngestionConfig{Connectors: []connectors.Connector{connector}, MaxItems: 10}
+	return a
+}
+
+func TestRunConnector_StoresNewItemsOnce(t *testing.T) {
+	published := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	connector := &fakeConnector{items: []connectors.Item{
+		{ID: "1", Title: "Otters return to the bay", Content: "A family was seen.", URL: "https://example.org/1", Published: published},
+		{ID: "2", Title: "Kelp forest recovering"},
+	}}
+	a := newTestIngestionAgent(t, &mockLLMProvider{}, connector)
+	ctx := context.Background()
+
+	result, err := a.RunConnector(ctx, "news")
+	if err != nil {
+		t.Fatalf("RunConnector: %v", err)
+	}
+	if result.Fetched != 2 || result.Ingested != 2 {
+		t.Fatalf("result = %+v, want 2 ingested", result)
+	}
+
+	records, err := a.memory.ListFiltered(ctx, memory.MemoryTypeLongTerm,
+		memory.SearchFilter{Metadata: map[string]interface{}{"source_id": "news:1"}}, 10, 0)
+	if err != nil || len(records) != 1 {
+		t.Fatalf("expected the stored item, got %v, %v", records, err)
+	}
+	record := records[0]
+	if record.Content != "[rss news] Otters return to the bay\nA family was seen." || record.Importance != IngestedImportance {
+		t.Errorf("unexpected memory: %q importance %v", record.Content, record.Importance)
+	}
+	if record.Metadata["content_source"] != memory.SourceConnector || record.Metadata["source_url"] != "https://example.org/1" {
+		t.Errorf("unexpected metadata: %v", record.Metadata)
+	}
+
+	// Pulling again stores nothing new
+	result, err = a.RunConnector(ctx, "news")
+	if err != nil || result.Ingested != 0 || result.Duplicates != 2 {
+		t.Fatalf("second pull = %+v, %v; want 2 duplicates", result, err)
+	}
+	if statuses := a.Connectors(); len(statuses) != 1 || statuses[0].Ingested != 2 || statuses[0].LastRun == nil {
+		t.Errorf("statuses = %+v", statuses)
+	}
+}
+
+func TestRunConnector_MaxItems(t *testing.T) {
+	connector := &fakeConnector{items: []connectors.Item{{ID: "1", Title: "a"}, {ID: "2", Title: "b"}, {ID: "3", Title: "c"}}}
+	a := newTestIngestionAgent(t, &mockLLMProvider{}, connector)
+	a.ingestion.MaxItems = 2
+
+	result, err := a.RunConnector(context.Background(), "news")
+	if err != nil || result.Ingested != 2 {
+		t.Fatalf("result = %+v, %v; want 2 ingested", result, err)
+	}
+	// The rest follow on the next pull
+	result, err = a.RunConnector(context.Background(), "news")
+	if err != nil || result.Ingested != 1 {
+		t.Fatalf("second pull = %+v, %v; want 1 ingested", result, err)
+	}
+}
+
+func TestRunConnector_Errors(t *testing.T) {
+	a := newTestIngestionAgent(t, &mockLLMProvider{}, &fakeConnector{err: errors.New("feed unreachable")})
+
+	if _, err := a.RunConnector(context.Background(), "missing"); !errors.Is(err, ErrConnectorNotFound) {
+		t.Errorf("err = %v, want ErrConnectorNotFound", err)
+	}
+	if _, err := a.RunConnector(context.Background(), "news"); err == nil {
+		t.Fatal("expected the fetch error")
+	}
+	if statuses := a.Connectors(); statuses[0].LastError != "feed unreachable" {
+		t.Errorf("last error = %q", statuses[0].LastError)
+	}
+}
+
+func TestRunConnector_IngestionPolicy(t *testing.T) {
+	connector := &fakeConnector{items: []connectors.Item{{ID: "1", Title: "Celebrity gossip"}}}
+	llmProv := &mockLLMProvider{completeResp: "DENY"}
+	a := newTestIngestionAgent(t, llmProv, connector)
+	ctx := context.Background()
+
+	gov := newTestGovernance(t)
+	a.governance = gov
+	proposal, err := gov.ProposeRule(ctx, "otter-1", &governance.Rule{Scope: IngestionPolicyScope, Body: "Only remember news about otters", ProposedBy: "otter-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gov.CastVote(ctx, proposal.ProposalID, governance.VoteYes); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := a.RunConnector(ctx, "news")
+	if err != nil || result.Denied != 1 || result.Ingested != 0 {
+		t.Fatalf("result = %+v, %v; want the item denied", result, err)
+	}
+
+	// A denied item isn't judged again under the same policy
+	llmProv.completeResp = "ALLOW"
+	if result, _ := a.RunConnector(ctx, "news"); result.Denied != 1 {
+		t.Errorf("second pull = %+v, want the item still denied", result)
+	}
+
+	// An unclear answer leaves the item for the next pull
+	a.ingestionState.denied = nil
+	llmProv.completeResp = "maybe"
+	if result, _ := a.RunConnector(ctx, "news"); result.Failed != 1 {
+		t.Errorf("pull with an unclear answer = %+v, want 1 failed", result)
+	}
+
+	llmProv.completeResp = "ALLOW"
+	if result, _ := a.RunConnector(ctx, "news"); result.Ingested != 1 {
+		t.Errorf("pull after the policy allows = %+v, want 1 ingested", result)
+	}
+}
@@ -0,0 +1,238 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"otter-ai/internal/llm"
+	"otter-ai/internal/prompts"
+	"otter-ai/internal/vectordb"
+)
+
+// Intent is what a chat message asks of the otter. It decides which tools
+// the LLM is offered for the turn.
+type Intent string
+
+const (
+	// IntentUnknown offers every tool
+	IntentUnknown Intent = ""
+	// IntentConversation is small talk and opinions, answered without tools
+	IntentConversation Intent = "conversation"
+	// IntentMemory asks about what the otter remembers
+	IntentMemory Intent = "memory"
+	// IntentStatus asks about the otter's health
+	IntentStatus Intent = "status"
+	// IntentGovernance asks about or acts on rules, proposals and rafts
+	IntentGovernance Intent = "governance"
+)
+
+// IntentMode decides how messages are classified
+type IntentMode string
+
+const (
+	// IntentModeOff offers every tool without classifying
+	IntentModeOff IntentMode = "off"
+	// IntentModeAuto compares the message embedding with exemplars and asks
+	// the LLM only when no intent stands out
+	IntentModeAuto IntentMode = "auto"
+	// IntentModeEmbedding only compares with exemplars; ambiguous messages
+	// are offered every tool
+	IntentModeEmbedding IntentMode = "embedding"
+	// IntentModeLLM always asks the LLM
+	IntentModeLLM IntentMode = "llm"
+)
+
+// Default intent classification settings
+const (
+	DefaultIntentThreshold = 0.7
+	DefaultIntentMargin    = 0.05
+)
+
+// IntentConfig tunes intent classification
+type IntentConfig struct {
+	Mode IntentMode // Empty disables classification
+	// Threshold is the least similarity to an exemplar an intent is
+	// chosen with
+	Threshold float64
+	// Margin is how far the best intent must lead the next one
+	Margin float64
+}
+
+// withDefaults fills unset thresholds with their defaults
+func (c IntentConfig) withDefaults() IntentConfig {
+	if c.Threshold <= 0 {
+		c.Threshold = DefaultIntentThreshold
+	}
+	if c.Margin <= 0 {
+		c.Margin = DefaultIntentMargin
+	}
+	return c
+}
+
+// intentExemplars are canonical messages of each intent, embedded once and
+// compared with every message
+var intentExemplars = map[Intent][]string{
+	IntentConversation: {
+		"hello, how are you?",
+		"thanks, that was helpful",
+		"what do you think about that?",
+		"tell me a joke",
+		"which one do you prefer?",
+	},
+	IntentMemory: {
+		"what do you remember about me?",
+		"search your memories for the project deadline",
+		"what was the last thing you remembered?",
+		"have we talked about this before?",
+		"compare your recent memories",
+	},
+	IntentStatus: {
+		"are you healthy?",
+		"what is your status?",
+		"how long have you been running?",
+		"is your memory database working?",
+	},
+	IntentGovernance: {
+		"what rules are in effect?",
+		"I want to propose a new rule",
+		"vote yes on the open proposal",
+		"show the open proposals",
+		"leave the raft",
+	},
+}
+
+// intentTools are the tools offered for each intent. Conversation gets none.
+var intentTools = map[Intent][]string{
+	IntentMemory:     {"search_memories", "get_last_memory", "compare_memories"},
+	IntentStatus:     {"get_health_status"},
+	IntentGovernance: {"list_governance_state", "propose_rule", "vote_on_proposal", "leave_raft"},
+}
+
+// intentState caches the exemplar embeddings
+type intentState struct {
+	mu      sync.Mutex
+	vectors map[Intent][][]float32
+}
+
+// classifyIntent returns the intent of a message, given its embedding.
+// Failures leave the intent unknown.
+func (a *Agent) classifyIntent(ctx context.Context, message string, embedding []float32) Intent {
+	mode := a.intent.Mode
+	if mode == "" || mode == IntentModeOff {
+		return IntentUnknown
+	}
+
+	if mode != IntentModeLLM {
+		vectors, err := a.exemplarVectors(ctx)
+		if err != nil {
+			a.log().WarnContext(ctx, "failed to embed intent exemplars", "error", err)
+		} else if intent, score := matchIntent(embedding, vectors, a.intent.Threshold, a.intent.Margin); intent != IntentUnknown {
+			a.log().DebugContext(ctx, "intent classified", "intent", intent, "method", "embedding", "similarity", score)
+			return intent
+		}
+		if mode == IntentModeEmbedding {
+			return IntentUnknown
+		}
+	}
+
+	intent, err := a.llmIntent(ctx, message)
+	if err != nil {
+		a.log().WarnContext(ctx, "failed to classify intent", "error", err)
+		return IntentUnknown
+	}
+	a.log().DebugContext(ctx, "intent classified", "intent", intent, "method", "llm")
+	return intent
+}
+
+// exemplarVectors embeds the intent exemplars on first use. A failure is
+// retried on the next message.
+func (a *Agent) exemplarVectors(ctx context.Context) (map[Intent][][]float32, error) {
+	a.intentState.mu.Lock()
+	defer a.intentState.mu.Unlock()
+	if a.intentState.vectors != nil {
+		return a.intentState.vectors, nil
+	}
+
+	vectors := make(map[Intent][][]float32, len(intentExemplars))
+	for intent, exemplars := range intentExemplars {
+		for _, exemplar := range exemplars {
+			vector, err := a.embed(ctx, exemplar)
+			if err != nil {
+				return nil, err
+			}
+			vectors[intent] = append(vectors[intent], vector)
+		}
+	}
+	a.intentState.vectors = vectors
+	return vectors, nil
+}
+
+// matchIntent returns the intent whose exemplars are most similar to the
+// embedding, if that similarity reaches threshold and leads every other
+// intent by margin
+func matchIntent(embedding []float32, vectors map[Intent][][]float32, threshold, margin float64) (Intent, float64) {
+	best, runnerUp := 0.0, 0.0
+	bestIntent := IntentUnknown
+	for intent, exemplars := range vectors {
+		score := 0.0
+		for _, vector := range exemplars {
+			if s := vectordb.CosineSimilarity(embedding, vector); s > score {
+				score = s
+			}
+		}
+		switch {
+		case score > best:
+			best, runnerUp, bestIntent = score, best, intent
+		case score > runnerUp:
+			runnerUp = score
+		}
+	}
+	if best < threshold || best-runnerUp < margin {
+		return IntentUnknown, best
+	}
+	return bestIntent, best
+}
+
+// llmIntent asks the LLM for the intent of a message
+func (a *Agent) llmIntent(ctx context.Context, message string) (Intent, error) {
+	resp, err := a.llm.Complete(ctx, &llm.CompletionRequest{
+		Prompt:  a.renderPrompt(ctx, prompts.Intent, struct{ Message string }{message}),
+		Profile: llm.ProfileClassification,
+	})
+	if err != nil {
+		return IntentUnknown, err
+	}
+	return parseIntent(resp.Text), nil
+}
+
+// parseIntent reads the intent named by the first word of an answer
+func parseIntent(answer string) Intent {
+	fields := strings.Fields(strings.ToLower(answer))
+	if len(fields) == 0 {
+		return IntentUnknown
+	}
+	switch intent := Intent(strings.Trim(fields[0], `.,:;!"'*`)); intent {
+	case IntentConversation, IntentMemory, IntentStatus, IntentGovernance:
+		return intent
+	}
+	return IntentUnknown
+}
+
+// routeTools keeps the tools offered for an intent
+func routeTools(tools []llm.ToolDefinition, intent Intent) []llm.ToolDefinition {
+	if intent == IntentUnknown {
+		return tools
+	}
+	allowed := make(map[string]bool, len(intentTools[intent]))
+	for _, name := range intentTools[intent] {
+		allowed[name] = true
+	}
+	routed := make([]llm.ToolDefinition, 0, len(allowed))
+	for _, tool := range tools {
+		if allowed[tool.Name] {
+			routed = append(routed, tool)
+		}
+	}
+	return routed
+}
@@ -0,0 +1,149 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"otter-ai/internal/llm"
+)
+
+// requestRecorder answers every completion with reply and keeps the requests
+type requestRecorder struct {
+	mockLLMProvider
+	reply    string
+	requests []*llm.CompletionRequest
+}
+
+func (m *requestRecorder) Complete(_ context.Context, req *llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	m.requests = append(m.requests, req)
+	return &llm.CompletionResponse{Text: m.reply}, nil
+}
+
+// intentAgent returns an agent classifying in mode, with exemplars already
+// embedded along the axes of a three dimensional space
+func intentAgent(provider llm.Provider, mode IntentMode) *Agent {
+	a := newTestAgent(provider)
+	a.intent = IntentConfig{Mode: mode}.withDefaults()
+	a.intentState.vectors = map[Intent][][]float32{
+		IntentMemory:     {{1, 0, 0}},
+		IntentStatus:     {{0, 1, 0}},
+		IntentGovernance: {{0, 0, 1}},
+	}
+	return a
+}
+
+func TestMatchIntent(t *testing.T) {
+	vectors := intentAgent(nil, IntentModeAuto).intentState.vectors
+	for _, tt := range []struct {
+		embedding []float32
+		want      Intent
+	}{
+		{[]float32{0.9, 0.1, 0}, IntentMemory},
+		{[]float32{0, 0.2, 1}, IntentGovernance},
+		{[]float32{1, 1, 0}, IntentUnknown},   // Tied
+		{[]float32{1, 1, 1.2}, IntentUnknown}, // Below the threshold
+		{[]float32{1, 0}, IntentUnknown},      // Other dimension
+	} {
+		if got, _ := matchIntent(tt.embedding, vectors, DefaultIntentThreshold, DefaultIntentMargin); got != tt.want {
+			t.Errorf("matchIntent(%v) = %q, want %q", tt.embedding, got, tt.want)
+		}
+	}
+}
+
+func TestClassifyIntent_Modes(t *testing.T) {
+	ctx := context.Background()
+	distinct := []float32{0, 1, 0}
+	ambiguous := []float32{1, 1, 0}
+
+	provider := &requestRecorder{reply: "Governance."}
+	a := intentAgent(provider, IntentModeAuto)
+	if got := a.classifyIntent(ctx, "how are you running?", distinct); got != IntentStatus {
+		t.Errorf("auto, clear = %q, want status", got)
+	}
+	if len(provider.requests) != 0 {
+		t.Errorf("a clear match should not ask the LLM")
+	}
+	if got := a.classifyIntent(ctx, "what now?", ambiguous); got != IntentGovernance {
+		t.Errorf("auto, ambiguous = %q, want the LLM's governance", got)
+	}
+	if len(provider.requests) != 1 || provider.requests[0].Profile != llm.ProfileClassification {
+		t.Errorf("an ambiguous match should ask the LLM once with the classification profile")
+	}
+
+	provider = &requestRecorder{reply: "memory"}
+	a = intentAgent(provider, IntentModeEmbedding)
+	if got := a.classifyIntent(ctx, "what now?", ambiguous); got != IntentUnknown || len(provider.requests) != 0 {
+		t.Errorf("embedding, ambiguous = %q after %d completions, want unknown without the LLM", got, len(provider.requests))
+	}
+
+	a = intentAgent(provider, IntentModeLLM)
+	if got := a.classifyIntent(ctx, "how are you running?", distinct); got != IntentMemory || len(provider.requests) != 1 {
+		t.Errorf("llm = %q after %d completions, want the LLM's memory", got, len(provider.requests))
+	}
+
+	for _, mode := range []IntentMode{"", IntentModeOff} {
+		if got := intentAgent(provider, mode).classifyIntent(ctx, "x", distinct); got != IntentUnknown {
+			t.Errorf("mode %q = %q, want unknown", mode, got)
+		}
+	}
+}
+
+func TestExemplarVectors_Cached(t *testing.T) {
+	provider := &countingEmbedder{}
+	a := newTestAgent(&mockLLMProvider{})
+	a.embedder = provider
+	for i := 0; i < 2; i++ {
+		if _, err := a.exemplarVectors(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := 0
+	for _, exemplars := range intentExemplars {
+		want += len(exemplars)
+	}
+	if provider.calls != want {
+		t.Errorf("embedded %d times, want each of the %d exemplars once", provider.calls, want)
+	}
+}
+
+type countingEmbedder struct{ calls int }
+
+func (e *countingEmbedder) Name() string { return "counting" }
+func (e *countingEmbedder) Embed(_ context.Context, _ string) ([]float32, error) {
+	e.calls++
+	return []float32{1, 0, 0}, nil
+}
+
+func TestParseIntent(t *testing.T) {
+	for answer, want := range map[string]Intent{
+		"governance":          IntentGovernance,
+		"  **Memory**\n":      IntentMemory,
+		"Conversation. It's…": IntentConversation,
+		"weather":             IntentUnknown,
+		"":                    IntentUnknown,
+	} {
+		if got := parseIntent(answer); got != want {
+			t.Errorf("parseIntent(%q) = %q, want %q", answer, got, want)
+		}
+	}
+}
+
+func TestProcessMessage_RoutesTools(t *testing.T) {
+	provider := &requestRecorder{reply: "Here are the rules."}
+	a := intentAgent(provider, IntentModeEmbedding)
+	a.embedder = &countingEmbedder{} // Embeds every message as memory
+
+	if _, err := a.ProcessMessage(context.Background(), "what did I tell you yesterday?"); err != nil {
+		t.Fatal(err)
+	}
+	if len(provider.requests) != 1 {
+		t.Fatalf("completions = %d, want 1", len(provider.requests))
+	}
+	var names []string
+	for _, tool := range provider.requests[0].Tools {
+		names = append(names, tool.Name)
+	}
+	if len(names) != 3 || names[0] != "search_memories" {
+		t.Errorf("tools offered = %v, want only the memory tools", names)
+	}
+}
@@ -0,0 +1,75 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"otter-ai/internal/errs"
+	"otter-ai/internal/events"
+)
+
+// ErrMaintenance is returned for messages while the otter is in
+// maintenance mode
+var ErrMaintenance = errs.New(errs.ErrUnavailable, "otter is in maintenance mode")
+
+// Mode is the otter's operating mode, switched during backups, migrations
+// and incident response
+type Mode struct {
+	// ReadOnly refuses memory writes and the proposals, votes and joins
+	// made through this otter. Chat is still answered, without being
+	// remembered.
+	ReadOnly bool `json:"read_only"`
+	// Maintenance refuses chat with ErrMaintenance; health checks and the
+	// admin and read endpoints still answer
+	Maintenance bool       `json:"maintenance"`
+	Reason      string     `json:"reason,omitempty"`
+	ChangedBy   string     `json:"changed_by,omitempty"`
+	ChangedAt   *time.Time `json:"changed_at,omitempty"`
+}
+
+// Normal reports whether neither read-only nor maintenance mode is on
+func (m Mode) Normal() bool {
+	return !m.ReadOnly && !m.Maintenance
+}
+
+// Mode returns the otter's operating mode
+func (a *Agent) Mode() Mode {
+	if mode := a.mode.Load(); mode != nil {
+		return *mode
+	}
+	return Mode{}
+}
+
+// SetMode switches the otter into or out of read-only and maintenance
+// mode, and returns the mode set. The change is logged and published as a
+// mode.changed event.
+func (a *Agent) SetMode(ctx context.Context, mode Mode) Mode {
+	now := time.Now()
+	mode.Reason = strings.TrimSpace(mode.Reason)
+	mode.ChangedAt = &now
+
+	if a.memory != nil {
+		a.memory.SetReadOnly(mode.ReadOnly)
+	}
+	if a.governance != nil {
+		a.governance.SetReadOnly(mode.ReadOnly)
+	}
+	a.mode.Store(&mode)
+
+	a.log().WarnContext(ctx, "operating mode changed", "read_only", mode.ReadOnly, "maintenance", mode.Maintenance,
+		"reason", mode.Reason, "changed_by", mode.ChangedBy)
+	a.events.Publish(events.ModeChanged, mode)
+	return mode
+}
+
+// unlessReadOnly skips a background job while memory is read-only, as it
+// could only fail to store what it produced
+func (a *Agent) unlessReadOnly(job func(ctx context.Context)) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		if a.memory != nil && a.memory.ReadOnly() {
+			return
+		}
+		job(ctx)
+	}
+}
@@ -0,0 +1,63 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"otter-ai/internal/errs"
+	"otter-ai/internal/events"
+	"otter-ai/internal/memory"
+)
+
+func TestMode_MaintenanceRefusesChat(t *testing.T) {
+	bus := events.NewBus()
+	sub := bus.Subscribe(1, events.ModeChanged)
+	a := New(Config{
+		Memory: memory.New(&mockVectorDB{}),
+		LLM:    &mockLLMProvider{completeResp: "hello"},
+		Events: bus,
+		Mode:   Mode{Maintenance: true, Reason: "migrating"},
+	})
+	defer a.Shutdown(context.Background())
+
+	if mode := a.Mode(); !mode.Maintenance || mode.Reason != "migrating" || mode.ChangedAt == nil {
+		t.Errorf("mode = %+v, want maintenance set from the config", mode)
+	}
+	if _, err := a.ProcessMessage(context.Background(), "hi"); !errors.Is(err, ErrMaintenance) || !errors.Is(err, errs.ErrUnavailable) {
+		t.Errorf("err = %v, want ErrMaintenance", err)
+	}
+	select {
+	case event := <-sub.C:
+		if mode, ok := event.Data.(Mode); !ok || !mode.Maintenance {
+			t.Errorf("event data = %+v", event.Data)
+		}
+	default:
+		t.Error("no mode.changed event published")
+	}
+
+	a.SetMode(context.Background(), Mode{})
+	if reply, err := a.ProcessMessage(context.Background(), "hi"); err != nil || reply != "hello" {
+		t.Errorf("after maintenance: reply = %q, err = %v", reply, err)
+	}
+}
+
+func TestMode_ReadOnlyStillAnswers(t *testing.T) {
+	mem := memory.New(&mockVectorDB{})
+	a := New(Config{Memory: mem, LLM: &mockLLMProvider{completeResp: "hello"}})
+	defer a.Shutdown(context.Background())
+
+	a.SetMode(context.Background(), Mode{ReadOnly: true, ChangedBy: "operator"})
+	if !mem.ReadOnly() {
+		t.Error("memory should be read-only")
+	}
+	if reply, err := a.ProcessMessage(context.Background(), "hi"); err != nil || reply != "hello" {
+		t.Errorf("reply = %q, err = %v, want an answer without remembering it", reply, err)
+	}
+
+	ran := false
+	a.unlessReadOnly(func(context.Context) { ran = true })(context.Background())
+	if ran {
+		t.Error("background job ran while read-only")
+	}
+}
@@ -0,0 +1,145 @@
+package agent
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"otter-ai/internal/memory"
+)
+
+// MaxMusingsInPrompt is the default number of recent musings added to prompts
+const MaxMusingsInPrompt = 3
+
+// MusingConfig tunes the idle reflection loop
+type MusingConfig struct {
+	Interval     time.Duration // How often the loop runs; zero disables it
+	MemoryWindow int           // Recent long-term memories reviewed per run
+	MinMemories  int           // Fewer recent memories than this skips the run
+	Timeout      time.Duration // Bounds one run
+	PromptLimit  int           // Recent musings added to prompts; negative disables
+}
+
+// DefaultMusingConfig returns the reflection loop's default settings
+func DefaultMusingConfig() MusingConfig {
+	return MusingConfig{
+		Interval:     IdleMusingInterval,
+		MemoryWindow: IdleMusingMemoryWindow,
+		MinMemories:  IdleMusingMinMemories,
+		Timeout:      IdleMusingTimeout,
+		PromptLimit:  MaxMusingsInPrompt,
+	}
+}
+
+// withDefaults fills unset fields other than Interval with their defaults
+func (c MusingConfig) withDefaults() MusingConfig {
+	d := DefaultMusingConfig()
+	if c.MemoryWindow <= 0 {
+		c.MemoryWindow = d.MemoryWindow
+	}
+	if c.MinMemories <= 0 {
+		c.MinMemories = d.MinMemories
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = d.Timeout
+	}
+	if c.PromptLimit == 0 {
+		c.PromptLimit = d.PromptLimit
+	}
+	return c
+}
+
+// MusingStatus reports the reflection loop's configuration and activity
+type MusingStatus struct {
+	Enabled         bool       `json:"enabled"`
+	IntervalSeconds int64      `json:"interval_seconds"`
+	Running         bool       `json:"running"`   // A run is in progress
+	Runs            int        `json:"runs"`      // Runs since startup
+	Generated       int        `json:"generated"` // Runs that stored a musing
+	Skipped         int        `json:"skipped"`   // Runs with nothing new to reflect on, or that failed
+	LastRunAt       *time.Time `json:"last_run_at,omitempty"`
+	LastMusingAt    *time.Time `json:"last_musing_at,omitempty"`
+	LastError       string     `json:"last_error,omitempty"` // Error of the latest run, if it failed
+}
+
+// musingStats records reflection loop activity
+type musingStats struct {
+	mu           sync.Mutex
+	runs         int
+	generated    int
+	skipped      int
+	lastRunAt    time.Time
+	lastMusingAt time.Time
+	lastError    string
+}
+
+// record notes one run's outcome; a nil musing without error is a run that
+// found nothing new to reflect on
+func (s *musingStats) record(musing *memory.MemoryRecord, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runs++
+	s.lastRunAt = time.Now()
+	s.lastError = ""
+	switch {
+	case err != nil:
+		s.lastError = err.Error()
+		s.skipped++
+	case musing == nil:
+		s.skipped++
+	default:
+		s.generated++
+		s.lastMusingAt = s.lastRunAt
+	}
+}
+
+// MusingStatus returns the reflection loop's configuration and activity
+func (a *Agent) MusingStatus() MusingStatus {
+	s := &a.musingStats
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := MusingStatus{
+		Enabled:         a.musing.Interval > 0,
+		IntervalSeconds: int64(a.musing.Interval.Seconds()),
+		Running:         a.musingActive.Load(),
+		Runs:            s.runs,
+		Generated:       s.generated,
+		Skipped:         s.skipped,
+		LastError:       s.lastError,
+	}
+	if !s.lastRunAt.IsZero() {
+		at := s.lastRunAt
+		status.LastRunAt = &at
+	}
+	if !s.lastMusingAt.IsZero() {
+		at := s.lastMusingAt
+		status.LastMusingAt = &at
+	}
+	return status
+}
+
+// ListMusings returns the most recent musings, newest first
+func (a *Agent) ListMusings(ctx context.Context, limit int) ([]memory.MemoryRecord, error) {
+	return a.memory.List(ctx, memory.MemoryTypeMusing, limit, 0)
+}
+
+// buildMusingContext lists the agent's recent musings for the prompt, as
+// many as fit the budget. They are framed as the agent's own reflections,
+// not as facts from the user.
+func (a *Agent) buildMusingContext(ctx context.Context, budget *promptBudget) string {
+	limit := a.musing.withDefaults().PromptLimit
+	if limit < 0 || a.memory == nil {
+		return ""
+	}
+	musings, err := a.ListMusings(ctx, limit)
+	if err != nil || len(musings) == 0 {
+		return ""
+	}
+
+	items := make([]string, len(musings))
+	for i, m := range musings {
+		items[i] = sanitizeForPrompt(m.Content)
+	}
+	return budgetedList(budget, "Your own recent reflections (tentative; not facts from the user):", items)
+}
@@ -0,0 +1,64 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGenerateIdleMusing_SurfacesInPrompt(t *testing.T) {
+	llmProv := &mockLLMProvider{completeResp: "The user keeps asking about tides.", embedResp: []float32{1, 0}}
+	a := newTestConsolidationAgent(t, llmProv)
+	ctx := context.Background()
+
+	storeTestMemory(t, a, "high tide was at 6am", []float32{1, 0}, time.Hour, 0.5, false)
+	musing, err := a.generateIdleMusing(ctx)
+	if err != nil || musing != nil {
+		t.Fatalf("expected no musing below MinMemories, got %v, %v", musing, err)
+	}
+
+	storeTestMemory(t, a, "low tide was at noon", []float32{0, 1}, 30*time.Minute, 0.5, false)
+	musing, err = a.generateIdleMusing(ctx)
+	if err != nil || musing == nil {
+		t.Fatalf("expected a musing, got %v, %v", musing, err)
+	}
+	a.musingStats.record(musing, err)
+
+	// Nothing new since the last musing
+	again, err := a.generateIdleMusing(ctx)
+	if err != nil || again != nil {
+		t.Fatalf("expected no repeat musing, got %v, %v", again, err)
+	}
+	a.musingStats.record(again, err)
+
+	prompt := a.buildMusingContext(ctx, nil)
+	if !containsStr(prompt, "keeps asking about tides") || !containsStr(prompt, "not facts from the user") {
+		t.Errorf("musing missing from prompt context: %q", prompt)
+	}
+
+	a.musing.PromptLimit = -1
+	if prompt := a.buildMusingContext(ctx, nil); prompt != "" {
+		t.Errorf("expected no musing context when disabled, got %q", prompt)
+	}
+
+	status := a.MusingStatus()
+	if status.Enabled || status.Runs != 2 || status.Generated != 1 || status.Skipped != 1 || status.LastMusingAt == nil {
+		t.Errorf("unexpected status %+v", status)
+	}
+}
+
+func TestMusingStats_RecordsErrors(t *testing.T) {
+	a := newTestAgent(nil)
+	a.musingStats.record(nil, errors.New("llm unavailable"))
+
+	status := a.MusingStatus()
+	if status.LastError != "llm unavailable" || status.Skipped != 1 || status.LastRunAt == nil {
+		t.Errorf("unexpected status %+v", status)
+	}
+
+	a.musingStats.record(nil, nil)
+	if status := a.MusingStatus(); status.LastError != "" {
+		t.Errorf("a clean run should clear the last error, got %q", status.LastError)
+	}
+}
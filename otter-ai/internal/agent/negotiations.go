@@ -0,0 +1,79 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"otter-ai/internal/governance"
+)
+
+// negotiationsCommand lists the negotiated compromises awaiting approval
+func (a *Agent) negotiationsCommand() string {
+	pending := a.governance.Negotiations(governance.NegotiationResolved)
+	if len(pending) == 0 {
+		return "No negotiated compromises are awaiting approval."
+	}
+	var b strings.Builder
+	b.WriteString("Compromises awaiting approval:")
+	for _, negotiation := range pending {
+		fmt.Fprintf(&b, "\n- %s: rafts %s and %s, %d conflicts\n    %s: %s",
+			negotiation.NegotiationID, negotiation.Raft1ID, negotiation.Raft2ID, len(negotiation.Conflicts),
+			negotiation.ProposedRule.Scope, negotiation.ProposedRule.Body)
+	}
+	b.WriteString("\n/approve <ID> [new text] proposes one to both rafts; /reject <ID> [reason] drops it.")
+	return b.String()
+}
+
+// approveCommand stages approving a negotiated compromise, optionally with
+// reworded text. Nothing is proposed until the user confirms.
+func (a *Agent) approveCommand(args string) string {
+	id, body, _ := strings.Cut(args, " ")
+	if id == "" {
+		return "Usage: /approve <negotiation ID> [new text]"
+	}
+	negotiation, err := a.governance.Negotiation(id)
+	if err != nil {
+		return fmt.Sprintf("There is no negotiation %s; /negotiations lists those awaiting approval.", id)
+	}
+	if negotiation.Status != governance.NegotiationResolved {
+		return fmt.Sprintf("Negotiation %s is %s, not awaiting approval.", id, negotiation.Status)
+	}
+
+	body = strings.TrimSpace(body)
+	text := negotiation.ProposedRule.Body
+	if body != "" {
+		text = body
+	}
+	a.setPendingAction(&pendingGovernanceAction{
+		Action:        "approve_negotiation",
+		NegotiationID: id,
+		RuleBody:      body,
+		Scope:         negotiation.ProposedRule.Scope,
+		CreatedAt:     time.Now(),
+	})
+	return fmt.Sprintf("This proposes the compromise to rafts %s and %s:\n  %s: %s\nReply \"confirm\" to propose it or \"cancel\" to leave it for later.",
+		negotiation.Raft1ID, negotiation.Raft2ID, negotiation.ProposedRule.Scope, text)
+}
+
+// approveNegotiationConfirmed approves a compromise the user confirmed
+func (a *Agent) approveNegotiationConfirmed(ctx context.Context, id, body string) string {
+	negotiation, err := a.governance.ApproveNegotiation(ctx, id, UserFromContext(ctx), body)
+	if err != nil {
+		return fmt.Sprintf("I couldn't approve negotiation %s: %v", id, err)
+	}
+	return fmt.Sprintf("Approved. The compromise is now up for a vote in rafts %s and %s.", negotiation.Raft1ID, negotiation.Raft2ID)
+}
+
+// rejectCommand turns a negotiated compromise down
+func (a *Agent) rejectCommand(ctx context.Context, args string) string {
+	id, reason, _ := strings.Cut(args, " ")
+	if id == "" {
+		return "Usage: /reject <negotiation ID> [reason]"
+	}
+	if _, err := a.governance.RejectNegotiation(ctx, id, UserFromContext(ctx), reason); err != nil {
+		return fmt.Sprintf("I couldn't reject negotiation %s: %v", id, err)
+	}
+	return fmt.Sprintf("Rejected negotiation %s; nothing was proposed.", id)
+}
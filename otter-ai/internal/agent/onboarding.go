@@ -0,0 +1,369 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"otter-ai/internal/errs"
+	"otter-ai/internal/events"
+	"otter-ai/internal/governance"
+	"otter-ai/internal/memory"
+	"otter-ai/internal/plugins"
+)
+
+// onboardActionPrefix starts the action IDs of onboarding "Done" buttons,
+// which have the form onboard:<onboarding_id>:<step_id>
+const onboardActionPrefix = "onboard:"
+
+// OnboardingTimeout bounds starting one member's onboarding
+const OnboardingTimeout = 30 * time.Second
+
+// ErrNotOnboardee is returned when someone other than the member being
+// onboarded tries to complete one of their steps
+var ErrNotOnboardee = errs.New(errs.ErrUnauthorized, "platform user is not the member being onboarded")
+
+// DefaultOnboardingSteps are the templates sent to new members, in order
+var DefaultOnboardingSteps = []string{"welcome", "rules", "proposals", "voting"}
+
+// defaultOnboardingTemplates defines one template per default step. Custom
+// template files may redefine any of them or add new steps.
+const defaultOnboardingTemplates = `
+{{define "welcome"}}Welcome to raft {{.RaftID}}, {{.MemberID}}! You were inducted by {{.InductedBy}} and the raft now has {{.Members}} active members. I'll walk you through how things work here; press Done after each step for the next one.{{end}}
+
+{{define "rules"}}{{if .Rules}}The raft's active rules:
+{{range .Rules}}- {{.Scope}} (v{{.Version}}): {{.Body}}
+{{end}}{{else}}The raft has no active rules yet.{{end}}{{end}}
+
+{{define "proposals"}}{{if .Proposals}}Proposals open for your vote:
+{{range .Proposals}}- {{describe .}} (closes {{date .Deadline}})
+{{end}}{{else}}There are no open proposals right now.{{end}}{{end}}
+
+{{define "voting"}}How voting works: any member can propose a rule or the removal of a member. Proposals stay open for {{.VotingPeriod}}. In this raft {{.QuorumPercentage}}% of members must vote and {{.MajorityPercentage}}% must vote YES to adopt a rule; overriding an existing rule or changing these thresholds takes {{.SuperMajorityPercentage}}%. You can vote YES, NO or ABSTAIN from the buttons on posted proposals.{{end}}
+`
+
+// OnboardingData is what onboarding templates are rendered with
+type OnboardingData struct {
+	RaftID                  string
+	MemberID                string
+	InductedBy              string
+	Members                 int                    // Active members, including the new one
+	Rules                   []*governance.Rule     // Active rules, by scope
+	Proposals               []*governance.Proposal // Open proposals, oldest first
+	VotingPeriod            time.Duration
+	QuorumPercentage        int // From the raft's voting policy
+	MajorityPercentage      int
+	SuperMajorityPercentage int
+}
+
+// OnboardingWorkflow is the ordered list of templated steps new raft
+// members are guided through
+type OnboardingWorkflow struct {
+	steps     []string
+	templates *template.Template
+}
+
+var onboardingFuncs = template.FuncMap{
+	"describe": describeProposal,
+	"date":     func(t time.Time) string { return t.UTC().Format(time.RFC1123) },
+}
+
+// NewOnboardingWorkflow builds a workflow from the default templates,
+// overridden or extended by the {{define}} blocks in templateFile if given.
+// Empty steps uses DefaultOnboardingSteps.
+func NewOnboardingWorkflow(templateFile string, steps []string) (*OnboardingWorkflow, error) {
+	templates := template.Must(template.New("onboarding").Funcs(onboardingFuncs).Parse(defaultOnboardingTemplates))
+	if templateFile != "" {
+		data, err := os.ReadFile(templateFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read onboarding templates: %w", err)
+		}
+		if templates, err = templates.Parse(string(data)); err != nil {
+			return nil, fmt.Errorf("failed to parse onboarding templates: %w", err)
+		}
+	}
+
+	if len(steps) == 0 {
+		steps = DefaultOnboardingSteps
+	}
+	for _, step := range steps {
+		if templates.Lookup(step) == nil {
+			return nil, fmt.Errorf("no onboarding template for step %q", step)
+		}
+		if strings.Contains(step, ":") {
+			return nil, fmt.Errorf("onboarding step %q must not contain ':'", step)
+		}
+	}
+	return &OnboardingWorkflow{steps: steps, templates: templates}, nil
+}
+
+// render executes one step's template
+func (w *OnboardingWorkflow) render(step string, data *OnboardingData) (string, error) {
+	var b strings.Builder
+	if err := w.templates.ExecuteTemplate(&b, step, data); err != nil {
+		return "", fmt.Errorf("failed to render onboarding step %s: %w", step, err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// describeProposal summarizes a proposal in one line
+func describeProposal(p *governance.Proposal) string {
+	if p.Kind == governance.ProposalKindEviction {
+		return "revoke member " + p.TargetMemberID
+	}
+	if p.Kind == governance.ProposalKindAdmission {
+		return "admit member " + p.TargetMemberID
+	}
+	if p.Kind == governance.ProposalKindSuspension && p.Rule != nil {
+		return fmt.Sprintf("suspend rule for %s: %s", p.Rule.Scope, p.Rule.Body)
+	}
+	if p.Kind == governance.ProposalKindReinstatement && p.Rule != nil {
+		return fmt.Sprintf("reinstate suspended rule for %s: %s", p.Rule.Scope, p.Rule.Body)
+	}
+	if p.Rule != nil {
+		return fmt.Sprintf("rule for %s: %s", p.Rule.Scope, p.Rule.Body)
+	}
+	return p.ProposalID
+}
+
+// onboardingID identifies a member's onboarding in a raft
+func onboardingID(raftID, memberID string) string {
+	return raftID + ":" + memberID
+}
+
+// startOnboardingListener onboards members as governance reports them joining
+func (a *Agent) startOnboardingListener(bus *events.Bus) {
+	a.listen(bus, func(event events.Event) {
+		joined, ok := event.Data.(governance.MemberEvent)
+		if !ok || joined.MemberID == a.governance.GetID() {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), OnboardingTimeout)
+		defer cancel()
+		if _, err := a.StartOnboarding(ctx, joined.RaftID, joined.MemberID, joined.InductedBy); err != nil {
+			a.log().Warn("failed to onboard member", "member_id", joined.MemberID, "raft_id", joined.RaftID, "error", err)
+		}
+	}, events.MemberJoined)
+}
+
+// listen calls handle for each event of the given types until the agent
+// shuts down
+func (a *Agent) listen(bus *events.Bus, handle func(events.Event), types ...string) {
+	sub := bus.Subscribe(0, types...)
+	a.idleWG.Add(1)
+	go func() {
+		defer a.idleWG.Done()
+		defer sub.Close()
+
+		for {
+			select {
+			case event, ok := <-sub.C:
+				if !ok {
+					return
+				}
+				handle(event)
+			case <-a.idleStop:
+				return
+			}
+		}
+	}()
+}
+
+// StartOnboarding begins, or restarts, a member's onboarding and sends its
+// first step to the member's contact
+func (a *Agent) StartOnboarding(ctx context.Context, raftID, memberID, inductedBy string) (*memory.OnboardingRecord, error) {
+	if a.onboarding == nil {
+		return nil, fmt.Errorf("onboarding is disabled")
+	}
+
+	record := &memory.OnboardingRecord{
+		ID:         onboardingID(raftID, memberID),
+		RaftID:     raftID,
+		MemberID:   memberID,
+		InductedBy: inductedBy,
+		StartedAt:  time.Now(),
+	}
+	if contact, ok := a.contacts[memberID]; ok {
+		record.Platform, record.UserID, _ = strings.Cut(contact, ":")
+	}
+	for _, step := range a.onboarding.steps {
+		record.Steps = append(record.Steps, memory.OnboardingStep{ID: step})
+	}
+
+	a.onboardingMu.Lock()
+	defer a.onboardingMu.Unlock()
+	a.sendNextOnboardingStep(ctx, record)
+	if err := a.memory.SaveOnboarding(ctx, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// CompleteOnboardingStep marks a step done and sends the next one. Once
+// every step is done the onboarding is complete.
+func (a *Agent) CompleteOnboardingStep(ctx context.Context, id, stepID string) (*memory.OnboardingRecord, error) {
+	a.onboardingMu.Lock()
+	defer a.onboardingMu.Unlock()
+
+	record, err := a.memory.LoadOnboarding(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	for i := range record.Steps {
+		if record.Steps[i].ID != stepID {
+			continue
+		}
+		found = true
+		if record.Steps[i].CompletedAt == nil {
+			now := time.Now()
+			record.Steps[i].CompletedAt = &now
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: %s has no step %q", memory.ErrOnboardingNotFound, id, stepID)
+	}
+
+	a.sendNextOnboardingStep(ctx, record)
+	if err := a.memory.SaveOnboarding(ctx, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// ListOnboardings returns onboarding records, most recently updated first
+func (a *Agent) ListOnboardings(ctx context.Context) ([]memory.OnboardingRecord, error) {
+	return a.memory.ListOnboardings(ctx, 100, 0)
+}
+
+// sendNextOnboardingStep sends the first incomplete step if it has not been
+// sent yet, or marks the onboarding complete when every step is done.
+// Failures are recorded on the record rather than returned, so the
+// onboarding can be advanced later through the API.
+func (a *Agent) sendNextOnboardingStep(ctx context.Context, record *memory.OnboardingRecord) {
+	var next *memory.OnboardingStep
+	for i := range record.Steps {
+		if record.Steps[i].CompletedAt == nil {
+			next = &record.Steps[i]
+			break
+		}
+	}
+	if next == nil {
+		if record.CompletedAt == nil {
+			now := time.Now()
+			record.CompletedAt = &now
+		}
+		return
+	}
+	if next.SentAt != nil {
+		return
+	}
+	if a.onboarding == nil {
+		record.LastError = "onboarding is disabled"
+		return
+	}
+	if record.Platform == "" || a.plugins == nil {
+		record.LastError = "member has no onboarding contact"
+		return
+	}
+
+	content, err := a.onboarding.render(next.ID, a.onboardingData(record))
+	if err != nil {
+		record.LastError = err.Error()
+		return
+	}
+	message := &plugins.Message{
+		Platform:  record.Platform,
+		UserID:    record.UserID,
+		Content:   content,
+		Timestamp: time.Now().Unix(),
+		Metadata:  map[string]interface{}{"onboarding_id": record.ID, "step": next.ID},
+		Actions: []plugins.Action{{
+			ID:    onboardActionPrefix + record.ID + ":" + next.ID,
+			Label: "Done",
+			Emoji: "✅",
+			Style: plugins.ActionStylePrimary,
+		}},
+	}
+	if err := a.plugins.SendMessage(ctx, record.Platform, message); err != nil {
+		record.LastError = err.Error()
+		return
+	}
+
+	now := time.Now()
+	next.SentAt = &now
+	record.LastError = ""
+}
+
+// onboardingData gathers the raft state onboarding templates describe
+func (a *Agent) onboardingData(record *memory.OnboardingRecord) *OnboardingData {
+	data := &OnboardingData{
+		RaftID:                  record.RaftID,
+		MemberID:                record.MemberID,
+		InductedBy:              record.InductedBy,
+		VotingPeriod:            a.governance.VotingPeriod(),
+		QuorumPercentage:        governance.QuorumPercentage,
+		MajorityPercentage:      governance.QuorumPercentage,
+		SuperMajorityPercentage: governance.SuperMajorityPercentage,
+	}
+	if policy, err := a.governance.VotingPolicy(record.RaftID); err == nil {
+		data.QuorumPercentage = int(math.Round(policy.Quorum * 100))
+		data.MajorityPercentage = int(math.Round(policy.Majority * 100))
+		data.SuperMajorityPercentage = int(math.Round(policy.SuperMajority * 100))
+	}
+
+	if members, err := a.governance.GetRaftMembers(record.RaftID); err == nil {
+		for _, m := range members {
+			if m.State == governance.StateActive {
+				data.Members++
+			}
+		}
+	}
+
+	for _, rule := range a.governance.GetActiveRulesForRaft(record.RaftID) {
+		data.Rules = append(data.Rules, rule)
+	}
+	sort.Slice(data.Rules, func(i, j int) bool { return data.Rules[i].Scope < data.Rules[j].Scope })
+
+	for _, p := range a.governance.GetOpenProposals() {
+		if p.RaftID == record.RaftID {
+			data.Proposals = append(data.Proposals, p)
+		}
+	}
+	sort.Slice(data.Proposals, func(i, j int) bool { return data.Proposals[i].ProposedAt.Before(data.Proposals[j].ProposedAt) })
+	return data
+}
+
+// HandleOnboardingInteraction completes the step behind an onboarding
+// "Done" action. Only the member being onboarded can complete their steps.
+func (a *Agent) HandleOnboardingInteraction(ctx context.Context, interaction *plugins.Interaction) (string, error) {
+	rest := strings.TrimPrefix(interaction.ActionID, onboardActionPrefix)
+	i := strings.LastIndex(rest, ":")
+	if i <= 0 {
+		return "", fmt.Errorf("unknown action: %s", interaction.ActionID)
+	}
+	id, stepID := rest[:i], rest[i+1:]
+
+	record, err := a.memory.LoadOnboarding(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if record.Platform != interaction.Platform || record.UserID != interaction.UserID {
+		return "", fmt.Errorf("%w: %s user %s", ErrNotOnboardee, interaction.Platform, interaction.UserID)
+	}
+
+	record, err = a.CompleteOnboardingStep(ctx, id, stepID)
+	if err != nil {
+		return "", err
+	}
+	if record.CompletedAt != nil {
+		return "Onboarding complete. Welcome aboard!", nil
+	}
+	return "Thanks! Sent the next step.", nil
+}
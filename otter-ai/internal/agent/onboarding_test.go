@@ -0,0 +1,122 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"otter-ai/internal/config"
+	"otter-ai/internal/events"
+	"otter-ai/internal/governance"
+	"otter-ai/internal/memory"
+	"otter-ai/internal/plugins"
+)
+
+func newOnboardingAgent(t *testing.T, workflow *OnboardingWorkflow) (*Agent, *chatPlugin, *governance.Governance) {
+	t.Helper()
+	plugin := &chatPlugin{}
+	mgr := plugins.NewManager(config.PluginConfig{})
+	mgr.Register(plugin)
+
+	a, gov := newTestGovernedAgent(t, Config{
+		Plugins:    mgr,
+		Onboarding: workflow,
+		Contacts:   map[string]string{"otter-2": "chat:bob"},
+		Events:     events.NewBus(),
+	})
+	return a, plugin, gov
+}
+
+func TestOnboarding_GuidesNewMember(t *testing.T) {
+	workflow, err := NewOnboardingWorkflow("", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, plugin, gov := newOnboardingAgent(t, workflow)
+	ctx := context.Background()
+
+	if _, err := gov.ProposeRule(ctx, "otter-1", &governance.Rule{Scope: "tone", Body: "be kind", ProposedBy: "otter-1"}); err != nil {
+		t.Fatal(err)
+	}
+	joinPeer(t, gov, "otter-1", "otter-2")
+
+	id := onboardingID("otter-1", "otter-2")
+	deadline := time.Now().Add(5 * time.Second)
+	for len(plugin.messages()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	sent := plugin.messages()
+	if len(sent) != 1 || sent[0].UserID != "bob" || sent[0].ChannelID != "" || !contains(sent[0].Content, "inducted by otter-1") {
+		t.Fatalf("expected a welcome DM to bob, got %+v", sent)
+	}
+
+	action := sent[0].Actions[0].ID
+	if _, err := plugin.handler(ctx, &plugins.Interaction{Platform: "chat", UserID: "mallory", ActionID: action}); !errors.Is(err, ErrNotOnboardee) {
+		t.Errorf("expected ErrNotOnboardee, got %v", err)
+	}
+
+	for i := 0; i < len(DefaultOnboardingSteps); i++ {
+		sent = plugin.messages()
+		if len(sent) != i+1 {
+			t.Fatalf("step %d: sent %d messages", i, len(sent))
+		}
+		if _, err := plugin.handler(ctx, &plugins.Interaction{Platform: "chat", UserID: "bob", ActionID: sent[i].Actions[0].ID}); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+	}
+	if !contains(plugin.messages()[2].Content, "be kind") {
+		t.Errorf("proposals step should list the open proposal: %q", plugin.messages()[2].Content)
+	}
+
+	record, err := a.memory.LoadOnboarding(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.CompletedAt == nil || len(plugin.messages()) != len(DefaultOnboardingSteps) {
+		t.Errorf("onboarding should be complete after every step: %+v", record)
+	}
+}
+
+func TestStartOnboarding_WithoutContact(t *testing.T) {
+	workflow, _ := NewOnboardingWorkflow("", nil)
+	a, plugin, _ := newOnboardingAgent(t, workflow)
+	ctx := context.Background()
+
+	record, err := a.StartOnboarding(ctx, "otter-1", "otter-3", "otter-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.LastError == "" || record.Steps[0].SentAt != nil || len(plugin.messages()) != 0 {
+		t.Errorf("expected nothing sent without a contact: %+v", record)
+	}
+
+	// Steps can still be completed, e.g. through the API
+	record, err = a.CompleteOnboardingStep(ctx, record.ID, "welcome")
+	if err != nil || record.Steps[0].CompletedAt == nil {
+		t.Fatalf("CompleteOnboardingStep = %+v, %v", record, err)
+	}
+	if _, err := a.CompleteOnboardingStep(ctx, record.ID, "missing"); !errors.Is(err, memory.ErrOnboardingNotFound) {
+		t.Errorf("expected ErrOnboardingNotFound for an unknown step, got %v", err)
+	}
+}
+
+func TestNewOnboardingWorkflow_CustomTemplates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "onboarding.tmpl")
+	os.WriteFile(path, []byte(`{{define "welcome"}}Ahoy {{.MemberID}}{{end}}{{define "charter"}}Read the charter{{end}}`), 0o600)
+
+	workflow, err := NewOnboardingWorkflow(path, []string{"welcome", "charter", "voting"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := workflow.render("welcome", &OnboardingData{MemberID: "otter-2"})
+	if err != nil || got != "Ahoy otter-2" {
+		t.Errorf("render = %q, %v", got, err)
+	}
+
+	if _, err := NewOnboardingWorkflow(path, []string{"welcome", "missing"}); err == nil {
+		t.Error("expected error for a step without a template")
+	}
+}
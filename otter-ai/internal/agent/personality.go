@@ -0,0 +1,351 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"otter-ai/internal/events"
+	"otter-ai/internal/governance"
+	"otter-ai/internal/memory"
+	"otter-ai/internal/vectordb"
+)
+
+// Personality drift defaults
+const (
+	PersonalityInterval   = time.Hour
+	PersonalityDriftRate  = 0.02
+	PersonalityRuleWeight = 5
+	PersonalityTimeout    = 2 * time.Minute
+	// personalitySignalScale is the average relative similarity that moves
+	// a trait by most of DriftRate in one evolution
+	personalitySignalScale = 0.05
+	// personalityReversion is the share of the gap to its baseline a trait
+	// closes each evolution, so drift stays bounded
+	personalityReversion = 0.1
+)
+
+// TraitSeed is the initial form of a personality trait
+type TraitSeed struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Strength    float32 `json:"strength"`
+}
+
+// DefaultPersonality is the personality seeded without a seed file
+var DefaultPersonality = []TraitSeed{
+	{Name: "curious", Description: "Asks follow-up questions and enjoys exploring new ideas and topics", Strength: 0.6},
+	{Name: "playful", Description: "Uses light humour and a warm, friendly tone", Strength: 0.4},
+	{Name: "concise", Description: "Keeps answers short, direct and to the point", Strength: 0.7},
+	{Name: "cautious", Description: "Respects rules and boundaries, and flags risks before acting", Strength: 0.5},
+}
+
+// PersonalityConfig tunes how the personality is seeded and drifts
+type PersonalityConfig struct {
+	Seed       []TraitSeed   // Traits seeded when missing; nil uses DefaultPersonality
+	Interval   time.Duration // How often observed influence is applied; zero disables drift
+	DriftRate  float32       // Most a trait's strength moves per evolution from influence
+	RuleWeight float64       // Influence of an adopted rule relative to one interaction
+}
+
+// withDefaults fills unset fields other than Interval with their defaults
+func (c PersonalityConfig) withDefaults() PersonalityConfig {
+	if c.Seed == nil {
+		c.Seed = DefaultPersonality
+	}
+	if c.DriftRate <= 0 {
+		c.DriftRate = PersonalityDriftRate
+	}
+	if c.RuleWeight <= 0 {
+		c.RuleWeight = PersonalityRuleWeight
+	}
+	return c
+}
+
+// LoadPersonalitySeed reads trait seeds from a JSON array file
+func LoadPersonalitySeed(path string) ([]TraitSeed, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read personality seed: %w", err)
+	}
+	var seeds []TraitSeed
+	if err := json.Unmarshal(data, &seeds); err != nil {
+		return nil, fmt.Errorf("failed to parse personality seed: %w", err)
+	}
+	seen := make(map[string]bool, len(seeds))
+	for _, seed := range seeds {
+		if seed.Name == "" || seed.Description == "" {
+			return nil, fmt.Errorf("personality seed traits need a name and description")
+		}
+		if seed.Strength < 0 || seed.Strength > 1 {
+			return nil, fmt.Errorf("trait %s: strength must be between 0 and 1", seed.Name)
+		}
+		if seen[seed.Name] {
+			return nil, fmt.Errorf("trait %s is seeded twice", seed.Name)
+		}
+		seen[seed.Name] = true
+	}
+	return seeds, nil
+}
+
+// PersonalityStatus reports the personality and how it is drifting
+type PersonalityStatus struct {
+	Traits          []memory.PersonalityTrait `json:"traits"`
+	DriftEnabled    bool                      `json:"drift_enabled"`
+	IntervalSeconds int64                     `json:"interval_seconds"`
+	Pending         int                       `json:"pending_observations"` // Observations not yet applied
+	LastEvolvedAt   *time.Time                `json:"last_evolved_at,omitempty"`
+}
+
+// personalityState caches traits and accumulates influence between evolutions
+type personalityState struct {
+	mu            sync.Mutex
+	traits        []memory.PersonalityTrait
+	loaded        bool
+	signals       map[string]float64 // Trait name -> weighted relative similarity
+	weight        float64            // Total weight of pending observations
+	observations  int
+	lastEvolvedAt time.Time
+}
+
+// SeedPersonality stores seeded traits that are missing and embeds traits
+// without a vector. Existing traits keep their evolved strength.
+func (a *Agent) SeedPersonality(ctx context.Context) error {
+	if a.memory == nil {
+		return nil
+	}
+	a.personalityState.mu.Lock()
+	defer a.personalityState.mu.Unlock()
+
+	traits, err := a.memory.ListTraits(ctx)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]int, len(traits))
+	for i, trait := range traits {
+		existing[trait.Name] = i
+	}
+	for _, seed := range a.personality.withDefaults().Seed {
+		if _, ok := existing[seed.Name]; ok {
+			continue
+		}
+		traits = append(traits, memory.PersonalityTrait{
+			Name:        seed.Name,
+			Description: seed.Description,
+			Strength:    seed.Strength,
+			Baseline:    seed.Strength,
+		})
+		existing[seed.Name] = len(traits) - 1
+	}
+
+	for i := range traits {
+		trait := &traits[i]
+		dirty := trait.EvolvedAt.IsZero() // Newly seeded
+		if len(trait.Vector) == 0 && a.llm != nil {
+			vector, err := a.embed(ctx, trait.Description)
+			if err != nil {
+				a.log().WarnContext(ctx, "failed to embed trait", "trait", trait.Name, "error", err)
+			} else {
+				trait.Vector = vector
+				dirty = true
+			}
+		}
+		if !dirty {
+			continue
+		}
+		if err := a.memory.SaveTrait(ctx, trait); err != nil {
+			return err
+		}
+	}
+
+	a.personalityState.traits = traits
+	a.personalityState.loaded = true
+	return nil
+}
+
+// loadTraitsLocked returns the cached traits, loading them on first use.
+// The caller holds personalityState.mu.
+func (a *Agent) loadTraitsLocked(ctx context.Context) ([]memory.PersonalityTrait, error) {
+	if a.personalityState.loaded {
+		return a.personalityState.traits, nil
+	}
+	traits, err := a.memory.ListTraits(ctx)
+	if err != nil {
+		return nil, err
+	}
+	a.personalityState.traits = traits
+	a.personalityState.loaded = true
+	return traits, nil
+}
+
+// observePersonality accumulates how much more similar vector is to each
+// trait than to the traits on average. Evolution turns it into drift.
+func (a *Agent) observePersonality(ctx context.Context, vector []float32, weight float64) {
+	if a.personality.Interval <= 0 || a.memory == nil || len(vector) == 0 {
+		return
+	}
+	a.personalityState.mu.Lock()
+	defer a.personalityState.mu.Unlock()
+
+	traits, err := a.loadTraitsLocked(ctx)
+	if err != nil {
+		return
+	}
+	similarity := make(map[string]float64, len(traits))
+	var total float64
+	for _, trait := range traits {
+		if len(trait.Vector) != len(vector) {
+			continue // Not embedded, or by a different model
+		}
+		similarity[trait.Name] = vectordb.CosineSimilarity(vector, trait.Vector)
+		total += similarity[trait.Name]
+	}
+	if len(similarity) == 0 {
+		return
+	}
+
+	mean := total / float64(len(similarity))
+	if a.personalityState.signals == nil {
+		a.personalityState.signals = make(map[string]float64)
+	}
+	for name, sim := range similarity {
+		a.personalityState.signals[name] += weight * (sim - mean)
+	}
+	a.personalityState.weight += weight
+	a.personalityState.observations++
+}
+
+// EvolvePersonality applies the influence observed since the last evolution
+// and pulls traits back toward their baseline. It returns how many traits
+// changed.
+func (a *Agent) EvolvePersonality(ctx context.Context) (int, error) {
+	if a.memory == nil {
+		return 0, nil
+	}
+	cfg := a.personality.withDefaults()
+	a.personalityState.mu.Lock()
+	defer a.personalityState.mu.Unlock()
+
+	traits, err := a.loadTraitsLocked(ctx)
+	if err != nil {
+		return 0, err
+	}
+	signals, weight := a.personalityState.signals, a.personalityState.weight
+	a.personalityState.signals = nil
+	a.personalityState.weight = 0
+	a.personalityState.observations = 0
+	a.personalityState.lastEvolvedAt = time.Now()
+
+	changed := 0
+	for i := range traits {
+		trait := &traits[i]
+		strength := float64(trait.Strength)
+		if weight > 0 {
+			avg := signals[trait.Name] / weight
+			strength += float64(cfg.DriftRate) * math.Tanh(avg/personalitySignalScale)
+		}
+		strength += personalityReversion * (float64(trait.Baseline) - strength)
+		strength = math.Max(0, math.Min(1, strength))
+		if math.Abs(strength-float64(trait.Strength)) < 1e-4 {
+			continue
+		}
+
+		trait.Strength = float32(strength)
+		trait.EvolvedAt = time.Now()
+		if err := a.memory.SaveTrait(ctx, trait); err != nil {
+			return changed, err
+		}
+		changed++
+	}
+	return changed, nil
+}
+
+// Personality reports the current traits and drift activity
+func (a *Agent) Personality(ctx context.Context) (*PersonalityStatus, error) {
+	status := &PersonalityStatus{
+		Traits:          []memory.PersonalityTrait{},
+		DriftEnabled:    a.personality.Interval > 0,
+		IntervalSeconds: int64(a.personality.Interval / time.Second),
+	}
+	if a.memory == nil {
+		return status, nil
+	}
+	a.personalityState.mu.Lock()
+	defer a.personalityState.mu.Unlock()
+
+	traits, err := a.loadTraitsLocked(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status.Traits = append(status.Traits, traits...)
+	status.Pending = a.personalityState.observations
+	if t := a.personalityState.lastEvolvedAt; !t.IsZero() {
+		status.LastEvolvedAt = &t
+	}
+	return status, nil
+}
+
+// buildPersonalityContext describes the personality for the system prompt
+func (a *Agent) buildPersonalityContext(ctx context.Context) string {
+	if a.memory == nil {
+		return ""
+	}
+	a.personalityState.mu.Lock()
+	traits, err := a.loadTraitsLocked(ctx)
+	a.personalityState.mu.Unlock()
+	if err != nil || len(traits) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("Your personality (let it shape your tone, never the facts):\n")
+	for _, trait := range traits {
+		var degree string
+		switch {
+		case trait.Strength >= 0.7:
+			degree = "strongly"
+		case trait.Strength >= 0.4:
+			degree = "moderately"
+		case trait.Strength >= 0.1:
+			degree = "slightly"
+		default:
+			continue
+		}
+		fmt.Fprintf(&b, "- %s %s: %s\n", degree, trait.Name, sanitizeForPrompt(trait.Description))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// startPersonalityLoop evolves the personality every interval
+func (a *Agent) startPersonalityLoop() {
+	a.startPeriodicJob(a.personality.Interval, PersonalityTimeout, a.unlessReadOnly(func(ctx context.Context) {
+		if _, err := a.EvolvePersonality(ctx); err != nil {
+			a.log().Warn("personality evolution failed", "error", err)
+		}
+	}))
+}
+
+// startPersonalityListener lets adopted governance rules influence the
+// personality
+func (a *Agent) startPersonalityListener(bus *events.Bus) {
+	a.listen(bus, func(event events.Event) {
+		rule, ok := event.Data.(governance.RuleEvent)
+		if !ok || rule.Body == "" {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), PersonalityTimeout)
+		defer cancel()
+		vector, err := a.embed(ctx, rule.Body)
+		if err != nil {
+			a.log().Warn("failed to embed adopted rule for personality", "rule_id", rule.RuleID, "error", err)
+			return
+		}
+		a.observePersonality(ctx, vector, a.personality.withDefaults().RuleWeight)
+	}, events.RuleAdopted)
+}
@@ -0,0 +1,101 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPersonality_DriftsTowardInteractions(t *testing.T) {
+	a := newTestConsolidationAgent(t, &mockLLMProvider{embedResp: []float32{1, 0}})
+	a.personality = PersonalityConfig{
+		Seed: []TraitSeed{
+			{Name: "curious", Description: "explores ideas", Strength: 0.5},
+			{Name: "terse", Description: "short answers", Strength: 0.5},
+		},
+		Interval: time.Hour,
+	}
+	ctx := context.Background()
+
+	if err := a.SeedPersonality(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// Give the traits distinct directions
+	a.personalityState.traits[0].Vector = []float32{1, 0}
+	a.personalityState.traits[1].Vector = []float32{0, 1}
+
+	for i := 0; i < 3; i++ {
+		a.observePersonality(ctx, []float32{0.9, 0.1}, 1)
+	}
+	changed, err := a.EvolvePersonality(ctx)
+	if err != nil || changed != 2 {
+		t.Fatalf("EvolvePersonality = %d, %v", changed, err)
+	}
+	status, err := a.Personality(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	curious, terse := status.Traits[0], status.Traits[1]
+	if curious.Strength <= 0.5 || terse.Strength >= 0.5 || curious.Strength > 0.5+PersonalityDriftRate {
+		t.Errorf("expected bounded drift toward curious: %+v", status.Traits)
+	}
+
+	// Without influence traits relax back toward their baseline
+	before := curious.Strength
+	if _, err := a.EvolvePersonality(ctx); err != nil {
+		t.Fatal(err)
+	}
+	status, _ = a.Personality(ctx)
+	if status.Traits[0].Strength >= before || status.Traits[0].Strength < 0.5 {
+		t.Errorf("expected reversion toward baseline, got %v", status.Traits[0].Strength)
+	}
+
+	// Reseeding keeps evolved strengths
+	evolved := status.Traits[0].Strength
+	a.personalityState.loaded = false
+	if err := a.SeedPersonality(ctx); err != nil {
+		t.Fatal(err)
+	}
+	status, _ = a.Personality(ctx)
+	if status.Traits[0].Strength != evolved {
+		t.Errorf("reseeding reset strength to %v", status.Traits[0].Strength)
+	}
+
+	prompt := a.buildPersonalityContext(ctx)
+	if !contains(prompt, "moderately curious: explores ideas") {
+		t.Errorf("unexpected personality context %q", prompt)
+	}
+}
+
+func TestPersonality_NoMemory(t *testing.T) {
+	a := newTestAgent(&mockLLMProvider{})
+	a.observePersonality(context.Background(), []float32{1}, 1)
+	if got := a.buildPersonalityContext(context.Background()); got != "" {
+		t.Errorf("expected no personality context, got %q", got)
+	}
+	if status, err := a.Personality(context.Background()); err != nil || len(status.Traits) != 0 {
+		t.Errorf("Personality = %+v, %v", status, err)
+	}
+}
+
+func TestLoadPersonalitySeed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "personality.json")
+	os.WriteFile(path, []byte(`[{"name": "wry", "description": "dry humour", "strength": 0.3}]`), 0o600)
+	seeds, err := LoadPersonalitySeed(path)
+	if err != nil || len(seeds) != 1 || seeds[0].Strength != 0.3 {
+		t.Fatalf("LoadPersonalitySeed = %+v, %v", seeds, err)
+	}
+
+	for _, bad := range []string{
+		`[{"name": "wry", "description": "dry humour", "strength": 2}]`,
+		`[{"name": "wry"}]`,
+		`[{"name": "wry", "description": "a"}, {"name": "wry", "description": "b"}]`,
+	} {
+		os.WriteFile(path, []byte(bad), 0o600)
+		if _, err := LoadPersonalitySeed(path); err == nil {
+			t.Errorf("expected error for %s", bad)
+		}
+	}
+}
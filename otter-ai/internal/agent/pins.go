@@ -0,0 +1,171 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"otter-ai/internal/memory"
+)
+
+// Pinning configuration
+const (
+	MaxPinnedInPrompt = 10
+	MaxPinLength      = 1000
+)
+
+// pinPrefixes are chat prefixes that pin the rest of the message
+var pinPrefixes = []string{"remember this:", "remember that:", "pin:"}
+
+// parsePinCommand returns the content to pin if the message is a pin command
+func parsePinCommand(message string) (string, bool) {
+	trimmed := strings.TrimSpace(message)
+	lower := strings.ToLower(trimmed)
+	for _, prefix := range pinPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return strings.TrimSpace(trimmed[len(prefix):]), true
+		}
+	}
+	return "", false
+}
+
+// parseUnpinCommand returns the memory ID if the message is "unpin <id>"
+func parseUnpinCommand(messageLower string) (string, bool) {
+	fields := strings.Fields(messageLower)
+	if len(fields) == 2 && fields[0] == "unpin" {
+		return fields[1], true
+	}
+	return "", false
+}
+
+// isListPinnedCommand reports whether the message asks for the pinned list
+func isListPinnedCommand(messageLower string) bool {
+	switch strings.TrimRight(messageLower, "?.! ") {
+	case "list pinned", "list pins", "show pinned", "show pins", "what have i pinned":
+		return true
+	}
+	return false
+}
+
+// handlePinCommands handles pin, unpin and list-pinned chat commands.
+// Returns handled=false when the message is not a pin command.
+func (a *Agent) handlePinCommands(ctx context.Context, message, messageLower string) (string, bool) {
+	if content, ok := parsePinCommand(message); ok {
+		if content == "" {
+			return "Tell me what to remember, e.g. \"remember this: my favourite river is the Thames\".", true
+		}
+		record, err := a.PinMemory(ctx, content)
+		if err != nil {
+			return fmt.Sprintf("I couldn't pin that: %v", err), true
+		}
+		return fmt.Sprintf("Pinned. I'll always remember that. (id: %s)", record.ID), true
+	}
+
+	if id, ok := parseUnpinCommand(messageLower); ok {
+		if _, err := a.UnpinMemory(ctx, id); err != nil {
+			return fmt.Sprintf("I couldn't unpin %s: %v", id, err), true
+		}
+		return fmt.Sprintf("Unpinned %s. It's now an ordinary memory.", id), true
+	}
+
+	if isListPinnedCommand(messageLower) {
+		pinned, err := a.ListPinned(ctx)
+		if err != nil {
+			return fmt.Sprintf("I couldn't list pinned memories: %v", err), true
+		}
+		if len(pinned) == 0 {
+			return "Nothing is pinned yet. Say \"remember this: ...\" to pin something.", true
+		}
+		var b strings.Builder
+		b.WriteString("Pinned memories:\n")
+		for _, p := range pinned {
+			b.WriteString(fmt.Sprintf("- %s (id: %s)\n", p.Content, p.ID))
+		}
+		return strings.TrimSpace(b.String()), true
+	}
+
+	return "", false
+}
+
+// PinMemory stores content as a pinned, maximum-importance memory
+func (a *Agent) PinMemory(ctx context.Context, content string) (*memory.MemoryRecord, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, fmt.Errorf("nothing to pin")
+	}
+	if len(content) > MaxPinLength {
+		return nil, fmt.Errorf("pinned content too long (max %d characters)", MaxPinLength)
+	}
+
+	embedding, err := a.embed(ctx, content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate embedding: %w", err)
+	}
+
+	record := &memory.MemoryRecord{
+		Content:   content,
+		Embedding: embedding,
+		Metadata: map[string]interface{}{
+			"content_source": "pin",
+		},
+	}
+	if err := a.memory.Pin(ctx, record); err != nil {
+		return nil, err
+	}
+
+	a.invalidatePinned()
+	return record, nil
+}
+
+// UnpinMemory clears the pin on a memory; the memory itself is kept
+func (a *Agent) UnpinMemory(ctx context.Context, id string) (*memory.MemoryRecord, error) {
+	record, err := a.memory.SetPinned(ctx, id, false)
+	if err != nil {
+		return nil, err
+	}
+	a.invalidatePinned()
+	return record, nil
+}
+
+// ListPinned returns all pinned memories, cached between changes
+func (a *Agent) ListPinned(ctx context.Context) ([]memory.MemoryRecord, error) {
+	a.pinnedMu.Lock()
+	defer a.pinnedMu.Unlock()
+
+	if a.pinnedLoaded {
+		return append([]memory.MemoryRecord{}, a.pinned...), nil
+	}
+
+	pinned, err := a.memory.ListPinned(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	a.pinned = pinned
+	a.pinnedLoaded = true
+	return append([]memory.MemoryRecord{}, pinned...), nil
+}
+
+func (a *Agent) invalidatePinned() {
+	a.pinnedMu.Lock()
+	a.pinnedLoaded = false
+	a.pinned = nil
+	a.pinnedMu.Unlock()
+}
+
+// buildPinnedContext lists pinned facts for the system prompt, as many as
+// fit the budget
+func (a *Agent) buildPinnedContext(ctx context.Context, budget *promptBudget) string {
+	pinned, err := a.ListPinned(ctx)
+	if err != nil || len(pinned) == 0 {
+		return ""
+	}
+	if len(pinned) > MaxPinnedInPrompt {
+		pinned = pinned[:MaxPinnedInPrompt]
+	}
+
+	items := make([]string, len(pinned))
+	for i, p := range pinned {
+		items[i] = sanitizeForPrompt(p.Content)
+	}
+	return budgetedList(budget, "Facts the user pinned (always honor these):", items)
+}
@@ -0,0 +1,88 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"otter-ai/internal/memory"
+)
+
+func TestParsePinCommand(t *testing.T) {
+	cases := map[string]string{
+		"remember this: I take my tea black":      "I take my tea black",
+		"Remember that: deploys are on Fridays":   "deploys are on Fridays",
+		"pin: the wifi password is in the drawer": "the wifi password is in the drawer",
+	}
+	for input, want := range cases {
+		got, ok := parsePinCommand(input)
+		if !ok || got != want {
+			t.Errorf("parsePinCommand(%q) = %q, %v; want %q", input, got, ok, want)
+		}
+	}
+
+	if _, ok := parsePinCommand("can you remember this for me"); ok {
+		t.Error("expected ordinary message not to be a pin command")
+	}
+}
+
+func TestParseUnpinCommand(t *testing.T) {
+	if id, ok := parseUnpinCommand("unpin abc123"); !ok || id != "abc123" {
+		t.Errorf("parseUnpinCommand = %q, %v", id, ok)
+	}
+	if _, ok := parseUnpinCommand("unpin"); ok {
+		t.Error("expected missing id not to match")
+	}
+}
+
+func TestIsListPinnedCommand(t *testing.T) {
+	for _, msg := range []string{"list pinned", "show pins", "what have i pinned?"} {
+		if !isListPinnedCommand(msg) {
+			t.Errorf("expected %q to list pins", msg)
+		}
+	}
+	if isListPinnedCommand("list the rules") {
+		t.Error("unexpected match")
+	}
+}
+
+func TestProcessMessage_PinCommand(t *testing.T) {
+	a := newTestAgent(&mockLLMProvider{completeResp: "should not be called"})
+	resp, err := a.ProcessMessage(context.Background(), "remember this: otters hold hands while sleeping")
+	if err != nil {
+		t.Fatalf("ProcessMessage: %v", err)
+	}
+	if !containsStr(resp, "Pinned") {
+		t.Errorf("unexpected response: %q", resp)
+	}
+}
+
+func TestProcessMessage_PinCommandEmpty(t *testing.T) {
+	a := newTestAgent(&mockLLMProvider{})
+	resp, _ := a.ProcessMessage(context.Background(), "remember this:")
+	if !containsStr(resp, "Tell me what to remember") {
+		t.Errorf("unexpected response: %q", resp)
+	}
+}
+
+func TestBuildPinnedContext(t *testing.T) {
+	a := newTestAgent(&mockLLMProvider{})
+	a.pinnedLoaded = true
+	a.pinned = nil
+	if got := a.buildPinnedContext(context.Background(), nil); got != "" {
+		t.Errorf("expected empty context, got %q", got)
+	}
+
+	a.invalidatePinned()
+	a.pinnedMu.Lock()
+	a.pinnedLoaded = true
+	a.pinned = append(a.pinned, pinnedRecord("likes kelp"))
+	a.pinnedMu.Unlock()
+
+	if got := a.buildPinnedContext(context.Background(), nil); !containsStr(got, "likes kelp") {
+		t.Errorf("expected pinned fact in context, got %q", got)
+	}
+}
+
+func pinnedRecord(content string) memory.MemoryRecord {
+	return memory.MemoryRecord{Content: content, Pinned: true}
+}
@@ -0,0 +1,175 @@
+package agent
+
+import (
+	"context"
+	"sort"
+
+	"otter-ai/internal/governance"
+	"otter-ai/internal/prompts"
+)
+
+// raftPrompt is one raft's section of a prompt
+type raftPrompt struct {
+	RaftID        string
+	Name          string // From the raft's metadata, if named
+	Purpose       string
+	Own           bool // This otter's own raft
+	ActiveMembers int
+	Rules         []*governance.Rule // Ordered by scope
+}
+
+// proposalPrompt is an open proposal as listed in the governance summary
+type proposalPrompt struct {
+	ID         string // Shortened for readability
+	RaftID     string
+	Evict      bool
+	Admit      bool
+	Suspend    bool // Emergency suspension of Rule
+	Reinstate  bool // Re-vote on the suspended Rule
+	Target     string
+	Reason     string
+	Rule       *governance.Rule
+	Impact     *governance.ImpactAnalysis // Nil until the analysis is ready
+	ProposedBy string
+	Yes, No    int
+	Deadline   string
+}
+
+// systemPromptData is what the system prompt is rendered with
+type systemPromptData struct {
+	Context    string          // Session, memory, capability and personality context
+	Governance bool            // Whether rules are included
+	Rafts      []raftPrompt    // Rafts this otter belongs to, by ID
+	Channel    *ChannelProfile // Profile of the channel the message arrived through
+}
+
+// governancePromptData is what the governance summary is rendered with
+type governancePromptData struct {
+	Rafts     []raftPrompt
+	Proposals []proposalPrompt
+}
+
+// SetPrompts replaces the prompt templates the agent renders, as when
+// they are reloaded; nil renders the built-in prompts
+func (a *Agent) SetPrompts(registry *prompts.Registry) {
+	a.prompts.Store(registry)
+}
+
+// renderPrompt renders a prompt template. A failing override is logged and
+// the built-in template used instead.
+func (a *Agent) renderPrompt(ctx context.Context, name string, data interface{}) string {
+	prompt, err := a.prompts.Load().Render(name, data)
+	if err != nil {
+		a.log().ErrorContext(ctx, "failed to render prompt template", "template", name, "error", err)
+	}
+	return prompt
+}
+
+// buildSystemPrompt renders the chat system prompt around the turn's
+// context. Rules are left out when governance is unavailable or switched
+// off for the turn, and limited to opts.Scope when set.
+func (a *Agent) buildSystemPrompt(ctx context.Context, conversationContext string, opts ContextOptions) string {
+	data := systemPromptData{Context: conversationContext, Channel: a.channelProfile(ctx)}
+	if a.governance != nil && !opts.NoGovernance {
+		data.Governance = true
+		data.Rafts = a.raftPrompts(opts.Scope)
+	}
+	return a.renderPrompt(ctx, prompts.System, data)
+}
+
+// raftPrompts groups the active rules governing scope by raft. Every live
+// raft this otter belongs to is listed with its active member count, even
+// when none of its rules apply.
+func (a *Agent) raftPrompts(scope string) []raftPrompt {
+	byRaft := make(map[string]*raftPrompt)
+	for _, summary := range a.governance.RaftSummaries() {
+		if summary.Archived {
+			continue
+		}
+		rp := &raftPrompt{
+			RaftID:  summary.RaftID,
+			Name:    summary.Metadata.Name,
+			Purpose: summary.Metadata.Purpose,
+			Own:     summary.RaftID == a.governance.GetID(),
+		}
+		for _, member := range summary.Members {
+			if member.State == governance.StateActive {
+				rp.ActiveMembers++
+			}
+		}
+		byRaft[summary.RaftID] = rp
+	}
+	for _, rule := range a.governance.RulesForScope(scope) {
+		rp, ok := byRaft[rule.RaftID]
+		if !ok {
+			rp = &raftPrompt{RaftID: rule.RaftID, Own: rule.RaftID == a.governance.GetID()}
+			byRaft[rule.RaftID] = rp
+		}
+		rp.Rules = append(rp.Rules, rule)
+	}
+
+	rafts := make([]raftPrompt, 0, len(byRaft))
+	for _, rp := range byRaft {
+		sort.Slice(rp.Rules, func(i, j int) bool { return rp.Rules[i].Scope < rp.Rules[j].Scope })
+		rafts = append(rafts, *rp)
+	}
+	sort.Slice(rafts, func(i, j int) bool { return rafts[i].RaftID < rafts[j].RaftID })
+	return rafts
+}
+
+// listedProposals orders open proposals as they are numbered in the
+// governance summary, oldest first. A non-empty scope keeps only rule
+// proposals covering it.
+func listedProposals(proposals []*governance.Proposal, scope string) []*governance.Proposal {
+	var listed []*governance.Proposal
+	for _, p := range proposals {
+		if scope != "" && (p.Rule == nil || !governance.ScopeMatches(p.Rule.Scope, scope)) {
+			continue
+		}
+		listed = append(listed, p)
+	}
+	sort.Slice(listed, func(i, j int) bool {
+		if !listed[i].ProposedAt.Equal(listed[j].ProposedAt) {
+			return listed[i].ProposedAt.Before(listed[j].ProposedAt)
+		}
+		return listed[i].ProposalID < listed[j].ProposalID
+	})
+	return listed
+}
+
+// proposalPrompts describes open proposals for the governance summary, in
+// the order of listedProposals
+func proposalPrompts(proposals []*governance.Proposal, scope string) []proposalPrompt {
+	var out []proposalPrompt
+	for _, p := range listedProposals(proposals, scope) {
+		pp := proposalPrompt{
+			ID:         p.ProposalID,
+			RaftID:     p.RaftID,
+			Evict:      p.Kind == governance.ProposalKindEviction,
+			Admit:      p.Kind == governance.ProposalKindAdmission,
+			Suspend:    p.Kind == governance.ProposalKindSuspension,
+			Reinstate:  p.Kind == governance.ProposalKindReinstatement,
+			Target:     p.TargetMemberID,
+			Reason:     p.Reason,
+			Rule:       p.Rule,
+			ProposedBy: p.ProposedBy,
+			Deadline:   formatDeadline(p.Deadline),
+		}
+		if p.Impact != nil && p.Impact.Status == governance.ImpactReady {
+			pp.Impact = p.Impact
+		}
+		if len(pp.ID) > 8 {
+			pp.ID = pp.ID[:8]
+		}
+		for _, vote := range p.Votes {
+			switch vote {
+			case governance.VoteYes:
+				pp.Yes++
+			case governance.VoteNo:
+				pp.No++
+			}
+		}
+		out = append(out, pp)
+	}
+	return out
+}
@@ -0,0 +1,96 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"otter-ai/internal/governance"
+)
+
+func TestRenderPrompt_RulesByRaft(t *testing.T) {
+	a := newTestAgent(nil)
+	out := a.renderPrompt(context.Background(), "system", systemPromptData{
+		Context:    "Recent conversation:\nUser: hi\n\n",
+		Governance: true,
+		Rafts: []raftPrompt{
+			{RaftID: "kelp-forest", ActiveMembers: 1, Rules: []*governance.Rule{{Scope: "general", Body: "be kind"}}},
+			{RaftID: "otter-1", Own: true, ActiveMembers: 3, Rules: []*governance.Rule{
+				{Scope: "general", Body: "be brief"},
+				{Scope: "secrets", Body: "never share passwords"},
+			}},
+			{RaftID: "tide-pool", ActiveMembers: 2},
+		},
+	})
+
+	for _, want := range []string{
+		"User: hi",
+		"  Raft kelp-forest (1 active member):\n    • be kind (scope: general)\n",
+		"  Raft otter-1 (own raft, 3 active members):\n    • be brief (scope: general)\n    • never share passwords (scope: secrets)\n",
+		"  Raft tide-pool (2 active members):\n    • No rules in effect\n",
+		"say which raft it comes from",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("system prompt missing %q:\n%s", want, out)
+		}
+	}
+
+	out = a.renderPrompt(context.Background(), "system", systemPromptData{Context: "ctx\n"})
+	if strings.Contains(out, "ACTIVE RULES") || strings.Contains(out, "which raft") {
+		t.Errorf("rules should be left out without governance:\n%s", out)
+	}
+	if !strings.Contains(out, "ctx\nCRITICAL INSTRUCTIONS:") {
+		t.Errorf("unexpected layout:\n%s", out)
+	}
+}
+
+func TestRenderPrompt_Governance(t *testing.T) {
+	a := newTestAgent(nil)
+	out := a.renderPrompt(context.Background(), "governance", governancePromptData{})
+	if out != "ACTIVE RULES: None currently in effect.\n\nOPEN PROPOSALS: None currently open.\n" {
+		t.Errorf("empty governance = %q", out)
+	}
+
+	out = a.renderPrompt(context.Background(), "governance", governancePromptData{
+		Proposals: proposalPrompts([]*governance.Proposal{
+			{ProposalID: "0123456789", RaftID: "otter-1", Rule: &governance.Rule{Scope: "general", Body: "be kind"}, ProposedBy: "otter-2",
+				Votes: map[string]governance.VoteType{"otter-1": governance.VoteYes, "otter-2": governance.VoteNo}, Deadline: time.Now().Add(time.Hour)},
+			{ProposalID: "evict", RaftID: "otter-1", Kind: governance.ProposalKindEviction, TargetMemberID: "otter-3", ProposedBy: "otter-1"},
+			{ProposalID: "impact", RaftID: "otter-1", Rule: &governance.Rule{Scope: "tone", Body: "be terse"}, ProposedBy: "otter-1",
+				Impact: &governance.ImpactAnalysis{Status: governance.ImpactReady, Summary: "Replies get shorter",
+					Conflicts: []governance.ImpactConflict{{Scope: "tone", Body: "explain fully", Reason: "opposite lengths"}},
+					Examples:  []string{"One-line answers"}}},
+			{ProposalID: "pending", RaftID: "otter-1", Rule: &governance.Rule{Scope: "tone", Body: "be loud"}, ProposedBy: "otter-1",
+				Impact: &governance.ImpactAnalysis{Status: governance.ImpactPending, Summary: "stale"}},
+		}, ""),
+	})
+	for _, want := range []string{
+		"  1. Proposal ID: 01234567 (raft otter-1)\n     Text: be kind\n     Scope: general\n     Proposed by: otter-2\n     Votes: 1 yes, 1 no\n",
+		"  2. Proposal ID: evict (raft otter-1)\n     Evict member: otter-3\n     Proposed by: otter-1\n",
+		"Voting closes: no deadline",
+		"     Scope: tone\n     Impact: Replies get shorter\n     Conflicts with [tone] explain fully: opposite lengths\n     Example: One-line answers\n     Proposed by: otter-1\n",
+		"     Text: be loud\n     Scope: tone\n     Proposed by: otter-1\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("governance summary missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestBuildSystemPrompt_Governance(t *testing.T) {
+	a := governedAgent(t, &mockLLMProvider{completeResp: "ok"}, "secrets", "never share passwords")
+	ctx := context.Background()
+
+	out := a.buildSystemPrompt(ctx, "", ContextOptions{})
+	if !strings.Contains(out, "Raft otter-1 (own raft, 1 active member):\n    • never share passwords (scope: secrets)") {
+		t.Errorf("system prompt should attribute the rule to its raft:\n%s", out)
+	}
+	out = a.buildSystemPrompt(ctx, "", ContextOptions{Scope: "general"})
+	if strings.Contains(out, "never share passwords") || !strings.Contains(out, "No rules in effect") {
+		t.Errorf("rules outside the scope should be left out:\n%s", out)
+	}
+	if out := a.buildSystemPrompt(ctx, "", ContextOptions{NoGovernance: true}); strings.Contains(out, "ACTIVE RULES") {
+		t.Errorf("rules should be left out with governance off:\n%s", out)
+	}
+}
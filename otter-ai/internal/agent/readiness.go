@@ -0,0 +1,160 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"otter-ai/internal/llm"
+)
+
+// ReadinessTimeout bounds each component's readiness check
+const ReadinessTimeout = 5 * time.Second
+
+// Component states reported by Readiness
+const (
+	ComponentOK       = "ok"
+	ComponentDegraded = "degraded" // Working, with some function lost
+	ComponentDown     = "down"
+	ComponentDisabled = "disabled" // Not configured
+)
+
+// ComponentHealth is the state of one component the otter depends on
+type ComponentHealth struct {
+	Status string `json:"status"`
+	// Critical components being down makes the otter unready; others only
+	// degrade it
+	Critical  bool              `json:"critical"`
+	Error     string            `json:"error,omitempty"`
+	Details   map[string]string `json:"details,omitempty"`
+	LatencyMs int64             `json:"latency_ms"`
+}
+
+// Readiness reports whether the otter can serve requests, by component
+type Readiness struct {
+	Ready      bool                       `json:"ready"`
+	Components map[string]ComponentHealth `json:"components"`
+}
+
+// Readiness checks the database, the LLM and embedding providers,
+// governance and plugins concurrently. The otter is ready while no
+// critical component is down; failing plugins only degrade it.
+func (a *Agent) Readiness(ctx context.Context) Readiness {
+	checks := map[string]func(context.Context) ComponentHealth{
+		"database":   a.checkDatabase,
+		"llm":        a.checkLLM,
+		"embedding":  a.checkEmbedding,
+		"governance": a.checkGovernance,
+		"plugins":    a.checkPlugins,
+	}
+
+	readiness := Readiness{Ready: true, Components: make(map[string]ComponentHealth, len(checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, ReadinessTimeout)
+			defer cancel()
+			started := time.Now()
+			health := check(ctx)
+			health.LatencyMs = time.Since(started).Milliseconds()
+
+			mu.Lock()
+			defer mu.Unlock()
+			readiness.Components[name] = health
+			if health.Critical && health.Status == ComponentDown {
+				readiness.Ready = false
+			}
+		}()
+	}
+	wg.Wait()
+	return readiness
+}
+
+// componentResult turns a check's error into a component's health
+func componentResult(critical bool, err error) ComponentHealth {
+	if err != nil {
+		return ComponentHealth{Status: ComponentDown, Critical: critical, Error: err.Error()}
+	}
+	return ComponentHealth{Status: ComponentOK, Critical: critical}
+}
+
+func (a *Agent) checkDatabase(ctx context.Context) ComponentHealth {
+	if a.memory == nil {
+		return ComponentHealth{Status: ComponentDown, Critical: true, Error: "no memory store"}
+	}
+	return componentResult(true, a.memory.Ping(ctx))
+}
+
+// checkLLM pings the completion provider. Providers that can't be pinged
+// are judged by their circuit breaker.
+func (a *Agent) checkLLM(ctx context.Context) ComponentHealth {
+	if a.llm == nil {
+		return ComponentHealth{Status: ComponentDown, Critical: true, Error: "no LLM provider"}
+	}
+	health := ComponentHealth{Status: ComponentOK, Critical: true, Details: map[string]string{"provider": a.llm.Name()}}
+	circuit := ""
+	if reporter, ok := a.llm.(llm.ResilienceReporter); ok {
+		circuit = reporter.ResilienceStats().Circuit
+		health.Details["circuit"] = circuit
+	}
+
+	err := llm.Ping(ctx, a.llm)
+	switch {
+	case errors.Is(err, llm.ErrPingUnsupported):
+		if circuit == llm.CircuitOpen {
+			health.Status, health.Error = ComponentDown, "circuit breaker open"
+		}
+	case err != nil:
+		health.Status, health.Error = ComponentDown, err.Error()
+	case circuit == llm.CircuitOpen:
+		// Reachable again, but requests fail fast until the breaker closes
+		health.Status = ComponentDegraded
+	}
+	return health
+}
+
+// checkEmbedding judges a separate embedding provider by its circuit
+// breaker; without one, embeddings come from the LLM provider
+func (a *Agent) checkEmbedding(ctx context.Context) ComponentHealth {
+	if a.embedder == nil {
+		return ComponentHealth{Status: ComponentDisabled, Details: map[string]string{"provider": "llm"}}
+	}
+	health := ComponentHealth{Status: ComponentOK, Critical: true, Details: map[string]string{"provider": a.embedder.Name()}}
+	if reporter, ok := a.embedder.(llm.ResilienceReporter); ok {
+		circuit := reporter.ResilienceStats().Circuit
+		health.Details["circuit"] = circuit
+		if circuit == llm.CircuitOpen {
+			health.Status, health.Error = ComponentDown, "circuit breaker open"
+		}
+	}
+	return health
+}
+
+func (a *Agent) checkGovernance(ctx context.Context) ComponentHealth {
+	if a.governance == nil {
+		return ComponentHealth{Status: ComponentDisabled}
+	}
+	return componentResult(true, a.governance.Check(ctx))
+}
+
+// checkPlugins pings the loaded plugins; any failing degrades the
+// component, as the supervisor restarts them
+func (a *Agent) checkPlugins(ctx context.Context) ComponentHealth {
+	if a.plugins == nil {
+		return ComponentHealth{Status: ComponentDisabled}
+	}
+	health := ComponentHealth{Status: ComponentOK, Details: make(map[string]string)}
+	for name, err := range a.plugins.Health(ctx) {
+		if err != nil {
+			health.Status = ComponentDegraded
+			health.Details[name] = err.Error()
+		} else {
+			health.Details[name] = ComponentOK
+		}
+	}
+	return health
+}
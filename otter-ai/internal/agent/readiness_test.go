@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"otter-ai/internal/llm"
+)
+
+// pingableLLM is a mock provider whose upstream can be checked
+type pingableLLM struct {
+	mockLLMProvider
+	pingErr error
+}
+
+func (m *pingableLLM) Ping(_ context.Context) error { return m.pingErr }
+
+func TestReadiness(t *testing.T) {
+	a := newTestAgent(&mockLLMProvider{})
+	r := a.Readiness(context.Background())
+	if !r.Ready {
+		t.Errorf("expected ready, got %+v", r.Components)
+	}
+	for name, want := range map[string]string{
+		"database":   ComponentOK,
+		"llm":        ComponentOK,
+		"embedding":  ComponentDisabled,
+		"governance": ComponentDisabled,
+		"plugins":    ComponentDisabled,
+	} {
+		if got := r.Components[name].Status; got != want {
+			t.Errorf("%s = %q; want %q", name, got, want)
+		}
+	}
+}
+
+func TestReadiness_LLMDown(t *testing.T) {
+	a := newTestAgent(&pingableLLM{pingErr: errors.New("connection refused")})
+	r := a.Readiness(context.Background())
+	if r.Ready {
+		t.Error("expected not ready while the LLM is unreachable")
+	}
+	if c := r.Components["llm"]; c.Status != ComponentDown || c.Error != "connection refused" {
+		t.Errorf("llm = %+v", c)
+	}
+}
+
+func TestReadiness_CircuitOpen(t *testing.T) {
+	provider := llm.WithResilience(&mockLLMProvider{completeErr: &llm.StatusError{StatusCode: 503}},
+		llm.ResilienceConfig{BreakerThreshold: 1, BreakerCooldown: time.Hour})
+	provider.Complete(context.Background(), &llm.CompletionRequest{Prompt: "hi"})
+	a := newTestAgent(provider)
+
+	r := a.Readiness(context.Background())
+	if c := r.Components["llm"]; c.Status != ComponentDown || c.Details["circuit"] != llm.CircuitOpen {
+		t.Errorf("llm = %+v", c)
+	}
+}
@@ -0,0 +1,43 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"otter-ai/internal/memory"
+)
+
+// RetentionTimeout bounds one memory retention run
+const RetentionTimeout = 10 * time.Minute
+
+// startRetentionLoop periodically applies the memory layer's retention policy
+func (a *Agent) startRetentionLoop(interval time.Duration) {
+	a.startPeriodicJob(interval, RetentionTimeout, a.unlessReadOnly(func(ctx context.Context) {
+		result, err := a.memory.RunRetention(ctx)
+		if err != nil {
+			a.log().Warn("memory retention stopped", "error", err)
+		}
+		if result != nil && result.Decayed+result.Reinforced+result.Forgotten > 0 {
+			a.log().Info("memory retention applied",
+				"decayed", result.Decayed, "reinforced", result.Reinforced, "forgotten", result.Forgotten)
+		}
+	}))
+}
+
+// startPurgeLoop periodically removes deleted memories and superseded
+// versions once they are older than the recovery window
+func (a *Agent) startPurgeLoop(interval, window time.Duration) {
+	a.startPeriodicJob(interval, RetentionTimeout, a.unlessReadOnly(func(ctx context.Context) {
+		result, err := a.memory.PurgeDeleted(ctx, window)
+		if errors.Is(err, memory.ErrVersioningUnsupported) {
+			return // Deletes are already final
+		}
+		if err != nil {
+			a.log().Warn("memory purge stopped", "error", err)
+		}
+		if result.Records+result.Versions > 0 {
+			a.log().Info("memory purge removed old records", "memories", result.Records, "versions", result.Versions)
+		}
+	}))
+}
@@ -0,0 +1,238 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"otter-ai/internal/llm"
+	"otter-ai/internal/memory"
+	"otter-ai/internal/prompts"
+	"otter-ai/internal/vectordb"
+)
+
+// RerankMode decides how retrieved memories are re-ranked
+type RerankMode string
+
+const (
+	// RerankOff keeps the search ranking
+	RerankOff RerankMode = "off"
+	// RerankLLM asks the LLM which memories help answer the query, dropping
+	// the others
+	RerankLLM RerankMode = "llm"
+	// RerankCrossEncoder orders memories by a cross-encoder's scores
+	RerankCrossEncoder RerankMode = "cross-encoder"
+)
+
+// DefaultRetrievalCandidates is how many memories are searched before
+// filtering and re-ranking when the configuration doesn't say
+const DefaultRetrievalCandidates = 20
+
+// RetrievalConfig tunes which searched memories reach the prompt
+type RetrievalConfig struct {
+	// MinScore is the least similarity to the query a memory is kept with;
+	// zero keeps every one
+	MinScore float64
+	// DedupeSimilarity is the similarity above which a memory repeats one
+	// ranked higher and is left out; zero keeps near duplicates
+	DedupeSimilarity float64
+	// Candidates is how many memories are searched before filtering and
+	// re-ranking; zero uses DefaultRetrievalCandidates
+	Candidates int
+	Rerank     RerankMode   // Empty keeps the search ranking
+	Reranker   llm.Reranker // Scores memories for RerankCrossEncoder
+	// FactBoost is added to the score of extracted facts so they rank
+	// above the transcripts they came from
+	FactBoost float64
+}
+
+// retrieveMemories searches long-term memories for a query and returns the
+// limit most relevant: candidates below the score cutoff are dropped, the
+// rest re-ranked and near duplicates of better ranked ones left out. A
+// failed re-ranking keeps the search ranking.
+func (a *Agent) retrieveMemories(ctx context.Context, query string, embedding []float32, limit int, filter memory.SearchFilter) ([]memory.MemoryRecord, error) {
+	cfg := a.retrieval
+	candidates := cfg.Candidates
+	if candidates <= 0 {
+		candidates = DefaultRetrievalCandidates
+	}
+	candidates = max(candidates, limit)
+	memories, err := a.memory.HybridSearch(ctx, query, embedding, memory.MemoryTypeLongTerm, candidates, filter)
+	if err != nil {
+		return nil, err
+	}
+	found := len(memories)
+
+	memories = preferFacts(memories, cfg.FactBoost)
+	relevant := memories[:0]
+	for _, mem := range memories {
+		if mem.Score >= cfg.MinScore {
+			relevant = append(relevant, mem)
+		}
+	}
+	memories = relevant
+
+	if len(memories) > 1 {
+		reranked, err := a.rerankMemories(ctx, query, memories)
+		if err != nil {
+			a.log().WarnContext(ctx, "failed to re-rank memories", "mode", cfg.Rerank, "error", err)
+		} else {
+			memories = reranked
+		}
+	}
+
+	memories = dedupeMemories(memories, cfg.DedupeSimilarity)
+	if len(memories) > limit {
+		memories = memories[:limit]
+	}
+	a.log().DebugContext(ctx, "retrieved memories", "candidates", found, "kept", len(memories), "rerank", cfg.Rerank)
+	return memories, nil
+}
+
+// preferFacts adds boost to the scores of extracted facts, reorders the
+// memories by score and leaves out interactions a fact was extracted from,
+// since the fact says what mattered in them
+func preferFacts(memories []memory.MemoryRecord, boost float64) []memory.MemoryRecord {
+	sources := make(map[string]bool)
+	for i := range memories {
+		if memories[i].Metadata["content_source"] != memory.SourceExtraction {
+			continue
+		}
+		memories[i].Score += boost
+		if source, ok := memories[i].Metadata["source_memory_id"].(string); ok {
+			sources[source] = true
+		}
+	}
+	if boost == 0 && len(sources) == 0 {
+		return memories
+	}
+	sort.SliceStable(memories, func(i, j int) bool { return memories[i].Score > memories[j].Score })
+
+	kept := memories[:0]
+	for _, mem := range memories {
+		if !sources[mem.ID] {
+			kept = append(kept, mem)
+		}
+	}
+	return kept
+}
+
+// rerankMemories reorders memories by relevance to the query as the
+// configured mode judges it
+func (a *Agent) rerankMemories(ctx context.Context, query string, memories []memory.MemoryRecord) ([]memory.MemoryRecord, error) {
+	switch a.retrieval.Rerank {
+	case RerankLLM:
+		return a.llmRerank(ctx, query, memories)
+	case RerankCrossEncoder:
+		if a.retrieval.Reranker == nil {
+			return nil, fmt.Errorf("no cross-encoder configured")
+		}
+		texts := make([]string, len(memories))
+		for i, mem := range memories {
+			texts[i] = mem.Content
+		}
+		scores, err := a.retrieval.Reranker.Rerank(ctx, query, texts)
+		if err != nil {
+			return nil, err
+		}
+		order := make([]int, len(memories))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool { return scores[order[i]] > scores[order[j]] })
+		reranked := make([]memory.MemoryRecord, len(memories))
+		for i, index := range order {
+			reranked[i] = memories[index]
+		}
+		return reranked, nil
+	}
+	return memories, nil
+}
+
+// llmRerank asks the LLM which memories help answer the query, best first.
+// Memories it leaves out are dropped.
+func (a *Agent) llmRerank(ctx context.Context, query string, memories []memory.MemoryRecord) ([]memory.MemoryRecord, error) {
+	previews := make([]string, len(memories))
+	for i, mem := range memories {
+		content := strings.Join(strings.Fields(mem.Content), " ")
+		if len(content) > MaxMemoryPreviewLength {
+			content = content[:MaxMemoryPreviewLength] + "..."
+		}
+		previews[i] = sanitizeForPrompt(content)
+	}
+	data := struct {
+		Query    string
+		Memories []string
+	}{query, previews}
+	resp, err := a.llm.Complete(ctx, &llm.CompletionRequest{
+		Prompt:  a.renderPrompt(ctx, prompts.MemoryRerank, data),
+		Profile: llm.ProfileClassification,
+	})
+	if err != nil {
+		return nil, err
+	}
+	order, err := parseRerankAnswer(resp.Text, len(memories))
+	if err != nil {
+		return nil, err
+	}
+	reranked := make([]memory.MemoryRecord, 0, len(order))
+	for _, index := range order {
+		reranked = append(reranked, memories[index])
+	}
+	return reranked, nil
+}
+
+// parseRerankAnswer reads the memory numbers of a re-ranking answer as
+// indexes, skipping repeats. "none" is no memory; an answer naming no
+// valid number is an error.
+func parseRerankAnswer(answer string, n int) ([]int, error) {
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if strings.HasPrefix(strings.Trim(answer, `."'*`), "none") {
+		return []int{}, nil
+	}
+	var order []int
+	seen := make(map[int]bool)
+	for _, field := range strings.FieldsFunc(answer, func(r rune) bool { return r < '0' || r > '9' }) {
+		number, err := strconv.Atoi(field)
+		if err != nil || number < 1 || number > n || seen[number] {
+			continue
+		}
+		seen[number] = true
+		order = append(order, number-1)
+	}
+	if len(order) == 0 {
+		return nil, fmt.Errorf("unexpected re-ranking answer %q", answer)
+	}
+	return order, nil
+}
+
+// dedupeMemories leaves out memories whose embedding is at least threshold
+// similar to one earlier in the list, or whose content is the same. A zero
+// threshold only leaves out identical content.
+func dedupeMemories(memories []memory.MemoryRecord, threshold float64) []memory.MemoryRecord {
+	kept := make([]memory.MemoryRecord, 0, len(memories))
+	contents := make(map[string]bool, len(memories))
+	for _, mem := range memories {
+		content := strings.ToLower(strings.Join(strings.Fields(mem.Content), " "))
+		if contents[content] {
+			continue
+		}
+		duplicate := false
+		if threshold > 0 && len(mem.Embedding) > 0 {
+			for _, other := range kept {
+				if vectordb.CosineSimilarity(mem.Embedding, other.Embedding) >= threshold {
+					duplicate = true
+					break
+				}
+			}
+		}
+		if duplicate {
+			continue
+		}
+		contents[content] = true
+		kept = append(kept, mem)
+	}
+	return kept
+}
@@ -0,0 +1,151 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"otter-ai/internal/memory"
+)
+
+// fixedReranker scores texts by a map, or fails
+type fixedReranker struct {
+	scores map[string]float64
+	err    error
+}
+
+func (r *fixedReranker) Rerank(_ context.Context, _ string, texts []string) ([]float64, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	scores := make([]float64, len(texts))
+	for i, text := range texts {
+		scores[i] = r.scores[text]
+	}
+	return scores, nil
+}
+
+// newTestRetrievalAgent stores memories at increasing angles from the
+// query vector {1, 0}: kelp is closest, then kelp again, then rocks and
+// finally weather, which is unrelated
+func newTestRetrievalAgent(t *testing.T, llmProv *mockLLMProvider) *Agent {
+	t.Helper()
+	a := newTestConsolidationAgent(t, llmProv)
+	storeTestMemory(t, a, "The user likes kelp", []float32{1, 0.05}, 0, 0.5, false)
+	storeTestMemory(t, a, "the user likes  kelp", []float32{1, 0.2}, 0, 0.5, false)
+	storeTestMemory(t, a, "Otters crack shells on rocks", []float32{1, 0.8}, 0, 0.5, false)
+	storeTestMemory(t, a, "It rained on Tuesday", []float32{0.05, 1}, 0, 0.5, false)
+	return a
+}
+
+func retrievedContents(memories []memory.MemoryRecord) []string {
+	contents := make([]string, len(memories))
+	for i, mem := range memories {
+		contents[i] = mem.Content
+	}
+	return contents
+}
+
+func TestRetrieveMemories_CutoffAndDedupe(t *testing.T) {
+	a := newTestRetrievalAgent(t, nil)
+	a.retrieval = RetrievalConfig{MinScore: 0.3}
+
+	memories, err := a.retrieveMemories(context.Background(), "", []float32{1, 0}, 5, memory.SearchFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Join(retrievedContents(memories), "|")
+	if got != "The user likes kelp|Otters crack shells on rocks" {
+		t.Errorf("retrieved %q, want the unrelated memory cut and the repeated one left out", got)
+	}
+	if memories[0].Score < 0.99 {
+		t.Errorf("score = %f, want the cosine similarity", memories[0].Score)
+	}
+}
+
+func TestRetrieveMemories_LLMRerank(t *testing.T) {
+	a := newTestRetrievalAgent(t, &mockLLMProvider{completeResp: "3, 1"})
+	a.retrieval = RetrievalConfig{MinScore: 0.3, Rerank: RerankLLM}
+
+	memories, err := a.retrieveMemories(context.Background(), "favourite food", []float32{1, 0}, 5, memory.SearchFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(retrievedContents(memories), "|"); got != "Otters crack shells on rocks|The user likes kelp" {
+		t.Errorf("retrieved %q, want the LLM's order", got)
+	}
+
+	// An answer naming no memory keeps the search ranking
+	a.llm = &mockLLMProvider{completeResp: "I'm not sure"}
+	memories, _ = a.retrieveMemories(context.Background(), "favourite food", []float32{1, 0}, 5, memory.SearchFilter{})
+	if len(memories) != 2 || memories[0].Content != "The user likes kelp" {
+		t.Errorf("retrieved %q after a failed re-ranking", retrievedContents(memories))
+	}
+
+	a.llm = &mockLLMProvider{completeResp: "none"}
+	if memories, _ := a.retrieveMemories(context.Background(), "favourite food", []float32{1, 0}, 5, memory.SearchFilter{}); len(memories) != 0 {
+		t.Errorf("retrieved %q when the LLM found none helpful", retrievedContents(memories))
+	}
+}
+
+func TestRetrieveMemories_CrossEncoder(t *testing.T) {
+	a := newTestRetrievalAgent(t, nil)
+	reranker := &fixedReranker{scores: map[string]float64{"Otters crack shells on rocks": 0.9, "The user likes kelp": 0.1}}
+	a.retrieval = RetrievalConfig{MinScore: 0.3, DedupeSimilarity: 0.95, Rerank: RerankCrossEncoder, Reranker: reranker}
+
+	memories, err := a.retrieveMemories(context.Background(), "favourite food", []float32{1, 0}, 1, memory.SearchFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(memories) != 1 || memories[0].Content != "Otters crack shells on rocks" {
+		t.Errorf("retrieved %q, want the cross-encoder's best", retrievedContents(memories))
+	}
+
+	reranker.err = errors.New("down")
+	memories, _ = a.retrieveMemories(context.Background(), "favourite food", []float32{1, 0}, 1, memory.SearchFilter{})
+	if len(memories) != 1 || memories[0].Content != "The user likes kelp" {
+		t.Errorf("retrieved %q, want the search ranking when re-ranking fails", retrievedContents(memories))
+	}
+}
+
+func TestParseRerankAnswer(t *testing.T) {
+	cases := []struct {
+		answer string
+		want   []int
+		err    bool
+	}{
+		{"3, 1", []int{2, 0}, false},
+		{"Memories 2 and 2, then 9 and 1.", []int{1, 0}, false},
+		{"None.", []int{}, false},
+		{"no idea", nil, true},
+	}
+	for _, c := range cases {
+		got, err := parseRerankAnswer(c.answer, 3)
+		if (err != nil) != c.err || len(got) != len(c.want) {
+			t.Errorf("parseRerankAnswer(%q) = %v, %v", c.answer, got, err)
+			continue
+		}
+		for i := range got {
+			if got[i] != c.want[i] {
+				t.Errorf("parseRerankAnswer(%q) = %v, want %v", c.answer, got, c.want)
+				break
+			}
+		}
+	}
+}
+
+func TestDedupeMemories(t *testing.T) {
+	memories := []memory.MemoryRecord{
+		{Content: "kelp", Embedding: []float32{1, 0}},
+		{Content: "Kelp ", Embedding: []float32{0, 1}},
+		{Content: "seaweed", Embedding: []float32{1, 0.01}},
+		{Content: "rocks", Embedding: []float32{0, 1}},
+	}
+	if got := len(dedupeMemories(memories, 0)); got != 3 {
+		t.Errorf("without a threshold kept %d, want identical content left out only", got)
+	}
+	if got := strings.Join(retrievedContents(dedupeMemories(memories, 0.99)), "|"); got != "kelp|rocks" {
+		t.Errorf("kept %q", got)
+	}
+}
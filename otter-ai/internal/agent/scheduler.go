@@ -0,0 +1,337 @@
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"otter-ai/internal/errs"
+	"otter-ai/internal/events"
+	"otter-ai/internal/governance"
+	"otter-ai/internal/memory"
+	"otter-ai/internal/plugins"
+)
+
+// Scheduled message kinds
+const (
+	ScheduleReminder = "reminder" // Free text, sent as given
+	ScheduleDigest   = "digest"   // The day's musings, written when due and rescheduled daily
+	ScheduleDeadline = "deadline" // A warning that an open proposal is about to close
+)
+
+// Scheduler configuration
+const (
+	DefaultScheduleMaxAttempts = 5
+	DefaultDeadlineWarning     = 24 * time.Hour
+	ScheduleTimeout            = time.Minute        // Bounds one delivery run
+	ScheduleRetention          = 7 * 24 * time.Hour // Finished messages are kept this long
+	MaxScheduledContentLength  = 2000
+	MaxScheduleAhead           = 366 * 24 * time.Hour
+	DigestMusings              = 10 // Most musings in one digest
+)
+
+// ErrInvalidSchedule is returned for messages that can't be scheduled
+var ErrInvalidSchedule = errs.New(errs.ErrInvalid, "invalid scheduled message")
+
+// ErrScheduleNotPending is returned when canceling a message that was
+// already sent, failed or canceled
+var ErrScheduleNotPending = errs.New(errs.ErrConflict, "scheduled message is no longer pending")
+
+// SchedulerConfig tunes delivery of scheduled outbound messages
+type SchedulerConfig struct {
+	Interval    time.Duration // How often due messages are delivered; zero disables delivery
+	MaxAttempts int           // Delivery attempts before a message fails; zero uses the default
+	// DigestTarget is the "platform:channel_id" a daily digest of musings
+	// is sent to; empty disables the digest
+	DigestTarget string
+	DigestAt     time.Duration // Local time of day the digest is sent, as an offset from midnight
+	// DeadlineTarget is the "platform:channel_id" warnings about closing
+	// proposals are sent to; empty disables them
+	DeadlineTarget  string
+	DeadlineWarning time.Duration // How long before a deadline the warning is sent; zero uses the default
+}
+
+// withDefaults fills unset fields other than Interval and the targets with
+// their defaults
+func (c SchedulerConfig) withDefaults() SchedulerConfig {
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = DefaultScheduleMaxAttempts
+	}
+	if c.DeadlineWarning <= 0 {
+		c.DeadlineWarning = DefaultDeadlineWarning
+	}
+	return c
+}
+
+// splitTarget splits a "platform:channel_id" target
+func splitTarget(target string) (string, string, bool) {
+	platform, channel, ok := strings.Cut(target, ":")
+	return platform, channel, ok && platform != "" && channel != ""
+}
+
+// scheduleID generates an ID for a scheduled message
+func scheduleID(msg *memory.ScheduledMessage) string {
+	data := fmt.Sprintf("%s|%s|%s|%s|%d", msg.Kind, msg.Platform, msg.ChannelID, msg.Content, time.Now().UnixNano())
+	hash := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(hash[:16])
+}
+
+// ScheduleMessage queues a message for delivery through a plugin at
+// msg.DeliverAt. It needs a platform and a channel or user; reminders also
+// need content.
+func (a *Agent) ScheduleMessage(ctx context.Context, msg *memory.ScheduledMessage) (*memory.ScheduledMessage, error) {
+	if msg.Kind == "" {
+		msg.Kind = ScheduleReminder
+	}
+	switch msg.Kind {
+	case ScheduleReminder, ScheduleDigest, ScheduleDeadline:
+	default:
+		return nil, fmt.Errorf("%w: unknown kind %q", ErrInvalidSchedule, msg.Kind)
+	}
+	msg.Content = strings.TrimSpace(msg.Content)
+	switch {
+	case msg.Platform == "" || (msg.ChannelID == "" && msg.UserID == ""):
+		return nil, fmt.Errorf("%w: a platform and a channel or user are required", ErrInvalidSchedule)
+	case msg.Kind == ScheduleReminder && msg.Content == "":
+		return nil, fmt.Errorf("%w: content is required", ErrInvalidSchedule)
+	case len(msg.Content) > MaxScheduledContentLength:
+		return nil, fmt.Errorf("%w: content too long (max %d characters)", ErrInvalidSchedule, MaxScheduledContentLength)
+	case msg.DeliverAt.IsZero():
+		return nil, fmt.Errorf("%w: deliver_at is required", ErrInvalidSchedule)
+	case time.Until(msg.DeliverAt) > MaxScheduleAhead:
+		return nil, fmt.Errorf("%w: deliver_at is more than a year away", ErrInvalidSchedule)
+	}
+	if a.plugins != nil {
+		if _, ok := a.plugins.Get(msg.Platform); !ok {
+			a.log().WarnContext(ctx, "scheduled message for a platform that is not loaded", "platform", msg.Platform)
+		}
+	}
+
+	if msg.ID == "" {
+		msg.ID = scheduleID(msg)
+	}
+	msg.Status = memory.ScheduledPending
+	if err := a.memory.SaveScheduled(ctx, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// ScheduledMessages lists scheduled messages with a status, or all of them
+// for an empty status, soonest first
+func (a *Agent) ScheduledMessages(ctx context.Context, status string) ([]memory.ScheduledMessage, error) {
+	return a.memory.ListScheduled(ctx, status)
+}
+
+// CancelScheduled cancels a pending scheduled message
+func (a *Agent) CancelScheduled(ctx context.Context, id string) (*memory.ScheduledMessage, error) {
+	msg, err := a.memory.LoadScheduled(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if msg.Status != memory.ScheduledPending {
+		return nil, fmt.Errorf("%w: %s is %s", ErrScheduleNotPending, id, msg.Status)
+	}
+	msg.Status = memory.ScheduledCanceled
+	if err := a.memory.SaveScheduled(ctx, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// startSchedulerLoop delivers due messages every interval
+func (a *Agent) startSchedulerLoop() {
+	a.startPeriodicJob(a.scheduler.Interval, ScheduleTimeout, func(ctx context.Context) {
+		a.runScheduler(ctx, time.Now())
+	})
+}
+
+// runScheduler delivers the messages due at now, schedules the next digest
+// when none is pending and drops finished messages past their retention
+func (a *Agent) runScheduler(ctx context.Context, now time.Time) {
+	messages, err := a.memory.ListScheduled(ctx, "")
+	if err != nil {
+		a.log().WarnContext(ctx, "failed to list scheduled messages", "error", err)
+		return
+	}
+
+	digestPending := false
+	for i := range messages {
+		msg := &messages[i]
+		switch {
+		case msg.Status == memory.ScheduledPending && !msg.DeliverAt.After(now):
+			a.deliverScheduled(ctx, msg, now)
+		case msg.Status != memory.ScheduledPending && now.Sub(msg.DeliverAt) > ScheduleRetention:
+			if err := a.memory.DeleteScheduled(ctx, msg.ID); err != nil {
+				a.log().WarnContext(ctx, "failed to drop finished scheduled message", "schedule_id", msg.ID, "error", err)
+			}
+			continue
+		}
+		if msg.Kind == ScheduleDigest && msg.Status == memory.ScheduledPending {
+			digestPending = true
+		}
+	}
+	if !digestPending {
+		a.scheduleDigest(ctx, now)
+	}
+}
+
+// deliverScheduled sends one due message and records the outcome. Digests
+// and deadline warnings are written now, and canceled when there is
+// nothing to say.
+func (a *Agent) deliverScheduled(ctx context.Context, msg *memory.ScheduledMessage, now time.Time) {
+	content, ok := a.composeScheduled(ctx, msg)
+	if !ok {
+		msg.Status = memory.ScheduledCanceled
+	} else if err := a.sendScheduled(ctx, msg, content); err != nil {
+		msg.Attempts++
+		msg.LastError = err.Error()
+		if msg.Attempts >= a.scheduler.withDefaults().MaxAttempts {
+			msg.Status = memory.ScheduledFailed
+		}
+		a.log().WarnContext(ctx, "failed to deliver scheduled message", "schedule_id", msg.ID, "kind", msg.Kind, "attempts", msg.Attempts, "error", err)
+	} else {
+		msg.Status = memory.ScheduledSent
+		msg.Content = content
+		sentAt := now
+		msg.SentAt = &sentAt
+	}
+	if err := a.memory.SaveScheduled(ctx, msg); err != nil {
+		a.log().WarnContext(ctx, "failed to save scheduled message", "schedule_id", msg.ID, "error", err)
+	}
+}
+
+// sendScheduled sends a message's content through its plugin
+func (a *Agent) sendScheduled(ctx context.Context, msg *memory.ScheduledMessage, content string) error {
+	if a.plugins == nil {
+		return fmt.Errorf("no plugins are loaded")
+	}
+	return a.plugins.SendMessage(ctx, msg.Platform, &plugins.Message{
+		Platform:  msg.Platform,
+		ChannelID: msg.ChannelID,
+		ThreadID:  msg.ThreadID,
+		UserID:    msg.UserID,
+		Content:   content,
+		Timestamp: time.Now().Unix(),
+	})
+}
+
+// composeScheduled writes the text of a due message; ok is false when it
+// should not be sent
+func (a *Agent) composeScheduled(ctx context.Context, msg *memory.ScheduledMessage) (string, bool) {
+	switch msg.Kind {
+	case ScheduleDigest:
+		return a.composeDigest(ctx, msg.DeliverAt.Add(-24*time.Hour))
+	case ScheduleDeadline:
+		return a.composeDeadlineWarning(msg.SubjectID)
+	}
+	return msg.Content, true
+}
+
+// composeDigest lists the musings written since a time
+func (a *Agent) composeDigest(ctx context.Context, since time.Time) (string, bool) {
+	musings, err := a.ListMusings(ctx, DigestMusings)
+	if err != nil {
+		a.log().WarnContext(ctx, "failed to list musings for digest", "error", err)
+		return "", false
+	}
+	var b strings.Builder
+	for _, m := range musings {
+		if m.Timestamp.After(since) {
+			b.WriteString("- " + m.Content + "\n")
+		}
+	}
+	if b.Len() == 0 {
+		return "", false
+	}
+	return "Today's musings:\n" + strings.TrimSpace(b.String()), true
+}
+
+// composeDeadlineWarning describes an open proposal about to close
+func (a *Agent) composeDeadlineWarning(proposalID string) (string, bool) {
+	if a.governance == nil {
+		return "", false
+	}
+	proposal, ok := a.governance.GetProposal(proposalID)
+	if !ok || proposal.Status != governance.ProposalOpen {
+		return "", false
+	}
+	tally := make(map[governance.VoteType]int, 3)
+	for _, vote := range proposal.Votes {
+		tally[vote]++
+	}
+	return fmt.Sprintf("Voting on proposal %s closes %s: %s (%d yes, %d no, %d abstain so far)",
+		proposal.ProposalID, proposal.Deadline.UTC().Format(time.RFC1123), describeProposal(proposal),
+		tally[governance.VoteYes], tally[governance.VoteNo], tally[governance.VoteAbstain]), true
+}
+
+// scheduleDigest queues the next daily digest when a digest target is set,
+// unless that day's digest was already sent or canceled
+func (a *Agent) scheduleDigest(ctx context.Context, now time.Time) {
+	platform, channel, ok := splitTarget(a.scheduler.DigestTarget)
+	if !ok {
+		return
+	}
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	next := midnight.Add(a.scheduler.DigestAt)
+	if !next.After(now) {
+		next = midnight.AddDate(0, 0, 1).Add(a.scheduler.DigestAt)
+	}
+	id := "digest:" + next.Format("2006-01-02")
+	if _, err := a.memory.LoadScheduled(ctx, id); err == nil {
+		return // Already sent or canceled
+	}
+	_, err := a.ScheduleMessage(ctx, &memory.ScheduledMessage{
+		ID:        id,
+		Kind:      ScheduleDigest,
+		Platform:  platform,
+		ChannelID: channel,
+		DeliverAt: next,
+		CreatedBy: "scheduler",
+	})
+	if err != nil {
+		a.log().WarnContext(ctx, "failed to schedule musings digest", "error", err)
+	}
+}
+
+// startDeadlineListener schedules a warning before each new proposal's
+// deadline
+func (a *Agent) startDeadlineListener(bus *events.Bus) {
+	a.listen(bus, func(event events.Event) {
+		proposal, ok := event.Data.(governance.ProposalEvent)
+		if !ok {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), ScheduleTimeout)
+		defer cancel()
+		a.scheduleDeadlineWarning(ctx, proposal, time.Now())
+	}, events.ProposalCreated)
+}
+
+// scheduleDeadlineWarning queues a warning about an open proposal, unless
+// its deadline is too close to warn before
+func (a *Agent) scheduleDeadlineWarning(ctx context.Context, proposal governance.ProposalEvent, now time.Time) {
+	platform, channel, ok := splitTarget(a.scheduler.DeadlineTarget)
+	if !ok || proposal.Status != governance.ProposalOpen {
+		return
+	}
+	warnAt := proposal.Deadline.Add(-a.scheduler.withDefaults().DeadlineWarning)
+	if !warnAt.After(now) {
+		return
+	}
+	_, err := a.ScheduleMessage(ctx, &memory.ScheduledMessage{
+		ID:        "deadline:" + proposal.ProposalID,
+		Kind:      ScheduleDeadline,
+		Platform:  platform,
+		ChannelID: channel,
+		SubjectID: proposal.ProposalID,
+		DeliverAt: warnAt,
+		CreatedBy: "scheduler",
+	})
+	if err != nil {
+		a.log().WarnContext(ctx, "failed to schedule deadline warning", "proposal_id", proposal.ProposalID, "error", err)
+	}
+}
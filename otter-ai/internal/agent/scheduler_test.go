@@ -0,0 +1,208 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"otter-ai/internal/config"
+	"otter-ai/internal/governance"
+	"otter-ai/internal/memory"
+	"otter-ai/internal/plugins"
+)
+
+// brokenPlugin fails every send
+type brokenPlugin struct{ chatPlugin }
+
+func (p *brokenPlugin) Name() string { return "broken" }
+func (p *brokenPlugin) SendMessage(context.Context, *plugins.Message) error {
+	return errors.New("platform unreachable")
+}
+
+// newSchedulerAgent returns an agent whose scheduler sends through a chat
+// plugin and a broken one, with the delivery loop left to the test
+func newSchedulerAgent(t *testing.T, cfg SchedulerConfig) (*Agent, *chatPlugin, *governance.Governance) {
+	t.Helper()
+	plugin := &chatPlugin{}
+	mgr := plugins.NewManager(config.PluginConfig{})
+	mgr.Register(plugin)
+	mgr.Register(&brokenPlugin{})
+
+	a, gov := newTestGovernedAgent(t, Config{Plugins: mgr, Scheduler: cfg})
+	return a, plugin, gov
+}
+
+// scheduled returns a scheduled message by ID
+func scheduled(t *testing.T, a *Agent, id string) *memory.ScheduledMessage {
+	t.Helper()
+	msg, err := a.memory.LoadScheduled(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+func TestScheduleMessage_Validation(t *testing.T) {
+	a, _, _ := newSchedulerAgent(t, SchedulerConfig{})
+	ctx := context.Background()
+	soon := time.Now().Add(time.Hour)
+
+	for name, msg := range map[string]*memory.ScheduledMessage{
+		"no platform":  {ChannelID: "general", Content: "hi", DeliverAt: soon},
+		"no recipient": {Platform: "chat", Content: "hi", DeliverAt: soon},
+		"no content":   {Platform: "chat", ChannelID: "general", DeliverAt: soon},
+		"no time":      {Platform: "chat", ChannelID: "general", Content: "hi"},
+		"too far":      {Platform: "chat", ChannelID: "general", Content: "hi", DeliverAt: soon.AddDate(2, 0, 0)},
+		"unknown kind": {Kind: "poem", Platform: "chat", ChannelID: "general", Content: "hi", DeliverAt: soon},
+	} {
+		if _, err := a.ScheduleMessage(ctx, msg); !errors.Is(err, ErrInvalidSchedule) {
+			t.Errorf("%s: err = %v, want ErrInvalidSchedule", name, err)
+		}
+	}
+
+	msg, err := a.ScheduleMessage(ctx, &memory.ScheduledMessage{Platform: "chat", UserID: "bob", Content: "hi", DeliverAt: soon})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.ID == "" || msg.Kind != ScheduleReminder || msg.Status != memory.ScheduledPending {
+		t.Errorf("scheduled = %+v", msg)
+	}
+	if _, err := a.CancelScheduled(ctx, msg.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.CancelScheduled(ctx, msg.ID); !errors.Is(err, ErrScheduleNotPending) {
+		t.Errorf("canceling twice: err = %v", err)
+	}
+}
+
+func TestRunScheduler_DeliversDueMessages(t *testing.T) {
+	a, plugin, _ := newSchedulerAgent(t, SchedulerConfig{MaxAttempts: 2})
+	ctx := context.Background()
+	now := time.Now()
+
+	due, _ := a.ScheduleMessage(ctx, &memory.ScheduledMessage{Platform: "chat", ChannelID: "general", Content: "check the nets", DeliverAt: now.Add(-time.Minute)})
+	later, _ := a.ScheduleMessage(ctx, &memory.ScheduledMessage{Platform: "chat", ChannelID: "general", Content: "tomorrow", DeliverAt: now.Add(24 * time.Hour)})
+	broken, _ := a.ScheduleMessage(ctx, &memory.ScheduledMessage{Platform: "broken", ChannelID: "general", Content: "lost", DeliverAt: now.Add(-time.Minute)})
+
+	a.runScheduler(ctx, now)
+	sent := plugin.messages()
+	if len(sent) != 1 || sent[0].Content != "check the nets" || sent[0].ChannelID != "general" {
+		t.Fatalf("sent = %+v, want only the due reminder", sent)
+	}
+	if msg := scheduled(t, a, due.ID); msg.Status != memory.ScheduledSent || msg.SentAt == nil {
+		t.Errorf("due message = %+v, want sent", msg)
+	}
+	if msg := scheduled(t, a, later.ID); msg.Status != memory.ScheduledPending {
+		t.Errorf("later message = %+v, want pending", msg)
+	}
+	if msg := scheduled(t, a, broken.ID); msg.Status != memory.ScheduledPending || msg.Attempts != 1 || msg.LastError == "" {
+		t.Errorf("undeliverable message = %+v, want a recorded retry", msg)
+	}
+
+	a.runScheduler(ctx, now.Add(time.Minute))
+	if msg := scheduled(t, a, broken.ID); msg.Status != memory.ScheduledFailed {
+		t.Errorf("undeliverable message = %+v, want failed after MaxAttempts", msg)
+	}
+	if len(plugin.messages()) != 1 {
+		t.Errorf("sent messages were sent again")
+	}
+
+	// Finished messages are dropped once past their retention
+	a.runScheduler(ctx, now.Add(ScheduleRetention+time.Hour))
+	if _, err := a.memory.LoadScheduled(ctx, due.ID); !errors.Is(err, memory.ErrScheduledNotFound) {
+		t.Errorf("finished message kept past retention: err = %v", err)
+	}
+}
+
+func TestRunScheduler_Digest(t *testing.T) {
+	a, plugin, _ := newSchedulerAgent(t, SchedulerConfig{DigestTarget: "chat:musings", DigestAt: 9 * time.Hour})
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 8, 0, 0, 0, time.Local)
+
+	a.runScheduler(ctx, now)
+	digest := scheduled(t, a, "digest:2026-03-01")
+	if digest.Kind != ScheduleDigest || !digest.DeliverAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("digest = %+v, want one at 09:00", digest)
+	}
+
+	// Nothing was mused, so the digest is skipped and tomorrow's queued
+	a.runScheduler(ctx, now.Add(2*time.Hour))
+	if len(plugin.messages()) != 0 || scheduled(t, a, digest.ID).Status != memory.ScheduledCanceled {
+		t.Errorf("empty digest was sent")
+	}
+	if next := scheduled(t, a, "digest:2026-03-02"); next.Status != memory.ScheduledPending {
+		t.Errorf("next digest = %+v", next)
+	}
+
+	// A canceled digest is not queued again
+	a.runScheduler(ctx, now.Add(3*time.Hour))
+	if msg := scheduled(t, a, digest.ID); msg.Status != memory.ScheduledCanceled {
+		t.Errorf("canceled digest was requeued: %+v", msg)
+	}
+}
+
+func TestComposeDigest(t *testing.T) {
+	a, _, _ := newSchedulerAgent(t, SchedulerConfig{})
+	ctx := context.Background()
+	if err := a.memory.Store(ctx, &memory.MemoryRecord{Type: memory.MemoryTypeMusing, Content: "Rivers remember their banks.", Embedding: []float32{1, 0, 0}}); err != nil {
+		t.Fatal(err)
+	}
+
+	content, ok := a.composeDigest(ctx, time.Now().Add(-time.Hour))
+	if !ok || !strings.Contains(content, "Rivers remember their banks.") {
+		t.Errorf("digest = %q, %v", content, ok)
+	}
+	if _, ok := a.composeDigest(ctx, time.Now().Add(time.Hour)); ok {
+		t.Error("digest written with no new musings")
+	}
+}
+
+func TestScheduleDeadlineWarning(t *testing.T) {
+	a, plugin, gov := newSchedulerAgent(t, SchedulerConfig{DeadlineTarget: "chat:governance", DeadlineWarning: time.Hour})
+	ctx := context.Background()
+	joinPeer(t, gov, "otter-1", "otter-2")
+
+	proposal, err := gov.ProposeRule(ctx, "otter-1", &governance.Rule{Scope: "general", Body: "be kind", ProposedBy: "otter-2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := governance.ProposalEvent{ProposalID: proposal.ProposalID, Status: governance.ProposalOpen, Deadline: proposal.Deadline}
+	a.scheduleDeadlineWarning(ctx, event, time.Now())
+	warning := scheduled(t, a, "deadline:"+proposal.ProposalID)
+	if !warning.DeliverAt.Equal(proposal.Deadline.Add(-time.Hour)) {
+		t.Errorf("warning at %v, want an hour before %v", warning.DeliverAt, proposal.Deadline)
+	}
+
+	a.runScheduler(ctx, warning.DeliverAt)
+	sent := plugin.messages()
+	if len(sent) != 1 || sent[0].ChannelID != "governance" || !strings.Contains(sent[0].Content, "be kind") {
+		t.Errorf("sent = %+v, want the deadline warning", sent)
+	}
+
+	// Too close to the deadline to warn
+	event.ProposalID = "late"
+	a.scheduleDeadlineWarning(ctx, event, proposal.Deadline.Add(-time.Minute))
+	if _, err := a.memory.LoadScheduled(ctx, "deadline:late"); err == nil {
+		t.Error("warning scheduled after its time")
+	}
+}
+
+func TestRemindCommand(t *testing.T) {
+	a, _, _ := newSchedulerAgent(t, SchedulerConfig{})
+	ctx := context.Background()
+
+	if reply := a.remindCommand(ctx, "2h check the nets"); !strings.Contains(reply, "plugin channel") {
+		t.Errorf("reminder outside a channel: %q", reply)
+	}
+	if reply := a.remindCommand(WithChannel(ctx, "chat", "general"), "soon check the nets"); !strings.HasPrefix(reply, "Usage") {
+		t.Errorf("bad duration: %q", reply)
+	}
+
+	reply := a.remindCommand(WithChannel(ctx, "chat", "general"), "2h check the nets")
+	pending, _ := a.ScheduledMessages(ctx, memory.ScheduledPending)
+	if len(pending) != 1 || pending[0].Content != "check the nets" || pending[0].ChannelID != "general" || !strings.HasPrefix(reply, "I'll remind you") {
+		t.Errorf("reply %q, pending = %+v", reply, pending)
+	}
+}
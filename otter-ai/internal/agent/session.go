@@ -0,0 +1,466 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"otter-ai/internal/errs"
+	"otter-ai/internal/llm"
+	"otter-ai/internal/memory"
+	"otter-ai/internal/prompts"
+)
+
+// Session configuration
+const (
+	DefaultSessionID      = "default"
+	MaxSessionIDLength    = 128
+	SessionSummaryBatch   = 6 // Evicted messages accumulated before folding into the summary
+	SessionSummaryMaxLen  = 2000
+	SessionCacheIdleTTL   = 2 * time.Hour
+	SessionListLimit      = 100
+	sessionSummaryTimeout = 60 * time.Second
+)
+
+// Session is a single conversation thread with its own short-term history.
+// Messages that slide out of the history window are folded into Summary so
+// long conversations keep their gist without growing the prompt unbounded.
+type Session struct {
+	ID        string
+	History   *ConversationHistory
+	CreatedAt time.Time
+
+	mu         sync.Mutex
+	summary    string
+	evicted    []ConversationMessage
+	lastActive time.Time
+	context    ContextOptions
+}
+
+// SessionInfo is a lightweight view of a session for listings
+type SessionInfo struct {
+	ID           string    `json:"id"`
+	MessageCount int       `json:"message_count"`
+	Summary      string    `json:"summary,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// SessionManager tracks active conversation sessions and persists them
+// through the memory layer.
+type SessionManager struct {
+	memory   *memory.Memory
+	sessions map[string]*Session
+	mu       sync.Mutex
+}
+
+type sessionContextKey struct{}
+
+// WithSession returns a context that routes ProcessMessage to the given session
+func WithSession(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, sessionContextKey{}, sessionID)
+}
+
+// SessionFromContext returns the session ID carried by ctx, or DefaultSessionID
+func SessionFromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(sessionContextKey{}).(string); ok && id != "" {
+		return id
+	}
+	return DefaultSessionID
+}
+
+type userContextKey struct{}
+
+// WithUser returns a context naming the user a message comes from. Their
+// interaction memories record it, so they can be erased on request.
+func WithUser(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userContextKey{}, userID)
+}
+
+// UserFromContext returns the user ID carried by ctx, empty when unknown
+func UserFromContext(ctx context.Context) string {
+	userID, _ := ctx.Value(userContextKey{}).(string)
+	return userID
+}
+
+// ValidateSessionID ensures a client-supplied session ID is safe to store
+func ValidateSessionID(id string) error {
+	if id == "" {
+		return fmt.Errorf("session ID is required")
+	}
+	if len(id) > MaxSessionIDLength {
+		return fmt.Errorf("session ID too long (max %d characters)", MaxSessionIDLength)
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return fmt.Errorf("session ID contains invalid character %q", r)
+		}
+	}
+	return nil
+}
+
+// newSessionManager creates a session manager seeded with the default
+// session backed by the given history, restoring its persisted state if any.
+func newSessionManager(mem *memory.Memory, defaultHistory *ConversationHistory) *SessionManager {
+	now := time.Now()
+	defaultSession := &Session{
+		ID:         DefaultSessionID,
+		History:    defaultHistory,
+		CreatedAt:  now,
+		lastActive: now,
+	}
+	if mem != nil {
+		if record, err := mem.LoadSession(context.Background(), DefaultSessionID); err == nil {
+			defaultSession.restore(record)
+		}
+	}
+
+	return &SessionManager{
+		memory:   mem,
+		sessions: map[string]*Session{DefaultSessionID: defaultSession},
+	}
+}
+
+// Get returns the session with the given ID, restoring it from the memory
+// layer or creating it when it is not cached.
+func (sm *SessionManager) Get(ctx context.Context, id string) *Session {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.pruneIdleLocked()
+
+	if session, ok := sm.sessions[id]; ok {
+		session.touch()
+		return session
+	}
+
+	session := &Session{
+		ID: id,
+		History: &ConversationHistory{
+			messages: make([]ConversationMessage, 0, ConversationHistoryLimit),
+		},
+		CreatedAt:  time.Now(),
+		lastActive: time.Now(),
+	}
+
+	if sm.memory != nil {
+		if record, err := sm.memory.LoadSession(ctx, id); err == nil {
+			session.restore(record)
+		}
+	}
+
+	sm.sessions[id] = session
+	return session
+}
+
+// Save persists a session through the memory layer
+func (sm *SessionManager) Save(ctx context.Context, session *Session) error {
+	if sm.memory == nil {
+		return nil
+	}
+	return sm.memory.SaveSession(ctx, session.record())
+}
+
+// Clear resets a session's history and summary and removes its persisted copy
+func (sm *SessionManager) Clear(ctx context.Context, id string) error {
+	sm.mu.Lock()
+	session, ok := sm.sessions[id]
+	if ok && id != DefaultSessionID {
+		delete(sm.sessions, id)
+	}
+	sm.mu.Unlock()
+
+	if ok {
+		session.reset()
+	}
+
+	if sm.memory == nil {
+		return nil
+	}
+	return sm.memory.DeleteSession(ctx, id)
+}
+
+// List returns persisted sessions merged with any cached sessions that have
+// not been written yet.
+func (sm *SessionManager) List(ctx context.Context) ([]SessionInfo, error) {
+	seen := make(map[string]bool)
+	var infos []SessionInfo
+
+	if sm.memory != nil {
+		records, err := sm.memory.ListSessions(ctx, SessionListLimit, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			seen[record.ID] = true
+			infos = append(infos, SessionInfo{
+				ID:           record.ID,
+				MessageCount: len(record.Messages),
+				Summary:      record.Summary,
+				CreatedAt:    record.CreatedAt,
+				UpdatedAt:    record.UpdatedAt,
+			})
+		}
+	}
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	for id, session := range sm.sessions {
+		if seen[id] {
+			continue
+		}
+		messages := session.History.GetRecent(ConversationHistoryLimit)
+		if len(messages) == 0 {
+			continue
+		}
+		infos = append(infos, session.info())
+	}
+
+	return infos, nil
+}
+
+// Snapshot returns the full stored state of a session
+func (sm *SessionManager) Snapshot(ctx context.Context, id string) (*memory.SessionRecord, error) {
+	sm.mu.Lock()
+	session, ok := sm.sessions[id]
+	sm.mu.Unlock()
+	if ok {
+		return session.record(), nil
+	}
+
+	if sm.memory == nil {
+		return nil, errs.Errorf(errs.ErrNotFound, "session not found: %s", id)
+	}
+	return sm.memory.LoadSession(ctx, id)
+}
+
+// pruneIdleLocked drops cached sessions that have been idle past the TTL.
+// Persisted copies remain and are restored on next access.
+func (sm *SessionManager) pruneIdleLocked() {
+	cutoff := time.Now().Add(-SessionCacheIdleTTL)
+	for id, session := range sm.sessions {
+		if id == DefaultSessionID {
+			continue
+		}
+		if session.idleSince().Before(cutoff) {
+			delete(sm.sessions, id)
+		}
+	}
+}
+
+// Summary returns the rolling summary of messages that left the window
+func (s *Session) Summary() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.summary
+}
+
+// Add appends a message and queues anything that slid out of the window for summarization
+func (s *Session) Add(role, content string) {
+	evicted := s.History.Add(role, content)
+
+	s.mu.Lock()
+	s.evicted = append(s.evicted, evicted...)
+	s.lastActive = time.Now()
+	s.mu.Unlock()
+}
+
+// takeEvicted returns queued evicted messages once a full batch is ready
+func (s *Session) takeEvicted() []ConversationMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.evicted) < SessionSummaryBatch {
+		return nil
+	}
+	batch := s.evicted
+	s.evicted = nil
+	return batch
+}
+
+func (s *Session) setSummary(summary string) {
+	summary = strings.TrimSpace(summary)
+	if len(summary) > SessionSummaryMaxLen {
+		summary = summary[len(summary)-SessionSummaryMaxLen:]
+	}
+	s.mu.Lock()
+	s.summary = summary
+	s.mu.Unlock()
+}
+
+func (s *Session) touch() {
+	s.mu.Lock()
+	s.lastActive = time.Now()
+	s.mu.Unlock()
+}
+
+func (s *Session) idleSince() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastActive
+}
+
+func (s *Session) reset() {
+	s.History.Clear()
+	s.mu.Lock()
+	s.summary = ""
+	s.evicted = nil
+	s.context = ContextOptions{}
+	s.mu.Unlock()
+}
+
+// ContextOptions returns the context controls set for this conversation
+func (s *Session) ContextOptions() ContextOptions {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.context
+}
+
+// SetContextOptions replaces the context controls for this conversation
+func (s *Session) SetContextOptions(opts ContextOptions) {
+	s.mu.Lock()
+	s.context = opts
+	s.mu.Unlock()
+}
+
+func (s *Session) info() SessionInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return SessionInfo{
+		ID:           s.ID,
+		MessageCount: len(s.History.GetRecent(ConversationHistoryLimit)),
+		Summary:      s.summary,
+		CreatedAt:    s.CreatedAt,
+		UpdatedAt:    s.lastActive,
+	}
+}
+
+// record converts the session into its persisted form
+func (s *Session) record() *memory.SessionRecord {
+	messages := s.History.GetRecent(ConversationHistoryLimit)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record := &memory.SessionRecord{
+		ID:           s.ID,
+		Summary:      s.summary,
+		Messages:     make([]memory.SessionMessage, 0, len(messages)),
+		CreatedAt:    s.CreatedAt,
+		UpdatedAt:    s.lastActive,
+		NoMemory:     s.context.NoMemory,
+		NoGovernance: s.context.NoGovernance,
+		Scope:        s.context.Scope,
+	}
+	for _, msg := range messages {
+		record.Messages = append(record.Messages, memory.SessionMessage{
+			Role:      msg.Role,
+			Content:   msg.Content,
+			Timestamp: msg.Timestamp,
+		})
+	}
+	return record
+}
+
+// restore loads persisted state into the session
+func (s *Session) restore(record *memory.SessionRecord) {
+	s.History.Clear()
+	s.History.mutex.Lock()
+	for _, msg := range record.Messages {
+		s.History.messages = append(s.History.messages, ConversationMessage{
+			Role:      msg.Role,
+			Content:   msg.Content,
+			Timestamp: msg.Timestamp,
+		})
+	}
+	if len(s.History.messages) > ConversationHistoryLimit {
+		s.History.messages = s.History.messages[len(s.History.messages)-ConversationHistoryLimit:]
+	}
+	s.History.mutex.Unlock()
+
+	s.mu.Lock()
+	s.summary = record.Summary
+	s.context = ContextOptions{
+		NoMemory:     record.NoMemory,
+		NoGovernance: record.NoGovernance,
+		Scope:        record.Scope,
+	}
+	if !record.CreatedAt.IsZero() {
+		s.CreatedAt = record.CreatedAt
+	}
+	s.mu.Unlock()
+}
+
+// session resolves the session for the current request context
+func (a *Agent) session(ctx context.Context) *Session {
+	if a.sessions == nil {
+		// Agents built without a session manager (e.g. in tests) only have
+		// the default conversation.
+		return &Session{ID: DefaultSessionID, History: a.conversation}
+	}
+	return a.sessions.Get(ctx, SessionFromContext(ctx))
+}
+
+// Sessions returns the session manager
+func (a *Agent) Sessions() *SessionManager {
+	return a.sessions
+}
+
+// ClearSession clears the history of a specific session
+func (a *Agent) ClearSession(ctx context.Context, sessionID string) error {
+	if a.sessions == nil {
+		if sessionID == DefaultSessionID {
+			a.conversation.Clear()
+		}
+		return nil
+	}
+	return a.sessions.Clear(ctx, sessionID)
+}
+
+// summarizeEvicted folds messages that slid out of the window into the
+// session's rolling summary. Falls back to a truncated transcript when the
+// LLM is unavailable so context is never silently lost.
+func (a *Agent) summarizeEvicted(ctx context.Context, session *Session) {
+	batch := session.takeEvicted()
+	if len(batch) == 0 {
+		return
+	}
+
+	var transcript strings.Builder
+	for _, msg := range batch {
+		role := "User"
+		if msg.Role == "assistant" {
+			role = "Otter"
+		}
+		transcript.WriteString(fmt.Sprintf("%s: %s\n", role, sanitizeForPrompt(msg.Content)))
+	}
+
+	previous := session.Summary()
+	summary := ""
+
+	if a.llm != nil {
+		prompt := a.renderPrompt(ctx, prompts.SessionSummary, struct{ Summary, Transcript string }{previous, transcript.String()})
+
+		summaryCtx, cancel := context.WithTimeout(ctx, sessionSummaryTimeout)
+		completion, err := a.llm.Complete(summaryCtx, &llm.CompletionRequest{
+			Prompt:  prompt,
+			Profile: llm.ProfileSummary,
+		})
+		cancel()
+		if err == nil {
+			summary = strings.TrimSpace(completion.Text)
+		} else {
+			a.log().WarnContext(ctx, "failed to summarize session", "session_id", session.ID, "error", err)
+		}
+	}
+
+	if summary == "" {
+		summary = strings.TrimSpace(previous + "\n" + transcript.String())
+	}
+
+	session.setSummary(summary)
+}
@@ -0,0 +1,144 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// newTestSessionAgent creates a test agent with a session manager and no persistence
+func newTestSessionAgent(llmProv *mockLLMProvider) *Agent {
+	a := newTestAgent(llmProv)
+	a.sessions = newSessionManager(nil, a.conversation)
+	return a
+}
+
+// --- session context ---
+
+func TestSessionFromContext_Default(t *testing.T) {
+	if got := SessionFromContext(context.Background()); got != DefaultSessionID {
+		t.Errorf("SessionFromContext = %q, want %q", got, DefaultSessionID)
+	}
+}
+
+func TestSessionFromContext_WithSession(t *testing.T) {
+	ctx := WithSession(context.Background(), "user-42")
+	if got := SessionFromContext(ctx); got != "user-42" {
+		t.Errorf("SessionFromContext = %q, want user-42", got)
+	}
+}
+
+// --- ValidateSessionID ---
+
+func TestValidateSessionID(t *testing.T) {
+	valid := []string{"default", "user-42", "discord:1234.5678", "a_b"}
+	for _, id := range valid {
+		if err := ValidateSessionID(id); err != nil {
+			t.Errorf("ValidateSessionID(%q) error: %v", id, err)
+		}
+	}
+
+	invalid := []string{"", "has space", "slash/id", strings.Repeat("x", MaxSessionIDLength+1)}
+	for _, id := range invalid {
+		if err := ValidateSessionID(id); err == nil {
+			t.Errorf("ValidateSessionID(%q) expected error", id)
+		}
+	}
+}
+
+// --- ProcessMessage routing ---
+
+func TestProcessMessage_SessionsAreIsolated(t *testing.T) {
+	a := newTestSessionAgent(&mockLLMProvider{completeResp: "ok"})
+
+	if _, err := a.ProcessMessage(WithSession(context.Background(), "alice"), "my name is alice"); err != nil {
+		t.Fatalf("ProcessMessage: %v", err)
+	}
+	if _, err := a.ProcessMessage(context.Background(), "hello from default"); err != nil {
+		t.Fatalf("ProcessMessage: %v", err)
+	}
+
+	alice := a.sessions.Get(context.Background(), "alice")
+	if msgs := alice.History.GetRecent(10); len(msgs) != 2 || msgs[0].Content != "my name is alice" {
+		t.Errorf("alice history = %+v", msgs)
+	}
+
+	def := a.conversation.GetRecent(10)
+	if len(def) != 2 || def[0].Content != "hello from default" {
+		t.Errorf("default history = %+v", def)
+	}
+}
+
+func TestClearSession_OnlyAffectsTarget(t *testing.T) {
+	a := newTestSessionAgent(&mockLLMProvider{completeResp: "ok"})
+	ctx := context.Background()
+
+	a.sessions.Get(ctx, "alice").Add("user", "hi")
+	a.conversation.Add("user", "default hi")
+
+	if err := a.ClearSession(ctx, "alice"); err != nil {
+		t.Fatalf("ClearSession: %v", err)
+	}
+	if msgs := a.sessions.Get(ctx, "alice").History.GetRecent(10); msgs != nil {
+		t.Errorf("expected alice cleared, got %d messages", len(msgs))
+	}
+	if msgs := a.conversation.GetRecent(10); len(msgs) != 1 {
+		t.Errorf("expected default untouched, got %d messages", len(msgs))
+	}
+}
+
+// --- summarization ---
+
+func TestSummarizeEvicted_UsesLLM(t *testing.T) {
+	a := newTestSessionAgent(&mockLLMProvider{completeResp: "They discussed otters."})
+	session := a.sessions.Get(context.Background(), "s1")
+
+	for i := 0; i < ConversationHistoryLimit+SessionSummaryBatch; i++ {
+		session.Add("user", "message")
+	}
+	a.summarizeEvicted(context.Background(), session)
+
+	if got := session.Summary(); got != "They discussed otters." {
+		t.Errorf("Summary = %q", got)
+	}
+}
+
+func TestSummarizeEvicted_FallbackOnLLMError(t *testing.T) {
+	a := newTestSessionAgent(&mockLLMProvider{completeErr: errors.New("offline")})
+	session := a.sessions.Get(context.Background(), "s1")
+
+	for i := 0; i < ConversationHistoryLimit+SessionSummaryBatch; i++ {
+		session.Add("user", "remember this")
+	}
+	a.summarizeEvicted(context.Background(), session)
+
+	if !strings.Contains(session.Summary(), "User: remember this") {
+		t.Errorf("expected transcript fallback, got %q", session.Summary())
+	}
+}
+
+func TestSummarizeEvicted_WaitsForBatch(t *testing.T) {
+	a := newTestSessionAgent(&mockLLMProvider{completeResp: "summary"})
+	session := a.sessions.Get(context.Background(), "s1")
+
+	for i := 0; i < ConversationHistoryLimit+1; i++ {
+		session.Add("user", "message")
+	}
+	a.summarizeEvicted(context.Background(), session)
+
+	if got := session.Summary(); got != "" {
+		t.Errorf("expected no summary before a full batch, got %q", got)
+	}
+}
+
+func TestBuildSessionContext_IncludesSummary(t *testing.T) {
+	a := newTestSessionAgent(nil)
+	session := a.sessions.Get(context.Background(), "s1")
+	session.setSummary("User likes kelp.")
+
+	ctx := a.buildSessionContext(session, nil)
+	if !containsStr(ctx, "User likes kelp.") {
+		t.Errorf("expected summary in context: %q", ctx)
+	}
+}
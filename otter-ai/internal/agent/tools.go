@@ -7,6 +7,10 @@ import (
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
 	"otter-ai/internal/governance"
 	"otter-ai/internal/llm"
 	"otter-ai/internal/memory"
@@ -67,12 +71,31 @@ func (a *Agent) agentTools() []llm.ToolDefinition {
 					{Name: "vote", Type: "string", Description: "The vote to cast", Required: true, Enum: []string{"yes", "no", "abstain"}},
 				},
 			},
+			llm.ToolDefinition{
+				Name:        "leave_raft",
+				Description: "Leave a raft this otter joined. The user must confirm before the raft is left.",
+				Parameters: []llm.ToolParameter{
+					{Name: "raft_id", Type: "string", Description: "The ID of the raft to leave", Required: true},
+				},
+			},
 		)
 	}
 
 	return tools
 }
 
+// filterTools drops tools that the turn's context controls or the channel
+// profile withhold
+func filterTools(tools []llm.ToolDefinition, opts ContextOptions, profile *ChannelProfile) []llm.ToolDefinition {
+	filtered := make([]llm.ToolDefinition, 0, len(tools))
+	for _, tool := range tools {
+		if opts.allowsTool(tool.Name) && profile.allowsTool(tool.Name) {
+			filtered = append(filtered, tool)
+		}
+	}
+	return filtered
+}
+
 // toolHandlers returns a mapping of tool name → execution function.
 func (a *Agent) toolHandlers() map[string]ToolHandler {
 	handlers := map[string]ToolHandler{
@@ -83,19 +106,31 @@ func (a *Agent) toolHandlers() map[string]ToolHandler {
 		"list_governance_state": a.toolListGovernanceState,
 		"propose_rule":          a.toolProposeRule,
 		"vote_on_proposal":      a.toolVoteOnProposal,
+		"leave_raft":            a.toolLeaveRaft,
 	}
 	return handlers
 }
 
 // executeTool dispatches a single tool call and returns the result.
 func (a *Agent) executeTool(ctx context.Context, call llm.ToolCall) string {
+	ctx, span := tracer.Start(ctx, "agent.executeTool", trace.WithAttributes(attribute.String("agent.tool", call.Name)))
+	defer span.End()
+
 	handlers := a.toolHandlers()
 	handler, ok := handlers[call.Name]
 	if !ok {
 		return fmt.Sprintf("Unknown tool: %s", call.Name)
 	}
+	if !ContextOptionsFromContext(ctx).allowsTool(call.Name) {
+		return fmt.Sprintf("Tool %s is disabled for this conversation.", call.Name)
+	}
+	if !a.channelProfile(ctx).allowsTool(call.Name) {
+		return fmt.Sprintf("Tool %s is not available in this channel.", call.Name)
+	}
 	result, err := handler(ctx, call.Arguments)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return fmt.Sprintf("Tool %s error: %v", call.Name, err)
 	}
 	return result
@@ -109,12 +144,13 @@ func (a *Agent) toolSearchMemories(ctx context.Context, args map[string]string)
 		return "No search query provided.", nil
 	}
 
-	embedding, err := a.llm.Embed(ctx, query)
+	embedding, err := a.embed(ctx, query)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate embedding: %w", err)
 	}
 
-	memories, err := a.memory.Search(ctx, embedding, memory.MemoryTypeLongTerm, DefaultMemorySearchLimit)
+	filter := memory.SearchFilter{Scope: ContextOptionsFromContext(ctx).Scope}
+	memories, err := a.retrieveMemories(ctx, query, embedding, DefaultMemorySearchLimit, filter)
 	if err != nil {
 		return "", fmt.Errorf("failed to search memories: %w", err)
 	}
@@ -138,12 +174,18 @@ func (a *Agent) toolSearchMemories(ctx context.Context, args map[string]string)
 	return sb.String(), nil
 }
 
-func (a *Agent) toolGetLastMemory(_ context.Context, _ map[string]string) (string, error) {
-	ctx := context.Background()
-	records, err := a.memory.List(ctx, memory.MemoryTypeLongTerm, 1, 0)
+func (a *Agent) toolGetLastMemory(ctx context.Context, _ map[string]string) (string, error) {
+	scope := ContextOptionsFromContext(ctx).Scope
+	limit := 1
+	if scope != "" {
+		limit = ScopedListWindow
+	}
+
+	records, err := a.memory.List(context.Background(), memory.MemoryTypeLongTerm, limit, 0)
 	if err != nil {
 		return "", fmt.Errorf("failed to read memory: %w", err)
 	}
+	records = filterMemoryScope(records, scope)
 	if len(records) == 0 {
 		return "No stored memories yet.", nil
 	}
@@ -174,11 +216,25 @@ func (a *Agent) toolGetHealthStatus(_ context.Context, _ map[string]string) (str
 	return fmt.Sprintf("Current health metrics:\n%s", string(jsonBytes)), nil
 }
 
-func (a *Agent) toolListGovernanceState(_ context.Context, _ map[string]string) (string, error) {
+func (a *Agent) toolListGovernanceState(ctx context.Context, _ map[string]string) (string, error) {
 	if a.governance == nil {
 		return "Governance system is not configured.", nil
 	}
-	return a.buildGovernanceContext(), nil
+	return a.buildGovernanceContext(ctx, ContextOptionsFromContext(ctx).Scope), nil
+}
+
+// filterMemoryScope keeps records in scope; an empty scope keeps everything
+func filterMemoryScope(records []memory.MemoryRecord, scope string) []memory.MemoryRecord {
+	if scope == "" {
+		return records
+	}
+	filtered := records[:0]
+	for _, record := range records {
+		if record.Scope == scope {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered
 }
 
 func (a *Agent) toolProposeRule(ctx context.Context, args map[string]string) (string, error) {
@@ -216,7 +272,23 @@ func (a *Agent) toolProposeRule(ctx context.Context, args map[string]string) (st
 		return "", fmt.Errorf("failed to propose rule: %w", err)
 	}
 
-	return fmt.Sprintf("Rule proposal submitted.\n\nProposal ID: %s\nRule: \"%s\"\nScope: %s\nStatus: Open for voting", proposal.ProposalID, ruleBody, scope), nil
+	return fmt.Sprintf("Rule proposal submitted.\n\nProposal ID: %s\nRule: \"%s\"\nScope: %s\nStatus: Open for voting until %s", proposal.ProposalID, ruleBody, scope, formatDeadline(proposal.Deadline)) + impactNote(proposal), nil
+}
+
+// impactNote describes a new proposal's impact analysis, if it is ready
+func impactNote(proposal *governance.Proposal) string {
+	impact := proposal.Impact
+	if impact == nil || impact.Status != governance.ImpactReady {
+		return ""
+	}
+	var b strings.Builder
+	if impact.Summary != "" {
+		fmt.Fprintf(&b, "\nImpact: %s", impact.Summary)
+	}
+	for _, conflict := range impact.Conflicts {
+		fmt.Fprintf(&b, "\nConflicts with [%s] %q: %s", conflict.Scope, conflict.Body, conflict.Reason)
+	}
+	return b.String()
 }
 
 func (a *Agent) toolVoteOnProposal(ctx context.Context, args map[string]string) (string, error) {
@@ -242,8 +314,7 @@ func (a *Agent) toolVoteOnProposal(ctx context.Context, args map[string]string)
 		return fmt.Sprintf("Invalid vote type: %s (must be yes/no/abstain).", voteStr), nil
 	}
 
-	voterID := a.governance.GetID()
-	if err := a.governance.Vote(ctx, proposalID, voterID, voteType); err != nil {
+	if err := a.governance.CastVote(ctx, proposalID, voteType); err != nil {
 		if strings.Contains(err.Error(), "voter must be an active member") {
 			return "Cannot vote: not an active raft member.", nil
 		}
@@ -252,3 +323,25 @@ func (a *Agent) toolVoteOnProposal(ctx context.Context, args map[string]string)
 
 	return fmt.Sprintf("Voted %s on proposal %s.", strings.ToUpper(voteStr), proposalID), nil
 }
+
+// toolLeaveRaft stages leaving a raft; it only happens once the user confirms
+func (a *Agent) toolLeaveRaft(_ context.Context, args map[string]string) (string, error) {
+	if a.governance == nil {
+		return "Governance system is not configured.", nil
+	}
+
+	raftID := strings.TrimSpace(args["raft_id"])
+	if raftID == "" {
+		return "No raft ID provided.", nil
+	}
+	if raftID == a.governance.GetID() {
+		return "Cannot leave this otter's own raft.", nil
+	}
+
+	a.setPendingAction(&pendingGovernanceAction{
+		Action:    "leave_raft",
+		RaftID:    raftID,
+		CreatedAt: time.Now(),
+	})
+	return fmt.Sprintf("Leaving raft %s will tell its members and stop following its rules. Ask the user to reply \"confirm\" to leave or \"cancel\" to stay.", raftID), nil
+}
@@ -0,0 +1,151 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"otter-ai/internal/errs"
+	"otter-ai/internal/governance"
+	"otter-ai/internal/plugins"
+)
+
+// voteActionPrefix starts the action IDs of inline voting buttons, which
+// have the form vote:<proposal_id>:<YES|NO|ABSTAIN>
+const voteActionPrefix = "vote:"
+
+// Inline voting errors
+var (
+	ErrUnknownVoter = errs.New(errs.ErrUnauthorized, "platform user is not mapped to a raft member")
+	ErrRemoteVoter  = errs.New(errs.ErrUnauthorized, "mapped member must vote from its own otter")
+)
+
+// voteOptions are the buttons attached to posted proposals, in order
+var voteOptions = []struct {
+	vote  governance.VoteType
+	label string
+	emoji string
+	style string
+}{
+	{governance.VoteYes, "Yes", "👍", plugins.ActionStylePrimary},
+	{governance.VoteNo, "No", "👎", plugins.ActionStyleDanger},
+	{governance.VoteAbstain, "Abstain", "🤷", plugins.ActionStyleSecondary},
+}
+
+// ProposalMessage renders a proposal as a plugin message with YES, NO and
+// ABSTAIN actions
+func ProposalMessage(proposal *governance.Proposal, channelID string) *plugins.Message {
+	var content strings.Builder
+	if proposal.Kind == governance.ProposalKindEviction {
+		fmt.Fprintf(&content, "Proposal to revoke member %s", proposal.TargetMemberID)
+	} else if proposal.Kind == governance.ProposalKindAdmission {
+		fmt.Fprintf(&content, "Request from %s to join the raft", proposal.TargetMemberID)
+	} else if proposal.Kind == governance.ProposalKindSuspension && proposal.Rule != nil {
+		fmt.Fprintf(&content, "EMERGENCY: proposal to suspend the rule for %s: %s", proposal.Rule.Scope, proposal.Rule.Body)
+	} else if proposal.Kind == governance.ProposalKindReinstatement && proposal.Rule != nil {
+		fmt.Fprintf(&content, "Re-vote on reinstating the suspended rule for %s: %s", proposal.Rule.Scope, proposal.Rule.Body)
+	} else if proposal.Rule != nil {
+		fmt.Fprintf(&content, "Proposed rule for %s: %s", proposal.Rule.Scope, proposal.Rule.Body)
+	}
+	if proposal.Reason != "" {
+		fmt.Fprintf(&content, "\nReason: %s", proposal.Reason)
+	}
+	fmt.Fprintf(&content, "\nProposed by %s in raft %s; voting closes %s",
+		proposal.ProposedBy, proposal.RaftID, proposal.Deadline.UTC().Format(time.RFC1123))
+
+	message := &plugins.Message{
+		ChannelID: channelID,
+		Content:   content.String(),
+		Timestamp: time.Now().Unix(),
+		Metadata:  map[string]interface{}{"proposal_id": proposal.ProposalID},
+	}
+	for _, option := range voteOptions {
+		message.Actions = append(message.Actions, plugins.Action{
+			ID:    voteActionPrefix + proposal.ProposalID + ":" + string(option.vote),
+			Label: option.label,
+			Emoji: option.emoji,
+			Style: option.style,
+		})
+	}
+	return message
+}
+
+// PostProposal posts an open proposal with inline voting actions to a
+// plugin channel
+func (a *Agent) PostProposal(ctx context.Context, platform, channelID, proposalID string) error {
+	if a.plugins == nil {
+		return fmt.Errorf("no plugin for platform: %s", platform)
+	}
+	proposal, ok := a.governance.GetProposal(proposalID)
+	if !ok {
+		return fmt.Errorf("%w: %s", governance.ErrProposalNotFound, proposalID)
+	}
+	if proposal.Status != governance.ProposalOpen {
+		return fmt.Errorf("proposal is closed")
+	}
+
+	message := ProposalMessage(proposal, channelID)
+	message.Platform = platform
+	return a.plugins.SendMessage(ctx, platform, message)
+}
+
+// handleInteraction routes plugin interactions by their action ID prefix
+func (a *Agent) handleInteraction(ctx context.Context, interaction *plugins.Interaction) (string, error) {
+	if strings.HasPrefix(interaction.ActionID, onboardActionPrefix) {
+		return a.HandleOnboardingInteraction(ctx, interaction)
+	}
+	return a.HandleVoteInteraction(ctx, interaction)
+}
+
+// HandleVoteInteraction casts the vote behind an inline voting action. The
+// platform user must be mapped to this otter's member ID: only this otter
+// can sign its ballot, so users mapped to other members are refused.
+func (a *Agent) HandleVoteInteraction(ctx context.Context, interaction *plugins.Interaction) (string, error) {
+	proposalID, vote, ok := parseVoteAction(interaction.ActionID)
+	if !ok {
+		return "", fmt.Errorf("unknown action: %s", interaction.ActionID)
+	}
+
+	memberID, ok := a.voters[interaction.Platform+":"+interaction.UserID]
+	if !ok {
+		return "", fmt.Errorf("%w: %s user %s", ErrUnknownVoter, interaction.Platform, interaction.UserID)
+	}
+	if memberID != a.governance.GetID() {
+		return "", fmt.Errorf("%w: %s", ErrRemoteVoter, memberID)
+	}
+
+	at := time.Now()
+	if interaction.Timestamp > 0 {
+		at = time.Unix(interaction.Timestamp, 0)
+	}
+	err := a.governance.CastInteractiveVote(ctx, proposalID, vote, governance.VoteInteraction{
+		Platform:  interaction.Platform,
+		ChannelID: interaction.ChannelID,
+		MessageID: interaction.MessageID,
+		UserID:    interaction.UserID,
+		Username:  interaction.Username,
+		At:        at,
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Recorded %s vote as %s", vote, memberID), nil
+}
+
+// parseVoteAction splits a vote action ID into its proposal and vote
+func parseVoteAction(actionID string) (string, governance.VoteType, bool) {
+	rest, ok := strings.CutPrefix(actionID, voteActionPrefix)
+	if !ok {
+		return "", "", false
+	}
+	i := strings.LastIndex(rest, ":")
+	if i <= 0 {
+		return "", "", false
+	}
+	vote := governance.VoteType(rest[i+1:])
+	if vote != governance.VoteYes && vote != governance.VoteNo && vote != governance.VoteAbstain {
+		return "", "", false
+	}
+	return rest[:i], vote, true
+}
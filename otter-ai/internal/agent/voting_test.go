@@ -0,0 +1,119 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"otter-ai/internal/config"
+	"otter-ai/internal/governance"
+	"otter-ai/internal/memory"
+	"otter-ai/internal/plugins"
+)
+
+// chatPlugin is an Interactive plugin that records sent messages
+type chatPlugin struct {
+	mu      sync.Mutex
+	sent    []*plugins.Message
+	handler plugins.InteractionHandler
+}
+
+func (p *chatPlugin) Name() string                                             { return "chat" }
+func (p *chatPlugin) Initialize(context.Context, map[string]string) error      { return nil }
+func (p *chatPlugin) HandleMessage(context.Context, *plugins.Message) error    { return nil }
+func (p *chatPlugin) Shutdown(context.Context) error                           { return nil }
+func (p *chatPlugin) SetInteractionHandler(handler plugins.InteractionHandler) { p.handler = handler }
+func (p *chatPlugin) SendMessage(_ context.Context, message *plugins.Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sent = append(p.sent, message)
+	return nil
+}
+
+// messages returns a copy of the messages sent so far
+func (p *chatPlugin) messages() []*plugins.Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]*plugins.Message(nil), p.sent...)
+}
+
+func newVotingAgent(t *testing.T) (*Agent, *chatPlugin, *governance.Proposal) {
+	t.Helper()
+	plugin := &chatPlugin{}
+	mgr := plugins.NewManager(config.PluginConfig{})
+	mgr.Register(plugin)
+
+	a, gov := newTestGovernedAgent(t, Config{
+		Memory:  memory.New(&mockVectorDB{}),
+		Plugins: mgr,
+		Voters:  map[string]string{"chat:alice": "otter-1", "chat:bob": "otter-2"},
+	})
+	joinPeer(t, gov, "otter-1", "otter-2")
+	proposal, err := gov.ProposeRule(context.Background(), "otter-1", &governance.Rule{Scope: "tone", Body: "be kind", ProposedBy: "otter-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a, plugin, proposal
+}
+
+func TestPostProposal_AttachesVoteActions(t *testing.T) {
+	a, plugin, proposal := newVotingAgent(t)
+
+	if err := a.PostProposal(context.Background(), "chat", "general", proposal.ProposalID); err != nil {
+		t.Fatal(err)
+	}
+	if len(plugin.sent) != 1 {
+		t.Fatalf("sent %d messages", len(plugin.sent))
+	}
+	message := plugin.sent[0]
+	if message.ChannelID != "general" || !contains(message.Content, "be kind") || len(message.Actions) != 3 {
+		t.Fatalf("unexpected message %+v", message)
+	}
+	if id, vote, ok := parseVoteAction(message.Actions[1].ID); !ok || id != proposal.ProposalID || vote != governance.VoteNo {
+		t.Errorf("action %q parsed as %q %q %v", message.Actions[1].ID, id, vote, ok)
+	}
+
+	if err := a.PostProposal(context.Background(), "chat", "general", "missing"); !errors.Is(err, governance.ErrProposalNotFound) {
+		t.Errorf("expected ErrProposalNotFound, got %v", err)
+	}
+}
+
+func TestHandleVoteInteraction(t *testing.T) {
+	a, plugin, proposal := newVotingAgent(t)
+	ctx := context.Background()
+	action := voteActionPrefix + proposal.ProposalID + ":" + string(governance.VoteYes)
+
+	_, err := plugin.handler(ctx, &plugins.Interaction{Platform: "chat", UserID: "mallory", ActionID: action})
+	if !errors.Is(err, ErrUnknownVoter) {
+		t.Errorf("expected ErrUnknownVoter, got %v", err)
+	}
+	_, err = plugin.handler(ctx, &plugins.Interaction{Platform: "chat", UserID: "bob", ActionID: action})
+	if !errors.Is(err, ErrRemoteVoter) {
+		t.Errorf("expected ErrRemoteVoter, got %v", err)
+	}
+
+	reply, err := plugin.handler(ctx, &plugins.Interaction{
+		Platform: "chat", ChannelID: "general", MessageID: "m1", UserID: "alice", Username: "Alice", ActionID: action,
+	})
+	if err != nil {
+		t.Fatalf("vote: %v (%s)", err, reply)
+	}
+	got, _ := a.governance.GetProposal(proposal.ProposalID)
+	if got.Votes["otter-1"] != governance.VoteYes {
+		t.Errorf("vote not recorded: %v", got.Votes)
+	}
+
+	var recorded *governance.VoteInteractionAudit
+	a.governance.EachAuditEntry(ctx, governance.AuditFilter{}, func(entry governance.AuditEntry) error {
+		if entry.Action == governance.AuditVoteInteraction {
+			recorded = &governance.VoteInteractionAudit{}
+			return json.Unmarshal(entry.Data, recorded)
+		}
+		return nil
+	})
+	if recorded == nil || recorded.Interaction.UserID != "alice" || recorded.Interaction.MessageID != "m1" {
+		t.Errorf("interaction not audited: %+v", recorded)
+	}
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"otter-ai/internal/backup"
+)
+
+// BackupRequest carries the passphrase a backup archive is encrypted with
+type BackupRequest struct {
+	Passphrase string `json:"passphrase"`
+}
+
+// SetBackupSource sets what POST /api/v1/admin/backup archives
+func (s *Server) SetBackupSource(src backup.Source) {
+	s.backup = &src
+}
+
+// handleBackup streams an encrypted archive of the database, key material
+// and configuration files
+func (s *Server) handleBackup(w http.ResponseWriter, r *http.Request) {
+	var req BackupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if s.backup == nil {
+		respondError(w, http.StatusServiceUnavailable, "backups are not configured")
+		return
+	}
+
+	var archive bytes.Buffer
+	manifest, err := backup.Create(r.Context(), &archive, *s.backup, req.Passphrase)
+	if errors.Is(err, backup.ErrWeakPassphrase) {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		s.log().ErrorContext(r.Context(), "failed to create backup", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to create backup")
+		return
+	}
+
+	var requestedBy string
+	if claims, ok := ClaimsFromContext(r.Context()); ok {
+		requestedBy = claims.UserID
+	}
+	s.log().InfoContext(r.Context(), "created backup", "requested_by", requestedBy, "bytes", archive.Len(),
+		"data_files", len(manifest.DataFiles), "config_files", len(manifest.ConfigFiles))
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", manifest.FileName()))
+	w.Header().Set("Content-Length", strconv.Itoa(archive.Len()))
+	w.WriteHeader(http.StatusOK)
+	archive.WriteTo(w)
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"otter-ai/internal/backup"
+)
+
+func TestHandleBackup(t *testing.T) {
+	s := newTestServer("secret123")
+	admin, _ := s.jwtManager.GenerateToken("operator")
+	member, _ := s.jwtManager.GenerateRoleToken("bot", RoleMember, time.Hour)
+
+	post := func(token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v1/admin/backup", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		s.handler().ServeHTTP(w, req)
+		return w
+	}
+
+	body := `{"passphrase": "correct horse battery"}`
+	if w := post(admin, body); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("unconfigured: status = %d, want 503", w.Code)
+	}
+
+	dataDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dataDir, "otter.key"), []byte("key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	s.SetBackupSource(backup.Source{OtterID: "otter-1", DataDir: dataDir})
+
+	if w := post(member, body); w.Code != http.StatusForbidden {
+		t.Errorf("member: status = %d, want 403", w.Code)
+	}
+	if w := post(admin, `{"passphrase": "short"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("weak passphrase: status = %d, want 400", w.Code)
+	}
+
+	w := post(admin, body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body: %s", w.Code, w.Body.String())
+	}
+	if disposition := w.Header().Get("Content-Disposition"); !strings.Contains(disposition, backup.FileExtension) {
+		t.Errorf("Content-Disposition = %q", disposition)
+	}
+
+	restoreDir := t.TempDir()
+	target := backup.Target{
+		DataDir:   filepath.Join(restoreDir, "data"),
+		DBPath:    filepath.Join(restoreDir, "otter.db"),
+		ConfigDir: filepath.Join(restoreDir, "config"),
+	}
+	if _, err := backup.Restore(bytes.NewReader(w.Body.Bytes()), "correct horse battery", target); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(target.DataDir, "otter.key")); string(got) != "key" {
+		t.Errorf("restored key = %q", got)
+	}
+}
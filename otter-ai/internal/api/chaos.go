@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"otter-ai/internal/governance"
+)
+
+// ChaosSettings are the faults chaos mode injects into federation
+// deliveries, with delays in milliseconds
+type ChaosSettings struct {
+	DropRate      float64  `json:"drop_rate"`      // Probability a delivery fails
+	DuplicateRate float64  `json:"duplicate_rate"` // Probability a delivery is sent twice
+	ReorderRate   float64  `json:"reorder_rate"`   // Probability a delivery is overtaken by later ones
+	MinDelayMS    int64    `json:"min_delay_ms"`
+	MaxDelayMS    int64    `json:"max_delay_ms"`
+	Partitioned   []string `json:"partitioned"` // Member IDs or endpoints cut off in both directions
+	Seed          int64    `json:"seed,omitempty"`
+}
+
+// ChaosResponse reports the faults being injected and how often they fired
+type ChaosResponse struct {
+	ChaosSettings
+	Stats governance.ChaosStats `json:"stats"`
+}
+
+// handleGetChaos reports chaos mode's faults and counters
+func (s *Server) handleGetChaos(w http.ResponseWriter, r *http.Request) {
+	status, err := s.agent.GetGovernance().ChaosStatus()
+	if err != nil {
+		respondChaosError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, chaosResponse(status))
+}
+
+// handleSetChaos replaces chaos mode's faults; an empty body heals the network
+func (s *Server) handleSetChaos(w http.ResponseWriter, r *http.Request) {
+	var req ChaosSettings
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	gov := s.agent.GetGovernance()
+	err := gov.SetChaos(governance.ChaosConfig{
+		DropRate:      req.DropRate,
+		DuplicateRate: req.DuplicateRate,
+		ReorderRate:   req.ReorderRate,
+		MinDelay:      time.Duration(req.MinDelayMS) * time.Millisecond,
+		MaxDelay:      time.Duration(req.MaxDelayMS) * time.Millisecond,
+		Partitioned:   req.Partitioned,
+		Seed:          req.Seed,
+	})
+	if err != nil {
+		respondChaosError(w, err)
+		return
+	}
+
+	status, err := gov.ChaosStatus()
+	if err != nil {
+		respondChaosError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, chaosResponse(status))
+}
+
+// chaosResponse converts a chaos status for the API
+func chaosResponse(status *governance.ChaosStatus) ChaosResponse {
+	partitioned := status.Config.Partitioned
+	if partitioned == nil {
+		partitioned = []string{}
+	}
+	return ChaosResponse{
+		ChaosSettings: ChaosSettings{
+			DropRate:      status.Config.DropRate,
+			DuplicateRate: status.Config.DuplicateRate,
+			ReorderRate:   status.Config.ReorderRate,
+			MinDelayMS:    status.Config.MinDelay.Milliseconds(),
+			MaxDelayMS:    status.Config.MaxDelay.Milliseconds(),
+			Partitioned:   partitioned,
+			Seed:          status.Config.Seed,
+		},
+		Stats: status.Stats,
+	}
+}
+
+// respondChaosError maps chaos mode errors to HTTP statuses
+func respondChaosError(w http.ResponseWriter, err error) {
+	if errors.Is(err, governance.ErrChaosDisabled) {
+		respondError(w, http.StatusForbidden, "chaos mode is disabled; start the otter with OTTER_CHAOS_ENABLED=true")
+		return
+	}
+	respondError(w, http.StatusBadRequest, err.Error())
+}
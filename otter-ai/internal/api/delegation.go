@@ -0,0 +1,59 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"otter-ai/internal/agent"
+)
+
+// DelegationRequest is the body of PUT /api/v1/governance/delegations
+type DelegationRequest struct {
+	Scope string `json:"scope"` // Rule scope pattern, e.g. "safety/*" or "*"
+	Mode  string `json:"mode"`  // owner, abstain or llm
+}
+
+// handleListDelegations lists the owner's standing vote policies
+func (s *Server) handleListDelegations(w http.ResponseWriter, r *http.Request) {
+	policies, err := s.agent.Delegations(r.Context())
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusInternalServerError, "")
+		return
+	}
+	respondJSON(w, http.StatusOK, policies)
+}
+
+// handleSetDelegation sets the standing vote policy for a scope
+func (s *Server) handleSetDelegation(w http.ResponseWriter, r *http.Request) {
+	var req DelegationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	var setBy string
+	if claims, ok := ClaimsFromContext(r.Context()); ok {
+		setBy = claims.UserID
+	}
+	policy, err := s.agent.SetDelegation(r.Context(), req.Scope, agent.DelegationMode(req.Mode), setBy)
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusInternalServerError, "")
+		return
+	}
+	respondJSON(w, http.StatusOK, policy)
+}
+
+// handleDeleteDelegation removes the policy set for a scope
+func (s *Server) handleDeleteDelegation(w http.ResponseWriter, r *http.Request) {
+	scope := r.URL.Query().Get("scope")
+	if scope == "" {
+		respondError(w, http.StatusBadRequest, "scope is required")
+		return
+	}
+	err := s.agent.RemoveDelegation(r.Context(), scope)
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusInternalServerError, "")
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"runtime/debug"
+
+	"otter-ai/internal/errs"
+	"otter-ai/internal/logging"
+)
+
+// kindStatus is the HTTP status for each kind of failure
+var kindStatus = map[error]int{
+	errs.ErrNotFound:       http.StatusNotFound,
+	errs.ErrUnauthorized:   http.StatusForbidden,
+	errs.ErrQuorum:         http.StatusConflict,
+	errs.ErrConflict:       http.StatusConflict,
+	errs.ErrInvalid:        http.StatusBadRequest,
+	errs.ErrLLMUnavailable: http.StatusServiceUnavailable,
+	errs.ErrUnavailable:    http.StatusServiceUnavailable,
+}
+
+// ErrorResponse is the body of an error response
+type ErrorResponse struct {
+	Error string `json:"error"`
+	// ErrorID is logged with a server error, so a report of one can be
+	// found in the logs
+	ErrorID string `json:"error_id,omitempty"`
+}
+
+// errorStatus returns the status for err's kind, or fallback for an error
+// of no kind
+func errorStatus(err error, fallback int) int {
+	if status, ok := kindStatus[errs.Kind(err)]; ok {
+		return status
+	}
+	return fallback
+}
+
+// respondErr answers with err's message and the status of its kind. An
+// error of no kind gets fallback, and message instead of its own when
+// message is set. Server errors are logged with an error ID the response
+// carries.
+func (s *Server) respondErr(w http.ResponseWriter, r *http.Request, err error, fallback int, message string) {
+	status := errorStatus(err, fallback)
+	resp := ErrorResponse{Error: err.Error()}
+	if errs.Kind(err) == nil && message != "" {
+		resp.Error = message
+	}
+	if status >= http.StatusInternalServerError {
+		resp.ErrorID = logging.NewRequestID()
+		s.log().ErrorContext(r.Context(), "request failed",
+			"path", r.URL.Path, "status", status, "error_id", resp.ErrorID, "error", err)
+	}
+	respondJSON(w, status, resp)
+}
+
+// recovered turns a panic in next into a 500 response with an error ID,
+// logged with the panic and its stack, so one bad request neither kills
+// the connection without an answer nor goes unexplained
+func (s *Server) recovered(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			p := recover()
+			if p == nil {
+				return
+			}
+			if err, ok := p.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(p)
+			}
+			id := logging.NewRequestID()
+			s.log().ErrorContext(r.Context(), "panic serving request",
+				"path", r.URL.Path, "error_id", id, "panic", p, "stack", string(debug.Stack()))
+			respondJSON(w, http.StatusInternalServerError, ErrorResponse{Error: "internal server error", ErrorID: id})
+		}()
+		next(w, r)
+	}
+}
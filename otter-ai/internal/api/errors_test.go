@@ -0,0 +1,110 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"otter-ai/internal/config"
+	"otter-ai/internal/errs"
+	"otter-ai/internal/governance"
+	"otter-ai/internal/logging"
+)
+
+func TestRespondErr(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		message    string
+		wantStatus int
+		wantError  string
+		wantID     bool
+	}{
+		{"not found", fmt.Errorf("%w: raft-9", governance.ErrRaftNotFound), "", http.StatusNotFound, "raft not found: raft-9", false},
+		{"unauthorized", governance.ErrUnknownSender, "", http.StatusForbidden, governance.ErrUnknownSender.Error(), false},
+		{"quorum", governance.ErrProposalClosed, "", http.StatusConflict, "proposal is closed", false},
+		{"llm unavailable", errs.New(errs.ErrLLMUnavailable, "no model"), "failed", http.StatusServiceUnavailable, "no model", true},
+		{"no kind", errors.New("disk on fire"), "failed to save", http.StatusInternalServerError, "failed to save", true},
+		{"no kind, own message", errors.New("bad input"), "", http.StatusTeapot, "bad input", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger, err := logging.New(&buf, config.LoggingConfig{Level: "debug"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := newTestServer("")
+			s.SetLogger(logger)
+
+			w := httptest.NewRecorder()
+			s.respondErr(w, httptest.NewRequest("GET", "/things", nil), tt.err, http.StatusTeapot, tt.message)
+			if tt.message == "failed to save" {
+				// The fallback applies only to errors of no kind
+				w = httptest.NewRecorder()
+				buf.Reset()
+				s.respondErr(w, httptest.NewRequest("GET", "/things", nil), tt.err, http.StatusInternalServerError, tt.message)
+			}
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			var resp ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Error != tt.wantError {
+				t.Errorf("error = %q, want %q", resp.Error, tt.wantError)
+			}
+			if (resp.ErrorID != "") != tt.wantID {
+				t.Errorf("error ID = %q, want one: %v", resp.ErrorID, tt.wantID)
+			}
+			if tt.wantID && !strings.Contains(buf.String(), "error_id="+resp.ErrorID) {
+				t.Errorf("log = %q, want the error ID", buf.String())
+			}
+		})
+	}
+}
+
+func TestRecovered(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, config.LoggingConfig{Level: "debug"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newTestServer("")
+	s.SetLogger(logger)
+
+	handler := s.recovered(func(w http.ResponseWriter, r *http.Request) {
+		panic("nil map")
+	})
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest("GET", "/things", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+	var resp ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Error != "internal server error" || resp.ErrorID == "" {
+		t.Errorf("response = %+v, want a generic error with an ID", resp)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "error_id="+resp.ErrorID) || !strings.Contains(out, "nil map") || !strings.Contains(out, "stack=") {
+		t.Errorf("log = %q, want the error ID, panic and stack", out)
+	}
+
+	defer func() {
+		if p := recover(); p != http.ErrAbortHandler {
+			t.Errorf("recovered %v, want ErrAbortHandler re-panicked", p)
+		}
+	}()
+	s.recovered(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	})(httptest.NewRecorder(), httptest.NewRequest("GET", "/things", nil))
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"otter-ai/internal/events"
+)
+
+// Event stream configuration
+const (
+	EventsWriteWait    = 10 * time.Second
+	EventsPongWait     = 60 * time.Second
+	EventsPingPeriod   = (EventsPongWait * 9) / 10
+	EventsMaxReadBytes = 512 // Clients only send control frames
+)
+
+// eventsUpgrader accepts any origin, matching the CORS policy of the REST API.
+// Access is still gated by requireAuth.
+var eventsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     func(*http.Request) bool { return true },
+}
+
+// SetEventBus sets the bus streamed to /api/v1/events subscribers
+func (s *Server) SetEventBus(bus *events.Bus) {
+	s.events = bus
+}
+
+// tokenFromQuery lets WebSocket clients, which cannot set headers from a
+// browser, pass their bearer token as a "token" query parameter.
+func tokenFromQuery(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if token := r.URL.Query().Get("token"); token != "" && r.Header.Get("Authorization") == "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		next(w, r)
+	}
+}
+
+// handleEvents upgrades to a WebSocket and pushes agent events as JSON
+// text frames. An optional comma-separated "types" query parameter limits
+// which event types are sent.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if s.events == nil {
+		respondError(w, http.StatusServiceUnavailable, "event stream not available")
+		return
+	}
+
+	var types []string
+	for _, t := range strings.Split(r.URL.Query().Get("types"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, t)
+		}
+	}
+
+	conn, err := eventsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already written an error response
+		return
+	}
+	defer conn.Close()
+
+	sub := s.events.Subscribe(events.DefaultSubscriberBuffer, types...)
+	defer sub.Close()
+
+	// Read until the client goes away so pongs and close frames are processed
+	closed := make(chan struct{})
+	conn.SetReadLimit(EventsMaxReadBytes)
+	conn.SetReadDeadline(time.Now().Add(EventsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(EventsPongWait))
+	})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(EventsPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case <-s.shutdownCh:
+			conn.SetWriteDeadline(time.Now().Add(EventsWriteWait))
+			conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"))
+			return
+		case event, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(EventsWriteWait))
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(EventsWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
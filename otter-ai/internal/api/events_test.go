@@ -0,0 +1,125 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"otter-ai/internal/events"
+)
+
+// dialEvents starts the events handler behind a test server and connects to it
+func dialEvents(t *testing.T, s *Server, query string) *websocket.Conn {
+	t.Helper()
+	srv := httptest.NewServer(tokenFromQuery(s.requireAuth(RoleMember, s.handleEvents)))
+	t.Cleanup(srv.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+query, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// waitForSubscriber blocks until the handler has subscribed to the bus
+func waitForSubscriber(t *testing.T, bus *events.Bus) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for bus.SubscriberCount() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("handler never subscribed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestHandleEvents_StreamsEvents(t *testing.T) {
+	s := newTestServer("")
+	bus := events.NewBus()
+	s.SetEventBus(bus)
+	conn := dialEvents(t, s, "?types=rule.adopted")
+	waitForSubscriber(t, bus)
+
+	bus.Publish(events.MemoryCreated, map[string]string{"id": "m1"})
+	bus.Publish(events.RuleAdopted, map[string]string{"rule_id": "r1"})
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	var event struct {
+		Type string            `json:"type"`
+		Data map[string]string `json:"data"`
+	}
+	if err := conn.ReadJSON(&event); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if event.Type != events.RuleAdopted || event.Data["rule_id"] != "r1" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+}
+
+func TestHandleEvents_Unsubscribes(t *testing.T) {
+	s := newTestServer("")
+	bus := events.NewBus()
+	s.SetEventBus(bus)
+	conn := dialEvents(t, s, "")
+	waitForSubscriber(t, bus)
+
+	conn.Close()
+
+	deadline := time.Now().Add(time.Second)
+	for bus.SubscriberCount() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("subscription should be released when the client disconnects")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestHandleEvents_NoBus(t *testing.T) {
+	s := newTestServer("")
+	req := httptest.NewRequest("GET", "/api/v1/events", nil)
+	w := httptest.NewRecorder()
+	s.handleEvents(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", w.Code)
+	}
+}
+
+func TestHandleEvents_RequiresAuth(t *testing.T) {
+	s := newTestServer("secret")
+	s.SetEventBus(events.NewBus())
+	srv := httptest.NewServer(tokenFromQuery(s.requireAuth(RoleMember, s.handleEvents)))
+	defer srv.Close()
+
+	_, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err == nil {
+		t.Fatal("expected dial to fail without a token")
+	}
+	if resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %+v", resp)
+	}
+}
+
+func TestTokenFromQuery(t *testing.T) {
+	var got string
+	handler := tokenFromQuery(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Authorization")
+	})
+
+	handler(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v1/events?token=abc", nil))
+	if got != "Bearer abc" {
+		t.Errorf("Authorization = %q, want Bearer abc", got)
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/events?token=abc", nil)
+	req.Header.Set("Authorization", "Bearer header")
+	handler(httptest.NewRecorder(), req)
+	if got != "Bearer header" {
+		t.Errorf("header token should take precedence, got %q", got)
+	}
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"otter-ai/internal/memory"
+)
+
+// handleExportMemories streams memories in the portable export format for
+// moving them to another vector backend or sharing them with another otter
+func (s *Server) handleExportMemories(w http.ResponseWriter, r *http.Request) {
+	mem := s.agent.GetMemory()
+	var opts memory.ExportOptions
+	if types := r.URL.Query().Get("type"); types != "" {
+		for _, name := range strings.Split(types, ",") {
+			memoryType := memory.MemoryType(strings.TrimSpace(name))
+			if _, ok := mem.Schema(memoryType); !ok {
+				respondError(w, http.StatusBadRequest, fmt.Sprintf("unknown memory type %q", memoryType))
+				return
+			}
+			opts.Types = append(opts.Types, memoryType)
+		}
+	}
+	opts.Embeddings, _ = strconv.ParseBool(r.URL.Query().Get("embeddings"))
+
+	filename := fmt.Sprintf("otter-memories-%s.jsonl", time.Now().UTC().Format("20060102-150405"))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	out := newNDJSONWriter(w)
+	n, err := mem.Export(r.Context(), w, opts)
+	if err != nil && r.Context().Err() == nil {
+		s.log().ErrorContext(r.Context(), "failed to export memories", "exported", n, "error", err)
+		out.Fail("failed to export memories")
+		return
+	}
+	out.Close()
+}
+
+// handleImportMemories stores the memories of a portable export sent as the
+// request body
+func (s *Server) handleImportMemories(w http.ResponseWriter, r *http.Request) {
+	reembed, _ := strconv.ParseBool(r.URL.Query().Get("reembed"))
+	result, err := s.agent.ImportMemories(r.Context(), r.Body, reembed)
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusInternalServerError, "failed to import memories")
+		return
+	}
+	respondJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"otter-ai/internal/agent"
+	"otter-ai/internal/config"
+	"otter-ai/internal/memory"
+	"otter-ai/internal/vectordb"
+)
+
+// newSQLiteTestServer returns a server whose memories are kept in SQLite
+func newSQLiteTestServer(t *testing.T) *Server {
+	t.Helper()
+	db, err := vectordb.NewSQLiteVectorDB(filepath.Join(t.TempDir(), "otter.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	ag := agent.New(agent.Config{Memory: memory.New(db), LLM: &mockLLMProvider{embedResp: []float32{0.1, 0.2, 0.3}}})
+	return NewServer(config.APIConfig{Passphrase: "secret123", RateLimit: 100, RateLimitWindow: time.Minute}, ag)
+}
+
+func TestHandleExportImportMemories(t *testing.T) {
+	src := newSQLiteTestServer(t)
+	ctx := context.Background()
+	for _, content := range []string{"kelp grows fast", "urchins eat kelp"} {
+		if err := src.agent.GetMemory().Store(ctx, &memory.MemoryRecord{Type: memory.MemoryTypeLongTerm, Content: content, Embedding: []float32{1, 0, 0}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	member, _ := src.jwtManager.GenerateRoleToken("bot", RoleMember, time.Hour)
+
+	req := httptest.NewRequest("GET", "/api/v1/memories/export?type=long_term", nil)
+	req.Header.Set("Authorization", "Bearer "+member)
+	w := httptest.NewRecorder()
+	src.handler().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("export status = %d: %s", w.Code, w.Body)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != NDJSONContentType {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	export := w.Body.String()
+	if lines := strings.Split(strings.TrimSpace(export), "\n"); len(lines) != 3 || strings.Contains(export, `"embedding"`) {
+		t.Errorf("export = %s, want a header and two memories without embeddings", export)
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/memories/export?type=dream", nil)
+	req.Header.Set("Authorization", "Bearer "+member)
+	w = httptest.NewRecorder()
+	src.handler().ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("unknown type: status = %d, want 400", w.Code)
+	}
+
+	dst := newSQLiteTestServer(t)
+	admin, _ := dst.jwtManager.GenerateToken("operator")
+	dstMember, _ := dst.jwtManager.GenerateRoleToken("bot", RoleMember, time.Hour)
+	post := func(token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v1/memories/import", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		dst.handler().ServeHTTP(w, req)
+		return w
+	}
+
+	if w := post(dstMember, export); w.Code != http.StatusForbidden {
+		t.Errorf("member import: status = %d, want 403", w.Code)
+	}
+	if w := post(admin, `{"id":"m1"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("missing header: status = %d, want 400", w.Code)
+	}
+
+	w = post(admin, export)
+	if w.Code != http.StatusOK {
+		t.Fatalf("import status = %d: %s", w.Code, w.Body)
+	}
+	var result memory.ImportResult
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Imported != 2 || result.ReEmbedded != 2 || len(result.Errors) != 0 {
+		t.Errorf("result = %+v, want two memories embedded by the importing otter", result)
+	}
+	if n, _ := dst.agent.GetMemory().Count(ctx, memory.MemoryTypeLongTerm); n != 2 {
+		t.Errorf("imported memories = %d, want 2", n)
+	}
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"otter-ai/internal/governance"
+	"otter-ai/internal/memory"
+)
+
+// ForgetRequest is the body of POST /api/v1/memories/forget. A memory must
+// match every field given, and at least one of ids, user_id, since, until
+// or match is required.
+type ForgetRequest struct {
+	IDs    []string            `json:"ids,omitempty"`
+	UserID string              `json:"user_id,omitempty"` // User the memories were recorded with, e.g. slack:U123
+	Since  time.Time           `json:"since,omitempty"`   // RFC 3339
+	Until  time.Time           `json:"until,omitempty"`   // RFC 3339
+	Match  string              `json:"match,omitempty"`   // Text the content contains, ignoring case
+	Types  []memory.MemoryType `json:"types,omitempty"`   // Default: long_term, musing and archived
+	Reason string              `json:"reason,omitempty"`
+	DryRun bool                `json:"dry_run,omitempty"` // Only report what would be erased
+}
+
+// handleForgetMemories permanently erases the memories matching the
+// request and records a tombstone in the audit log
+func (s *Server) handleForgetMemories(w http.ResponseWriter, r *http.Request) {
+	var req ForgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(req.Reason) > 1000 {
+		respondError(w, http.StatusBadRequest, "reason too long (max 1000 characters)")
+		return
+	}
+	if !req.Since.IsZero() && !req.Until.IsZero() && req.Until.Before(req.Since) {
+		respondError(w, http.StatusBadRequest, "until is before since")
+		return
+	}
+
+	erasure, err := s.agent.ForgetMemories(r.Context(), governance.ErasureRequest{
+		Criteria: memory.ForgetCriteria{
+			IDs:    req.IDs,
+			UserID: req.UserID,
+			Since:  req.Since,
+			Until:  req.Until,
+			Match:  req.Match,
+			Types:  req.Types,
+		},
+		RequestedBy: erasureRequester(r),
+		Reason:      req.Reason,
+		DryRun:      req.DryRun,
+	})
+	if err != nil {
+		s.respondErasureError(w, r, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, erasure)
+}
+
+// handleDeleteMemory permanently erases one memory and records a tombstone
+// in the audit log. A held memory is refused rather than reported.
+func (s *Server) handleDeleteMemory(w http.ResponseWriter, r *http.Request) {
+	req := governance.ErasureRequest{
+		Criteria:    memory.ForgetCriteria{IDs: []string{r.PathValue("id")}, Types: []memory.MemoryType{memoryTypeParam(r)}},
+		RequestedBy: erasureRequester(r),
+		Reason:      r.URL.Query().Get("reason"),
+		DryRun:      true,
+	}
+	preview, err := s.agent.ForgetMemories(r.Context(), req)
+	if err != nil {
+		s.respondErasureError(w, r, err)
+		return
+	}
+	switch {
+	case len(preview.Held) > 0:
+		respondError(w, http.StatusConflict, memory.ErrHeld.Error())
+		return
+	case len(preview.Erased) == 0:
+		respondError(w, http.StatusNotFound, "memory not found")
+		return
+	}
+
+	req.DryRun = false
+	erasure, err := s.agent.ForgetMemories(r.Context(), req)
+	if err != nil {
+		s.respondErasureError(w, r, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, erasure)
+}
+
+// erasureRequester names the caller an erasure is recorded for
+func erasureRequester(r *http.Request) string {
+	if claims, ok := ClaimsFromContext(r.Context()); ok {
+		return claims.UserID
+	}
+	return "api"
+}
+
+// respondErasureError maps an erasure error to a response
+func (s *Server) respondErasureError(w http.ResponseWriter, r *http.Request, err error) {
+	s.respondErr(w, r, err, http.StatusInternalServerError, "failed to erase memories")
+}
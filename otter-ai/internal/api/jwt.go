@@ -13,14 +13,51 @@ import (
 const (
 	JWTExpirationTime = 24 * time.Hour // Token expires after 24 hours
 	JWTIssuer         = "otter-ai"
+	MaxTokenLifetime  = 365 * 24 * time.Hour // Longest lifetime of a minted token
 )
 
+// Role is what a token's holder may do through the API. Each role may do
+// everything the roles below it may.
+type Role string
+
+const (
+	RoleObserver Role = "observer" // Read status and governance state
+	RoleMember   Role = "member"   // Also chat, read memories, propose and vote
+	RoleAdmin    Role = "admin"    // Also operator actions and minting tokens
+)
+
+var roleRanks = map[Role]int{RoleObserver: 1, RoleMember: 2, RoleAdmin: 3}
+
+// ParseRole parses a role name
+func ParseRole(name string) (Role, error) {
+	role := Role(name)
+	if roleRanks[role] == 0 {
+		return "", fmt.Errorf("role must be admin, member or observer")
+	}
+	return role, nil
+}
+
+// allows reports whether the role may use an endpoint requiring required
+func (r Role) allows(required Role) bool {
+	return roleRanks[r] >= roleRanks[required]
+}
+
 // Claims represents the JWT claims
 type Claims struct {
 	UserID string `json:"user_id"`
+	Role   Role   `json:"role,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// role returns the token's role. Tokens issued before roles existed were
+// only given for the host passphrase, so they act as admin.
+func (c *Claims) role() Role {
+	if c.Role == "" {
+		return RoleAdmin
+	}
+	return c.Role
+}
+
 // JWTManager handles JWT token operations
 type JWTManager struct {
 	secretKey []byte
@@ -38,12 +75,18 @@ func NewJWTManager(secret string) (*JWTManager, error) {
 	}, nil
 }
 
-// GenerateToken generates a new JWT token for a user
+// GenerateToken generates a new admin JWT token for a user
 func (m *JWTManager) GenerateToken(userID string) (string, error) {
+	return m.GenerateRoleToken(userID, RoleAdmin, JWTExpirationTime)
+}
+
+// GenerateRoleToken generates a JWT token limited to a role, valid for ttl
+func (m *JWTManager) GenerateRoleToken(userID string, role Role, ttl time.Duration) (string, error) {
 	claims := &Claims{
 		UserID: userID,
+		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(JWTExpirationTime)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 			Issuer:    JWTIssuer,
@@ -92,6 +92,42 @@ func TestValidateToken_TokenFields(t *testing.T) {
 	}
 }
 
+func TestGenerateRoleToken(t *testing.T) {
+	m, _ := NewJWTManager("secret")
+	token, err := m.GenerateRoleToken("dashboard", RoleObserver, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := m.ValidateToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserID != "dashboard" || claims.role() != RoleObserver {
+		t.Errorf("claims = %+v", claims)
+	}
+	if claims.role().allows(RoleMember) || !claims.role().allows(RoleObserver) {
+		t.Error("an observer should only be allowed observer endpoints")
+	}
+
+	// Tokens from before roles existed were issued for the passphrase
+	if legacy := (&Claims{UserID: "otter-user"}); legacy.role() != RoleAdmin {
+		t.Errorf("legacy role = %s, want admin", legacy.role())
+	}
+}
+
+func TestParseRole(t *testing.T) {
+	for _, name := range []string{"admin", "member", "observer"} {
+		if role, err := ParseRole(name); err != nil || string(role) != name {
+			t.Errorf("ParseRole(%q) = %q, %v", name, role, err)
+		}
+	}
+	for _, name := range []string{"", "root", "Admin"} {
+		if _, err := ParseRole(name); err == nil {
+			t.Errorf("expected %q to be rejected", name)
+		}
+	}
+}
+
 // --- generateRandomSecret ---
 
 func TestGenerateRandomSecret(t *testing.T) {
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"otter-ai/internal/logging"
+)
+
+// MaxRequestIDLength bounds request IDs accepted from callers
+const MaxRequestIDLength = 128
+
+// SetLogger sets the logger the server logs to
+func (s *Server) SetLogger(logger *slog.Logger) {
+	s.logger = logger
+}
+
+// log returns the server's logger
+func (s *Server) log() *slog.Logger {
+	if s.logger == nil {
+		return slog.Default()
+	}
+	return s.logger
+}
+
+// logged gives each request to a route an ID, taken from the caller's
+// X-Request-ID header when it sends one, carries it in the request context
+// for everything logged while serving the request, and echoes it in the
+// response
+func (s *Server) logged(route string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(logging.RequestIDHeader)
+		if id == "" || len(id) > MaxRequestIDLength {
+			id = logging.NewRequestID()
+		}
+		w.Header().Set(logging.RequestIDHeader, id)
+		ctx := logging.WithRequestID(r.Context(), id)
+
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next(sw, r.WithContext(ctx))
+
+		s.log().DebugContext(ctx, "request served",
+			"route", route, "path", r.URL.Path, "status", sw.status, "duration", time.Since(start))
+	}
+}
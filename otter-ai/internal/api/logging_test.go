@@ -0,0 +1,45 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"otter-ai/internal/config"
+	"otter-ai/internal/logging"
+)
+
+func TestLogged_RequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, config.LoggingConfig{Level: "debug"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newTestServer("")
+	s.SetLogger(logger)
+
+	var seen string
+	handler := s.logged("GET /things", func(w http.ResponseWriter, r *http.Request) {
+		seen = logging.RequestID(r.Context())
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	req := httptest.NewRequest("GET", "/things", nil)
+	req.Header.Set(logging.RequestIDHeader, "caller-id")
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if seen != "caller-id" || w.Header().Get(logging.RequestIDHeader) != "caller-id" {
+		t.Errorf("request ID = %q, echoed %q, want the caller's", seen, w.Header().Get(logging.RequestIDHeader))
+	}
+	if out := buf.String(); !strings.Contains(out, "request_id=caller-id") || !strings.Contains(out, "status=418") {
+		t.Errorf("log = %q, want the request ID and status", out)
+	}
+
+	w = httptest.NewRecorder()
+	handler(w, httptest.NewRequest("GET", "/things", nil))
+	if seen == "" || seen == "caller-id" || w.Header().Get(logging.RequestIDHeader) != seen {
+		t.Errorf("generated request ID = %q, echoed %q", seen, w.Header().Get(logging.RequestIDHeader))
+	}
+}
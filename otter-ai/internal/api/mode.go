@@ -0,0 +1,48 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"otter-ai/internal/agent"
+)
+
+// SetModeRequest is the body of PUT /api/v1/admin/mode. An omitted flag
+// keeps its current setting.
+type SetModeRequest struct {
+	ReadOnly    *bool  `json:"read_only,omitempty"`
+	Maintenance *bool  `json:"maintenance,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// handleGetMode reports whether the otter is read-only or in maintenance
+func (s *Server) handleGetMode(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, s.agent.Mode())
+}
+
+// handleSetMode switches the otter into or out of read-only and
+// maintenance mode
+func (s *Server) handleSetMode(w http.ResponseWriter, r *http.Request) {
+	var req SetModeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.ReadOnly == nil && req.Maintenance == nil {
+		respondError(w, http.StatusBadRequest, "read_only or maintenance is required")
+		return
+	}
+
+	current := s.agent.Mode()
+	mode := agent.Mode{ReadOnly: current.ReadOnly, Maintenance: current.Maintenance, Reason: req.Reason}
+	if req.ReadOnly != nil {
+		mode.ReadOnly = *req.ReadOnly
+	}
+	if req.Maintenance != nil {
+		mode.Maintenance = *req.Maintenance
+	}
+	if claims, ok := ClaimsFromContext(r.Context()); ok {
+		mode.ChangedBy = claims.UserID
+	}
+	respondJSON(w, http.StatusOK, s.agent.SetMode(r.Context(), mode))
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"otter-ai/internal/agent"
+)
+
+func TestHandleMode(t *testing.T) {
+	s := newTestServer("secret123")
+	admin, _ := s.jwtManager.GenerateToken("operator")
+	member, _ := s.jwtManager.GenerateRoleToken("bot", RoleMember, time.Hour)
+
+	do := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		s.handler().ServeHTTP(w, req)
+		return w
+	}
+
+	if w := do("PUT", "/api/v1/admin/mode", member, `{"maintenance": true}`); w.Code != http.StatusForbidden {
+		t.Errorf("member: status = %d, want 403", w.Code)
+	}
+	if w := do("PUT", "/api/v1/admin/mode", admin, `{}`); w.Code != http.StatusBadRequest {
+		t.Errorf("no flags: status = %d, want 400", w.Code)
+	}
+
+	w := do("PUT", "/api/v1/admin/mode", admin, `{"maintenance": true, "reason": "restoring a backup"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var mode agent.Mode
+	if err := json.NewDecoder(w.Body).Decode(&mode); err != nil {
+		t.Fatal(err)
+	}
+	if !mode.Maintenance || mode.ReadOnly || mode.ChangedBy != "operator" || mode.Reason != "restoring a backup" {
+		t.Errorf("mode = %+v", mode)
+	}
+
+	if w := do("POST", "/api/v1/chat", admin, `{"message": "hi"}`); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("chat in maintenance: status = %d, want 503", w.Code)
+	}
+	w = do("GET", "/readyz", "", "")
+	var readiness ReadinessResponse
+	if err := json.NewDecoder(w.Body).Decode(&readiness); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusOK || readiness.Status != "maintenance" || readiness.Mode == nil {
+		t.Errorf("readiness in maintenance: status = %d, body = %+v", w.Code, readiness)
+	}
+
+	if w := do("PUT", "/api/v1/admin/mode", admin, `{"read_only": true}`); w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	w = do("GET", "/api/v1/admin/mode", admin, "")
+	mode = agent.Mode{}
+	if err := json.NewDecoder(w.Body).Decode(&mode); err != nil {
+		t.Fatal(err)
+	}
+	if !mode.Maintenance || !mode.ReadOnly {
+		t.Errorf("mode = %+v, want maintenance kept and read-only added", mode)
+	}
+}
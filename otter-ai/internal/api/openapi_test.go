@@ -0,0 +1,132 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenAPIDocument_CoversRoutes(t *testing.T) {
+	server := newTestServer("")
+	doc := server.openAPIDocument()
+
+	if doc["openapi"] != "3.0.3" {
+		t.Errorf("openapi = %v, want 3.0.3", doc["openapi"])
+	}
+	paths := doc["paths"].(map[string]interface{})
+	for _, rt := range server.routes() {
+		item, ok := paths[rt.Path].(map[string]interface{})
+		if !ok {
+			t.Errorf("path %s missing from document", rt.Path)
+			continue
+		}
+		operation, ok := item[strings.ToLower(rt.Method)].(map[string]interface{})
+		if !ok {
+			t.Errorf("%s %s missing from document", rt.Method, rt.Path)
+			continue
+		}
+		_, secured := operation["security"]
+		if secured == rt.Public {
+			t.Errorf("%s %s: security = %v, want %v", rt.Method, rt.Path, secured, !rt.Public)
+		}
+	}
+}
+
+func TestOpenAPIDocument_Schemas(t *testing.T) {
+	server := newTestServer("")
+	doc := server.openAPIDocument()
+
+	// Round-trip through JSON to check the document serializes cleanly
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var decoded struct {
+		Paths      map[string]map[string]json.RawMessage
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]json.RawMessage
+			}
+		}
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	chat, ok := decoded.Components.Schemas["ChatRequest"]
+	if !ok {
+		t.Fatal("ChatRequest schema missing")
+	}
+	// Embedded ContextOptions fields are flattened like encoding/json does
+	for _, field := range []string{"message", "session_id", "no_memory", "no_governance", "scope"} {
+		if _, ok := chat.Properties[field]; !ok {
+			t.Errorf("ChatRequest missing property %q", field)
+		}
+	}
+
+	if _, ok := decoded.Paths["/api/v1/governance/rafts/{id}/digest"]["get"]; !ok {
+		t.Error("digest operation missing")
+	}
+	if !strings.Contains(string(data), `"in":"path"`) {
+		t.Error("path parameters should be documented")
+	}
+}
+
+func TestHandleOpenAPI(t *testing.T) {
+	server := newTestServer("secret")
+	handler := server.handler()
+
+	req := httptest.NewRequest("GET", "/api/v1/openapi.json", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200 without auth, got %d", w.Code)
+	}
+	var doc map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if _, ok := doc["paths"]; !ok {
+		t.Error("document has no paths")
+	}
+}
+
+func TestHandleSwaggerUI(t *testing.T) {
+	server := newTestServer("secret")
+
+	req := httptest.NewRequest("GET", "/api/v1/docs", nil)
+	w := httptest.NewRecorder()
+	server.handler().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") {
+		t.Errorf("Content-Type = %q", w.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(w.Body.String(), "/api/v1/openapi.json") {
+		t.Error("page should load the OpenAPI document")
+	}
+}
+
+func TestHandler_RequiresAuthOnProtectedRoutes(t *testing.T) {
+	server := newTestServer("secret")
+	handler := server.handler()
+
+	req := httptest.NewRequest("GET", "/api/v1/status", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/health", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200 for /health, got %d", w.Code)
+	}
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"otter-ai/internal/governance"
+)
+
+// Governance listing page sizes
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+// PageInfo places a page within a paginated listing
+type PageInfo struct {
+	Total      int    `json:"total"` // Items matching the filters, across every page
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`                // Position of the page's first item
+	NextCursor string `json:"next_cursor,omitempty"` // Pass as cursor for the next page; empty on the last page
+}
+
+// RulePage is a page of GET /api/v1/governance/rules
+type RulePage struct {
+	Items []*governance.Rule `json:"items"`
+	PageInfo
+}
+
+// ProposalPage is a page of GET /api/v1/governance/proposals
+type ProposalPage struct {
+	Items []*governance.Proposal `json:"items"`
+	PageInfo
+}
+
+// pageQuery documents the query parameters parsePageParams reads
+var pageQuery = []queryParam{
+	{"limit", fmt.Sprintf("Items per page (default: %d, max: %d)", DefaultPageLimit, MaxPageLimit)},
+	{"offset", "Items to skip; not allowed with cursor"},
+	{"cursor", "next_cursor of the previous page. Unlike offset, it does not skip or repeat items when the listing changes between pages"},
+	{"since", "Only items from this RFC 3339 timestamp or YYYY-MM-DD date on"},
+}
+
+// pageParams are a listing request's paging parameters
+type pageParams struct {
+	limit  int
+	offset int
+	cursor string // Decoded key of the previous page's last item
+	since  time.Time
+}
+
+// parsePageParams reads limit, offset, cursor and since
+func parsePageParams(r *http.Request) (pageParams, error) {
+	query := r.URL.Query()
+	params := pageParams{limit: DefaultPageLimit}
+	if value := query.Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return params, errors.New("limit must be a positive integer")
+		}
+		params.limit = min(n, MaxPageLimit)
+	}
+	if value := query.Get("offset"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return params, errors.New("offset must be a non-negative integer")
+		}
+		params.offset = n
+	}
+	if value := query.Get("cursor"); value != "" {
+		if query.Has("offset") {
+			return params, errors.New("cursor and offset cannot be combined")
+		}
+		key, err := base64.RawURLEncoding.DecodeString(value)
+		if err != nil || len(key) == 0 {
+			return params, errors.New("invalid cursor")
+		}
+		params.cursor = string(key)
+	}
+	if value := query.Get("since"); value != "" {
+		since, err := parseSinceParam(value)
+		if err != nil {
+			return params, errors.New("since must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+		}
+		params.since = since
+	}
+	return params, nil
+}
+
+// parseSinceParam parses an RFC 3339 timestamp, or a date meaning the start
+// of that day in UTC
+func parseSinceParam(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// page returns the bounds of the requested page of a listing whose items
+// have the given keys, in ascending order. A cursor is the key of the last
+// item already seen, so the page starts at the first key after it.
+func (p pageParams) page(keys []string) (int, int, PageInfo) {
+	start := min(p.offset, len(keys))
+	if p.cursor != "" {
+		start = sort.SearchStrings(keys, p.cursor)
+		if start < len(keys) && keys[start] == p.cursor {
+			start++
+		}
+	}
+	end := min(start+p.limit, len(keys))
+
+	info := PageInfo{Total: len(keys), Limit: p.limit, Offset: start}
+	if end < len(keys) && end > 0 {
+		info.NextCursor = base64.RawURLEncoding.EncodeToString([]byte(keys[end-1]))
+	}
+	return start, end, info
+}
+
+// ruleKey orders rules as governance.ListRules does
+func ruleKey(rule *governance.Rule) string {
+	return fmt.Sprintf("%s\x00%s\x00%010d\x00%s", rule.RaftID, rule.Scope, rule.Version, rule.RuleID)
+}
+
+// proposalKey orders proposals as governance.ListProposals does: newest
+// first, then by ID
+func proposalKey(proposal *governance.Proposal) string {
+	nanos := max(proposal.ProposedAt.UnixNano(), 0)
+	return fmt.Sprintf("%019d\x00%s", math.MaxInt64-nanos, proposal.ProposalID)
+}
@@ -1,12 +1,17 @@
 package api
 
 import (
-	"fmt"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"otter-ai/internal/config"
 )
 
 // Rate limiting constants
@@ -16,12 +21,35 @@ const (
 	CleanupInterval        = 5 * time.Minute // cleanup old entries
 )
 
+// RateLimitKey is what requests are counted against
+type RateLimitKey string
+
+const (
+	RateLimitByIP      RateLimitKey = "ip"      // The client's IP
+	RateLimitBySubject RateLimitKey = "subject" // A valid bearer token's subject, so one user's tokens share a limit
+	RateLimitByToken   RateLimitKey = "token"   // Each valid bearer token, such as a service's minted token
+)
+
+// rateClass groups endpoints that share a limit
+type rateClass string
+
+const (
+	rateClassDefault rateClass = "default"
+	rateClassChat    rateClass = "chat" // Chat messages and webhooks, which each cost an LLM call
+	rateClassRead    rateClass = "read" // Read-only requests
+)
+
 // RateLimiter implements a sliding window rate limiter
 type RateLimiter struct {
 	requests map[string]*clientRate
 	mu       sync.RWMutex
 	limit    int
 	window   time.Duration
+
+	classLimits map[rateClass]int // Limits overriding limit for a class
+	key         RateLimitKey
+	trusted     []*net.IPNet // Proxies whose forwarding headers are believed
+	tokens      *JWTManager  // Verifies bearer tokens for subject and token keys
 }
 
 // clientRate tracks requests for a single client
@@ -40,9 +68,11 @@ func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
 	}
 
 	rl := &RateLimiter{
-		requests: make(map[string]*clientRate),
-		limit:    limit,
-		window:   window,
+		requests:    make(map[string]*clientRate),
+		limit:       limit,
+		window:      window,
+		classLimits: make(map[rateClass]int),
+		key:         RateLimitByIP,
 	}
 
 	// Start cleanup goroutine
@@ -51,17 +81,89 @@ func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
 	return rl
 }
 
+// newServerRateLimiter creates the API server's rate limiter from its
+// configuration
+func newServerRateLimiter(cfg config.APIConfig, tokens *JWTManager) *RateLimiter {
+	rl := NewRateLimiter(cfg.RateLimit, cfg.RateLimitWindow)
+	rl.tokens = tokens
+	rl.Reconfigure(cfg)
+	return rl
+}
+
+// Reconfigure applies a configuration's limits, window, key and trusted
+// proxies to a running limiter. Requests already counted stay counted,
+// against the new window.
+func (rl *RateLimiter) Reconfigure(cfg config.APIConfig) {
+	classLimits := make(map[rateClass]int)
+	if cfg.RateLimitChat > 0 {
+		classLimits[rateClassChat] = cfg.RateLimitChat
+	}
+	if cfg.RateLimitRead > 0 {
+		classLimits[rateClassRead] = cfg.RateLimitRead
+	}
+	key := RateLimitByIP
+	if cfg.RateLimitKey != "" {
+		key = RateLimitKey(cfg.RateLimitKey)
+	}
+	trusted := parseTrustedProxies(cfg.TrustedProxies)
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.limit = DefaultRateLimit
+	if cfg.RateLimit > 0 {
+		rl.limit = cfg.RateLimit
+	}
+	rl.window = DefaultRateLimitWindow
+	if cfg.RateLimitWindow > 0 {
+		rl.window = cfg.RateLimitWindow
+	}
+	rl.classLimits = classLimits
+	rl.key = key
+	rl.trusted = trusted
+}
+
+// parseTrustedProxies parses CIDRs and single IPs, skipping invalid ones,
+// which config validation reports
+func parseTrustedProxies(proxies []string) []*net.IPNet {
+	var nets []*net.IPNet
+	for _, proxy := range proxies {
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			nets = append(nets, network)
+			continue
+		}
+		if ip := net.ParseIP(proxy); ip != nil {
+			bits := 8 * len(ip.To16())
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		}
+	}
+	return nets
+}
+
 // Allow checks if a request from the given identifier is allowed
 func (rl *RateLimiter) Allow(identifier string) bool {
+	rl.mu.RLock()
+	limit := rl.limit
+	rl.mu.RUnlock()
+	allowed, _ := rl.allow(identifier, limit)
+	return allowed
+}
+
+// allow records a request against key if it is under limit, and otherwise
+// returns how long until it would be
+func (rl *RateLimiter) allow(key string, limit int) (bool, time.Duration) {
 	now := time.Now()
 
 	rl.mu.Lock()
-	client, exists := rl.requests[identifier]
+	window := rl.window
+	client, exists := rl.requests[key]
 	if !exists {
 		client = &clientRate{
-			timestamps: make([]time.Time, 0, rl.limit),
+			timestamps: make([]time.Time, 0, limit),
 		}
-		rl.requests[identifier] = client
+		rl.requests[key] = client
 	}
 	rl.mu.Unlock()
 
@@ -69,7 +171,7 @@ func (rl *RateLimiter) Allow(identifier string) bool {
 	defer client.mu.Unlock()
 
 	// Remove timestamps outside the window
-	cutoff := now.Add(-rl.window)
+	cutoff := now.Add(-window)
 	validTimestamps := make([]time.Time, 0, len(client.timestamps))
 	for _, ts := range client.timestamps {
 		if ts.After(cutoff) {
@@ -78,14 +180,15 @@ func (rl *RateLimiter) Allow(identifier string) bool {
 	}
 	client.timestamps = validTimestamps
 
-	// Check if limit exceeded
-	if len(client.timestamps) >= rl.limit {
-		return false
+	// Check if limit exceeded; there is room again once enough of the
+	// oldest requests leave the window
+	if len(client.timestamps) >= limit {
+		return false, client.timestamps[len(client.timestamps)-limit].Add(window).Sub(now)
 	}
 
 	// Add current request
 	client.timestamps = append(client.timestamps, now)
-	return true
+	return true, 0
 }
 
 // cleanup periodically removes stale entries
@@ -112,14 +215,18 @@ func (rl *RateLimiter) cleanup() {
 // Middleware returns an HTTP middleware that applies rate limiting
 func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Use IP address as identifier
-		identifier := getClientIP(r)
-
-		if !rl.Allow(identifier) {
-			w.Header().Set("Content-Type", "application/json")
-			w.Header().Set("X-RateLimit-Limit", fmt.Sprintf("%d", rl.limit))
-			w.Header().Set("X-RateLimit-Window", rl.window.String())
-			w.WriteHeader(http.StatusTooManyRequests)
+		class := classifyRequest(r)
+		rl.mu.RLock()
+		limit, window, key, trusted := rl.limit, rl.window, rl.key, rl.trusted
+		if classLimit, ok := rl.classLimits[class]; ok {
+			limit = classLimit
+		}
+		rl.mu.RUnlock()
+
+		if allowed, retryAfter := rl.allow(string(class)+"|"+rl.identify(r, key, trusted), limit); !allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(max(int(math.Ceil(retryAfter.Seconds())), 1)))
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit))
+			w.Header().Set("X-RateLimit-Window", window.String())
 			respondError(w, http.StatusTooManyRequests, "rate limit exceeded")
 			return
 		}
@@ -128,40 +235,90 @@ func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
 	})
 }
 
-// getClientIP extracts the client IP from the request
-func getClientIP(r *http.Request) string {
+// classifyRequest returns the class of limit a request counts against
+func classifyRequest(r *http.Request) rateClass {
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v1/chat",
+		r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/api/v1/plugins/webhook/"):
+		return rateClassChat
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		return rateClassRead
+	}
+	return rateClassDefault
+}
+
+// identify returns who a request is counted against. Requests without a
+// valid bearer token are counted by IP whatever the key.
+func (rl *RateLimiter) identify(r *http.Request, key RateLimitKey, trusted []*net.IPNet) string {
+	if key != RateLimitByIP && rl.tokens != nil {
+		if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			token = strings.TrimSpace(token)
+			if claims, err := rl.tokens.ValidateToken(token); err == nil {
+				if key == RateLimitBySubject {
+					return "subject:" + claims.UserID
+				}
+				sum := sha256.Sum256([]byte(token))
+				return "token:" + hex.EncodeToString(sum[:16])
+			}
+		}
+	}
+	return "ip:" + getClientIP(r, trusted)
+}
+
+// getClientIP extracts the client IP from the request. Forwarding headers
+// are only believed when the request comes from a trusted proxy, since any
+// client can set them. X-Forwarded-For is read from the right, skipping
+// trusted proxies, as each proxy appends the address it received from.
+func getClientIP(r *http.Request, trusted []*net.IPNet) string {
+	remote := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		remote = host
+	}
+	if !isTrustedProxy(remote, trusted) {
+		return remote
+	}
+
 	// Check X-Forwarded-For header (for proxies)
 	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-		// Use the first IP in the list
-		parts := strings.Split(xff, ",")
-		if len(parts) > 0 {
-			ip := strings.TrimSpace(parts[0])
-			if parsed := net.ParseIP(ip); parsed != nil {
-				return parsed.String()
+		hops := strings.Split(xff, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := normalizeIP(hops[i])
+			if i == 0 || !isTrustedProxy(ip, trusted) {
+				return ip
 			}
-			if host, _, err := net.SplitHostPort(ip); err == nil {
-				return host
-			}
-			return ip
 		}
 	}
 
 	// Check X-Real-IP header (for proxies)
 	if xri := r.Header.Get("X-Real-IP"); xri != "" {
-		ip := strings.TrimSpace(xri)
-		if parsed := net.ParseIP(ip); parsed != nil {
-			return parsed.String()
-		}
-		if host, _, err := net.SplitHostPort(ip); err == nil {
-			return host
-		}
-		return ip
+		return normalizeIP(xri)
 	}
 
-	// Use RemoteAddr as fallback
-	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+	return remote
+}
+
+// normalizeIP strips whitespace and any port from a forwarded address
+func normalizeIP(value string) string {
+	ip := strings.TrimSpace(value)
+	if parsed := net.ParseIP(ip); parsed != nil {
+		return parsed.String()
+	}
+	if host, _, err := net.SplitHostPort(ip); err == nil {
 		return host
 	}
+	return ip
+}
 
-	return r.RemoteAddr
+// isTrustedProxy reports whether an address is one of the trusted proxies
+func isTrustedProxy(addr string, trusted []*net.IPNet) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, network := range trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
 }
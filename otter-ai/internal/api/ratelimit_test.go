@@ -5,6 +5,8 @@ import (
 	"net/http/httptest"
 	"testing"
 	"time"
+
+	"otter-ai/internal/config"
 )
 
 // --- NewRateLimiter ---
@@ -105,12 +107,92 @@ func TestMiddleware_RateLimited(t *testing.T) {
 	}
 }
 
+func TestMiddleware_RetryAfter(t *testing.T) {
+	rl := NewRateLimiter(1, 30*time.Second)
+	handler := rl.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("POST", "/api/v1/governance/vote", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d; want 429", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("Retry-After = %q; want 30", got)
+	}
+}
+
+func TestMiddleware_ClassLimits(t *testing.T) {
+	rl := newServerRateLimiter(config.APIConfig{RateLimit: 5, RateLimitWindow: time.Minute, RateLimitChat: 1, RateLimitRead: 2}, nil)
+	handler := rl.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(method, path string) int {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w.Code
+	}
+
+	if serve("POST", "/api/v1/chat") != http.StatusOK || serve("POST", "/api/v1/chat") != http.StatusTooManyRequests {
+		t.Error("expected the second chat message to be limited")
+	}
+	// Reads and other writes have their own budgets
+	if serve("GET", "/api/v1/status") != http.StatusOK || serve("GET", "/api/v1/status") != http.StatusOK {
+		t.Error("expected two reads to be allowed")
+	}
+	if serve("GET", "/api/v1/status") != http.StatusTooManyRequests {
+		t.Error("expected the third read to be limited")
+	}
+	if serve("POST", "/api/v1/governance/vote") != http.StatusOK {
+		t.Error("expected a vote to use the default limit")
+	}
+}
+
+func TestMiddleware_KeyBySubject(t *testing.T) {
+	tokens, _ := NewJWTManager("secret")
+	rl := newServerRateLimiter(config.APIConfig{RateLimit: 1, RateLimitWindow: time.Minute, RateLimitKey: "subject"}, tokens)
+	handler := rl.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(token string) int {
+		req := httptest.NewRequest("POST", "/api/v1/governance/vote", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	alice1, _ := tokens.GenerateRoleToken("alice", RoleMember, time.Hour)
+	alice2, _ := tokens.GenerateRoleToken("alice", RoleObserver, time.Hour)
+	bob, _ := tokens.GenerateRoleToken("bob", RoleMember, time.Hour)
+
+	// Users behind the same address have their own limits
+	if serve(alice1) != http.StatusOK || serve(bob) != http.StatusOK {
+		t.Error("expected alice and bob to be limited separately")
+	}
+	if serve(alice2) != http.StatusTooManyRequests {
+		t.Error("expected alice's tokens to share a limit")
+	}
+	// Forged tokens are counted by IP
+	if serve("forged") != http.StatusOK || serve("forged") != http.StatusTooManyRequests {
+		t.Error("expected invalid tokens to fall back to the IP limit")
+	}
+}
+
 // --- getClientIP ---
 
+// testProxies trusts httptest's default remote address and a second proxy
+var testProxies = parseTrustedProxies([]string{"192.0.2.0/24", "10.0.0.2"})
+
 func TestGetClientIP_XForwardedFor(t *testing.T) {
 	req := httptest.NewRequest("GET", "/", nil)
 	req.Header.Set("X-Forwarded-For", "10.0.0.1, 10.0.0.2")
-	ip := getClientIP(req)
+	ip := getClientIP(req, testProxies)
 	if ip != "10.0.0.1" {
 		t.Errorf("ip = %q; want 10.0.0.1", ip)
 	}
@@ -119,16 +201,33 @@ func TestGetClientIP_XForwardedFor(t *testing.T) {
 func TestGetClientIP_XForwardedFor_WithPort(t *testing.T) {
 	req := httptest.NewRequest("GET", "/", nil)
 	req.Header.Set("X-Forwarded-For", "10.0.0.1:8080")
-	ip := getClientIP(req)
+	ip := getClientIP(req, testProxies)
 	if ip != "10.0.0.1" {
 		t.Errorf("ip = %q; want 10.0.0.1", ip)
 	}
 }
 
+func TestGetClientIP_XForwardedFor_Spoofed(t *testing.T) {
+	// A client's own X-Forwarded-For is ignored without a trusted proxy
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "203.0.113.7:4444"
+	req.Header.Set("X-Forwarded-For", "10.0.0.1")
+	if ip := getClientIP(req, testProxies); ip != "203.0.113.7" {
+		t.Errorf("ip = %q; want 203.0.113.7", ip)
+	}
+
+	// Behind a trusted proxy, entries a client prepended are skipped
+	req = httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Forwarded-For", "1.1.1.1, 203.0.113.7")
+	if ip := getClientIP(req, testProxies); ip != "203.0.113.7" {
+		t.Errorf("ip = %q; want 203.0.113.7", ip)
+	}
+}
+
 func TestGetClientIP_XRealIP(t *testing.T) {
 	req := httptest.NewRequest("GET", "/", nil)
 	req.Header.Set("X-Real-IP", "192.168.1.1")
-	ip := getClientIP(req)
+	ip := getClientIP(req, testProxies)
 	if ip != "192.168.1.1" {
 		t.Errorf("ip = %q; want 192.168.1.1", ip)
 	}
@@ -137,7 +236,7 @@ func TestGetClientIP_XRealIP(t *testing.T) {
 func TestGetClientIP_XRealIP_WithPort(t *testing.T) {
 	req := httptest.NewRequest("GET", "/", nil)
 	req.Header.Set("X-Real-IP", "192.168.1.1:9090")
-	ip := getClientIP(req)
+	ip := getClientIP(req, testProxies)
 	if ip != "192.168.1.1" {
 		t.Errorf("ip = %q; want 192.168.1.1", ip)
 	}
@@ -146,7 +245,7 @@ func TestGetClientIP_XRealIP_WithPort(t *testing.T) {
 func TestGetClientIP_RemoteAddr(t *testing.T) {
 	req := httptest.NewRequest("GET", "/", nil)
 	req.RemoteAddr = "172.16.0.1:5555"
-	ip := getClientIP(req)
+	ip := getClientIP(req, testProxies)
 	if ip != "172.16.0.1" {
 		t.Errorf("ip = %q; want 172.16.0.1", ip)
 	}
@@ -155,8 +254,32 @@ func TestGetClientIP_RemoteAddr(t *testing.T) {
 func TestGetClientIP_RemoteAddr_NoPort(t *testing.T) {
 	req := httptest.NewRequest("GET", "/", nil)
 	req.RemoteAddr = "172.16.0.1"
-	ip := getClientIP(req)
+	ip := getClientIP(req, testProxies)
 	if ip != "172.16.0.1" {
 		t.Errorf("ip = %q; want 172.16.0.1", ip)
 	}
 }
+
+func TestReconfigure(t *testing.T) {
+	rl := newServerRateLimiter(config.APIConfig{RateLimit: 1, RateLimitWindow: time.Minute}, nil)
+	handler := rl.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/chat", nil))
+		return w
+	}
+
+	if serve().Code != http.StatusOK || serve().Code != http.StatusTooManyRequests {
+		t.Fatal("expected the second message to be limited")
+	}
+	rl.Reconfigure(config.APIConfig{RateLimit: 1, RateLimitChat: 3, RateLimitWindow: time.Hour})
+	if serve().Code != http.StatusOK || serve().Code != http.StatusOK {
+		t.Error("expected a raised chat limit to admit two more messages")
+	}
+	w := serve()
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("X-RateLimit-Limit") != "3" || w.Header().Get("X-RateLimit-Window") != "1h0m0s" {
+		t.Errorf("code = %d, headers = %v", w.Code, w.Header())
+	}
+}
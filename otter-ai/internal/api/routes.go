@@ -0,0 +1,325 @@
+package api
+
+import (
+	"net/http"
+
+	"otter-ai/internal/agent"
+	"otter-ai/internal/events"
+	"otter-ai/internal/governance"
+	"otter-ai/internal/llm/usage"
+	"otter-ai/internal/memory"
+	"otter-ai/internal/plugins"
+	"otter-ai/internal/vectordb"
+)
+
+// route describes one API endpoint. The same table registers handlers and
+// generates the OpenAPI document, so the two cannot drift apart.
+type route struct {
+	Method     string
+	Path       string
+	Handler    http.HandlerFunc
+	Public     bool // Skip requireAuth
+	Role       Role // Least role allowed; defaults to observer for GET and member otherwise
+	QueryToken bool // Accept the bearer token as a "token" query parameter
+	Tag        string
+	Summary    string
+	Query      []queryParam
+	Request    interface{} // Zero value of the JSON request body type, if any
+	Response   interface{} // Zero value of the JSON response body type
+	Status     int         // Success status; defaults to 200
+	Stream     bool        // Response is NDJSON, one Response value per line
+}
+
+// requiredRole returns the least role allowed to call the endpoint
+func (rt route) requiredRole() Role {
+	switch {
+	case rt.Role != "":
+		return rt.Role
+	case rt.Method == http.MethodGet:
+		return RoleObserver
+	}
+	return RoleMember
+}
+
+// queryParam documents an optional query string parameter
+type queryParam struct {
+	Name        string
+	Description string
+}
+
+// routes returns every endpoint served by the API
+func (s *Server) routes() []route {
+	return []route{
+		{Method: "GET", Path: "/healthz", Handler: s.handleHealth, Public: true, Tag: "System",
+			Summary: "Liveness check", Response: map[string]string{}},
+		{Method: "GET", Path: "/health", Handler: s.handleHealth, Public: true, Tag: "System",
+			Summary: "Liveness check; alias of /healthz", Response: map[string]string{}},
+		{Method: "GET", Path: "/readyz", Handler: s.handleReadiness, Public: true, Tag: "System",
+			Summary:  "Readiness check of the database, LLM, governance and plugins; 503 while a critical one is down",
+			Response: ReadinessResponse{}},
+		{Method: "GET", Path: "/api/v1/openapi.json", Handler: s.handleOpenAPI, Public: true, Tag: "System",
+			Summary: "OpenAPI document for this API", Response: map[string]interface{}{}},
+		{Method: "GET", Path: "/api/v1/docs", Handler: s.handleSwaggerUI, Public: true, Tag: "System",
+			Summary: "Swagger UI for this API"},
+		{Method: "POST", Path: "/api/v1/auth", Handler: s.handleAuth, Public: true, Tag: "Auth",
+			Summary: "Exchange the host passphrase for a JWT", Request: AuthRequest{}, Response: AuthResponse{}},
+		{Method: "POST", Path: "/api/v1/auth/tokens", Handler: s.handleMintToken, Role: RoleAdmin, Tag: "Auth",
+			Summary: "Mint a token limited to a role for another user or service", Request: MintTokenRequest{},
+			Response: MintTokenResponse{}, Status: http.StatusCreated},
+		{Method: "POST", Path: "/api/v1/admin/backup", Handler: s.handleBackup, Role: RoleAdmin, Tag: "Admin",
+			Summary: "Download an encrypted archive of the database, key material and configuration files", Request: BackupRequest{}},
+		{Method: "GET", Path: "/api/v1/admin/storage", Handler: s.handleStorage, Role: RoleAdmin, Tag: "Admin",
+			Summary: "SQLite database size, table sizes and row counts, and the latest integrity check, vacuum and checkpoint", Response: vectordb.StorageReport{}},
+		{Method: "GET", Path: "/api/v1/admin/mode", Handler: s.handleGetMode, Role: RoleAdmin, Tag: "Admin",
+			Summary: "Whether the otter is read-only or in maintenance mode, and who switched it", Response: agent.Mode{}},
+		{Method: "PUT", Path: "/api/v1/admin/mode", Handler: s.handleSetMode, Role: RoleAdmin, Tag: "Admin",
+			Summary: "Switch read-only mode (no memory writes, proposals or votes) and maintenance mode (chat answers 503)",
+			Request: SetModeRequest{}, Response: agent.Mode{}},
+		{Method: "GET", Path: "/api/v1/status", Handler: s.handleStatus, Tag: "System",
+			Summary: "Version, runtime metrics and raft topology", Response: StatusResponse{}},
+		{Method: "GET", Path: "/api/v1/capabilities", Handler: s.handleGetCapabilities, Tag: "System",
+			Summary: "Capability manifest given to the LLM: plugins, tools, memory, governance role and limitations", Response: agent.CapabilityManifest{}},
+		{Method: "GET", Path: "/api/v1/usage", Handler: s.handleGetUsage, Tag: "System",
+			Summary: "LLM token usage per day, provider and purpose, and the daily token budget", Response: usage.Report{},
+			Query: []queryParam{{"days", "Days of totals to return, today included (default: 7, max: 366)"}}},
+
+		{Method: "POST", Path: "/api/v1/chat", Handler: s.handleChat, Tag: "Chat",
+			Summary: "Send a message", Request: ChatRequest{}, Response: ChatResponse{}},
+		{Method: "POST", Path: "/api/v1/chat/clear", Handler: s.handleClearChat, Tag: "Chat",
+			Summary: "Clear a session's conversation history", Request: ClearChatRequest{}, Response: map[string]string{},
+			Query: []queryParam{{"session_id", "Session to clear (default: default)"}}},
+		{Method: "GET", Path: "/api/v1/chat/sessions", Handler: s.handleListSessions, Role: RoleMember, Tag: "Chat",
+			Summary: "List conversation sessions", Response: []agent.SessionInfo{}},
+		{Method: "GET", Path: "/api/v1/chat/sessions/{id}", Handler: s.handleGetSession, Role: RoleMember, Tag: "Chat",
+			Summary: "Get a session's recent messages and summary", Response: memory.SessionRecord{}},
+		{Method: "DELETE", Path: "/api/v1/chat/sessions/{id}", Handler: s.handleDeleteSession, Tag: "Chat",
+			Summary: "Delete a session and its stored history", Response: map[string]string{}},
+		{Method: "POST", Path: "/api/v1/plugins/webhook/{name}", Handler: s.handleWebhook, Public: true, Tag: "Chat",
+			Summary: "Send a JSON payload to a webhook endpoint, which maps it to a message, and get the reply. Authenticated with the endpoint's bearer token",
+			Request: map[string]interface{}{}, Response: WebhookResponse{}},
+		{Method: "GET", Path: "/api/v1/plugins/threads", Handler: s.handleListThreadLinks, Role: RoleMember, Tag: "Chat",
+			Summary: "Platform threads linked to another session than their own", Response: []plugins.ThreadLink{}},
+		{Method: "PUT", Path: "/api/v1/plugins/threads", Handler: s.handleLinkThread, Tag: "Chat",
+			Summary: "Make a platform thread continue a session; an empty session_id gives it its own again", Request: plugins.ThreadLink{}, Response: plugins.ThreadLink{}},
+		{Method: "GET", Path: "/api/v1/schedule", Handler: s.handleListScheduled, Tag: "Chat",
+			Summary: "Scheduled outbound messages, soonest first", Response: []memory.ScheduledMessage{},
+			Query: []queryParam{{"status", "Filter by status: pending, sent, failed or canceled"}}},
+		{Method: "POST", Path: "/api/v1/schedule", Handler: s.handleScheduleMessage, Tag: "Chat",
+			Summary: "Schedule a reminder sent through a plugin at deliver_at or after delay", Request: ScheduleRequest{}, Response: memory.ScheduledMessage{}, Status: http.StatusCreated},
+		{Method: "DELETE", Path: "/api/v1/schedule/{id}", Handler: s.handleCancelScheduled, Tag: "Chat",
+			Summary: "Cancel a pending scheduled message", Response: memory.ScheduledMessage{}},
+
+		{Method: "GET", Path: "/api/v1/memories", Handler: s.handleListMemories, Role: RoleMember, Tag: "Memory",
+			Summary: "List memories, newest first, optionally filtered", Response: []memory.MemoryRecord{},
+			Query: []queryParam{
+				{"type", "Memory type: long_term, short_term, musing, personality or archived (default: long_term)"},
+				{"since", "Only memories stored at or after this RFC 3339 timestamp or YYYY-MM-DD date"},
+				{"until", "Only memories stored at or before this RFC 3339 timestamp or YYYY-MM-DD date (end of day)"},
+				{"scope", "Only memories in this scope"},
+				{"min_importance", "Only memories at least this important (0-1)"},
+				{"meta.<key>", "Only memories whose metadata field <key> equals the value; true, false and numbers match booleans and numbers"},
+			}},
+		{Method: "GET", Path: "/api/v1/memories/stream", Handler: s.handleStreamMemories, Role: RoleMember, Tag: "Memory",
+			Summary: "Stream all memories of a type as NDJSON", Response: memory.MemoryRecord{}, Stream: true,
+			Query: []queryParam{{"type", "Memory type: long_term, short_term, musing, personality or archived (default: long_term)"}}},
+		{Method: "GET", Path: "/api/v1/memories/export", Handler: s.handleExportMemories, Role: RoleMember, Tag: "Memory",
+			Summary: "Export memories as versioned JSONL: an export header line, then one memory per line", Response: memory.ExportedMemory{}, Stream: true,
+			Query: []queryParam{
+				{"type", "Comma-separated memory types to export (default: long_term,musing)"},
+				{"embeddings", "Include embeddings; without them the importing otter re-embeds (default: false)"},
+			}},
+		{Method: "POST", Path: "/api/v1/memories/import", Handler: s.handleImportMemories, Role: RoleAdmin, Tag: "Memory",
+			Summary: "Import memories from a JSONL export sent as the request body; memories already stored are skipped", Response: memory.ImportResult{},
+			Query: []queryParam{{"reembed", "Embed every memory again instead of keeping exported embeddings (default: false)"}}},
+		{Method: "POST", Path: "/api/v1/memories/forget", Handler: s.handleForgetMemories, Role: RoleAdmin, Tag: "Memory",
+			Summary: "Permanently erase the memories matching every given criterion, with their versions, and record a signed tombstone in the audit log. Memories under legal hold are kept and listed as held",
+			Request: ForgetRequest{}, Response: governance.Erasure{}},
+		{Method: "GET", Path: "/api/v1/musings", Handler: s.handleListMusings, Role: RoleMember, Tag: "Memory",
+			Summary: "Reflection loop status and recent musings", Response: MusingsResponse{},
+			Query: []queryParam{{"limit", "Most recent musings to return (default: 10, max: 50)"}}},
+		{Method: "GET", Path: "/api/v1/personality", Handler: s.handleGetPersonality, Role: RoleMember, Tag: "Memory",
+			Summary: "Personality traits and how they are drifting", Response: agent.PersonalityStatus{}},
+		{Method: "GET", Path: "/api/v1/connectors", Handler: s.handleListConnectors, Tag: "Memory",
+			Summary: "External sources ingested into memory and what they ingested", Response: []agent.ConnectorStatus{}},
+		{Method: "POST", Path: "/api/v1/connectors/{name}/run", Handler: s.handleRunConnector, Tag: "Memory",
+			Summary: "Pull a connector now instead of waiting for its interval", Response: agent.IngestionResult{}},
+		{Method: "GET", Path: "/api/v1/memories/pinned", Handler: s.handleListPinned, Role: RoleMember, Tag: "Memory",
+			Summary: "List pinned memories", Response: []memory.MemoryRecord{}},
+		{Method: "DELETE", Path: "/api/v1/memories/pinned/{id}", Handler: s.handleUnpinMemory, Tag: "Memory",
+			Summary: "Unpin a memory (the memory itself is kept)", Response: memory.MemoryRecord{}},
+		{Method: "GET", Path: "/api/v1/memories/{id}/versions", Handler: s.handleListMemoryVersions, Role: RoleMember, Tag: "Memory",
+			Summary: "List a memory's versions, newest first, including a deleted memory's until purged", Response: []memory.MemoryVersion{},
+			Query: []queryParam{{"type", "Memory type (default: long_term)"}}},
+		{Method: "DELETE", Path: "/api/v1/memories/{id}", Handler: s.handleDeleteMemory, Role: RoleAdmin, Tag: "Memory",
+			Summary: "Permanently erase a memory, with its versions, and record a signed tombstone in the audit log. Memories under legal hold are refused (409)", Response: governance.Erasure{},
+			Query: []queryParam{
+				{"type", "Memory type (default: long_term)"},
+				{"reason", "Why the memory is erased, kept in the tombstone"},
+			}},
+		{Method: "POST", Path: "/api/v1/memories/{id}/restore", Handler: s.handleRestoreMemory, Role: RoleAdmin, Tag: "Memory",
+			Summary: "Undo the deletion of a memory within the recovery window", Response: memory.MemoryRecord{},
+			Query: []queryParam{{"type", "Memory type (default: long_term)"}}},
+		{Method: "POST", Path: "/api/v1/memories/{id}/revert", Handler: s.handleRevertMemory, Role: RoleAdmin, Tag: "Memory",
+			Summary: "Make an earlier version of a memory current again", Request: RevertMemoryRequest{}, Response: memory.MemoryRecord{},
+			Query: []queryParam{{"type", "Memory type (default: long_term)"}}},
+
+		{Method: "GET", Path: "/api/v1/governance/rules", Handler: s.handleListRules, Tag: "Governance",
+			Summary: "List a page of rules ordered by raft, scope and version. Without any parameter but raft_id, active rules are returned keyed by raft and scope, or by scope for one raft", Response: RulePage{},
+			Query: append([]queryParam{
+				{"raft_id", "Only this raft's rules"},
+				{"status", "Filter by status: active, inactive or all (default: active)"},
+			}, pageQuery...)},
+		{Method: "POST", Path: "/api/v1/governance/rules", Handler: s.handleProposeRule, Tag: "Governance",
+			Summary: "Propose a new rule", Request: ProposeRuleRequest{}, Response: governance.Proposal{}, Status: http.StatusCreated},
+		{Method: "GET", Path: "/api/v1/governance/rules/{id}/history", Handler: s.handleRuleHistory, Tag: "Governance",
+			Summary: "Adopted versions of a rule, oldest first", Response: []governance.RuleVersion{}},
+		{Method: "GET", Path: "/api/v1/governance/effects", Handler: s.handleListEffects, Tag: "Governance",
+			Summary: "Runtime settings changed by the active rules' effect lines, and the rules setting them", Response: []governance.ActiveEffect{}},
+		{Method: "GET", Path: "/api/v1/governance/proposals", Handler: s.handleListProposals, Tag: "Governance",
+			Summary: "List a page of proposals with vote tallies and voting deadlines, newest first", Response: ProposalPage{},
+			Query: append([]queryParam{
+				{"raft_id", "Only this raft's proposals"},
+				{"status", "Filter by status: open or closed"},
+			}, pageQuery...)},
+		{Method: "GET", Path: "/api/v1/governance/proposals/{id}", Handler: s.handleGetProposal, Tag: "Governance",
+			Summary: "Get a proposal with its vote tally and voting deadline", Response: governance.Proposal{}},
+		{Method: "POST", Path: "/api/v1/governance/proposals/{id}/post", Handler: s.handlePostProposal, Tag: "Governance",
+			Summary: "Post an open proposal to a plugin channel with inline YES/NO/ABSTAIN buttons", Request: PostProposalRequest{},
+			Response: map[string]string{}},
+		{Method: "POST", Path: "/api/v1/governance/evictions", Handler: s.handleProposeEviction, Tag: "Governance",
+			Summary: "Propose revoking a raft member", Request: ProposeEvictionRequest{}, Response: governance.Proposal{}, Status: http.StatusCreated},
+		{Method: "POST", Path: "/api/v1/governance/suspensions", Handler: s.handleProposeSuspension, Tag: "Governance",
+			Summary: "Propose suspending an active rule in an emergency; adopted by the fast-track quorum, it opens a full re-vote",
+			Request: ProposeSuspensionRequest{}, Response: governance.Proposal{}, Status: http.StatusCreated},
+		{Method: "GET", Path: "/api/v1/governance/suspensions", Handler: s.handleListSuspensions, Tag: "Governance",
+			Summary: "Rule suspensions and how their re-votes ended, newest first, optionally filtered by raft_id", Response: []governance.Suspension{}},
+		{Method: "POST", Path: "/api/v1/governance/suspensions/{rule_id}/revote", Handler: s.handleReopenRevote, Tag: "Governance",
+			Summary: "Reopen the re-vote on a suspended rule, e.g. after a restart lost it", Request: ReopenRevoteRequest{},
+			Response: governance.Proposal{}, Status: http.StatusCreated},
+		{Method: "POST", Path: "/api/v1/governance/vote", Handler: s.handleVote, Tag: "Governance",
+			Summary: "Vote on a proposal", Request: VoteRequest{}, Response: map[string]string{}},
+		{Method: "GET", Path: "/api/v1/governance/delegations", Handler: s.handleListDelegations, Tag: "Governance",
+			Summary: "Standing policies for how this otter votes on rule proposals by scope, ordered by scope", Response: []memory.DelegationPolicy{}},
+		{Method: "PUT", Path: "/api/v1/governance/delegations", Handler: s.handleSetDelegation, Role: RoleAdmin, Tag: "Governance",
+			Summary: "Set the vote policy for a scope: owner (wait for the owner), abstain, or llm (judged against the personality)",
+			Request: DelegationRequest{}, Response: memory.DelegationPolicy{}},
+		{Method: "DELETE", Path: "/api/v1/governance/delegations", Handler: s.handleDeleteDelegation, Role: RoleAdmin, Tag: "Governance",
+			Summary: "Remove the vote policy set for a scope", Response: map[string]string{},
+			Query: []queryParam{{"scope", "Scope the policy was set for"}}},
+		{Method: "POST", Path: "/api/v1/governance/join/challenge", Handler: s.handleJoinChallenge, Public: true, Tag: "Governance",
+			Summary: "Issue the nonce a peer otter signs before requesting membership", Request: JoinChallengeRequest{}, Response: governance.JoinChallenge{}},
+		{Method: "POST", Path: "/api/v1/governance/join", Handler: s.handleJoinRaft, Public: true, Tag: "Governance",
+			Summary: "Request membership of a raft (called by peer otters); uninvited requesters are held pending an admission vote (202)",
+			Request: JoinRaftRequest{}, Response: JoinRaftResponse{}},
+		{Method: "GET", Path: "/api/v1/governance/join-requests", Handler: s.handleListJoinRequests, Tag: "Governance",
+			Summary: "List join requests awaiting their admission vote, optionally filtered by raft_id", Response: []governance.PendingJoin{}},
+		{Method: "GET", Path: "/api/v1/governance/conflicts", Handler: s.handleListConflicts, Tag: "Governance",
+			Summary: "Rule conflicts found when joining rafts, newest first, optionally filtered by raft_id", Response: []governance.ConflictReport{}},
+		{Method: "GET", Path: "/api/v1/governance/negotiations", Handler: s.handleListNegotiations, Tag: "Governance",
+			Summary: "Negotiations started by rule conflicts, newest first", Response: []governance.NegotiationReport{},
+			Query: []queryParam{{"status", "Filter by status, e.g. resolved for compromises awaiting approval"}}},
+		{Method: "GET", Path: "/api/v1/governance/negotiations/{id}", Handler: s.handleGetNegotiation, Tag: "Governance",
+			Summary: "A negotiation with its conflicts, drafted compromise and full LLM transcript", Response: governance.NegotiationReport{}},
+		{Method: "POST", Path: "/api/v1/governance/negotiations/{id}/approve", Handler: s.handleApproveNegotiation, Role: RoleAdmin, Tag: "Governance",
+			Summary: "Approve a drafted compromise, optionally with edited text, and propose it to both rafts",
+			Request: ApproveNegotiationRequest{}, Response: governance.NegotiationReport{}},
+		{Method: "POST", Path: "/api/v1/governance/negotiations/{id}/reject", Handler: s.handleRejectNegotiation, Role: RoleAdmin, Tag: "Governance",
+			Summary: "Reject a drafted compromise; nothing is proposed and the join does not go ahead",
+			Request: RejectNegotiationRequest{}, Response: governance.NegotiationReport{}},
+		{Method: "GET", Path: "/api/v1/governance/capabilities", Handler: s.handleCapabilities, Public: true, Tag: "Governance",
+			Summary: "Signed protocol version, crypto suites and message types this otter supports", Response: governance.CapabilityDescriptor{}},
+		{Method: "GET", Path: "/api/v1/governance/chaos", Handler: s.handleGetChaos, Tag: "Governance",
+			Summary: "Faults chaos mode injects into federation deliveries, and how often they fired", Response: ChaosResponse{}},
+		{Method: "PUT", Path: "/api/v1/governance/chaos", Handler: s.handleSetChaos, Role: RoleAdmin, Tag: "Governance",
+			Summary: "Replace chaos mode's faults (only on otters started with OTTER_CHAOS_ENABLED)", Request: ChaosSettings{}, Response: ChaosResponse{}},
+		{Method: "POST", Path: "/api/v1/governance/federation", Handler: s.handleFederation, Public: true, Tag: "Governance",
+			Summary: "Receive a signed message from a raft peer", Request: governance.Envelope{}, Response: map[string]string{}},
+		{Method: "GET", Path: "/api/v1/governance/members", Handler: s.handleListMembers, Tag: "Governance",
+			Summary: "List raft members", Response: []map[string]interface{}{},
+			Query: []queryParam{{"raft_id", "Raft to list (default: this otter's own raft)"}}},
+		{Method: "GET", Path: "/api/v1/governance/tasks", Handler: s.handleListLLMTasks, Tag: "Governance",
+			Summary: "List governance tasks queued for LLM replay", Response: []governance.LLMTask{},
+			Query: []queryParam{{"status", "Filter by status: pending, running, completed or failed"}}},
+		{Method: "POST", Path: "/api/v1/governance/tasks/{id}/retry", Handler: s.handleRetryLLMTask, Tag: "Governance",
+			Summary: "Retry a pending or failed task immediately", Response: governance.LLMTask{}},
+		{Method: "GET", Path: "/api/v1/governance/rafts", Handler: s.handleListRafts, Tag: "Governance",
+			Summary: "Rafts this otter belongs or belonged to", Response: []governance.RaftSummary{},
+			Query: []queryParam{
+				{"archived", "true for archived rafts only, false for live ones"},
+				{"tag", "Only rafts with this tag"},
+			}},
+		{Method: "GET", Path: "/api/v1/governance/rafts/{id}/metadata", Handler: s.handleGetRaftMetadata, Tag: "Governance",
+			Summary: "A raft's name, description, purpose and tags", Response: governance.RaftMetadata{}},
+		{Method: "PUT", Path: "/api/v1/governance/rafts/{id}/metadata", Handler: s.handleUpdateRaftMetadata, Tag: "Governance",
+			Summary: "Change a raft's metadata: at once while the updater is its only member, otherwise by proposing a governance/metadata rule (202)",
+			Request: RaftMetadataRequest{}, Response: RaftMetadataResponse{}},
+		{Method: "POST", Path: "/api/v1/governance/rafts/{id}/invites", Handler: s.handleCreateInvite, Role: RoleAdmin, Tag: "Governance",
+			Summary: "Sign a time-limited invite token admitting an otter to a raft", Request: CreateInviteRequest{}, Response: InviteResponse{}, Status: http.StatusCreated},
+		{Method: "POST", Path: "/api/v1/governance/rafts/{id}/archive", Handler: s.handleArchiveRaft, Role: RoleAdmin, Tag: "Governance",
+			Summary: "Archive a raft, keeping its history read-only", Request: ArchiveRaftRequest{}, Response: map[string]string{}},
+		{Method: "POST", Path: "/api/v1/governance/rafts/{id}/leave", Handler: s.handleLeaveRaft, Role: RoleAdmin, Tag: "Governance",
+			Summary: "Leave a raft, telling its members and archiving it locally", Response: map[string]string{}},
+		{Method: "GET", Path: "/api/v1/governance/rafts/{id}/digest", Handler: s.handleRuleSetDigest, Public: true, Tag: "Governance",
+			Summary: "Merkle digest of a raft's adopted rules, signed for peers", Response: governance.RuleSetDigest{}},
+		{Method: "GET", Path: "/api/v1/governance/rafts/{id}/rules", Handler: s.handleRaftRules, Public: true, Tag: "Governance",
+			Summary: "Adopted rules of a raft, signed for peers", Response: []governance.Rule{}},
+		{Method: "GET", Path: "/api/v1/governance/rafts/{id}/keys", Handler: s.handleMemberKeys, Public: true, Tag: "Governance",
+			Summary: "Signing keys of a raft's members, signed for peers", Response: []governance.MemberKey{}},
+		{Method: "GET", Path: "/api/v1/governance/rafts/{id}/voting-policy", Handler: s.handleVotingPolicy, Tag: "Governance",
+			Summary: "Quorum, majority, super-majority and tie-break a raft decides proposals by; changed by adopting a governance/voting rule", Response: governance.VotingPolicy{}},
+		{Method: "POST", Path: "/api/v1/governance/rafts/{id}/reconcile", Handler: s.handleReconcileRules, Tag: "Governance",
+			Summary: "Pull missing rules from a peer", Request: ReconcileRequest{}, Response: governance.ReconcileResult{}},
+		{Method: "GET", Path: "/api/v1/governance/state", Handler: s.handleGovernanceState, Tag: "Governance",
+			Summary: "Rafts, members, active rules and open proposals as of a point in time", Response: governance.GovernanceState{},
+			Query: []queryParam{{"as_of", "RFC 3339 timestamp or YYYY-MM-DD date (default: now)"}}},
+		{Method: "GET", Path: "/api/v1/governance/audit", Handler: s.handleAuditLog, Tag: "Governance",
+			Summary: "Stream the governance audit log as NDJSON, oldest first", Response: governance.AuditEntry{}, Stream: true,
+			Query: []queryParam{
+				{"raft_id", "Only entries for this raft"},
+				{"since", "RFC 3339 timestamp or YYYY-MM-DD date"},
+				{"until", "RFC 3339 timestamp or YYYY-MM-DD date"},
+			}},
+		{Method: "GET", Path: "/api/v1/governance/audit/verify", Handler: s.handleVerifyAudit, Tag: "Governance",
+			Summary: "Verify the audit log's hash chain and signatures", Response: governance.AuditVerification{}},
+		{Method: "GET", Path: "/api/v1/governance/holds", Handler: s.handleListHolds, Tag: "Governance",
+			Summary: "List legal holds, newest first", Response: []governance.Hold{},
+			Query: []queryParam{{"status", "Filter by status: active or released (default: both)"}}},
+		{Method: "POST", Path: "/api/v1/governance/holds", Handler: s.handlePlaceHold, Role: RoleAdmin, Tag: "Governance",
+			Summary: "Place a legal hold on a memory, proposal or audit range", Request: PlaceHoldRequest{},
+			Response: governance.Hold{}, Status: http.StatusCreated},
+		{Method: "POST", Path: "/api/v1/governance/holds/{id}/release", Handler: s.handleReleaseHold, Role: RoleAdmin, Tag: "Governance",
+			Summary: "Approve releasing a legal hold as a provisioned approver; two distinct approvers other than the placer release it",
+			Request: ReleaseHoldRequest{}, Response: governance.Hold{}},
+		{Method: "GET", Path: "/api/v1/governance/onboarding", Handler: s.handleListOnboardings, Tag: "Governance",
+			Summary: "List new members' onboarding progress", Response: []memory.OnboardingRecord{}},
+		{Method: "POST", Path: "/api/v1/governance/onboarding/{id}/steps/{step}/complete", Handler: s.handleCompleteOnboardingStep,
+			Tag: "Governance", Summary: "Mark an onboarding step done and send the next one", Response: memory.OnboardingRecord{}},
+		{Method: "GET", Path: "/api/v1/governance/drift", Handler: s.handleDriftReports, Tag: "Governance",
+			Summary: "Latest rule drift reports per raft and peer", Response: []governance.DriftReport{},
+			Query: []queryParam{{"refresh", "Set to true to check peers now"}}},
+
+		{Method: "GET", Path: "/api/v1/events", Handler: s.handleEvents, Role: RoleMember, QueryToken: true, Tag: "Events",
+			Summary: "WebSocket stream of agent events (upgrade required)", Response: events.Event{},
+			Query: []queryParam{
+				{"types", "Comma-separated event types to receive"},
+				{"token", "Bearer token for clients that cannot set headers"},
+			}},
+	}
+}
+
+// handler builds the routed, authenticated handler for every endpoint
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+	for _, rt := range s.routes() {
+		h := rt.Handler
+		if !rt.Public {
+			h = s.requireAuth(rt.requiredRole(), h)
+		}
+		if rt.QueryToken {
+			h = tokenFromQuery(h)
+		}
+		route := rt.Method + " " + rt.Path
+		mux.HandleFunc(route, traced(route, s.logged(route, s.recovered(h))))
+	}
+	return mux
+}
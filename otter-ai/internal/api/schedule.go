@@ -0,0 +1,86 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"otter-ai/internal/agent"
+	"otter-ai/internal/memory"
+)
+
+// ScheduleRequest is the body of POST /api/v1/schedule. The message is
+// delivered at deliver_at, or after delay when no time is given.
+type ScheduleRequest struct {
+	Platform  string    `json:"platform"`
+	ChannelID string    `json:"channel_id,omitempty"`
+	ThreadID  string    `json:"thread_id,omitempty"` // Thread in the channel to post in
+	UserID    string    `json:"user_id,omitempty"`   // Direct message recipient when there is no channel
+	Content   string    `json:"content"`
+	DeliverAt time.Time `json:"deliver_at,omitempty"`
+	Delay     string    `json:"delay,omitempty"` // e.g. "2h"
+}
+
+// handleListScheduled lists scheduled messages, soonest first
+func (s *Server) handleListScheduled(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", memory.ScheduledPending, memory.ScheduledSent, memory.ScheduledFailed, memory.ScheduledCanceled:
+	default:
+		respondError(w, http.StatusBadRequest, "status must be pending, sent, failed or canceled")
+		return
+	}
+	messages, err := s.agent.ScheduledMessages(r.Context(), status)
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusInternalServerError, "")
+		return
+	}
+	respondJSON(w, http.StatusOK, messages)
+}
+
+// handleScheduleMessage schedules a reminder
+func (s *Server) handleScheduleMessage(w http.ResponseWriter, r *http.Request) {
+	var req ScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.DeliverAt.IsZero() {
+		delay, err := time.ParseDuration(req.Delay)
+		if err != nil || delay <= 0 {
+			respondError(w, http.StatusBadRequest, "deliver_at or a positive delay is required")
+			return
+		}
+		req.DeliverAt = time.Now().Add(delay)
+	}
+
+	var createdBy string
+	if claims, ok := ClaimsFromContext(r.Context()); ok {
+		createdBy = claims.UserID
+	}
+	msg, err := s.agent.ScheduleMessage(r.Context(), &memory.ScheduledMessage{
+		Kind:      agent.ScheduleReminder,
+		Platform:  req.Platform,
+		ChannelID: req.ChannelID,
+		ThreadID:  req.ThreadID,
+		UserID:    req.UserID,
+		Content:   req.Content,
+		DeliverAt: req.DeliverAt,
+		CreatedBy: createdBy,
+	})
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusInternalServerError, "")
+		return
+	}
+	respondJSON(w, http.StatusCreated, msg)
+}
+
+// handleCancelScheduled cancels a pending scheduled message
+func (s *Server) handleCancelScheduled(w http.ResponseWriter, r *http.Request) {
+	msg, err := s.agent.CancelScheduled(r.Context(), r.PathValue("id"))
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusInternalServerError, "")
+		return
+	}
+	respondJSON(w, http.StatusOK, msg)
+}
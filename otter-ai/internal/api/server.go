@@ -2,18 +2,30 @@ package api
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/subtle"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"otter-ai/internal/agent"
+	"otter-ai/internal/backup"
 	"otter-ai/internal/config"
+	"otter-ai/internal/events"
 	"otter-ai/internal/governance"
+	"otter-ai/internal/llm"
+	"otter-ai/internal/llm/usage"
 	"otter-ai/internal/memory"
+	"otter-ai/internal/version"
 )
 
 // Constants for API server configuration
@@ -30,6 +42,11 @@ type Server struct {
 	server      *http.Server
 	jwtManager  *JWTManager
 	rateLimiter *RateLimiter
+	events      *events.Bus    // Streamed to /api/v1/events subscribers
+	backup      *backup.Source // Archived by POST /api/v1/admin/backup; nil when unset
+	shutdownCh  chan struct{}  // Closed on shutdown to end event streams
+	stopOnce    sync.Once
+	logger      *slog.Logger
 }
 
 // NewServer creates a new API server
@@ -37,42 +54,25 @@ func NewServer(cfg config.APIConfig, agent *agent.Agent) *Server {
 	// Initialize JWT manager
 	jwtManager, err := NewJWTManager(cfg.JWTSecret)
 	if err != nil {
-		log.Printf("Warning: Failed to initialize JWT manager: %v", err)
+		slog.Warn("failed to initialize JWT manager", "error", err)
 	}
 
 	// Initialize rate limiter
-	rateLimiter := NewRateLimiter(cfg.RateLimit, cfg.RateLimitWindow)
+	rateLimiter := newServerRateLimiter(cfg, jwtManager)
 
 	return &Server{
 		config:      cfg,
 		agent:       agent,
 		jwtManager:  jwtManager,
 		rateLimiter: rateLimiter,
+		shutdownCh:  make(chan struct{}),
 	}
 }
 
 // Start starts the API server
 func (s *Server) Start() error {
-	mux := http.NewServeMux()
-
-	// Health check (no auth required)
-	mux.HandleFunc("/health", s.handleHealth)
-
-	// Authentication endpoint
-	mux.HandleFunc("POST /api/v1/auth", s.handleAuth)
-
-	// Protected endpoints - require authentication
-	mux.HandleFunc("POST /api/v1/chat", s.requireAuth(s.handleChat))
-	mux.HandleFunc("POST /api/v1/chat/clear", s.requireAuth(s.handleClearChat))
-	mux.HandleFunc("GET /api/v1/memories", s.requireAuth(s.handleListMemories))
-	mux.HandleFunc("GET /api/v1/governance/rules", s.requireAuth(s.handleListRules))
-	mux.HandleFunc("POST /api/v1/governance/rules", s.requireAuth(s.handleProposeRule))
-	mux.HandleFunc("POST /api/v1/governance/vote", s.requireAuth(s.handleVote))
-	mux.HandleFunc("POST /api/v1/governance/join", s.requireAuth(s.handleJoinRaft))
-	mux.HandleFunc("GET /api/v1/governance/members", s.requireAuth(s.handleListMembers))
-
 	// Apply middleware chain: rate limiting -> CORS
-	handler := corsMiddleware(s.rateLimiter.Middleware(mux))
+	handler := corsMiddleware(s.rateLimiter.Middleware(s.handler()))
 
 	s.server = &http.Server{
 		Addr:         fmt.Sprintf("%s:%d", s.config.Host, s.config.Port),
@@ -82,35 +82,132 @@ func (s *Server) Start() error {
 		IdleTimeout:  ServerIdleTimeout,
 	}
 
-	log.Printf("API server listening on %s", s.server.Addr)
+	s.log().Info("API server listening", "addr", s.server.Addr)
 	return s.server.ListenAndServe()
 }
 
 // Shutdown gracefully shuts down the server
 func (s *Server) Shutdown(ctx context.Context) error {
+	// Hijacked WebSocket connections are not tracked by http.Server
+	s.stopOnce.Do(func() { close(s.shutdownCh) })
 	return s.server.Shutdown(ctx)
 }
 
-// handleHealth handles health check requests
+// Reload applies the reloadable parts of a new API configuration, the rate
+// limits, to the running server. The address and secrets stay as they are.
+func (s *Server) Reload(cfg config.APIConfig) {
+	s.rateLimiter.Reconfigure(cfg)
+}
+
+// handleHealth handles liveness checks. It answers as long as the process
+// serves HTTP, whatever the state of the otter's dependencies.
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{
-		"status": "healthy",
-		"time":   time.Now().Format(time.RFC3339),
+		"status":  "healthy",
+		"time":    time.Now().Format(time.RFC3339),
+		"version": version.Version,
 	})
 }
 
+// ReadinessResponse reports whether this otter can serve requests, and
+// the state of each component it depends on
+type ReadinessResponse struct {
+	Status     string                           `json:"status"` // ready, maintenance or not_ready
+	Components map[string]agent.ComponentHealth `json:"components"`
+	Mode       *agent.Mode                      `json:"mode,omitempty"` // Set unless the mode is normal
+}
+
+// handleReadiness checks the database, LLM, governance and plugins, and
+// answers 503 while a critical one is down so load balancers route around
+// this otter. An otter in maintenance mode stays ready, so it is not
+// restarted while being investigated.
+func (s *Server) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	readiness := s.agent.Readiness(r.Context())
+	resp := ReadinessResponse{Status: "ready", Components: readiness.Components}
+	code := http.StatusOK
+	if mode := s.agent.Mode(); !mode.Normal() {
+		resp.Mode = &mode
+		if mode.Maintenance {
+			resp.Status = "maintenance"
+		}
+	}
+	if !readiness.Ready {
+		resp.Status, code = "not_ready", http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// StatusResponse describes this otter for operators and fleet tooling
+type StatusResponse struct {
+	OtterID       string                   `json:"otter_id"`
+	Version       string                   `json:"version"`
+	StartedAt     time.Time                `json:"started_at"`
+	UptimeSeconds int64                    `json:"uptime_seconds"`
+	Health        map[string]interface{}   `json:"health"`
+	Rafts         []governance.RaftSummary `json:"rafts"`
+	// Request outcomes, circuit breaker state and queue load per LLM role
+	LLM map[string]llm.ResilienceStats `json:"llm,omitempty"`
+	// Mode is whether the otter is read-only or in maintenance
+	Mode agent.Mode `json:"mode"`
+}
+
+// handleStatus reports version, runtime metrics and raft topology
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	status := StatusResponse{
+		Version:       version.Version,
+		StartedAt:     s.agent.StartedAt(),
+		UptimeSeconds: int64(time.Since(s.agent.StartedAt()).Seconds()),
+		Health:        s.agent.HealthSnapshot(),
+		Rafts:         []governance.RaftSummary{},
+		LLM:           s.agent.LLMStats(),
+		Mode:          s.agent.Mode(),
+	}
+
+	if gov := s.agent.GetGovernance(); gov != nil {
+		status.OtterID = gov.GetID()
+		status.Rafts = gov.RaftSummaries()
+	}
+
+	respondJSON(w, http.StatusOK, status)
+}
+
+// ChatRequest is the body of POST /api/v1/chat
+type ChatRequest struct {
+	Message              string `json:"message"`
+	SessionID            string `json:"session_id,omitempty"` // Optional: defaults to the shared session
+	Platform             string `json:"platform,omitempty"`   // Optional: plugin the message arrived through, selecting its channel profile
+	ChannelID            string `json:"channel_id,omitempty"` // Optional: channel on that platform
+	ThreadID             string `json:"thread_id,omitempty"`  // Optional: thread in that channel reminders are posted in
+	agent.ContextOptions        // Optional: no_memory, no_governance, scope for this turn
+}
+
+// ChatResponse is the reply to a chat message
+type ChatResponse struct {
+	Response  string `json:"response"`
+	SessionID string `json:"session_id"`
+	Blocked   bool   `json:"blocked,omitempty"` // A chat hook blocked the turn; Response is its reply
+}
+
 // handleChat handles chat requests
 func (s *Server) handleChat(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		Message string `json:"message"`
-	}
+	var req ChatRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respondError(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
+	if req.SessionID == "" {
+		req.SessionID = agent.DefaultSessionID
+	}
+	if err := agent.ValidateSessionID(req.SessionID); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	if req.Message == "" {
 		respondError(w, http.StatusBadRequest, "message is required")
 		return
@@ -121,300 +218,1791 @@ func (s *Server) handleChat(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response, err := s.agent.ProcessMessage(r.Context(), req.Message)
+	if err := req.ContextOptions.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.ChannelID != "" && req.Platform == "" {
+		respondError(w, http.StatusBadRequest, "channel_id requires a platform")
+		return
+	}
+	if req.ThreadID != "" && req.ChannelID == "" {
+		respondError(w, http.StatusBadRequest, "thread_id requires a channel_id")
+		return
+	}
+
+	ctx := agent.WithSession(r.Context(), req.SessionID)
+	ctx = agent.WithContextOptions(ctx, req.ContextOptions)
+	if req.Platform != "" {
+		ctx = agent.WithThread(ctx, req.Platform, req.ChannelID, req.ThreadID)
+	}
+	if claims, ok := ClaimsFromContext(r.Context()); ok {
+		ctx = agent.WithUser(ctx, claims.UserID)
+	}
+	response, err := s.agent.ProcessMessage(ctx, req.Message)
+	var blocked *agent.BlockedError
+	if errors.As(err, &blocked) {
+		respondJSON(w, http.StatusOK, ChatResponse{
+			Response:  blocked.Reply,
+			SessionID: req.SessionID,
+			Blocked:   true,
+		})
+		return
+	}
 	if err != nil {
-		log.Printf("Error processing message: %v", err)
-		respondError(w, http.StatusInternalServerError, "failed to process message")
+		s.respondErr(w, r, err, http.StatusInternalServerError, "failed to process message")
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]string{
-		"response": response,
+	respondJSON(w, http.StatusOK, ChatResponse{
+		Response:  response,
+		SessionID: req.SessionID,
 	})
 }
 
-// handleClearChat clears the conversation history
+// ClearChatRequest is the optional body of POST /api/v1/chat/clear
+type ClearChatRequest struct {
+	SessionID string `json:"session_id,omitempty"`
+}
+
+// handleClearChat clears the conversation history of a session.
+// The session may be given as a session_id query parameter or in an
+// optional JSON body; it defaults to the shared session.
 func (s *Server) handleClearChat(w http.ResponseWriter, r *http.Request) {
-	s.agent.ClearConversation()
+	var req ClearChatRequest
+	req.SessionID = r.URL.Query().Get("session_id")
+	if req.SessionID == "" && r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+			respondError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+	if req.SessionID == "" {
+		req.SessionID = agent.DefaultSessionID
+	}
+	if err := agent.ValidateSessionID(req.SessionID); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := s.agent.ClearSession(r.Context(), req.SessionID); err != nil {
+		s.log().ErrorContext(r.Context(), "failed to clear session", "session_id", req.SessionID, "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to clear conversation")
+		return
+	}
+
 	respondJSON(w, http.StatusOK, map[string]string{
-		"message": "Conversation history cleared",
+		"message":    "Conversation history cleared",
+		"session_id": req.SessionID,
 	})
 }
 
-// handleListMemories handles listing memories
-func (s *Server) handleListMemories(w http.ResponseWriter, r *http.Request) {
-	memType := r.URL.Query().Get("type")
-	if memType == "" {
-		memType = string(memory.MemoryTypeLongTerm)
+// handleListSessions lists known conversation sessions
+func (s *Server) handleListSessions(w http.ResponseWriter, r *http.Request) {
+	sessions := s.agent.Sessions()
+	if sessions == nil {
+		respondJSON(w, http.StatusOK, []agent.SessionInfo{})
+		return
 	}
 
-	memories, err := s.agent.GetMemory().List(r.Context(), memory.MemoryType(memType), 50, 0)
+	infos, err := sessions.List(r.Context())
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "failed to list memories")
+		s.respondErr(w, r, err, http.StatusInternalServerError, "failed to list sessions")
 		return
 	}
+	if infos == nil {
+		infos = []agent.SessionInfo{}
+	}
 
-	respondJSON(w, http.StatusOK, memories)
-}
-
-// Memories and musings can only be created/modified by the otter agent internally.
-// No public API endpoints are provided for creating or deleting memories.
-
-// handleListRules handles listing active governance rules
-func (s *Server) handleListRules(w http.ResponseWriter, r *http.Request) {
-	rules := s.agent.GetGovernance().GetActiveRules()
-	respondJSON(w, http.StatusOK, rules)
+	respondJSON(w, http.StatusOK, infos)
 }
 
-// handleProposeRule handles proposing a new rule
-func (s *Server) handleProposeRule(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		RaftID     string `json:"raft_id"` // Optional: defaults to otter's own raft
-		Scope      string `json:"scope"`
-		Body       string `json:"body"`
-		ProposedBy string `json:"proposed_by"`
-		BaseRuleID string `json:"base_rule_id,omitempty"`
+// handleGetSession returns the stored history and summary of a session
+func (s *Server) handleGetSession(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := agent.ValidateSessionID(id); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "invalid request body")
+	sessions := s.agent.Sessions()
+	if sessions == nil {
+		respondError(w, http.StatusNotFound, "session not found")
 		return
 	}
 
-	if req.Scope == "" || req.Body == "" || req.ProposedBy == "" {
-		respondError(w, http.StatusBadRequest, "scope, body, and proposed_by are required")
+	record, err := sessions.Snapshot(r.Context(), id)
+	if err != nil {
+		respondError(w, http.StatusNotFound, "session not found")
 		return
 	}
 
-	if len(req.Body) > 1000 {
-		respondError(w, http.StatusBadRequest, "rule body too long (max 1000 characters)")
+	respondJSON(w, http.StatusOK, record)
+}
+
+// handleDeleteSession deletes a session and its persisted history
+func (s *Server) handleDeleteSession(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := agent.ValidateSessionID(id); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	if len(req.Scope) > 100 {
-		respondError(w, http.StatusBadRequest, "scope too long (max 100 characters)")
+	if err := s.agent.ClearSession(r.Context(), id); err != nil {
+		s.log().ErrorContext(r.Context(), "failed to delete session", "session_id", id, "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to delete session")
 		return
 	}
 
-	// Default to otter's own raft if not specified
-	raftID := req.RaftID
-	if raftID == "" {
-		raftID = s.agent.GetGovernance().GetID() // Use otter's own raft ID
-	}
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message":    "Session deleted",
+		"session_id": id,
+	})
+}
 
-	rule := &governance.Rule{
-		Scope:      req.Scope,
-		Body:       req.Body,
-		ProposedBy: req.ProposedBy,
-		BaseRuleID: req.BaseRuleID,
-		Timestamp:  time.Now(),
+// handleListMemories handles listing memories
+func (s *Server) handleListMemories(w http.ResponseWriter, r *http.Request) {
+	filter, err := memoryFilterParams(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
-	proposal, err := s.agent.GetGovernance().ProposeRule(r.Context(), raftID, rule)
+	memories, err := s.agent.GetMemory().ListFiltered(r.Context(), memoryTypeParam(r), filter, 50, 0)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		s.respondErr(w, r, err, http.StatusInternalServerError, "failed to list memories")
 		return
 	}
+	if memories == nil {
+		memories = []memory.MemoryRecord{}
+	}
 
-	respondJSON(w, http.StatusCreated, proposal)
+	respondJSON(w, http.StatusOK, memories)
 }
 
-// handleVote handles voting on a proposal
-func (s *Server) handleVote(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		ProposalID string `json:"proposal_id"`
-		VoterID    string `json:"voter_id"`
-		Vote       string `json:"vote"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "invalid request body")
+// handleListPinned lists memories the user pinned through chat
+func (s *Server) handleListPinned(w http.ResponseWriter, r *http.Request) {
+	pinned, err := s.agent.ListPinned(r.Context())
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusInternalServerError, "failed to list pinned memories")
 		return
 	}
-
-	if req.ProposalID == "" || req.VoterID == "" || req.Vote == "" {
-		respondError(w, http.StatusBadRequest, "proposal_id, voter_id, and vote are required")
-		return
+	if pinned == nil {
+		pinned = []memory.MemoryRecord{}
 	}
 
-	vote := governance.VoteType(req.Vote)
-	if vote != governance.VoteYes && vote != governance.VoteNo && vote != governance.VoteAbstain {
-		respondError(w, http.StatusBadRequest, "vote must be YES, NO, or ABSTAIN")
+	respondJSON(w, http.StatusOK, pinned)
+}
+
+// handleUnpinMemory removes the pin from a memory; the memory itself is kept
+func (s *Server) handleUnpinMemory(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		respondError(w, http.StatusBadRequest, "memory id is required")
 		return
 	}
 
-	err := s.agent.GetGovernance().Vote(r.Context(), req.ProposalID, req.VoterID, governance.VoteType(req.Vote))
+	record, err := s.agent.UnpinMemory(r.Context(), id)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondError(w, http.StatusNotFound, "memory not found")
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]string{
-		"status": "vote recorded",
-	})
+	respondJSON(w, http.StatusOK, record)
 }
 
-// handleJoinRaft handles membership induction requests from peer otters.
-func (s *Server) handleJoinRaft(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		RaftID      string `json:"raft_id"`
-		RequesterID string `json:"requester_id"`
-		PublicKey   string `json:"public_key"`
+// memoryTypeParam reads the type query parameter, defaulting to long-term
+func memoryTypeParam(r *http.Request) memory.MemoryType {
+	if memType := r.URL.Query().Get("type"); memType != "" {
+		return memory.MemoryType(memType)
 	}
+	return memory.MemoryTypeLongTerm
+}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "invalid request body")
-		return
+// memoryMetadataParamPrefix marks query parameters filtering on a metadata
+// field, e.g. meta.source=slack
+const memoryMetadataParamPrefix = "meta."
+
+// memoryFilterParams reads the since, until, scope, min_importance and
+// meta.<key> query parameters. Metadata values of true, false or a number
+// match booleans and numbers; anything else matches strings.
+func memoryFilterParams(r *http.Request) (memory.SearchFilter, error) {
+	query := r.URL.Query()
+	filter := memory.SearchFilter{Scope: query.Get("scope")}
+
+	for name, target := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if value := query.Get(name); value != "" {
+			t, err := parseTimeParam(value)
+			if err != nil {
+				return filter, fmt.Errorf("%s must be an RFC 3339 timestamp or a YYYY-MM-DD date", name)
+			}
+			*target = t
+		}
+	}
+	if value := query.Get("min_importance"); value != "" {
+		importance, err := strconv.ParseFloat(value, 32)
+		if err != nil {
+			return filter, errors.New("min_importance must be a number")
+		}
+		filter.MinImportance = float32(importance)
 	}
 
-	if req.RaftID == "" || req.RequesterID == "" || req.PublicKey == "" {
-		respondError(w, http.StatusBadRequest, "raft_id, requester_id, and public_key are required")
+	for name, values := range query {
+		key := strings.TrimPrefix(name, memoryMetadataParamPrefix)
+		if key == name || len(values) == 0 {
+			continue
+		}
+		if filter.Metadata == nil {
+			filter.Metadata = make(map[string]interface{})
+		}
+		filter.Metadata[key] = metadataParamValue(values[0])
+	}
+	return filter, nil
+}
+
+// metadataParamValue types a metadata filter value from its query string
+func metadataParamValue(value string) interface{} {
+	if value == "true" || value == "false" {
+		return value == "true"
+	}
+	if n, err := strconv.ParseFloat(value, 64); err == nil {
+		return n
+	}
+	return value
+}
+
+// respondVersionError maps memory versioning errors to HTTP statuses
+func (s *Server) respondVersionError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, memory.ErrVersioningUnsupported) {
+		respondError(w, http.StatusNotImplemented, err.Error())
 		return
 	}
+	s.respondErr(w, r, err, http.StatusInternalServerError, "failed to update memory")
+}
 
-	publicKey, err := hex.DecodeString(req.PublicKey)
+// handleListMemoryVersions lists a memory's stored versions
+func (s *Server) handleListMemoryVersions(w http.ResponseWriter, r *http.Request) {
+	versions, err := s.agent.GetMemory().Versions(r.Context(), r.PathValue("id"), memoryTypeParam(r))
 	if err != nil {
-		respondError(w, http.StatusBadRequest, "public_key must be valid hex")
+		s.respondVersionError(w, r, err)
 		return
 	}
-
-	if err := s.agent.GetGovernance().RequestJoin(r.Context(), req.RaftID, req.RequesterID, publicKey); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+	if len(versions) == 0 {
+		respondError(w, http.StatusNotFound, "memory not found")
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]string{
-		"status": "join accepted",
-	})
+	respondJSON(w, http.StatusOK, versions)
 }
 
-// handleListMembers handles listing raft members
-func (s *Server) handleListMembers(w http.ResponseWriter, r *http.Request) {
-	raftID := r.URL.Query().Get("raft_id")
-	if raftID == "" {
-		// Default to otter's own raft
-		raftID = s.agent.GetGovernance().GetID()
+// handleRestoreMemory undoes the soft delete of a memory
+func (s *Server) handleRestoreMemory(w http.ResponseWriter, r *http.Request) {
+	id, memType := r.PathValue("id"), memoryTypeParam(r)
+	if err := s.agent.GetMemory().Restore(r.Context(), id, memType); err != nil {
+		s.respondVersionError(w, r, err)
+		return
 	}
 
-	// Get members from the specified raft
-	members, err := s.agent.GetGovernance().GetRaftMembers(raftID)
+	record, err := s.agent.GetMemory().Get(r.Context(), id, memType)
 	if err != nil {
-		respondError(w, http.StatusNotFound, err.Error())
+		s.respondErr(w, r, err, http.StatusInternalServerError, "failed to load restored memory")
 		return
 	}
+	respondJSON(w, http.StatusOK, record)
+}
 
-	// Format members for response
-	response := make([]interface{}, 0, len(members))
-	for _, member := range members {
-		response = append(response, map[string]interface{}{
-			"id":          member.ID,
-			"state":       string(member.State),
-			"joined_at":   member.JoinedAt,
-			"last_seen":   member.LastSeenAt,
-			"inducted_by": member.InductedBy,
-		})
-	}
-
-	respondJSON(w, http.StatusOK, response)
+// RevertMemoryRequest names the version a memory is reverted to
+type RevertMemoryRequest struct {
+	Version int `json:"version"`
 }
 
-// handleAuth handles authentication requests
-func (s *Server) handleAuth(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		Passphrase string `json:"passphrase"`
+// handleRevertMemory makes an earlier version of a memory current again
+func (s *Server) handleRevertMemory(w http.ResponseWriter, r *http.Request) {
+	var req RevertMemoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Version < 1 {
+		respondError(w, http.StatusBadRequest, "a positive version is required")
+		return
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "invalid request body")
+	id, memType := r.PathValue("id"), memoryTypeParam(r)
+	if err := s.agent.GetMemory().Revert(r.Context(), id, memType, req.Version); err != nil {
+		s.respondVersionError(w, r, err)
 		return
 	}
 
-	// If no passphrase is configured, allow access without JWT
-	if s.config.Passphrase == "" {
-		respondJSON(w, http.StatusOK, map[string]interface{}{
-			"authenticated": true,
-			"token":         "",
-		})
+	record, err := s.agent.GetMemory().Get(r.Context(), id, memType)
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusInternalServerError, "failed to load reverted memory")
 		return
 	}
+	respondJSON(w, http.StatusOK, record)
+}
 
-	// Validate passphrase
-	if req.Passphrase != s.config.Passphrase {
-		respondError(w, http.StatusUnauthorized, "invalid passphrase")
-		return
+// Musing list limits
+const (
+	DefaultMusingsLimit = 10
+	MaxMusingsLimit     = 50
+)
+
+// MusingsResponse reports the reflection loop and its latest musings
+type MusingsResponse struct {
+	Status  agent.MusingStatus    `json:"status"`
+	Musings []memory.MemoryRecord `json:"musings"`
+}
+
+// handleListMusings reports the reflection loop's status and recent musings
+func (s *Server) handleListMusings(w http.ResponseWriter, r *http.Request) {
+	limit := DefaultMusingsLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			respondError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = min(n, MaxMusingsLimit)
 	}
 
-	// Generate JWT token
-	token, err := s.jwtManager.GenerateToken("otter-user")
+	musings, err := s.agent.ListMusings(r.Context(), limit)
 	if err != nil {
-		log.Printf("Error generating JWT token: %v", err)
-		respondError(w, http.StatusInternalServerError, "failed to generate token")
+		s.respondErr(w, r, err, http.StatusInternalServerError, "failed to list musings")
 		return
 	}
+	if musings == nil {
+		musings = []memory.MemoryRecord{}
+	}
 
-	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"authenticated": true,
-		"token":         token,
-		"expires_in":    int(JWTExpirationTime.Seconds()),
-	})
+	respondJSON(w, http.StatusOK, MusingsResponse{Status: s.agent.MusingStatus(), Musings: musings})
 }
 
-// requireAuth is a middleware that checks for valid authentication
-func (s *Server) requireAuth(next http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		// If no passphrase is configured, allow all requests
-		if s.config.Passphrase == "" {
-			next(w, r)
-			return
-		}
+// handleGetPersonality reports the agent's personality traits. Traits are
+// seeded at startup and only drift internally, so this is read-only.
+func (s *Server) handleGetPersonality(w http.ResponseWriter, r *http.Request) {
+	status, err := s.agent.Personality(r.Context())
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusInternalServerError, "failed to load personality")
+		return
+	}
+	respondJSON(w, http.StatusOK, status)
+}
 
-		// Check Authorization header
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			respondError(w, http.StatusUnauthorized, "missing authorization header")
-			return
-		}
+// handleListConnectors lists the configured connectors
+func (s *Server) handleListConnectors(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, s.agent.Connectors())
+}
 
-		// Expect "Bearer <token>"
-		const prefix = "Bearer "
-		if len(authHeader) < len(prefix) || !strings.HasPrefix(authHeader, prefix) {
-			respondError(w, http.StatusUnauthorized, "invalid authorization format")
+// handleRunConnector pulls one connector now
+func (s *Server) handleRunConnector(w http.ResponseWriter, r *http.Request) {
+	result, err := s.agent.RunConnector(r.Context(), r.PathValue("name"))
+	if err != nil {
+		if errors.Is(err, agent.ErrConnectorNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
 			return
 		}
+		s.log().ErrorContext(r.Context(), "connector failed", "connector", r.PathValue("name"), "error", err)
+		respondError(w, http.StatusBadGateway, "connector failed: "+err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, result)
+}
 
-		token := strings.TrimSpace(authHeader[len(prefix):])
+// DefaultUsageDays is how many days of totals a usage report covers by default
+const DefaultUsageDays = 7
 
-		// Validate JWT token
-		claims, err := s.jwtManager.ValidateToken(token)
-		if err != nil {
-			respondError(w, http.StatusUnauthorized, "invalid or expired token")
+// handleGetUsage reports LLM token usage and the daily budget
+func (s *Server) handleGetUsage(w http.ResponseWriter, r *http.Request) {
+	days := DefaultUsageDays
+	if value := r.URL.Query().Get("days"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			respondError(w, http.StatusBadRequest, "days must be a positive integer")
 			return
 		}
+		days = min(n, usage.MaxReportDays)
+	}
 
-		// Token is valid, add claims to request context if needed
-		_ = claims // Can be used for user identification in the future
-
-		next(w, r)
+	report, err := s.agent.Usage(r.Context(), days)
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusInternalServerError, "failed to load usage")
+		return
 	}
+	respondJSON(w, http.StatusOK, report)
 }
 
-// corsMiddleware adds CORS headers
-// WARNING: Allows all origins (*) - restrict in production
-func corsMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// TODO: Restrict to specific origins in production
-		// For now, allow all for development convenience
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+// handleGetCapabilities returns the capability manifest injected into the
+// chat system prompt
+func (s *Server) handleGetCapabilities(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, s.agent.Capabilities(r.Context()))
+}
 
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
+// Memories and musings can only be created/modified by the otter agent internally.
+// No public API endpoints are provided for creating or deleting memories;
+// pins are created through chat and can only be cleared here.
+
+// handleListRules handles listing active governance rules. With a raft_id
+// query parameter it returns that raft's rules keyed by scope; otherwise all
+// active rules keyed by raft ID and then scope.
+//
+// Without paging or filter parameters other than raft_id, active rules are
+// returned keyed by raft and scope, or by scope for one raft, as peers
+// running older versions fetch them when joining.
+func (s *Server) handleListRules(w http.ResponseWriter, r *http.Request) {
+	gov := s.agent.GetGovernance()
+	query := r.URL.Query()
+	if !query.Has("limit") && !query.Has("offset") && !query.Has("cursor") && !query.Has("status") && !query.Has("since") {
+		if raftID := query.Get("raft_id"); raftID != "" {
+			respondJSON(w, http.StatusOK, gov.GetActiveRulesForRaft(raftID))
 			return
 		}
+		respondJSON(w, http.StatusOK, gov.GetActiveRules())
+		return
+	}
 
-		next.ServeHTTP(w, r)
+	params, err := parsePageParams(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	status := governance.RuleStatus(query.Get("status"))
+	switch status {
+	case "", governance.RuleStatusActive, governance.RuleStatusInactive, governance.RuleStatusAll:
+	default:
+		respondError(w, http.StatusBadRequest, "status must be active, inactive or all")
+		return
+	}
+
+	rules := gov.ListRules(governance.RuleFilter{RaftID: query.Get("raft_id"), Status: status, Since: params.since})
+	keys := make([]string, len(rules))
+	for i, rule := range rules {
+		keys[i] = ruleKey(rule)
+	}
+	start, end, info := params.page(keys)
+	respondJSON(w, http.StatusOK, RulePage{Items: append([]*governance.Rule{}, rules[start:end]...), PageInfo: info})
+}
+
+// ProposeRuleRequest is the body of POST /api/v1/governance/rules
+type ProposeRuleRequest struct {
+	RaftID     string `json:"raft_id,omitempty"` // Optional: defaults to otter's own raft
+	Scope      string `json:"scope"`
+	Body       string `json:"body"`
+	ProposedBy string `json:"proposed_by"`
+	BaseRuleID string `json:"base_rule_id,omitempty"`
+	// Optional Go duration such as "72h" after which an undecided proposal is
+	// closed as rejected; defaults to OTTER_PROPOSAL_VOTING_PERIOD
+	VotingPeriod string `json:"voting_period,omitempty"`
+}
+
+// handleProposeRule handles proposing a new rule
+func (s *Server) handleProposeRule(w http.ResponseWriter, r *http.Request) {
+	var req ProposeRuleRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.Scope == "" || req.Body == "" || req.ProposedBy == "" {
+		respondError(w, http.StatusBadRequest, "scope, body, and proposed_by are required")
+		return
+	}
+
+	if len(req.Body) > 1000 {
+		respondError(w, http.StatusBadRequest, "rule body too long (max 1000 characters)")
+		return
+	}
+
+	if len(req.Scope) > 100 {
+		respondError(w, http.StatusBadRequest, "scope too long (max 100 characters)")
+		return
+	}
+
+	var votingPeriod time.Duration
+	if req.VotingPeriod != "" {
+		var err error
+		if votingPeriod, err = time.ParseDuration(req.VotingPeriod); err != nil {
+			respondError(w, http.StatusBadRequest, "voting_period must be a duration such as 72h")
+			return
+		}
+		if err := governance.ValidateVotingPeriod(votingPeriod); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	// Default to otter's own raft if not specified
+	raftID := req.RaftID
+	if raftID == "" {
+		raftID = s.agent.GetGovernance().GetID() // Use otter's own raft ID
+	}
+
+	rule := &governance.Rule{
+		Scope:      req.Scope,
+		Body:       req.Body,
+		ProposedBy: req.ProposedBy,
+		BaseRuleID: req.BaseRuleID,
+		Timestamp:  time.Now(),
+	}
+
+	proposal, err := s.agent.GetGovernance().ProposeRuleWithVotingPeriod(r.Context(), raftID, rule, votingPeriod)
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusBadRequest, "")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, proposal)
+}
+
+// handleRuleHistory returns the amendment chain of a rule
+func (s *Server) handleRuleHistory(w http.ResponseWriter, r *http.Request) {
+	history, err := s.agent.GetGovernance().RuleHistory(r.PathValue("id"))
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusInternalServerError, "")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, history)
+}
+
+// handleListProposals lists a page of proposals with their tallies and
+// voting deadlines, newest first
+func (s *Server) handleListProposals(w http.ResponseWriter, r *http.Request) {
+	params, err := parsePageParams(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	status := governance.ProposalStatus(r.URL.Query().Get("status"))
+	if status != "" && status != governance.ProposalOpen && status != governance.ProposalClosed {
+		respondError(w, http.StatusBadRequest, "status must be open or closed")
+		return
+	}
+
+	proposals := s.agent.GetGovernance().ListProposals(governance.ProposalFilter{
+		RaftID: r.URL.Query().Get("raft_id"),
+		Status: status,
+		Since:  params.since,
+	})
+	keys := make([]string, len(proposals))
+	for i, proposal := range proposals {
+		keys[i] = proposalKey(proposal)
+	}
+	start, end, info := params.page(keys)
+	respondJSON(w, http.StatusOK, ProposalPage{Items: append([]*governance.Proposal{}, proposals[start:end]...), PageInfo: info})
+}
+
+// handleGetProposal returns one proposal with its tally and voting deadline
+func (s *Server) handleGetProposal(w http.ResponseWriter, r *http.Request) {
+	proposal, ok := s.agent.GetGovernance().GetProposal(r.PathValue("id"))
+	if !ok {
+		respondError(w, http.StatusNotFound, governance.ErrProposalNotFound.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, proposal)
+}
+
+// ProposeEvictionRequest is the body of POST /api/v1/governance/evictions
+type ProposeEvictionRequest struct {
+	RaftID     string `json:"raft_id,omitempty"` // Optional: defaults to otter's own raft
+	MemberID   string `json:"member_id"`
+	ProposedBy string `json:"proposed_by"`
+	Reason     string `json:"reason,omitempty"`
+	// Optional Go duration such as "72h"; defaults to OTTER_PROPOSAL_VOTING_PERIOD
+	VotingPeriod string `json:"voting_period,omitempty"`
+}
+
+// handleProposeEviction opens a proposal to revoke a raft member
+func (s *Server) handleProposeEviction(w http.ResponseWriter, r *http.Request) {
+	var req ProposeEvictionRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.MemberID == "" || req.ProposedBy == "" {
+		respondError(w, http.StatusBadRequest, "member_id and proposed_by are required")
+		return
+	}
+
+	if len(req.Reason) > 1000 {
+		respondError(w, http.StatusBadRequest, "reason too long (max 1000 characters)")
+		return
+	}
+
+	var votingPeriod time.Duration
+	if req.VotingPeriod != "" {
+		var err error
+		if votingPeriod, err = time.ParseDuration(req.VotingPeriod); err != nil {
+			respondError(w, http.StatusBadRequest, "voting_period must be a duration such as 72h")
+			return
+		}
+		if err := governance.ValidateVotingPeriod(votingPeriod); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	gov := s.agent.GetGovernance()
+	raftID := req.RaftID
+	if raftID == "" {
+		raftID = gov.GetID()
+	}
+
+	proposal, err := gov.ProposeEviction(r.Context(), raftID, req.MemberID, req.ProposedBy, req.Reason, votingPeriod)
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusBadRequest, "")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, proposal)
+}
+
+// ProposeSuspensionRequest is the body of POST /api/v1/governance/suspensions
+type ProposeSuspensionRequest struct {
+	RaftID     string `json:"raft_id,omitempty"` // Optional: defaults to otter's own raft
+	RuleID     string `json:"rule_id"`
+	ProposedBy string `json:"proposed_by"`
+	Reason     string `json:"reason"` // The harm the rule is causing
+}
+
+// handleProposeSuspension opens an emergency proposal to suspend a rule
+func (s *Server) handleProposeSuspension(w http.ResponseWriter, r *http.Request) {
+	var req ProposeSuspensionRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.RuleID == "" || req.ProposedBy == "" || req.Reason == "" {
+		respondError(w, http.StatusBadRequest, "rule_id, proposed_by and reason are required")
+		return
+	}
+
+	if len(req.Reason) > 1000 {
+		respondError(w, http.StatusBadRequest, "reason too long (max 1000 characters)")
+		return
+	}
+
+	gov := s.agent.GetGovernance()
+	raftID := req.RaftID
+	if raftID == "" {
+		raftID = gov.GetID()
+	}
+
+	proposal, err := gov.ProposeSuspension(r.Context(), raftID, req.RuleID, req.ProposedBy, req.Reason)
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusBadRequest, "")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, proposal)
+}
+
+// handleListSuspensions returns rule suspensions, newest first
+func (s *Server) handleListSuspensions(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, s.agent.GetGovernance().Suspensions(r.URL.Query().Get("raft_id")))
+}
+
+// ReopenRevoteRequest is the body of POST /api/v1/governance/suspensions/{rule_id}/revote
+type ReopenRevoteRequest struct {
+	ProposedBy string `json:"proposed_by"`
+}
+
+// handleReopenRevote opens a new re-vote on a suspended rule
+func (s *Server) handleReopenRevote(w http.ResponseWriter, r *http.Request) {
+	var req ReopenRevoteRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ProposedBy == "" {
+		respondError(w, http.StatusBadRequest, "proposed_by is required")
+		return
+	}
+
+	proposal, err := s.agent.GetGovernance().ProposeReinstatement(r.Context(), r.PathValue("rule_id"), req.ProposedBy)
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusBadRequest, "")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, proposal)
+}
+
+// handleFederation accepts a signed envelope from a peer. Peers hold no API
+// token, so the route is public and the envelope signature authenticates it.
+func (s *Server) handleFederation(w http.ResponseWriter, r *http.Request) {
+	var env governance.Envelope
+
+	if err := json.NewDecoder(r.Body).Decode(&env); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid envelope")
+		return
+	}
+
+	err := s.agent.GetGovernance().HandleEnvelope(r.Context(), &env)
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusBadRequest, "")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"status": "accepted",
+	})
+}
+
+// PostProposalRequest is the body of POST /api/v1/governance/proposals/{id}/post
+type PostProposalRequest struct {
+	Platform  string `json:"platform"`
+	ChannelID string `json:"channel_id"`
+}
+
+// handlePostProposal posts a proposal to a chat platform so members can
+// vote on it with buttons
+func (s *Server) handlePostProposal(w http.ResponseWriter, r *http.Request) {
+	var req PostProposalRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Platform == "" || req.ChannelID == "" {
+		respondError(w, http.StatusBadRequest, "platform and channel_id are required")
+		return
+	}
+
+	err := s.agent.PostProposal(r.Context(), req.Platform, req.ChannelID, r.PathValue("id"))
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusBadRequest, "")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"status": "posted",
+	})
+}
+
+// VoteRequest is the body of POST /api/v1/governance/vote
+type VoteRequest struct {
+	ProposalID string `json:"proposal_id"`
+	VoterID    string `json:"voter_id"`
+	Vote       string `json:"vote"`
+	Timestamp  string `json:"timestamp,omitempty"` // RFC 3339; required with signature
+	Signature  string `json:"signature,omitempty"` // Hex Ed25519 signature; optional when voting as this otter
+}
+
+// handleVote handles voting on a proposal
+func (s *Server) handleVote(w http.ResponseWriter, r *http.Request) {
+	var req VoteRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.ProposalID == "" || req.VoterID == "" || req.Vote == "" {
+		respondError(w, http.StatusBadRequest, "proposal_id, voter_id, and vote are required")
+		return
+	}
+
+	vote := governance.VoteType(req.Vote)
+	if vote != governance.VoteYes && vote != governance.VoteNo && vote != governance.VoteAbstain {
+		respondError(w, http.StatusBadRequest, "vote must be YES, NO, or ABSTAIN")
+		return
+	}
+
+	gov := s.agent.GetGovernance()
+
+	var err error
+	if req.Signature == "" && req.VoterID == gov.GetID() {
+		// The authenticated operator votes as this otter; sign locally
+		err = gov.CastVote(r.Context(), req.ProposalID, vote)
+	} else {
+		ballot := governance.SignedVote{
+			ProposalID: req.ProposalID,
+			VoterID:    req.VoterID,
+			Vote:       vote,
+		}
+		if ballot.Timestamp, err = time.Parse(time.RFC3339, req.Timestamp); err != nil {
+			respondError(w, http.StatusBadRequest, "timestamp must be RFC 3339")
+			return
+		}
+		if ballot.Signature, err = hex.DecodeString(req.Signature); err != nil {
+			respondError(w, http.StatusBadRequest, "signature must be valid hex")
+			return
+		}
+		err = gov.Vote(r.Context(), ballot)
+	}
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusBadRequest, "")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"status": "vote recorded",
+	})
+}
+
+// JoinRaftRequest is the body of POST /api/v1/governance/join
+type JoinRaftRequest struct {
+	RaftID      string `json:"raft_id"`
+	RequesterID string `json:"requester_id"`
+	PublicKey   string `json:"public_key"`
+	SigningKey  string `json:"signing_key,omitempty"` // Optional for peers that predate rule signing
+	Endpoint    string `json:"endpoint,omitempty"`    // Optional API address of the requester
+	// Signed capability descriptor; optional for peers that predate the handshake
+	Capabilities *governance.CapabilityDescriptor `json:"capabilities,omitempty"`
+	// Nonce from POST /api/v1/governance/join/challenge, and its signature
+	// made with the key for SigningKey
+	Nonce              string `json:"nonce"`
+	ChallengeSignature string `json:"challenge_signature"`
+	Invite             string `json:"invite,omitempty"` // Invite token, if the requester was invited
+}
+
+// CreateInviteRequest is the body of POST /api/v1/governance/rafts/{id}/invites
+type CreateInviteRequest struct {
+	InviteeID string `json:"invitee_id,omitempty"` // Restricts the invite to one otter
+	TTL       string `json:"ttl,omitempty"`        // Defaults to 24h, at most 168h
+}
+
+// InviteResponse carries a signed invite and the token to hand to the invitee
+type InviteResponse struct {
+	Token  string             `json:"token"`
+	Invite *governance.Invite `json:"invite"`
+}
+
+// handleCreateInvite signs an invite token admitting an otter to a raft
+func (s *Server) handleCreateInvite(w http.ResponseWriter, r *http.Request) {
+	var req CreateInviteRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+
+	var ttl time.Duration
+	if req.TTL != "" {
+		var err error
+		if ttl, err = time.ParseDuration(req.TTL); err != nil {
+			respondError(w, http.StatusBadRequest, "ttl must be a duration such as 24h")
+			return
+		}
+	}
+
+	invite, err := s.agent.GetGovernance().CreateInvite(r.PathValue("id"), strings.TrimSpace(req.InviteeID), ttl)
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusBadRequest, "")
+		return
+	}
+
+	token, err := invite.Token()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusCreated, InviteResponse{Token: token, Invite: invite})
+}
+
+// JoinChallengeRequest is the body of POST /api/v1/governance/join/challenge
+type JoinChallengeRequest struct {
+	RaftID      string `json:"raft_id"`
+	RequesterID string `json:"requester_id"`
+}
+
+// handleJoinChallenge issues the nonce a peer otter signs to prove it holds
+// the signing key it will ask to join with
+func (s *Server) handleJoinChallenge(w http.ResponseWriter, r *http.Request) {
+	var req JoinChallengeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.RaftID == "" || req.RequesterID == "" {
+		respondError(w, http.StatusBadRequest, "raft_id and requester_id are required")
+		return
+	}
+
+	challenge, err := s.agent.GetGovernance().IssueJoinChallenge(req.RaftID, req.RequesterID)
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusServiceUnavailable, "")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, challenge)
+}
+
+// JoinRaftResponse identifies the inducting otter to a new member
+type JoinRaftResponse struct {
+	Status       string                           `json:"status"`
+	MemberID     string                           `json:"member_id"`
+	PublicKey    string                           `json:"public_key"`
+	SigningKey   string                           `json:"signing_key"`
+	Endpoint     string                           `json:"endpoint"`
+	Capabilities *governance.CapabilityDescriptor `json:"capabilities,omitempty"`
+	Negotiated   *governance.Capabilities         `json:"negotiated,omitempty"`
+	State        governance.MembershipState       `json:"state"`
+	ProposalID   string                           `json:"proposal_id,omitempty"` // Admission proposal, while pending
+}
+
+// handleJoinRaft handles membership induction requests from peer otters.
+func (s *Server) handleJoinRaft(w http.ResponseWriter, r *http.Request) {
+	var req JoinRaftRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.RaftID == "" || req.RequesterID == "" || req.PublicKey == "" {
+		respondError(w, http.StatusBadRequest, "raft_id, requester_id, and public_key are required")
+		return
+	}
+
+	publicKey, err := hex.DecodeString(req.PublicKey)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "public_key must be valid hex")
+		return
+	}
+
+	var signingKey []byte
+	if req.SigningKey != "" {
+		signingKey, err = hex.DecodeString(req.SigningKey)
+		if err != nil || len(signingKey) != ed25519.PublicKeySize {
+			respondError(w, http.StatusBadRequest, "signing_key must be a hex-encoded Ed25519 public key")
+			return
+		}
+	}
+
+	challengeSig, err := hex.DecodeString(req.ChallengeSignature)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "challenge_signature must be valid hex")
+		return
+	}
+
+	resp, err := s.agent.GetGovernance().RequestJoin(r.Context(), governance.JoinRequest{
+		RaftID:      req.RaftID,
+		RequesterID: req.RequesterID,
+		PublicKey:   publicKey,
+		SigningKey:  signingKey,
+		Endpoint:    strings.TrimSpace(req.Endpoint),
+		Descriptor:  req.Capabilities,
+
+		Nonce:              req.Nonce,
+		ChallengeSignature: challengeSig,
+		Invite:             strings.TrimSpace(req.Invite),
+	})
+	if errors.Is(err, governance.ErrJoinChallenge) {
+		// A fresh challenge answered with the right key authenticates
+		respondError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusBadRequest, "")
+		return
+	}
+
+	status, code := "join accepted", http.StatusOK
+	if resp.State == governance.StatePending {
+		status, code = "join pending", http.StatusAccepted
+	}
+	respondJSON(w, code, JoinRaftResponse{
+		Status:       status,
+		MemberID:     resp.MemberID,
+		PublicKey:    hex.EncodeToString(resp.PublicKey),
+		SigningKey:   hex.EncodeToString(resp.SigningKey),
+		Endpoint:     resp.Endpoint,
+		Capabilities: resp.Descriptor,
+		Negotiated:   resp.Capabilities,
+		State:        resp.State,
+		ProposalID:   resp.ProposalID,
+	})
+}
+
+// handleListJoinRequests lists join requests awaiting their admission vote,
+// optionally for one raft
+func (s *Server) handleListJoinRequests(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, s.agent.GetGovernance().PendingJoins(r.URL.Query().Get("raft_id")))
+}
+
+// handleListConflicts lists rule conflicts found when joining rafts, with
+// the negotiations they started
+func (s *Server) handleListConflicts(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, s.agent.GetGovernance().Conflicts(r.URL.Query().Get("raft_id")))
+}
+
+// handleListEffects lists the effects of the active rules in force
+func (s *Server) handleListEffects(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, s.agent.GetGovernance().RuleEffects())
+}
+
+// handleListNegotiations lists negotiations, optionally by status
+func (s *Server) handleListNegotiations(w http.ResponseWriter, r *http.Request) {
+	status := governance.NegotiationStatus(r.URL.Query().Get("status"))
+	respondJSON(w, http.StatusOK, s.agent.GetGovernance().Negotiations(status))
+}
+
+// handleGetNegotiation returns a negotiation with its LLM transcript
+func (s *Server) handleGetNegotiation(w http.ResponseWriter, r *http.Request) {
+	negotiation, err := s.agent.GetGovernance().Negotiation(r.PathValue("id"))
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusNotFound, "")
+		return
+	}
+	respondJSON(w, http.StatusOK, negotiation)
+}
+
+// ApproveNegotiationRequest is the body of POST
+// /api/v1/governance/negotiations/{id}/approve
+type ApproveNegotiationRequest struct {
+	Body string `json:"body"` // Replaces the compromise's text when set
+}
+
+// handleApproveNegotiation approves a drafted compromise as the
+// authenticated user and opens the votes on it in both rafts
+func (s *Server) handleApproveNegotiation(w http.ResponseWriter, r *http.Request) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		respondError(w, http.StatusForbidden, "reviewing a negotiation requires an authenticated reviewer")
+		return
+	}
+
+	var req ApproveNegotiationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	negotiation, err := s.agent.GetGovernance().ApproveNegotiation(r.Context(), r.PathValue("id"), claims.UserID, req.Body)
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusBadRequest, "")
+		return
+	}
+	respondJSON(w, http.StatusOK, negotiation)
+}
+
+// RejectNegotiationRequest is the body of POST
+// /api/v1/governance/negotiations/{id}/reject
+type RejectNegotiationRequest struct {
+	Reason string `json:"reason"`
+}
+
+// handleRejectNegotiation turns a drafted compromise down as the
+// authenticated user
+func (s *Server) handleRejectNegotiation(w http.ResponseWriter, r *http.Request) {
+	claims, ok := ClaimsFromContext(r.Context())
+	if !ok {
+		respondError(w, http.StatusForbidden, "reviewing a negotiation requires an authenticated reviewer")
+		return
+	}
+
+	var req RejectNegotiationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	negotiation, err := s.agent.GetGovernance().RejectNegotiation(r.Context(), r.PathValue("id"), claims.UserID, req.Reason)
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusBadRequest, "")
+		return
+	}
+	respondJSON(w, http.StatusOK, negotiation)
+}
+
+// handleCapabilities returns this otter's signed capability descriptor so
+// peers can check compatibility before joining or exchanging messages
+func (s *Server) handleCapabilities(w http.ResponseWriter, r *http.Request) {
+	descriptor, err := s.agent.GetGovernance().CapabilityDescriptor()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, descriptor)
+}
+
+// handleListMembers handles listing raft members
+func (s *Server) handleListMembers(w http.ResponseWriter, r *http.Request) {
+	raftID := r.URL.Query().Get("raft_id")
+	if raftID == "" {
+		// Default to otter's own raft
+		raftID = s.agent.GetGovernance().GetID()
+	}
+
+	// Get members from the specified raft
+	members, err := s.agent.GetGovernance().GetRaftMembers(raftID)
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusNotFound, "")
+		return
+	}
+
+	// Format members for response
+	response := make([]interface{}, 0, len(members))
+	for _, member := range members {
+		response = append(response, map[string]interface{}{
+			"id":          member.ID,
+			"state":       string(member.State),
+			"joined_at":   member.JoinedAt,
+			"last_seen":   member.LastSeenAt,
+			"inducted_by": member.InductedBy,
+			"signing_key": hex.EncodeToString(member.SigningKey),
+			"liveness":    s.agent.GetGovernance().Liveness(member),
+		})
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+// AuthRequest is the body of POST /api/v1/auth
+type AuthRequest struct {
+	Passphrase string `json:"passphrase"`
+}
+
+// AuthResponse reports whether authentication succeeded. Token is empty
+// when the server has no passphrase configured.
+type AuthResponse struct {
+	Authenticated bool   `json:"authenticated"`
+	Token         string `json:"token"`
+	Role          Role   `json:"role,omitempty"`
+	ExpiresIn     int    `json:"expires_in,omitempty"` // Token lifetime in seconds
+}
+
+// handleAuth handles authentication requests
+func (s *Server) handleAuth(w http.ResponseWriter, r *http.Request) {
+	var req AuthRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	// If no passphrase is configured, allow access without JWT
+	if s.config.Passphrase == "" {
+		respondJSON(w, http.StatusOK, AuthResponse{Authenticated: true})
+		return
+	}
+
+	// Validate passphrase
+	if req.Passphrase != s.config.Passphrase {
+		respondError(w, http.StatusUnauthorized, "invalid passphrase")
+		return
+	}
+
+	// Generate JWT token
+	token, err := s.jwtManager.GenerateToken("otter-user")
+	if err != nil {
+		s.log().ErrorContext(r.Context(), "failed to generate JWT", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to generate token")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, AuthResponse{
+		Authenticated: true,
+		Token:         token,
+		Role:          RoleAdmin,
+		ExpiresIn:     int(JWTExpirationTime.Seconds()),
+	})
+}
+
+// MintTokenRequest is the body of POST /api/v1/auth/tokens
+type MintTokenRequest struct {
+	Subject string `json:"subject"` // User or service the token is for
+	Role    Role   `json:"role"`
+	// Optional Go duration such as "720h"; defaults to 24h
+	ExpiresIn string `json:"expires_in,omitempty"`
+}
+
+// MintTokenResponse is a token minted for another user or service
+type MintTokenResponse struct {
+	Token     string    `json:"token"`
+	Subject   string    `json:"subject"`
+	Role      Role      `json:"role"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// handleMintToken mints a token limited to a role, for a user or service
+// that should not hold the host passphrase
+func (s *Server) handleMintToken(w http.ResponseWriter, r *http.Request) {
+	var req MintTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if s.config.Passphrase == "" {
+		respondError(w, http.StatusConflict, "no host passphrase is configured, so the API does not check tokens")
+		return
+	}
+	if req.Subject == "" || len(req.Subject) > 100 {
+		respondError(w, http.StatusBadRequest, "subject is required (max 100 characters)")
+		return
+	}
+	role, err := ParseRole(string(req.Role))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	ttl := JWTExpirationTime
+	if req.ExpiresIn != "" {
+		if ttl, err = time.ParseDuration(req.ExpiresIn); err != nil || ttl <= 0 || ttl > MaxTokenLifetime {
+			respondError(w, http.StatusBadRequest, "expires_in must be a duration such as 720h, up to 8760h")
+			return
+		}
+	}
+
+	token, err := s.jwtManager.GenerateRoleToken(req.Subject, role, ttl)
+	if err != nil {
+		s.log().ErrorContext(r.Context(), "failed to generate JWT", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to generate token")
+		return
+	}
+
+	var mintedBy string
+	if claims, ok := ClaimsFromContext(r.Context()); ok {
+		mintedBy = claims.UserID
+	}
+	s.log().InfoContext(r.Context(), "minted api token", "subject", req.Subject, "role", string(role), "minted_by", mintedBy, "ttl", ttl)
+
+	respondJSON(w, http.StatusCreated, MintTokenResponse{
+		Token:     token,
+		Subject:   req.Subject,
+		Role:      role,
+		ExpiresAt: time.Now().Add(ttl),
+	})
+}
+
+// claimsKey is the request context key of a verified token's claims
+type claimsKey struct{}
+
+// ClaimsFromContext returns the claims of the token a request was
+// authorized with. There are none when no passphrase is configured.
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*Claims)
+	return claims, ok
+}
+
+// requireAuth is a middleware that checks for a valid token whose role
+// allows the endpoint
+func (s *Server) requireAuth(role Role, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// If no passphrase is configured, allow all requests
+		if s.config.Passphrase == "" {
+			next(w, r)
+			return
+		}
+
+		// Check Authorization header
+		authHeader := r.Header.Get("Authorization")
+		if authHeader == "" {
+			respondError(w, http.StatusUnauthorized, "missing authorization header")
+			return
+		}
+
+		// Expect "Bearer <token>"
+		const prefix = "Bearer "
+		if len(authHeader) < len(prefix) || !strings.HasPrefix(authHeader, prefix) {
+			respondError(w, http.StatusUnauthorized, "invalid authorization format")
+			return
+		}
+
+		token := strings.TrimSpace(authHeader[len(prefix):])
+
+		// Validate JWT token
+		claims, err := s.jwtManager.ValidateToken(token)
+		if err != nil {
+			respondError(w, http.StatusUnauthorized, "invalid or expired token")
+			return
+		}
+
+		if !claims.role().allows(role) {
+			respondError(w, http.StatusForbidden, fmt.Sprintf("this endpoint requires the %s role", role))
+			return
+		}
+
+		next(w, r.WithContext(context.WithValue(r.Context(), claimsKey{}, claims)))
+	}
+}
+
+// corsMiddleware adds CORS headers
+// WARNING: Allows all origins (*) - restrict in production
+func corsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// TODO: Restrict to specific origins in production
+		// For now, allow all for development convenience
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID")
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleListLLMTasks lists governance tasks queued for LLM replay
+func (s *Server) handleListLLMTasks(w http.ResponseWriter, r *http.Request) {
+	tasks := s.agent.GetGovernance().GetLLMTasks()
+
+	status := governance.LLMTaskStatus(r.URL.Query().Get("status"))
+	if status != "" {
+		filtered := make([]*governance.LLMTask, 0, len(tasks))
+		for _, task := range tasks {
+			if task.Status == status {
+				filtered = append(filtered, task)
+			}
+		}
+		tasks = filtered
+	}
+
+	respondJSON(w, http.StatusOK, tasks)
+}
+
+// handleRetryLLMTask forces an immediate replay of a queued task
+func (s *Server) handleRetryLLMTask(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("id")
+	gov := s.agent.GetGovernance()
+
+	if _, ok := gov.GetLLMTask(taskID); !ok {
+		respondError(w, http.StatusNotFound, "task not found")
+		return
+	}
+
+	if err := gov.RetryLLMTask(r.Context(), taskID); err != nil {
+		s.respondErr(w, r, err, http.StatusConflict, "")
+		return
+	}
+
+	task, _ := gov.GetLLMTask(taskID)
+	respondJSON(w, http.StatusOK, task)
+}
+
+// handleRuleSetDigest returns the Merkle digest of a raft's adopted rules for peer comparison
+func (s *Server) handleRuleSetDigest(w http.ResponseWriter, r *http.Request) {
+	digest, err := s.agent.GetGovernance().RuleSetDigest(r.PathValue("id"))
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusNotFound, "")
+		return
+	}
+
+	s.respondPeerJSON(w, r, digest)
+}
+
+// handleVotingPolicy returns the voting policy a raft decides proposals by
+func (s *Server) handleVotingPolicy(w http.ResponseWriter, r *http.Request) {
+	policy, err := s.agent.GetGovernance().VotingPolicy(r.PathValue("id"))
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusNotFound, "")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, policy)
+}
+
+// handleRaftRules returns a raft's adopted rules so peers can reconcile against them
+func (s *Server) handleRaftRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := s.agent.GetGovernance().RaftRules(r.PathValue("id"))
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusNotFound, "")
+		return
+	}
+
+	s.respondPeerJSON(w, r, rules)
+}
+
+// handleMemberKeys returns the signing keys of a raft's members so peers
+// can verify who signed its rules
+func (s *Server) handleMemberKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := s.agent.GetGovernance().MemberKeys(r.PathValue("id"))
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusNotFound, "")
+		return
+	}
+
+	s.respondPeerJSON(w, r, keys)
+}
+
+// handleListRafts lists the rafts this otter belongs to or belonged to.
+// Pass archived=true for archived rafts only, archived=false for live ones,
+// and tag to list only rafts with that tag.
+func (s *Server) handleListRafts(w http.ResponseWriter, r *http.Request) {
+	var archived *bool
+	if value := r.URL.Query().Get("archived"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "archived must be true or false")
+			return
+		}
+		archived = &parsed
+	}
+
+	tag := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("tag")))
+
+	rafts := []governance.RaftSummary{}
+	for _, raft := range s.agent.GetGovernance().RaftSummaries() {
+		if (archived == nil || raft.Archived == *archived) && (tag == "" || slices.Contains(raft.Metadata.Tags, tag)) {
+			rafts = append(rafts, raft)
+		}
+	}
+	respondJSON(w, http.StatusOK, rafts)
+}
+
+// RaftMetadataRequest is the body of PUT /api/v1/governance/rafts/{id}/metadata
+type RaftMetadataRequest struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Purpose     string   `json:"purpose"`
+	Tags        []string `json:"tags"`
+	UpdatedBy   string   `json:"updated_by"` // Defaults to this otter
+}
+
+// RaftMetadataResponse reports a metadata change: applied at once in a
+// solo raft, or proposed as a metadata rule otherwise
+type RaftMetadataResponse struct {
+	Metadata *governance.RaftMetadata `json:"metadata,omitempty"`
+	Proposal *governance.Proposal     `json:"proposal,omitempty"`
+}
+
+// handleGetRaftMetadata returns a raft's name, description, purpose and tags
+func (s *Server) handleGetRaftMetadata(w http.ResponseWriter, r *http.Request) {
+	metadata, err := s.agent.GetGovernance().RaftMetadata(r.PathValue("id"))
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusNotFound, "")
+		return
+	}
+	respondJSON(w, http.StatusOK, metadata)
+}
+
+// handleUpdateRaftMetadata changes a raft's metadata: directly while the
+// updater is its only member (200), through a proposal otherwise (202)
+func (s *Server) handleUpdateRaftMetadata(w http.ResponseWriter, r *http.Request) {
+	var req RaftMetadataRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	gov := s.agent.GetGovernance()
+	if req.UpdatedBy == "" {
+		req.UpdatedBy = gov.GetID()
+	}
+
+	raftID := r.PathValue("id")
+	metadata := governance.RaftMetadata{Name: req.Name, Description: req.Description, Purpose: req.Purpose, Tags: req.Tags}
+	proposal, err := gov.UpdateRaftMetadata(r.Context(), raftID, metadata, req.UpdatedBy)
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusBadRequest, "")
+		return
+	}
+	if proposal != nil {
+		respondJSON(w, http.StatusAccepted, RaftMetadataResponse{Proposal: proposal})
+		return
+	}
+
+	if metadata, err = gov.RaftMetadata(raftID); err != nil {
+		s.respondErr(w, r, err, http.StatusNotFound, "")
+		return
+	}
+	respondJSON(w, http.StatusOK, RaftMetadataResponse{Metadata: &metadata})
+}
+
+// ArchiveRaftRequest is the body of POST /api/v1/governance/rafts/{id}/archive
+type ArchiveRaftRequest struct {
+	ArchivedBy string `json:"archived_by"` // Defaults to this otter
+	Reason     string `json:"reason"`
+}
+
+// handleArchiveRaft moves a raft to the read-only archived state
+func (s *Server) handleArchiveRaft(w http.ResponseWriter, r *http.Request) {
+	var req ArchiveRaftRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	gov := s.agent.GetGovernance()
+	if req.ArchivedBy == "" {
+		req.ArchivedBy = gov.GetID()
+	}
+
+	err := gov.ArchiveRaft(r.Context(), r.PathValue("id"), req.ArchivedBy, req.Reason)
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusBadRequest, "")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "archived"})
+}
+
+// handleLeaveRaft takes this otter out of a raft it joined
+func (s *Server) handleLeaveRaft(w http.ResponseWriter, r *http.Request) {
+	err := s.agent.GetGovernance().LeaveRaft(r.Context(), r.PathValue("id"))
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusBadRequest, "")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "left"})
+}
+
+// handleDriftReports returns the latest rule drift reports.
+// Pass refresh=true to check every peer now instead of waiting for the next pass.
+func (s *Server) handleDriftReports(w http.ResponseWriter, r *http.Request) {
+	gov := s.agent.GetGovernance()
+
+	if refresh, _ := strconv.ParseBool(r.URL.Query().Get("refresh")); refresh {
+		gov.CheckAllDrift(r.Context())
+	}
+
+	respondJSON(w, http.StatusOK, gov.DriftReports())
+}
+
+// ReconcileRequest is the body of POST /api/v1/governance/rafts/{id}/reconcile
+type ReconcileRequest struct {
+	PeerID string `json:"peer_id"`
+}
+
+// handleReconcileRules pulls missing rules for a raft from a peer
+func (s *Server) handleReconcileRules(w http.ResponseWriter, r *http.Request) {
+	var req ReconcileRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.PeerID == "" {
+		respondError(w, http.StatusBadRequest, "peer_id is required")
+		return
+	}
+
+	gov := s.agent.GetGovernance()
+	raftID := r.PathValue("id")
+	if _, err := gov.RaftRules(raftID); err != nil {
+		s.respondErr(w, r, err, http.StatusNotFound, "")
+		return
+	}
+
+	result, err := gov.ReconcileRules(r.Context(), raftID, req.PeerID)
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusBadGateway, "")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// handleGovernanceState reconstructs governance state as of a point in time
+// from the audit log; as_of defaults to now
+func (s *Server) handleGovernanceState(w http.ResponseWriter, r *http.Request) {
+	asOf := time.Now()
+	if value := r.URL.Query().Get("as_of"); value != "" {
+		var err error
+		if asOf, err = parseTimeParam(value); err != nil {
+			respondError(w, http.StatusBadRequest, "as_of must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+			return
+		}
+	}
+
+	state, err := s.agent.GetGovernance().StateAt(r.Context(), asOf)
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusInternalServerError, "failed to reconstruct governance state")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, state)
+}
+
+// handleAuditLog streams governance audit entries, oldest first, as NDJSON
+func (s *Server) handleAuditLog(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := governance.AuditFilter{RaftID: query.Get("raft_id")}
+	for name, target := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if value := query.Get(name); value != "" {
+			t, err := parseTimeParam(value)
+			if err != nil {
+				respondError(w, http.StatusBadRequest, name+" must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+				return
+			}
+			*target = t
+		}
+	}
+
+	out := newNDJSONWriter(w)
+	err := s.agent.GetGovernance().EachAuditEntry(r.Context(), filter, func(entry governance.AuditEntry) error {
+		return out.Write(entry)
+	})
+	if err != nil && r.Context().Err() == nil {
+		out.Fail("failed to stream audit log")
+		return
+	}
+	out.Close()
+}
+
+// handleVerifyAudit checks the audit log's hash chain and signatures. A
+// broken log is still a 200: the report says where it breaks.
+func (s *Server) handleVerifyAudit(w http.ResponseWriter, r *http.Request) {
+	report, err := s.agent.GetGovernance().VerifyAudit(r.Context())
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusInternalServerError, "failed to verify audit log")
+		return
+	}
+	respondJSON(w, http.StatusOK, report)
+}
+
+// PlaceHoldRequest is the body of POST /api/v1/governance/holds
+type PlaceHoldRequest struct {
+	Kind       governance.HoldKind `json:"kind"`                  // memory, proposal or audit
+	SubjectID  string              `json:"subject_id,omitempty"`  // Memory or proposal ID
+	MemoryType memory.MemoryType   `json:"memory_type,omitempty"` // Optional: defaults to long_term
+	RaftID     string              `json:"raft_id,omitempty"`     // Optional for audit holds: defaults to every raft
+	Since      time.Time           `json:"since,omitempty"`       // Audit holds: RFC 3339 start of the range
+	Until      time.Time           `json:"until,omitempty"`       // Audit holds: RFC 3339 end of the range
+	Reason     string              `json:"reason"`
+	PlacedBy   string              `json:"placed_by"`
+	// Passphrase is placed_by's own approver passphrase, required when
+	// OTTER_HOLD_APPROVERS provisions approvers
+	Passphrase string `json:"passphrase,omitempty"`
+}
+
+// handlePlaceHold places a legal hold on a memory, proposal or audit range
+func (s *Server) handlePlaceHold(w http.ResponseWriter, r *http.Request) {
+	var req PlaceHoldRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if len(req.Reason) > 1000 {
+		respondError(w, http.StatusBadRequest, "reason too long (max 1000 characters)")
+		return
+	}
+	// The placer may not approve the release, so it must be an approver
+	// who can't sign in under another name
+	if len(s.config.HoldApprovers) > 0 && !s.holdApprover(req.PlacedBy, req.Passphrase) {
+		respondError(w, http.StatusUnauthorized, "placed_by must be a provisioned hold approver with their passphrase")
+		return
+	}
+
+	hold, err := s.agent.GetGovernance().PlaceHold(r.Context(), governance.HoldRequest{
+		Kind:       req.Kind,
+		SubjectID:  req.SubjectID,
+		MemoryType: req.MemoryType,
+		RaftID:     req.RaftID,
+		Since:      req.Since,
+		Until:      req.Until,
+		Reason:     req.Reason,
+		PlacedBy:   req.PlacedBy,
 	})
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusBadRequest, "")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, hold)
+}
+
+// handleListHolds lists legal holds, newest first
+func (s *Server) handleListHolds(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if status != "" && status != "active" && status != "released" {
+		respondError(w, http.StatusBadRequest, "status must be active or released")
+		return
+	}
+
+	holds := []*governance.Hold{}
+	for _, hold := range s.agent.GetGovernance().GetHolds(status != "active") {
+		if status != "released" || !hold.Active() {
+			holds = append(holds, hold)
+		}
+	}
+
+	respondJSON(w, http.StatusOK, holds)
+}
+
+// ReleaseHoldRequest is the body of POST /api/v1/governance/holds/{id}/release
+type ReleaseHoldRequest struct {
+	Approver   string `json:"approver"`   // A provisioned hold approver
+	Passphrase string `json:"passphrase"` // The approver's own passphrase
+}
+
+// handleReleaseHold records an approver's consent to release a hold. Token
+// subjects are not approvers: every passphrase login is "otter-user" and
+// an admin can mint a token for any name, so each approver proves who they
+// are with their own passphrase from OTTER_HOLD_APPROVERS.
+func (s *Server) handleReleaseHold(w http.ResponseWriter, r *http.Request) {
+	var req ReleaseHoldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if len(s.config.HoldApprovers) == 0 {
+		respondError(w, http.StatusConflict, "no hold approvers are provisioned; set OTTER_HOLD_APPROVERS")
+		return
+	}
+	if !s.holdApprover(req.Approver, req.Passphrase) {
+		respondError(w, http.StatusUnauthorized, "invalid approver or passphrase")
+		return
+	}
+
+	hold, err := s.agent.GetGovernance().ApproveHoldRelease(r.Context(), r.PathValue("id"), req.Approver)
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusBadRequest, "")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, hold)
+}
+
+// holdApprover reports whether name is a provisioned hold approver and
+// passphrase is theirs
+func (s *Server) holdApprover(name, passphrase string) bool {
+	want, ok := s.config.HoldApprovers[name]
+	if !ok || name == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(passphrase), []byte(want)) == 1
+}
+
+// handleListOnboardings lists new members' onboarding progress
+func (s *Server) handleListOnboardings(w http.ResponseWriter, r *http.Request) {
+	records, err := s.agent.ListOnboardings(r.Context())
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusInternalServerError, "failed to list onboardings")
+		return
+	}
+	if records == nil {
+		records = []memory.OnboardingRecord{}
+	}
+
+	respondJSON(w, http.StatusOK, records)
+}
+
+// handleCompleteOnboardingStep marks an onboarding step done on the member's
+// behalf, for members without a plugin contact
+func (s *Server) handleCompleteOnboardingStep(w http.ResponseWriter, r *http.Request) {
+	record, err := s.agent.CompleteOnboardingStep(r.Context(), r.PathValue("id"), r.PathValue("step"))
+	if err != nil {
+		s.respondErr(w, r, err, http.StatusInternalServerError, "failed to update onboarding")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, record)
+}
+
+// parseTimeParam parses an RFC 3339 timestamp, or a date meaning the end of
+// that day in UTC
+func parseTimeParam(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	day, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return day.Add(24*time.Hour - time.Nanosecond), nil
 }
 
 // respondJSON writes a JSON response
@@ -424,9 +2012,29 @@ func respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	json.NewEncoder(w).Encode(data)
 }
 
+// respondPeerJSON writes a JSON response for peer otters, signed by this
+// otter. Peer reads need no token, so the signature is what the caller
+// checks.
+func (s *Server) respondPeerJSON(w http.ResponseWriter, r *http.Request, data interface{}) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to encode response")
+		return
+	}
+	header, err := s.agent.GetGovernance().SignPeerResponse(r.URL.EscapedPath(), body)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	for key, values := range header {
+		w.Header()[key] = values
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
 // respondError writes an error response
 func respondError(w http.ResponseWriter, status int, message string) {
-	respondJSON(w, status, map[string]string{
-		"error": message,
-	})
+	respondJSON(w, status, ErrorResponse{Error: message})
 }
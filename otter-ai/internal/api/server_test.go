@@ -3,7 +3,10 @@ package api
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -14,6 +17,7 @@ import (
 	"otter-ai/internal/config"
 	"otter-ai/internal/governance"
 	"otter-ai/internal/llm"
+	"otter-ai/internal/llm/usage"
 	"otter-ai/internal/memory"
 	"otter-ai/internal/vectordb"
 )
@@ -119,6 +123,39 @@ func TestHandleHealth(t *testing.T) {
 	}
 }
 
+func TestHandleReadiness(t *testing.T) {
+	s := newTestServer("")
+	w := httptest.NewRecorder()
+	s.handleReadiness(w, httptest.NewRequest("GET", "/readyz", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d; want 200", w.Code)
+	}
+	var body ReadinessResponse
+	json.NewDecoder(w.Body).Decode(&body)
+	if body.Status != "ready" || body.Components["database"].Status != agent.ComponentOK {
+		t.Errorf("body = %+v", body)
+	}
+
+	s.agent = agent.New(agent.Config{Memory: memory.New(&failingVectorDB{}), LLM: &mockLLMProvider{}})
+	w = httptest.NewRecorder()
+	s.handleReadiness(w, httptest.NewRequest("GET", "/readyz", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d; want 503", w.Code)
+	}
+	body = ReadinessResponse{}
+	json.NewDecoder(w.Body).Decode(&body)
+	if body.Status != "not_ready" || body.Components["database"].Status != agent.ComponentDown {
+		t.Errorf("body = %+v", body)
+	}
+}
+
+// failingVectorDB is a mockVectorDB whose database is unreachable
+type failingVectorDB struct{ mockVectorDB }
+
+func (m *failingVectorDB) List(_ context.Context, _ string, _, _ int) ([]vectordb.Record, error) {
+	return nil, errors.New("database is locked")
+}
+
 // --- Mock VectorDB ---
 
 type mockVectorDB struct{}
@@ -126,7 +163,7 @@ type mockVectorDB struct{}
 func (m *mockVectorDB) Store(_ context.Context, _ string, _ string, _ []float32, _ map[string]interface{}) error {
 	return nil
 }
-func (m *mockVectorDB) Search(_ context.Context, _ string, _ []float32, _ int) ([]vectordb.SearchResult, error) {
+func (m *mockVectorDB) Search(_ context.Context, _ string, _ []float32, _ int, _ vectordb.Filter) ([]vectordb.SearchResult, error) {
 	return nil, nil
 }
 func (m *mockVectorDB) Get(_ context.Context, _ string, _ string) (*vectordb.Record, error) {
@@ -251,7 +288,7 @@ func TestHandleAuth_InvalidBody(t *testing.T) {
 func TestRequireAuth_NoPassphrase(t *testing.T) {
 	s := newTestServer("")
 	called := false
-	handler := s.requireAuth(func(w http.ResponseWriter, r *http.Request) {
+	handler := s.requireAuth(RoleObserver, func(w http.ResponseWriter, r *http.Request) {
 		called = true
 		w.WriteHeader(http.StatusOK)
 	})
@@ -270,7 +307,7 @@ func TestRequireAuth_ValidToken(t *testing.T) {
 	token, _ := s.jwtManager.GenerateToken("test-user")
 
 	called := false
-	handler := s.requireAuth(func(w http.ResponseWriter, r *http.Request) {
+	handler := s.requireAuth(RoleObserver, func(w http.ResponseWriter, r *http.Request) {
 		called = true
 		w.WriteHeader(http.StatusOK)
 	})
@@ -287,7 +324,7 @@ func TestRequireAuth_ValidToken(t *testing.T) {
 
 func TestRequireAuth_InvalidToken(t *testing.T) {
 	s := newTestServer("secret123")
-	handler := s.requireAuth(func(w http.ResponseWriter, r *http.Request) {
+	handler := s.requireAuth(RoleObserver, func(w http.ResponseWriter, r *http.Request) {
 		t.Error("handler should not be called")
 	})
 
@@ -303,7 +340,7 @@ func TestRequireAuth_InvalidToken(t *testing.T) {
 
 func TestRequireAuth_MissingHeader(t *testing.T) {
 	s := newTestServer("secret123")
-	handler := s.requireAuth(func(w http.ResponseWriter, r *http.Request) {
+	handler := s.requireAuth(RoleObserver, func(w http.ResponseWriter, r *http.Request) {
 		t.Error("handler should not be called")
 	})
 
@@ -318,7 +355,7 @@ func TestRequireAuth_MissingHeader(t *testing.T) {
 
 func TestRequireAuth_BadFormat(t *testing.T) {
 	s := newTestServer("secret123")
-	handler := s.requireAuth(func(w http.ResponseWriter, r *http.Request) {
+	handler := s.requireAuth(RoleObserver, func(w http.ResponseWriter, r *http.Request) {
 		t.Error("handler should not be called")
 	})
 
@@ -332,6 +369,86 @@ func TestRequireAuth_BadFormat(t *testing.T) {
 	}
 }
 
+func TestRequireAuth_Roles(t *testing.T) {
+	s := newTestServer("secret123")
+	observer, _ := s.jwtManager.GenerateRoleToken("dashboard", RoleObserver, time.Hour)
+	member, _ := s.jwtManager.GenerateRoleToken("bot", RoleMember, time.Hour)
+
+	call := func(method, path, token string) int {
+		req := httptest.NewRequest(method, path, strings.NewReader("{}"))
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		s.handler().ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := call("GET", "/api/v1/status", observer); code != http.StatusOK {
+		t.Errorf("observer status = %d, want 200", code)
+	}
+	if code := call("GET", "/api/v1/memories", observer); code != http.StatusForbidden {
+		t.Errorf("observer memories = %d, want 403", code)
+	}
+	if code := call("POST", "/api/v1/governance/vote", observer); code != http.StatusForbidden {
+		t.Errorf("observer vote = %d, want 403", code)
+	}
+	if code := call("GET", "/api/v1/memories", member); code == http.StatusForbidden {
+		t.Error("member should be allowed to read memories")
+	}
+	if code := call("PUT", "/api/v1/governance/chaos", member); code != http.StatusForbidden {
+		t.Errorf("member chaos = %d, want 403", code)
+	}
+	if code := call("POST", "/api/v1/auth/tokens", member); code != http.StatusForbidden {
+		t.Errorf("member minting = %d, want 403", code)
+	}
+}
+
+func TestHandleMintToken(t *testing.T) {
+	s := newTestServer("secret123")
+	admin, _ := s.jwtManager.GenerateToken("operator")
+
+	mint := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v1/auth/tokens", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+admin)
+		w := httptest.NewRecorder()
+		s.handler().ServeHTTP(w, req)
+		return w
+	}
+
+	w := mint(`{"subject": "grafana", "role": "observer", "expires_in": "720h"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, body: %s", w.Code, w.Body.String())
+	}
+	var resp MintTokenResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	claims, err := s.jwtManager.ValidateToken(resp.Token)
+	if err != nil || claims.UserID != "grafana" || claims.role() != RoleObserver {
+		t.Fatalf("minted claims = %+v, %v", claims, err)
+	}
+	if until := time.Until(resp.ExpiresAt); until < 719*time.Hour || until > 720*time.Hour {
+		t.Errorf("expires in %v, want 720h", until)
+	}
+
+	for _, body := range []string{
+		`{"role": "observer"}`,
+		`{"subject": "grafana", "role": "root"}`,
+		`{"subject": "grafana", "role": "member", "expires_in": "9000h"}`,
+		`{"subject": "grafana", "role": "member", "expires_in": "soon"}`,
+	} {
+		if w := mint(body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", body, w.Code)
+		}
+	}
+
+	// Without a passphrase tokens are never checked
+	open := newTestServer("")
+	req := httptest.NewRequest("POST", "/api/v1/auth/tokens", strings.NewReader(`{"subject": "grafana", "role": "observer"}`))
+	w = httptest.NewRecorder()
+	open.handler().ServeHTTP(w, req)
+	if w.Code != http.StatusConflict {
+		t.Errorf("no passphrase status = %d, want 409", w.Code)
+	}
+}
+
 // --- handleChat ---
 
 func TestHandleChat_EmptyMessage(t *testing.T) {
@@ -390,266 +507,1877 @@ func TestHandleChat_Success(t *testing.T) {
 	}
 }
 
-// --- handleClearChat ---
+func TestHandleChat_BlockedByHook(t *testing.T) {
+	policy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(agent.HookResult{Action: agent.HookBlock, Response: "Not here, please."})
+	}))
+	defer policy.Close()
 
-func TestHandleClearChat(t *testing.T) {
 	s := newTestServer("")
-	req := httptest.NewRequest("POST", "/api/v1/chat/clear", nil)
+	s.agent = agent.New(agent.Config{
+		Memory: memory.New(&mockVectorDB{}),
+		LLM:    &mockLLMProvider{completeResp: "mock response"},
+		Hooks:  []agent.Hook{agent.NewHTTPHook(agent.HookBeforeMessage, policy.URL, "", time.Second)},
+	})
+
+	req := httptest.NewRequest("POST", "/api/v1/chat", strings.NewReader(`{"message": "hello"}`))
 	w := httptest.NewRecorder()
-	s.handleClearChat(w, req)
+	s.handleChat(w, req)
 
 	if w.Code != http.StatusOK {
-		t.Errorf("status = %d, want 200", w.Code)
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	var resp ChatResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if !resp.Blocked || resp.Response != "Not here, please." {
+		t.Errorf("unexpected response %+v", resp)
 	}
 }
 
-// --- handleListMemories ---
+// --- handleClearChat ---
 
-func TestHandleListMemories(t *testing.T) {
+func TestHandleClearChat(t *testing.T) {
 	s := newTestServer("")
-	req := httptest.NewRequest("GET", "/api/v1/memories", nil)
+	req := httptest.NewRequest("POST", "/api/v1/chat/clear", nil)
 	w := httptest.NewRecorder()
-	s.handleListMemories(w, req)
+	s.handleClearChat(w, req)
 
 	if w.Code != http.StatusOK {
 		t.Errorf("status = %d, want 200", w.Code)
 	}
 }
 
-func TestHandleListMemories_WithType(t *testing.T) {
+func TestHandleClearChat_WithSession(t *testing.T) {
 	s := newTestServer("")
-	req := httptest.NewRequest("GET", "/api/v1/memories?type=musing", nil)
+	req := httptest.NewRequest("POST", "/api/v1/chat/clear", strings.NewReader(`{"session_id": "user-1"}`))
 	w := httptest.NewRecorder()
-	s.handleListMemories(w, req)
+	s.handleClearChat(w, req)
 
 	if w.Code != http.StatusOK {
 		t.Errorf("status = %d, want 200", w.Code)
 	}
+	var resp map[string]string
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp["session_id"] != "user-1" {
+		t.Errorf("session_id = %q, want user-1", resp["session_id"])
+	}
 }
 
-// --- handleListRules ---
-
-func TestHandleListRules(t *testing.T) {
-	s := newTestServerWithGov(t)
-	req := httptest.NewRequest("GET", "/api/v1/governance/rules", nil)
+func TestHandleClearChat_InvalidSession(t *testing.T) {
+	s := newTestServer("")
+	req := httptest.NewRequest("POST", "/api/v1/chat/clear?session_id=bad%20id", nil)
 	w := httptest.NewRecorder()
-	s.handleListRules(w, req)
+	s.handleClearChat(w, req)
 
-	if w.Code != http.StatusOK {
-		t.Errorf("status = %d, want 200", w.Code)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
 	}
 }
 
-// --- handleProposeRule ---
+// --- session endpoints ---
 
-func TestHandleProposeRule_Success(t *testing.T) {
-	s := newTestServerWithGov(t)
-	otterID := s.agent.GetGovernance().GetID()
-	body, _ := json.Marshal(map[string]string{
-		"scope":       "safety",
-		"body":        "be kind",
-		"proposed_by": otterID,
-	})
-	req := httptest.NewRequest("POST", "/api/v1/governance/rules", bytes.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
+func TestHandleChat_WithSession(t *testing.T) {
+	s := newTestServer("")
+	body := `{"message": "hello", "session_id": "user-1"}`
+	req := httptest.NewRequest("POST", "/api/v1/chat", strings.NewReader(body))
 	w := httptest.NewRecorder()
-	s.handleProposeRule(w, req)
+	s.handleChat(w, req)
 
-	if w.Code != http.StatusCreated {
-		t.Errorf("status = %d, want 201, body: %s", w.Code, w.Body.String())
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	var resp map[string]string
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp["session_id"] != "user-1" {
+		t.Errorf("session_id = %q, want user-1", resp["session_id"])
 	}
-}
-
-func TestHandleProposeRule_MissingFields(t *testing.T) {
-	s := newTestServerWithGov(t)
-	body := `{"scope": "safety"}`
-	req := httptest.NewRequest("POST", "/api/v1/governance/rules", strings.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-	s.handleProposeRule(w, req)
 
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("status = %d, want 400", w.Code)
+	req = httptest.NewRequest("GET", "/api/v1/chat/sessions/user-1", nil)
+	req.SetPathValue("id", "user-1")
+	w = httptest.NewRecorder()
+	s.handleGetSession(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("get session status = %d, want 200", w.Code)
+	}
+	var record memory.SessionRecord
+	json.NewDecoder(w.Body).Decode(&record)
+	if len(record.Messages) != 2 {
+		t.Errorf("expected 2 messages in session, got %d", len(record.Messages))
 	}
 }
 
-func TestHandleProposeRule_BodyTooLong(t *testing.T) {
-	s := newTestServerWithGov(t)
-	longBody := strings.Repeat("x", 1001)
-	body, _ := json.Marshal(map[string]string{
-		"scope":       "safety",
-		"body":        longBody,
-		"proposed_by": "otter-1",
-	})
-	req := httptest.NewRequest("POST", "/api/v1/governance/rules", bytes.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
+func TestHandleChat_InvalidSession(t *testing.T) {
+	s := newTestServer("")
+	body := `{"message": "hello", "session_id": "../etc"}`
+	req := httptest.NewRequest("POST", "/api/v1/chat", strings.NewReader(body))
 	w := httptest.NewRecorder()
-	s.handleProposeRule(w, req)
+	s.handleChat(w, req)
 
 	if w.Code != http.StatusBadRequest {
 		t.Errorf("status = %d, want 400", w.Code)
 	}
 }
 
-func TestHandleProposeRule_ScopeTooLong(t *testing.T) {
-	s := newTestServerWithGov(t)
-	longScope := strings.Repeat("x", 101)
-	body, _ := json.Marshal(map[string]string{
-		"scope":       longScope,
-		"body":        "rule",
-		"proposed_by": "otter-1",
-	})
-	req := httptest.NewRequest("POST", "/api/v1/governance/rules", bytes.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
+func TestHandleListSessions(t *testing.T) {
+	s := newTestServer("")
+	req := httptest.NewRequest("GET", "/api/v1/chat/sessions", nil)
 	w := httptest.NewRecorder()
-	s.handleProposeRule(w, req)
+	s.handleListSessions(w, req)
 
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("status = %d, want 400", w.Code)
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", w.Code)
 	}
 }
 
-// --- handleVote ---
-
-func TestHandleVote_MissingFields(t *testing.T) {
-	s := newTestServerWithGov(t)
-	body := `{"proposal_id": "p1"}`
-	req := httptest.NewRequest("POST", "/api/v1/governance/vote", strings.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
+func TestHandleGetSession_NotFound(t *testing.T) {
+	s := newTestServer("")
+	req := httptest.NewRequest("GET", "/api/v1/chat/sessions/nobody", nil)
+	req.SetPathValue("id", "nobody")
 	w := httptest.NewRecorder()
-	s.handleVote(w, req)
+	s.handleGetSession(w, req)
 
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("status = %d, want 400", w.Code)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", w.Code)
 	}
 }
 
-func TestHandleVote_InvalidVoteType(t *testing.T) {
-	s := newTestServerWithGov(t)
-	body := `{"proposal_id": "p1", "voter_id": "v1", "vote": "MAYBE"}`
-	req := httptest.NewRequest("POST", "/api/v1/governance/vote", strings.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
+func TestHandleDeleteSession(t *testing.T) {
+	s := newTestServer("")
+	req := httptest.NewRequest("DELETE", "/api/v1/chat/sessions/user-1", nil)
+	req.SetPathValue("id", "user-1")
 	w := httptest.NewRecorder()
-	s.handleVote(w, req)
+	s.handleDeleteSession(w, req)
 
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("status = %d, want 400", w.Code)
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", w.Code)
 	}
 }
 
-// --- handleJoinRaft ---
+// --- handleGetCapabilities ---
 
-func TestHandleJoinRaft_MissingFields(t *testing.T) {
-	s := newTestServerWithGov(t)
-	body := `{"raft_id": "r1"}`
-	req := httptest.NewRequest("POST", "/api/v1/governance/join", strings.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
+func TestHandleGetCapabilities(t *testing.T) {
+	s := newTestServer("")
+	req := httptest.NewRequest("GET", "/api/v1/capabilities", nil)
 	w := httptest.NewRecorder()
-	s.handleJoinRaft(w, req)
+	s.handler().ServeHTTP(w, req)
 
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("status = %d, want 400", w.Code)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	var manifest agent.CapabilityManifest
+	if err := json.Unmarshal(w.Body.Bytes(), &manifest); err != nil {
+		t.Fatal(err)
+	}
+	if manifest.LLMProvider != "mock" || len(manifest.Tools) == 0 || len(manifest.Limitations) == 0 {
+		t.Errorf("unexpected manifest: %+v", manifest)
 	}
 }
 
-func TestHandleJoinRaft_InvalidHex(t *testing.T) {
-	s := newTestServerWithGov(t)
-	body := `{"raft_id": "r1", "requester_id": "o2", "public_key": "not-hex"}`
-	req := httptest.NewRequest("POST", "/api/v1/governance/join", strings.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
+func TestHandleConnectors_NoneConfigured(t *testing.T) {
+	s := newTestServer("")
 	w := httptest.NewRecorder()
-	s.handleJoinRaft(w, req)
+	s.handler().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/connectors", nil))
+	if w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != "[]" {
+		t.Errorf("list = %d %s, want 200 []", w.Code, w.Body.String())
+	}
 
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("status = %d, want 400", w.Code)
+	w = httptest.NewRecorder()
+	s.handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/connectors/news/run", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("run status = %d, want 404", w.Code)
 	}
 }
 
-func TestHandleJoinRaft_Success(t *testing.T) {
-	s := newTestServerWithGov(t)
-	raftID := s.agent.GetGovernance().GetID()
-	body, _ := json.Marshal(map[string]string{
-		"raft_id":      raftID,
-		"requester_id": "new-otter",
-		"public_key":   "abcdef1234567890",
-	})
-	req := httptest.NewRequest("POST", "/api/v1/governance/join", bytes.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
+func TestHandleGetUsage(t *testing.T) {
+	s := newTestServer("")
 	w := httptest.NewRecorder()
-	s.handleJoinRaft(w, req)
+	s.handler().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/usage", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503 without a usage tracker", w.Code)
+	}
+
+	tracker, err := usage.NewTracker(context.Background(), nil, config.UsageConfig{DailyTokenBudget: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracker.Record(context.Background(), "mock", "chat", 1200)
+	s.agent = agent.New(agent.Config{Memory: memory.New(&mockVectorDB{}), LLM: &mockLLMProvider{}, Usage: tracker})
 
+	w = httptest.NewRecorder()
+	s.handler().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/usage?days=30", nil))
 	if w.Code != http.StatusOK {
-		t.Errorf("status = %d, want 200, body: %s", w.Code, w.Body.String())
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var report usage.Report
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatal(err)
+	}
+	if report.TokensToday != 1200 || !report.BudgetExceeded || report.DailyBudget != 1000 {
+		t.Errorf("report = %+v, want 1200 of 1000 tokens used", report)
+	}
+
+	w = httptest.NewRecorder()
+	s.handler().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/usage?days=0", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("days=0 status = %d, want 400", w.Code)
 	}
 }
 
-// --- handleListMembers ---
+// --- handleListMemories ---
 
-func TestHandleListMembers(t *testing.T) {
-	s := newTestServerWithGov(t)
-	req := httptest.NewRequest("GET", "/api/v1/governance/members", nil)
+func TestHandleListMemories(t *testing.T) {
+	s := newTestServer("")
+	req := httptest.NewRequest("GET", "/api/v1/memories", nil)
 	w := httptest.NewRecorder()
-	s.handleListMembers(w, req)
+	s.handleListMemories(w, req)
 
 	if w.Code != http.StatusOK {
 		t.Errorf("status = %d, want 200", w.Code)
 	}
 }
 
-func TestHandleListMembers_WithRaftID(t *testing.T) {
-	s := newTestServerWithGov(t)
-	raftID := s.agent.GetGovernance().GetID()
-	req := httptest.NewRequest("GET", "/api/v1/governance/members?raft_id="+raftID, nil)
+func TestHandleListMemories_WithType(t *testing.T) {
+	s := newTestServer("")
+	req := httptest.NewRequest("GET", "/api/v1/memories?type=musing", nil)
 	w := httptest.NewRecorder()
-	s.handleListMembers(w, req)
+	s.handleListMemories(w, req)
 
 	if w.Code != http.StatusOK {
-		t.Errorf("status = %d", w.Code)
+		t.Errorf("status = %d, want 200", w.Code)
 	}
 }
 
-func TestHandleListMembers_NonexistentRaft(t *testing.T) {
-	s := newTestServerWithGov(t)
-	req := httptest.NewRequest("GET", "/api/v1/governance/members?raft_id=nonexistent", nil)
+func TestHandleListMemories_Filters(t *testing.T) {
+	s := newTestServer("")
+	req := httptest.NewRequest("GET", "/api/v1/memories?since=2025-01-01&scope=work&min_importance=0.5&meta.source=slack", nil)
 	w := httptest.NewRecorder()
-	s.handleListMembers(w, req)
+	s.handler().ServeHTTP(w, req)
 
-	if w.Code != http.StatusNotFound {
-		t.Errorf("status = %d, want 404", w.Code)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	if body := strings.TrimSpace(w.Body.String()); body != "[]" {
+		t.Errorf("body = %s, want []", body)
 	}
 }
 
-// newTestServerWithGov creates a test server with real governance (uses t.TempDir).
-func newTestServerWithGov(t *testing.T) *Server {
-	t.Helper()
-	vdb := &mockVectorDB{}
-	mem := memory.New(vdb)
-	mockLLM := &mockLLMProvider{
-		completeResp: "mock response",
-		embedResp:    []float32{0.1, 0.2, 0.3},
+func TestHandleListMemories_BadFilter(t *testing.T) {
+	s := newTestServer("")
+	for _, query := range []string{"since=yesterday", "min_importance=high", "meta.bad-key=x"} {
+		req := httptest.NewRequest("GET", "/api/v1/memories?"+query, nil)
+		w := httptest.NewRecorder()
+		s.handler().ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", query, w.Code)
+		}
 	}
+}
 
-	gov, err := governance.New(governance.RaftConfig{
-		ID:      "test-otter",
-		DataDir: t.TempDir(),
-	}, mem)
+func TestMemoryFilterParams(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/v1/memories?until=2025-03-01T12:00:00Z&meta.pinned=true&meta.count=3&meta.source=slack", nil)
+	filter, err := memoryFilterParams(req)
 	if err != nil {
-		t.Fatal(err)
+		t.Fatalf("memoryFilterParams: %v", err)
+	}
+	if !filter.Until.Equal(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("until = %v", filter.Until)
+	}
+	if filter.Metadata["pinned"] != true || filter.Metadata["count"] != float64(3) || filter.Metadata["source"] != "slack" {
+		t.Errorf("metadata = %v", filter.Metadata)
 	}
+}
 
-	ag := agent.New(agent.Config{
-		Memory:     mem,
-		LLM:        mockLLM,
-		Governance: gov,
+// --- handleListRules ---
+
+func TestHandleListRules(t *testing.T) {
+	s := newTestServerWithGov(t)
+	req := httptest.NewRequest("GET", "/api/v1/governance/rules", nil)
+	w := httptest.NewRecorder()
+	s.handleListRules(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", w.Code)
+	}
+}
+
+func TestHandleListRules_Pagination(t *testing.T) {
+	s := newTestServerWithGov(t)
+	gov := s.agent.GetGovernance()
+	for _, scope := range []string{"a", "b", "c"} {
+		proposal, err := gov.ProposeRule(context.Background(), gov.GetID(),
+			&governance.Rule{Scope: scope, Body: "be kind", ProposedBy: gov.GetID(), Timestamp: time.Now()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := gov.CastVote(context.Background(), proposal.ProposalID, governance.VoteYes); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	list := func(query string) (*httptest.ResponseRecorder, RulePage) {
+		req := httptest.NewRequest("GET", "/api/v1/governance/rules"+query, nil)
+		w := httptest.NewRecorder()
+		s.handler().ServeHTTP(w, req)
+		var page RulePage
+		json.Unmarshal(w.Body.Bytes(), &page)
+		return w, page
+	}
+
+	// Without paging parameters the legacy shape is kept for peers
+	w, _ := list("?raft_id=" + gov.GetID())
+	var byScope map[string]*governance.Rule
+	if err := json.Unmarshal(w.Body.Bytes(), &byScope); err != nil || len(byScope) != 3 {
+		t.Errorf("legacy listing = %s", w.Body.String())
+	}
+
+	_, page := list("?limit=2")
+	if page.Total != 3 || len(page.Items) != 2 || page.Items[0].Scope != "a" || page.NextCursor == "" {
+		t.Fatalf("first page = %+v", page)
+	}
+	_, page = list("?limit=2&cursor=" + page.NextCursor)
+	if len(page.Items) != 1 || page.Items[0].Scope != "c" || page.Offset != 2 || page.NextCursor != "" {
+		t.Errorf("second page = %+v", page)
+	}
+	if _, page := list("?status=inactive"); page.Total != 0 {
+		t.Errorf("inactive total = %d", page.Total)
+	}
+	if w, _ := list("?status=pending"); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid status = %d, want 400", w.Code)
+	}
+}
+
+// --- handleProposeRule ---
+
+func TestHandleProposeRule_Success(t *testing.T) {
+	s := newTestServerWithGov(t)
+	otterID := s.agent.GetGovernance().GetID()
+	body, _ := json.Marshal(map[string]string{
+		"scope":       "safety",
+		"body":        "be kind",
+		"proposed_by": otterID,
+	})
+	req := httptest.NewRequest("POST", "/api/v1/governance/rules", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.handleProposeRule(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("status = %d, want 201, body: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandleProposeRule_VotingPeriod(t *testing.T) {
+	s := newTestServerWithGov(t)
+	otterID := s.agent.GetGovernance().GetID()
+	body, _ := json.Marshal(map[string]string{
+		"scope":         "safety",
+		"body":          "be kind",
+		"proposed_by":   otterID,
+		"voting_period": "72h",
 	})
+	req := httptest.NewRequest("POST", "/api/v1/governance/rules", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	s.handleProposeRule(w, req)
 
-	apiCfg := config.APIConfig{
-		Port:            0,
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201, body: %s", w.Code, w.Body.String())
+	}
+	var proposal governance.Proposal
+	json.NewDecoder(w.Body).Decode(&proposal)
+	if got := proposal.Deadline.Sub(proposal.ProposedAt); got != 72*time.Hour {
+		t.Errorf("voting period = %v, want 72h", got)
+	}
+
+	// List it back with its deadline
+	req = httptest.NewRequest("GET", "/api/v1/governance/proposals?status=open", nil)
+	w = httptest.NewRecorder()
+	s.handleListProposals(w, req)
+	var page ProposalPage
+	json.NewDecoder(w.Body).Decode(&page)
+	proposals := page.Items
+	if len(proposals) != 1 || proposals[0].Deadline.IsZero() {
+		t.Errorf("open proposals = %+v", proposals)
+	}
+	if len(proposals) == 1 && (len(proposals[0].EligibleVoters) != 1 || proposals[0].EligibleVoters[0] != otterID) {
+		t.Errorf("EligibleVoters = %v, want the proposer's solo raft", proposals[0].EligibleVoters)
+	}
+
+	for _, period := range []string{"soon", "1s"} {
+		body, _ := json.Marshal(map[string]string{
+			"scope": "safety", "body": "be kind", "proposed_by": otterID, "voting_period": period,
+		})
+		req := httptest.NewRequest("POST", "/api/v1/governance/rules", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		s.handleProposeRule(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("voting_period %q: status = %d, want 400", period, w.Code)
+		}
+	}
+}
+
+func TestHandleListProposals_Pagination(t *testing.T) {
+	s := newTestServerWithGov(t)
+	gov := s.agent.GetGovernance()
+	otterID := gov.GetID()
+	for i := 0; i < 5; i++ {
+		rule := &governance.Rule{Scope: fmt.Sprintf("scope-%d", i), Body: "be kind", ProposedBy: otterID, Timestamp: time.Now()}
+		if _, err := gov.ProposeRule(context.Background(), otterID, rule); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	list := func(query string) (ProposalPage, int) {
+		req := httptest.NewRequest("GET", "/api/v1/governance/proposals"+query, nil)
+		w := httptest.NewRecorder()
+		s.handler().ServeHTTP(w, req)
+		var page ProposalPage
+		json.NewDecoder(w.Body).Decode(&page)
+		return page, w.Code
+	}
+
+	// Walk every page by cursor, newest first
+	var seen []string
+	page, _ := list("?limit=2")
+	for {
+		if page.Total != 5 || page.Limit != 2 {
+			t.Fatalf("page = %+v", page.PageInfo)
+		}
+		for _, proposal := range page.Items {
+			seen = append(seen, proposal.ProposalID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		page, _ = list("?limit=2&cursor=" + page.NextCursor)
+	}
+	all := gov.ListProposals(governance.ProposalFilter{})
+	if len(seen) != 5 {
+		t.Fatalf("walked %d proposals, want 5", len(seen))
+	}
+	for i, proposal := range all {
+		if seen[i] != proposal.ProposalID {
+			t.Errorf("proposal %d = %s, want %s", i, seen[i], proposal.ProposalID)
+		}
+	}
+
+	if page, _ := list("?offset=4&limit=2"); len(page.Items) != 1 || page.Offset != 4 || page.NextCursor != "" {
+		t.Errorf("last page = %+v", page)
+	}
+	if page, _ := list("?raft_id=other-raft"); page.Total != 0 || page.Items == nil {
+		t.Errorf("other raft page = %+v", page)
+	}
+	if page, _ := list("?since=" + time.Now().Add(time.Hour).Format(time.RFC3339)); page.Total != 0 {
+		t.Errorf("future since total = %d", page.Total)
+	}
+	for _, query := range []string{"?limit=0", "?offset=-1", "?cursor=%21", "?cursor=YQ&offset=1", "?since=yesterday"} {
+		if _, code := list(query); code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", query, code)
+		}
+	}
+}
+
+func TestHandleGetProposal(t *testing.T) {
+	s := newTestServerWithGov(t)
+	gov := s.agent.GetGovernance()
+	proposal, err := gov.ProposeRule(context.Background(), gov.GetID(),
+		&governance.Rule{Scope: "safety", Body: "be kind", ProposedBy: gov.GetID(), Timestamp: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/governance/proposals/"+proposal.ProposalID, nil)
+	w := httptest.NewRecorder()
+	s.handler().ServeHTTP(w, req)
+	var got governance.Proposal
+	json.NewDecoder(w.Body).Decode(&got)
+	if w.Code != http.StatusOK || got.ProposalID != proposal.ProposalID {
+		t.Errorf("status = %d, proposal = %+v", w.Code, got)
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/governance/proposals/missing", nil)
+	w = httptest.NewRecorder()
+	s.handler().ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("missing proposal status = %d, want 404", w.Code)
+	}
+}
+
+func TestHandleListProposals_InvalidStatus(t *testing.T) {
+	s := newTestServerWithGov(t)
+	req := httptest.NewRequest("GET", "/api/v1/governance/proposals?status=maybe", nil)
+	w := httptest.NewRecorder()
+	s.handleListProposals(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
+
+func TestHandleGovernanceState(t *testing.T) {
+	s := newTestServerWithGov(t)
+
+	req := httptest.NewRequest("GET", "/api/v1/governance/state?as_of=yesterday", nil)
+	w := httptest.NewRecorder()
+	s.handleGovernanceState(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("bad as_of: status = %d, want 400", w.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/governance/state", nil)
+	w = httptest.NewRecorder()
+	s.handleGovernanceState(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	var state governance.GovernanceState
+	if err := json.NewDecoder(w.Body).Decode(&state); err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Rafts) != 1 || len(state.Rafts[0].Members) != 1 {
+		t.Errorf("current state should hold the own raft and member: %+v", state.Rafts)
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/governance/state?as_of=2000-01-01", nil)
+	w = httptest.NewRecorder()
+	s.handleGovernanceState(w, req)
+	state = governance.GovernanceState{}
+	json.NewDecoder(w.Body).Decode(&state)
+	if len(state.Rafts) != 0 {
+		t.Errorf("nothing existed in 2000, got %+v", state.Rafts)
+	}
+}
+
+func TestHandleAuditLog(t *testing.T) {
+	s := newTestServerWithGov(t)
+
+	req := httptest.NewRequest("GET", "/api/v1/governance/audit", nil)
+	w := httptest.NewRecorder()
+	s.handleAuditLog(w, req)
+
+	if ct := w.Header().Get("Content-Type"); ct != NDJSONContentType {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	var entry governance.AuditEntry
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Action != governance.AuditMemberJoined || entry.SubjectID != "test-otter" {
+		t.Errorf("first entry = %+v", entry)
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/governance/audit?since=bogus", nil)
+	w = httptest.NewRecorder()
+	s.handleAuditLog(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("bad since: status = %d, want 400", w.Code)
+	}
+}
+
+func TestHandleVerifyAudit(t *testing.T) {
+	s := newTestServerWithGov(t)
+
+	req := httptest.NewRequest("GET", "/api/v1/governance/audit/verify", nil)
+	w := httptest.NewRecorder()
+	s.handler().ServeHTTP(w, req)
+
+	var report governance.AuditVerification
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusOK || !report.Valid || report.Entries == 0 || report.Head == "" {
+		t.Errorf("status = %d, report = %+v", w.Code, report)
+	}
+}
+
+func TestHandleRuleHistory(t *testing.T) {
+	s := newTestServerWithGov(t)
+	gov := s.agent.GetGovernance()
+	ctx := context.Background()
+
+	adopt := func(rule *governance.Rule) *governance.Rule {
+		rule.ProposedBy = "test-otter"
+		proposal, err := gov.ProposeRule(ctx, "test-otter", rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := gov.CastVote(ctx, proposal.ProposalID, governance.VoteYes); err != nil {
+			t.Fatal(err)
+		}
+		return proposal.Rule
+	}
+	v1 := adopt(&governance.Rule{Scope: "safety", Body: "be kind"})
+	v2 := adopt(&governance.Rule{Body: "be kind and patient", BaseRuleID: v1.RuleID})
+
+	req := httptest.NewRequest("GET", "/api/v1/governance/rules/"+v1.RuleID+"/history", nil)
+	w := httptest.NewRecorder()
+	s.handler().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	var history []governance.RuleVersion
+	if err := json.NewDecoder(w.Body).Decode(&history); err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[1].RuleID != v2.RuleID || history[1].Version != 2 || !history[1].Active {
+		t.Errorf("unexpected history %+v", history)
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/governance/rules/missing/history", nil)
+	w = httptest.NewRecorder()
+	s.handler().ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown rule: status = %d, want 404", w.Code)
+	}
+}
+
+func TestHandleHolds(t *testing.T) {
+	gov := newTestServerWithGov(t)
+	s := NewServer(config.APIConfig{
 		Host:            "localhost",
-		Passphrase:      "",
+		Passphrase:      "secret123",
 		RateLimit:       100,
 		RateLimitWindow: time.Minute,
+		HoldApprovers:   map[string]string{"counsel": "counsel-pass", "alice": "alice-pass", "bob": "bob-pass"},
+	}, gov.agent)
+	admin, _ := s.jwtManager.GenerateToken("otter-user")
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+admin)
+		w := httptest.NewRecorder()
+		s.handler().ServeHTTP(w, req)
+		return w
 	}
 
-	return NewServer(apiCfg, ag)
+	if w := do("POST", "/api/v1/governance/holds", `{"kind":"audit","since":"2026-01-01T00:00:00Z","until":"2026-02-01T00:00:00Z","reason":"r","placed_by":"counsel"}`); w.Code != http.StatusUnauthorized {
+		t.Errorf("place without the placer's passphrase: status = %d, want 401", w.Code)
+	}
+	w := do("POST", "/api/v1/governance/holds", `{"kind":"audit","since":"2026-01-01T00:00:00Z","until":"2026-02-01T00:00:00Z","reason":"litigation","placed_by":"counsel","passphrase":"counsel-pass"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("place: status = %d: %s", w.Code, w.Body.String())
+	}
+	var hold governance.Hold
+	if err := json.NewDecoder(w.Body).Decode(&hold); err != nil {
+		t.Fatal(err)
+	}
+
+	if w := do("POST", "/api/v1/governance/holds", `{"kind":"proposal","subject_id":"missing","reason":"r","placed_by":"counsel","passphrase":"counsel-pass"}`); w.Code != http.StatusNotFound {
+		t.Errorf("unknown proposal: status = %d, want 404", w.Code)
+	}
+	if w := do("POST", "/api/v1/governance/holds", `{"kind":"audit","reason":"r","placed_by":"counsel","passphrase":"counsel-pass"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("missing range: status = %d, want 400", w.Code)
+	}
+
+	release := "/api/v1/governance/holds/" + hold.HoldID + "/release"
+	if w := do("POST", release, `{"approver":"counsel","passphrase":"counsel-pass"}`); w.Code != http.StatusConflict {
+		t.Errorf("placer approval: status = %d, want 409", w.Code)
+	}
+	if w := do("POST", release, `{"approver":"alice","passphrase":"bob-pass"}`); w.Code != http.StatusUnauthorized {
+		t.Errorf("wrong passphrase: status = %d, want 401", w.Code)
+	}
+	if w := do("POST", release, `{"approver":"alice","passphrase":"alice-pass"}`); w.Code != http.StatusOK {
+		t.Fatalf("first approval: status = %d: %s", w.Code, w.Body.String())
+	}
+	if w := do("POST", release, `{"approver":"alice","passphrase":"alice-pass"}`); w.Code != http.StatusConflict {
+		t.Errorf("repeat approval: status = %d, want 409", w.Code)
+	}
+
+	var active []governance.Hold
+	json.NewDecoder(do("GET", "/api/v1/governance/holds?status=active", "").Body).Decode(&active)
+	if len(active) != 1 {
+		t.Errorf("active holds = %d, want 1", len(active))
+	}
+
+	if w := do("POST", release, `{"approver":"bob","passphrase":"bob-pass"}`); w.Code != http.StatusOK {
+		t.Fatalf("second approval: status = %d: %s", w.Code, w.Body.String())
+	}
+	var released []governance.Hold
+	json.NewDecoder(do("GET", "/api/v1/governance/holds?status=released", "").Body).Decode(&released)
+	if len(released) != 1 || released[0].ReleasedAt == nil || len(released[0].ReleaseApprovals) != 2 {
+		t.Errorf("released holds = %+v", released)
+	}
+	if len(released) == 1 && released[0].ReleaseApprovals[0] != "alice" {
+		t.Errorf("first approver = %q, want the provisioned approver", released[0].ReleaseApprovals[0])
+	}
+
+	if w := do("POST", "/api/v1/governance/holds/missing/release", `{"approver":"alice","passphrase":"alice-pass"}`); w.Code != http.StatusNotFound {
+		t.Errorf("unknown hold: status = %d, want 404", w.Code)
+	}
+	if w := do("GET", "/api/v1/governance/holds?status=bogus", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("bad status: status = %d, want 400", w.Code)
+	}
+}
+
+// Token subjects are not approvers: every passphrase login shares one
+// subject and an admin can mint a token for any name
+func TestHandleReleaseHold_TokenSubjectsAreNotApprovers(t *testing.T) {
+	gov := newTestServerWithGov(t)
+	s := NewServer(config.APIConfig{
+		Host:            "localhost",
+		Passphrase:      "secret123",
+		RateLimit:       100,
+		RateLimitWindow: time.Minute,
+		HoldApprovers:   map[string]string{"alice": "alice-pass", "bob": "bob-pass"},
+	}, gov.agent)
+	hold, err := s.agent.GetGovernance().PlaceHold(context.Background(), governance.HoldRequest{
+		Kind: governance.HoldAudit, Since: time.Now().Add(-time.Hour), Until: time.Now(), Reason: "r", PlacedBy: "counsel",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	release := "/api/v1/governance/holds/" + hold.HoldID + "/release"
+
+	auth := httptest.NewRecorder()
+	s.handler().ServeHTTP(auth, httptest.NewRequest("POST", "/api/v1/auth", strings.NewReader(`{"passphrase":"secret123"}`)))
+	var login AuthResponse
+	json.NewDecoder(auth.Body).Decode(&login)
+
+	mint := httptest.NewRequest("POST", "/api/v1/auth/tokens", strings.NewReader(`{"subject":"bob","role":"admin"}`))
+	mint.Header.Set("Authorization", "Bearer "+login.Token)
+	minted := httptest.NewRecorder()
+	s.handler().ServeHTTP(minted, mint)
+	var bob MintTokenResponse
+	json.NewDecoder(minted.Body).Decode(&bob)
+	if login.Token == "" || bob.Token == "" {
+		t.Fatalf("login %q, minted %s", login.Token, minted.Body.String())
+	}
+
+	for name, token := range map[string]string{"passphrase login": login.Token, "minted bob": bob.Token} {
+		req := httptest.NewRequest("POST", release, strings.NewReader(`{}`))
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		s.handler().ServeHTTP(w, req)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("%s without approver credentials: status = %d, want 401", name, w.Code)
+		}
+	}
+	if got := s.agent.GetGovernance().GetHolds(false); len(got) != 1 || len(got[0].ReleaseApprovals) != 0 {
+		t.Errorf("token subjects must not be recorded as approvers: %+v", got)
+	}
+
+	// Without provisioned approvers no hold can be released
+	unprovisioned := newTestServerWithGov(t)
+	w := httptest.NewRecorder()
+	unprovisioned.handler().ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/governance/holds/x/release", strings.NewReader(`{"approver":"alice","passphrase":"alice-pass"}`)))
+	if w.Code != http.StatusConflict {
+		t.Errorf("unprovisioned approval: status = %d, want 409", w.Code)
+	}
+}
+
+func TestHandleProposeEviction(t *testing.T) {
+	s := newTestServerWithGov(t)
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"missing member", `{"proposed_by": "test-otter"}`, http.StatusBadRequest},
+		{"bad voting period", `{"member_id": "otter-2", "proposed_by": "test-otter", "voting_period": "soon"}`, http.StatusBadRequest},
+		{"unknown member", `{"member_id": "otter-2", "proposed_by": "test-otter"}`, http.StatusBadRequest},
+		{"own eviction", `{"member_id": "test-otter", "proposed_by": "test-otter"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/v1/governance/evictions", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			s.handleProposeEviction(w, req)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleFederation_RejectsUnknownSender(t *testing.T) {
+	s := newTestServerWithGov(t)
+
+	// Served without a token: peers authenticate with the envelope signature
+	body := `{"type": "member.revoked", "raft_id": "test-otter", "sender_id": "otter-9", "timestamp": "` +
+		time.Now().Format(time.RFC3339) + `", "payload": {}, "signature": "c2ln"}`
+	req := httptest.NewRequest("POST", "/api/v1/governance/federation", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	s.handler().ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("POST", "/api/v1/governance/federation", strings.NewReader("not json"))
+	w = httptest.NewRecorder()
+	s.handler().ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
+
+func TestHandleProposeRule_MissingFields(t *testing.T) {
+	s := newTestServerWithGov(t)
+	body := `{"scope": "safety"}`
+	req := httptest.NewRequest("POST", "/api/v1/governance/rules", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.handleProposeRule(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
+
+func TestHandleProposeRule_BodyTooLong(t *testing.T) {
+	s := newTestServerWithGov(t)
+	longBody := strings.Repeat("x", 1001)
+	body, _ := json.Marshal(map[string]string{
+		"scope":       "safety",
+		"body":        longBody,
+		"proposed_by": "otter-1",
+	})
+	req := httptest.NewRequest("POST", "/api/v1/governance/rules", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.handleProposeRule(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
+
+func TestHandleProposeRule_ScopeTooLong(t *testing.T) {
+	s := newTestServerWithGov(t)
+	longScope := strings.Repeat("x", 101)
+	body, _ := json.Marshal(map[string]string{
+		"scope":       longScope,
+		"body":        "rule",
+		"proposed_by": "otter-1",
+	})
+	req := httptest.NewRequest("POST", "/api/v1/governance/rules", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.handleProposeRule(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
+
+// --- handleVote ---
+
+func TestHandleVote_MissingFields(t *testing.T) {
+	s := newTestServerWithGov(t)
+	body := `{"proposal_id": "p1"}`
+	req := httptest.NewRequest("POST", "/api/v1/governance/vote", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.handleVote(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
+
+func TestHandleVote_InvalidVoteType(t *testing.T) {
+	s := newTestServerWithGov(t)
+	body := `{"proposal_id": "p1", "voter_id": "v1", "vote": "MAYBE"}`
+	req := httptest.NewRequest("POST", "/api/v1/governance/vote", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.handleVote(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
+
+func TestHandleVote_AsLocalOtter(t *testing.T) {
+	s := newTestServerWithGov(t)
+	gov := s.agent.GetGovernance()
+	proposal, err := gov.ProposeRule(context.Background(), "test-otter", &governance.Rule{Scope: "safety", Body: "be kind", ProposedBy: "test-otter"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := fmt.Sprintf(`{"proposal_id": %q, "voter_id": "test-otter", "vote": "YES"}`, proposal.ProposalID)
+	req := httptest.NewRequest("POST", "/api/v1/governance/vote", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	s.handleVote(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandleVote_BadSignature(t *testing.T) {
+	s := newTestServerWithGov(t)
+	gov := s.agent.GetGovernance()
+	proposal, _ := gov.ProposeRule(context.Background(), "test-otter", &governance.Rule{Scope: "safety", Body: "be kind", ProposedBy: "test-otter"})
+
+	body := fmt.Sprintf(`{"proposal_id": %q, "voter_id": "test-otter", "vote": "YES", "timestamp": %q, "signature": "deadbeef"}`,
+		proposal.ProposalID, time.Now().Format(time.RFC3339))
+	req := httptest.NewRequest("POST", "/api/v1/governance/vote", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	s.handleVote(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", w.Code)
+	}
+}
+
+func TestHandleVote_RemoteVoterRequiresTimestamp(t *testing.T) {
+	s := newTestServerWithGov(t)
+	body := `{"proposal_id": "p1", "voter_id": "someone-else", "vote": "YES"}`
+	req := httptest.NewRequest("POST", "/api/v1/governance/vote", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	s.handleVote(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
+
+// --- handleJoinRaft ---
+
+func TestHandleJoinRaft_MissingFields(t *testing.T) {
+	s := newTestServerWithGov(t)
+	body := `{"raft_id": "r1"}`
+	req := httptest.NewRequest("POST", "/api/v1/governance/join", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.handleJoinRaft(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
+
+func TestHandleJoinRaft_InvalidHex(t *testing.T) {
+	s := newTestServerWithGov(t)
+	body := `{"raft_id": "r1", "requester_id": "o2", "public_key": "not-hex"}`
+	req := httptest.NewRequest("POST", "/api/v1/governance/join", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.handleJoinRaft(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
+
+// answeredJoinBody requests a join challenge for requesterID through the
+// API and returns a join request body answering it with a fresh key
+func answeredJoinBody(t *testing.T, s *Server, requesterID string) map[string]interface{} {
+	t.Helper()
+	raftID := s.agent.GetGovernance().GetID()
+	body, _ := json.Marshal(JoinChallengeRequest{RaftID: raftID, RequesterID: requesterID})
+	w := httptest.NewRecorder()
+	s.handleJoinChallenge(w, httptest.NewRequest("POST", "/api/v1/governance/join/challenge", bytes.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("challenge status = %d, body: %s", w.Code, w.Body.String())
+	}
+	var challenge governance.JoinChallenge
+	json.Unmarshal(w.Body.Bytes(), &challenge)
+
+	peer, err := governance.NewCryptoSystem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := peer.SignJoinChallenge(&challenge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[string]interface{}{
+		"raft_id":             raftID,
+		"requester_id":        requesterID,
+		"public_key":          hex.EncodeToString(peer.GetPublicKey()),
+		"signing_key":         hex.EncodeToString(peer.GetSigningPublicKey()),
+		"nonce":               challenge.Nonce,
+		"challenge_signature": hex.EncodeToString(sig),
+	}
+}
+
+func TestHandleJoinRaft_Success(t *testing.T) {
+	s := newTestServerWithGov(t)
+	body, _ := json.Marshal(answeredJoinBody(t, s, "new-otter"))
+	req := httptest.NewRequest("POST", "/api/v1/governance/join", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.handleJoinRaft(w, req)
+
+	// Uninvited requesters wait for the raft's admission vote
+	var resp JoinRaftResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if w.Code != http.StatusAccepted || resp.State != governance.StatePending || resp.ProposalID == "" {
+		t.Fatalf("status = %d, want 202 and a pending admission, body: %s", w.Code, w.Body.String())
+	}
+
+	// The answer cannot be replayed
+	w = httptest.NewRecorder()
+	s.handleJoinRaft(w, httptest.NewRequest("POST", "/api/v1/governance/join", bytes.NewReader(body)))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("replayed answer: status = %d, want 401", w.Code)
+	}
+
+	// Nor can the requester ask again while pending
+	again, _ := json.Marshal(answeredJoinBody(t, s, "new-otter"))
+	w = httptest.NewRecorder()
+	s.handleJoinRaft(w, httptest.NewRequest("POST", "/api/v1/governance/join", bytes.NewReader(again)))
+	if w.Code != http.StatusConflict {
+		t.Errorf("second request: status = %d, want 409", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	s.handleListJoinRequests(w, httptest.NewRequest("GET", "/api/v1/governance/join-requests?raft_id="+s.agent.GetGovernance().GetID(), nil))
+	var pending []governance.PendingJoin
+	json.Unmarshal(w.Body.Bytes(), &pending)
+	if len(pending) != 1 || pending[0].MemberID != "new-otter" || pending[0].ProposalID != resp.ProposalID {
+		t.Errorf("pending joins = %+v", pending)
+	}
+}
+
+func TestHandleJoinRaft_RequiresChallenge(t *testing.T) {
+	s := newTestServerWithGov(t)
+	body, _ := json.Marshal(map[string]string{
+		"raft_id":      s.agent.GetGovernance().GetID(),
+		"requester_id": "new-otter",
+		"public_key":   "abcdef1234567890",
+	})
+	w := httptest.NewRecorder()
+	s.handleJoinRaft(w, httptest.NewRequest("POST", "/api/v1/governance/join", bytes.NewReader(body)))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401, body: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandleCreateInvite(t *testing.T) {
+	s := newTestServerWithGov(t)
+	raftID := s.agent.GetGovernance().GetID()
+
+	req := httptest.NewRequest("POST", "/api/v1/governance/rafts/"+raftID+"/invites", strings.NewReader(`{"invitee_id": "new-otter", "ttl": "1h"}`))
+	req.SetPathValue("id", raftID)
+	w := httptest.NewRecorder()
+	s.handleCreateInvite(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201, body: %s", w.Code, w.Body.String())
+	}
+	var invite InviteResponse
+	json.Unmarshal(w.Body.Bytes(), &invite)
+	if invite.Token == "" || invite.Invite == nil || invite.Invite.InviteeID != "new-otter" {
+		t.Fatalf("response = %+v", invite)
+	}
+
+	// Another otter cannot use it
+	body := answeredJoinBody(t, s, "other-otter")
+	body["invite"] = invite.Token
+	data, _ := json.Marshal(body)
+	w = httptest.NewRecorder()
+	s.handleJoinRaft(w, httptest.NewRequest("POST", "/api/v1/governance/join", bytes.NewReader(data)))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("other invitee: status = %d, want 403", w.Code)
+	}
+
+	body = answeredJoinBody(t, s, "new-otter")
+	body["invite"] = invite.Token
+	data, _ = json.Marshal(body)
+	w = httptest.NewRecorder()
+	s.handleJoinRaft(w, httptest.NewRequest("POST", "/api/v1/governance/join", bytes.NewReader(data)))
+	if w.Code != http.StatusOK {
+		t.Errorf("invited join: status = %d, want 200, body: %s", w.Code, w.Body.String())
+	}
+
+	for _, tt := range []struct {
+		raftID, body string
+		want         int
+	}{
+		{"missing", `{}`, http.StatusNotFound},
+		{raftID, `{"ttl": "soon"}`, http.StatusBadRequest},
+		{raftID, `{"ttl": "720h"}`, http.StatusBadRequest},
+	} {
+		req := httptest.NewRequest("POST", "/api/v1/governance/rafts/"+tt.raftID+"/invites", strings.NewReader(tt.body))
+		req.SetPathValue("id", tt.raftID)
+		w := httptest.NewRecorder()
+		s.handleCreateInvite(w, req)
+		if w.Code != tt.want {
+			t.Errorf("%s %s: status = %d, want %d", tt.raftID, tt.body, w.Code, tt.want)
+		}
+	}
+
+	// Issuing invites is an admin action
+	authed := newTestServerWithGovAuth(t, "secret123")
+	member, _ := authed.jwtManager.GenerateRoleToken("bot", RoleMember, time.Hour)
+	req = httptest.NewRequest("POST", "/api/v1/governance/rafts/"+raftID+"/invites", strings.NewReader(`{}`))
+	req.Header.Set("Authorization", "Bearer "+member)
+	w = httptest.NewRecorder()
+	authed.handler().ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("member token: status = %d, want 403", w.Code)
+	}
+}
+
+func TestHandleJoinChallenge_Errors(t *testing.T) {
+	s := newTestServerWithGov(t)
+	for body, want := range map[string]int{
+		`{"raft_id": "r1"}`:                            http.StatusBadRequest,
+		`{"raft_id": "missing", "requester_id": "o2"}`: http.StatusNotFound,
+	} {
+		w := httptest.NewRecorder()
+		s.handleJoinChallenge(w, httptest.NewRequest("POST", "/api/v1/governance/join/challenge", strings.NewReader(body)))
+		if w.Code != want {
+			t.Errorf("%s: status = %d, want %d", body, w.Code, want)
+		}
+	}
+}
+
+func TestHandleJoinRaft_ExchangesCapabilities(t *testing.T) {
+	s := newTestServerWithGov(t)
+	gov := s.agent.GetGovernance()
+	raftID := gov.GetID()
+
+	// A descriptor relabelled for another otter no longer verifies
+	descriptor, _ := gov.CapabilityDescriptor()
+	descriptor.OtterID = "new-otter"
+	body, _ := json.Marshal(map[string]interface{}{
+		"raft_id":      raftID,
+		"requester_id": "new-otter",
+		"public_key":   "abcdef1234567890",
+		"capabilities": descriptor,
+	})
+	req := httptest.NewRequest("POST", "/api/v1/governance/join", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	s.handleJoinRaft(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("tampered descriptor: status = %d, want 403", w.Code)
+	}
+
+	body, _ = json.Marshal(answeredJoinBody(t, s, "legacy-otter"))
+	req = httptest.NewRequest("POST", "/api/v1/governance/join", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	s.handleJoinRaft(w, req)
+
+	var resp JoinRaftResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if w.Code != http.StatusAccepted || resp.Capabilities == nil || resp.Negotiated == nil {
+		t.Fatalf("status = %d, body: %s", w.Code, w.Body.String())
+	}
+	if err := governance.VerifyCapabilityDescriptor(resp.Capabilities); err != nil {
+		t.Errorf("response descriptor: %v", err)
+	}
+}
+
+func TestHandleCapabilities(t *testing.T) {
+	s := newTestServerWithGov(t)
+	req := httptest.NewRequest("GET", "/api/v1/governance/capabilities", nil)
+	w := httptest.NewRecorder()
+	s.handler().ServeHTTP(w, req)
+
+	var descriptor governance.CapabilityDescriptor
+	json.Unmarshal(w.Body.Bytes(), &descriptor)
+	if w.Code != http.StatusOK || descriptor.Capabilities.ProtocolVersion != governance.ProtocolVersion {
+		t.Fatalf("status = %d, body: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandleListConflicts(t *testing.T) {
+	s := newTestServerWithGov(t)
+	req := httptest.NewRequest("GET", "/api/v1/governance/conflicts?raft_id=raft-2", nil)
+	w := httptest.NewRecorder()
+	s.handler().ServeHTTP(w, req)
+
+	var reports []governance.ConflictReport
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &reports) != nil || reports == nil || len(reports) != 0 {
+		t.Errorf("status = %d, body: %s, want an empty list", w.Code, w.Body.String())
+	}
+}
+
+// --- handleListMembers ---
+
+func TestHandleListMembers(t *testing.T) {
+	s := newTestServerWithGov(t)
+	req := httptest.NewRequest("GET", "/api/v1/governance/members", nil)
+	w := httptest.NewRecorder()
+	s.handleListMembers(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", w.Code)
+	}
+}
+
+func TestHandleListMembers_WithRaftID(t *testing.T) {
+	s := newTestServerWithGov(t)
+	raftID := s.agent.GetGovernance().GetID()
+	req := httptest.NewRequest("GET", "/api/v1/governance/members?raft_id="+raftID, nil)
+	w := httptest.NewRecorder()
+	s.handleListMembers(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d", w.Code)
+	}
+	var members []struct {
+		ID       string              `json:"id"`
+		Liveness governance.Liveness `json:"liveness"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &members); err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 1 || members[0].Liveness.Status != governance.LivenessSelf {
+		t.Errorf("members = %+v, want this otter with liveness self", members)
+	}
+}
+
+func TestHandleListMembers_NonexistentRaft(t *testing.T) {
+	s := newTestServerWithGov(t)
+	req := httptest.NewRequest("GET", "/api/v1/governance/members?raft_id=nonexistent", nil)
+	w := httptest.NewRecorder()
+	s.handleListMembers(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", w.Code)
+	}
+}
+
+// newTestServerWithGov creates a test server with real governance (uses t.TempDir).
+func newTestServerWithGov(t *testing.T) *Server {
+	t.Helper()
+	vdb := &mockVectorDB{}
+	mem := memory.New(vdb)
+	mockLLM := &mockLLMProvider{
+		completeResp: "mock response",
+		embedResp:    []float32{0.1, 0.2, 0.3},
+	}
+
+	gov, err := governance.New(governance.RaftConfig{
+		ID:      "test-otter",
+		DataDir: t.TempDir(),
+	}, mem)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ag := agent.New(agent.Config{
+		Memory:     mem,
+		LLM:        mockLLM,
+		Governance: gov,
+	})
+
+	apiCfg := config.APIConfig{
+		Port:            0,
+		Host:            "localhost",
+		Passphrase:      "",
+		RateLimit:       100,
+		RateLimitWindow: time.Minute,
+	}
+
+	return NewServer(apiCfg, ag)
+}
+
+// newTestServerWithGovAuth is newTestServerWithGov with authentication on,
+// for handlers that act as the caller named in the token.
+func newTestServerWithGovAuth(t *testing.T, passphrase string) *Server {
+	t.Helper()
+	s := newTestServerWithGov(t)
+	return NewServer(config.APIConfig{
+		Host:            "localhost",
+		Passphrase:      passphrase,
+		RateLimit:       100,
+		RateLimitWindow: time.Minute,
+	}, s.agent)
+}
+
+// --- governance LLM tasks ---
+
+func TestHandleListLLMTasks(t *testing.T) {
+	s := newTestServerWithGov(t)
+	req := httptest.NewRequest("GET", "/api/v1/governance/tasks?status=pending", nil)
+	w := httptest.NewRecorder()
+	s.handleListLLMTasks(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", w.Code)
+	}
+}
+
+func TestHandleRetryLLMTask_NotFound(t *testing.T) {
+	s := newTestServerWithGov(t)
+	req := httptest.NewRequest("POST", "/api/v1/governance/tasks/missing/retry", nil)
+	req.SetPathValue("id", "missing")
+	w := httptest.NewRecorder()
+	s.handleRetryLLMTask(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", w.Code)
+	}
+}
+
+// --- rule drift ---
+
+func TestHandleRuleSetDigest(t *testing.T) {
+	s := newTestServerWithGov(t)
+	req := httptest.NewRequest("GET", "/api/v1/governance/rafts/test-otter/digest", nil)
+	req.SetPathValue("id", "test-otter")
+	w := httptest.NewRecorder()
+	s.handleRuleSetDigest(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	var digest governance.RuleSetDigest
+	if err := json.NewDecoder(w.Body).Decode(&digest); err != nil {
+		t.Fatal(err)
+	}
+	if digest.RaftID != "test-otter" || digest.Root == "" {
+		t.Errorf("unexpected digest: %+v", digest)
+	}
+}
+
+func TestHandleRuleSetDigest_UnknownRaft(t *testing.T) {
+	s := newTestServerWithGov(t)
+	req := httptest.NewRequest("GET", "/api/v1/governance/rafts/nope/digest", nil)
+	req.SetPathValue("id", "nope")
+	w := httptest.NewRecorder()
+	s.handleRuleSetDigest(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", w.Code)
+	}
+}
+
+func TestHandleVotingPolicy(t *testing.T) {
+	s := newTestServerWithGov(t)
+	req := httptest.NewRequest("GET", "/api/v1/governance/rafts/test-otter/voting-policy", nil)
+	req.SetPathValue("id", "test-otter")
+	w := httptest.NewRecorder()
+	s.handleVotingPolicy(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	var policy governance.VotingPolicy
+	if err := json.NewDecoder(w.Body).Decode(&policy); err != nil {
+		t.Fatal(err)
+	}
+	if policy != governance.DefaultVotingPolicy {
+		t.Errorf("policy = %+v, want the default", policy)
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/governance/rafts/nope/voting-policy", nil)
+	req.SetPathValue("id", "nope")
+	w = httptest.NewRecorder()
+	s.handleVotingPolicy(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown raft: status = %d, want 404", w.Code)
+	}
+}
+
+func TestHandleSuspensions(t *testing.T) {
+	s := newTestServerWithGov(t)
+
+	req := httptest.NewRequest("POST", "/api/v1/governance/suspensions", strings.NewReader(`{"rule_id":"r1","proposed_by":"test-otter"}`))
+	w := httptest.NewRecorder()
+	s.handleProposeSuspension(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("missing reason: status = %d, want 400", w.Code)
+	}
+
+	req = httptest.NewRequest("POST", "/api/v1/governance/suspensions", strings.NewReader(`{"rule_id":"r1","proposed_by":"test-otter","reason":"harm"}`))
+	w = httptest.NewRecorder()
+	s.handleProposeSuspension(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown rule: status = %d, want 404", w.Code)
+	}
+
+	req = httptest.NewRequest("POST", "/api/v1/governance/suspensions/r1/revote", strings.NewReader(`{"proposed_by":"test-otter"}`))
+	req.SetPathValue("rule_id", "r1")
+	w = httptest.NewRecorder()
+	s.handleReopenRevote(w, req)
+	if w.Code != http.StatusConflict {
+		t.Errorf("rule not suspended: status = %d, want 409", w.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/governance/suspensions", nil)
+	w = httptest.NewRecorder()
+	s.handleListSuspensions(w, req)
+	if w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != "[]" {
+		t.Errorf("list: status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandleRaftMetadata(t *testing.T) {
+	s := newTestServerWithGov(t)
+
+	req := httptest.NewRequest("PUT", "/api/v1/governance/rafts/test-otter/metadata", strings.NewReader(`{"name":"Home","purpose":"Help my owner","tags":["Solo"]}`))
+	req.SetPathValue("id", "test-otter")
+	w := httptest.NewRecorder()
+	s.handleUpdateRaftMetadata(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/governance/rafts?tag=solo", nil)
+	w = httptest.NewRecorder()
+	s.handleListRafts(w, req)
+	var rafts []governance.RaftSummary
+	if err := json.NewDecoder(w.Body).Decode(&rafts); err != nil {
+		t.Fatal(err)
+	}
+	if len(rafts) != 1 || rafts[0].Metadata.Name != "Home" || rafts[0].Metadata.Purpose != "Help my owner" {
+		t.Errorf("rafts = %+v", rafts)
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/governance/rafts?tag=other", nil)
+	w = httptest.NewRecorder()
+	s.handleListRafts(w, req)
+	if strings.TrimSpace(w.Body.String()) != "[]" {
+		t.Errorf("unmatched tag: body = %s", w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/governance/rafts/nope/metadata", nil)
+	req.SetPathValue("id", "nope")
+	w = httptest.NewRecorder()
+	s.handleGetRaftMetadata(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown raft: status = %d, want 404", w.Code)
+	}
+}
+
+func TestHandleNegotiations(t *testing.T) {
+	s := newTestServerWithGov(t)
+
+	req := httptest.NewRequest("GET", "/api/v1/governance/negotiations?status=resolved", nil)
+	w := httptest.NewRecorder()
+	s.handleListNegotiations(w, req)
+	if w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != "[]" {
+		t.Errorf("list: status = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/governance/negotiations/nope", nil)
+	req.SetPathValue("id", "nope")
+	w = httptest.NewRecorder()
+	s.handleGetNegotiation(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("get unknown: status = %d, want 404", w.Code)
+	}
+
+	// Reviews are recorded against the caller, so they need a token
+	for _, action := range []string{"approve", "reject"} {
+		req = httptest.NewRequest("POST", "/api/v1/governance/negotiations/nope/"+action, nil)
+		w = httptest.NewRecorder()
+		s.handler().ServeHTTP(w, req)
+		if w.Code != http.StatusForbidden {
+			t.Errorf("anonymous %s: status = %d, want 403", action, w.Code)
+		}
+	}
+
+	authed := newTestServerWithGovAuth(t, "secret123")
+	admin, _ := authed.jwtManager.GenerateToken("owner")
+	for action, body := range map[string]string{"approve": `{"body":"be kind"}`, "reject": ""} {
+		req = httptest.NewRequest("POST", "/api/v1/governance/negotiations/nope/"+action, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+admin)
+		w = httptest.NewRecorder()
+		authed.handler().ServeHTTP(w, req)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("%s unknown: status = %d, want 404", action, w.Code)
+		}
+	}
+}
+
+func TestHandleDriftReports(t *testing.T) {
+	s := newTestServerWithGov(t)
+	req := httptest.NewRequest("GET", "/api/v1/governance/drift?refresh=true", nil)
+	w := httptest.NewRecorder()
+	s.handleDriftReports(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", w.Code)
+	}
+}
+
+func TestHandleReconcileRules_MissingPeer(t *testing.T) {
+	s := newTestServerWithGov(t)
+	req := httptest.NewRequest("POST", "/api/v1/governance/rafts/test-otter/reconcile", strings.NewReader(`{}`))
+	req.SetPathValue("id", "test-otter")
+	w := httptest.NewRecorder()
+	s.handleReconcileRules(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
+
+func TestHandleReconcileRules_UnknownRaft(t *testing.T) {
+	s := newTestServerWithGov(t)
+	req := httptest.NewRequest("POST", "/api/v1/governance/rafts/nope/reconcile", strings.NewReader(`{"peer_id":"p"}`))
+	req.SetPathValue("id", "nope")
+	w := httptest.NewRecorder()
+	s.handleReconcileRules(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", w.Code)
+	}
+}
+
+// Peers join, check drift and reconcile through the public, signed routes,
+// so federation keeps working when an operator passphrase is set
+func TestPeerReads_WithPassphrase(t *testing.T) {
+	s := newTestServerWithGovAuth(t, "secret123")
+	srv := httptest.NewServer(s.handler())
+	defer srv.Close()
+
+	for _, path := range []string{
+		"/api/v1/governance/rafts/test-otter/digest",
+		"/api/v1/governance/rafts/test-otter/rules",
+		"/api/v1/governance/rafts/test-otter/keys",
+	} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("%s: status = %d, want 200", path, resp.StatusCode)
+		}
+		if resp.Header.Get(governance.HeaderOtterSignature) == "" || resp.Header.Get(governance.HeaderOtterID) != "test-otter" {
+			t.Errorf("%s: response is not signed by test-otter: %v", path, resp.Header)
+		}
+	}
+	// Operator routes still need a token
+	resp, err := http.Get(srv.URL + "/api/v1/governance/members")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("members: status = %d, want 401", resp.StatusCode)
+	}
+
+	invite, err := s.agent.GetGovernance().CreateInvite("test-otter", "peer-otter", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := invite.Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+	peer, err := governance.New(governance.RaftConfig{ID: "peer-otter", DataDir: t.TempDir()}, memory.New(&mockVectorDB{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peer.Shutdown(context.Background())
+	if err := peer.JoinRaftWithInvite(context.Background(), token, srv.URL, nil); err != nil {
+		t.Fatalf("join with a passphrase set: %v", err)
+	}
+
+	reports, err := peer.CheckDrift(context.Background(), "test-otter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 || reports[0].Error != "" || !reports[0].InSync {
+		t.Errorf("drift check with a passphrase set: %+v", reports)
+	}
+}
+
+// --- pinned memories ---
+
+func TestHandleListPinned(t *testing.T) {
+	s := newTestServer("")
+	req := httptest.NewRequest("GET", "/api/v1/memories/pinned", nil)
+	w := httptest.NewRecorder()
+	s.handleListPinned(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", w.Code)
+	}
+}
+
+func TestHandleUnpinMemory_NotFound(t *testing.T) {
+	s := newTestServer("")
+	req := httptest.NewRequest("DELETE", "/api/v1/memories/pinned/missing", nil)
+	req.SetPathValue("id", "missing")
+	w := httptest.NewRecorder()
+	s.handleUnpinMemory(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", w.Code)
+	}
+}
+
+// --- musings ---
+
+func TestHandleListMusings(t *testing.T) {
+	s := newTestServer("")
+	req := httptest.NewRequest("GET", "/api/v1/musings?limit=5", nil)
+	w := httptest.NewRecorder()
+	s.handleListMusings(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	var resp MusingsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Musings == nil || resp.Status.Enabled {
+		t.Errorf("unexpected response %+v", resp)
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/musings?limit=zero", nil)
+	w = httptest.NewRecorder()
+	s.handleListMusings(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
+
+// --- memory versions ---
+
+func TestHandleRestoreMemory_Unsupported(t *testing.T) {
+	s := newTestServer("")
+	req := httptest.NewRequest("POST", "/api/v1/memories/m1/restore", nil)
+	w := httptest.NewRecorder()
+	s.handler().ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("status = %d, want 501", w.Code)
+	}
+}
+
+func TestHandleRevertMemory_BadRequest(t *testing.T) {
+	s := newTestServer("")
+	req := httptest.NewRequest("POST", "/api/v1/memories/m1/revert", strings.NewReader(`{"version": 0}`))
+	w := httptest.NewRecorder()
+	s.handler().ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
+
+// --- personality ---
+
+func TestHandleGetPersonality(t *testing.T) {
+	s := newTestServer("")
+	if err := s.agent.SeedPersonality(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/personality", nil)
+	w := httptest.NewRecorder()
+	s.handler().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	var resp agent.PersonalityStatus
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Traits) != len(agent.DefaultPersonality) || resp.DriftEnabled {
+		t.Errorf("unexpected response %+v", resp)
+	}
+}
+
+// --- onboarding ---
+
+func TestHandleCompleteOnboardingStep_NotFound(t *testing.T) {
+	s := newTestServer("")
+	req := httptest.NewRequest("POST", "/api/v1/governance/onboarding/raft-1:otter-2/steps/welcome/complete", nil)
+	w := httptest.NewRecorder()
+	s.handler().ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", w.Code)
+	}
+}
+
+// --- handleStatus ---
+
+func TestHandleStatus(t *testing.T) {
+	s := newTestServerWithGov(t)
+	req := httptest.NewRequest("GET", "/api/v1/status", nil)
+	w := httptest.NewRecorder()
+	s.handleStatus(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	var resp StatusResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.OtterID == "" || resp.Version == "" {
+		t.Errorf("expected otter id and version, got %+v", resp)
+	}
+	if len(resp.Rafts) == 0 {
+		t.Error("expected at least the self raft")
+	}
+}
+
+func TestHandleChat_ContextOptions(t *testing.T) {
+	s := newTestServer("")
+	body := `{"message": "hello", "no_memory": true, "no_governance": true, "scope": "safety"}`
+	req := httptest.NewRequest("POST", "/api/v1/chat", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	s.handleChat(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", w.Code)
+	}
+}
+
+func TestHandleChat_InvalidScope(t *testing.T) {
+	s := newTestServer("")
+	body := fmt.Sprintf(`{"message": "hello", "scope": %q}`, strings.Repeat("s", agent.MaxContextScopeLength+1))
+	req := httptest.NewRequest("POST", "/api/v1/chat", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	s.handleChat(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
+
+func TestHandleChat_ChannelRequiresPlatform(t *testing.T) {
+	s := newTestServer("")
+	for body, want := range map[string]int{
+		`{"message": "hello", "platform": "slack", "channel_id": "general"}`: http.StatusOK,
+		`{"message": "hello", "channel_id": "general"}`:                      http.StatusBadRequest,
+	} {
+		req := httptest.NewRequest("POST", "/api/v1/chat", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		s.handleChat(w, req)
+		if w.Code != want {
+			t.Errorf("%s: status = %d, want %d", body, w.Code, want)
+		}
+	}
+}
+
+// --- chaos mode ---
+
+func TestHandleChaos_Disabled(t *testing.T) {
+	s := newTestServerWithGov(t)
+
+	req := httptest.NewRequest("GET", "/api/v1/governance/chaos", nil)
+	w := httptest.NewRecorder()
+	s.handleGetChaos(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("GET status = %d, want 403", w.Code)
+	}
+
+	req = httptest.NewRequest("PUT", "/api/v1/governance/chaos", strings.NewReader(`{"drop_rate": 0.5}`))
+	w = httptest.NewRecorder()
+	s.handleSetChaos(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("PUT status = %d, want 403", w.Code)
+	}
+}
+
+func TestHandleSetChaos(t *testing.T) {
+	gov, err := governance.New(governance.RaftConfig{
+		ID:      "test-otter",
+		DataDir: t.TempDir(),
+		Chaos:   &governance.ChaosConfig{},
+	}, memory.New(&mockVectorDB{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewServer(config.APIConfig{RateLimit: 100, RateLimitWindow: time.Minute}, agent.New(agent.Config{Governance: gov}))
+
+	req := httptest.NewRequest("PUT", "/api/v1/governance/chaos", strings.NewReader(`{"drop_rate": 2}`))
+	w := httptest.NewRecorder()
+	s.handleSetChaos(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("invalid rate status = %d, want 400", w.Code)
+	}
+
+	req = httptest.NewRequest("PUT", "/api/v1/governance/chaos", strings.NewReader(`{"drop_rate": 0.25, "max_delay_ms": 50, "partitioned": ["otter-2"]}`))
+	w = httptest.NewRecorder()
+	s.handleSetChaos(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+	}
+	var resp ChaosResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.DropRate != 0.25 || resp.MaxDelayMS != 50 || len(resp.Partitioned) != 1 {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+// --- archived rafts ---
+
+func TestHandleArchiveRaft(t *testing.T) {
+	s := newTestServerWithGov(t)
+
+	for path, want := range map[string]int{
+		"/api/v1/governance/rafts/missing/archive":    http.StatusNotFound,
+		"/api/v1/governance/rafts/test-otter/archive": http.StatusBadRequest, // Own raft
+	} {
+		req := httptest.NewRequest("POST", path, strings.NewReader(`{"reason": "dissolved"}`))
+		w := httptest.NewRecorder()
+		s.handler().ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("POST %s status = %d, want %d", path, w.Code, want)
+		}
+	}
+}
+
+func TestHandleLeaveRaft(t *testing.T) {
+	s := newTestServerWithGov(t)
+
+	for path, want := range map[string]int{
+		"/api/v1/governance/rafts/missing/leave":    http.StatusNotFound,
+		"/api/v1/governance/rafts/test-otter/leave": http.StatusBadRequest, // Own raft
+	} {
+		req := httptest.NewRequest("POST", path, nil)
+		w := httptest.NewRecorder()
+		s.handler().ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("POST %s status = %d, want %d", path, w.Code, want)
+		}
+	}
+}
+
+func TestHandleListRafts_ArchivedFilter(t *testing.T) {
+	s := newTestServerWithGov(t)
+
+	for query, want := range map[string]int{"": 1, "?archived=false": 1, "?archived=true": 0} {
+		req := httptest.NewRequest("GET", "/api/v1/governance/rafts"+query, nil)
+		w := httptest.NewRecorder()
+		s.handler().ServeHTTP(w, req)
+		var rafts []governance.RaftSummary
+		if err := json.Unmarshal(w.Body.Bytes(), &rafts); err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+		if len(rafts) != want {
+			t.Errorf("GET rafts%s = %d rafts, want %d", query, len(rafts), want)
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/governance/rafts?archived=maybe", nil)
+	w := httptest.NewRecorder()
+	s.handler().ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("invalid archived status = %d, want 400", w.Code)
+	}
+}
+
+func TestHandleListEffects(t *testing.T) {
+	s := newTestServerWithGov(t)
+
+	req := httptest.NewRequest("GET", "/api/v1/governance/effects", nil)
+	w := httptest.NewRecorder()
+	s.handleListEffects(w, req)
+	if w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != "[]" {
+		t.Errorf("list: status = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	body, _ := json.Marshal(map[string]string{
+		"scope":       "tone",
+		"body":        "be calm\neffect: chat.temperature = 7",
+		"proposed_by": s.agent.GetGovernance().GetID(),
+	})
+	req = httptest.NewRequest("POST", "/api/v1/governance/rules", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	s.handleProposeRule(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("out-of-range effect: status = %d, want 400: %s", w.Code, w.Body.String())
+	}
 }
@@ -0,0 +1,24 @@
+package api
+
+import (
+	"net/http"
+
+	"otter-ai/internal/vectordb"
+)
+
+// handleStorage reports the SQLite database's table sizes, row counts and
+// latest maintenance run
+func (s *Server) handleStorage(w http.ResponseWriter, r *http.Request) {
+	maintainer, ok := s.agent.GetMemory().GetVectorDB().(vectordb.Maintainer)
+	if !ok {
+		respondError(w, http.StatusNotImplemented, "the vector backend has no local database to report on")
+		return
+	}
+	report, err := maintainer.Storage(r.Context())
+	if err != nil {
+		s.log().ErrorContext(r.Context(), "failed to report storage", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to report storage")
+		return
+	}
+	respondJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"otter-ai/internal/agent"
+	"otter-ai/internal/config"
+	"otter-ai/internal/memory"
+	"otter-ai/internal/vectordb"
+)
+
+func TestHandleStorage(t *testing.T) {
+	s := newTestServer("secret123")
+	req := httptest.NewRequest("GET", "/api/v1/admin/storage", nil)
+	w := httptest.NewRecorder()
+	s.handleStorage(w, req)
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("without a local database: status = %d, want 501", w.Code)
+	}
+
+	db, err := vectordb.NewSQLiteVectorDB(filepath.Join(t.TempDir(), "otter.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Maintain(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ag := agent.New(agent.Config{Memory: memory.New(db), LLM: &mockLLMProvider{}})
+	s = NewServer(config.APIConfig{Passphrase: "secret123", RateLimit: 100, RateLimitWindow: time.Minute}, ag)
+	admin, _ := s.jwtManager.GenerateToken("operator")
+	member, _ := s.jwtManager.GenerateRoleToken("bot", RoleMember, time.Hour)
+
+	get := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/v1/admin/storage", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		s.handler().ServeHTTP(w, req)
+		return w
+	}
+	if w := get(member); w.Code != http.StatusForbidden {
+		t.Errorf("member: status = %d, want 403", w.Code)
+	}
+	w = get(admin)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var report vectordb.StorageReport
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Tables) == 0 || report.LastMaintained == nil || !report.LastMaintained.Healthy() {
+		t.Errorf("report = %+v", report)
+	}
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"otter-ai/internal/memory"
+)
+
+// NDJSON streaming configuration
+const (
+	NDJSONContentType = "application/x-ndjson"
+	NDJSONFlushEvery  = 100 // Rows written between flushes
+)
+
+// ndjsonWriter writes one JSON document per line, flushing periodically so
+// clients receive rows while the database cursor is still being read
+type ndjsonWriter struct {
+	rc   *http.ResponseController
+	enc  *json.Encoder
+	rows int
+}
+
+// newNDJSONWriter commits a 200 response with NDJSON headers. Streams can
+// outlive ServerWriteTimeout, so the write deadline is lifted.
+func newNDJSONWriter(w http.ResponseWriter) *ndjsonWriter {
+	rc := http.NewResponseController(w)
+	rc.SetWriteDeadline(time.Time{}) // Not supported by every writer; best effort
+
+	w.Header().Set("Content-Type", NDJSONContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	return &ndjsonWriter{rc: rc, enc: json.NewEncoder(w)}
+}
+
+// Write encodes one row
+func (n *ndjsonWriter) Write(v interface{}) error {
+	if err := n.enc.Encode(v); err != nil {
+		return err
+	}
+	n.rows++
+	if n.rows%NDJSONFlushEvery == 0 {
+		n.rc.Flush()
+	}
+	return nil
+}
+
+// Fail ends the stream with an error. The status code has already been
+// sent, so the failure is reported as a final {"error": "..."} line.
+func (n *ndjsonWriter) Fail(message string) {
+	n.enc.Encode(map[string]string{"error": message})
+	n.rc.Flush()
+}
+
+// Close flushes any rows still buffered
+func (n *ndjsonWriter) Close() {
+	n.rc.Flush()
+}
+
+// handleStreamMemories streams every memory of a type as NDJSON, reading
+// rows from the database cursor instead of building the full list
+func (s *Server) handleStreamMemories(w http.ResponseWriter, r *http.Request) {
+	memType := r.URL.Query().Get("type")
+	if memType == "" {
+		memType = string(memory.MemoryTypeLongTerm)
+	}
+
+	out := newNDJSONWriter(w)
+	err := s.agent.GetMemory().Each(r.Context(), memory.MemoryType(memType), func(record memory.MemoryRecord) error {
+		return out.Write(record)
+	})
+	if err != nil && r.Context().Err() == nil {
+		out.Fail("failed to stream memories")
+		return
+	}
+	out.Close()
+}
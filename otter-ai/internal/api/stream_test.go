@@ -0,0 +1,96 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"otter-ai/internal/agent"
+	"otter-ai/internal/memory"
+	"otter-ai/internal/vectordb"
+)
+
+// iterVectorDB streams fixed records through vectordb.Iterator, then
+// optionally fails
+type iterVectorDB struct {
+	mockVectorDB
+	records []vectordb.Record
+	err     error
+}
+
+func (m *iterVectorDB) Iterate(_ context.Context, _ string, fn func(vectordb.Record) error) error {
+	for _, record := range m.records {
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	return m.err
+}
+
+func newStreamTestServer(vdb vectordb.VectorDB) *Server {
+	ag := agent.New(agent.Config{Memory: memory.New(vdb), LLM: &mockLLMProvider{}})
+	return &Server{agent: ag}
+}
+
+func decodeNDJSON(t *testing.T, w *httptest.ResponseRecorder) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("invalid NDJSON line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestHandleStreamMemories(t *testing.T) {
+	vdb := &iterVectorDB{records: []vectordb.Record{
+		{ID: "m1", Metadata: map[string]interface{}{"content": "first", "type": "long_term"}},
+		{ID: "m2", Metadata: map[string]interface{}{"content": "second", "type": "long_term"}},
+	}}
+	s := newStreamTestServer(vdb)
+
+	req := httptest.NewRequest("GET", "/api/v1/memories/stream", nil)
+	w := httptest.NewRecorder()
+	s.handleStreamMemories(w, req)
+
+	if w.Code != 200 {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != NDJSONContentType {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	lines := decodeNDJSON(t, w)
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+	if lines[0]["ID"] != "m1" || lines[1]["Content"] != "second" {
+		t.Errorf("unexpected rows: %v", lines)
+	}
+}
+
+func TestHandleStreamMemories_ErrorLine(t *testing.T) {
+	vdb := &iterVectorDB{
+		records: []vectordb.Record{{ID: "m1", Metadata: map[string]interface{}{"content": "first"}}},
+		err:     errors.New("disk on fire"),
+	}
+	s := newStreamTestServer(vdb)
+
+	req := httptest.NewRequest("GET", "/api/v1/memories/stream", nil)
+	w := httptest.NewRecorder()
+	s.handleStreamMemories(w, req)
+
+	lines := decodeNDJSON(t, w)
+	if len(lines) != 2 {
+		t.Fatalf("expected a row and an error line, got %v", lines)
+	}
+	if lines[1]["error"] != "failed to stream memories" {
+		t.Errorf("last line = %v", lines[1])
+	}
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"otter-ai/internal/agent"
+	"otter-ai/internal/plugins"
+)
+
+// handleListThreadLinks lists platform threads linked to other sessions
+func (s *Server) handleListThreadLinks(w http.ResponseWriter, r *http.Request) {
+	links := []plugins.ThreadLink{}
+	if mgr := s.agent.GetPlugins(); mgr != nil {
+		links = mgr.ThreadLinks()
+	}
+	respondJSON(w, http.StatusOK, links)
+}
+
+// handleLinkThread makes a platform thread continue a session, or use its
+// own again when session_id is empty
+func (s *Server) handleLinkThread(w http.ResponseWriter, r *http.Request) {
+	var link plugins.ThreadLink
+	if err := json.NewDecoder(r.Body).Decode(&link); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if link.SessionID != "" {
+		if err := agent.ValidateSessionID(link.SessionID); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	mgr := s.agent.GetPlugins()
+	if mgr == nil {
+		respondError(w, http.StatusServiceUnavailable, "plugins are not available")
+		return
+	}
+	if err := mgr.LinkThread(link.Thread, link.SessionID); err != nil {
+		s.respondErr(w, r, err, http.StatusBadRequest, "")
+		return
+	}
+	respondJSON(w, http.StatusOK, link)
+}
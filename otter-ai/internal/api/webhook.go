@@ -0,0 +1,53 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"otter-ai/internal/plugins"
+)
+
+// WebhookResponse is the reply to a webhook
+type WebhookResponse struct {
+	Reply string `json:"reply"`
+}
+
+// handleWebhook turns a JSON payload posted to a webhook endpoint into a
+// message and returns the agent's reply. The endpoint's own token
+// authenticates the caller.
+func (s *Server) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	var webhooks *plugins.WebhookPlugin
+	if mgr := s.agent.GetPlugins(); mgr != nil {
+		if plugin, ok := mgr.Get(plugins.WebhookPlatform); ok {
+			webhooks, _ = plugin.(*plugins.WebhookPlugin)
+		}
+	}
+	if webhooks == nil {
+		respondError(w, http.StatusNotFound, "webhooks are not configured")
+		return
+	}
+
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, plugins.MaxWebhookPayload))
+	if err != nil {
+		respondError(w, http.StatusRequestEntityTooLarge, "payload too large")
+		return
+	}
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	reply, err := webhooks.Receive(r.Context(), r.PathValue("name"), strings.TrimSpace(token), payload)
+	switch {
+	case errors.Is(err, plugins.ErrWebhookNotFound):
+		respondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, plugins.ErrWebhookUnauthorized):
+		respondError(w, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, plugins.ErrWebhookPayload):
+		respondError(w, http.StatusBadRequest, err.Error())
+	case err != nil:
+		s.log().ErrorContext(r.Context(), "failed to process webhook", "webhook", r.PathValue("name"), "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to process message")
+	default:
+		respondJSON(w, http.StatusOK, WebhookResponse{Reply: reply})
+	}
+}
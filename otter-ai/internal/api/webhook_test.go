@@ -0,0 +1,68 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"otter-ai/internal/agent"
+	"otter-ai/internal/config"
+	"otter-ai/internal/memory"
+	"otter-ai/internal/plugins"
+)
+
+func newWebhookTestServer(t *testing.T) *Server {
+	t.Helper()
+	webhooks, err := plugins.NewWebhookPlugin([]plugins.WebhookEndpoint{
+		{Name: "ci", Token: "s3cret", Message: "Build {{.status}}"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mgr := plugins.NewManager(config.PluginConfig{})
+	mgr.Register(webhooks)
+	ag := agent.New(agent.Config{
+		Memory:  memory.New(&mockVectorDB{}),
+		LLM:     &mockLLMProvider{completeResp: "mock response"},
+		Plugins: mgr,
+	})
+	return NewServer(config.APIConfig{RateLimit: 100}, ag)
+}
+
+func TestHandleWebhook(t *testing.T) {
+	s := newWebhookTestServer(t)
+	tests := []struct {
+		name, webhook, token, body string
+		want                       int
+	}{
+		{"reply", "ci", "s3cret", `{"status": "failed"}`, http.StatusOK},
+		{"unknown", "cd", "s3cret", `{"status": "failed"}`, http.StatusNotFound},
+		{"wrong token", "ci", "guess", `{"status": "failed"}`, http.StatusUnauthorized},
+		{"missing field", "ci", "s3cret", `{}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/v1/plugins/webhook/"+tt.webhook, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+			s.handler().ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+			if tt.want == http.StatusOK && !strings.Contains(w.Body.String(), `"reply":"mock response"`) {
+				t.Errorf("body = %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleWebhook_NotConfigured(t *testing.T) {
+	s := newTestServer("")
+	req := httptest.NewRequest("POST", "/api/v1/plugins/webhook/ci", strings.NewReader(`{}`))
+	w := httptest.NewRecorder()
+	s.handleWebhook(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", w.Code)
+	}
+}
@@ -0,0 +1,386 @@
+// Package backup snapshots an otter's database, key material and
+// configuration files into a single passphrase-encrypted archive, and
+// restores such an archive into a fresh data directory, so that an otter
+// can be moved between hosts.
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+
+	"otter-ai/internal/vectordb"
+	"otter-ai/internal/version"
+)
+
+// Archive layout
+const (
+	FormatVersion       = 1
+	FileExtension       = ".otterbak"
+	MinPassphraseLength = 12
+
+	manifestEntry = "manifest.json"
+	databaseEntry = "otter.db"
+	dataDirPrefix = "data/"
+	configPrefix  = "config/"
+)
+
+// Archive encryption: AES-256-GCM under a key derived from the passphrase
+// with scrypt. The header is authenticated along with the contents.
+var archiveMagic = []byte("OTTERBAK")
+
+const (
+	saltSize  = 16
+	nonceSize = 12
+	scryptN   = 1 << 15
+	scryptR   = 8
+	scryptP   = 1
+)
+
+var (
+	// ErrWeakPassphrase is returned for passphrases shorter than MinPassphraseLength
+	ErrWeakPassphrase = fmt.Errorf("the archive passphrase must be at least %d characters", MinPassphraseLength)
+	// ErrWrongPassphrase is returned when an archive does not decrypt
+	ErrWrongPassphrase = errors.New("wrong passphrase or damaged archive")
+	// ErrTargetNotEmpty is returned when restoring over existing state
+	ErrTargetNotEmpty = errors.New("restore target is not empty")
+)
+
+// Source is what a backup captures
+type Source struct {
+	OtterID     string
+	DB          *sql.DB  // Copied with SQLite's online backup API; nil skips it
+	DBPath      string   // Skipped when it lies inside DataDir
+	DataDir     string   // Key files, rollovers and bootstrap rules, copied whole
+	ConfigFiles []string // Configuration files, such as the constitution; missing ones are skipped
+}
+
+// Target is where a backup is restored. DataDir must be empty or missing,
+// and DBPath must not exist.
+type Target struct {
+	DataDir   string
+	DBPath    string
+	ConfigDir string // Receives the archived configuration files
+}
+
+// Manifest describes an archive's contents
+type Manifest struct {
+	FormatVersion int               `json:"format_version"`
+	OtterID       string            `json:"otter_id"`
+	OtterVersion  string            `json:"otter_version"`
+	CreatedAt     time.Time         `json:"created_at"`
+	Database      bool              `json:"database"`
+	DataFiles     []string          `json:"data_files"`             // Relative to the data directory
+	ConfigFiles   map[string]string `json:"config_files,omitempty"` // Archived name -> original path
+}
+
+// FileName suggests a file name for an archive
+func (m *Manifest) FileName() string {
+	return fmt.Sprintf("otter-%s-%s%s", m.OtterID, m.CreatedAt.UTC().Format("20060102T150405Z"), FileExtension)
+}
+
+// archiveEntry is a file to archive
+type archiveEntry struct {
+	name string
+	path string
+}
+
+// Create writes an encrypted archive of src to w
+func Create(ctx context.Context, w io.Writer, src Source, passphrase string) (*Manifest, error) {
+	if len(passphrase) < MinPassphraseLength {
+		return nil, ErrWeakPassphrase
+	}
+	manifest := &Manifest{
+		FormatVersion: FormatVersion,
+		OtterID:       src.OtterID,
+		OtterVersion:  version.Version,
+		CreatedAt:     time.Now().UTC(),
+	}
+	var entries []archiveEntry
+
+	if src.DB != nil {
+		tmp, err := os.MkdirTemp("", "otter-backup-")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create a temporary directory: %w", err)
+		}
+		defer os.RemoveAll(tmp)
+		snapshot := filepath.Join(tmp, databaseEntry)
+		if err := vectordb.Backup(ctx, src.DB, snapshot); err != nil {
+			return nil, err
+		}
+		manifest.Database = true
+		entries = append(entries, archiveEntry{name: databaseEntry, path: snapshot})
+	}
+
+	if src.DataDir != "" {
+		dataFiles, err := dataDirEntries(src.DataDir, src.DBPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range dataFiles {
+			manifest.DataFiles = append(manifest.DataFiles, strings.TrimPrefix(entry.name, dataDirPrefix))
+		}
+		entries = append(entries, dataFiles...)
+	}
+
+	used := make(map[string]bool)
+	for _, file := range src.ConfigFiles {
+		if file == "" {
+			continue
+		}
+		if _, err := os.Stat(file); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		name := filepath.Base(file)
+		for i := 2; used[name]; i++ {
+			name = fmt.Sprintf("%d-%s", i, filepath.Base(file))
+		}
+		used[name] = true
+		if manifest.ConfigFiles == nil {
+			manifest.ConfigFiles = make(map[string]string)
+		}
+		manifest.ConfigFiles[name] = file
+		entries = append(entries, archiveEntry{name: configPrefix + name, path: file})
+	}
+
+	var plain bytes.Buffer
+	if err := writeTarball(&plain, manifest, entries); err != nil {
+		return nil, err
+	}
+	if err := seal(w, plain.Bytes(), passphrase); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// dataDirEntries lists the regular files under dataDir, leaving out the
+// database and its journals should they live there
+func dataDirEntries(dataDir, dbPath string) ([]archiveEntry, error) {
+	skip := make(map[string]bool)
+	if dbPath != "" {
+		abs, _ := filepath.Abs(dbPath)
+		for _, suffix := range []string{"", "-wal", "-shm", "-journal"} {
+			skip[abs+suffix] = true
+		}
+	}
+
+	var entries []archiveEntry
+	err := filepath.WalkDir(dataDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		if abs, _ := filepath.Abs(p); skip[abs] {
+			return nil
+		}
+		rel, err := filepath.Rel(dataDir, p)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, archiveEntry{name: dataDirPrefix + filepath.ToSlash(rel), path: p})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data directory: %w", err)
+	}
+	return entries, nil
+}
+
+// writeTarball writes the manifest and entries as a gzipped tarball
+func writeTarball(w io.Writer, manifest *Manifest, entries []archiveEntry) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	if err := writeTarEntry(tw, manifestEntry, data); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		data, err := os.ReadFile(entry.path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", entry.path, err)
+		}
+		if err := writeTarEntry(tw, entry.name, data); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return gz.Close()
+}
+
+func writeTarEntry(tw *tar.Writer, name string, data []byte) error {
+	header := &tar.Header{Name: name, Mode: 0600, Size: int64(len(data)), ModTime: time.Now()}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// Restore decrypts an archive read from r into target
+func Restore(r io.Reader, passphrase string, target Target) (*Manifest, error) {
+	if target.DataDir == "" || target.DBPath == "" || target.ConfigDir == "" {
+		return nil, errors.New("restore needs a data directory, a database path and a config directory")
+	}
+	if entries, err := os.ReadDir(target.DataDir); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("%w: %s has files in it", ErrTargetNotEmpty, target.DataDir)
+	}
+	if _, err := os.Stat(target.DBPath); err == nil {
+		return nil, fmt.Errorf("%w: %s already exists", ErrTargetNotEmpty, target.DBPath)
+	}
+
+	sealed, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	plain, err := open(sealed, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(plain))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	tr := tar.NewReader(gz)
+	var manifest *Manifest
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+
+		if header.Name == manifestEntry {
+			manifest = &Manifest{}
+			if err := json.Unmarshal(data, manifest); err != nil {
+				return nil, fmt.Errorf("failed to parse manifest: %w", err)
+			}
+			if manifest.FormatVersion > FormatVersion {
+				return nil, fmt.Errorf("archive format %d is newer than this build supports (%d)", manifest.FormatVersion, FormatVersion)
+			}
+			continue
+		}
+		dest, err := restorePath(header.Name, target)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(dest), 0700); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(dest, data, 0600); err != nil {
+			return nil, fmt.Errorf("failed to restore %s: %w", header.Name, err)
+		}
+	}
+	if manifest == nil {
+		return nil, errors.New("archive has no manifest")
+	}
+	return manifest, nil
+}
+
+// restorePath maps an archive entry to where it is restored, refusing
+// names that would land outside the target
+func restorePath(name string, target Target) (string, error) {
+	clean := path.Clean(name)
+	if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("archive entry %q is outside the archive", name)
+	}
+	switch {
+	case clean == databaseEntry:
+		return target.DBPath, nil
+	case strings.HasPrefix(clean, dataDirPrefix):
+		return filepath.Join(target.DataDir, filepath.FromSlash(strings.TrimPrefix(clean, dataDirPrefix))), nil
+	case strings.HasPrefix(clean, configPrefix):
+		return filepath.Join(target.ConfigDir, filepath.FromSlash(strings.TrimPrefix(clean, configPrefix))), nil
+	}
+	return "", fmt.Errorf("unexpected archive entry %q", name)
+}
+
+// seal encrypts plain and writes the archive header and ciphertext to w
+func seal(w io.Writer, plain []byte, passphrase string) error {
+	header := make([]byte, len(archiveMagic)+1+saltSize+nonceSize)
+	copy(header, archiveMagic)
+	header[len(archiveMagic)] = FormatVersion
+	salt := header[len(archiveMagic)+1 : len(archiveMagic)+1+saltSize]
+	nonce := header[len(archiveMagic)+1+saltSize:]
+	if _, err := rand.Read(header[len(archiveMagic)+1:]); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	gcm, err := archiveCipher(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err = w.Write(gcm.Seal(nil, nonce, plain, header))
+	return err
+}
+
+// open checks an archive's header and decrypts its contents
+func open(sealed []byte, passphrase string) ([]byte, error) {
+	headerSize := len(archiveMagic) + 1 + saltSize + nonceSize
+	if len(sealed) < headerSize || !bytes.Equal(sealed[:len(archiveMagic)], archiveMagic) {
+		return nil, errors.New("not an otter backup archive")
+	}
+	if v := sealed[len(archiveMagic)]; v != FormatVersion {
+		return nil, fmt.Errorf("unsupported archive encryption version %d", v)
+	}
+	header := sealed[:headerSize]
+	salt := header[len(archiveMagic)+1 : len(archiveMagic)+1+saltSize]
+	nonce := header[len(archiveMagic)+1+saltSize:]
+
+	gcm, err := archiveCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := gcm.Open(nil, nonce, sealed[headerSize:], header)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return plain, nil
+}
+
+// archiveCipher derives the archive key from the passphrase
+func archiveCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive archive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
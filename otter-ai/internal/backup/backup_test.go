@@ -0,0 +1,164 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"otter-ai/internal/vectordb"
+)
+
+const testPassphrase = "correct horse battery"
+
+// testSource returns a source with a database, a data directory holding
+// the database among its keys, and a configuration file
+func testSource(t *testing.T) Source {
+	t.Helper()
+	dir := t.TempDir()
+	dataDir := filepath.Join(dir, "data")
+	dbPath := filepath.Join(dataDir, "otter.db")
+	if err := os.MkdirAll(filepath.Join(dataDir, "retired-keys"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{"otter.key": "key", "retired-keys/old.sign.key": "old"} {
+		if err := os.WriteFile(filepath.Join(dataDir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	constitution := filepath.Join(dir, "constitution.yaml")
+	if err := os.WriteFile(constitution, []byte("rules: []"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := vectordb.NewSQLiteVectorDB(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Store(context.Background(), vectordb.TableMemories, "m1", []float32{1, 0}, map[string]interface{}{"content": "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	return Source{
+		OtterID:     "otter-1",
+		DB:          db.GetDB(),
+		DBPath:      dbPath,
+		DataDir:     dataDir,
+		ConfigFiles: []string{constitution, filepath.Join(dir, "missing.yaml")},
+	}
+}
+
+func newTarget(t *testing.T) Target {
+	dir := t.TempDir()
+	return Target{
+		DataDir:   filepath.Join(dir, "data"),
+		DBPath:    filepath.Join(dir, "otter.db"),
+		ConfigDir: filepath.Join(dir, "config"),
+	}
+}
+
+func TestCreateAndRestore(t *testing.T) {
+	src := testSource(t)
+	var archive bytes.Buffer
+	manifest, err := Create(context.Background(), &archive, src, testPassphrase)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if !manifest.Database || len(manifest.DataFiles) != 2 || len(manifest.ConfigFiles) != 1 {
+		t.Errorf("manifest = %+v", manifest)
+	}
+	if bytes.Contains(archive.Bytes(), []byte("retired-keys")) {
+		t.Error("archive contents should be encrypted")
+	}
+
+	target := newTarget(t)
+	restored, err := Restore(bytes.NewReader(archive.Bytes()), testPassphrase, target)
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if restored.OtterID != "otter-1" {
+		t.Errorf("restored manifest = %+v", restored)
+	}
+	for path, want := range map[string]string{
+		filepath.Join(target.DataDir, "otter.key"):                 "key",
+		filepath.Join(target.DataDir, "retired-keys/old.sign.key"): "old",
+		filepath.Join(target.ConfigDir, "constitution.yaml"):       "rules: []",
+	} {
+		if got, err := os.ReadFile(path); err != nil || string(got) != want {
+			t.Errorf("%s = %q, %v; want %q", path, got, err, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(target.DataDir, "otter.db")); err == nil {
+		t.Error("the live database should not be copied from the data directory")
+	}
+
+	db, err := vectordb.NewSQLiteVectorDB(target.DBPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Get(context.Background(), vectordb.TableMemories, "m1"); err != nil {
+		t.Errorf("restored database is missing a memory: %v", err)
+	}
+}
+
+func TestCreate_WeakPassphrase(t *testing.T) {
+	if _, err := Create(context.Background(), &bytes.Buffer{}, Source{}, "short"); !errors.Is(err, ErrWeakPassphrase) {
+		t.Errorf("err = %v, want ErrWeakPassphrase", err)
+	}
+}
+
+func TestRestore_Rejections(t *testing.T) {
+	var archive bytes.Buffer
+	if _, err := Create(context.Background(), &archive, testSource(t), testPassphrase); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Restore(bytes.NewReader(archive.Bytes()), "wrong passphrase!", newTarget(t)); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("wrong passphrase: err = %v", err)
+	}
+
+	damaged := bytes.Clone(archive.Bytes())
+	damaged[len(damaged)-1] ^= 0xff
+	if _, err := Restore(bytes.NewReader(damaged), testPassphrase, newTarget(t)); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("damaged archive: err = %v", err)
+	}
+
+	target := newTarget(t)
+	if err := os.MkdirAll(target.DataDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(target.DataDir, "otter.key"), []byte("existing"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Restore(bytes.NewReader(archive.Bytes()), testPassphrase, target); !errors.Is(err, ErrTargetNotEmpty) {
+		t.Errorf("non-empty data directory: err = %v", err)
+	}
+}
+
+func TestRestore_RefusesEntriesOutsideTarget(t *testing.T) {
+	var plain bytes.Buffer
+	gz := gzip.NewWriter(&plain)
+	tw := tar.NewWriter(gz)
+	if err := writeTarEntry(tw, "data/../../escaped", []byte("x")); err != nil {
+		t.Fatal(err)
+	}
+	tw.Close()
+	gz.Close()
+
+	var archive bytes.Buffer
+	if err := seal(&archive, plain.Bytes(), testPassphrase); err != nil {
+		t.Fatal(err)
+	}
+	target := newTarget(t)
+	if _, err := Restore(&archive, testPassphrase, target); err == nil {
+		t.Error("expected an entry escaping the target to be refused")
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(target.DataDir), "escaped")); err == nil {
+		t.Error("entry was written outside the target")
+	}
+}
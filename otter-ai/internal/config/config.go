@@ -2,8 +2,12 @@ package config
 
 import (
 	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -11,14 +15,49 @@ import (
 
 // Config holds all application configuration
 type Config struct {
+	// File is the YAML config file the configuration was read from; empty
+	// when there was none
+	File          string
 	Env           string
 	Port          int
 	DBPath        string
 	VectorBackend string
-	Raft          RaftConfig
-	LLM           LLMConfig
-	API           APIConfig
-	Plugins       PluginConfig
+	// VectorSnapshot is the file the memory backend loads at startup and
+	// saves at shutdown; empty keeps its memories in RAM only
+	VectorSnapshot string
+	// ShutdownTimeout is how long shutdown waits for in-flight messages and
+	// memory writes before closing the database regardless
+	ShutdownTimeout time.Duration
+	// ReadOnly starts the otter refusing memory writes, proposals and votes
+	ReadOnly bool
+	// MaintenanceMode starts the otter refusing chat while health checks
+	// and the admin endpoints still answer
+	MaintenanceMode bool
+	Raft            RaftConfig
+	LLM             LLMConfig
+	Embedding       EmbeddingConfig
+	API             APIConfig
+	Plugins         PluginConfig
+	Hooks           HooksConfig
+	SQLite          SQLiteConfig
+	LanceDB         LanceDBConfig
+	Qdrant          QdrantConfig
+	Consolidation   ConsolidationConfig
+	Retention       RetentionConfig
+	Dedup           DedupConfig
+	Musing          MusingConfig
+	Intent          IntentConfig
+	Retrieval       RetrievalConfig
+	Extraction      ExtractionConfig
+	Scheduler       SchedulerConfig
+	Onboarding      OnboardingConfig
+	Personality     PersonalityConfig
+	Chaos           ChaosConfig
+	Connectors      ConnectorsConfig
+	Tracing         TracingConfig
+	Usage           UsageConfig
+	Logging         LoggingConfig
+	Secrets         SecretsConfig
 }
 
 // RaftConfig holds raft-specific configuration
@@ -27,7 +66,37 @@ type RaftConfig struct {
 	Type          string // super-raft, raft, sub-raft
 	BindAddr      string
 	AdvertiseAddr string
+	PeerEndpoint  string // HTTP API address peers use to reach this otter
 	DataDir       string
+	VotingPeriod  time.Duration // How long proposals stay open before being rejected
+	// HeartbeatInterval is how often raft peers are sent a heartbeat; zero
+	// disables heartbeats
+	HeartbeatInterval time.Duration
+	// HeartbeatGrace is how long a peer may go unheard before it is marked
+	// inactive
+	HeartbeatGrace time.Duration
+	// LeaveRules is what happens to a raft's rules when this otter leaves
+	// it: "drop" or "keep" them as personal rules
+	LeaveRules string
+	// Voting* are the voting policy of rafts without a voting rule of their
+	// own: fractions of eligible members, with zero using the built-in
+	// default, and how ties are broken
+	VotingQuorum        float64
+	VotingMajority      float64
+	VotingSuperMajority float64
+	VotingTieBreak      string
+	// SuspensionQuorum is how many YES votes suspend a rule in an
+	// emergency, and SuspensionWindow how long the suspension stays open
+	SuspensionQuorum int
+	SuspensionWindow time.Duration
+	// RuleEnforcement is how active rules constrain the agent: "off",
+	// "structured" to enforce rule directives, or "llm" to also have the LLM
+	// check replies against free-text rules
+	RuleEnforcement string
+	// BootstrapFile is the constitution: a YAML or JSON file of rules adopted
+	// when a fresh otter initializes its solo raft. Empty uses bootstrap.yaml
+	// in DataDir when present.
+	BootstrapFile string
 }
 
 // LLMConfig holds LLM provider configuration
@@ -37,6 +106,38 @@ type LLMConfig struct {
 	Model          string
 	EmbeddingModel string
 	APIKey         string
+	Profiles       map[string]LLMProfile // Overrides of the named parameter profiles
+	PromptDir      string                // <name>.tmpl files overriding prompt templates
+	ContextWindow  int                   // Tokens the model reads per request; zero leaves chat prompts unbounded
+
+	Timeout          time.Duration // Per attempt; zero disables
+	MaxRetries       int           // Retries for rate limiting, server errors and timeouts
+	RetryBackoff     time.Duration // Wait before the first retry, doubling after each
+	BreakerThreshold int           // Consecutive failures that open the circuit; zero disables
+	BreakerCooldown  time.Duration // How long the circuit stays open
+
+	MaxConcurrent int           // Completions in flight at once; zero disables the limit
+	MaxQueued     int           // Requests waiting for a slot before new ones are refused; zero is unbounded
+	QueueTimeout  time.Duration // Longest a request waits for a slot; zero waits as long as its caller
+}
+
+// EmbeddingConfig selects where embeddings come from. Unset fields fall
+// back to the LLM configuration when the provider is the same or unset.
+type EmbeddingConfig struct {
+	Provider string // ollama, openwebui or openai; empty uses the LLM provider
+	Model    string
+	Endpoint string
+	APIKey   string
+	// MaxConcurrent bounds embedding requests in flight at once, queued like
+	// completions; zero disables the limit
+	MaxConcurrent int
+}
+
+// LLMProfile overrides one named LLM parameter profile
+type LLMProfile struct {
+	Temperature     *float32 // Nil keeps the profile's temperature
+	OmitTemperature bool     // Never send a temperature, for models that reject one
+	MaxTokens       int      // Zero keeps the profile's limit
 }
 
 // APIConfig holds API server configuration
@@ -47,45 +148,348 @@ type APIConfig struct {
 	JWTSecret       string        // JWT signing secret (auto-generated if empty)
 	RateLimit       int           // Requests per window
 	RateLimitWindow time.Duration // Rate limit time window
+	// RateLimitChat and RateLimitRead limit chat messages and read-only
+	// requests separately; 0 uses RateLimit
+	RateLimitChat int
+	RateLimitRead int
+	// RateLimitKey is what requests are counted against: "ip", "subject"
+	// for a bearer token's subject, or "token" for each bearer token
+	RateLimitKey string
+	// TrustedProxies are the CIDRs or IPs whose forwarding headers are
+	// believed when finding a client's IP
+	TrustedProxies []string
+	// HoldApprovers maps each provisioned legal hold approver to their own
+	// passphrase; a hold is released only by approvers presenting theirs
+	HoldApprovers map[string]string
+}
+
+// SQLiteConfig tunes the SQLite database at DBPath
+type SQLiteConfig struct {
+	BusyTimeout         time.Duration // How long a statement waits on a locked database
+	WAL                 bool          // Write-ahead logging; off keeps the rollback journal
+	BatchWindow         time.Duration // How long a memory write waits for others to share its transaction
+	BatchSize           int           // Most memory writes per transaction; 1 disables batching, 0 uses the default
+	MaintenanceInterval time.Duration // How often integrity is checked, free pages vacuumed and the WAL checkpointed; 0 disables
+}
+
+// LanceDBConfig locates the LanceDB server used by the lancedb backend
+type LanceDBConfig struct {
+	URL      string
+	APIKey   string
+	Database string
+}
+
+// QdrantConfig locates the Qdrant server used by the qdrant backend
+type QdrantConfig struct {
+	URL              string
+	APIKey           string
+	CollectionPrefix string // Prepended to table names, so several otters can share a server
+}
+
+// HooksConfig lists external policy endpoints called on every chat turn
+type HooksConfig struct {
+	BeforeMessage  []string      // URLs called, in order, before a message is processed
+	BeforeResponse []string      // URLs called, in order, before a reply is returned
+	Timeout        time.Duration // Per-call timeout
+	FailOpen       bool          // Continue when a hook fails instead of blocking the turn
+	Token          string        // Sent to hooks as a bearer token, if set
+}
+
+// ConsolidationConfig tunes the background job that summarizes clusters of
+// old, low-importance memories
+type ConsolidationConfig struct {
+	Interval        time.Duration // How often the job runs; zero disables it
+	MinAge          time.Duration // Only memories older than this are consolidated
+	MaxImportance   float32       // Only memories at or below this importance are consolidated
+	Similarity      float64       // Minimum cosine similarity for a memory to join a cluster
+	MinClusterSize  int           // Smaller clusters are left alone
+	MaxClusterSize  int
+	BatchSize       int  // Most memories considered per run
+	DeleteOriginals bool // Delete summarized memories instead of archiving them
+}
+
+// RetentionConfig tunes importance decay and forgetting of long-term memories
+type RetentionConfig struct {
+	Interval      time.Duration // How often retention runs; zero disables it
+	HalfLife      time.Duration // Time for an unretrieved memory's importance to halve
+	Floor         float32       // Memories that decay below this importance are forgotten
+	Demote        bool          // Archive forgotten memories instead of deleting them
+	Reinforcement float32       // Importance added each time a memory is retrieved
+	MinAge        time.Duration // Younger memories neither decay nor get forgotten
+	// PurgeInterval is how often deleted memories and superseded versions
+	// older than RecoveryWindow are permanently removed; zero disables it
+	PurgeInterval  time.Duration
+	RecoveryWindow time.Duration
+}
+
+// DedupConfig tunes near-duplicate detection when memories are stored
+type DedupConfig struct {
+	Threshold float64       // Cosine similarity at which a memory repeats another; zero disables detection
+	Window    time.Duration // Only memories stored this recently are compared; zero compares all
+	Action    string        // skip, merge or bump
+	Bump      float32       // Importance added to the existing memory by merge and bump
+}
+
+// MusingConfig tunes the idle reflection loop that turns recent memories
+// into musings
+type MusingConfig struct {
+	Interval     time.Duration // How often the loop runs; zero disables it
+	MemoryWindow int           // Recent memories reviewed per run
+	MinMemories  int           // Fewer recent memories than this skips the run
+	Timeout      time.Duration // Bounds one run
+	PromptLimit  int           // Recent musings added to prompts; zero disables
+}
+
+// IntentConfig tunes the intent classification that decides which tools a
+// chat message is offered
+type IntentConfig struct {
+	Mode      string  // off, auto, embedding or llm
+	Threshold float64 // Least exemplar similarity an intent is chosen with
+	Margin    float64 // How far the best intent must lead the next one
+}
+
+// RetrievalConfig tunes which searched memories reach the prompt
+type RetrievalConfig struct {
+	MinScore         float64 // Least similarity to the query a memory is kept with
+	DedupeSimilarity float64 // Similarity at which a memory repeats a better ranked one
+	Candidates       int     // Memories searched before filtering and re-ranking
+	Rerank           string  // off, llm or cross-encoder
+	RerankEndpoint   string  // Base URL of the cross-encoder's rerank service
+	RerankAPIKey     string
+	FactBoost        float64 // Added to the scores of extracted facts
+}
+
+// ExtractionConfig tunes the stage that stores the facts and preferences
+// each interaction states as memories of their own
+type ExtractionConfig struct {
+	Enabled    bool
+	MaxFacts   int     // Most facts kept per interaction
+	Importance float32 // Importance facts are stored at
+}
+
+// SchedulerConfig tunes delivery of scheduled outbound messages
+type SchedulerConfig struct {
+	Interval        time.Duration // How often due messages are delivered; zero disables delivery
+	MaxAttempts     int           // Delivery attempts before a message fails
+	DigestTarget    string        // "platform:channel_id" for the daily musings digest; empty disables it
+	DigestAt        time.Duration // Local time of day the digest is sent, as an offset from midnight
+	DeadlineTarget  string        // "platform:channel_id" for proposal deadline warnings; empty disables them
+	DeadlineWarning time.Duration // How long before a deadline the warning is sent
+}
+
+// OnboardingConfig tunes the guided onboarding sent to new raft members
+type OnboardingConfig struct {
+	Enabled      bool
+	TemplateFile string   // Template {{define}} blocks overriding or adding steps
+	Steps        []string // Template names sent in order; empty uses the defaults
+	// Contacts maps member IDs to the "platform:user_id" onboarding is sent to
+	Contacts map[string]string
+}
+
+// PersonalityConfig tunes the agent's personality traits and their drift
+type PersonalityConfig struct {
+	SeedFile   string        // JSON array of {name, description, strength} traits; empty uses the defaults
+	Interval   time.Duration // How often observed influence is applied; zero disables drift
+	DriftRate  float64       // Most a trait's strength moves per evolution from influence
+	RuleWeight float64       // Influence of an adopted rule relative to one interaction
+}
+
+// ChaosConfig injects network faults into governance federation, for test
+// networks only
+type ChaosConfig struct {
+	Enabled       bool // Chaos mode; also allows faults to be changed through the API
+	DropRate      float64
+	DuplicateRate float64
+	ReorderRate   float64
+	MinDelay      time.Duration
+	MaxDelay      time.Duration
+	Partitioned   []string // Member IDs or endpoints cut off in both directions
+	Seed          int64    // Zero seeds from the clock
+}
+
+// ConnectorConfig names one external source to ingest
+type ConnectorConfig struct {
+	Name   string
+	Kind   string // rss, ical or github
+	Target string // Feed or calendar URL, or owner/repo for github
+}
+
+// ConnectorsConfig tunes ingestion of external sources into memory
+type ConnectorsConfig struct {
+	Sources     []ConnectorConfig
+	Interval    time.Duration // How often sources are pulled
+	MaxItems    int           // Most new items ingested per source per pull
+	GitHubToken string        // Optional token for the GitHub API
+}
+
+// TracingConfig configures OpenTelemetry trace export
+type TracingConfig struct {
+	Enabled     bool
+	Endpoint    string  // OTLP/HTTP collector, host:port or URL; empty uses OTEL_EXPORTER_OTLP_ENDPOINT or localhost:4318
+	Insecure    bool    // Export over plain HTTP
+	SampleRate  float64 // Fraction of new traces recorded; traces continued from a caller follow its decision
+	ServiceName string
+}
+
+// LoggingConfig configures the structured logger
+type LoggingConfig struct {
+	Level  string // debug, info, warn or error
+	Format string // text or json
+}
+
+// UsageConfig holds LLM token accounting configuration
+type UsageConfig struct {
+	DailyTokenBudget int      // Tokens per UTC day before deferrable calls are refused; zero means no budget
+	Deferrable       []string // Purposes refused once the budget is spent; empty uses the defaults
 }
 
 // PluginConfig holds plugin configuration
 type PluginConfig struct {
-	Enabled  []string
-	Discord  PluginSettings
-	Signal   PluginSettings
-	Telegram PluginSettings
-	Slack    PluginSettings
+	// Dir holds plugin executables, each run as a separate process; empty
+	// loads none
+	Dir string
+	// Enabled limits which discovered plugins are started; empty starts all
+	Enabled []string
+	// Settings holds per-plugin settings by lowercased plugin name, read
+	// from OTTER_PLUGIN_<NAME>_<SETTING> variables
+	Settings map[string]PluginSettings
+	// HealthInterval is how often running plugins are pinged; a plugin that
+	// fails to answer or exits is restarted
+	HealthInterval time.Duration
+	// Voters maps "platform:user_id" to the raft member a platform user
+	// votes as through inline voting buttons
+	Voters map[string]string
+	// ProfilesFile is a YAML or JSON file of per-platform and per-channel
+	// agent profiles; empty applies none
+	ProfilesFile string
+	// WebhooksFile is a YAML or JSON file of inbound webhook endpoints;
+	// empty disables the webhook plugin
+	WebhooksFile string
+	// NATS connects plugins through a message bus
+	NATS NATSConfig
+}
+
+// NATSConfig holds the plugin message bus configuration
+type NATSConfig struct {
+	URL       string   // nats://[user[:password]@]host[:port]; empty disables the bus
+	Subject   string   // Prefix of the bus subjects
+	Queue     string   // Queue group otter workers share inbound messages in
+	Platforms []string // Platforms whose plugins are reached through the bus
 }
 
 // PluginSettings holds generic plugin settings
 type PluginSettings struct {
-	Enabled bool
-	Token   string
-	Config  map[string]string
+	Enabled bool              // False keeps the plugin from loading
+	Token   string            // Passed to the plugin as "token"
+	Config  map[string]string // Passed to the plugin as is
 }
 
-// Load reads configuration from environment variables and .env file
+// Load reads configuration from environment variables, the .env file and
+// the YAML config file named by OTTER_CONFIG_FILE (otter.yaml by default).
+// Environment variables override the config file.
 func Load() (*Config, error) {
 	// Load .env file if it exists (development mode)
 	_ = godotenv.Load()
 
+	file, err := readConfigFile(os.Getenv("OTTER_CONFIG_FILE"))
+	if err != nil {
+		return nil, err
+	}
+	loadMu.Lock()
+	current = file
+	defer func() {
+		current = nil
+		loadMu.Unlock()
+	}()
+
+	cfg, err := load()
+	if file == nil {
+		return cfg, err
+	}
+	if err != nil {
+		// Values the file got wrong are likelier causes than what they fell
+		// back to
+		if len(file.problems) > 0 {
+			return nil, file.err()
+		}
+		return nil, file.annotate(err)
+	}
+	if err := file.check(); err != nil {
+		return nil, err
+	}
+	cfg.File = file.path
+	return cfg, nil
+}
+
+func load() (*Config, error) {
 	raftID, err := getEnvRequired("OTTER_RAFT_ID")
 	if err != nil {
 		return nil, err
 	}
 
+	profiles, err := parseLLMProfiles(lookupEnv("OTTER_LLM_PROFILES"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTTER_LLM_PROFILES: %w", err)
+	}
+
+	holdApprovers, err := parseHoldApprovers(getEnvAsList("OTTER_HOLD_APPROVERS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTTER_HOLD_APPROVERS: %w", err)
+	}
+
+	voters, err := parseVoters(getEnvAsList("OTTER_PLUGIN_VOTERS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTTER_PLUGIN_VOTERS: %w", err)
+	}
+
+	pluginSettings, err := loadPluginSettings()
+	if err != nil {
+		return nil, fmt.Errorf("invalid plugin settings: %w", err)
+	}
+
+	contacts, err := parseContacts(getEnvAsList("OTTER_ONBOARDING_CONTACTS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTTER_ONBOARDING_CONTACTS: %w", err)
+	}
+
+	digestAt, err := parseTimeOfDay(getEnv("OTTER_SCHEDULER_DIGEST_TIME", "09:00"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTTER_SCHEDULER_DIGEST_TIME: %w", err)
+	}
+
+	sources, err := parseConnectors(getEnvAsList("OTTER_CONNECTORS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTTER_CONNECTORS: %w", err)
+	}
+
 	cfg := &Config{
-		Env:           getEnv("OTTER_ENV", "development"),
-		Port:          getEnvAsInt("OTTER_PORT", 8080),
-		DBPath:        getEnv("OTTER_DB_PATH", "/data/otter.db"),
-		VectorBackend: getEnv("OTTER_VECTOR_BACKEND", "sqlite"),
+		Env:             getEnv("OTTER_ENV", "development"),
+		Port:            getEnvAsInt("OTTER_PORT", 8080),
+		DBPath:          getEnv("OTTER_DB_PATH", "/data/otter.db"),
+		VectorBackend:   getEnv("OTTER_VECTOR_BACKEND", "sqlite"),
+		VectorSnapshot:  getEnv("OTTER_VECTOR_SNAPSHOT", ""),
+		ShutdownTimeout: getEnvAsDuration("OTTER_SHUTDOWN_TIMEOUT", 30*time.Second),
+		ReadOnly:        getEnvAsBool("OTTER_READ_ONLY", false),
+		MaintenanceMode: getEnvAsBool("OTTER_MAINTENANCE_MODE", false),
 		Raft: RaftConfig{
-			ID:            raftID,
-			Type:          getEnv("OTTER_RAFT_TYPE", "raft"),
-			BindAddr:      getEnv("OTTER_RAFT_BIND_ADDR", "127.0.0.1:7000"),
-			AdvertiseAddr: getEnv("OTTER_RAFT_ADVERTISE_ADDR", "127.0.0.1:7000"),
-			DataDir:       getEnv("OTTER_RAFT_DATA_DIR", "/data/raft"),
+			ID:                  raftID,
+			Type:                getEnv("OTTER_RAFT_TYPE", "raft"),
+			BindAddr:            getEnv("OTTER_RAFT_BIND_ADDR", "127.0.0.1:7000"),
+			AdvertiseAddr:       getEnv("OTTER_RAFT_ADVERTISE_ADDR", "127.0.0.1:7000"),
+			PeerEndpoint:        getEnv("OTTER_RAFT_PEER_ENDPOINT", ""),
+			DataDir:             getEnv("OTTER_RAFT_DATA_DIR", "/data/raft"),
+			VotingPeriod:        getEnvAsDuration("OTTER_PROPOSAL_VOTING_PERIOD", 7*24*time.Hour),
+			HeartbeatInterval:   getEnvAsDuration("OTTER_HEARTBEAT_INTERVAL", 30*time.Second),
+			HeartbeatGrace:      getEnvAsDuration("OTTER_HEARTBEAT_GRACE", 5*time.Minute),
+			LeaveRules:          getEnv("OTTER_LEAVE_RULES", "drop"),
+			VotingQuorum:        getEnvAsFloat("OTTER_VOTING_QUORUM", 0.67),
+			VotingMajority:      getEnvAsFloat("OTTER_VOTING_MAJORITY", 0.67),
+			VotingSuperMajority: getEnvAsFloat("OTTER_VOTING_SUPER_MAJORITY", 0.75),
+			VotingTieBreak:      getEnv("OTTER_VOTING_TIE_BREAK", "reject"),
+			SuspensionQuorum:    getEnvAsInt("OTTER_SUSPENSION_QUORUM", 2),
+			SuspensionWindow:    getEnvAsDuration("OTTER_SUSPENSION_WINDOW", time.Hour),
+			RuleEnforcement:     getEnv("OTTER_RULE_ENFORCEMENT", "structured"),
+			BootstrapFile:       getEnv("OTTER_CONSTITUTION_PATH", getEnv("OTTER_BOOTSTRAP_FILE", "")),
 		},
 		LLM: LLMConfig{
 			Provider:       getEnv("OTTER_LLM_PROVIDER", "openwebui"),
@@ -93,6 +497,25 @@ func Load() (*Config, error) {
 			Model:          getEnv("OTTER_LLM_MODEL", "llama2"),
 			EmbeddingModel: getEnv("OTTER_LLM_EMBEDDING_MODEL", ""),
 			APIKey:         getEnv("OTTER_LLM_API_KEY", ""),
+			Profiles:       profiles,
+			PromptDir:      getEnv("OTTER_LLM_PROMPT_DIR", ""),
+			ContextWindow:  getEnvAsInt("OTTER_LLM_CONTEXT_WINDOW", 4096),
+
+			Timeout:          getEnvAsDuration("OTTER_LLM_TIMEOUT", 120*time.Second),
+			MaxRetries:       getEnvAsInt("OTTER_LLM_MAX_RETRIES", 2),
+			RetryBackoff:     getEnvAsDuration("OTTER_LLM_RETRY_BACKOFF", 500*time.Millisecond),
+			BreakerThreshold: getEnvAsInt("OTTER_LLM_BREAKER_THRESHOLD", 5),
+			BreakerCooldown:  getEnvAsDuration("OTTER_LLM_BREAKER_COOLDOWN", 30*time.Second),
+			MaxConcurrent:    getEnvAsInt("OTTER_LLM_MAX_CONCURRENT", 4),
+			MaxQueued:        getEnvAsInt("OTTER_LLM_MAX_QUEUED", 64),
+			QueueTimeout:     getEnvAsDuration("OTTER_LLM_QUEUE_TIMEOUT", 2*time.Minute),
+		},
+		Embedding: EmbeddingConfig{
+			Provider:      getEnv("OTTER_EMBEDDING_PROVIDER", ""),
+			Model:         getEnv("OTTER_EMBEDDING_MODEL", ""),
+			Endpoint:      getEnv("OTTER_EMBEDDING_ENDPOINT", ""),
+			APIKey:        getEnv("OTTER_EMBEDDING_API_KEY", ""),
+			MaxConcurrent: getEnvAsInt("OTTER_EMBEDDING_MAX_CONCURRENT", 8),
 		},
 		API: APIConfig{
 			Port:            getEnvAsInt("OTTER_PORT", 8080),
@@ -101,12 +524,169 @@ func Load() (*Config, error) {
 			JWTSecret:       getEnv("OTTER_JWT_SECRET", ""),
 			RateLimit:       getEnvAsInt("OTTER_RATE_LIMIT", 100),
 			RateLimitWindow: getEnvAsDuration("OTTER_RATE_LIMIT_WINDOW", 1*time.Minute),
+			RateLimitChat:   getEnvAsInt("OTTER_RATE_LIMIT_CHAT", 0),
+			RateLimitRead:   getEnvAsInt("OTTER_RATE_LIMIT_READ", 0),
+			RateLimitKey:    getEnv("OTTER_RATE_LIMIT_KEY", "ip"),
+			TrustedProxies:  getEnvAsList("OTTER_TRUSTED_PROXIES"),
+			HoldApprovers:   holdApprovers,
 		},
 		Plugins: PluginConfig{
-			Enabled: []string{},
+			Dir:            getEnv("OTTER_PLUGINS_DIR", ""),
+			Enabled:        getEnvAsList("OTTER_PLUGINS_ENABLED"),
+			Settings:       pluginSettings,
+			HealthInterval: getEnvAsDuration("OTTER_PLUGIN_HEALTH_INTERVAL", 30*time.Second),
+			Voters:         voters,
+			ProfilesFile:   getEnv("OTTER_CHANNEL_PROFILES_FILE", ""),
+			WebhooksFile:   getEnv("OTTER_WEBHOOKS_FILE", ""),
+			NATS: NATSConfig{
+				URL:       getEnv("OTTER_NATS_URL", ""),
+				Subject:   getEnv("OTTER_NATS_SUBJECT", "otter"),
+				Queue:     getEnv("OTTER_NATS_QUEUE", "otter-workers"),
+				Platforms: getEnvAsList("OTTER_NATS_PLATFORMS"),
+			},
+		},
+		SQLite: SQLiteConfig{
+			BusyTimeout:         getEnvAsDuration("OTTER_SQLITE_BUSY_TIMEOUT", 5*time.Second),
+			WAL:                 getEnvAsBool("OTTER_SQLITE_WAL", true),
+			BatchWindow:         getEnvAsDuration("OTTER_SQLITE_BATCH_WINDOW", 5*time.Millisecond),
+			BatchSize:           getEnvAsInt("OTTER_SQLITE_BATCH_SIZE", 100),
+			MaintenanceInterval: getEnvAsDuration("OTTER_SQLITE_MAINTENANCE_INTERVAL", 24*time.Hour),
+		},
+		LanceDB: LanceDBConfig{
+			URL:      getEnv("OTTER_LANCEDB_URL", ""),
+			APIKey:   getEnv("OTTER_LANCEDB_API_KEY", ""),
+			Database: getEnv("OTTER_LANCEDB_DATABASE", ""),
+		},
+		Qdrant: QdrantConfig{
+			URL:              getEnv("OTTER_QDRANT_URL", ""),
+			APIKey:           getEnv("OTTER_QDRANT_API_KEY", ""),
+			CollectionPrefix: getEnv("OTTER_QDRANT_COLLECTION_PREFIX", "otter_"),
+		},
+		Consolidation: ConsolidationConfig{
+			Interval:        getEnvAsDuration("OTTER_CONSOLIDATION_INTERVAL", 6*time.Hour),
+			MinAge:          getEnvAsDuration("OTTER_CONSOLIDATION_MIN_AGE", 7*24*time.Hour),
+			MaxImportance:   float32(getEnvAsFloat("OTTER_CONSOLIDATION_MAX_IMPORTANCE", 0.5)),
+			Similarity:      getEnvAsFloat("OTTER_CONSOLIDATION_SIMILARITY", 0.8),
+			MinClusterSize:  getEnvAsInt("OTTER_CONSOLIDATION_MIN_CLUSTER", 3),
+			MaxClusterSize:  getEnvAsInt("OTTER_CONSOLIDATION_MAX_CLUSTER", 12),
+			BatchSize:       getEnvAsInt("OTTER_CONSOLIDATION_BATCH_SIZE", 500),
+			DeleteOriginals: getEnvAsBool("OTTER_CONSOLIDATION_DELETE_ORIGINALS", false),
+		},
+		Retention: RetentionConfig{
+			Interval:       getEnvAsDuration("OTTER_RETENTION_INTERVAL", 24*time.Hour),
+			HalfLife:       getEnvAsDuration("OTTER_RETENTION_HALF_LIFE", 90*24*time.Hour),
+			Floor:          float32(getEnvAsFloat("OTTER_RETENTION_FLOOR", 0.05)),
+			Demote:         getEnvAsBool("OTTER_RETENTION_DEMOTE", true),
+			Reinforcement:  float32(getEnvAsFloat("OTTER_RETENTION_REINFORCEMENT", 0.05)),
+			MinAge:         getEnvAsDuration("OTTER_RETENTION_MIN_AGE", 7*24*time.Hour),
+			PurgeInterval:  getEnvAsDuration("OTTER_RETENTION_PURGE_INTERVAL", 24*time.Hour),
+			RecoveryWindow: getEnvAsDuration("OTTER_RETENTION_RECOVERY_WINDOW", 30*24*time.Hour),
+		},
+		Dedup: DedupConfig{
+			Threshold: getEnvAsFloat("OTTER_DEDUP_THRESHOLD", 0.97),
+			Window:    getEnvAsDuration("OTTER_DEDUP_WINDOW", 7*24*time.Hour),
+			Action:    getEnv("OTTER_DEDUP_ACTION", "bump"),
+			Bump:      float32(getEnvAsFloat("OTTER_DEDUP_BUMP", 0.05)),
+		},
+		Musing: MusingConfig{
+			Interval:     getEnvAsDuration("OTTER_MUSING_INTERVAL", 2*time.Minute),
+			MemoryWindow: getEnvAsInt("OTTER_MUSING_MEMORY_WINDOW", 8),
+			MinMemories:  getEnvAsInt("OTTER_MUSING_MIN_MEMORIES", 2),
+			Timeout:      getEnvAsDuration("OTTER_MUSING_TIMEOUT", 180*time.Second),
+			PromptLimit:  getEnvAsInt("OTTER_MUSING_PROMPT_LIMIT", 3),
+		},
+		Intent: IntentConfig{
+			Mode:      getEnv("OTTER_INTENT_MODE", "auto"),
+			Threshold: getEnvAsFloat("OTTER_INTENT_THRESHOLD", 0.7),
+			Margin:    getEnvAsFloat("OTTER_INTENT_MARGIN", 0.05),
+		},
+		Retrieval: RetrievalConfig{
+			MinScore:         getEnvAsFloat("OTTER_RETRIEVAL_MIN_SCORE", 0.2),
+			DedupeSimilarity: getEnvAsFloat("OTTER_RETRIEVAL_DEDUPE_SIMILARITY", 0.95),
+			Candidates:       getEnvAsInt("OTTER_RETRIEVAL_CANDIDATES", 20),
+			Rerank:           getEnv("OTTER_RETRIEVAL_RERANK", "off"),
+			RerankEndpoint:   getEnv("OTTER_RETRIEVAL_RERANK_ENDPOINT", ""),
+			RerankAPIKey:     getEnv("OTTER_RETRIEVAL_RERANK_API_KEY", ""),
+			FactBoost:        getEnvAsFloat("OTTER_RETRIEVAL_FACT_BOOST", 0.05),
+		},
+		Extraction: ExtractionConfig{
+			Enabled:    getEnvAsBool("OTTER_EXTRACTION_ENABLED", true),
+			MaxFacts:   getEnvAsInt("OTTER_EXTRACTION_MAX_FACTS", 5),
+			Importance: float32(getEnvAsFloat("OTTER_EXTRACTION_IMPORTANCE", 0.8)),
+		},
+		Scheduler: SchedulerConfig{
+			Interval:        getEnvAsDuration("OTTER_SCHEDULER_INTERVAL", 30*time.Second),
+			MaxAttempts:     getEnvAsInt("OTTER_SCHEDULER_MAX_ATTEMPTS", 5),
+			DigestTarget:    getEnv("OTTER_SCHEDULER_DIGEST_TARGET", ""),
+			DigestAt:        digestAt,
+			DeadlineTarget:  getEnv("OTTER_SCHEDULER_DEADLINE_TARGET", ""),
+			DeadlineWarning: getEnvAsDuration("OTTER_SCHEDULER_DEADLINE_WARNING", 24*time.Hour),
+		},
+		Onboarding: OnboardingConfig{
+			Enabled:      getEnvAsBool("OTTER_ONBOARDING_ENABLED", true),
+			TemplateFile: getEnv("OTTER_ONBOARDING_TEMPLATES", ""),
+			Steps:        getEnvAsList("OTTER_ONBOARDING_STEPS"),
+			Contacts:     contacts,
+		},
+		Personality: PersonalityConfig{
+			SeedFile:   getEnv("OTTER_PERSONALITY_SEED_FILE", ""),
+			Interval:   getEnvAsDuration("OTTER_PERSONALITY_INTERVAL", time.Hour),
+			DriftRate:  getEnvAsFloat("OTTER_PERSONALITY_DRIFT_RATE", 0.02),
+			RuleWeight: getEnvAsFloat("OTTER_PERSONALITY_RULE_WEIGHT", 5),
+		},
+		Chaos: ChaosConfig{
+			Enabled:       getEnvAsBool("OTTER_CHAOS_ENABLED", false),
+			DropRate:      getEnvAsFloat("OTTER_CHAOS_DROP_RATE", 0),
+			DuplicateRate: getEnvAsFloat("OTTER_CHAOS_DUPLICATE_RATE", 0),
+			ReorderRate:   getEnvAsFloat("OTTER_CHAOS_REORDER_RATE", 0),
+			MinDelay:      getEnvAsDuration("OTTER_CHAOS_MIN_DELAY", 0),
+			MaxDelay:      getEnvAsDuration("OTTER_CHAOS_MAX_DELAY", 0),
+			Partitioned:   getEnvAsList("OTTER_CHAOS_PARTITION"),
+			Seed:          int64(getEnvAsInt("OTTER_CHAOS_SEED", 0)),
+		},
+		Connectors: ConnectorsConfig{
+			Sources:     sources,
+			Interval:    getEnvAsDuration("OTTER_CONNECTOR_INTERVAL", 15*time.Minute),
+			MaxItems:    getEnvAsInt("OTTER_CONNECTOR_MAX_ITEMS", 20),
+			GitHubToken: getEnv("OTTER_CONNECTOR_GITHUB_TOKEN", ""),
+		},
+		Tracing: TracingConfig{
+			Enabled:     getEnvAsBool("OTTER_TRACING_ENABLED", false),
+			Endpoint:    getEnv("OTTER_OTLP_ENDPOINT", ""),
+			Insecure:    getEnvAsBool("OTTER_OTLP_INSECURE", false),
+			SampleRate:  getEnvAsFloat("OTTER_TRACING_SAMPLE_RATE", 1),
+			ServiceName: getEnv("OTTER_TRACING_SERVICE_NAME", "otter-ai"),
+		},
+		Logging: LoggingConfig{
+			Level:  getEnv("OTTER_LOG_LEVEL", "info"),
+			Format: getEnv("OTTER_LOG_FORMAT", "text"),
+		},
+		Usage: UsageConfig{
+			DailyTokenBudget: getEnvAsInt("OTTER_LLM_DAILY_TOKEN_BUDGET", 0),
+			Deferrable:       getEnvAsList("OTTER_LLM_BUDGET_DEFERRABLE"),
+		},
+		Hooks: HooksConfig{
+			BeforeMessage:  getEnvAsList("OTTER_HOOK_BEFORE_MESSAGE_URLS"),
+			BeforeResponse: getEnvAsList("OTTER_HOOK_BEFORE_RESPONSE_URLS"),
+			Timeout:        getEnvAsDuration("OTTER_HOOK_TIMEOUT", 5*time.Second),
+			FailOpen:       getEnvAsBool("OTTER_HOOK_FAIL_OPEN", false),
+			Token:          getEnv("OTTER_HOOK_TOKEN", ""),
+		},
+		Secrets: SecretsConfig{
+			VaultAddr:      getEnv("OTTER_VAULT_ADDR", os.Getenv("VAULT_ADDR")),
+			VaultToken:     getEnv("OTTER_VAULT_TOKEN", os.Getenv("VAULT_TOKEN")),
+			VaultNamespace: getEnv("OTTER_VAULT_NAMESPACE", os.Getenv("VAULT_NAMESPACE")),
+			AWSRegion:      getEnv("OTTER_AWS_REGION", getEnv("AWS_REGION", os.Getenv("AWS_DEFAULT_REGION"))),
+			AWSEndpoint:    getEnv("OTTER_AWS_SECRETS_ENDPOINT", ""),
+			SOPSBinary:     getEnv("OTTER_SOPS_BINARY", "sops"),
+			Timeout:        getEnvAsDuration("OTTER_SECRETS_TIMEOUT", 10*time.Second),
 		},
 	}
 
+	if err := cfg.resolveSecrets(); err != nil {
+		return nil, err
+	}
+
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
@@ -122,16 +702,308 @@ func (c *Config) Validate() error {
 
 	// Raft type validation removed - all otters start as their own raft
 
+	if c.Raft.VotingPeriod < 0 {
+		return fmt.Errorf("OTTER_PROPOSAL_VOTING_PERIOD must not be negative")
+	}
+
+	if c.Raft.HeartbeatInterval < 0 {
+		return fmt.Errorf("OTTER_HEARTBEAT_INTERVAL must not be negative")
+	}
+	if c.Raft.HeartbeatInterval > 0 && c.Raft.HeartbeatGrace <= c.Raft.HeartbeatInterval {
+		return fmt.Errorf("OTTER_HEARTBEAT_GRACE must be longer than OTTER_HEARTBEAT_INTERVAL")
+	}
+	switch c.Raft.LeaveRules {
+	case "", "drop", "keep":
+	default:
+		return fmt.Errorf("OTTER_LEAVE_RULES must be drop or keep, got %q", c.Raft.LeaveRules)
+	}
+	for _, fraction := range []struct {
+		name  string
+		value float64
+	}{
+		{"OTTER_VOTING_QUORUM", c.Raft.VotingQuorum},
+		{"OTTER_VOTING_MAJORITY", c.Raft.VotingMajority},
+		{"OTTER_VOTING_SUPER_MAJORITY", c.Raft.VotingSuperMajority},
+	} {
+		if fraction.value < 0 || fraction.value > 1 {
+			return fmt.Errorf("%s must be between 0 and 1, got %v", fraction.name, fraction.value)
+		}
+	}
+	if c.Raft.VotingMajority > 0 && c.Raft.VotingSuperMajority > 0 && c.Raft.VotingSuperMajority < c.Raft.VotingMajority {
+		return fmt.Errorf("OTTER_VOTING_SUPER_MAJORITY must be at least OTTER_VOTING_MAJORITY")
+	}
+	switch c.Raft.VotingTieBreak {
+	case "", "reject", "adopt", "proposer":
+	default:
+		return fmt.Errorf("OTTER_VOTING_TIE_BREAK must be reject, adopt or proposer, got %q", c.Raft.VotingTieBreak)
+	}
+	if c.Raft.SuspensionQuorum < 0 {
+		return fmt.Errorf("OTTER_SUSPENSION_QUORUM must not be negative")
+	}
+	if c.Raft.SuspensionWindow < 0 {
+		return fmt.Errorf("OTTER_SUSPENSION_WINDOW must not be negative")
+	}
+	switch c.Raft.RuleEnforcement {
+	case "", "off", "structured", "llm":
+	default:
+		return fmt.Errorf("OTTER_RULE_ENFORCEMENT must be off, structured or llm, got %q", c.Raft.RuleEnforcement)
+	}
+
 	if c.Port < 1 || c.Port > 65535 {
 		return fmt.Errorf("invalid port: %d", c.Port)
 	}
 
+	if c.API.RateLimitChat < 0 || c.API.RateLimitRead < 0 {
+		return fmt.Errorf("OTTER_RATE_LIMIT_CHAT and OTTER_RATE_LIMIT_READ must not be negative")
+	}
+	switch c.API.RateLimitKey {
+	case "", "ip", "subject", "token":
+	default:
+		return fmt.Errorf("OTTER_RATE_LIMIT_KEY must be ip, subject or token, got %q", c.API.RateLimitKey)
+	}
+	for _, proxy := range c.API.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			return fmt.Errorf("OTTER_TRUSTED_PROXIES: %q is not an IP or CIDR", proxy)
+		}
+	}
+
+	if c.SQLite.BusyTimeout < 0 || c.SQLite.BatchWindow < 0 || c.SQLite.BatchSize < 0 || c.SQLite.MaintenanceInterval < 0 {
+		return fmt.Errorf("OTTER_SQLITE_BUSY_TIMEOUT, OTTER_SQLITE_BATCH_WINDOW, OTTER_SQLITE_BATCH_SIZE and OTTER_SQLITE_MAINTENANCE_INTERVAL must not be negative")
+	}
+	if c.ShutdownTimeout < 0 {
+		return fmt.Errorf("OTTER_SHUTDOWN_TIMEOUT must not be negative")
+	}
+	if c.VectorSnapshot != "" && c.VectorBackend != "memory" {
+		return fmt.Errorf("OTTER_VECTOR_SNAPSHOT is only used by the memory backend")
+	}
+	if c.VectorBackend == "lancedb" && c.LanceDB.URL == "" {
+		return fmt.Errorf("OTTER_LANCEDB_URL is required for the lancedb backend")
+	}
+	if c.VectorBackend == "qdrant" && c.Qdrant.URL == "" {
+		return fmt.Errorf("OTTER_QDRANT_URL is required for the qdrant backend")
+	}
+
+	if c.LLM.Timeout < 0 || c.LLM.MaxRetries < 0 || c.LLM.RetryBackoff < 0 || c.LLM.BreakerThreshold < 0 || c.LLM.BreakerCooldown < 0 {
+		return fmt.Errorf("OTTER_LLM_TIMEOUT, retry and circuit breaker settings must not be negative")
+	}
+	if c.LLM.ContextWindow < 0 {
+		return fmt.Errorf("OTTER_LLM_CONTEXT_WINDOW must not be negative")
+	}
+	if c.LLM.MaxConcurrent < 0 || c.LLM.MaxQueued < 0 || c.LLM.QueueTimeout < 0 || c.Embedding.MaxConcurrent < 0 {
+		return fmt.Errorf("OTTER_LLM_MAX_CONCURRENT, OTTER_LLM_MAX_QUEUED, OTTER_LLM_QUEUE_TIMEOUT and OTTER_EMBEDDING_MAX_CONCURRENT must not be negative")
+	}
+
+	if c.Consolidation.Interval < 0 {
+		return fmt.Errorf("OTTER_CONSOLIDATION_INTERVAL must not be negative")
+	}
+	// A separate embedding provider shares nothing with the LLM's settings
+	if c.Embedding.Provider != "" && c.Embedding.Provider != c.LLM.Provider {
+		switch c.Embedding.Provider {
+		case "ollama", "openwebui":
+			if c.Embedding.Model == "" {
+				return fmt.Errorf("OTTER_EMBEDDING_MODEL is required for the %s embedding provider", c.Embedding.Provider)
+			}
+		case "openai":
+			if c.Embedding.APIKey == "" {
+				return fmt.Errorf("OTTER_EMBEDDING_API_KEY is required for the openai embedding provider")
+			}
+		default:
+			return fmt.Errorf("OTTER_EMBEDDING_PROVIDER must be ollama, openwebui or openai")
+		}
+		if c.Embedding.Endpoint == "" {
+			return fmt.Errorf("OTTER_EMBEDDING_ENDPOINT is required for a separate embedding provider")
+		}
+	}
+
+	if c.Consolidation.Interval > 0 {
+		if c.Consolidation.Similarity <= 0 || c.Consolidation.Similarity > 1 {
+			return fmt.Errorf("OTTER_CONSOLIDATION_SIMILARITY must be between 0 and 1")
+		}
+		if c.Consolidation.MinClusterSize < 2 || c.Consolidation.MaxClusterSize < c.Consolidation.MinClusterSize {
+			return fmt.Errorf("consolidation cluster sizes must satisfy 2 <= OTTER_CONSOLIDATION_MIN_CLUSTER <= OTTER_CONSOLIDATION_MAX_CLUSTER")
+		}
+	}
+
+	if c.Retention.PurgeInterval < 0 || c.Retention.RecoveryWindow < 0 {
+		return fmt.Errorf("OTTER_RETENTION_PURGE_INTERVAL and OTTER_RETENTION_RECOVERY_WINDOW must not be negative")
+	}
+	if c.Retention.Interval < 0 || c.Retention.HalfLife < 0 || c.Retention.MinAge < 0 {
+		return fmt.Errorf("OTTER_RETENTION_INTERVAL, OTTER_RETENTION_HALF_LIFE and OTTER_RETENTION_MIN_AGE must not be negative")
+	}
+	if c.Retention.Floor < 0 || c.Retention.Floor >= 1 {
+		return fmt.Errorf("OTTER_RETENTION_FLOOR must be at least 0 and below 1")
+	}
+	if c.Retention.Reinforcement < 0 || c.Retention.Reinforcement > 1 {
+		return fmt.Errorf("OTTER_RETENTION_REINFORCEMENT must be between 0 and 1")
+	}
+
+	if c.Dedup.Threshold < 0 || c.Dedup.Threshold > 1 || c.Dedup.Bump < 0 || c.Dedup.Bump > 1 {
+		return fmt.Errorf("OTTER_DEDUP_THRESHOLD and OTTER_DEDUP_BUMP must be between 0 and 1")
+	}
+	if c.Dedup.Window < 0 {
+		return fmt.Errorf("OTTER_DEDUP_WINDOW must not be negative")
+	}
+	switch c.Dedup.Action {
+	case "", "skip", "merge", "bump":
+	default:
+		return fmt.Errorf("OTTER_DEDUP_ACTION must be skip, merge or bump, got %q", c.Dedup.Action)
+	}
+
+	if c.Musing.Interval < 0 || c.Musing.Timeout < 0 {
+		return fmt.Errorf("OTTER_MUSING_INTERVAL and OTTER_MUSING_TIMEOUT must not be negative")
+	}
+	if c.Musing.Interval > 0 && (c.Musing.MinMemories < 1 || c.Musing.MemoryWindow < c.Musing.MinMemories) {
+		return fmt.Errorf("musing windows must satisfy 1 <= OTTER_MUSING_MIN_MEMORIES <= OTTER_MUSING_MEMORY_WINDOW")
+	}
+	if c.Musing.PromptLimit < 0 {
+		return fmt.Errorf("OTTER_MUSING_PROMPT_LIMIT must not be negative")
+	}
+
+	switch c.Intent.Mode {
+	case "", "off", "auto", "embedding", "llm":
+	default:
+		return fmt.Errorf("OTTER_INTENT_MODE must be off, auto, embedding or llm, got %q", c.Intent.Mode)
+	}
+	if c.Intent.Threshold < 0 || c.Intent.Threshold > 1 || c.Intent.Margin < 0 || c.Intent.Margin > 1 {
+		return fmt.Errorf("OTTER_INTENT_THRESHOLD and OTTER_INTENT_MARGIN must be between 0 and 1")
+	}
+
+	if c.Retrieval.MinScore < 0 || c.Retrieval.MinScore > 1 || c.Retrieval.DedupeSimilarity < 0 || c.Retrieval.DedupeSimilarity > 1 {
+		return fmt.Errorf("OTTER_RETRIEVAL_MIN_SCORE and OTTER_RETRIEVAL_DEDUPE_SIMILARITY must be between 0 and 1")
+	}
+	if c.Retrieval.Candidates < 0 {
+		return fmt.Errorf("OTTER_RETRIEVAL_CANDIDATES must not be negative")
+	}
+	if c.Retrieval.FactBoost < 0 || c.Retrieval.FactBoost > 1 {
+		return fmt.Errorf("OTTER_RETRIEVAL_FACT_BOOST must be between 0 and 1")
+	}
+	switch c.Retrieval.Rerank {
+	case "", "off", "llm":
+	case "cross-encoder":
+		if c.Retrieval.RerankEndpoint == "" {
+			return fmt.Errorf("OTTER_RETRIEVAL_RERANK_ENDPOINT is required for cross-encoder re-ranking")
+		}
+	default:
+		return fmt.Errorf("OTTER_RETRIEVAL_RERANK must be off, llm or cross-encoder, got %q", c.Retrieval.Rerank)
+	}
+
+	if c.Extraction.MaxFacts < 0 {
+		return fmt.Errorf("OTTER_EXTRACTION_MAX_FACTS must not be negative")
+	}
+	if c.Extraction.Importance < 0 || c.Extraction.Importance > 1 {
+		return fmt.Errorf("OTTER_EXTRACTION_IMPORTANCE must be between 0 and 1")
+	}
+
+	if c.Scheduler.Interval < 0 || c.Scheduler.DeadlineWarning < 0 || c.Scheduler.MaxAttempts < 0 {
+		return fmt.Errorf("OTTER_SCHEDULER_INTERVAL, OTTER_SCHEDULER_MAX_ATTEMPTS and OTTER_SCHEDULER_DEADLINE_WARNING must not be negative")
+	}
+	for key, target := range map[string]string{
+		"OTTER_SCHEDULER_DIGEST_TARGET":   c.Scheduler.DigestTarget,
+		"OTTER_SCHEDULER_DEADLINE_TARGET": c.Scheduler.DeadlineTarget,
+	} {
+		if platform, channel, ok := strings.Cut(target, ":"); target != "" && (!ok || platform == "" || channel == "") {
+			return fmt.Errorf("%s must be platform:channel_id, got %q", key, target)
+		}
+	}
+
+	if c.Plugins.HealthInterval < 0 {
+		return fmt.Errorf("OTTER_PLUGIN_HEALTH_INTERVAL must not be negative")
+	}
+	if c.Plugins.NATS.URL != "" {
+		if u, err := url.Parse(c.Plugins.NATS.URL); err != nil || u.Scheme != "nats" || u.Host == "" {
+			return fmt.Errorf("OTTER_NATS_URL must be nats://[user[:password]@]host[:port]")
+		}
+		if c.Plugins.NATS.Subject == "" || strings.ContainsAny(c.Plugins.NATS.Subject, " *>") {
+			return fmt.Errorf("OTTER_NATS_SUBJECT must be a subject prefix without spaces or wildcards")
+		}
+	}
+
+	if c.Personality.Interval < 0 {
+		return fmt.Errorf("OTTER_PERSONALITY_INTERVAL must not be negative")
+	}
+	if c.Personality.Interval > 0 && (c.Personality.DriftRate <= 0 || c.Personality.DriftRate > 1) {
+		return fmt.Errorf("OTTER_PERSONALITY_DRIFT_RATE must be between 0 and 1")
+	}
+	if c.Personality.Interval > 0 && c.Personality.RuleWeight <= 0 {
+		return fmt.Errorf("OTTER_PERSONALITY_RULE_WEIGHT must be positive")
+	}
+
+	if c.Chaos.Enabled {
+		for name, rate := range map[string]float64{
+			"OTTER_CHAOS_DROP_RATE":      c.Chaos.DropRate,
+			"OTTER_CHAOS_DUPLICATE_RATE": c.Chaos.DuplicateRate,
+			"OTTER_CHAOS_REORDER_RATE":   c.Chaos.ReorderRate,
+		} {
+			if rate < 0 || rate > 1 {
+				return fmt.Errorf("%s must be between 0 and 1", name)
+			}
+		}
+		if c.Chaos.MinDelay < 0 || c.Chaos.MaxDelay < c.Chaos.MinDelay {
+			return fmt.Errorf("chaos delays must satisfy 0 <= OTTER_CHAOS_MIN_DELAY <= OTTER_CHAOS_MAX_DELAY")
+		}
+	}
+
+	if len(c.Connectors.Sources) > 0 {
+		if c.Connectors.Interval <= 0 || c.Connectors.MaxItems <= 0 {
+			return fmt.Errorf("OTTER_CONNECTOR_INTERVAL and OTTER_CONNECTOR_MAX_ITEMS must be positive")
+		}
+		names := make(map[string]bool, len(c.Connectors.Sources))
+		for _, source := range c.Connectors.Sources {
+			if names[source.Name] {
+				return fmt.Errorf("duplicate connector name: %s", source.Name)
+			}
+			names[source.Name] = true
+
+			switch source.Kind {
+			case "rss", "ical":
+				u, err := url.Parse(source.Target)
+				if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+					return fmt.Errorf("connector %s: invalid URL %q", source.Name, source.Target)
+				}
+			case "github":
+				if owner, repo, ok := strings.Cut(source.Target, "/"); !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+					return fmt.Errorf("connector %s: expected owner/repo, got %q", source.Name, source.Target)
+				}
+			default:
+				return fmt.Errorf("connector %s: kind must be rss, ical or github", source.Name)
+			}
+		}
+	}
+
+	if c.Tracing.Enabled && (c.Tracing.SampleRate < 0 || c.Tracing.SampleRate > 1) {
+		return fmt.Errorf("OTTER_TRACING_SAMPLE_RATE must be between 0 and 1")
+	}
+
+	if c.Logging.Level != "" {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(c.Logging.Level)); err != nil {
+			return fmt.Errorf("OTTER_LOG_LEVEL must be debug, info, warn or error")
+		}
+	}
+	if c.Logging.Format != "" && c.Logging.Format != "text" && c.Logging.Format != "json" {
+		return fmt.Errorf("OTTER_LOG_FORMAT must be text or json")
+	}
+
+	if c.Usage.DailyTokenBudget < 0 {
+		return fmt.Errorf("OTTER_LLM_DAILY_TOKEN_BUDGET must not be negative")
+	}
+
+	for _, hookURL := range append(append([]string{}, c.Hooks.BeforeMessage...), c.Hooks.BeforeResponse...) {
+		u, err := url.Parse(hookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid hook URL: %q", hookURL)
+		}
+	}
+	if len(c.Hooks.BeforeMessage)+len(c.Hooks.BeforeResponse) > 0 && c.Hooks.Timeout <= 0 {
+		return fmt.Errorf("OTTER_HOOK_TIMEOUT must be positive")
+	}
+
 	return nil
 }
 
 // getEnv retrieves an environment variable or returns a default value
 func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
+	if value := lookupEnv(key); value != "" {
 		return value
 	}
 	return defaultValue
@@ -139,7 +1011,7 @@ func getEnv(key, defaultValue string) string {
 
 // getEnvRequired retrieves a required environment variable or fails
 func getEnvRequired(key string) (string, error) {
-	value := os.Getenv(key)
+	value := lookupEnv(key)
 	if value == "" {
 		return "", fmt.Errorf("required environment variable %s is not set", key)
 	}
@@ -148,12 +1020,13 @@ func getEnvRequired(key string) (string, error) {
 
 // getEnvAsInt retrieves an environment variable as an integer or returns a default value
 func getEnvAsInt(key string, defaultValue int) int {
-	valueStr := os.Getenv(key)
+	valueStr := lookupEnv(key)
 	if valueStr == "" {
 		return defaultValue
 	}
 	value, err := strconv.Atoi(valueStr)
 	if err != nil {
+		invalidValue(key, "an integer")
 		return defaultValue
 	}
 	return value
@@ -161,13 +1034,180 @@ func getEnvAsInt(key string, defaultValue int) int {
 
 // getEnvAsDuration retrieves an environment variable as a duration or returns a default value
 func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
-	valueStr := os.Getenv(key)
+	valueStr := lookupEnv(key)
 	if valueStr == "" {
 		return defaultValue
 	}
 	value, err := time.ParseDuration(valueStr)
 	if err != nil {
+		invalidValue(key, "a duration")
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvAsFloat retrieves an environment variable as a float or returns a default value
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	valueStr := lookupEnv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		invalidValue(key, "a number")
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvAsBool retrieves an environment variable as a boolean or returns a default value
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := lookupEnv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		invalidValue(key, "true or false")
 		return defaultValue
 	}
 	return value
 }
+
+// parseLLMProfiles parses profile overrides of the form
+// "musing:temperature=0.9,max_tokens=300;chat:temperature=none", where
+// temperature=none leaves the temperature to the provider
+func parseLLMProfiles(value string) (map[string]LLMProfile, error) {
+	profiles := make(map[string]LLMProfile)
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, settings, ok := strings.Cut(entry, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("expected name:settings, got %q", entry)
+		}
+
+		profile := profiles[name]
+		for _, setting := range strings.Split(settings, ",") {
+			key, raw, ok := strings.Cut(strings.TrimSpace(setting), "=")
+			if !ok {
+				return nil, fmt.Errorf("profile %s: expected key=value, got %q", name, setting)
+			}
+			raw = strings.TrimSpace(raw)
+			switch strings.TrimSpace(key) {
+			case "temperature":
+				if raw == "none" {
+					profile.OmitTemperature = true
+					profile.Temperature = nil
+					continue
+				}
+				temperature, err := strconv.ParseFloat(raw, 32)
+				if err != nil || temperature < 0 || temperature > 2 {
+					return nil, fmt.Errorf("profile %s: temperature must be between 0 and 2 or none", name)
+				}
+				t := float32(temperature)
+				profile.Temperature = &t
+				profile.OmitTemperature = false
+			case "max_tokens":
+				maxTokens, err := strconv.Atoi(raw)
+				if err != nil || maxTokens <= 0 {
+					return nil, fmt.Errorf("profile %s: max_tokens must be a positive integer", name)
+				}
+				profile.MaxTokens = maxTokens
+			default:
+				return nil, fmt.Errorf("profile %s: unknown setting %q", name, key)
+			}
+		}
+		profiles[name] = profile
+	}
+	return profiles, nil
+}
+
+// parseHoldApprovers parses approver to passphrase mappings of the form
+// "alice=passphrase"
+func parseHoldApprovers(entries []string) (map[string]string, error) {
+	approvers := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		name, passphrase, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || passphrase == "" {
+			return nil, fmt.Errorf("expected approver=passphrase, got an entry for %q", name)
+		}
+		if _, exists := approvers[name]; exists {
+			return nil, fmt.Errorf("approver %q is listed twice", name)
+		}
+		approvers[name] = passphrase
+	}
+	return approvers, nil
+}
+
+// parseVoters parses platform user to member mappings of the form
+// "discord:1234=otter-1"
+func parseVoters(entries []string) (map[string]string, error) {
+	voters := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		user, member, ok := strings.Cut(entry, "=")
+		platform, userID, hasPlatform := strings.Cut(strings.TrimSpace(user), ":")
+		member = strings.TrimSpace(member)
+		if !ok || !hasPlatform || platform == "" || userID == "" || member == "" {
+			return nil, fmt.Errorf("expected platform:user_id=member_id, got %q", entry)
+		}
+		voters[platform+":"+userID] = member
+	}
+	return voters, nil
+}
+
+// parseContacts parses member to platform user mappings of the form
+// "otter-2=discord:1234"
+func parseContacts(entries []string) (map[string]string, error) {
+	contacts := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		member, user, ok := strings.Cut(entry, "=")
+		platform, userID, hasPlatform := strings.Cut(strings.TrimSpace(user), ":")
+		member = strings.TrimSpace(member)
+		if !ok || !hasPlatform || platform == "" || userID == "" || member == "" {
+			return nil, fmt.Errorf("expected member_id=platform:user_id, got %q", entry)
+		}
+		contacts[member] = platform + ":" + userID
+	}
+	return contacts, nil
+}
+
+// parseTimeOfDay parses a 24-hour "HH:MM" time as an offset from midnight
+func parseTimeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("expected HH:MM, got %q", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// parseConnectors parses sources of the form name=kind:target, e.g.
+// news=rss:https://example.org/feed.xml or code=github:owner/repo
+func parseConnectors(entries []string) ([]ConnectorConfig, error) {
+	sources := make([]ConnectorConfig, 0, len(entries))
+	for _, entry := range entries {
+		name, source, ok := strings.Cut(entry, "=")
+		kind, target, hasKind := strings.Cut(strings.TrimSpace(source), ":")
+		name = strings.TrimSpace(name)
+		if !ok || !hasKind || name == "" || kind == "" || target == "" {
+			return nil, fmt.Errorf("expected name=kind:target, got %q", entry)
+		}
+		sources = append(sources, ConnectorConfig{Name: name, Kind: strings.ToLower(kind), Target: target})
+	}
+	return sources, nil
+}
+
+// getEnvAsList retrieves a comma-separated environment variable, skipping empty entries
+func getEnvAsList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(lookupEnv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
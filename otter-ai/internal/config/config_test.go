@@ -1,7 +1,12 @@
 package config
 
 import (
+	"bytes"
+	"fmt"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -15,6 +20,7 @@ func clearEnv(t *testing.T) {
 		"OTTER_LLM_ENDPOINT", "OTTER_LLM_MODEL", "OTTER_LLM_API_KEY",
 		"OTTER_HOST", "OTTER_HOST_PASSPHRASE", "OTTER_JWT_SECRET",
 		"OTTER_RATE_LIMIT", "OTTER_RATE_LIMIT_WINDOW",
+		"OTTER_CONSTITUTION_PATH", "OTTER_BOOTSTRAP_FILE",
 	} {
 		os.Unsetenv(k)
 	}
@@ -99,6 +105,26 @@ func TestLoad_CustomValues(t *testing.T) {
 	}
 }
 
+func TestLoad_ConstitutionPath(t *testing.T) {
+	clearEnv(t)
+	os.Setenv("OTTER_RAFT_ID", "r1")
+	os.Setenv("OTTER_BOOTSTRAP_FILE", "/etc/otter/bootstrap.yaml")
+	t.Cleanup(func() { clearEnv(t) })
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Raft.BootstrapFile != "/etc/otter/bootstrap.yaml" {
+		t.Errorf("BootstrapFile = %q", cfg.Raft.BootstrapFile)
+	}
+
+	os.Setenv("OTTER_CONSTITUTION_PATH", "/etc/otter/constitution.json")
+	if cfg, _ = Load(); cfg.Raft.BootstrapFile != "/etc/otter/constitution.json" {
+		t.Errorf("OTTER_CONSTITUTION_PATH should win, got %q", cfg.Raft.BootstrapFile)
+	}
+}
+
 func TestValidate_EmptyRaftID(t *testing.T) {
 	cfg := &Config{Raft: RaftConfig{ID: ""}, Port: 8080}
 	if err := cfg.Validate(); err == nil {
@@ -217,3 +243,631 @@ func TestGetEnvAsDuration_Invalid(t *testing.T) {
 		t.Errorf("got %v; want 10s (default)", v)
 	}
 }
+
+func TestGetEnvAsList(t *testing.T) {
+	os.Setenv("OTTER_LIST_TEST", " http://a ,, http://b,")
+	t.Cleanup(func() { os.Unsetenv("OTTER_LIST_TEST") })
+
+	got := getEnvAsList("OTTER_LIST_TEST")
+	if len(got) != 2 || got[0] != "http://a" || got[1] != "http://b" {
+		t.Errorf("got %q", got)
+	}
+	if got := getEnvAsList("OTTER_NONEXISTENT"); got != nil {
+		t.Errorf("unset list = %q, want nil", got)
+	}
+}
+
+func TestGetEnvAsBool(t *testing.T) {
+	os.Setenv("OTTER_BOOL_TEST", "true")
+	os.Setenv("OTTER_BOOL_BAD", "maybe")
+	t.Cleanup(func() {
+		os.Unsetenv("OTTER_BOOL_TEST")
+		os.Unsetenv("OTTER_BOOL_BAD")
+	})
+
+	if !getEnvAsBool("OTTER_BOOL_TEST", false) {
+		t.Error("expected true")
+	}
+	if !getEnvAsBool("OTTER_BOOL_BAD", true) {
+		t.Error("invalid value should fall back to the default")
+	}
+}
+
+func TestValidate_HookURLs(t *testing.T) {
+	cfg := &Config{Raft: RaftConfig{ID: "r"}, Port: 8080,
+		Hooks: HooksConfig{BeforeMessage: []string{"https://dlp.example.com/check"}, Timeout: time.Second}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	cfg.Hooks.BeforeResponse = []string{"dlp.example.com"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a hook URL without scheme")
+	}
+
+	cfg.Hooks.BeforeResponse = nil
+	cfg.Hooks.Timeout = 0
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a zero hook timeout")
+	}
+}
+
+func TestValidate_LanceDBRequiresURL(t *testing.T) {
+	cfg := &Config{Raft: RaftConfig{ID: "r"}, Port: 8080, VectorBackend: "lancedb", Hooks: HooksConfig{Timeout: time.Second}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for lancedb without OTTER_LANCEDB_URL")
+	}
+
+	cfg.LanceDB.URL = "http://lancedb:8080"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
+
+func TestValidate_QdrantRequiresURL(t *testing.T) {
+	cfg := &Config{Raft: RaftConfig{ID: "r"}, Port: 8080, VectorBackend: "qdrant", Hooks: HooksConfig{Timeout: time.Second}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for qdrant without OTTER_QDRANT_URL")
+	}
+
+	cfg.Qdrant.URL = "http://qdrant:6333"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
+
+func TestValidate_VectorSnapshot(t *testing.T) {
+	cfg := &Config{Raft: RaftConfig{ID: "r"}, Port: 8080, VectorBackend: "sqlite", VectorSnapshot: "/data/vectors.json"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a snapshot with the sqlite backend")
+	}
+	cfg.VectorBackend = "memory"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
+
+func TestValidate_Consolidation(t *testing.T) {
+	cfg := &Config{Raft: RaftConfig{ID: "r"}, Port: 8080,
+		Consolidation: ConsolidationConfig{Interval: time.Hour, Similarity: 0.8, MinClusterSize: 3, MaxClusterSize: 12}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	cfg.Consolidation.MaxClusterSize = 2
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for max cluster size below min")
+	}
+
+	cfg.Consolidation.MaxClusterSize = 12
+	cfg.Consolidation.Similarity = 1.5
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for similarity above 1")
+	}
+
+	// Disabled consolidation is not validated further
+	cfg.Consolidation.Interval = 0
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate with consolidation disabled: %v", err)
+	}
+}
+
+func TestParseLLMProfiles(t *testing.T) {
+	profiles, err := parseLLMProfiles("musing: temperature=0.8, max_tokens=300; chat:temperature=none;")
+	if err != nil {
+		t.Fatalf("parseLLMProfiles: %v", err)
+	}
+	musing := profiles["musing"]
+	if musing.Temperature == nil || *musing.Temperature != 0.8 || musing.MaxTokens != 300 {
+		t.Errorf("musing = %+v", musing)
+	}
+	if chat := profiles["chat"]; !chat.OmitTemperature || chat.Temperature != nil {
+		t.Errorf("chat = %+v", chat)
+	}
+
+	if profiles, err := parseLLMProfiles(""); err != nil || len(profiles) != 0 {
+		t.Errorf("empty value = %v, %v", profiles, err)
+	}
+
+	for _, bad := range []string{"musing", "musing:temp=1", "musing:temperature=3", "chat:max_tokens=0", ":temperature=1"} {
+		if _, err := parseLLMProfiles(bad); err == nil {
+			t.Errorf("parseLLMProfiles(%q) should fail", bad)
+		}
+	}
+}
+
+func TestValidate_Retention(t *testing.T) {
+	cfg := &Config{Raft: RaftConfig{ID: "r"}, Port: 8080,
+		Retention: RetentionConfig{Interval: time.Hour, HalfLife: 24 * time.Hour, Floor: 0.05, Reinforcement: 0.1}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	cfg.Retention.Floor = 1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for floor of 1")
+	}
+
+	cfg.Retention.Floor = 0.05
+	cfg.Retention.HalfLife = -time.Hour
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative half-life")
+	}
+}
+
+func TestParseVoters(t *testing.T) {
+	voters, err := parseVoters([]string{"discord:1234=otter-1", "slack:U01 = otter-1"})
+	if err != nil {
+		t.Fatalf("parseVoters: %v", err)
+	}
+	if voters["discord:1234"] != "otter-1" || voters["slack:U01"] != "otter-1" {
+		t.Errorf("unexpected voters %v", voters)
+	}
+
+	for _, bad := range []string{"discord=otter-1", "discord:1234", ":1234=otter-1", "discord:1234="} {
+		if _, err := parseVoters([]string{bad}); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestParseHoldApprovers(t *testing.T) {
+	approvers, err := parseHoldApprovers([]string{"alice=correct horse", " bob =a=b"})
+	if err != nil {
+		t.Fatalf("parseHoldApprovers: %v", err)
+	}
+	if approvers["alice"] != "correct horse" || approvers["bob"] != "a=b" {
+		t.Errorf("unexpected approvers %v", approvers)
+	}
+
+	for _, bad := range [][]string{{"alice"}, {"=secret"}, {"alice="}, {"alice=x", "alice=y"}} {
+		if _, err := parseHoldApprovers(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestParseContacts(t *testing.T) {
+	contacts, err := parseContacts([]string{"otter-2=discord:1234", " otter-3 = slack:U01"})
+	if err != nil {
+		t.Fatalf("parseContacts: %v", err)
+	}
+	if contacts["otter-2"] != "discord:1234" || contacts["otter-3"] != "slack:U01" {
+		t.Errorf("unexpected contacts %v", contacts)
+	}
+
+	for _, bad := range []string{"otter-2=discord", "otter-2", "=discord:1234", "otter-2=:1234"} {
+		if _, err := parseContacts([]string{bad}); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestValidate_Musing(t *testing.T) {
+	cfg := &Config{Raft: RaftConfig{ID: "r"}, Port: 8080,
+		Musing: MusingConfig{Interval: time.Minute, MemoryWindow: 8, MinMemories: 2, Timeout: time.Minute, PromptLimit: 3}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	cfg.Musing.MinMemories = 9
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for min memories above the window")
+	}
+
+	// Window sizes don't matter while the loop is disabled
+	cfg.Musing.Interval = 0
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate with loop disabled: %v", err)
+	}
+
+	cfg.Musing.PromptLimit = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative prompt limit")
+	}
+}
+
+func TestValidate_Personality(t *testing.T) {
+	cfg := &Config{Raft: RaftConfig{ID: "r"}, Port: 8080,
+		Personality: PersonalityConfig{Interval: time.Hour, DriftRate: 0.02, RuleWeight: 5}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	cfg.Personality.DriftRate = 1.5
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a drift rate above 1")
+	}
+
+	// Drift settings don't matter while drift is disabled
+	cfg.Personality.Interval = 0
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate with drift disabled: %v", err)
+	}
+}
+
+func TestValidate_Chaos(t *testing.T) {
+	cfg := &Config{Raft: RaftConfig{ID: "r"}, Port: 8080,
+		Chaos: ChaosConfig{Enabled: true, DropRate: 0.2, MaxDelay: time.Second}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	cfg.Chaos.ReorderRate = 1.2
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a reorder rate above 1")
+	}
+
+	cfg.Chaos.ReorderRate = 0
+	cfg.Chaos.MinDelay = 2 * time.Second
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a min delay above the max delay")
+	}
+
+	// Fault settings don't matter while chaos mode is disabled
+	cfg.Chaos.Enabled = false
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate with chaos disabled: %v", err)
+	}
+}
+
+func TestValidate_Embedding(t *testing.T) {
+	cfg := &Config{Raft: RaftConfig{ID: "r"}, Port: 8080, LLM: LLMConfig{Provider: "ollama"},
+		Embedding: EmbeddingConfig{Provider: "openai", Endpoint: "https://api.openai.com/v1", APIKey: "sk-test"}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	cfg.Embedding.APIKey = ""
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a separate openai provider without an API key")
+	}
+
+	cfg.Embedding = EmbeddingConfig{Provider: "anthropic", Endpoint: "https://api.anthropic.com", Model: "m"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for an embedding provider without embeddings")
+	}
+
+	// The LLM's provider may only override the model
+	cfg.Embedding = EmbeddingConfig{Provider: "ollama", Model: "nomic-embed-text"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate with the LLM's provider: %v", err)
+	}
+}
+
+func TestValidate_LLMResilience(t *testing.T) {
+	cfg := &Config{Raft: RaftConfig{ID: "r"}, Port: 8080,
+		LLM: LLMConfig{Provider: "ollama", Timeout: time.Minute, MaxRetries: 2, BreakerThreshold: 5}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	cfg.LLM.MaxRetries = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative retries")
+	}
+	cfg.LLM.MaxRetries = 2
+
+	cfg.LLM.MaxConcurrent = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a negative concurrency limit")
+	}
+}
+
+func TestParseConnectors(t *testing.T) {
+	sources, err := parseConnectors([]string{"news=rss:https://example.org/feed.xml", "code=GitHub:otters/raft"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sources) != 2 || sources[0] != (ConnectorConfig{Name: "news", Kind: "rss", Target: "https://example.org/feed.xml"}) || sources[1].Kind != "github" {
+		t.Errorf("unexpected sources: %+v", sources)
+	}
+	if _, err := parseConnectors([]string{"news=rss"}); err == nil {
+		t.Error("expected error for a source without a kind")
+	}
+}
+
+func TestValidate_Connectors(t *testing.T) {
+	cfg := &Config{Raft: RaftConfig{ID: "r"}, Port: 8080, Connectors: ConnectorsConfig{
+		Sources:  []ConnectorConfig{{Name: "news", Kind: "rss", Target: "https://example.org/feed.xml"}, {Name: "code", Kind: "github", Target: "otters/raft"}},
+		Interval: time.Minute, MaxItems: 10,
+	}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	cfg.Connectors.Sources[1].Target = "otters"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a github target without a repo")
+	}
+
+	cfg.Connectors.Sources[1] = ConnectorConfig{Name: "news", Kind: "ical", Target: "https://example.org/cal.ics"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for duplicate connector names")
+	}
+
+	cfg.Connectors.Sources = cfg.Connectors.Sources[:1]
+	cfg.Connectors.Interval = 0
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a zero interval")
+	}
+}
+
+func TestValidate_Tracing(t *testing.T) {
+	cfg := &Config{Raft: RaftConfig{ID: "r"}, Port: 8080, Tracing: TracingConfig{Enabled: true, SampleRate: 0.5}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	cfg.Tracing.SampleRate = 2
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a sample rate above 1")
+	}
+}
+
+func TestValidate_TokenBudget(t *testing.T) {
+	cfg := &Config{Raft: RaftConfig{ID: "r"}, Port: 8080, Usage: UsageConfig{DailyTokenBudget: 100000}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	cfg.Usage.DailyTokenBudget = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a negative token budget")
+	}
+}
+
+func TestValidate_Logging(t *testing.T) {
+	cfg := &Config{Raft: RaftConfig{ID: "r"}, Port: 8080, Logging: LoggingConfig{Level: "debug", Format: "json"}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	cfg.Logging.Level = "verbose"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for an unknown log level")
+	}
+
+	cfg.Logging = LoggingConfig{Level: "warn", Format: "xml"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for an unknown log format")
+	}
+}
+
+func TestValidate_Heartbeat(t *testing.T) {
+	cfg := &Config{Raft: RaftConfig{ID: "r", HeartbeatInterval: 30 * time.Second, HeartbeatGrace: 5 * time.Minute}, Port: 8080}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	cfg.Raft.HeartbeatGrace = 30 * time.Second
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a grace period no longer than the interval")
+	}
+
+	cfg.Raft.HeartbeatInterval = -time.Second
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a negative heartbeat interval")
+	}
+}
+
+func TestValidate_LeaveRules(t *testing.T) {
+	cfg := &Config{Raft: RaftConfig{ID: "r", LeaveRules: "keep"}, Port: 8080}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	cfg.Raft.LeaveRules = "archive"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for an unknown leave-rules mode")
+	}
+}
+
+func TestValidate_VotingPolicy(t *testing.T) {
+	cfg := &Config{Raft: RaftConfig{ID: "r", VotingQuorum: 0.5, VotingMajority: 0.5, VotingSuperMajority: 0.6, VotingTieBreak: "proposer"}, Port: 8080}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	for _, mutate := range []func(*RaftConfig){
+		func(r *RaftConfig) { r.VotingQuorum = 1.5 },
+		func(r *RaftConfig) { r.VotingMajority = -0.1 },
+		func(r *RaftConfig) { r.VotingSuperMajority = 0.4 },
+		func(r *RaftConfig) { r.VotingTieBreak = "coin" },
+		func(r *RaftConfig) { r.SuspensionQuorum = -1 },
+		func(r *RaftConfig) { r.SuspensionWindow = -time.Minute },
+	} {
+		bad := *cfg
+		mutate(&bad.Raft)
+		if err := bad.Validate(); err == nil {
+			t.Errorf("expected error for %+v", bad.Raft)
+		}
+	}
+}
+
+func TestValidate_RateLimiting(t *testing.T) {
+	cfg := &Config{Raft: RaftConfig{ID: "r"}, Port: 8080,
+		API: APIConfig{RateLimitKey: "subject", RateLimitChat: 10, TrustedProxies: []string{"10.0.0.0/8", "192.168.1.1"}}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	cfg.API.RateLimitKey = "cookie"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for an unknown rate limit key")
+	}
+	cfg.API.RateLimitKey = "token"
+	cfg.API.TrustedProxies = []string{"proxy.local"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a trusted proxy that is not an IP or CIDR")
+	}
+	cfg.API.TrustedProxies = nil
+	cfg.API.RateLimitRead = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a negative read limit")
+	}
+}
+
+func TestValidate_RuleEnforcement(t *testing.T) {
+	cfg := &Config{Raft: RaftConfig{ID: "r", RuleEnforcement: "llm"}, Port: 8080}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	cfg.Raft.RuleEnforcement = "strict"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for an unknown enforcement mode")
+	}
+}
+
+func TestValidate_Intent(t *testing.T) {
+	cfg := &Config{Raft: RaftConfig{ID: "r"}, Port: 8080, Intent: IntentConfig{Mode: "embedding", Threshold: 0.8}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	cfg.Intent.Mode = "regex"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for an unknown intent mode")
+	}
+	cfg.Intent = IntentConfig{Mode: "auto", Threshold: 1.5}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a threshold above 1")
+	}
+}
+
+func TestValidate_Retrieval(t *testing.T) {
+	cfg := &Config{Raft: RaftConfig{ID: "r"}, Port: 8080, Retrieval: RetrievalConfig{MinScore: 0.2, DedupeSimilarity: 0.95, Rerank: "llm"}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	cfg.Retrieval.Rerank = "cross-encoder"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a cross-encoder without an endpoint")
+	}
+	cfg.Retrieval.RerankEndpoint = "http://rerank:8080"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate with an endpoint: %v", err)
+	}
+	cfg.Retrieval.MinScore = -0.1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a negative score cutoff")
+	}
+}
+
+func TestValidate_Dedup(t *testing.T) {
+	cfg := &Config{Raft: RaftConfig{ID: "r"}, Port: 8080, Dedup: DedupConfig{Threshold: 0.97, Window: time.Hour, Action: "merge", Bump: 0.05}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	cfg.Dedup.Action = "delete"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for an unknown action")
+	}
+	cfg.Dedup.Action = "skip"
+	cfg.Dedup.Threshold = 1.2
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a threshold above 1")
+	}
+}
+
+func TestValidate_Extraction(t *testing.T) {
+	cfg := &Config{Raft: RaftConfig{ID: "r"}, Port: 8080, Extraction: ExtractionConfig{Enabled: true, MaxFacts: 5, Importance: 0.8}, Retrieval: RetrievalConfig{FactBoost: 0.05}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	cfg.Extraction.Importance = 1.5
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for an importance above 1")
+	}
+	cfg.Extraction.Importance = 0.8
+	cfg.Retrieval.FactBoost = -0.1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a negative fact boost")
+	}
+}
+
+func TestValidate_Scheduler(t *testing.T) {
+	cfg := &Config{Raft: RaftConfig{ID: "r"}, Port: 8080, Scheduler: SchedulerConfig{Interval: time.Minute, DigestTarget: "discord:1234"}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	cfg.Scheduler.DeadlineTarget = "discord"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a target without a channel")
+	}
+	cfg.Scheduler = SchedulerConfig{Interval: -time.Second}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a negative interval")
+	}
+}
+
+func TestParseTimeOfDay(t *testing.T) {
+	if d, err := parseTimeOfDay("09:30"); err != nil || d != 9*time.Hour+30*time.Minute {
+		t.Errorf("parseTimeOfDay(09:30) = %v, %v", d, err)
+	}
+	for _, value := range []string{"9am", "24:00", ""} {
+		if _, err := parseTimeOfDay(value); err == nil {
+			t.Errorf("parseTimeOfDay(%q): expected an error", value)
+		}
+	}
+}
+
+func TestValidate_SQLite(t *testing.T) {
+	cfg := &Config{Raft: RaftConfig{ID: "r"}, Port: 8080, SQLite: SQLiteConfig{BusyTimeout: 5 * time.Second, WAL: true, BatchWindow: 5 * time.Millisecond, BatchSize: 100}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+	cfg.SQLite.BatchSize = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a negative batch size")
+	}
+}
+
+func TestPluginSettings_Redacted(t *testing.T) {
+	settings := PluginSettings{Enabled: true, Token: "bot-token", Config: map[string]string{"api_key": "k-123", "guild_id": "42"}}
+	out := fmt.Sprint(settings)
+	if strings.Contains(out, "bot-token") || strings.Contains(out, "k-123") || !strings.Contains(out, "guild_id=42") {
+		t.Errorf("settings = %s", out)
+	}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("loading", "settings", settings)
+	if strings.Contains(buf.String(), "bot-token") || strings.Contains(buf.String(), "k-123") {
+		t.Errorf("logged %s", buf.String())
+	}
+}
+
+func TestLoad_SecretReferences(t *testing.T) {
+	clearEnv(t)
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "sops")
+	if err := os.WriteFile(binary, []byte("#!/bin/sh\nexec cat \"$2\"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "secrets.yaml")
+	if err := os.WriteFile(file, []byte("llm: sk-sops\ndiscord: bot-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("OTTER_RAFT_ID", "r")
+	t.Setenv("OTTER_SOPS_BINARY", binary)
+	t.Setenv("OTTER_LLM_API_KEY", "secret://sops/"+file+"#llm")
+	t.Setenv("OTTER_PLUGIN_DISCORD_TOKEN", "secret://sops/"+file+"#discord")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.LLM.APIKey != "sk-sops" || cfg.Plugins.Settings["discord"].Token != "bot-token" {
+		t.Errorf("LLM.APIKey = %q, discord token = %q", cfg.LLM.APIKey, cfg.Plugins.Settings["discord"].Token)
+	}
+
+	t.Setenv("OTTER_LLM_API_KEY", "secret://sops/"+file+"#missing")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "OTTER_LLM_API_KEY") {
+		t.Errorf("Load error = %v; want one naming OTTER_LLM_API_KEY", err)
+	}
+}
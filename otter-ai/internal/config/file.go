@@ -0,0 +1,281 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultConfigFile is read from the working directory when
+// OTTER_CONFIG_FILE doesn't name another file. It is optional.
+const DefaultConfigFile = "otter.yaml"
+
+// keyedLists are the variables whose file value may be a mapping, written
+// as a list of key=value entries
+var keyedLists = map[string]bool{
+	"OTTER_PLUGIN_VOTERS":       true,
+	"OTTER_HOLD_APPROVERS":      true,
+	"OTTER_ONBOARDING_CONTACTS": true,
+	"OTTER_CONNECTORS":          true,
+}
+
+// configFile holds the settings of a config file by the environment
+// variable each one stands for. A key path joined with underscores names
+// the variable: llm: {model: x} sets OTTER_LLM_MODEL.
+type configFile struct {
+	path     string
+	values   map[string]string // Variable -> value
+	keys     map[string]string // Variable -> key path in the file, for messages
+	used     map[string]bool   // Variables Load looked up
+	problems []string
+}
+
+var (
+	// loadMu serializes Loads, which read through current
+	loadMu sync.Mutex
+	// current is the file the running Load reads; nil outside Load
+	current *configFile
+)
+
+// readConfigFile reads a YAML config file. An empty path reads
+// DefaultConfigFile if it exists and returns nil if it doesn't.
+func readConfigFile(path string) (*configFile, error) {
+	explicit := path != ""
+	if !explicit {
+		path = DefaultConfigFile
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !explicit {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var root map[string]interface{}
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	file := &configFile{
+		path:   path,
+		values: make(map[string]string),
+		keys:   make(map[string]string),
+		used:   make(map[string]bool),
+	}
+	for key, value := range root {
+		file.flatten("OTTER", "", key, value)
+	}
+	if len(file.problems) > 0 {
+		return nil, file.err()
+	}
+	return file, nil
+}
+
+// flatten records the variables a file key and its value set
+func (f *configFile) flatten(prefix, parentPath, key string, value interface{}) {
+	name := prefix + "_" + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(key))
+	path := key
+	if parentPath != "" {
+		path = parentPath + "." + key
+	}
+
+	var encoded string
+	switch v := value.(type) {
+	case nil:
+		return
+	case map[interface{}]interface{}:
+		f.problems = append(f.problems, fmt.Sprintf("%s: keys must be strings; quote them", path))
+		return
+	case map[string]interface{}:
+		switch {
+		case name == "OTTER_LLM_PROFILES":
+			encoded = encodeLLMProfiles(v)
+		case keyedLists[name]:
+			encoded = strings.Join(encodeEntries(v, "="), ",")
+		default:
+			for child, childValue := range v {
+				f.flatten(name, path, child, childValue)
+			}
+			return
+		}
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			switch item.(type) {
+			case map[string]interface{}, []interface{}:
+				f.problems = append(f.problems, fmt.Sprintf("%s: expected a list of values", path))
+				return
+			}
+			items = append(items, fmt.Sprint(item))
+		}
+		encoded = strings.Join(items, ",")
+	default:
+		encoded = fmt.Sprint(v)
+	}
+
+	if other, ok := f.keys[name]; ok {
+		f.problems = append(f.problems, fmt.Sprintf("%s and %s both set %s", other, path, name))
+		return
+	}
+	f.values[name] = encoded
+	f.keys[name] = path
+}
+
+// encodeEntries writes a mapping as sorted key<sep>value entries
+func encodeEntries(m map[string]interface{}, sep string) []string {
+	entries := make([]string, 0, len(m))
+	for key, value := range m {
+		entries = append(entries, key+sep+fmt.Sprint(value))
+	}
+	sort.Strings(entries)
+	return entries
+}
+
+// encodeLLMProfiles writes llm.profiles in the OTTER_LLM_PROFILES syntax,
+// e.g. {musing: {temperature: 0.9}} as "musing:temperature=0.9"
+func encodeLLMProfiles(profiles map[string]interface{}) string {
+	entries := make([]string, 0, len(profiles))
+	for name, settings := range profiles {
+		switch s := settings.(type) {
+		case map[string]interface{}:
+			entries = append(entries, name+":"+strings.Join(encodeEntries(s, "="), ","))
+		default:
+			entries = append(entries, name+":"+fmt.Sprint(s))
+		}
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ";")
+}
+
+// lookupEnv returns an environment variable or, while Load reads a config
+// file, the file's value for it. The environment wins.
+func lookupEnv(key string) string {
+	if current != nil {
+		current.used[key] = true
+	}
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	if current != nil {
+		return current.values[key]
+	}
+	return ""
+}
+
+// lookupEnvPrefix returns the names of the variables starting with prefix
+// that are set in the environment or, while Load reads one, the config
+// file
+func lookupEnvPrefix(prefix string) []string {
+	set := make(map[string]bool)
+	if current != nil {
+		for name := range current.values {
+			if strings.HasPrefix(name, prefix) {
+				set[name] = true
+			}
+		}
+	}
+	for _, entry := range os.Environ() {
+		if name, value, _ := strings.Cut(entry, "="); strings.HasPrefix(name, prefix) && value != "" {
+			set[name] = true
+		}
+	}
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// invalidValue reports a config file value that doesn't parse as the kind
+// of value its variable holds. Invalid environment values fall back to
+// the default as they always have.
+func invalidValue(key, kind string) {
+	if current == nil || os.Getenv(key) != "" {
+		return
+	}
+	if path, ok := current.keys[key]; ok {
+		current.problems = append(current.problems, fmt.Sprintf("%s: expected %s, got %q", path, kind, current.values[key]))
+	}
+}
+
+// check reports file keys no variable is read for and values that didn't
+// parse
+func (f *configFile) check() error {
+	var unknown []string
+	for name, path := range f.keys {
+		if f.used[name] {
+			continue
+		}
+		problem := fmt.Sprintf("%s: unknown setting %s", path, name)
+		if suggestion := f.closestUsed(name); suggestion != "" {
+			problem += fmt.Sprintf(" (did you mean %s?)", suggestion)
+		}
+		unknown = append(unknown, problem)
+	}
+	sort.Strings(unknown)
+	f.problems = append(f.problems, unknown...)
+	if len(f.problems) > 0 {
+		return f.err()
+	}
+	return nil
+}
+
+func (f *configFile) err() error {
+	return fmt.Errorf("invalid config file %s:\n  %s", f.path, strings.Join(f.problems, "\n  "))
+}
+
+// closestUsed returns the known variable nearest to a misspelled one
+func (f *configFile) closestUsed(name string) string {
+	best, bestDistance := "", 4
+	for known := range f.used {
+		if d := editDistance(name, known); d < bestDistance || (d == bestDistance && known < best) {
+			best, bestDistance = known, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		row := make([]int, len(b)+1)
+		row[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			row[j] = min(previous[j]+1, row[j-1]+1, previous[j-1]+cost)
+		}
+		previous = row
+	}
+	return previous[len(b)]
+}
+
+var variablePattern = regexp.MustCompile(`OTTER_[A-Z0-9_]+`)
+
+// annotate points a validation error at the file keys that set the
+// variables it names
+func (f *configFile) annotate(err error) error {
+	var sources []string
+	for _, name := range variablePattern.FindAllString(err.Error(), -1) {
+		if path, ok := f.keys[name]; ok && os.Getenv(name) == "" {
+			sources = append(sources, fmt.Sprintf("%s is set by %s in %s", name, path, f.path))
+		}
+	}
+	if len(sources) == 0 {
+		return err
+	}
+	return fmt.Errorf("%w (%s)", err, strings.Join(sources, "; "))
+}
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	clearEnv(t)
+	path := filepath.Join(t.TempDir(), "otter.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("OTTER_CONFIG_FILE", path)
+	return path
+}
+
+func TestLoad_ConfigFile(t *testing.T) {
+	path := writeConfigFile(t, `
+raft:
+  id: otter-7
+llm:
+  model: mistral
+  profiles:
+    musing: {temperature: 0.9, max_tokens: 300}
+rate_limit: 50
+rate_limit_window: 2m
+trusted_proxies: [10.0.0.0/8, 127.0.0.1]
+plugin:
+  voters:
+    "discord:1234": otter-7
+log:
+  level: debug
+`)
+	t.Setenv("OTTER_LLM_MODEL", "llama3")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.File != path || cfg.Raft.ID != "otter-7" || cfg.Logging.Level != "debug" {
+		t.Errorf("File = %q, Raft.ID = %q, Logging.Level = %q", cfg.File, cfg.Raft.ID, cfg.Logging.Level)
+	}
+	if cfg.LLM.Model != "llama3" {
+		t.Errorf("LLM.Model = %q; want the environment to override the file", cfg.LLM.Model)
+	}
+	if cfg.API.RateLimit != 50 || cfg.API.RateLimitWindow != 2*time.Minute || len(cfg.API.TrustedProxies) != 2 {
+		t.Errorf("API = %+v", cfg.API)
+	}
+	if profile := cfg.LLM.Profiles["musing"]; profile.MaxTokens != 300 {
+		t.Errorf("musing profile = %+v", profile)
+	}
+	if cfg.Plugins.Voters["discord:1234"] != "otter-7" {
+		t.Errorf("Voters = %v", cfg.Plugins.Voters)
+	}
+}
+
+func TestLoad_ConfigFileErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name, content string
+		want          []string
+	}{
+		{"unknown key", "raft: {id: r}\nllm:\n  modle: x\n", []string{"llm.modle", "did you mean OTTER_LLM_MODEL?"}},
+		{"invalid value", "raft: {id: r}\nrate_limit: lots\n", []string{"rate_limit: expected an integer"}},
+		{"invalid setting", "raft: {id: r}\ndedup: {action: forget}\n", []string{"OTTER_DEDUP_ACTION is set by dedup.action"}},
+		{"not yaml", "raft: [\n", []string{"invalid config file"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			writeConfigFile(t, tt.content)
+			_, err := Load()
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q doesn't mention %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoad_MissingConfigFile(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("OTTER_RAFT_ID", "r")
+	t.Setenv("OTTER_CONFIG_FILE", filepath.Join(t.TempDir(), "missing.yaml"))
+	if _, err := Load(); err == nil {
+		t.Error("expected an error for a named config file that doesn't exist")
+	}
+}
+
+func TestLoad_ExampleConfigFile(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("OTTER_CONFIG_FILE", filepath.Join("..", "..", "otter.example.yaml"))
+	if _, err := Load(); err != nil {
+		t.Errorf("Load: %v", err)
+	}
+}
+
+func TestRestartRequired(t *testing.T) {
+	current := &Config{Raft: RaftConfig{ID: "r"}, API: APIConfig{RateLimit: 100}, Logging: LoggingConfig{Level: "info"}}
+	next := *current
+	next.API.RateLimit = 10
+	next.Logging.Level = "debug"
+	if sections := current.RestartRequired(&next); len(sections) != 0 {
+		t.Errorf("RestartRequired = %v for reloadable changes", sections)
+	}
+
+	next.API.Port = 9090
+	next.LLM.Model = "mistral"
+	if sections := current.RestartRequired(&next); strings.Join(sections, ",") != "LLM,API" {
+		t.Errorf("RestartRequired = %v", sections)
+	}
+}
+
+func TestLoad_PluginSettings(t *testing.T) {
+	writeConfigFile(t, `
+raft: {id: r}
+plugin:
+  health_interval: 1m
+  discord:
+    token: file-token
+    guild_id: 42
+  slack:
+    enabled: false
+`)
+	t.Setenv("OTTER_PLUGIN_DISCORD_TOKEN", "env-token")
+	t.Setenv("OTTER_PLUGIN_TELEGRAM_API_KEY", "tg-secret")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	discord := cfg.Plugins.Settings["discord"]
+	if !discord.Enabled || discord.Token != "env-token" || discord.Config["guild_id"] != "42" {
+		t.Errorf("discord = %+v", discord)
+	}
+	if cfg.Plugins.Settings["slack"].Enabled {
+		t.Error("slack enabled; want it disabled by the file")
+	}
+	if telegram := cfg.Plugins.Settings["telegram"]; telegram.Config["api_key"] != "tg-secret" {
+		t.Errorf("telegram = %+v", telegram)
+	}
+	if cfg.Plugins.HealthInterval != time.Minute {
+		t.Errorf("HealthInterval = %v", cfg.Plugins.HealthInterval)
+	}
+}
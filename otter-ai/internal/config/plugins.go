@@ -0,0 +1,107 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Redacted replaces secret values in logs
+const Redacted = "[REDACTED]"
+
+// pluginPrefix starts the variables holding per-plugin settings,
+// OTTER_PLUGIN_<NAME>_<SETTING>
+const pluginPrefix = "OTTER_PLUGIN_"
+
+// pluginVariables are the variables starting with pluginPrefix that aren't
+// plugin settings
+var pluginVariables = map[string]bool{
+	"OTTER_PLUGIN_HEALTH_INTERVAL": true,
+	"OTTER_PLUGIN_VOTERS":          true,
+	"OTTER_PLUGIN_PROTOCOL":        true, // Set for plugin processes
+}
+
+// secretWords are the words of a setting name that mark its value secret
+var secretWords = map[string]bool{
+	"token": true, "secret": true, "password": true, "passphrase": true,
+	"key": true, "apikey": true, "credentials": true,
+}
+
+// IsSecretSetting reports whether a plugin setting holds a secret, judging
+// by the words of its name: "token", "bot_token" and "api_key" do
+func IsSecretSetting(name string) bool {
+	for _, word := range strings.FieldsFunc(strings.ToLower(name), func(r rune) bool { return r == '_' || r == '-' || r == '.' }) {
+		if secretWords[word] {
+			return true
+		}
+	}
+	return false
+}
+
+// LogValue logs the settings with the token and secret config redacted
+func (s PluginSettings) LogValue() slog.Value {
+	attrs := []slog.Attr{slog.Bool("enabled", s.Enabled)}
+	if s.Token != "" {
+		attrs = append(attrs, slog.String("token", Redacted))
+	}
+	keys := make([]string, 0, len(s.Config))
+	for key := range s.Config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := s.Config[key]
+		if IsSecretSetting(key) {
+			value = Redacted
+		}
+		attrs = append(attrs, slog.String(key, value))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// String formats the settings with secrets redacted, so printing them
+// can't leak a token
+func (s PluginSettings) String() string {
+	return s.LogValue().String()
+}
+
+// loadPluginSettings reads the OTTER_PLUGIN_<NAME>_<SETTING> variables, or
+// plugin.<name>.<setting> in the config file, into settings by lowercased
+// plugin name. ENABLED and TOKEN fill their fields; other settings go into
+// Config under their lowercased names. A plugin with settings is enabled
+// unless ENABLED says otherwise.
+func loadPluginSettings() (map[string]PluginSettings, error) {
+	settings := make(map[string]PluginSettings)
+	for _, name := range lookupEnvPrefix(pluginPrefix) {
+		if pluginVariables[name] {
+			continue
+		}
+		plugin, setting, ok := strings.Cut(strings.TrimPrefix(name, pluginPrefix), "_")
+		if !ok || plugin == "" || setting == "" {
+			return nil, fmt.Errorf("%s must be %s<NAME>_<SETTING>", name, pluginPrefix)
+		}
+		plugin = strings.ToLower(plugin)
+
+		s, exists := settings[plugin]
+		if !exists {
+			s = PluginSettings{Enabled: true, Config: make(map[string]string)}
+		}
+		value := lookupEnv(name)
+		switch setting {
+		case "ENABLED":
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be true or false, got %q", name, value)
+			}
+			s.Enabled = enabled
+		case "TOKEN":
+			s.Token = value
+		default:
+			s.Config[strings.ToLower(setting)] = value
+		}
+		settings[plugin] = s
+	}
+	return settings, nil
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"reflect"
+)
+
+// withoutReloadable returns a copy of c without the settings a running
+// otter applies on reload: the API rate limits, the log level, the prompt
+// directory and the read-only and maintenance modes
+func (c Config) withoutReloadable() Config {
+	c.API.RateLimit = 0
+	c.API.RateLimitWindow = 0
+	c.API.RateLimitChat = 0
+	c.API.RateLimitRead = 0
+	c.API.RateLimitKey = ""
+	c.API.TrustedProxies = nil
+	c.Logging.Level = ""
+	c.LLM.PromptDir = ""
+	c.ReadOnly = false
+	c.MaintenanceMode = false
+	return c
+}
+
+// RestartRequired returns the names of the sections of next, such as
+// "LLM" or "Raft", that differ from c in settings only a restart applies
+func (c *Config) RestartRequired(next *Config) []string {
+	current, updated := reflect.ValueOf(c.withoutReloadable()), reflect.ValueOf(next.withoutReloadable())
+	var sections []string
+	for i := 0; i < current.NumField(); i++ {
+		if !reflect.DeepEqual(current.Field(i).Interface(), updated.Field(i).Interface()) {
+			sections = append(sections, current.Type().Field(i).Name)
+		}
+	}
+	return sections
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"otter-ai/internal/secrets"
+)
+
+// SecretsConfig locates the backends secret:// references are resolved
+// from
+type SecretsConfig struct {
+	VaultAddr      string // Defaults to VAULT_ADDR
+	VaultToken     string // Defaults to VAULT_TOKEN
+	VaultNamespace string // Defaults to VAULT_NAMESPACE
+	AWSRegion      string // Defaults to AWS_REGION; credentials come from the AWS_* variables
+	AWSEndpoint    string // Overrides the Secrets Manager endpoint
+	SOPSBinary     string
+	Timeout        time.Duration // Per secret fetched
+}
+
+// secretFields returns the settings that may be secret:// references, by
+// the variable each is read from
+func (c *Config) secretFields() map[string]*string {
+	return map[string]*string{
+		"OTTER_LLM_API_KEY":              &c.LLM.APIKey,
+		"OTTER_EMBEDDING_API_KEY":        &c.Embedding.APIKey,
+		"OTTER_HOST_PASSPHRASE":          &c.API.Passphrase,
+		"OTTER_JWT_SECRET":               &c.API.JWTSecret,
+		"OTTER_NATS_URL":                 &c.Plugins.NATS.URL,
+		"OTTER_LANCEDB_API_KEY":          &c.LanceDB.APIKey,
+		"OTTER_QDRANT_API_KEY":           &c.Qdrant.APIKey,
+		"OTTER_RETRIEVAL_RERANK_API_KEY": &c.Retrieval.RerankAPIKey,
+		"OTTER_CONNECTOR_GITHUB_TOKEN":   &c.Connectors.GitHubToken,
+		"OTTER_HOOK_TOKEN":               &c.Hooks.Token,
+	}
+}
+
+// resolveSecrets replaces secret:// references in the sensitive settings,
+// hold approver passphrases and plugin settings with the secrets they name. Nothing is fetched
+// when no setting is a reference.
+func (c *Config) resolveSecrets() error {
+	var resolver *secrets.Resolver
+	resolve := func(name string, value *string) error {
+		if !secrets.IsReference(*value) {
+			return nil
+		}
+		if resolver == nil {
+			resolver = secrets.NewResolver(secrets.Config{
+				VaultAddr:      c.Secrets.VaultAddr,
+				VaultToken:     c.Secrets.VaultToken,
+				VaultNamespace: c.Secrets.VaultNamespace,
+				AWSRegion:      c.Secrets.AWSRegion,
+				AWSEndpoint:    c.Secrets.AWSEndpoint,
+				SOPSBinary:     c.Secrets.SOPSBinary,
+				Timeout:        c.Secrets.Timeout,
+			})
+		}
+		secret, err := resolver.Resolve(context.Background(), *value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+		*value = secret
+		return nil
+	}
+
+	fields := c.secretFields()
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := resolve(name, fields[name]); err != nil {
+			return err
+		}
+	}
+
+	for name, passphrase := range c.API.HoldApprovers {
+		if err := resolve("OTTER_HOLD_APPROVERS "+name, &passphrase); err != nil {
+			return err
+		}
+		c.API.HoldApprovers[name] = passphrase
+	}
+
+	for plugin, settings := range c.Plugins.Settings {
+		prefix := pluginPrefix + strings.ToUpper(plugin) + "_"
+		if err := resolve(prefix+"TOKEN", &settings.Token); err != nil {
+			return err
+		}
+		for key, value := range settings.Config {
+			if err := resolve(prefix+strings.ToUpper(key), &value); err != nil {
+				return err
+			}
+			settings.Config[key] = value
+		}
+		c.Plugins.Settings[plugin] = settings
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+// Package connectors pulls items from external sources, such as news feeds,
+// calendars and GitHub repositories, so the otter stays aware of what its
+// community is doing outside conversations.
+package connectors
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"otter-ai/internal/config"
+)
+
+// Connector kinds
+const (
+	KindRSS    = "rss"    // RSS 2.0 or Atom feed
+	KindICal   = "ical"   // iCalendar feed
+	KindGitHub = "github" // Public events of a GitHub repository
+)
+
+const (
+	// MaxResponseBytes caps how much of a source's response is read
+	MaxResponseBytes = 5 << 20
+	// MaxItemContent caps the content kept from one item
+	MaxItemContent = 4000
+)
+
+// Item is one entry pulled from a source
+type Item struct {
+	ID        string // Stable within the source; used to skip items already ingested
+	Title     string
+	Content   string
+	URL       string
+	Author    string
+	Published time.Time // Zero when the source gives no date
+}
+
+// Connector pulls the current items of one external source
+type Connector interface {
+	// Name identifies the configured source
+	Name() string
+
+	// Kind returns the connector kind (rss, ical or github)
+	Kind() string
+
+	// Fetch returns the source's current items, newest first where the
+	// source orders them
+	Fetch(ctx context.Context) ([]Item, error)
+}
+
+// New creates the connector for a configured source
+func New(cfg config.ConnectorConfig, githubToken string, client *http.Client) (Connector, error) {
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	switch cfg.Kind {
+	case KindRSS:
+		return &feedConnector{name: cfg.Name, url: cfg.Target, client: client}, nil
+	case KindICal:
+		return &icalConnector{name: cfg.Name, url: cfg.Target, client: client}, nil
+	case KindGitHub:
+		owner, repo, ok := strings.Cut(cfg.Target, "/")
+		if !ok || owner == "" || repo == "" {
+			return nil, fmt.Errorf("github connector %s: expected owner/repo, got %q", cfg.Name, cfg.Target)
+		}
+		return &githubConnector{name: cfg.Name, repo: cfg.Target, token: githubToken, api: GitHubAPI, client: client}, nil
+	default:
+		return nil, fmt.Errorf("unknown connector kind: %s", cfg.Kind)
+	}
+}
+
+// fetch GETs a URL and returns the body of a successful response
+func fetch(ctx context.Context, client *http.Client, url string, header http.Header) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s returned status %d", url, resp.StatusCode)
+	}
+	return body, nil
+}
+
+var (
+	htmlTag    = regexp.MustCompile(`<[^>]*>`)
+	whitespace = regexp.MustCompile(`\s+`)
+)
+
+// plainText strips markup from item content and bounds its length
+func plainText(s string) string {
+	s = html.UnescapeString(htmlTag.ReplaceAllString(s, " "))
+	s = strings.TrimSpace(whitespace.ReplaceAllString(s, " "))
+	if len(s) > MaxItemContent {
+		s = strings.ToValidUTF8(s[:MaxItemContent], "") + "…"
+	}
+	return s
+}
@@ -0,0 +1,178 @@
+package connectors
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"otter-ai/internal/config"
+)
+
+func TestNew(t *testing.T) {
+	for _, kind := range []string{KindRSS, KindICal} {
+		c, err := New(config.ConnectorConfig{Name: "src", Kind: kind, Target: "https://example.org/feed"}, "", nil)
+		if err != nil || c.Kind() != kind || c.Name() != "src" {
+			t.Errorf("New(%s) = %v, %v", kind, c, err)
+		}
+	}
+	if _, err := New(config.ConnectorConfig{Name: "code", Kind: KindGitHub, Target: "otters"}, "", nil); err == nil {
+		t.Error("expected error for a github target without owner/repo")
+	}
+	if _, err := New(config.ConnectorConfig{Name: "x", Kind: "mastodon", Target: "https://example.org"}, "", nil); err == nil {
+		t.Error("expected error for an unknown kind")
+	}
+}
+
+func TestParseFeed_RSS(t *testing.T) {
+	items, err := parseFeed([]byte(`<?xml version="1.0"?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<channel><title>Otter News</title>
+<item>
+  <guid>https://example.org/1</guid>
+  <title>Otters return</title>
+  <link>https://example.org/1</link>
+  <description>&lt;p&gt;A family of &lt;b&gt;otters&lt;/b&gt; was seen.&lt;/p&gt;</description>
+  <dc:creator>River Desk</dc:creator>
+  <pubDate>Thu, 01 Oct 2026 12:00:00 +0000</pubDate>
+</item>
+<item><title>No guid</title><link>https://example.org/2</link></item>
+</channel></rss>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2", len(items))
+	}
+	first := items[0]
+	if first.ID != "https://example.org/1" || first.Content != "A family of otters was seen." || first.Author != "River Desk" {
+		t.Errorf("unexpected item: %+v", first)
+	}
+	if !first.Published.Equal(time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("published = %v", first.Published)
+	}
+	if items[1].ID != "https://example.org/2" {
+		t.Errorf("an item without a guid should be identified by its link, got %q", items[1].ID)
+	}
+}
+
+func TestParseFeed_Atom(t *testing.T) {
+	items, err := parseFeed([]byte(`<feed xmlns="http://www.w3.org/2005/Atom">
+<entry>
+  <id>tag:example.org,2026:1</id>
+  <title>Kelp update</title>
+  <link rel="alternate" href="https://example.org/kelp"/>
+  <summary>The forest is recovering.</summary>
+  <author><name>Sea Desk</name></author>
+  <updated>2026-10-02T08:00:00Z</updated>
+</entry>
+</feed>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].URL != "https://example.org/kelp" || items[0].Author != "Sea Desk" || items[0].Published.IsZero() {
+		t.Errorf("unexpected items: %+v", items)
+	}
+
+	if _, err := parseFeed([]byte(`<html></html>`)); err == nil {
+		t.Error("expected error for a document that is not a feed")
+	}
+}
+
+const testCalendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:meetup-1\r\n" +
+	"SUMMARY:Otter meetup\r\n" +
+	"DTSTART:20261020T180000Z\r\n" +
+	"LOCATION:Harbour\\, pier 3\r\n" +
+	"DESCRIPTION:Bring snacks\\nand friends. This description is folded acro\r\n" +
+	" ss two lines.\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:cleanup\r\n" +
+	"RECURRENCE-ID:20261018T090000Z\r\n" +
+	"SUMMARY:Beach cleanup\r\n" +
+	"DTSTART;TZID=Europe/London:20261018T100000\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:old\r\n" +
+	"SUMMARY:Last year's meetup\r\n" +
+	"DTSTART;VALUE=DATE:20251020\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestICalConnector(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testCalendar))
+	}))
+	defer srv.Close()
+
+	c := &icalConnector{name: "events", url: srv.URL, client: srv.Client(),
+		now: func() time.Time { return time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC) }}
+	items, err := c.Fetch(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Last year's meetup is outside the window; the rest come soonest first
+	if len(items) != 2 || items[0].ID != "cleanup@20261018T090000Z" || items[1].ID != "meetup-1" {
+		t.Fatalf("unexpected items: %+v", items)
+	}
+	meetup := items[1]
+	if meetup.Title != "Otter meetup" || !strings.Contains(meetup.Content, "at Harbour, pier 3") ||
+		!strings.Contains(meetup.Content, "folded across two lines") {
+		t.Errorf("unexpected event: %+v", meetup)
+	}
+	if items[0].Published.UTC().Hour() != 9 {
+		t.Errorf("TZID start = %v, want 09:00 UTC", items[0].Published.UTC())
+	}
+
+	if _, err := parseICal([]byte("not a calendar")); err == nil {
+		t.Error("expected error for a document that is not a calendar")
+	}
+}
+
+func TestGitHubConnector(t *testing.T) {
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/otters/raft/events" {
+			http.NotFound(w, r)
+			return
+		}
+		auth = r.Header.Get("Authorization")
+		w.Write([]byte(`[
+			{"id": "3", "type": "IssuesEvent", "created_at": "2026-10-14T10:00:00Z", "actor": {"login": "ana"},
+			 "payload": {"action": "opened", "issue": {"number": 7, "title": "Vote on tides", "body": "Proposal body", "html_url": "https://github.com/otters/raft/issues/7"}}},
+			{"id": "2", "type": "WatchEvent", "created_at": "2026-10-14T09:00:00Z", "actor": {"login": "bo"}, "payload": {"action": "started"}},
+			{"id": "1", "type": "PushEvent", "created_at": "2026-10-14T08:00:00Z", "actor": {"login": "cy"},
+			 "payload": {"ref": "refs/heads/main", "size": 2, "commits": [{"message": "Fix drift\n\nDetails"}, {"message": "Add tests"}]}}
+		]`))
+	}))
+	defer srv.Close()
+
+	c := &githubConnector{name: "code", repo: "otters/raft", token: "gh-token", api: srv.URL, client: srv.Client()}
+	items, err := c.Fetch(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if auth != "Bearer gh-token" {
+		t.Errorf("Authorization = %q", auth)
+	}
+	// Stars are skipped
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2: %+v", len(items), items)
+	}
+	if items[0].Title != "ana opened issue #7 in otters/raft: Vote on tides" || items[0].URL != "https://github.com/otters/raft/issues/7" {
+		t.Errorf("unexpected issue item: %+v", items[0])
+	}
+	if items[1].Title != "cy pushed 2 commit(s) to main in otters/raft" || items[1].Content != "Fix drift; Add tests" {
+		t.Errorf("unexpected push item: %+v", items[1])
+	}
+
+	c.repo = "otters/missing"
+	if _, err := c.Fetch(context.Background()); err == nil {
+		t.Error("expected error for a missing repository")
+	}
+}
@@ -0,0 +1,140 @@
+package connectors
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// feedConnector reads an RSS 2.0 or Atom feed
+type feedConnector struct {
+	name   string
+	url    string
+	client *http.Client
+}
+
+func (c *feedConnector) Name() string { return c.name }
+func (c *feedConnector) Kind() string { return KindRSS }
+
+func (c *feedConnector) Fetch(ctx context.Context) ([]Item, error) {
+	body, err := fetch(ctx, c.client, c.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return parseFeed(body)
+}
+
+type rssFeed struct {
+	Items []struct {
+		GUID        string `xml:"guid"`
+		Title       string `xml:"title"`
+		Link        string `xml:"link"`
+		Description string `xml:"description"`
+		Content     string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+		Author      string `xml:"author"`
+		Creator     string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+		PubDate     string `xml:"pubDate"`
+	} `xml:"channel>item"`
+}
+
+type atomFeed struct {
+	Entries []struct {
+		ID    string `xml:"id"`
+		Title string `xml:"title"`
+		Links []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Summary   string `xml:"summary"`
+		Content   string `xml:"content"`
+		Author    string `xml:"author>name"`
+		Published string `xml:"published"`
+		Updated   string `xml:"updated"`
+	} `xml:"entry"`
+}
+
+// parseFeed parses an RSS 2.0 or Atom document
+func parseFeed(data []byte) ([]Item, error) {
+	var root struct{ XMLName xml.Name }
+	if err := xml.NewDecoder(bytes.NewReader(data)).Decode(&root); err != nil {
+		return nil, fmt.Errorf("invalid feed: %w", err)
+	}
+
+	var items []Item
+	switch root.XMLName.Local {
+	case "rss":
+		var feed rssFeed
+		if err := xml.Unmarshal(data, &feed); err != nil {
+			return nil, fmt.Errorf("invalid RSS feed: %w", err)
+		}
+		for _, entry := range feed.Items {
+			item := Item{
+				ID:        firstNonEmpty(entry.GUID, entry.Link, entry.Title),
+				Title:     plainText(entry.Title),
+				Content:   plainText(firstNonEmpty(entry.Content, entry.Description)),
+				URL:       strings.TrimSpace(entry.Link),
+				Author:    firstNonEmpty(entry.Creator, entry.Author),
+				Published: parseFeedTime(entry.PubDate),
+			}
+			items = append(items, item)
+		}
+	case "feed":
+		var feed atomFeed
+		if err := xml.Unmarshal(data, &feed); err != nil {
+			return nil, fmt.Errorf("invalid Atom feed: %w", err)
+		}
+		for _, entry := range feed.Entries {
+			item := Item{
+				ID:        firstNonEmpty(entry.ID, entry.Title),
+				Title:     plainText(entry.Title),
+				Content:   plainText(firstNonEmpty(entry.Content, entry.Summary)),
+				Author:    strings.TrimSpace(entry.Author),
+				Published: parseFeedTime(firstNonEmpty(entry.Published, entry.Updated)),
+			}
+			for _, link := range entry.Links {
+				if link.Rel == "" || link.Rel == "alternate" {
+					item.URL = link.Href
+					break
+				}
+			}
+			items = append(items, item)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported feed format: <%s>", root.XMLName.Local)
+	}
+
+	valid := items[:0]
+	for _, item := range items {
+		if item.ID != "" {
+			valid = append(valid, item)
+		}
+	}
+	return valid, nil
+}
+
+// feedTimeLayouts are the date formats seen in RSS and Atom feeds
+var feedTimeLayouts = []string{time.RFC1123Z, time.RFC1123, time.RFC3339, "Mon, 2 Jan 2006 15:04:05 -0700", "Mon, 2 Jan 2006 15:04:05 MST"}
+
+// parseFeedTime parses a feed date, returning zero when it can't
+func parseFeedTime(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range feedTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
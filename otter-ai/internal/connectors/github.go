@@ -0,0 +1,149 @@
+package connectors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// GitHubAPI is the GitHub REST API base URL
+const GitHubAPI = "https://api.github.com"
+
+// githubConnector reads the public events of a repository
+type githubConnector struct {
+	name   string
+	repo   string // owner/repo
+	token  string // Optional; raises the rate limit and allows private repositories
+	api    string
+	client *http.Client
+}
+
+func (c *githubConnector) Name() string { return c.name }
+func (c *githubConnector) Kind() string { return KindGitHub }
+
+// githubEvent is the part of a repository event the connector reads
+type githubEvent struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Actor     struct {
+		Login string `json:"login"`
+	} `json:"actor"`
+	Payload struct {
+		Action  string `json:"action"`
+		Ref     string `json:"ref"`
+		RefType string `json:"ref_type"`
+		Size    int    `json:"size"`
+		Commits []struct {
+			Message string `json:"message"`
+		} `json:"commits"`
+		Issue       *githubIssue `json:"issue"`
+		PullRequest *githubIssue `json:"pull_request"`
+		Comment     *struct {
+			Body    string `json:"body"`
+			HTMLURL string `json:"html_url"`
+		} `json:"comment"`
+		Release *struct {
+			Name    string `json:"name"`
+			TagName string `json:"tag_name"`
+			Body    string `json:"body"`
+			HTMLURL string `json:"html_url"`
+		} `json:"release"`
+	} `json:"payload"`
+}
+
+type githubIssue struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+}
+
+// Fetch returns the repository's recent events, newest first
+func (c *githubConnector) Fetch(ctx context.Context) ([]Item, error) {
+	header := http.Header{"Accept": {"application/vnd.github+json"}}
+	if c.token != "" {
+		header.Set("Authorization", "Bearer "+c.token)
+	}
+	body, err := fetch(ctx, c.client, fmt.Sprintf("%s/repos/%s/events", c.api, c.repo), header)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []githubEvent
+	if err := json.Unmarshal(body, &events); err != nil {
+		return nil, fmt.Errorf("invalid GitHub events: %w", err)
+	}
+
+	items := make([]Item, 0, len(events))
+	for _, event := range events {
+		if item, ok := c.item(event); ok {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// item describes an event, skipping kinds that say little about the
+// community's activity such as stars and forks
+func (c *githubConnector) item(event githubEvent) (Item, bool) {
+	actor := event.Actor.Login
+	payload := event.Payload
+	item := Item{ID: event.ID, Author: actor, Published: event.CreatedAt, URL: "https://github.com/" + c.repo}
+
+	switch event.Type {
+	case "PushEvent":
+		branch := strings.TrimPrefix(payload.Ref, "refs/heads/")
+		count := payload.Size
+		if count == 0 {
+			count = len(payload.Commits)
+		}
+		item.Title = fmt.Sprintf("%s pushed %d commit(s) to %s in %s", actor, count, branch, c.repo)
+		var messages []string
+		for _, commit := range payload.Commits {
+			subject, _, _ := strings.Cut(commit.Message, "\n")
+			messages = append(messages, subject)
+		}
+		item.Content = strings.Join(messages, "; ")
+	case "PullRequestEvent", "IssuesEvent":
+		issue, noun := payload.Issue, "issue"
+		if event.Type == "PullRequestEvent" {
+			issue, noun = payload.PullRequest, "pull request"
+		}
+		if issue == nil {
+			return Item{}, false
+		}
+		item.Title = fmt.Sprintf("%s %s %s #%d in %s: %s", actor, payload.Action, noun, issue.Number, c.repo, issue.Title)
+		item.Content = issue.Body
+		item.URL = issue.HTMLURL
+	case "IssueCommentEvent":
+		if payload.Issue == nil || payload.Comment == nil {
+			return Item{}, false
+		}
+		item.Title = fmt.Sprintf("%s commented on #%d in %s: %s", actor, payload.Issue.Number, c.repo, payload.Issue.Title)
+		item.Content = payload.Comment.Body
+		item.URL = payload.Comment.HTMLURL
+	case "ReleaseEvent":
+		if payload.Release == nil {
+			return Item{}, false
+		}
+		item.Title = fmt.Sprintf("%s %s release %s of %s", actor, payload.Action, firstNonEmpty(payload.Release.Name, payload.Release.TagName), c.repo)
+		item.Content = payload.Release.Body
+		item.URL = payload.Release.HTMLURL
+	case "CreateEvent", "DeleteEvent":
+		verb := "created"
+		if event.Type == "DeleteEvent" {
+			verb = "deleted"
+		}
+		item.Title = fmt.Sprintf("%s %s %s %s in %s", actor, verb, payload.RefType, payload.Ref, c.repo)
+	default:
+		return Item{}, false
+	}
+
+	item.Title = plainText(item.Title)
+	item.Content = plainText(item.Content)
+	return item, item.ID != ""
+}
@@ -0,0 +1,198 @@
+package connectors
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ICalPastWindow is how far back events are still ingested; older events
+// in a calendar feed are history the otter doesn't need
+const ICalPastWindow = 7 * 24 * time.Hour
+
+// icalConnector reads the events of an iCalendar feed
+type icalConnector struct {
+	name   string
+	url    string
+	client *http.Client
+	now    func() time.Time // Overridden in tests
+}
+
+func (c *icalConnector) Name() string { return c.name }
+func (c *icalConnector) Kind() string { return KindICal }
+
+// Fetch returns recent and upcoming events, soonest first
+func (c *icalConnector) Fetch(ctx context.Context) ([]Item, error) {
+	body, err := fetch(ctx, c.client, c.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	events, err := parseICal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if c.now != nil {
+		now = c.now()
+	}
+	cutoff := now.Add(-ICalPastWindow)
+	items := events[:0]
+	for _, event := range events {
+		if event.Published.IsZero() || event.Published.After(cutoff) {
+			items = append(items, event)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Published.Before(items[j].Published) })
+	return items, nil
+}
+
+// parseICal returns the VEVENTs of an iCalendar document as items whose
+// Published time is the event's start
+func parseICal(data []byte) ([]Item, error) {
+	lines, err := unfoldICal(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 || !strings.EqualFold(lines[0], "BEGIN:VCALENDAR") {
+		return nil, fmt.Errorf("invalid calendar: missing BEGIN:VCALENDAR")
+	}
+
+	var items []Item
+	var event map[string]icalProperty
+	for _, line := range lines {
+		name, prop, ok := parseICalLine(line)
+		if !ok {
+			continue
+		}
+		switch {
+		case name == "BEGIN" && strings.EqualFold(prop.value, "VEVENT"):
+			event = make(map[string]icalProperty)
+		case name == "END" && strings.EqualFold(prop.value, "VEVENT"):
+			if item, ok := icalItem(event); ok {
+				items = append(items, item)
+			}
+			event = nil
+		case event != nil:
+			if _, seen := event[name]; !seen {
+				event[name] = prop
+			}
+		}
+	}
+	return items, nil
+}
+
+// icalProperty is one content line's parameters and value
+type icalProperty struct {
+	params map[string]string
+	value  string
+}
+
+// unfoldICal splits a document into content lines, joining continuation
+// lines that start with a space or tab
+func unfoldICal(data []byte) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), MaxResponseBytes)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("invalid calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// parseICalLine splits NAME;PARAM=VALUE:value
+func parseICalLine(line string) (string, icalProperty, bool) {
+	head, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return "", icalProperty{}, false
+	}
+	parts := strings.Split(head, ";")
+	prop := icalProperty{params: make(map[string]string), value: value}
+	for _, param := range parts[1:] {
+		if key, val, ok := strings.Cut(param, "="); ok {
+			prop.params[strings.ToUpper(key)] = strings.Trim(val, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), prop, true
+}
+
+// icalItem converts a parsed VEVENT
+func icalItem(event map[string]icalProperty) (Item, bool) {
+	uid := event["UID"].value
+	if uid == "" {
+		return Item{}, false
+	}
+	// Occurrences of a recurring event share a UID
+	if recurrence := event["RECURRENCE-ID"].value; recurrence != "" {
+		uid += "@" + recurrence
+	}
+
+	start := parseICalTime(event["DTSTART"])
+	var details []string
+	if !start.IsZero() {
+		details = append(details, "Starts "+start.Format("Mon 2 Jan 2006 15:04 MST"))
+	}
+	if location := icalText(event["LOCATION"].value); location != "" {
+		details = append(details, "at "+location)
+	}
+	content := strings.Join(details, " ")
+	if description := icalText(event["DESCRIPTION"].value); description != "" {
+		if content != "" {
+			content += ". "
+		}
+		content += description
+	}
+
+	return Item{
+		ID:        uid,
+		Title:     plainText(icalText(event["SUMMARY"].value)),
+		Content:   plainText(content),
+		URL:       event["URL"].value,
+		Author:    strings.TrimPrefix(strings.ToLower(event["ORGANIZER"].value), "mailto:"),
+		Published: start,
+	}, true
+}
+
+// parseICalTime parses a DATE or DATE-TIME value in UTC, a TZID zone or
+// floating local time
+func parseICalTime(prop icalProperty) time.Time {
+	value := prop.value
+	if value == "" {
+		return time.Time{}
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, _ := time.Parse("20060102T150405Z", value)
+		return t
+	}
+	location := time.Local
+	if tzid := prop.params["TZID"]; tzid != "" {
+		if zone, err := time.LoadLocation(tzid); err == nil {
+			location = zone
+		}
+	}
+	if t, err := time.ParseInLocation("20060102T150405", value, location); err == nil {
+		return t
+	}
+	t, _ := time.ParseInLocation("20060102", value, location)
+	return t
+}
+
+// icalText unescapes a TEXT value
+func icalText(s string) string {
+	return strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(s)
+}
@@ -0,0 +1,69 @@
+// Package errs defines the kinds of failure the agent, governance and
+// memory packages share, so the API can answer each error with the right
+// status code without knowing every package's errors. A package's own
+// errors keep their messages and identities, and are also of a kind:
+//
+//	var ErrRuleNotFound = errs.New(errs.ErrNotFound, "rule not found")
+//
+// matches both errors.Is(err, ErrRuleNotFound) and
+// errors.Is(err, errs.ErrNotFound).
+package errs
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kinds of failure
+var (
+	// ErrNotFound is for a raft, proposal, rule, memory or other record
+	// that doesn't exist
+	ErrNotFound = errors.New("not found")
+	// ErrUnauthorized is for a caller, voter or peer not allowed to do
+	// what it asked, such as a bad signature or a non-member
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrQuorum is for a request the raft's vote doesn't allow: the
+	// proposal is closed or expired, or the voter isn't eligible
+	ErrQuorum = errors.New("no quorum")
+	// ErrLLMUnavailable is for work that needs an LLM provider that is
+	// down, overloaded or not configured
+	ErrLLMUnavailable = errors.New("llm unavailable")
+	// ErrInvalid is for a malformed or incomplete request
+	ErrInvalid = errors.New("invalid request")
+	// ErrConflict is for a request the record's state doesn't allow, such
+	// as changing an archived raft or a held memory
+	ErrConflict = errors.New("conflict")
+	// ErrUnavailable is for a feature that is switched off or a service
+	// that is shutting down
+	ErrUnavailable = errors.New("unavailable")
+)
+
+// kindError is an error that is also of a kind
+type kindError struct {
+	err  error
+	kind error
+}
+
+func (e *kindError) Error() string { return e.err.Error() }
+
+func (e *kindError) Unwrap() []error { return []error{e.err, e.kind} }
+
+// New returns an error with message text that is of kind
+func New(kind error, text string) error {
+	return &kindError{err: errors.New(text), kind: kind}
+}
+
+// Errorf formats an error of kind as fmt.Errorf does, so %w still wraps
+func Errorf(kind error, format string, args ...interface{}) error {
+	return &kindError{err: fmt.Errorf(format, args...), kind: kind}
+}
+
+// Kind returns the kind of err, or nil when it has none
+func Kind(err error) error {
+	for _, kind := range []error{ErrNotFound, ErrUnauthorized, ErrQuorum, ErrLLMUnavailable, ErrInvalid, ErrConflict, ErrUnavailable} {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestKind(t *testing.T) {
+	errRuleNotFound := New(ErrNotFound, "rule not found")
+	wrapped := fmt.Errorf("load rule r1: %w", errRuleNotFound)
+
+	if !errors.Is(wrapped, errRuleNotFound) || !errors.Is(wrapped, ErrNotFound) {
+		t.Error("a wrapped error should match both itself and its kind")
+	}
+	if errors.Is(wrapped, ErrConflict) {
+		t.Error("an error should not match another kind")
+	}
+	if wrapped.Error() != "load rule r1: rule not found" {
+		t.Errorf("message = %q, want the kind left out", wrapped.Error())
+	}
+	if Kind(wrapped) != ErrNotFound {
+		t.Errorf("Kind = %v", Kind(wrapped))
+	}
+	if Kind(errors.New("boom")) != nil {
+		t.Error("an untyped error should have no kind")
+	}
+
+	cause := errors.New("expired")
+	err := Errorf(ErrQuorum, "vote refused: %w", cause)
+	if !errors.Is(err, cause) || Kind(err) != ErrQuorum || err.Error() != "vote refused: expired" {
+		t.Errorf("Errorf = %v, kind %v", err, Kind(err))
+	}
+}
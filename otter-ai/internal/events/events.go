@@ -0,0 +1,153 @@
+package events
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Event types published by the agent
+const (
+	ProposalCreated = "proposal.created"
+	ProposalClosed  = "proposal.closed"
+	VoteCast        = "vote.cast"
+	RuleAdopted     = "rule.adopted"
+	RuleSuspended   = "rule.suspended"
+	RuleReinstated  = "rule.reinstated"
+	MemberJoined    = "member.joined"
+	MemberRevoked   = "member.revoked"
+	MemberLeft      = "member.left"
+	RaftArchived    = "raft.archived"
+	MemoryCreated   = "memory.created"
+	PluginMessage   = "plugin.message"
+	PluginsChanged  = "plugins.changed"
+
+	// A negotiated compromise is waiting for a person to approve it
+	NegotiationAwaitingApproval = "negotiation.awaiting_approval"
+	// The otter was switched into or out of read-only or maintenance mode
+	ModeChanged = "mode.changed"
+)
+
+// DefaultSubscriberBuffer is the number of events queued per subscriber
+// before further events are dropped for that subscriber.
+const DefaultSubscriberBuffer = 64
+
+// Event is a single notification pushed to subscribers
+type Event struct {
+	Type      string      `json:"type"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data,omitempty"`
+}
+
+// Bus fans events out to subscribers. Publishing never blocks: a subscriber
+// that falls behind misses events rather than stalling governance or memory.
+// A nil *Bus is valid and discards everything.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[*Subscription]struct{}
+}
+
+// Subscription receives events from a Bus until it is closed
+type Subscription struct {
+	C <-chan Event
+
+	bus     *Bus
+	ch      chan Event
+	types   map[string]bool
+	dropped atomic.Int64
+	once    sync.Once
+}
+
+// NewBus creates an event bus with no subscribers
+func NewBus() *Bus {
+	return &Bus{
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish sends an event to every subscriber interested in its type
+func (b *Bus) Publish(eventType string, data interface{}) {
+	if b == nil {
+		return
+	}
+
+	event := Event{
+		Type:      eventType,
+		Timestamp: time.Now(),
+		Data:      data,
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subscribers {
+		if len(sub.types) > 0 && !sub.types[eventType] {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
+}
+
+// Subscribe registers a subscriber. When types is non-empty only those
+// event types are delivered.
+func (b *Bus) Subscribe(buffer int, types ...string) *Subscription {
+	if buffer <= 0 {
+		buffer = DefaultSubscriberBuffer
+	}
+
+	ch := make(chan Event, buffer)
+	sub := &Subscription{
+		C:   ch,
+		bus: b,
+		ch:  ch,
+	}
+	if len(types) > 0 {
+		sub.types = make(map[string]bool, len(types))
+		for _, t := range types {
+			sub.types[t] = true
+		}
+	}
+
+	if b == nil {
+		close(ch)
+		return sub
+	}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+	return sub
+}
+
+// SubscriberCount returns the number of active subscribers
+func (b *Bus) SubscriberCount() int {
+	if b == nil {
+		return 0
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subscribers)
+}
+
+// Close unregisters the subscription and closes its channel
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		if s.bus == nil {
+			return
+		}
+		s.bus.mu.Lock()
+		delete(s.bus.subscribers, s)
+		s.bus.mu.Unlock()
+		close(s.ch)
+	})
+}
+
+// Dropped returns how many events were discarded because the subscriber
+// was not keeping up
+func (s *Subscription) Dropped() int64 {
+	return s.dropped.Load()
+}
@@ -0,0 +1,95 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func receive(t *testing.T, sub *Subscription) Event {
+	t.Helper()
+	select {
+	case event := <-sub.C:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+		return Event{}
+	}
+}
+
+func TestBus_PublishSubscribe(t *testing.T) {
+	bus := NewBus()
+	sub := bus.Subscribe(0)
+	defer sub.Close()
+
+	bus.Publish(RuleAdopted, "r1")
+
+	event := receive(t, sub)
+	if event.Type != RuleAdopted || event.Data != "r1" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+	if event.Timestamp.IsZero() {
+		t.Error("event should be timestamped")
+	}
+}
+
+func TestBus_TypeFilter(t *testing.T) {
+	bus := NewBus()
+	sub := bus.Subscribe(0, VoteCast)
+	defer sub.Close()
+
+	bus.Publish(MemoryCreated, nil)
+	bus.Publish(VoteCast, nil)
+
+	if event := receive(t, sub); event.Type != VoteCast {
+		t.Errorf("expected only %s, got %s", VoteCast, event.Type)
+	}
+}
+
+func TestBus_DropsForSlowSubscriber(t *testing.T) {
+	bus := NewBus()
+	sub := bus.Subscribe(1)
+	defer sub.Close()
+
+	bus.Publish(VoteCast, 1)
+	bus.Publish(VoteCast, 2)
+
+	if sub.Dropped() != 1 {
+		t.Errorf("Dropped = %d, want 1", sub.Dropped())
+	}
+	if event := receive(t, sub); event.Data != 1 {
+		t.Errorf("expected first event to be kept, got %v", event.Data)
+	}
+}
+
+func TestSubscription_Close(t *testing.T) {
+	bus := NewBus()
+	sub := bus.Subscribe(0)
+	if bus.SubscriberCount() != 1 {
+		t.Fatalf("SubscriberCount = %d, want 1", bus.SubscriberCount())
+	}
+
+	sub.Close()
+	sub.Close() // idempotent
+
+	if bus.SubscriberCount() != 0 {
+		t.Errorf("SubscriberCount = %d, want 0", bus.SubscriberCount())
+	}
+	if _, ok := <-sub.C; ok {
+		t.Error("channel should be closed")
+	}
+	bus.Publish(VoteCast, nil) // must not panic
+}
+
+func TestBus_Nil(t *testing.T) {
+	var bus *Bus
+	bus.Publish(VoteCast, nil)
+
+	sub := bus.Subscribe(0)
+	if _, ok := <-sub.C; ok {
+		t.Error("nil bus subscription should be closed")
+	}
+	sub.Close()
+	if bus.SubscriberCount() != 0 {
+		t.Error("nil bus has no subscribers")
+	}
+}
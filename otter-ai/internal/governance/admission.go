@@ -0,0 +1,271 @@
+package governance
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"otter-ai/internal/errs"
+	"otter-ai/internal/events"
+)
+
+// ErrJoinPending is returned for a join request from an otter whose earlier
+// request is still awaiting its admission vote
+var ErrJoinPending = errs.New(errs.ErrConflict, "join request is already pending")
+
+// MemberAdmission is the payload of a member.admitted federation message,
+// telling the raft and the new member that its admission was adopted
+type MemberAdmission struct {
+	RaftID     string    `json:"raft_id"`
+	MemberID   string    `json:"member_id"`
+	ProposalID string    `json:"proposal_id"`
+	AdmittedAt time.Time `json:"admitted_at"`
+}
+
+// PendingJoin is a join request awaiting its admission vote
+type PendingJoin struct {
+	RaftID      string    `json:"raft_id"`
+	MemberID    string    `json:"member_id"`
+	Endpoint    string    `json:"endpoint,omitempty"`
+	RequestedAt time.Time `json:"requested_at"`
+	ProposalID  string    `json:"proposal_id"`
+	Deadline    time.Time `json:"deadline"`
+	YesVotes    int       `json:"yes_votes"`
+	NoVotes     int       `json:"no_votes"`
+}
+
+// proposeAdmission opens the proposal deciding whether a pending member is
+// admitted. It is decided by the raft's normal quorum of its active members.
+func (g *Governance) proposeAdmission(raft *RaftInfo, member *Member) (*Proposal, error) {
+	g.proposals.mu.Lock()
+	defer g.proposals.mu.Unlock()
+
+	for _, open := range g.proposals.proposals {
+		if open.Status == ProposalOpen && open.Kind == ProposalKindAdmission &&
+			open.RaftID == raft.RaftID && open.TargetMemberID == member.ID {
+			return nil, fmt.Errorf("%w: %s as proposal %s", ErrJoinPending, member.ID, open.ProposalID)
+		}
+	}
+
+	now := time.Now()
+	proposal := &Proposal{
+		ProposalID:     generateID(fmt.Sprintf("admit|%s|%s|%d", raft.RaftID, member.ID, now.UnixNano())),
+		RaftID:         raft.RaftID,
+		Kind:           ProposalKindAdmission,
+		TargetMemberID: member.ID,
+		Reason:         "join request",
+		ProposedBy:     g.config.ID,
+		ProposedAt:     now,
+		Deadline:       now.Add(g.VotingPeriod()),
+		Votes:          make(map[string]VoteType),
+		Ballots:        make(map[string]*SignedVote),
+		Status:         ProposalOpen,
+		Result:         ResultPending,
+		EligibleVoters: snapshotVoters(raft, member.ID),
+	}
+	g.proposals.proposals[proposal.ProposalID] = proposal
+
+	g.publish(events.ProposalCreated, proposalEvent(proposal))
+	g.recordAudit(AuditProposalCreated, raft.RaftID, proposal.ProposalID, g.config.ID, proposalEvent(proposal))
+	g.announceProposal(proposal)
+
+	return proposal, nil
+}
+
+// admitMember activates the member an adopted admission proposal names and
+// tells the raft and the new member. The caller must hold the proposal
+// registry lock.
+func (g *Governance) admitMember(proposal *Proposal) {
+	member := g.settlePendingMember(proposal.RaftID, proposal.TargetMemberID, proposal.ProposalID, StateActive)
+	if member == nil {
+		return
+	}
+
+	g.publishAdmission(proposal.RaftID, member, proposal.ProposalID)
+
+	admission := MemberAdmission{
+		RaftID:     proposal.RaftID,
+		MemberID:   member.ID,
+		ProposalID: proposal.ProposalID,
+		AdmittedAt: time.Now(),
+	}
+	go g.broadcast(context.Background(), proposal.RaftID, MessageMemberAdmitted, admission)
+}
+
+// rejectAdmission turns down the member a rejected or expired admission
+// proposal names. The caller must hold the proposal registry lock.
+func (g *Governance) rejectAdmission(proposal *Proposal) {
+	g.settlePendingMember(proposal.RaftID, proposal.TargetMemberID, proposal.ProposalID, StateRejected)
+}
+
+// settlePendingMember moves a pending member to state and returns a
+// snapshot of it, or nil if the member is no longer pending
+func (g *Governance) settlePendingMember(raftID, memberID, proposalID string, state MembershipState) *Member {
+	g.rafts.mu.RLock()
+	raft, exists := g.rafts.rafts[raftID]
+	g.rafts.mu.RUnlock()
+	if !exists {
+		return nil
+	}
+
+	raft.mu.Lock()
+	member, ok := raft.Members[memberID]
+	if !ok || member.State != StatePending {
+		raft.mu.Unlock()
+		return nil
+	}
+	member.State = state
+	if state == StateActive {
+		member.LastSeenAt = time.Now()
+	}
+	snapshot := *member
+	raft.mu.Unlock()
+
+	g.log().Info("settled join request", "member_id", memberID, "raft_id", raftID, "state", state, "proposal_id", proposalID)
+	g.recordAudit(AuditMemberStateChanged, raftID, memberID, proposalID,
+		MemberAudit{MemberID: memberID, State: state})
+
+	if err := g.saveRaft(context.Background(), raft); err != nil {
+		g.log().Warn("failed to persist join decision", "member_id", memberID, "raft_id", raftID, "error", err)
+	}
+	return &snapshot
+}
+
+// PendingJoins lists the join requests awaiting an admission vote in a
+// raft, or in every raft when raftID is empty, oldest first
+func (g *Governance) PendingJoins(raftID string) []*PendingJoin {
+	g.proposals.mu.RLock()
+	var admissions []*Proposal
+	pending := []*PendingJoin{}
+	for _, proposal := range g.proposals.proposals {
+		if proposal.Kind == ProposalKindAdmission && proposal.Status == ProposalOpen &&
+			(raftID == "" || proposal.RaftID == raftID) {
+			admissions = append(admissions, proposal)
+			join := &PendingJoin{
+				RaftID:     proposal.RaftID,
+				MemberID:   proposal.TargetMemberID,
+				ProposalID: proposal.ProposalID,
+				Deadline:   proposal.Deadline,
+			}
+			for _, vote := range proposal.Votes {
+				switch vote {
+				case VoteYes:
+					join.YesVotes++
+				case VoteNo:
+					join.NoVotes++
+				}
+			}
+			pending = append(pending, join)
+		}
+	}
+	g.proposals.mu.RUnlock()
+
+	for i, join := range pending {
+		join.RequestedAt = admissions[i].ProposedAt
+
+		g.rafts.mu.RLock()
+		raft, exists := g.rafts.rafts[join.RaftID]
+		g.rafts.mu.RUnlock()
+		if !exists {
+			continue
+		}
+		raft.mu.RLock()
+		if member, ok := raft.Members[join.MemberID]; ok {
+			join.Endpoint = member.Endpoint
+		}
+		raft.mu.RUnlock()
+	}
+
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].RequestedAt.Before(pending[j].RequestedAt)
+	})
+	return pending
+}
+
+// applyRemoteAdmission activates a member a peer admitted. This otter's own
+// admission is only taken from the otter that inducted it, which holds the
+// admission vote. Another member's is only taken from the origin of the
+// admission proposal mirrored here, once that proposal was adopted; until
+// then its outcome activates the member instead.
+func (g *Governance) applyRemoteAdmission(ctx context.Context, env *Envelope, admission *MemberAdmission) error {
+	if admission.RaftID != env.RaftID {
+		return fmt.Errorf("admission for raft %s sent in an envelope for raft %s", admission.RaftID, env.RaftID)
+	}
+	if admission.MemberID == "" {
+		return fmt.Errorf("admission names no member")
+	}
+
+	if admission.MemberID == g.config.ID {
+		if inductor := g.selfInductor(admission.RaftID); env.SenderID != inductor {
+			return fmt.Errorf("%w: %s did not induct this otter into raft %s", ErrUnknownSender, env.SenderID, admission.RaftID)
+		}
+	} else {
+		g.proposals.mu.RLock()
+		proposal, exists := g.proposals.proposals[admission.ProposalID]
+		var adopted bool
+		if exists {
+			exists = proposal.Kind == ProposalKindAdmission && proposal.RaftID == admission.RaftID &&
+				proposal.TargetMemberID == admission.MemberID && proposal.Origin == env.SenderID
+			adopted = proposal.Result == ResultAdopted
+		}
+		g.proposals.mu.RUnlock()
+		if !exists {
+			return fmt.Errorf("%w: %s holds no admission proposal %s for %s", ErrUnknownSender, env.SenderID, admission.ProposalID, admission.MemberID)
+		}
+		if !adopted {
+			return nil // The proposal's outcome activates the member
+		}
+	}
+
+	if member := g.settlePendingMember(admission.RaftID, admission.MemberID, admission.ProposalID, StateActive); member != nil {
+		g.log().InfoContext(ctx, "member admitted by peer", "member_id", member.ID, "raft_id", admission.RaftID, "sender_id", env.SenderID)
+		g.publishAdmission(admission.RaftID, member, admission.ProposalID)
+	}
+	return nil
+}
+
+// applyAdmissionOutcome settles the member a mirrored admission proposal
+// names once its origin announced the outcome. The caller must hold the
+// proposal registry lock.
+func (g *Governance) applyAdmissionOutcome(proposal *Proposal) {
+	if proposal.Kind != ProposalKindAdmission {
+		return
+	}
+	switch proposal.Result {
+	case ResultAdopted:
+		if member := g.settlePendingMember(proposal.RaftID, proposal.TargetMemberID, proposal.ProposalID, StateActive); member != nil {
+			g.publishAdmission(proposal.RaftID, member, proposal.ProposalID)
+		}
+	case ResultRejected:
+		g.rejectAdmission(proposal)
+	}
+}
+
+// publishAdmission publishes that a pending member was admitted
+func (g *Governance) publishAdmission(raftID string, member *Member, proposalID string) {
+	g.publish(events.MemberJoined, MemberEvent{
+		RaftID:     raftID,
+		MemberID:   member.ID,
+		State:      member.State,
+		InductedBy: member.InductedBy,
+		ProposalID: proposalID,
+	})
+}
+
+// selfInductor returns the ID of the otter that inducted this otter into
+// a raft, or "" when it is not a member
+func (g *Governance) selfInductor(raftID string) string {
+	g.rafts.mu.RLock()
+	raft, exists := g.rafts.rafts[raftID]
+	g.rafts.mu.RUnlock()
+	if !exists {
+		return ""
+	}
+	raft.mu.RLock()
+	defer raft.mu.RUnlock()
+	if self, ok := raft.Members[g.config.ID]; ok {
+		return self.InductedBy
+	}
+	return ""
+}
@@ -0,0 +1,195 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// requestUninvitedJoin asks g to admit peer into raft "otter-1" and returns
+// the admission proposal
+func requestUninvitedJoin(t *testing.T, g, peer *Governance) *Proposal {
+	t.Helper()
+	req := answeredJoinRequest(t, g, peer, "otter-1")
+	req.Endpoint = "http://" + peer.config.ID
+	req.Descriptor, _ = peer.CapabilityDescriptor()
+
+	resp, err := g.RequestJoin(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.State != StatePending || resp.ProposalID == "" {
+		t.Fatalf("response = %+v, want a pending admission", resp)
+	}
+	proposal, ok := g.GetProposal(resp.ProposalID)
+	if !ok {
+		t.Fatal("admission proposal not found")
+	}
+	return proposal
+}
+
+func TestRequestJoin_HeldPendingUntilAdmitted(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	peers := addPeers(g, "otter-2")
+	transport := newRecordingTransport()
+	g.SetTransport(transport)
+	ctx := context.Background()
+
+	proposal := requestUninvitedJoin(t, g, newTestGovernance("otter-3"))
+	if proposal.Kind != ProposalKindAdmission || proposal.TargetMemberID != "otter-3" {
+		t.Errorf("proposal = %+v, want admission of otter-3", proposal)
+	}
+	if len(proposal.EligibleVoters) != 2 {
+		t.Errorf("EligibleVoters = %v, want the two active members", proposal.EligibleVoters)
+	}
+	if state := g.rafts.rafts["otter-1"].Members["otter-3"].State; state != StatePending {
+		t.Fatalf("otter-3 state = %s, want pending", state)
+	}
+	for _, member := range g.getActiveMembers("otter-1") {
+		if member.ID == "otter-3" {
+			t.Error("pending member counted as active")
+		}
+	}
+	if pending := g.PendingJoins("otter-1"); len(pending) != 1 || pending[0].MemberID != "otter-3" || pending[0].Endpoint != "http://otter-3" {
+		t.Errorf("PendingJoins() = %+v", pending)
+	}
+
+	// A second request while pending is refused
+	if _, err := g.RequestJoin(ctx, answeredJoinRequest(t, g, newTestGovernance("otter-3"), "otter-1")); !errors.Is(err, ErrJoinPending) {
+		t.Errorf("expected ErrJoinPending, got %v", err)
+	}
+
+	// Two active members need both votes
+	if err := g.CastVote(ctx, proposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	if state := g.rafts.rafts["otter-1"].Members["otter-3"].State; state != StatePending {
+		t.Fatalf("otter-3 admitted on one of two votes, state = %s", state)
+	}
+	if err := peerVote(t, g, peers["otter-2"], proposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	if state := g.rafts.rafts["otter-1"].Members["otter-3"].State; state != StateActive {
+		t.Fatalf("otter-3 state = %s, want active", state)
+	}
+	if pending := g.PendingJoins(""); len(pending) != 0 {
+		t.Errorf("PendingJoins() = %+v after admission", pending)
+	}
+
+	// The new member is told it was admitted
+	transport.waitFor(t, "http://otter-3", MessageMemberAdmitted)
+}
+
+func TestRequestJoin_AdmissionRejected(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	peer := newTestGovernance("otter-2")
+
+	proposal := requestUninvitedJoin(t, g, peer)
+	if err := g.CastVote(context.Background(), proposal.ProposalID, VoteNo); err != nil {
+		t.Fatal(err)
+	}
+	if proposal.Result != ResultRejected {
+		t.Fatalf("result = %s, want rejected", proposal.Result)
+	}
+	if state := g.rafts.rafts["otter-1"].Members["otter-2"].State; state != StateRejected {
+		t.Errorf("otter-2 state = %s, want rejected", state)
+	}
+
+	// A turned down otter may ask again
+	requestUninvitedJoin(t, g, peer)
+}
+
+func TestRequestJoin_AdmissionExpired(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	proposal := requestUninvitedJoin(t, g, newTestGovernance("otter-2"))
+
+	if closed := g.closeExpiredProposals(proposal.Deadline.Add(time.Second)); closed != 1 {
+		t.Fatalf("closed %d proposals, want 1", closed)
+	}
+	if state := g.rafts.rafts["otter-1"].Members["otter-2"].State; state != StateRejected {
+		t.Errorf("otter-2 state = %s, want rejected", state)
+	}
+}
+
+func TestHandleEnvelope_AdmitsSelf(t *testing.T) {
+	sender, receiver := federatedPair(t)
+	self := receiver.rafts.rafts["otter-1"].Members["otter-2"]
+	self.State, self.InductedBy = StatePending, "otter-1"
+	bystander := newTestGovernance("otter-3")
+	receiver.rafts.rafts["otter-1"].Members["otter-3"].SigningKey = bystander.crypto.GetSigningPublicKey()
+	admission := MemberAdmission{RaftID: "otter-1", MemberID: "otter-2", ProposalID: "p1", AdmittedAt: time.Now()}
+
+	// Only the otter that inducted this one holds its admission vote
+	env, err := bystander.sealEnvelope(MessageMemberAdmitted, "otter-1", admission)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := receiver.HandleEnvelope(context.Background(), env); !errors.Is(err, ErrUnknownSender) {
+		t.Errorf("admission from a bystander: err = %v, want ErrUnknownSender", err)
+	}
+	if self.State != StatePending {
+		t.Fatalf("own membership state = %s after a bystander's admission, want pending", self.State)
+	}
+
+	env, err = sender.sealEnvelope(MessageMemberAdmitted, "otter-1", admission)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := receiver.HandleEnvelope(context.Background(), env); err != nil {
+		t.Fatal(err)
+	}
+	if self.State != StateActive {
+		t.Errorf("own membership state = %s, want active", self.State)
+	}
+}
+
+func TestHandleEnvelope_AdmitsOtherMemberOnlyOnAdoptedVote(t *testing.T) {
+	sender, receiver := federatedPair(t)
+	raft := receiver.rafts.rafts["otter-1"]
+	raft.Members["otter-4"] = &Member{ID: "otter-4", State: StatePending, JoinedAt: time.Now()}
+	bystander := newTestGovernance("otter-3")
+	raft.Members["otter-3"].SigningKey = bystander.crypto.GetSigningPublicKey()
+	ctx := context.Background()
+	deliver := func(from *Governance, msgType string, payload interface{}) error {
+		t.Helper()
+		env, err := from.sealEnvelope(msgType, "otter-1", payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return receiver.HandleEnvelope(ctx, env)
+	}
+	admission := MemberAdmission{RaftID: "otter-1", MemberID: "otter-4", ProposalID: "admit-4", AdmittedAt: time.Now()}
+
+	// No admission vote is known for the member
+	if err := deliver(bystander, MessageMemberAdmitted, admission); !errors.Is(err, ErrUnknownSender) {
+		t.Errorf("admission without a vote: err = %v, want ErrUnknownSender", err)
+	}
+
+	if err := deliver(sender, MessageProposalOpened, ProposalAnnouncement{
+		ProposalID: "admit-4", RaftID: "otter-1", Kind: ProposalKindAdmission, TargetMemberID: "otter-4",
+		ProposedBy: "otter-1", ProposedAt: time.Now(), Deadline: time.Now().Add(time.Hour),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// An unrelated member cannot settle another otter's vote
+	if err := deliver(bystander, MessageMemberAdmitted, admission); !errors.Is(err, ErrUnknownSender) {
+		t.Errorf("admission from a bystander: err = %v, want ErrUnknownSender", err)
+	}
+	// Nor can the origin before the vote was adopted
+	if err := deliver(sender, MessageMemberAdmitted, admission); err != nil {
+		t.Fatal(err)
+	}
+	if state := raft.Members["otter-4"].State; state != StatePending {
+		t.Fatalf("otter-4 state = %s before the vote was adopted, want pending", state)
+	}
+
+	if err := deliver(sender, MessageProposalClosed, ProposalOutcome{
+		ProposalID: "admit-4", RaftID: "otter-1", Result: ResultAdopted, QuorumMet: true, ClosedAt: time.Now(),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if state := raft.Members["otter-4"].State; state != StateActive {
+		t.Errorf("otter-4 state = %s after the adopted outcome, want active", state)
+	}
+}
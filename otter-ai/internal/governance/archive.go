@@ -0,0 +1,107 @@
+package governance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"otter-ai/internal/errs"
+	"otter-ai/internal/events"
+)
+
+var (
+	// ErrRaftNotFound is returned for operations on a raft this otter does
+	// not know
+	ErrRaftNotFound = errs.New(errs.ErrNotFound, "raft not found")
+	// ErrRaftArchived is returned for changes to an archived raft, which is
+	// kept read-only for its history
+	ErrRaftArchived = errs.New(errs.ErrConflict, "raft is archived")
+)
+
+// RaftArchive records why and when a raft was archived. An archived raft
+// keeps its rules, members and audit history for reference, but its rules
+// are no longer in force and it takes no part in conflict detection,
+// quorum or federation.
+type RaftArchive struct {
+	ArchivedAt time.Time `json:"archived_at"`
+	ArchivedBy string    `json:"archived_by"`
+	Reason     string    `json:"reason,omitempty"`
+}
+
+// RaftArchiveEvent describes an archived raft in published events
+type RaftArchiveEvent struct {
+	RaftID string `json:"raft_id"`
+	RaftArchive
+}
+
+// isArchived reports whether the raft is archived
+func (r *RaftInfo) isArchived() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.Archive != nil
+}
+
+// liveRaft returns a raft that can still change
+func (g *Governance) liveRaft(raftID string) (*RaftInfo, error) {
+	g.rafts.mu.RLock()
+	raft, exists := g.rafts.rafts[raftID]
+	g.rafts.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrRaftNotFound, raftID)
+	}
+	if raft.isArchived() {
+		return nil, fmt.Errorf("%w: %s", ErrRaftArchived, raftID)
+	}
+	return raft, nil
+}
+
+// ArchiveRaft moves a raft this otter no longer takes part in, such as one
+// it left or one that dissolved, to a read-only archived state. Its rules
+// stop being in force and its open proposals are closed as rejected, but
+// the rules, members and audit history stay queryable. This otter's own
+// raft cannot be archived.
+func (g *Governance) ArchiveRaft(ctx context.Context, raftID, archivedBy, reason string) error {
+	if raftID == g.config.ID {
+		return fmt.Errorf("cannot archive this otter's own raft")
+	}
+	raft, err := g.liveRaft(raftID)
+	if err != nil {
+		return err
+	}
+
+	archive := &RaftArchive{ArchivedAt: time.Now(), ArchivedBy: archivedBy, Reason: reason}
+	raft.mu.Lock()
+	raft.Archive = archive
+	raft.mu.Unlock()
+
+	// The raft's rules stay in the registry for history but are no longer active
+	g.rules.mu.Lock()
+	for key := range g.rules.active {
+		if key.RaftID == raftID {
+			delete(g.rules.active, key)
+		}
+	}
+	g.rules.mu.Unlock()
+
+	g.proposals.mu.Lock()
+	for _, proposal := range g.proposals.proposals {
+		if proposal.RaftID == raftID && proposal.Status == ProposalOpen {
+			proposal.Status = ProposalClosed
+			proposal.Result = ResultRejected
+			proposal.ClosedAt = &archive.ArchivedAt
+			g.publish(events.ProposalClosed, proposalEvent(proposal))
+			g.recordAudit(AuditProposalClosed, raftID, proposal.ProposalID, archivedBy, proposalEvent(proposal))
+		}
+	}
+	g.proposals.mu.Unlock()
+
+	if err := g.saveRaft(ctx, raft); err != nil {
+		g.log().WarnContext(ctx, "failed to persist archived raft", "raft_id", raftID, "error", err)
+	}
+
+	event := RaftArchiveEvent{RaftID: raftID, RaftArchive: *archive}
+	g.recordAudit(AuditRaftArchived, raftID, raftID, archivedBy, event)
+	g.publish(events.RaftArchived, event)
+	return nil
+}
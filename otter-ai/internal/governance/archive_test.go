@@ -0,0 +1,149 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"otter-ai/internal/memory"
+	"otter-ai/internal/vectordb"
+)
+
+// joinTestRaft adds a raft with a second member, an adopted rule and an
+// open proposal
+func joinTestRaft(t *testing.T, g *Governance) *Proposal {
+	t.Helper()
+	now := time.Now()
+	rule := signedTestRule(t, g)
+	rule.AdoptedAt = &now
+	g.rafts.rafts["raft-1"] = &RaftInfo{
+		RaftID: "raft-1",
+		Members: map[string]*Member{
+			g.config.ID: {ID: g.config.ID, State: StateActive, JoinedAt: now, LastSeenAt: now, SigningKey: g.crypto.GetSigningPublicKey()},
+			"otter-2":   {ID: "otter-2", State: StateActive, JoinedAt: now, LastSeenAt: now},
+		},
+		Rules:     map[string]*Rule{rule.RuleID: rule},
+		CreatedAt: now,
+	}
+	g.rules.rules[rule.RuleID] = rule
+	g.rules.active[activeKey(rule)] = rule
+
+	proposal, err := g.ProposeRule(context.Background(), "raft-1", &Rule{Scope: "tone", Body: "be brief", ProposedBy: g.config.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return proposal
+}
+
+func TestArchiveRaft(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	proposal := joinTestRaft(t, g)
+	ctx := context.Background()
+
+	if err := g.ArchiveRaft(ctx, "raft-1", "otter-1", "raft dissolved"); err != nil {
+		t.Fatalf("ArchiveRaft: %v", err)
+	}
+
+	// History stays queryable
+	if rules, err := g.RaftRules("raft-1"); err != nil || len(rules) != 1 {
+		t.Errorf("RaftRules = %v, %v; want the archived rule", rules, err)
+	}
+	var summary *RaftSummary
+	for _, s := range g.RaftSummaries() {
+		if s.RaftID == "raft-1" {
+			summary = &s
+		}
+	}
+	if summary == nil || !summary.Archived || summary.Archive.Reason != "raft dissolved" || len(summary.Members) != 2 {
+		t.Errorf("summary = %+v, want archived with both members", summary)
+	}
+
+	// But its rules are no longer in force and its proposals are closed
+	if _, ok := g.GetActiveRules()["raft-1"]; ok {
+		t.Error("archived raft's rules are still active")
+	}
+	if proposal.Status != ProposalClosed || proposal.Result != ResultRejected {
+		t.Errorf("open proposal = %s/%s, want closed and rejected", proposal.Status, proposal.Result)
+	}
+
+	// And it is read-only
+	if _, err := g.ProposeRule(ctx, "raft-1", &Rule{Scope: "x", Body: "y", ProposedBy: "otter-1"}); !errors.Is(err, ErrRaftArchived) {
+		t.Errorf("ProposeRule err = %v, want ErrRaftArchived", err)
+	}
+	if _, err := g.ProposeEviction(ctx, "raft-1", "otter-2", "otter-1", "", 0); !errors.Is(err, ErrRaftArchived) {
+		t.Errorf("ProposeEviction err = %v, want ErrRaftArchived", err)
+	}
+	if _, err := g.RequestJoin(ctx, JoinRequest{RaftID: "raft-1", RequesterID: "otter-3"}); !errors.Is(err, ErrRaftArchived) {
+		t.Errorf("RequestJoin err = %v, want ErrRaftArchived", err)
+	}
+	if err := g.ArchiveRaft(ctx, "raft-1", "otter-1", ""); !errors.Is(err, ErrRaftArchived) {
+		t.Errorf("second ArchiveRaft err = %v, want ErrRaftArchived", err)
+	}
+}
+
+func TestArchiveRaft_ExcludedFromConflictsAndQuorum(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	joinTestRaft(t, g)
+	if err := g.ArchiveRaft(context.Background(), "raft-1", "otter-1", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	target := map[string]*Rule{"t1": {RuleID: "t1", Scope: "safety", Body: "be bold"}}
+	if conflicts := g.detectRuleConflicts("raft-2", target); len(conflicts) != 0 {
+		t.Errorf("got %d conflicts with an archived raft, want 0", len(conflicts))
+	}
+	if members := g.getActiveMembers("raft-1"); members != nil {
+		t.Errorf("archived raft has %d voting members, want none", len(members))
+	}
+}
+
+func TestArchiveRaft_RefusesOwnRaft(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	if err := g.ArchiveRaft(context.Background(), "otter-1", "otter-1", ""); err == nil {
+		t.Error("archiving the otter's own raft should fail")
+	}
+	if err := g.ArchiveRaft(context.Background(), "missing", "otter-1", ""); !errors.Is(err, ErrRaftNotFound) {
+		t.Errorf("err = %v, want ErrRaftNotFound", err)
+	}
+}
+
+func TestArchiveRaft_Persists(t *testing.T) {
+	dir := t.TempDir()
+	db, err := vectordb.NewSQLiteVectorDB(filepath.Join(dir, "otter.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	g, err := New(RaftConfig{ID: "otter-1", DataDir: dir}, memory.New(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Shutdown(context.Background())
+	joinTestRaft(t, g)
+	if err := g.signMember("raft-1", g.rafts.rafts["raft-1"].Members["otter-1"]); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.ArchiveRaft(context.Background(), "raft-1", "otter-1", "left"); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := New(RaftConfig{ID: "otter-1", DataDir: dir}, memory.New(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reloaded.Shutdown(context.Background())
+
+	raft := reloaded.rafts.rafts["raft-1"]
+	if raft == nil || raft.Archive == nil || raft.Archive.Reason != "left" {
+		t.Fatalf("reloaded raft = %+v, want archived", raft)
+	}
+	if len(raft.Rules) != 1 {
+		t.Errorf("reloaded %d rules, want the archived rule", len(raft.Rules))
+	}
+	if _, ok := reloaded.GetActiveRules()["raft-1"]; ok {
+		t.Error("reloaded archived raft's rules are active")
+	}
+}
@@ -0,0 +1,297 @@
+package governance
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// AuditAction identifies a governance change recorded in the audit log
+type AuditAction string
+
+const (
+	AuditMemberJoined        AuditAction = "member.joined"
+	AuditMemberStateChanged  AuditAction = "member.state_changed"
+	AuditRuleAdopted         AuditAction = "rule.adopted"
+	AuditRuleDeactivated     AuditAction = "rule.deactivated"
+	AuditRuleSuspended       AuditAction = "rule.suspended"  // Deactivated in an emergency pending a re-vote
+	AuditRuleReinstated      AuditAction = "rule.reinstated" // Restored by the re-vote on its suspension
+	AuditProposalCreated     AuditAction = "proposal.created"
+	AuditProposalClosed      AuditAction = "proposal.closed"
+	AuditVoteCast            AuditAction = "vote.cast"
+	AuditVoteInteraction     AuditAction = "vote.interaction" // A vote cast from a chat platform button
+	AuditVoteDelegated       AuditAction = "vote.delegated"   // A vote cast under the owner's standing policy
+	AuditHoldPlaced          AuditAction = "hold.placed"
+	AuditHoldReleaseApproved AuditAction = "hold.release_approved"
+	AuditHoldReleased        AuditAction = "hold.released"
+	AuditRaftArchived        AuditAction = "raft.archived"
+	AuditKeyInstalled        AuditAction = "key.installed" // This otter started signing the log with a new key
+	AuditRuleViolated        AuditAction = "rule.violated" // An agent output broke an active rule
+	AuditKeyRotated          AuditAction = "key.rotated"   // A member's signing key was replaced through a rollover
+	AuditMemoryErased        AuditAction = "memory.erased" // Memories were erased on request; the entry is their tombstone
+)
+
+// AuditActorBackfill marks entries reconstructed from stored state for
+// changes made before the audit log existed
+const AuditActorBackfill = "backfill"
+
+// AuditEntry is one append-only record of a governance change. Each entry
+// is hash-chained to the one before it and signed with the otter's key;
+// entries written before the chain existed have no hash.
+type AuditEntry struct {
+	ID        int64           `json:"id"`
+	Timestamp time.Time       `json:"timestamp"`
+	Action    AuditAction     `json:"action"`
+	RaftID    string          `json:"raft_id"`
+	SubjectID string          `json:"subject_id"` // Member, rule, proposal, hold or erasure ID
+	Actor     string          `json:"actor,omitempty"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	PrevHash  string          `json:"prev_hash,omitempty"`
+	Hash      string          `json:"hash,omitempty"`
+	Signature []byte          `json:"signature,omitempty"`
+	SignerKey []byte          `json:"signer_key,omitempty"`
+}
+
+// MemberAudit is the data of member audit entries
+type MemberAudit struct {
+	MemberID   string          `json:"member_id"`
+	State      MembershipState `json:"state"`
+	InductedBy string          `json:"inducted_by,omitempty"`
+}
+
+// AuditFilter selects audit entries. Zero fields match everything.
+type AuditFilter struct {
+	RaftID string
+	Since  time.Time // Inclusive
+	Until  time.Time // Inclusive
+}
+
+func (f AuditFilter) matches(entry *AuditEntry) bool {
+	if f.RaftID != "" && entry.RaftID != f.RaftID {
+		return false
+	}
+	if !f.Since.IsZero() && entry.Timestamp.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && entry.Timestamp.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// auditLog holds entries when no database is available. Its lock also
+// serializes appends to the database so each entry chains to the last.
+type auditLog struct {
+	entries []AuditEntry
+	mu      sync.Mutex
+}
+
+func (g *Governance) auditMemory() *auditLog {
+	g.auditOnce.Do(func() {
+		if g.audit == nil {
+			g.audit = &auditLog{}
+		}
+	})
+	return g.audit
+}
+
+// recordAudit appends an entry to the audit log. Failures are logged rather
+// than returned so a storage problem never blocks governance itself.
+func (g *Governance) recordAudit(action AuditAction, raftID, subjectID, actor string, data interface{}) {
+	g.appendAudit(AuditEntry{
+		Timestamp: time.Now(),
+		Action:    action,
+		RaftID:    raftID,
+		SubjectID: subjectID,
+		Actor:     actor,
+	}, data)
+}
+
+func (g *Governance) appendAudit(entry AuditEntry, data interface{}) {
+	if data != nil {
+		payload, err := json.Marshal(data)
+		if err != nil {
+			g.log().Warn("failed to encode audit entry", "action", entry.Action, "error", err)
+			return
+		}
+		entry.Data = payload
+	}
+
+	log := g.auditMemory()
+	log.mu.Lock()
+	defer log.mu.Unlock()
+
+	db := g.getDB()
+	if db != nil {
+		var prevHash sql.NullString
+		err := db.QueryRow(`SELECT hash FROM governance_audit ORDER BY id DESC LIMIT 1`).Scan(&prevHash)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			g.log().Warn("failed to read audit chain head", "action", entry.Action, "error", err)
+			return
+		}
+		entry.PrevHash = prevHash.String
+	} else if n := len(log.entries); n > 0 {
+		entry.PrevHash = log.entries[n-1].Hash
+	}
+	g.sealAudit(&entry)
+
+	if db != nil {
+		_, err := db.Exec(`
+			INSERT INTO governance_audit (timestamp, action, raft_id, subject_id, actor, data, prev_hash, hash, signature, signer_key)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, entry.Timestamp.UnixNano(), string(entry.Action), entry.RaftID, entry.SubjectID, entry.Actor, string(entry.Data),
+			entry.PrevHash, entry.Hash, entry.Signature, entry.SignerKey)
+		if err != nil {
+			g.log().Warn("failed to write audit entry", "action", entry.Action, "subject_id", entry.SubjectID, "error", err)
+		}
+		return
+	}
+
+	entry.ID = int64(len(log.entries) + 1)
+	log.entries = append(log.entries, entry)
+}
+
+// EachAuditEntry streams matching audit entries, oldest first, to fn.
+// Returning an error from fn stops the iteration.
+func (g *Governance) EachAuditEntry(ctx context.Context, filter AuditFilter, fn func(AuditEntry) error) error {
+	db := g.getDB()
+	if db == nil {
+		log := g.auditMemory()
+		log.mu.Lock()
+		entries := make([]AuditEntry, len(log.entries))
+		copy(entries, log.entries)
+		log.mu.Unlock()
+
+		for i := range entries {
+			if filter.matches(&entries[i]) {
+				if err := fn(entries[i]); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	return eachAuditRow(ctx, db, filter, fn)
+}
+
+// eachAuditRow streams matching audit entries from the database, oldest
+// first, to fn
+func eachAuditRow(ctx context.Context, db *sql.DB, filter AuditFilter, fn func(AuditEntry) error) error {
+	query := `SELECT id, timestamp, action, raft_id, subject_id, actor, data, prev_hash, hash, signature, signer_key
+		FROM governance_audit WHERE 1 = 1`
+	var args []interface{}
+	if filter.RaftID != "" {
+		query += ` AND raft_id = ?`
+		args = append(args, filter.RaftID)
+	}
+	if !filter.Since.IsZero() {
+		query += ` AND timestamp >= ?`
+		args = append(args, filter.Since.UnixNano())
+	}
+	if !filter.Until.IsZero() {
+		query += ` AND timestamp <= ?`
+		args = append(args, filter.Until.UnixNano())
+	}
+	query += ` ORDER BY id`
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry AuditEntry
+		var timestamp int64
+		var action string
+		var actor, data, prevHash, hash sql.NullString
+		if err := rows.Scan(&entry.ID, &timestamp, &action, &entry.RaftID, &entry.SubjectID, &actor, &data,
+			&prevHash, &hash, &entry.Signature, &entry.SignerKey); err != nil {
+			return fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		entry.Timestamp = time.Unix(0, timestamp)
+		entry.Action = AuditAction(action)
+		entry.Actor = actor.String
+		entry.PrevHash = prevHash.String
+		entry.Hash = hash.String
+		if data.String != "" {
+			entry.Data = json.RawMessage(data.String)
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// backfillAudit seeds an empty audit log from the loaded state, so rafts,
+// members and rules that predate the log still show up in time-travel
+// queries. Entries are dated by when each change originally happened.
+func (g *Governance) backfillAudit(ctx context.Context) {
+	empty := true
+	g.EachAuditEntry(ctx, AuditFilter{}, func(AuditEntry) error {
+		empty = false
+		return errStopIteration
+	})
+	if !empty {
+		return
+	}
+
+	type seed struct {
+		entry AuditEntry
+		data  interface{}
+	}
+	var seeds []seed
+
+	g.rafts.mu.RLock()
+	for _, raft := range g.rafts.rafts {
+		raft.mu.RLock()
+		for _, member := range raft.Members {
+			seeds = append(seeds, seed{
+				entry: AuditEntry{Timestamp: member.JoinedAt, Action: AuditMemberJoined, RaftID: raft.RaftID, SubjectID: member.ID},
+				data:  MemberAudit{MemberID: member.ID, State: StateActive, InductedBy: member.InductedBy},
+			})
+			if member.State != StateActive {
+				changedAt := member.LastSeenAt
+				if member.ExpiresAt != nil {
+					changedAt = *member.ExpiresAt
+				}
+				seeds = append(seeds, seed{
+					entry: AuditEntry{Timestamp: changedAt, Action: AuditMemberStateChanged, RaftID: raft.RaftID, SubjectID: member.ID},
+					data:  MemberAudit{MemberID: member.ID, State: member.State},
+				})
+			}
+		}
+		raft.mu.RUnlock()
+	}
+	g.rafts.mu.RUnlock()
+
+	g.rules.mu.RLock()
+	for _, rule := range g.rules.active {
+		if rule.AdoptedAt == nil {
+			continue
+		}
+		seeds = append(seeds, seed{
+			entry: AuditEntry{Timestamp: *rule.AdoptedAt, Action: AuditRuleAdopted, RaftID: rule.RaftID, SubjectID: rule.RuleID},
+			data:  ruleEvent(rule),
+		})
+	}
+	g.rules.mu.RUnlock()
+
+	sort.SliceStable(seeds, func(i, j int) bool {
+		return seeds[i].entry.Timestamp.Before(seeds[j].entry.Timestamp)
+	})
+	for _, s := range seeds {
+		s.entry.Actor = AuditActorBackfill
+		g.appendAudit(s.entry, s.data)
+	}
+}
+
+// errStopIteration ends an audit iteration early without reporting an error
+var errStopIteration = errors.New("stop iteration")
@@ -0,0 +1,149 @@
+package governance
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func auditActions(t *testing.T, g *Governance, filter AuditFilter) []AuditAction {
+	t.Helper()
+	var actions []AuditAction
+	err := g.EachAuditEntry(context.Background(), filter, func(entry AuditEntry) error {
+		actions = append(actions, entry.Action)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("EachAuditEntry: %v", err)
+	}
+	return actions
+}
+
+func TestAudit_RecordsProposalLifecycle(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	proposal, err := g.ProposeRule(context.Background(), "otter-1",
+		&Rule{Scope: "safety", Body: "be kind", ProposedBy: "otter-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CastVote(context.Background(), proposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+
+	got := auditActions(t, g, AuditFilter{})
+	want := []AuditAction{AuditProposalCreated, AuditVoteCast, AuditRuleAdopted, AuditProposalClosed}
+	if len(got) != len(want) {
+		t.Fatalf("actions = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("actions = %v, want %v", got, want)
+			break
+		}
+	}
+
+	if n := len(auditActions(t, g, AuditFilter{RaftID: "other"})); n != 0 {
+		t.Errorf("raft filter returned %d entries", n)
+	}
+}
+
+func TestStateAt_RulesAndMembersOverTime(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	ctx := context.Background()
+
+	// Entries are dated explicitly so the test does not depend on timing
+	day := func(n int) time.Time { return time.Date(2026, 1, n, 12, 0, 0, 0, time.UTC) }
+	base := &Rule{RuleID: "r1", RaftID: "otter-1", Scope: "safety", Body: "be kind", Version: 1}
+	override := &Rule{RuleID: "r2", RaftID: "otter-1", Scope: "safety", Body: "be very kind", Version: 2, BaseRuleID: "r1"}
+
+	g.appendAudit(AuditEntry{Timestamp: day(1), Action: AuditMemberJoined, RaftID: "otter-1", SubjectID: "otter-1"},
+		MemberAudit{MemberID: "otter-1", State: StateActive, InductedBy: "self"})
+	g.appendAudit(AuditEntry{Timestamp: day(2), Action: AuditRuleAdopted, RaftID: "otter-1", SubjectID: "r1"}, ruleEvent(base))
+	g.appendAudit(AuditEntry{Timestamp: day(3), Action: AuditMemberJoined, RaftID: "otter-1", SubjectID: "otter-2"},
+		MemberAudit{MemberID: "otter-2", State: StateActive, InductedBy: "otter-1"})
+	g.appendAudit(AuditEntry{Timestamp: day(5), Action: AuditRuleDeactivated, RaftID: "otter-1", SubjectID: "r1"}, ruleEvent(base))
+	g.appendAudit(AuditEntry{Timestamp: day(5), Action: AuditRuleAdopted, RaftID: "otter-1", SubjectID: "r2"}, ruleEvent(override))
+	g.appendAudit(AuditEntry{Timestamp: day(7), Action: AuditMemberStateChanged, RaftID: "otter-1", SubjectID: "otter-2"},
+		MemberAudit{MemberID: "otter-2", State: StateExpired})
+
+	state, err := g.StateAt(ctx, day(1).Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Rafts) != 0 {
+		t.Errorf("nothing existed before day 1, got %+v", state.Rafts)
+	}
+
+	state, _ = g.StateAt(ctx, day(4))
+	raft := state.Rafts[0]
+	if len(raft.Members) != 2 || len(raft.Rules) != 1 || raft.Rules[0].RuleID != "r1" {
+		t.Errorf("day 4 state = %+v", raft)
+	}
+	if !raft.Rules[0].AdoptedAt.Equal(day(2)) {
+		t.Errorf("AdoptedAt = %v, want %v", raft.Rules[0].AdoptedAt, day(2))
+	}
+
+	state, _ = g.StateAt(ctx, day(6))
+	raft = state.Rafts[0]
+	if len(raft.Rules) != 1 || raft.Rules[0].RuleID != "r2" {
+		t.Errorf("override should be in force on day 6, got %+v", raft.Rules)
+	}
+
+	state, _ = g.StateAt(ctx, day(8))
+	for _, member := range state.Rafts[0].Members {
+		if member.MemberID == "otter-2" && member.State != StateExpired {
+			t.Errorf("otter-2 should be expired on day 8, got %s", member.State)
+		}
+		if member.MemberID == "otter-2" && !member.JoinedAt.Equal(day(3)) {
+			t.Errorf("JoinedAt = %v, want %v", member.JoinedAt, day(3))
+		}
+	}
+}
+
+func TestStateAt_OpenProposals(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	g.rafts.rafts["otter-1"].Members["otter-2"] = &Member{ID: "otter-2", State: StateActive}
+
+	proposal, err := g.ProposeRule(context.Background(), "otter-1",
+		&Rule{Scope: "safety", Body: "be kind", ProposedBy: "otter-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CastVote(context.Background(), proposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := g.StateAt(context.Background(), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	open := state.Rafts[0].OpenProposals
+	if len(open) != 1 || open[0].ProposalID != proposal.ProposalID || open[0].YesVotes != 1 {
+		t.Errorf("open proposals = %+v", open)
+	}
+}
+
+func TestBackfillAudit(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	adopted := time.Now().Add(-48 * time.Hour)
+	rule := &Rule{RuleID: "r1", RaftID: "otter-1", Scope: "safety", Body: "be kind", AdoptedAt: &adopted}
+	g.rules.rules["r1"] = rule
+	g.rules.active[activeKey(rule)] = rule
+
+	g.backfillAudit(context.Background())
+	got := auditActions(t, g, AuditFilter{})
+	if len(got) != 2 {
+		t.Fatalf("backfill recorded %v", got)
+	}
+
+	state, _ := g.StateAt(context.Background(), adopted.Add(time.Minute))
+	if len(state.Rafts) != 1 || len(state.Rafts[0].Rules) != 1 {
+		t.Errorf("backfilled rule should be in force after its adoption: %+v", state.Rafts)
+	}
+
+	// A non-empty log is left alone
+	g.backfillAudit(context.Background())
+	if n := len(auditActions(t, g, AuditFilter{})); n != 2 {
+		t.Errorf("second backfill added entries, now %d", n)
+	}
+}
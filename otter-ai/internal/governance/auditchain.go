@@ -0,0 +1,197 @@
+package governance
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// KeyAudit is the data of key.installed audit entries
+type KeyAudit struct {
+	SigningKey string `json:"signing_key"` // Hex Ed25519 public key
+}
+
+// AuditVerification reports whether the audit log's hash chain and
+// signatures are intact
+type AuditVerification struct {
+	Valid     bool   `json:"valid"`
+	Entries   int    `json:"entries"`              // Entries checked
+	Unchained int    `json:"unchained"`            // Entries written before the chain existed
+	Head      string `json:"head,omitempty"`       // Hash of the last entry; deleting trailing entries only shows against a recorded head
+	SignerKey string `json:"signer_key,omitempty"` // Hex key that signed the last entry
+	BrokenAt  int64  `json:"broken_at,omitempty"`  // ID of the first entry that fails
+	Problem   string `json:"problem,omitempty"`
+}
+
+// auditEntryHash returns the hex SHA-256 an entry is chained by. It covers
+// the previous entry's hash and every recorded field but the ID.
+func auditEntryHash(entry *AuditEntry) string {
+	sum := sha256.Sum256(canonicalPayload(
+		entry.PrevHash,
+		strconv.FormatInt(entry.Timestamp.UnixNano(), 10),
+		string(entry.Action),
+		entry.RaftID,
+		entry.SubjectID,
+		entry.Actor,
+		string(entry.Data),
+	))
+	return hex.EncodeToString(sum[:])
+}
+
+// sealAudit hashes an entry onto its PrevHash and signs the hash. Without a
+// signing key the entry is chained but unsigned, which verification reports.
+func (g *Governance) sealAudit(entry *AuditEntry) {
+	entry.Hash = auditEntryHash(entry)
+	if g.crypto == nil {
+		return
+	}
+	sum, _ := hex.DecodeString(entry.Hash)
+	signature, err := g.crypto.Sign(sum)
+	if err != nil {
+		g.log().Warn("failed to sign audit entry", "action", entry.Action, "error", err)
+		return
+	}
+	entry.Signature = signature
+	entry.SignerKey = g.crypto.GetSigningPublicKey()
+}
+
+// recordKeyInstalled notes in the audit log when this otter signs with a key
+// the log's last entry was not signed with, such as after `keytool
+// generate`. Verification only accepts a change of signer at such an entry.
+func (g *Governance) recordKeyInstalled(ctx context.Context) {
+	if g.crypto == nil {
+		return
+	}
+	key := g.crypto.GetSigningPublicKey()
+
+	var last AuditEntry
+	empty := true
+	g.EachAuditEntry(ctx, AuditFilter{}, func(entry AuditEntry) error {
+		last, empty = entry, false
+		return nil
+	})
+	if empty || bytes.Equal(last.SignerKey, key) {
+		return
+	}
+	g.recordAudit(AuditKeyInstalled, g.config.ID, g.config.ID, g.config.ID, KeyAudit{SigningKey: hex.EncodeToString(key)})
+}
+
+// VerifyAudit checks this otter's audit log against its current signing key
+func (g *Governance) VerifyAudit(ctx context.Context) (*AuditVerification, error) {
+	var trusted []byte
+	if g.crypto != nil {
+		trusted = g.crypto.GetSigningPublicKey()
+	}
+	v := &auditVerifier{trusted: trusted}
+	if err := g.EachAuditEntry(ctx, AuditFilter{}, v.check); err != nil && !errors.Is(err, errStopIteration) {
+		return nil, err
+	}
+	return v.result(), nil
+}
+
+// VerifyAuditLog checks the audit log in a database against a trusted
+// signing key, without a running otter
+func VerifyAuditLog(ctx context.Context, db *sql.DB, trusted []byte) (*AuditVerification, error) {
+	v := &auditVerifier{trusted: trusted}
+	if err := eachAuditRow(ctx, db, AuditFilter{}, v.check); err != nil && !errors.Is(err, errStopIteration) {
+		return nil, err
+	}
+	return v.result(), nil
+}
+
+// auditVerifier walks the audit log oldest first. Leading entries without a
+// hash predate the chain; after the first chained entry every entry must
+// link to the one before it, hash to its recorded hash and carry a valid
+// signature. The signer may only change at a key.installed entry announcing
+// the new key, and the last entry must be signed by the trusted key.
+type auditVerifier struct {
+	trusted  []byte
+	report   AuditVerification
+	prevHash string
+	signer   []byte
+	chained  bool
+}
+
+func (v *auditVerifier) check(entry AuditEntry) error {
+	if v.report.BrokenAt != 0 {
+		return errStopIteration
+	}
+	v.report.Entries++
+
+	if entry.Hash == "" && !v.chained {
+		v.report.Unchained++
+		return nil
+	}
+	v.chained = true
+
+	switch {
+	case entry.Hash == "":
+		return v.fail(entry.ID, "entry is not chained")
+	case entry.PrevHash != v.prevHash:
+		return v.fail(entry.ID, "entry does not link to the previous entry")
+	case auditEntryHash(&entry) != entry.Hash:
+		return v.fail(entry.ID, "entry does not match its hash")
+	case len(entry.Signature) == 0:
+		return v.fail(entry.ID, "entry is unsigned")
+	}
+	sum, _ := hex.DecodeString(entry.Hash)
+	if !VerifySignature(sum, entry.Signature, entry.SignerKey) {
+		return v.fail(entry.ID, "entry signature is invalid")
+	}
+	if v.signer != nil && !bytes.Equal(v.signer, entry.SignerKey) && !announcesKey(&entry) {
+		return v.fail(entry.ID, "signer changed without a key.installed entry")
+	}
+
+	v.prevHash = entry.Hash
+	v.signer = entry.SignerKey
+	return nil
+}
+
+func (v *auditVerifier) fail(id int64, problem string) error {
+	v.report.BrokenAt = id
+	v.report.Problem = problem
+	return errStopIteration
+}
+
+func (v *auditVerifier) result() *AuditVerification {
+	report := v.report
+	report.Head = v.prevHash
+	if v.signer != nil {
+		report.SignerKey = hex.EncodeToString(v.signer)
+	}
+	if report.Problem == "" && v.signer != nil && v.trusted != nil && !bytes.Equal(v.signer, v.trusted) {
+		report.Problem = "last entry is not signed by the trusted key"
+	}
+	report.Valid = report.Problem == ""
+	return &report
+}
+
+// announcesKey reports whether an entry is a key.installed entry for the
+// key that signed it
+func announcesKey(entry *AuditEntry) bool {
+	if entry.Action != AuditKeyInstalled {
+		return false
+	}
+	var data KeyAudit
+	if err := json.Unmarshal(entry.Data, &data); err != nil {
+		return false
+	}
+	return data.SigningKey == hex.EncodeToString(entry.SignerKey)
+}
+
+// String summarizes the verification for operators
+func (v *AuditVerification) String() string {
+	if v.Valid {
+		return fmt.Sprintf("audit log intact: %d entries (%d before the chain), head %s", v.Entries, v.Unchained, v.Head)
+	}
+	if v.BrokenAt != 0 {
+		return fmt.Sprintf("audit log broken at entry %d: %s", v.BrokenAt, v.Problem)
+	}
+	return "audit log invalid: " + v.Problem
+}
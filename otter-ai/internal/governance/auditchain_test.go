@@ -0,0 +1,128 @@
+package governance
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"otter-ai/internal/memory"
+	"otter-ai/internal/vectordb"
+)
+
+func TestVerifyAudit_DetectsTampering(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	ctx := context.Background()
+	for _, subject := range []string{"r1", "r2", "r3"} {
+		g.recordAudit(AuditRuleAdopted, "otter-1", subject, "otter-1", nil)
+	}
+
+	report, err := g.VerifyAudit(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Valid || report.Entries != 3 || report.Head != g.audit.entries[2].Hash {
+		t.Fatalf("report = %+v", report)
+	}
+	if g.audit.entries[1].PrevHash != g.audit.entries[0].Hash {
+		t.Error("entries are not chained")
+	}
+
+	g.audit.entries[1].SubjectID = "forged"
+	report, _ = g.VerifyAudit(ctx)
+	if report.Valid || report.BrokenAt != 2 || report.Problem != "entry does not match its hash" {
+		t.Errorf("tampered report = %+v", report)
+	}
+
+	// Rehashing the forged entry breaks its signature instead
+	g.audit.entries[1].Hash = auditEntryHash(&g.audit.entries[1])
+	report, _ = g.VerifyAudit(ctx)
+	if report.Valid || report.BrokenAt != 2 || report.Problem != "entry signature is invalid" {
+		t.Errorf("rehashed report = %+v", report)
+	}
+}
+
+func TestVerifyAuditLog_Database(t *testing.T) {
+	dir := t.TempDir()
+	db, err := vectordb.NewSQLiteVectorDB(filepath.Join(dir, "otter.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	// An entry written before the chain existed
+	if _, err := db.GetDB().Exec(`INSERT INTO governance_audit (timestamp, action, raft_id, subject_id) VALUES (?, ?, ?, ?)`,
+		time.Now().UnixNano(), string(AuditMemberJoined), "otter-1", "otter-0"); err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := New(RaftConfig{ID: "otter-1", DataDir: dir}, memory.New(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Shutdown(ctx)
+	g.recordAudit(AuditVoteCast, "otter-1", "p1", "otter-1", nil)
+	g.recordAudit(AuditVoteCast, "otter-1", "p2", "otter-1", nil)
+	key := g.crypto.GetSigningPublicKey()
+
+	report, err := VerifyAuditLog(ctx, db.GetDB(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Valid || report.Unchained != 1 || report.Entries < 3 {
+		t.Fatalf("report = %+v", report)
+	}
+
+	// Deleting an entry from the middle breaks the next one's link
+	if _, err := db.GetDB().Exec(`DELETE FROM governance_audit WHERE subject_id = 'p1'`); err != nil {
+		t.Fatal(err)
+	}
+	report, _ = VerifyAuditLog(ctx, db.GetDB(), key)
+	if report.Valid || report.Problem != "entry does not link to the previous entry" {
+		t.Errorf("report after deletion = %+v", report)
+	}
+}
+
+func TestVerifyAudit_KeyInstalled(t *testing.T) {
+	dir := t.TempDir()
+	db, err := vectordb.NewSQLiteVectorDB(filepath.Join(dir, "otter.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	g, err := New(RaftConfig{ID: "otter-1", DataDir: dir}, memory.New(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldKey := g.crypto.GetSigningPublicKey()
+	g.Shutdown(ctx)
+
+	// A new key is announced in the log on the next start
+	if _, err := RegenerateKeys(dir); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := New(RaftConfig{ID: "otter-1", DataDir: dir}, memory.New(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reloaded.Shutdown(ctx)
+
+	actions := auditActions(t, reloaded, AuditFilter{})
+	if actions[len(actions)-1] != AuditKeyInstalled {
+		t.Errorf("last action = %s, want %s", actions[len(actions)-1], AuditKeyInstalled)
+	}
+	report, err := reloaded.VerifyAudit(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Valid {
+		t.Errorf("report = %+v", report)
+	}
+
+	if report, _ := VerifyAuditLog(ctx, db.GetDB(), oldKey); report.Valid {
+		t.Error("expected the retired key not to be trusted for the log's head")
+	}
+}
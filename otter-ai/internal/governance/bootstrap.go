@@ -0,0 +1,130 @@
+package governance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultBootstrapFile is the bootstrap rules file looked for in the data
+// directory when none is configured
+const DefaultBootstrapFile = "bootstrap.yaml"
+
+// AuditActorBootstrap proposes bootstrap rules, so their adoption is
+// audited as a bootstrap adoption rather than a vote
+const AuditActorBootstrap = "bootstrap"
+
+// BootstrapRule is a foundational rule adopted without a vote when a fresh
+// otter initializes its solo raft
+type BootstrapRule struct {
+	Scope string `yaml:"scope" json:"scope"`
+	Body  string `yaml:"body" json:"body"`
+}
+
+// bootstrapFile is the layout of a bootstrap.yaml or its JSON equivalent
+type bootstrapFile struct {
+	Rules []BootstrapRule `yaml:"rules" json:"rules"`
+}
+
+// LoadBootstrapRules reads bootstrap rules from a YAML file of the form
+//
+//	rules:
+//	  - scope: conduct
+//	    body: Treat every member with respect.
+//
+// or, when the file name ends in .json, the same layout as JSON
+func LoadBootstrapRules(path string) ([]BootstrapRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bootstrap rules: %w", err)
+	}
+	var file bootstrapFile
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &file)
+	} else {
+		err = yaml.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse bootstrap rules: %w", err)
+	}
+	if err := ValidateBootstrapRules(file.Rules); err != nil {
+		return nil, err
+	}
+	return file.Rules, nil
+}
+
+// ValidateBootstrapRules checks every rule has a valid scope and a body,
+// that voting, metadata and charter rules and rule effects parse, and that
+// no scope is given two rules
+func ValidateBootstrapRules(rules []BootstrapRule) error {
+	scopes := make(map[string]bool, len(rules))
+	for i, rule := range rules {
+		if strings.TrimSpace(rule.Scope) == "" || strings.TrimSpace(rule.Body) == "" {
+			return fmt.Errorf("bootstrap rule %d needs a scope and a body", i+1)
+		}
+		if err := ValidateScope(rule.Scope); err != nil {
+			return fmt.Errorf("bootstrap rule %d: %w", i+1, err)
+		}
+		var err error
+		switch rule.Scope {
+		case VotingScope:
+			_, err = ParseVotingPolicy(rule.Body)
+		case MetadataScope:
+			_, err = ParseRaftMetadata(rule.Body)
+		case CharterScope:
+			_, err = ParseCharter(rule.Body)
+		}
+		if err == nil {
+			_, err = ParseRuleEffects(rule.Body)
+		}
+		if err != nil {
+			return fmt.Errorf("bootstrap rule %d: %w", i+1, err)
+		}
+		if scopes[rule.Scope] {
+			return fmt.Errorf("bootstrap scope %q has more than one rule", rule.Scope)
+		}
+		scopes[rule.Scope] = true
+	}
+	return nil
+}
+
+// isFresh reports whether this otter has never persisted its solo raft.
+// Without a database nothing survives a restart, so every start is fresh.
+func (g *Governance) isFresh() bool {
+	db := g.getDB()
+	if db == nil {
+		return true
+	}
+	var count int
+	err := db.QueryRowContext(context.Background(), `SELECT COUNT(*) FROM governance_rafts WHERE raft_id = ?`, g.config.ID).Scan(&count)
+	return err == nil && count == 0
+}
+
+// adoptBootstrapRules adopts the configured bootstrap rules in the solo
+// raft. Each is signed by this otter like any other rule.
+func (g *Governance) adoptBootstrapRules(rules []BootstrapRule) error {
+	for _, bootstrap := range rules {
+		now := time.Now()
+		rule := &Rule{
+			RaftID:     g.config.ID,
+			Scope:      bootstrap.Scope,
+			Version:    1,
+			Timestamp:  now,
+			Body:       strings.TrimSpace(bootstrap.Body),
+			ProposedBy: AuditActorBootstrap,
+			AdoptedAt:  &now,
+		}
+		rule.RuleID = generateID(rule)
+		if err := g.signRule(rule); err != nil {
+			return err
+		}
+		g.activateRule(rule)
+	}
+	return nil
+}
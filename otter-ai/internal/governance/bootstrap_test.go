@@ -0,0 +1,101 @@
+package governance
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"otter-ai/internal/memory"
+	"otter-ai/internal/vectordb"
+)
+
+func TestBootstrapRules_AdoptedOnceOnFreshOtter(t *testing.T) {
+	dir := t.TempDir()
+	db, err := vectordb.NewSQLiteVectorDB(filepath.Join(dir, "otter.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	cfg := RaftConfig{ID: "otter-1", DataDir: dir, BootstrapRules: []BootstrapRule{
+		{Scope: "conduct", Body: "Treat every member with respect."},
+		{Scope: "privacy", Body: "Never share a member's private data."},
+	}}
+	ctx := context.Background()
+
+	g, err := New(cfg, memory.New(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := g.GetActiveRulesForRaft("otter-1")
+	if len(rules) != 2 || rules["conduct"] == nil {
+		t.Fatalf("expected both bootstrap rules to be active, got %+v", rules)
+	}
+	if err := verifyRule(rules["conduct"]); err != nil || rules["conduct"].SignedBy != "otter-1" {
+		t.Errorf("bootstrap rule should be signed by the otter: %v", err)
+	}
+
+	var adoptions int
+	g.EachAuditEntry(ctx, AuditFilter{}, func(entry AuditEntry) error {
+		if entry.Action == AuditRuleAdopted && entry.Actor == AuditActorBootstrap {
+			adoptions++
+		}
+		return nil
+	})
+	if adoptions != 2 {
+		t.Errorf("expected 2 bootstrap adoptions in the audit log, got %d", adoptions)
+	}
+	g.Shutdown(ctx)
+
+	// A restart keeps the rules without adopting them again
+	g, err = New(cfg, memory.New(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Shutdown(ctx)
+	if rules := g.GetActiveRulesForRaft("otter-1"); len(rules) != 2 {
+		t.Errorf("bootstrap rules should survive a restart, got %+v", rules)
+	}
+	adoptions = 0
+	g.EachAuditEntry(ctx, AuditFilter{}, func(entry AuditEntry) error {
+		if entry.Action == AuditRuleAdopted {
+			adoptions++
+		}
+		return nil
+	})
+	if adoptions != 2 {
+		t.Errorf("bootstrap rules were adopted again: %d adoptions", adoptions)
+	}
+}
+
+func TestLoadBootstrapRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bootstrap.yaml")
+	os.WriteFile(path, []byte("rules:\n  - scope: conduct\n    body: Be kind.\n  - scope: tone\n    body: |\n      Keep replies\n      short.\n"), 0o600)
+	rules, err := LoadBootstrapRules(path)
+	if err != nil || len(rules) != 2 || rules[1].Body != "Keep replies\nshort.\n" {
+		t.Fatalf("LoadBootstrapRules = %+v, %v", rules, err)
+	}
+
+	for _, bad := range []string{
+		"rules:\n  - scope: conduct\n",
+		"rules:\n  - scope: a\n    body: x\n  - scope: a\n    body: y\n",
+		"rules: [",
+		"rules:\n  - scope: communication//discord\n    body: x\n",
+	} {
+		os.WriteFile(path, []byte(bad), 0o600)
+		if _, err := LoadBootstrapRules(path); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+
+	jsonPath := filepath.Join(t.TempDir(), "constitution.json")
+	os.WriteFile(jsonPath, []byte(`{"rules":[{"scope":"conduct","body":"Be kind."},{"scope":"communication/*","body":"Be brief."}]}`), 0o600)
+	rules, err = LoadBootstrapRules(jsonPath)
+	if err != nil || len(rules) != 2 || rules[1].Scope != "communication/*" {
+		t.Fatalf("LoadBootstrapRules(json) = %+v, %v", rules, err)
+	}
+	os.WriteFile(jsonPath, []byte("rules:\n  - scope: conduct\n    body: Be kind.\n"), 0o600)
+	if _, err := LoadBootstrapRules(jsonPath); err == nil {
+		t.Error("expected a .json file to be parsed as JSON")
+	}
+}
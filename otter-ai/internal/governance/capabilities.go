@@ -0,0 +1,214 @@
+package governance
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"otter-ai/internal/errs"
+)
+
+// Federation protocol versions. Peers speak the highest version both
+// support, which must be at least each side's minimum.
+const (
+	ProtocolVersion    = 1
+	MinProtocolVersion = 1
+)
+
+// Crypto suites an otter can use with its peers
+const (
+	CryptoSuiteEd25519    = "ed25519"      // Signatures on rules, votes, members and envelopes
+	CryptoSuiteECDHP256   = "ecdh-p256"    // Key agreement for encrypted exchanges
+	CryptoSuiteAES256GCM  = "aes-256-gcm"  // Symmetric encryption with the agreed key
+	CapabilityMaxAge      = EnvelopeMaxAge // Older descriptors are treated as replays
+	capabilityListMaxSize = 64
+)
+
+// ErrIncompatiblePeer is returned when two otters share no usable protocol
+// version or crypto suite
+var ErrIncompatiblePeer = errs.New(errs.ErrConflict, "peer is incompatible")
+
+// Capabilities lists the protocol versions, crypto suites and federation
+// message types an otter supports, or that two otters agreed on
+type Capabilities struct {
+	ProtocolVersion    int      `json:"protocol_version"`
+	MinProtocolVersion int      `json:"min_protocol_version"`
+	CryptoSuites       []string `json:"crypto_suites"`
+	MessageTypes       []string `json:"message_types"`
+}
+
+// Supports reports whether a federation message type is in the set
+func (c *Capabilities) Supports(msgType string) bool {
+	return hasString(c.MessageTypes, msgType)
+}
+
+// Supports reports whether a federation message type can be sent to the
+// member. Members without negotiated capabilities are assumed to be legacy.
+func (m *Member) Supports(msgType string) bool {
+	if m.Capabilities == nil {
+		legacy := LegacyCapabilities()
+		return legacy.Supports(msgType)
+	}
+	return m.Capabilities.Supports(msgType)
+}
+
+// CapabilityDescriptor is an otter's capabilities signed with its Ed25519
+// signing key, exchanged when otters connect
+type CapabilityDescriptor struct {
+	OtterID      string       `json:"otter_id"`
+	Capabilities Capabilities `json:"capabilities"`
+	SigningKey   []byte       `json:"signing_key"`
+	Timestamp    time.Time    `json:"timestamp"`
+	Signature    []byte       `json:"signature"`
+}
+
+// LocalCapabilities returns what this otter supports
+func LocalCapabilities() Capabilities {
+	return Capabilities{
+		ProtocolVersion:    ProtocolVersion,
+		MinProtocolVersion: MinProtocolVersion,
+		CryptoSuites:       []string{CryptoSuiteEd25519, CryptoSuiteECDHP256, CryptoSuiteAES256GCM},
+		MessageTypes:       supportedMessageTypes(),
+	}
+}
+
+// LegacyCapabilities is assumed for peers that predate the handshake: the
+// first protocol version with the messages it shipped with
+func LegacyCapabilities() Capabilities {
+	return Capabilities{
+		ProtocolVersion:    1,
+		MinProtocolVersion: 1,
+		CryptoSuites:       []string{CryptoSuiteEd25519, CryptoSuiteECDHP256, CryptoSuiteAES256GCM},
+		MessageTypes:       []string{MessageMemberRevoked},
+	}
+}
+
+// capabilitySigningPayload returns the bytes a descriptor signature covers
+func capabilitySigningPayload(d *CapabilityDescriptor) []byte {
+	return canonicalPayload(
+		"capabilities",
+		d.OtterID,
+		strconv.Itoa(d.Capabilities.ProtocolVersion),
+		strconv.Itoa(d.Capabilities.MinProtocolVersion),
+		strings.Join(d.Capabilities.CryptoSuites, ","),
+		strings.Join(d.Capabilities.MessageTypes, ","),
+		string(d.SigningKey),
+		strconv.FormatInt(d.Timestamp.Unix(), 10),
+	)
+}
+
+// CapabilityDescriptor returns this otter's signed capability descriptor
+func (g *Governance) CapabilityDescriptor() (*CapabilityDescriptor, error) {
+	d := &CapabilityDescriptor{
+		OtterID:      g.config.ID,
+		Capabilities: LocalCapabilities(),
+		SigningKey:   g.crypto.GetSigningPublicKey(),
+		Timestamp:    time.Now(),
+	}
+	sig, err := g.crypto.Sign(capabilitySigningPayload(d))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign capabilities: %w", err)
+	}
+	d.Signature = sig
+	return d, nil
+}
+
+// VerifyCapabilityDescriptor checks a peer's descriptor is recent, well
+// formed and signed by the key it carries. Callers that already know the
+// peer's signing key must also check it matches.
+func VerifyCapabilityDescriptor(d *CapabilityDescriptor) error {
+	if d.OtterID == "" {
+		return fmt.Errorf("capability descriptor is missing the otter ID")
+	}
+	if len(d.Signature) == 0 {
+		return fmt.Errorf("%w: capabilities of %s", ErrUnsigned, d.OtterID)
+	}
+	if len(d.SigningKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("capability descriptor of %s has an invalid signing key", d.OtterID)
+	}
+	if len(d.Capabilities.CryptoSuites) > capabilityListMaxSize || len(d.Capabilities.MessageTypes) > capabilityListMaxSize {
+		return fmt.Errorf("capability descriptor of %s is too large", d.OtterID)
+	}
+
+	now := time.Now()
+	if d.Timestamp.After(now.Add(VoteClockSkew)) || d.Timestamp.Before(now.Add(-CapabilityMaxAge)) {
+		return fmt.Errorf("capability descriptor of %s is outside the accepted time window", d.OtterID)
+	}
+	if !VerifySignature(capabilitySigningPayload(d), d.Signature, d.SigningKey) {
+		return fmt.Errorf("%w: capabilities of %s", ErrInvalidSignature, d.OtterID)
+	}
+	return nil
+}
+
+// NegotiateCapabilities returns the subset two otters can both use: the
+// highest common protocol version and the crypto suites and message types
+// both support
+func NegotiateCapabilities(local, remote Capabilities) (Capabilities, error) {
+	version := local.ProtocolVersion
+	if remote.ProtocolVersion < version {
+		version = remote.ProtocolVersion
+	}
+	if version < local.MinProtocolVersion || version < remote.MinProtocolVersion {
+		return Capabilities{}, fmt.Errorf("%w: no common protocol version (local %d-%d, peer %d-%d)", ErrIncompatiblePeer,
+			local.MinProtocolVersion, local.ProtocolVersion, remote.MinProtocolVersion, remote.ProtocolVersion)
+	}
+
+	agreed := Capabilities{
+		ProtocolVersion:    version,
+		MinProtocolVersion: version,
+		CryptoSuites:       intersectStrings(local.CryptoSuites, remote.CryptoSuites),
+		MessageTypes:       intersectStrings(local.MessageTypes, remote.MessageTypes),
+	}
+	if !hasString(agreed.CryptoSuites, CryptoSuiteEd25519) {
+		return Capabilities{}, fmt.Errorf("%w: peer does not support %s signatures", ErrIncompatiblePeer, CryptoSuiteEd25519)
+	}
+	return agreed, nil
+}
+
+// negotiatePeer verifies a peer's descriptor and negotiates with it. Peers
+// that send no descriptor predate the handshake and get LegacyCapabilities.
+func negotiatePeer(d *CapabilityDescriptor, peerID string, signingKey []byte) (*Capabilities, error) {
+	remote := LegacyCapabilities()
+	if d != nil {
+		if err := VerifyCapabilityDescriptor(d); err != nil {
+			return nil, err
+		}
+		if d.OtterID != peerID {
+			return nil, fmt.Errorf("capability descriptor is for %s, not %s", d.OtterID, peerID)
+		}
+		if len(signingKey) > 0 && string(signingKey) != string(d.SigningKey) {
+			return nil, fmt.Errorf("%w: capabilities of %s are signed with another key", ErrInvalidSignature, peerID)
+		}
+		remote = d.Capabilities
+	}
+
+	agreed, err := NegotiateCapabilities(LocalCapabilities(), remote)
+	if err != nil {
+		return nil, err
+	}
+	return &agreed, nil
+}
+
+// intersectStrings returns the values present in both lists, sorted
+func intersectStrings(a, b []string) []string {
+	common := []string{}
+	for _, v := range a {
+		if hasString(b, v) && !hasString(common, v) {
+			common = append(common, v)
+		}
+	}
+	sort.Strings(common)
+	return common
+}
+
+func hasString(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,193 @@
+package governance
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCapabilityDescriptor_SignAndVerify(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	d, err := g.CapabilityDescriptor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Capabilities.ProtocolVersion != ProtocolVersion || !d.Capabilities.Supports(MessageMemberRevoked) {
+		t.Errorf("unexpected capabilities %+v", d.Capabilities)
+	}
+	if err := VerifyCapabilityDescriptor(d); err != nil {
+		t.Fatalf("VerifyCapabilityDescriptor: %v", err)
+	}
+
+	// Survives a JSON round trip, as it does over the wire
+	data, _ := json.Marshal(d)
+	var decoded CapabilityDescriptor
+	json.Unmarshal(data, &decoded)
+	if err := VerifyCapabilityDescriptor(&decoded); err != nil {
+		t.Fatalf("after round trip: %v", err)
+	}
+
+	decoded.Capabilities.ProtocolVersion = 99
+	if err := VerifyCapabilityDescriptor(&decoded); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature after tampering, got %v", err)
+	}
+
+	stale := *d
+	stale.Timestamp = time.Now().Add(-2 * CapabilityMaxAge)
+	if err := VerifyCapabilityDescriptor(&stale); err == nil {
+		t.Error("stale descriptor should be rejected")
+	}
+
+	unsigned := *d
+	unsigned.Signature = nil
+	if err := VerifyCapabilityDescriptor(&unsigned); !errors.Is(err, ErrUnsigned) {
+		t.Errorf("expected ErrUnsigned, got %v", err)
+	}
+}
+
+func TestNegotiateCapabilities(t *testing.T) {
+	local := Capabilities{
+		ProtocolVersion: 3, MinProtocolVersion: 1,
+		CryptoSuites: []string{CryptoSuiteEd25519, CryptoSuiteECDHP256},
+		MessageTypes: []string{"a", "b", "c"},
+	}
+	remote := Capabilities{
+		ProtocolVersion: 2, MinProtocolVersion: 2,
+		CryptoSuites: []string{CryptoSuiteECDHP256, CryptoSuiteEd25519, "kyber"},
+		MessageTypes: []string{"c", "b", "d"},
+	}
+
+	agreed, err := NegotiateCapabilities(local, remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if agreed.ProtocolVersion != 2 {
+		t.Errorf("version = %d, want 2", agreed.ProtocolVersion)
+	}
+	if len(agreed.CryptoSuites) != 2 || agreed.Supports("a") || !agreed.Supports("b") || !agreed.Supports("c") {
+		t.Errorf("unexpected agreement %+v", agreed)
+	}
+
+	remote.MinProtocolVersion, remote.ProtocolVersion = 4, 5
+	if _, err := NegotiateCapabilities(local, remote); !errors.Is(err, ErrIncompatiblePeer) {
+		t.Errorf("expected ErrIncompatiblePeer for disjoint versions, got %v", err)
+	}
+
+	remote = LegacyCapabilities()
+	remote.CryptoSuites = []string{"rsa"}
+	if _, err := NegotiateCapabilities(local, remote); !errors.Is(err, ErrIncompatiblePeer) {
+		t.Errorf("expected ErrIncompatiblePeer without ed25519, got %v", err)
+	}
+}
+
+func TestRequestJoin_NegotiatesCapabilities(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	peer := newTestGovernance("otter-2")
+	d, _ := peer.CapabilityDescriptor()
+
+	req := answeredJoinRequest(t, g, peer, "otter-1")
+	req.Descriptor = d
+	resp, err := g.RequestJoin(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Descriptor == nil || VerifyCapabilityDescriptor(resp.Descriptor) != nil {
+		t.Error("response should carry a valid descriptor")
+	}
+	members, _ := g.GetRaftMembers("otter-1")
+	for _, m := range members {
+		if m.ID == "otter-2" && (m.Capabilities == nil || m.Capabilities.ProtocolVersion != ProtocolVersion) {
+			t.Errorf("negotiated capabilities not recorded: %+v", m.Capabilities)
+		}
+	}
+
+	// A descriptor signed with a key other than the requester's
+	other := newTestGovernance("otter-3")
+	forged, _ := other.CapabilityDescriptor()
+	forged.OtterID = "otter-2"
+	_, err = g.RequestJoin(context.Background(), JoinRequest{
+		RaftID: "otter-1", RequesterID: "otter-2", PublicKey: []byte("pubkey"),
+		SigningKey: peer.crypto.GetSigningPublicKey(), Descriptor: forged,
+	})
+	if !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func TestRequestJoin_RejectsIncompatiblePeer(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	peer := newTestGovernance("otter-2")
+
+	d := &CapabilityDescriptor{
+		OtterID: "otter-2",
+		Capabilities: Capabilities{
+			ProtocolVersion: ProtocolVersion + 2, MinProtocolVersion: ProtocolVersion + 1,
+			CryptoSuites: []string{CryptoSuiteEd25519},
+		},
+		SigningKey: peer.crypto.GetSigningPublicKey(),
+		Timestamp:  time.Now(),
+	}
+	d.Signature, _ = peer.crypto.Sign(capabilitySigningPayload(d))
+
+	_, err := g.RequestJoin(context.Background(), JoinRequest{
+		RaftID: "otter-1", RequesterID: "otter-2", PublicKey: []byte("pubkey"), Descriptor: d,
+	})
+	if !errors.Is(err, ErrIncompatiblePeer) {
+		t.Fatalf("expected ErrIncompatiblePeer, got %v", err)
+	}
+	members, _ := g.GetRaftMembers("otter-1")
+	for _, m := range members {
+		if m.ID == "otter-2" {
+			t.Error("incompatible peer should not be inducted")
+		}
+	}
+}
+
+func TestParseJoinResponse_VerifiesCapabilities(t *testing.T) {
+	peer := newTestGovernance("peer")
+	d, _ := peer.CapabilityDescriptor()
+	body, _ := json.Marshal(map[string]interface{}{
+		"member_id":    "peer",
+		"signing_key":  hex.EncodeToString(peer.crypto.GetSigningPublicKey()),
+		"capabilities": d,
+	})
+
+	member, err := parseJoinResponse(body, "http://peer:8080")
+	if err != nil || member.Capabilities == nil {
+		t.Fatalf("member = %+v, err = %v", member, err)
+	}
+
+	other := newTestGovernance("other")
+	body, _ = json.Marshal(map[string]interface{}{
+		"member_id":    "peer",
+		"signing_key":  hex.EncodeToString(other.crypto.GetSigningPublicKey()),
+		"capabilities": d,
+	})
+	if _, err := parseJoinResponse(body, "http://peer:8080"); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature for a mismatched key, got %v", err)
+	}
+}
+
+func TestBroadcast_SkipsUnsupportedMessageTypes(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	transport := newRecordingTransport()
+	g.SetTransport(transport)
+
+	now := time.Now()
+	g.rafts.rafts["otter-1"].Members["modern"] = &Member{ID: "modern", State: StateActive, JoinedAt: now, Endpoint: "http://modern",
+		Capabilities: &Capabilities{ProtocolVersion: 1, MessageTypes: []string{MessageMemberRevoked, "future.message"}}}
+	g.rafts.rafts["otter-1"].Members["legacy"] = &Member{ID: "legacy", State: StateActive, JoinedAt: now, Endpoint: "http://legacy"}
+
+	g.broadcast(context.Background(), "otter-1", "future.message", map[string]string{})
+	if len(transport.sent["http://modern"]) != 1 || len(transport.sent["http://legacy"]) != 0 {
+		t.Errorf("future.message sent to %v", transport.sent)
+	}
+
+	g.broadcast(context.Background(), "otter-1", MessageMemberRevoked, map[string]string{})
+	if len(transport.sent["http://legacy"]) != 1 {
+		t.Errorf("legacy peers should still get %s", MessageMemberRevoked)
+	}
+}
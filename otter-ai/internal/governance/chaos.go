@@ -0,0 +1,226 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"otter-ai/internal/errs"
+)
+
+// ChaosReorderDelay is how long a reordered delivery is held back, on top
+// of any configured delay, so later deliveries overtake it
+const ChaosReorderDelay = 250 * time.Millisecond
+
+var (
+	// ErrChaosDisabled is returned when changing faults on an otter not
+	// started in chaos mode
+	ErrChaosDisabled = errs.New(errs.ErrUnavailable, "chaos mode is disabled")
+	// ErrChaosDropped is returned for a delivery chaos mode dropped
+	ErrChaosDropped = errors.New("delivery dropped by chaos mode")
+	// ErrChaosPartitioned is returned for deliveries to, and envelopes from,
+	// a peer partitioned away by chaos mode
+	ErrChaosPartitioned = errors.New("peer partitioned by chaos mode")
+)
+
+// ChaosConfig injects network faults into federation deliveries, to check
+// that proposals, votes and negotiations converge over unreliable links.
+// The zero ChaosConfig injects nothing.
+type ChaosConfig struct {
+	DropRate      float64       // Probability a delivery fails
+	DuplicateRate float64       // Probability a delivery is sent twice
+	ReorderRate   float64       // Probability a delivery is held back so later ones overtake it
+	MinDelay      time.Duration // Latency added to every delivery is drawn from [MinDelay, MaxDelay]
+	MaxDelay      time.Duration
+	Partitioned   []string // Member IDs or endpoints cut off in both directions
+	Seed          int64    // Seeds fault decisions for reproducible runs; zero uses the clock
+}
+
+// Validate checks rates are probabilities and delays are ordered
+func (c ChaosConfig) Validate() error {
+	for name, rate := range map[string]float64{"drop": c.DropRate, "duplicate": c.DuplicateRate, "reorder": c.ReorderRate} {
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("chaos %s rate must be between 0 and 1", name)
+		}
+	}
+	if c.MinDelay < 0 || c.MaxDelay < c.MinDelay {
+		return fmt.Errorf("chaos delays must satisfy 0 <= min delay <= max delay")
+	}
+	return nil
+}
+
+// ChaosStats counts the faults injected since chaos mode was configured
+type ChaosStats struct {
+	Deliveries  int64 `json:"deliveries"`
+	Dropped     int64 `json:"dropped"`
+	Duplicated  int64 `json:"duplicated"`
+	Reordered   int64 `json:"reordered"`
+	Partitioned int64 `json:"partitioned"` // Deliveries and received envelopes refused
+}
+
+// ChaosStatus reports the faults being injected
+type ChaosStatus struct {
+	Config ChaosConfig
+	Stats  ChaosStats
+}
+
+// chaosState holds the active faults and their counters
+type chaosState struct {
+	mu     sync.Mutex
+	config ChaosConfig
+	rng    *rand.Rand
+	cut    map[string]bool // Partitioned member IDs and endpoints
+
+	deliveries, dropped, duplicated, reordered, partitioned atomic.Int64
+}
+
+func newChaosState(config ChaosConfig) *chaosState {
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	cut := make(map[string]bool, len(config.Partitioned))
+	for _, peer := range config.Partitioned {
+		cut[peer] = true
+	}
+	return &chaosState{config: config, rng: rand.New(rand.NewSource(seed)), cut: cut}
+}
+
+// roll reports whether an event with the given probability happens
+func (c *chaosState) roll(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rng.Float64() < rate
+}
+
+// delay draws the latency added to one delivery
+func (c *chaosState) delay() time.Duration {
+	spread := c.config.MaxDelay - c.config.MinDelay
+	if spread <= 0 {
+		return c.config.MinDelay
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.config.MinDelay + time.Duration(c.rng.Int63n(int64(spread)+1))
+}
+
+// SetChaos replaces the faults injected into federation deliveries. It is
+// refused with ErrChaosDisabled unless the otter was started in chaos mode.
+func (g *Governance) SetChaos(config ChaosConfig) error {
+	if g.config.Chaos == nil {
+		return ErrChaosDisabled
+	}
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	g.chaos.Store(newChaosState(config))
+	return nil
+}
+
+// ChaosStatus returns the faults being injected, or ErrChaosDisabled
+func (g *Governance) ChaosStatus() (*ChaosStatus, error) {
+	chaos := g.chaos.Load()
+	if chaos == nil {
+		return nil, ErrChaosDisabled
+	}
+	return &ChaosStatus{
+		Config: chaos.config,
+		Stats: ChaosStats{
+			Deliveries:  chaos.deliveries.Load(),
+			Dropped:     chaos.dropped.Load(),
+			Duplicated:  chaos.duplicated.Load(),
+			Reordered:   chaos.reordered.Load(),
+			Partitioned: chaos.partitioned.Load(),
+		},
+	}, nil
+}
+
+// chaosPartitioned reports whether chaos mode cut a member off
+func (g *Governance) chaosPartitioned(memberID string) bool {
+	chaos := g.chaos.Load()
+	if chaos == nil || !chaos.cut[memberID] {
+		return false
+	}
+	chaos.partitioned.Add(1)
+	return true
+}
+
+// chaosTransport injects the configured faults into another transport
+type chaosTransport struct {
+	chaos *chaosState
+	next  Transport
+	g     *Governance
+}
+
+func (t *chaosTransport) Send(ctx context.Context, endpoint string, env *Envelope) error {
+	chaos := t.chaos
+	chaos.deliveries.Add(1)
+
+	if chaos.cut[endpoint] || t.endpointCut(endpoint) {
+		chaos.partitioned.Add(1)
+		return fmt.Errorf("%w: %s", ErrChaosPartitioned, endpoint)
+	}
+	if chaos.roll(chaos.config.DropRate) {
+		chaos.dropped.Add(1)
+		return fmt.Errorf("%w: %s to %s", ErrChaosDropped, env.Type, endpoint)
+	}
+
+	delay := chaos.delay()
+	if chaos.roll(chaos.config.ReorderRate) {
+		// Deliver in the background after later sends have gone out
+		chaos.reordered.Add(1)
+		go func() {
+			time.Sleep(delay + ChaosReorderDelay)
+			ctx, cancel := context.WithTimeout(context.Background(), GovernanceHTTPTimeout)
+			defer cancel()
+			if err := t.next.Send(ctx, endpoint, env); err != nil {
+				slog.Warn("reordered delivery failed", "message_type", env.Type, "endpoint", endpoint, "error", err)
+			}
+		}()
+		return nil
+	}
+
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if err := t.next.Send(ctx, endpoint, env); err != nil {
+		return err
+	}
+	if chaos.roll(chaos.config.DuplicateRate) {
+		chaos.duplicated.Add(1)
+		t.next.Send(ctx, endpoint, env)
+	}
+	return nil
+}
+
+// endpointCut reports whether the endpoint belongs to a partitioned member
+func (t *chaosTransport) endpointCut(endpoint string) bool {
+	if len(t.chaos.cut) == 0 {
+		return false
+	}
+	t.g.rafts.mu.RLock()
+	defer t.g.rafts.mu.RUnlock()
+	for _, raft := range t.g.rafts.rafts {
+		raft.mu.RLock()
+		for id, member := range raft.Members {
+			if member.Endpoint == endpoint && t.chaos.cut[id] {
+				raft.mu.RUnlock()
+				return true
+			}
+		}
+		raft.mu.RUnlock()
+	}
+	return false
+}
@@ -0,0 +1,166 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// newChaosGovernance returns a governance in chaos mode whose deliveries
+// are recorded
+func newChaosGovernance(t *testing.T, config ChaosConfig) (*Governance, *recordingTransport) {
+	t.Helper()
+	g := newTestGovernance("otter-1")
+	g.config.Chaos = &config
+	if err := g.SetChaos(config); err != nil {
+		t.Fatal(err)
+	}
+	transport := newRecordingTransport()
+	g.SetTransport(transport)
+	return g, transport
+}
+
+func TestSetChaos_DisabledWithoutChaosMode(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	if err := g.SetChaos(ChaosConfig{DropRate: 1}); !errors.Is(err, ErrChaosDisabled) {
+		t.Errorf("SetChaos err = %v, want ErrChaosDisabled", err)
+	}
+	if _, err := g.ChaosStatus(); !errors.Is(err, ErrChaosDisabled) {
+		t.Errorf("ChaosStatus err = %v, want ErrChaosDisabled", err)
+	}
+}
+
+func TestChaosConfig_Validate(t *testing.T) {
+	for _, config := range []ChaosConfig{
+		{DropRate: 1.5},
+		{ReorderRate: -0.1},
+		{MinDelay: time.Second, MaxDelay: time.Millisecond},
+	} {
+		if err := config.Validate(); err == nil {
+			t.Errorf("%+v should be invalid", config)
+		}
+	}
+}
+
+func TestChaos_DropAndDuplicate(t *testing.T) {
+	ctx := context.Background()
+	env := &Envelope{Type: MessageMemberRevoked}
+
+	g, transport := newChaosGovernance(t, ChaosConfig{DropRate: 1})
+	if err := g.federation().Send(ctx, "http://peer", env); !errors.Is(err, ErrChaosDropped) {
+		t.Errorf("Send err = %v, want ErrChaosDropped", err)
+	}
+	if len(transport.sent["http://peer"]) != 0 {
+		t.Error("dropped delivery reached the peer")
+	}
+
+	if err := g.SetChaos(ChaosConfig{DuplicateRate: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.federation().Send(ctx, "http://peer", env); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(transport.sent["http://peer"]); got != 2 {
+		t.Errorf("delivered %d times, want 2", got)
+	}
+	status, _ := g.ChaosStatus()
+	if status.Stats.Deliveries != 1 || status.Stats.Duplicated != 1 {
+		t.Errorf("stats = %+v", status.Stats)
+	}
+}
+
+func TestChaos_Reorder(t *testing.T) {
+	ctx := context.Background()
+	g, transport := newChaosGovernance(t, ChaosConfig{ReorderRate: 1})
+	first, second := &Envelope{Type: "first"}, &Envelope{Type: "second"}
+
+	if err := g.federation().Send(ctx, "http://peer", first); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.SetChaos(ChaosConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.federation().Send(ctx, "http://peer", second); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-transport.done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("reordered delivery never arrived")
+		}
+	}
+
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+	sent := transport.sent["http://peer"]
+	if len(sent) != 2 || sent[0].Type != "second" || sent[1].Type != "first" {
+		t.Errorf("delivery order = %v, want second then first", []string{sent[0].Type, sent[1].Type})
+	}
+}
+
+func TestChaos_Delay(t *testing.T) {
+	g, _ := newChaosGovernance(t, ChaosConfig{MinDelay: 20 * time.Millisecond, MaxDelay: 20 * time.Millisecond})
+	start := time.Now()
+	if err := g.federation().Send(context.Background(), "http://peer", &Envelope{}); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("delivery took %v, want at least 20ms", elapsed)
+	}
+}
+
+func TestChaos_PartitionBothDirections(t *testing.T) {
+	sender, receiver := federatedPair(t)
+	receiver.config.Chaos = &ChaosConfig{}
+	if err := receiver.SetChaos(ChaosConfig{Partitioned: []string{"otter-1"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Envelopes from the partitioned peer are refused
+	mirrorEviction(receiver, "otter-3")
+	env := revocationEnvelope(t, sender, "otter-3", sender, receiver)
+	if err := receiver.HandleEnvelope(context.Background(), env); !errors.Is(err, ErrChaosPartitioned) {
+		t.Errorf("HandleEnvelope err = %v, want ErrChaosPartitioned", err)
+	}
+
+	// Deliveries to it are refused by member ID as well as endpoint
+	receiver.rafts.rafts["otter-1"].Members["otter-1"].Endpoint = "http://otter-1"
+	transport := newRecordingTransport()
+	receiver.SetTransport(transport)
+	if err := receiver.federation().Send(context.Background(), "http://otter-1", env); !errors.Is(err, ErrChaosPartitioned) {
+		t.Errorf("Send err = %v, want ErrChaosPartitioned", err)
+	}
+	if len(transport.sent) != 0 {
+		t.Error("delivery crossed the partition")
+	}
+
+	// Healing the partition lets envelopes through again
+	if err := receiver.SetChaos(ChaosConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := receiver.HandleEnvelope(context.Background(), env); err != nil {
+		t.Errorf("HandleEnvelope after healing: %v", err)
+	}
+}
+
+// Revocations still converge when most deliveries fail, thanks to retries
+func TestChaos_BroadcastRetriesThroughDrops(t *testing.T) {
+	g, transport := newChaosGovernance(t, ChaosConfig{DropRate: 0.5, Seed: 7})
+	g.rafts.rafts["otter-1"].Members["otter-2"] = &Member{ID: "otter-2", State: StateActive, JoinedAt: time.Now(), Endpoint: "http://otter-2"}
+
+	delivered := 0
+	for i := 0; i < 10; i++ {
+		g.broadcast(context.Background(), "otter-1", MessageMemberRevoked, map[string]string{})
+		transport.mu.Lock()
+		delivered = len(transport.sent["http://otter-2"])
+		transport.mu.Unlock()
+		for len(transport.done) > 0 {
+			<-transport.done
+		}
+	}
+	if delivered < 8 {
+		t.Errorf("only %d of 10 broadcasts delivered through 50%% drops with retries", delivered)
+	}
+}
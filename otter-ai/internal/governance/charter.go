@@ -0,0 +1,192 @@
+package governance
+
+import (
+	"strings"
+
+	"otter-ai/internal/errs"
+)
+
+// CharterScope is the scope of the rule that federates a raft under
+// another. Its adopted rule names the parent raft whose active rules the
+// raft inherits, optionally only those in some scopes.
+const CharterScope = "governance/charter"
+
+// governanceScopePrefix starts the scopes of rules that govern a raft
+// itself. They are never inherited: every raft votes on its own policy,
+// metadata and charter.
+const governanceScopePrefix = "governance/"
+
+// Charter is a raft's declared inheritance. The parent's active rules are
+// the raft's baseline; a local rule in the same scope overrides the
+// inherited one. Inherited rules are resolved from the parent's rules this
+// otter knows, so they apply where the otter also sits in the parent raft.
+type Charter struct {
+	ParentRaftID string   `json:"parent_raft_id"`
+	Scopes       []string `json:"scopes,omitempty"` // Scope patterns inherited; empty inherits every rule
+}
+
+// ParseCharter reads a charter rule's body: a line "inherit: <raft-id>"
+// and optionally "scopes: safety/*, conduct". Other lines are free text.
+func ParseCharter(body string) (Charter, error) {
+	var charter Charter
+	for _, line := range strings.Split(body, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), ":")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "inherit", "parent":
+			charter.ParentRaftID = value
+		case "scopes", "scope":
+			for _, scope := range strings.Split(value, ",") {
+				if scope = strings.TrimSpace(scope); scope != "" {
+					charter.Scopes = append(charter.Scopes, scope)
+				}
+			}
+		}
+	}
+	if charter.ParentRaftID == "" {
+		return Charter{}, errs.New(errs.ErrInvalid, "a charter rule must name the raft it inherits from")
+	}
+	for _, scope := range charter.Scopes {
+		if err := ValidateScope(scope); err != nil {
+			return Charter{}, errs.Errorf(errs.ErrInvalid, "charter scope %q: %v", scope, err)
+		}
+	}
+	return charter, nil
+}
+
+// inherits reports whether the charter passes a parent rule's scope down
+func (c Charter) inherits(scope string) bool {
+	if strings.HasPrefix(scope, governanceScopePrefix) {
+		return false
+	}
+	if len(c.Scopes) == 0 {
+		return true
+	}
+	for _, pattern := range c.Scopes {
+		if pattern == scope || ScopeMatches(pattern, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// Charter returns a raft's charter, reporting whether it has one
+func (g *Governance) Charter(raftID string) (Charter, bool) {
+	g.rules.mu.RLock()
+	defer g.rules.mu.RUnlock()
+	return g.charterLocked(raftID)
+}
+
+// charterLocked reads a raft's charter from its active charter rule. The
+// caller holds the rules lock. A rule that does not parse, as from a peer,
+// is no charter.
+func (g *Governance) charterLocked(raftID string) (Charter, bool) {
+	rule, ok := g.rules.active[ruleKey{RaftID: raftID, Scope: CharterScope}]
+	if !ok {
+		return Charter{}, false
+	}
+	charter, err := ParseCharter(rule.Body)
+	if err != nil || charter.ParentRaftID == raftID {
+		return Charter{}, false
+	}
+	return charter, true
+}
+
+// effectiveRulesLocked returns a raft's active rules keyed by scope: its
+// own, then those its charter inherits from the parent's effective rules.
+// Local rules win over inherited ones. seen stops charter cycles. The
+// caller holds the rules lock.
+func (g *Governance) effectiveRulesLocked(raftID string, seen map[string]bool) map[string]*Rule {
+	seen[raftID] = true
+	rules := make(map[string]*Rule)
+	for key, rule := range g.rules.active {
+		if key.RaftID == raftID {
+			rules[key.Scope] = rule
+		}
+	}
+
+	charter, ok := g.charterLocked(raftID)
+	if !ok || seen[charter.ParentRaftID] {
+		return rules
+	}
+	for scope, rule := range g.effectiveRulesLocked(charter.ParentRaftID, seen) {
+		if _, local := rules[scope]; !local && charter.inherits(scope) {
+			rules[scope] = rule
+		}
+	}
+	return rules
+}
+
+// InheritedRules returns the rules a raft inherits through its charter and
+// does not override, keyed by scope
+func (g *Governance) InheritedRules(raftID string) map[string]*Rule {
+	inherited := make(map[string]*Rule)
+	for scope, rule := range g.GetActiveRulesForRaft(raftID) {
+		if rule.RaftID != raftID {
+			inherited[scope] = rule
+		}
+	}
+	return inherited
+}
+
+// inheritedRuleCovering returns the inherited rule a new local rule in
+// scope would override: one in the same scope or a wider one covering it
+func (g *Governance) inheritedRuleCovering(raftID, scope string) *Rule {
+	for _, rule := range g.InheritedRules(raftID) {
+		if rule.Scope == scope || ScopeMatches(rule.Scope, scope) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// validateCharter checks a proposed charter for a raft: it parses, names
+// another raft and does not lead back to the raft through the parent's own
+// charters
+func (g *Governance) validateCharter(raftID, body string) error {
+	charter, err := ParseCharter(body)
+	if err != nil {
+		return err
+	}
+	g.rules.mu.RLock()
+	defer g.rules.mu.RUnlock()
+	seen := map[string]bool{raftID: true}
+	for parent := charter.ParentRaftID; ; {
+		if seen[parent] {
+			return errs.Errorf(errs.ErrInvalid, "charter would make raft %s inherit from itself", raftID)
+		}
+		seen[parent] = true
+		next, ok := g.charterLocked(parent)
+		if !ok {
+			return nil
+		}
+		parent = next.ParentRaftID
+	}
+}
+
+// charters returns the charter of every raft that has one
+func (g *Governance) charters() map[string]Charter {
+	g.rules.mu.RLock()
+	defer g.rules.mu.RUnlock()
+	charters := make(map[string]Charter)
+	for key := range g.rules.active {
+		if key.Scope != CharterScope {
+			continue
+		}
+		if charter, ok := g.charterLocked(key.RaftID); ok {
+			charters[key.RaftID] = charter
+		}
+	}
+	return charters
+}
+
+// settledByCharter reports whether a child raft's rule and its parent's
+// overlapping rule are resolved by the child's charter: the parent's rule
+// is inherited and the child's local rule overrides it
+func settledByCharter(charters map[string]Charter, childRaftID, parentRaftID string, parentRule *Rule) bool {
+	charter, ok := charters[childRaftID]
+	return ok && charter.ParentRaftID == parentRaftID && charter.inherits(parentRule.Scope)
+}
@@ -0,0 +1,146 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"otter-ai/internal/errs"
+)
+
+// addParentRaft makes g a member of an "org" raft with the given active
+// rules, keyed by scope
+func addParentRaft(g *Governance, rules map[string]string) {
+	now := time.Now()
+	raft := &RaftInfo{
+		RaftID:    "org",
+		Members:   map[string]*Member{g.config.ID: {ID: g.config.ID, State: StateActive, JoinedAt: now}},
+		Rules:     make(map[string]*Rule),
+		CreatedAt: now,
+	}
+	g.rafts.rafts["org"] = raft
+	for scope, body := range rules {
+		rule := &Rule{RuleID: "org-" + scope, RaftID: "org", Scope: scope, Body: body, Version: 1, AdoptedAt: &now}
+		raft.Rules[rule.RuleID] = rule
+		g.rules.rules[rule.RuleID] = rule
+		g.rules.active[activeKey(rule)] = rule
+	}
+}
+
+func adoptSoloRule(t *testing.T, g *Governance, scope, body string) *Proposal {
+	t.Helper()
+	ctx := context.Background()
+	proposal, err := g.ProposeRule(ctx, g.config.ID, &Rule{Scope: scope, Body: body, ProposedBy: g.config.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CastVote(ctx, proposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	if proposal.Result != ResultAdopted {
+		t.Fatalf("result = %q, want adopted", proposal.Result)
+	}
+	return proposal
+}
+
+func TestParseCharter(t *testing.T) {
+	charter, err := ParseCharter("We follow the org charter.\nInherit: org\nscopes: safety, conduct/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Charter{ParentRaftID: "org", Scopes: []string{"safety", "conduct/*"}}
+	if !reflect.DeepEqual(charter, want) {
+		t.Errorf("charter = %+v, want %+v", charter, want)
+	}
+
+	for _, body := range []string{"scopes: safety", "inherit: org\nscopes: a/*/b"} {
+		if _, err := ParseCharter(body); !errors.Is(err, errs.ErrInvalid) {
+			t.Errorf("ParseCharter(%q) = %v, want an invalid-input error", body, err)
+		}
+	}
+}
+
+func TestCharter_InheritsWithLocalOverrides(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	addParentRaft(g, map[string]string{
+		"safety":       "Be careful",
+		"conduct/chat": "Be kind",
+		"tools":        "Ask first",
+		MetadataScope:  "name: Org",
+	})
+	adoptSoloRule(t, g, CharterScope, "inherit: org\nscopes: safety, conduct/*")
+
+	rules := g.GetActiveRulesForRaft("otter-1")
+	for _, scope := range []string{"safety", "conduct/chat"} {
+		if rule := rules[scope]; rule == nil || rule.RaftID != "org" {
+			t.Errorf("rules[%q] = %+v, want the inherited org rule", scope, rule)
+		}
+	}
+	for _, scope := range []string{"tools", MetadataScope} {
+		if rules[scope] != nil {
+			t.Errorf("rules[%q] inherited outside the charter's scopes", scope)
+		}
+	}
+
+	// Overriding an inherited rule takes the super-majority, and then wins
+	proposal, err := g.ProposeRule(context.Background(), "otter-1", &Rule{Scope: "safety", Body: "Be very careful", ProposedBy: "otter-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.needsSuperMajority(proposal) {
+		t.Error("overriding an inherited rule should need the super-majority")
+	}
+	if err := g.CastVote(context.Background(), proposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	if rule := g.GetActiveRulesForRaft("otter-1")["safety"]; rule == nil || rule.RaftID != "otter-1" {
+		t.Errorf("safety = %+v, want the local override", rule)
+	}
+	if inherited := g.InheritedRules("otter-1"); len(inherited) != 1 || inherited["conduct/chat"] == nil {
+		t.Errorf("inherited = %v, want only conduct/chat", inherited)
+	}
+
+	// A new scope outside the inherited ones is an ordinary rule
+	fresh := &Proposal{RaftID: "otter-1", Kind: ProposalKindRule, Rule: &Rule{Scope: "tone", Body: "Be brief"}}
+	if g.needsSuperMajority(fresh) {
+		t.Error("a rule in a scope nothing is inherited in should need only the majority")
+	}
+
+	for _, summary := range g.RaftSummaries() {
+		if summary.RaftID == "otter-1" && (summary.Charter == nil || summary.Charter.ParentRaftID != "org" || summary.InheritedRules != 1) {
+			t.Errorf("summary charter = %+v, inherited = %d", summary.Charter, summary.InheritedRules)
+		}
+	}
+}
+
+func TestCharter_RefusesCycles(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	addParentRaft(g, map[string]string{CharterScope: "inherit: otter-1"})
+	ctx := context.Background()
+
+	for _, body := range []string{"inherit: otter-1", "inherit: org"} {
+		if _, err := g.ProposeRule(ctx, "otter-1", &Rule{Scope: CharterScope, Body: body, ProposedBy: "otter-1"}); !errors.Is(err, errs.ErrInvalid) {
+			t.Errorf("charter %q: err = %v, want a cycle refused", body, err)
+		}
+	}
+}
+
+func TestDetectRuleConflicts_SettledByCharter(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	g.rafts.rafts["otter-1"].Rules["r-existing"] = &Rule{RuleID: "r-existing", Scope: "safety", Body: "be cautious"}
+
+	target := map[string]*Rule{
+		"r1": {RuleID: "r1", Scope: "safety", Body: "be bold"},
+	}
+	if conflicts := g.detectRuleConflicts("raft-2", target); len(conflicts) != 1 {
+		t.Fatalf("got %d conflicts without a charter, want 1", len(conflicts))
+	}
+
+	// The target raft inherits from this otter's raft and overrides safety
+	target["r-charter"] = &Rule{RuleID: "r-charter", Scope: CharterScope, Body: "inherit: otter-1"}
+	if conflicts := g.detectRuleConflicts("raft-2", target); len(conflicts) != 0 {
+		t.Errorf("got %d conflicts with a local override, want 0", len(conflicts))
+	}
+}
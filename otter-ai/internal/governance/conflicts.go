@@ -0,0 +1,224 @@
+package governance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"otter-ai/internal/llm"
+	"otter-ai/internal/prompts"
+	"otter-ai/internal/vectordb"
+)
+
+const (
+	// SemanticConflictSimilarity is the cosine similarity above which two
+	// rules in different scopes are checked for a contradiction
+	SemanticConflictSimilarity = 0.75
+	// SemanticConflictConfidence is the LLM confidence a contradiction
+	// needs to count as a conflict
+	SemanticConflictConfidence = 0.7
+)
+
+// ConflictReport is a detected rule conflict with the negotiation it started
+type ConflictReport struct {
+	ConflictID        string            `json:"conflict_id"`
+	NegotiationID     string            `json:"negotiation_id"`
+	NegotiationStatus NegotiationStatus `json:"negotiation_status"`
+	Raft1ID           string            `json:"raft1_id"`
+	Raft2ID           string            `json:"raft2_id"`
+	Scope             string            `json:"scope"`
+	Rule1             *Rule             `json:"rule1"`
+	Rule2             *Rule             `json:"rule2"`
+	Semantic          bool              `json:"semantic"` // Rules in scopes that do not overlap but contradict each other
+	Confidence        float64           `json:"confidence"`
+	Reason            string            `json:"reason,omitempty"`
+	DetectedAt        time.Time         `json:"detected_at"`
+}
+
+// SetEmbedder sets the provider used to embed rules for semantic conflict
+// detection
+func (g *Governance) SetEmbedder(embedder llm.EmbeddingProvider) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.embedder = embedder
+}
+
+func (g *Governance) ruleEmbedder() llm.EmbeddingProvider {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.embedder
+}
+
+// detectSemanticConflicts finds target rules that contradict existing rules
+// in a scope that does not overlap theirs, which detectRuleConflicts cannot
+// see. Pairs whose
+// embeddings are similar enough are put to the LLM, and a contradiction it
+// is confident in becomes a conflict in the target rule's scope. Detection
+// needs both an embedder and an LLM, and is skipped on provider errors so
+// a join is never blocked by it.
+func (g *Governance) detectSemanticConflicts(ctx context.Context, targetRaftID string, targetRules map[string]*Rule) []*RuleConflict {
+	embedder, provider := g.ruleEmbedder(), g.llmProvider()
+	if embedder == nil || provider == nil || len(targetRules) == 0 {
+		return nil
+	}
+
+	type existingRule struct {
+		raftID string
+		rule   *Rule
+	}
+	var existing []existingRule
+	g.rafts.mu.RLock()
+	for raftID, raft := range g.rafts.rafts {
+		if raftID == targetRaftID || raft.isArchived() {
+			continue
+		}
+		raft.mu.RLock()
+		for _, rule := range raft.Rules {
+			existing = append(existing, existingRule{raftID: raftID, rule: rule})
+		}
+		raft.mu.RUnlock()
+	}
+	g.rafts.mu.RUnlock()
+	if len(existing) == 0 {
+		return nil
+	}
+
+	vectors := make(map[string][]float32)
+	embed := func(body string) ([]float32, error) {
+		if vector, ok := vectors[body]; ok {
+			return vector, nil
+		}
+		vector, err := embedder.Embed(ctx, body)
+		if err != nil {
+			return nil, err
+		}
+		vectors[body] = vector
+		return vector, nil
+	}
+
+	var conflicts []*RuleConflict
+	for _, targetRule := range sortedRules(targetRules) {
+		for _, e := range existing {
+			if ScopesOverlap(targetRule.Scope, e.rule.Scope) || targetRule.Body == e.rule.Body {
+				continue // Overlapping scopes are detectRuleConflicts' to judge
+			}
+			a, err := embed(targetRule.Body)
+			if err != nil {
+				g.log().WarnContext(ctx, "semantic conflict detection skipped", "raft_id", targetRaftID, "error", err)
+				return conflicts
+			}
+			b, err := embed(e.rule.Body)
+			if err != nil {
+				g.log().WarnContext(ctx, "semantic conflict detection skipped", "raft_id", targetRaftID, "error", err)
+				return conflicts
+			}
+			if vectordb.CosineSimilarity(a, b) < SemanticConflictSimilarity {
+				continue
+			}
+
+			resp, err := provider.Complete(ctx, &llm.CompletionRequest{
+				Prompt:  g.buildContradictionPrompt(e.rule, targetRule),
+				Profile: llm.ProfileNegotiation,
+			})
+			if err != nil {
+				g.log().WarnContext(ctx, "semantic conflict detection skipped", "raft_id", targetRaftID, "error", err)
+				return conflicts
+			}
+			if resp == nil {
+				continue
+			}
+			contradicts, confidence, reason := parseContradiction(resp.Text)
+			if !contradicts || confidence < SemanticConflictConfidence {
+				continue
+			}
+			conflicts = append(conflicts, &RuleConflict{
+				ConflictID:    generateID(fmt.Sprintf("%s-%s", targetRule.RuleID, e.rule.RuleID)),
+				Raft1ID:       e.raftID,
+				Raft2ID:       targetRaftID,
+				Rule1:         e.rule,
+				Rule2:         targetRule,
+				ConflictScope: targetRule.Scope,
+				Confidence:    confidence,
+				Reason:        reason,
+				DetectedAt:    time.Now(),
+			})
+		}
+	}
+
+	if len(conflicts) > 0 {
+		g.log().InfoContext(ctx, "detected semantic rule conflicts", "raft_id", targetRaftID, "conflicts", len(conflicts))
+	}
+	return conflicts
+}
+
+// buildContradictionPrompt asks whether two rules in scopes that do not
+// overlap contradict each other
+func (g *Governance) buildContradictionPrompt(existing, target *Rule) string {
+	return g.renderPrompt(prompts.RuleContradiction, struct{ Existing, Target *Rule }{existing, target})
+}
+
+// parseContradiction reads a contradiction verdict. A reply that is not the
+// requested JSON is no contradiction.
+func parseContradiction(raw string) (bool, float64, string) {
+	var parsed struct {
+		Contradicts bool    `json:"contradicts"`
+		Confidence  float64 `json:"confidence"`
+		Reason      string  `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(trimCodeFence(raw)), &parsed); err != nil {
+		return false, 0, ""
+	}
+	confidence := parsed.Confidence
+	if confidence < 0 {
+		confidence = 0
+	}
+	if confidence > 1 {
+		confidence = 1
+	}
+	return parsed.Contradicts, confidence, strings.TrimSpace(parsed.Reason)
+}
+
+// Conflicts returns the rule conflicts found when joining rafts, newest
+// first, optionally only those involving raftID
+func (g *Governance) Conflicts(raftID string) []ConflictReport {
+	g.negotiations.mu.RLock()
+	defer g.negotiations.mu.RUnlock()
+
+	reports := make([]ConflictReport, 0)
+	for _, negotiation := range g.negotiations.negotiations {
+		for _, conflict := range negotiation.Conflicts {
+			if conflict == nil || (raftID != "" && conflict.Raft1ID != raftID && conflict.Raft2ID != raftID) {
+				continue
+			}
+			reports = append(reports, conflictReport(negotiation, conflict))
+		}
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].DetectedAt.After(reports[j].DetectedAt)
+	})
+	return reports
+}
+
+// conflictReport describes a conflict with the negotiation it started
+func conflictReport(negotiation *Negotiation, conflict *RuleConflict) ConflictReport {
+	report := ConflictReport{
+		ConflictID:        conflict.ConflictID,
+		NegotiationID:     negotiation.NegotiationID,
+		NegotiationStatus: negotiation.Status,
+		Raft1ID:           conflict.Raft1ID,
+		Raft2ID:           conflict.Raft2ID,
+		Scope:             conflict.ConflictScope,
+		Rule1:             conflict.Rule1,
+		Rule2:             conflict.Rule2,
+		Confidence:        conflict.Confidence,
+		Reason:            conflict.Reason,
+		DetectedAt:        conflict.DetectedAt,
+	}
+	if conflict.Rule1 != nil && conflict.Rule2 != nil {
+		report.Semantic = !ScopesOverlap(conflict.Rule1.Scope, conflict.Rule2.Scope)
+	}
+	return report
+}
@@ -0,0 +1,93 @@
+package governance
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// vectorEmbedder embeds each text as a fixed vector
+type vectorEmbedder map[string][]float32
+
+func (v vectorEmbedder) Name() string { return "vectors" }
+func (v vectorEmbedder) Embed(_ context.Context, text string) ([]float32, error) {
+	return v[text], nil
+}
+
+func TestDetectSemanticConflicts(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	g.rafts.rafts["otter-1"].Rules["r-existing"] = &Rule{RuleID: "r-existing", Scope: "privacy", Body: "never share logs"}
+	provider := &scriptedLLMProvider{replies: []string{`{"contradicts":true,"confidence":0.9,"reason":"publishing logs shares them"}`}}
+	g.SetLLMProvider(provider)
+	g.SetEmbedder(vectorEmbedder{
+		"never share logs":       {1, 0},
+		"publish all logs":       {0.9, 0.1},
+		"greet newcomers warmly": {0, 1},
+	})
+	targetRules := map[string]*Rule{
+		"r1": {RuleID: "r1", Scope: "transparency", Body: "publish all logs"},
+		"r2": {RuleID: "r2", Scope: "tone", Body: "greet newcomers warmly"},
+	}
+
+	conflicts := g.detectSemanticConflicts(context.Background(), "raft-2", targetRules)
+	if len(conflicts) != 1 {
+		t.Fatalf("expected 1 conflict, got %d", len(conflicts))
+	}
+	conflict := conflicts[0]
+	if conflict.Rule2.RuleID != "r1" || conflict.ConflictScope != "transparency" || conflict.Confidence != 0.9 || conflict.Reason == "" {
+		t.Errorf("conflict = %+v", conflict)
+	}
+	// The dissimilar rule never reached the LLM
+	if len(provider.prompts) != 1 {
+		t.Errorf("LLM was asked %d times, want once", len(provider.prompts))
+	}
+}
+
+func TestDetectSemanticConflicts_LowConfidence(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	g.rafts.rafts["otter-1"].Rules["r-existing"] = &Rule{RuleID: "r-existing", Scope: "privacy", Body: "never share logs"}
+	g.SetLLMProvider(&mockLLMProvider{response: `{"contradicts":true,"confidence":0.4,"reason":"maybe"}`})
+	g.SetEmbedder(&mockLLMProvider{})
+
+	targetRules := map[string]*Rule{"r1": {RuleID: "r1", Scope: "transparency", Body: "publish all logs"}}
+	if conflicts := g.detectSemanticConflicts(context.Background(), "raft-2", targetRules); len(conflicts) != 0 {
+		t.Errorf("expected no conflicts below the confidence threshold, got %+v", conflicts)
+	}
+
+	// Without an embedder nothing is checked
+	g.SetEmbedder(nil)
+	g.SetLLMProvider(&mockLLMProvider{response: `{"contradicts":true,"confidence":1}`})
+	if conflicts := g.detectSemanticConflicts(context.Background(), "raft-2", targetRules); len(conflicts) != 0 {
+		t.Errorf("expected no conflicts without an embedder, got %+v", conflicts)
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	now := time.Now()
+	g.negotiations.negotiations["n1"] = &Negotiation{
+		NegotiationID: "n1",
+		Status:        NegotiationResolved,
+		Conflicts: []*RuleConflict{
+			{ConflictID: "c1", Raft1ID: "otter-1", Raft2ID: "raft-2", ConflictScope: "safety", Confidence: 1, DetectedAt: now.Add(-time.Minute),
+				Rule1: &Rule{Scope: "safety"}, Rule2: &Rule{Scope: "safety"}},
+			{ConflictID: "c2", Raft1ID: "otter-1", Raft2ID: "raft-2", ConflictScope: "transparency", Confidence: 0.9, DetectedAt: now,
+				Rule1: &Rule{Scope: "privacy"}, Rule2: &Rule{Scope: "transparency"}},
+		},
+	}
+	g.negotiations.negotiations["n2"] = &Negotiation{
+		NegotiationID: "n2",
+		Conflicts:     []*RuleConflict{{ConflictID: "c3", Raft1ID: "otter-1", Raft2ID: "raft-3", DetectedAt: now}},
+	}
+
+	reports := g.Conflicts("raft-2")
+	if len(reports) != 2 || reports[0].ConflictID != "c2" || !reports[0].Semantic || reports[1].Semantic {
+		t.Fatalf("reports = %+v", reports)
+	}
+	if reports[0].NegotiationID != "n1" || reports[0].NegotiationStatus != NegotiationResolved {
+		t.Errorf("report = %+v", reports[0])
+	}
+	if all := g.Conflicts(""); len(all) != 3 {
+		t.Errorf("expected 3 conflicts in all, got %d", len(all))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/ecdh"
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/sha256"
 	"fmt"
@@ -15,11 +16,15 @@ import (
 // Cryptography layer implementing hybrid ECDH + Kyber with AES-512
 // Note: Kyber implementation requires external library (not included in this stub)
 
-// CryptoSystem manages cryptographic operations for governance
+// CryptoSystem manages cryptographic operations for governance.
+// The ECDH key is used for key agreement; the Ed25519 key signs rules,
+// votes and membership records.
 type CryptoSystem struct {
 	privateKey *ecdh.PrivateKey
 	publicKey  *ecdh.PublicKey
 	curve      ecdh.Curve
+	signingKey ed25519.PrivateKey
+	verifyKey  ed25519.PublicKey
 }
 
 // NewCryptoSystem creates a new cryptography system
@@ -32,10 +37,17 @@ func NewCryptoSystem() (*CryptoSystem, error) {
 		return nil, fmt.Errorf("failed to generate private key: %w", err)
 	}
 
+	verifyKey, signingKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+
 	return &CryptoSystem{
 		privateKey: privateKey,
 		publicKey:  privateKey.PublicKey(),
 		curve:      curve,
+		signingKey: signingKey,
+		verifyKey:  verifyKey,
 	}, nil
 }
 
@@ -44,6 +56,11 @@ func (cs *CryptoSystem) GetPublicKey() []byte {
 	return cs.publicKey.Bytes()
 }
 
+// GetSigningPublicKey returns the Ed25519 public key used to verify this otter's signatures
+func (cs *CryptoSystem) GetSigningPublicKey() []byte {
+	return append([]byte(nil), cs.verifyKey...)
+}
+
 // DeriveSharedSecret derives a shared secret using ECDH
 func (cs *CryptoSystem) DeriveSharedSecret(peerPublicKey []byte) ([]byte, error) {
 	peerPubKey, err := cs.curve.NewPublicKey(peerPublicKey)
@@ -121,36 +138,25 @@ func (cs *CryptoSystem) Decrypt(ciphertext []byte, sharedSecret []byte) ([]byte,
 	return plaintext, nil
 }
 
-// Sign signs a message using the private key
+// Sign signs a message with the Ed25519 signing key
 func (cs *CryptoSystem) Sign(message []byte) ([]byte, error) {
-	// Simple signature using HMAC with the private key
-	// In production, use ECDSA or Ed25519 for proper signatures
-	hash := sha256.New()
-	hash.Write(cs.privateKey.Bytes())
-	hash.Write(message)
-	return hash.Sum(nil), nil
+	if len(cs.signingKey) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("signing key not available")
+	}
+	return ed25519.Sign(cs.signingKey, message), nil
 }
 
-// Verify verifies a signature
+// Verify verifies an Ed25519 signature against the signer's public key
 func (cs *CryptoSystem) Verify(message []byte, signature []byte, publicKey []byte) bool {
-	// Simple verification using HMAC
-	// In production, use ECDSA or Ed25519 for proper verification
-	hash := sha256.New()
-	hash.Write(publicKey)
-	hash.Write(message)
-	expectedSig := hash.Sum(nil)
-
-	if len(signature) != len(expectedSig) {
-		return false
-	}
+	return VerifySignature(message, signature, publicKey)
+}
 
-	for i := range signature {
-		if signature[i] != expectedSig[i] {
-			return false
-		}
+// VerifySignature verifies an Ed25519 signature without needing a local key
+func VerifySignature(message []byte, signature []byte, publicKey []byte) bool {
+	if len(publicKey) != ed25519.PublicKeySize || len(signature) != ed25519.SignatureSize {
+		return false
 	}
-
-	return true
+	return ed25519.Verify(ed25519.PublicKey(publicKey), message, signature)
 }
 
 // KyberKeyPair represents a Kyber key pair (stub)
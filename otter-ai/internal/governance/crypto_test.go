@@ -146,24 +146,37 @@ func TestSignVerify(t *testing.T) {
 		t.Fatalf("Sign: %v", err)
 	}
 
-	// Verify uses public key bytes, matching the Sign implementation which uses private key bytes.
-	// But Verify takes publicKey parameter, and Sign uses privateKey.
-	// The current implementation is HMAC-based and NOT standard ECDSA.
-	// Sign: sha256(privateKey || msg)
-	// Verify: sha256(publicKey || msg)
-	// These will NOT match unless we pass the private key as "publicKey" to Verify.
-	// This tests the actual behavior of the stub implementation.
-	valid := cs.Verify(msg, sig, cs.privateKey.Bytes())
+	valid := cs.Verify(msg, sig, cs.GetSigningPublicKey())
 	if !valid {
 		t.Error("signature should be valid")
 	}
 }
 
+func TestVerify_TamperedMessage(t *testing.T) {
+	cs, _ := NewCryptoSystem()
+	sig, _ := cs.Sign([]byte("original"))
+
+	if cs.Verify([]byte("tampered"), sig, cs.GetSigningPublicKey()) {
+		t.Error("should reject signature over a different message")
+	}
+}
+
+func TestVerify_WrongKey(t *testing.T) {
+	cs1, _ := NewCryptoSystem()
+	cs2, _ := NewCryptoSystem()
+	msg := []byte("test")
+	sig, _ := cs1.Sign(msg)
+
+	if cs2.Verify(msg, sig, cs2.GetSigningPublicKey()) {
+		t.Error("should reject signature from another key")
+	}
+}
+
 func TestVerify_InvalidSignature(t *testing.T) {
 	cs, _ := NewCryptoSystem()
 	msg := []byte("test")
 
-	valid := cs.Verify(msg, []byte("bad signature"), cs.GetPublicKey())
+	valid := cs.Verify(msg, []byte("bad signature"), cs.GetSigningPublicKey())
 	if valid {
 		t.Error("should reject invalid signature")
 	}
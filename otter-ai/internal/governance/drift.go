@@ -0,0 +1,486 @@
+package governance
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"otter-ai/internal/events"
+)
+
+// Drift detection configuration
+const (
+	DriftCheckInterval = 10 * time.Minute
+)
+
+// RuleSetDigest is a Merkle commitment to the adopted rules of a raft.
+// Leaves maps rule ID to the hash of its canonical serialization so peers
+// can tell exactly which rules differ when the roots disagree.
+type RuleSetDigest struct {
+	RaftID     string            `json:"raft_id"`
+	Root       string            `json:"root"`
+	Leaves     map[string]string `json:"leaves"`
+	ComputedAt time.Time         `json:"computed_at"`
+}
+
+// DriftReport compares this otter's rule set for a raft with one peer's
+type DriftReport struct {
+	RaftID        string    `json:"raft_id"`
+	PeerID        string    `json:"peer_id"`
+	Endpoint      string    `json:"endpoint"`
+	LocalRoot     string    `json:"local_root"`
+	PeerRoot      string    `json:"peer_root,omitempty"`
+	InSync        bool      `json:"in_sync"`
+	MissingLocal  []string  `json:"missing_local,omitempty"`  // Adopted by the peer but not here
+	MissingRemote []string  `json:"missing_remote,omitempty"` // Adopted here but not by the peer
+	Differing     []string  `json:"differing,omitempty"`      // Same rule ID, different content
+	Error         string    `json:"error,omitempty"`
+	CheckedAt     time.Time `json:"checked_at"`
+}
+
+// ReconcileResult describes what a reconciliation pass changed
+type ReconcileResult struct {
+	RaftID    string   `json:"raft_id"`
+	PeerID    string   `json:"peer_id"`
+	Adopted   []string `json:"adopted"`   // Rules copied from the peer
+	Replaced  []string `json:"replaced"`  // Local rules superseded by a newer peer version
+	Conflicts []string `json:"conflicts"` // Differing or unproven rules that need a human or a new proposal
+}
+
+// driftState holds the latest report per raft and peer
+type driftState struct {
+	reports map[string]*DriftReport // raftID/peerID -> report
+	mu      sync.RWMutex
+}
+
+var errNoPeerEndpoint = errors.New("peer has no known endpoint")
+
+// ruleLeafHash hashes the fields every member agrees on for an adopted rule.
+// Signatures and adoption times are local to each otter and are excluded.
+func ruleLeafHash(rule *Rule) string {
+	hash := sha256.Sum256(canonicalPayload(
+		"rule-leaf",
+		rule.RuleID,
+		rule.RaftID,
+		rule.Scope,
+		strconv.Itoa(rule.Version),
+		strconv.FormatInt(rule.Timestamp.Unix(), 10),
+		rule.Body,
+		rule.BaseRuleID,
+		rule.ProposedBy,
+	))
+	return hex.EncodeToString(hash[:])
+}
+
+// merkleRoot folds leaf hashes (ordered by rule ID) pairwise into a root.
+// An odd node is carried up by pairing it with itself.
+func merkleRoot(leaves map[string]string) string {
+	ids := make([]string, 0, len(leaves))
+	for id := range leaves {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	level := make([][]byte, 0, len(ids))
+	for _, id := range ids {
+		leaf, _ := hex.DecodeString(leaves[id])
+		level = append(level, leaf)
+	}
+	if len(level) == 0 {
+		empty := sha256.Sum256(nil)
+		return hex.EncodeToString(empty[:])
+	}
+
+	for len(level) > 1 {
+		next := make([][]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			right := level[i]
+			if i+1 < len(level) {
+				right = level[i+1]
+			}
+			node := sha256.Sum256(append(append([]byte{}, level[i]...), right...))
+			next = append(next, node[:])
+		}
+		level = next
+	}
+	return hex.EncodeToString(level[0])
+}
+
+// RuleSetDigest computes the Merkle digest of a raft's adopted rules
+func (g *Governance) RuleSetDigest(raftID string) (*RuleSetDigest, error) {
+	rules, err := g.adoptedRules(raftID)
+	if err != nil {
+		return nil, err
+	}
+
+	leaves := make(map[string]string, len(rules))
+	for _, rule := range rules {
+		leaves[rule.RuleID] = ruleLeafHash(rule)
+	}
+
+	return &RuleSetDigest{
+		RaftID:     raftID,
+		Root:       merkleRoot(leaves),
+		Leaves:     leaves,
+		ComputedAt: time.Now(),
+	}, nil
+}
+
+// RaftRules returns the adopted rules of a raft, ordered by rule ID
+func (g *Governance) RaftRules(raftID string) ([]*Rule, error) {
+	rules, err := g.adoptedRules(raftID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].RuleID < rules[j].RuleID
+	})
+	return rules, nil
+}
+
+func (g *Governance) adoptedRules(raftID string) ([]*Rule, error) {
+	g.rafts.mu.RLock()
+	raft, exists := g.rafts.rafts[raftID]
+	g.rafts.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrRaftNotFound, raftID)
+	}
+
+	raft.mu.RLock()
+	defer raft.mu.RUnlock()
+
+	rules := make([]*Rule, 0, len(raft.Rules))
+	for _, rule := range raft.Rules {
+		if rule.AdoptedAt != nil {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+// compareDigests fills the difference fields of a report
+func compareDigests(report *DriftReport, local, peer *RuleSetDigest) {
+	report.LocalRoot = local.Root
+	report.PeerRoot = peer.Root
+	report.InSync = local.Root == peer.Root
+	if report.InSync {
+		return
+	}
+
+	for id, hash := range peer.Leaves {
+		localHash, ok := local.Leaves[id]
+		switch {
+		case !ok:
+			report.MissingLocal = append(report.MissingLocal, id)
+		case localHash != hash:
+			report.Differing = append(report.Differing, id)
+		}
+	}
+	for id := range local.Leaves {
+		if _, ok := peer.Leaves[id]; !ok {
+			report.MissingRemote = append(report.MissingRemote, id)
+		}
+	}
+	sort.Strings(report.MissingLocal)
+	sort.Strings(report.MissingRemote)
+	sort.Strings(report.Differing)
+}
+
+// CheckDrift compares a raft's rule set with every reachable peer
+func (g *Governance) CheckDrift(ctx context.Context, raftID string) ([]*DriftReport, error) {
+	local, err := g.RuleSetDigest(raftID)
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []*DriftReport
+	for _, peer := range g.raftPeers(raftID) {
+		report := &DriftReport{
+			RaftID:    raftID,
+			PeerID:    peer.ID,
+			Endpoint:  peer.Endpoint,
+			LocalRoot: local.Root,
+			CheckedAt: time.Now(),
+		}
+
+		remote, err := g.fetchRuleSetDigest(ctx, peer, raftID)
+		if err != nil {
+			report.Error = err.Error()
+		} else {
+			compareDigests(report, local, remote)
+		}
+
+		if !report.InSync && report.Error == "" {
+			g.log().WarnContext(ctx, "rule drift detected", "raft_id", raftID, "peer_id", peer.ID,
+				"missing_locally", len(report.MissingLocal), "missing_on_peer", len(report.MissingRemote), "differing", len(report.Differing))
+		}
+		g.recordDrift(report)
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// CheckAllDrift runs a drift check for every raft with reachable peers
+func (g *Governance) CheckAllDrift(ctx context.Context) []*DriftReport {
+	g.rafts.mu.RLock()
+	raftIDs := make([]string, 0, len(g.rafts.rafts))
+	for raftID := range g.rafts.rafts {
+		raftIDs = append(raftIDs, raftID)
+	}
+	g.rafts.mu.RUnlock()
+	sort.Strings(raftIDs)
+
+	var reports []*DriftReport
+	for _, raftID := range raftIDs {
+		raftReports, err := g.CheckDrift(ctx, raftID)
+		if err != nil {
+			continue
+		}
+		reports = append(reports, raftReports...)
+	}
+	return reports
+}
+
+// DriftReports returns the latest report for every raft and peer checked
+func (g *Governance) DriftReports() []*DriftReport {
+	state := g.driftState()
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	reports := make([]*DriftReport, 0, len(state.reports))
+	for _, report := range state.reports {
+		copied := *report
+		reports = append(reports, &copied)
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].RaftID != reports[j].RaftID {
+			return reports[i].RaftID < reports[j].RaftID
+		}
+		return reports[i].PeerID < reports[j].PeerID
+	})
+	return reports
+}
+
+func (g *Governance) driftState() *driftState {
+	g.driftOnce.Do(func() {
+		if g.drift == nil {
+			g.drift = &driftState{reports: make(map[string]*DriftReport)}
+		}
+	})
+	return g.drift
+}
+
+func (g *Governance) recordDrift(report *DriftReport) {
+	state := g.driftState()
+	state.mu.Lock()
+	state.reports[report.RaftID+"/"+report.PeerID] = report
+	state.mu.Unlock()
+}
+
+// driftMonitor periodically checks every raft for rule drift and pulls
+// rules it is missing from its peers
+func (g *Governance) driftMonitor() {
+	ticker := time.NewTicker(DriftCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			g.SyncRules(context.Background())
+		case <-g.shutdownCh:
+			return
+		}
+	}
+}
+
+// ReconcileRules pulls a peer's rules for a raft and adopts the ones that are
+// missing locally. A differing rule is replaced only when the peer holds a
+// newer version of it; otherwise it is reported as a conflict. Every rule
+// taken from the peer must carry a valid signature and proof of adoption;
+// unproven rules are reported as conflicts too.
+func (g *Governance) ReconcileRules(ctx context.Context, raftID, peerID string) (*ReconcileResult, error) {
+	raft, err := g.liveRaft(raftID)
+	if err != nil {
+		return nil, err
+	}
+
+	raft.mu.RLock()
+	member, ok := raft.Members[peerID]
+	var peer *Member
+	if ok {
+		peer = &Member{ID: member.ID, Endpoint: member.Endpoint, SigningKey: member.SigningKey}
+	}
+	members := make(map[string]*Member, len(raft.Members))
+	for id, member := range raft.Members {
+		members[id] = member
+	}
+	raft.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%s is not a member of raft %s", peerID, raftID)
+	}
+	if peer.Endpoint == "" {
+		return nil, fmt.Errorf("%w: %s", errNoPeerEndpoint, peerID)
+	}
+
+	peerRules, err := g.fetchPeerRaftRules(ctx, peer, raftID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReconcileResult{RaftID: raftID, PeerID: peerID}
+	var candidates []*Rule
+	replaces := make(map[string]bool)
+
+	raft.mu.RLock()
+	for _, rule := range peerRules {
+		if rule.RaftID != raftID || rule.AdoptedAt == nil {
+			continue
+		}
+		local, exists := raft.Rules[rule.RuleID]
+		switch {
+		case !exists:
+		case ruleLeafHash(local) == ruleLeafHash(rule):
+			continue
+		case rule.Version > local.Version:
+			replaces[rule.RuleID] = true
+		default:
+			result.Conflicts = append(result.Conflicts, rule.RuleID)
+			continue
+		}
+		candidates = append(candidates, rule)
+	}
+	raft.mu.RUnlock()
+
+	// Verify everything before changing any state. Rules whose adoption
+	// the peer cannot prove are reported as conflicts and left alone.
+	var toAdopt []*Rule
+	for _, rule := range candidates {
+		if err := verifyRuleSigner(rule, members); err != nil {
+			return nil, fmt.Errorf("refusing to reconcile with %s: %w", peerID, err)
+		}
+		if err := g.verifyRuleAdoption(raft, rule, peerID); err != nil {
+			g.log().WarnContext(ctx, "not reconciling unproven rule", "rule_id", rule.RuleID, "raft_id", raftID, "peer_id", peerID, "error", err)
+			result.Conflicts = append(result.Conflicts, rule.RuleID)
+			continue
+		}
+		if replaces[rule.RuleID] {
+			result.Replaced = append(result.Replaced, rule.RuleID)
+		} else {
+			result.Adopted = append(result.Adopted, rule.RuleID)
+		}
+		toAdopt = append(toAdopt, rule)
+	}
+
+	for _, rule := range toAdopt {
+		g.adoptReconciledRule(raft, rule)
+	}
+
+	if len(toAdopt) > 0 {
+		if err := g.saveRaft(ctx, raft); err != nil {
+			g.log().WarnContext(ctx, "failed to persist reconciled raft", "raft_id", raftID, "error", err)
+		}
+	}
+
+	sort.Strings(result.Adopted)
+	sort.Strings(result.Replaced)
+	sort.Strings(result.Conflicts)
+	return result, nil
+}
+
+// adoptReconciledRule installs a rule received from a peer during reconciliation
+func (g *Governance) adoptReconciledRule(raft *RaftInfo, rule *Rule) {
+	raft.mu.Lock()
+	raft.Rules[rule.RuleID] = rule
+	raft.mu.Unlock()
+
+	g.rules.mu.Lock()
+	g.rules.rules[rule.RuleID] = rule
+	current, ok := g.rules.active[activeKey(rule)]
+	activated := !ok || current.RuleID == rule.RuleID || current.Version < rule.Version
+	if activated {
+		g.rules.active[activeKey(rule)] = rule
+	}
+	g.rules.mu.Unlock()
+	if activated {
+		g.applyMetaRule(raft, rule)
+	}
+
+	g.publish(events.RuleAdopted, ruleEvent(rule))
+	if activated {
+		g.recordAudit(AuditRuleAdopted, rule.RaftID, rule.RuleID, rule.SignedBy, ruleEvent(rule))
+	}
+}
+
+// fetchRuleSetDigest fetches a peer's signed digest for a raft
+func (g *Governance) fetchRuleSetDigest(ctx context.Context, peer *Member, raftID string) (*RuleSetDigest, error) {
+	var digest RuleSetDigest
+	if err := getPeerJSON(ctx, peer.Endpoint, "/api/v1/governance/rafts/"+url.PathEscape(raftID)+"/digest", peer, &digest); err != nil {
+		return nil, err
+	}
+	return &digest, nil
+}
+
+// fetchPeerRaftRules fetches a peer's signed list of adopted rules for a raft
+func (g *Governance) fetchPeerRaftRules(ctx context.Context, peer *Member, raftID string) ([]*Rule, error) {
+	var rules []*Rule
+	if err := getPeerJSON(ctx, peer.Endpoint, "/api/v1/governance/rafts/"+url.PathEscape(raftID)+"/rules", peer, &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// getPeerJSON performs a GET against a peer's API and decodes the JSON body.
+// When signer is set the response must be signed by it; an otter that is not
+// yet a member of the raft passes nil and verifies what it reads otherwise.
+func getPeerJSON(ctx context.Context, endpoint, path string, signer *Member, out interface{}) error {
+	endpoint = strings.TrimSpace(endpoint)
+	if endpoint == "" {
+		return errNoPeerEndpoint
+	}
+	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+		endpoint = "http://" + endpoint
+	}
+
+	target := strings.TrimRight(endpoint, "/") + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return fmt.Errorf("failed creating request: %w", err)
+	}
+
+	client := &http.Client{Timeout: GovernanceHTTPTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed fetching %s: %w", target, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed reading response from %s: %w", target, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d: %s", target, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if signer != nil {
+		if err := verifyPeerResponse(resp.Header, req.URL.EscapedPath(), body, signer); err != nil {
+			return fmt.Errorf("refusing response from %s: %w", target, err)
+		}
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response from %s: %w", target, err)
+	}
+	return nil
+}
@@ -0,0 +1,379 @@
+package governance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// addAdoptedRule signs and activates a rule in the given raft
+func addAdoptedRule(t *testing.T, g *Governance, raftID, ruleID, scope, body string, version int) *Rule {
+	t.Helper()
+	adopted := time.Now()
+	rule := &Rule{
+		RuleID:     ruleID,
+		RaftID:     raftID,
+		Scope:      scope,
+		Version:    version,
+		Timestamp:  time.Unix(1700000000, 0),
+		Body:       body,
+		ProposedBy: g.config.ID,
+		AdoptedAt:  &adopted,
+	}
+	if err := g.signRule(rule); err != nil {
+		t.Fatal(err)
+	}
+	g.activateRule(rule)
+	return rule
+}
+
+// proveRule attaches the voters' YES ballots on rule's proposal, as the
+// otter that tallied the adoption does
+func proveRule(t *testing.T, rule *Rule, voters ...*Governance) {
+	t.Helper()
+	rule.ProposalID = ruleProposalID(rule)
+	rule.Ballots = nil
+	for _, voter := range voters {
+		ballot, err := voter.SignVote(rule.ProposalID, VoteYes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rule.Ballots = append(rule.Ballots, ballot)
+	}
+}
+
+// addSharedRaft registers raft-1 on g with peerID reachable at endpoint
+func addSharedRaft(g *Governance, peerID, endpoint string, peerSigningKey []byte) {
+	now := time.Now()
+	g.rafts.rafts["raft-1"] = &RaftInfo{
+		RaftID: "raft-1",
+		Members: map[string]*Member{
+			g.config.ID: {ID: g.config.ID, State: StateActive, JoinedAt: now, LastSeenAt: now, SigningKey: g.crypto.GetSigningPublicKey()},
+			peerID:      {ID: peerID, State: StateActive, JoinedAt: now, LastSeenAt: now, Endpoint: endpoint, SigningKey: peerSigningKey},
+		},
+		Rules:     make(map[string]*Rule),
+		CreatedAt: now,
+	}
+}
+
+// writeSigned writes v as a peer response signed by g, as the API does
+func writeSigned(w http.ResponseWriter, r *http.Request, g *Governance, v interface{}) {
+	body, _ := json.Marshal(v)
+	header, _ := g.SignPeerResponse(r.URL.EscapedPath(), body)
+	for key, values := range header {
+		w.Header()[key] = values
+	}
+	w.Write(body)
+}
+
+// servePeer exposes a peer's signed digest and rules for raft-1 over HTTP
+func servePeer(t *testing.T, peer *Governance) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/digest"):
+			digest, _ := peer.RuleSetDigest("raft-1")
+			writeSigned(w, r, peer, digest)
+		case strings.HasSuffix(r.URL.Path, "/rules"):
+			rules, _ := peer.RaftRules("raft-1")
+			writeSigned(w, r, peer, rules)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestMerkleRoot_OrderIndependent(t *testing.T) {
+	a := map[string]string{"r1": ruleLeafHash(&Rule{RuleID: "r1", Body: "a"}), "r2": ruleLeafHash(&Rule{RuleID: "r2", Body: "b"}), "r3": ruleLeafHash(&Rule{RuleID: "r3", Body: "c"})}
+	b := map[string]string{"r3": a["r3"], "r1": a["r1"], "r2": a["r2"]}
+
+	if merkleRoot(a) != merkleRoot(b) {
+		t.Error("root should not depend on map order")
+	}
+
+	delete(b, "r3")
+	if merkleRoot(a) == merkleRoot(b) {
+		t.Error("root should change when a leaf is removed")
+	}
+}
+
+func TestMerkleRoot_Empty(t *testing.T) {
+	if merkleRoot(nil) == "" {
+		t.Error("empty rule set should still have a root")
+	}
+}
+
+func TestRuleLeafHash_IgnoresLocalFields(t *testing.T) {
+	adopted := time.Now()
+	r1 := &Rule{RuleID: "r1", Body: "be kind", Version: 1}
+	r2 := *r1
+	r2.AdoptedAt = &adopted
+	r2.Signature = []byte("sig")
+
+	if ruleLeafHash(r1) != ruleLeafHash(&r2) {
+		t.Error("adoption time and signature should not affect the leaf hash")
+	}
+
+	r2.Body = "be bold"
+	if ruleLeafHash(r1) == ruleLeafHash(&r2) {
+		t.Error("body change should affect the leaf hash")
+	}
+}
+
+func TestCompareDigests(t *testing.T) {
+	local := &RuleSetDigest{Leaves: map[string]string{"a": "1", "b": "2", "c": "3"}}
+	peer := &RuleSetDigest{Leaves: map[string]string{"a": "1", "b": "x", "d": "4"}}
+	local.Root = merkleRoot(map[string]string{"a": "01"})
+	peer.Root = merkleRoot(map[string]string{"b": "02"})
+
+	report := &DriftReport{}
+	compareDigests(report, local, peer)
+
+	if report.InSync {
+		t.Fatal("expected drift")
+	}
+	if len(report.MissingLocal) != 1 || report.MissingLocal[0] != "d" {
+		t.Errorf("MissingLocal = %v", report.MissingLocal)
+	}
+	if len(report.MissingRemote) != 1 || report.MissingRemote[0] != "c" {
+		t.Errorf("MissingRemote = %v", report.MissingRemote)
+	}
+	if len(report.Differing) != 1 || report.Differing[0] != "b" {
+		t.Errorf("Differing = %v", report.Differing)
+	}
+}
+
+func TestCheckDrift_InSync(t *testing.T) {
+	peer := newTestGovernance("peer")
+	addSharedRaft(peer, "otter-1", "", nil)
+	rule := addAdoptedRule(t, peer, "raft-1", "r1", "safety", "be kind", 1)
+	srv := servePeer(t, peer)
+
+	g := newTestGovernance("otter-1")
+	addSharedRaft(g, "peer", srv.URL, peer.crypto.GetSigningPublicKey())
+	copied := *rule
+	g.activateRule(&copied)
+
+	reports, err := g.CheckDrift(context.Background(), "raft-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 || !reports[0].InSync {
+		t.Fatalf("expected one in-sync report, got %+v", reports)
+	}
+	if len(g.DriftReports()) != 1 {
+		t.Error("report should be recorded")
+	}
+}
+
+func TestCheckDrift_DetectsMissingRule(t *testing.T) {
+	peer := newTestGovernance("peer")
+	addSharedRaft(peer, "otter-1", "", nil)
+	addAdoptedRule(t, peer, "raft-1", "r1", "safety", "be kind", 1)
+	srv := servePeer(t, peer)
+
+	g := newTestGovernance("otter-1")
+	addSharedRaft(g, "peer", srv.URL, peer.crypto.GetSigningPublicKey())
+
+	reports, _ := g.CheckDrift(context.Background(), "raft-1")
+	if len(reports) != 1 || reports[0].InSync {
+		t.Fatalf("expected drift, got %+v", reports)
+	}
+	if len(reports[0].MissingLocal) != 1 || reports[0].MissingLocal[0] != "r1" {
+		t.Errorf("MissingLocal = %v", reports[0].MissingLocal)
+	}
+}
+
+func TestCheckDrift_UnreachablePeer(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	addSharedRaft(g, "peer", "http://127.0.0.1:1", nil)
+
+	reports, _ := g.CheckDrift(context.Background(), "raft-1")
+	if len(reports) != 1 || reports[0].Error == "" {
+		t.Fatalf("expected an error report, got %+v", reports)
+	}
+}
+
+func TestCheckDrift_RefusesUnsignedDigest(t *testing.T) {
+	peer := newTestGovernance("peer")
+	addSharedRaft(peer, "otter-1", "", nil)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		digest, _ := peer.RuleSetDigest("raft-1")
+		json.NewEncoder(w).Encode(digest)
+	}))
+	defer srv.Close()
+
+	g := newTestGovernance("otter-1")
+	addSharedRaft(g, "peer", srv.URL, peer.crypto.GetSigningPublicKey())
+
+	reports, _ := g.CheckDrift(context.Background(), "raft-1")
+	if len(reports) != 1 || !strings.Contains(reports[0].Error, ErrUnsigned.Error()) {
+		t.Fatalf("expected an unsigned-response error, got %+v", reports)
+	}
+}
+
+func TestCheckDrift_RefusesDigestSignedByAnotherOtter(t *testing.T) {
+	peer := newTestGovernance("peer")
+	addSharedRaft(peer, "otter-1", "", nil)
+	impostor := newTestGovernance("impostor")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		digest, _ := peer.RuleSetDigest("raft-1")
+		writeSigned(w, r, impostor, digest)
+	}))
+	defer srv.Close()
+
+	g := newTestGovernance("otter-1")
+	addSharedRaft(g, "peer", srv.URL, peer.crypto.GetSigningPublicKey())
+
+	reports, _ := g.CheckDrift(context.Background(), "raft-1")
+	if len(reports) != 1 || !strings.Contains(reports[0].Error, ErrInvalidSignature.Error()) {
+		t.Fatalf("expected an invalid-signature error, got %+v", reports)
+	}
+}
+
+func TestCheckDrift_SkipsPeersWithoutEndpoint(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	addSharedRaft(g, "peer", "", nil)
+
+	reports, err := g.CheckDrift(context.Background(), "raft-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 0 {
+		t.Errorf("expected no reports, got %d", len(reports))
+	}
+}
+
+func TestReconcileRules_AdoptsMissing(t *testing.T) {
+	peer := newTestGovernance("peer")
+	addSharedRaft(peer, "otter-1", "", nil)
+	rule := addAdoptedRule(t, peer, "raft-1", "r1", "safety", "be kind", 1)
+	srv := servePeer(t, peer)
+
+	g := newTestGovernance("otter-1")
+	addSharedRaft(g, "peer", srv.URL, peer.crypto.GetSigningPublicKey())
+	proveRule(t, rule, peer, g)
+
+	result, err := g.ReconcileRules(context.Background(), "raft-1", "peer")
+	if err != nil {
+		t.Fatalf("ReconcileRules: %v", err)
+	}
+	if len(result.Adopted) != 1 || result.Adopted[0] != "r1" {
+		t.Errorf("Adopted = %v", result.Adopted)
+	}
+
+	local, _ := g.RuleSetDigest("raft-1")
+	remote, _ := peer.RuleSetDigest("raft-1")
+	if local.Root != remote.Root {
+		t.Error("roots should match after reconciliation")
+	}
+	if active := g.GetActiveRulesForRaft("raft-1")["safety"]; active == nil || active.Body != "be kind" {
+		t.Error("reconciled rule should be active")
+	}
+}
+
+func TestReconcileRules_ReportsConflict(t *testing.T) {
+	peer := newTestGovernance("peer")
+	addSharedRaft(peer, "otter-1", "", nil)
+	addAdoptedRule(t, peer, "raft-1", "r1", "safety", "be bold", 1)
+	srv := servePeer(t, peer)
+
+	g := newTestGovernance("otter-1")
+	addSharedRaft(g, "peer", srv.URL, peer.crypto.GetSigningPublicKey())
+	addAdoptedRule(t, g, "raft-1", "r1", "safety", "be kind", 1)
+
+	result, err := g.ReconcileRules(context.Background(), "raft-1", "peer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Conflicts) != 1 || len(result.Adopted) != 0 {
+		t.Errorf("expected one conflict, got %+v", result)
+	}
+	if g.GetActiveRulesForRaft("raft-1")["safety"].Body != "be kind" {
+		t.Error("conflicting rule must not overwrite the local one")
+	}
+}
+
+func TestReconcileRules_ReportsUnprovenRule(t *testing.T) {
+	peer := newTestGovernance("peer")
+	addSharedRaft(peer, "otter-1", "", nil)
+	rule := addAdoptedRule(t, peer, "raft-1", "r1", "safety", "be kind", 1)
+	srv := servePeer(t, peer)
+
+	g := newTestGovernance("otter-1")
+	addSharedRaft(g, "peer", srv.URL, peer.crypto.GetSigningPublicKey())
+
+	// Without ballots, and with too few of them, the peer's word is all there is
+	for _, voters := range [][]*Governance{nil, {peer}} {
+		proveRule(t, rule, voters...)
+		result, err := g.ReconcileRules(context.Background(), "raft-1", "peer")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Conflicts) != 1 || len(result.Adopted) != 0 {
+			t.Errorf("%d ballots: expected one conflict, got %+v", len(voters), result)
+		}
+		if g.GetActiveRulesForRaft("raft-1")["safety"] != nil {
+			t.Fatalf("%d ballots: unproven rule must not be adopted", len(voters))
+		}
+	}
+}
+
+func TestReconcileRules_RejectsForeignSigner(t *testing.T) {
+	peer := newTestGovernance("peer")
+	addSharedRaft(peer, "otter-1", "", nil)
+	addAdoptedRule(t, peer, "raft-1", "r1", "safety", "be kind", 1)
+	srv := servePeer(t, peer)
+
+	// The locally recorded signing key for "peer" does not match the one it signed with
+	other := newTestGovernance("other")
+	g := newTestGovernance("otter-1")
+	addSharedRaft(g, "peer", srv.URL, other.crypto.GetSigningPublicKey())
+
+	if _, err := g.ReconcileRules(context.Background(), "raft-1", "peer"); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+	if rules, _ := g.RaftRules("raft-1"); len(rules) != 0 {
+		t.Error("no rules should be adopted from an unverified peer")
+	}
+}
+
+func TestReconcileRules_UnknownPeer(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	addSharedRaft(g, "peer", "", nil)
+
+	if _, err := g.ReconcileRules(context.Background(), "raft-1", "stranger"); err == nil {
+		t.Error("expected error for non-member peer")
+	}
+	if _, err := g.ReconcileRules(context.Background(), "raft-1", "peer"); !errors.Is(err, errNoPeerEndpoint) {
+		t.Errorf("expected errNoPeerEndpoint, got %v", err)
+	}
+}
+
+func TestParseJoinResponse(t *testing.T) {
+	peer := newTestGovernance("peer")
+	body, _ := json.Marshal(map[string]string{
+		"status":      "join accepted",
+		"member_id":   "peer",
+		"signing_key": ExportSigningPublicKey(peer.crypto),
+	})
+
+	member, err := parseJoinResponse(body, "http://peer:8080")
+	if err != nil || member == nil || member.ID != "peer" || member.Endpoint != "http://peer:8080" {
+		t.Fatalf("unexpected member: %+v", member)
+	}
+	if len(member.SigningKey) == 0 {
+		t.Error("signing key should be parsed")
+	}
+
+	if member, _ := parseJoinResponse([]byte(`{"status":"join accepted"}`), "x"); member != nil {
+		t.Error("legacy response should yield no member")
+	}
+}
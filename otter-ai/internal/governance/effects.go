@@ -0,0 +1,163 @@
+package governance
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"otter-ai/internal/errs"
+)
+
+// EffectKey names a runtime setting a rule can change
+type EffectKey string
+
+const (
+	// EffectChatTemperature sets the temperature of chat replies, 0 to 2
+	EffectChatTemperature EffectKey = "chat.temperature"
+	// EffectChannelDisabled stops the agent answering a plugin platform,
+	// "slack", or one of its channels, "slack:C123". Unlike the other
+	// effects it adds up across rules.
+	EffectChannelDisabled EffectKey = "channel.disabled"
+	// EffectMemoryRetentionDays sets the memory retention half-life in days
+	EffectMemoryRetentionDays EffectKey = "memory.retention_days"
+)
+
+// MaxRetentionDays bounds the memory.retention_days effect
+const MaxRetentionDays = 3650
+
+// RuleEffect is a machine-readable line of a rule body, written as
+// "effect: <key> = <value>", applied to the agent while the rule is active
+type RuleEffect struct {
+	Key   EffectKey `json:"key"`
+	Value string    `json:"value"`
+}
+
+// ActiveEffect is an effect in force and the rule it comes from
+type ActiveEffect struct {
+	RuleEffect
+	RuleID string `json:"rule_id"`
+	RaftID string `json:"raft_id"`
+	Scope  string `json:"scope"`
+}
+
+// ParseRuleEffects reads the effect lines of a rule body, checking their
+// keys and values. Other lines are left to the prompt and enforcement.
+func ParseRuleEffects(body string) ([]RuleEffect, error) {
+	var effects []RuleEffect
+	for _, line := range strings.Split(body, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), ":")
+		if !found || !strings.EqualFold(strings.TrimSpace(key), "effect") {
+			continue
+		}
+		name, setting, found := strings.Cut(value, "=")
+		if !found {
+			return nil, errs.Errorf(errs.ErrInvalid, "effect %q must be written as <key> = <value>", strings.TrimSpace(value))
+		}
+		effect := RuleEffect{Key: EffectKey(strings.ToLower(strings.TrimSpace(name))), Value: strings.TrimSpace(setting)}
+		if err := effect.Validate(); err != nil {
+			return nil, err
+		}
+		effects = append(effects, effect)
+	}
+	return effects, nil
+}
+
+// Validate checks the effect's key and value
+func (e RuleEffect) Validate() error {
+	switch e.Key {
+	case EffectChatTemperature:
+		t, err := strconv.ParseFloat(e.Value, 32)
+		if err != nil || t < 0 || t > 2 {
+			return errs.Errorf(errs.ErrInvalid, "%s must be between 0 and 2, not %q", e.Key, e.Value)
+		}
+	case EffectChannelDisabled:
+		platform, _, _ := strings.Cut(e.Value, ":")
+		if platform == "" || strings.ContainsAny(e.Value, " \t") {
+			return errs.Errorf(errs.ErrInvalid, "%s must name a platform or platform:channel, not %q", e.Key, e.Value)
+		}
+	case EffectMemoryRetentionDays:
+		days, err := strconv.Atoi(e.Value)
+		if err != nil || days < 1 || days > MaxRetentionDays {
+			return errs.Errorf(errs.ErrInvalid, "%s must be a whole number of days from 1 to %d, not %q", e.Key, MaxRetentionDays, e.Value)
+		}
+	default:
+		return errs.Errorf(errs.ErrInvalid, "unknown effect %q", e.Key)
+	}
+	return nil
+}
+
+// String writes the effect as a rule body line
+func (e RuleEffect) String() string {
+	return fmt.Sprintf("effect: %s = %s", e.Key, e.Value)
+}
+
+// Float returns the effect's value as a number, for chat.temperature
+func (e RuleEffect) Float() float32 {
+	f, _ := strconv.ParseFloat(e.Value, 32)
+	return float32(f)
+}
+
+// Int returns the effect's value as a whole number, for memory.retention_days
+func (e RuleEffect) Int() int {
+	n, _ := strconv.Atoi(e.Value)
+	return n
+}
+
+// RuleEffects returns the effects of the active rules, ordered by key. Of
+// several rules setting the same key the most recently adopted wins;
+// disabled channels add up. Rules of provisional rafts have no effect, and
+// a rule stops having its effects once overridden, suspended or archived.
+func (g *Governance) RuleEffects() []ActiveEffect {
+	g.rafts.mu.RLock()
+	provisional := make(map[string]bool)
+	for id, raft := range g.rafts.rafts {
+		if raft.Provisional {
+			provisional[id] = true
+		}
+	}
+	g.rafts.mu.RUnlock()
+
+	var rules []*Rule
+	for _, rule := range g.RulesForScope("") {
+		if !provisional[rule.RaftID] {
+			rules = append(rules, rule)
+		}
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		return adoptedBefore(rules[i], rules[j])
+	})
+
+	winners := make(map[EffectKey]ActiveEffect)
+	disabled := make(map[string]ActiveEffect)
+	for _, rule := range rules {
+		effects, err := ParseRuleEffects(rule.Body)
+		if err != nil {
+			g.log().Warn("ignoring unreadable rule effects", "rule_id", rule.RuleID, "raft_id", rule.RaftID, "error", err)
+			continue
+		}
+		for _, effect := range effects {
+			active := ActiveEffect{RuleEffect: effect, RuleID: rule.RuleID, RaftID: rule.RaftID, Scope: rule.Scope}
+			if effect.Key == EffectChannelDisabled {
+				disabled[effect.Value] = active
+			} else {
+				winners[effect.Key] = active
+			}
+		}
+	}
+
+	active := make([]ActiveEffect, 0, len(winners)+len(disabled))
+	for _, effect := range winners {
+		active = append(active, effect)
+	}
+	for _, effect := range disabled {
+		active = append(active, effect)
+	}
+	sort.Slice(active, func(i, j int) bool {
+		if active[i].Key != active[j].Key {
+			return active[i].Key < active[j].Key
+		}
+		return active[i].Value < active[j].Value
+	})
+	return active
+}
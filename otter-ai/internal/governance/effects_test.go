@@ -0,0 +1,67 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"otter-ai/internal/errs"
+)
+
+func TestParseRuleEffects(t *testing.T) {
+	effects, err := ParseRuleEffects("Keep replies calm.\nEffect: Chat.Temperature = 0.3\neffect: channel.disabled = slack:C123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []RuleEffect{
+		{Key: EffectChatTemperature, Value: "0.3"},
+		{Key: EffectChannelDisabled, Value: "slack:C123"},
+	}
+	if !reflect.DeepEqual(effects, want) {
+		t.Errorf("effects = %+v, want %+v", effects, want)
+	}
+	if effects[0].Float() != 0.3 || effects[0].String() != "effect: chat.temperature = 0.3" {
+		t.Errorf("temperature = %v, line = %q", effects[0].Float(), effects[0].String())
+	}
+
+	for _, body := range []string{
+		"effect: chat.temperature",
+		"effect: chat.temperature = 3",
+		"effect: memory.retention_days = 0",
+		"effect: channel.disabled = :C123",
+		"effect: shell.exec = ls",
+	} {
+		if _, err := ParseRuleEffects(body); !errors.Is(err, errs.ErrInvalid) {
+			t.Errorf("%q: err = %v, want invalid", body, err)
+		}
+	}
+}
+
+func TestRuleEffects_LatestWinsAndOverrideReverts(t *testing.T) {
+	g := newTestGovernance("otter-1")
+
+	calm := adoptTestRule(t, g, &Rule{Scope: "tone", Body: "be calm\neffect: chat.temperature = 0.2\neffect: channel.disabled = slack"})
+	earlier := time.Now().Add(-time.Hour)
+	calm.AdoptedAt = &earlier
+	lively := adoptTestRule(t, g, &Rule{Scope: "style", Body: "be lively\neffect: chat.temperature = 0.9\neffect: channel.disabled = discord:general"})
+
+	effects := g.RuleEffects()
+	if len(effects) != 3 {
+		t.Fatalf("effects = %+v, want two disabled channels and one temperature", effects)
+	}
+	if effects[2].Key != EffectChatTemperature || effects[2].Value != "0.9" || effects[2].RuleID != lively.RuleID {
+		t.Errorf("temperature = %+v, want the later rule's 0.9", effects[2])
+	}
+
+	adoptTestRule(t, g, &Rule{Body: "be lively", BaseRuleID: lively.RuleID})
+	effects = g.RuleEffects()
+	if len(effects) != 2 || effects[1].Value != "0.2" || effects[1].RuleID != calm.RuleID {
+		t.Errorf("after override effects = %+v, want the earlier rule's 0.2 back", effects)
+	}
+
+	if _, err := g.ProposeRule(context.Background(), "otter-1", &Rule{Scope: "tone", Body: "effect: memory.retention_days = forever", ProposedBy: "otter-1"}); !errors.Is(err, errs.ErrInvalid) {
+		t.Errorf("proposing an unreadable effect: err = %v, want invalid", err)
+	}
+}
@@ -0,0 +1,278 @@
+package governance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"otter-ai/internal/llm"
+	"otter-ai/internal/prompts"
+)
+
+// EnforcementMode decides how active rules constrain the agent beyond being
+// part of its prompt
+type EnforcementMode string
+
+const (
+	// EnforcementOff only puts rules in the prompt
+	EnforcementOff EnforcementMode = "off"
+	// EnforcementStructured enforces the directives in rule bodies
+	EnforcementStructured EnforcementMode = "structured"
+	// EnforcementLLM also has the LLM judge replies against free-text rules
+	EnforcementLLM EnforcementMode = "llm"
+)
+
+// DirectiveKind is what a rule directive forbids
+type DirectiveKind string
+
+const (
+	DirectiveDenyTool    DirectiveKind = "deny tool"    // Calling the named tool
+	DirectiveDenyText    DirectiveKind = "deny text"    // Replies or tool arguments containing the text, ignoring case
+	DirectiveDenyPattern DirectiveKind = "deny pattern" // Replies or tool arguments matching the regular expression
+)
+
+// RuleDirective is one machine-checkable line of a rule body, written as
+// "<kind>: <value>", such as "deny tool: propose_rule"
+type RuleDirective struct {
+	Kind    DirectiveKind
+	Value   string
+	pattern *regexp.Regexp
+}
+
+// ViolationTarget is the agent output a rule was checked against
+type ViolationTarget string
+
+const (
+	TargetResponse ViolationTarget = "response"
+	TargetToolCall ViolationTarget = "tool_call"
+)
+
+// ViolationOutcome is what the agent did about a violation
+type ViolationOutcome string
+
+const (
+	ViolationBlocked     ViolationOutcome = "blocked"     // The output was withheld
+	ViolationRegenerated ViolationOutcome = "regenerated" // The reply was written again
+)
+
+// RuleViolation is an agent output that breaks an active rule
+type RuleViolation struct {
+	RuleID  string           `json:"rule_id"`
+	RaftID  string           `json:"raft_id"`
+	Scope   string           `json:"scope"`
+	Target  ViolationTarget  `json:"target"`
+	Tool    string           `json:"tool,omitempty"`
+	Reason  string           `json:"reason"`
+	Outcome ViolationOutcome `json:"outcome,omitempty"`
+}
+
+// ParseRuleDirectives reads the directive lines of a rule body. Other lines
+// are free text and are left to the prompt, or to the LLM check.
+func ParseRuleDirectives(body string) ([]RuleDirective, error) {
+	var directives []RuleDirective
+	for _, line := range strings.Split(body, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), ":")
+		if !found {
+			continue
+		}
+		kind := DirectiveKind(strings.ToLower(strings.Join(strings.Fields(key), " ")))
+		value = strings.TrimSpace(value)
+		switch kind {
+		case DirectiveDenyTool, DirectiveDenyText:
+			if value == "" {
+				return nil, fmt.Errorf("%s directive needs a value", kind)
+			}
+			if kind == DirectiveDenyTool {
+				for _, tool := range strings.Split(value, ",") {
+					if tool = strings.TrimSpace(tool); tool != "" {
+						directives = append(directives, RuleDirective{Kind: kind, Value: tool})
+					}
+				}
+				continue
+			}
+			directives = append(directives, RuleDirective{Kind: kind, Value: value})
+		case DirectiveDenyPattern:
+			pattern, err := regexp.Compile(value)
+			if err != nil || value == "" {
+				return nil, fmt.Errorf("invalid deny pattern %q", value)
+			}
+			directives = append(directives, RuleDirective{Kind: kind, Value: value, pattern: pattern})
+		}
+	}
+	return directives, nil
+}
+
+// matchesText reports whether a text directive matches s
+func (d RuleDirective) matchesText(s string) bool {
+	switch d.Kind {
+	case DirectiveDenyText:
+		return strings.Contains(strings.ToLower(s), strings.ToLower(d.Value))
+	case DirectiveDenyPattern:
+		return d.pattern.MatchString(s)
+	}
+	return false
+}
+
+// Enforcement returns how rules constrain the agent, defaulting to structured
+func (g *Governance) Enforcement() EnforcementMode {
+	if g.config.Enforcement == "" {
+		return EnforcementStructured
+	}
+	return g.config.Enforcement
+}
+
+// enforcedRule is an active rule with its parsed directives
+type enforcedRule struct {
+	rule       *Rule
+	directives []RuleDirective
+}
+
+// enforcedRules returns the rules governing a conversation scope, as
+// RulesForScope resolves them. Rules whose directives no longer parse are
+// enforced as free text.
+func (g *Governance) enforcedRules(scope string) []enforcedRule {
+	if g.Enforcement() == EnforcementOff {
+		return nil
+	}
+	rules := g.RulesForScope(scope)
+	enforced := make([]enforcedRule, 0, len(rules))
+	for _, rule := range rules {
+		directives, _ := ParseRuleDirectives(rule.Body)
+		enforced = append(enforced, enforcedRule{rule: rule, directives: directives})
+	}
+	return enforced
+}
+
+func newViolation(rule *Rule, target ViolationTarget, tool, reason string) RuleViolation {
+	return RuleViolation{RuleID: rule.RuleID, RaftID: rule.RaftID, Scope: rule.Scope, Target: target, Tool: tool, Reason: reason}
+}
+
+// CheckToolCall returns the rules governing scope that a tool call would
+// break: rules denying the tool, and text directives matched by its
+// arguments. An empty scope checks every active rule.
+func (g *Governance) CheckToolCall(ctx context.Context, scope, tool string, args map[string]string) []RuleViolation {
+	var violations []RuleViolation
+	for _, enforced := range g.enforcedRules(scope) {
+		for _, directive := range enforced.directives {
+			if directive.Kind == DirectiveDenyTool {
+				if strings.EqualFold(directive.Value, tool) {
+					violations = append(violations, newViolation(enforced.rule, TargetToolCall, tool, "the tool "+tool+" is denied"))
+					break
+				}
+				continue
+			}
+			if name, ok := matchingArgument(directive, args); ok {
+				violations = append(violations, newViolation(enforced.rule, TargetToolCall, tool,
+					fmt.Sprintf("argument %s matches %s %q", name, directive.Kind, directive.Value)))
+				break
+			}
+		}
+	}
+	return violations
+}
+
+// matchingArgument returns the first argument, by name, a text directive matches
+func matchingArgument(directive RuleDirective, args map[string]string) (string, bool) {
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if directive.matchesText(args[name]) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// CheckResponse returns the rules governing scope that a reply breaks.
+// Text directives are always checked; with EnforcementLLM, rules without
+// directives are put to the LLM. An LLM failure is logged and skips that
+// check, since the rules are still in the prompt.
+func (g *Governance) CheckResponse(ctx context.Context, scope, message, response string) []RuleViolation {
+	var violations []RuleViolation
+	var freeText []*Rule
+	for _, enforced := range g.enforcedRules(scope) {
+		if len(enforced.directives) == 0 {
+			freeText = append(freeText, enforced.rule)
+			continue
+		}
+		for _, directive := range enforced.directives {
+			if directive.matchesText(response) {
+				violations = append(violations, newViolation(enforced.rule, TargetResponse, "",
+					fmt.Sprintf("reply matches %s %q", directive.Kind, directive.Value)))
+				break
+			}
+		}
+	}
+
+	if g.Enforcement() == EnforcementLLM && len(freeText) > 0 {
+		violations = append(violations, g.judgeResponse(ctx, freeText, message, response)...)
+	}
+	return violations
+}
+
+// judgeResponse asks the LLM which free-text rules a reply breaks
+func (g *Governance) judgeResponse(ctx context.Context, rules []*Rule, message, response string) []RuleViolation {
+	provider := g.llmProvider()
+	if provider == nil {
+		return nil
+	}
+	resp, err := provider.Complete(ctx, &llm.CompletionRequest{
+		Prompt:  g.buildRuleCheckPrompt(rules, message, response),
+		Profile: llm.ProfileNegotiation,
+	})
+	if err != nil || resp == nil {
+		g.log().WarnContext(ctx, "rule check skipped", "error", err)
+		return nil
+	}
+
+	var parsed struct {
+		Violations []struct {
+			RuleID string `json:"rule_id"`
+			Reason string `json:"reason"`
+		} `json:"violations"`
+	}
+	if err := json.Unmarshal([]byte(trimCodeFence(resp.Text)), &parsed); err != nil {
+		g.log().WarnContext(ctx, "rule check reply was not JSON", "error", err)
+		return nil
+	}
+
+	byID := make(map[string]*Rule, len(rules))
+	for _, rule := range rules {
+		byID[rule.RuleID] = rule
+	}
+	var violations []RuleViolation
+	for _, v := range parsed.Violations {
+		rule, ok := byID[v.RuleID]
+		if !ok {
+			continue // Only rules that were asked about
+		}
+		delete(byID, v.RuleID)
+		violations = append(violations, newViolation(rule, TargetResponse, "", strings.TrimSpace(v.Reason)))
+	}
+	return violations
+}
+
+// buildRuleCheckPrompt asks which of the rules a reply breaks
+func (g *Governance) buildRuleCheckPrompt(rules []*Rule, message, response string) string {
+	return g.renderPrompt(prompts.RuleCheck, struct {
+		Rules             []*Rule
+		Message, Response string
+	}{rules, message, response})
+}
+
+// RecordRuleViolations appends each violation, with what was done about it,
+// to the audit log
+func (g *Governance) RecordRuleViolations(ctx context.Context, violations []RuleViolation, outcome ViolationOutcome) {
+	for _, violation := range violations {
+		violation.Outcome = outcome
+		g.log().InfoContext(ctx, "agent output broke a rule", "rule_id", violation.RuleID, "raft_id", violation.RaftID,
+			"target", string(violation.Target), "outcome", string(outcome), "reason", violation.Reason)
+		g.recordAudit(AuditRuleViolated, violation.RaftID, violation.RuleID, g.config.ID, violation)
+	}
+}
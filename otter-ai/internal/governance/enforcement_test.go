@@ -0,0 +1,93 @@
+package governance
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseRuleDirectives(t *testing.T) {
+	directives, err := ParseRuleDirectives("Keep secrets safe.\nDeny tool: propose_rule, vote_on_proposal\ndeny text: hunter2\ndeny pattern: \\b\\d{3}-\\d{2}-\\d{4}\\b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(directives) != 4 || directives[1].Value != "vote_on_proposal" || directives[2].Kind != DirectiveDenyText {
+		t.Fatalf("directives = %+v", directives)
+	}
+	if !directives[3].matchesText("ssn 123-45-6789") || directives[3].matchesText("call 555-1234") {
+		t.Error("deny pattern matched the wrong text")
+	}
+
+	if directives, err := ParseRuleDirectives("Be kind: always."); err != nil || len(directives) != 0 {
+		t.Errorf("free text parsed as %+v, %v", directives, err)
+	}
+	for _, body := range []string{"deny pattern: (", "deny tool:"} {
+		if _, err := ParseRuleDirectives(body); err == nil {
+			t.Errorf("expected %q to be rejected", body)
+		}
+	}
+}
+
+func TestProposeRule_RejectsInvalidDirective(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	if _, err := g.ProposeRule(context.Background(), "otter-1", &Rule{Scope: "safety", Body: "deny pattern: [", ProposedBy: "otter-1"}); err == nil {
+		t.Error("expected a rule with an invalid pattern to be rejected")
+	}
+}
+
+func TestCheckToolCall(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	g.activateRule(&Rule{RuleID: "r1", RaftID: "otter-1", Scope: "governance", Body: "deny tool: propose_rule"})
+	g.activateRule(&Rule{RuleID: "r2", RaftID: "otter-1", Scope: "privacy", Body: "deny text: hunter2"})
+	ctx := context.Background()
+
+	violations := g.CheckToolCall(ctx, "", "propose_rule", map[string]string{"rule_body": "share hunter2"})
+	if len(violations) != 2 || violations[0].RuleID != "r1" || violations[1].Reason != `argument rule_body matches deny text "hunter2"` {
+		t.Errorf("violations = %+v", violations)
+	}
+	if violations := g.CheckToolCall(ctx, "", "search_memories", map[string]string{"query": "cats"}); len(violations) != 0 {
+		t.Errorf("allowed call reported %+v", violations)
+	}
+
+	g.config.Enforcement = EnforcementOff
+	if violations := g.CheckToolCall(ctx, "", "propose_rule", nil); len(violations) != 0 {
+		t.Errorf("enforcement off reported %+v", violations)
+	}
+}
+
+func TestCheckResponse_LLM(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	g.config.Enforcement = EnforcementLLM
+	g.activateRule(&Rule{RuleID: "r1", RaftID: "otter-1", Scope: "tone", Body: "Never use sarcasm."})
+	g.activateRule(&Rule{RuleID: "r2", RaftID: "otter-1", Scope: "privacy", Body: "deny text: hunter2"})
+	provider := &scriptedLLMProvider{replies: []string{`{"violations":[{"rule_id":"r1","reason":"the reply is sarcastic"},{"rule_id":"r9","reason":"made up"}]}`}}
+	g.SetLLMProvider(provider)
+
+	violations := g.CheckResponse(context.Background(), "", "hi", "Oh great, hunter2, how original.")
+	if len(violations) != 2 || violations[0].RuleID != "r2" || violations[1].RuleID != "r1" || violations[1].Reason != "the reply is sarcastic" {
+		t.Errorf("violations = %+v", violations)
+	}
+	// Only the free-text rule was put to the LLM
+	if len(provider.prompts) != 1 || !contains(provider.prompts[0], "Never use sarcasm.") || contains(provider.prompts[0], "deny text") {
+		t.Errorf("prompts = %v", provider.prompts)
+	}
+
+	// Structured enforcement leaves free-text rules to the prompt
+	g.config.Enforcement = EnforcementStructured
+	if violations := g.CheckResponse(context.Background(), "", "hi", "Oh great."); len(violations) != 0 {
+		t.Errorf("structured enforcement reported %+v", violations)
+	}
+}
+
+func TestRecordRuleViolations(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	g.RecordRuleViolations(context.Background(), []RuleViolation{{RuleID: "r1", RaftID: "otter-1", Scope: "privacy", Target: TargetResponse}}, ViolationBlocked)
+
+	var found bool
+	g.EachAuditEntry(context.Background(), AuditFilter{}, func(entry AuditEntry) error {
+		found = found || entry.Action == AuditRuleViolated && entry.SubjectID == "r1" && contains(string(entry.Data), `"outcome":"blocked"`)
+		return nil
+	})
+	if !found {
+		t.Error("violation was not audited")
+	}
+}
@@ -0,0 +1,104 @@
+package governance
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"otter-ai/internal/memory"
+)
+
+// ErasureRequest asks for memories to be erased permanently
+type ErasureRequest struct {
+	Criteria    memory.ForgetCriteria
+	RequestedBy string
+	Reason      string
+	DryRun      bool // Only report what would be erased
+}
+
+// Erasure reports an erasure. Its tombstone is the memory.erased audit
+// entry with ErasureID as its subject.
+type Erasure struct {
+	ErasureID   string    `json:"erasure_id,omitempty"` // Empty for dry runs
+	RequestedBy string    `json:"requested_by"`
+	Reason      string    `json:"reason,omitempty"`
+	ErasedAt    time.Time `json:"erased_at"`
+	memory.ForgetResult
+}
+
+// ErasureAudit is the data of memory.erased audit entries: a tombstone of
+// what an erasure matched, signed with the rest of the log. The user and
+// text matched are kept as hashes so the log doesn't repeat what was
+// erased, and memories only by ID and content hash.
+type ErasureAudit struct {
+	IDs       []string                 `json:"ids,omitempty"`
+	UserHash  string                   `json:"user_hash,omitempty"`
+	MatchHash string                   `json:"match_hash,omitempty"`
+	Since     *time.Time               `json:"since,omitempty"`
+	Until     *time.Time               `json:"until,omitempty"`
+	Types     []memory.MemoryType      `json:"types,omitempty"`
+	Reason    string                   `json:"reason,omitempty"`
+	Erased    []memory.ForgottenMemory `json:"erased"`
+	Held      []memory.ForgottenMemory `json:"held,omitempty"`
+}
+
+// EraseMemories permanently erases the memories matching the request's
+// criteria and records a tombstone in the audit log, even when nothing
+// matched, so the request is shown to have been honored. Memories under
+// legal hold are kept and listed as held.
+func (g *Governance) EraseMemories(ctx context.Context, req ErasureRequest) (*Erasure, error) {
+	if req.RequestedBy == "" {
+		return nil, fmt.Errorf("requested_by is required")
+	}
+	if g.memory == nil {
+		return nil, fmt.Errorf("memory is not configured")
+	}
+
+	result, err := g.memory.Forget(ctx, req.Criteria, req.DryRun)
+	if err != nil {
+		return nil, err
+	}
+	erasure := &Erasure{
+		RequestedBy:  req.RequestedBy,
+		Reason:       req.Reason,
+		ErasedAt:     time.Now(),
+		ForgetResult: *result,
+	}
+	if req.DryRun {
+		return erasure, nil
+	}
+
+	erasure.ErasureID = generateID(fmt.Sprintf("erase|%s|%d", req.RequestedBy, erasure.ErasedAt.UnixNano()))
+	tombstone := ErasureAudit{
+		IDs:       req.Criteria.IDs,
+		UserHash:  erasureHash(req.Criteria.UserID),
+		MatchHash: erasureHash(req.Criteria.Match),
+		Types:     req.Criteria.Types,
+		Reason:    req.Reason,
+		Erased:    result.Erased,
+		Held:      result.Held,
+	}
+	if !req.Criteria.Since.IsZero() {
+		tombstone.Since = &req.Criteria.Since
+	}
+	if !req.Criteria.Until.IsZero() {
+		tombstone.Until = &req.Criteria.Until
+	}
+	g.recordAudit(AuditMemoryErased, g.config.ID, erasure.ErasureID, req.RequestedBy, tombstone)
+
+	g.log().InfoContext(ctx, "erased memories", "erasure_id", erasure.ErasureID, "requested_by", req.RequestedBy,
+		"erased", len(result.Erased), "held", len(result.Held))
+	return erasure, nil
+}
+
+// erasureHash returns the hex SHA-256 a tombstone records an erased user
+// or text by, empty for empty input
+func erasureHash(value string) string {
+	if value == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
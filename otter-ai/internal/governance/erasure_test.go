@@ -0,0 +1,80 @@
+package governance
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"otter-ai/internal/memory"
+)
+
+func TestEraseMemories_RecordsTombstone(t *testing.T) {
+	g, mem, _, _ := newTestHoldGovernance(t)
+	ctx := context.Background()
+
+	store := func(content string) *memory.MemoryRecord {
+		record := &memory.MemoryRecord{Type: memory.MemoryTypeLongTerm, Content: content, Embedding: []float32{1, 0},
+			Metadata: map[string]interface{}{"user_id": "slack:U1"}}
+		if err := mem.Store(ctx, record); err != nil {
+			t.Fatal(err)
+		}
+		return record
+	}
+	erased := store("Dana lives on Kelp Street")
+	held := store("Dana signed the contract")
+	if _, err := g.PlaceHold(ctx, HoldRequest{Kind: HoldMemory, SubjectID: held.ID, Reason: "litigation", PlacedBy: "counsel"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := g.EraseMemories(ctx, ErasureRequest{Criteria: memory.ForgetCriteria{Match: "dana"}}); err == nil {
+		t.Error("expected error without requested_by")
+	}
+
+	preview, err := g.EraseMemories(ctx, ErasureRequest{Criteria: memory.ForgetCriteria{UserID: "slack:U1", Match: "Dana"}, RequestedBy: "slack:U1", DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.ErasureID != "" || len(preview.Erased) != 1 {
+		t.Errorf("preview = %+v", preview)
+	}
+
+	erasure, err := g.EraseMemories(ctx, ErasureRequest{Criteria: memory.ForgetCriteria{UserID: "slack:U1", Match: "Dana"}, RequestedBy: "slack:U1", Reason: "GDPR request"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if erasure.ErasureID == "" || len(erasure.Erased) != 1 || erasure.Erased[0].ID != erased.ID || len(erasure.Held) != 1 {
+		t.Fatalf("erasure = %+v", erasure)
+	}
+	if _, err := mem.Get(ctx, held.ID, memory.MemoryTypeLongTerm); err != nil {
+		t.Errorf("held memory should be kept: %v", err)
+	}
+
+	var tombstone *AuditEntry
+	g.EachAuditEntry(ctx, AuditFilter{}, func(entry AuditEntry) error {
+		if entry.Action == AuditMemoryErased {
+			tombstone = &entry
+		}
+		return nil
+	})
+	if tombstone == nil || tombstone.SubjectID != erasure.ErasureID || tombstone.Actor != "slack:U1" {
+		t.Fatalf("tombstone = %+v", tombstone)
+	}
+	var data ErasureAudit
+	if err := json.Unmarshal(tombstone.Data, &data); err != nil {
+		t.Fatal(err)
+	}
+	if data.UserHash != erasureHash("slack:U1") || data.MatchHash != erasureHash("Dana") || data.Reason != "GDPR request" ||
+		len(data.Erased) != 1 || data.Erased[0].ContentHash == "" || len(data.Held) != 1 {
+		t.Errorf("tombstone data = %+v", data)
+	}
+	for _, leaked := range []string{"slack:U1", "Dana", "Kelp Street"} {
+		if strings.Contains(string(tombstone.Data), leaked) {
+			t.Errorf("tombstone repeats erased data %q: %s", leaked, tombstone.Data)
+		}
+	}
+
+	if report, err := g.VerifyAudit(ctx); err != nil || !report.Valid {
+		t.Errorf("VerifyAudit = %+v, %v", report, err)
+	}
+}
@@ -0,0 +1,116 @@
+package governance
+
+import (
+	"time"
+
+	"otter-ai/internal/events"
+)
+
+// ProposalEvent describes a proposal in published events
+type ProposalEvent struct {
+	ProposalID     string         `json:"proposal_id"`
+	RaftID         string         `json:"raft_id"`
+	RuleID         string         `json:"rule_id"`
+	Scope          string         `json:"scope"`
+	Body           string         `json:"body"`
+	ProposedBy     string         `json:"proposed_by"`
+	Status         ProposalStatus `json:"status"`
+	Result         ProposalResult `json:"result"`
+	YesVotes       int            `json:"yes_votes"`
+	NoVotes        int            `json:"no_votes"`
+	AbstainVotes   int            `json:"abstain_votes"`
+	Deadline       time.Time      `json:"deadline"`
+	Expired        bool           `json:"expired,omitempty"`
+	Kind           ProposalKind   `json:"kind"`
+	TargetMember   string         `json:"target_member_id,omitempty"` // Eviction and admission proposals only
+	EligibleVoters []string       `json:"eligible_voters,omitempty"`
+	Reason         string         `json:"reason,omitempty"`
+}
+
+// VoteEvent describes a recorded vote in published events
+type VoteEvent struct {
+	ProposalID string   `json:"proposal_id"`
+	RaftID     string   `json:"raft_id"`
+	VoterID    string   `json:"voter_id"`
+	Vote       VoteType `json:"vote"`
+}
+
+// RuleEvent describes an adopted rule in published events
+type RuleEvent struct {
+	RuleID     string `json:"rule_id"`
+	RaftID     string `json:"raft_id"`
+	Scope      string `json:"scope"`
+	Body       string `json:"body"`
+	Version    int    `json:"version"`
+	BaseRuleID string `json:"base_rule_id,omitempty"`
+	ProposedBy string `json:"proposed_by"`
+}
+
+// MemberEvent describes a membership change in published events
+type MemberEvent struct {
+	RaftID     string          `json:"raft_id"`
+	MemberID   string          `json:"member_id"`
+	State      MembershipState `json:"state"`
+	InductedBy string          `json:"inducted_by,omitempty"`
+	ProposalID string          `json:"proposal_id,omitempty"`
+}
+
+// SetEventBus sets the bus governance changes are published to
+func (g *Governance) SetEventBus(bus *events.Bus) {
+	g.events.Store(bus)
+}
+
+// publish sends an event if an event bus is configured. It never blocks,
+// so it is safe to call while holding registry locks.
+func (g *Governance) publish(eventType string, data interface{}) {
+	g.events.Load().Publish(eventType, data)
+}
+
+// proposalEvent snapshots a proposal for publishing. The caller must hold
+// the proposal registry lock or own the proposal.
+func proposalEvent(proposal *Proposal) ProposalEvent {
+	event := ProposalEvent{
+		ProposalID:     proposal.ProposalID,
+		RaftID:         proposal.RaftID,
+		ProposedBy:     proposal.ProposedBy,
+		Status:         proposal.Status,
+		Result:         proposal.Result,
+		Deadline:       proposal.Deadline,
+		Expired:        proposal.Expired,
+		Kind:           proposal.kind(),
+		Reason:         proposal.Reason,
+		EligibleVoters: proposal.EligibleVoters,
+	}
+	if proposal.Kind == ProposalKindEviction || proposal.Kind == ProposalKindAdmission {
+		event.TargetMember = proposal.TargetMemberID
+	}
+	if proposal.Rule != nil {
+		event.RuleID = proposal.Rule.RuleID
+		event.Scope = proposal.Rule.Scope
+		event.Body = proposal.Rule.Body
+	}
+	for _, vote := range proposal.Votes {
+		switch vote {
+		case VoteYes:
+			event.YesVotes++
+		case VoteNo:
+			event.NoVotes++
+		case VoteAbstain:
+			event.AbstainVotes++
+		}
+	}
+	return event
+}
+
+// ruleEvent snapshots a rule for publishing
+func ruleEvent(rule *Rule) RuleEvent {
+	return RuleEvent{
+		RuleID:     rule.RuleID,
+		RaftID:     rule.RaftID,
+		Scope:      rule.Scope,
+		Body:       rule.Body,
+		Version:    rule.Version,
+		BaseRuleID: rule.BaseRuleID,
+		ProposedBy: rule.ProposedBy,
+	}
+}
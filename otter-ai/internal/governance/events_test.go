@@ -0,0 +1,53 @@
+package governance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"otter-ai/internal/events"
+)
+
+func TestGovernanceEvents_ProposalLifecycle(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	bus := events.NewBus()
+	g.SetEventBus(bus)
+	sub := bus.Subscribe(0)
+	defer sub.Close()
+
+	rule := &Rule{Scope: "safety", Body: "be kind", ProposedBy: "otter-1"}
+	proposal, err := g.ProposeRule(context.Background(), "otter-1", rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CastVote(context.Background(), proposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+
+	// Solo raft: the YES vote adopts the rule and closes the proposal
+	want := []string{events.ProposalCreated, events.VoteCast, events.RuleAdopted, events.ProposalClosed}
+	for _, eventType := range want {
+		select {
+		case event := <-sub.C:
+			if event.Type != eventType {
+				t.Fatalf("got %s, want %s", event.Type, eventType)
+			}
+			if event.Type == events.ProposalClosed {
+				closed := event.Data.(ProposalEvent)
+				if closed.Result != ResultAdopted || closed.YesVotes != 1 {
+					t.Errorf("unexpected closed proposal event: %+v", closed)
+				}
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %s", eventType)
+		}
+	}
+}
+
+func TestGovernanceEvents_NoBus(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	rule := &Rule{Scope: "safety", Body: "be kind", ProposedBy: "otter-1"}
+	if _, err := g.ProposeRule(context.Background(), "otter-1", rule); err != nil {
+		t.Fatalf("ProposeRule without an event bus: %v", err)
+	}
+}
@@ -0,0 +1,280 @@
+package governance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"otter-ai/internal/events"
+)
+
+// ProposalKind distinguishes what an adopted proposal changes
+type ProposalKind string
+
+const (
+	ProposalKindRule      ProposalKind = "rule"      // Adopts Rule
+	ProposalKindEviction  ProposalKind = "eviction"  // Revokes TargetMemberID
+	ProposalKindAdmission ProposalKind = "admission" // Admits pending TargetMemberID
+	// Emergency deactivation of Rule, fast-tracked by SuspensionQuorum
+	ProposalKindSuspension ProposalKind = "suspension"
+	// Full re-vote on a suspended Rule: reinstates it if adopted
+	ProposalKindReinstatement ProposalKind = "reinstatement"
+)
+
+// MemberRevocation is the payload of a member.revoked federation message.
+// Ballots carry the signed votes that adopted the eviction so peers can
+// check them against the signing keys they hold.
+type MemberRevocation struct {
+	RaftID     string       `json:"raft_id"`
+	MemberID   string       `json:"member_id"`
+	ProposalID string       `json:"proposal_id"`
+	Reason     string       `json:"reason,omitempty"`
+	RevokedAt  time.Time    `json:"revoked_at"`
+	Ballots    []SignedVote `json:"ballots"`
+}
+
+// ProposeEviction opens a proposal to revoke a member of a raft. It is
+// adopted by a super-majority of the other active members; the member being
+// evicted cannot vote on it. Zero votingPeriod uses the default.
+func (g *Governance) ProposeEviction(ctx context.Context, raftID, memberID, proposedBy, reason string, votingPeriod time.Duration) (*Proposal, error) {
+	if err := g.writable(); err != nil {
+		return nil, err
+	}
+	if err := ValidateVotingPeriod(votingPeriod); err != nil {
+		return nil, err
+	}
+	if votingPeriod == 0 {
+		votingPeriod = g.VotingPeriod()
+	}
+	if memberID == proposedBy {
+		return nil, fmt.Errorf("a member cannot propose its own eviction")
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	raft, err := g.liveRaft(raftID)
+	if err != nil {
+		return nil, err
+	}
+
+	raft.mu.RLock()
+	proposer, proposerExists := raft.Members[proposedBy]
+	target, targetExists := raft.Members[memberID]
+	raft.mu.RUnlock()
+
+	if !proposerExists || proposer.State != StateActive {
+		return nil, fmt.Errorf("proposer must be an active member of raft %s", raftID)
+	}
+	if !targetExists {
+		return nil, fmt.Errorf("%s is not a member of raft %s", memberID, raftID)
+	}
+	if target.State == StateRevoked || target.State == StateLeft {
+		return nil, fmt.Errorf("member %s is already %s", memberID, target.State)
+	}
+
+	g.proposals.mu.Lock()
+	defer g.proposals.mu.Unlock()
+
+	for _, open := range g.proposals.proposals {
+		if open.Status == ProposalOpen && open.Kind == ProposalKindEviction &&
+			open.RaftID == raftID && open.TargetMemberID == memberID {
+			return nil, fmt.Errorf("eviction of %s is already open as proposal %s", memberID, open.ProposalID)
+		}
+	}
+
+	now := time.Now()
+	proposal := &Proposal{
+		ProposalID:     generateID(fmt.Sprintf("evict|%s|%s|%s|%d", raftID, memberID, proposedBy, now.UnixNano())),
+		RaftID:         raftID,
+		Kind:           ProposalKindEviction,
+		TargetMemberID: memberID,
+		Reason:         reason,
+		ProposedBy:     proposedBy,
+		ProposedAt:     now,
+		Deadline:       now.Add(votingPeriod),
+		Votes:          make(map[string]VoteType),
+		Ballots:        make(map[string]*SignedVote),
+		Status:         ProposalOpen,
+		Result:         ResultPending,
+		EligibleVoters: snapshotVoters(raft, memberID),
+	}
+	g.proposals.proposals[proposal.ProposalID] = proposal
+
+	g.publish(events.ProposalCreated, proposalEvent(proposal))
+	g.recordAudit(AuditProposalCreated, raftID, proposal.ProposalID, proposedBy, proposalEvent(proposal))
+	g.announceProposal(proposal)
+
+	return proposal, nil
+}
+
+// checkEvictionOutcome decides an eviction proposal. The caller must hold
+// the proposal registry lock.
+func (g *Governance) checkEvictionOutcome(proposal *Proposal) {
+	eligible := g.eligibleVoters(proposal)
+	if len(eligible) == 0 {
+		return
+	}
+
+	count := tally{eligible: len(eligible), proposerVote: proposal.Votes[proposal.ProposedBy]}
+	for _, voterID := range eligible {
+		vote, ok := proposal.Votes[voterID]
+		if !ok {
+			continue
+		}
+		count.cast++
+		switch vote {
+		case VoteYes:
+			count.yes++
+		case VoteNo:
+			count.no++
+		}
+	}
+
+	// Revoking a member takes the raft's super-majority
+	quorumMet, adopted, closed := g.proposalPolicy(proposal).decide(count, true)
+	proposal.QuorumMet = quorumMet
+	if !closed {
+		return
+	}
+
+	now := time.Now()
+	proposal.Status = ProposalClosed
+	proposal.ClosedAt = &now
+	proposal.Result = ResultRejected
+
+	if adopted {
+		proposal.Result = ResultAdopted
+		if target := g.revokeMember(proposal.RaftID, proposal.TargetMemberID, proposal.ProposalID); target != nil {
+			revocation := MemberRevocation{
+				RaftID:     proposal.RaftID,
+				MemberID:   proposal.TargetMemberID,
+				ProposalID: proposal.ProposalID,
+				Reason:     proposal.Reason,
+				RevokedAt:  now,
+			}
+			for _, ballot := range proposal.Ballots {
+				revocation.Ballots = append(revocation.Ballots, *ballot)
+			}
+			go g.broadcast(context.Background(), proposal.RaftID, MessageMemberRevoked, revocation, target)
+		}
+	}
+
+	g.publish(events.ProposalClosed, proposalEvent(proposal))
+	g.recordAudit(AuditProposalClosed, proposal.RaftID, proposal.ProposalID, "", proposalEvent(proposal))
+	g.announceOutcome(proposal)
+
+	if adopted {
+		g.dropBallots(proposal.RaftID, proposal.TargetMemberID)
+	}
+}
+
+// revokeMember moves a member to StateRevoked and returns a snapshot of it,
+// or nil if it was unknown or already revoked
+func (g *Governance) revokeMember(raftID, memberID, proposalID string) *Member {
+	g.rafts.mu.RLock()
+	raft, exists := g.rafts.rafts[raftID]
+	g.rafts.mu.RUnlock()
+	if !exists {
+		return nil
+	}
+
+	raft.mu.Lock()
+	member, ok := raft.Members[memberID]
+	if !ok || member.State == StateRevoked {
+		raft.mu.Unlock()
+		return nil
+	}
+	member.State = StateRevoked
+	snapshot := *member
+	raft.mu.Unlock()
+
+	g.log().Info("revoked member", "member_id", memberID, "raft_id", raftID, "proposal_id", proposalID)
+
+	g.publish(events.MemberRevoked, MemberEvent{
+		RaftID:     raftID,
+		MemberID:   memberID,
+		State:      StateRevoked,
+		ProposalID: proposalID,
+	})
+	g.recordAudit(AuditMemberStateChanged, raftID, memberID, proposalID,
+		MemberAudit{MemberID: memberID, State: StateRevoked})
+
+	if err := g.saveRaft(context.Background(), raft); err != nil {
+		g.log().Warn("failed to persist revocation", "member_id", memberID, "raft_id", raftID, "error", err)
+	}
+	return &snapshot
+}
+
+// dropBallots discards a revoked member's votes on the raft's open
+// proposals and re-evaluates them against the smaller membership. The
+// caller must hold the proposal registry lock.
+func (g *Governance) dropBallots(raftID, memberID string) {
+	for _, proposal := range g.proposals.proposals {
+		if proposal.RaftID != raftID || proposal.Status != ProposalOpen {
+			continue
+		}
+		delete(proposal.Votes, memberID)
+		delete(proposal.Ballots, memberID)
+		g.checkProposalOutcome(proposal)
+	}
+}
+
+// applyRemoteRevocation applies an eviction adopted by a peer. The sender
+// must hold the eviction proposal this otter mirrors, and the ballots it
+// sends must adopt it: they are verified against the voters' signing keys
+// and must reach the raft's super-majority of the proposal's eligible
+// voters. The envelope itself has already been checked against the
+// sender's signing key.
+func (g *Governance) applyRemoteRevocation(ctx context.Context, env *Envelope, revocation *MemberRevocation) error {
+	if revocation.RaftID != env.RaftID {
+		return fmt.Errorf("revocation for raft %s sent in an envelope for raft %s", revocation.RaftID, env.RaftID)
+	}
+	if revocation.MemberID == "" || revocation.MemberID == env.SenderID {
+		return fmt.Errorf("invalid revocation target %q", revocation.MemberID)
+	}
+
+	g.proposals.mu.RLock()
+	proposal, exists := g.proposals.proposals[revocation.ProposalID]
+	var eligible []string
+	var probe Proposal
+	if exists {
+		exists = proposal.Kind == ProposalKindEviction && proposal.RaftID == revocation.RaftID &&
+			proposal.TargetMemberID == revocation.MemberID && proposal.Origin == env.SenderID
+		for _, voterID := range proposal.EligibleVoters {
+			if voterID != revocation.MemberID {
+				eligible = append(eligible, voterID)
+			}
+		}
+		probe = Proposal{ProposalID: proposal.ProposalID, RaftID: proposal.RaftID, ProposedBy: proposal.ProposedBy}
+	}
+	g.proposals.mu.RUnlock()
+	if !exists {
+		return fmt.Errorf("%w: %s holds no eviction proposal %s for %s", ErrUnknownSender, env.SenderID, revocation.ProposalID, revocation.MemberID)
+	}
+
+	keys := g.memberSigningKeys(revocation.RaftID)
+	for i := range revocation.Ballots {
+		ballot := &revocation.Ballots[i]
+		if ballot.ProposalID != revocation.ProposalID {
+			return fmt.Errorf("ballot from %s is for proposal %s", ballot.VoterID, ballot.ProposalID)
+		}
+		if key := keys[ballot.VoterID]; len(key) > 0 {
+			if err := verifyVote(ballot, key); err != nil {
+				return fmt.Errorf("rejecting revocation of %s: %w", revocation.MemberID, err)
+			}
+		}
+	}
+	count := ballotTally(probe.ProposalID, probe.ProposedBy, revocation.Ballots, eligible, keys)
+	if _, adopted, _ := g.proposalPolicy(&probe).decide(count, true); !adopted {
+		return fmt.Errorf("revocation of %s carries %d verified YES ballots of %d eligible", revocation.MemberID, count.yes, count.eligible)
+	}
+
+	if g.revokeMember(revocation.RaftID, revocation.MemberID, revocation.ProposalID) != nil {
+		g.log().InfoContext(ctx, "member revoked by peer", "member_id", revocation.MemberID, "raft_id", revocation.RaftID, "sender_id", env.SenderID)
+		g.proposals.mu.Lock()
+		g.dropBallots(revocation.RaftID, revocation.MemberID)
+		g.proposals.mu.Unlock()
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package governance
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingTransport captures envelopes instead of sending them
+type recordingTransport struct {
+	mu   sync.Mutex
+	sent map[string][]*Envelope // endpoint -> envelopes
+	done chan struct{}
+}
+
+func newRecordingTransport() *recordingTransport {
+	return &recordingTransport{sent: make(map[string][]*Envelope), done: make(chan struct{}, 16)}
+}
+
+func (r *recordingTransport) Send(_ context.Context, endpoint string, env *Envelope) error {
+	r.mu.Lock()
+	r.sent[endpoint] = append(r.sent[endpoint], env)
+	r.mu.Unlock()
+	r.done <- struct{}{}
+	return nil
+}
+
+// waitFor waits until an envelope of msgType has been sent to endpoint
+func (r *recordingTransport) waitFor(t *testing.T, endpoint, msgType string) *Envelope {
+	t.Helper()
+	deadline := time.After(2 * time.Second)
+	for {
+		r.mu.Lock()
+		for _, env := range r.sent[endpoint] {
+			if env.Type == msgType {
+				r.mu.Unlock()
+				return env
+			}
+		}
+		r.mu.Unlock()
+		select {
+		case <-r.done:
+		case <-deadline:
+			t.Fatalf("no %s envelope was sent to %s", msgType, endpoint)
+		}
+	}
+}
+
+// addPeers adds otters with their own keys as active members of g's raft
+func addPeers(g *Governance, ids ...string) map[string]*Governance {
+	peers := make(map[string]*Governance, len(ids))
+	raft := g.rafts.rafts[g.config.ID]
+	for _, id := range ids {
+		peer := newTestGovernance(id)
+		peers[id] = peer
+		raft.Members[id] = &Member{
+			ID:         id,
+			State:      StateActive,
+			JoinedAt:   time.Now(),
+			SigningKey: peer.crypto.GetSigningPublicKey(),
+			Endpoint:   "http://" + id,
+		}
+	}
+	return peers
+}
+
+func peerVote(t *testing.T, g, peer *Governance, proposalID string, vote VoteType) error {
+	t.Helper()
+	ballot, err := peer.SignVote(proposalID, vote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g.Vote(context.Background(), ballot)
+}
+
+func TestProposeEviction_Validation(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	addPeers(g, "otter-2")
+	ctx := context.Background()
+
+	if _, err := g.ProposeEviction(ctx, "otter-1", "otter-1", "otter-1", "", 0); err == nil {
+		t.Error("expected error proposing own eviction")
+	}
+	if _, err := g.ProposeEviction(ctx, "otter-1", "otter-9", "otter-1", "", 0); err == nil {
+		t.Error("expected error for unknown member")
+	}
+	if _, err := g.ProposeEviction(ctx, "otter-1", "otter-2", "otter-1", "", time.Second); err == nil {
+		t.Error("expected error for too short voting period")
+	}
+
+	proposal, err := g.ProposeEviction(ctx, "otter-1", "otter-2", "otter-1", "spamming proposals", 0)
+	if err != nil {
+		t.Fatalf("ProposeEviction: %v", err)
+	}
+	if proposal.Kind != ProposalKindEviction || proposal.TargetMemberID != "otter-2" || proposal.Rule != nil {
+		t.Errorf("proposal = %+v", proposal)
+	}
+	if _, err := g.ProposeEviction(ctx, "otter-1", "otter-2", "otter-1", "", 0); err == nil {
+		t.Error("expected error for a second open eviction of the same member")
+	}
+}
+
+func TestEviction_TargetCannotVote(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	peers := addPeers(g, "otter-2", "otter-3")
+
+	proposal, err := g.ProposeEviction(context.Background(), "otter-1", "otter-3", "otter-1", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := peerVote(t, g, peers["otter-3"], proposal.ProposalID, VoteNo); err == nil {
+		t.Error("expected the target's vote to be refused")
+	}
+}
+
+func TestEviction_AdoptedBySuperMajority(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	peers := addPeers(g, "otter-2", "otter-3", "otter-4")
+	transport := newRecordingTransport()
+	g.SetTransport(transport)
+	ctx := context.Background()
+
+	// otter-4 has voted on an unrelated rule proposal
+	ruleProposal, err := g.ProposeRule(ctx, "otter-1", &Rule{Scope: "safety", Body: "be kind", ProposedBy: "otter-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := peerVote(t, g, peers["otter-4"], ruleProposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+
+	proposal, err := g.ProposeEviction(ctx, "otter-1", "otter-4", "otter-1", "misbehaving", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CastVote(ctx, proposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	if err := peerVote(t, g, peers["otter-2"], proposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	// 3 eligible voters, super-majority = ceil(3*75/100) = 3
+	if proposal.Status != ProposalOpen {
+		t.Fatalf("two of three votes should not evict, status = %s", proposal.Status)
+	}
+	if err := peerVote(t, g, peers["otter-3"], proposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	if proposal.Result != ResultAdopted {
+		t.Fatalf("result = %s, want adopted", proposal.Result)
+	}
+
+	if state := g.rafts.rafts["otter-1"].Members["otter-4"].State; state != StateRevoked {
+		t.Errorf("otter-4 state = %s, want revoked", state)
+	}
+	if _, ok := ruleProposal.Votes["otter-4"]; ok {
+		t.Error("revoked member's vote should be dropped from open proposals")
+	}
+	if err := peerVote(t, g, peers["otter-4"], ruleProposal.ProposalID, VoteYes); err == nil {
+		t.Error("revoked member should not be able to vote")
+	}
+
+	// The revocation goes to the remaining peers and to the evicted member
+	for i := 0; i < 3; i++ {
+		select {
+		case <-transport.done:
+		case <-time.After(2 * time.Second):
+			t.Fatal("revocation was not broadcast")
+		}
+	}
+	for _, endpoint := range []string{"http://otter-2", "http://otter-3", "http://otter-4"} {
+		sent := transport.sent[endpoint]
+		if len(sent) != 1 || sent[0].Type != MessageMemberRevoked {
+			t.Errorf("%s received %+v", endpoint, sent)
+		}
+	}
+}
+
+func TestEviction_RejectedWhenEveryoneVoted(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	peers := addPeers(g, "otter-2", "otter-3")
+
+	proposal, _ := g.ProposeEviction(context.Background(), "otter-1", "otter-3", "otter-1", "", 0)
+	g.CastVote(context.Background(), proposal.ProposalID, VoteYes)
+	peerVote(t, g, peers["otter-2"], proposal.ProposalID, VoteNo)
+
+	if proposal.Result != ResultRejected {
+		t.Errorf("result = %s, want rejected", proposal.Result)
+	}
+	if state := g.rafts.rafts["otter-1"].Members["otter-3"].State; state != StateActive {
+		t.Errorf("otter-3 state = %s, want active", state)
+	}
+}
@@ -0,0 +1,114 @@
+package governance
+
+import (
+	"fmt"
+	"time"
+
+	"otter-ai/internal/errs"
+	"otter-ai/internal/events"
+)
+
+// Proposal voting deadlines
+const (
+	DefaultVotingPeriod   = 7 * 24 * time.Hour
+	MinVotingPeriod       = 1 * time.Minute
+	MaxVotingPeriod       = 90 * 24 * time.Hour
+	ProposalSweepInterval = 1 * time.Minute
+)
+
+// ErrProposalExpired is returned when voting on a proposal past its deadline
+var ErrProposalExpired = errs.New(errs.ErrQuorum, "proposal voting deadline has passed")
+
+// ValidateVotingPeriod checks a requested voting period. Zero selects the
+// configured default.
+func ValidateVotingPeriod(period time.Duration) error {
+	if period == 0 {
+		return nil
+	}
+	if period < MinVotingPeriod || period > MaxVotingPeriod {
+		return fmt.Errorf("voting period must be between %s and %s", MinVotingPeriod, MaxVotingPeriod)
+	}
+	return nil
+}
+
+// VotingPeriod returns the default time proposals stay open
+func (g *Governance) VotingPeriod() time.Duration {
+	if g.config.VotingPeriod > 0 {
+		return g.config.VotingPeriod
+	}
+	return DefaultVotingPeriod
+}
+
+// proposalSweeper periodically closes proposals whose deadline has passed
+func (g *Governance) proposalSweeper() {
+	ticker := time.NewTicker(ProposalSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if closed := g.closeExpiredProposals(time.Now()); closed > 0 {
+				g.log().Info("closed expired proposals", "proposals", closed)
+			}
+		case <-g.shutdownCh:
+			return
+		}
+	}
+}
+
+// closeExpiredProposals rejects every open proposal whose deadline is
+// before now and returns how many were closed
+func (g *Governance) closeExpiredProposals(now time.Time) int {
+	g.proposals.mu.Lock()
+	defer g.proposals.mu.Unlock()
+
+	closed := 0
+	for _, proposal := range g.proposals.proposals {
+		if proposal.Status == ProposalOpen && proposal.pastDeadline(now) {
+			g.expireProposal(proposal, now)
+			closed++
+		}
+	}
+	return closed
+}
+
+// expireProposal closes a proposal as rejected because voting ran out of
+// time. The caller must hold the proposal registry lock.
+func (g *Governance) expireProposal(proposal *Proposal, now time.Time) {
+	proposal.Status = ProposalClosed
+	proposal.Result = ResultRejected
+	proposal.Expired = true
+	proposal.ClosedAt = &now
+
+	switch proposal.Kind {
+	case ProposalKindAdmission:
+		g.rejectAdmission(proposal)
+	case ProposalKindReinstatement:
+		// Mirrors wait for the origin's outcome
+		if g.tallies(proposal) {
+			g.resolveSuspension(proposal)
+		}
+	}
+
+	g.publish(events.ProposalClosed, proposalEvent(proposal))
+	g.recordAudit(AuditProposalClosed, proposal.RaftID, proposal.ProposalID, g.config.ID, proposalEvent(proposal))
+	g.announceOutcome(proposal)
+}
+
+// withdrawProposal closes an open proposal undecided. The caller must hold
+// the proposal registry lock.
+func (g *Governance) withdrawProposal(proposal *Proposal, by string, now time.Time) {
+	proposal.Status = ProposalClosed
+	proposal.Result = ResultWithdrawn
+	proposal.ClosedAt = &now
+
+	g.publish(events.ProposalClosed, proposalEvent(proposal))
+	g.recordAudit(AuditProposalClosed, proposal.RaftID, proposal.ProposalID, by, proposalEvent(proposal))
+	g.announceOutcome(proposal)
+}
+
+// pastDeadline reports whether voting on the proposal has ended. Proposals
+// without a deadline never expire.
+func (p *Proposal) pastDeadline(now time.Time) bool {
+	return !p.Deadline.IsZero() && now.After(p.Deadline)
+}
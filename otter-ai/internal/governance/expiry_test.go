@@ -0,0 +1,98 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"otter-ai/internal/events"
+)
+
+func proposeForExpiry(t *testing.T, g *Governance, period time.Duration) *Proposal {
+	t.Helper()
+	proposal, err := g.ProposeRuleWithVotingPeriod(context.Background(), g.config.ID,
+		&Rule{Scope: "safety", Body: "be kind", ProposedBy: g.config.ID}, period)
+	if err != nil {
+		t.Fatalf("ProposeRuleWithVotingPeriod: %v", err)
+	}
+	return proposal
+}
+
+func TestProposeRule_DefaultDeadline(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	proposal := proposeForExpiry(t, g, 0)
+
+	want := proposal.ProposedAt.Add(DefaultVotingPeriod)
+	if !proposal.Deadline.Equal(want) {
+		t.Errorf("Deadline = %v, want %v", proposal.Deadline, want)
+	}
+
+	g.config.VotingPeriod = time.Hour
+	proposal = proposeForExpiry(t, g, 0)
+	if got := proposal.Deadline.Sub(proposal.ProposedAt); got != time.Hour {
+		t.Errorf("configured voting period = %v, want 1h", got)
+	}
+}
+
+func TestProposeRule_InvalidVotingPeriod(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	for _, period := range []time.Duration{time.Second, MaxVotingPeriod + time.Hour, -time.Hour} {
+		_, err := g.ProposeRuleWithVotingPeriod(context.Background(), "otter-1",
+			&Rule{Scope: "safety", Body: "be kind", ProposedBy: "otter-1"}, period)
+		if err == nil {
+			t.Errorf("voting period %v should be rejected", period)
+		}
+	}
+}
+
+func TestCloseExpiredProposals(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	bus := events.NewBus()
+	g.SetEventBus(bus)
+	sub := bus.Subscribe(4, events.ProposalClosed)
+	defer sub.Close()
+
+	short := proposeForExpiry(t, g, time.Hour)
+	long := proposeForExpiry(t, g, 48*time.Hour)
+
+	if closed := g.closeExpiredProposals(time.Now()); closed != 0 {
+		t.Fatalf("closed %d proposals before any deadline", closed)
+	}
+	if closed := g.closeExpiredProposals(time.Now().Add(2 * time.Hour)); closed != 1 {
+		t.Fatalf("closed %d proposals, want 1", closed)
+	}
+
+	if short.Status != ProposalClosed || short.Result != ResultRejected || !short.Expired || short.ClosedAt == nil {
+		t.Errorf("expired proposal = %+v", short)
+	}
+	if long.Status != ProposalOpen {
+		t.Error("proposal before its deadline should stay open")
+	}
+
+	select {
+	case event := <-sub.C:
+		if data := event.Data.(ProposalEvent); !data.Expired || data.Result != ResultRejected {
+			t.Errorf("event = %+v", data)
+		}
+	case <-time.After(time.Second):
+		t.Error("expected a proposal.closed event")
+	}
+}
+
+func TestVote_PastDeadline(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	proposal := proposeForExpiry(t, g, time.Hour)
+	proposal.Deadline = time.Now().Add(-time.Minute)
+
+	err := g.CastVote(context.Background(), proposal.ProposalID, VoteYes)
+	if !errors.Is(err, ErrProposalExpired) {
+		t.Fatalf("err = %v, want ErrProposalExpired", err)
+	}
+	if proposal.Status != ProposalClosed || !proposal.Expired {
+		t.Error("voting past the deadline should close the proposal")
+	}
+	if len(proposal.Votes) != 0 {
+		t.Error("late vote must not be recorded")
+	}
+}
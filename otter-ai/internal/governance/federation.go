@@ -0,0 +1,356 @@
+package governance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"otter-ai/internal/errs"
+	"otter-ai/internal/tracing"
+)
+
+// Federation transport configuration
+const (
+	FederationPath      = "/api/v1/governance/federation"
+	EnvelopeMaxAge      = 10 * time.Minute // Older envelopes are treated as replays
+	FederationSendRetry = 2                // Extra attempts per peer after a failed send
+)
+
+// Message types carried in federation envelopes
+const (
+	MessageMemberRevoked  = "member.revoked"
+	MessageMemberAdmitted = "member.admitted"
+)
+
+// supportedMessageTypes lists the message types HandleEnvelope accepts. It
+// is advertised to peers in the capability handshake, so keep it in sync.
+func supportedMessageTypes() []string {
+	return []string{
+		MessageMemberRevoked, MessageMemberAdmitted, MessageHeartbeat, MessageRuleAdopted,
+		MessageProposalOpened, MessageVoteCast, MessageProposalClosed, MessageMemberLeft,
+		MessageKeyRollover,
+	}
+}
+
+// ErrUnknownSender is returned for envelopes whose sender is not an active
+// member of the raft they address
+var ErrUnknownSender = errs.New(errs.ErrUnauthorized, "sender is not an active member of the raft")
+
+// Envelope is a signed message between members of a raft. The signature
+// covers every field, so a relaying peer cannot alter the payload or replay
+// it into another raft.
+type Envelope struct {
+	Type      string          `json:"type"`
+	RaftID    string          `json:"raft_id"`
+	SenderID  string          `json:"sender_id"`
+	Timestamp time.Time       `json:"timestamp"`
+	Payload   json.RawMessage `json:"payload"`
+	Signature []byte          `json:"signature"`
+}
+
+// Transport delivers envelopes to a peer's API endpoint
+type Transport interface {
+	Send(ctx context.Context, endpoint string, env *Envelope) error
+}
+
+// httpTransport posts envelopes as JSON to the peer's federation endpoint
+type httpTransport struct {
+	client *http.Client
+}
+
+func (t *httpTransport) Send(ctx context.Context, endpoint string, env *Envelope) (err error) {
+	ctx, span := tracer.Start(ctx, "governance.Send", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("governance.message_type", env.Type),
+		attribute.String("server.address", endpoint),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	endpoint = strings.TrimSpace(endpoint)
+	if endpoint == "" {
+		return errNoPeerEndpoint
+	}
+	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+		endpoint = "http://" + endpoint
+	}
+
+	body, err := json.Marshal(env)
+	if err != nil {
+		return fmt.Errorf("failed to marshal envelope: %w", err)
+	}
+
+	target := strings.TrimRight(endpoint, "/") + FederationPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	tracing.Inject(ctx, req.Header)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed sending %s to %s: %w", env.Type, target, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s rejected %s (%d): %s", target, env.Type, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
+
+// SetTransport replaces the transport used to reach peers
+func (g *Governance) SetTransport(t Transport) {
+	g.transport.Store(&t)
+}
+
+// federation returns the transport envelopes are sent with, through chaos
+// mode's faults when it is on
+func (g *Governance) federation() Transport {
+	var transport Transport = &httpTransport{client: &http.Client{Timeout: GovernanceHTTPTimeout}}
+	if t := g.transport.Load(); t != nil {
+		transport = *t
+	}
+	if chaos := g.chaos.Load(); chaos != nil {
+		return &chaosTransport{chaos: chaos, next: transport, g: g}
+	}
+	return transport
+}
+
+// envelopeSigningPayload returns the bytes an envelope signature covers
+func envelopeSigningPayload(env *Envelope) []byte {
+	return canonicalPayload(
+		"envelope",
+		env.Type,
+		env.RaftID,
+		env.SenderID,
+		strconv.FormatInt(env.Timestamp.Unix(), 10),
+		string(env.Payload),
+	)
+}
+
+// sealEnvelope builds and signs an envelope from this otter
+func (g *Governance) sealEnvelope(msgType, raftID string, payload interface{}) (*Envelope, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s payload: %w", msgType, err)
+	}
+
+	env := &Envelope{
+		Type:      msgType,
+		RaftID:    raftID,
+		SenderID:  g.config.ID,
+		Timestamp: time.Now(),
+		Payload:   data,
+	}
+	sig, err := g.crypto.Sign(envelopeSigningPayload(env))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign envelope: %w", err)
+	}
+	env.Signature = sig
+	return env, nil
+}
+
+// openEnvelope checks that an envelope is recent and signed by an active
+// member of its raft, using the signing key recorded for that member, or
+// for a key rollover the key it leads to. Heartbeats are also accepted from
+// members marked inactive for going quiet, so that they can come back.
+func (g *Governance) openEnvelope(env *Envelope) error {
+	if env.Type == "" || env.RaftID == "" || env.SenderID == "" {
+		return fmt.Errorf("envelope is missing type, raft or sender")
+	}
+	if len(env.Signature) == 0 {
+		return fmt.Errorf("%w: envelope from %s", ErrUnsigned, env.SenderID)
+	}
+
+	now := time.Now()
+	if env.Timestamp.After(now.Add(VoteClockSkew)) || env.Timestamp.Before(now.Add(-EnvelopeMaxAge)) {
+		return fmt.Errorf("envelope from %s is outside the accepted time window", env.SenderID)
+	}
+
+	g.rafts.mu.RLock()
+	raft, exists := g.rafts.rafts[env.RaftID]
+	g.rafts.mu.RUnlock()
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrRaftNotFound, env.RaftID)
+	}
+
+	raft.mu.RLock()
+	sender, ok := raft.Members[env.SenderID]
+	ok = ok && (sender.State == StateActive || sender.State == StateInactive && env.Type == MessageHeartbeat)
+	var signingKey []byte
+	if ok {
+		signingKey = sender.SigningKey
+	}
+	raft.mu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("%w: %s in raft %s", ErrUnknownSender, env.SenderID, env.RaftID)
+	}
+	if env.Type == MessageKeyRollover {
+		// Signed with the key the rollovers lead to, not the recorded one
+		var announcement KeyRolloverAnnouncement
+		if err := json.Unmarshal(env.Payload, &announcement); err != nil {
+			return fmt.Errorf("invalid %s payload: %w", env.Type, err)
+		}
+		var err error
+		if signingKey, err = followRollovers(signingKey, env.SenderID, announcement.Rollovers); err != nil {
+			return err
+		}
+	}
+	if len(signingKey) == 0 || !VerifySignature(envelopeSigningPayload(env), env.Signature, signingKey) {
+		return fmt.Errorf("%w: envelope from %s", ErrInvalidSignature, env.SenderID)
+	}
+	return nil
+}
+
+// HandleEnvelope verifies an envelope received from a peer and applies it
+func (g *Governance) HandleEnvelope(ctx context.Context, env *Envelope) (err error) {
+	ctx, span := tracer.Start(ctx, "governance.HandleEnvelope", trace.WithAttributes(
+		attribute.String("governance.message_type", env.Type),
+		attribute.String("governance.raft_id", env.RaftID),
+		attribute.String("governance.sender_id", env.SenderID),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	if g.chaosPartitioned(env.SenderID) {
+		return fmt.Errorf("%w: %s", ErrChaosPartitioned, env.SenderID)
+	}
+	if err := g.openEnvelope(env); err != nil {
+		return err
+	}
+	if _, err := g.liveRaft(env.RaftID); err != nil {
+		return err
+	}
+	g.memberSeen(ctx, env.RaftID, env.SenderID)
+
+	switch env.Type {
+	case MessageMemberRevoked:
+		var revocation MemberRevocation
+		if err := json.Unmarshal(env.Payload, &revocation); err != nil {
+			return fmt.Errorf("invalid %s payload: %w", env.Type, err)
+		}
+		return g.applyRemoteRevocation(ctx, env, &revocation)
+	case MessageMemberAdmitted:
+		var admission MemberAdmission
+		if err := json.Unmarshal(env.Payload, &admission); err != nil {
+			return fmt.Errorf("invalid %s payload: %w", env.Type, err)
+		}
+		return g.applyRemoteAdmission(ctx, env, &admission)
+	case MessageHeartbeat:
+		return nil // Seen above
+	case MessageRuleAdopted:
+		var adoption RuleAdoption
+		if err := json.Unmarshal(env.Payload, &adoption); err != nil {
+			return fmt.Errorf("invalid %s payload: %w", env.Type, err)
+		}
+		return g.applyRemoteRule(ctx, env, &adoption)
+	case MessageProposalOpened:
+		var announcement ProposalAnnouncement
+		if err := json.Unmarshal(env.Payload, &announcement); err != nil {
+			return fmt.Errorf("invalid %s payload: %w", env.Type, err)
+		}
+		return g.applyRemoteProposal(ctx, env, &announcement)
+	case MessageVoteCast:
+		var ballot SignedVote
+		if err := json.Unmarshal(env.Payload, &ballot); err != nil {
+			return fmt.Errorf("invalid %s payload: %w", env.Type, err)
+		}
+		return g.applyRemoteVote(ctx, env, &ballot)
+	case MessageProposalClosed:
+		var outcome ProposalOutcome
+		if err := json.Unmarshal(env.Payload, &outcome); err != nil {
+			return fmt.Errorf("invalid %s payload: %w", env.Type, err)
+		}
+		return g.applyRemoteOutcome(ctx, env, &outcome)
+	case MessageMemberLeft:
+		var departure MemberDeparture
+		if err := json.Unmarshal(env.Payload, &departure); err != nil {
+			return fmt.Errorf("invalid %s payload: %w", env.Type, err)
+		}
+		return g.applyRemoteDeparture(ctx, env, &departure)
+	case MessageKeyRollover:
+		var announcement KeyRolloverAnnouncement
+		if err := json.Unmarshal(env.Payload, &announcement); err != nil {
+			return fmt.Errorf("invalid %s payload: %w", env.Type, err)
+		}
+		return g.applyRemoteRollover(ctx, env, &announcement)
+	default:
+		return fmt.Errorf("unsupported message type: %s", env.Type)
+	}
+}
+
+// broadcast signs a message and sends it to the raft's reachable peers plus
+// any extra members named (who may no longer be active), skipping peers
+// whose negotiated capabilities lack the message type. Delivery failures
+// are retried a few times and then logged; peers that miss a message catch
+// up through drift reconciliation.
+func (g *Governance) broadcast(ctx context.Context, raftID, msgType string, payload interface{}, extra ...*Member) {
+	ctx, span := tracer.Start(ctx, "governance.broadcast", trace.WithAttributes(
+		attribute.String("governance.message_type", msgType),
+		attribute.String("governance.raft_id", raftID),
+	))
+	defer span.End()
+
+	env, err := g.sealEnvelope(msgType, raftID, payload)
+	if err != nil {
+		g.log().WarnContext(ctx, "failed to seal envelope", "message_type", msgType, "raft_id", raftID, "error", err)
+		return
+	}
+
+	targets := make(map[string]string)
+	for _, member := range append(g.raftPeers(raftID), extra...) {
+		if member == nil || member.ID == g.config.ID || member.Endpoint == "" {
+			continue
+		}
+		// Peers that did not agree to this message type would misread or reject it
+		if !member.Supports(msgType) {
+			g.log().WarnContext(ctx, "not sending message to a member that does not support it", "message_type", msgType, "member_id", member.ID)
+			continue
+		}
+		targets[member.ID] = member.Endpoint
+	}
+
+	ids := make([]string, 0, len(targets))
+	for id := range targets {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	transport := g.federation()
+	for _, id := range ids {
+		var err error
+		for attempt := 0; attempt <= FederationSendRetry; attempt++ {
+			if err = transport.Send(ctx, targets[id], env); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			g.log().WarnContext(ctx, "failed to deliver envelope", "message_type", msgType, "member_id", id, "error", err)
+		}
+	}
+}
+
+// raftPeers returns active members of a raft, other than this otter, that have an endpoint
+func (g *Governance) raftPeers(raftID string) []*Member {
+	var peers []*Member
+	for _, member := range g.getActiveMembers(raftID) {
+		if member.ID != g.config.ID && member.Endpoint != "" {
+			peers = append(peers, member)
+		}
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].ID < peers[j].ID
+	})
+	return peers
+}
@@ -0,0 +1,214 @@
+package governance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// federatedPair returns a sender and a receiver that both know the sender
+// as an active member of raft "otter-1", plus a third member to evict
+func federatedPair(t *testing.T) (sender, receiver *Governance) {
+	t.Helper()
+	sender = newTestGovernance("otter-1")
+	receiver = newTestGovernance("otter-2")
+
+	now := time.Now()
+	receiver.rafts.rafts["otter-1"] = &RaftInfo{
+		RaftID: "otter-1",
+		Members: map[string]*Member{
+			"otter-1": {ID: "otter-1", State: StateActive, JoinedAt: now, SigningKey: sender.crypto.GetSigningPublicKey()},
+			"otter-2": {ID: "otter-2", State: StateActive, JoinedAt: now, SigningKey: receiver.crypto.GetSigningPublicKey()},
+			"otter-3": {ID: "otter-3", State: StateActive, JoinedAt: now},
+		},
+		Rules: make(map[string]*Rule),
+	}
+	return sender, receiver
+}
+
+// mirrorEviction registers on receiver the eviction proposal p1 for target,
+// as mirrored from the sender's otter
+func mirrorEviction(receiver *Governance, target string) {
+	raft := receiver.rafts.rafts["otter-1"]
+	receiver.proposals.proposals["p1"] = &Proposal{
+		ProposalID: "p1", RaftID: "otter-1", Kind: ProposalKindEviction, TargetMemberID: target,
+		ProposedBy: "otter-1", Origin: "otter-1", Status: ProposalOpen, Result: ResultPending,
+		Votes: make(map[string]VoteType), Ballots: make(map[string]*SignedVote),
+		EligibleVoters: snapshotVoters(raft, target),
+	}
+}
+
+// revocationEnvelope seals the revocation of target adopted by p1, carrying
+// the voters' YES ballots
+func revocationEnvelope(t *testing.T, sender *Governance, target string, voters ...*Governance) *Envelope {
+	t.Helper()
+	revocation := MemberRevocation{
+		RaftID:     "otter-1",
+		MemberID:   target,
+		ProposalID: "p1",
+		RevokedAt:  time.Now(),
+	}
+	for _, voter := range voters {
+		ballot, err := voter.SignVote("p1", VoteYes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		revocation.Ballots = append(revocation.Ballots, ballot)
+	}
+	env, err := sender.sealEnvelope(MessageMemberRevoked, "otter-1", revocation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return env
+}
+
+func TestHandleEnvelope_AppliesRevocation(t *testing.T) {
+	sender, receiver := federatedPair(t)
+	ctx := context.Background()
+
+	// Without a mirrored eviction proposal the sender's word counts for nothing
+	if err := receiver.HandleEnvelope(ctx, revocationEnvelope(t, sender, "otter-3", sender, receiver)); !errors.Is(err, ErrUnknownSender) {
+		t.Fatalf("no proposal: err = %v, want ErrUnknownSender", err)
+	}
+	// Nor does a proposal to evict someone else
+	mirrorEviction(receiver, "otter-2")
+	if err := receiver.HandleEnvelope(ctx, revocationEnvelope(t, sender, "otter-3", sender, receiver)); !errors.Is(err, ErrUnknownSender) {
+		t.Fatalf("proposal for another member: err = %v, want ErrUnknownSender", err)
+	}
+
+	// A lone ballot is short of the super-majority
+	mirrorEviction(receiver, "otter-3")
+	if err := receiver.HandleEnvelope(ctx, revocationEnvelope(t, sender, "otter-3", sender)); err == nil {
+		t.Fatal("a lone ballot should not revoke a member")
+	}
+	if state := receiver.rafts.rafts["otter-1"].Members["otter-3"].State; state != StateActive {
+		t.Fatalf("otter-3 state = %s after a lone ballot, want active", state)
+	}
+
+	env := revocationEnvelope(t, sender, "otter-3", sender, receiver)
+	if err := receiver.HandleEnvelope(ctx, env); err != nil {
+		t.Fatalf("HandleEnvelope: %v", err)
+	}
+	if state := receiver.rafts.rafts["otter-1"].Members["otter-3"].State; state != StateRevoked {
+		t.Errorf("otter-3 state = %s, want revoked", state)
+	}
+
+	// Redelivery is harmless
+	if err := receiver.HandleEnvelope(ctx, env); err != nil {
+		t.Errorf("redelivery: %v", err)
+	}
+}
+
+func TestHandleEnvelope_RefusesRevocationFromOtherOrigin(t *testing.T) {
+	sender, receiver := federatedPair(t)
+	mirrorEviction(receiver, "otter-3")
+	receiver.proposals.proposals["p1"].Origin = "otter-2"
+
+	err := receiver.HandleEnvelope(context.Background(), revocationEnvelope(t, sender, "otter-3", sender, receiver))
+	if !errors.Is(err, ErrUnknownSender) {
+		t.Errorf("err = %v, want ErrUnknownSender", err)
+	}
+}
+
+func TestHandleEnvelope_EvictsSelf(t *testing.T) {
+	sender, receiver := federatedPair(t)
+	third := newTestGovernance("otter-3")
+	receiver.rafts.rafts["otter-1"].Members["otter-3"].SigningKey = third.crypto.GetSigningPublicKey()
+	mirrorEviction(receiver, "otter-2")
+
+	if err := receiver.HandleEnvelope(context.Background(), revocationEnvelope(t, sender, "otter-2", sender, third)); err != nil {
+		t.Fatal(err)
+	}
+	if state := receiver.rafts.rafts["otter-1"].Members["otter-2"].State; state != StateRevoked {
+		t.Errorf("own membership state = %s, want revoked", state)
+	}
+}
+
+func TestHandleEnvelope_Rejections(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(env *Envelope)
+		want   error
+	}{
+		{"tampered payload", func(env *Envelope) { env.Payload = json.RawMessage(`{"raft_id":"otter-1","member_id":"otter-2"}`) }, ErrInvalidSignature},
+		{"other raft", func(env *Envelope) { env.RaftID = "otter-2" }, ErrUnknownSender},
+		{"unknown sender", func(env *Envelope) { env.SenderID = "otter-9" }, ErrUnknownSender},
+		{"unsigned", func(env *Envelope) { env.Signature = nil }, ErrUnsigned},
+		{"stale", func(env *Envelope) { env.Timestamp = time.Now().Add(-time.Hour) }, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender, receiver := federatedPair(t)
+			mirrorEviction(receiver, "otter-3")
+			env := revocationEnvelope(t, sender, "otter-3", sender, receiver)
+			tt.tamper(env)
+
+			err := receiver.HandleEnvelope(context.Background(), env)
+			if err == nil {
+				t.Fatal("expected the envelope to be rejected")
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+			if state := receiver.rafts.rafts["otter-1"].Members["otter-3"].State; state != StateActive {
+				t.Errorf("rejected envelope changed state to %s", state)
+			}
+		})
+	}
+}
+
+func TestHandleEnvelope_ForgedBallot(t *testing.T) {
+	sender, receiver := federatedPair(t)
+	mirrorEviction(receiver, "otter-3")
+	forger := newTestGovernance("otter-2") // Not the receiver's key for otter-2
+	ballot, _ := forger.SignVote("p1", VoteYes)
+
+	env, _ := sender.sealEnvelope(MessageMemberRevoked, "otter-1", MemberRevocation{
+		RaftID: "otter-1", MemberID: "otter-3", ProposalID: "p1",
+		Ballots: []SignedVote{ballot},
+	})
+	if err := receiver.HandleEnvelope(context.Background(), env); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("err = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestHTTPTransport_Send(t *testing.T) {
+	sender, receiver := federatedPair(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != FederationPath {
+			http.NotFound(w, r)
+			return
+		}
+		var env Envelope
+		if err := json.NewDecoder(r.Body).Decode(&env); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := receiver.HandleEnvelope(r.Context(), &env); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	mirrorEviction(receiver, "otter-3")
+	transport := sender.federation()
+	if err := transport.Send(context.Background(), srv.URL, revocationEnvelope(t, sender, "otter-3", sender, receiver)); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if state := receiver.rafts.rafts["otter-1"].Members["otter-3"].State; state != StateRevoked {
+		t.Errorf("otter-3 state = %s, want revoked", state)
+	}
+
+	env := revocationEnvelope(t, sender, "otter-3", sender, receiver)
+	env.SenderID = "otter-9"
+	if err := transport.Send(context.Background(), srv.URL, env); err == nil {
+		t.Error("expected a rejected envelope to return an error")
+	}
+}
@@ -3,20 +3,35 @@ package governance
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"otter-ai/internal/errs"
+	"otter-ai/internal/events"
 	"otter-ai/internal/llm"
 	"otter-ai/internal/memory"
+	"otter-ai/internal/prompts"
+	"otter-ai/internal/tracing"
 )
 
+var tracer = tracing.Tracer("governance")
+
 // Constants for governance thresholds and timeouts
 const (
 	MemberExpirationDays    = 90
@@ -29,19 +44,42 @@ const (
 	GovernanceHTTPTimeout   = 15 * time.Second
 	NegotiationVoteTimeout  = 30 * time.Second
 	NegotiationPollInterval = 500 * time.Millisecond
+	VoteClockSkew           = 5 * time.Minute // Tolerance for vote timestamps
 )
 
 // Governance system implementing Raft-based governance model
 type Governance struct {
-	config       RaftConfig
-	memory       *memory.Memory
-	rafts        *RaftRegistry        // All rafts this otter is part of
-	rules        *RuleRegistry        // Global rule registry
-	proposals    *ProposalRegistry    // Proposal registry
-	negotiations *NegotiationRegistry // Inter-raft negotiations
-	crypto       *CryptoSystem
-	mu           sync.RWMutex
-	shutdownCh   chan struct{}
+	config          RaftConfig
+	memory          *memory.Memory
+	rafts           *RaftRegistry        // All rafts this otter is part of
+	rules           *RuleRegistry        // Global rule registry
+	proposals       *ProposalRegistry    // Proposal registry
+	negotiations    *NegotiationRegistry // Inter-raft negotiations
+	crypto          *CryptoSystem
+	rollovers       []KeyRollover         // Rotations of this otter's signing key, oldest first
+	llm             llm.Provider          // Used to replay deferred LLM tasks
+	embedder        llm.EmbeddingProvider // Pre-filters rule pairs for semantic conflict checks
+	prompts         *prompts.Registry     // Nil renders the built-in prompts
+	tasks           *LLMTaskQueue         // Governance tasks awaiting LLM replay
+	tasksOnce       sync.Once
+	drift           *driftState // Latest rule drift reports per raft and peer
+	driftOnce       sync.Once
+	events          atomic.Pointer[events.Bus] // Receives proposal, vote and rule events
+	transport       atomic.Pointer[Transport]  // Delivers federation envelopes; HTTP when unset
+	chaos           atomic.Pointer[chaosState] // Faults injected into deliveries in chaos mode
+	audit           *auditLog                  // Audit entries kept in memory when no database is available
+	auditOnce       sync.Once
+	holds           *holdRegistry // Legal holds, released or not
+	holdsOnce       sync.Once
+	challenges      *joinChallenges // Join challenges awaiting an answer
+	challengesOnce  sync.Once
+	suspensions     *suspensionRegistry // Emergency rule suspensions, resolved or not
+	suspensionsOnce sync.Once
+	mu              sync.RWMutex
+	shutdownCh      chan struct{}
+	shutdownOnce    sync.Once
+	background      sync.WaitGroup // Monitors and workers started by New
+	readOnly        atomic.Bool    // Local proposals, votes and joins are refused with ErrReadOnly
 }
 
 // RaftConfig holds governance configuration
@@ -50,7 +88,39 @@ type RaftConfig struct {
 	Type          RaftType // Deprecated: kept for backwards compatibility
 	BindAddr      string
 	AdvertiseAddr string
+	PeerEndpoint  string // HTTP API address advertised to peers for rule sync
 	DataDir       string
+	VotingPeriod  time.Duration // Default time proposals stay open; 0 uses DefaultVotingPeriod
+	// HeartbeatInterval is how often raft peers are sent a heartbeat; zero
+	// disables heartbeats, and with them marking quiet peers inactive
+	HeartbeatInterval time.Duration
+	// HeartbeatGrace is how long a peer may go unheard before it is marked
+	// inactive; 0 uses DefaultHeartbeatGrace
+	HeartbeatGrace time.Duration
+	// LeaveRules decides what happens to a raft's rules when this otter
+	// leaves it; empty drops them
+	LeaveRules LeaveRules
+	// Enforcement decides how active rules constrain the agent's replies
+	// and tool calls; empty enforces rule directives
+	Enforcement EnforcementMode
+	// VotingPolicy is the policy of rafts without a voting rule of their
+	// own; zero fields use DefaultVotingPolicy
+	VotingPolicy VotingPolicy
+	// SuspensionQuorum is how many YES votes suspend a rule in an
+	// emergency, capped at the raft's voters; 0 uses DefaultSuspensionQuorum
+	SuspensionQuorum int
+	// SuspensionWindow is how long an emergency suspension stays open; 0
+	// uses DefaultSuspensionWindow
+	SuspensionWindow time.Duration
+	// BootstrapRules are adopted in the solo raft the first time this otter
+	// initializes it, so it doesn't start with an empty constitution
+	BootstrapRules []BootstrapRule
+	// Chaos starts the otter in chaos mode, injecting these faults into
+	// federation deliveries and allowing them to be changed at runtime.
+	// Nil disables chaos mode; it is meant for test networks only.
+	Chaos *ChaosConfig
+	// Logger receives governance logs; nil logs to slog's default logger
+	Logger *slog.Logger
 }
 
 // RaftType is deprecated but kept for backwards compatibility
@@ -71,6 +141,8 @@ const (
 	StateExpired  MembershipState = "expired"
 	StateRevoked  MembershipState = "revoked"
 	StateLeft     MembershipState = "left"
+	StatePending  MembershipState = "pending"  // Requested to join; awaiting the admission vote
+	StateRejected MembershipState = "rejected" // Join request voted down or expired
 )
 
 // Member represents a raft member
@@ -80,9 +152,40 @@ type Member struct {
 	JoinedAt   time.Time
 	LastSeenAt time.Time
 	PublicKey  []byte
-	Signature  []byte
+	SigningKey []byte // Ed25519 key the member signs rules and votes with
+	Signature  []byte // Local otter's signature over the membership record
 	InductedBy string
 	ExpiresAt  *time.Time
+	Endpoint   string // HTTP API address used to reach the member, if known
+	// Capabilities negotiated with the member at join; nil for members that
+	// joined before the capability handshake existed
+	Capabilities *Capabilities
+}
+
+// JoinRequest is a peer's request to be inducted into a raft
+type JoinRequest struct {
+	RaftID      string
+	RequesterID string
+	PublicKey   []byte
+	SigningKey  []byte                // May be nil for peers that predate rule signing
+	Endpoint    string                // Requester's advertised API address, if any
+	Descriptor  *CapabilityDescriptor // May be nil for peers that predate the capability handshake
+
+	Nonce              string // From IssueJoinChallenge
+	ChallengeSignature []byte // Nonce signed with the key for SigningKey
+	Invite             string // Invite token, if the requester was invited
+}
+
+// JoinResponse identifies the inducting otter to the new member
+type JoinResponse struct {
+	MemberID     string
+	PublicKey    []byte
+	SigningKey   []byte
+	Endpoint     string
+	Descriptor   *CapabilityDescriptor
+	Capabilities *Capabilities   // Negotiated with the requester
+	State        MembershipState // Pending until the raft votes on the admission proposal
+	ProposalID   string          // Admission proposal, for pending requesters
 }
 
 // RaftInfo describes a raft group
@@ -91,7 +194,19 @@ type RaftInfo struct {
 	Members   map[string]*Member // memberID -> Member
 	Rules     map[string]*Rule   // ruleID -> Rule
 	CreatedAt time.Time
-	mu        sync.RWMutex
+	Archive   *RaftArchive // Set once the raft is archived and read-only
+	// VotingPolicy is set by the raft's adopted voting rule; nil uses the
+	// configured defaults
+	VotingPolicy *VotingPolicy
+	// Metadata names and describes the raft; nil until it is first set
+	Metadata *RaftMetadata
+	// Provisional rafts stand in for a raft this otter is negotiating to
+	// join; they are never persisted and are removed if the join fails
+	Provisional bool
+	// UsedInvites holds the IDs of invites redeemed to join the raft until
+	// they expire, so an invite cannot be replayed after a restart
+	UsedInvites map[string]time.Time
+	mu          sync.RWMutex
 }
 
 // RaftRegistry manages multiple raft memberships
@@ -109,9 +224,13 @@ type Rule struct {
 	Timestamp  time.Time
 	Body       string
 	BaseRuleID string // For overrides
-	Signature  []byte
+	Signature  []byte // Ed25519 signature over the canonical rule payload
+	SignedBy   string // Otter that produced Signature
+	SignerKey  []byte // Ed25519 public key of SignedBy
 	ProposedBy string
 	AdoptedAt  *time.Time
+	ProposalID string       // Proposal that adopted the rule; empty for rules adopted without a vote
+	Ballots    []SignedVote // Signed votes that adopted it, so peers can check the adoption
 }
 
 // RuleConflict represents a conflict between two raft rules
@@ -121,7 +240,9 @@ type RuleConflict struct {
 	Raft2ID       string
 	Rule1         *Rule
 	Rule2         *Rule
-	ConflictScope string // What scope these rules conflict on
+	ConflictScope string  // What scope these rules conflict on
+	Confidence    float64 // 1 for conflicts in overlapping scopes; the LLM's confidence for semantic ones
+	Reason        string  // Why the LLM judged the rules contradictory, for semantic conflicts
 	DetectedAt    time.Time
 }
 
@@ -134,18 +255,45 @@ const (
 	VoteAbstain VoteType = "ABSTAIN"
 )
 
+// SignedVote is a ballot signed with the voter's Ed25519 key over
+// (ProposalID, Vote, Timestamp)
+type SignedVote struct {
+	ProposalID string
+	VoterID    string
+	Vote       VoteType
+	Timestamp  time.Time
+	Signature  []byte
+}
+
 // Proposal represents a rule proposal
 type Proposal struct {
-	ProposalID string
-	RaftID     string // Which raft this proposal is for
-	Rule       *Rule
-	ProposedBy string
-	ProposedAt time.Time
-	Votes      map[string]VoteType
-	Status     ProposalStatus
-	QuorumMet  bool
-	Result     ProposalResult
-	ClosedAt   *time.Time
+	ProposalID     string
+	RaftID         string       // Which raft this proposal is for
+	Kind           ProposalKind // Empty for proposals made before kinds existed, which are rule proposals
+	Rule           *Rule        // Nil for eviction proposals
+	TargetMemberID string       // Member to revoke, for eviction proposals
+	Reason         string       // Why the proposer wants the change, if given
+	ProposedBy     string
+	ProposedAt     time.Time
+	Votes          map[string]VoteType
+	Ballots        map[string]*SignedVote // Signed ballots backing Votes
+	Status         ProposalStatus
+	QuorumMet      bool
+	Result         ProposalResult
+	ClosedAt       *time.Time
+	Deadline       time.Time       // Voting closes at this time; the proposal is then rejected
+	Expired        bool            // Closed because the deadline passed
+	EligibleVoters []string        // Active members when the proposal opened; nil for proposals made before snapshots
+	Origin         string          // Otter keeping the canonical tally when mirrored from a peer; empty when this otter keeps it
+	Impact         *ImpactAnalysis // LLM analysis of a rule proposal's effects; nil without an LLM provider
+}
+
+// kind returns the proposal kind, treating an empty kind as a rule proposal
+func (p *Proposal) kind() ProposalKind {
+	if p.Kind == "" {
+		return ProposalKindRule
+	}
+	return p.Kind
 }
 
 // Negotiation represents an inter-raft rule negotiation
@@ -155,13 +303,19 @@ type Negotiation struct {
 	Raft2ID        string
 	TargetEndpoint string
 	Conflicts      []*RuleConflict
+	Raft2Rules     []*Rule   // The target raft's rules, fed to the negotiation
 	ProposedRule   *Rule     // The negotiated compromise rule
 	Raft1Proposal  *Proposal // Proposal in raft 1
 	Raft2Proposal  *Proposal // Proposal in raft 2
 	Status         NegotiationStatus
 	StartedAt      time.Time
 	CompletedAt    *time.Time
-	LLMTranscript  []string // Record of LLM negotiation
+	LLMTranscript  []string           // Record of LLM negotiation
+	Rounds         []NegotiationRound // Drafts and critiques, round by round
+	FailureReason  string             // Why the vote or join failed, once rolled back or failed
+	ReviewedBy     string             // Who approved or rejected the compromise
+	ReviewedAt     *time.Time
+	Edited         bool // The reviewer changed the compromise's text before it was proposed
 }
 
 // NegotiationStatus defines negotiation state
@@ -169,8 +323,13 @@ type NegotiationStatus string
 
 const (
 	NegotiationInProgress NegotiationStatus = "in_progress"
-	NegotiationResolved   NegotiationStatus = "resolved"
+	NegotiationResolved   NegotiationStatus = "resolved" // A compromise was drafted and awaits approval
 	NegotiationFailed     NegotiationStatus = "failed"
+	NegotiationDeferred   NegotiationStatus = "deferred" // Waiting for the LLM provider to recover
+	NegotiationVoting     NegotiationStatus = "voting"   // Both rafts are voting on the compromise
+	NegotiationPeered     NegotiationStatus = "peered"   // Both rafts adopted it and the join completed
+	NegotiationRolledBack NegotiationStatus = "rolled_back"
+	NegotiationRejected   NegotiationStatus = "rejected" // The reviewer turned the compromise down
 )
 
 // NegotiationRegistry manages inter-raft negotiations
@@ -191,24 +350,45 @@ const (
 type ProposalResult string
 
 const (
-	ResultPending  ProposalResult = "pending"
-	ResultAdopted  ProposalResult = "adopted"
-	ResultRejected ProposalResult = "rejected"
+	ResultPending   ProposalResult = "pending"
+	ResultAdopted   ProposalResult = "adopted"
+	ResultRejected  ProposalResult = "rejected"
+	ResultWithdrawn ProposalResult = "withdrawn" // Closed undecided, e.g. when a negotiated join is rolled back
 )
 
 // RuleRegistry manages governance rules
 type RuleRegistry struct {
 	rules  map[string]*Rule
-	active map[string]*Rule // Active rules by scope
+	active map[ruleKey]*Rule // Active rules by raft and scope
 	mu     sync.RWMutex
 }
 
+// ruleKey identifies the active rule slot of a scope within one raft, so
+// rafts with rules of the same scope don't shadow each other
+type ruleKey struct {
+	RaftID string
+	Scope  string
+}
+
+// activeKey returns the registry key of a rule
+func activeKey(rule *Rule) ruleKey {
+	return ruleKey{RaftID: rule.RaftID, Scope: rule.Scope}
+}
+
 // ProposalRegistry manages proposals
 type ProposalRegistry struct {
 	proposals map[string]*Proposal
 	mu        sync.RWMutex
 }
 
+// log returns the governance logger
+func (g *Governance) log() *slog.Logger {
+	if g.config.Logger == nil {
+		return slog.Default()
+	}
+	return g.config.Logger
+}
+
 // New creates a new governance system
 func New(config RaftConfig, mem *memory.Memory) (*Governance, error) {
 	// Initialize cryptographic system (load existing or generate new)
@@ -216,6 +396,10 @@ func New(config RaftConfig, mem *memory.Memory) (*Governance, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize crypto system: %w", err)
 	}
+	rollovers, err := LoadKeyRollovers(config.DataDir)
+	if err != nil {
+		return nil, err
+	}
 
 	g := &Governance{
 		config: config,
@@ -225,7 +409,7 @@ func New(config RaftConfig, mem *memory.Memory) (*Governance, error) {
 		},
 		rules: &RuleRegistry{
 			rules:  make(map[string]*Rule),
-			active: make(map[string]*Rule),
+			active: make(map[ruleKey]*Rule),
 		},
 		proposals: &ProposalRegistry{
 			proposals: make(map[string]*Proposal),
@@ -234,9 +418,21 @@ func New(config RaftConfig, mem *memory.Memory) (*Governance, error) {
 			negotiations: make(map[string]*Negotiation),
 		},
 		crypto:     cryptoSystem,
+		rollovers:  rollovers,
 		shutdownCh: make(chan struct{}),
 	}
 
+	if err := ValidateBootstrapRules(config.BootstrapRules); err != nil {
+		return nil, err
+	}
+	if config.Chaos != nil {
+		if err := g.SetChaos(*config.Chaos); err != nil {
+			return nil, err
+		}
+		g.log().Warn("chaos mode is on; federation deliveries will be disrupted")
+	}
+	fresh := g.isFresh()
+
 	// Initialize this otter as a solo raft
 	if err := g.initializeSelf(); err != nil {
 		return nil, fmt.Errorf("failed to initialize self: %w", err)
@@ -246,11 +442,46 @@ func New(config RaftConfig, mem *memory.Memory) (*Governance, error) {
 	// This will restore any additional rafts this otter was part of
 	if err := g.loadGovernanceState(context.Background()); err != nil {
 		// Don't fail if persistence is not available yet, just log
-		fmt.Printf("Note: Could not load persisted governance state (may be first run): %v\n", err)
+		g.log().Info("could not load persisted governance state (may be first run)", "error", err)
+	}
+
+	// Seed the audit log from stored state on first run after upgrading
+	g.backfillAudit(context.Background())
+	g.recordKeyInstalled(context.Background())
+	g.adoptRotatedKey(context.Background())
+
+	if fresh {
+		if err := g.adoptBootstrapRules(config.BootstrapRules); err != nil {
+			return nil, fmt.Errorf("failed to adopt bootstrap rules: %w", err)
+		}
+	}
+
+	// Restore LLM tasks that were still waiting for replay
+	if err := g.loadLLMTasks(context.Background()); err != nil {
+		g.log().Info("could not load queued LLM tasks", "error", err)
+	}
+
+	// Restore legal holds
+	if err := g.loadHolds(context.Background()); err != nil {
+		g.log().Info("could not load legal holds", "error", err)
+	}
+
+	// Keep suspended rules out of force
+	if err := g.loadSuspensions(context.Background()); err != nil {
+		g.log().Info("could not load rule suspensions", "error", err)
 	}
 
 	// Start background tasks
-	go g.livenessMonitor()
+	g.goBackground(g.livenessMonitor)
+	g.goBackground(g.llmTaskWorker)
+	g.goBackground(g.driftMonitor)
+	g.goBackground(g.proposalSweeper)
+	if len(g.rollovers) > 0 {
+		go g.announceKeyRollovers(context.Background())
+	}
+	if config.HeartbeatInterval > 0 {
+		g.goBackground(g.heartbeatMonitor)
+	}
 
 	return g, nil
 }
@@ -264,8 +495,12 @@ func (g *Governance) initializeSelf() error {
 		JoinedAt:   now,
 		LastSeenAt: now,
 		PublicKey:  g.crypto.GetPublicKey(),
+		SigningKey: g.crypto.GetSigningPublicKey(),
 		InductedBy: "self", // Bootstrap
 	}
+	if err := g.signMember(g.config.ID, member); err != nil {
+		return err
+	}
 
 	// Create initial raft with just this otter
 	raft := &RaftInfo{
@@ -283,7 +518,7 @@ func (g *Governance) initializeSelf() error {
 	ctx := context.Background()
 	if err := g.saveRaft(ctx, raft); err != nil {
 		// Don't fail initialization if persistence fails
-		fmt.Printf("Warning: Failed to persist initial raft: %v\n", err)
+		g.log().Warn("failed to persist initial raft", "error", err)
 	}
 
 	return nil
@@ -304,38 +539,92 @@ func (g *Governance) livenessMonitor() {
 	}
 }
 
-// checkExpiredMembers marks members expired after 90 days of inactivity
+// checkExpiredMembers marks members expired after 90 days of inactivity,
+// whether or not they were already marked inactive for going quiet
 func (g *Governance) checkExpiredMembers() {
+	for _, raftID := range g.expireMembers() {
+		g.recountProposals(raftID)
+	}
+}
+
+// expireMembers marks inactive members expired and returns the rafts that
+// lost members
+func (g *Governance) expireMembers() []string {
 	g.rafts.mu.Lock()
 	defer g.rafts.mu.Unlock()
 
+	var changed []string
 	expirationThreshold := time.Now().Add(-MemberExpirationDays * 24 * time.Hour)
 
 	for _, raft := range g.rafts.rafts {
 		raft.mu.Lock()
+		if raft.Archive != nil {
+			raft.mu.Unlock()
+			continue // Archived memberships are frozen as they were
+		}
+		expired := false
 		for _, member := range raft.Members {
-			if member.State == StateActive && member.LastSeenAt.Before(expirationThreshold) {
+			if (member.State == StateActive || member.State == StateInactive) && member.LastSeenAt.Before(expirationThreshold) {
 				member.State = StateExpired
 				expiresAt := member.LastSeenAt.Add(MemberExpirationDays * 24 * time.Hour)
 				member.ExpiresAt = &expiresAt
+				g.recordAudit(AuditMemberStateChanged, raft.RaftID, member.ID, g.config.ID,
+					MemberAudit{MemberID: member.ID, State: StateExpired})
+				expired = true
 			}
 		}
 		raft.mu.Unlock()
+		if expired {
+			changed = append(changed, raft.RaftID)
+		}
 	}
+	return changed
 }
 
-// ProposeRule submits a new rule proposal for a specific raft
+// ProposeRule submits a new rule proposal for a specific raft, open for the
+// default voting period
 func (g *Governance) ProposeRule(ctx context.Context, raftID string, rule *Rule) (*Proposal, error) {
+	return g.ProposeRuleWithVotingPeriod(ctx, raftID, rule, 0)
+}
+
+// ProposeRuleWithVotingPeriod submits a rule proposal that is closed as
+// rejected if it is still open after votingPeriod. Zero uses the default.
+func (g *Governance) ProposeRuleWithVotingPeriod(ctx context.Context, raftID string, rule *Rule, votingPeriod time.Duration) (_ *Proposal, err error) {
+	ctx, span := tracer.Start(ctx, "governance.ProposeRule", trace.WithAttributes(
+		attribute.String("governance.raft_id", raftID),
+		attribute.String("governance.scope", rule.Scope),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	if err := g.writable(); err != nil {
+		return nil, err
+	}
+	if err := ValidateVotingPeriod(votingPeriod); err != nil {
+		return nil, err
+	}
+	if _, err := ParseRuleDirectives(rule.Body); err != nil {
+		return nil, err
+	}
+	if votingPeriod == 0 {
+		votingPeriod = g.VotingPeriod()
+	}
+
+	proposal, err := g.openRuleProposal(raftID, rule, votingPeriod)
+	if err != nil {
+		return nil, err
+	}
+	g.analyzeImpact(ctx, proposal)
+	return proposal, nil
+}
+
+// openRuleProposal signs a rule and opens the proposal to adopt it
+func (g *Governance) openRuleProposal(raftID string, rule *Rule, votingPeriod time.Duration) (*Proposal, error) {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
-	// Validate raft exists
-	g.rafts.mu.RLock()
-	raft, exists := g.rafts.rafts[raftID]
-	g.rafts.mu.RUnlock()
-
-	if !exists {
-		return nil, fmt.Errorf("raft not found: %s", raftID)
+	raft, err := g.liveRaft(raftID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Validate proposer is active member of this raft
@@ -354,43 +643,123 @@ func (g *Governance) ProposeRule(ctx context.Context, raftID string, rule *Rule)
 	// Set raft ID on rule
 	rule.RaftID = raftID
 
+	// An amendment is the next version of the rule it amends
+	if rule.BaseRuleID != "" {
+		base, err := g.amendmentBase(raftID, rule.BaseRuleID)
+		if err != nil {
+			return nil, err
+		}
+		if rule.Scope == "" {
+			rule.Scope = base.Scope
+		}
+		rule.Version = base.Version + 1
+	} else if rule.Version == 0 {
+		rule.Version = 1
+	}
+	if err := ValidateScope(rule.Scope); err != nil {
+		return nil, err
+	}
+	switch rule.Scope {
+	case VotingScope:
+		if _, err := ParseVotingPolicy(rule.Body); err != nil {
+			return nil, err
+		}
+	case MetadataScope:
+		if _, err := ParseRaftMetadata(rule.Body); err != nil {
+			return nil, err
+		}
+	case CharterScope:
+		if err := g.validateCharter(raftID, rule.Body); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := ParseRuleEffects(rule.Body); err != nil {
+		return nil, err
+	}
+
 	if rule.RuleID == "" {
 		rule.RuleID = generateID(rule)
 	}
 
-	// Generate proposal ID
-	proposalID := generateID(rule)
+	// Sign the rule so peers can verify where it came from
+	if err := g.signRule(rule); err != nil {
+		return nil, err
+	}
+
+	// The proposal ID is bound to the signed rule, so ballots on it can
+	// vouch for the rule's adoption
+	proposalID := ruleProposalID(rule)
 
+	now := time.Now()
 	proposal := &Proposal{
 		ProposalID: proposalID,
 		RaftID:     raftID,
+		Kind:       ProposalKindRule,
 		Rule:       rule,
 		ProposedBy: rule.ProposedBy,
-		ProposedAt: time.Now(),
+		ProposedAt: now,
+		Deadline:   now.Add(votingPeriod),
 		Votes:      make(map[string]VoteType),
+		Ballots:    make(map[string]*SignedVote),
 		Status:     ProposalOpen,
 		Result:     ResultPending,
+
+		EligibleVoters: snapshotVoters(raft, ""),
 	}
 
 	g.proposals.mu.Lock()
 	g.proposals.proposals[proposalID] = proposal
 	g.proposals.mu.Unlock()
 
+	g.publish(events.ProposalCreated, proposalEvent(proposal))
+	g.recordAudit(AuditProposalCreated, raftID, proposalID, proposal.ProposedBy, proposalEvent(proposal))
+	g.announceProposal(proposal)
+
 	return proposal, nil
 }
 
-// Vote casts a vote on a proposal
-func (g *Governance) Vote(ctx context.Context, proposalID, voterID string, vote VoteType) error {
+// ErrProposalNotFound is returned for operations on an unknown proposal
+var ErrProposalNotFound = errs.New(errs.ErrNotFound, "proposal not found")
+
+// ErrProposalClosed is returned for votes on a proposal already decided
+var ErrProposalClosed = errs.New(errs.ErrQuorum, "proposal is closed")
+
+// Vote records a signed vote on a proposal. The signature must verify
+// against the signing key stored for the voter's membership.
+func (g *Governance) Vote(ctx context.Context, ballot SignedVote) (err error) {
+	ctx, span := tracer.Start(ctx, "governance.Vote", trace.WithAttributes(
+		attribute.String("governance.proposal_id", ballot.ProposalID),
+		attribute.String("governance.voter_id", ballot.VoterID),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	if err := g.writable(); err != nil {
+		return err
+	}
+	return g.recordVote(ctx, ballot, true)
+}
+
+// recordVote validates and records a ballot. Ballots cast through this
+// otter are relayed to the raft's other members; ballots relayed from a
+// peer are not passed on. Only the otter keeping a proposal's canonical
+// tally decides its outcome.
+func (g *Governance) recordVote(ctx context.Context, ballot SignedVote, relay bool) error {
 	g.proposals.mu.Lock()
 	defer g.proposals.mu.Unlock()
 
-	proposal, exists := g.proposals.proposals[proposalID]
+	proposal, exists := g.proposals.proposals[ballot.ProposalID]
 	if !exists {
-		return fmt.Errorf("proposal not found")
+		return ErrProposalNotFound
 	}
 
 	if proposal.Status != ProposalOpen {
-		return fmt.Errorf("proposal is closed")
+		return ErrProposalClosed
+	}
+
+	// Close proposals the sweeper has not reached yet
+	if now := time.Now(); proposal.pastDeadline(now) {
+		g.expireProposal(proposal, now)
+		return ErrProposalExpired
 	}
 
 	// Validate voter is active member of the proposal's raft
@@ -399,123 +768,231 @@ func (g *Governance) Vote(ctx context.Context, proposalID, voterID string, vote
 	g.rafts.mu.RUnlock()
 
 	if !exists {
-		return fmt.Errorf("raft not found")
+		return ErrRaftNotFound
 	}
 
 	raft.mu.RLock()
-	voter, exists := raft.Members[voterID]
+	voter, exists := raft.Members[ballot.VoterID]
+	var signingKey []byte
+	var voterState MembershipState
+	if exists {
+		signingKey, voterState = voter.SigningKey, voter.State
+	}
 	raft.mu.RUnlock()
 
-	if !exists || voter.State != StateActive {
-		return fmt.Errorf("voter must be an active member of this raft")
+	if !exists || voterState != StateActive {
+		return errs.New(errs.ErrUnauthorized, "voter must be an active member of this raft")
+	}
+	if proposal.Kind == ProposalKindEviction && ballot.VoterID == proposal.TargetMemberID {
+		return errs.New(errs.ErrQuorum, "a member cannot vote on its own eviction")
+	}
+	if proposal.EligibleVoters != nil && !hasVoter(proposal.EligibleVoters, ballot.VoterID) {
+		return errs.Errorf(errs.ErrQuorum, "%s joined after the proposal opened and cannot vote on it", ballot.VoterID)
 	}
 
-	proposal.Votes[voterID] = vote
+	if err := verifyVote(&ballot, signingKey); err != nil {
+		return err
+	}
+
+	// Reject timestamps that could not belong to a vote on this proposal
+	if ballot.Timestamp.After(time.Now().Add(VoteClockSkew)) {
+		return fmt.Errorf("vote timestamp is in the future")
+	}
+	if ballot.Timestamp.Before(proposal.ProposedAt.Add(-VoteClockSkew)) {
+		return fmt.Errorf("vote timestamp predates the proposal")
+	}
+
+	// A replayed older ballot must not overwrite a newer one
+	if previous, ok := proposal.Ballots[ballot.VoterID]; ok && !ballot.Timestamp.After(previous.Timestamp) {
+		return fmt.Errorf("vote from %s is not newer than the recorded ballot", ballot.VoterID)
+	}
+
+	if proposal.Ballots == nil {
+		proposal.Ballots = make(map[string]*SignedVote)
+	}
+	proposal.Votes[ballot.VoterID] = ballot.Vote
+	proposal.Ballots[ballot.VoterID] = &ballot
+
+	voteEvent := VoteEvent{
+		ProposalID: proposal.ProposalID,
+		RaftID:     proposal.RaftID,
+		VoterID:    ballot.VoterID,
+		Vote:       ballot.Vote,
+	}
+	g.publish(events.VoteCast, voteEvent)
+	g.recordAudit(AuditVoteCast, proposal.RaftID, proposal.ProposalID, ballot.VoterID, voteEvent)
+
+	if relay {
+		g.relayVote(proposal, ballot)
+	}
 
 	// Check if voting is complete
-	g.checkProposalOutcome(proposal)
+	if g.tallies(proposal) {
+		g.checkProposalOutcome(proposal)
+	}
 
 	return nil
 }
 
-// checkProposalOutcome determines if a proposal has reached a decision
-func (g *Governance) checkProposalOutcome(proposal *Proposal) {
-	activeMembers := g.getActiveMembers(proposal.RaftID)
-	if activeMembers == nil {
-		// Raft doesn't exist - should not happen if proposal was created correctly
-		return
-	}
-	totalActive := len(activeMembers)
-	if totalActive == 0 {
-		// No active members - proposal cannot proceed
-		return
+// SignVote signs a ballot as this otter
+func (g *Governance) SignVote(proposalID string, vote VoteType) (SignedVote, error) {
+	ballot := SignedVote{
+		ProposalID: proposalID,
+		VoterID:    g.config.ID,
+		Vote:       vote,
+		Timestamp:  time.Now(),
 	}
 
-	// Count votes
-	yesVotes := 0
-	noVotes := 0
+	sig, err := g.crypto.Sign(voteSigningPayload(&ballot))
+	if err != nil {
+		return SignedVote{}, fmt.Errorf("failed to sign vote: %w", err)
+	}
+	ballot.Signature = sig
+	return ballot, nil
+}
 
-	for _, vote := range proposal.Votes {
-		switch vote {
-		case VoteYes:
-			yesVotes++
-		case VoteNo:
-			noVotes++
-		}
+// CastVote signs and records a vote from this otter
+func (g *Governance) CastVote(ctx context.Context, proposalID string, vote VoteType) error {
+	ballot, err := g.SignVote(proposalID, vote)
+	if err != nil {
+		return err
 	}
+	return g.Vote(ctx, ballot)
+}
 
-	votescast := len(proposal.Votes)
-	totalVotes := yesVotes + noVotes
+// VoteInteraction records the chat platform interaction a vote came from
+type VoteInteraction struct {
+	Platform  string    `json:"platform"`
+	ChannelID string    `json:"channel_id,omitempty"`
+	MessageID string    `json:"message_id,omitempty"`
+	UserID    string    `json:"user_id"`
+	Username  string    `json:"username,omitempty"`
+	At        time.Time `json:"at"`
+}
 
-	// Determine adoption based on raft size
-	var adopted bool
-	var shouldClose bool
+// VoteInteractionAudit is the data of vote.interaction audit entries
+type VoteInteractionAudit struct {
+	Vote        VoteType        `json:"vote"`
+	Interaction VoteInteraction `json:"interaction"`
+}
 
-	switch totalActive {
-	case 1:
-		// Solo otter: auto-adopt if they vote YES, reject if NO
-		proposal.QuorumMet = votescast >= 1
-		if votescast >= 1 {
-			adopted = yesVotes >= 1
-			shouldClose = true
-		}
+// CastInteractiveVote signs and records a vote from this otter made through
+// a chat platform, and records the interaction in the audit log
+func (g *Governance) CastInteractiveVote(ctx context.Context, proposalID string, vote VoteType, interaction VoteInteraction) error {
+	if err := g.CastVote(ctx, proposalID, vote); err != nil {
+		return err
+	}
 
-	case 2:
-		// Two otters: require unanimous consent (both must vote YES)
-		proposal.QuorumMet = votescast >= 2 // Both must vote
-		if proposal.QuorumMet {
-			adopted = yesVotes == 2 && noVotes == 0
-			shouldClose = true
-		}
+	raftID := g.config.ID
+	if proposal, ok := g.GetProposal(proposalID); ok {
+		raftID = proposal.RaftID
+	}
+	g.recordAudit(AuditVoteInteraction, raftID, proposalID, g.config.ID,
+		VoteInteractionAudit{Vote: vote, Interaction: interaction})
+	return nil
+}
 
-	default:
-		// 3+ otters: 2/3 majority of total active members
-		// Quorum: at least 2/3 must participate
-		quorumThreshold := (totalActive*QuorumPercentage + 99) / 100 // Ceiling calculation
-		proposal.QuorumMet = votescast >= quorumThreshold
+// DelegatedVoteAudit is the data of vote.delegated audit entries
+type DelegatedVoteAudit struct {
+	Vote   VoteType `json:"vote"`
+	Scope  string   `json:"scope"` // Scope of the policy applied
+	Mode   string   `json:"mode"`
+	Reason string   `json:"reason,omitempty"`
+}
 
-		if !proposal.QuorumMet {
-			return
-		}
+// CastDelegatedVote signs and records a vote this otter cast on its own
+// under a standing policy set by its owner, and records the policy in the
+// audit log
+func (g *Governance) CastDelegatedVote(ctx context.Context, proposalID string, vote VoteType, delegation DelegatedVoteAudit) error {
+	if err := g.CastVote(ctx, proposalID, vote); err != nil {
+		return err
+	}
 
-		if totalVotes == 0 {
-			return
-		}
+	raftID := g.config.ID
+	if proposal, ok := g.GetProposal(proposalID); ok {
+		raftID = proposal.RaftID
+	}
+	delegation.Vote = vote
+	g.recordAudit(AuditVoteDelegated, raftID, proposalID, g.config.ID, delegation)
+	return nil
+}
 
-		// Determine if super-majority is needed (for overrides)
-		needsSuperMajority := proposal.Rule.BaseRuleID != ""
+// checkProposalOutcome determines if a proposal has reached a decision
+func (g *Governance) checkProposalOutcome(proposal *Proposal) {
+	switch proposal.Kind {
+	case ProposalKindEviction:
+		g.checkEvictionOutcome(proposal)
+		return
+	case ProposalKindSuspension:
+		g.checkSuspensionOutcome(proposal)
+		return
+	}
 
-		if needsSuperMajority {
-			// Super-majority: YES > 75% of total active members
-			requiredVotes := (totalActive*SuperMajorityPercentage + 99) / 100 // Ceiling calculation
-			adopted = yesVotes >= requiredVotes
-		} else {
-			// 2/3 majority: YES >= 2/3 of total active members
-			requiredVotes := (totalActive*QuorumPercentage + 99) / 100 // Ceiling calculation
-			adopted = yesVotes >= requiredVotes
-		}
+	voters := g.eligibleVoters(proposal)
+	totalActive := len(voters)
+	if totalActive == 0 {
+		// No eligible voters left - proposal cannot proceed
+		return
+	}
 
-		// Close if decision reached or all members voted
-		shouldClose = adopted || votescast >= totalActive
+	// Count the votes of eligible voters only
+	count := tally{eligible: totalActive, proposerVote: proposal.Votes[proposal.ProposedBy]}
+	for _, voterID := range voters {
+		vote, ok := proposal.Votes[voterID]
+		if !ok {
+			continue
+		}
+		count.cast++
+		switch vote {
+		case VoteYes:
+			count.yes++
+		case VoteNo:
+			count.no++
+		}
 	}
 
+	quorumMet, adopted, shouldClose := g.proposalPolicy(proposal).decide(count, g.needsSuperMajority(proposal))
+	proposal.QuorumMet = quorumMet
+
 	if shouldClose {
 		if adopted {
 			proposal.Result = ResultAdopted
 			proposal.Status = ProposalClosed
 			now := time.Now()
 			proposal.ClosedAt = &now
-			proposal.Rule.AdoptedAt = &now
 
-			// Activate the rule
-			g.activateRule(proposal.Rule)
+			switch proposal.Kind {
+			case ProposalKindAdmission:
+				g.admitMember(proposal)
+			case ProposalKindReinstatement:
+				g.resolveSuspension(proposal)
+			default:
+				// Activate the rule and tell the rest of the raft, with
+				// the ballots that adopted it
+				proposal.Rule.AdoptedAt = &now
+				proposal.Rule.ProposalID = proposal.ProposalID
+				proposal.Rule.Ballots = sortedBallots(proposal)
+				g.activateRule(proposal.Rule)
+				g.announceRule(proposal.ProposalID, proposal.Rule)
+			}
 		} else {
 			// All members voted, but not adopted
 			proposal.Result = ResultRejected
 			proposal.Status = ProposalClosed
 			now := time.Now()
 			proposal.ClosedAt = &now
+
+			switch proposal.Kind {
+			case ProposalKindAdmission:
+				g.rejectAdmission(proposal)
+			case ProposalKindReinstatement:
+				g.resolveSuspension(proposal)
+			}
 		}
+
+		g.publish(events.ProposalClosed, proposalEvent(proposal))
+		g.recordAudit(AuditProposalClosed, proposal.RaftID, proposal.ProposalID, "", proposalEvent(proposal))
+		g.announceOutcome(proposal)
 	}
 }
 
@@ -523,7 +1000,7 @@ func (g *Governance) checkProposalOutcome(proposal *Proposal) {
 func (g *Governance) activateRule(rule *Rule) {
 	g.rules.mu.Lock()
 	g.rules.rules[rule.RuleID] = rule
-	g.rules.active[rule.Scope] = rule
+	g.rules.active[activeKey(rule)] = rule
 	g.rules.mu.Unlock()
 
 	// Add to raft's rules
@@ -536,10 +1013,19 @@ func (g *Governance) activateRule(rule *Rule) {
 		raft.Rules[rule.RuleID] = rule
 		raft.mu.Unlock()
 
-		// Persist the rule and raft to database
+		// Persist the rule and raft to database; a provisional raft's rules
+		// are persisted with the raft if the join completes
 		ctx := context.Background()
-		if err := g.saveRule(ctx, rule); err != nil {
-			fmt.Printf("Warning: Failed to persist rule %s: %v\n", rule.RuleID, err)
+		metaRule := g.applyMetaRule(raft, rule)
+		if !raft.Provisional {
+			if err := g.saveRule(ctx, rule); err != nil {
+				g.log().Warn("failed to persist rule", "rule_id", rule.RuleID, "error", err)
+			}
+			if metaRule {
+				if err := g.saveRaft(ctx, raft); err != nil {
+					g.log().Warn("failed to persist raft settings", "raft_id", raft.RaftID, "error", err)
+				}
+			}
 		}
 	}
 
@@ -547,11 +1033,19 @@ func (g *Governance) activateRule(rule *Rule) {
 	if rule.BaseRuleID != "" {
 		g.rules.mu.Lock()
 		baseRule := g.rules.rules[rule.BaseRuleID]
-		if baseRule != nil && g.rules.active[baseRule.Scope] == baseRule {
-			delete(g.rules.active, baseRule.Scope)
+		deactivated := baseRule != nil && g.rules.active[activeKey(baseRule)] == baseRule
+		if deactivated {
+			delete(g.rules.active, activeKey(baseRule))
 		}
 		g.rules.mu.Unlock()
+
+		if deactivated {
+			g.recordAudit(AuditRuleDeactivated, baseRule.RaftID, baseRule.RuleID, rule.ProposedBy, ruleEvent(baseRule))
+		}
 	}
+
+	g.publish(events.RuleAdopted, ruleEvent(rule))
+	g.recordAudit(AuditRuleAdopted, rule.RaftID, rule.RuleID, rule.ProposedBy, ruleEvent(rule))
 }
 
 // getActiveMembers returns all active members of a raft
@@ -567,6 +1061,11 @@ func (g *Governance) getActiveMembers(raftID string) []*Member {
 	raft.mu.RLock()
 	defer raft.mu.RUnlock()
 
+	// Archived rafts take no part in quorum or federation
+	if raft.Archive != nil {
+		return nil
+	}
+
 	var active []*Member
 	for _, member := range raft.Members {
 		if member.State == StateActive {
@@ -576,18 +1075,85 @@ func (g *Governance) getActiveMembers(raftID string) []*Member {
 	return active
 }
 
-// GetActiveRules returns all active rules
-func (g *Governance) GetActiveRules() map[string]*Rule {
+// snapshotVoters returns the IDs of a raft's active members other than
+// exclude, sorted. It is taken when a proposal opens so members joining
+// later do not move its quorum.
+func snapshotVoters(raft *RaftInfo, exclude string) []string {
+	raft.mu.RLock()
+	defer raft.mu.RUnlock()
+
+	voters := []string{}
+	for id, member := range raft.Members {
+		if member.State == StateActive && id != exclude {
+			voters = append(voters, id)
+		}
+	}
+	sort.Strings(voters)
+	return voters
+}
+
+// hasVoter reports whether a sorted voter snapshot includes id
+func hasVoter(voters []string, id string) bool {
+	i := sort.SearchStrings(voters, id)
+	return i < len(voters) && voters[i] == id
+}
+
+// eligibleVoters returns the members whose votes count towards a proposal:
+// its voter snapshot less anyone no longer active. Proposals opened before
+// snapshots were taken use the raft's current active members.
+func (g *Governance) eligibleVoters(proposal *Proposal) []string {
+	var voters []string
+	for _, member := range g.getActiveMembers(proposal.RaftID) {
+		if proposal.Kind == ProposalKindEviction && member.ID == proposal.TargetMemberID {
+			continue
+		}
+		if proposal.EligibleVoters == nil || hasVoter(proposal.EligibleVoters, member.ID) {
+			voters = append(voters, member.ID)
+		}
+	}
+	sort.Strings(voters)
+	return voters
+}
+
+// recountProposals re-evaluates the raft's open proposals after its
+// membership changed, so departed members stop counting towards them.
+// Proposals mirrored from a peer are left to their origin.
+func (g *Governance) recountProposals(raftID string) {
+	g.proposals.mu.Lock()
+	defer g.proposals.mu.Unlock()
+
+	for _, proposal := range g.proposals.proposals {
+		if proposal.RaftID == raftID && proposal.Status == ProposalOpen && g.tallies(proposal) {
+			g.checkProposalOutcome(proposal)
+		}
+	}
+}
+
+// GetActiveRules returns all active rules, keyed by raft ID and then scope
+func (g *Governance) GetActiveRules() map[string]map[string]*Rule {
 	g.rules.mu.RLock()
 	defer g.rules.mu.RUnlock()
 
-	rules := make(map[string]*Rule)
-	for scope, rule := range g.rules.active {
-		rules[scope] = rule
+	rules := make(map[string]map[string]*Rule)
+	for key, rule := range g.rules.active {
+		if rules[key.RaftID] == nil {
+			rules[key.RaftID] = make(map[string]*Rule)
+		}
+		rules[key.RaftID][key.Scope] = rule
 	}
 	return rules
 }
 
+// GetActiveRulesForRaft returns the rules in force in one raft, keyed by
+// scope: its own active rules, then those inherited through its charter
+// that it does not override. Inherited rules keep their raft's ID.
+func (g *Governance) GetActiveRulesForRaft(raftID string) map[string]*Rule {
+	g.rules.mu.RLock()
+	defer g.rules.mu.RUnlock()
+
+	return g.effectiveRulesLocked(raftID, make(map[string]bool))
+}
+
 // GetProposal returns a proposal by ID
 func (g *Governance) GetProposal(proposalID string) (*Proposal, bool) {
 	g.proposals.mu.RLock()
@@ -624,62 +1190,163 @@ func (g *Governance) GetAllProposals() []*Proposal {
 }
 
 // RequestJoin handles a join request from another otter to join a specific raft
-// This otter must already be a member of the target raft to accept the request
-func (g *Governance) RequestJoin(ctx context.Context, targetRaftID string, requesterID string, publicKey []byte) error {
+// This otter must already be a member of the target raft to accept the request.
+// Invited requesters are admitted at once; others are held pending while the
+// raft votes on an admission proposal.
+func (g *Governance) RequestJoin(ctx context.Context, req JoinRequest) (*JoinResponse, error) {
 	// Validate this otter is a member of the target raft
-	g.rafts.mu.RLock()
-	raft, exists := g.rafts.rafts[targetRaftID]
-	g.rafts.mu.RUnlock()
+	raft, err := g.liveRaft(req.RaftID)
+	if errors.Is(err, ErrRaftNotFound) {
+		return nil, fmt.Errorf("not a member of raft %s", req.RaftID)
+	} else if err != nil {
+		return nil, err
+	}
 
-	if !exists {
-		return fmt.Errorf("not a member of raft %s", targetRaftID)
+	// Agree on a protocol the requester and this otter both speak
+	capabilities, err := negotiatePeer(req.Descriptor, req.RequesterID, req.SigningKey)
+	if err != nil {
+		return nil, fmt.Errorf("capability handshake with %s failed: %w", req.RequesterID, err)
+	}
+
+	// The requester must prove it holds the signing key it presents
+	if err := g.verifyJoinChallenge(&req); err != nil {
+		return nil, err
+	}
+
+	raft.mu.RLock()
+	existing, known := raft.Members[req.RequesterID]
+	raft.mu.RUnlock()
+	if known && existing.State == StatePending {
+		return nil, fmt.Errorf("%w: %s in raft %s", ErrJoinPending, req.RequesterID, req.RaftID)
+	}
+
+	// An invite inducts the requester on behalf of the member who issued it
+	inductedBy := g.config.ID
+	state := StatePending
+	if req.Invite != "" {
+		invite, err := g.redeemInvite(raft, &req)
+		if err != nil {
+			return nil, err
+		}
+		inductedBy = invite.InviterID
+		state = StateActive
+	}
+
+	descriptor, err := g.CapabilityDescriptor()
+	if err != nil {
+		return nil, err
 	}
 
 	// Create new member
 	now := time.Now()
 	member := &Member{
-		ID:         requesterID,
-		State:      StateActive,
-		JoinedAt:   now,
-		LastSeenAt: now,
-		PublicKey:  publicKey,
-		InductedBy: g.config.ID,
+		ID:           req.RequesterID,
+		State:        state,
+		JoinedAt:     now,
+		LastSeenAt:   now,
+		PublicKey:    req.PublicKey,
+		SigningKey:   req.SigningKey,
+		InductedBy:   inductedBy,
+		Endpoint:     req.Endpoint,
+		Capabilities: capabilities,
+	}
+	if err := g.signMember(req.RaftID, member); err != nil {
+		return nil, err
 	}
 
 	// Add member to raft
 	raft.mu.Lock()
-	raft.Members[requesterID] = member
+	raft.Members[req.RequesterID] = member
 	raft.mu.Unlock()
 
-	return nil
+	g.recordAudit(AuditMemberJoined, req.RaftID, member.ID, g.config.ID,
+		MemberAudit{MemberID: member.ID, State: member.State, InductedBy: member.InductedBy})
+	if state == StateActive {
+		g.publish(events.MemberJoined, MemberEvent{
+			RaftID: req.RaftID, MemberID: member.ID, State: member.State, InductedBy: member.InductedBy,
+		})
+	}
+
+	if err := g.saveRaft(ctx, raft); err != nil {
+		g.log().WarnContext(ctx, "failed to persist member", "member_id", req.RequesterID, "raft_id", req.RaftID, "error", err)
+	}
+
+	resp := &JoinResponse{
+		MemberID:     g.config.ID,
+		PublicKey:    g.crypto.GetPublicKey(),
+		SigningKey:   g.crypto.GetSigningPublicKey(),
+		Endpoint:     g.config.PeerEndpoint,
+		Descriptor:   descriptor,
+		Capabilities: capabilities,
+		State:        state,
+	}
+	if state == StatePending {
+		proposal, err := g.proposeAdmission(raft, member)
+		if err != nil {
+			return nil, err
+		}
+		resp.ProposalID = proposal.ProposalID
+		return resp, nil
+	}
+
+	g.recountProposals(req.RaftID)
+	return resp, nil
 }
 
 // JoinRaft attempts to join this otter to another otter's raft
-// This handles the full flow: adopt rules, detect conflicts, negotiate if needed
+// This handles the full flow: adopt rules, detect conflicts, negotiate if needed.
+// After a negotiation it returns once the compromise is up for a vote in
+// both rafts; the join completes, or is rolled back, in the background.
 func (g *Governance) JoinRaft(ctx context.Context, targetRaftID string, targetOtterEndpoint string, llmProvider interface{}) error {
+	return g.joinRaft(ctx, targetRaftID, targetOtterEndpoint, "", llmProvider)
+}
+
+// JoinRaftWithInvite joins the raft an invite token was issued for,
+// presenting the token to the otter at targetOtterEndpoint
+func (g *Governance) JoinRaftWithInvite(ctx context.Context, token string, targetOtterEndpoint string, llmProvider interface{}) error {
+	invite, err := ParseInvite(token)
+	if err != nil {
+		return err
+	}
+	return g.joinRaft(ctx, invite.RaftID, targetOtterEndpoint, token, llmProvider)
+}
+
+func (g *Governance) joinRaft(ctx context.Context, targetRaftID, targetOtterEndpoint, invite string, llmProvider interface{}) error {
+	if err := g.writable(); err != nil {
+		return err
+	}
+
 	// Step 1: Get target raft's rules
 	targetRules, err := g.fetchRaftRules(ctx, targetOtterEndpoint, targetRaftID)
 	if err != nil {
 		return fmt.Errorf("failed to fetch target raft rules: %w", err)
 	}
 
-	// Step 2: Detect conflicts with existing rafts
+	// Step 2: Detect conflicts with existing rafts, then contradictions across scopes
 	conflicts := g.detectRuleConflicts(targetRaftID, targetRules)
+	conflicts = append(conflicts, g.detectSemanticConflicts(ctx, targetRaftID, targetRules)...)
 
 	// Step 3: If no conflicts, adopt rules and join
 	if len(conflicts) == 0 {
-		return g.adoptRulesAndJoin(ctx, targetRaftID, targetRules, targetOtterEndpoint)
+		return g.adoptRulesAndJoin(ctx, targetRaftID, targetRules, targetOtterEndpoint, invite)
 	}
 
 	// Step 4: If conflicts exist, initiate LLM negotiation
-	negotiation, err := g.startNegotiation(ctx, targetRaftID, targetOtterEndpoint, conflicts, llmProvider)
+	negotiation, err := g.startNegotiation(ctx, targetRaftID, targetOtterEndpoint, conflicts, targetRules, llmProvider)
 	if err != nil {
 		return fmt.Errorf("negotiation initiation failed: %w", err)
 	}
 
-	// Step 5: If negotiation succeeds, propose amendments to both rafts
+	// Step 5: If negotiation succeeds, the compromise waits for a person to
+	// approve it before it is proposed to both rafts
 	if negotiation.Status == NegotiationResolved {
-		return g.executeDualRaftVote(ctx, negotiation, llmProvider)
+		g.awaitApproval(ctx, negotiation)
+		return fmt.Errorf("%w: negotiation %s", ErrNegotiationAwaitingApproval, negotiation.NegotiationID)
+	}
+
+	// The LLM was unavailable; the negotiation resumes from the task queue
+	if negotiation.Status == NegotiationDeferred {
+		return fmt.Errorf("%w: negotiation %s queued", ErrTaskDeferred, negotiation.NegotiationID)
 	}
 
 	return fmt.Errorf("negotiation failed: rafts could not agree on common rules")
@@ -689,27 +1356,46 @@ func (g *Governance) JoinRaft(ctx context.Context, targetRaftID string, targetOt
 func (g *Governance) detectRuleConflicts(targetRaftID string, targetRules map[string]*Rule) []*RuleConflict {
 	var conflicts []*RuleConflict
 
+	// Where one raft inherits from the other, the child's local rules
+	// override the parent's by charter and do not conflict
+	charters := g.charters()
+	for _, rule := range targetRules {
+		if rule.Scope != CharterScope || (rule.RaftID != "" && rule.RaftID != targetRaftID) {
+			continue
+		}
+		if charter, err := ParseCharter(rule.Body); err == nil {
+			charters[targetRaftID] = charter
+		}
+	}
+
 	g.rafts.mu.RLock()
 	defer g.rafts.mu.RUnlock()
 
 	// Check each existing raft's rules
 	for existingRaftID, existingRaft := range g.rafts.rafts {
-		if existingRaftID == targetRaftID {
-			continue // Skip self
+		if existingRaftID == targetRaftID || existingRaft.isArchived() {
+			continue // Skip self and rafts whose rules are no longer in force
 		}
 
 		existingRaft.mu.RLock()
 		for _, targetRule := range targetRules {
 			for _, existingRule := range existingRaft.Rules {
-				// Rules conflict if they have the same scope but different bodies
-				if targetRule.Scope == existingRule.Scope && targetRule.Body != existingRule.Body {
+				if settledByCharter(charters, existingRaftID, targetRaftID, targetRule) ||
+					settledByCharter(charters, targetRaftID, existingRaftID, existingRule) {
+					continue
+				}
+				// Rules conflict if their scopes overlap but their bodies differ.
+				// Once both are in force the more specific one would silently
+				// win where they overlap, so that scope is negotiated.
+				if ScopesOverlap(targetRule.Scope, existingRule.Scope) && targetRule.Body != existingRule.Body {
 					conflict := &RuleConflict{
 						ConflictID:    generateID(fmt.Sprintf("%s-%s", targetRule.RuleID, existingRule.RuleID)),
 						Raft1ID:       existingRaftID,
 						Raft2ID:       targetRaftID,
 						Rule1:         existingRule,
 						Rule2:         targetRule,
-						ConflictScope: targetRule.Scope,
+						ConflictScope: narrowerScope(existingRule.Scope, targetRule.Scope),
+						Confidence:    1,
 						DetectedAt:    time.Now(),
 					}
 					conflicts = append(conflicts, conflict)
@@ -723,7 +1409,7 @@ func (g *Governance) detectRuleConflicts(targetRaftID string, targetRules map[st
 }
 
 // adoptRulesAndJoin adopts all target raft rules and joins the raft
-func (g *Governance) adoptRulesAndJoin(ctx context.Context, targetRaftID string, targetRules map[string]*Rule, endpoint string) error {
+func (g *Governance) adoptRulesAndJoin(ctx context.Context, targetRaftID string, targetRules map[string]*Rule, endpoint, invite string) error {
 	// Create raft info for the new membership
 	g.rafts.mu.Lock()
 
@@ -733,37 +1419,74 @@ func (g *Governance) adoptRulesAndJoin(ctx context.Context, targetRaftID string,
 		Rules:     targetRules,
 		CreatedAt: time.Now(),
 	}
+	g.applyMetaRules(raft)
 
 	g.rafts.rafts[targetRaftID] = raft
 	g.rafts.mu.Unlock()
 
+	// Until the target raft inducts us, the placeholder is dropped on failure
+	abandon := func() {
+		g.rafts.mu.Lock()
+		delete(g.rafts.rafts, targetRaftID)
+		g.rafts.mu.Unlock()
+	}
+
 	// Persist the new raft membership
 	if err := g.saveRaft(ctx, raft); err != nil {
-		fmt.Printf("Warning: Failed to persist raft %s: %v\n", targetRaftID, err)
+		g.log().WarnContext(ctx, "failed to persist raft", "raft_id", targetRaftID, "error", err)
 	}
 
 	// Request membership from target raft via API.
 	endpoint = strings.TrimSpace(endpoint)
 	if endpoint == "" {
+		abandon()
 		return fmt.Errorf("target endpoint is required for join request")
 	}
 	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
 		endpoint = "http://" + endpoint
 	}
 
-	joinReq := map[string]string{
-		"raft_id":      targetRaftID,
-		"requester_id": g.config.ID,
-		"public_key":   hex.EncodeToString(g.crypto.GetPublicKey()),
+	descriptor, err := g.CapabilityDescriptor()
+	if err != nil {
+		abandon()
+		return err
+	}
+
+	// Prove possession of our signing key before asking to be inducted
+	challenge, err := g.fetchJoinChallenge(ctx, endpoint, targetRaftID)
+	if err != nil {
+		abandon()
+		return err
+	}
+	challengeSig, err := g.crypto.SignJoinChallenge(challenge)
+	if err != nil {
+		abandon()
+		return fmt.Errorf("failed to sign join challenge: %w", err)
+	}
+
+	joinReq := map[string]interface{}{
+		"raft_id":             targetRaftID,
+		"requester_id":        g.config.ID,
+		"public_key":          hex.EncodeToString(g.crypto.GetPublicKey()),
+		"signing_key":         hex.EncodeToString(g.crypto.GetSigningPublicKey()),
+		"endpoint":            g.config.PeerEndpoint,
+		"capabilities":        descriptor,
+		"nonce":               challenge.Nonce,
+		"challenge_signature": hex.EncodeToString(challengeSig),
+	}
+	if invite != "" {
+		joinReq["invite"] = invite
 	}
 	body, err := json.Marshal(joinReq)
 	if err != nil {
+		abandon()
 		return fmt.Errorf("failed to marshal join request: %w", err)
 	}
 
 	url := strings.TrimRight(endpoint, "/") + "/api/v1/governance/join"
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
+		abandon()
 		return fmt.Errorf("failed to create join request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
@@ -771,44 +1494,136 @@ func (g *Governance) adoptRulesAndJoin(ctx context.Context, targetRaftID string,
 	client := &http.Client{Timeout: GovernanceHTTPTimeout}
 	resp, err := client.Do(req)
 	if err != nil {
+		abandon()
 		return fmt.Errorf("failed to send join request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
+		abandon()
 		return fmt.Errorf("failed to read join response: %w", err)
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		// Roll back local membership when remote induction fails.
-		g.rafts.mu.Lock()
-		delete(g.rafts.rafts, targetRaftID)
-		g.rafts.mu.Unlock()
+		abandon()
 		return fmt.Errorf("join request rejected (%d): %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
 	}
 
-	// Reflect self as active local member in this raft after successful induction.
-	raft.mu.Lock()
-	raft.Members[g.config.ID] = &Member{
+	// Check the inducting otter's capabilities before trusting its messages.
+	// The peer has already inducted us, so an incompatible peer leaves a
+	// membership it must revoke; that is better than misreading its messages.
+	inductor, err := parseJoinResponse(respBody, endpoint)
+	if err != nil {
+		abandon()
+		return fmt.Errorf("capability handshake with raft %s failed: %w", targetRaftID, err)
+	}
+
+	// Only adopt rules signed by the raft's members, whose keys the
+	// inducting otter vouches for
+	if err := g.verifyFetchedRules(ctx, endpoint, targetRaftID, targetRules, inductor); err != nil {
+		abandon()
+		return err
+	}
+
+	// Reflect self as a local member in this raft after successful induction;
+	// pending until the raft votes us in if it holds an admission vote
+	var joined struct {
+		State MembershipState `json:"state"`
+	}
+	json.Unmarshal(respBody, &joined)
+	selfState := StateActive
+	if joined.State == StatePending {
+		selfState = StatePending
+	}
+	self := &Member{
 		ID:         g.config.ID,
-		State:      StateActive,
+		State:      selfState,
 		JoinedAt:   time.Now(),
 		LastSeenAt: time.Now(),
 		PublicKey:  g.crypto.GetPublicKey(),
+		SigningKey: g.crypto.GetSigningPublicKey(),
 		InductedBy: targetRaftID,
 	}
+	// The inducting otter holds the admission vote, and only it may
+	// announce that this otter was admitted
+	if inductor != nil {
+		self.InductedBy = inductor.ID
+	}
+	if err := g.signMember(targetRaftID, self); err != nil {
+		return err
+	}
+
+	// Record the inducting otter so rule drift can be checked against it
+	if inductor != nil {
+		if err := g.signMember(targetRaftID, inductor); err != nil {
+			return err
+		}
+	}
+
+	raft.mu.Lock()
+	raft.Members[g.config.ID] = self
+	if inductor != nil && inductor.ID != g.config.ID {
+		raft.Members[inductor.ID] = inductor
+	}
 	raft.mu.Unlock()
 
+	g.recordAudit(AuditMemberJoined, targetRaftID, self.ID, targetRaftID,
+		MemberAudit{MemberID: self.ID, State: self.State, InductedBy: self.InductedBy})
+	if inductor != nil && inductor.ID != g.config.ID {
+		g.recordAudit(AuditMemberJoined, targetRaftID, inductor.ID, targetRaftID,
+			MemberAudit{MemberID: inductor.ID, State: inductor.State, InductedBy: inductor.InductedBy})
+	}
+
 	if err := g.saveRaft(ctx, raft); err != nil {
-		fmt.Printf("Warning: Failed to persist inducted raft membership %s: %v\n", targetRaftID, err)
+		g.log().WarnContext(ctx, "failed to persist inducted raft membership", "raft_id", targetRaftID, "error", err)
 	}
 
 	return nil
 }
 
+// parseJoinResponse builds a member record for the inducting otter and
+// negotiates capabilities with it. Older peers reply without identifying
+// themselves, in which case nil is returned.
+func parseJoinResponse(body []byte, endpoint string) (*Member, error) {
+	var resp struct {
+		MemberID     string                `json:"member_id"`
+		PublicKey    string                `json:"public_key"`
+		SigningKey   string                `json:"signing_key"`
+		Endpoint     string                `json:"endpoint"`
+		Capabilities *CapabilityDescriptor `json:"capabilities"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil || resp.MemberID == "" {
+		return nil, nil
+	}
+
+	now := time.Now()
+	member := &Member{
+		ID:         resp.MemberID,
+		State:      StateActive,
+		JoinedAt:   now,
+		LastSeenAt: now,
+		Endpoint:   endpoint,
+	}
+	if resp.Endpoint != "" {
+		member.Endpoint = resp.Endpoint
+	}
+	member.PublicKey, _ = hex.DecodeString(resp.PublicKey)
+	if key, err := hex.DecodeString(resp.SigningKey); err == nil && len(key) == ed25519.PublicKeySize {
+		member.SigningKey = key
+	}
+
+	capabilities, err := negotiatePeer(resp.Capabilities, member.ID, member.SigningKey)
+	if err != nil {
+		return nil, err
+	}
+	member.Capabilities = capabilities
+	return member, nil
+}
+
 // startNegotiation initiates LLM-based negotiation between conflicting rafts
-func (g *Governance) startNegotiation(ctx context.Context, targetRaftID string, targetEndpoint string, conflicts []*RuleConflict, llmProvider interface{}) (*Negotiation, error) {
+func (g *Governance) startNegotiation(ctx context.Context, targetRaftID string, targetEndpoint string, conflicts []*RuleConflict, targetRules map[string]*Rule, llmProvider interface{}) (*Negotiation, error) {
 	if len(conflicts) == 0 {
 		return nil, fmt.Errorf("no conflicts to negotiate")
 	}
@@ -821,6 +1636,7 @@ func (g *Governance) startNegotiation(ctx context.Context, targetRaftID string,
 		Raft2ID:        targetRaftID,
 		TargetEndpoint: strings.TrimSpace(targetEndpoint),
 		Conflicts:      conflicts,
+		Raft2Rules:     sortedRules(targetRules),
 		Status:         NegotiationInProgress,
 		StartedAt:      time.Now(),
 		LLMTranscript:  make([]string, 0),
@@ -832,6 +1648,14 @@ func (g *Governance) startNegotiation(ctx context.Context, targetRaftID string,
 
 	// Perform LLM negotiation
 	proposedRule, err := g.negotiateWithLLM(ctx, negotiation, llmProvider)
+	if errors.Is(err, ErrLLMUnavailable) {
+		negotiation.Status = NegotiationDeferred
+		if _, qerr := g.enqueueLLMTask(ctx, TaskNegotiation, negotiationID, negotiation, err); qerr != nil {
+			negotiation.Status = NegotiationFailed
+			return negotiation, fmt.Errorf("failed to queue negotiation: %w", qerr)
+		}
+		return negotiation, nil
+	}
 	if err != nil {
 		negotiation.Status = NegotiationFailed
 		return negotiation, err
@@ -845,242 +1669,67 @@ func (g *Governance) startNegotiation(ctx context.Context, targetRaftID string,
 	return negotiation, nil
 }
 
-// negotiateWithLLM uses LLM to negotiate a compromise between conflicting rules
-func (g *Governance) negotiateWithLLM(ctx context.Context, negotiation *Negotiation, llmProvider interface{}) (*Rule, error) {
-	// Build negotiation prompt
-	prompt := g.buildNegotiationPrompt(negotiation)
-
-	// Record the prompt in the transcript
-	negotiation.LLMTranscript = append(negotiation.LLMTranscript, prompt)
-
-	scope := negotiation.Conflicts[0].ConflictScope
-	body := ""
-
-	if provider, ok := llmProvider.(interface {
-		Complete(context.Context, *llm.CompletionRequest) (*llm.CompletionResponse, error)
-	}); ok {
-		resp, err := provider.Complete(ctx, &llm.CompletionRequest{
-			Prompt:      fmt.Sprintf("%s\n\nReturn ONLY JSON in this shape: {\"scope\":\"...\",\"body\":\"...\"}", prompt),
-			MaxTokens:   400,
-			Temperature: 0.2,
-		})
-		if err == nil && resp != nil {
-			negotiation.LLMTranscript = append(negotiation.LLMTranscript, resp.Text)
-			if parsedScope, parsedBody := parseNegotiatedRuleResponse(resp.Text, scope); parsedBody != "" {
-				scope = parsedScope
-				body = parsedBody
-			}
-		}
-	}
-
-	if body == "" {
-		body = synthesizeCompromiseRuleBody(negotiation.Conflicts)
-	}
-
-	proposedBy := g.pickActiveMemberID(negotiation.Raft1ID)
-	if proposedBy == "" {
-		proposedBy = g.config.ID
-	}
-
-	compromiseRule := &Rule{
-		RuleID:     generateID(fmt.Sprintf("compromise-%s-%s", negotiation.NegotiationID, negotiation.Raft1ID)),
-		RaftID:     negotiation.Raft1ID,
-		Scope:      scope,
-		Version:    maxConflictVersion(negotiation.Conflicts) + 1,
-		Timestamp:  time.Now(),
-		Body:       body,
-		ProposedBy: proposedBy,
-	}
-
-	return compromiseRule, nil
-}
-
 // buildNegotiationPrompt creates a prompt for LLM negotiation
 func (g *Governance) buildNegotiationPrompt(negotiation *Negotiation) string {
-	prompt := fmt.Sprintf(`You are mediating a governance rule conflict between two otter rafts.
-
-Raft 1 ID: %s
-Raft 2 ID: %s
-
-Conflicts:
-`, negotiation.Raft1ID, negotiation.Raft2ID)
-
-	for i, conflict := range negotiation.Conflicts {
-		prompt += fmt.Sprintf(`
-Conflict %d - Scope: %s
-Raft 1 Rule: %s
-Raft 2 Rule: %s
-`, i+1, conflict.ConflictScope, conflict.Rule1.Body, conflict.Rule2.Body)
-	}
-
-	prompt += `
-Please propose a compromise rule that respects both rafts' interests and can be adopted by both.
-The proposal should be clear, actionable, and acceptable to all members of both rafts.
-`
-
-	return prompt
+	return g.renderPrompt(prompts.Negotiation, struct {
+		Raft1ID, Raft2ID       string
+		Conflicts              []*RuleConflict
+		Raft1Rules, Raft2Rules []*Rule
+	}{
+		Raft1ID:    negotiation.Raft1ID,
+		Raft2ID:    negotiation.Raft2ID,
+		Conflicts:  negotiation.Conflicts,
+		Raft1Rules: g.negotiationRules(negotiation, negotiation.Raft1ID),
+		Raft2Rules: g.negotiationRules(negotiation, negotiation.Raft2ID),
+	})
 }
 
-// executeDualRaftVote proposes the negotiated rule to both rafts and waits for votes
+// executeDualRaftVote proposes the negotiated rule to both rafts and waits
+// for their votes in the background: the join is finalized once both adopt
+// it, and rolled back if either rejects it or the vote times out
 func (g *Governance) executeDualRaftVote(ctx context.Context, negotiation *Negotiation, _ interface{}) error {
-	proposer1 := g.pickActiveMemberID(negotiation.Raft1ID)
-	proposer2 := g.pickActiveMemberID(negotiation.Raft2ID)
-	if proposer1 == "" || proposer2 == "" {
-		return fmt.Errorf("cannot execute dual-raft vote: both rafts must have at least one active member")
-	}
-
-	// Create independent rule instances so each raft owns its own rule ID.
-	rule1 := *negotiation.ProposedRule
-	rule1.RaftID = negotiation.Raft1ID
-	rule1.ProposedBy = proposer1
-	rule1.RuleID = generateID(fmt.Sprintf("%s|%s|%s", negotiation.Raft1ID, rule1.Scope, rule1.Body))
-
-	rule2 := *negotiation.ProposedRule
-	rule2.RaftID = negotiation.Raft2ID
-	rule2.ProposedBy = proposer2
-	rule2.RuleID = generateID(fmt.Sprintf("%s|%s|%s", negotiation.Raft2ID, rule2.Scope, rule2.Body))
-
-	proposal1, err := g.ProposeRule(ctx, negotiation.Raft1ID, &rule1)
-	if err != nil {
-		return fmt.Errorf("failed to propose to raft 1: %w", err)
-	}
-
-	proposal2, err := g.ProposeRule(ctx, negotiation.Raft2ID, &rule2)
-	if err != nil {
-		return fmt.Errorf("failed to propose to raft 2: %w", err)
-	}
-
-	negotiation.Raft1Proposal = proposal1
-	negotiation.Raft2Proposal = proposal2
-
-	// Cast initial YES votes from the local active members we selected as proposers.
-	_ = g.Vote(ctx, proposal1.ProposalID, proposer1, VoteYes)
-	_ = g.Vote(ctx, proposal2.ProposalID, proposer2, VoteYes)
-
-	ticker := time.NewTicker(NegotiationPollInterval)
-	defer ticker.Stop()
-	deadline := time.NewTimer(NegotiationVoteTimeout)
-	defer deadline.Stop()
-
-	for {
-		latest1, ok1 := g.GetProposal(proposal1.ProposalID)
-		latest2, ok2 := g.GetProposal(proposal2.ProposalID)
-		if !ok1 || !ok2 {
-			return fmt.Errorf("negotiation proposals missing while awaiting outcome")
-		}
-
-		if latest1.Status == ProposalClosed && latest2.Status == ProposalClosed {
-			if latest1.Result == ResultAdopted && latest2.Result == ResultAdopted {
-				return nil
-			}
-			return fmt.Errorf("negotiation vote failed: raft1=%s raft2=%s", latest1.Result, latest2.Result)
-		}
-
-		select {
-		case <-ctx.Done():
-			return fmt.Errorf("negotiation vote canceled: %w", ctx.Err())
-		case <-deadline.C:
-			return fmt.Errorf("negotiation vote timed out waiting for both rafts to close proposals")
-		case <-ticker.C:
-		}
+	if err := g.openDualRaftVote(ctx, negotiation); err != nil {
+		return err
 	}
+	go g.awaitDualRaftVote(negotiation)
+	return nil
 }
 
-// fetchRaftRules fetches all rules from a remote raft
-// In a real implementation, this would make an HTTP/gRPC call
+// fetchRaftRules fetches the adopted rules of a raft from one of its otters.
+// The caller is not yet a member, so the rules are verified once the join
+// handshake has authenticated the inducting otter.
 func (g *Governance) fetchRaftRules(ctx context.Context, endpoint string, raftID string) (map[string]*Rule, error) {
-	endpoint = strings.TrimSpace(endpoint)
-	if endpoint == "" {
-		return nil, fmt.Errorf("target endpoint is required")
-	}
-	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
-		endpoint = "http://" + endpoint
-	}
-
-	url := strings.TrimRight(endpoint, "/") + "/api/v1/governance/rules"
-	client := &http.Client{Timeout: GovernanceHTTPTimeout}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed creating request: %w", err)
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed fetching raft rules from %s: %w", url, err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed reading rules response: %w", err)
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("rules endpoint returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	var listed []*Rule
+	if err := getPeerJSON(ctx, endpoint, "/api/v1/governance/rafts/"+url.PathEscape(raftID)+"/rules", nil, &listed); err != nil {
+		return nil, fmt.Errorf("failed fetching raft rules: %w", err)
 	}
 
 	rules := make(map[string]*Rule)
-
-	var byScope map[string]*Rule
-	if err := json.Unmarshal(body, &byScope); err == nil && len(byScope) > 0 {
-		now := time.Now()
-		for scope, rule := range byScope {
-			if rule == nil {
-				continue
-			}
-			if rule.Scope == "" {
-				rule.Scope = scope
-			}
-			if rule.RaftID == "" {
-				rule.RaftID = raftID
-			}
-			if rule.Timestamp.IsZero() {
-				rule.Timestamp = now
-			}
-			if rule.Version == 0 {
-				rule.Version = 1
-			}
-			if rule.RuleID == "" {
-				rule.RuleID = generateID(fmt.Sprintf("%s|%s|%s", raftID, rule.Scope, rule.Body))
-			}
-			rules[rule.RuleID] = rule
+	now := time.Now()
+	for _, rule := range listed {
+		// Rules the raft inherits through its charter stay its parent's
+		if rule == nil || (rule.RaftID != "" && rule.RaftID != raftID) {
+			continue
 		}
-		return rules, nil
-	}
-
-	var asList []*Rule
-	if err := json.Unmarshal(body, &asList); err == nil && len(asList) > 0 {
-		now := time.Now()
-		for _, rule := range asList {
-			if rule == nil {
-				continue
-			}
-			if rule.RaftID == "" {
-				rule.RaftID = raftID
-			}
-			if rule.Timestamp.IsZero() {
-				rule.Timestamp = now
-			}
-			if rule.Version == 0 {
-				rule.Version = 1
-			}
-			if rule.RuleID == "" {
-				rule.RuleID = generateID(fmt.Sprintf("%s|%s|%s", raftID, rule.Scope, rule.Body))
-			}
-			rules[rule.RuleID] = rule
+		if rule.RaftID == "" {
+			rule.RaftID = raftID
+		}
+		if rule.Timestamp.IsZero() {
+			rule.Timestamp = now
 		}
-		return rules, nil
+		if rule.Version == 0 {
+			rule.Version = 1
+		}
+		if rule.RuleID == "" {
+			rule.RuleID = generateID(fmt.Sprintf("%s|%s|%s", raftID, rule.Scope, rule.Body))
+		}
+		rules[rule.RuleID] = rule
 	}
-
-	return nil, fmt.Errorf("unable to parse rules response from %s", url)
+	return rules, nil
 }
 
 func parseNegotiatedRuleResponse(raw string, defaultScope string) (string, string) {
-	clean := strings.TrimSpace(raw)
-	clean = strings.TrimPrefix(clean, "```json")
-	clean = strings.TrimPrefix(clean, "```")
-	clean = strings.TrimSuffix(clean, "```")
-	clean = strings.TrimSpace(clean)
+	clean := trimCodeFence(raw)
 
 	var parsed struct {
 		Scope string `json:"scope"`
@@ -1175,7 +1824,7 @@ func (g *Governance) GetRaftMembers(raftID string) ([]*Member, error) {
 	g.rafts.mu.RUnlock()
 
 	if !exists {
-		return nil, fmt.Errorf("raft not found: %s", raftID)
+		return nil, fmt.Errorf("%w: %s", ErrRaftNotFound, raftID)
 	}
 
 	raft.mu.RLock()
@@ -1193,10 +1842,49 @@ func (g *Governance) GetCrypto() *CryptoSystem {
 	return g.crypto
 }
 
-// Shutdown gracefully shuts down the governance system
+// Shutdown stops the background monitors, waits for them to finish and
+// persists every raft, so liveness heard from members since the last write
+// survives a restart. Calling it again only persists again.
 func (g *Governance) Shutdown(ctx context.Context) error {
-	close(g.shutdownCh)
-	return nil
+	g.shutdownOnce.Do(func() { close(g.shutdownCh) })
+
+	done := make(chan struct{})
+	go func() {
+		g.background.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if g.getDB() == nil {
+		return nil
+	}
+	g.rafts.mu.RLock()
+	rafts := make([]*RaftInfo, 0, len(g.rafts.rafts))
+	for _, raft := range g.rafts.rafts {
+		rafts = append(rafts, raft)
+	}
+	g.rafts.mu.RUnlock()
+
+	var errs []error
+	for _, raft := range rafts {
+		if err := g.saveRaft(ctx, raft); err != nil {
+			errs = append(errs, fmt.Errorf("raft %s: %w", raft.RaftID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// goBackground runs a monitor or worker that Shutdown waits for
+func (g *Governance) goBackground(run func()) {
+	g.background.Add(1)
+	go func() {
+		defer g.background.Done()
+		run()
+	}()
 }
 
 // generateID generates a deterministic ID for an object
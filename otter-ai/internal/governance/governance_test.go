@@ -2,9 +2,14 @@ package governance
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -19,7 +24,7 @@ type mockVectorDB struct{}
 func (m *mockVectorDB) Store(_ context.Context, _ string, _ string, _ []float32, _ map[string]interface{}) error {
 	return nil
 }
-func (m *mockVectorDB) Search(_ context.Context, _ string, _ []float32, _ int) ([]vectordb.SearchResult, error) {
+func (m *mockVectorDB) Search(_ context.Context, _ string, _ []float32, _ int, _ vectordb.Filter) ([]vectordb.SearchResult, error) {
 	return nil, nil
 }
 func (m *mockVectorDB) Get(_ context.Context, _ string, _ string) (*vectordb.Record, error) {
@@ -311,7 +316,7 @@ func newTestGovernance(id string) *Governance {
 		},
 		rules: &RuleRegistry{
 			rules:  make(map[string]*Rule),
-			active: make(map[string]*Rule),
+			active: make(map[ruleKey]*Rule),
 		},
 		proposals: &ProposalRegistry{
 			proposals: make(map[string]*Proposal),
@@ -326,7 +331,7 @@ func newTestGovernance(id string) *Governance {
 	now := time.Now()
 	raft := &RaftInfo{
 		RaftID:    id,
-		Members:   map[string]*Member{id: {ID: id, State: StateActive, JoinedAt: now, LastSeenAt: now}},
+		Members:   map[string]*Member{id: {ID: id, State: StateActive, JoinedAt: now, LastSeenAt: now, SigningKey: crypto.GetSigningPublicKey()}},
 		Rules:     make(map[string]*Rule),
 		CreatedAt: now,
 	}
@@ -369,6 +374,46 @@ func TestShutdown(t *testing.T) {
 	}
 }
 
+func TestShutdown_PersistsLiveness(t *testing.T) {
+	dir := t.TempDir()
+	db, err := vectordb.NewSQLiteVectorDB(filepath.Join(dir, "otter.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	g, err := New(RaftConfig{ID: "otter-1", DataDir: dir}, memory.New(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	joinTestRaft(t, g)
+	member := g.rafts.rafts["raft-1"].Members["otter-1"]
+	if err := g.signMember("raft-1", member); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.saveRaft(context.Background(), g.rafts.rafts["raft-1"]); err != nil {
+		t.Fatal(err)
+	}
+	// Heartbeats only write a member's LastSeenAt once it is stale
+	seen := member.LastSeenAt.Add(time.Minute).Truncate(time.Second)
+	member.LastSeenAt = seen
+	if err := g.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if err := g.Shutdown(context.Background()); err != nil {
+		t.Errorf("second Shutdown: %v", err)
+	}
+
+	reloaded, err := New(RaftConfig{ID: "otter-1", DataDir: dir}, memory.New(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reloaded.Shutdown(context.Background())
+	if got := reloaded.rafts.rafts["raft-1"].Members["otter-1"].LastSeenAt; !got.Equal(seen) {
+		t.Errorf("reloaded LastSeenAt = %v; want %v", got, seen)
+	}
+}
+
 // --- GetRaftMembers ---
 
 func TestGetRaftMembers_Found(t *testing.T) {
@@ -425,16 +470,39 @@ func TestGetActiveRules_Empty(t *testing.T) {
 
 func TestGetActiveRules_WithRules(t *testing.T) {
 	g := newTestGovernance("otter-1")
-	rule := &Rule{RuleID: "r1", Scope: "safety", Body: "be kind"}
-	g.rules.active["safety"] = rule
+	rule := &Rule{RuleID: "r1", RaftID: "otter-1", Scope: "safety", Body: "be kind"}
+	g.rules.active[activeKey(rule)] = rule
 	g.rules.rules["r1"] = rule
 
 	rules := g.GetActiveRules()
 	if len(rules) != 1 {
-		t.Errorf("expected 1 rule, got %d", len(rules))
+		t.Errorf("expected 1 raft, got %d", len(rules))
+	}
+	if rules["otter-1"]["safety"].Body != "be kind" {
+		t.Errorf("rule body = %q", rules["otter-1"]["safety"].Body)
+	}
+}
+
+func TestGetActiveRules_SameScopeInDifferentRafts(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	g.rafts.rafts["raft-2"] = &RaftInfo{RaftID: "raft-2", Members: make(map[string]*Member), Rules: make(map[string]*Rule)}
+
+	own := &Rule{RuleID: "r1", RaftID: "otter-1", Scope: "safety", Body: "be kind"}
+	other := &Rule{RuleID: "r2", RaftID: "raft-2", Scope: "safety", Body: "be careful"}
+	g.activateRule(own)
+	g.activateRule(other)
+
+	rules := g.GetActiveRules()
+	if rules["otter-1"]["safety"] != own || rules["raft-2"]["safety"] != other {
+		t.Errorf("rules of the same scope in different rafts should not shadow each other: %+v", rules)
+	}
+
+	forRaft := g.GetActiveRulesForRaft("raft-2")
+	if len(forRaft) != 1 || forRaft["safety"] != other {
+		t.Errorf("GetActiveRulesForRaft = %+v", forRaft)
 	}
-	if rules["safety"].Body != "be kind" {
-		t.Errorf("rule body = %q", rules["safety"].Body)
+	if len(g.GetActiveRulesForRaft("unknown")) != 0 {
+		t.Error("unknown raft should have no rules")
 	}
 }
 
@@ -547,7 +615,7 @@ func TestVote_Success(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	err = g.Vote(context.Background(), proposal.ProposalID, "otter-1", VoteYes)
+	err = g.CastVote(context.Background(), proposal.ProposalID, VoteYes)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -564,7 +632,7 @@ func TestVote_Success(t *testing.T) {
 
 func TestVote_ProposalNotFound(t *testing.T) {
 	g := newTestGovernance("otter-1")
-	err := g.Vote(context.Background(), "nonexistent", "otter-1", VoteYes)
+	err := g.CastVote(context.Background(), "nonexistent", VoteYes)
 	if err == nil {
 		t.Error("expected error")
 	}
@@ -575,9 +643,9 @@ func TestVote_ProposalClosed(t *testing.T) {
 	rule := &Rule{Scope: "safety", Body: "x", ProposedBy: "otter-1"}
 	proposal, _ := g.ProposeRule(context.Background(), "otter-1", rule)
 	// Vote to close it
-	g.Vote(context.Background(), proposal.ProposalID, "otter-1", VoteYes)
+	g.CastVote(context.Background(), proposal.ProposalID, VoteYes)
 	// Try voting again
-	err := g.Vote(context.Background(), proposal.ProposalID, "otter-1", VoteYes)
+	err := g.CastVote(context.Background(), proposal.ProposalID, VoteYes)
 	if err == nil {
 		t.Error("expected error for closed proposal")
 	}
@@ -587,12 +655,93 @@ func TestVote_VoterNotActive(t *testing.T) {
 	g := newTestGovernance("otter-1")
 	rule := &Rule{Scope: "safety", Body: "x", ProposedBy: "otter-1"}
 	proposal, _ := g.ProposeRule(context.Background(), "otter-1", rule)
-	err := g.Vote(context.Background(), proposal.ProposalID, "otter-99", VoteYes)
+	err := g.Vote(context.Background(), SignedVote{ProposalID: proposal.ProposalID, VoterID: "otter-99", Vote: VoteYes})
 	if err == nil {
 		t.Error("expected error for non-member voter")
 	}
 }
 
+// --- Eligible voter snapshot ---
+
+func TestVote_LateJoinerDoesNotMoveQuorum(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	peers := addPeers(g, "otter-2")
+	ctx := context.Background()
+
+	proposal, err := g.ProposeRule(ctx, "otter-1", &Rule{Scope: "safety", Body: "be kind", ProposedBy: "otter-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(proposal.EligibleVoters, ","); got != "otter-1,otter-2" {
+		t.Fatalf("EligibleVoters = %v, want otter-1 and otter-2", proposal.EligibleVoters)
+	}
+
+	// otter-3 joins while the vote is open
+	for id, peer := range addPeers(g, "otter-3") {
+		peers[id] = peer
+	}
+	if err := peerVote(t, g, peers["otter-3"], proposal.ProposalID, VoteYes); err == nil {
+		t.Error("a member who joined after the proposal opened should not be able to vote")
+	}
+
+	// The two snapshotted voters still decide it unanimously
+	if err := g.CastVote(ctx, proposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	if err := peerVote(t, g, peers["otter-2"], proposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	if proposal.Result != ResultAdopted {
+		t.Errorf("result = %q, want adopted by the snapshotted voters", proposal.Result)
+	}
+}
+
+func TestCheckExpiredMembers_RecountsOpenProposals(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	peers := addPeers(g, "otter-2", "otter-3")
+	raft := g.rafts.rafts["otter-1"]
+	raft.Members["otter-2"].LastSeenAt = time.Now()
+	raft.Members["otter-3"].LastSeenAt = time.Now().Add(-(MemberExpirationDays + 1) * 24 * time.Hour)
+	ctx := context.Background()
+
+	proposal, err := g.ProposeRule(ctx, "otter-1", &Rule{Scope: "safety", Body: "be kind", ProposedBy: "otter-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CastVote(ctx, proposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	if err := peerVote(t, g, peers["otter-2"], proposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	// Quorum of three voters is ceil(3*67/100) = 3
+	if proposal.Status != ProposalOpen {
+		t.Fatalf("two of three votes should not decide, status = %s", proposal.Status)
+	}
+
+	// otter-3 expires, leaving two voters who both voted YES
+	g.checkExpiredMembers()
+	if proposal.Result != ResultAdopted {
+		t.Errorf("result = %q, want adopted once the expired member stopped counting", proposal.Result)
+	}
+	if len(proposal.EligibleVoters) != 3 {
+		t.Errorf("EligibleVoters = %v, want the snapshot kept as taken", proposal.EligibleVoters)
+	}
+}
+
+func TestProposeEviction_SnapshotExcludesTarget(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	addPeers(g, "otter-2", "otter-3")
+
+	proposal, err := g.ProposeEviction(context.Background(), "otter-1", "otter-3", "otter-1", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(proposal.EligibleVoters, ","); got != "otter-1,otter-2" {
+		t.Errorf("EligibleVoters = %v, want otter-1 and otter-2", proposal.EligibleVoters)
+	}
+}
+
 // --- checkProposalOutcome: solo raft ---
 
 func TestCheckProposalOutcome_SoloYes(t *testing.T) {
@@ -772,7 +921,7 @@ func TestActivateRule(t *testing.T) {
 	rule := &Rule{RuleID: "r1", RaftID: "otter-1", Scope: "safety", Body: "be kind"}
 	g.activateRule(rule)
 
-	if g.rules.active["safety"] != rule {
+	if g.rules.active[activeKey(rule)] != rule {
 		t.Error("rule not activated")
 	}
 	if g.rules.rules["r1"] != rule {
@@ -793,9 +942,9 @@ func TestActivateRule_Override(t *testing.T) {
 	g.activateRule(override)
 
 	// Base rule should be deactivated from active map
-	if _, exists := g.rules.active["safety"]; exists {
+	if _, exists := g.rules.active[activeKey(override)]; exists {
 		// The override has the same scope; the active map should have the override
-		active := g.rules.active["safety"]
+		active := g.rules.active[activeKey(override)]
 		if active != nil && active.RuleID == "base-1" {
 			t.Error("base rule should have been deactivated")
 		}
@@ -852,7 +1001,7 @@ func TestDetectRuleConflicts_SameBody(t *testing.T) {
 
 func TestRequestJoin_Success(t *testing.T) {
 	g := newTestGovernance("otter-1")
-	err := g.RequestJoin(context.Background(), "otter-1", "otter-2", []byte("pubkey"))
+	_, err := g.RequestJoin(context.Background(), answeredJoinRequest(t, g, newTestGovernance("otter-2"), "otter-1"))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -863,7 +1012,7 @@ func TestRequestJoin_Success(t *testing.T) {
 
 func TestRequestJoin_RaftNotFound(t *testing.T) {
 	g := newTestGovernance("otter-1")
-	err := g.RequestJoin(context.Background(), "nonexistent", "otter-2", []byte("pubkey"))
+	_, err := g.RequestJoin(context.Background(), JoinRequest{RaftID: "nonexistent", RequesterID: "otter-2", PublicKey: []byte("pubkey")})
 	if err == nil {
 		t.Error("expected error")
 	}
@@ -991,25 +1140,6 @@ func TestNew(t *testing.T) {
 
 // --- fetchRaftRules ---
 
-func TestFetchRaftRules_MapFormat(t *testing.T) {
-	rules := map[string]*Rule{
-		"safety": {Scope: "safety", Body: "be kind", RuleID: "r1", RaftID: "raft-1", Version: 1},
-	}
-	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		json.NewEncoder(w).Encode(rules)
-	}))
-	defer srv.Close()
-
-	g := newTestGovernance("otter-1")
-	fetched, err := g.fetchRaftRules(context.Background(), srv.URL, "raft-1")
-	if err != nil {
-		t.Fatal(err)
-	}
-	if len(fetched) == 0 {
-		t.Error("expected at least one rule")
-	}
-}
-
 func TestFetchRaftRules_ListFormat(t *testing.T) {
 	rules := []*Rule{
 		{Scope: "safety", Body: "be kind", RuleID: "r1", RaftID: "raft-1", Version: 1},
@@ -1153,7 +1283,7 @@ func TestNegotiateWithLLM_FallbackSynthesize(t *testing.T) {
 
 func TestStartNegotiation_NoConflicts(t *testing.T) {
 	g := newTestGovernance("otter-1")
-	_, err := g.startNegotiation(context.Background(), "raft-2", "endpoint", nil, nil)
+	_, err := g.startNegotiation(context.Background(), "raft-2", "endpoint", nil, nil, nil)
 	if err == nil {
 		t.Error("expected error for no conflicts")
 	}
@@ -1161,37 +1291,105 @@ func TestStartNegotiation_NoConflicts(t *testing.T) {
 
 // --- JoinRaft with httptest ---
 
-func TestJoinRaft_NoConflicts(t *testing.T) {
-	g := newTestGovernance("otter-1")
+// signedRaftRule is a raft-2 rule signed by g, as its inducting otter serves it
+func signedRaftRule(t *testing.T, g *Governance, ruleID, scope, body string) *Rule {
+	t.Helper()
+	rule := &Rule{RuleID: ruleID, RaftID: "raft-2", Scope: scope, Body: body, Version: 1, Timestamp: time.Now(), ProposedBy: g.config.ID}
+	if err := g.signRule(rule); err != nil {
+		t.Fatal(err)
+	}
+	return rule
+}
 
-	// Server returns target raft rules (no overlap with our raft)
-	rulesSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path == "/api/v1/governance/rules" {
-			rules := map[string]*Rule{
-				"ethics": {Scope: "ethics", Body: "be honest"},
+// joinTarget serves raft-2 as inductor would to a joining otter: its rules,
+// its members' signed keys, a join challenge and an accepting join response.
+// onJoin, if set, sees each join request body.
+func joinTarget(t *testing.T, inductor *Governance, rules []*Rule, onJoin func(body []byte)) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/governance/rafts/raft-2/rules":
+			writeSigned(w, r, inductor, rules)
+		case "/api/v1/governance/rafts/raft-2/keys":
+			writeSigned(w, r, inductor, []MemberKey{{ID: inductor.config.ID, SigningKey: ExportSigningPublicKey(inductor.crypto)}})
+		case "/api/v1/governance/join/challenge":
+			json.NewEncoder(w).Encode(JoinChallenge{RaftID: "raft-2", RequesterID: "otter-1", Nonce: "abcd"})
+		case "/api/v1/governance/join":
+			body, _ := io.ReadAll(r.Body)
+			if onJoin != nil {
+				onJoin(body)
 			}
-			json.NewEncoder(w).Encode(rules)
-			return
-		}
-		if r.URL.Path == "/api/v1/governance/join" {
-			json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
-			return
+			descriptor, _ := inductor.CapabilityDescriptor()
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"member_id":    inductor.config.ID,
+				"signing_key":  ExportSigningPublicKey(inductor.crypto),
+				"capabilities": descriptor,
+				"state":        StateActive,
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
 		}
-		w.WriteHeader(http.StatusNotFound)
 	}))
-	defer rulesSrv.Close()
+	t.Cleanup(srv.Close)
+	return srv
+}
 
-	err := g.JoinRaft(context.Background(), "raft-2", rulesSrv.URL, nil)
+func TestJoinRaft_NoConflicts(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	var joinReq struct {
+		Nonce              string `json:"nonce"`
+		ChallengeSignature string `json:"challenge_signature"`
+	}
+
+	// The target raft's rules do not overlap with our raft
+	inductor := newTestGovernance("otter-2")
+	srv := joinTarget(t, inductor, []*Rule{signedRaftRule(t, inductor, "r2", "ethics", "be honest")}, func(body []byte) {
+		json.Unmarshal(body, &joinReq)
+	})
+
+	err := g.JoinRaft(context.Background(), "raft-2", srv.URL, nil)
 	if err != nil {
 		t.Fatalf("JoinRaft error: %v", err)
 	}
 
+	// The join request answers the challenge with our signing key
+	sig, _ := hex.DecodeString(joinReq.ChallengeSignature)
+	payload := joinChallengePayload("raft-2", "otter-1", "abcd", g.crypto.GetPublicKey(), g.crypto.GetSigningPublicKey())
+	if joinReq.Nonce != "abcd" || !VerifySignature(payload, sig, g.crypto.GetSigningPublicKey()) {
+		t.Errorf("join request did not answer the challenge: %+v", joinReq)
+	}
+
 	// Verify we now have the raft
 	g.rafts.mu.RLock()
-	_, exists := g.rafts.rafts["raft-2"]
+	raft, exists := g.rafts.rafts["raft-2"]
 	g.rafts.mu.RUnlock()
-	if !exists {
-		t.Error("expected raft-2 to be added")
+	if !exists || raft.Rules["r2"] == nil {
+		t.Error("expected raft-2 to be added with its rules")
+	}
+}
+
+func TestJoinRaft_RefusesUnsignedRules(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	srv := joinTarget(t, newTestGovernance("otter-2"), []*Rule{{RuleID: "r2", RaftID: "raft-2", Scope: "ethics", Body: "be honest", Version: 1}}, nil)
+
+	if err := g.JoinRaft(context.Background(), "raft-2", srv.URL, nil); !errors.Is(err, ErrUnsigned) {
+		t.Fatalf("JoinRaft err = %v, want ErrUnsigned", err)
+	}
+	if _, exists := g.rafts.rafts["raft-2"]; exists {
+		t.Error("raft-2 should have been rolled back")
+	}
+}
+
+func TestJoinRaft_RefusesRulesSignedByNonMembers(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	outsider := newTestGovernance("outsider")
+	srv := joinTarget(t, newTestGovernance("otter-2"), []*Rule{signedRaftRule(t, outsider, "r2", "ethics", "be honest")}, nil)
+
+	if err := g.JoinRaft(context.Background(), "raft-2", srv.URL, nil); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("JoinRaft err = %v, want ErrInvalidSignature", err)
+	}
+	if _, exists := g.rafts.rafts["raft-2"]; exists {
+		t.Error("raft-2 should have been rolled back")
 	}
 }
 
@@ -1203,10 +1401,8 @@ func TestJoinRaft_WithConflicts_Negotiation(t *testing.T) {
 	}
 
 	rulesSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path == "/api/v1/governance/rules" {
-			rules := map[string]*Rule{
-				"r2": {Scope: "safety", Body: "be bold", RuleID: "r2", Version: 1},
-			}
+		if r.URL.Path == "/api/v1/governance/rafts/raft-2/rules" {
+			rules := []*Rule{{Scope: "safety", Body: "be bold", RuleID: "r2", Version: 1}}
 			json.NewEncoder(w).Encode(rules)
 			return
 		}
@@ -1218,23 +1414,26 @@ func TestJoinRaft_WithConflicts_Negotiation(t *testing.T) {
 		response: `{"scope":"safety","body":"Be balanced"}`,
 	}
 
-	// This will negotiate but the dual-raft vote needs both proposals to close.
-	// With a solo raft, the local proposal auto-closes, but the remote one won't.
-	// The function will time out on the remote vote.
+	// The compromise is drafted but waits for approval before any vote
 	err := g.JoinRaft(context.Background(), "raft-2", rulesSrv.URL, mockLLM)
-	// We expect an error about the negotiation vote timing out or failing
-	if err == nil {
-		t.Log("JoinRaft succeeded (both proposals auto-closed)")
+	if !errors.Is(err, ErrNegotiationAwaitingApproval) {
+		t.Fatalf("JoinRaft err = %v, want awaiting approval", err)
+	}
+	pending := g.Negotiations(NegotiationResolved)
+	if len(pending) != 1 || pending[0].ProposedRule == nil || pending[0].ProposedRule.Body != "Be balanced" {
+		t.Fatalf("pending negotiations = %+v", pending)
+	}
+	if open := g.GetOpenProposals(); len(open) != 0 {
+		t.Errorf("%d proposals opened before approval", len(open))
 	}
-	// This is acceptable - we're testing the negotiation path works without panicking
 }
 
 func TestJoinRaft_RemoteReject(t *testing.T) {
 	g := newTestGovernance("otter-1")
 
 	rulesSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path == "/api/v1/governance/rules" {
-			json.NewEncoder(w).Encode(map[string]*Rule{})
+		if r.URL.Path == "/api/v1/governance/rafts/raft-2/rules" {
+			json.NewEncoder(w).Encode([]*Rule{})
 			return
 		}
 		if r.URL.Path == "/api/v1/governance/join" {
@@ -1263,8 +1462,38 @@ func TestJoinRaft_RemoteReject(t *testing.T) {
 
 func TestAdoptRulesAndJoin_EmptyEndpoint(t *testing.T) {
 	g := newTestGovernance("otter-1")
-	err := g.adoptRulesAndJoin(context.Background(), "raft-2", map[string]*Rule{}, "")
+	err := g.adoptRulesAndJoin(context.Background(), "raft-2", map[string]*Rule{}, "", "")
 	if err == nil {
 		t.Error("expected error for empty endpoint")
 	}
+	g.rafts.mu.RLock()
+	_, exists := g.rafts.rafts["raft-2"]
+	g.rafts.mu.RUnlock()
+	if exists {
+		t.Error("raft-2 should have been rolled back")
+	}
+}
+
+func TestAdoptRulesAndJoin_SigningFailureRollsBack(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	g.crypto.signingKey = nil
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/governance/join/challenge" {
+			json.NewEncoder(w).Encode(JoinChallenge{RaftID: "raft-2", RequesterID: "otter-1", Nonce: "feed"})
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	if err := g.adoptRulesAndJoin(context.Background(), "raft-2", map[string]*Rule{}, srv.URL, ""); err == nil {
+		t.Fatal("expected error without a signing key")
+	}
+	g.rafts.mu.RLock()
+	_, exists := g.rafts.rafts["raft-2"]
+	g.rafts.mu.RUnlock()
+	if exists {
+		t.Error("raft-2 should have been rolled back")
+	}
 }
@@ -0,0 +1,241 @@
+package governance
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// DefaultHeartbeatGrace is how long a peer may go unheard before it is
+// marked inactive, when no grace period is configured
+const DefaultHeartbeatGrace = 5 * time.Minute
+
+// MessageHeartbeat tells raft peers that the sender is alive
+const MessageHeartbeat = "member.heartbeat"
+
+// Heartbeat is the payload of a member.heartbeat federation message
+type Heartbeat struct {
+	SentAt time.Time `json:"sent_at"`
+}
+
+// LivenessStatus summarizes whether a member has been heard from recently
+type LivenessStatus string
+
+const (
+	LivenessSelf        LivenessStatus = "self"        // This otter
+	LivenessAlive       LivenessStatus = "alive"       // Heard from within the grace period
+	LivenessUnreachable LivenessStatus = "unreachable" // Not heard from within the grace period
+	LivenessUntracked   LivenessStatus = "untracked"   // Heartbeats are off or the member does not send them
+)
+
+// Liveness reports when a member was last heard from
+type Liveness struct {
+	Status           LivenessStatus `json:"status"`
+	LastSeenAt       time.Time      `json:"last_seen_at"`
+	SecondsSinceSeen int64          `json:"seconds_since_seen"`
+}
+
+// heartbeatGrace returns how long peers may go unheard
+func (g *Governance) heartbeatGrace() time.Duration {
+	if g.config.HeartbeatGrace > 0 {
+		return g.config.HeartbeatGrace
+	}
+	return DefaultHeartbeatGrace
+}
+
+// heartbeatMonitor sends heartbeats to every raft peer and marks peers
+// that have gone quiet inactive
+func (g *Governance) heartbeatMonitor() {
+	ticker := time.NewTicker(g.config.HeartbeatInterval)
+	defer ticker.Stop()
+
+	// Peers get a full grace period from start-up, however long ago they
+	// were last seen before this otter went down
+	started := time.Now()
+
+	for {
+		select {
+		case <-ticker.C:
+			g.sendHeartbeats(context.Background())
+			g.checkUnreachableMembers(started, time.Now())
+		case <-g.shutdownCh:
+			return
+		}
+	}
+}
+
+// sendHeartbeats sends a heartbeat to the members of every live raft this
+// otter is active in. Inactive members are included so that a peer can
+// tell it was reconnected, even when each side had marked the other
+// inactive.
+func (g *Governance) sendHeartbeats(ctx context.Context) {
+	heartbeat := Heartbeat{SentAt: time.Now()}
+	for _, raftID := range g.heartbeatRafts() {
+		g.broadcast(ctx, raftID, MessageHeartbeat, heartbeat, g.membersInState(raftID, StateInactive)...)
+	}
+}
+
+// heartbeatRafts returns the live rafts in which this otter is an active
+// member alongside at least one other otter
+func (g *Governance) heartbeatRafts() []string {
+	g.rafts.mu.RLock()
+	defer g.rafts.mu.RUnlock()
+
+	var raftIDs []string
+	for raftID, raft := range g.rafts.rafts {
+		raft.mu.RLock()
+		self, ok := raft.Members[g.config.ID]
+		if raft.Archive == nil && ok && self.State == StateActive && len(raft.Members) > 1 {
+			raftIDs = append(raftIDs, raftID)
+		}
+		raft.mu.RUnlock()
+	}
+	sort.Strings(raftIDs)
+	return raftIDs
+}
+
+// membersInState returns snapshots of a raft's members in state
+func (g *Governance) membersInState(raftID string, state MembershipState) []*Member {
+	g.rafts.mu.RLock()
+	raft, exists := g.rafts.rafts[raftID]
+	g.rafts.mu.RUnlock()
+	if !exists {
+		return nil
+	}
+
+	raft.mu.RLock()
+	defer raft.mu.RUnlock()
+	var members []*Member
+	for _, member := range raft.Members {
+		if member.State == state {
+			snapshot := *member
+			members = append(members, &snapshot)
+		}
+	}
+	return members
+}
+
+// checkUnreachableMembers marks peers inactive when they have not been
+// heard from within the grace period, counted from no earlier than since,
+// and recounts the open proposals of rafts that lost active members
+func (g *Governance) checkUnreachableMembers(since, now time.Time) {
+	for _, raftID := range g.markUnreachableMembers(since, now) {
+		g.recountProposals(raftID)
+	}
+}
+
+// markUnreachableMembers marks quiet peers inactive and returns the rafts
+// that changed. Peers that do not send heartbeats are left alone.
+func (g *Governance) markUnreachableMembers(since, now time.Time) []string {
+	g.rafts.mu.RLock()
+	rafts := make([]*RaftInfo, 0, len(g.rafts.rafts))
+	for _, raft := range g.rafts.rafts {
+		rafts = append(rafts, raft)
+	}
+	g.rafts.mu.RUnlock()
+
+	threshold := now.Add(-g.heartbeatGrace())
+	if since.After(threshold) {
+		return nil
+	}
+
+	var changed []string
+	for _, raft := range rafts {
+		raft.mu.Lock()
+		if raft.Archive != nil {
+			raft.mu.Unlock()
+			continue
+		}
+		var quiet []string
+		for _, member := range raft.Members {
+			if member.ID == g.config.ID || member.State != StateActive || !member.Supports(MessageHeartbeat) {
+				continue
+			}
+			if member.LastSeenAt.Before(threshold) {
+				member.State = StateInactive
+				quiet = append(quiet, member.ID)
+			}
+		}
+		raft.mu.Unlock()
+		if len(quiet) == 0 {
+			continue
+		}
+
+		sort.Strings(quiet)
+		for _, memberID := range quiet {
+			g.log().Warn("member unreachable", "member_id", memberID, "raft_id", raft.RaftID, "grace", g.heartbeatGrace())
+			g.recordAudit(AuditMemberStateChanged, raft.RaftID, memberID, g.config.ID,
+				MemberAudit{MemberID: memberID, State: StateInactive})
+		}
+		if err := g.saveRaft(context.Background(), raft); err != nil {
+			g.log().Warn("failed to persist unreachable members", "raft_id", raft.RaftID, "error", err)
+		}
+		changed = append(changed, raft.RaftID)
+	}
+	return changed
+}
+
+// memberSeen records traffic from a member: its LastSeenAt moves forward
+// and, if it had been marked inactive for going quiet, it is active again.
+// The raft is persisted when the member came back or its stored LastSeenAt
+// is stale, so heartbeats do not write on every receipt.
+func (g *Governance) memberSeen(ctx context.Context, raftID, memberID string) {
+	g.rafts.mu.RLock()
+	raft, exists := g.rafts.rafts[raftID]
+	g.rafts.mu.RUnlock()
+	if !exists {
+		return
+	}
+
+	now := time.Now()
+	raft.mu.Lock()
+	member, ok := raft.Members[memberID]
+	if !ok || (member.State != StateActive && member.State != StateInactive) {
+		raft.mu.Unlock()
+		return
+	}
+	stale := now.Sub(member.LastSeenAt) > LivenessCheckInterval
+	reactivated := member.State == StateInactive
+	member.LastSeenAt = now
+	if reactivated {
+		member.State = StateActive
+	}
+	raft.mu.Unlock()
+
+	if reactivated {
+		g.log().InfoContext(ctx, "member reachable again", "member_id", memberID, "raft_id", raftID)
+		g.recordAudit(AuditMemberStateChanged, raftID, memberID, g.config.ID,
+			MemberAudit{MemberID: memberID, State: StateActive})
+	}
+	if reactivated || stale {
+		if err := g.saveRaft(ctx, raft); err != nil {
+			g.log().WarnContext(ctx, "failed to persist member liveness", "member_id", memberID, "raft_id", raftID, "error", err)
+		}
+	}
+}
+
+// Liveness reports how recently a member of one of this otter's rafts was
+// heard from
+func (g *Governance) Liveness(member *Member) Liveness {
+	now := time.Now()
+	liveness := Liveness{
+		LastSeenAt:       member.LastSeenAt,
+		SecondsSinceSeen: int64(now.Sub(member.LastSeenAt) / time.Second),
+	}
+	switch {
+	case member.ID == g.config.ID:
+		liveness.Status = LivenessSelf
+		liveness.LastSeenAt, liveness.SecondsSinceSeen = now, 0
+	case g.config.HeartbeatInterval <= 0 || !member.Supports(MessageHeartbeat):
+		liveness.Status = LivenessUntracked
+	case member.State == StateInactive:
+		liveness.Status = LivenessUnreachable
+	case member.State != StateActive:
+		liveness.Status = LivenessUntracked
+	case now.Sub(member.LastSeenAt) > g.heartbeatGrace():
+		liveness.Status = LivenessUnreachable
+	default:
+		liveness.Status = LivenessAlive
+	}
+	return liveness
+}
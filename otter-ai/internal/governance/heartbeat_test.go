@@ -0,0 +1,112 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// heartbeatPeers adds peers to g's raft that negotiated heartbeats
+func heartbeatPeers(g *Governance, ids ...string) map[string]*Governance {
+	peers := addPeers(g, ids...)
+	capabilities := LocalCapabilities()
+	for _, id := range ids {
+		g.rafts.rafts[g.config.ID].Members[id].Capabilities = &capabilities
+	}
+	return peers
+}
+
+func TestMarkUnreachableMembers(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	heartbeatPeers(g, "otter-2", "otter-3")
+	addPeers(g, "otter-4") // Legacy peer that never sends heartbeats
+	members := g.rafts.rafts["otter-1"].Members
+	now := time.Now()
+	members["otter-2"].LastSeenAt = now.Add(-time.Minute)
+	members["otter-3"].LastSeenAt = now.Add(-time.Hour)
+	members["otter-4"].LastSeenAt = now.Add(-time.Hour)
+
+	// Nothing is marked within a grace period of start-up
+	if changed := g.markUnreachableMembers(now.Add(-time.Minute), now); len(changed) != 0 {
+		t.Fatalf("changed %v within the start-up grace period", changed)
+	}
+
+	changed := g.markUnreachableMembers(now.Add(-time.Hour), now)
+	if len(changed) != 1 || changed[0] != "otter-1" {
+		t.Fatalf("changed = %v, want [otter-1]", changed)
+	}
+	for id, want := range map[string]MembershipState{
+		"otter-1": StateActive, "otter-2": StateActive, "otter-3": StateInactive, "otter-4": StateActive,
+	} {
+		if members[id].State != want {
+			t.Errorf("%s state = %s, want %s", id, members[id].State, want)
+		}
+	}
+	if got := g.Liveness(members["otter-4"]).Status; got != LivenessUntracked {
+		t.Errorf("legacy peer liveness = %s, want untracked", got)
+	}
+}
+
+func TestHandleEnvelope_HeartbeatRevivesMember(t *testing.T) {
+	sender, receiver := federatedPair(t)
+	member := receiver.rafts.rafts["otter-1"].Members["otter-1"]
+	member.State = StateInactive
+	member.LastSeenAt = time.Now().Add(-time.Hour)
+
+	// Only heartbeats are accepted from a member marked inactive
+	mirrorEviction(receiver, "otter-3")
+	env := revocationEnvelope(t, sender, "otter-3", sender, receiver)
+	if err := receiver.HandleEnvelope(context.Background(), env); !errors.Is(err, ErrUnknownSender) {
+		t.Fatalf("expected ErrUnknownSender, got %v", err)
+	}
+
+	env, err := sender.sealEnvelope(MessageHeartbeat, "otter-1", Heartbeat{SentAt: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := receiver.HandleEnvelope(context.Background(), env); err != nil {
+		t.Fatal(err)
+	}
+	if member.State != StateActive || time.Since(member.LastSeenAt) > time.Minute {
+		t.Errorf("member = %+v, want active and just seen", member)
+	}
+}
+
+func TestCheckUnreachableMembers_RecountsOpenProposals(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	heartbeatPeers(g, "otter-2")
+	g.rafts.rafts["otter-1"].Members["otter-2"].LastSeenAt = time.Now().Add(-time.Hour)
+
+	proposal, err := g.ProposeRule(context.Background(), "otter-1", &Rule{Scope: "safety", Body: "be kind", ProposedBy: "otter-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CastVote(context.Background(), proposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	if proposal.Status != ProposalOpen {
+		t.Fatal("proposal closed before otter-2 voted")
+	}
+
+	// With otter-2 unreachable, the remaining voter's YES carries it
+	g.checkUnreachableMembers(time.Now().Add(-time.Hour), time.Now())
+	if proposal.Result != ResultAdopted {
+		t.Errorf("result = %s, want adopted once otter-2 went quiet", proposal.Result)
+	}
+}
+
+func TestSendHeartbeats(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	heartbeatPeers(g, "otter-2", "otter-3")
+	g.rafts.rafts["otter-1"].Members["otter-3"].State = StateInactive
+	transport := newRecordingTransport()
+	g.SetTransport(transport)
+
+	g.sendHeartbeats(context.Background())
+	for _, endpoint := range []string{"http://otter-2", "http://otter-3"} {
+		if sent := transport.sent[endpoint]; len(sent) != 1 || sent[0].Type != MessageHeartbeat {
+			t.Errorf("sent to %s: %+v, want one heartbeat", endpoint, sent)
+		}
+	}
+}
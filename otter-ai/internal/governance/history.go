@@ -0,0 +1,122 @@
+package governance
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"otter-ai/internal/errs"
+)
+
+// ErrRuleNotFound is returned when a rule ID is not an adopted rule
+var ErrRuleNotFound = errs.New(errs.ErrNotFound, "rule not found")
+
+// RuleVersion is one adopted version in a rule's amendment history
+type RuleVersion struct {
+	RuleID     string     `json:"rule_id"`
+	RaftID     string     `json:"raft_id"`
+	Scope      string     `json:"scope"`
+	Version    int        `json:"version"`
+	Body       string     `json:"body"`
+	BaseRuleID string     `json:"base_rule_id,omitempty"` // The version this one amended
+	ProposedBy string     `json:"proposed_by"`
+	AdoptedAt  *time.Time `json:"adopted_at,omitempty"`
+	Active     bool       `json:"active"`
+}
+
+// amendmentBase checks that an amendment of baseRuleID may be proposed in a
+// raft and returns the base. Only the latest version of a rule can be
+// amended, so the history stays a single chain.
+func (g *Governance) amendmentBase(raftID, baseRuleID string) (*Rule, error) {
+	g.rules.mu.RLock()
+	defer g.rules.mu.RUnlock()
+
+	base, ok := g.rules.rules[baseRuleID]
+	if !ok {
+		return nil, fmt.Errorf("%w: base rule %s", ErrRuleNotFound, baseRuleID)
+	}
+	if base.RaftID != raftID {
+		return nil, fmt.Errorf("base rule %s belongs to raft %s, not %s", baseRuleID, base.RaftID, raftID)
+	}
+	for _, rule := range g.rules.rules {
+		if rule.BaseRuleID == baseRuleID {
+			return nil, fmt.Errorf("rule %s was already amended by %s; amend the latest version", baseRuleID, rule.RuleID)
+		}
+	}
+	return base, nil
+}
+
+// RuleHistory returns the version chain containing ruleID, oldest first.
+// Any version's ID may be given; the chain runs from the original rule to
+// its latest adopted amendment.
+func (g *Governance) RuleHistory(ruleID string) ([]RuleVersion, error) {
+	g.rules.mu.RLock()
+	defer g.rules.mu.RUnlock()
+
+	rule, ok := g.rules.rules[ruleID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrRuleNotFound, ruleID)
+	}
+
+	// Walk back to the original version. Bases adopted elsewhere and never
+	// synced here end the chain early.
+	seen := map[string]bool{rule.RuleID: true}
+	root := rule
+	for root.BaseRuleID != "" {
+		base, ok := g.rules.rules[root.BaseRuleID]
+		if !ok || seen[base.RuleID] {
+			break
+		}
+		seen[base.RuleID] = true
+		root = base
+	}
+
+	amendments := make(map[string][]*Rule)
+	for _, candidate := range g.rules.rules {
+		if candidate.BaseRuleID != "" {
+			amendments[candidate.BaseRuleID] = append(amendments[candidate.BaseRuleID], candidate)
+		}
+	}
+
+	var history []RuleVersion
+	visited := make(map[string]bool)
+	for current := root; current != nil && !visited[current.RuleID]; {
+		visited[current.RuleID] = true
+		history = append(history, RuleVersion{
+			RuleID:     current.RuleID,
+			RaftID:     current.RaftID,
+			Scope:      current.Scope,
+			Version:    current.Version,
+			Body:       current.Body,
+			BaseRuleID: current.BaseRuleID,
+			ProposedBy: current.ProposedBy,
+			AdoptedAt:  current.AdoptedAt,
+			Active:     g.rules.active[activeKey(current)] == current,
+		})
+
+		// Competing amendments can only both be adopted via reconciliation
+		// with a peer; follow the one adopted first.
+		next := amendments[current.RuleID]
+		if len(next) == 0 {
+			break
+		}
+		sort.Slice(next, func(i, j int) bool {
+			return adoptedBefore(next[i], next[j])
+		})
+		current = next[0]
+	}
+	return history, nil
+}
+
+// adoptedBefore orders rules by adoption time, then ID
+func adoptedBefore(a, b *Rule) bool {
+	switch {
+	case a.AdoptedAt == nil || b.AdoptedAt == nil:
+		if (a.AdoptedAt == nil) != (b.AdoptedAt == nil) {
+			return a.AdoptedAt != nil
+		}
+	case !a.AdoptedAt.Equal(*b.AdoptedAt):
+		return a.AdoptedAt.Before(*b.AdoptedAt)
+	}
+	return a.RuleID < b.RuleID
+}
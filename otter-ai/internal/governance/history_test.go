@@ -0,0 +1,105 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// adoptTestRule proposes a rule in the self raft and adopts it with this
+// otter's vote
+func adoptTestRule(t *testing.T, g *Governance, rule *Rule) *Rule {
+	t.Helper()
+	rule.ProposedBy = g.config.ID
+	proposal, err := g.ProposeRule(context.Background(), g.config.ID, rule)
+	if err != nil {
+		t.Fatalf("ProposeRule: %v", err)
+	}
+	if err := g.CastVote(context.Background(), proposal.ProposalID, VoteYes); err != nil {
+		t.Fatalf("CastVote: %v", err)
+	}
+	if proposal.Result != ResultAdopted {
+		t.Fatalf("proposal not adopted: %s", proposal.Result)
+	}
+	return proposal.Rule
+}
+
+func TestProposeRule_AmendmentIncrementsVersion(t *testing.T) {
+	g := newTestGovernance("otter-1")
+
+	original := adoptTestRule(t, g, &Rule{Scope: "safety", Body: "be kind"})
+	if original.Version != 1 {
+		t.Fatalf("new rule version = %d, want 1", original.Version)
+	}
+
+	amendment := adoptTestRule(t, g, &Rule{Body: "be kind and patient", BaseRuleID: original.RuleID})
+	if amendment.Version != 2 || amendment.Scope != "safety" {
+		t.Errorf("amendment = v%d in %q, want v2 in safety", amendment.Version, amendment.Scope)
+	}
+	if active := g.GetActiveRulesForRaft("otter-1")["safety"]; active != amendment {
+		t.Errorf("active rule = %v, want the amendment", active)
+	}
+	if _, kept := g.rules.rules[original.RuleID]; !kept {
+		t.Error("the amended version should be kept for history")
+	}
+}
+
+func TestProposeRule_AmendmentValidation(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	ctx := context.Background()
+
+	_, err := g.ProposeRule(ctx, "otter-1", &Rule{Body: "x", BaseRuleID: "missing", ProposedBy: "otter-1"})
+	if !errors.Is(err, ErrRuleNotFound) {
+		t.Errorf("expected ErrRuleNotFound, got %v", err)
+	}
+
+	original := adoptTestRule(t, g, &Rule{Scope: "safety", Body: "be kind"})
+	adoptTestRule(t, g, &Rule{Body: "be kind and patient", BaseRuleID: original.RuleID})
+
+	if _, err := g.ProposeRule(ctx, "otter-1", &Rule{Body: "be terse", BaseRuleID: original.RuleID, ProposedBy: "otter-1"}); err == nil {
+		t.Error("amending a superseded version should fail")
+	}
+
+	g.rafts.rafts["raft-2"] = &RaftInfo{RaftID: "raft-2", Members: g.rafts.rafts["otter-1"].Members, Rules: map[string]*Rule{}}
+	if _, err := g.ProposeRule(ctx, "raft-2", &Rule{Body: "x", BaseRuleID: original.RuleID, ProposedBy: "otter-1"}); err == nil {
+		t.Error("amending another raft's rule should fail")
+	}
+}
+
+func TestRuleHistory(t *testing.T) {
+	g := newTestGovernance("otter-1")
+
+	v1 := adoptTestRule(t, g, &Rule{Scope: "safety", Body: "be kind"})
+	v2 := adoptTestRule(t, g, &Rule{Body: "be kind and patient", BaseRuleID: v1.RuleID})
+	v3 := adoptTestRule(t, g, &Rule{Body: "be kind, patient and brief", BaseRuleID: v2.RuleID})
+	adoptTestRule(t, g, &Rule{Scope: "tone", Body: "be warm"})
+
+	// Any version resolves to the whole chain
+	for _, id := range []string{v1.RuleID, v2.RuleID, v3.RuleID} {
+		history, err := g.RuleHistory(id)
+		if err != nil {
+			t.Fatalf("RuleHistory(%s): %v", id, err)
+		}
+		if len(history) != 3 {
+			t.Fatalf("RuleHistory(%s) has %d versions, want 3", id, len(history))
+		}
+		for i, version := range history {
+			if version.Version != i+1 {
+				t.Errorf("history[%d].Version = %d", i, version.Version)
+			}
+			if version.AdoptedAt == nil || version.ProposedBy != "otter-1" {
+				t.Errorf("history[%d] missing adoption details: %+v", i, version)
+			}
+			if version.Active != (i == 2) {
+				t.Errorf("history[%d].Active = %v", i, version.Active)
+			}
+		}
+		if history[1].BaseRuleID != v1.RuleID || history[2].RuleID != v3.RuleID {
+			t.Errorf("unexpected chain %+v", history)
+		}
+	}
+
+	if _, err := g.RuleHistory("missing"); !errors.Is(err, ErrRuleNotFound) {
+		t.Errorf("expected ErrRuleNotFound, got %v", err)
+	}
+}
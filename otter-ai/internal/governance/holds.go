@@ -0,0 +1,309 @@
+package governance
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"otter-ai/internal/errs"
+	"otter-ai/internal/memory"
+)
+
+// HoldReleaseApprovals is the number of distinct approvers needed to
+// release a legal hold
+const HoldReleaseApprovals = 2
+
+// Legal hold errors
+var (
+	ErrHoldNotFound      = errs.New(errs.ErrNotFound, "hold not found")
+	ErrHoldReleased      = errs.New(errs.ErrConflict, "hold already released")
+	ErrDuplicateApproval = errs.New(errs.ErrConflict, "approver already approved this release")
+	ErrPlacerApproval    = errs.New(errs.ErrConflict, "the approver who placed a hold cannot approve its release")
+)
+
+// HoldKind identifies what a legal hold covers
+type HoldKind string
+
+const (
+	HoldMemory   HoldKind = "memory"
+	HoldProposal HoldKind = "proposal"
+	HoldAudit    HoldKind = "audit"
+)
+
+// Hold marks records immutable: exempt from pruning, redaction and
+// compaction until released by HoldReleaseApprovals distinct approvers
+type Hold struct {
+	HoldID           string            `json:"hold_id"`
+	Kind             HoldKind          `json:"kind"`
+	SubjectID        string            `json:"subject_id,omitempty"`  // Memory or proposal ID
+	MemoryType       memory.MemoryType `json:"memory_type,omitempty"` // For memory holds
+	RaftID           string            `json:"raft_id,omitempty"`     // For audit holds; empty covers every raft
+	Since            *time.Time        `json:"since,omitempty"`       // Start of an audit hold, inclusive
+	Until            *time.Time        `json:"until,omitempty"`       // End of an audit hold, inclusive
+	Reason           string            `json:"reason"`
+	PlacedBy         string            `json:"placed_by"`
+	PlacedAt         time.Time         `json:"placed_at"`
+	ReleaseApprovals []string          `json:"release_approvals,omitempty"`
+	ReleasedAt       *time.Time        `json:"released_at,omitempty"`
+}
+
+// Active reports whether the hold is still in force
+func (h *Hold) Active() bool {
+	return h.ReleasedAt == nil
+}
+
+// covers reports whether the hold applies to an audit entry
+func (h *Hold) covers(entry *AuditEntry) bool {
+	if h.Kind != HoldAudit || !h.Active() {
+		return false
+	}
+	filter := AuditFilter{RaftID: h.RaftID}
+	if h.Since != nil {
+		filter.Since = *h.Since
+	}
+	if h.Until != nil {
+		filter.Until = *h.Until
+	}
+	return filter.matches(entry)
+}
+
+// HoldRequest describes a hold to place
+type HoldRequest struct {
+	Kind       HoldKind
+	SubjectID  string
+	MemoryType memory.MemoryType // Defaults to long_term
+	RaftID     string
+	Since      time.Time
+	Until      time.Time
+	Reason     string
+	PlacedBy   string
+}
+
+// holdRegistry holds every placed hold, released or not
+type holdRegistry struct {
+	holds map[string]*Hold
+	mu    sync.RWMutex
+}
+
+func (g *Governance) holdRegistry() *holdRegistry {
+	g.holdsOnce.Do(func() {
+		if g.holds == nil {
+			g.holds = &holdRegistry{holds: make(map[string]*Hold)}
+		}
+	})
+	return g.holds
+}
+
+// PlaceHold places a legal hold. Memory holds also flag the memory itself
+// so retention jobs see the hold without consulting governance.
+func (g *Governance) PlaceHold(ctx context.Context, req HoldRequest) (*Hold, error) {
+	if req.PlacedBy == "" {
+		return nil, fmt.Errorf("placed_by is required")
+	}
+	if req.Reason == "" {
+		return nil, fmt.Errorf("reason is required")
+	}
+
+	now := time.Now()
+	hold := &Hold{
+		Kind:     req.Kind,
+		Reason:   req.Reason,
+		PlacedBy: req.PlacedBy,
+		PlacedAt: now,
+	}
+
+	switch req.Kind {
+	case HoldMemory:
+		if req.SubjectID == "" {
+			return nil, fmt.Errorf("subject_id is required for memory holds")
+		}
+		if req.MemoryType == "" {
+			req.MemoryType = memory.MemoryTypeLongTerm
+		}
+		if _, err := g.memory.SetHeld(ctx, req.SubjectID, req.MemoryType, true); err != nil {
+			return nil, err
+		}
+		hold.SubjectID = req.SubjectID
+		hold.MemoryType = req.MemoryType
+
+	case HoldProposal:
+		if _, ok := g.GetProposal(req.SubjectID); !ok {
+			return nil, fmt.Errorf("%w: %s", ErrProposalNotFound, req.SubjectID)
+		}
+		hold.SubjectID = req.SubjectID
+
+	case HoldAudit:
+		if req.Since.IsZero() || req.Until.IsZero() {
+			return nil, fmt.Errorf("since and until are required for audit holds")
+		}
+		if req.Until.Before(req.Since) {
+			return nil, fmt.Errorf("until is before since")
+		}
+		since, until := req.Since, req.Until
+		hold.Since, hold.Until = &since, &until
+		hold.RaftID = req.RaftID
+
+	default:
+		return nil, fmt.Errorf("unknown hold kind %q", req.Kind)
+	}
+
+	hold.HoldID = generateID(fmt.Sprintf("hold-%s-%s-%d", hold.Kind, hold.SubjectID, now.UnixNano()))
+
+	r := g.holdRegistry()
+	r.mu.Lock()
+	r.holds[hold.HoldID] = hold
+	snapshot := copyHold(hold)
+	r.mu.Unlock()
+
+	if err := g.saveHold(ctx, snapshot); err != nil {
+		g.log().WarnContext(ctx, "failed to persist hold", "hold_id", hold.HoldID, "error", err)
+	}
+	g.recordAudit(AuditHoldPlaced, g.holdRaft(snapshot), hold.HoldID, hold.PlacedBy, snapshot)
+
+	return snapshot, nil
+}
+
+// ApproveHoldRelease records an approver's consent to release a hold. The
+// hold is released once HoldReleaseApprovals distinct approvers, none of
+// them the one who placed it, agree.
+func (g *Governance) ApproveHoldRelease(ctx context.Context, holdID, approver string) (*Hold, error) {
+	if approver == "" {
+		return nil, fmt.Errorf("approver is required")
+	}
+
+	r := g.holdRegistry()
+	r.mu.Lock()
+	hold, ok := r.holds[holdID]
+	if !ok {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrHoldNotFound, holdID)
+	}
+	if !hold.Active() {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrHoldReleased, holdID)
+	}
+	if approver == hold.PlacedBy {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrPlacerApproval, approver)
+	}
+	for _, existing := range hold.ReleaseApprovals {
+		if existing == approver {
+			r.mu.Unlock()
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateApproval, approver)
+		}
+	}
+
+	hold.ReleaseApprovals = append(hold.ReleaseApprovals, approver)
+	released := len(hold.ReleaseApprovals) >= HoldReleaseApprovals
+	if released {
+		now := time.Now()
+		hold.ReleasedAt = &now
+	}
+	stillHeld := released && hold.Kind == HoldMemory && r.memoryHeldLocked(hold.SubjectID)
+	snapshot := copyHold(hold)
+	r.mu.Unlock()
+
+	if err := g.saveHold(ctx, snapshot); err != nil {
+		g.log().WarnContext(ctx, "failed to persist hold", "hold_id", holdID, "error", err)
+	}
+
+	auditRaft := g.holdRaft(snapshot)
+	g.recordAudit(AuditHoldReleaseApproved, auditRaft, holdID, approver, snapshot)
+	if !released {
+		return snapshot, nil
+	}
+	g.recordAudit(AuditHoldReleased, auditRaft, holdID, approver, snapshot)
+
+	// Another hold may still cover the same memory
+	if snapshot.Kind == HoldMemory && !stillHeld {
+		if _, err := g.memory.SetHeld(ctx, snapshot.SubjectID, snapshot.MemoryType, false); err != nil {
+			g.log().WarnContext(ctx, "failed to lift hold flag on memory", "memory_id", snapshot.SubjectID, "error", err)
+		}
+	}
+
+	return snapshot, nil
+}
+
+// GetHolds returns holds, newest first. Released holds are included only
+// when includeReleased is set.
+func (g *Governance) GetHolds(includeReleased bool) []*Hold {
+	r := g.holdRegistry()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	holds := make([]*Hold, 0, len(r.holds))
+	for _, hold := range r.holds {
+		if hold.Active() || includeReleased {
+			holds = append(holds, copyHold(hold))
+		}
+	}
+	sort.Slice(holds, func(i, j int) bool {
+		return holds[i].PlacedAt.After(holds[j].PlacedAt)
+	})
+	return holds
+}
+
+// MemoryHeld reports whether an active hold covers a memory
+func (g *Governance) MemoryHeld(memoryID string) bool {
+	r := g.holdRegistry()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.memoryHeldLocked(memoryID)
+}
+
+// ProposalHeld reports whether an active hold covers a proposal
+func (g *Governance) ProposalHeld(proposalID string) bool {
+	r := g.holdRegistry()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, hold := range r.holds {
+		if hold.Kind == HoldProposal && hold.Active() && hold.SubjectID == proposalID {
+			return true
+		}
+	}
+	return false
+}
+
+// AuditHeld reports whether an active hold covers an audit entry. Jobs that
+// prune or compact the audit log must skip held entries.
+func (g *Governance) AuditHeld(entry AuditEntry) bool {
+	r := g.holdRegistry()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, hold := range r.holds {
+		if hold.covers(&entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// holdRaft is the raft a hold's audit entries are recorded under
+func (g *Governance) holdRaft(hold *Hold) string {
+	if hold.Kind == HoldProposal {
+		if proposal, ok := g.GetProposal(hold.SubjectID); ok {
+			return proposal.RaftID
+		}
+	}
+	if hold.RaftID != "" {
+		return hold.RaftID
+	}
+	return g.config.ID
+}
+
+func (r *holdRegistry) memoryHeldLocked(memoryID string) bool {
+	for _, hold := range r.holds {
+		if hold.Kind == HoldMemory && hold.Active() && hold.SubjectID == memoryID {
+			return true
+		}
+	}
+	return false
+}
+
+func copyHold(hold *Hold) *Hold {
+	copied := *hold
+	copied.ReleaseApprovals = append([]string(nil), hold.ReleaseApprovals...)
+	return &copied
+}
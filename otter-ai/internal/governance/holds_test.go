@@ -0,0 +1,220 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"otter-ai/internal/memory"
+	"otter-ai/internal/vectordb"
+)
+
+func newTestHoldGovernance(t *testing.T) (*Governance, *memory.Memory, string, *vectordb.SQLiteVectorDB) {
+	t.Helper()
+	dir := t.TempDir()
+	db, err := vectordb.NewSQLiteVectorDB(filepath.Join(dir, "otter.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	mem := memory.New(db)
+	g, err := New(RaftConfig{ID: "otter-1", DataDir: dir}, mem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { g.Shutdown(context.Background()) })
+	return g, mem, dir, db
+}
+
+func TestMemoryHold_DualRelease(t *testing.T) {
+	g, mem, _, _ := newTestHoldGovernance(t)
+	ctx := context.Background()
+
+	record := &memory.MemoryRecord{Type: memory.MemoryTypeLongTerm, Content: "contract terms", Embedding: []float32{1, 0}}
+	if err := mem.Store(ctx, record); err != nil {
+		t.Fatal(err)
+	}
+
+	hold, err := g.PlaceHold(ctx, HoldRequest{Kind: HoldMemory, SubjectID: record.ID, Reason: "litigation", PlacedBy: "counsel"})
+	if err != nil {
+		t.Fatalf("PlaceHold: %v", err)
+	}
+	if !g.MemoryHeld(record.ID) {
+		t.Error("memory should be held")
+	}
+	if err := mem.Delete(ctx, record.ID, memory.MemoryTypeLongTerm); !errors.Is(err, memory.ErrHeld) {
+		t.Errorf("Delete err = %v, want ErrHeld", err)
+	}
+
+	hold, err = g.ApproveHoldRelease(ctx, hold.HoldID, "alice")
+	if err != nil {
+		t.Fatalf("first approval: %v", err)
+	}
+	if !hold.Active() {
+		t.Error("one approval must not release the hold")
+	}
+	if _, err := g.ApproveHoldRelease(ctx, hold.HoldID, "alice"); !errors.Is(err, ErrDuplicateApproval) {
+		t.Errorf("repeat approval err = %v, want ErrDuplicateApproval", err)
+	}
+
+	hold, err = g.ApproveHoldRelease(ctx, hold.HoldID, "bob")
+	if err != nil {
+		t.Fatalf("second approval: %v", err)
+	}
+	if hold.Active() || g.MemoryHeld(record.ID) {
+		t.Error("two approvals should release the hold")
+	}
+	got, err := mem.Get(ctx, record.ID, memory.MemoryTypeLongTerm)
+	if err != nil || got.Held {
+		t.Errorf("memory hold flag should be lifted: %+v, %v", got, err)
+	}
+	if _, err := g.ApproveHoldRelease(ctx, hold.HoldID, "carol"); !errors.Is(err, ErrHoldReleased) {
+		t.Errorf("approval after release err = %v, want ErrHoldReleased", err)
+	}
+
+	var actions []AuditAction
+	g.EachAuditEntry(ctx, AuditFilter{}, func(entry AuditEntry) error {
+		if entry.SubjectID == hold.HoldID {
+			actions = append(actions, entry.Action)
+		}
+		return nil
+	})
+	want := []AuditAction{AuditHoldPlaced, AuditHoldReleaseApproved, AuditHoldReleaseApproved, AuditHoldReleased}
+	if len(actions) != len(want) {
+		t.Fatalf("audit actions = %v, want %v", actions, want)
+	}
+	for i := range want {
+		if actions[i] != want[i] {
+			t.Errorf("audit actions = %v, want %v", actions, want)
+			break
+		}
+	}
+}
+
+func TestApproveHoldRelease_RefusesPlacer(t *testing.T) {
+	g, _, _, _ := newTestHoldGovernance(t)
+	ctx := context.Background()
+
+	since := time.Now().Add(-time.Hour)
+	hold, err := g.PlaceHold(ctx, HoldRequest{Kind: HoldAudit, Since: since, Until: time.Now(), Reason: "r", PlacedBy: "alice"})
+	if err != nil {
+		t.Fatalf("PlaceHold: %v", err)
+	}
+	if _, err := g.ApproveHoldRelease(ctx, hold.HoldID, "alice"); !errors.Is(err, ErrPlacerApproval) {
+		t.Fatalf("placer approval err = %v, want ErrPlacerApproval", err)
+	}
+	g.ApproveHoldRelease(ctx, hold.HoldID, "bob")
+	if got := g.GetHolds(true); len(got) != 1 || !got[0].Active() || len(got[0].ReleaseApprovals) != 1 {
+		t.Errorf("the placer's refused approval must not count: %+v", got)
+	}
+}
+
+func TestMemoryHold_OverlappingHolds(t *testing.T) {
+	g, mem, _, _ := newTestHoldGovernance(t)
+	ctx := context.Background()
+
+	record := &memory.MemoryRecord{Type: memory.MemoryTypeLongTerm, Content: "ledger", Embedding: []float32{1, 0}}
+	mem.Store(ctx, record)
+
+	first, _ := g.PlaceHold(ctx, HoldRequest{Kind: HoldMemory, SubjectID: record.ID, Reason: "audit", PlacedBy: "counsel"})
+	if _, err := g.PlaceHold(ctx, HoldRequest{Kind: HoldMemory, SubjectID: record.ID, Reason: "subpoena", PlacedBy: "counsel"}); err != nil {
+		t.Fatal(err)
+	}
+	g.ApproveHoldRelease(ctx, first.HoldID, "alice")
+	g.ApproveHoldRelease(ctx, first.HoldID, "bob")
+
+	got, _ := mem.Get(ctx, record.ID, memory.MemoryTypeLongTerm)
+	if !got.Held {
+		t.Error("memory should stay held while another hold covers it")
+	}
+}
+
+func TestAuditHold_PersistsAcrossRestart(t *testing.T) {
+	g, _, dir, db := newTestHoldGovernance(t)
+	ctx := context.Background()
+
+	since := time.Now().Add(-time.Hour)
+	until := time.Now().Add(time.Hour)
+	hold, err := g.PlaceHold(ctx, HoldRequest{Kind: HoldAudit, RaftID: "otter-1", Since: since, Until: until,
+		Reason: "regulator request", PlacedBy: "compliance"})
+	if err != nil {
+		t.Fatalf("PlaceHold: %v", err)
+	}
+	g.ApproveHoldRelease(ctx, hold.HoldID, "alice")
+
+	reloaded, err := New(RaftConfig{ID: "otter-1", DataDir: dir}, memory.New(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reloaded.Shutdown(ctx)
+
+	holds := reloaded.GetHolds(false)
+	if len(holds) != 1 || holds[0].HoldID != hold.HoldID || len(holds[0].ReleaseApprovals) != 1 {
+		t.Fatalf("holds after reload = %+v", holds)
+	}
+	if !holds[0].Since.Equal(since) || !holds[0].Until.Equal(until) {
+		t.Errorf("range after reload = %v..%v, want %v..%v", holds[0].Since, holds[0].Until, since, until)
+	}
+
+	if !reloaded.AuditHeld(AuditEntry{RaftID: "otter-1", Timestamp: time.Now()}) {
+		t.Error("entry inside the range should be held")
+	}
+	if reloaded.AuditHeld(AuditEntry{RaftID: "otter-1", Timestamp: until.Add(time.Minute)}) {
+		t.Error("entry after the range should not be held")
+	}
+	if reloaded.AuditHeld(AuditEntry{RaftID: "raft-2", Timestamp: time.Now()}) {
+		t.Error("entry of another raft should not be held")
+	}
+}
+
+func TestPlaceHold_Validation(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	ctx := context.Background()
+	now := time.Now()
+
+	cases := []HoldRequest{
+		{Kind: HoldAudit, Since: now, Until: now, PlacedBy: "counsel"},                              // No reason
+		{Kind: HoldAudit, Since: now, Until: now, Reason: "r"},                                      // No placer
+		{Kind: "session", SubjectID: "s", Reason: "r", PlacedBy: "counsel"},                         // Unknown kind
+		{Kind: HoldAudit, Since: now, Until: now.Add(-time.Hour), Reason: "r", PlacedBy: "counsel"}, // Inverted range
+		{Kind: HoldAudit, Reason: "r", PlacedBy: "counsel"},                                         // No range
+		{Kind: HoldMemory, Reason: "r", PlacedBy: "counsel"},                                        // No subject
+	}
+	for i, req := range cases {
+		if _, err := g.PlaceHold(ctx, req); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+
+	_, err := g.PlaceHold(ctx, HoldRequest{Kind: HoldProposal, SubjectID: "missing", Reason: "r", PlacedBy: "counsel"})
+	if !errors.Is(err, ErrProposalNotFound) {
+		t.Errorf("err = %v, want ErrProposalNotFound", err)
+	}
+	if len(g.GetHolds(true)) != 0 {
+		t.Error("rejected holds must not be recorded")
+	}
+}
+
+func TestProposalHold(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	g.proposals.proposals["p1"] = &Proposal{ProposalID: "p1", RaftID: "otter-1", Status: ProposalOpen}
+
+	hold, err := g.PlaceHold(context.Background(), HoldRequest{Kind: HoldProposal, SubjectID: "p1", Reason: "r", PlacedBy: "counsel"})
+	if err != nil {
+		t.Fatalf("PlaceHold: %v", err)
+	}
+	if !g.ProposalHeld("p1") || g.ProposalHeld("p2") {
+		t.Error("only p1 should be held")
+	}
+	if _, err := g.ApproveHoldRelease(context.Background(), "nope", "alice"); !errors.Is(err, ErrHoldNotFound) {
+		t.Errorf("err = %v, want ErrHoldNotFound", err)
+	}
+	g.ApproveHoldRelease(context.Background(), hold.HoldID, "alice")
+	g.ApproveHoldRelease(context.Background(), hold.HoldID, "bob")
+	if g.ProposalHeld("p1") {
+		t.Error("released hold should no longer cover p1")
+	}
+}
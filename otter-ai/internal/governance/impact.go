@@ -0,0 +1,200 @@
+package governance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"otter-ai/internal/llm"
+	"otter-ai/internal/prompts"
+)
+
+// ImpactAnalysisTimeout bounds the LLM call analysing a new proposal, so
+// proposing is not held up by a slow provider; an analysis that times out
+// is queued for replay
+const ImpactAnalysisTimeout = 30 * time.Second
+
+// TaskImpactAnalysis replays the impact analysis of a proposal made while
+// the LLM provider was unavailable
+const TaskImpactAnalysis LLMTaskKind = "impact_analysis"
+
+// ImpactStatus is the state of a proposal's impact analysis
+type ImpactStatus string
+
+const (
+	ImpactPending ImpactStatus = "pending" // Queued until the LLM provider recovers
+	ImpactReady   ImpactStatus = "ready"
+	ImpactFailed  ImpactStatus = "failed" // The LLM's reply could not be read
+)
+
+// ImpactConflict is an active rule a proposal would conflict with
+type ImpactConflict struct {
+	RuleID string `json:"rule_id"`
+	Scope  string `json:"scope"`
+	Body   string `json:"body"`
+	Reason string `json:"reason"`
+}
+
+// ImpactAnalysis is the LLM's assessment of what adopting a rule proposal
+// would change, attached to the proposal for voters to weigh
+type ImpactAnalysis struct {
+	Status         ImpactStatus     `json:"status"`
+	Summary        string           `json:"summary,omitempty"`
+	Conflicts      []ImpactConflict `json:"conflicts,omitempty"`
+	AffectedScopes []string         `json:"affected_scopes,omitempty"`
+	Examples       []string         `json:"examples,omitempty"` // Behaviour that would change
+	Error          string           `json:"error,omitempty"`
+	GeneratedAt    time.Time        `json:"generated_at"`
+}
+
+// analyzeImpact attaches an impact analysis to a new rule proposal. When
+// the provider fails, the analysis is marked pending and queued for
+// replay; nothing is attached without a provider.
+func (g *Governance) analyzeImpact(ctx context.Context, proposal *Proposal) {
+	provider := g.llmProvider()
+	if provider == nil || proposal.Rule == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ImpactAnalysisTimeout)
+	defer cancel()
+	impact, err := g.generateImpact(ctx, proposal, provider)
+	if errors.Is(err, ErrLLMUnavailable) {
+		impact = &ImpactAnalysis{Status: ImpactPending, Error: err.Error(), GeneratedAt: time.Now()}
+		if _, qerr := g.enqueueLLMTask(context.WithoutCancel(ctx), TaskImpactAnalysis, proposal.ProposalID, nil, err); qerr != nil {
+			g.log().WarnContext(ctx, "failed to queue impact analysis", "proposal_id", proposal.ProposalID, "error", qerr)
+		}
+	}
+	g.setImpact(proposal, impact)
+}
+
+// replayImpactAnalysis analyses a proposal whose analysis was deferred.
+// Proposals closed in the meantime are skipped.
+func (g *Governance) replayImpactAnalysis(ctx context.Context, task *LLMTask, provider completer) (string, error) {
+	proposal, ok := g.GetProposal(task.RefID)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrProposalNotFound, task.RefID)
+	}
+	g.proposals.mu.RLock()
+	open := proposal.Status == ProposalOpen
+	g.proposals.mu.RUnlock()
+	if !open {
+		return "proposal closed before analysis", nil
+	}
+
+	impact, err := g.generateImpact(ctx, proposal, provider)
+	if err != nil {
+		return "", err
+	}
+	g.setImpact(proposal, impact)
+	return impact.Summary, nil
+}
+
+// setImpact attaches an analysis to a proposal
+func (g *Governance) setImpact(proposal *Proposal, impact *ImpactAnalysis) {
+	g.proposals.mu.Lock()
+	defer g.proposals.mu.Unlock()
+	proposal.Impact = impact
+}
+
+// generateImpact asks the LLM how a rule proposal would interact with the
+// raft's active rules. Provider errors wrap ErrLLMUnavailable; a reply
+// that is not the requested JSON gives a failed analysis.
+func (g *Governance) generateImpact(ctx context.Context, proposal *Proposal, provider completer) (*ImpactAnalysis, error) {
+	rules := g.impactRules(proposal)
+	resp, err := provider.Complete(ctx, &llm.CompletionRequest{
+		Prompt:  g.buildImpactPrompt(proposal, rules),
+		Profile: llm.ProfileNegotiation,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrLLMUnavailable, err)
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("%w: empty response", ErrLLMUnavailable)
+	}
+	return parseImpact(resp.Text, rules), nil
+}
+
+// impactRules returns the raft's active rules a proposal is weighed
+// against, leaving out the rule an amendment replaces
+func (g *Governance) impactRules(proposal *Proposal) []*Rule {
+	var rules []*Rule
+	for _, rule := range sortedRules(g.GetActiveRulesForRaft(proposal.RaftID)) {
+		if rule.RuleID != proposal.Rule.BaseRuleID {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// buildImpactPrompt asks for a proposal's conflicts, affected scopes and
+// examples of changed behaviour, with the raft's rules numbered
+func (g *Governance) buildImpactPrompt(proposal *Proposal, rules []*Rule) string {
+	var base *Rule
+	if proposal.Rule.BaseRuleID != "" {
+		g.rules.mu.RLock()
+		base = g.rules.rules[proposal.Rule.BaseRuleID]
+		g.rules.mu.RUnlock()
+	}
+	return g.renderPrompt(prompts.ProposalImpact, struct {
+		RaftID   string
+		Proposed *Rule
+		Amends   *Rule
+		Reason   string
+		Rules    []*Rule
+	}{proposal.RaftID, proposal.Rule, base, proposal.Reason, rules})
+}
+
+// parseImpact reads an impact analysis. Conflicts name rules by their
+// number in the prompt; numbers matching no rule are dropped.
+func parseImpact(raw string, rules []*Rule) *ImpactAnalysis {
+	var parsed struct {
+		Summary   string `json:"summary"`
+		Conflicts []struct {
+			Rule   int    `json:"rule"`
+			Reason string `json:"reason"`
+		} `json:"conflicts"`
+		AffectedScopes []string `json:"affected_scopes"`
+		Examples       []string `json:"examples"`
+	}
+	impact := &ImpactAnalysis{GeneratedAt: time.Now()}
+	if err := json.Unmarshal([]byte(trimCodeFence(raw)), &parsed); err != nil {
+		impact.Status = ImpactFailed
+		impact.Error = "the LLM's analysis was not valid JSON"
+		return impact
+	}
+
+	impact.Status = ImpactReady
+	impact.Summary = strings.TrimSpace(parsed.Summary)
+	for _, conflict := range parsed.Conflicts {
+		if conflict.Rule < 1 || conflict.Rule > len(rules) {
+			continue
+		}
+		rule := rules[conflict.Rule-1]
+		impact.Conflicts = append(impact.Conflicts, ImpactConflict{
+			RuleID: rule.RuleID,
+			Scope:  rule.Scope,
+			Body:   rule.Body,
+			Reason: strings.TrimSpace(conflict.Reason),
+		})
+	}
+	seen := make(map[string]bool)
+	for _, scope := range parsed.AffectedScopes {
+		scope = strings.TrimSpace(scope)
+		if scope != "" && !seen[scope] {
+			seen[scope] = true
+			impact.AffectedScopes = append(impact.AffectedScopes, scope)
+		}
+	}
+	sort.Strings(impact.AffectedScopes)
+	for _, example := range parsed.Examples {
+		if example = strings.TrimSpace(example); example != "" {
+			impact.Examples = append(impact.Examples, example)
+		}
+	}
+	return impact
+}
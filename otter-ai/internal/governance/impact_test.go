@@ -0,0 +1,87 @@
+package governance
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestProposeRule_AttachesImpactAnalysis(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	g.rules.active[ruleKey{RaftID: "otter-1", Scope: "tone"}] = &Rule{RuleID: "r-tone", RaftID: "otter-1", Scope: "tone", Body: "explain fully"}
+	provider := &scriptedLLMProvider{replies: []string{"```json\n" + `{"summary":"Replies get shorter","conflicts":[{"rule":1,"reason":"opposite lengths"},{"rule":7,"reason":"made up"}],"affected_scopes":["tone","tone"," general "],"examples":["One-line answers",""]}` + "\n```"}}
+	g.SetLLMProvider(provider)
+
+	proposal, err := g.ProposeRule(context.Background(), "otter-1", &Rule{Scope: "general", Body: "be terse", ProposedBy: "otter-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	impact := proposal.Impact
+	if impact == nil || impact.Status != ImpactReady || impact.Summary != "Replies get shorter" {
+		t.Fatalf("impact = %+v, want a ready analysis", impact)
+	}
+	if len(impact.Conflicts) != 1 || impact.Conflicts[0].RuleID != "r-tone" || impact.Conflicts[0].Reason != "opposite lengths" {
+		t.Errorf("conflicts = %+v, want only the numbered rule that exists", impact.Conflicts)
+	}
+	if strings.Join(impact.AffectedScopes, ",") != "general,tone" || len(impact.Examples) != 1 {
+		t.Errorf("scopes = %v, examples = %v", impact.AffectedScopes, impact.Examples)
+	}
+	if len(provider.prompts) != 1 || !strings.Contains(provider.prompts[0], "1. [tone] explain fully") || !strings.Contains(provider.prompts[0], "be terse") {
+		t.Errorf("prompt = %q, want the proposal and the numbered rules", provider.prompts)
+	}
+}
+
+func TestProposeRule_ImpactUnreadable(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	g.SetLLMProvider(&scriptedLLMProvider{replies: []string{"It seems fine."}})
+
+	proposal, err := g.ProposeRule(context.Background(), "otter-1", &Rule{Scope: "general", Body: "be terse", ProposedBy: "otter-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proposal.Impact == nil || proposal.Impact.Status != ImpactFailed {
+		t.Errorf("impact = %+v, want a failed analysis", proposal.Impact)
+	}
+	if len(g.GetLLMTasks()) != 0 {
+		t.Error("an unreadable analysis should not be queued")
+	}
+}
+
+func TestProposeRule_ImpactWithoutProvider(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	proposal, err := g.ProposeRule(context.Background(), "otter-1", &Rule{Scope: "general", Body: "be terse", ProposedBy: "otter-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proposal.Impact != nil {
+		t.Errorf("impact = %+v, want none without a provider", proposal.Impact)
+	}
+}
+
+func TestProposeRule_ImpactDeferredUntilProviderRecovers(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	g.SetLLMProvider(&failingLLMProvider{})
+
+	proposal, err := g.ProposeRule(context.Background(), "otter-1", &Rule{Scope: "general", Body: "be terse", ProposedBy: "otter-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proposal.Impact == nil || proposal.Impact.Status != ImpactPending {
+		t.Fatalf("impact = %+v, want pending", proposal.Impact)
+	}
+	tasks := g.GetLLMTasks()
+	if len(tasks) != 1 || tasks[0].Kind != TaskImpactAnalysis || tasks[0].RefID != proposal.ProposalID {
+		t.Fatalf("tasks = %+v, want the analysis queued", tasks)
+	}
+
+	g.SetLLMProvider(&scriptedLLMProvider{replies: []string{`{"summary":"Replies get shorter"}`}})
+	makeDue(g)
+	g.ProcessLLMTasks(context.Background())
+
+	if task, _ := g.GetLLMTask(tasks[0].TaskID); task.Status != TaskCompleted {
+		t.Errorf("task status = %q, want completed", task.Status)
+	}
+	if proposal.Impact.Status != ImpactReady || proposal.Impact.Summary != "Replies get shorter" {
+		t.Errorf("impact = %+v, want the replayed analysis", proposal.Impact)
+	}
+}
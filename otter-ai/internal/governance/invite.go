@@ -0,0 +1,161 @@
+package governance
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"otter-ai/internal/errs"
+)
+
+const (
+	// DefaultInviteTTL is how long an invite is valid when no TTL is given
+	DefaultInviteTTL = 24 * time.Hour
+	// MaxInviteTTL bounds how long an invite can be valid
+	MaxInviteTTL = 7 * 24 * time.Hour
+)
+
+// ErrInvalidInvite is returned for invites that are malformed, mis-signed,
+// expired, already used or not meant for the joining otter
+var ErrInvalidInvite = errs.New(errs.ErrUnauthorized, "invalid invite")
+
+// Invite admits an otter to a raft on the word of an existing member. It is
+// signed with the inviter's Ed25519 key, so it can be created offline with
+// keytool and checked by any member holding the inviter's signing key.
+type Invite struct {
+	InviteID  string    `json:"invite_id"`
+	RaftID    string    `json:"raft_id"`
+	InviterID string    `json:"inviter_id"`
+	InviteeID string    `json:"invitee_id,omitempty"` // Only this otter may use the invite, when set
+	ExpiresAt time.Time `json:"expires_at"`
+	Signature []byte    `json:"signature"`
+}
+
+// NewInvite signs an invite to a raft as inviterID. Zero ttl uses
+// DefaultInviteTTL.
+func NewInvite(cs *CryptoSystem, raftID, inviterID, inviteeID string, ttl time.Duration) (*Invite, error) {
+	if raftID == "" || inviterID == "" {
+		return nil, fmt.Errorf("raft and inviter IDs are required")
+	}
+	if ttl == 0 {
+		ttl = DefaultInviteTTL
+	}
+	if ttl < 0 || ttl > MaxInviteTTL {
+		return nil, fmt.Errorf("invite TTL must be between 0 and %s", MaxInviteTTL)
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate invite ID: %w", err)
+	}
+	invite := &Invite{
+		InviteID:  hex.EncodeToString(id),
+		RaftID:    raftID,
+		InviterID: inviterID,
+		InviteeID: inviteeID,
+		ExpiresAt: time.Now().Add(ttl).Truncate(time.Second),
+	}
+	sig, err := cs.Sign(inviteSigningPayload(invite))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign invite: %w", err)
+	}
+	invite.Signature = sig
+	return invite, nil
+}
+
+// Token encodes the invite for handing to the invitee
+func (i *Invite) Token() (string, error) {
+	data, err := json.Marshal(i)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode invite: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// ParseInvite decodes an invite token without verifying it
+func ParseInvite(token string) (*Invite, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: token is not base64url", ErrInvalidInvite)
+	}
+	var invite Invite
+	if err := json.Unmarshal(data, &invite); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInvite, err)
+	}
+	return &invite, nil
+}
+
+// inviteSigningPayload returns the bytes an invite signature covers
+func inviteSigningPayload(invite *Invite) []byte {
+	return canonicalPayload(
+		"invite",
+		invite.InviteID,
+		invite.RaftID,
+		invite.InviterID,
+		invite.InviteeID,
+		strconv.FormatInt(invite.ExpiresAt.Unix(), 10),
+	)
+}
+
+// CreateInvite signs an invite to one of this otter's rafts
+func (g *Governance) CreateInvite(raftID, inviteeID string, ttl time.Duration) (*Invite, error) {
+	raft, err := g.liveRaft(raftID)
+	if err != nil {
+		return nil, err
+	}
+	raft.mu.RLock()
+	self, ok := raft.Members[g.config.ID]
+	raft.mu.RUnlock()
+	if !ok || self.State != StateActive {
+		return nil, fmt.Errorf("only active members can invite to raft %s", raftID)
+	}
+	return NewInvite(g.crypto, raftID, g.config.ID, inviteeID, ttl)
+}
+
+// redeemInvite verifies the invite a join request presents and marks it
+// used on the raft, which persists it with the raft. The inviter must be an
+// active member of the raft whose signing key verifies the invite.
+func (g *Governance) redeemInvite(raft *RaftInfo, req *JoinRequest) (*Invite, error) {
+	invite, err := ParseInvite(req.Invite)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	switch {
+	case invite.RaftID != req.RaftID:
+		return nil, fmt.Errorf("%w: issued for raft %s", ErrInvalidInvite, invite.RaftID)
+	case invite.InviteeID != "" && invite.InviteeID != req.RequesterID:
+		return nil, fmt.Errorf("%w: issued for %s", ErrInvalidInvite, invite.InviteeID)
+	case now.After(invite.ExpiresAt):
+		return nil, fmt.Errorf("%w: expired at %s", ErrInvalidInvite, invite.ExpiresAt.Format(time.RFC3339))
+	}
+
+	raft.mu.Lock()
+	defer raft.mu.Unlock()
+
+	inviter, ok := raft.Members[invite.InviterID]
+	if !ok || inviter.State != StateActive || len(inviter.SigningKey) == 0 {
+		return nil, fmt.Errorf("%w: %s is not an active member of raft %s", ErrInvalidInvite, invite.InviterID, invite.RaftID)
+	}
+	if !VerifySignature(inviteSigningPayload(invite), invite.Signature, inviter.SigningKey) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInvite, ErrInvalidSignature)
+	}
+
+	if raft.UsedInvites == nil {
+		raft.UsedInvites = make(map[string]time.Time)
+	}
+	for id, expiresAt := range raft.UsedInvites {
+		if now.After(expiresAt) {
+			delete(raft.UsedInvites, id)
+		}
+	}
+	if _, used := raft.UsedInvites[invite.InviteID]; used {
+		return nil, fmt.Errorf("%w: already used", ErrInvalidInvite)
+	}
+	raft.UsedInvites[invite.InviteID] = invite.ExpiresAt
+	return invite, nil
+}
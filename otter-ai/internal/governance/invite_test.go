@@ -0,0 +1,169 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"otter-ai/internal/memory"
+	"otter-ai/internal/vectordb"
+)
+
+func TestRequestJoin_WithInvite(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	peers := addPeers(g, "otter-2")
+	invitee := newTestGovernance("otter-3")
+
+	// otter-2 signs the invite; otter-1 inducts on its behalf
+	invite, err := NewInvite(peers["otter-2"].crypto, "otter-1", "otter-2", "otter-3", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := invite.Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := answeredJoinRequest(t, g, invitee, "otter-1")
+	req.Invite = token
+	if _, err := g.RequestJoin(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	member := g.rafts.rafts["otter-1"].Members["otter-3"]
+	if member == nil || member.InductedBy != "otter-2" {
+		t.Fatalf("member = %+v, want inducted by otter-2", member)
+	}
+	if err := g.verifyMember("otter-1", member); err != nil {
+		t.Errorf("verifyMember: %v", err)
+	}
+
+	// The invite is used up
+	delete(g.rafts.rafts["otter-1"].Members, "otter-3")
+	req = answeredJoinRequest(t, g, invitee, "otter-1")
+	req.Invite = token
+	if _, err := g.RequestJoin(context.Background(), req); !errors.Is(err, ErrInvalidInvite) {
+		t.Errorf("reused invite: expected ErrInvalidInvite, got %v", err)
+	}
+}
+
+func TestRequestJoin_InvalidInvite(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	addPeers(g, "otter-2")
+	outsider := newTestGovernance("otter-9")
+	invitee := newTestGovernance("otter-3")
+
+	tests := []struct {
+		name   string
+		invite func() (*Invite, error)
+	}{
+		{"other invitee", func() (*Invite, error) { return NewInvite(g.crypto, "otter-1", "otter-1", "otter-4", 0) }},
+		{"other raft", func() (*Invite, error) { return NewInvite(g.crypto, "raft-2", "otter-1", "", 0) }},
+		{"not a member", func() (*Invite, error) { return NewInvite(outsider.crypto, "otter-1", "otter-9", "", 0) }},
+		{"signed by someone else", func() (*Invite, error) { return NewInvite(outsider.crypto, "otter-1", "otter-2", "", 0) }},
+		{"expired", func() (*Invite, error) {
+			invite, err := NewInvite(g.crypto, "otter-1", "otter-1", "", time.Second)
+			if err == nil {
+				invite.ExpiresAt = time.Now().Add(-time.Minute)
+				invite.Signature, err = g.crypto.Sign(inviteSigningPayload(invite))
+			}
+			return invite, err
+		}},
+		{"tampered", func() (*Invite, error) {
+			invite, err := NewInvite(g.crypto, "otter-1", "otter-1", "otter-4", 0)
+			if err == nil {
+				invite.InviteeID = "otter-3"
+			}
+			return invite, err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invite, err := tt.invite()
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := answeredJoinRequest(t, g, invitee, "otter-1")
+			if req.Invite, err = invite.Token(); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := g.RequestJoin(context.Background(), req); !errors.Is(err, ErrInvalidInvite) {
+				t.Errorf("expected ErrInvalidInvite, got %v", err)
+			}
+		})
+	}
+
+	req := answeredJoinRequest(t, g, invitee, "otter-1")
+	req.Invite = "not a token"
+	if _, err := g.RequestJoin(context.Background(), req); !errors.Is(err, ErrInvalidInvite) {
+		t.Errorf("garbled token: expected ErrInvalidInvite, got %v", err)
+	}
+	if _, exists := g.rafts.rafts["otter-1"].Members["otter-3"]; exists {
+		t.Error("otter-3 should not have been inducted")
+	}
+}
+
+func TestRequestJoin_InviteUsedAcrossRestart(t *testing.T) {
+	dir := t.TempDir()
+	db, err := vectordb.NewSQLiteVectorDB(filepath.Join(dir, "otter.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	g, err := New(RaftConfig{ID: "otter-1", DataDir: dir}, memory.New(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	invite, err := g.CreateInvite("otter-1", "", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := invite.Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := answeredJoinRequest(t, g, newTestGovernance("otter-3"), "otter-1")
+	req.Invite = token
+	if _, err := g.RequestJoin(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	g.Shutdown(ctx)
+
+	reloaded, err := New(RaftConfig{ID: "otter-1", DataDir: dir}, memory.New(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reloaded.Shutdown(ctx)
+	req = answeredJoinRequest(t, reloaded, newTestGovernance("otter-4"), "otter-1")
+	req.Invite = token
+	if _, err := reloaded.RequestJoin(ctx, req); !errors.Is(err, ErrInvalidInvite) {
+		t.Errorf("replayed invite after restart: expected ErrInvalidInvite, got %v", err)
+	}
+}
+
+func TestCreateInvite(t *testing.T) {
+	g := newTestGovernance("otter-1")
+
+	invite, err := g.CreateInvite("otter-1", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ttl := time.Until(invite.ExpiresAt); ttl < DefaultInviteTTL-time.Minute || ttl > DefaultInviteTTL {
+		t.Errorf("expires in %v, want about %v", ttl, DefaultInviteTTL)
+	}
+	token, _ := invite.Token()
+	parsed, err := ParseInvite(token)
+	if err != nil || parsed.InviteID != invite.InviteID || parsed.InviterID != "otter-1" {
+		t.Errorf("ParseInvite() = %+v, %v", parsed, err)
+	}
+
+	if _, err := g.CreateInvite("missing", "", 0); !errors.Is(err, ErrRaftNotFound) {
+		t.Errorf("expected ErrRaftNotFound, got %v", err)
+	}
+	if _, err := g.CreateInvite("otter-1", "", MaxInviteTTL+time.Hour); err == nil {
+		t.Error("expected error for a TTL over the maximum")
+	}
+}
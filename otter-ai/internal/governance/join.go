@@ -0,0 +1,235 @@
+package governance
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"otter-ai/internal/errs"
+)
+
+const (
+	// JoinChallengeTTL bounds how long a join challenge can be answered
+	JoinChallengeTTL = 2 * time.Minute
+	// MaxPendingJoinChallenges bounds the challenges awaiting an answer
+	MaxPendingJoinChallenges = 1024
+)
+
+// ErrJoinChallenge is returned when a join request does not prove
+// possession of the signing key it presents
+var ErrJoinChallenge = errs.New(errs.ErrUnauthorized, "join challenge failed")
+
+// JoinChallenge is a one-time nonce a requester signs with its Ed25519 key
+// to prove it holds the key it asks to be inducted with
+type JoinChallenge struct {
+	RaftID      string    `json:"raft_id"`
+	RequesterID string    `json:"requester_id"`
+	Nonce       string    `json:"nonce"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// joinChallenges holds the challenges issued and not yet answered
+type joinChallenges struct {
+	pending map[string]*JoinChallenge // nonce -> challenge
+	mu      sync.Mutex
+}
+
+func (g *Governance) joinChallengeRegistry() *joinChallenges {
+	g.challengesOnce.Do(func() {
+		if g.challenges == nil {
+			g.challenges = &joinChallenges{pending: make(map[string]*JoinChallenge)}
+		}
+	})
+	return g.challenges
+}
+
+// IssueJoinChallenge returns a nonce the requester must sign before it can
+// join the raft. Each nonce is answerable once, within JoinChallengeTTL.
+func (g *Governance) IssueJoinChallenge(raftID, requesterID string) (*JoinChallenge, error) {
+	if requesterID == "" {
+		return nil, fmt.Errorf("requester_id is required")
+	}
+	if _, err := g.liveRaft(raftID); err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, 32)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	now := time.Now()
+	challenge := &JoinChallenge{
+		RaftID:      raftID,
+		RequesterID: requesterID,
+		Nonce:       hex.EncodeToString(nonce),
+		ExpiresAt:   now.Add(JoinChallengeTTL),
+	}
+
+	registry := g.joinChallengeRegistry()
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	for key, pending := range registry.pending {
+		if now.After(pending.ExpiresAt) {
+			delete(registry.pending, key)
+		}
+	}
+	if len(registry.pending) >= MaxPendingJoinChallenges {
+		return nil, fmt.Errorf("too many pending join challenges")
+	}
+	registry.pending[challenge.Nonce] = challenge
+
+	copied := *challenge
+	return &copied, nil
+}
+
+// SignJoinChallenge answers a join challenge, binding the nonce to this
+// otter's public keys
+func (cs *CryptoSystem) SignJoinChallenge(challenge *JoinChallenge) ([]byte, error) {
+	return cs.Sign(joinChallengePayload(challenge.RaftID, challenge.RequesterID, challenge.Nonce, cs.GetPublicKey(), cs.GetSigningPublicKey()))
+}
+
+// verifyJoinChallenge consumes the challenge a join request answers and
+// checks its signature against the signing key the requester presents
+func (g *Governance) verifyJoinChallenge(req *JoinRequest) error {
+	if len(req.SigningKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("%w: a signing key is required", ErrJoinChallenge)
+	}
+	if req.Nonce == "" || len(req.ChallengeSignature) == 0 {
+		return fmt.Errorf("%w: request a challenge and sign it before joining", ErrJoinChallenge)
+	}
+
+	registry := g.joinChallengeRegistry()
+	registry.mu.Lock()
+	challenge, ok := registry.pending[req.Nonce]
+	delete(registry.pending, req.Nonce)
+	registry.mu.Unlock()
+
+	if !ok || time.Now().After(challenge.ExpiresAt) {
+		return fmt.Errorf("%w: unknown or expired nonce", ErrJoinChallenge)
+	}
+	if challenge.RaftID != req.RaftID || challenge.RequesterID != req.RequesterID {
+		return fmt.Errorf("%w: nonce was issued for %s joining raft %s", ErrJoinChallenge, challenge.RequesterID, challenge.RaftID)
+	}
+
+	payload := joinChallengePayload(req.RaftID, req.RequesterID, req.Nonce, req.PublicKey, req.SigningKey)
+	if !VerifySignature(payload, req.ChallengeSignature, req.SigningKey) {
+		return fmt.Errorf("%w: %v", ErrJoinChallenge, ErrInvalidSignature)
+	}
+	return nil
+}
+
+// fetchJoinChallenge asks the inducting otter for a join challenge
+func (g *Governance) fetchJoinChallenge(ctx context.Context, endpoint, raftID string) (*JoinChallenge, error) {
+	body, err := json.Marshal(map[string]string{"raft_id": raftID, "requester_id": g.config.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal join challenge request: %w", err)
+	}
+
+	target := strings.TrimRight(endpoint, "/") + "/api/v1/governance/join/challenge"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create join challenge request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: GovernanceHTTPTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request join challenge: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read join challenge: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("join challenge refused (%d): %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var challenge JoinChallenge
+	if err := json.Unmarshal(respBody, &challenge); err != nil {
+		return nil, fmt.Errorf("failed to parse join challenge: %w", err)
+	}
+	if challenge.RaftID != raftID || challenge.RequesterID != g.config.ID || challenge.Nonce == "" {
+		return nil, fmt.Errorf("join challenge does not match the request")
+	}
+	return &challenge, nil
+}
+
+// MemberKey is a raft member's signing key, hex encoded, as GET
+// /api/v1/governance/rafts/{id}/keys lists it
+type MemberKey struct {
+	ID         string `json:"id"`
+	SigningKey string `json:"signing_key"`
+}
+
+// MemberKeys lists the signing keys of a raft's members, ordered by ID, so
+// otters checking the raft's rules can verify who signed them
+func (g *Governance) MemberKeys(raftID string) ([]MemberKey, error) {
+	g.rafts.mu.RLock()
+	raft, exists := g.rafts.rafts[raftID]
+	g.rafts.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrRaftNotFound, raftID)
+	}
+
+	raft.mu.RLock()
+	keys := make([]MemberKey, 0, len(raft.Members))
+	for _, member := range raft.Members {
+		if len(member.SigningKey) > 0 {
+			keys = append(keys, MemberKey{ID: member.ID, SigningKey: hex.EncodeToString(member.SigningKey)})
+		}
+	}
+	raft.mu.RUnlock()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+	return keys, nil
+}
+
+// verifyFetchedRules checks the rules a raft's otter sent against the
+// signing keys of the raft's members. The keys are fetched from the same
+// otter but must be signed by the inducting otter, whose key the join
+// handshake authenticated; this otter's own key covers rules it signed,
+// such as a negotiated compromise.
+func (g *Governance) verifyFetchedRules(ctx context.Context, endpoint, raftID string, rules map[string]*Rule, inductor *Member) error {
+	if inductor == nil || len(inductor.SigningKey) == 0 {
+		return fmt.Errorf("%w: raft %s was joined through an otter that did not identify itself", ErrUnsigned, raftID)
+	}
+	members, err := g.fetchRaftSigningKeys(ctx, endpoint, raftID, inductor)
+	if err != nil {
+		return fmt.Errorf("rejecting rules from %s: %w", inductor.ID, err)
+	}
+	members[inductor.ID] = inductor
+	members[g.config.ID] = &Member{ID: g.config.ID, SigningKey: g.crypto.GetSigningPublicKey()}
+	return g.verifyReceivedRules(ctx, rules, members, inductor.ID)
+}
+
+// fetchRaftSigningKeys asks a raft's otter for its members' signing keys,
+// in a response signer must have signed
+func (g *Governance) fetchRaftSigningKeys(ctx context.Context, endpoint, raftID string, signer *Member) (map[string]*Member, error) {
+	var listed []MemberKey
+	if err := getPeerJSON(ctx, endpoint, "/api/v1/governance/rafts/"+url.PathEscape(raftID)+"/keys", signer, &listed); err != nil {
+		return nil, fmt.Errorf("failed to fetch the members' signing keys: %w", err)
+	}
+	members := make(map[string]*Member, len(listed))
+	for _, m := range listed {
+		key, err := hex.DecodeString(m.SigningKey)
+		if err != nil {
+			return nil, fmt.Errorf("member %s has an unreadable signing key: %w", m.ID, err)
+		}
+		members[m.ID] = &Member{ID: m.ID, SigningKey: key}
+	}
+	return members, nil
+}
@@ -0,0 +1,107 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// answeredJoinRequest returns a join request from peer that answers a
+// fresh challenge issued by g
+func answeredJoinRequest(t *testing.T, g, peer *Governance, raftID string) JoinRequest {
+	t.Helper()
+	challenge, err := g.IssueJoinChallenge(raftID, peer.config.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := peer.crypto.SignJoinChallenge(challenge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return JoinRequest{
+		RaftID:             raftID,
+		RequesterID:        peer.config.ID,
+		PublicKey:          peer.crypto.GetPublicKey(),
+		SigningKey:         peer.crypto.GetSigningPublicKey(),
+		Nonce:              challenge.Nonce,
+		ChallengeSignature: sig,
+	}
+}
+
+func TestRequestJoin_ChallengeAnswered(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	peer := newTestGovernance("otter-2")
+	ctx := context.Background()
+
+	req := answeredJoinRequest(t, g, peer, "otter-1")
+	if _, err := g.RequestJoin(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if _, exists := g.rafts.rafts["otter-1"].Members["otter-2"]; !exists {
+		t.Fatal("otter-2 not added as member")
+	}
+
+	// Each nonce is answerable once
+	if _, err := g.RequestJoin(ctx, req); !errors.Is(err, ErrJoinChallenge) {
+		t.Errorf("replayed answer: expected ErrJoinChallenge, got %v", err)
+	}
+}
+
+func TestRequestJoin_ChallengeRejected(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	peer := newTestGovernance("otter-2")
+	impostor := newTestGovernance("otter-3")
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		tamper func(*JoinRequest)
+	}{
+		{"no answer", func(req *JoinRequest) { req.Nonce, req.ChallengeSignature = "", nil }},
+		{"no signing key", func(req *JoinRequest) { req.SigningKey = nil }},
+		{"unknown nonce", func(req *JoinRequest) { req.Nonce = "feed" }},
+		{"other requester", func(req *JoinRequest) { req.RequesterID = "otter-3" }},
+		{"key not held", func(req *JoinRequest) { req.SigningKey = impostor.crypto.GetSigningPublicKey() }},
+		{"other encryption key", func(req *JoinRequest) { req.PublicKey = impostor.crypto.GetPublicKey() }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := answeredJoinRequest(t, g, peer, "otter-1")
+			tt.tamper(&req)
+			if _, err := g.RequestJoin(ctx, req); !errors.Is(err, ErrJoinChallenge) {
+				t.Errorf("expected ErrJoinChallenge, got %v", err)
+			}
+		})
+	}
+	if len(g.rafts.rafts["otter-1"].Members) != 1 {
+		t.Error("no requester should have been inducted")
+	}
+}
+
+func TestRequestJoin_ChallengeExpired(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	peer := newTestGovernance("otter-2")
+
+	req := answeredJoinRequest(t, g, peer, "otter-1")
+	g.challenges.pending[req.Nonce].ExpiresAt = time.Now().Add(-time.Second)
+	if _, err := g.RequestJoin(context.Background(), req); !errors.Is(err, ErrJoinChallenge) {
+		t.Errorf("expected ErrJoinChallenge, got %v", err)
+	}
+}
+
+func TestIssueJoinChallenge_Validation(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	if _, err := g.IssueJoinChallenge("missing", "otter-2"); !errors.Is(err, ErrRaftNotFound) {
+		t.Errorf("expected ErrRaftNotFound, got %v", err)
+	}
+	if _, err := g.IssueJoinChallenge("otter-1", ""); err == nil {
+		t.Error("expected error without a requester")
+	}
+
+	a, _ := g.IssueJoinChallenge("otter-1", "otter-2")
+	b, _ := g.IssueJoinChallenge("otter-1", "otter-2")
+	if a.Nonce == b.Nonce || len(a.Nonce) != 64 {
+		t.Errorf("nonces %q and %q, want distinct 32-byte values", a.Nonce, b.Nonce)
+	}
+}
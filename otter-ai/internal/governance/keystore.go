@@ -2,20 +2,40 @@ package governance
 
 import (
 	"crypto/ecdh"
+	"crypto/ed25519"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+)
+
+// Key file names within the data directory
+const (
+	KeyFile        = "otter.key"      // ECDH P-256 private key (hex)
+	SigningKeyFile = "otter.sign.key" // Ed25519 seed (hex)
+	RolloverFile   = "otter.rollovers.json"
+	RetiredKeyDir  = "retired-keys" // Signing seeds replaced by `keytool rotate`
 )
 
 // LoadOrGenerateKeys loads keys from disk or generates new ones
 func LoadOrGenerateKeys(dataDir string) (*CryptoSystem, error) {
-	keyPath := filepath.Join(dataDir, "otter.key")
+	keyPath := filepath.Join(dataDir, KeyFile)
+	signingKeyPath := filepath.Join(dataDir, SigningKeyFile)
 
 	// Try to load existing key
 	if data, err := os.ReadFile(keyPath); err == nil {
-		return loadCryptoSystemFromBytes(data)
+		cs, err := loadCryptoSystemFromBytes(data)
+		if err != nil {
+			return nil, err
+		}
+		if err := loadOrGenerateSigningKey(signingKeyPath, cs); err != nil {
+			return nil, err
+		}
+		return cs, nil
 	}
 
 	// Generate new key
@@ -28,19 +48,19 @@ func LoadOrGenerateKeys(dataDir string) (*CryptoSystem, error) {
 	if err := savePrivateKey(keyPath, cs); err != nil {
 		return nil, fmt.Errorf("failed to save key: %w", err)
 	}
+	if err := saveSigningKey(signingKeyPath, cs); err != nil {
+		return nil, fmt.Errorf("failed to save signing key: %w", err)
+	}
 
 	return cs, nil
 }
 
 // loadCryptoSystemFromBytes loads a CryptoSystem from private key bytes
 func loadCryptoSystemFromBytes(data []byte) (*CryptoSystem, error) {
-	// Decode hex if necessary
+	// Keys are written as hex; fall back to raw bytes for older key files
 	keyBytes := data
-	if len(data) > 0 && data[0] != 0x30 { // Not DER format, try hex
-		decoded, err := hex.DecodeString(string(data))
-		if err == nil {
-			keyBytes = decoded
-		}
+	if decoded, err := hex.DecodeString(strings.TrimSpace(string(data))); err == nil {
+		keyBytes = decoded
 	}
 
 	curve := ecdh.P256()
@@ -56,6 +76,38 @@ func loadCryptoSystemFromBytes(data []byte) (*CryptoSystem, error) {
 	}, nil
 }
 
+// loadOrGenerateSigningKey attaches the Ed25519 signing key, creating one
+// for data directories that predate signing keys.
+func loadOrGenerateSigningKey(path string, cs *CryptoSystem) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		_, signingKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return fmt.Errorf("failed to generate signing key: %w", err)
+		}
+		cs.setSigningKey(signingKey)
+		if err := saveSigningKey(path, cs); err != nil {
+			return fmt.Errorf("failed to save signing key: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read signing key: %w", err)
+	}
+
+	seed, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return fmt.Errorf("failed to parse signing key")
+	}
+	cs.setSigningKey(ed25519.NewKeyFromSeed(seed))
+	return nil
+}
+
+func (cs *CryptoSystem) setSigningKey(key ed25519.PrivateKey) {
+	cs.signingKey = key
+	cs.verifyKey = key.Public().(ed25519.PublicKey)
+}
+
 // savePrivateKey saves the private key to disk
 func savePrivateKey(path string, cs *CryptoSystem) error {
 	// Ensure directory exists
@@ -70,11 +122,26 @@ func savePrivateKey(path string, cs *CryptoSystem) error {
 	return os.WriteFile(path, []byte(keyHex), 0600)
 }
 
+// saveSigningKey saves the Ed25519 seed to disk
+func saveSigningKey(path string, cs *CryptoSystem) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	seedHex := hex.EncodeToString(cs.signingKey.Seed())
+	return os.WriteFile(path, []byte(seedHex), 0600)
+}
+
 // ExportPublicKey exports the public key as hex string
 func ExportPublicKey(cs *CryptoSystem) string {
 	return hex.EncodeToString(cs.GetPublicKey())
 }
 
+// ExportSigningPublicKey exports the Ed25519 public key as hex string
+func ExportSigningPublicKey(cs *CryptoSystem) string {
+	return hex.EncodeToString(cs.GetSigningPublicKey())
+}
+
 // ImportPublicKey imports a public key from hex string
 func ImportPublicKey(hexKey string) ([]byte, error) {
 	return hex.DecodeString(hexKey)
@@ -95,32 +162,96 @@ func NewCryptoSystemFromSeed(seed []byte) (*CryptoSystem, error) {
 		return nil, fmt.Errorf("failed to create private key from seed: %w", err)
 	}
 
-	return &CryptoSystem{
+	cs := &CryptoSystem{
 		privateKey: privateKey,
 		publicKey:  privateKey.PublicKey(),
 		curve:      curve,
-	}, nil
+	}
+
+	// Derive a separate signing seed so the two keys are not identical
+	signingSeed := sha256.Sum256(append([]byte("otter-ai-signing:"), seed[:32]...))
+	cs.setSigningKey(ed25519.NewKeyFromSeed(signingSeed[:]))
+
+	return cs, nil
 }
 
 // RegenerateKeys generates a new key pair (use with caution!)
 func RegenerateKeys(dataDir string) (*CryptoSystem, error) {
-	cs := &CryptoSystem{
-		curve: ecdh.P256(),
-	}
-
-	privateKey, err := cs.curve.GenerateKey(rand.Reader)
+	cs, err := NewCryptoSystem()
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate key: %w", err)
 	}
 
-	cs.privateKey = privateKey
-	cs.publicKey = privateKey.PublicKey()
-
-	// Save the new key
-	keyPath := filepath.Join(dataDir, "otter.key")
+	// Save the new keys
+	keyPath := filepath.Join(dataDir, KeyFile)
 	if err := savePrivateKey(keyPath, cs); err != nil {
 		return nil, fmt.Errorf("failed to save new key: %w", err)
 	}
+	if err := saveSigningKey(filepath.Join(dataDir, SigningKeyFile), cs); err != nil {
+		return nil, fmt.Errorf("failed to save new signing key: %w", err)
+	}
 
 	return cs, nil
 }
+
+// RotateKeys replaces the signing key with a new one, keeping the
+// encryption key. The old seed is kept under RetiredKeyDir, and a rollover
+// signed by the old key is appended to RolloverFile so that the otter can
+// prove the change to its rafts.
+func RotateKeys(dataDir, otterID string) (*CryptoSystem, *KeyRollover, error) {
+	signingKeyPath := filepath.Join(dataDir, SigningKeyFile)
+	if _, err := os.Stat(signingKeyPath); err != nil {
+		return nil, nil, fmt.Errorf("no signing key to rotate: %w", err)
+	}
+	cs, err := LoadOrGenerateKeys(dataDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	rollovers, err := LoadKeyRollovers(dataDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, signingKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	rollover, err := signKeyRollover(cs, otterID, signingKey.Public().(ed25519.PublicKey))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	retiredPath := filepath.Join(dataDir, RetiredKeyDir, ExportSigningPublicKey(cs)[:16]+".sign.key")
+	if err := saveSigningKey(retiredPath, cs); err != nil {
+		return nil, nil, fmt.Errorf("failed to keep retired signing key: %w", err)
+	}
+	data, err := json.MarshalIndent(append(rollovers, *rollover), "", "  ")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal key rollovers: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dataDir, RolloverFile), data, 0600); err != nil {
+		return nil, nil, fmt.Errorf("failed to save key rollover: %w", err)
+	}
+
+	cs.setSigningKey(signingKey)
+	if err := saveSigningKey(signingKeyPath, cs); err != nil {
+		return nil, nil, fmt.Errorf("failed to save new signing key: %w", err)
+	}
+	return cs, rollover, nil
+}
+
+// LoadKeyRollovers reads the data directory's key rollovers, oldest first
+func LoadKeyRollovers(dataDir string) ([]KeyRollover, error) {
+	data, err := os.ReadFile(filepath.Join(dataDir, RolloverFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key rollovers: %w", err)
+	}
+	var rollovers []KeyRollover
+	if err := json.Unmarshal(data, &rollovers); err != nil {
+		return nil, fmt.Errorf("failed to parse key rollovers: %w", err)
+	}
+	return rollovers, nil
+}
@@ -41,6 +41,32 @@ func TestLoadOrGenerateKeys_LoadExisting(t *testing.T) {
 	if hex.EncodeToString(pub1) != hex.EncodeToString(pub2) {
 		t.Error("reloaded key should match original")
 	}
+	if hex.EncodeToString(cs1.GetSigningPublicKey()) != hex.EncodeToString(cs2.GetSigningPublicKey()) {
+		t.Error("reloaded signing key should match original")
+	}
+}
+
+func TestLoadOrGenerateKeys_AddsSigningKeyToLegacyDir(t *testing.T) {
+	dir := t.TempDir()
+	cs1, _ := LoadOrGenerateKeys(dir)
+	os.Remove(filepath.Join(dir, SigningKeyFile))
+
+	cs2, err := LoadOrGenerateKeys(dir)
+	if err != nil {
+		t.Fatalf("LoadOrGenerateKeys: %v", err)
+	}
+	if hex.EncodeToString(cs1.GetPublicKey()) != hex.EncodeToString(cs2.GetPublicKey()) {
+		t.Error("ECDH key should be preserved")
+	}
+	if _, err := os.Stat(filepath.Join(dir, SigningKeyFile)); err != nil {
+		t.Error("signing key file not created")
+	}
+
+	msg := []byte("rule")
+	sig, _ := cs2.Sign(msg)
+	if !VerifySignature(msg, sig, cs2.GetSigningPublicKey()) {
+		t.Error("new signing key should produce valid signatures")
+	}
 }
 
 func TestExportPublicKey(t *testing.T) {
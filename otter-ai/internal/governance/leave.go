@@ -0,0 +1,148 @@
+package governance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"otter-ai/internal/errs"
+	"otter-ai/internal/events"
+)
+
+// MessageMemberLeft tells a raft's members that the sender left it
+const MessageMemberLeft = "member.left"
+
+// ErrNotMember is returned when this otter is not a member of a raft
+var ErrNotMember = errs.New(errs.ErrConflict, "not a member of the raft")
+
+// LeaveRules decides what happens to a raft's rules when this otter leaves it
+type LeaveRules string
+
+const (
+	// LeaveRulesDrop lets the raft's rules lapse
+	LeaveRulesDrop LeaveRules = "drop"
+	// LeaveRulesKeep proposes the raft's rules to this otter's own raft,
+	// for scopes it has no rule of its own in, so they live on as
+	// personal rules
+	LeaveRulesKeep LeaveRules = "keep"
+)
+
+// MemberDeparture is the payload of a member.left message
+type MemberDeparture struct {
+	RaftID   string    `json:"raft_id"`
+	MemberID string    `json:"member_id"`
+	LeftAt   time.Time `json:"left_at"`
+}
+
+// LeaveRaft takes this otter out of a raft it joined: its membership moves
+// to StateLeft, the raft's other members are told, and the raft is
+// archived, which takes its rules out of the active set. With
+// LeaveRulesKeep the rules are first proposed to this otter's own raft.
+// Leaving a raft with no other active member dissolves it.
+func (g *Governance) LeaveRaft(ctx context.Context, raftID string) error {
+	if raftID == g.config.ID {
+		return fmt.Errorf("cannot leave this otter's own raft")
+	}
+	raft, err := g.liveRaft(raftID)
+	if err != nil {
+		return err
+	}
+
+	raft.mu.RLock()
+	self, exists := raft.Members[g.config.ID]
+	left := exists && (self.State == StateLeft || self.State == StateRevoked || self.State == StateRejected)
+	remaining := 0
+	for id, member := range raft.Members {
+		if id != g.config.ID && member.State == StateActive {
+			remaining++
+		}
+	}
+	raft.mu.RUnlock()
+	if !exists || left {
+		return fmt.Errorf("%w: %s", ErrNotMember, raftID)
+	}
+
+	if g.leaveRules() == LeaveRulesKeep {
+		g.keepRulesAsPersonal(ctx, raftID)
+	}
+
+	// Tell the peers while they are still reachable through the raft
+	departure := MemberDeparture{RaftID: raftID, MemberID: g.config.ID, LeftAt: time.Now()}
+	g.broadcast(ctx, raftID, MessageMemberLeft, departure, g.membersInState(raftID, StateInactive)...)
+
+	raft.mu.Lock()
+	self.State = StateLeft
+	raft.mu.Unlock()
+	g.recordAudit(AuditMemberStateChanged, raftID, g.config.ID, g.config.ID, MemberAudit{MemberID: g.config.ID, State: StateLeft})
+	g.publish(events.MemberLeft, MemberEvent{RaftID: raftID, MemberID: g.config.ID, State: StateLeft})
+
+	reason := "left the raft"
+	if remaining == 0 {
+		reason = "dissolved: the last member left"
+	}
+	g.log().InfoContext(ctx, "left raft", "raft_id", raftID, "reason", reason, "rules", string(g.leaveRules()))
+	return g.ArchiveRaft(ctx, raftID, g.config.ID, reason)
+}
+
+// leaveRules returns how rules are handled on leaving, defaulting to drop
+func (g *Governance) leaveRules() LeaveRules {
+	if g.config.LeaveRules == "" {
+		return LeaveRulesDrop
+	}
+	return g.config.LeaveRules
+}
+
+// keepRulesAsPersonal proposes a raft's active rules to this otter's own
+// raft, skipping scopes the own raft already has a rule in. A solo own raft
+// adopts them straight away on this otter's vote.
+func (g *Governance) keepRulesAsPersonal(ctx context.Context, raftID string) {
+	own := g.GetActiveRulesForRaft(g.config.ID)
+	for _, rule := range sortedRules(g.GetActiveRulesForRaft(raftID)) {
+		if own[rule.Scope] != nil {
+			continue
+		}
+		proposal, err := g.ProposeRule(ctx, g.config.ID, &Rule{Scope: rule.Scope, Body: rule.Body, ProposedBy: g.config.ID})
+		if err != nil {
+			g.log().WarnContext(ctx, "failed to keep rule from left raft", "raft_id", raftID, "rule_id", rule.RuleID, "error", err)
+			continue
+		}
+		if err := g.CastVote(ctx, proposal.ProposalID, VoteYes); err != nil {
+			g.log().WarnContext(ctx, "failed to vote for kept rule", "raft_id", raftID, "proposal_id", proposal.ProposalID, "error", err)
+		}
+	}
+}
+
+// applyRemoteDeparture records that a peer left the raft. Only the member
+// itself can announce its departure; open proposals are recounted without it.
+func (g *Governance) applyRemoteDeparture(ctx context.Context, env *Envelope, departure *MemberDeparture) error {
+	if departure.RaftID != env.RaftID {
+		return fmt.Errorf("departure from raft %s sent in an envelope for raft %s", departure.RaftID, env.RaftID)
+	}
+	if departure.MemberID != env.SenderID {
+		return fmt.Errorf("%w: %s cannot announce %s leaving", ErrUnknownSender, env.SenderID, departure.MemberID)
+	}
+
+	raft, err := g.liveRaft(env.RaftID)
+	if err != nil {
+		return err
+	}
+	raft.mu.Lock()
+	member, exists := raft.Members[departure.MemberID]
+	if !exists || member.State == StateLeft {
+		raft.mu.Unlock()
+		return nil
+	}
+	member.State = StateLeft
+	raft.mu.Unlock()
+
+	g.log().InfoContext(ctx, "member left raft", "member_id", departure.MemberID, "raft_id", departure.RaftID)
+	g.publish(events.MemberLeft, MemberEvent{RaftID: departure.RaftID, MemberID: departure.MemberID, State: StateLeft})
+	g.recordAudit(AuditMemberStateChanged, departure.RaftID, departure.MemberID, env.SenderID,
+		MemberAudit{MemberID: departure.MemberID, State: StateLeft})
+	if err := g.saveRaft(ctx, raft); err != nil {
+		g.log().WarnContext(ctx, "failed to persist departure", "member_id", departure.MemberID, "raft_id", departure.RaftID, "error", err)
+	}
+
+	g.recountProposals(departure.RaftID)
+	return nil
+}
@@ -0,0 +1,96 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// leavingPair makes otter-2 a member of otter-1's raft, where it follows an
+// adopted safety rule
+func leavingPair(t *testing.T) (origin, leaver *Governance) {
+	t.Helper()
+	origin = newTestGovernance("otter-1")
+	leaver = newTestGovernance("otter-2")
+	sharedRaft(origin, leaver)
+	leaver.activateRule(&Rule{RuleID: "r-safety", RaftID: "otter-1", Scope: "safety", Body: "no spam", Timestamp: time.Now()})
+	return origin, leaver
+}
+
+func TestLeaveRaft_DropsRules(t *testing.T) {
+	origin, leaver := leavingPair(t)
+
+	if err := leaver.LeaveRaft(context.Background(), "otter-1"); err != nil {
+		t.Fatalf("LeaveRaft: %v", err)
+	}
+
+	raft := leaver.rafts.rafts["otter-1"]
+	if raft.Members["otter-2"].State != StateLeft {
+		t.Errorf("own membership = %s, want left", raft.Members["otter-2"].State)
+	}
+	if raft.Archive == nil || raft.Archive.Reason != "left the raft" {
+		t.Errorf("archive = %+v", raft.Archive)
+	}
+	if rules := leaver.GetActiveRulesForRaft("otter-1"); len(rules) != 0 {
+		t.Errorf("expected the raft's rules to be dropped, got %v", rules)
+	}
+	if rules := leaver.GetActiveRulesForRaft("otter-2"); len(rules) != 0 {
+		t.Errorf("expected no personal rules in drop mode, got %v", rules)
+	}
+
+	// The raft's other member heard about it
+	if state := origin.rafts.rafts["otter-1"].Members["otter-2"].State; state != StateLeft {
+		t.Errorf("origin sees otter-2 as %s, want left", state)
+	}
+}
+
+func TestLeaveRaft_KeepsRulesAsPersonal(t *testing.T) {
+	_, leaver := leavingPair(t)
+	leaver.config.LeaveRules = LeaveRulesKeep
+	leaver.activateRule(&Rule{RuleID: "r-tone", RaftID: "otter-1", Scope: "tone", Body: "be formal", Timestamp: time.Now()})
+	leaver.activateRule(&Rule{RuleID: "r-own", RaftID: "otter-2", Scope: "tone", Body: "be casual", Timestamp: time.Now()})
+
+	if err := leaver.LeaveRaft(context.Background(), "otter-1"); err != nil {
+		t.Fatalf("LeaveRaft: %v", err)
+	}
+
+	own := leaver.GetActiveRulesForRaft("otter-2")
+	if own["safety"] == nil || own["safety"].Body != "no spam" {
+		t.Errorf("expected the safety rule to be kept as personal, got %v", own)
+	}
+	if own["tone"] == nil || own["tone"].Body != "be casual" {
+		t.Errorf("an existing personal rule was replaced: %v", own["tone"])
+	}
+}
+
+func TestLeaveRaft_Refused(t *testing.T) {
+	_, leaver := leavingPair(t)
+	ctx := context.Background()
+
+	if err := leaver.LeaveRaft(ctx, "otter-2"); err == nil {
+		t.Error("expected leaving the own raft to fail")
+	}
+	if err := leaver.LeaveRaft(ctx, "missing"); !errors.Is(err, ErrRaftNotFound) {
+		t.Errorf("missing raft error = %v", err)
+	}
+
+	leaver.rafts.rafts["otter-1"].Members["otter-2"].State = StateRevoked
+	if err := leaver.LeaveRaft(ctx, "otter-1"); !errors.Is(err, ErrNotMember) {
+		t.Errorf("revoked member error = %v", err)
+	}
+}
+
+func TestApplyRemoteDeparture_OnlyFromTheMember(t *testing.T) {
+	origin, leaver := leavingPair(t)
+	addPeers(origin, "otter-3")
+
+	forged := &Envelope{Type: MessageMemberLeft, RaftID: "otter-1", SenderID: leaver.config.ID}
+	departure := &MemberDeparture{RaftID: "otter-1", MemberID: "otter-3", LeftAt: time.Now()}
+	if err := origin.applyRemoteDeparture(context.Background(), forged, departure); !errors.Is(err, ErrUnknownSender) {
+		t.Errorf("forged departure error = %v", err)
+	}
+	if state := origin.rafts.rafts["otter-1"].Members["otter-3"].State; state != StateActive {
+		t.Errorf("otter-3 = %s after a forged departure", state)
+	}
+}
@@ -0,0 +1,107 @@
+package governance
+
+import (
+	"sort"
+	"time"
+)
+
+// RuleStatus filters rule listings
+type RuleStatus string
+
+const (
+	RuleStatusActive   RuleStatus = "active"   // Rules currently in force
+	RuleStatusInactive RuleStatus = "inactive" // Superseded, overridden or archived rules
+	RuleStatusAll      RuleStatus = "all"
+)
+
+// RuleFilter selects rules to list. Empty fields match every rule, except
+// Status, which defaults to active rules.
+type RuleFilter struct {
+	RaftID string
+	Status RuleStatus
+	Since  time.Time // Adopted at or after, inclusive
+}
+
+// ProposalFilter selects proposals to list. Empty fields match every
+// proposal.
+type ProposalFilter struct {
+	RaftID string
+	Status ProposalStatus
+	Since  time.Time // Proposed at or after, inclusive
+}
+
+// ruleAdoptedAt returns when a rule was adopted, falling back to its
+// timestamp for rules received without one
+func ruleAdoptedAt(rule *Rule) time.Time {
+	if rule.AdoptedAt != nil {
+		return *rule.AdoptedAt
+	}
+	return rule.Timestamp
+}
+
+// ListRules returns the rules matching filter, ordered by raft, scope,
+// version and ID
+func (g *Governance) ListRules(filter RuleFilter) []*Rule {
+	status := filter.Status
+	if status == "" {
+		status = RuleStatusActive
+	}
+
+	g.rules.mu.RLock()
+	var rules []*Rule
+	for _, rule := range g.rules.rules {
+		active := g.rules.active[activeKey(rule)] == rule
+		switch {
+		case status == RuleStatusActive && !active, status == RuleStatusInactive && active:
+			continue
+		case filter.RaftID != "" && rule.RaftID != filter.RaftID:
+			continue
+		case !filter.Since.IsZero() && ruleAdoptedAt(rule).Before(filter.Since):
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	g.rules.mu.RUnlock()
+
+	sort.Slice(rules, func(i, j int) bool {
+		a, b := rules[i], rules[j]
+		switch {
+		case a.RaftID != b.RaftID:
+			return a.RaftID < b.RaftID
+		case a.Scope != b.Scope:
+			return a.Scope < b.Scope
+		case a.Version != b.Version:
+			return a.Version < b.Version
+		}
+		return a.RuleID < b.RuleID
+	})
+	return rules
+}
+
+// ListProposals returns the proposals matching filter, newest first, with
+// proposals made at the same time ordered by ID
+func (g *Governance) ListProposals(filter ProposalFilter) []*Proposal {
+	g.proposals.mu.RLock()
+	var proposals []*Proposal
+	for _, proposal := range g.proposals.proposals {
+		switch {
+		case filter.Status != "" && proposal.Status != filter.Status:
+			continue
+		case filter.RaftID != "" && proposal.RaftID != filter.RaftID:
+			continue
+		case !filter.Since.IsZero() && proposal.ProposedAt.Before(filter.Since):
+			continue
+		}
+		proposals = append(proposals, proposal)
+	}
+	g.proposals.mu.RUnlock()
+
+	sort.Slice(proposals, func(i, j int) bool {
+		a, b := proposals[i], proposals[j]
+		if !a.ProposedAt.Equal(b.ProposedAt) {
+			return a.ProposedAt.After(b.ProposedAt)
+		}
+		return a.ProposalID < b.ProposalID
+	})
+	return proposals
+}
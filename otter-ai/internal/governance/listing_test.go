@@ -0,0 +1,54 @@
+package governance
+
+import (
+	"testing"
+	"time"
+)
+
+func TestListRules_Filters(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	old := time.Now().Add(-48 * time.Hour)
+	g.activateRule(&Rule{RuleID: "base", RaftID: "otter-1", Scope: "safety", Version: 1, Body: "be kind", AdoptedAt: &old})
+	g.activateRule(&Rule{RuleID: "override", RaftID: "otter-1", Scope: "safety", Version: 2, Body: "be very kind", BaseRuleID: "base", Timestamp: time.Now()})
+	g.activateRule(&Rule{RuleID: "peer", RaftID: "raft-2", Scope: "privacy", Version: 1, Body: "keep secrets", Timestamp: time.Now()})
+
+	ids := func(rules []*Rule) []string {
+		var out []string
+		for _, rule := range rules {
+			out = append(out, rule.RuleID)
+		}
+		return out
+	}
+
+	if got := ids(g.ListRules(RuleFilter{})); len(got) != 2 || got[0] != "override" || got[1] != "peer" {
+		t.Errorf("active rules = %v", got)
+	}
+	if got := ids(g.ListRules(RuleFilter{Status: RuleStatusInactive})); len(got) != 1 || got[0] != "base" {
+		t.Errorf("inactive rules = %v", got)
+	}
+	if got := ids(g.ListRules(RuleFilter{Status: RuleStatusAll, RaftID: "otter-1"})); len(got) != 2 || got[0] != "base" {
+		t.Errorf("all otter-1 rules = %v", got)
+	}
+	if got := ids(g.ListRules(RuleFilter{Status: RuleStatusAll, Since: time.Now().Add(-time.Hour)})); len(got) != 2 {
+		t.Errorf("rules adopted in the last hour = %v", got)
+	}
+}
+
+func TestListProposals_Filters(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	now := time.Now()
+	g.proposals.proposals["p1"] = &Proposal{ProposalID: "p1", RaftID: "otter-1", Status: ProposalClosed, ProposedAt: now.Add(-2 * time.Hour)}
+	g.proposals.proposals["p2"] = &Proposal{ProposalID: "p2", RaftID: "otter-1", Status: ProposalOpen, ProposedAt: now}
+	g.proposals.proposals["p3"] = &Proposal{ProposalID: "p3", RaftID: "raft-2", Status: ProposalOpen, ProposedAt: now}
+
+	got := g.ListProposals(ProposalFilter{})
+	if len(got) != 3 || got[0].ProposalID != "p2" || got[1].ProposalID != "p3" || got[2].ProposalID != "p1" {
+		t.Errorf("proposals are not newest first, then by ID: %v, %v, %v", got[0].ProposalID, got[1].ProposalID, got[2].ProposalID)
+	}
+	if got := g.ListProposals(ProposalFilter{RaftID: "otter-1", Status: ProposalOpen}); len(got) != 1 || got[0].ProposalID != "p2" {
+		t.Errorf("open otter-1 proposals = %+v", got)
+	}
+	if got := g.ListProposals(ProposalFilter{Since: now.Add(-time.Hour)}); len(got) != 2 {
+		t.Errorf("recent proposals = %d, want 2", len(got))
+	}
+}
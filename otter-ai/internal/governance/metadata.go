@@ -0,0 +1,237 @@
+package governance
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"otter-ai/internal/errs"
+)
+
+// MetadataScope is the scope of the rule that names and describes a raft.
+// Its adopted rule sets the raft's metadata.
+const MetadataScope = "governance/metadata"
+
+// Raft metadata limits
+const (
+	MaxRaftNameLength = 100
+	MaxRaftTextLength = 1000 // Description and purpose
+	MaxRaftTags       = 20
+	MaxRaftTagLength  = 50
+)
+
+// AuditRaftMetadataChanged records a solo raft's metadata edited directly
+// by its only member
+const AuditRaftMetadataChanged AuditAction = "raft.metadata_changed"
+
+// RaftMetadata describes a raft to the people using it; rafts are
+// otherwise known only by ID
+type RaftMetadata struct {
+	Name        string   `json:"name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Purpose     string   `json:"purpose,omitempty"` // What the raft exists to do
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// RaftMetadataAudit is the data of raft.metadata_changed audit entries
+type RaftMetadataAudit struct {
+	RaftID   string       `json:"raft_id"`
+	Metadata RaftMetadata `json:"metadata"`
+}
+
+// normalize trims the fields and lowercases, dedupes and sorts the tags
+func (m RaftMetadata) normalize() RaftMetadata {
+	m.Name = strings.TrimSpace(m.Name)
+	m.Description = strings.TrimSpace(m.Description)
+	m.Purpose = strings.TrimSpace(m.Purpose)
+	seen := make(map[string]bool)
+	var tags []string
+	for _, tag := range m.Tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+	m.Tags = tags
+	return m
+}
+
+// Validate checks the metadata's lengths. Fields are single lines, so the
+// metadata can be written as a rule body.
+func (m RaftMetadata) Validate() error {
+	if len(m.Name) > MaxRaftNameLength {
+		return errs.Errorf(errs.ErrInvalid, "name too long (max %d characters)", MaxRaftNameLength)
+	}
+	for _, f := range []struct{ name, value string }{
+		{"name", m.Name}, {"description", m.Description}, {"purpose", m.Purpose},
+	} {
+		if len(f.value) > MaxRaftTextLength {
+			return errs.Errorf(errs.ErrInvalid, "%s too long (max %d characters)", f.name, MaxRaftTextLength)
+		}
+		if strings.ContainsAny(f.value, "\r\n") {
+			return errs.Errorf(errs.ErrInvalid, "%s must be a single line", f.name)
+		}
+	}
+	if len(m.Tags) > MaxRaftTags {
+		return errs.Errorf(errs.ErrInvalid, "too many tags (max %d)", MaxRaftTags)
+	}
+	for _, tag := range m.Tags {
+		if len(tag) > MaxRaftTagLength || strings.ContainsAny(tag, ",\r\n") {
+			return errs.Errorf(errs.ErrInvalid, "invalid tag %q", tag)
+		}
+	}
+	return nil
+}
+
+// Body writes the metadata as the body of a metadata rule
+func (m RaftMetadata) Body() string {
+	var lines []string
+	for _, f := range []struct{ key, value string }{
+		{"name", m.Name}, {"description", m.Description}, {"purpose", m.Purpose}, {"tags", strings.Join(m.Tags, ", ")},
+	} {
+		if f.value != "" {
+			lines = append(lines, f.key+": "+f.value)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ParseRaftMetadata reads a metadata rule's body: lines "name: ...",
+// "description: ...", "purpose: ..." and "tags: a, b". Other lines are
+// ignored; fields left out are cleared.
+func ParseRaftMetadata(body string) (RaftMetadata, error) {
+	var m RaftMetadata
+	for _, line := range strings.Split(body, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), ":")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "name":
+			m.Name = value
+		case "description":
+			m.Description = value
+		case "purpose":
+			m.Purpose = value
+		case "tags":
+			m.Tags = strings.Split(value, ",")
+		}
+	}
+	m = m.normalize()
+	if m.Name == "" && m.Description == "" && m.Purpose == "" && len(m.Tags) == 0 {
+		return RaftMetadata{}, errs.New(errs.ErrInvalid, "a metadata rule must set name, description, purpose or tags")
+	}
+	return m, m.Validate()
+}
+
+// RaftMetadata returns a raft's name, description, purpose and tags
+func (g *Governance) RaftMetadata(raftID string) (RaftMetadata, error) {
+	g.rafts.mu.RLock()
+	raft, ok := g.rafts.rafts[raftID]
+	g.rafts.mu.RUnlock()
+	if !ok {
+		return RaftMetadata{}, fmt.Errorf("%w: %s", ErrRaftNotFound, raftID)
+	}
+	return raft.metadata(), nil
+}
+
+// metadata returns a copy of the raft's metadata
+func (r *RaftInfo) metadata() RaftMetadata {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.Metadata == nil {
+		return RaftMetadata{}
+	}
+	m := *r.Metadata
+	m.Tags = append([]string(nil), m.Tags...)
+	return m
+}
+
+// UpdateRaftMetadata changes a raft's metadata. While by is the raft's only
+// active member the change applies at once and no proposal is returned;
+// otherwise a metadata rule is proposed for the raft to vote on, as an
+// amendment when the raft already has one.
+func (g *Governance) UpdateRaftMetadata(ctx context.Context, raftID string, metadata RaftMetadata, by string) (*Proposal, error) {
+	if err := g.writable(); err != nil {
+		return nil, err
+	}
+	metadata = metadata.normalize()
+	if err := metadata.Validate(); err != nil {
+		return nil, err
+	}
+
+	raft, err := g.liveRaft(raftID)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.requireActiveMember(raft, by); err != nil {
+		return nil, err
+	}
+
+	if active := g.getActiveMembers(raftID); len(active) > 1 {
+		if metadata.Body() == "" {
+			return nil, errs.New(errs.ErrInvalid, "a metadata rule must set name, description, purpose or tags")
+		}
+		// A raft with metadata already amends its current metadata rule
+		rule := &Rule{Scope: MetadataScope, Body: metadata.Body(), ProposedBy: by}
+		if current, ok := g.GetActiveRulesForRaft(raftID)[MetadataScope]; ok {
+			rule.BaseRuleID = current.RuleID
+		}
+		return g.ProposeRule(ctx, raftID, rule)
+	}
+
+	raft.mu.Lock()
+	raft.Metadata = &metadata
+	raft.mu.Unlock()
+	if err := g.saveRaft(ctx, raft); err != nil {
+		g.log().WarnContext(ctx, "failed to persist raft metadata", "raft_id", raftID, "error", err)
+	}
+	g.recordAudit(AuditRaftMetadataChanged, raftID, raftID, by, RaftMetadataAudit{RaftID: raftID, Metadata: metadata})
+	return nil, nil
+}
+
+// applyMetadataRule makes an adopted metadata rule its raft's metadata,
+// reporting whether it was one. A rule that does not parse, as from a
+// peer, leaves the metadata unchanged.
+func (g *Governance) applyMetadataRule(raft *RaftInfo, rule *Rule) bool {
+	if rule.Scope != MetadataScope {
+		return false
+	}
+	metadata, err := ParseRaftMetadata(rule.Body)
+	if err != nil {
+		g.log().Warn("ignoring unreadable metadata rule", "rule_id", rule.RuleID, "raft_id", rule.RaftID, "error", err)
+		return false
+	}
+	raft.mu.Lock()
+	raft.Metadata = &metadata
+	raft.mu.Unlock()
+	return true
+}
+
+// applyMetaRule applies an adopted rule that governs the raft itself, a
+// voting or metadata rule, reporting whether it was one
+func (g *Governance) applyMetaRule(raft *RaftInfo, rule *Rule) bool {
+	return g.applyVotingRule(raft, rule) || g.applyMetadataRule(raft, rule)
+}
+
+// applyMetaRules applies the latest adopted voting and metadata rules among
+// a raft's rules, as when joining a raft with its rules
+func (g *Governance) applyMetaRules(raft *RaftInfo) {
+	g.applyVotingRules(raft)
+
+	var latest *Rule
+	raft.mu.RLock()
+	for _, rule := range raft.Rules {
+		if rule.Scope == MetadataScope && rule.AdoptedAt != nil && (latest == nil || rule.Version > latest.Version) {
+			latest = rule
+		}
+	}
+	raft.mu.RUnlock()
+	if latest != nil {
+		g.applyMetadataRule(raft, latest)
+	}
+}
@@ -0,0 +1,130 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"otter-ai/internal/errs"
+	"otter-ai/internal/memory"
+	"otter-ai/internal/vectordb"
+)
+
+func TestParseRaftMetadata(t *testing.T) {
+	m, err := ParseRaftMetadata("Name: Kelp Forest\ndescription:  Otters of the north shore \npurpose: Keep replies kind\ntags: Ocean, kelp, ocean,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := RaftMetadata{Name: "Kelp Forest", Description: "Otters of the north shore", Purpose: "Keep replies kind", Tags: []string{"kelp", "ocean"}}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("metadata = %+v, want %+v", m, want)
+	}
+	if back, err := ParseRaftMetadata(want.Body()); err != nil || !reflect.DeepEqual(back, want) {
+		t.Errorf("round trip = %+v, %v", back, err)
+	}
+
+	for _, body := range []string{"nothing to see", "name: " + strings.Repeat("x", MaxRaftNameLength+1)} {
+		if _, err := ParseRaftMetadata(body); !errors.Is(err, errs.ErrInvalid) {
+			t.Errorf("ParseRaftMetadata(%.20q) = %v, want an invalid-input error", body, err)
+		}
+	}
+}
+
+func TestUpdateRaftMetadata_Solo(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	ctx := context.Background()
+
+	proposal, err := g.UpdateRaftMetadata(ctx, "otter-1", RaftMetadata{Name: " Home ", Tags: []string{"Solo"}}, "otter-1")
+	if err != nil || proposal != nil {
+		t.Fatalf("UpdateRaftMetadata = %v, %v; want applied directly", proposal, err)
+	}
+	if m, _ := g.RaftMetadata("otter-1"); m.Name != "Home" || !reflect.DeepEqual(m.Tags, []string{"solo"}) {
+		t.Errorf("metadata = %+v", m)
+	}
+	for _, summary := range g.RaftSummaries() {
+		if summary.RaftID == "otter-1" && summary.Metadata.Name != "Home" {
+			t.Errorf("summary metadata = %+v", summary.Metadata)
+		}
+	}
+
+	if _, err := g.UpdateRaftMetadata(ctx, "otter-1", RaftMetadata{Name: "Home"}, "stranger"); err == nil {
+		t.Error("expected a non-member to be refused")
+	}
+	if _, err := g.UpdateRaftMetadata(ctx, "otter-1", RaftMetadata{Purpose: "two\nlines"}, "otter-1"); !errors.Is(err, errs.ErrInvalid) {
+		t.Errorf("multi-line purpose: err = %v", err)
+	}
+	if _, err := g.RaftMetadata("nope"); !errors.Is(err, ErrRaftNotFound) {
+		t.Errorf("unknown raft: err = %v", err)
+	}
+}
+
+func TestUpdateRaftMetadata_ProposedInSharedRaft(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	peers := addPeers(g, "otter-2")
+	ctx := context.Background()
+
+	proposal, err := g.UpdateRaftMetadata(ctx, "otter-1", RaftMetadata{Name: "Pod", Purpose: "Share rules"}, "otter-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proposal == nil || proposal.Rule.Scope != MetadataScope {
+		t.Fatalf("proposal = %+v, want a metadata rule proposal", proposal)
+	}
+	if m, _ := g.RaftMetadata("otter-1"); m.Name != "" {
+		t.Errorf("metadata changed before the vote: %+v", m)
+	}
+
+	if err := g.CastVote(ctx, proposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	if err := peerVote(t, g, peers["otter-2"], proposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	if m, _ := g.RaftMetadata("otter-1"); m.Name != "Pod" || m.Purpose != "Share rules" {
+		t.Errorf("metadata = %+v, want the adopted rule's", m)
+	}
+
+	// A further change amends the adopted metadata rule
+	amendment, err := g.UpdateRaftMetadata(ctx, "otter-1", RaftMetadata{Name: "Pod II"}, "otter-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if amendment.Rule.BaseRuleID != proposal.Rule.RuleID {
+		t.Errorf("base rule = %q, want %q", amendment.Rule.BaseRuleID, proposal.Rule.RuleID)
+	}
+
+	if _, err := g.ProposeRule(ctx, "otter-1", &Rule{Scope: MetadataScope, Body: "colour: blue", ProposedBy: "otter-1"}); !errors.Is(err, errs.ErrInvalid) {
+		t.Errorf("unreadable metadata rule: err = %v", err)
+	}
+}
+
+func TestRaftMetadata_PersistedAcrossRestart(t *testing.T) {
+	dir := t.TempDir()
+	db, err := vectordb.NewSQLiteVectorDB(filepath.Join(dir, "otter.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	g, err := New(RaftConfig{ID: "otter-1", DataDir: dir}, memory.New(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, err := g.UpdateRaftMetadata(ctx, "otter-1", RaftMetadata{Name: "Home", Description: "Just me"}, "otter-1"); err != nil {
+		t.Fatal(err)
+	}
+	g.Shutdown(ctx)
+
+	reloaded, err := New(RaftConfig{ID: "otter-1", DataDir: dir}, memory.New(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reloaded.Shutdown(ctx)
+	if m, _ := reloaded.RaftMetadata("otter-1"); m.Name != "Home" || m.Description != "Just me" {
+		t.Errorf("reloaded metadata = %+v", m)
+	}
+}
@@ -0,0 +1,420 @@
+package governance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"otter-ai/internal/llm"
+	"otter-ai/internal/prompts"
+)
+
+// MaxNegotiationRounds bounds the propose → critique → refine iterations
+// of an LLM negotiation
+const MaxNegotiationRounds = 3
+
+// NegotiationRound records one draft of a compromise rule and how each
+// raft's rules judged it
+type NegotiationRound struct {
+	Round     int
+	Body      string
+	Critiques []NegotiationCritique
+}
+
+// NegotiationCritique is one raft's view of a draft compromise
+type NegotiationCritique struct {
+	RaftID     string
+	Acceptable bool
+	Concerns   string
+}
+
+const negotiationDraftFormat = "Return ONLY JSON in this shape: {\"scope\":\"...\",\"body\":\"...\"}"
+
+// negotiateWithLLM uses LLM to negotiate a compromise between conflicting rules.
+// A draft is critiqued from the perspective of each raft's rules and refined
+// until both accept it, a refinement no longer changes it, or
+// MaxNegotiationRounds is reached. Every prompt and reply is kept in the
+// transcript, and the compromise keeps the conflict's scope.
+// A provider error returns ErrLLMUnavailable so the caller can queue a retry;
+// without a provider a mechanical compromise is synthesized instead.
+func (g *Governance) negotiateWithLLM(ctx context.Context, negotiation *Negotiation, llmProvider interface{}) (*Rule, error) {
+	scope := negotiation.Conflicts[0].ConflictScope
+	body := ""
+
+	if provider, ok := llmProvider.(completer); ok && provider != nil {
+		// A replayed negotiation starts over; the transcript keeps the earlier attempt
+		negotiation.Rounds = nil
+
+		for round := 1; round <= MaxNegotiationRounds; round++ {
+			prompt := g.buildNegotiationPrompt(negotiation)
+			if round > 1 {
+				prompt = g.buildRefinementPrompt(prompt, scope, negotiation.Rounds[len(negotiation.Rounds)-1])
+			}
+			raw, err := negotiationTurn(ctx, negotiation, provider, prompt+"\n\n"+negotiationDraftFormat)
+			if err != nil {
+				return nil, err
+			}
+			_, draft := parseNegotiatedRuleResponse(raw, scope)
+			if draft == "" || draft == body {
+				break // Nothing new to critique
+			}
+			body = draft
+
+			record := NegotiationRound{Round: round, Body: body}
+			accepted := true
+			for _, raftID := range []string{negotiation.Raft1ID, negotiation.Raft2ID} {
+				raw, err := negotiationTurn(ctx, negotiation, provider, g.buildCritiquePrompt(negotiation, raftID, scope, body))
+				if err != nil {
+					return nil, err
+				}
+				critique := parseNegotiationCritique(raftID, raw)
+				record.Critiques = append(record.Critiques, critique)
+				accepted = accepted && critique.Acceptable
+			}
+			negotiation.Rounds = append(negotiation.Rounds, record)
+
+			g.log().InfoContext(ctx, "negotiation round", "negotiation_id", negotiation.NegotiationID, "round", round, "accepted", accepted)
+			if accepted {
+				break
+			}
+		}
+	}
+
+	if body == "" {
+		body = synthesizeCompromiseRuleBody(negotiation.Conflicts)
+	}
+
+	proposedBy := g.pickActiveMemberID(negotiation.Raft1ID)
+	if proposedBy == "" {
+		proposedBy = g.config.ID
+	}
+
+	compromiseRule := &Rule{
+		RuleID:     generateID(fmt.Sprintf("compromise-%s-%s", negotiation.NegotiationID, negotiation.Raft1ID)),
+		RaftID:     negotiation.Raft1ID,
+		Scope:      scope,
+		Version:    maxConflictVersion(negotiation.Conflicts) + 1,
+		Timestamp:  time.Now(),
+		Body:       body,
+		ProposedBy: proposedBy,
+	}
+
+	return compromiseRule, nil
+}
+
+// negotiationTurn sends one prompt to the provider, recording the prompt
+// and its reply in the negotiation's transcript
+func negotiationTurn(ctx context.Context, negotiation *Negotiation, provider completer, prompt string) (string, error) {
+	negotiation.LLMTranscript = append(negotiation.LLMTranscript, prompt)
+
+	resp, err := provider.Complete(ctx, &llm.CompletionRequest{
+		Prompt:  prompt,
+		Profile: llm.ProfileNegotiation,
+	})
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrLLMUnavailable, err)
+	}
+	if resp == nil {
+		return "", nil
+	}
+	negotiation.LLMTranscript = append(negotiation.LLMTranscript, resp.Text)
+	return resp.Text, nil
+}
+
+// buildCritiquePrompt asks the LLM to judge a draft compromise from the
+// perspective of one raft's rules
+func (g *Governance) buildCritiquePrompt(negotiation *Negotiation, raftID, scope, body string) string {
+	return g.renderPrompt(prompts.NegotiationCritique, struct {
+		RaftID, Scope, Body string
+		Rules               []*Rule
+	}{raftID, scope, body, g.negotiationRules(negotiation, raftID)})
+}
+
+// buildRefinementPrompt extends the negotiation prompt with the previous
+// draft and the critiques it received
+func (g *Governance) buildRefinementPrompt(prompt, scope string, previous NegotiationRound) string {
+	return g.renderPrompt(prompts.NegotiationRefinement, struct {
+		Prompt, Scope string
+		Previous      NegotiationRound
+	}{prompt, scope, previous})
+}
+
+// negotiationRules returns the rules a raft brings to a negotiation: the
+// local raft's active rules, or the target raft's fetched rules. Without
+// the latter, as for negotiations queued before they were recorded, the
+// target's conflicting rules stand in for them.
+func (g *Governance) negotiationRules(negotiation *Negotiation, raftID string) []*Rule {
+	if raftID != negotiation.Raft2ID {
+		return sortedRules(g.GetActiveRulesForRaft(raftID))
+	}
+	if len(negotiation.Raft2Rules) > 0 {
+		return negotiation.Raft2Rules
+	}
+	rules := make([]*Rule, 0, len(negotiation.Conflicts))
+	for _, conflict := range negotiation.Conflicts {
+		if conflict != nil && conflict.Rule2 != nil {
+			rules = append(rules, conflict.Rule2)
+		}
+	}
+	return rules
+}
+
+// sortedRules returns the rules in a map ordered by scope, then rule ID
+func sortedRules(rules map[string]*Rule) []*Rule {
+	sorted := make([]*Rule, 0, len(rules))
+	for _, rule := range rules {
+		if rule != nil {
+			sorted = append(sorted, rule)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Scope != sorted[j].Scope {
+			return sorted[i].Scope < sorted[j].Scope
+		}
+		return sorted[i].RuleID < sorted[j].RuleID
+	})
+	return sorted
+}
+
+// parseNegotiationCritique reads a critique reply. A reply that is not the
+// requested JSON counts as a rejection, with its text as the concerns.
+func parseNegotiationCritique(raftID, raw string) NegotiationCritique {
+	clean := trimCodeFence(raw)
+	var parsed struct {
+		Acceptable bool   `json:"acceptable"`
+		Concerns   string `json:"concerns"`
+	}
+	if err := json.Unmarshal([]byte(clean), &parsed); err != nil {
+		return NegotiationCritique{RaftID: raftID, Concerns: clean}
+	}
+	return NegotiationCritique{RaftID: raftID, Acceptable: parsed.Acceptable, Concerns: strings.TrimSpace(parsed.Concerns)}
+}
+
+// trimCodeFence strips a markdown code fence from an LLM reply
+func trimCodeFence(raw string) string {
+	clean := strings.TrimSpace(raw)
+	clean = strings.TrimPrefix(clean, "```json")
+	clean = strings.TrimPrefix(clean, "```")
+	clean = strings.TrimSuffix(clean, "```")
+	return strings.TrimSpace(clean)
+}
+
+// openDualRaftVote proposes the negotiated rule to both rafts. Until the
+// join completes, the target raft is represented by a provisional raft
+// holding its rules, in which this otter proposes and votes for the joining
+// side; the target's members have their say when the join request reaches
+// their admission vote.
+func (g *Governance) openDualRaftVote(ctx context.Context, negotiation *Negotiation) error {
+	g.addProvisionalRaft(negotiation)
+
+	proposer1 := g.pickActiveMemberID(negotiation.Raft1ID)
+	proposer2 := g.pickActiveMemberID(negotiation.Raft2ID)
+	if proposer1 == "" || proposer2 == "" {
+		g.removeProvisionalRaft(negotiation.Raft2ID)
+		return fmt.Errorf("cannot execute dual-raft vote: both rafts must have at least one active member")
+	}
+
+	// Create independent rule instances so each raft owns its own rule ID.
+	rule1 := *negotiation.ProposedRule
+	rule1.RaftID = negotiation.Raft1ID
+	rule1.ProposedBy = proposer1
+	rule1.RuleID = generateID(fmt.Sprintf("%s|%s|%s", negotiation.Raft1ID, rule1.Scope, rule1.Body))
+
+	rule2 := *negotiation.ProposedRule
+	rule2.RaftID = negotiation.Raft2ID
+	rule2.ProposedBy = proposer2
+	rule2.RuleID = generateID(fmt.Sprintf("%s|%s|%s", negotiation.Raft2ID, rule2.Scope, rule2.Body))
+
+	proposal1, err := g.ProposeRule(ctx, negotiation.Raft1ID, &rule1)
+	if err != nil {
+		g.removeProvisionalRaft(negotiation.Raft2ID)
+		return fmt.Errorf("failed to propose to raft 1: %w", err)
+	}
+
+	proposal2, err := g.ProposeRule(ctx, negotiation.Raft2ID, &rule2)
+	if err != nil {
+		g.withdrawProposals(proposal1)
+		g.removeProvisionalRaft(negotiation.Raft2ID)
+		return fmt.Errorf("failed to propose to raft 2: %w", err)
+	}
+
+	g.negotiations.mu.Lock()
+	negotiation.Raft1Proposal = proposal1
+	negotiation.Raft2Proposal = proposal2
+	negotiation.Status = NegotiationVoting
+	g.negotiations.mu.Unlock()
+
+	// Cast initial YES votes where this otter is the proposer; other members
+	// must sign their own ballots.
+	if proposer1 == g.config.ID {
+		_ = g.CastVote(ctx, proposal1.ProposalID, VoteYes)
+	}
+	if proposer2 == g.config.ID {
+		_ = g.CastVote(ctx, proposal2.ProposalID, VoteYes)
+	}
+	return nil
+}
+
+// awaitDualRaftVote waits for both proposals of a negotiation to close. The
+// vote times out NegotiationVoteTimeout after the later voting deadline,
+// leaving time for expired proposals to be swept.
+func (g *Governance) awaitDualRaftVote(negotiation *Negotiation) {
+	ctx := context.Background()
+	proposal1, proposal2 := negotiation.Raft1Proposal, negotiation.Raft2Proposal
+
+	g.proposals.mu.RLock()
+	deadline := proposal1.Deadline
+	if proposal2.Deadline.After(deadline) {
+		deadline = proposal2.Deadline
+	}
+	g.proposals.mu.RUnlock()
+	timeout := time.NewTimer(time.Until(deadline) + NegotiationVoteTimeout)
+	defer timeout.Stop()
+	ticker := time.NewTicker(NegotiationPollInterval)
+	defer ticker.Stop()
+
+	for {
+		g.proposals.mu.RLock()
+		status1, result1 := proposal1.Status, proposal1.Result
+		status2, result2 := proposal2.Status, proposal2.Result
+		g.proposals.mu.RUnlock()
+
+		switch {
+		case result1 == ResultAdopted && result2 == ResultAdopted:
+			g.finalizeNegotiatedJoin(ctx, negotiation)
+			return
+		case status1 == ProposalClosed && result1 != ResultAdopted, status2 == ProposalClosed && result2 != ResultAdopted:
+			g.rollbackNegotiation(ctx, negotiation, fmt.Sprintf("negotiation vote failed: raft1=%s raft2=%s", result1, result2))
+			return
+		}
+
+		select {
+		case <-g.shutdownCh:
+			return
+		case <-timeout.C:
+			g.rollbackNegotiation(ctx, negotiation, "negotiation vote timed out waiting for both rafts to close proposals")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// finalizeNegotiatedJoin joins the target raft once both rafts adopted the
+// compromise, taking its rules with the compromise in place of the
+// conflicting ones. The join replaces the provisional raft.
+func (g *Governance) finalizeNegotiatedJoin(ctx context.Context, negotiation *Negotiation) {
+	g.rafts.mu.RLock()
+	raft, exists := g.rafts.rafts[negotiation.Raft2ID]
+	g.rafts.mu.RUnlock()
+	if !exists || !raft.Provisional {
+		g.rollbackNegotiation(ctx, negotiation, "provisional raft is gone")
+		return
+	}
+	raft.mu.RLock()
+	rules := make(map[string]*Rule, len(raft.Rules))
+	for id, rule := range raft.Rules {
+		rules[id] = rule
+	}
+	raft.mu.RUnlock()
+
+	if err := g.adoptRulesAndJoin(ctx, negotiation.Raft2ID, rules, negotiation.TargetEndpoint, ""); err != nil {
+		g.rollbackNegotiation(ctx, negotiation, fmt.Sprintf("join after negotiation failed: %v", err))
+		return
+	}
+
+	now := time.Now()
+	g.negotiations.mu.Lock()
+	negotiation.Status = NegotiationPeered
+	negotiation.CompletedAt = &now
+	g.negotiations.mu.Unlock()
+	g.log().InfoContext(ctx, "negotiated join completed", "negotiation_id", negotiation.NegotiationID, "raft_id", negotiation.Raft2ID)
+}
+
+// rollbackNegotiation undoes a negotiated join that failed: proposals still
+// open are withdrawn and the provisional raft is removed. A compromise one
+// raft already adopted stays in force there.
+func (g *Governance) rollbackNegotiation(ctx context.Context, negotiation *Negotiation, reason string) {
+	g.withdrawProposals(negotiation.Raft1Proposal, negotiation.Raft2Proposal)
+	g.removeProvisionalRaft(negotiation.Raft2ID)
+
+	now := time.Now()
+	g.negotiations.mu.Lock()
+	negotiation.Status = NegotiationRolledBack
+	negotiation.FailureReason = reason
+	negotiation.CompletedAt = &now
+	g.negotiations.mu.Unlock()
+	g.log().WarnContext(ctx, "negotiated join rolled back", "negotiation_id", negotiation.NegotiationID, "raft_id", negotiation.Raft2ID, "reason", reason)
+}
+
+// withdrawProposals withdraws those of the proposals that are still open
+func (g *Governance) withdrawProposals(proposals ...*Proposal) {
+	g.proposals.mu.Lock()
+	defer g.proposals.mu.Unlock()
+	now := time.Now()
+	for _, proposal := range proposals {
+		if proposal != nil && proposal.Status == ProposalOpen {
+			g.withdrawProposal(proposal, g.config.ID, now)
+		}
+	}
+}
+
+// addProvisionalRaft stands in for the target raft of a negotiation, with
+// its rules and this otter as the joining member, unless this otter already
+// belongs to it
+func (g *Governance) addProvisionalRaft(negotiation *Negotiation) {
+	now := time.Now()
+	raft := &RaftInfo{
+		RaftID: negotiation.Raft2ID,
+		Members: map[string]*Member{
+			g.config.ID: {
+				ID:         g.config.ID,
+				State:      StateActive,
+				JoinedAt:   now,
+				LastSeenAt: now,
+				PublicKey:  g.crypto.GetPublicKey(),
+				SigningKey: g.crypto.GetSigningPublicKey(),
+			},
+		},
+		Rules:       make(map[string]*Rule, len(negotiation.Raft2Rules)),
+		CreatedAt:   now,
+		Provisional: true,
+	}
+	for _, rule := range negotiation.Raft2Rules {
+		raft.Rules[rule.RuleID] = rule
+	}
+	g.applyMetaRules(raft)
+
+	g.rafts.mu.Lock()
+	defer g.rafts.mu.Unlock()
+	if _, exists := g.rafts.rafts[negotiation.Raft2ID]; !exists {
+		g.rafts.rafts[negotiation.Raft2ID] = raft
+	}
+}
+
+// removeProvisionalRaft drops a provisional raft and the rules adopted in it
+func (g *Governance) removeProvisionalRaft(raftID string) {
+	g.rafts.mu.Lock()
+	raft, exists := g.rafts.rafts[raftID]
+	if !exists || !raft.Provisional {
+		g.rafts.mu.Unlock()
+		return
+	}
+	delete(g.rafts.rafts, raftID)
+	g.rafts.mu.Unlock()
+
+	g.rules.mu.Lock()
+	defer g.rules.mu.Unlock()
+	for key := range g.rules.active {
+		if key.RaftID == raftID {
+			delete(g.rules.active, key)
+		}
+	}
+	for id, rule := range g.rules.rules {
+		if rule.RaftID == raftID {
+			delete(g.rules.rules, id)
+		}
+	}
+}
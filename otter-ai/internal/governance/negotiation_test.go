@@ -0,0 +1,201 @@
+package governance
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"otter-ai/internal/llm"
+)
+
+// scriptedLLMProvider answers each completion with the next scripted reply
+// and records the prompts it was sent
+type scriptedLLMProvider struct {
+	replies []string
+	prompts []string
+}
+
+func (s *scriptedLLMProvider) Name() string { return "scripted" }
+func (s *scriptedLLMProvider) Complete(_ context.Context, req *llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	s.prompts = append(s.prompts, req.Prompt)
+	reply := s.replies[0]
+	if len(s.replies) > 1 {
+		s.replies = s.replies[1:]
+	}
+	return &llm.CompletionResponse{Text: reply}, nil
+}
+func (s *scriptedLLMProvider) Embed(_ context.Context, _ string) ([]float32, error) {
+	return []float32{0.1, 0.2}, nil
+}
+
+func testNegotiation() *Negotiation {
+	return &Negotiation{
+		NegotiationID: "n1",
+		Raft1ID:       "otter-1",
+		Raft2ID:       "raft-2",
+		Conflicts: []*RuleConflict{{
+			ConflictScope: "safety",
+			Rule1:         &Rule{Scope: "safety", Body: "be cautious", Version: 1},
+			Rule2:         &Rule{Scope: "safety", Body: "be bold", Version: 2},
+		}},
+		Raft2Rules: []*Rule{
+			{Scope: "privacy", Body: "never share logs"},
+			{Scope: "safety", Body: "be bold"},
+		},
+	}
+}
+
+func TestNegotiateWithLLM_RefinesUntilBothAccept(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	g.rules.active[ruleKey{RaftID: "otter-1", Scope: "tone"}] = &Rule{RaftID: "otter-1", Scope: "tone", Body: "be polite"}
+	negotiation := testNegotiation()
+	provider := &scriptedLLMProvider{replies: []string{
+		`{"scope":"safety","body":"Be bold"}`,
+		`{"acceptable":false,"concerns":"ignores caution"}`,
+		`{"acceptable":true,"concerns":""}`,
+		"```json\n{\"scope\":\"risk\",\"body\":\"Be bold after a risk check\"}\n```",
+		`{"acceptable":true,"concerns":""}`,
+		`{"acceptable":true,"concerns":""}`,
+	}}
+
+	rule, err := g.negotiateWithLLM(context.Background(), negotiation, provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rule.Body != "Be bold after a risk check" || rule.Scope != "safety" {
+		t.Errorf("rule = %q in %q, want the refined body in the conflict's scope", rule.Body, rule.Scope)
+	}
+	if len(negotiation.Rounds) != 2 || len(provider.prompts) != 6 || len(negotiation.LLMTranscript) != 12 {
+		t.Fatalf("rounds = %d, prompts = %d, transcript = %d", len(negotiation.Rounds), len(provider.prompts), len(negotiation.LLMTranscript))
+	}
+	first := negotiation.Rounds[0]
+	if first.Critiques[0].RaftID != "otter-1" || first.Critiques[0].Acceptable || !first.Critiques[1].Acceptable {
+		t.Errorf("first round critiques = %+v", first.Critiques)
+	}
+
+	// Both rafts' rules are fed in, and the refinement sees the critiques
+	if !strings.Contains(provider.prompts[0], "be polite") || !strings.Contains(provider.prompts[0], "never share logs") {
+		t.Errorf("draft prompt missing a raft's rules: %s", provider.prompts[0])
+	}
+	if !strings.Contains(provider.prompts[1], "be polite") || strings.Contains(provider.prompts[1], "never share logs") {
+		t.Errorf("raft 1 critique should see only its own rules: %s", provider.prompts[1])
+	}
+	if !strings.Contains(provider.prompts[3], "ignores caution") {
+		t.Errorf("refinement prompt missing the critique: %s", provider.prompts[3])
+	}
+}
+
+func TestNegotiateWithLLM_StopsAtMaxRounds(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	negotiation := testNegotiation()
+	provider := &scriptedLLMProvider{}
+	for round := 1; round <= MaxNegotiationRounds+1; round++ {
+		provider.replies = append(provider.replies,
+			`{"scope":"safety","body":"draft `+strconv.Itoa(round)+`"}`,
+			`{"acceptable":false,"concerns":"no"}`,
+			`{"acceptable":false,"concerns":"no"}`)
+	}
+
+	rule, err := g.negotiateWithLLM(context.Background(), negotiation, provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(negotiation.Rounds) != MaxNegotiationRounds || rule.Body != negotiation.Rounds[MaxNegotiationRounds-1].Body {
+		t.Errorf("rounds = %d, body = %q; want the last of %d rounds", len(negotiation.Rounds), rule.Body, MaxNegotiationRounds)
+	}
+}
+
+func TestParseNegotiationCritique(t *testing.T) {
+	if c := parseNegotiationCritique("raft-1", "```json\n{\"acceptable\":true,\"concerns\":\" none \"}\n```"); !c.Acceptable || c.Concerns != "none" {
+		t.Errorf("critique = %+v", c)
+	}
+	if c := parseNegotiationCritique("raft-1", "looks risky"); c.Acceptable || c.Concerns != "looks risky" {
+		t.Errorf("free-text critique = %+v, want a rejection", c)
+	}
+}
+
+// negotiatedJoin returns a resolved negotiation for otter-1 joining raft-2
+// through the otter at endpoint
+func negotiatedJoin(endpoint string) *Negotiation {
+	negotiation := testNegotiation()
+	negotiation.TargetEndpoint = endpoint
+	negotiation.Raft2Rules = []*Rule{{RuleID: "r2", RaftID: "raft-2", Scope: "safety", Body: "be bold", Version: 2}}
+	negotiation.ProposedRule = &Rule{Scope: "safety", Body: "be bold with care", Version: 3}
+	negotiation.Status = NegotiationResolved
+	return negotiation
+}
+
+func TestDualRaftVote_JoinsWhenBothAdopt(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	inductor := newTestGovernance("otter-2")
+	r2 := signedRaftRule(t, inductor, "r2", "safety", "be bold")
+	srv := joinTarget(t, inductor, []*Rule{r2}, nil)
+	negotiation := negotiatedJoin(srv.URL)
+	negotiation.Raft2Rules = []*Rule{r2}
+
+	if err := g.openDualRaftVote(context.Background(), negotiation); err != nil {
+		t.Fatal(err)
+	}
+	if summary := summarizeRaft(g.rafts.rafts["raft-2"]); !summary.Provisional {
+		t.Error("raft-2 should be provisional while the vote runs")
+	}
+	g.awaitDualRaftVote(negotiation)
+
+	if negotiation.Status != NegotiationPeered {
+		t.Fatalf("status = %s (%s), want peered", negotiation.Status, negotiation.FailureReason)
+	}
+	raft := g.rafts.rafts["raft-2"]
+	if raft.Provisional || raft.Rules[negotiation.Raft2Proposal.Rule.RuleID] == nil || raft.Rules["r2"] == nil {
+		t.Errorf("raft-2 = %+v, want joined with the compromise", raft)
+	}
+}
+
+func TestDualRaftVote_RollsBackOnRejection(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	peers := addPeers(g, "otter-2")
+	negotiation := negotiatedJoin("http://unused")
+
+	if err := g.openDualRaftVote(context.Background(), negotiation); err != nil {
+		t.Fatal(err)
+	}
+	if err := peerVote(t, g, peers["otter-2"], negotiation.Raft1Proposal.ProposalID, VoteNo); err != nil {
+		t.Fatal(err)
+	}
+	g.awaitDualRaftVote(negotiation)
+
+	if negotiation.Status != NegotiationRolledBack || negotiation.FailureReason == "" {
+		t.Errorf("status = %s (%q), want rolled back", negotiation.Status, negotiation.FailureReason)
+	}
+	if _, exists := g.rafts.rafts["raft-2"]; exists {
+		t.Error("provisional raft should be removed")
+	}
+	if len(g.GetActiveRulesForRaft("raft-2")) != 0 {
+		t.Error("rules adopted in the provisional raft should be dropped")
+	}
+}
+
+func TestDualRaftVote_WithdrawsOnTimeout(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	addPeers(g, "otter-2")
+	negotiation := negotiatedJoin("http://unused")
+
+	if err := g.openDualRaftVote(context.Background(), negotiation); err != nil {
+		t.Fatal(err)
+	}
+	// otter-2 never votes and the deadline is long past
+	negotiation.Raft1Proposal.Deadline = time.Now().Add(-time.Hour)
+	negotiation.Raft2Proposal.Deadline = time.Now().Add(-time.Hour)
+	g.awaitDualRaftVote(negotiation)
+
+	if negotiation.Status != NegotiationRolledBack {
+		t.Errorf("status = %s, want rolled back", negotiation.Status)
+	}
+	if proposal := negotiation.Raft1Proposal; proposal.Status != ProposalClosed || proposal.Result != ResultWithdrawn {
+		t.Errorf("raft 1 proposal = %s/%s, want withdrawn", proposal.Status, proposal.Result)
+	}
+	if _, exists := g.rafts.rafts["raft-2"]; exists {
+		t.Error("provisional raft should be removed")
+	}
+}
@@ -3,12 +3,20 @@ package governance
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
+
+	"otter-ai/internal/memory"
 )
 
-// saveRaft persists a raft to the database
+// saveRaft persists a raft to the database. Provisional rafts are not
+// persisted.
 func (g *Governance) saveRaft(ctx context.Context, raft *RaftInfo) error {
+	if raft.Provisional {
+		return nil
+	}
 	db := g.getDB()
 	if db == nil {
 		return fmt.Errorf("database not available")
@@ -20,30 +28,84 @@ func (g *Governance) saveRaft(ctx context.Context, raft *RaftInfo) error {
 	}
 	defer tx.Rollback()
 
+	raft.mu.RLock()
+
 	// Insert or update raft
+	var archivedAt *int64
+	var archivedBy, archiveReason *string
+	if raft.Archive != nil {
+		at := raft.Archive.ArchivedAt.Unix()
+		archivedAt, archivedBy, archiveReason = &at, &raft.Archive.ArchivedBy, &raft.Archive.Reason
+	}
+	var votingPolicy *string
+	if raft.VotingPolicy != nil {
+		data, err := json.Marshal(raft.VotingPolicy)
+		if err != nil {
+			raft.mu.RUnlock()
+			return fmt.Errorf("failed to marshal voting policy: %w", err)
+		}
+		encoded := string(data)
+		votingPolicy = &encoded
+	}
+	var metadata *string
+	if raft.Metadata != nil {
+		data, err := json.Marshal(raft.Metadata)
+		if err != nil {
+			raft.mu.RUnlock()
+			return fmt.Errorf("failed to marshal raft metadata: %w", err)
+		}
+		encoded := string(data)
+		metadata = &encoded
+	}
+	var usedInvites *string
+	if raft.UsedInvites != nil {
+		data, err := json.Marshal(raft.UsedInvites)
+		if err != nil {
+			raft.mu.RUnlock()
+			return fmt.Errorf("failed to marshal used invites: %w", err)
+		}
+		encoded := string(data)
+		usedInvites = &encoded
+	}
+	// Metadata and used invites not yet loaded, as for the self raft saved
+	// at startup, keep the stored ones; a solo raft's metadata may have been
+	// set without a rule
 	_, err = tx.ExecContext(ctx, `
-		INSERT OR REPLACE INTO governance_rafts (raft_id, created_at, updated_at)
-		VALUES (?, ?, ?)
-	`, raft.RaftID, raft.CreatedAt.Unix(), time.Now().Unix())
+		INSERT OR REPLACE INTO governance_rafts (raft_id, created_at, updated_at, archived_at, archived_by, archive_reason, voting_policy, metadata, used_invites)
+		VALUES (?, ?, ?, ?, ?, ?, ?,
+			COALESCE(?, (SELECT metadata FROM governance_rafts WHERE raft_id = ?)),
+			COALESCE(?, (SELECT used_invites FROM governance_rafts WHERE raft_id = ?)))
+	`, raft.RaftID, raft.CreatedAt.Unix(), time.Now().Unix(), archivedAt, archivedBy, archiveReason, votingPolicy,
+		metadata, raft.RaftID, usedInvites, raft.RaftID)
 	if err != nil {
+		raft.mu.RUnlock()
 		return fmt.Errorf("failed to save raft: %w", err)
 	}
 
 	// Save all members of this raft
-	raft.mu.RLock()
 	for _, member := range raft.Members {
 		var expiresAt *int64
 		if member.ExpiresAt != nil {
 			exp := member.ExpiresAt.Unix()
 			expiresAt = &exp
 		}
+		var capabilities *string
+		if member.Capabilities != nil {
+			data, err := json.Marshal(member.Capabilities)
+			if err != nil {
+				raft.mu.RUnlock()
+				return fmt.Errorf("failed to marshal capabilities of %s: %w", member.ID, err)
+			}
+			encoded := string(data)
+			capabilities = &encoded
+		}
 
 		_, err = tx.ExecContext(ctx, `
 			INSERT OR REPLACE INTO governance_members 
-			(raft_id, member_id, state, joined_at, last_seen_at, public_key, signature, inducted_by, expires_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			(raft_id, member_id, state, joined_at, last_seen_at, public_key, signing_key, signature, inducted_by, expires_at, endpoint, capabilities)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		`, raft.RaftID, member.ID, string(member.State), member.JoinedAt.Unix(),
-			member.LastSeenAt.Unix(), member.PublicKey, member.Signature, member.InductedBy, expiresAt)
+			member.LastSeenAt.Unix(), member.PublicKey, member.SigningKey, member.Signature, member.InductedBy, expiresAt, member.Endpoint, capabilities)
 		if err != nil {
 			raft.mu.RUnlock()
 			return fmt.Errorf("failed to save member: %w", err)
@@ -95,12 +157,25 @@ func (g *Governance) saveRuleInTx(ctx context.Context, tx *sql.Tx, rule *Rule) e
 		baseRuleID = &rule.BaseRuleID
 	}
 
+	var proposalID, ballots *string
+	if rule.ProposalID != "" {
+		proposalID = &rule.ProposalID
+	}
+	if len(rule.Ballots) > 0 {
+		data, err := json.Marshal(rule.Ballots)
+		if err != nil {
+			return fmt.Errorf("failed to marshal rule ballots: %w", err)
+		}
+		encoded := string(data)
+		ballots = &encoded
+	}
+
 	_, err := tx.ExecContext(ctx, `
 		INSERT OR REPLACE INTO governance_rules 
-		(rule_id, raft_id, scope, version, timestamp, body, base_rule_id, signature, proposed_by, adopted_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		(rule_id, raft_id, scope, version, timestamp, body, base_rule_id, signature, signed_by, signer_key, proposed_by, adopted_at, proposal_id, ballots)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, rule.RuleID, rule.RaftID, rule.Scope, rule.Version, rule.Timestamp.Unix(),
-		rule.Body, baseRuleID, rule.Signature, rule.ProposedBy, adoptedAt)
+		rule.Body, baseRuleID, rule.Signature, rule.SignedBy, rule.SignerKey, rule.ProposedBy, adoptedAt, proposalID, ballots)
 
 	if err != nil {
 		return fmt.Errorf("failed to save rule: %w", err)
@@ -117,7 +192,7 @@ func (g *Governance) loadGovernanceState(ctx context.Context) error {
 	}
 
 	// Load all rafts
-	rows, err := db.QueryContext(ctx, `SELECT raft_id, created_at FROM governance_rafts`)
+	rows, err := db.QueryContext(ctx, `SELECT raft_id, created_at, archived_at, archived_by, archive_reason, voting_policy, metadata, used_invites FROM governance_rafts`)
 	if err != nil {
 		return fmt.Errorf("failed to query rafts: %w", err)
 	}
@@ -125,40 +200,77 @@ func (g *Governance) loadGovernanceState(ctx context.Context) error {
 
 	var raftIDs []string
 	raftCreatedAt := make(map[string]time.Time)
+	raftArchives := make(map[string]*RaftArchive)
+	raftPolicies := make(map[string]*VotingPolicy)
+	raftMetadata := make(map[string]*RaftMetadata)
+	raftInvites := make(map[string]map[string]time.Time)
 
 	for rows.Next() {
 		var raftID string
 		var createdAt int64
-		if err := rows.Scan(&raftID, &createdAt); err != nil {
+		var archivedAt *int64
+		var archivedBy, archiveReason, votingPolicy, metadata, usedInvites *string
+		if err := rows.Scan(&raftID, &createdAt, &archivedAt, &archivedBy, &archiveReason, &votingPolicy, &metadata, &usedInvites); err != nil {
 			return fmt.Errorf("failed to scan raft: %w", err)
 		}
+		if votingPolicy != nil {
+			var policy VotingPolicy
+			if err := json.Unmarshal([]byte(*votingPolicy), &policy); err != nil {
+				g.log().WarnContext(ctx, "ignoring unreadable voting policy", "raft_id", raftID, "error", err)
+			} else {
+				raftPolicies[raftID] = &policy
+			}
+		}
+		if metadata != nil {
+			var m RaftMetadata
+			if err := json.Unmarshal([]byte(*metadata), &m); err != nil {
+				g.log().WarnContext(ctx, "ignoring unreadable raft metadata", "raft_id", raftID, "error", err)
+			} else {
+				raftMetadata[raftID] = &m
+			}
+		}
+		if usedInvites != nil {
+			var used map[string]time.Time
+			if err := json.Unmarshal([]byte(*usedInvites), &used); err != nil {
+				g.log().WarnContext(ctx, "ignoring unreadable used invites", "raft_id", raftID, "error", err)
+			} else {
+				raftInvites[raftID] = used
+			}
+		}
 		raftIDs = append(raftIDs, raftID)
 		raftCreatedAt[raftID] = time.Unix(createdAt, 0)
+		if archivedAt != nil {
+			archive := &RaftArchive{ArchivedAt: time.Unix(*archivedAt, 0)}
+			if archivedBy != nil {
+				archive.ArchivedBy = *archivedBy
+			}
+			if archiveReason != nil {
+				archive.Reason = *archiveReason
+			}
+			raftArchives[raftID] = archive
+		}
 	}
 	rows.Close()
 
 	// Load each raft with its members and rules
 	for _, raftID := range raftIDs {
-		// Skip if this raft already exists (e.g., the self raft created during bootstrap)
-		g.rafts.mu.RLock()
-		_, exists := g.rafts.rafts[raftID]
-		g.rafts.mu.RUnlock()
-
-		if exists && raftID == g.config.ID {
-			// Skip loading self raft as it was just bootstrapped
-			continue
-		}
-
+		// The self raft was just re-initialized and saved, so loading it
+		// replaces it with the same self member plus its persisted members
+		// and rules
 		raft := &RaftInfo{
-			RaftID:    raftID,
-			CreatedAt: raftCreatedAt[raftID],
-			Members:   make(map[string]*Member),
-			Rules:     make(map[string]*Rule),
+			RaftID:       raftID,
+			CreatedAt:    raftCreatedAt[raftID],
+			Archive:      raftArchives[raftID],
+			Members:      make(map[string]*Member),
+			VotingPolicy: raftPolicies[raftID],
+			Metadata:     raftMetadata[raftID],
+			Rules:        make(map[string]*Rule),
+			UsedInvites:  raftInvites[raftID],
 		}
 
 		// Load members
 		memberRows, err := db.QueryContext(ctx, `
-			SELECT member_id, state, joined_at, last_seen_at, public_key, signature, inducted_by, expires_at
+			SELECT member_id, state, joined_at, last_seen_at, public_key, signing_key, signature, inducted_by, expires_at, endpoint, capabilities
 			FROM governance_members WHERE raft_id = ?
 		`, raftID)
 		if err != nil {
@@ -168,10 +280,11 @@ func (g *Governance) loadGovernanceState(ctx context.Context) error {
 		for memberRows.Next() {
 			var memberID, state, inductedBy string
 			var joinedAt, lastSeenAt int64
-			var publicKey, signature []byte
+			var publicKey, signingKey, signature []byte
 			var expiresAt *int64
+			var endpoint, capabilities *string
 
-			err := memberRows.Scan(&memberID, &state, &joinedAt, &lastSeenAt, &publicKey, &signature, &inductedBy, &expiresAt)
+			err := memberRows.Scan(&memberID, &state, &joinedAt, &lastSeenAt, &publicKey, &signingKey, &signature, &inductedBy, &expiresAt, &endpoint, &capabilities)
 			if err != nil {
 				memberRows.Close()
 				return fmt.Errorf("failed to scan member: %w", err)
@@ -183,6 +296,7 @@ func (g *Governance) loadGovernanceState(ctx context.Context) error {
 				JoinedAt:   time.Unix(joinedAt, 0),
 				LastSeenAt: time.Unix(lastSeenAt, 0),
 				PublicKey:  publicKey,
+				SigningKey: signingKey,
 				Signature:  signature,
 				InductedBy: inductedBy,
 			}
@@ -191,6 +305,24 @@ func (g *Governance) loadGovernanceState(ctx context.Context) error {
 				expires := time.Unix(*expiresAt, 0)
 				member.ExpiresAt = &expires
 			}
+			if endpoint != nil {
+				member.Endpoint = *endpoint
+			}
+			if capabilities != nil {
+				var negotiated Capabilities
+				if err := json.Unmarshal([]byte(*capabilities), &negotiated); err != nil {
+					g.log().WarnContext(ctx, "ignoring unreadable member capabilities", "member_id", memberID, "raft_id", raftID, "error", err)
+				} else {
+					member.Capabilities = &negotiated
+				}
+			}
+
+			if err := g.verifyMember(raftID, member); errors.Is(err, ErrUnsigned) {
+				g.log().WarnContext(ctx, "membership is unsigned", "member_id", memberID, "raft_id", raftID)
+			} else if err != nil {
+				g.log().WarnContext(ctx, "dropping membership record", "error", err)
+				continue
+			}
 
 			raft.Members[memberID] = member
 		}
@@ -198,7 +330,7 @@ func (g *Governance) loadGovernanceState(ctx context.Context) error {
 
 		// Load rules
 		ruleRows, err := db.QueryContext(ctx, `
-			SELECT rule_id, raft_id, scope, version, timestamp, body, base_rule_id, signature, proposed_by, adopted_at
+			SELECT rule_id, raft_id, scope, version, timestamp, body, base_rule_id, signature, signed_by, signer_key, proposed_by, adopted_at, proposal_id, ballots
 			FROM governance_rules WHERE raft_id = ?
 		`, raftID)
 		if err != nil {
@@ -209,11 +341,11 @@ func (g *Governance) loadGovernanceState(ctx context.Context) error {
 			var ruleID, raftIDCol, scope, body, proposedBy string
 			var version int
 			var timestamp int64
-			var baseRuleID *string
-			var signature []byte
+			var baseRuleID, signedBy, proposalID, ballots *string
+			var signature, signerKey []byte
 			var adoptedAt *int64
 
-			err := ruleRows.Scan(&ruleID, &raftIDCol, &scope, &version, &timestamp, &body, &baseRuleID, &signature, &proposedBy, &adoptedAt)
+			err := ruleRows.Scan(&ruleID, &raftIDCol, &scope, &version, &timestamp, &body, &baseRuleID, &signature, &signedBy, &signerKey, &proposedBy, &adoptedAt, &proposalID, &ballots)
 			if err != nil {
 				ruleRows.Close()
 				return fmt.Errorf("failed to scan rule: %w", err)
@@ -227,12 +359,31 @@ func (g *Governance) loadGovernanceState(ctx context.Context) error {
 				Timestamp:  time.Unix(timestamp, 0),
 				Body:       body,
 				Signature:  signature,
+				SignerKey:  signerKey,
 				ProposedBy: proposedBy,
 			}
 
 			if baseRuleID != nil {
 				rule.BaseRuleID = *baseRuleID
 			}
+			if signedBy != nil {
+				rule.SignedBy = *signedBy
+			}
+			if proposalID != nil {
+				rule.ProposalID = *proposalID
+			}
+			if ballots != nil {
+				if err := json.Unmarshal([]byte(*ballots), &rule.Ballots); err != nil {
+					g.log().WarnContext(ctx, "ignoring unreadable rule ballots", "rule_id", ruleID, "error", err)
+				}
+			}
+
+			if err := verifyStoredRule(rule, raft.Members); errors.Is(err, ErrUnsigned) {
+				g.log().WarnContext(ctx, "rule is unsigned", "rule_id", ruleID, "raft_id", raftID)
+			} else if err != nil {
+				g.log().WarnContext(ctx, "dropping rule record", "error", err)
+				continue
+			}
 
 			if adoptedAt != nil {
 				adopted := time.Unix(*adoptedAt, 0)
@@ -240,16 +391,36 @@ func (g *Governance) loadGovernanceState(ctx context.Context) error {
 			}
 
 			raft.Rules[ruleID] = rule
+		}
+		ruleRows.Close()
 
-			// Add to global rule registry if adopted
+		// The self raft was saved before loading without its policy, so
+		// it is read back from its adopted voting rule
+		if raft.VotingPolicy == nil {
+			g.applyVotingRules(raft)
+		}
+
+		// Add adopted rules to the global registry. Amended versions stay
+		// there for history but only the latest version is active.
+		g.rules.mu.Lock()
+		amended := make(map[string]bool)
+		for _, rule := range raft.Rules {
 			if rule.AdoptedAt != nil {
-				g.rules.mu.Lock()
-				g.rules.rules[ruleID] = rule
-				g.rules.active[scope] = rule
-				g.rules.mu.Unlock()
+				g.rules.rules[rule.RuleID] = rule
+				if rule.BaseRuleID != "" {
+					amended[rule.BaseRuleID] = true
+				}
 			}
 		}
-		ruleRows.Close()
+		for _, rule := range raft.Rules {
+			if rule.AdoptedAt == nil || amended[rule.RuleID] || raft.Archive != nil {
+				continue
+			}
+			if current, ok := g.rules.active[activeKey(rule)]; !ok || current.Version < rule.Version {
+				g.rules.active[activeKey(rule)] = rule
+			}
+		}
+		g.rules.mu.Unlock()
 
 		// Add raft to registry
 		g.rafts.mu.Lock()
@@ -260,6 +431,273 @@ func (g *Governance) loadGovernanceState(ctx context.Context) error {
 	return nil
 }
 
+// saveLLMTask persists a queued LLM task
+func (g *Governance) saveLLMTask(ctx context.Context, task *LLMTask) error {
+	db := g.getDB()
+	if db == nil {
+		return fmt.Errorf("database not available")
+	}
+
+	_, err := db.ExecContext(ctx, `
+		INSERT OR REPLACE INTO governance_llm_tasks
+		(task_id, kind, ref_id, payload, status, attempts, last_error, result, created_at, updated_at, next_attempt_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, task.TaskID, string(task.Kind), task.RefID, []byte(task.Payload), string(task.Status), task.Attempts,
+		task.LastError, task.Result, task.CreatedAt.Unix(), task.UpdatedAt.Unix(), task.NextAttemptAt.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to save llm task: %w", err)
+	}
+
+	return nil
+}
+
+// loadLLMTasks restores unfinished LLM tasks into the queue
+func (g *Governance) loadLLMTasks(ctx context.Context) error {
+	db := g.getDB()
+	if db == nil {
+		return fmt.Errorf("database not available")
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT task_id, kind, ref_id, payload, status, attempts, last_error, result, created_at, updated_at, next_attempt_at
+		FROM governance_llm_tasks WHERE status IN (?, ?)
+	`, string(TaskPending), string(TaskRunning))
+	if err != nil {
+		return fmt.Errorf("failed to query llm tasks: %w", err)
+	}
+	defer rows.Close()
+
+	q := g.llmTasks()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for rows.Next() {
+		var taskID, kind, refID, status string
+		var payload []byte
+		var lastError, result *string
+		var attempts int
+		var createdAt, updatedAt, nextAttemptAt int64
+
+		if err := rows.Scan(&taskID, &kind, &refID, &payload, &status, &attempts, &lastError, &result, &createdAt, &updatedAt, &nextAttemptAt); err != nil {
+			return fmt.Errorf("failed to scan llm task: %w", err)
+		}
+
+		task := &LLMTask{
+			TaskID:        taskID,
+			Kind:          LLMTaskKind(kind),
+			RefID:         refID,
+			Payload:       payload,
+			Status:        TaskPending, // Tasks interrupted mid-run are retried
+			Attempts:      attempts,
+			CreatedAt:     time.Unix(createdAt, 0),
+			UpdatedAt:     time.Unix(updatedAt, 0),
+			NextAttemptAt: time.Unix(nextAttemptAt, 0),
+		}
+		if lastError != nil {
+			task.LastError = *lastError
+		}
+		if result != nil {
+			task.Result = *result
+		}
+
+		q.tasks[taskID] = task
+	}
+
+	return rows.Err()
+}
+
+// saveHold persists a legal hold. Audit range bounds are Unix nanoseconds
+// to match audit entry timestamps.
+func (g *Governance) saveHold(ctx context.Context, hold *Hold) error {
+	db := g.getDB()
+	if db == nil {
+		return fmt.Errorf("database not available")
+	}
+
+	approvals, err := json.Marshal(hold.ReleaseApprovals)
+	if err != nil {
+		return fmt.Errorf("failed to marshal release approvals: %w", err)
+	}
+
+	var since, until, releasedAt *int64
+	if hold.Since != nil {
+		ns := hold.Since.UnixNano()
+		since = &ns
+	}
+	if hold.Until != nil {
+		ns := hold.Until.UnixNano()
+		until = &ns
+	}
+	if hold.ReleasedAt != nil {
+		ts := hold.ReleasedAt.Unix()
+		releasedAt = &ts
+	}
+
+	_, err = db.ExecContext(ctx, `
+		INSERT OR REPLACE INTO governance_holds
+		(hold_id, kind, subject_id, memory_type, raft_id, since, until, reason, placed_by, placed_at, release_approvals, released_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, hold.HoldID, string(hold.Kind), hold.SubjectID, string(hold.MemoryType), hold.RaftID, since, until,
+		hold.Reason, hold.PlacedBy, hold.PlacedAt.Unix(), string(approvals), releasedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save hold: %w", err)
+	}
+
+	return nil
+}
+
+// loadHolds restores legal holds into the registry
+func (g *Governance) loadHolds(ctx context.Context) error {
+	db := g.getDB()
+	if db == nil {
+		return fmt.Errorf("database not available")
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT hold_id, kind, subject_id, memory_type, raft_id, since, until, reason, placed_by, placed_at, release_approvals, released_at
+		FROM governance_holds
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to query holds: %w", err)
+	}
+	defer rows.Close()
+
+	r := g.holdRegistry()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for rows.Next() {
+		var holdID, kind, reason, placedBy string
+		var subjectID, memoryType, raftID, approvals *string
+		var since, until, releasedAt *int64
+		var placedAt int64
+
+		if err := rows.Scan(&holdID, &kind, &subjectID, &memoryType, &raftID, &since, &until,
+			&reason, &placedBy, &placedAt, &approvals, &releasedAt); err != nil {
+			return fmt.Errorf("failed to scan hold: %w", err)
+		}
+
+		hold := &Hold{
+			HoldID:   holdID,
+			Kind:     HoldKind(kind),
+			Reason:   reason,
+			PlacedBy: placedBy,
+			PlacedAt: time.Unix(placedAt, 0),
+		}
+		if subjectID != nil {
+			hold.SubjectID = *subjectID
+		}
+		if memoryType != nil {
+			hold.MemoryType = memory.MemoryType(*memoryType)
+		}
+		if raftID != nil {
+			hold.RaftID = *raftID
+		}
+		if since != nil {
+			t := time.Unix(0, *since)
+			hold.Since = &t
+		}
+		if until != nil {
+			t := time.Unix(0, *until)
+			hold.Until = &t
+		}
+		if approvals != nil && *approvals != "" {
+			if err := json.Unmarshal([]byte(*approvals), &hold.ReleaseApprovals); err != nil {
+				return fmt.Errorf("failed to decode approvals of hold %s: %w", holdID, err)
+			}
+		}
+		if releasedAt != nil {
+			t := time.Unix(*releasedAt, 0)
+			hold.ReleasedAt = &t
+		}
+
+		r.holds[holdID] = hold
+	}
+
+	return rows.Err()
+}
+
+// saveSuspension persists a rule suspension
+func (g *Governance) saveSuspension(ctx context.Context, suspension *Suspension) error {
+	db := g.getDB()
+	if db == nil {
+		return fmt.Errorf("database not available")
+	}
+
+	var resolvedAt *int64
+	if suspension.ResolvedAt != nil {
+		ts := suspension.ResolvedAt.Unix()
+		resolvedAt = &ts
+	}
+	_, err := db.ExecContext(ctx, `
+		INSERT OR REPLACE INTO governance_suspensions
+		(rule_id, raft_id, scope, proposal_id, revote_id, reason, suspended_by, suspended_at, status, resolved_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, suspension.RuleID, suspension.RaftID, suspension.Scope, suspension.ProposalID, suspension.RevoteID,
+		suspension.Reason, suspension.SuspendedBy, suspension.SuspendedAt.Unix(), string(suspension.Status), resolvedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save suspension: %w", err)
+	}
+
+	return nil
+}
+
+// loadSuspensions restores rule suspensions and takes rules that are
+// still suspended or were repealed back out of the active set
+func (g *Governance) loadSuspensions(ctx context.Context) error {
+	db := g.getDB()
+	if db == nil {
+		return fmt.Errorf("database not available")
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT rule_id, raft_id, scope, proposal_id, revote_id, reason, suspended_by, suspended_at, status, resolved_at
+		FROM governance_suspensions
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to query suspensions: %w", err)
+	}
+	defer rows.Close()
+
+	r := g.suspensionRegistry()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for rows.Next() {
+		var suspension Suspension
+		var revoteID *string
+		var status string
+		var suspendedAt int64
+		var resolvedAt *int64
+
+		if err := rows.Scan(&suspension.RuleID, &suspension.RaftID, &suspension.Scope, &suspension.ProposalID, &revoteID,
+			&suspension.Reason, &suspension.SuspendedBy, &suspendedAt, &status, &resolvedAt); err != nil {
+			return fmt.Errorf("failed to scan suspension: %w", err)
+		}
+		if revoteID != nil {
+			suspension.RevoteID = *revoteID
+		}
+		suspension.SuspendedAt = time.Unix(suspendedAt, 0)
+		suspension.Status = SuspensionStatus(status)
+		if resolvedAt != nil {
+			t := time.Unix(*resolvedAt, 0)
+			suspension.ResolvedAt = &t
+		}
+		r.suspensions[suspension.RuleID] = &suspension
+
+		if suspension.Status != SuspensionReinstated {
+			g.rules.mu.Lock()
+			key := ruleKey{RaftID: suspension.RaftID, Scope: suspension.Scope}
+			if active, ok := g.rules.active[key]; ok && active.RuleID == suspension.RuleID {
+				delete(g.rules.active, key)
+			}
+			g.rules.mu.Unlock()
+		}
+	}
+
+	return rows.Err()
+}
+
 // getDB returns the database connection from the memory layer's vectorDB
 func (g *Governance) getDB() *sql.DB {
 	// The memory layer wraps the SQLiteVectorDB
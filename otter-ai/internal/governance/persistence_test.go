@@ -0,0 +1,212 @@
+package governance
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"otter-ai/internal/memory"
+	"otter-ai/internal/vectordb"
+)
+
+func TestLoadGovernanceState_VerifiesSignatures(t *testing.T) {
+	dir := t.TempDir()
+	db, err := vectordb.NewSQLiteVectorDB(filepath.Join(dir, "otter.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	g, err := New(RaftConfig{ID: "otter-1", DataDir: dir}, memory.New(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Shutdown(context.Background())
+
+	ctx := context.Background()
+	good := signedTestRule(t, g)
+	bad := signedTestRule(t, g)
+	bad.RuleID = "r2"
+	bad.Scope = "tone"
+	member := &Member{ID: "otter-1", State: StateActive, JoinedAt: time.Now(), LastSeenAt: time.Now(),
+		SigningKey: g.crypto.GetSigningPublicKey(), InductedBy: "raft-1"}
+	if err := g.signMember("raft-1", member); err != nil {
+		t.Fatal(err)
+	}
+	raft := &RaftInfo{
+		RaftID:    "raft-1",
+		Members:   map[string]*Member{member.ID: member},
+		Rules:     map[string]*Rule{good.RuleID: good, bad.RuleID: bad},
+		CreatedAt: time.Now(),
+	}
+	if err := g.saveRaft(ctx, raft); err != nil {
+		t.Fatal(err)
+	}
+
+	// Tamper with one rule directly in the database
+	if _, err := db.GetDB().Exec(`UPDATE governance_rules SET body = 'tampered' WHERE rule_id = 'r2'`); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := New(RaftConfig{ID: "otter-1", DataDir: dir}, memory.New(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reloaded.Shutdown(context.Background())
+
+	loaded := reloaded.rafts.rafts["raft-1"]
+	if loaded == nil {
+		t.Fatal("raft-1 not restored")
+	}
+	if _, ok := loaded.Rules["r1"]; !ok {
+		t.Error("validly signed rule should load")
+	}
+	if _, ok := loaded.Rules["r2"]; ok {
+		t.Error("tampered rule should be dropped")
+	}
+	if _, ok := loaded.Members["otter-1"]; !ok {
+		t.Error("signed membership should load")
+	}
+}
+
+func TestAuditLog_PersistsAndReplays(t *testing.T) {
+	dir := t.TempDir()
+	db, err := vectordb.NewSQLiteVectorDB(filepath.Join(dir, "otter.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	g, err := New(RaftConfig{ID: "otter-1", DataDir: dir}, memory.New(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Shutdown(context.Background())
+
+	ctx := context.Background()
+	beforeRule := time.Now()
+	proposal, err := g.ProposeRule(ctx, "otter-1", &Rule{Scope: "safety", Body: "be kind", ProposedBy: "otter-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CastVote(ctx, proposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+
+	// Startup backfilled the self membership; the proposal added the rest
+	var actions []AuditAction
+	g.EachAuditEntry(ctx, AuditFilter{}, func(entry AuditEntry) error {
+		actions = append(actions, entry.Action)
+		return nil
+	})
+	if len(actions) != 5 || actions[0] != AuditMemberJoined {
+		t.Fatalf("actions = %v", actions)
+	}
+
+	state, err := g.StateAt(ctx, beforeRule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Rafts) != 1 || len(state.Rafts[0].Members) != 1 || len(state.Rafts[0].Rules) != 0 {
+		t.Errorf("state before the rule = %+v", state.Rafts)
+	}
+
+	state, _ = g.StateAt(ctx, time.Now())
+	if len(state.Rafts[0].Rules) != 1 || state.Rafts[0].Rules[0].Body != "be kind" {
+		t.Errorf("state after adoption = %+v", state.Rafts[0].Rules)
+	}
+}
+
+func TestLoadGovernanceState_ActivatesLatestVersion(t *testing.T) {
+	dir := t.TempDir()
+	db, err := vectordb.NewSQLiteVectorDB(filepath.Join(dir, "otter.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	g, err := New(RaftConfig{ID: "otter-1", DataDir: dir}, memory.New(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Shutdown(context.Background())
+
+	adopted := time.Now()
+	v1 := signedTestRule(t, g)
+	v1.AdoptedAt = &adopted
+	v2 := &Rule{RuleID: "r2", RaftID: "raft-1", Scope: "safety", Version: 2, Timestamp: time.Now(),
+		Body: "be kind and patient", BaseRuleID: "r1", ProposedBy: "otter-1", AdoptedAt: &adopted}
+	if err := g.signRule(v2); err != nil {
+		t.Fatal(err)
+	}
+	member := &Member{ID: "otter-1", State: StateActive, JoinedAt: time.Now(), LastSeenAt: time.Now(),
+		SigningKey: g.crypto.GetSigningPublicKey(), InductedBy: "raft-1"}
+	if err := g.signMember("raft-1", member); err != nil {
+		t.Fatal(err)
+	}
+	raft := &RaftInfo{
+		RaftID:    "raft-1",
+		Members:   map[string]*Member{member.ID: member},
+		Rules:     map[string]*Rule{v1.RuleID: v1, v2.RuleID: v2},
+		CreatedAt: time.Now(),
+	}
+	if err := g.saveRaft(context.Background(), raft); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := New(RaftConfig{ID: "otter-1", DataDir: dir}, memory.New(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reloaded.Shutdown(context.Background())
+
+	if active := reloaded.GetActiveRulesForRaft("raft-1")["safety"]; active == nil || active.RuleID != "r2" {
+		t.Errorf("active rule = %v, want r2", active)
+	}
+	history, err := reloaded.RuleHistory("r2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[0].RuleID != "r1" {
+		t.Errorf("unexpected history %+v", history)
+	}
+}
+
+func TestLoadGovernanceState_KeepsAdoptionBallots(t *testing.T) {
+	dir := t.TempDir()
+	db, err := vectordb.NewSQLiteVectorDB(filepath.Join(dir, "otter.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	g, err := New(RaftConfig{ID: "otter-1", DataDir: dir}, memory.New(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Shutdown(context.Background())
+
+	ctx := context.Background()
+	proposal, err := g.ProposeRule(ctx, "otter-1", &Rule{Scope: "safety", Body: "be kind", ProposedBy: "otter-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CastVote(ctx, proposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := New(RaftConfig{ID: "otter-1", DataDir: dir}, memory.New(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reloaded.Shutdown(context.Background())
+
+	rule := reloaded.GetActiveRulesForRaft("otter-1")["safety"]
+	if rule == nil || rule.ProposalID != proposal.ProposalID || len(rule.Ballots) != 1 || rule.Ballots[0].VoterID != "otter-1" {
+		t.Fatalf("reloaded rule = %+v, want it with its adopting ballot", rule)
+	}
+	if err := verifyVote(&rule.Ballots[0], g.crypto.GetSigningPublicKey()); err != nil {
+		t.Errorf("reloaded ballot: %v", err)
+	}
+}
@@ -0,0 +1,27 @@
+package governance
+
+import (
+	"otter-ai/internal/prompts"
+)
+
+// SetPrompts sets the templates governance prompts are rendered from; nil
+// uses the built-in templates
+func (g *Governance) SetPrompts(registry *prompts.Registry) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.prompts = registry
+}
+
+// renderPrompt renders a governance prompt. A failing override is logged
+// and the built-in template used instead.
+func (g *Governance) renderPrompt(name string, data interface{}) string {
+	g.mu.RLock()
+	registry := g.prompts
+	g.mu.RUnlock()
+
+	prompt, err := registry.Render(name, data)
+	if err != nil {
+		g.log().Warn("failed to render prompt template", "template", name, "error", err)
+	}
+	return prompt
+}
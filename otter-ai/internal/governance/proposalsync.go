@@ -0,0 +1,279 @@
+package governance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"otter-ai/internal/events"
+)
+
+// Message types that keep a raft's members in step on its proposals. The
+// otter a proposal was opened on keeps the canonical tally: it announces
+// the proposal, every member's otter relays the votes cast on it, and the
+// origin announces the outcome.
+const (
+	MessageProposalOpened = "proposal.opened"
+	MessageVoteCast       = "vote.cast"
+	MessageProposalClosed = "proposal.closed"
+)
+
+// ProposalAnnouncement is the payload of a proposal.opened message
+type ProposalAnnouncement struct {
+	ProposalID     string       `json:"proposal_id"`
+	RaftID         string       `json:"raft_id"`
+	Kind           ProposalKind `json:"kind"`
+	Rule           *Rule        `json:"rule,omitempty"`
+	TargetMemberID string       `json:"target_member_id,omitempty"`
+	Reason         string       `json:"reason,omitempty"`
+	ProposedBy     string       `json:"proposed_by"`
+	ProposedAt     time.Time    `json:"proposed_at"`
+	Deadline       time.Time    `json:"deadline"`
+	EligibleVoters []string     `json:"eligible_voters"`
+}
+
+// ProposalOutcome is the payload of a proposal.closed message
+type ProposalOutcome struct {
+	ProposalID string         `json:"proposal_id"`
+	RaftID     string         `json:"raft_id"`
+	Result     ProposalResult `json:"result"`
+	QuorumMet  bool           `json:"quorum_met"`
+	Expired    bool           `json:"expired"`
+	ClosedAt   time.Time      `json:"closed_at"`
+	Ballots    []SignedVote   `json:"ballots"`
+}
+
+// tallies reports whether this otter keeps the canonical tally of a
+// proposal, rather than mirroring one opened on another member's otter
+func (g *Governance) tallies(proposal *Proposal) bool {
+	return proposal.Origin == "" || proposal.Origin == g.config.ID
+}
+
+// announceProposal tells the raft's other members about a proposal opened
+// on this otter, so they can vote on it through their own otters
+func (g *Governance) announceProposal(proposal *Proposal) {
+	if !g.tallies(proposal) || len(g.raftPeers(proposal.RaftID)) == 0 {
+		return
+	}
+	announcement := ProposalAnnouncement{
+		ProposalID:     proposal.ProposalID,
+		RaftID:         proposal.RaftID,
+		Kind:           proposal.kind(),
+		TargetMemberID: proposal.TargetMemberID,
+		Reason:         proposal.Reason,
+		ProposedBy:     proposal.ProposedBy,
+		ProposedAt:     proposal.ProposedAt,
+		Deadline:       proposal.Deadline,
+		EligibleVoters: proposal.EligibleVoters,
+	}
+	if proposal.Rule != nil {
+		rule := *proposal.Rule
+		announcement.Rule = &rule
+	}
+	go g.broadcast(context.Background(), proposal.RaftID, MessageProposalOpened, announcement)
+}
+
+// relayVote sends a ballot cast on this otter to the raft's other members,
+// where the proposal's origin counts it. The caller must hold the proposal
+// registry lock.
+func (g *Governance) relayVote(proposal *Proposal, ballot SignedVote) {
+	if len(g.raftPeers(proposal.RaftID)) == 0 {
+		return
+	}
+	go g.broadcast(context.Background(), proposal.RaftID, MessageVoteCast, ballot)
+}
+
+// announceOutcome tells the raft's other members how a proposal this otter
+// tallied was decided. The caller must hold the proposal registry lock.
+func (g *Governance) announceOutcome(proposal *Proposal) {
+	if !g.tallies(proposal) || proposal.ClosedAt == nil || len(g.raftPeers(proposal.RaftID)) == 0 {
+		return
+	}
+	outcome := ProposalOutcome{
+		ProposalID: proposal.ProposalID,
+		RaftID:     proposal.RaftID,
+		Result:     proposal.Result,
+		QuorumMet:  proposal.QuorumMet,
+		Expired:    proposal.Expired,
+		ClosedAt:   *proposal.ClosedAt,
+		Ballots:    make([]SignedVote, 0, len(proposal.Ballots)),
+	}
+	for _, ballot := range proposal.Ballots {
+		outcome.Ballots = append(outcome.Ballots, *ballot)
+	}
+	go g.broadcast(context.Background(), proposal.RaftID, MessageProposalClosed, outcome)
+}
+
+// applyRemoteProposal mirrors a proposal opened on a peer's otter. The
+// sender keeps its canonical tally.
+func (g *Governance) applyRemoteProposal(ctx context.Context, env *Envelope, announcement *ProposalAnnouncement) error {
+	if announcement.RaftID != env.RaftID {
+		return fmt.Errorf("proposal for raft %s sent in an envelope for raft %s", announcement.RaftID, env.RaftID)
+	}
+	if announcement.ProposalID == "" {
+		return fmt.Errorf("announced proposal has no ID")
+	}
+
+	raft, err := g.liveRaft(env.RaftID)
+	if err != nil {
+		return err
+	}
+	switch announcement.Kind {
+	case ProposalKindRule:
+		if announcement.Rule == nil || announcement.Rule.RaftID != env.RaftID {
+			return fmt.Errorf("rule proposal %s carries no rule for raft %s", announcement.ProposalID, env.RaftID)
+		}
+		raft.mu.RLock()
+		members := make(map[string]*Member, len(raft.Members))
+		for id, member := range raft.Members {
+			members[id] = member
+		}
+		raft.mu.RUnlock()
+		if err := verifyRuleSigner(announcement.Rule, members); err != nil {
+			return fmt.Errorf("refusing proposal %s from %s: %w", announcement.ProposalID, env.SenderID, err)
+		}
+		announcement.Rule.AdoptedAt = nil
+	case ProposalKindSuspension, ProposalKindReinstatement:
+		// Only rules this otter already holds can be suspended here
+		if announcement.Rule == nil {
+			return fmt.Errorf("%s proposal %s names no rule", announcement.Kind, announcement.ProposalID)
+		}
+		g.rules.mu.RLock()
+		rule, known := g.rules.rules[announcement.Rule.RuleID]
+		g.rules.mu.RUnlock()
+		if !known || rule.RaftID != env.RaftID {
+			return fmt.Errorf("%w: %s", ErrRuleNotFound, announcement.Rule.RuleID)
+		}
+		announcement.Rule = rule
+	case ProposalKindEviction, ProposalKindAdmission:
+		if announcement.TargetMemberID == "" {
+			return fmt.Errorf("%s proposal %s names no member", announcement.Kind, announcement.ProposalID)
+		}
+		announcement.Rule = nil
+	default:
+		return fmt.Errorf("unsupported proposal kind: %s", announcement.Kind)
+	}
+
+	g.proposals.mu.Lock()
+	defer g.proposals.mu.Unlock()
+	if _, exists := g.proposals.proposals[announcement.ProposalID]; exists {
+		return nil // Already known
+	}
+
+	proposal := &Proposal{
+		ProposalID:     announcement.ProposalID,
+		RaftID:         announcement.RaftID,
+		Kind:           announcement.Kind,
+		Rule:           announcement.Rule,
+		TargetMemberID: announcement.TargetMemberID,
+		Reason:         announcement.Reason,
+		ProposedBy:     announcement.ProposedBy,
+		ProposedAt:     announcement.ProposedAt,
+		Deadline:       announcement.Deadline,
+		Votes:          make(map[string]VoteType),
+		Ballots:        make(map[string]*SignedVote),
+		Status:         ProposalOpen,
+		Result:         ResultPending,
+		EligibleVoters: announcement.EligibleVoters,
+		Origin:         env.SenderID,
+	}
+	if proposal.EligibleVoters == nil {
+		proposal.EligibleVoters = []string{}
+	}
+	g.proposals.proposals[proposal.ProposalID] = proposal
+
+	g.log().InfoContext(ctx, "mirrored proposal from peer", "proposal_id", proposal.ProposalID, "raft_id", proposal.RaftID, "kind", proposal.Kind, "origin", env.SenderID)
+	g.publish(events.ProposalCreated, proposalEvent(proposal))
+	g.recordAudit(AuditProposalCreated, proposal.RaftID, proposal.ProposalID, proposal.ProposedBy, proposalEvent(proposal))
+	return nil
+}
+
+// applyRemoteVote records a ballot a member cast through its own otter.
+// The ballot's signature is checked against the voter's key as for any
+// vote; on the proposal's origin it counts towards the canonical tally.
+func (g *Governance) applyRemoteVote(ctx context.Context, env *Envelope, ballot *SignedVote) error {
+	g.proposals.mu.RLock()
+	proposal, exists := g.proposals.proposals[ballot.ProposalID]
+	var raftID string
+	var open bool
+	if exists {
+		raftID, open = proposal.RaftID, proposal.Status == ProposalOpen
+	}
+	g.proposals.mu.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrProposalNotFound, ballot.ProposalID)
+	}
+	if raftID != env.RaftID {
+		return fmt.Errorf("vote on a proposal of raft %s sent in an envelope for raft %s", raftID, env.RaftID)
+	}
+	if !open {
+		return nil // Decided already; the outcome carries the ballots
+	}
+	return g.recordVote(ctx, *ballot, false)
+}
+
+// applyRemoteOutcome closes a mirrored proposal as its origin decided it.
+// Only the origin can close it; the effects of the decision arrive in
+// their own messages, such as rule.adopted, except for suspensions and
+// their re-votes, which take effect with the outcome.
+func (g *Governance) applyRemoteOutcome(ctx context.Context, env *Envelope, outcome *ProposalOutcome) error {
+	g.proposals.mu.Lock()
+	defer g.proposals.mu.Unlock()
+
+	proposal, exists := g.proposals.proposals[outcome.ProposalID]
+	if !exists {
+		return nil // Never announced here; nothing to close
+	}
+	if proposal.RaftID != env.RaftID || proposal.Origin != env.SenderID {
+		return fmt.Errorf("%w: %s does not tally proposal %s", ErrUnknownSender, env.SenderID, outcome.ProposalID)
+	}
+	if proposal.Status != ProposalOpen && !proposal.Expired {
+		return nil
+	}
+	if outcome.Result != ResultAdopted && outcome.Result != ResultRejected && outcome.Result != ResultWithdrawn {
+		return fmt.Errorf("invalid outcome %q for proposal %s", outcome.Result, outcome.ProposalID)
+	}
+
+	keys := g.memberSigningKeys(proposal.RaftID)
+	for i := range outcome.Ballots {
+		ballot := outcome.Ballots[i]
+		if ballot.ProposalID != proposal.ProposalID || verifyVote(&ballot, keys[ballot.VoterID]) != nil {
+			g.log().WarnContext(ctx, "skipping invalid ballot in proposal outcome", "proposal_id", proposal.ProposalID, "voter_id", ballot.VoterID)
+			continue
+		}
+		proposal.Votes[ballot.VoterID] = ballot.Vote
+		proposal.Ballots[ballot.VoterID] = &ballot
+	}
+
+	closedAt := outcome.ClosedAt
+	proposal.Status = ProposalClosed
+	proposal.Result = outcome.Result
+	proposal.QuorumMet = outcome.QuorumMet
+	proposal.Expired = outcome.Expired
+	proposal.ClosedAt = &closedAt
+
+	g.publish(events.ProposalClosed, proposalEvent(proposal))
+	g.recordAudit(AuditProposalClosed, proposal.RaftID, proposal.ProposalID, env.SenderID, proposalEvent(proposal))
+	g.applySuspensionOutcome(proposal)
+	g.applyAdmissionOutcome(proposal)
+	return nil
+}
+
+// memberSigningKeys returns the signing keys of a raft's members by ID
+func (g *Governance) memberSigningKeys(raftID string) map[string][]byte {
+	g.rafts.mu.RLock()
+	raft, exists := g.rafts.rafts[raftID]
+	g.rafts.mu.RUnlock()
+	if !exists {
+		return nil
+	}
+
+	raft.mu.RLock()
+	defer raft.mu.RUnlock()
+	keys := make(map[string][]byte, len(raft.Members))
+	for id, member := range raft.Members {
+		keys[id] = member.SigningKey
+	}
+	return keys
+}
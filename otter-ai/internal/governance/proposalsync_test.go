@@ -0,0 +1,164 @@
+package governance
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// loopbackTransport delivers envelopes straight to the otter at each endpoint
+type loopbackTransport map[string]*Governance
+
+func (l loopbackTransport) Send(ctx context.Context, endpoint string, env *Envelope) error {
+	return l[endpoint].HandleEnvelope(ctx, env)
+}
+
+// sharedRaft makes a and b members of a's raft on both otters, reachable
+// through a loopback transport
+func sharedRaft(a, b *Governance) {
+	now := time.Now()
+	capabilities := LocalCapabilities()
+	members := func() map[string]*Member {
+		return map[string]*Member{
+			a.config.ID: {ID: a.config.ID, State: StateActive, JoinedAt: now, LastSeenAt: now, Endpoint: "http://" + a.config.ID,
+				SigningKey: a.crypto.GetSigningPublicKey(), Capabilities: &capabilities},
+			b.config.ID: {ID: b.config.ID, State: StateActive, JoinedAt: now, LastSeenAt: now, Endpoint: "http://" + b.config.ID,
+				SigningKey: b.crypto.GetSigningPublicKey(), Capabilities: &capabilities},
+		}
+	}
+	a.rafts.rafts[a.config.ID].Members = members()
+	b.rafts.rafts[a.config.ID] = &RaftInfo{RaftID: a.config.ID, Members: members(), Rules: make(map[string]*Rule), CreatedAt: now}
+
+	transport := loopbackTransport{"http://" + a.config.ID: a, "http://" + b.config.ID: b}
+	a.SetTransport(transport)
+	b.SetTransport(transport)
+}
+
+// waitForProposal waits until g holds the proposal in status
+func waitForProposal(t *testing.T, g *Governance, proposalID string, status ProposalStatus) Proposal {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		g.proposals.mu.RLock()
+		proposal, ok := g.proposals.proposals[proposalID]
+		var snapshot Proposal
+		if ok {
+			snapshot = *proposal
+		}
+		g.proposals.mu.RUnlock()
+		if ok && snapshot.Status == status {
+			return snapshot
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("proposal %s never reached %s on %s", proposalID, status, g.config.ID)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestProposalSync_RemoteVoteDecidesOnOrigin(t *testing.T) {
+	origin := newTestGovernance("otter-1")
+	peer := newTestGovernance("otter-2")
+	sharedRaft(origin, peer)
+	ctx := context.Background()
+
+	proposal, err := origin.ProposeRule(ctx, "otter-1", &Rule{Scope: "safety", Body: "be kind", ProposedBy: "otter-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mirror := waitForProposal(t, peer, proposal.ProposalID, ProposalOpen)
+	if mirror.Origin != "otter-1" || mirror.Rule == nil || mirror.Rule.Body != "be kind" || len(mirror.EligibleVoters) != 2 {
+		t.Fatalf("mirror = %+v", mirror)
+	}
+
+	// otter-2 votes through its own otter; the origin counts it
+	if err := peer.CastVote(ctx, proposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		origin.proposals.mu.RLock()
+		_, counted := proposal.Votes["otter-2"]
+		origin.proposals.mu.RUnlock()
+		if counted {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("otter-2's vote never reached the origin")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if err := origin.CastVote(ctx, proposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	if proposal.Result != ResultAdopted {
+		t.Fatalf("origin result = %s, want adopted", proposal.Result)
+	}
+
+	closed := waitForProposal(t, peer, proposal.ProposalID, ProposalClosed)
+	if closed.Result != ResultAdopted || len(closed.Ballots) != 2 {
+		t.Errorf("mirror = %+v, want adopted with both ballots", closed)
+	}
+	for deadline := time.Now().Add(2 * time.Second); peer.GetActiveRulesForRaft("otter-1")["safety"] == nil; {
+		if time.Now().After(deadline) {
+			t.Fatal("adopted rule never reached otter-2")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestProposalSync_MirrorDoesNotDecide(t *testing.T) {
+	sender, receiver := federatedPair(t)
+	ctx := context.Background()
+
+	rule := &Rule{RuleID: "r1", RaftID: "otter-1", Scope: "safety", Version: 1, Timestamp: time.Now(), Body: "be kind", ProposedBy: "otter-1"}
+	if err := sender.signRule(rule); err != nil {
+		t.Fatal(err)
+	}
+	env, err := sender.sealEnvelope(MessageProposalOpened, "otter-1", ProposalAnnouncement{
+		ProposalID:     "p1",
+		RaftID:         "otter-1",
+		Kind:           ProposalKindRule,
+		Rule:           rule,
+		ProposedBy:     "otter-1",
+		ProposedAt:     time.Now(),
+		Deadline:       time.Now().Add(time.Hour),
+		EligibleVoters: []string{"otter-2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := receiver.HandleEnvelope(ctx, env); err != nil {
+		t.Fatal(err)
+	}
+
+	// A lone eligible voter would carry a proposal it tallied itself
+	if err := receiver.CastVote(ctx, "p1", VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	mirror, _ := receiver.GetProposal("p1")
+	if mirror.Status != ProposalOpen || receiver.GetActiveRulesForRaft("otter-1")["safety"] != nil {
+		t.Errorf("mirror = %+v, want it left open for the origin", mirror)
+	}
+
+	// Only the origin can close it
+	outcome := ProposalOutcome{ProposalID: "p1", RaftID: "otter-1", Result: ResultAdopted, ClosedAt: time.Now()}
+	forged, err := receiver.sealEnvelope(MessageProposalClosed, "otter-1", outcome)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := receiver.HandleEnvelope(ctx, forged); err == nil {
+		t.Error("expected an outcome from a non-origin to be refused")
+	}
+	closing, err := sender.sealEnvelope(MessageProposalClosed, "otter-1", outcome)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := receiver.HandleEnvelope(ctx, closing); err != nil {
+		t.Fatal(err)
+	}
+	if mirror.Status != ProposalClosed || mirror.Result != ResultAdopted {
+		t.Errorf("mirror = %+v, want closed as adopted", mirror)
+	}
+}
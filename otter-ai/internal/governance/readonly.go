@@ -0,0 +1,27 @@
+package governance
+
+import "otter-ai/internal/errs"
+
+// ErrReadOnly is returned for proposals, votes and joins made through this
+// otter while governance is read-only
+var ErrReadOnly = errs.New(errs.ErrUnavailable, "governance is read-only")
+
+// SetReadOnly refuses or allows proposals, votes and joins made through
+// this otter. Proposals, ballots and outcomes relayed by peers are still
+// recorded, so its rafts aren't held up.
+func (g *Governance) SetReadOnly(readOnly bool) {
+	g.readOnly.Store(readOnly)
+}
+
+// ReadOnly reports whether proposals, votes and joins are refused
+func (g *Governance) ReadOnly() bool {
+	return g.readOnly.Load()
+}
+
+// writable returns ErrReadOnly while governance is read-only
+func (g *Governance) writable() error {
+	if g.readOnly.Load() {
+		return ErrReadOnly
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestReadOnly_RefusesProposalsAndVotes(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	ctx := context.Background()
+	proposal, err := g.ProposeRule(ctx, "otter-1", &Rule{Scope: "tone", Body: "be kind", ProposedBy: "otter-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.SetReadOnly(true)
+	if _, err := g.ProposeRule(ctx, "otter-1", &Rule{Scope: "style", Body: "be brief", ProposedBy: "otter-1"}); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("ProposeRule: err = %v, want ErrReadOnly", err)
+	}
+	if err := g.CastVote(ctx, proposal.ProposalID, VoteYes); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("CastVote: err = %v, want ErrReadOnly", err)
+	}
+	if proposal.Result == ResultAdopted {
+		t.Error("proposal adopted while read-only")
+	}
+
+	g.SetReadOnly(false)
+	if err := g.CastVote(ctx, proposal.ProposalID, VoteYes); err != nil {
+		t.Fatalf("CastVote after leaving read-only mode: %v", err)
+	}
+	if proposal.Result != ResultAdopted {
+		t.Errorf("result = %s, want adopted", proposal.Result)
+	}
+}
@@ -0,0 +1,219 @@
+package governance
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"otter-ai/internal/errs"
+	"otter-ai/internal/events"
+)
+
+// Negotiation review errors
+var (
+	ErrNegotiationNotFound = errs.New(errs.ErrNotFound, "negotiation not found")
+	// ErrNegotiationAwaitingApproval is returned by a join whose negotiated
+	// compromise must be approved before it is put to the rafts' votes
+	ErrNegotiationAwaitingApproval = errs.New(errs.ErrConflict, "negotiated compromise awaits approval")
+)
+
+// Negotiation review audit actions
+const (
+	AuditNegotiationApproved AuditAction = "negotiation.approved"
+	AuditNegotiationRejected AuditAction = "negotiation.rejected"
+)
+
+// NegotiationReport describes a negotiation for review: its conflicts, the
+// compromise it drafted and the full LLM transcript that led there
+type NegotiationReport struct {
+	NegotiationID   string            `json:"negotiation_id"`
+	Raft1ID         string            `json:"raft1_id"`
+	Raft2ID         string            `json:"raft2_id"`
+	Status          NegotiationStatus `json:"status"`
+	Conflicts       []ConflictReport  `json:"conflicts"`
+	ProposedRule    *Rule             `json:"proposed_rule,omitempty"`
+	Edited          bool              `json:"edited,omitempty"` // The reviewer changed the compromise's text
+	Raft1ProposalID string            `json:"raft1_proposal_id,omitempty"`
+	Raft2ProposalID string            `json:"raft2_proposal_id,omitempty"`
+	LLMTranscript   []string          `json:"llm_transcript"`
+	ReviewedBy      string            `json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time        `json:"reviewed_at,omitempty"`
+	FailureReason   string            `json:"failure_reason,omitempty"`
+	StartedAt       time.Time         `json:"started_at"`
+	CompletedAt     *time.Time        `json:"completed_at,omitempty"`
+}
+
+// NegotiationEvent is the data of negotiation.awaiting_approval events
+type NegotiationEvent struct {
+	NegotiationID string `json:"negotiation_id"`
+	Raft1ID       string `json:"raft1_id"`
+	Raft2ID       string `json:"raft2_id"`
+	Scope         string `json:"scope"`
+	Body          string `json:"body"`
+}
+
+// report describes the negotiation. The caller holds the negotiations lock.
+func (n *Negotiation) report() NegotiationReport {
+	report := NegotiationReport{
+		NegotiationID: n.NegotiationID,
+		Raft1ID:       n.Raft1ID,
+		Raft2ID:       n.Raft2ID,
+		Status:        n.Status,
+		Conflicts:     make([]ConflictReport, 0, len(n.Conflicts)),
+		Edited:        n.Edited,
+		LLMTranscript: append([]string{}, n.LLMTranscript...),
+		ReviewedBy:    n.ReviewedBy,
+		ReviewedAt:    n.ReviewedAt,
+		FailureReason: n.FailureReason,
+		StartedAt:     n.StartedAt,
+		CompletedAt:   n.CompletedAt,
+	}
+	for _, conflict := range n.Conflicts {
+		if conflict != nil {
+			report.Conflicts = append(report.Conflicts, conflictReport(n, conflict))
+		}
+	}
+	if n.ProposedRule != nil {
+		rule := *n.ProposedRule
+		report.ProposedRule = &rule
+	}
+	if n.Raft1Proposal != nil {
+		report.Raft1ProposalID = n.Raft1Proposal.ProposalID
+	}
+	if n.Raft2Proposal != nil {
+		report.Raft2ProposalID = n.Raft2Proposal.ProposalID
+	}
+	return report
+}
+
+// Negotiations returns the negotiations this otter started, newest first,
+// optionally only those with the given status
+func (g *Governance) Negotiations(status NegotiationStatus) []NegotiationReport {
+	g.negotiations.mu.RLock()
+	defer g.negotiations.mu.RUnlock()
+
+	reports := make([]NegotiationReport, 0)
+	for _, negotiation := range g.negotiations.negotiations {
+		if status == "" || negotiation.Status == status {
+			reports = append(reports, negotiation.report())
+		}
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].StartedAt.After(reports[j].StartedAt)
+	})
+	return reports
+}
+
+// Negotiation returns one negotiation with its full LLM transcript
+func (g *Governance) Negotiation(negotiationID string) (NegotiationReport, error) {
+	g.negotiations.mu.RLock()
+	defer g.negotiations.mu.RUnlock()
+
+	negotiation, ok := g.negotiations.negotiations[negotiationID]
+	if !ok {
+		return NegotiationReport{}, fmt.Errorf("%w: %s", ErrNegotiationNotFound, negotiationID)
+	}
+	return negotiation.report(), nil
+}
+
+// awaitApproval announces a drafted compromise so a person can review it
+func (g *Governance) awaitApproval(ctx context.Context, negotiation *Negotiation) {
+	g.negotiations.mu.RLock()
+	event := NegotiationEvent{
+		NegotiationID: negotiation.NegotiationID,
+		Raft1ID:       negotiation.Raft1ID,
+		Raft2ID:       negotiation.Raft2ID,
+		Scope:         negotiation.ProposedRule.Scope,
+		Body:          negotiation.ProposedRule.Body,
+	}
+	g.negotiations.mu.RUnlock()
+
+	g.log().InfoContext(ctx, "negotiated compromise awaits approval", "negotiation_id", negotiation.NegotiationID, "raft_id", negotiation.Raft2ID)
+	g.publish(events.NegotiationAwaitingApproval, event)
+}
+
+// reviewNegotiation takes a negotiation awaiting approval for review,
+// moving it to status so a second reviewer cannot act on it too
+func (g *Governance) reviewNegotiation(negotiationID, by string, status NegotiationStatus) (*Negotiation, error) {
+	g.negotiations.mu.Lock()
+	defer g.negotiations.mu.Unlock()
+
+	negotiation, ok := g.negotiations.negotiations[negotiationID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNegotiationNotFound, negotiationID)
+	}
+	if negotiation.Status != NegotiationResolved || negotiation.ProposedRule == nil {
+		return nil, errs.Errorf(errs.ErrConflict, "negotiation %s is %s, not awaiting approval", negotiationID, negotiation.Status)
+	}
+	now := time.Now()
+	negotiation.Status = status
+	negotiation.ReviewedBy = by
+	negotiation.ReviewedAt = &now
+	return negotiation, nil
+}
+
+// ApproveNegotiation approves a drafted compromise and proposes it to both
+// rafts. A non-empty body replaces the compromise's text first; the scope
+// stays the conflict's.
+func (g *Governance) ApproveNegotiation(ctx context.Context, negotiationID, by, body string) (NegotiationReport, error) {
+	if err := g.writable(); err != nil {
+		return NegotiationReport{}, err
+	}
+	if strings.TrimSpace(by) == "" {
+		by = g.config.ID
+	}
+	negotiation, err := g.reviewNegotiation(negotiationID, by, NegotiationVoting)
+	if err != nil {
+		return NegotiationReport{}, err
+	}
+
+	g.negotiations.mu.Lock()
+	if body = strings.TrimSpace(body); body != "" && body != negotiation.ProposedRule.Body {
+		rule := *negotiation.ProposedRule
+		rule.Body = body
+		negotiation.ProposedRule = &rule
+		negotiation.Edited = true
+	}
+	g.negotiations.mu.Unlock()
+
+	if err := g.executeDualRaftVote(ctx, negotiation, nil); err != nil {
+		// The compromise stays up for approval once the rafts can vote
+		g.negotiations.mu.Lock()
+		negotiation.Status = NegotiationResolved
+		negotiation.ReviewedBy, negotiation.ReviewedAt = "", nil
+		g.negotiations.mu.Unlock()
+		return NegotiationReport{}, err
+	}
+
+	report, err := g.Negotiation(negotiationID)
+	g.recordAudit(AuditNegotiationApproved, negotiation.Raft1ID, negotiationID, by, report)
+	return report, err
+}
+
+// RejectNegotiation turns a drafted compromise down; nothing is proposed
+// and the join does not go ahead
+func (g *Governance) RejectNegotiation(ctx context.Context, negotiationID, by, reason string) (NegotiationReport, error) {
+	if strings.TrimSpace(by) == "" {
+		by = g.config.ID
+	}
+	negotiation, err := g.reviewNegotiation(negotiationID, by, NegotiationRejected)
+	if err != nil {
+		return NegotiationReport{}, err
+	}
+
+	g.negotiations.mu.Lock()
+	negotiation.FailureReason = strings.TrimSpace(reason)
+	if negotiation.FailureReason == "" {
+		negotiation.FailureReason = "compromise rejected by " + by
+	}
+	now := time.Now()
+	negotiation.CompletedAt = &now
+	g.negotiations.mu.Unlock()
+
+	g.log().InfoContext(ctx, "negotiated compromise rejected", "negotiation_id", negotiationID, "by", by)
+	report, err := g.Negotiation(negotiationID)
+	g.recordAudit(AuditNegotiationRejected, negotiation.Raft1ID, negotiationID, by, report)
+	return report, err
+}
@@ -0,0 +1,76 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"otter-ai/internal/errs"
+)
+
+// pendingNegotiation registers a compromise awaiting approval for otter-1,
+// which shares its raft with otter-2 so the votes stay open
+func pendingNegotiation(t *testing.T) (*Governance, *Negotiation) {
+	t.Helper()
+	g := newTestGovernance("otter-1")
+	addPeers(g, "otter-2")
+	t.Cleanup(func() { close(g.shutdownCh) })
+	negotiation := negotiatedJoin("http://unused")
+	negotiation.LLMTranscript = []string{"prompt", "reply"}
+	g.negotiations.negotiations[negotiation.NegotiationID] = negotiation
+	return g, negotiation
+}
+
+func TestApproveNegotiation_EditsAndProposes(t *testing.T) {
+	g, negotiation := pendingNegotiation(t)
+	ctx := context.Background()
+
+	report, err := g.Negotiation(negotiation.NegotiationID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Status != NegotiationResolved || len(report.LLMTranscript) != 2 || len(report.Conflicts) != 1 {
+		t.Errorf("report = %+v", report)
+	}
+
+	report, err = g.ApproveNegotiation(ctx, negotiation.NegotiationID, "owner", "be bold, wearing a lifejacket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Status != NegotiationVoting || !report.Edited || report.ReviewedBy != "owner" || report.Raft1ProposalID == "" {
+		t.Errorf("report = %+v, want voting on the edited compromise", report)
+	}
+	proposal, ok := g.GetProposal(report.Raft1ProposalID)
+	if !ok || proposal.Rule.Body != "be bold, wearing a lifejacket" || proposal.Rule.Scope != "safety" {
+		t.Errorf("raft 1 proposal = %+v", proposal)
+	}
+
+	if _, err := g.ApproveNegotiation(ctx, negotiation.NegotiationID, "owner", ""); !errors.Is(err, errs.ErrConflict) {
+		t.Errorf("second approval: err = %v, want a conflict", err)
+	}
+}
+
+func TestRejectNegotiation(t *testing.T) {
+	g, negotiation := pendingNegotiation(t)
+	ctx := context.Background()
+
+	report, err := g.RejectNegotiation(ctx, negotiation.NegotiationID, "", "too vague")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Status != NegotiationRejected || report.FailureReason != "too vague" || report.ReviewedBy != "otter-1" || report.CompletedAt == nil {
+		t.Errorf("report = %+v", report)
+	}
+	if open := g.GetOpenProposals(); len(open) != 0 {
+		t.Errorf("a rejected compromise opened %d proposals", len(open))
+	}
+	if _, err := g.ApproveNegotiation(ctx, negotiation.NegotiationID, "", ""); !errors.Is(err, errs.ErrConflict) {
+		t.Errorf("approving a rejected compromise: err = %v", err)
+	}
+	if _, err := g.Negotiation("nope"); !errors.Is(err, ErrNegotiationNotFound) {
+		t.Errorf("unknown negotiation: err = %v", err)
+	}
+	if listed := g.Negotiations(NegotiationResolved); len(listed) != 0 {
+		t.Errorf("awaiting approval = %d, want 0", len(listed))
+	}
+}
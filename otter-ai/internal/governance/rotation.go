@@ -0,0 +1,173 @@
+package governance
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// MessageKeyRollover tells a raft's members that the sender now signs with
+// a new key. The envelope is signed with the new key.
+const MessageKeyRollover = "key.rolled_over"
+
+// KeyRollover is a statement, signed by an otter's old signing key, that
+// its signatures now come from a new key
+type KeyRollover struct {
+	OtterID   string    `json:"otter_id"`
+	OldKey    []byte    `json:"old_key"`
+	NewKey    []byte    `json:"new_key"`
+	RotatedAt time.Time `json:"rotated_at"`
+	Signature []byte    `json:"signature"` // By OldKey
+}
+
+// KeyRolloverAnnouncement is the payload of a key.rolled_over message
+type KeyRolloverAnnouncement struct {
+	Rollovers []KeyRollover `json:"rollovers"` // Oldest first, ending at the sender's current key
+}
+
+// rolloverSigningPayload returns the bytes a rollover signature covers
+func rolloverSigningPayload(rollover *KeyRollover) []byte {
+	return canonicalPayload(
+		"rollover",
+		rollover.OtterID,
+		hex.EncodeToString(rollover.OldKey),
+		hex.EncodeToString(rollover.NewKey),
+		strconv.FormatInt(rollover.RotatedAt.Unix(), 10),
+	)
+}
+
+// signKeyRollover has cs, the old key, vouch for newKey
+func signKeyRollover(cs *CryptoSystem, otterID string, newKey []byte) (*KeyRollover, error) {
+	rollover := &KeyRollover{
+		OtterID:   otterID,
+		OldKey:    cs.GetSigningPublicKey(),
+		NewKey:    newKey,
+		RotatedAt: time.Now().Truncate(time.Second),
+	}
+	sig, err := cs.Sign(rolloverSigningPayload(rollover))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign key rollover: %w", err)
+	}
+	rollover.Signature = sig
+	return rollover, nil
+}
+
+// followRollovers returns the key an otter signs with now, starting from a
+// key it was known by. Rollovers from keys the chain has not reached, such
+// as earlier ones already applied, are skipped; every rollover followed
+// must be for otterID and signed by the key it replaces.
+func followRollovers(from []byte, otterID string, rollovers []KeyRollover) ([]byte, error) {
+	key := from
+	for i := range rollovers {
+		rollover := &rollovers[i]
+		if !bytes.Equal(rollover.OldKey, key) {
+			continue
+		}
+		if rollover.OtterID != otterID {
+			return nil, fmt.Errorf("key rollover for %s presented by %s", rollover.OtterID, otterID)
+		}
+		if len(rollover.NewKey) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("key rollover for %s has an invalid new key", otterID)
+		}
+		if !VerifySignature(rolloverSigningPayload(rollover), rollover.Signature, rollover.OldKey) {
+			return nil, fmt.Errorf("%w: key rollover for %s", ErrInvalidSignature, otterID)
+		}
+		key = rollover.NewKey
+	}
+	return key, nil
+}
+
+// adoptRotatedKey brings this otter's membership records up to date after
+// `keytool rotate`: its own records move to the current signing key, and
+// records signed with a retired key are signed again with the current one.
+func (g *Governance) adoptRotatedKey(ctx context.Context) {
+	if len(g.rollovers) == 0 {
+		return
+	}
+	current := g.crypto.GetSigningPublicKey()
+
+	g.rafts.mu.RLock()
+	rafts := make([]*RaftInfo, 0, len(g.rafts.rafts))
+	for _, raft := range g.rafts.rafts {
+		rafts = append(rafts, raft)
+	}
+	g.rafts.mu.RUnlock()
+
+	for _, raft := range rafts {
+		raft.mu.Lock()
+		rotated, changed := false, false
+		if self, ok := raft.Members[g.config.ID]; ok && !bytes.Equal(self.SigningKey, current) {
+			if key, err := followRollovers(self.SigningKey, g.config.ID, g.rollovers); err == nil && bytes.Equal(key, current) {
+				self.SigningKey = current
+				rotated = true
+			}
+		}
+		for _, member := range raft.Members {
+			if len(member.Signature) == 0 || !rotated && g.crypto.Verify(memberSigningPayload(raft.RaftID, member), member.Signature, current) {
+				continue
+			}
+			if err := g.signMember(raft.RaftID, member); err != nil {
+				g.log().WarnContext(ctx, "failed to sign membership with the rotated key", "member_id", member.ID, "raft_id", raft.RaftID, "error", err)
+				continue
+			}
+			changed = true
+		}
+		raft.mu.Unlock()
+
+		if rotated {
+			g.recordAudit(AuditKeyRotated, raft.RaftID, g.config.ID, g.config.ID, KeyAudit{SigningKey: hex.EncodeToString(current)})
+		}
+		if changed {
+			if err := g.saveRaft(ctx, raft); err != nil {
+				g.log().WarnContext(ctx, "failed to persist rotated key", "raft_id", raft.RaftID, "error", err)
+			}
+		}
+	}
+}
+
+// announceKeyRollovers sends this otter's rollovers to the members of every
+// raft it shares with others. It runs on each start, so members that were
+// unreachable when the key was rotated catch up later; members that already
+// know the current key ignore it.
+func (g *Governance) announceKeyRollovers(ctx context.Context) {
+	announcement := KeyRolloverAnnouncement{Rollovers: g.rollovers}
+	for _, raftID := range g.heartbeatRafts() {
+		g.broadcast(ctx, raftID, MessageKeyRollover, announcement, g.membersInState(raftID, StateInactive)...)
+	}
+}
+
+// applyRemoteRollover moves a peer's membership to the key its rollovers
+// lead to. openEnvelope has already checked the envelope against that key.
+func (g *Governance) applyRemoteRollover(ctx context.Context, env *Envelope, announcement *KeyRolloverAnnouncement) error {
+	raft, err := g.liveRaft(env.RaftID)
+	if err != nil {
+		return err
+	}
+	raft.mu.Lock()
+	member, exists := raft.Members[env.SenderID]
+	if !exists {
+		raft.mu.Unlock()
+		return fmt.Errorf("%w: %s in raft %s", ErrUnknownSender, env.SenderID, env.RaftID)
+	}
+	key, err := followRollovers(member.SigningKey, env.SenderID, announcement.Rollovers)
+	if err != nil || bytes.Equal(key, member.SigningKey) {
+		raft.mu.Unlock()
+		return err
+	}
+	member.SigningKey = key
+	if err := g.signMember(env.RaftID, member); err != nil {
+		g.log().WarnContext(ctx, "failed to sign membership with the rotated key", "member_id", member.ID, "raft_id", env.RaftID, "error", err)
+	}
+	raft.mu.Unlock()
+
+	g.log().InfoContext(ctx, "member rotated its signing key", "member_id", env.SenderID, "raft_id", env.RaftID)
+	g.recordAudit(AuditKeyRotated, env.RaftID, env.SenderID, env.SenderID, KeyAudit{SigningKey: hex.EncodeToString(key)})
+	if err := g.saveRaft(ctx, raft); err != nil {
+		g.log().WarnContext(ctx, "failed to persist rotated key", "member_id", env.SenderID, "raft_id", env.RaftID, "error", err)
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package governance
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"otter-ai/internal/memory"
+	"otter-ai/internal/vectordb"
+)
+
+func TestRotateKeys(t *testing.T) {
+	dir := t.TempDir()
+	if _, _, err := RotateKeys(dir, "otter-1"); err == nil {
+		t.Error("expected an error rotating a key that does not exist")
+	}
+
+	original, err := LoadOrGenerateKeys(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, rollover, err := RotateKeys(dir, "otter-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(rollover.OldKey, original.GetSigningPublicKey()) || !bytes.Equal(rollover.NewKey, first.GetSigningPublicKey()) {
+		t.Errorf("rollover = %x -> %x", rollover.OldKey, rollover.NewKey)
+	}
+	if !bytes.Equal(first.GetPublicKey(), original.GetPublicKey()) {
+		t.Error("the encryption key should be kept")
+	}
+	second, _, err := RotateKeys(dir, "otter-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadOrGenerateKeys(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(loaded.GetSigningPublicKey(), second.GetSigningPublicKey()) {
+		t.Error("the new signing key should be stored")
+	}
+	if _, err := os.Stat(filepath.Join(dir, RetiredKeyDir, ExportSigningPublicKey(original)[:16]+".sign.key")); err != nil {
+		t.Errorf("retired key not kept: %v", err)
+	}
+
+	rollovers, err := LoadKeyRollovers(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rollovers) != 2 {
+		t.Fatalf("rollovers = %d, want 2", len(rollovers))
+	}
+	key, err := followRollovers(original.GetSigningPublicKey(), "otter-1", rollovers)
+	if err != nil || !bytes.Equal(key, second.GetSigningPublicKey()) {
+		t.Errorf("followRollovers = %x, %v; want the current key", key, err)
+	}
+	if _, err := followRollovers(original.GetSigningPublicKey(), "otter-2", rollovers); err == nil {
+		t.Error("expected rollovers presented for another otter to be rejected")
+	}
+}
+
+func countAction(actions []AuditAction, action AuditAction) int {
+	n := 0
+	for _, a := range actions {
+		if a == action {
+			n++
+		}
+	}
+	return n
+}
+
+// rotateInMemory gives g a new signing key and the rollover vouching for it
+func rotateInMemory(t *testing.T, g *Governance) {
+	t.Helper()
+	next, err := NewCryptoSystem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rollover, err := signKeyRollover(g.crypto, g.config.ID, next.GetSigningPublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.crypto = next
+	g.rollovers = append(g.rollovers, *rollover)
+}
+
+func TestHandleEnvelope_KeyRollover(t *testing.T) {
+	sender, receiver := federatedPair(t)
+	ctx := context.Background()
+	rotateInMemory(t, sender)
+	rotateInMemory(t, sender)
+
+	heartbeat, err := sender.sealEnvelope(MessageHeartbeat, "otter-1", Heartbeat{SentAt: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := receiver.HandleEnvelope(ctx, heartbeat); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("heartbeat before the rollover: err = %v, want ErrInvalidSignature", err)
+	}
+
+	env, err := sender.sealEnvelope(MessageKeyRollover, "otter-1", KeyRolloverAnnouncement{Rollovers: sender.rollovers})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := receiver.HandleEnvelope(ctx, env); err != nil {
+		t.Fatalf("HandleEnvelope: %v", err)
+	}
+	member := receiver.rafts.rafts["otter-1"].Members["otter-1"]
+	if !bytes.Equal(member.SigningKey, sender.crypto.GetSigningPublicKey()) {
+		t.Error("member should be known by its new key")
+	}
+	if err := receiver.verifyMember("otter-1", member); err != nil {
+		t.Errorf("membership should be signed again: %v", err)
+	}
+	if err := receiver.HandleEnvelope(ctx, heartbeat); err != nil {
+		t.Errorf("heartbeat after the rollover: %v", err)
+	}
+	if n := countAction(auditActions(t, receiver, AuditFilter{}), AuditKeyRotated); n != 1 {
+		t.Errorf("key.rotated entries = %d, want 1", n)
+	}
+
+	// Redelivery is harmless
+	if err := receiver.HandleEnvelope(ctx, env); err != nil {
+		t.Errorf("redelivery: %v", err)
+	}
+}
+
+func TestHandleEnvelope_KeyRolloverRejections(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("rollover not signed by the old key", func(t *testing.T) {
+		sender, receiver := federatedPair(t)
+		oldKey := sender.crypto.GetSigningPublicKey()
+		impostor := newTestGovernance("otter-1")
+		rotateInMemory(t, impostor)
+		forged := impostor.rollovers[0]
+		forged.OldKey = oldKey
+
+		env, err := impostor.sealEnvelope(MessageKeyRollover, "otter-1", KeyRolloverAnnouncement{Rollovers: []KeyRollover{forged}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := receiver.HandleEnvelope(ctx, env); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("err = %v, want ErrInvalidSignature", err)
+		}
+		if !bytes.Equal(receiver.rafts.rafts["otter-1"].Members["otter-1"].SigningKey, oldKey) {
+			t.Error("a forged rollover should not change the member's key")
+		}
+	})
+
+	t.Run("envelope not signed by the new key", func(t *testing.T) {
+		sender, receiver := federatedPair(t)
+		rotateInMemory(t, sender)
+		rollovers := sender.rollovers
+		sender.crypto, _ = NewCryptoSystem()
+
+		env, err := sender.sealEnvelope(MessageKeyRollover, "otter-1", KeyRolloverAnnouncement{Rollovers: rollovers})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := receiver.HandleEnvelope(ctx, env); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("err = %v, want ErrInvalidSignature", err)
+		}
+	})
+}
+
+func TestAnnounceKeyRollovers(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	heartbeatPeers(g, "otter-2")
+	transport := newRecordingTransport()
+	g.SetTransport(transport)
+	rotateInMemory(t, g)
+
+	g.announceKeyRollovers(context.Background())
+	env := transport.waitFor(t, "http://otter-2", MessageKeyRollover)
+	if !VerifySignature(envelopeSigningPayload(env), env.Signature, g.crypto.GetSigningPublicKey()) {
+		t.Error("announcement should be signed with the new key")
+	}
+}
+
+func TestNew_AdoptsRotatedKey(t *testing.T) {
+	dir := t.TempDir()
+	db, err := vectordb.NewSQLiteVectorDB(filepath.Join(dir, "otter.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	g, err := New(RaftConfig{ID: "otter-1", DataDir: dir}, memory.New(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	raft := &RaftInfo{RaftID: "raft-1", Members: map[string]*Member{}, Rules: make(map[string]*Rule), CreatedAt: now}
+	for _, id := range []string{"otter-1", "otter-2"} {
+		member := &Member{ID: id, State: StateActive, JoinedAt: now, LastSeenAt: now, InductedBy: "raft-1"}
+		if id == "otter-1" {
+			member.SigningKey = g.crypto.GetSigningPublicKey()
+		}
+		if err := g.signMember("raft-1", member); err != nil {
+			t.Fatal(err)
+		}
+		raft.Members[id] = member
+	}
+	if err := g.saveRaft(ctx, raft); err != nil {
+		t.Fatal(err)
+	}
+	g.Shutdown(ctx)
+
+	cs, _, err := RotateKeys(dir, "otter-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := New(RaftConfig{ID: "otter-1", DataDir: dir}, memory.New(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reloaded.Shutdown(ctx)
+
+	loaded := reloaded.rafts.rafts["raft-1"]
+	if loaded == nil || len(loaded.Members) != 2 {
+		t.Fatalf("memberships signed with the retired key should load: %+v", loaded)
+	}
+	current := cs.GetSigningPublicKey()
+	if !bytes.Equal(loaded.Members["otter-1"].SigningKey, current) {
+		t.Error("own membership should move to the new key")
+	}
+	for id, member := range loaded.Members {
+		if !VerifySignature(memberSigningPayload("raft-1", member), member.Signature, current) {
+			t.Errorf("membership of %s should be signed with the new key", id)
+		}
+	}
+	if n := countAction(auditActions(t, reloaded, AuditFilter{RaftID: "raft-1"}), AuditKeyRotated); n != 1 {
+		t.Errorf("key.rotated entries = %d, want 1", n)
+	}
+	if report, err := reloaded.VerifyAudit(ctx); err != nil || !report.Valid {
+		t.Errorf("VerifyAudit = %+v, %v", report, err)
+	}
+}
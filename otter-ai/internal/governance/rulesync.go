@@ -0,0 +1,159 @@
+package governance
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"otter-ai/internal/errs"
+)
+
+// MessageRuleAdopted carries a rule adopted in a raft to its other members
+const MessageRuleAdopted = "rule.adopted"
+
+// ErrUnprovenRule indicates a rule from a peer carries no proof that its
+// raft adopted it
+var ErrUnprovenRule = errs.New(errs.ErrUnauthorized, "rule adoption is not proven")
+
+// RuleAdoption is the payload of a rule.adopted federation message
+type RuleAdoption struct {
+	RaftID     string    `json:"raft_id"`
+	ProposalID string    `json:"proposal_id,omitempty"`
+	Rule       Rule      `json:"rule"`
+	AdoptedAt  time.Time `json:"adopted_at"`
+}
+
+// announceRule broadcasts a rule this otter adopted to the raft's other
+// members. Peers that miss it pick it up in the next anti-entropy sync.
+func (g *Governance) announceRule(proposalID string, rule *Rule) {
+	if rule.AdoptedAt == nil || len(g.raftPeers(rule.RaftID)) == 0 {
+		return
+	}
+	adoption := RuleAdoption{
+		RaftID:     rule.RaftID,
+		ProposalID: proposalID,
+		Rule:       *rule,
+		AdoptedAt:  *rule.AdoptedAt,
+	}
+	go g.broadcast(context.Background(), rule.RaftID, MessageRuleAdopted, adoption)
+}
+
+// applyRemoteRule installs a rule a peer adopted. The rule must be signed,
+// by a key matching its signer's membership when the signer is a member,
+// and its adoption must be proven (see verifyRuleAdoption). A rule that
+// differs from the local copy is only taken when it is a newer version;
+// otherwise it is left for drift reconciliation to report.
+func (g *Governance) applyRemoteRule(ctx context.Context, env *Envelope, adoption *RuleAdoption) error {
+	rule := adoption.Rule
+	if adoption.RaftID != env.RaftID || rule.RaftID != env.RaftID {
+		return fmt.Errorf("rule for raft %s sent in an envelope for raft %s", rule.RaftID, env.RaftID)
+	}
+	if rule.RuleID == "" {
+		return fmt.Errorf("adopted rule has no ID")
+	}
+	if rule.ProposalID == "" {
+		rule.ProposalID = adoption.ProposalID
+	}
+	adoptedAt := adoption.AdoptedAt
+	rule.AdoptedAt = &adoptedAt
+
+	raft, err := g.liveRaft(env.RaftID)
+	if err != nil {
+		return err
+	}
+
+	raft.mu.RLock()
+	members := make(map[string]*Member, len(raft.Members))
+	for id, member := range raft.Members {
+		members[id] = member
+	}
+	local, exists := raft.Rules[rule.RuleID]
+	raft.mu.RUnlock()
+
+	if err := verifyRuleSigner(&rule, members); err != nil {
+		return fmt.Errorf("refusing rule %s from %s: %w", rule.RuleID, env.SenderID, err)
+	}
+	if err := g.verifyRuleAdoption(raft, &rule, env.SenderID); err != nil {
+		return fmt.Errorf("refusing rule %s from %s: %w", rule.RuleID, env.SenderID, err)
+	}
+
+	switch {
+	case !exists || local.AdoptedAt == nil:
+	case ruleLeafHash(local) == ruleLeafHash(&rule):
+		return nil // Already adopted
+	case rule.Version > local.Version:
+	default:
+		g.log().WarnContext(ctx, "adopted rule from peer conflicts with local copy", "rule_id", rule.RuleID, "raft_id", rule.RaftID, "sender_id", env.SenderID)
+		return nil
+	}
+
+	g.adoptReconciledRule(raft, &rule)
+	g.log().InfoContext(ctx, "adopted rule from peer", "rule_id", rule.RuleID, "raft_id", rule.RaftID, "sender_id", env.SenderID, "proposal_id", rule.ProposalID)
+	if err := g.saveRaft(ctx, raft); err != nil {
+		g.log().WarnContext(ctx, "failed to persist replicated rule", "rule_id", rule.RuleID, "raft_id", rule.RaftID, "error", err)
+	}
+	return nil
+}
+
+// ruleProposalID derives the ID of the proposal adopting a rule from the
+// rule's signed payload, so ballots on the proposal vouch for that rule
+func ruleProposalID(rule *Rule) string {
+	hash := sha256.Sum256(ruleSigningPayload(rule))
+	return hex.EncodeToString(hash[:16])
+}
+
+// verifyRuleAdoption checks that a rule received from senderID was adopted
+// by its raft. Either this otter mirrors the adopted proposal for exactly
+// this rule, opened on the sender's otter, or the rule carries ballots on
+// its proposal that adopt it under the raft's voting policy, counted over
+// the raft's active members.
+func (g *Governance) verifyRuleAdoption(raft *RaftInfo, rule *Rule, senderID string) error {
+	if rule.ProposalID == "" {
+		return fmt.Errorf("%w: rule %s names no proposal", ErrUnprovenRule, rule.RuleID)
+	}
+
+	g.proposals.mu.RLock()
+	proposal, exists := g.proposals.proposals[rule.ProposalID]
+	mirrored := exists && proposal.kind() == ProposalKindRule && proposal.RaftID == rule.RaftID &&
+		proposal.Origin == senderID && proposal.Result == ResultAdopted &&
+		proposal.Rule != nil && ruleLeafHash(proposal.Rule) == ruleLeafHash(rule)
+	g.proposals.mu.RUnlock()
+	if mirrored {
+		return nil
+	}
+
+	if rule.ProposalID != ruleProposalID(rule) {
+		return fmt.Errorf("%w: proposal %s is not for rule %s", ErrUnprovenRule, rule.ProposalID, rule.RuleID)
+	}
+	probe := &Proposal{ProposalID: rule.ProposalID, RaftID: rule.RaftID, Kind: ProposalKindRule, Rule: rule}
+	count := ballotTally(rule.ProposalID, rule.ProposedBy, rule.Ballots, snapshotVoters(raft, ""), g.memberSigningKeys(rule.RaftID))
+	if _, adopted, _ := g.proposalPolicy(probe).decide(count, g.needsSuperMajority(probe)); !adopted {
+		return fmt.Errorf("%w: rule %s carries %d verified YES ballots of %d eligible", ErrUnprovenRule, rule.RuleID, count.yes, count.eligible)
+	}
+	return nil
+}
+
+// SyncRules is an anti-entropy pass: it compares every raft's Merkle root
+// with each reachable peer and reconciles with the peers that hold rules
+// this otter is missing or has an older version of
+func (g *Governance) SyncRules(ctx context.Context) []*ReconcileResult {
+	var results []*ReconcileResult
+	for _, report := range g.CheckAllDrift(ctx) {
+		if report.InSync || report.Error != "" || (len(report.MissingLocal) == 0 && len(report.Differing) == 0) {
+			continue
+		}
+		result, err := g.ReconcileRules(ctx, report.RaftID, report.PeerID)
+		if err != nil {
+			g.log().WarnContext(ctx, "rule sync failed", "raft_id", report.RaftID, "peer_id", report.PeerID, "error", err)
+			continue
+		}
+		if len(result.Adopted) > 0 || len(result.Replaced) > 0 {
+			g.log().InfoContext(ctx, "synced rules from peer", "raft_id", report.RaftID, "peer_id", report.PeerID,
+				"adopted", len(result.Adopted), "replaced", len(result.Replaced), "conflicts", len(result.Conflicts))
+		}
+		results = append(results, result)
+	}
+	return results
+}
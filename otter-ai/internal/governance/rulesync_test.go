@@ -0,0 +1,175 @@
+package governance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// ruleEnvelope seals a rule.adopted message for rule from sender
+func ruleEnvelope(t *testing.T, sender *Governance, rule *Rule) *Envelope {
+	t.Helper()
+	env, err := sender.sealEnvelope(MessageRuleAdopted, rule.RaftID, RuleAdoption{
+		RaftID: rule.RaftID, ProposalID: "p1", Rule: *rule, AdoptedAt: *rule.AdoptedAt,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return env
+}
+
+func TestCastVote_BroadcastsAdoptedRule(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	peers := heartbeatPeers(g, "otter-2")
+	transport := newRecordingTransport()
+	g.SetTransport(transport)
+	ctx := context.Background()
+
+	proposal, err := g.ProposeRule(ctx, "otter-1", &Rule{Scope: "safety", Body: "be kind", ProposedBy: "otter-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CastVote(ctx, proposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	if err := peerVote(t, g, peers["otter-2"], proposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+
+	env := transport.waitFor(t, "http://otter-2", MessageRuleAdopted)
+	var adoption RuleAdoption
+	if err := json.Unmarshal(env.Payload, &adoption); err != nil {
+		t.Fatal(err)
+	}
+	if adoption.Rule.RuleID != proposal.Rule.RuleID || adoption.ProposalID != proposal.ProposalID {
+		t.Errorf("adoption = %+v", adoption)
+	}
+	// The rule carries the ballots that adopted it, bound to its proposal
+	if adoption.Rule.ProposalID != ruleProposalID(&adoption.Rule) || len(adoption.Rule.Ballots) != 2 {
+		t.Errorf("adopted rule proof = %s with %d ballots", adoption.Rule.ProposalID, len(adoption.Rule.Ballots))
+	}
+}
+
+func TestHandleEnvelope_AdoptsReplicatedRule(t *testing.T) {
+	sender, receiver := federatedPair(t)
+	third := newTestGovernance("otter-3")
+	receiver.rafts.rafts["otter-1"].Members["otter-3"].SigningKey = third.crypto.GetSigningPublicKey()
+	rule := addAdoptedRule(t, sender, "otter-1", "r1", "safety", "be kind", 1)
+
+	// The sender's word alone, or its own ballot, does not prove adoption
+	for _, voters := range [][]*Governance{nil, {sender}} {
+		if voters != nil {
+			proveRule(t, rule, voters...)
+		}
+		err := receiver.HandleEnvelope(context.Background(), ruleEnvelope(t, sender, rule))
+		if !errors.Is(err, ErrUnprovenRule) {
+			t.Fatalf("%d ballots: err = %v, want ErrUnprovenRule", len(voters), err)
+		}
+		if receiver.GetActiveRulesForRaft("otter-1")["safety"] != nil {
+			t.Fatalf("%d ballots: unproven rule should not be adopted", len(voters))
+		}
+	}
+
+	proveRule(t, rule, sender, receiver, third)
+	if err := receiver.HandleEnvelope(context.Background(), ruleEnvelope(t, sender, rule)); err != nil {
+		t.Fatal(err)
+	}
+	active := receiver.GetActiveRulesForRaft("otter-1")["safety"]
+	if active == nil || active.RuleID != "r1" || active.AdoptedAt == nil || len(active.Ballots) != 3 {
+		t.Fatalf("active rule = %+v, want r1 with its ballots", active)
+	}
+	local, _ := receiver.RuleSetDigest("otter-1")
+	remote, _ := sender.RuleSetDigest("otter-1")
+	if local.Root != remote.Root {
+		t.Error("roots should match after replication")
+	}
+
+	// A newer version replaces it; an older one does not
+	newer := addAdoptedRule(t, sender, "otter-1", "r1", "safety", "be very kind", 2)
+	proveRule(t, newer, sender, receiver, third)
+	if err := receiver.HandleEnvelope(context.Background(), ruleEnvelope(t, sender, newer)); err != nil {
+		t.Fatal(err)
+	}
+	if err := receiver.HandleEnvelope(context.Background(), ruleEnvelope(t, sender, rule)); err != nil {
+		t.Fatal(err)
+	}
+	if body := receiver.GetActiveRulesForRaft("otter-1")["safety"].Body; body != "be very kind" {
+		t.Errorf("active body = %q, want the newer version", body)
+	}
+}
+
+func TestHandleEnvelope_AdoptsRuleOfMirroredProposal(t *testing.T) {
+	sender, receiver := federatedPair(t)
+	rule := addAdoptedRule(t, sender, "otter-1", "r1", "safety", "be kind", 1)
+
+	// The receiver mirrors p1, opened on another member's otter
+	mirrored := *rule
+	receiver.proposals.proposals["p1"] = &Proposal{
+		ProposalID: "p1", RaftID: "otter-1", Kind: ProposalKindRule, Rule: &mirrored,
+		Origin: "otter-3", Status: ProposalClosed, Result: ResultAdopted,
+	}
+	if err := receiver.HandleEnvelope(context.Background(), ruleEnvelope(t, sender, rule)); !errors.Is(err, ErrUnprovenRule) {
+		t.Fatalf("proposal of another origin: err = %v, want ErrUnprovenRule", err)
+	}
+
+	receiver.proposals.proposals["p1"].Origin = "otter-1"
+	if err := receiver.HandleEnvelope(context.Background(), ruleEnvelope(t, sender, rule)); err != nil {
+		t.Fatal(err)
+	}
+	if active := receiver.GetActiveRulesForRaft("otter-1")["safety"]; active == nil || active.RuleID != "r1" {
+		t.Fatalf("active rule = %+v, want r1", active)
+	}
+}
+
+func TestHandleEnvelope_RejectsTamperedRule(t *testing.T) {
+	sender, receiver := federatedPair(t)
+	rule := addAdoptedRule(t, sender, "otter-1", "r1", "safety", "be kind", 1)
+	rule.Body = "be unkind"
+
+	err := receiver.HandleEnvelope(context.Background(), ruleEnvelope(t, sender, rule))
+	if !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+	if receiver.GetActiveRulesForRaft("otter-1")["safety"] != nil {
+		t.Error("tampered rule should not be adopted")
+	}
+}
+
+func TestHandleEnvelope_RejectsUnsignedRule(t *testing.T) {
+	sender, receiver := federatedPair(t)
+	rule := addAdoptedRule(t, sender, "otter-1", "r1", "safety", "be kind", 1)
+	rule.SignedBy, rule.SignerKey, rule.Signature = "", nil, nil
+
+	err := receiver.HandleEnvelope(context.Background(), ruleEnvelope(t, sender, rule))
+	if !errors.Is(err, ErrUnsigned) {
+		t.Errorf("expected ErrUnsigned, got %v", err)
+	}
+	if receiver.GetActiveRulesForRaft("otter-1")["safety"] != nil {
+		t.Error("unsigned rule should not be adopted")
+	}
+}
+
+func TestSyncRules_PullsMissingRules(t *testing.T) {
+	peer := newTestGovernance("peer")
+	addSharedRaft(peer, "otter-1", "", nil)
+	rule := addAdoptedRule(t, peer, "raft-1", "r1", "safety", "be kind", 1)
+	srv := servePeer(t, peer)
+
+	g := newTestGovernance("otter-1")
+	addSharedRaft(g, "peer", srv.URL, peer.crypto.GetSigningPublicKey())
+	proveRule(t, rule, peer, g)
+
+	results := g.SyncRules(context.Background())
+	if len(results) != 1 || len(results[0].Adopted) != 1 {
+		t.Fatalf("results = %+v, want r1 adopted", results)
+	}
+	if reports, _ := g.CheckDrift(context.Background(), "raft-1"); len(reports) != 1 || !reports[0].InSync {
+		t.Errorf("reports = %+v, want in sync", reports)
+	}
+
+	// Nothing to do once in sync
+	if results := g.SyncRules(context.Background()); len(results) != 0 {
+		t.Errorf("results = %+v after sync", results)
+	}
+}
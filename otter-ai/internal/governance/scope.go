@@ -0,0 +1,156 @@
+package governance
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Rule scopes are paths such as "communication/discord/general". A scope
+// ending in "/*" covers that path and everything below it, and "*" alone
+// covers every scope. Other scopes only cover themselves.
+const (
+	ScopeSeparator = "/"
+	ScopeWildcard  = "*"
+)
+
+// ValidateScope checks a rule scope: no empty segments, and a wildcard only
+// as the whole last segment
+func ValidateScope(scope string) error {
+	if strings.TrimSpace(scope) == "" {
+		return fmt.Errorf("scope is required")
+	}
+	segments := strings.Split(scope, ScopeSeparator)
+	for i, segment := range segments {
+		if strings.TrimSpace(segment) == "" {
+			return fmt.Errorf("invalid scope %q: empty segment", scope)
+		}
+		if strings.Contains(segment, ScopeWildcard) && (segment != ScopeWildcard || i != len(segments)-1) {
+			return fmt.Errorf("invalid scope %q: a wildcard must be the whole last segment", scope)
+		}
+	}
+	return nil
+}
+
+// splitScope returns a scope's segments without a trailing wildcard, and
+// whether it had one
+func splitScope(scope string) ([]string, bool) {
+	if scope == ScopeWildcard {
+		return nil, true
+	}
+	if prefix, ok := strings.CutSuffix(scope, ScopeSeparator+ScopeWildcard); ok {
+		return strings.Split(prefix, ScopeSeparator), true
+	}
+	return strings.Split(scope, ScopeSeparator), false
+}
+
+// hasSegmentPrefix reports whether segments begins with prefix
+func hasSegmentPrefix(segments, prefix []string) bool {
+	if len(prefix) > len(segments) {
+		return false
+	}
+	for i := range prefix {
+		if segments[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ScopeMatches reports whether a rule scope covers a conversation scope
+func ScopeMatches(pattern, scope string) bool {
+	prefix, wildcard := splitScope(pattern)
+	if !wildcard {
+		return pattern == scope
+	}
+	return hasSegmentPrefix(strings.Split(scope, ScopeSeparator), prefix)
+}
+
+// ScopesOverlap reports whether some conversation scope is covered by both
+// rule scopes
+func ScopesOverlap(a, b string) bool {
+	aPrefix, aWildcard := splitScope(a)
+	bPrefix, bWildcard := splitScope(b)
+	switch {
+	case !aWildcard && !bWildcard:
+		return a == b
+	case !aWildcard:
+		return ScopeMatches(b, a)
+	case !bWildcard:
+		return ScopeMatches(a, b)
+	}
+	return hasSegmentPrefix(aPrefix, bPrefix) || hasSegmentPrefix(bPrefix, aPrefix)
+}
+
+// ScopeSpecificity ranks rule scopes: deeper scopes are more specific, and
+// an exact scope is more specific than a wildcard over the same path
+func ScopeSpecificity(scope string) int {
+	prefix, wildcard := splitScope(scope)
+	specificity := 2 * len(prefix)
+	if !wildcard {
+		specificity++
+	}
+	return specificity
+}
+
+// narrowerScope returns the more specific of two overlapping scopes
+func narrowerScope(a, b string) string {
+	if ScopeSpecificity(b) > ScopeSpecificity(a) {
+		return b
+	}
+	return a
+}
+
+// RulesForScope returns the active rules that govern a conversation scope,
+// ordered by raft and scope. Of the rules whose scope covers it, only the
+// most specific apply, and an override beats the rule it overrides. An
+// empty scope returns every active rule.
+func (g *Governance) RulesForScope(scope string) []*Rule {
+	if g.rules == nil {
+		return nil
+	}
+	g.rules.mu.RLock()
+	var matching []*Rule
+	for _, rule := range g.rules.active {
+		if scope == "" || ScopeMatches(rule.Scope, scope) {
+			matching = append(matching, rule)
+		}
+	}
+	g.rules.mu.RUnlock()
+
+	if scope != "" {
+		// A base rule adopted after its override stays active, so overrides
+		// are dropped by ID before specificity is compared
+		overridden := make(map[string]bool)
+		for _, rule := range matching {
+			if rule.BaseRuleID != "" {
+				overridden[rule.BaseRuleID] = true
+			}
+		}
+		best := -1
+		candidates := matching[:0]
+		for _, rule := range matching {
+			if overridden[rule.RuleID] {
+				continue
+			}
+			candidates = append(candidates, rule)
+			if s := ScopeSpecificity(rule.Scope); s > best {
+				best = s
+			}
+		}
+		matching = nil
+		for _, rule := range candidates {
+			if ScopeSpecificity(rule.Scope) == best {
+				matching = append(matching, rule)
+			}
+		}
+	}
+
+	sort.Slice(matching, func(i, j int) bool {
+		if matching[i].RaftID != matching[j].RaftID {
+			return matching[i].RaftID < matching[j].RaftID
+		}
+		return matching[i].Scope < matching[j].Scope
+	})
+	return matching
+}
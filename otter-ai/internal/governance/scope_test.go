@@ -0,0 +1,144 @@
+package governance
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestValidateScope(t *testing.T) {
+	for _, scope := range []string{"safety", "communication/discord/*", "*", "data retention"} {
+		if err := ValidateScope(scope); err != nil {
+			t.Errorf("ValidateScope(%q): %v", scope, err)
+		}
+	}
+	for _, scope := range []string{"", "communication//discord", "/safety", "a/*/b", "disc*", "a/"} {
+		if err := ValidateScope(scope); err == nil {
+			t.Errorf("expected %q to be invalid", scope)
+		}
+	}
+}
+
+func TestScopeMatches(t *testing.T) {
+	tests := []struct {
+		pattern, scope string
+		want           bool
+	}{
+		{"safety", "safety", true},
+		{"safety", "safety/extra", false},
+		{"communication/*", "communication", true},
+		{"communication/*", "communication/discord/general", true},
+		{"communication/discord/*", "communication/slack", false},
+		{"communication/*", "communications", false},
+		{"*", "anything/at/all", true},
+	}
+	for _, tt := range tests {
+		if got := ScopeMatches(tt.pattern, tt.scope); got != tt.want {
+			t.Errorf("ScopeMatches(%q, %q) = %v, want %v", tt.pattern, tt.scope, got, tt.want)
+		}
+	}
+}
+
+func TestScopesOverlap(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"safety", "safety", true},
+		{"safety", "privacy", false},
+		{"communication/*", "communication/discord/*", true},
+		{"communication/discord/*", "communication/slack/*", false},
+		{"communication/discord", "communication/*", true},
+		{"*", "safety", true},
+	}
+	for _, tt := range tests {
+		if got := ScopesOverlap(tt.a, tt.b); got != tt.want {
+			t.Errorf("ScopesOverlap(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+		if got := ScopesOverlap(tt.b, tt.a); got != tt.want {
+			t.Errorf("ScopesOverlap(%q, %q) = %v, want %v", tt.b, tt.a, got, tt.want)
+		}
+	}
+
+	if !(ScopeSpecificity("a/b/*") > ScopeSpecificity("a") && ScopeSpecificity("a") > ScopeSpecificity("a/*") && ScopeSpecificity("a/*") > ScopeSpecificity("*")) {
+		t.Error("specificity does not rank deeper and exact scopes first")
+	}
+}
+
+func TestRulesForScope_Precedence(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	g.activateRule(&Rule{RuleID: "all", RaftID: "otter-1", Scope: "*", Body: "be kind"})
+	g.activateRule(&Rule{RuleID: "comms", RaftID: "otter-1", Scope: "communication/*", Body: "be brief"})
+	g.activateRule(&Rule{RuleID: "discord", RaftID: "otter-1", Scope: "communication/discord/*", Body: "use emoji"})
+	// An override whose base arrives afterwards, so both are active
+	g.activateRule(&Rule{RuleID: "peer-discord-v2", RaftID: "raft-3", Scope: "communication/discord/*", Body: "no links at all", BaseRuleID: "peer-discord"})
+	g.activateRule(&Rule{RuleID: "peer-discord", RaftID: "raft-2", Scope: "communication/discord/*", Body: "no links"})
+
+	ids := func(rules []*Rule) []string {
+		var out []string
+		for _, rule := range rules {
+			out = append(out, rule.RuleID)
+		}
+		return out
+	}
+
+	// The most specific rules win, and the override beats its base
+	if got := ids(g.RulesForScope("communication/discord/general")); len(got) != 2 || got[0] != "discord" || got[1] != "peer-discord-v2" {
+		t.Errorf("discord rules = %v", got)
+	}
+	if got := ids(g.RulesForScope("communication/slack")); len(got) != 1 || got[0] != "comms" {
+		t.Errorf("slack rules = %v", got)
+	}
+	if got := ids(g.RulesForScope("safety")); len(got) != 1 || got[0] != "all" {
+		t.Errorf("safety rules = %v", got)
+	}
+	if got := g.RulesForScope(""); len(got) != 5 {
+		t.Errorf("expected every active rule without a scope, got %d", len(got))
+	}
+
+	// An override beats its base even when the base is more specific
+	g.activateRule(&Rule{RuleID: "safety-wide", RaftID: "raft-2", Scope: "safety/*", Body: "ask first", BaseRuleID: "safety-narrow"})
+	g.activateRule(&Rule{RuleID: "safety-narrow", RaftID: "raft-2", Scope: "safety/tools", Body: "never run tools"})
+	if got := ids(g.RulesForScope("safety/tools")); len(got) != 1 || got[0] != "safety-wide" {
+		t.Errorf("safety/tools rules = %v", got)
+	}
+}
+
+func TestDetectRuleConflicts_OverlappingScopes(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	g.rafts.rafts["otter-1"].Rules["r1"] = &Rule{RuleID: "r1", Scope: "communication/*", Body: "be brief"}
+
+	conflicts := g.detectRuleConflicts("raft-2", map[string]*Rule{
+		"r2": {RuleID: "r2", Scope: "communication/discord/*", Body: "write at length"},
+		"r3": {RuleID: "r3", Scope: "safety", Body: "be careful"},
+	})
+	if len(conflicts) != 1 || conflicts[0].Rule2.RuleID != "r2" || conflicts[0].ConflictScope != "communication/discord/*" {
+		t.Errorf("conflicts = %+v", conflicts)
+	}
+}
+
+func TestCheckResponse_ScopedRules(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	now := time.Now()
+	g.activateRule(&Rule{RuleID: "comms", RaftID: "otter-1", Scope: "communication/*", Body: "deny text: lol", Timestamp: now})
+	g.activateRule(&Rule{RuleID: "discord", RaftID: "otter-1", Scope: "communication/discord/*", Body: "deny text: http", Timestamp: now})
+	ctx := context.Background()
+
+	// In Discord only the Discord rule applies
+	if violations := g.CheckResponse(ctx, "communication/discord", "hi", "lol see http://x"); len(violations) != 1 || violations[0].RuleID != "discord" {
+		t.Errorf("discord violations = %+v", violations)
+	}
+	if violations := g.CheckResponse(ctx, "communication/slack", "hi", "lol see http://x"); len(violations) != 1 || violations[0].RuleID != "comms" {
+		t.Errorf("slack violations = %+v", violations)
+	}
+	if violations := g.CheckResponse(ctx, "", "hi", "lol see http://x"); len(violations) != 2 {
+		t.Errorf("unscoped violations = %+v", violations)
+	}
+}
+
+func TestProposeRule_RejectsInvalidScope(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	if _, err := g.ProposeRule(context.Background(), "otter-1", &Rule{Scope: "communication/*/discord", Body: "be kind", ProposedBy: "otter-1"}); err == nil {
+		t.Error("expected a scope with a wildcard in the middle to be rejected")
+	}
+}
@@ -0,0 +1,202 @@
+package governance
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	"otter-ai/internal/errs"
+)
+
+// ErrUnsigned indicates a record carries no signature (written before signing existed)
+var ErrUnsigned = errs.New(errs.ErrUnauthorized, "record is unsigned")
+
+// ErrInvalidSignature indicates a record's signature failed verification
+var ErrInvalidSignature = errs.New(errs.ErrUnauthorized, "invalid signature")
+
+// canonicalPayload length-prefixes each field so distinct field lists never
+// serialize to the same bytes.
+func canonicalPayload(fields ...string) []byte {
+	var b bytes.Buffer
+	for _, f := range fields {
+		b.WriteString(strconv.Itoa(len(f)))
+		b.WriteByte(':')
+		b.WriteString(f)
+		b.WriteByte(';')
+	}
+	return b.Bytes()
+}
+
+// ruleSigningPayload returns the bytes a rule signature covers. Timestamps
+// are taken at second precision, matching what persistence stores.
+func ruleSigningPayload(rule *Rule) []byte {
+	return canonicalPayload(
+		"rule",
+		rule.RuleID,
+		rule.RaftID,
+		rule.Scope,
+		strconv.Itoa(rule.Version),
+		strconv.FormatInt(rule.Timestamp.Unix(), 10),
+		rule.Body,
+		rule.BaseRuleID,
+		rule.ProposedBy,
+		rule.SignedBy,
+	)
+}
+
+// memberSigningPayload returns the bytes a membership signature covers
+func memberSigningPayload(raftID string, member *Member) []byte {
+	return canonicalPayload(
+		"member",
+		raftID,
+		member.ID,
+		hex.EncodeToString(member.PublicKey),
+		hex.EncodeToString(member.SigningKey),
+		strconv.FormatInt(member.JoinedAt.Unix(), 10),
+		member.InductedBy,
+	)
+}
+
+// joinChallengePayload returns the bytes a join challenge answer covers
+func joinChallengePayload(raftID, requesterID, nonce string, publicKey, signingKey []byte) []byte {
+	return canonicalPayload(
+		"join",
+		raftID,
+		requesterID,
+		nonce,
+		hex.EncodeToString(publicKey),
+		hex.EncodeToString(signingKey),
+	)
+}
+
+// voteSigningPayload returns the bytes a vote signature covers
+func voteSigningPayload(ballot *SignedVote) []byte {
+	return canonicalPayload(
+		"vote",
+		ballot.ProposalID,
+		string(ballot.Vote),
+		strconv.FormatInt(ballot.Timestamp.Unix(), 10),
+	)
+}
+
+// verifyVote checks a ballot against the voter's registered signing key
+func verifyVote(ballot *SignedVote, signingKey []byte) error {
+	if len(ballot.Signature) == 0 {
+		return fmt.Errorf("%w: vote from %s", ErrUnsigned, ballot.VoterID)
+	}
+	if len(signingKey) == 0 {
+		return fmt.Errorf("%w: voter %s has no registered signing key", ErrInvalidSignature, ballot.VoterID)
+	}
+	if !VerifySignature(voteSigningPayload(ballot), ballot.Signature, signingKey) {
+		return fmt.Errorf("%w: vote from %s", ErrInvalidSignature, ballot.VoterID)
+	}
+	return nil
+}
+
+// signRule signs a rule as this otter
+func (g *Governance) signRule(rule *Rule) error {
+	rule.SignedBy = g.config.ID
+	rule.SignerKey = g.crypto.GetSigningPublicKey()
+
+	sig, err := g.crypto.Sign(ruleSigningPayload(rule))
+	if err != nil {
+		return fmt.Errorf("failed to sign rule: %w", err)
+	}
+	rule.Signature = sig
+	return nil
+}
+
+// verifyRule checks a rule's signature against the key it carries, which
+// proves only that the record is intact. Rules received from peers go
+// through verifyRuleSigner. Returns ErrUnsigned for legacy rules so callers
+// can decide how to treat them.
+func verifyRule(rule *Rule) error {
+	if len(rule.Signature) == 0 {
+		return ErrUnsigned
+	}
+	if rule.SignedBy == "" || len(rule.SignerKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("%w: rule %s has no signer", ErrInvalidSignature, rule.RuleID)
+	}
+	if !VerifySignature(ruleSigningPayload(rule), rule.Signature, rule.SignerKey) {
+		return fmt.Errorf("%w: rule %s", ErrInvalidSignature, rule.RuleID)
+	}
+	return nil
+}
+
+// verifyRuleSigner checks a rule's signature against the signing key
+// registered for the member that signed it, so a rule cannot be signed
+// under a key of the sender's choosing. Rules signed by anyone who is not
+// a member, or by a member with no registered key, are rejected.
+func verifyRuleSigner(rule *Rule, members map[string]*Member) error {
+	if len(rule.Signature) == 0 {
+		return ErrUnsigned
+	}
+	member, ok := members[rule.SignedBy]
+	if !ok {
+		return fmt.Errorf("%w: rule %s is signed by %q, who is not a member", ErrInvalidSignature, rule.RuleID, rule.SignedBy)
+	}
+	if len(member.SigningKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("%w: rule %s is signed by %s, who has no registered signing key", ErrInvalidSignature, rule.RuleID, rule.SignedBy)
+	}
+	if len(rule.SignerKey) > 0 && !bytes.Equal(member.SigningKey, rule.SignerKey) {
+		return fmt.Errorf("%w: rule %s signer key does not match member %s", ErrInvalidSignature, rule.RuleID, rule.SignedBy)
+	}
+	if !VerifySignature(ruleSigningPayload(rule), rule.Signature, member.SigningKey) {
+		return fmt.Errorf("%w: rule %s", ErrInvalidSignature, rule.RuleID)
+	}
+	return nil
+}
+
+// verifyStoredRule checks a rule read back from the database. Rules were
+// checked against their signer's membership when received; a signer this
+// otter keeps no key for, such as a member of a joined raft other than the
+// one that inducted it, is checked against the key the rule carries.
+func verifyStoredRule(rule *Rule, members map[string]*Member) error {
+	if member, ok := members[rule.SignedBy]; ok && len(member.SigningKey) > 0 {
+		return verifyRuleSigner(rule, members)
+	}
+	return verifyRule(rule)
+}
+
+// signMember records this otter's signature over a membership record
+func (g *Governance) signMember(raftID string, member *Member) error {
+	sig, err := g.crypto.Sign(memberSigningPayload(raftID, member))
+	if err != nil {
+		return fmt.Errorf("failed to sign membership: %w", err)
+	}
+	member.Signature = sig
+	return nil
+}
+
+// verifyMember checks that a membership record was signed by this otter,
+// with its current signing key or one it rolled over from
+func (g *Governance) verifyMember(raftID string, member *Member) error {
+	if len(member.Signature) == 0 {
+		return ErrUnsigned
+	}
+	payload := memberSigningPayload(raftID, member)
+	if g.crypto.Verify(payload, member.Signature, g.crypto.GetSigningPublicKey()) {
+		return nil
+	}
+	for _, rollover := range g.rollovers {
+		if g.crypto.Verify(payload, member.Signature, rollover.OldKey) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: member %s of raft %s", ErrInvalidSignature, member.ID, raftID)
+}
+
+// verifyReceivedRules rejects a peer's rule set if any rule is unsigned,
+// mis-signed or signed by anyone but a member of the raft
+func (g *Governance) verifyReceivedRules(ctx context.Context, rules map[string]*Rule, members map[string]*Member, source string) error {
+	for _, rule := range rules {
+		if err := verifyRuleSigner(rule, members); err != nil {
+			g.log().WarnContext(ctx, "rejecting received rule", "rule_id", rule.RuleID, "source", source, "error", err)
+			return fmt.Errorf("rejecting rules from %s: %w", source, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,394 @@
+package governance
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func signedTestRule(t *testing.T, g *Governance) *Rule {
+	t.Helper()
+	rule := &Rule{
+		RuleID:     "r1",
+		RaftID:     "raft-1",
+		Scope:      "safety",
+		Version:    1,
+		Timestamp:  time.Now(),
+		Body:       "be kind",
+		ProposedBy: g.config.ID,
+	}
+	if err := g.signRule(rule); err != nil {
+		t.Fatalf("signRule: %v", err)
+	}
+	return rule
+}
+
+func TestSignRule_Verifies(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	rule := signedTestRule(t, g)
+
+	if rule.SignedBy != "otter-1" {
+		t.Errorf("SignedBy = %q", rule.SignedBy)
+	}
+	if err := verifyRule(rule); err != nil {
+		t.Errorf("verifyRule: %v", err)
+	}
+}
+
+func TestVerifyRule_Tampered(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	rule := signedTestRule(t, g)
+	rule.Body = "be unkind"
+
+	if err := verifyRule(rule); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func TestVerifyRule_Unsigned(t *testing.T) {
+	if err := verifyRule(&Rule{RuleID: "r1"}); !errors.Is(err, ErrUnsigned) {
+		t.Errorf("expected ErrUnsigned, got %v", err)
+	}
+}
+
+func TestVerifyRuleSigner_KeyMismatch(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	imposter := newTestGovernance("otter-1")
+	rule := signedTestRule(t, imposter)
+
+	members := map[string]*Member{
+		"otter-1": {ID: "otter-1", SigningKey: g.crypto.GetSigningPublicKey()},
+	}
+	if err := verifyRuleSigner(rule, members); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func TestProposeRule_SignsRule(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	proposal, err := g.ProposeRule(context.Background(), "otter-1", &Rule{Scope: "safety", Body: "be kind", Version: 1, ProposedBy: "otter-1"})
+	if err != nil {
+		t.Fatalf("ProposeRule: %v", err)
+	}
+	if err := verifyRule(proposal.Rule); err != nil {
+		t.Errorf("proposed rule should be signed: %v", err)
+	}
+}
+
+func TestSignMember_Verifies(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	member := &Member{ID: "otter-2", JoinedAt: time.Now(), PublicKey: []byte("pub"), InductedBy: "otter-1"}
+	if err := g.signMember("raft-1", member); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.verifyMember("raft-1", member); err != nil {
+		t.Errorf("verifyMember: %v", err)
+	}
+
+	member.InductedBy = "someone-else"
+	if err := g.verifyMember("raft-1", member); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature after tampering, got %v", err)
+	}
+}
+
+func TestRequestJoin_SignsMembership(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	req := answeredJoinRequest(t, g, newTestGovernance("otter-2"), "otter-1")
+	if _, err := g.RequestJoin(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+
+	members, _ := g.GetRaftMembers("otter-1")
+	for _, m := range members {
+		if m.ID == "otter-2" {
+			if err := g.verifyMember("otter-1", m); err != nil {
+				t.Errorf("verifyMember: %v", err)
+			}
+			return
+		}
+	}
+	t.Error("otter-2 not added")
+}
+
+// rulesPeer serves a raft's rules and the signing keys of its members,
+// signed by the first member, which stands as the inducting otter
+func rulesPeer(t *testing.T, rule *Rule, members ...*Governance) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/governance/rafts/raft-1/keys" {
+			listed := make([]MemberKey, 0, len(members))
+			for _, m := range members {
+				listed = append(listed, MemberKey{ID: m.config.ID, SigningKey: hex.EncodeToString(m.crypto.GetSigningPublicKey())})
+			}
+			writeSigned(w, r, members[0], listed)
+			return
+		}
+		writeSigned(w, r, members[0], []*Rule{rule})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// inductorRecord is the member record the join handshake yields for g
+func inductorRecord(g *Governance) *Member {
+	return &Member{ID: g.config.ID, SigningKey: g.crypto.GetSigningPublicKey()}
+}
+
+// fetchAndVerify fetches raft-1's rules from srv and verifies them as a
+// join through inductor would
+func fetchAndVerify(t *testing.T, g *Governance, srv *httptest.Server, inductor *Member) (map[string]*Rule, error) {
+	t.Helper()
+	rules, err := g.fetchRaftRules(context.Background(), srv.URL, "raft-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rules, g.verifyFetchedRules(context.Background(), srv.URL, "raft-1", rules, inductor)
+}
+
+func TestVerifyFetchedRules_RejectsTamperedRule(t *testing.T) {
+	peer := newTestGovernance("peer")
+	rule := signedTestRule(t, peer)
+	rule.Body = "tampered in transit"
+	srv := rulesPeer(t, rule, peer)
+
+	g := newTestGovernance("otter-1")
+	if _, err := fetchAndVerify(t, g, srv, inductorRecord(peer)); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func TestVerifyFetchedRules_AcceptsSignedRule(t *testing.T) {
+	peer := newTestGovernance("peer")
+	rule := signedTestRule(t, peer)
+	srv := rulesPeer(t, rule, peer)
+
+	g := newTestGovernance("otter-1")
+	fetched, err := fetchAndVerify(t, g, srv, inductorRecord(peer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fetched["r1"]; got == nil || got.SignedBy != "peer" {
+		t.Errorf("expected signed rule from peer, got %+v", got)
+	}
+}
+
+func TestVerifyFetchedRules_AcceptsRuleSignedByAnotherMember(t *testing.T) {
+	peer := newTestGovernance("peer")
+	other := newTestGovernance("other")
+	rule := signedTestRule(t, other)
+	srv := rulesPeer(t, rule, peer, other)
+
+	g := newTestGovernance("otter-1")
+	if _, err := fetchAndVerify(t, g, srv, inductorRecord(peer)); err != nil {
+		t.Errorf("rule signed by a member the inductor vouches for: %v", err)
+	}
+}
+
+func TestVerifyFetchedRules_RejectsUnsignedRule(t *testing.T) {
+	peer := newTestGovernance("peer")
+	srv := rulesPeer(t, &Rule{RuleID: "r1", Scope: "safety", Body: "be kind", Version: 1}, peer)
+
+	g := newTestGovernance("otter-1")
+	if _, err := fetchAndVerify(t, g, srv, inductorRecord(peer)); !errors.Is(err, ErrUnsigned) {
+		t.Errorf("unsigned rule: err = %v, want ErrUnsigned", err)
+	}
+}
+
+func TestVerifyFetchedRules_RejectsNonMemberSigner(t *testing.T) {
+	peer := newTestGovernance("peer")
+	outsider := newTestGovernance("outsider")
+	rule := signedTestRule(t, outsider)
+	srv := rulesPeer(t, rule, peer)
+
+	g := newTestGovernance("otter-1")
+	if _, err := fetchAndVerify(t, g, srv, inductorRecord(peer)); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("rule signed by a non-member: err = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestVerifyFetchedRules_RejectsKeysTheInductorDidNotSign(t *testing.T) {
+	// The otter serving the rules lists a forger as a member, but the
+	// inducting otter's key did not sign that list
+	forger := newTestGovernance("forger")
+	rule := signedTestRule(t, forger)
+	srv := rulesPeer(t, rule, forger)
+
+	g := newTestGovernance("otter-1")
+	if _, err := fetchAndVerify(t, g, srv, inductorRecord(newTestGovernance("peer"))); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("keys not signed by the inductor: err = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestVerifyFetchedRules_RequiresInductor(t *testing.T) {
+	peer := newTestGovernance("peer")
+	srv := rulesPeer(t, signedTestRule(t, peer), peer)
+
+	g := newTestGovernance("otter-1")
+	if _, err := fetchAndVerify(t, g, srv, nil); !errors.Is(err, ErrUnsigned) {
+		t.Errorf("no inductor: err = %v, want ErrUnsigned", err)
+	}
+}
+
+func TestVerifyRuleSigner_NonMemberAndKeyless(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	outsider := newTestGovernance("outsider")
+	rule := signedTestRule(t, outsider)
+
+	// The rule verifies against the key it carries, but its signer is no member
+	if err := verifyRule(rule); err != nil {
+		t.Fatalf("verifyRule: %v", err)
+	}
+	members := map[string]*Member{"otter-1": {ID: "otter-1", SigningKey: g.crypto.GetSigningPublicKey()}}
+	if err := verifyRuleSigner(rule, members); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("non-member signer: err = %v, want ErrInvalidSignature", err)
+	}
+
+	members["outsider"] = &Member{ID: "outsider"}
+	if err := verifyRuleSigner(rule, members); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("keyless signer: err = %v, want ErrInvalidSignature", err)
+	}
+
+	// A forger carrying its own key cannot sign as a member
+	forged := signedTestRule(t, newTestGovernance("otter-1"))
+	if err := verifyRuleSigner(forged, members); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("forged signer: err = %v, want ErrInvalidSignature", err)
+	}
+	forged.SignerKey = nil
+	if err := verifyRuleSigner(forged, members); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("forged signer without a carried key: err = %v, want ErrInvalidSignature", err)
+	}
+
+	members["outsider"].SigningKey = outsider.crypto.GetSigningPublicKey()
+	if err := verifyRuleSigner(rule, members); err != nil {
+		t.Errorf("registered signer: %v", err)
+	}
+}
+
+// openThreeMemberProposal creates a raft where otter-1 and otter-2 cannot
+// close a proposal on their own, so ballots can be replaced.
+func openThreeMemberProposal(t *testing.T, g, peer *Governance) *Proposal {
+	t.Helper()
+	now := time.Now()
+	raft := g.rafts.rafts["otter-1"]
+	raft.Members["otter-2"] = &Member{ID: "otter-2", State: StateActive, JoinedAt: now, LastSeenAt: now, SigningKey: peer.crypto.GetSigningPublicKey()}
+	raft.Members["otter-3"] = &Member{ID: "otter-3", State: StateActive, JoinedAt: now, LastSeenAt: now}
+
+	proposal, err := g.ProposeRule(context.Background(), "otter-1", &Rule{Scope: "safety", Body: "be kind", Version: 1, ProposedBy: "otter-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return proposal
+}
+
+func ballotAt(t *testing.T, signer *Governance, proposalID string, vote VoteType, ts time.Time) SignedVote {
+	t.Helper()
+	ballot := SignedVote{ProposalID: proposalID, VoterID: signer.config.ID, Vote: vote, Timestamp: ts}
+	sig, err := signer.crypto.Sign(voteSigningPayload(&ballot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ballot.Signature = sig
+	return ballot
+}
+
+func TestVote_AcceptsPeerSignedBallot(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	peer := newTestGovernance("otter-2")
+	proposal := openThreeMemberProposal(t, g, peer)
+
+	ballot, err := peer.SignVote(proposal.ProposalID, VoteNo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Vote(context.Background(), ballot); err != nil {
+		t.Fatalf("Vote: %v", err)
+	}
+
+	p, _ := g.GetProposal(proposal.ProposalID)
+	if p.Votes["otter-2"] != VoteNo || p.Ballots["otter-2"] == nil {
+		t.Error("signed ballot should be recorded")
+	}
+}
+
+func TestVote_RejectsUnsigned(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	proposal, _ := g.ProposeRule(context.Background(), "otter-1", &Rule{Scope: "safety", Body: "x", ProposedBy: "otter-1"})
+
+	err := g.Vote(context.Background(), SignedVote{ProposalID: proposal.ProposalID, VoterID: "otter-1", Vote: VoteYes, Timestamp: time.Now()})
+	if !errors.Is(err, ErrUnsigned) {
+		t.Errorf("expected ErrUnsigned, got %v", err)
+	}
+}
+
+func TestVote_RejectsForgedBallot(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	peer := newTestGovernance("otter-2")
+	proposal := openThreeMemberProposal(t, g, peer)
+
+	// Signed by a key that is not registered for otter-2
+	forger := newTestGovernance("otter-2")
+	ballot, _ := forger.SignVote(proposal.ProposalID, VoteYes)
+	if err := g.Vote(context.Background(), ballot); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+
+	// Altering the vote after signing breaks the signature
+	ballot, _ = peer.SignVote(proposal.ProposalID, VoteNo)
+	ballot.Vote = VoteYes
+	if err := g.Vote(context.Background(), ballot); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature for altered vote, got %v", err)
+	}
+}
+
+func TestVote_RejectsMemberWithoutSigningKey(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	peer := newTestGovernance("otter-3")
+	proposal := openThreeMemberProposal(t, g, newTestGovernance("otter-2"))
+
+	ballot, _ := peer.SignVote(proposal.ProposalID, VoteYes)
+	if err := g.Vote(context.Background(), ballot); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func TestVote_RejectsReplayedOlderBallot(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	peer := newTestGovernance("otter-2")
+	proposal := openThreeMemberProposal(t, g, peer)
+
+	now := time.Now()
+	older := ballotAt(t, peer, proposal.ProposalID, VoteYes, now.Add(-time.Second))
+	newer := ballotAt(t, peer, proposal.ProposalID, VoteNo, now)
+
+	if err := g.Vote(context.Background(), newer); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Vote(context.Background(), older); err == nil {
+		t.Error("older ballot should not replace a newer one")
+	}
+
+	p, _ := g.GetProposal(proposal.ProposalID)
+	if p.Votes["otter-2"] != VoteNo {
+		t.Errorf("vote = %s, want NO", p.Votes["otter-2"])
+	}
+}
+
+func TestVote_RejectsOutOfRangeTimestamp(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	peer := newTestGovernance("otter-2")
+	proposal := openThreeMemberProposal(t, g, peer)
+
+	future := ballotAt(t, peer, proposal.ProposalID, VoteYes, time.Now().Add(time.Hour))
+	if err := g.Vote(context.Background(), future); err == nil {
+		t.Error("expected error for future timestamp")
+	}
+
+	stale := ballotAt(t, peer, proposal.ProposalID, VoteYes, proposal.ProposedAt.Add(-time.Hour))
+	if err := g.Vote(context.Background(), stale); err == nil {
+		t.Error("expected error for timestamp before the proposal")
+	}
+}
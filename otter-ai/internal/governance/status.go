@@ -0,0 +1,145 @@
+package governance
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MemberSummary is a compact view of a raft member for status reports
+type MemberSummary struct {
+	ID    string          `json:"id"`
+	State MembershipState `json:"state"`
+}
+
+// RaftSummary describes a raft's topology and adopted rule set so that
+// operators can compare what different otters believe about the same raft.
+type RaftSummary struct {
+	RaftID           string            `json:"raft_id"`
+	Members          []MemberSummary   `json:"members"`
+	RuleCount        int               `json:"rule_count"`
+	RulesDigest      string            `json:"rules_digest"`
+	RuleFingerprints map[string]string `json:"rule_fingerprints"` // scope -> fingerprint of the adopted body
+	Archived         bool              `json:"archived"`
+	Provisional      bool              `json:"provisional,omitempty"`     // Held while a negotiated join is voted on
+	Archive          *RaftArchive      `json:"archive,omitempty"`         // When, why and by whom the raft was archived
+	VotingPolicy     VotingPolicy      `json:"voting_policy"`             // How the raft decides proposals
+	Metadata         RaftMetadata      `json:"metadata"`                  // Name, description, purpose and tags
+	Charter          *Charter          `json:"charter,omitempty"`         // The raft it inherits rules from
+	InheritedRules   int               `json:"inherited_rules,omitempty"` // Rules in force through the charter
+}
+
+// RaftSummaries returns a summary of every raft this otter belongs to
+func (g *Governance) RaftSummaries() []RaftSummary {
+	g.rafts.mu.RLock()
+	rafts := make([]*RaftInfo, 0, len(g.rafts.rafts))
+	for _, raft := range g.rafts.rafts {
+		rafts = append(rafts, raft)
+	}
+	g.rafts.mu.RUnlock()
+
+	summaries := make([]RaftSummary, 0, len(rafts))
+	for _, raft := range rafts {
+		summary := summarizeRaft(raft)
+		summary.VotingPolicy = g.raftVotingPolicy(raft)
+		summary.Metadata = raft.metadata()
+		if charter, ok := g.Charter(raft.RaftID); ok {
+			summary.Charter = &charter
+			summary.InheritedRules = len(g.InheritedRules(raft.RaftID))
+		}
+		summaries = append(summaries, summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].RaftID < summaries[j].RaftID
+	})
+	return summaries
+}
+
+// summarizeRaft builds the summary for a single raft
+func summarizeRaft(raft *RaftInfo) RaftSummary {
+	raft.mu.RLock()
+	defer raft.mu.RUnlock()
+
+	summary := RaftSummary{
+		RaftID:           raft.RaftID,
+		Members:          make([]MemberSummary, 0, len(raft.Members)),
+		RuleFingerprints: make(map[string]string),
+		Archived:         raft.Archive != nil,
+		Provisional:      raft.Provisional,
+		Archive:          raft.Archive,
+	}
+
+	for _, member := range raft.Members {
+		summary.Members = append(summary.Members, MemberSummary{ID: member.ID, State: member.State})
+	}
+	sort.Slice(summary.Members, func(i, j int) bool {
+		return summary.Members[i].ID < summary.Members[j].ID
+	})
+
+	// Only the latest adopted version of each scope counts towards the rule set
+	latest := make(map[string]*Rule)
+	for _, rule := range raft.Rules {
+		if rule.AdoptedAt == nil {
+			continue
+		}
+		if current, ok := latest[rule.Scope]; !ok || rule.Version > current.Version {
+			latest[rule.Scope] = rule
+		}
+	}
+
+	scopes := make([]string, 0, len(latest))
+	for scope, rule := range latest {
+		summary.RuleFingerprints[scope] = RuleFingerprint(rule.Body)
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+
+	var digest strings.Builder
+	for _, scope := range scopes {
+		digest.WriteString(fmt.Sprintf("%s=%s\n", scope, summary.RuleFingerprints[scope]))
+	}
+	summary.RuleCount = len(latest)
+	summary.RulesDigest = RuleFingerprint(digest.String())
+
+	return summary
+}
+
+// RuleFingerprint returns a short stable fingerprint of a rule body
+func RuleFingerprint(body string) string {
+	hash := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(hash[:8])
+}
+
+// Check reports whether governance can serve requests: it is running and
+// this otter is an active member of its own raft. Readiness probes use it.
+func (g *Governance) Check(ctx context.Context) error {
+	select {
+	case <-g.shutdownCh:
+		return fmt.Errorf("governance is shut down")
+	default:
+	}
+
+	g.rafts.mu.RLock()
+	raft, ok := g.rafts.rafts[g.config.ID]
+	g.rafts.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("own raft %s is missing", g.config.ID)
+	}
+	raft.mu.RLock()
+	self, ok := raft.Members[g.config.ID]
+	state := StateActive
+	if ok {
+		state = self.State
+	}
+	raft.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("not a member of own raft %s", g.config.ID)
+	}
+	if state != StateActive {
+		return fmt.Errorf("membership of own raft is %s", state)
+	}
+	return ctx.Err()
+}
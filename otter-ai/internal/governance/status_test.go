@@ -0,0 +1,46 @@
+package governance
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRaftSummaries_UsesLatestAdoptedRule(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	adopted := time.Now()
+
+	raft := g.rafts.rafts["otter-1"]
+	raft.Rules["r1"] = &Rule{RuleID: "r1", Scope: "safety", Version: 1, Body: "old", AdoptedAt: &adopted}
+	raft.Rules["r2"] = &Rule{RuleID: "r2", Scope: "safety", Version: 2, Body: "new", AdoptedAt: &adopted}
+	raft.Rules["r3"] = &Rule{RuleID: "r3", Scope: "privacy", Version: 1, Body: "pending"}
+
+	summaries := g.RaftSummaries()
+	if len(summaries) != 1 {
+		t.Fatalf("expected 1 summary, got %d", len(summaries))
+	}
+	s := summaries[0]
+	if s.RuleCount != 1 {
+		t.Errorf("rule count = %d, want 1", s.RuleCount)
+	}
+	if s.RuleFingerprints["safety"] != RuleFingerprint("new") {
+		t.Errorf("expected fingerprint of latest version")
+	}
+	if _, ok := s.RuleFingerprints["privacy"]; ok {
+		t.Error("unadopted rules should not be fingerprinted")
+	}
+	if len(s.Members) != 1 || s.Members[0].ID != "otter-1" {
+		t.Errorf("members = %+v", s.Members)
+	}
+}
+
+func TestRaftSummaries_DigestStable(t *testing.T) {
+	a := newTestGovernance("otter-1")
+	b := newTestGovernance("otter-1")
+	adopted := time.Now()
+	a.rafts.rafts["otter-1"].Rules["x"] = &Rule{Scope: "s", Body: "b", AdoptedAt: &adopted}
+	b.rafts.rafts["otter-1"].Rules["y"] = &Rule{Scope: "s", Body: "b", AdoptedAt: &adopted}
+
+	if a.RaftSummaries()[0].RulesDigest != b.RaftSummaries()[0].RulesDigest {
+		t.Error("expected identical rule sets to share a digest")
+	}
+}
@@ -0,0 +1,409 @@
+package governance
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"otter-ai/internal/errs"
+	"otter-ai/internal/events"
+)
+
+// Emergency suspension defaults: any two members within an hour
+const (
+	DefaultSuspensionQuorum = 2
+	DefaultSuspensionWindow = time.Hour
+)
+
+// Suspension errors
+var (
+	ErrRuleNotActive    = errs.New(errs.ErrConflict, "rule is not active")
+	ErrRuleNotSuspended = errs.New(errs.ErrConflict, "rule is not suspended")
+)
+
+// SuspensionStatus is the state of a rule's emergency suspension
+type SuspensionStatus string
+
+const (
+	SuspensionActive     SuspensionStatus = "suspended"  // Deactivated pending the re-vote
+	SuspensionReinstated SuspensionStatus = "reinstated" // The re-vote restored the rule
+	SuspensionRepealed   SuspensionStatus = "repealed"   // The re-vote failed; the rule stays inactive
+)
+
+// Suspension records a rule deactivated by a fast-tracked emergency
+// proposal, and the full re-vote that decides whether it comes back
+type Suspension struct {
+	RuleID      string           `json:"rule_id"`
+	RaftID      string           `json:"raft_id"`
+	Scope       string           `json:"scope"`
+	ProposalID  string           `json:"proposal_id"`         // The adopted suspension proposal
+	RevoteID    string           `json:"revote_id,omitempty"` // The latest reinstatement proposal
+	Reason      string           `json:"reason"`
+	SuspendedBy string           `json:"suspended_by"` // Who proposed the suspension
+	SuspendedAt time.Time        `json:"suspended_at"`
+	Status      SuspensionStatus `json:"status"`
+	ResolvedAt  *time.Time       `json:"resolved_at,omitempty"`
+}
+
+// suspensionRegistry holds rule suspensions by rule ID, resolved or not
+type suspensionRegistry struct {
+	suspensions map[string]*Suspension
+	mu          sync.RWMutex
+}
+
+func (g *Governance) suspensionRegistry() *suspensionRegistry {
+	g.suspensionsOnce.Do(func() {
+		if g.suspensions == nil {
+			g.suspensions = &suspensionRegistry{suspensions: make(map[string]*Suspension)}
+		}
+	})
+	return g.suspensions
+}
+
+// suspensionQuorum returns the YES votes that suspend a rule, capped at the
+// proposal's eligible voters so small rafts can still act
+func (g *Governance) suspensionQuorum(eligible int) int {
+	quorum := g.config.SuspensionQuorum
+	if quorum <= 0 {
+		quorum = DefaultSuspensionQuorum
+	}
+	return min(quorum, eligible)
+}
+
+// SuspensionWindow returns how long a suspension proposal stays open
+func (g *Governance) SuspensionWindow() time.Duration {
+	if g.config.SuspensionWindow <= 0 {
+		return DefaultSuspensionWindow
+	}
+	return g.config.SuspensionWindow
+}
+
+// ProposeSuspension opens an emergency proposal to deactivate an active
+// rule of a raft. It is adopted as soon as the fast-track quorum of members
+// votes YES within the suspension window, and then opens the full re-vote
+// on reinstating the rule. A reason is required.
+func (g *Governance) ProposeSuspension(ctx context.Context, raftID, ruleID, proposedBy, reason string) (*Proposal, error) {
+	if err := g.writable(); err != nil {
+		return nil, err
+	}
+	if reason == "" {
+		return nil, errs.New(errs.ErrInvalid, "a suspension needs a reason")
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	raft, err := g.liveRaft(raftID)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.requireActiveMember(raft, proposedBy); err != nil {
+		return nil, err
+	}
+
+	g.rules.mu.RLock()
+	rule, known := g.rules.rules[ruleID]
+	active := known && rule.RaftID == raftID && g.rules.active[activeKey(rule)] == rule
+	g.rules.mu.RUnlock()
+	if !known || rule.RaftID != raftID {
+		return nil, fmt.Errorf("%w: %s", ErrRuleNotFound, ruleID)
+	}
+	if !active {
+		return nil, fmt.Errorf("%w: %s", ErrRuleNotActive, ruleID)
+	}
+
+	g.proposals.mu.Lock()
+	defer g.proposals.mu.Unlock()
+
+	for _, open := range g.proposals.proposals {
+		if open.Status == ProposalOpen && open.Kind == ProposalKindSuspension && open.Rule != nil && open.Rule.RuleID == ruleID {
+			return nil, errs.Errorf(errs.ErrConflict, "suspension of rule %s is already open as proposal %s", ruleID, open.ProposalID)
+		}
+	}
+
+	now := time.Now()
+	proposal := &Proposal{
+		ProposalID:     generateID(fmt.Sprintf("suspend|%s|%s|%s|%d", raftID, ruleID, proposedBy, now.UnixNano())),
+		RaftID:         raftID,
+		Kind:           ProposalKindSuspension,
+		Rule:           rule,
+		Reason:         reason,
+		ProposedBy:     proposedBy,
+		ProposedAt:     now,
+		Deadline:       now.Add(g.SuspensionWindow()),
+		Votes:          make(map[string]VoteType),
+		Ballots:        make(map[string]*SignedVote),
+		Status:         ProposalOpen,
+		Result:         ResultPending,
+		EligibleVoters: snapshotVoters(raft, ""),
+	}
+	g.proposals.proposals[proposal.ProposalID] = proposal
+
+	g.publish(events.ProposalCreated, proposalEvent(proposal))
+	g.recordAudit(AuditProposalCreated, raftID, proposal.ProposalID, proposedBy, proposalEvent(proposal))
+	g.announceProposal(proposal)
+
+	return proposal, nil
+}
+
+// ProposeReinstatement opens the re-vote on a suspended rule, as when the
+// one opened with the suspension was lost to a restart. Only one re-vote
+// per rule is open at a time.
+func (g *Governance) ProposeReinstatement(ctx context.Context, ruleID, proposedBy string) (*Proposal, error) {
+	if err := g.writable(); err != nil {
+		return nil, err
+	}
+	r := g.suspensionRegistry()
+	r.mu.RLock()
+	suspension, ok := r.suspensions[ruleID]
+	r.mu.RUnlock()
+	if !ok || suspension.Status != SuspensionActive {
+		return nil, fmt.Errorf("%w: %s", ErrRuleNotSuspended, ruleID)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	raft, err := g.liveRaft(suspension.RaftID)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.requireActiveMember(raft, proposedBy); err != nil {
+		return nil, err
+	}
+
+	g.proposals.mu.Lock()
+	defer g.proposals.mu.Unlock()
+	for _, open := range g.proposals.proposals {
+		if open.Status == ProposalOpen && open.Kind == ProposalKindReinstatement && open.Rule != nil && open.Rule.RuleID == ruleID {
+			return nil, errs.Errorf(errs.ErrConflict, "re-vote on rule %s is already open as proposal %s", ruleID, open.ProposalID)
+		}
+	}
+	return g.openReinstatement(raft, suspension, proposedBy), nil
+}
+
+// requireActiveMember checks that a proposer is an active member of a raft
+func (g *Governance) requireActiveMember(raft *RaftInfo, memberID string) error {
+	raft.mu.RLock()
+	member, exists := raft.Members[memberID]
+	raft.mu.RUnlock()
+	if !exists || member.State != StateActive {
+		return fmt.Errorf("proposer must be an active member of raft %s", raft.RaftID)
+	}
+	return nil
+}
+
+// openReinstatement opens the full re-vote on a suspended rule, decided by
+// the raft's voting policy over the normal voting period. The caller must
+// hold the proposal registry lock.
+func (g *Governance) openReinstatement(raft *RaftInfo, suspension *Suspension, proposedBy string) *Proposal {
+	g.rules.mu.RLock()
+	rule := g.rules.rules[suspension.RuleID]
+	g.rules.mu.RUnlock()
+
+	now := time.Now()
+	proposal := &Proposal{
+		ProposalID:     generateID(fmt.Sprintf("reinstate|%s|%s|%s|%d", raft.RaftID, suspension.RuleID, proposedBy, now.UnixNano())),
+		RaftID:         raft.RaftID,
+		Kind:           ProposalKindReinstatement,
+		Rule:           rule,
+		Reason:         suspension.Reason,
+		ProposedBy:     proposedBy,
+		ProposedAt:     now,
+		Deadline:       now.Add(g.VotingPeriod()),
+		Votes:          make(map[string]VoteType),
+		Ballots:        make(map[string]*SignedVote),
+		Status:         ProposalOpen,
+		Result:         ResultPending,
+		EligibleVoters: snapshotVoters(raft, ""),
+	}
+	g.proposals.proposals[proposal.ProposalID] = proposal
+
+	r := g.suspensionRegistry()
+	r.mu.Lock()
+	suspension.RevoteID = proposal.ProposalID
+	r.mu.Unlock()
+	g.persistSuspension(suspension)
+
+	g.publish(events.ProposalCreated, proposalEvent(proposal))
+	g.recordAudit(AuditProposalCreated, raft.RaftID, proposal.ProposalID, proposedBy, proposalEvent(proposal))
+	g.announceProposal(proposal)
+	return proposal
+}
+
+// checkSuspensionOutcome decides a suspension proposal: adopted once the
+// fast-track quorum has voted YES, rejected once that can no longer happen.
+// Unlike other proposals a NO vote does not outweigh a YES. The caller must
+// hold the proposal registry lock.
+func (g *Governance) checkSuspensionOutcome(proposal *Proposal) {
+	eligible := g.eligibleVoters(proposal)
+	if len(eligible) == 0 {
+		return
+	}
+
+	yes, cast := 0, 0
+	for _, voterID := range eligible {
+		if vote, ok := proposal.Votes[voterID]; ok {
+			cast++
+			if vote == VoteYes {
+				yes++
+			}
+		}
+	}
+	need := g.suspensionQuorum(len(eligible))
+	proposal.QuorumMet = yes >= need
+	adopted := yes >= need
+	if !adopted && yes+len(eligible)-cast >= need {
+		return
+	}
+
+	now := time.Now()
+	proposal.Status = ProposalClosed
+	proposal.ClosedAt = &now
+	proposal.Result = ResultRejected
+	if adopted {
+		proposal.Result = ResultAdopted
+	}
+
+	g.publish(events.ProposalClosed, proposalEvent(proposal))
+	g.recordAudit(AuditProposalClosed, proposal.RaftID, proposal.ProposalID, "", proposalEvent(proposal))
+	g.announceOutcome(proposal)
+
+	if adopted {
+		if suspension := g.suspendRule(proposal); suspension != nil {
+			if raft, err := g.liveRaft(proposal.RaftID); err == nil {
+				g.openReinstatement(raft, suspension, proposal.ProposedBy)
+			}
+		}
+	}
+}
+
+// suspendRule takes an adopted suspension's rule out of the active set and
+// records the suspension, returning it; nil when the rule is no longer the
+// active one of its scope
+func (g *Governance) suspendRule(proposal *Proposal) *Suspension {
+	rule := proposal.Rule
+	g.rules.mu.Lock()
+	active := g.rules.active[activeKey(rule)] == rule
+	if active {
+		delete(g.rules.active, activeKey(rule))
+	}
+	g.rules.mu.Unlock()
+	if !active {
+		return nil
+	}
+
+	suspension := &Suspension{
+		RuleID:      rule.RuleID,
+		RaftID:      rule.RaftID,
+		Scope:       rule.Scope,
+		ProposalID:  proposal.ProposalID,
+		Reason:      proposal.Reason,
+		SuspendedBy: proposal.ProposedBy,
+		SuspendedAt: time.Now(),
+		Status:      SuspensionActive,
+	}
+	r := g.suspensionRegistry()
+	r.mu.Lock()
+	r.suspensions[rule.RuleID] = suspension
+	r.mu.Unlock()
+	g.persistSuspension(suspension)
+
+	g.log().Warn("suspended rule pending re-vote", "rule_id", rule.RuleID, "raft_id", rule.RaftID, "proposal_id", proposal.ProposalID)
+	g.publish(events.RuleSuspended, ruleEvent(rule))
+	g.recordAudit(AuditRuleSuspended, rule.RaftID, rule.RuleID, proposal.ProposedBy, ruleEvent(rule))
+	return suspension
+}
+
+// resolveSuspension settles a suspended rule by its re-vote: an adopted
+// re-vote restores the rule unless another rule has taken its scope since,
+// any other outcome repeals it
+func (g *Governance) resolveSuspension(proposal *Proposal) {
+	r := g.suspensionRegistry()
+	r.mu.Lock()
+	suspension, ok := r.suspensions[proposal.Rule.RuleID]
+	if !ok || suspension.Status != SuspensionActive {
+		r.mu.Unlock()
+		return
+	}
+	now := time.Now()
+	suspension.ResolvedAt = &now
+	suspension.Status = SuspensionRepealed
+	if proposal.Result == ResultAdopted {
+		suspension.Status = SuspensionReinstated
+	}
+	r.mu.Unlock()
+	g.persistSuspension(suspension)
+
+	rule := proposal.Rule
+	if suspension.Status == SuspensionRepealed {
+		g.log().Info("repealed suspended rule", "rule_id", rule.RuleID, "raft_id", rule.RaftID, "proposal_id", proposal.ProposalID)
+		g.recordAudit(AuditRuleDeactivated, rule.RaftID, rule.RuleID, proposal.ProposedBy, ruleEvent(rule))
+		return
+	}
+
+	g.rules.mu.Lock()
+	_, taken := g.rules.active[activeKey(rule)]
+	if !taken {
+		g.rules.active[activeKey(rule)] = rule
+	}
+	g.rules.mu.Unlock()
+	if taken {
+		g.log().Info("scope of reinstated rule was taken while it was suspended", "rule_id", rule.RuleID, "scope", rule.Scope)
+		return
+	}
+	g.publish(events.RuleReinstated, ruleEvent(rule))
+	g.recordAudit(AuditRuleReinstated, rule.RaftID, rule.RuleID, proposal.ProposedBy, ruleEvent(rule))
+}
+
+// applySuspensionOutcome applies a suspension or re-vote its origin
+// decided. Suspensions must carry verified YES ballots from the fast-track
+// quorum; the origin announces the re-vote itself. The caller must hold
+// the proposal registry lock.
+func (g *Governance) applySuspensionOutcome(proposal *Proposal) {
+	switch proposal.Kind {
+	case ProposalKindSuspension:
+		if proposal.Result != ResultAdopted {
+			return
+		}
+		yes := 0
+		for _, vote := range proposal.Votes {
+			if vote == VoteYes {
+				yes++
+			}
+		}
+		if yes < g.suspensionQuorum(len(proposal.EligibleVoters)) {
+			g.log().Warn("ignoring suspension without its quorum of ballots", "proposal_id", proposal.ProposalID)
+			return
+		}
+		g.suspendRule(proposal)
+	case ProposalKindReinstatement:
+		g.resolveSuspension(proposal)
+	}
+}
+
+// Suspensions returns a raft's rule suspensions, newest first; an empty
+// raft ID returns every raft's
+func (g *Governance) Suspensions(raftID string) []Suspension {
+	r := g.suspensionRegistry()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := []Suspension{}
+	for _, suspension := range r.suspensions {
+		if raftID == "" || suspension.RaftID == raftID {
+			list = append(list, *suspension)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].SuspendedAt.After(list[j].SuspendedAt) })
+	return list
+}
+
+// persistSuspension saves a suspension, logging failures
+func (g *Governance) persistSuspension(suspension *Suspension) {
+	if err := g.saveSuspension(context.Background(), suspension); err != nil {
+		g.log().Warn("failed to persist rule suspension", "rule_id", suspension.RuleID, "error", err)
+	}
+}
@@ -0,0 +1,245 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"otter-ai/internal/errs"
+	"otter-ai/internal/memory"
+	"otter-ai/internal/vectordb"
+)
+
+// suspendableRaft returns a three-member raft with an adopted rule r1
+func suspendableRaft(t *testing.T) (*Governance, map[string]*Governance, *Rule) {
+	t.Helper()
+	g := newTestGovernance("otter-1")
+	peers := addPeers(g, "otter-2", "otter-3")
+	now := time.Now()
+	rule := &Rule{RuleID: "r1", RaftID: "otter-1", Scope: "safety", Body: "Always cite sources", Version: 1, AdoptedAt: &now}
+	g.activateRule(rule)
+	return g, peers, rule
+}
+
+// openProposalOfKind returns the open proposal of a kind, failing if none is
+func openProposalOfKind(t *testing.T, g *Governance, kind ProposalKind) *Proposal {
+	t.Helper()
+	for _, p := range g.GetOpenProposals() {
+		if p.Kind == kind {
+			return p
+		}
+	}
+	t.Fatalf("no open %s proposal", kind)
+	return nil
+}
+
+func TestProposeSuspension_Validation(t *testing.T) {
+	g, _, _ := suspendableRaft(t)
+	ctx := context.Background()
+
+	if _, err := g.ProposeSuspension(ctx, "otter-1", "r1", "otter-1", ""); !errors.Is(err, errs.ErrInvalid) {
+		t.Errorf("no reason: err = %v", err)
+	}
+	if _, err := g.ProposeSuspension(ctx, "otter-1", "missing", "otter-1", "harm"); !errors.Is(err, ErrRuleNotFound) {
+		t.Errorf("unknown rule: err = %v", err)
+	}
+	if _, err := g.ProposeSuspension(ctx, "otter-1", "r1", "stranger", "harm"); err == nil {
+		t.Error("expected a non-member to be refused")
+	}
+	if _, err := g.ProposeSuspension(ctx, "otter-1", "r1", "otter-1", "harm"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.ProposeSuspension(ctx, "otter-1", "r1", "otter-2", "harm"); !errors.Is(err, errs.ErrConflict) {
+		t.Errorf("duplicate: err = %v", err)
+	}
+}
+
+func TestSuspension_FastTrackAndReinstate(t *testing.T) {
+	g, peers, rule := suspendableRaft(t)
+	ctx := context.Background()
+
+	proposal, err := g.ProposeSuspension(ctx, "otter-1", "r1", "otter-1", "makes the agent refuse to answer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if window := proposal.Deadline.Sub(proposal.ProposedAt); window != DefaultSuspensionWindow {
+		t.Errorf("window = %v, want %v", window, DefaultSuspensionWindow)
+	}
+	if err := g.CastVote(ctx, proposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	if proposal.Status != ProposalOpen {
+		t.Fatal("one YES vote should not suspend the rule")
+	}
+	if err := peerVote(t, g, peers["otter-2"], proposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	if proposal.Result != ResultAdopted {
+		t.Fatalf("result = %q, want adopted by two members", proposal.Result)
+	}
+	if _, active := g.GetActiveRulesForRaft("otter-1")["safety"]; active {
+		t.Error("suspended rule is still active")
+	}
+
+	revote := openProposalOfKind(t, g, ProposalKindReinstatement)
+	suspensions := g.Suspensions("otter-1")
+	if len(suspensions) != 1 || suspensions[0].Status != SuspensionActive || suspensions[0].RevoteID != revote.ProposalID {
+		t.Fatalf("suspensions = %+v", suspensions)
+	}
+	if revote.Rule != rule || revote.Deadline.Sub(revote.ProposedAt) != g.VotingPeriod() {
+		t.Errorf("re-vote = %+v, want the suspended rule over the normal voting period", revote)
+	}
+	if _, err := g.ProposeReinstatement(ctx, "r1", "otter-2"); !errors.Is(err, errs.ErrConflict) {
+		t.Errorf("second re-vote: err = %v", err)
+	}
+
+	if err := g.CastVote(ctx, revote.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"otter-2", "otter-3"} {
+		if err := peerVote(t, g, peers[id], revote.ProposalID, VoteYes); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if revote.Result != ResultAdopted {
+		t.Fatalf("re-vote result = %q", revote.Result)
+	}
+	if got := g.GetActiveRulesForRaft("otter-1")["safety"]; got != rule {
+		t.Errorf("active rule = %v, want the reinstated rule", got)
+	}
+	if status := g.Suspensions("")[0].Status; status != SuspensionReinstated {
+		t.Errorf("status = %q, want reinstated", status)
+	}
+}
+
+func TestSuspension_RejectedRevoteRepeals(t *testing.T) {
+	g, peers, _ := suspendableRaft(t)
+	g.config.SuspensionQuorum = 1
+	ctx := context.Background()
+
+	proposal, err := g.ProposeSuspension(ctx, "otter-1", "r1", "otter-1", "harm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CastVote(ctx, proposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	revote := openProposalOfKind(t, g, ProposalKindReinstatement)
+	for _, id := range []string{"otter-2", "otter-3"} {
+		if err := peerVote(t, g, peers[id], revote.ProposalID, VoteNo); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := g.CastVote(ctx, revote.ProposalID, VoteNo); err != nil {
+		t.Fatal(err)
+	}
+	if revote.Result != ResultRejected {
+		t.Fatalf("re-vote result = %q", revote.Result)
+	}
+	if _, active := g.GetActiveRulesForRaft("otter-1")["safety"]; active {
+		t.Error("repealed rule is active")
+	}
+	if status := g.Suspensions("otter-1")[0].Status; status != SuspensionRepealed {
+		t.Errorf("status = %q, want repealed", status)
+	}
+	if _, err := g.ProposeReinstatement(ctx, "r1", "otter-1"); !errors.Is(err, ErrRuleNotSuspended) {
+		t.Errorf("re-vote on a repealed rule: err = %v", err)
+	}
+}
+
+func TestSuspension_FailsWithoutQuorum(t *testing.T) {
+	g, peers, rule := suspendableRaft(t)
+	ctx := context.Background()
+
+	// Two NO votes leave too few voters to reach two YES votes
+	blocked, err := g.ProposeSuspension(ctx, "otter-1", "r1", "otter-1", "harm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"otter-2", "otter-3"} {
+		if err := peerVote(t, g, peers[id], blocked.ProposalID, VoteNo); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if blocked.Result != ResultRejected {
+		t.Errorf("result = %q, want rejected", blocked.Result)
+	}
+
+	// The window runs out before the quorum is reached
+	expiring, err := g.ProposeSuspension(ctx, "otter-1", "r1", "otter-1", "harm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CastVote(ctx, expiring.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	g.closeExpiredProposals(time.Now().Add(DefaultSuspensionWindow + time.Minute))
+	if !expiring.Expired || expiring.Result != ResultRejected {
+		t.Errorf("proposal = %+v, want expired", expiring)
+	}
+	if got := g.GetActiveRulesForRaft("otter-1")["safety"]; got != rule {
+		t.Error("rule should stay active when the suspension fails")
+	}
+	if len(g.Suspensions("")) != 0 {
+		t.Error("no suspension should be recorded")
+	}
+}
+
+func TestSuspension_PersistedAcrossRestart(t *testing.T) {
+	dir := t.TempDir()
+	db, err := vectordb.NewSQLiteVectorDB(filepath.Join(dir, "otter.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	g, err := New(RaftConfig{ID: "otter-1", DataDir: dir}, memory.New(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	adopt, err := g.ProposeRule(ctx, "otter-1", &Rule{Scope: "safety", Body: "Always cite sources", ProposedBy: "otter-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CastVote(ctx, adopt.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+
+	// A solo otter's fast-track quorum is itself
+	suspend, err := g.ProposeSuspension(ctx, "otter-1", adopt.Rule.RuleID, "otter-1", "harm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CastVote(ctx, suspend.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	g.Shutdown(ctx)
+
+	reloaded, err := New(RaftConfig{ID: "otter-1", DataDir: dir}, memory.New(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reloaded.Shutdown(ctx)
+	if _, active := reloaded.GetActiveRulesForRaft("otter-1")["safety"]; active {
+		t.Error("suspended rule was reactivated by a restart")
+	}
+	suspensions := reloaded.Suspensions("otter-1")
+	if len(suspensions) != 1 || suspensions[0].Status != SuspensionActive || suspensions[0].RevoteID == "" {
+		t.Fatalf("suspensions = %+v", suspensions)
+	}
+
+	// The re-vote was lost with the restart, so it is reopened
+	revote, err := reloaded.ProposeReinstatement(ctx, adopt.Rule.RuleID, "otter-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reloaded.CastVote(ctx, revote.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	if _, active := reloaded.GetActiveRulesForRaft("otter-1")["safety"]; !active {
+		t.Error("reinstated rule is not active")
+	}
+}
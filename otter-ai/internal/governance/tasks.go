@@ -0,0 +1,350 @@
+package governance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"otter-ai/internal/errs"
+	"otter-ai/internal/llm"
+)
+
+// LLM task queue configuration
+const (
+	LLMTaskPollInterval = 30 * time.Second
+	LLMTaskBaseBackoff  = 30 * time.Second
+	LLMTaskMaxBackoff   = 30 * time.Minute
+	LLMTaskMaxAttempts  = 20
+	LLMTaskTimeout      = 120 * time.Second
+)
+
+// ErrLLMUnavailable indicates the LLM provider failed and the work should be retried later
+var ErrLLMUnavailable = errs.New(errs.ErrLLMUnavailable, "llm provider unavailable")
+
+// ErrTaskDeferred is returned when an operation was queued for replay instead of completing
+var ErrTaskDeferred = errs.New(errs.ErrLLMUnavailable, "governance task deferred until the llm provider recovers")
+
+// LLMTaskKind identifies the governance operation a task replays
+type LLMTaskKind string
+
+const (
+	TaskNegotiation LLMTaskKind = "negotiation"
+)
+
+// LLMTaskStatus defines the lifecycle of a queued task
+type LLMTaskStatus string
+
+const (
+	TaskPending   LLMTaskStatus = "pending"
+	TaskRunning   LLMTaskStatus = "running"
+	TaskCompleted LLMTaskStatus = "completed"
+	TaskFailed    LLMTaskStatus = "failed"
+)
+
+// LLMTask is a governance operation waiting on the LLM provider
+type LLMTask struct {
+	TaskID        string
+	Kind          LLMTaskKind
+	RefID         string          // ID of the object the task operates on (e.g. negotiation ID)
+	Payload       json.RawMessage // Snapshot needed to replay the task after a restart
+	Status        LLMTaskStatus
+	Attempts      int
+	LastError     string
+	Result        string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	NextAttemptAt time.Time
+}
+
+// LLMTaskHandler replays a queued task. Returning an error wrapping
+// ErrLLMUnavailable reschedules the task; any other error fails it.
+type LLMTaskHandler func(ctx context.Context, task *LLMTask, provider completer) (string, error)
+
+// completer is the subset of llm.Provider governance needs
+type completer interface {
+	Complete(context.Context, *llm.CompletionRequest) (*llm.CompletionResponse, error)
+}
+
+// LLMTaskQueue holds governance tasks awaiting LLM replay
+type LLMTaskQueue struct {
+	tasks    map[string]*LLMTask
+	handlers map[LLMTaskKind]LLMTaskHandler
+	mu       sync.RWMutex
+}
+
+func newLLMTaskQueue() *LLMTaskQueue {
+	return &LLMTaskQueue{
+		tasks:    make(map[string]*LLMTask),
+		handlers: make(map[LLMTaskKind]LLMTaskHandler),
+	}
+}
+
+// llmTasks returns the task queue, creating it with the built-in handlers on first use
+func (g *Governance) llmTasks() *LLMTaskQueue {
+	g.tasksOnce.Do(func() {
+		if g.tasks == nil {
+			g.tasks = newLLMTaskQueue()
+		}
+		g.tasks.handlers[TaskNegotiation] = g.replayNegotiation
+		g.tasks.handlers[TaskImpactAnalysis] = g.replayImpactAnalysis
+	})
+	return g.tasks
+}
+
+// SetLLMProvider sets the provider used to replay queued governance tasks
+func (g *Governance) SetLLMProvider(provider llm.Provider) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.llm = provider
+}
+
+func (g *Governance) llmProvider() completer {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if g.llm == nil {
+		return nil
+	}
+	return g.llm
+}
+
+// RegisterLLMTaskHandler registers a replay handler for a task kind
+func (g *Governance) RegisterLLMTaskHandler(kind LLMTaskKind, handler LLMTaskHandler) {
+	q := g.llmTasks()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[kind] = handler
+}
+
+// enqueueLLMTask queues a task for replay and persists it
+func (g *Governance) enqueueLLMTask(ctx context.Context, kind LLMTaskKind, refID string, payload interface{}, cause error) (*LLMTask, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	now := time.Now()
+	task := &LLMTask{
+		TaskID:        generateID(fmt.Sprintf("task-%s-%s-%d", kind, refID, now.UnixNano())),
+		Kind:          kind,
+		RefID:         refID,
+		Payload:       data,
+		Status:        TaskPending,
+		Attempts:      1,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		NextAttemptAt: now.Add(llmTaskBackoff(1)),
+	}
+	if cause != nil {
+		task.LastError = cause.Error()
+	}
+
+	q := g.llmTasks()
+	q.mu.Lock()
+	q.tasks[task.TaskID] = task
+	q.mu.Unlock()
+
+	if err := g.saveLLMTask(ctx, task); err != nil {
+		g.log().WarnContext(ctx, "failed to persist LLM task", "task_id", task.TaskID, "error", err)
+	}
+
+	return task, nil
+}
+
+// GetLLMTasks returns all queued tasks, newest first
+func (g *Governance) GetLLMTasks() []*LLMTask {
+	q := g.llmTasks()
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	tasks := make([]*LLMTask, 0, len(q.tasks))
+	for _, task := range q.tasks {
+		copied := *task
+		tasks = append(tasks, &copied)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].CreatedAt.After(tasks[j].CreatedAt)
+	})
+	return tasks
+}
+
+// GetLLMTask returns a single queued task
+func (g *Governance) GetLLMTask(taskID string) (*LLMTask, bool) {
+	q := g.llmTasks()
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	task, ok := q.tasks[taskID]
+	if !ok {
+		return nil, false
+	}
+	copied := *task
+	return &copied, true
+}
+
+// RetryLLMTask makes a pending or failed task due immediately
+func (g *Governance) RetryLLMTask(ctx context.Context, taskID string) error {
+	q := g.llmTasks()
+	q.mu.Lock()
+	task, ok := q.tasks[taskID]
+	if !ok {
+		q.mu.Unlock()
+		return errs.Errorf(errs.ErrNotFound, "task not found: %s", taskID)
+	}
+	if task.Status != TaskPending && task.Status != TaskFailed {
+		q.mu.Unlock()
+		return fmt.Errorf("task %s is %s", taskID, task.Status)
+	}
+	task.Status = TaskPending
+	task.NextAttemptAt = time.Now()
+	task.UpdatedAt = time.Now()
+	snapshot := *task
+	q.mu.Unlock()
+
+	if err := g.saveLLMTask(ctx, &snapshot); err != nil {
+		g.log().WarnContext(ctx, "failed to persist LLM task", "task_id", taskID, "error", err)
+	}
+
+	g.ProcessLLMTasks(ctx)
+	return nil
+}
+
+// llmTaskWorker replays due tasks until shutdown
+func (g *Governance) llmTaskWorker() {
+	ticker := time.NewTicker(LLMTaskPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			g.ProcessLLMTasks(context.Background())
+		case <-g.shutdownCh:
+			return
+		}
+	}
+}
+
+// ProcessLLMTasks runs every task that is due. Tasks are skipped while no
+// provider is configured so an outage does not burn through attempts.
+func (g *Governance) ProcessLLMTasks(ctx context.Context) {
+	provider := g.llmProvider()
+	if provider == nil {
+		return
+	}
+
+	q := g.llmTasks()
+	now := time.Now()
+
+	q.mu.Lock()
+	var due []*LLMTask
+	for _, task := range q.tasks {
+		if task.Status == TaskPending && !task.NextAttemptAt.After(now) {
+			task.Status = TaskRunning
+			due = append(due, task)
+		}
+	}
+	q.mu.Unlock()
+
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].CreatedAt.Before(due[j].CreatedAt)
+	})
+
+	for _, task := range due {
+		g.runLLMTask(ctx, task, provider)
+	}
+}
+
+// runLLMTask executes a single task and records the outcome
+func (g *Governance) runLLMTask(ctx context.Context, task *LLMTask, provider completer) {
+	q := g.llmTasks()
+
+	q.mu.RLock()
+	handler, ok := q.handlers[task.Kind]
+	snapshot := *task
+	q.mu.RUnlock()
+
+	var result string
+	var err error
+	if !ok {
+		err = fmt.Errorf("no handler registered for task kind %q", task.Kind)
+	} else {
+		taskCtx, cancel := context.WithTimeout(ctx, LLMTaskTimeout)
+		result, err = handler(taskCtx, &snapshot, provider)
+		cancel()
+	}
+
+	q.mu.Lock()
+	task.Attempts++
+	task.UpdatedAt = time.Now()
+	switch {
+	case err == nil:
+		task.Status = TaskCompleted
+		task.Result = result
+		task.LastError = ""
+	case errors.Is(err, ErrLLMUnavailable) && task.Attempts < LLMTaskMaxAttempts:
+		task.Status = TaskPending
+		task.LastError = err.Error()
+		task.NextAttemptAt = time.Now().Add(llmTaskBackoff(task.Attempts))
+	default:
+		task.Status = TaskFailed
+		task.LastError = err.Error()
+	}
+	final := *task
+	q.mu.Unlock()
+
+	if err := g.saveLLMTask(ctx, &final); err != nil {
+		g.log().WarnContext(ctx, "failed to persist LLM task", "task_id", final.TaskID, "error", err)
+	}
+}
+
+// llmTaskBackoff returns the exponential retry delay after the given number of attempts
+func llmTaskBackoff(attempts int) time.Duration {
+	delay := LLMTaskBaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= LLMTaskMaxBackoff {
+			return LLMTaskMaxBackoff
+		}
+	}
+	return delay
+}
+
+// replayNegotiation retries a deferred negotiation until the LLM produces
+// a compromise, which then waits for approval.
+func (g *Governance) replayNegotiation(ctx context.Context, task *LLMTask, provider completer) (string, error) {
+	g.negotiations.mu.RLock()
+	negotiation, ok := g.negotiations.negotiations[task.RefID]
+	g.negotiations.mu.RUnlock()
+
+	if !ok {
+		// Restore from the snapshot taken when the task was queued (e.g. after a restart)
+		negotiation = &Negotiation{}
+		if err := json.Unmarshal(task.Payload, negotiation); err != nil {
+			return "", fmt.Errorf("failed to restore negotiation %s: %w", task.RefID, err)
+		}
+		g.negotiations.mu.Lock()
+		g.negotiations.negotiations[negotiation.NegotiationID] = negotiation
+		g.negotiations.mu.Unlock()
+	}
+
+	if len(negotiation.Conflicts) == 0 {
+		return "", fmt.Errorf("negotiation %s has no conflicts to resolve", task.RefID)
+	}
+
+	proposedRule, err := g.negotiateWithLLM(ctx, negotiation, provider)
+	if err != nil {
+		return "", err
+	}
+
+	negotiation.ProposedRule = proposedRule
+	negotiation.Status = NegotiationResolved
+	now := time.Now()
+	negotiation.CompletedAt = &now
+
+	g.awaitApproval(ctx, negotiation)
+
+	return proposedRule.Body, nil
+}
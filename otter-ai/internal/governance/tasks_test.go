@@ -0,0 +1,183 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"otter-ai/internal/llm"
+)
+
+// failingLLMProvider simulates a provider outage
+type failingLLMProvider struct{}
+
+func (f *failingLLMProvider) Name() string { return "failing" }
+func (f *failingLLMProvider) Complete(_ context.Context, _ *llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	return nil, errors.New("connection refused")
+}
+func (f *failingLLMProvider) Embed(_ context.Context, _ string) ([]float32, error) {
+	return nil, errors.New("connection refused")
+}
+
+func testConflicts() []*RuleConflict {
+	return []*RuleConflict{
+		{
+			Raft1ID:       "otter-1",
+			Raft2ID:       "raft-2",
+			ConflictScope: "safety",
+			Rule1:         &Rule{Body: "be cautious", Version: 1},
+			Rule2:         &Rule{Body: "be bold", Version: 1},
+		},
+	}
+}
+
+// makeDue forces every pending task to be due now
+func makeDue(g *Governance) {
+	q := g.llmTasks()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, task := range q.tasks {
+		task.NextAttemptAt = time.Now().Add(-time.Second)
+	}
+}
+
+func TestNegotiateWithLLM_ProviderError(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	negotiation := &Negotiation{Raft1ID: "otter-1", Raft2ID: "raft-2", Conflicts: testConflicts()}
+
+	_, err := g.negotiateWithLLM(context.Background(), negotiation, &failingLLMProvider{})
+	if !errors.Is(err, ErrLLMUnavailable) {
+		t.Errorf("expected ErrLLMUnavailable, got %v", err)
+	}
+}
+
+func TestStartNegotiation_DeferredOnLLMFailure(t *testing.T) {
+	g := newTestGovernance("otter-1")
+
+	negotiation, err := g.startNegotiation(context.Background(), "raft-2", "endpoint", testConflicts(), nil, &failingLLMProvider{})
+	if err != nil {
+		t.Fatalf("startNegotiation: %v", err)
+	}
+	if negotiation.Status != NegotiationDeferred {
+		t.Errorf("status = %s, want %s", negotiation.Status, NegotiationDeferred)
+	}
+
+	tasks := g.GetLLMTasks()
+	if len(tasks) != 1 {
+		t.Fatalf("expected 1 queued task, got %d", len(tasks))
+	}
+	if tasks[0].Kind != TaskNegotiation || tasks[0].RefID != negotiation.NegotiationID {
+		t.Errorf("unexpected task: %+v", tasks[0])
+	}
+	if tasks[0].Status != TaskPending {
+		t.Errorf("task status = %s, want pending", tasks[0].Status)
+	}
+}
+
+func TestProcessLLMTasks_ReplaysWhenProviderRecovers(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	negotiation, _ := g.startNegotiation(context.Background(), "raft-2", "endpoint", testConflicts(), nil, &failingLLMProvider{})
+
+	g.SetLLMProvider(&mockLLMProvider{response: `{"scope":"safety","body":"Be bold carefully"}`})
+	makeDue(g)
+	g.ProcessLLMTasks(context.Background())
+
+	task := g.GetLLMTasks()[0]
+	if task.Status != TaskCompleted {
+		t.Fatalf("task status = %s, want completed (err: %s)", task.Status, task.LastError)
+	}
+	if task.Result != "Be bold carefully" {
+		t.Errorf("result = %q", task.Result)
+	}
+	if negotiation.Status != NegotiationResolved || negotiation.ProposedRule == nil {
+		t.Errorf("negotiation not resolved: %s", negotiation.Status)
+	}
+}
+
+func TestProcessLLMTasks_StillFailingReschedules(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	_, _ = g.startNegotiation(context.Background(), "raft-2", "endpoint", testConflicts(), nil, &failingLLMProvider{})
+
+	g.SetLLMProvider(&failingLLMProvider{})
+	makeDue(g)
+	g.ProcessLLMTasks(context.Background())
+
+	task := g.GetLLMTasks()[0]
+	if task.Status != TaskPending {
+		t.Errorf("task status = %s, want pending", task.Status)
+	}
+	if task.Attempts != 2 {
+		t.Errorf("attempts = %d, want 2", task.Attempts)
+	}
+	if !task.NextAttemptAt.After(time.Now()) {
+		t.Error("expected next attempt to be rescheduled into the future")
+	}
+}
+
+func TestProcessLLMTasks_NoProviderSkips(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	_, _ = g.startNegotiation(context.Background(), "raft-2", "endpoint", testConflicts(), nil, &failingLLMProvider{})
+
+	makeDue(g)
+	g.ProcessLLMTasks(context.Background())
+
+	task := g.GetLLMTasks()[0]
+	if task.Status != TaskPending || task.Attempts != 1 {
+		t.Errorf("expected task untouched without a provider, got %s after %d attempts", task.Status, task.Attempts)
+	}
+}
+
+func TestProcessLLMTasks_UnknownKindFails(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	task, err := g.enqueueLLMTask(context.Background(), LLMTaskKind("mystery"), "ref", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.SetLLMProvider(&mockLLMProvider{response: "ok"})
+	makeDue(g)
+	g.ProcessLLMTasks(context.Background())
+
+	got, _ := g.GetLLMTask(task.TaskID)
+	if got.Status != TaskFailed {
+		t.Errorf("status = %s, want failed", got.Status)
+	}
+}
+
+func TestRegisterLLMTaskHandler(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	g.RegisterLLMTaskHandler("summary", func(ctx context.Context, task *LLMTask, provider completer) (string, error) {
+		return "summarized " + task.RefID, nil
+	})
+	task, _ := g.enqueueLLMTask(context.Background(), "summary", "p1", nil, nil)
+
+	g.SetLLMProvider(&mockLLMProvider{})
+	if err := g.RetryLLMTask(context.Background(), task.TaskID); err != nil {
+		t.Fatalf("RetryLLMTask: %v", err)
+	}
+
+	got, _ := g.GetLLMTask(task.TaskID)
+	if got.Status != TaskCompleted || got.Result != "summarized p1" {
+		t.Errorf("unexpected task state: %+v", got)
+	}
+}
+
+func TestRetryLLMTask_NotFound(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	if err := g.RetryLLMTask(context.Background(), "missing"); err == nil {
+		t.Error("expected error for unknown task")
+	}
+}
+
+func TestLLMTaskBackoff(t *testing.T) {
+	if d := llmTaskBackoff(1); d != LLMTaskBaseBackoff {
+		t.Errorf("backoff(1) = %v, want %v", d, LLMTaskBaseBackoff)
+	}
+	if d := llmTaskBackoff(2); d != 2*LLMTaskBaseBackoff {
+		t.Errorf("backoff(2) = %v, want %v", d, 2*LLMTaskBaseBackoff)
+	}
+	if d := llmTaskBackoff(50); d != LLMTaskMaxBackoff {
+		t.Errorf("backoff(50) = %v, want %v", d, LLMTaskMaxBackoff)
+	}
+}
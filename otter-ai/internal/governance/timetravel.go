@@ -0,0 +1,161 @@
+package governance
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// GovernanceState is governance as it stood at a point in time, rebuilt
+// by replaying the audit log
+type GovernanceState struct {
+	AsOf  time.Time    `json:"as_of"`
+	Rafts []*RaftState `json:"rafts"`
+}
+
+// RaftState is one raft's members, active rules and open proposals
+type RaftState struct {
+	RaftID        string          `json:"raft_id"`
+	Members       []MemberState   `json:"members"`
+	Rules         []RuleState     `json:"rules"`
+	OpenProposals []ProposalEvent `json:"open_proposals"`
+	Archived      bool            `json:"archived"`
+}
+
+// MemberState is a member's standing at the queried time
+type MemberState struct {
+	MemberID   string          `json:"member_id"`
+	State      MembershipState `json:"state"`
+	JoinedAt   time.Time       `json:"joined_at"`
+	InductedBy string          `json:"inducted_by,omitempty"`
+}
+
+// RuleState is a rule that was in force at the queried time
+type RuleState struct {
+	RuleEvent
+	AdoptedAt time.Time `json:"adopted_at"`
+}
+
+// raftReplay accumulates one raft's state while replaying
+type raftReplay struct {
+	members   map[string]*MemberState
+	rules     map[string]*RuleState // By scope
+	proposals map[string]ProposalEvent
+	archived  bool
+}
+
+// StateAt reconstructs which members and rules each raft had, and which
+// proposals were open, as of the given time
+func (g *Governance) StateAt(ctx context.Context, asOf time.Time) (*GovernanceState, error) {
+	rafts := make(map[string]*raftReplay)
+	raftFor := func(raftID string) *raftReplay {
+		r, ok := rafts[raftID]
+		if !ok {
+			r = &raftReplay{
+				members:   make(map[string]*MemberState),
+				rules:     make(map[string]*RuleState),
+				proposals: make(map[string]ProposalEvent),
+			}
+			rafts[raftID] = r
+		}
+		return r
+	}
+
+	err := g.EachAuditEntry(ctx, AuditFilter{Until: asOf}, func(entry AuditEntry) error {
+		r := raftFor(entry.RaftID)
+
+		switch entry.Action {
+		case AuditMemberJoined, AuditMemberStateChanged:
+			var data MemberAudit
+			if err := json.Unmarshal(entry.Data, &data); err != nil {
+				return nil // Skip malformed entries rather than failing the whole replay
+			}
+			member, ok := r.members[entry.SubjectID]
+			if !ok {
+				member = &MemberState{MemberID: entry.SubjectID, JoinedAt: entry.Timestamp, InductedBy: data.InductedBy}
+				r.members[entry.SubjectID] = member
+			}
+			member.State = data.State
+
+		case AuditRuleAdopted, AuditRuleReinstated:
+			var data RuleEvent
+			if err := json.Unmarshal(entry.Data, &data); err != nil {
+				return nil
+			}
+			r.rules[data.Scope] = &RuleState{RuleEvent: data, AdoptedAt: entry.Timestamp}
+
+		case AuditRuleDeactivated, AuditRuleSuspended:
+			var data RuleEvent
+			if err := json.Unmarshal(entry.Data, &data); err != nil {
+				return nil
+			}
+			if current, ok := r.rules[data.Scope]; ok && current.RuleID == entry.SubjectID {
+				delete(r.rules, data.Scope)
+			}
+
+		case AuditProposalCreated:
+			var data ProposalEvent
+			if err := json.Unmarshal(entry.Data, &data); err != nil {
+				return nil
+			}
+			r.proposals[entry.SubjectID] = data
+
+		case AuditProposalClosed:
+			delete(r.proposals, entry.SubjectID)
+
+		case AuditRaftArchived:
+			r.archived = true
+
+		case AuditVoteCast:
+			var data VoteEvent
+			if err := json.Unmarshal(entry.Data, &data); err != nil {
+				return nil
+			}
+			if proposal, ok := r.proposals[entry.SubjectID]; ok {
+				switch data.Vote {
+				case VoteYes:
+					proposal.YesVotes++
+				case VoteNo:
+					proposal.NoVotes++
+				case VoteAbstain:
+					proposal.AbstainVotes++
+				}
+				r.proposals[entry.SubjectID] = proposal
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	state := &GovernanceState{AsOf: asOf, Rafts: make([]*RaftState, 0, len(rafts))}
+	for raftID, r := range rafts {
+		raft := &RaftState{
+			RaftID:        raftID,
+			Members:       make([]MemberState, 0, len(r.members)),
+			Rules:         make([]RuleState, 0, len(r.rules)),
+			OpenProposals: make([]ProposalEvent, 0, len(r.proposals)),
+			Archived:      r.archived,
+		}
+		for _, member := range r.members {
+			raft.Members = append(raft.Members, *member)
+		}
+		for _, rule := range r.rules {
+			raft.Rules = append(raft.Rules, *rule)
+		}
+		for _, proposal := range r.proposals {
+			raft.OpenProposals = append(raft.OpenProposals, proposal)
+		}
+		sort.Slice(raft.Members, func(i, j int) bool { return raft.Members[i].MemberID < raft.Members[j].MemberID })
+		sort.Slice(raft.Rules, func(i, j int) bool { return raft.Rules[i].Scope < raft.Rules[j].Scope })
+		sort.Slice(raft.OpenProposals, func(i, j int) bool {
+			return raft.OpenProposals[i].ProposalID < raft.OpenProposals[j].ProposalID
+		})
+		state.Rafts = append(state.Rafts, raft)
+	}
+	sort.Slice(state.Rafts, func(i, j int) bool { return state.Rafts[i].RaftID < state.Rafts[j].RaftID })
+
+	return state, nil
+}
@@ -0,0 +1,288 @@
+package governance
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"otter-ai/internal/errs"
+)
+
+// VotingScope is the scope of a raft's meta-governance rule. Its adopted
+// rule sets the raft's voting policy, and changing it takes the raft's
+// super-majority.
+const VotingScope = "governance/voting"
+
+// TieBreak decides a proposal whose YES votes reach the majority but do not
+// outnumber its NO votes, once every eligible member has voted. Ties only
+// arise with a majority fraction of one half or less.
+type TieBreak string
+
+const (
+	TieBreakReject   TieBreak = "reject"   // The proposal is rejected
+	TieBreakAdopt    TieBreak = "adopt"    // The proposal is adopted
+	TieBreakProposer TieBreak = "proposer" // The proposer's vote decides; rejected when it did not vote
+)
+
+// VotingPolicy sets how a raft decides proposals. Fractions are of the
+// proposal's eligible voters and are rounded up to whole votes. Zero
+// fields take the default policy's.
+type VotingPolicy struct {
+	Quorum        float64  `json:"quorum,omitempty"`         // Share that must vote
+	Majority      float64  `json:"majority,omitempty"`       // Share that must vote YES
+	SuperMajority float64  `json:"super_majority,omitempty"` // Share that must vote YES on amendments, evictions, voting rules and inherited overrides
+	TieBreak      TieBreak `json:"tie_break,omitempty"`
+}
+
+// DefaultVotingPolicy is the policy of rafts that have not adopted their
+// own: two thirds to vote and adopt, three quarters for overrides. It
+// makes solo otters decide alone and two-member rafts decide unanimously.
+var DefaultVotingPolicy = VotingPolicy{
+	Quorum:        QuorumPercentage / 100.0,
+	Majority:      QuorumPercentage / 100.0,
+	SuperMajority: SuperMajorityPercentage / 100.0,
+	TieBreak:      TieBreakReject,
+}
+
+// withDefaults fills the policy's zero fields from base
+func (p VotingPolicy) withDefaults(base VotingPolicy) VotingPolicy {
+	if p.Quorum == 0 {
+		p.Quorum = base.Quorum
+	}
+	if p.Majority == 0 {
+		p.Majority = base.Majority
+	}
+	if p.SuperMajority == 0 {
+		p.SuperMajority = base.SuperMajority
+	}
+	if p.TieBreak == "" {
+		p.TieBreak = base.TieBreak
+	}
+	return p
+}
+
+// Validate checks that set fractions lie in (0, 1], that a set
+// super-majority is no lower than a set majority, and the tie-break
+func (p VotingPolicy) Validate() error {
+	for _, f := range []struct {
+		name  string
+		value float64
+	}{{"quorum", p.Quorum}, {"majority", p.Majority}, {"super majority", p.SuperMajority}} {
+		if f.value < 0 || f.value > 1 || math.IsNaN(f.value) {
+			return errs.Errorf(errs.ErrInvalid, "%s must be a fraction between 0 and 1", f.name)
+		}
+	}
+	if p.Majority > 0 && p.SuperMajority > 0 && p.SuperMajority < p.Majority {
+		return errs.New(errs.ErrInvalid, "super majority must be at least the majority")
+	}
+	switch p.TieBreak {
+	case "", TieBreakReject, TieBreakAdopt, TieBreakProposer:
+	default:
+		return errs.Errorf(errs.ErrInvalid, "tie break must be reject, adopt or proposer, not %q", p.TieBreak)
+	}
+	return nil
+}
+
+// ParseVotingPolicy reads a voting rule's body: lines such as "quorum:
+// 0.6", "majority: 50%", "super majority: 0.75" and "tie break: proposer".
+// Other lines are free text; settings left out take the defaults.
+func ParseVotingPolicy(body string) (VotingPolicy, error) {
+	var policy VotingPolicy
+	for _, line := range strings.Split(body, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), ":")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		var target *float64
+		switch strings.ToLower(strings.Join(strings.Fields(key), " ")) {
+		case "quorum":
+			target = &policy.Quorum
+		case "majority":
+			target = &policy.Majority
+		case "super majority", "supermajority":
+			target = &policy.SuperMajority
+		case "tie break", "tiebreak":
+			policy.TieBreak = TieBreak(strings.ToLower(value))
+			continue
+		default:
+			continue
+		}
+		fraction, err := parseFraction(value)
+		if err != nil {
+			return VotingPolicy{}, errs.Errorf(errs.ErrInvalid, "invalid %s %q", strings.ToLower(strings.TrimSpace(key)), value)
+		}
+		*target = fraction
+	}
+	if policy == (VotingPolicy{}) {
+		return VotingPolicy{}, errs.New(errs.ErrInvalid, "a voting rule must set quorum, majority, super majority or tie break")
+	}
+	return policy, policy.Validate()
+}
+
+// parseFraction reads a fraction such as 0.6 or a percentage such as 60%
+func parseFraction(value string) (float64, error) {
+	if percent, ok := strings.CutSuffix(value, "%"); ok {
+		f, err := strconv.ParseFloat(strings.TrimSpace(percent), 64)
+		return f / 100, err
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// defaultVotingPolicy returns the configured default policy
+func (g *Governance) defaultVotingPolicy() VotingPolicy {
+	return g.config.VotingPolicy.withDefaults(DefaultVotingPolicy)
+}
+
+// VotingPolicy returns the policy a raft decides proposals by: its adopted
+// voting rule's, over the configured defaults
+func (g *Governance) VotingPolicy(raftID string) (VotingPolicy, error) {
+	g.rafts.mu.RLock()
+	raft, ok := g.rafts.rafts[raftID]
+	g.rafts.mu.RUnlock()
+	if !ok {
+		return VotingPolicy{}, fmt.Errorf("%w: %s", ErrRaftNotFound, raftID)
+	}
+	return g.raftVotingPolicy(raft), nil
+}
+
+func (g *Governance) raftVotingPolicy(raft *RaftInfo) VotingPolicy {
+	raft.mu.RLock()
+	defer raft.mu.RUnlock()
+	if raft.VotingPolicy == nil {
+		return g.defaultVotingPolicy()
+	}
+	return raft.VotingPolicy.withDefaults(g.defaultVotingPolicy())
+}
+
+// proposalPolicy returns the voting policy of a proposal's raft
+func (g *Governance) proposalPolicy(proposal *Proposal) VotingPolicy {
+	policy, err := g.VotingPolicy(proposal.RaftID)
+	if err != nil {
+		return g.defaultVotingPolicy()
+	}
+	return policy
+}
+
+// applyVotingRule makes an adopted voting rule its raft's voting policy,
+// reporting whether it was one. A rule that does not parse, as from a
+// peer, leaves the policy unchanged.
+func (g *Governance) applyVotingRule(raft *RaftInfo, rule *Rule) bool {
+	if rule.Scope != VotingScope {
+		return false
+	}
+	policy, err := ParseVotingPolicy(rule.Body)
+	if err != nil {
+		g.log().Warn("ignoring unreadable voting rule", "rule_id", rule.RuleID, "raft_id", rule.RaftID, "error", err)
+		return false
+	}
+	raft.mu.Lock()
+	raft.VotingPolicy = &policy
+	raft.mu.Unlock()
+	return true
+}
+
+// applyVotingRules sets a raft's voting policy from the latest adopted
+// voting rule among its rules, as when joining a raft with its rules
+func (g *Governance) applyVotingRules(raft *RaftInfo) {
+	var latest *Rule
+	raft.mu.RLock()
+	for _, rule := range raft.Rules {
+		if rule.Scope == VotingScope && rule.AdoptedAt != nil && (latest == nil || rule.Version > latest.Version) {
+			latest = rule
+		}
+	}
+	raft.mu.RUnlock()
+	if latest != nil {
+		g.applyVotingRule(raft, latest)
+	}
+}
+
+// needsSuperMajority reports whether a rule proposal changes an adopted
+// rule or the raft's voting policy, or overrides a rule the raft inherits
+// through its charter
+func (g *Governance) needsSuperMajority(proposal *Proposal) bool {
+	if proposal.kind() != ProposalKindRule || proposal.Rule == nil {
+		return false
+	}
+	rule := proposal.Rule
+	return rule.BaseRuleID != "" || rule.Scope == VotingScope || g.inheritedRuleCovering(proposal.RaftID, rule.Scope) != nil
+}
+
+// tally is the count of a proposal's eligible votes
+type tally struct {
+	eligible, cast, yes, no int
+	proposerVote            VoteType // Empty when the proposer has not voted
+}
+
+// ballotTally counts the ballots on proposalID cast by the eligible voters.
+// Ballots that do not verify against the voter's signing key are ignored.
+func ballotTally(proposalID, proposer string, ballots []SignedVote, eligible []string, keys map[string][]byte) tally {
+	votes := make(map[string]VoteType, len(ballots))
+	for i := range ballots {
+		ballot := &ballots[i]
+		if ballot.ProposalID != proposalID || !hasVoter(eligible, ballot.VoterID) || verifyVote(ballot, keys[ballot.VoterID]) != nil {
+			continue
+		}
+		votes[ballot.VoterID] = ballot.Vote
+	}
+
+	count := tally{eligible: len(eligible), cast: len(votes), proposerVote: votes[proposer]}
+	for _, vote := range votes {
+		switch vote {
+		case VoteYes:
+			count.yes++
+		case VoteNo:
+			count.no++
+		}
+	}
+	return count
+}
+
+// sortedBallots returns a proposal's ballots ordered by voter. The caller
+// must hold the proposal registry lock.
+func sortedBallots(proposal *Proposal) []SignedVote {
+	ballots := make([]SignedVote, 0, len(proposal.Ballots))
+	for _, ballot := range proposal.Ballots {
+		ballots = append(ballots, *ballot)
+	}
+	sort.Slice(ballots, func(i, j int) bool { return ballots[i].VoterID < ballots[j].VoterID })
+	return ballots
+}
+
+// threshold returns the votes a fraction of n needs, rounded up
+func threshold(n int, fraction float64) int {
+	return int(math.Ceil(float64(n)*fraction - 1e-9))
+}
+
+// decide applies the policy to a tally. A proposal is adopted once its
+// YES votes reach the (super-)majority and outnumber its NO votes, and
+// closes when adopted or when every eligible member has voted.
+func (p VotingPolicy) decide(t tally, super bool) (quorumMet, adopted, closed bool) {
+	quorumMet = t.cast >= threshold(t.eligible, p.Quorum)
+	allVoted := t.cast >= t.eligible
+	if !quorumMet {
+		return false, false, false
+	}
+
+	fraction := p.Majority
+	if super {
+		fraction = p.SuperMajority
+	}
+	reached := t.yes >= threshold(t.eligible, fraction)
+	switch {
+	case reached && t.yes > t.no:
+		return true, true, true
+	case reached && t.yes == t.no && allVoted:
+		switch p.TieBreak {
+		case TieBreakAdopt:
+			adopted = true
+		case TieBreakProposer:
+			adopted = t.proposerVote == VoteYes
+		}
+		return true, adopted, true
+	}
+	return true, false, allVoted
+}
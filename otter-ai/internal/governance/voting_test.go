@@ -0,0 +1,171 @@
+package governance
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"otter-ai/internal/errs"
+	"otter-ai/internal/memory"
+	"otter-ai/internal/vectordb"
+)
+
+func TestParseVotingPolicy(t *testing.T) {
+	policy, err := ParseVotingPolicy("Decide by simple majority.\nQuorum: 50%\nmajority: 0.5\nSuper  Majority: 0.6\ntie break: Proposer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := VotingPolicy{Quorum: 0.5, Majority: 0.5, SuperMajority: 0.6, TieBreak: TieBreakProposer}
+	if policy != want {
+		t.Errorf("policy = %+v, want %+v", policy, want)
+	}
+
+	for _, body := range []string{
+		"No settings here",
+		"quorum: lots",
+		"majority: 1.5",
+		"majority: 0.8\nsuper majority: 0.7",
+		"tie break: coin",
+	} {
+		if _, err := ParseVotingPolicy(body); !errors.Is(err, errs.ErrInvalid) {
+			t.Errorf("ParseVotingPolicy(%q) = %v, want an invalid-input error", body, err)
+		}
+	}
+}
+
+func TestVotingPolicy_Decide(t *testing.T) {
+	simple := VotingPolicy{Quorum: 0.5, Majority: 0.5, SuperMajority: 0.75}
+	tests := []struct {
+		name                       string
+		policy                     VotingPolicy
+		tally                      tally
+		super                      bool
+		wantQuorum, wantAdopt, end bool
+	}{
+		{"default solo yes", DefaultVotingPolicy, tally{eligible: 1, cast: 1, yes: 1}, false, true, true, true},
+		{"default pair split", DefaultVotingPolicy, tally{eligible: 2, cast: 2, yes: 1, no: 1}, false, true, false, true},
+		{"default pair waiting", DefaultVotingPolicy, tally{eligible: 2, cast: 1, yes: 1}, false, false, false, false},
+		{"default three needs all", DefaultVotingPolicy, tally{eligible: 3, cast: 2, yes: 2}, false, false, false, false},
+		{"simple majority early", simple, tally{eligible: 4, cast: 2, yes: 2}, false, true, true, true},
+		{"simple majority super", simple, tally{eligible: 4, cast: 2, yes: 2}, true, true, false, false},
+		{"tie waits for every vote", simple, tally{eligible: 5, cast: 4, yes: 2, no: 2}, false, true, false, false},
+		{"tie rejected", simple, tally{eligible: 4, cast: 4, yes: 2, no: 2}, false, true, false, true},
+		{"tie adopted", VotingPolicy{Quorum: 0.5, Majority: 0.5, TieBreak: TieBreakAdopt}, tally{eligible: 4, cast: 4, yes: 2, no: 2}, false, true, true, true},
+		{"tie to proposer", VotingPolicy{Quorum: 0.5, Majority: 0.5, TieBreak: TieBreakProposer}, tally{eligible: 4, cast: 4, yes: 2, no: 2, proposerVote: VoteYes}, false, true, true, true},
+		{"tie against proposer", VotingPolicy{Quorum: 0.5, Majority: 0.5, TieBreak: TieBreakProposer}, tally{eligible: 4, cast: 4, yes: 2, no: 2, proposerVote: VoteNo}, false, true, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quorum, adopted, closed := tt.policy.withDefaults(DefaultVotingPolicy).decide(tt.tally, tt.super)
+			if quorum != tt.wantQuorum || adopted != tt.wantAdopt || closed != tt.end {
+				t.Errorf("decide = quorum %v, adopted %v, closed %v; want %v, %v, %v", quorum, adopted, closed, tt.wantQuorum, tt.wantAdopt, tt.end)
+			}
+		})
+	}
+}
+
+func TestCheckProposalOutcome_RaftVotingPolicy(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	addPeers(g, "otter-2", "otter-3", "otter-4")
+	g.rafts.rafts["otter-1"].VotingPolicy = &VotingPolicy{Quorum: 0.5, Majority: 0.5}
+
+	proposal := &Proposal{
+		ProposalID: "p1",
+		RaftID:     "otter-1",
+		Rule:       &Rule{RuleID: "r1", RaftID: "otter-1", Scope: "test", Body: "rule"},
+		Votes:      map[string]VoteType{"otter-1": VoteYes, "otter-2": VoteYes},
+		Status:     ProposalOpen,
+		Result:     ResultPending,
+	}
+	g.checkProposalOutcome(proposal)
+	if proposal.Result != ResultAdopted {
+		t.Errorf("result = %q, want adopted by half of four members", proposal.Result)
+	}
+}
+
+func TestVotingRule_SetsRaftPolicy(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	ctx := context.Background()
+
+	if _, err := g.ProposeRule(ctx, "otter-1", &Rule{Scope: VotingScope, Body: "quorum: 2", ProposedBy: "otter-1"}); !errors.Is(err, errs.ErrInvalid) {
+		t.Fatalf("ProposeRule = %v, want an invalid voting rule refused", err)
+	}
+
+	proposal, err := g.ProposeRule(ctx, "otter-1", &Rule{Scope: VotingScope, Body: "majority: 0.5\ntie break: adopt", ProposedBy: "otter-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.needsSuperMajority(proposal) {
+		t.Error("a voting rule should need the super-majority")
+	}
+	if err := g.CastVote(ctx, proposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	if proposal.Result != ResultAdopted {
+		t.Fatalf("result = %q, want adopted", proposal.Result)
+	}
+
+	policy, err := g.VotingPolicy("otter-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := DefaultVotingPolicy
+	want.Majority, want.TieBreak = 0.5, TieBreakAdopt
+	if policy != want {
+		t.Errorf("policy = %+v, want %+v", policy, want)
+	}
+	for _, summary := range g.RaftSummaries() {
+		if summary.RaftID == "otter-1" && summary.VotingPolicy != want {
+			t.Errorf("summary policy = %+v, want %+v", summary.VotingPolicy, want)
+		}
+	}
+}
+
+func TestVotingPolicy_ConfiguredDefault(t *testing.T) {
+	g := newTestGovernance("otter-1")
+	g.config.VotingPolicy = VotingPolicy{Quorum: 0.4, TieBreak: TieBreakProposer}
+
+	policy, err := g.VotingPolicy("otter-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy.Quorum != 0.4 || policy.TieBreak != TieBreakProposer || policy.Majority != DefaultVotingPolicy.Majority {
+		t.Errorf("policy = %+v, want the configured quorum and tie-break over the defaults", policy)
+	}
+	if _, err := g.VotingPolicy("nope"); !errors.Is(err, ErrRaftNotFound) {
+		t.Errorf("unknown raft: err = %v", err)
+	}
+}
+
+func TestVotingPolicy_Persisted(t *testing.T) {
+	dir := t.TempDir()
+	db, err := vectordb.NewSQLiteVectorDB(filepath.Join(dir, "otter.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	g, err := New(RaftConfig{ID: "otter-1", DataDir: dir}, memory.New(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	proposal, err := g.ProposeRule(ctx, "otter-1", &Rule{Scope: VotingScope, Body: "quorum: 0.5", ProposedBy: "otter-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CastVote(ctx, proposal.ProposalID, VoteYes); err != nil {
+		t.Fatal(err)
+	}
+	g.Shutdown(ctx)
+
+	reloaded, err := New(RaftConfig{ID: "otter-1", DataDir: dir}, memory.New(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reloaded.Shutdown(ctx)
+	if policy, _ := reloaded.VotingPolicy("otter-1"); policy.Quorum != 0.5 {
+		t.Errorf("reloaded policy = %+v, want the adopted quorum", policy)
+	}
+}
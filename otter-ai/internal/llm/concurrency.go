@@ -0,0 +1,232 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"otter-ai/internal/config"
+	"otter-ai/internal/errs"
+)
+
+// ErrOverloaded is returned without queueing a request when a provider's
+// queue is full, or when a request waited longer than the queue timeout
+var ErrOverloaded = errs.New(errs.ErrLLMUnavailable, "llm provider overloaded")
+
+// Priority is the lane a request waits in for a free slot. Waiting
+// interactive requests always go before background ones.
+type Priority int
+
+const (
+	PriorityInteractive Priority = iota // Chat and anything a user is waiting for
+	PriorityBackground                  // Musing, summaries, consolidation and other scheduled work
+	priorityLanes
+)
+
+// String returns the lane name
+func (p Priority) String() string {
+	if p == PriorityBackground {
+		return "background"
+	}
+	return "interactive"
+}
+
+type priorityKey struct{}
+
+// WithPriority returns a context whose requests wait in the given lane,
+// whatever their profile
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// requestPriority returns the lane of a request: the context's, or
+// background for the musing, summary and introspection profiles
+func requestPriority(ctx context.Context, profile string) Priority {
+	if priority, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return priority
+	}
+	switch profile {
+	case ProfileMusing, ProfileSummary, ProfileIntrospection:
+		return PriorityBackground
+	}
+	return PriorityInteractive
+}
+
+// ConcurrencyConfig bounds the requests a provider serves at once. Zero
+// MaxConcurrent disables the limit; zero MaxQueued or QueueTimeout lets
+// requests queue without bound.
+type ConcurrencyConfig struct {
+	MaxConcurrent int           // Requests in flight at once
+	MaxQueued     int           // Requests waiting for a slot before new ones are refused
+	QueueTimeout  time.Duration // Longest a request waits for a slot
+}
+
+// concurrencyConfig reads a provider's limit and the shared queue settings
+// from the LLM configuration
+func concurrencyConfig(cfg config.LLMConfig, maxConcurrent int) ConcurrencyConfig {
+	return ConcurrencyConfig{
+		MaxConcurrent: maxConcurrent,
+		MaxQueued:     cfg.MaxQueued,
+		QueueTimeout:  cfg.QueueTimeout,
+	}
+}
+
+// QueueStats describes a concurrency-limited provider's load
+type QueueStats struct {
+	Limit       int              `json:"limit"`
+	Active      int              `json:"active"`
+	Queued      map[string]int   `json:"queued"` // Waiting requests by lane
+	Overloaded  int64            `json:"overloaded"`
+	QueueWaitMs map[string]int64 `json:"queue_wait_ms"` // Total time requests waited, by lane
+}
+
+// limitedProvider bounds the concurrent requests to a provider, queueing
+// the rest by priority
+type limitedProvider struct {
+	Provider
+	config ConcurrencyConfig
+
+	mu     sync.Mutex
+	active int
+	queues [priorityLanes][]chan struct{}
+
+	overloaded atomic.Int64
+	waited     [priorityLanes]atomic.Int64 // Milliseconds
+}
+
+// WithConcurrencyLimit wraps a provider so at most config.MaxConcurrent
+// requests run at once. Others queue, interactive before background, and
+// fail with ErrOverloaded when the queue is full or they wait too long.
+// Resilience stats of the wrapped provider stay visible, with the queue's
+// added.
+func WithConcurrencyLimit(provider Provider, config ConcurrencyConfig) Provider {
+	if config.MaxConcurrent <= 0 {
+		return provider
+	}
+	return &limitedProvider{Provider: provider, config: config}
+}
+
+func (p *limitedProvider) Complete(ctx context.Context, request *CompletionRequest) (*CompletionResponse, error) {
+	if err := p.acquire(ctx, requestPriority(ctx, request.Profile)); err != nil {
+		return nil, err
+	}
+	defer p.release()
+	return p.Provider.Complete(ctx, request)
+}
+
+func (p *limitedProvider) Embed(ctx context.Context, text string) ([]float32, error) {
+	if err := p.acquire(ctx, requestPriority(ctx, "")); err != nil {
+		return nil, err
+	}
+	defer p.release()
+	return p.Provider.Embed(ctx, text)
+}
+
+// acquire takes a slot, waiting in the request's lane while none is free
+func (p *limitedProvider) acquire(ctx context.Context, lane Priority) error {
+	p.mu.Lock()
+	if p.active < p.config.MaxConcurrent && p.queuedLocked() == 0 {
+		p.active++
+		p.mu.Unlock()
+		return nil
+	}
+	if p.config.MaxQueued > 0 && p.queuedLocked() >= p.config.MaxQueued {
+		p.mu.Unlock()
+		p.overloaded.Add(1)
+		return fmt.Errorf("%w: %s queue is full", ErrOverloaded, p.Name())
+	}
+	ready := make(chan struct{})
+	p.queues[lane] = append(p.queues[lane], ready)
+	p.mu.Unlock()
+
+	start := time.Now()
+	defer func() { p.waited[lane].Add(time.Since(start).Milliseconds()) }()
+
+	var timeout <-chan time.Time
+	if p.config.QueueTimeout > 0 {
+		timer := time.NewTimer(p.config.QueueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	var err error
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-timeout:
+		p.overloaded.Add(1)
+		err = fmt.Errorf("%w: no %s slot within %s", ErrOverloaded, p.Name(), p.config.QueueTimeout)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, waiter := range p.queues[lane] {
+		if waiter == ready {
+			p.queues[lane] = append(p.queues[lane][:i], p.queues[lane][i+1:]...)
+			return err
+		}
+	}
+	// The slot was handed over while giving up; pass it on
+	p.releaseLocked()
+	return err
+}
+
+// release frees a slot, handing it to the first waiter of the highest lane
+func (p *limitedProvider) release() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.releaseLocked()
+}
+
+func (p *limitedProvider) releaseLocked() {
+	for lane := range p.queues {
+		if len(p.queues[lane]) > 0 {
+			ready := p.queues[lane][0]
+			p.queues[lane] = p.queues[lane][1:]
+			close(ready)
+			return
+		}
+	}
+	p.active--
+}
+
+// queuedLocked returns how many requests are waiting
+func (p *limitedProvider) queuedLocked() int {
+	n := 0
+	for _, queue := range p.queues {
+		n += len(queue)
+	}
+	return n
+}
+
+// QueueStats returns the provider's current load
+func (p *limitedProvider) QueueStats() QueueStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := QueueStats{
+		Limit:       p.config.MaxConcurrent,
+		Active:      p.active,
+		Queued:      make(map[string]int, priorityLanes),
+		Overloaded:  p.overloaded.Load(),
+		QueueWaitMs: make(map[string]int64, priorityLanes),
+	}
+	for lane := Priority(0); lane < priorityLanes; lane++ {
+		stats.Queued[lane.String()] = len(p.queues[lane])
+		stats.QueueWaitMs[lane.String()] = p.waited[lane].Load()
+	}
+	return stats
+}
+
+// ResilienceStats returns the wrapped provider's stats with the queue's
+func (p *limitedProvider) ResilienceStats() ResilienceStats {
+	var stats ResilienceStats
+	if reporter, ok := p.Provider.(ResilienceReporter); ok {
+		stats = reporter.ResilienceStats()
+	}
+	queue := p.QueueStats()
+	stats.Queue = &queue
+	return stats
+}
@@ -0,0 +1,216 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gatedProvider blocks each call until it is released, recording the order
+// calls started in
+type gatedProvider struct {
+	release chan struct{}
+
+	mu      sync.Mutex
+	started []string
+	active  int
+	peak    int
+}
+
+func newGatedProvider() *gatedProvider {
+	return &gatedProvider{release: make(chan struct{})}
+}
+
+func (p *gatedProvider) Complete(ctx context.Context, request *CompletionRequest) (*CompletionResponse, error) {
+	p.mu.Lock()
+	p.started = append(p.started, request.Prompt)
+	p.active++
+	p.peak = max(p.peak, p.active)
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		p.active--
+		p.mu.Unlock()
+	}()
+
+	select {
+	case <-p.release:
+		return &CompletionResponse{Text: request.Prompt}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (p *gatedProvider) Embed(ctx context.Context, text string) ([]float32, error) {
+	if _, err := p.Complete(ctx, &CompletionRequest{Prompt: text}); err != nil {
+		return nil, err
+	}
+	return []float32{1}, nil
+}
+
+func (p *gatedProvider) Name() string { return "gated" }
+
+func (p *gatedProvider) startedCalls() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.started...)
+}
+
+func queueStats(t *testing.T, p Provider) QueueStats {
+	t.Helper()
+	s := stats(t, p)
+	if s.Queue == nil {
+		t.Fatal("no queue stats")
+	}
+	return *s.Queue
+}
+
+// waitFor polls until cond holds
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not reached")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWithConcurrencyLimit_Disabled(t *testing.T) {
+	inner := newGatedProvider()
+	if p := WithConcurrencyLimit(inner, ConcurrencyConfig{}); p != Provider(inner) {
+		t.Error("a zero limit should return the provider unwrapped")
+	}
+}
+
+func TestWithConcurrencyLimit_BoundsConcurrency(t *testing.T) {
+	inner := newGatedProvider()
+	p := WithConcurrencyLimit(inner, ConcurrencyConfig{MaxConcurrent: 2})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := p.Complete(context.Background(), &CompletionRequest{Prompt: "x"}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	waitFor(t, func() bool { return queueStats(t, p).Queued["interactive"] == 3 })
+	if s := queueStats(t, p); s.Active != 2 || s.Limit != 2 {
+		t.Errorf("stats = %+v", s)
+	}
+	close(inner.release)
+	wg.Wait()
+
+	if inner.peak != 2 {
+		t.Errorf("peak concurrency = %d, want 2", inner.peak)
+	}
+	if s := queueStats(t, p); s.Active != 0 || s.Queued["interactive"] != 0 {
+		t.Errorf("stats after = %+v", s)
+	}
+}
+
+func TestWithConcurrencyLimit_InteractiveFirst(t *testing.T) {
+	inner := newGatedProvider()
+	p := WithConcurrencyLimit(inner, ConcurrencyConfig{MaxConcurrent: 1})
+
+	var wg sync.WaitGroup
+	run := func(ctx context.Context, request *CompletionRequest) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.Complete(ctx, request)
+		}()
+	}
+	run(context.Background(), &CompletionRequest{Prompt: "first"})
+	waitFor(t, func() bool { return len(inner.startedCalls()) == 1 })
+
+	run(context.Background(), &CompletionRequest{Prompt: "musing", Profile: ProfileMusing})
+	waitFor(t, func() bool { return queueStats(t, p).Queued["background"] == 1 })
+	run(WithPriority(context.Background(), PriorityBackground), &CompletionRequest{Prompt: "consolidation"})
+	waitFor(t, func() bool { return queueStats(t, p).Queued["background"] == 2 })
+	run(context.Background(), &CompletionRequest{Prompt: "chat", Profile: ProfileChat})
+	waitFor(t, func() bool { return queueStats(t, p).Queued["interactive"] == 1 })
+
+	for i := 0; i < 4; i++ {
+		inner.release <- struct{}{}
+	}
+	wg.Wait()
+
+	want := []string{"first", "chat", "musing", "consolidation"}
+	got := inner.startedCalls()
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("call order = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestWithConcurrencyLimit_Backpressure(t *testing.T) {
+	inner := newGatedProvider()
+	p := WithConcurrencyLimit(inner, ConcurrencyConfig{MaxConcurrent: 1, MaxQueued: 1})
+	defer close(inner.release)
+
+	go p.Complete(context.Background(), &CompletionRequest{})
+	waitFor(t, func() bool { return queueStats(t, p).Active == 1 })
+	go p.Complete(context.Background(), &CompletionRequest{})
+	waitFor(t, func() bool { return queueStats(t, p).Queued["interactive"] == 1 })
+
+	if _, err := p.Complete(context.Background(), &CompletionRequest{}); !errors.Is(err, ErrOverloaded) {
+		t.Errorf("err = %v, want ErrOverloaded", err)
+	}
+	if _, err := p.Embed(context.Background(), "text"); !errors.Is(err, ErrOverloaded) {
+		t.Errorf("Embed err = %v, want ErrOverloaded", err)
+	}
+	if s := queueStats(t, p); s.Overloaded != 2 {
+		t.Errorf("overloaded = %d, want 2", s.Overloaded)
+	}
+}
+
+func TestWithConcurrencyLimit_GivingUpFreesPlace(t *testing.T) {
+	inner := newGatedProvider()
+	p := WithConcurrencyLimit(inner, ConcurrencyConfig{MaxConcurrent: 1, QueueTimeout: 20 * time.Millisecond})
+
+	go p.Complete(context.Background(), &CompletionRequest{Prompt: "busy"})
+	waitFor(t, func() bool { return queueStats(t, p).Active == 1 })
+
+	if _, err := p.Complete(context.Background(), &CompletionRequest{}); !errors.Is(err, ErrOverloaded) {
+		t.Errorf("err = %v, want ErrOverloaded after the queue timeout", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := p.Complete(ctx, &CompletionRequest{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if s := queueStats(t, p); s.Queued["interactive"] != 0 || s.Active != 1 {
+		t.Errorf("stats = %+v", s)
+	}
+
+	inner.release <- struct{}{}
+	waitFor(t, func() bool { return queueStats(t, p).Active == 0 })
+	done := make(chan error, 1)
+	go func() {
+		_, err := p.Complete(context.Background(), &CompletionRequest{Prompt: "next"})
+		done <- err
+	}()
+	inner.release <- struct{}{}
+	if err := <-done; err != nil {
+		t.Errorf("Complete after the queue emptied: %v", err)
+	}
+}
+
+func TestWithConcurrencyLimit_KeepsResilienceStats(t *testing.T) {
+	inner := WithResilience(&flakyProvider{}, ResilienceConfig{})
+	p := WithConcurrencyLimit(inner, ConcurrencyConfig{MaxConcurrent: 1})
+	if _, err := p.Complete(context.Background(), &CompletionRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if s := stats(t, p); s.Requests != 1 || s.Queue == nil {
+		t.Errorf("stats = %+v", s)
+	}
+}
@@ -0,0 +1,101 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+
+	"otter-ai/internal/config"
+)
+
+// dimensionProbe is embedded to learn the dimension of a provider's vectors
+const dimensionProbe = "otter"
+
+// EmbeddingProvider generates the vectors memories are stored and searched
+// by. Every Provider is one, but embeddings may come from a different
+// backend than chat.
+type EmbeddingProvider interface {
+	// Embed generates embeddings for the given text
+	Embed(ctx context.Context, text string) ([]float32, error)
+
+	// Name returns the provider name
+	Name() string
+}
+
+// NewEmbeddingProvider creates the embedding provider. Unset fields of cfg
+// fall back to the chat configuration when the provider is the same, or is
+// not set. Requests get the LLM's timeout, retries and circuit breaker.
+func NewEmbeddingProvider(chat config.LLMConfig, cfg config.EmbeddingConfig) (EmbeddingProvider, error) {
+	embedding := embeddingConfig(chat, cfg)
+
+	var provider Provider
+	var err error
+	switch ProviderType(embedding.Provider) {
+	case ProviderOpenWebUI:
+		provider, err = NewOpenWebUIProvider(embedding)
+	case ProviderOpenAI:
+		provider, err = NewOpenAIProvider(embedding)
+	case ProviderOllama:
+		provider, err = NewOllamaProvider(embedding)
+	case ProviderAnthropic:
+		return nil, fmt.Errorf("anthropic does not provide embeddings; set OTTER_EMBEDDING_PROVIDER")
+	default:
+		return nil, fmt.Errorf("unsupported embedding provider: %s", embedding.Provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+	provider = WithResilience(provider, resilienceConfig(chat))
+	return WithConcurrencyLimit(provider, concurrencyConfig(chat, cfg.MaxConcurrent)), nil
+}
+
+// EmbeddingModel names the model NewEmbeddingProvider embeds with, as
+// provider/model, for recording which model produced stored vectors
+func EmbeddingModel(chat config.LLMConfig, cfg config.EmbeddingConfig) string {
+	embedding := embeddingConfig(chat, cfg)
+	model := embedding.EmbeddingModel
+	if model == "" {
+		model = embedding.Model
+		if ProviderType(embedding.Provider) == ProviderOpenAI {
+			model = DefaultOpenAIEmbeddingModel
+		}
+	}
+	return embedding.Provider + "/" + model
+}
+
+// embeddingConfig returns the provider configuration embeddings are
+// requested with
+func embeddingConfig(chat config.LLMConfig, cfg config.EmbeddingConfig) config.LLMConfig {
+	embedding := config.LLMConfig{
+		Provider:       cfg.Provider,
+		Endpoint:       cfg.Endpoint,
+		APIKey:         cfg.APIKey,
+		EmbeddingModel: cfg.Model,
+	}
+	if embedding.Provider == "" || embedding.Provider == chat.Provider {
+		embedding.Provider = chat.Provider
+		embedding.Model = chat.Model
+		if embedding.Endpoint == "" {
+			embedding.Endpoint = chat.Endpoint
+		}
+		if embedding.APIKey == "" {
+			embedding.APIKey = chat.APIKey
+		}
+		if embedding.EmbeddingModel == "" {
+			embedding.EmbeddingModel = chat.EmbeddingModel
+		}
+	}
+	return embedding
+}
+
+// Dimension returns the dimension of the provider's vectors by embedding a
+// probe text
+func Dimension(ctx context.Context, provider EmbeddingProvider) (int, error) {
+	vector, err := provider.Embed(ctx, dimensionProbe)
+	if err != nil {
+		return 0, fmt.Errorf("failed to probe embedding dimension: %w", err)
+	}
+	if len(vector) == 0 {
+		return 0, fmt.Errorf("%s returned an empty embedding", provider.Name())
+	}
+	return len(vector), nil
+}
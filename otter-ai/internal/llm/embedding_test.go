@@ -0,0 +1,95 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"otter-ai/internal/config"
+)
+
+func TestNewEmbeddingProvider_Separate(t *testing.T) {
+	var model string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		model, _ = body["model"].(string)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": []map[string]interface{}{{"embedding": []float32{0.1, 0.2, 0.3}}},
+		})
+	}))
+	defer srv.Close()
+
+	chat := config.LLMConfig{Provider: "ollama", Endpoint: "http://ollama:11434", Model: "llama2"}
+	p, err := NewEmbeddingProvider(chat, config.EmbeddingConfig{Provider: "openai", Endpoint: srv.URL, APIKey: "sk-test"})
+	if err != nil {
+		t.Fatalf("NewEmbeddingProvider: %v", err)
+	}
+	if p.Name() != "openai" {
+		t.Errorf("Name() = %q, want openai", p.Name())
+	}
+
+	dimension, err := Dimension(context.Background(), p)
+	if err != nil {
+		t.Fatalf("Dimension: %v", err)
+	}
+	if dimension != 3 {
+		t.Errorf("dimension = %d, want 3", dimension)
+	}
+	if model != DefaultOpenAIEmbeddingModel {
+		t.Errorf("model = %q, want %q", model, DefaultOpenAIEmbeddingModel)
+	}
+}
+
+func TestNewEmbeddingProvider_FallsBackToChat(t *testing.T) {
+	var model string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		model, _ = body["model"].(string)
+		json.NewEncoder(w).Encode(map[string]interface{}{"embedding": []float32{0.1}})
+	}))
+	defer srv.Close()
+
+	chat := config.LLMConfig{Provider: "ollama", Endpoint: srv.URL, Model: "llama2"}
+	p, err := NewEmbeddingProvider(chat, config.EmbeddingConfig{Model: "nomic-embed-text"})
+	if err != nil {
+		t.Fatalf("NewEmbeddingProvider: %v", err)
+	}
+	if _, err := p.Embed(context.Background(), "hello"); err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if model != "nomic-embed-text" {
+		t.Errorf("model = %q, want nomic-embed-text", model)
+	}
+}
+
+func TestNewEmbeddingProvider_Anthropic(t *testing.T) {
+	_, err := NewEmbeddingProvider(config.LLMConfig{Provider: "anthropic"}, config.EmbeddingConfig{})
+	if err == nil {
+		t.Error("expected error: anthropic has no embeddings")
+	}
+}
+
+func TestEmbeddingModel(t *testing.T) {
+	chat := config.LLMConfig{Provider: "ollama", Model: "llama2", EmbeddingModel: "nomic-embed-text"}
+	tests := []struct {
+		cfg  config.EmbeddingConfig
+		want string
+	}{
+		{config.EmbeddingConfig{}, "ollama/nomic-embed-text"},
+		{config.EmbeddingConfig{Model: "mxbai-embed-large"}, "ollama/mxbai-embed-large"},
+		{config.EmbeddingConfig{Provider: "openai"}, "openai/" + DefaultOpenAIEmbeddingModel},
+		{config.EmbeddingConfig{Provider: "openwebui", Model: "bge-m3"}, "openwebui/bge-m3"},
+	}
+	for _, tt := range tests {
+		if got := EmbeddingModel(chat, tt.cfg); got != tt.want {
+			t.Errorf("EmbeddingModel(%+v) = %q, want %q", tt.cfg, got, tt.want)
+		}
+	}
+	if got := EmbeddingModel(config.LLMConfig{Provider: "ollama", Model: "llama2"}, config.EmbeddingConfig{}); got != "ollama/llama2" {
+		t.Errorf("chat model fallback = %q, want ollama/llama2", got)
+	}
+}
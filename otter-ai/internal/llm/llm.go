@@ -44,12 +44,19 @@ type ToolCall struct {
 
 // CompletionRequest represents a completion request
 type CompletionRequest struct {
-	Prompt       string
-	MaxTokens    int
-	Temperature  float32
-	StopTokens   []string
-	SystemPrompt string
-	Tools        []ToolDefinition // available tools (optional)
+	Prompt         string
+	Profile        string // Parameter profile for the task (see profiles.go); fills unset parameters
+	MaxTokens      int
+	Temperature    float32 // 0 leaves the provider default unless TemperatureSet
+	TemperatureSet bool    // Send Temperature even when it is 0
+	StopTokens     []string
+	SystemPrompt   string
+	Tools          []ToolDefinition // available tools (optional)
+}
+
+// sendTemperature reports whether the request sets a temperature
+func (r *CompletionRequest) sendTemperature() bool {
+	return r.TemperatureSet || r.Temperature > 0
 }
 
 // CompletionResponse represents a completion response
@@ -70,20 +77,29 @@ const (
 	ProviderOllama    ProviderType = "ollama"
 )
 
-// NewProvider creates a new LLM provider based on configuration
+// NewProvider creates a new LLM provider based on configuration. Requests
+// naming a parameter profile get the configured profile's parameters, and
+// all requests get the configured timeout, retries and circuit breaker.
 func NewProvider(cfg config.LLMConfig) (Provider, error) {
+	var provider Provider
+	var err error
 	switch ProviderType(cfg.Provider) {
 	case ProviderOpenWebUI:
-		return NewOpenWebUIProvider(cfg)
+		provider, err = NewOpenWebUIProvider(cfg)
 	case ProviderOpenAI:
-		return NewOpenAIProvider(cfg)
+		provider, err = NewOpenAIProvider(cfg)
 	case ProviderAnthropic:
-		return NewAnthropicProvider(cfg)
+		provider, err = NewAnthropicProvider(cfg)
 	case ProviderOllama:
-		return NewOllamaProvider(cfg)
+		provider, err = NewOllamaProvider(cfg)
 	default:
 		return nil, fmt.Errorf("unsupported LLM provider: %s", cfg.Provider)
 	}
+	if err != nil {
+		return nil, err
+	}
+	provider = WithResilience(WithProfiles(provider, NewProfiles(cfg.Profiles)), resilienceConfig(cfg))
+	return WithConcurrencyLimit(provider, concurrencyConfig(cfg, cfg.MaxConcurrent)), nil
 }
 
 // buildOpenAITools converts ToolDefinitions to the OpenAI function-calling
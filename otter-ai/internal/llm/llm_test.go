@@ -760,3 +760,30 @@ func TestOpenWebUI_Complete_WithAllOptions(t *testing.T) {
 		t.Fatalf("Complete: %v", err)
 	}
 }
+
+// --- Ping ---
+
+func TestPing(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/tags" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"models": []}`))
+	}))
+	defer srv.Close()
+
+	p, _ := NewOllamaProvider(config.LLMConfig{Endpoint: srv.URL, Model: "test"})
+	wrapped := WithResilience(WithConcurrencyLimit(p, ConcurrencyConfig{MaxConcurrent: 1}), ResilienceConfig{})
+	if err := Ping(context.Background(), wrapped); err != nil {
+		t.Errorf("Ping through wrappers: %v", err)
+	}
+
+	down, _ := NewOllamaProvider(config.LLMConfig{Endpoint: srv.URL + "/missing", Model: "test"})
+	if err := Ping(context.Background(), down); err == nil {
+		t.Error("expected an error for a failing upstream")
+	}
+	if err := Ping(context.Background(), &AnthropicProvider{}); err != ErrPingUnsupported {
+		t.Errorf("Ping(anthropic) = %v; want ErrPingUnsupported", err)
+	}
+}
@@ -0,0 +1,83 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Pinger is implemented by providers that can check their upstream is
+// reachable without generating anything
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// ErrPingUnsupported is returned by Ping for providers that can't be
+// checked
+var ErrPingUnsupported = errors.New("provider does not support health checks")
+
+// Ping checks a provider's upstream is reachable, looking through the
+// wrappers adding profiles, resilience, concurrency limits and usage
+// tracking. Pings skip those wrappers, so they aren't retried, queued or
+// counted.
+func Ping(ctx context.Context, provider Provider) error {
+	for provider != nil {
+		if pinger, ok := provider.(Pinger); ok {
+			return pinger.Ping(ctx)
+		}
+		wrapper, ok := provider.(interface{ Unwrap() Provider })
+		if !ok {
+			break
+		}
+		provider = wrapper.Unwrap()
+	}
+	return ErrPingUnsupported
+}
+
+// pingURL checks a GET of url succeeds, sending apiKey as a bearer token
+// when set
+func pingURL(ctx context.Context, client *http.Client, url, apiKey string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("provider returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Ping lists the installed models
+func (p *OllamaProvider) Ping(ctx context.Context) error {
+	return pingURL(ctx, p.client, p.endpoint+"/api/tags", "")
+}
+
+// Ping lists the available models, which also checks the API key
+func (p *OpenWebUIProvider) Ping(ctx context.Context) error {
+	return pingURL(ctx, p.client, p.endpoint+"/api/models", p.apiKey)
+}
+
+// Ping lists the available models, which also checks the API key
+func (p *OpenAIProvider) Ping(ctx context.Context) error {
+	return pingURL(ctx, p.client, p.endpoint+"/models", p.apiKey)
+}
+
+// Unwrap returns the wrapped provider
+func (p *profiledProvider) Unwrap() Provider { return p.Provider }
+
+// Unwrap returns the wrapped provider
+func (p *resilientProvider) Unwrap() Provider { return p.Provider }
+
+// Unwrap returns the wrapped provider
+func (p *limitedProvider) Unwrap() Provider { return p.Provider }
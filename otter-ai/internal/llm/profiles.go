@@ -0,0 +1,99 @@
+package llm
+
+import (
+	"context"
+
+	"otter-ai/internal/config"
+)
+
+// Parameter profile names. Callers name the kind of task a completion is
+// for and the provider fills in that profile's sampling parameters.
+const (
+	ProfileChat           = "chat"
+	ProfileClassification = "classification"
+	ProfileMusing         = "musing"
+	ProfileNegotiation    = "negotiation"
+	ProfileSummary        = "summary"
+	ProfileIntrospection  = "introspection"
+)
+
+// Profile is a named set of sampling parameters
+type Profile struct {
+	Temperature     float32
+	OmitTemperature bool // Leave the temperature to the provider, for models that reject one
+	MaxTokens       int
+}
+
+// DefaultProfiles returns the built-in parameter profiles
+func DefaultProfiles() Profiles {
+	return Profiles{
+		ProfileChat:           {Temperature: 1.0, MaxTokens: 300},
+		ProfileClassification: {Temperature: 0, MaxTokens: 100},
+		ProfileMusing:         {Temperature: 0.9, MaxTokens: 220},
+		ProfileNegotiation:    {Temperature: 0.3, MaxTokens: 400},
+		ProfileSummary:        {Temperature: 0.2, MaxTokens: 200},
+		ProfileIntrospection:  {Temperature: 0.4, MaxTokens: 260},
+	}
+}
+
+// Profiles maps profile names to parameters
+type Profiles map[string]Profile
+
+// NewProfiles returns the default profiles with configured overrides
+// applied. Overrides may also define new profiles.
+func NewProfiles(overrides map[string]config.LLMProfile) Profiles {
+	profiles := DefaultProfiles()
+	for name, override := range overrides {
+		profile := profiles[name]
+		if override.Temperature != nil {
+			profile.Temperature = *override.Temperature
+			profile.OmitTemperature = false
+		}
+		if override.OmitTemperature {
+			profile.OmitTemperature = true
+		}
+		if override.MaxTokens > 0 {
+			profile.MaxTokens = override.MaxTokens
+		}
+		profiles[name] = profile
+	}
+	return profiles
+}
+
+// Apply returns the request with parameters it leaves unset filled from its
+// profile. Requests without a known profile are returned unchanged.
+func (p Profiles) Apply(request *CompletionRequest) *CompletionRequest {
+	profile, ok := p[request.Profile]
+	if request.Profile == "" || !ok {
+		return request
+	}
+
+	applied := *request
+	if applied.MaxTokens == 0 {
+		applied.MaxTokens = profile.MaxTokens
+	}
+	if profile.OmitTemperature {
+		applied.Temperature = 0
+		applied.TemperatureSet = false
+	} else if applied.Temperature == 0 && !applied.TemperatureSet {
+		applied.Temperature = profile.Temperature
+		applied.TemperatureSet = true
+	}
+	return &applied
+}
+
+// profiledProvider applies parameter profiles before calling a provider
+type profiledProvider struct {
+	Provider
+	profiles Profiles
+}
+
+// WithProfiles wraps a provider so requests naming a profile get its
+// parameters
+func WithProfiles(provider Provider, profiles Profiles) Provider {
+	return &profiledProvider{Provider: provider, profiles: profiles}
+}
+
+func (p *profiledProvider) Complete(ctx context.Context, request *CompletionRequest) (*CompletionResponse, error) {
+	return p.Provider.Complete(ctx, p.profiles.Apply(request))
+}
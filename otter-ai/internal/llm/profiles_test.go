@@ -0,0 +1,77 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"otter-ai/internal/config"
+)
+
+func TestProfiles_Apply(t *testing.T) {
+	profiles := DefaultProfiles()
+
+	req := profiles.Apply(&CompletionRequest{Prompt: "hi", Profile: ProfileNegotiation})
+	if req.Temperature != 0.3 || !req.TemperatureSet || req.MaxTokens != 400 {
+		t.Errorf("negotiation request = %+v", req)
+	}
+
+	req = profiles.Apply(&CompletionRequest{Profile: ProfileMusing, MaxTokens: 50, Temperature: 0.5})
+	if req.Temperature != 0.5 || req.MaxTokens != 50 {
+		t.Errorf("explicit parameters should win, got %+v", req)
+	}
+
+	original := &CompletionRequest{Profile: "unknown"}
+	if profiles.Apply(original) != original {
+		t.Error("unknown profile should leave the request unchanged")
+	}
+}
+
+func TestNewProfiles_Overrides(t *testing.T) {
+	temperature := float32(0.6)
+	profiles := NewProfiles(map[string]config.LLMProfile{
+		ProfileMusing: {Temperature: &temperature},
+		ProfileChat:   {OmitTemperature: true, MaxTokens: 1000},
+		"poetry":      {Temperature: &temperature, MaxTokens: 80},
+	})
+
+	if musing := profiles[ProfileMusing]; musing.Temperature != 0.6 || musing.MaxTokens != 220 {
+		t.Errorf("musing = %+v", musing)
+	}
+	chat := profiles.Apply(&CompletionRequest{Profile: ProfileChat})
+	if chat.TemperatureSet || chat.MaxTokens != 1000 {
+		t.Errorf("chat request = %+v", chat)
+	}
+	if poetry := profiles["poetry"]; poetry.Temperature != 0.6 || poetry.MaxTokens != 80 {
+		t.Errorf("custom profile = %+v", poetry)
+	}
+}
+
+func TestWithProfiles_SendsZeroTemperature(t *testing.T) {
+	var sent map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&sent)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"content": "ok"}, "finish_reason": "stop"},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	p, err := NewProvider(config.LLMConfig{Provider: "openai", Endpoint: srv.URL, Model: "gpt-4", APIKey: "sk-test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Complete(context.Background(), &CompletionRequest{Prompt: "hi", Profile: ProfileClassification}); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if temperature, ok := sent["temperature"]; !ok || temperature != 0.0 {
+		t.Errorf("classification should send temperature 0, got %v", sent["temperature"])
+	}
+	if sent["max_completion_tokens"] != 100.0 {
+		t.Errorf("max_completion_tokens = %v, want 100", sent["max_completion_tokens"])
+	}
+}
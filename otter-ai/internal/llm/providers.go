@@ -6,7 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"time"
 
@@ -16,21 +16,29 @@ import (
 // Constants for LLM provider configuration
 const (
 	LLMClientTimeout = 120 * time.Second // Timeout for LLM API requests
+	// DefaultOpenAIEmbeddingModel is used when no embedding model is configured
+	DefaultOpenAIEmbeddingModel = "text-embedding-3-small"
 )
 
 // OllamaProvider implements the Ollama LLM provider
 type OllamaProvider struct {
-	endpoint string
-	model    string
-	client   *http.Client
+	endpoint       string
+	model          string
+	embeddingModel string
+	client         *http.Client
 }
 
 // NewOllamaProvider creates a new Ollama provider
 func NewOllamaProvider(cfg config.LLMConfig) (*OllamaProvider, error) {
+	embModel := cfg.EmbeddingModel
+	if embModel == "" {
+		embModel = cfg.Model
+	}
 	return &OllamaProvider{
-		endpoint: cfg.Endpoint,
-		model:    cfg.Model,
-		client:   &http.Client{Timeout: LLMClientTimeout},
+		endpoint:       cfg.Endpoint,
+		model:          cfg.Model,
+		embeddingModel: embModel,
+		client:         &http.Client{Timeout: LLMClientTimeout},
 	}, nil
 }
 
@@ -58,7 +66,7 @@ func (p *OllamaProvider) Complete(ctx context.Context, request *CompletionReques
 		}
 	}
 
-	if request.Temperature > 0 {
+	if request.sendTemperature() {
 		if options, ok := reqBody["options"].(map[string]interface{}); ok {
 			options["temperature"] = request.Temperature
 		} else {
@@ -91,12 +99,14 @@ func (p *OllamaProvider) Complete(ctx context.Context, request *CompletionReques
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error: %s", string(body))
+		return nil, newStatusError("Ollama", resp, body)
 	}
 
 	var result struct {
-		Response string `json:"response"`
-		Done     bool   `json:"done"`
+		Response        string `json:"response"`
+		Done            bool   `json:"done"`
+		PromptEvalCount int    `json:"prompt_eval_count"`
+		EvalCount       int    `json:"eval_count"`
 	}
 
 	if err := json.Unmarshal(body, &result); err != nil {
@@ -105,6 +115,7 @@ func (p *OllamaProvider) Complete(ctx context.Context, request *CompletionReques
 
 	return &CompletionResponse{
 		Text:         result.Response,
+		TokensUsed:   result.PromptEvalCount + result.EvalCount,
 		FinishReason: "stop",
 	}, nil
 }
@@ -134,7 +145,7 @@ func (p *OllamaProvider) completeWithTools(ctx context.Context, request *Complet
 	if request.MaxTokens > 0 {
 		options["num_predict"] = request.MaxTokens
 	}
-	if request.Temperature > 0 {
+	if request.sendTemperature() {
 		options["temperature"] = request.Temperature
 	}
 	if len(options) > 0 {
@@ -164,7 +175,7 @@ func (p *OllamaProvider) completeWithTools(ctx context.Context, request *Complet
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Ollama API error: %s", string(body))
+		return nil, newStatusError("Ollama", resp, body)
 	}
 
 	var result struct {
@@ -172,7 +183,9 @@ func (p *OllamaProvider) completeWithTools(ctx context.Context, request *Complet
 			Content   string               `json:"content"`
 			ToolCalls []openAIToolCallJSON `json:"tool_calls"`
 		} `json:"message"`
-		Done bool `json:"done"`
+		Done            bool `json:"done"`
+		PromptEvalCount int  `json:"prompt_eval_count"`
+		EvalCount       int  `json:"eval_count"`
 	}
 
 	if err := json.Unmarshal(body, &result); err != nil {
@@ -181,6 +194,7 @@ func (p *OllamaProvider) completeWithTools(ctx context.Context, request *Complet
 
 	return &CompletionResponse{
 		Text:         result.Message.Content,
+		TokensUsed:   result.PromptEvalCount + result.EvalCount,
 		FinishReason: "stop",
 		ToolCalls:    parseOpenAIToolCalls(result.Message.ToolCalls),
 	}, nil
@@ -189,7 +203,7 @@ func (p *OllamaProvider) completeWithTools(ctx context.Context, request *Complet
 // Embed generates embeddings
 func (p *OllamaProvider) Embed(ctx context.Context, text string) ([]float32, error) {
 	reqBody := map[string]interface{}{
-		"model":  p.model,
+		"model":  p.embeddingModel,
 		"prompt": text,
 	}
 
@@ -216,7 +230,7 @@ func (p *OllamaProvider) Embed(ctx context.Context, text string) ([]float32, err
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error: %s", string(body))
+		return nil, newStatusError("Ollama", resp, body)
 	}
 
 	var result struct {
@@ -267,7 +281,7 @@ func (p *OpenWebUIProvider) Complete(ctx context.Context, request *CompletionReq
 	resp, err := p.doComplete(ctx, request, true)
 	if err != nil && len(request.Tools) > 0 && resp == nil {
 		// Retry without tools — the model may not support function calling.
-		fmt.Println("OpenWebUI: retrying without tools")
+		slog.InfoContext(ctx, "OpenWebUI request failed with tools; retrying without them", "error", err)
 		return p.doComplete(ctx, request, false)
 	}
 	return resp, err
@@ -297,7 +311,7 @@ func (p *OpenWebUIProvider) doComplete(ctx context.Context, request *CompletionR
 		reqBody["max_tokens"] = request.MaxTokens
 	}
 
-	if request.Temperature > 0 {
+	if request.sendTemperature() {
 		reqBody["temperature"] = request.Temperature
 	}
 
@@ -337,7 +351,7 @@ func (p *OpenWebUIProvider) doComplete(ctx context.Context, request *CompletionR
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("OpenWebUI API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, newStatusError("OpenWebUI", resp, body)
 	}
 
 	var result struct {
@@ -358,7 +372,7 @@ func (p *OpenWebUIProvider) doComplete(ctx context.Context, request *CompletionR
 	}
 
 	if len(result.Choices) == 0 {
-		fmt.Printf("OpenWebUI returned 0 choices. Raw response: %s\n", string(body))
+		slog.WarnContext(ctx, "OpenWebUI returned no choices", "response", string(body))
 		return nil, fmt.Errorf("no response from OpenWebUI")
 	}
 
@@ -383,7 +397,7 @@ func (p *OpenWebUIProvider) Embed(ctx context.Context, text string) ([]float32,
 	}
 
 	url := p.endpoint + "/api/embeddings"
-	log.Printf("[DEBUG] Embed request: url=%s model=%s input_len=%d", url, p.embeddingModel, len(text))
+	slog.DebugContext(ctx, "embed request", "url", url, "model", p.embeddingModel, "input_len", len(text))
 
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
@@ -405,10 +419,10 @@ func (p *OpenWebUIProvider) Embed(ctx context.Context, text string) ([]float32,
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	log.Printf("[DEBUG] Embed response: status=%d body_len=%d body=%s", resp.StatusCode, len(body), string(body))
+	slog.DebugContext(ctx, "embed response", "status", resp.StatusCode, "body_len", len(body))
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("OpenWebUI API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, newStatusError("OpenWebUI", resp, body)
 	}
 
 	// OpenAI-compatible format: {"data": [{"embedding": [...], "index": 0}], ...}
@@ -437,10 +451,11 @@ func (p *OpenWebUIProvider) Name() string {
 
 // OpenAIProvider implements the OpenAI LLM provider
 type OpenAIProvider struct {
-	endpoint string
-	model    string
-	apiKey   string
-	client   *http.Client
+	endpoint       string
+	model          string
+	embeddingModel string
+	apiKey         string
+	client         *http.Client
 }
 
 // NewOpenAIProvider creates a new OpenAI provider
@@ -449,11 +464,16 @@ func NewOpenAIProvider(cfg config.LLMConfig) (*OpenAIProvider, error) {
 		return nil, fmt.Errorf("OpenAI API key is required")
 	}
 
+	embModel := cfg.EmbeddingModel
+	if embModel == "" {
+		embModel = DefaultOpenAIEmbeddingModel
+	}
 	return &OpenAIProvider{
-		endpoint: cfg.Endpoint,
-		model:    cfg.Model,
-		apiKey:   cfg.APIKey,
-		client:   &http.Client{Timeout: LLMClientTimeout},
+		endpoint:       cfg.Endpoint,
+		model:          cfg.Model,
+		embeddingModel: embModel,
+		apiKey:         cfg.APIKey,
+		client:         &http.Client{Timeout: LLMClientTimeout},
 	}, nil
 }
 
@@ -485,7 +505,7 @@ func (p *OpenAIProvider) Complete(ctx context.Context, request *CompletionReques
 
 	// Only set temperature if it's explicitly different from 1.0
 	// Some models (like o1) don't support custom temperature
-	if request.Temperature > 0 && request.Temperature != 1.0 {
+	if request.sendTemperature() && request.Temperature != 1.0 {
 		reqBody["temperature"] = request.Temperature
 	}
 
@@ -521,7 +541,7 @@ func (p *OpenAIProvider) Complete(ctx context.Context, request *CompletionReques
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, newStatusError("OpenAI", resp, body)
 	}
 
 	var result struct {
@@ -555,12 +575,9 @@ func (p *OpenAIProvider) Complete(ctx context.Context, request *CompletionReques
 
 // Embed generates embeddings using OpenAI's embeddings API
 func (p *OpenAIProvider) Embed(ctx context.Context, text string) ([]float32, error) {
-	// Use text-embedding-3-small as default embedding model
-	embeddingModel := "text-embedding-3-small"
-
 	reqBody := map[string]interface{}{
 		"input": text,
-		"model": embeddingModel,
+		"model": p.embeddingModel,
 	}
 
 	jsonData, err := json.Marshal(reqBody)
@@ -587,7 +604,7 @@ func (p *OpenAIProvider) Embed(ctx context.Context, text string) ([]float32, err
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("OpenAI API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, newStatusError("OpenAI", resp, body)
 	}
 
 	var result struct {
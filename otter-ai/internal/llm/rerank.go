@@ -0,0 +1,92 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// RerankTimeout bounds one re-ranking request
+const RerankTimeout = 30 * time.Second
+
+// Reranker scores how relevant each text is to a query, typically with a
+// cross-encoder reading query and text together. Higher scores are more
+// relevant.
+type Reranker interface {
+	Rerank(ctx context.Context, query string, texts []string) ([]float64, error)
+}
+
+// CrossEncoder re-ranks through a rerank endpoint compatible with Hugging
+// Face Text Embeddings Inference: POST {"query", "texts"} to /rerank,
+// answered with [{"index", "score"}]
+type CrossEncoder struct {
+	endpoint string
+	apiKey   string
+	client   *http.Client
+}
+
+// NewCrossEncoder creates a re-ranker for the rerank service at endpoint.
+// apiKey, when set, is sent as a bearer token.
+func NewCrossEncoder(endpoint, apiKey string) *CrossEncoder {
+	return &CrossEncoder{endpoint: endpoint, apiKey: apiKey, client: &http.Client{Timeout: RerankTimeout}}
+}
+
+// Rerank returns the score of each text, in order
+func (c *CrossEncoder) Rerank(ctx context.Context, query string, texts []string) ([]float64, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	jsonData, err := json.Marshal(map[string]interface{}{"query": query, "texts": texts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint+"/rerank", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError("Rerank", resp, body)
+	}
+
+	var result []struct {
+		Index int     `json:"index"`
+		Score float64 `json:"score"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if len(result) != len(texts) {
+		return nil, fmt.Errorf("rerank returned %d scores for %d texts", len(result), len(texts))
+	}
+
+	scores := make([]float64, len(texts))
+	seen := make([]bool, len(texts))
+	for _, r := range result {
+		if r.Index < 0 || r.Index >= len(texts) || seen[r.Index] {
+			return nil, fmt.Errorf("rerank returned an invalid index %d", r.Index)
+		}
+		scores[r.Index] = r.Score
+		seen[r.Index] = true
+	}
+	return scores, nil
+}
@@ -0,0 +1,60 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCrossEncoder_Rerank(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rerank" || r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("request %s with %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		var req struct {
+			Query string   `json:"query"`
+			Texts []string `json:"texts"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Query != "otters" || len(req.Texts) != 2 {
+			t.Errorf("request = %+v", req)
+		}
+		// Answered best first, as the service sorts by score
+		w.Write([]byte(`[{"index":1,"score":0.9},{"index":0,"score":0.2}]`))
+	}))
+	defer server.Close()
+
+	scores, err := NewCrossEncoder(server.URL, "key").Rerank(context.Background(), "otters", []string{"weather", "otters"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scores[0] != 0.2 || scores[1] != 0.9 {
+		t.Errorf("scores = %v, want them in text order", scores)
+	}
+}
+
+func TestCrossEncoder_Errors(t *testing.T) {
+	answer, status := `[{"index":0,"score":1}]`, http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(answer))
+	}))
+	defer server.Close()
+	encoder := NewCrossEncoder(server.URL, "")
+
+	if _, err := encoder.Rerank(context.Background(), "q", []string{"a", "b"}); err == nil {
+		t.Error("expected error for a missing score")
+	}
+	answer = `[{"index":0,"score":1},{"index":0,"score":1}]`
+	if _, err := encoder.Rerank(context.Background(), "q", []string{"a", "b"}); err == nil {
+		t.Error("expected error for a repeated index")
+	}
+	status = http.StatusServiceUnavailable
+	var statusErr *StatusError
+	if _, err := encoder.Rerank(context.Background(), "q", []string{"a", "b"}); !errors.As(err, &statusErr) {
+		t.Errorf("err = %v, want a StatusError", err)
+	}
+}
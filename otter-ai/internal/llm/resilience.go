@@ -0,0 +1,283 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"otter-ai/internal/config"
+	"otter-ai/internal/errs"
+	"otter-ai/internal/tracing"
+)
+
+var tracer = tracing.Tracer("llm")
+
+// LLMMaxRetryBackoff caps the wait between retries, including waits asked
+// for by a Retry-After header
+const LLMMaxRetryBackoff = 30 * time.Second
+
+// ErrCircuitOpen is returned without calling the provider while its circuit
+// breaker is open after repeated failures
+var ErrCircuitOpen = errs.New(errs.ErrLLMUnavailable, "llm circuit breaker open")
+
+// StatusError is an error response from an LLM API
+type StatusError struct {
+	API        string // Which API answered, e.g. "OpenAI"
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration // Wait asked for by a Retry-After header, if any
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s API error (status %d): %s", e.API, e.StatusCode, e.Body)
+}
+
+// newStatusError builds the error for a non-OK response
+func newStatusError(api string, resp *http.Response, body []byte) *StatusError {
+	err := &StatusError{API: api, StatusCode: resp.StatusCode, Body: string(body)}
+	if seconds, parseErr := strconv.Atoi(resp.Header.Get("Retry-After")); parseErr == nil && seconds > 0 {
+		err.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return err
+}
+
+// ResilienceConfig tunes the timeout, retry and circuit breaker wrapper.
+// Zero fields disable the corresponding behaviour.
+type ResilienceConfig struct {
+	Timeout          time.Duration // Per attempt
+	MaxRetries       int           // Retries after the first attempt for 429, 5xx, timeouts and connection failures
+	RetryBackoff     time.Duration // Wait before the first retry, doubling for each further one
+	BreakerThreshold int           // Consecutive failed requests that open the circuit
+	BreakerCooldown  time.Duration // How long the circuit stays open before a trial request
+}
+
+// resilienceConfig reads the wrapper's settings from the LLM configuration
+func resilienceConfig(cfg config.LLMConfig) ResilienceConfig {
+	return ResilienceConfig{
+		Timeout:          cfg.Timeout,
+		MaxRetries:       cfg.MaxRetries,
+		RetryBackoff:     cfg.RetryBackoff,
+		BreakerThreshold: cfg.BreakerThreshold,
+		BreakerCooldown:  cfg.BreakerCooldown,
+	}
+}
+
+// Circuit breaker states
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half_open" // Cooldown over; the next request decides
+)
+
+// ResilienceStats counts the outcomes of requests through the wrapper
+type ResilienceStats struct {
+	Requests  int64  `json:"requests"`
+	Successes int64  `json:"successes"`
+	Failures  int64  `json:"failures"` // Requests that failed after any retries
+	Retries   int64  `json:"retries"`
+	Timeouts  int64  `json:"timeouts"` // Attempts cut off by the per-attempt timeout
+	Rejected  int64  `json:"rejected"` // Requests failed fast by the open circuit
+	Circuit   string `json:"circuit"`
+
+	Queue *QueueStats `json:"queue,omitempty"` // Set when concurrency is limited
+}
+
+// ResilienceReporter is implemented by providers wrapped by WithResilience
+type ResilienceReporter interface {
+	ResilienceStats() ResilienceStats
+}
+
+// resilientProvider applies timeouts, retries and a circuit breaker to a
+// provider
+type resilientProvider struct {
+	Provider
+	config ResilienceConfig
+
+	mu        sync.Mutex
+	failures  int       // Consecutive failed requests
+	openUntil time.Time // Zero while the circuit is closed
+	probing   bool      // A half-open trial request is in flight
+
+	requests, successes, failed, retries, timeouts, rejected atomic.Int64
+}
+
+// WithResilience wraps a provider so requests time out, are retried with
+// exponential backoff on transient failures, and fail fast while the
+// upstream keeps failing
+func WithResilience(provider Provider, config ResilienceConfig) Provider {
+	return &resilientProvider{Provider: provider, config: config}
+}
+
+func (p *resilientProvider) Complete(ctx context.Context, request *CompletionRequest) (*CompletionResponse, error) {
+	var response *CompletionResponse
+	err := p.do(ctx, "llm.Complete", func(ctx context.Context) error {
+		var err error
+		response, err = p.Provider.Complete(ctx, request)
+		return err
+	})
+	return response, err
+}
+
+func (p *resilientProvider) Embed(ctx context.Context, text string) ([]float32, error) {
+	var embedding []float32
+	err := p.do(ctx, "llm.Embed", func(ctx context.Context) error {
+		var err error
+		embedding, err = p.Provider.Embed(ctx, text)
+		return err
+	})
+	return embedding, err
+}
+
+// ResilienceStats returns the outcome counters and circuit state
+func (p *resilientProvider) ResilienceStats() ResilienceStats {
+	return ResilienceStats{
+		Requests:  p.requests.Load(),
+		Successes: p.successes.Load(),
+		Failures:  p.failed.Load(),
+		Retries:   p.retries.Load(),
+		Timeouts:  p.timeouts.Load(),
+		Rejected:  p.rejected.Load(),
+		Circuit:   p.circuit(time.Now()),
+	}
+}
+
+// do runs one request through the breaker, retrying transient failures
+func (p *resilientProvider) do(ctx context.Context, operation string, call func(context.Context) error) (err error) {
+	ctx, span := tracer.Start(ctx, operation, trace.WithAttributes(attribute.String("llm.provider", p.Name())))
+	defer func() { tracing.End(span, err) }()
+
+	p.requests.Add(1)
+	if !p.allow(time.Now()) {
+		p.rejected.Add(1)
+		return fmt.Errorf("%w: %s", ErrCircuitOpen, p.Name())
+	}
+
+	for attempt := 0; ; attempt++ {
+		span.SetAttributes(attribute.Int("llm.attempts", attempt+1))
+		err = p.attempt(ctx, call)
+		if err == nil || attempt >= p.config.MaxRetries || ctx.Err() != nil || !transient(err) {
+			break
+		}
+
+		p.retries.Add(1)
+		select {
+		case <-time.After(p.backoff(attempt, err)):
+		case <-ctx.Done():
+		}
+	}
+
+	p.record(err, time.Now())
+	if err != nil {
+		p.failed.Add(1)
+		return err
+	}
+	p.successes.Add(1)
+	return nil
+}
+
+// attempt makes one call within the per-attempt timeout
+func (p *resilientProvider) attempt(ctx context.Context, call func(context.Context) error) error {
+	if p.config.Timeout <= 0 {
+		return call(ctx)
+	}
+	attemptCtx, cancel := context.WithTimeout(ctx, p.config.Timeout)
+	defer cancel()
+
+	err := call(attemptCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+		p.timeouts.Add(1)
+		return fmt.Errorf("%s request timed out after %s: %w", p.Name(), p.config.Timeout, context.DeadlineExceeded)
+	}
+	return err
+}
+
+// backoff returns the wait before retry number attempt+1
+func (p *resilientProvider) backoff(attempt int, err error) time.Duration {
+	wait := p.config.RetryBackoff << attempt
+	var status *StatusError
+	if errors.As(err, &status) && status.RetryAfter > wait {
+		wait = status.RetryAfter
+	}
+	if wait > LLMMaxRetryBackoff || wait < 0 {
+		wait = LLMMaxRetryBackoff
+	}
+	return wait
+}
+
+// transient reports whether a failure is worth retrying: rate limiting,
+// server errors, timeouts and connection failures
+func transient(err error) bool {
+	var status *StatusError
+	if errors.As(err, &status) {
+		return status.StatusCode == http.StatusTooManyRequests || status.StatusCode >= 500
+	}
+	var urlErr *url.Error
+	return errors.Is(err, context.DeadlineExceeded) || errors.As(err, &urlErr)
+}
+
+// allow reports whether a request may go to the provider, letting a single
+// trial request through once the cooldown is over
+func (p *resilientProvider) allow(now time.Time) bool {
+	if p.config.BreakerThreshold <= 0 {
+		return true
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.openUntil.IsZero() {
+		return true
+	}
+	if now.Before(p.openUntil) || p.probing {
+		return false
+	}
+	p.probing = true
+	return true
+}
+
+// record updates the breaker with a request's outcome. Only transient
+// failures count towards opening it; a request the API refuses as invalid
+// says nothing about the upstream's health.
+func (p *resilientProvider) record(err error, now time.Time) {
+	if p.config.BreakerThreshold <= 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	wasProbing := p.probing
+	p.probing = false
+	if err == nil || !transient(err) {
+		p.failures = 0
+		p.openUntil = time.Time{}
+		return
+	}
+
+	p.failures++
+	if wasProbing || p.failures >= p.config.BreakerThreshold {
+		p.openUntil = now.Add(p.config.BreakerCooldown)
+	}
+}
+
+// circuit returns the breaker state
+func (p *resilientProvider) circuit(now time.Time) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch {
+	case p.openUntil.IsZero():
+		return CircuitClosed
+	case now.Before(p.openUntil):
+		return CircuitOpen
+	default:
+		return CircuitHalfOpen
+	}
+}
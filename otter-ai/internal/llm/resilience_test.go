@@ -0,0 +1,173 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"otter-ai/internal/config"
+)
+
+// flakyProvider fails with the queued errors before succeeding
+type flakyProvider struct {
+	errs  []error
+	calls atomic.Int32
+	wait  time.Duration // How long each call takes unless cancelled
+}
+
+func (p *flakyProvider) Complete(ctx context.Context, request *CompletionRequest) (*CompletionResponse, error) {
+	call := int(p.calls.Add(1)) - 1
+	if p.wait > 0 {
+		select {
+		case <-time.After(p.wait):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if call < len(p.errs) {
+		return nil, p.errs[call]
+	}
+	return &CompletionResponse{Text: "ok"}, nil
+}
+
+func (p *flakyProvider) Embed(ctx context.Context, text string) ([]float32, error) {
+	if _, err := p.Complete(ctx, nil); err != nil {
+		return nil, err
+	}
+	return []float32{1}, nil
+}
+
+func (p *flakyProvider) Name() string { return "flaky" }
+
+func unavailable() error {
+	return &StatusError{API: "Test", StatusCode: http.StatusServiceUnavailable, Body: "down"}
+}
+
+func stats(t *testing.T, p Provider) ResilienceStats {
+	t.Helper()
+	reporter, ok := p.(ResilienceReporter)
+	if !ok {
+		t.Fatal("provider does not report resilience stats")
+	}
+	return reporter.ResilienceStats()
+}
+
+func TestWithResilience_RetriesServerErrors(t *testing.T) {
+	inner := &flakyProvider{errs: []error{unavailable(), unavailable()}}
+	p := WithResilience(inner, ResilienceConfig{MaxRetries: 2, RetryBackoff: time.Millisecond})
+
+	resp, err := p.Complete(context.Background(), &CompletionRequest{})
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if resp.Text != "ok" || inner.calls.Load() != 3 {
+		t.Errorf("got %q after %d calls, want ok after 3", resp.Text, inner.calls.Load())
+	}
+	if s := stats(t, p); s.Requests != 1 || s.Successes != 1 || s.Retries != 2 || s.Failures != 0 {
+		t.Errorf("stats = %+v", s)
+	}
+}
+
+func TestWithResilience_DoesNotRetryClientErrors(t *testing.T) {
+	inner := &flakyProvider{errs: []error{&StatusError{API: "Test", StatusCode: http.StatusBadRequest}}}
+	p := WithResilience(inner, ResilienceConfig{MaxRetries: 3, RetryBackoff: time.Millisecond, BreakerThreshold: 1})
+
+	_, err := p.Complete(context.Background(), &CompletionRequest{})
+	var status *StatusError
+	if !errors.As(err, &status) || status.StatusCode != http.StatusBadRequest {
+		t.Fatalf("err = %v, want the 400", err)
+	}
+	if inner.calls.Load() != 1 {
+		t.Errorf("calls = %d, want 1", inner.calls.Load())
+	}
+	// A bad request says nothing about the upstream's health
+	if s := stats(t, p); s.Failures != 1 || s.Circuit != CircuitClosed {
+		t.Errorf("stats = %+v", s)
+	}
+}
+
+func TestWithResilience_Timeout(t *testing.T) {
+	inner := &flakyProvider{wait: time.Second}
+	p := WithResilience(inner, ResilienceConfig{Timeout: 10 * time.Millisecond, MaxRetries: 1, RetryBackoff: time.Millisecond})
+
+	start := time.Now()
+	_, err := p.Embed(context.Background(), "hi")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("took %s despite the timeout", elapsed)
+	}
+	if s := stats(t, p); s.Timeouts != 2 || s.Retries != 1 || s.Failures != 1 {
+		t.Errorf("stats = %+v", s)
+	}
+}
+
+func TestWithResilience_CircuitBreaker(t *testing.T) {
+	inner := &flakyProvider{errs: []error{unavailable(), unavailable(), unavailable()}}
+	p := WithResilience(inner, ResilienceConfig{BreakerThreshold: 2, BreakerCooldown: 50 * time.Millisecond})
+	ctx := context.Background()
+
+	p.Complete(ctx, &CompletionRequest{})
+	p.Complete(ctx, &CompletionRequest{})
+	if s := stats(t, p); s.Circuit != CircuitOpen {
+		t.Fatalf("circuit = %s after 2 failures, want open", s.Circuit)
+	}
+
+	// While open, requests fail fast without reaching the provider
+	if _, err := p.Complete(ctx, &CompletionRequest{}); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("err = %v, want ErrCircuitOpen", err)
+	}
+	if inner.calls.Load() != 2 {
+		t.Errorf("calls = %d, want 2", inner.calls.Load())
+	}
+
+	// A failed trial after the cooldown reopens the circuit at once
+	time.Sleep(60 * time.Millisecond)
+	if s := stats(t, p); s.Circuit != CircuitHalfOpen {
+		t.Errorf("circuit = %s after the cooldown, want half_open", s.Circuit)
+	}
+	p.Complete(ctx, &CompletionRequest{})
+	if s := stats(t, p); s.Circuit != CircuitOpen {
+		t.Fatalf("circuit = %s after a failed trial, want open", s.Circuit)
+	}
+
+	// A successful trial closes it
+	time.Sleep(60 * time.Millisecond)
+	if _, err := p.Complete(ctx, &CompletionRequest{}); err != nil {
+		t.Fatalf("trial request: %v", err)
+	}
+	if s := stats(t, p); s.Circuit != CircuitClosed || s.Rejected != 1 || s.Successes != 1 || s.Failures != 3 {
+		t.Errorf("stats = %+v", s)
+	}
+}
+
+func TestWithResilience_HonoursRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "slow down", http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"embedding": [0.5, 0.5]}`))
+	}))
+	defer srv.Close()
+
+	p, err := NewProvider(config.LLMConfig{Provider: "ollama", Endpoint: srv.URL, Model: "m",
+		MaxRetries: 1, RetryBackoff: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if _, err := p.Embed(context.Background(), "hi"); err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, want the 1s Retry-After", elapsed)
+	}
+}
@@ -0,0 +1,96 @@
+package llm
+
+import "unicode"
+
+// CountTokens estimates how many tokens text takes up in a model's context
+// window. It splits text the way BPE tokenizers pre-tokenize it, into
+// words, numbers, punctuation and line breaks, and counts long words and
+// numbers as several tokens. Ideographic scripts count a token per
+// character. Estimates run slightly high for English, so budgets built on
+// them keep some slack whatever tokenizer the model uses.
+func CountTokens(text string) int {
+	tokens := 0
+	letters, digits := 0, 0
+	flush := func() {
+		if letters > 0 {
+			tokens += 1 + (letters-1)/5
+		}
+		if digits > 0 {
+			tokens += (digits + 2) / 3
+		}
+		letters, digits = 0, 0
+	}
+
+	newline := false
+	for _, r := range text {
+		switch {
+		case isIdeograph(r):
+			flush()
+			tokens++
+		case unicode.IsLetter(r) || unicode.IsMark(r):
+			if digits > 0 {
+				flush()
+			}
+			letters++
+		case unicode.IsDigit(r):
+			if letters > 0 {
+				flush()
+			}
+			digits++
+		case r == '\n':
+			flush()
+			if !newline {
+				tokens++ // A run of line breaks is one token
+			}
+		case unicode.IsSpace(r):
+			flush() // Spaces merge into the next word
+		default:
+			flush()
+			tokens++
+		}
+		newline = r == '\n'
+	}
+	flush()
+	return tokens
+}
+
+// isIdeograph reports whether r is from a script tokenizers split per
+// character
+func isIdeograph(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul, unicode.Thai)
+}
+
+// TruncateTokens shortens text to at most maxTokens tokens by CountTokens,
+// cutting at a line or word boundary where there is one and marking the cut
+// with an ellipsis. Text that fits is returned unchanged.
+func TruncateTokens(text string, maxTokens int) string {
+	if CountTokens(text) <= maxTokens {
+		return text
+	}
+	if maxTokens <= 1 {
+		return ""
+	}
+	const ellipsis = "…"
+
+	// The longest prefix that fits with the ellipsis
+	runes := []rune(text)
+	lo, hi := 0, len(runes)
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if CountTokens(string(runes[:mid]))+1 <= maxTokens {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	cut := string(runes[:lo])
+
+	// Back up to a boundary unless that loses most of what fits
+	for i := len(cut) - 1; i > len(cut)/2; i-- {
+		if cut[i] == '\n' || cut[i] == ' ' {
+			cut = cut[:i]
+			break
+		}
+	}
+	return cut + ellipsis
+}
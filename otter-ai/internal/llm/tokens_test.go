@@ -0,0 +1,57 @@
+package llm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCountTokens(t *testing.T) {
+	cases := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"hello", 1},
+		{"hello world", 2},
+		{"Hello, world!", 4},
+		{"internationalization", 4},
+		{"2026", 2},
+		{"line one\n\n\nline two", 5},
+		{"日本語", 3},
+	}
+	for _, c := range cases {
+		if got := CountTokens(c.text); got != c.want {
+			t.Errorf("CountTokens(%q) = %d, want %d", c.text, got, c.want)
+		}
+	}
+}
+
+func TestCountTokens_EnglishErrsHigh(t *testing.T) {
+	// 33 tokens with common BPE vocabularies
+	text := "Sea otters hold hands while they sleep so that they don't drift apart. " +
+		"They also keep a favourite rock in a pouch of skin under their forelegs."
+	if got := CountTokens(text); got < 33 || got > 45 {
+		t.Errorf("CountTokens = %d, want a little over 33", got)
+	}
+}
+
+func TestTruncateTokens(t *testing.T) {
+	text := strings.Repeat("otters float together ", 50)
+	if got := TruncateTokens("short text", 10); got != "short text" {
+		t.Errorf("text that fits changed: %q", got)
+	}
+
+	got := TruncateTokens(text, 20)
+	if n := CountTokens(got); n > 20 || n < 15 {
+		t.Errorf("truncated to %d tokens, want at most 20 and close to it", n)
+	}
+	if !strings.HasSuffix(got, "…") || !strings.HasPrefix(text, strings.TrimSuffix(got, "…")) {
+		t.Errorf("want a prefix marked with an ellipsis, got %q", got)
+	}
+	if strings.HasSuffix(strings.TrimSuffix(got, "…"), "otte") {
+		t.Errorf("cut inside a word: %q", got)
+	}
+	if got := TruncateTokens(text, 0); got != "" {
+		t.Errorf("TruncateTokens(0) = %q, want empty", got)
+	}
+}
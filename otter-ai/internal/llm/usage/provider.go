@@ -0,0 +1,96 @@
+package usage
+
+import (
+	"context"
+	"log/slog"
+
+	"otter-ai/internal/llm"
+)
+
+// provider counts the requests of a wrapped completion provider
+type provider struct {
+	llm.Provider
+	tracker *Tracker
+}
+
+// embedder counts the requests of a wrapped embedding provider
+type embedder struct {
+	llm.EmbeddingProvider
+	tracker *Tracker
+}
+
+// Wrap returns a provider recording the tokens each completion uses and
+// refusing deferrable completions once the budget is spent. Resilience
+// stats of the wrapped provider stay visible.
+func Wrap(inner llm.Provider, tracker *Tracker) llm.Provider {
+	p := &provider{Provider: inner, tracker: tracker}
+	if reporter, ok := inner.(llm.ResilienceReporter); ok {
+		return struct {
+			*provider
+			llm.ResilienceReporter
+		}{p, reporter}
+	}
+	return p
+}
+
+// WrapEmbedder returns an embedding provider counting its requests.
+// Embeddings are never refused, as storing and searching memories needs
+// them.
+func WrapEmbedder(inner llm.EmbeddingProvider, tracker *Tracker) llm.EmbeddingProvider {
+	e := &embedder{EmbeddingProvider: inner, tracker: tracker}
+	if reporter, ok := inner.(llm.ResilienceReporter); ok {
+		return struct {
+			*embedder
+			llm.ResilienceReporter
+		}{e, reporter}
+	}
+	return e
+}
+
+func (p *provider) Complete(ctx context.Context, request *llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	purpose := request.Profile
+	if purpose == "" {
+		purpose = PurposeOther
+	}
+	if err := p.tracker.Allow(purpose); err != nil {
+		return nil, err
+	}
+
+	response, err := p.Provider.Complete(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	p.tracker.record(ctx, p.Name(), purpose, response.TokensUsed)
+	return response, nil
+}
+
+func (p *provider) Embed(ctx context.Context, text string) ([]float32, error) {
+	embedding, err := p.Provider.Embed(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	p.tracker.record(ctx, p.Name(), PurposeEmbedding, 0)
+	return embedding, nil
+}
+
+func (e *embedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	embedding, err := e.EmbeddingProvider.Embed(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	e.tracker.record(ctx, e.Name(), PurposeEmbedding, 0)
+	return embedding, nil
+}
+
+// record adds a request to the totals. Failing to record never fails the
+// request itself.
+func (t *Tracker) record(ctx context.Context, provider, purpose string, tokens int) {
+	if err := t.Record(context.WithoutCancel(ctx), provider, purpose, tokens); err != nil {
+		slog.WarnContext(ctx, "failed to record token usage", "provider", provider, "purpose", purpose, "error", err)
+	}
+}
+
+// Unwrap returns the wrapped provider, so llm.Ping can reach it
+func (p *provider) Unwrap() llm.Provider {
+	return p.Provider
+}
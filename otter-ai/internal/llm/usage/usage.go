@@ -0,0 +1,199 @@
+// Package usage accounts for the tokens LLM requests use, per UTC day,
+// provider and purpose, and keeps to a daily token budget by refusing
+// deferrable requests once it is spent. A request's purpose is the
+// parameter profile it names, so chat, classification, negotiation and
+// background work such as musing are counted apart.
+package usage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"otter-ai/internal/config"
+	"otter-ai/internal/llm"
+)
+
+// Purposes other than the parameter profiles
+const (
+	PurposeEmbedding = "embedding"
+	PurposeOther     = "other" // Completions naming no profile
+)
+
+// DayFormat is the layout of the UTC day totals are kept under
+const DayFormat = "2006-01-02"
+
+// MaxReportDays bounds how far back a report goes
+const MaxReportDays = 366
+
+// ErrBudgetExceeded is returned instead of calling the provider for a
+// deferrable request once the day's token budget is spent
+var ErrBudgetExceeded = errors.New("daily token budget exceeded")
+
+// DefaultDeferrable returns the purposes refused once the budget is spent:
+// background work that can wait for the next day. Chat, classification,
+// negotiation and embeddings keep working.
+func DefaultDeferrable() []string {
+	return []string{llm.ProfileMusing, llm.ProfileSummary, llm.ProfileIntrospection}
+}
+
+// Total is the usage of one provider for one purpose on one day
+type Total struct {
+	Day      string `json:"day"`
+	Provider string `json:"provider"`
+	Purpose  string `json:"purpose"`
+	Requests int64  `json:"requests"`
+	Tokens   int64  `json:"tokens"`
+}
+
+// Report summarizes usage and the state of the budget
+type Report struct {
+	Day            string   `json:"day"`          // Current UTC day
+	DailyBudget    int64    `json:"daily_budget"` // Zero means no budget
+	TokensToday    int64    `json:"tokens_today"`
+	BudgetExceeded bool     `json:"budget_exceeded"`
+	Deferrable     []string `json:"deferrable"`     // Purposes refused while the budget is exceeded
+	DeferredToday  int64    `json:"deferred_today"` // Requests refused today since startup
+	Totals         []Total  `json:"totals"`         // Newest day first
+}
+
+// Tracker records usage in the llm_usage table and enforces the budget
+type Tracker struct {
+	db         *sql.DB // Nil keeps only today's count, in memory
+	budget     int64
+	deferrable []string
+	now        func() time.Time
+
+	mu       sync.Mutex
+	day      string
+	tokens   int64 // Used on day
+	deferred int64 // Refused on day
+}
+
+// NewTracker returns a tracker persisting to db, resuming today's count
+// from it
+func NewTracker(ctx context.Context, db *sql.DB, cfg config.UsageConfig) (*Tracker, error) {
+	deferrable := cfg.Deferrable
+	if len(deferrable) == 0 {
+		deferrable = DefaultDeferrable()
+	}
+	t := &Tracker{db: db, budget: int64(cfg.DailyTokenBudget), deferrable: deferrable, now: time.Now}
+
+	t.day = t.today()
+	if db != nil {
+		err := db.QueryRowContext(ctx, "SELECT COALESCE(SUM(tokens), 0) FROM llm_usage WHERE day = ?", t.day).Scan(&t.tokens)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load today's token usage: %w", err)
+		}
+	}
+	return t, nil
+}
+
+func (t *Tracker) today() string {
+	return t.now().UTC().Format(DayFormat)
+}
+
+// rollover starts a new day's count once the UTC day changes. Callers hold mu.
+func (t *Tracker) rollover() {
+	if day := t.today(); day != t.day {
+		t.day, t.tokens, t.deferred = day, 0, 0
+	}
+}
+
+// Allow reports whether a request for a purpose may go ahead, returning
+// ErrBudgetExceeded for a deferrable one once the budget is spent
+func (t *Tracker) Allow(purpose string) error {
+	if t.budget <= 0 || !t.isDeferrable(purpose) {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.rollover()
+	if t.tokens < t.budget {
+		return nil
+	}
+	t.deferred++
+	return fmt.Errorf("%w: %d of %d tokens used, %s deferred until tomorrow (UTC)", ErrBudgetExceeded, t.tokens, t.budget, purpose)
+}
+
+func (t *Tracker) isDeferrable(purpose string) bool {
+	for _, deferrable := range t.deferrable {
+		if deferrable == purpose {
+			return true
+		}
+	}
+	return false
+}
+
+// Record adds a completed request to the day's totals
+func (t *Tracker) Record(ctx context.Context, provider, purpose string, tokens int) error {
+	t.mu.Lock()
+	t.rollover()
+	t.tokens += int64(tokens)
+	day := t.day
+	t.mu.Unlock()
+
+	if t.db == nil {
+		return nil
+	}
+	_, err := t.db.ExecContext(ctx, `
+		INSERT INTO llm_usage (day, provider, purpose, requests, tokens) VALUES (?, ?, ?, 1, ?)
+		ON CONFLICT (day, provider, purpose) DO UPDATE SET
+			requests = requests + 1,
+			tokens = tokens + excluded.tokens
+	`, day, provider, purpose, tokens)
+	if err != nil {
+		return fmt.Errorf("failed to record token usage: %w", err)
+	}
+	return nil
+}
+
+// Report returns the budget state and the totals of the last days days,
+// today included
+func (t *Tracker) Report(ctx context.Context, days int) (*Report, error) {
+	if days < 1 {
+		days = 1
+	}
+	if days > MaxReportDays {
+		days = MaxReportDays
+	}
+
+	t.mu.Lock()
+	t.rollover()
+	report := &Report{
+		Day:            t.day,
+		DailyBudget:    t.budget,
+		TokensToday:    t.tokens,
+		BudgetExceeded: t.budget > 0 && t.tokens >= t.budget,
+		Deferrable:     t.deferrable,
+		DeferredToday:  t.deferred,
+		Totals:         []Total{},
+	}
+	t.mu.Unlock()
+
+	if t.db == nil {
+		return report, nil
+	}
+	since := t.now().UTC().AddDate(0, 0, -(days - 1)).Format(DayFormat)
+	rows, err := t.db.QueryContext(ctx, `
+		SELECT day, provider, purpose, requests, tokens FROM llm_usage
+		WHERE day >= ? ORDER BY day DESC, provider, purpose
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query token usage: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var total Total
+		if err := rows.Scan(&total.Day, &total.Provider, &total.Purpose, &total.Requests, &total.Tokens); err != nil {
+			return nil, fmt.Errorf("failed to scan token usage: %w", err)
+		}
+		report.Totals = append(report.Totals, total)
+	}
+	return report, rows.Err()
+}
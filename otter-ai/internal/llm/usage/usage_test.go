@@ -0,0 +1,239 @@
+package usage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"otter-ai/internal/config"
+	"otter-ai/internal/llm"
+	"otter-ai/internal/vectordb"
+)
+
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	vdb, err := vectordb.NewSQLiteVectorDB(filepath.Join(t.TempDir(), "usage.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteVectorDB() error = %v", err)
+	}
+	t.Cleanup(func() { vdb.Close() })
+	return vdb.GetDB()
+}
+
+type fakeProvider struct {
+	tokens int
+	err    error
+}
+
+func (p *fakeProvider) Complete(ctx context.Context, request *llm.CompletionRequest) (*llm.CompletionResponse, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &llm.CompletionResponse{Text: "ok", TokensUsed: p.tokens}, nil
+}
+
+func (p *fakeProvider) Embed(ctx context.Context, text string) ([]float32, error) {
+	return []float32{0.1}, nil
+}
+
+func (p *fakeProvider) Name() string { return "fake" }
+
+func TestTracker_RecordAndReport(t *testing.T) {
+	ctx := context.Background()
+	tracker, err := NewTracker(ctx, newTestDB(t), config.UsageConfig{})
+	if err != nil {
+		t.Fatalf("NewTracker() error = %v", err)
+	}
+
+	for _, r := range []struct {
+		purpose string
+		tokens  int
+	}{{llm.ProfileChat, 100}, {llm.ProfileChat, 50}, {llm.ProfileMusing, 30}} {
+		if err := tracker.Record(ctx, "fake", r.purpose, r.tokens); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	report, err := tracker.Report(ctx, 7)
+	if err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+	if report.TokensToday != 180 {
+		t.Errorf("TokensToday = %d, want 180", report.TokensToday)
+	}
+	if report.BudgetExceeded {
+		t.Error("BudgetExceeded without a budget")
+	}
+	want := []Total{
+		{Day: report.Day, Provider: "fake", Purpose: llm.ProfileChat, Requests: 2, Tokens: 150},
+		{Day: report.Day, Provider: "fake", Purpose: llm.ProfileMusing, Requests: 1, Tokens: 30},
+	}
+	if len(report.Totals) != len(want) {
+		t.Fatalf("Totals = %+v, want %+v", report.Totals, want)
+	}
+	for i := range want {
+		if report.Totals[i] != want[i] {
+			t.Errorf("Totals[%d] = %+v, want %+v", i, report.Totals[i], want[i])
+		}
+	}
+}
+
+func TestTracker_ReportWindow(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	tracker, err := NewTracker(ctx, db, config.UsageConfig{})
+	if err != nil {
+		t.Fatalf("NewTracker() error = %v", err)
+	}
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	for _, day := range []time.Time{now.AddDate(0, 0, -10), now.AddDate(0, 0, -1), now} {
+		tracker.now = func() time.Time { return day }
+		if err := tracker.Record(ctx, "fake", llm.ProfileChat, 10); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+	tracker.now = func() time.Time { return now }
+
+	report, err := tracker.Report(ctx, 2)
+	if err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+	if len(report.Totals) != 2 || report.Totals[0].Day != "2026-03-10" || report.Totals[1].Day != "2026-03-09" {
+		t.Errorf("Totals = %+v, want today and yesterday, newest first", report.Totals)
+	}
+	if report.TokensToday != 10 {
+		t.Errorf("TokensToday = %d, want 10 after the day rolled over", report.TokensToday)
+	}
+}
+
+func TestTracker_Budget(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	tracker, err := NewTracker(ctx, db, config.UsageConfig{DailyTokenBudget: 100})
+	if err != nil {
+		t.Fatalf("NewTracker() error = %v", err)
+	}
+
+	if err := tracker.Allow(llm.ProfileMusing); err != nil {
+		t.Fatalf("Allow() before spending the budget = %v", err)
+	}
+	if err := tracker.Record(ctx, "fake", llm.ProfileChat, 100); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	if err := tracker.Allow(llm.ProfileMusing); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Allow(musing) = %v, want ErrBudgetExceeded", err)
+	}
+	for _, purpose := range []string{llm.ProfileChat, llm.ProfileClassification, llm.ProfileNegotiation, PurposeEmbedding} {
+		if err := tracker.Allow(purpose); err != nil {
+			t.Errorf("Allow(%s) = %v, want essential work to continue", purpose, err)
+		}
+	}
+
+	report, err := tracker.Report(ctx, 1)
+	if err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+	if !report.BudgetExceeded || report.DeferredToday != 1 {
+		t.Errorf("report = %+v, want budget exceeded with one deferred request", report)
+	}
+
+	// A restarted otter resumes today's count
+	restarted, err := NewTracker(ctx, db, config.UsageConfig{DailyTokenBudget: 100})
+	if err != nil {
+		t.Fatalf("NewTracker() error = %v", err)
+	}
+	if err := restarted.Allow(llm.ProfileSummary); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Allow(summary) after restart = %v, want ErrBudgetExceeded", err)
+	}
+
+	// The budget is per UTC day
+	restarted.now = func() time.Time { return time.Now().Add(24 * time.Hour) }
+	if err := restarted.Allow(llm.ProfileSummary); err != nil {
+		t.Errorf("Allow(summary) the next day = %v", err)
+	}
+}
+
+func TestTracker_ConfiguredDeferrable(t *testing.T) {
+	tracker, err := NewTracker(context.Background(), nil, config.UsageConfig{DailyTokenBudget: 1, Deferrable: []string{llm.ProfileClassification}})
+	if err != nil {
+		t.Fatalf("NewTracker() error = %v", err)
+	}
+	tracker.Record(context.Background(), "fake", llm.ProfileChat, 1)
+
+	if err := tracker.Allow(llm.ProfileClassification); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Allow(classification) = %v, want ErrBudgetExceeded", err)
+	}
+	if err := tracker.Allow(llm.ProfileMusing); err != nil {
+		t.Errorf("Allow(musing) = %v, want nil when not configured as deferrable", err)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	ctx := context.Background()
+	tracker, err := NewTracker(ctx, newTestDB(t), config.UsageConfig{DailyTokenBudget: 40})
+	if err != nil {
+		t.Fatalf("NewTracker() error = %v", err)
+	}
+	inner := &fakeProvider{tokens: 25}
+	provider := Wrap(inner, tracker)
+
+	if _, err := provider.Complete(ctx, &llm.CompletionRequest{Profile: llm.ProfileMusing}); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if _, err := provider.Complete(ctx, &llm.CompletionRequest{}); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if _, err := provider.Embed(ctx, "text"); err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	inner.err = errors.New("upstream down")
+	if _, err := provider.Complete(ctx, &llm.CompletionRequest{Profile: llm.ProfileChat}); err == nil {
+		t.Fatal("Complete() error = nil, want the provider's error")
+	}
+
+	if _, err := provider.Complete(ctx, &llm.CompletionRequest{Profile: llm.ProfileMusing}); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Complete(musing) over budget = %v, want ErrBudgetExceeded", err)
+	}
+
+	report, err := tracker.Report(ctx, 1)
+	if err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+	got := map[string]Total{}
+	for _, total := range report.Totals {
+		got[total.Purpose] = total
+	}
+	if got[llm.ProfileMusing].Tokens != 25 || got[PurposeOther].Tokens != 25 {
+		t.Errorf("totals = %+v, want 25 tokens each for musing and other", report.Totals)
+	}
+	if got[PurposeEmbedding].Requests != 1 {
+		t.Errorf("embedding requests = %d, want 1", got[PurposeEmbedding].Requests)
+	}
+	if _, ok := got[llm.ProfileChat]; ok {
+		t.Error("failed request was recorded")
+	}
+}
+
+func TestWrap_KeepsResilienceStats(t *testing.T) {
+	tracker, err := NewTracker(context.Background(), nil, config.UsageConfig{})
+	if err != nil {
+		t.Fatalf("NewTracker() error = %v", err)
+	}
+
+	resilient := llm.WithResilience(&fakeProvider{}, llm.ResilienceConfig{})
+	if _, ok := Wrap(resilient, tracker).(llm.ResilienceReporter); !ok {
+		t.Error("Wrap() hid the resilience stats of the wrapped provider")
+	}
+	if _, ok := WrapEmbedder(resilient, tracker).(llm.ResilienceReporter); !ok {
+		t.Error("WrapEmbedder() hid the resilience stats of the wrapped provider")
+	}
+	if _, ok := Wrap(&fakeProvider{}, tracker).(llm.ResilienceReporter); ok {
+		t.Error("Wrap() reports resilience stats for a provider without them")
+	}
+}
@@ -0,0 +1,112 @@
+// Package logging builds the structured logger main injects into the
+// otter's subsystems, and carries request IDs through contexts so every
+// line logged while serving a request can be correlated with it.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"otter-ai/internal/config"
+)
+
+// RequestIDHeader carries a request's ID in and out of the API
+const RequestIDHeader = "X-Request-ID"
+
+// Log formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// New returns a logger writing to w at the configured level and format.
+// Lines logged with a context include its request ID and trace ID.
+func New(w io.Writer, cfg config.LoggingConfig) (*slog.Logger, error) {
+	level := new(slog.LevelVar)
+	if err := setLevel(level, cfg.Level); err != nil {
+		return nil, err
+	}
+
+	options := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch cfg.Format {
+	case FormatText, "":
+		handler = slog.NewTextHandler(w, options)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, options)
+	default:
+		return nil, fmt.Errorf("invalid log format %q", cfg.Format)
+	}
+	return slog.New(contextHandler{handler, level}), nil
+}
+
+// SetLevel changes the level of a logger returned by New, and of every
+// logger derived from it, while they run
+func SetLevel(logger *slog.Logger, level string) error {
+	h, ok := logger.Handler().(contextHandler)
+	if !ok {
+		return fmt.Errorf("logger was not built by logging.New")
+	}
+	return setLevel(h.level, level)
+}
+
+func setLevel(v *slog.LevelVar, level string) error {
+	parsed := slog.LevelInfo
+	if level != "" {
+		if err := parsed.UnmarshalText([]byte(level)); err != nil {
+			return fmt.Errorf("invalid log level %q: %w", level, err)
+		}
+	}
+	v.Set(parsed)
+	return nil
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context carrying a request ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, if any
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// NewRequestID returns a random request ID
+func NewRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// contextHandler adds the request and trace IDs of a record's context
+type contextHandler struct {
+	slog.Handler
+	level *slog.LevelVar
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		record.AddAttrs(slog.String("trace_id", span.TraceID().String()))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs), h.level}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name), h.level}
+}
@@ -0,0 +1,104 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"otter-ai/internal/config"
+)
+
+func TestNew_JSONWithRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, config.LoggingConfig{Level: "info", Format: FormatJSON})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+	ctx = WithRequestID(ctx, "req-1")
+
+	logger.With("component", "test").InfoContext(ctx, "hello", "n", 1)
+	logger.Debug("hidden below the level")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want 1: %s", len(lines), buf.String())
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("line is not JSON: %v", err)
+	}
+	for key, want := range map[string]interface{}{
+		"msg": "hello", "component": "test", "n": float64(1),
+		"request_id": "req-1", "trace_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+	} {
+		if entry[key] != want {
+			t.Errorf("%s = %v, want %v", key, entry[key], want)
+		}
+	}
+}
+
+func TestNew_TextWithoutRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, config.LoggingConfig{Level: "debug", Format: FormatText})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	logger.DebugContext(context.Background(), "detail")
+
+	out := buf.String()
+	if !strings.Contains(out, "level=DEBUG") || !strings.Contains(out, "msg=detail") {
+		t.Errorf("output = %q", out)
+	}
+	if strings.Contains(out, "request_id") || strings.Contains(out, "trace_id") {
+		t.Errorf("output = %q, want no IDs without them in the context", out)
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	if _, err := New(&bytes.Buffer{}, config.LoggingConfig{Level: "loud"}); err == nil {
+		t.Error("expected error for an unknown level")
+	}
+	if _, err := New(&bytes.Buffer{}, config.LoggingConfig{Format: "xml"}); err == nil {
+		t.Error("expected error for an unknown format")
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	if id := RequestID(context.Background()); id != "" {
+		t.Errorf("RequestID() = %q without one", id)
+	}
+	a, b := NewRequestID(), NewRequestID()
+	if len(a) != 16 || a == b {
+		t.Errorf("NewRequestID() = %q, %q, want distinct 16 character IDs", a, b)
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, config.LoggingConfig{Level: "info"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	derived := logger.With("component", "test")
+
+	derived.Debug("hidden")
+	if err := SetLevel(logger, "debug"); err != nil {
+		t.Fatal(err)
+	}
+	derived.Debug("shown")
+	if out := buf.String(); strings.Contains(out, "hidden") || !strings.Contains(out, "shown") {
+		t.Errorf("output = %q", out)
+	}
+
+	if err := SetLevel(logger, "loud"); err == nil {
+		t.Error("expected error for an unknown level")
+	}
+}
@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Reasons recorded in the archive_reason field of archived memories
+const (
+	ArchiveReasonConsolidated = "consolidated"
+	ArchiveReasonDecayed      = "decayed"
+)
+
+// Archive moves a memory to the archive, recording the summary that replaced
+// it. Archived memories no longer surface in search but can still be listed
+// with MemoryTypeArchived. Memories under legal hold are refused with ErrHeld.
+func (m *Memory) Archive(ctx context.Context, record *MemoryRecord, summaryID string) error {
+	return m.archive(ctx, record, map[string]interface{}{
+		"archive_reason":    ArchiveReasonConsolidated,
+		"consolidated_into": summaryID,
+	})
+}
+
+// archive moves a memory to the archive with extra metadata
+func (m *Memory) archive(ctx context.Context, record *MemoryRecord, fields map[string]interface{}) error {
+	if record.Held {
+		return fmt.Errorf("%w: %s", ErrHeld, record.ID)
+	}
+
+	archived := *record
+	archived.Type = MemoryTypeArchived
+	archived.Metadata = make(map[string]interface{}, len(record.Metadata)+len(fields)+2)
+	for k, v := range record.Metadata {
+		archived.Metadata[k] = v
+	}
+	for k, v := range fields {
+		archived.Metadata[k] = v
+	}
+	archived.Metadata["archived_from"] = string(record.Type)
+	archived.Metadata["archived_at"] = time.Now().Unix()
+
+	if err := m.write(ctx, &archived); err != nil {
+		return fmt.Errorf("failed to archive memory: %w", err)
+	}
+	return m.Delete(ctx, record.ID, record.Type)
+}
@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// DedupAction decides what happens to a memory that repeats a recent one
+type DedupAction string
+
+const (
+	// DedupSkip drops the new memory and leaves the existing one as it is
+	DedupSkip DedupAction = "skip"
+	// DedupMerge rewrites the existing memory with the new one's content,
+	// embedding and timestamp, keeping the higher importance
+	DedupMerge DedupAction = "merge"
+	// DedupBump drops the new memory and raises the existing one's importance
+	DedupBump DedupAction = "bump"
+)
+
+// dedupSources are the content sources checked for duplicates: what the
+// otter is told. Memories it writes itself, like consolidation summaries,
+// restate others on purpose.
+var dedupSources = []string{SourceInteraction, SourceExtraction, SourcePlugin}
+
+// DedupPolicy tunes near-duplicate detection in Store
+type DedupPolicy struct {
+	// Threshold is the cosine similarity at which a new memory repeats an
+	// existing one; zero disables detection
+	Threshold float64
+	Window    time.Duration // Only memories stored this recently are compared; zero compares all
+	Action    DedupAction   // Empty skips
+	Bump      float32       // Importance added to the existing memory by DedupBump and DedupMerge
+}
+
+// SetDedupPolicy sets the policy Store detects near duplicates with
+func (m *Memory) SetDedupPolicy(policy DedupPolicy) {
+	m.dedup.Store(&policy)
+}
+
+// absorbDuplicate looks for a recent memory of the same type and scope
+// that the new record repeats and applies the policy's action to it. On a
+// match it reports true and sets record.ID to the existing memory's, and
+// the record must not be stored. Records with an ID, pinned records and
+// the agent's own writings are never checked. Pinned and held memories
+// absorb duplicates without being changed.
+func (m *Memory) absorbDuplicate(ctx context.Context, record *MemoryRecord) (bool, error) {
+	policy := m.dedup.Load()
+	if policy == nil || policy.Threshold <= 0 || record.ID != "" || record.Pinned || len(record.Embedding) == 0 {
+		return false, nil
+	}
+	if source, _ := record.Metadata["content_source"].(string); !slices.Contains(dedupSources, source) {
+		return false, nil
+	}
+
+	filter := SearchFilter{Scope: record.Scope}
+	if policy.Window > 0 {
+		filter.Since = time.Now().Add(-policy.Window)
+	}
+	results, err := m.vectorDB.Search(ctx, m.getTableForType(record.Type), record.Embedding, 1, filter.storeFilter())
+	if err != nil {
+		return false, fmt.Errorf("failed to search for duplicates: %w", err)
+	}
+	if len(results) == 0 || results[0].Score < policy.Threshold {
+		return false, nil
+	}
+	existing := recordFromStore(results[0].ID, results[0].Vector, results[0].Metadata)
+	if existing.Scope != record.Scope {
+		return false, nil
+	}
+	record.ID = existing.ID
+
+	action := policy.Action
+	if existing.Pinned || existing.Held {
+		action = DedupSkip
+	}
+	switch action {
+	case DedupMerge:
+		existing.Content = record.Content
+		existing.Embedding = record.Embedding
+		existing.Timestamp = record.Timestamp
+		existing.Importance = max(existing.Importance, record.Importance)
+		for key, value := range record.Metadata {
+			existing.Metadata[key] = value
+		}
+		fallthrough
+	case DedupBump:
+		existing.Importance = min(existing.Importance+policy.Bump, 1)
+		count, _ := metadataNumber(existing.Metadata["duplicate_count"])
+		existing.Metadata["duplicate_count"] = count + 1
+		if err := m.write(ctx, &existing); err != nil {
+			return true, err
+		}
+	}
+
+	m.log().DebugContext(ctx, "absorbed duplicate memory", "memory_id", existing.ID, "action", action, "similarity", results[0].Score)
+	return true, nil
+}
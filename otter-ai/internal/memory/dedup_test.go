@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func newTestDedupMemory(t *testing.T, policy DedupPolicy) *Memory {
+	t.Helper()
+	db := newTestSQLiteDB(t)
+	mem := New(db)
+	mem.SetDedupPolicy(policy)
+	return mem
+}
+
+func storeInteraction(t *testing.T, mem *Memory, content string, embedding []float32, scope string) *MemoryRecord {
+	t.Helper()
+	record := &MemoryRecord{Type: MemoryTypeLongTerm, Content: content, Embedding: embedding, Scope: scope, Importance: 0.5,
+		Metadata: map[string]interface{}{"content_source": SourceInteraction}}
+	if err := mem.Store(context.Background(), record); err != nil {
+		t.Fatal(err)
+	}
+	return record
+}
+
+func TestStore_Dedup(t *testing.T) {
+	ctx := context.Background()
+	for _, tt := range []struct {
+		action     DedupAction
+		content    string
+		importance float32
+		count      float64
+	}{
+		{DedupSkip, "[user] hi\n[agent] hello", 0.5, 0},
+		{DedupBump, "[user] hi\n[agent] hello", 0.6, 1},
+		{DedupMerge, "[user] hi!\n[agent] hello!", 0.6, 1},
+	} {
+		t.Run(string(tt.action), func(t *testing.T) {
+			mem := newTestDedupMemory(t, DedupPolicy{Threshold: 0.95, Window: time.Hour, Action: tt.action, Bump: 0.1})
+			first := storeInteraction(t, mem, "[user] hi\n[agent] hello", []float32{1, 0.01}, "")
+			repeat := storeInteraction(t, mem, "[user] hi!\n[agent] hello!", []float32{1, 0.02}, "")
+			if repeat.ID != first.ID {
+				t.Fatalf("repeat stored as %s, want it absorbed by %s", repeat.ID, first.ID)
+			}
+			if count, _ := mem.Count(ctx, MemoryTypeLongTerm); count != 1 {
+				t.Errorf("Count = %d, want 1", count)
+			}
+
+			got, err := mem.Get(ctx, first.ID, MemoryTypeLongTerm)
+			if err != nil {
+				t.Fatal(err)
+			}
+			count, _ := metadataNumber(got.Metadata["duplicate_count"])
+			if got.Content != tt.content || got.Importance < tt.importance-0.001 || got.Importance > tt.importance+0.001 || count != tt.count {
+				t.Errorf("existing memory = %q, importance %v, duplicates %v", got.Content, got.Importance, count)
+			}
+		})
+	}
+}
+
+func TestStore_DedupKeepsDistinctMemories(t *testing.T) {
+	mem := newTestDedupMemory(t, DedupPolicy{Threshold: 0.95, Action: DedupBump, Bump: 0.1})
+	ctx := context.Background()
+
+	storeInteraction(t, mem, "kelp", []float32{1, 0}, "")
+	storeInteraction(t, mem, "rocks", []float32{0, 1}, "")
+	storeInteraction(t, mem, "kelp, at work", []float32{1, 0}, "work")
+	summary := &MemoryRecord{Type: MemoryTypeLongTerm, Content: "kelp summary", Embedding: []float32{1, 0},
+		Metadata: map[string]interface{}{"content_source": SourceAgentGenerated}}
+	if err := mem.Store(ctx, summary); err != nil {
+		t.Fatal(err)
+	}
+	pinned := &MemoryRecord{Type: MemoryTypeLongTerm, Content: "kelp!", Embedding: []float32{1, 0}, Pinned: true,
+		Metadata: map[string]interface{}{"content_source": SourceInteraction}}
+	if err := mem.Store(ctx, pinned); err != nil {
+		t.Fatal(err)
+	}
+
+	if count, _ := mem.Count(ctx, MemoryTypeLongTerm); count != 5 {
+		t.Errorf("Count = %d, want 5: other scopes, agent writings and pins are not duplicates", count)
+	}
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"otter-ai/internal/errs"
+	"otter-ai/internal/vectordb"
+)
+
+// ErrDelegationNotFound is returned for scopes without a delegation policy
+var ErrDelegationNotFound = errs.New(errs.ErrNotFound, "delegation not found")
+
+// MaxDelegations bounds how many delegation policies are listed
+const MaxDelegations = 1000
+
+// DelegationPolicy is the owner's standing instruction for how the otter
+// votes on proposals in a scope
+type DelegationPolicy struct {
+	Scope     string    `json:"scope"` // Rule scope pattern, e.g. "safety/*"
+	Mode      string    `json:"mode"`
+	SetBy     string    `json:"set_by,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// delegationID is the record ID of a scope's policy
+func delegationID(scope string) string {
+	return "delegation:" + scope
+}
+
+// SaveDelegation persists a delegation policy, replacing the scope's
+// previous one
+func (m *Memory) SaveDelegation(ctx context.Context, policy *DelegationPolicy) error {
+	if policy.Scope == "" {
+		return fmt.Errorf("delegation scope is required")
+	}
+	policy.UpdatedAt = time.Now()
+
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return fmt.Errorf("failed to marshal delegation: %w", err)
+	}
+	metadata := map[string]interface{}{
+		"type":       "delegation",
+		"delegation": string(data),
+		"updated_at": policy.UpdatedAt.Unix(),
+	}
+	if err := m.vectorDB.Store(ctx, vectordb.TableDelegations, delegationID(policy.Scope), nil, metadata); err != nil {
+		return fmt.Errorf("failed to store delegation: %w", err)
+	}
+	return nil
+}
+
+// ListDelegations returns the delegation policies ordered by scope
+func (m *Memory) ListDelegations(ctx context.Context) ([]DelegationPolicy, error) {
+	stored, err := m.vectorDB.List(ctx, vectordb.TableDelegations, MaxDelegations, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list delegations: %w", err)
+	}
+
+	policies := make([]DelegationPolicy, 0, len(stored))
+	for _, s := range stored {
+		raw, ok := s.Metadata["delegation"].(string)
+		if !ok {
+			continue
+		}
+		var policy DelegationPolicy
+		if err := json.Unmarshal([]byte(raw), &policy); err != nil {
+			continue // Skip corrupt entries rather than failing the whole listing
+		}
+		policies = append(policies, policy)
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i].Scope < policies[j].Scope })
+	return policies, nil
+}
+
+// DeleteDelegation removes a scope's delegation policy
+func (m *Memory) DeleteDelegation(ctx context.Context, scope string) error {
+	stored, err := m.vectorDB.Get(ctx, vectordb.TableDelegations, delegationID(scope))
+	if err != nil || stored == nil {
+		return fmt.Errorf("%w: %s", ErrDelegationNotFound, scope)
+	}
+	if err := m.vectorDB.Delete(ctx, vectordb.TableDelegations, delegationID(scope)); err != nil {
+		return fmt.Errorf("failed to delete delegation: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDelegations(t *testing.T) {
+	mem := New(newMockVectorDB())
+	ctx := context.Background()
+
+	for _, policy := range []*DelegationPolicy{
+		{Scope: "safety/*", Mode: "abstain", SetBy: "owner"},
+		{Scope: "general", Mode: "llm"},
+		{Scope: "general", Mode: "owner"},
+	} {
+		if err := mem.SaveDelegation(ctx, policy); err != nil {
+			t.Fatalf("SaveDelegation: %v", err)
+		}
+	}
+	policies, err := mem.ListDelegations(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policies) != 2 || policies[0].Scope != "general" || policies[0].Mode != "owner" || policies[1].SetBy != "owner" {
+		t.Errorf("ListDelegations = %+v, want one policy per scope ordered by scope", policies)
+	}
+
+	if err := mem.DeleteDelegation(ctx, "general"); err != nil {
+		t.Fatal(err)
+	}
+	if err := mem.DeleteDelegation(ctx, "general"); !errors.Is(err, ErrDelegationNotFound) {
+		t.Errorf("deleting twice: err = %v, want ErrDelegationNotFound", err)
+	}
+	if err := mem.SaveDelegation(ctx, &DelegationPolicy{}); err == nil {
+		t.Error("expected error for an empty scope")
+	}
+}
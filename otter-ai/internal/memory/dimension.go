@@ -0,0 +1,223 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"otter-ai/internal/vectordb"
+)
+
+// ErrDimensionMismatch is returned for embeddings whose dimension differs
+// from the vectors already stored
+var ErrDimensionMismatch = errors.New("embedding dimension mismatch")
+
+// ErrModelMismatch is returned when stored memories were embedded with
+// another model than the configured one
+var ErrModelMismatch = errors.New("embedding model mismatch")
+
+// ErrReindexUnsupported is returned when the vector backend can't record
+// embedding models or replace vectors in place
+var ErrReindexUnsupported = errors.New("memory backend does not support re-embedding")
+
+// embeddedTypes are the memory types searched by embedding
+var embeddedTypes = []MemoryType{MemoryTypeLongTerm, MemoryTypeMusing, MemoryTypeArchived, MemoryTypePersonality}
+
+// StoredDimension returns the dimension of the stored vectors, or zero when
+// no memory has been embedded yet
+func (m *Memory) StoredDimension(ctx context.Context) (int, error) {
+	for _, memoryType := range embeddedTypes {
+		dimension, err := m.storedDimension(ctx, memoryType)
+		if err != nil || dimension > 0 {
+			return dimension, err
+		}
+	}
+	return 0, nil
+}
+
+// storedDimension returns the dimension of a type's stored vectors, zero
+// when none is stored
+func (m *Memory) storedDimension(ctx context.Context, memoryType MemoryType) (int, error) {
+	records, err := m.ListFiltered(ctx, memoryType, SearchFilter{}, 1, 0)
+	if err != nil {
+		return 0, err
+	}
+	if len(records) > 0 {
+		return len(records[0].Embedding), nil
+	}
+	return 0, nil
+}
+
+// CheckDimension verifies embeddings of the given dimension can be compared
+// with the stored vectors, then makes Store reject any other dimension.
+// Vectors from a different embedding model can't be searched together, so
+// switching models requires re-embedding the stored memories first.
+func (m *Memory) CheckDimension(ctx context.Context, dimension int) error {
+	return m.CheckEmbeddingModel(ctx, "", dimension)
+}
+
+// CheckEmbeddingModel verifies embeddings from model, of the given
+// dimension, can be searched with the stored vectors, then makes Store
+// reject any other dimension. Where the backend keeps a registry, the model
+// and dimension are recorded for each table the first time and a later
+// change of either is refused until Reindex has re-embedded the table. An
+// empty model only checks the dimension.
+func (m *Memory) CheckEmbeddingModel(ctx context.Context, model string, dimension int) error {
+	registry, _ := m.vectorDB.(vectordb.EmbeddingRegistry)
+	for _, memoryType := range embeddedTypes {
+		table := m.getTableForType(memoryType)
+		var space *vectordb.EmbeddingSpace
+		if registry != nil {
+			var err error
+			if space, err = registry.EmbeddingSpace(ctx, table); err != nil {
+				return err
+			}
+		}
+
+		if space == nil {
+			stored, err := m.storedDimension(ctx, memoryType)
+			if err != nil {
+				return err
+			}
+			if stored > 0 && stored != dimension {
+				return fmt.Errorf("%w: the embedding provider returns %d dimensions but stored %s memories have %d; run otterctl reindex",
+					ErrDimensionMismatch, dimension, memoryType, stored)
+			}
+			if registry != nil && model != "" {
+				if err := registry.SetEmbeddingSpace(ctx, table, vectordb.EmbeddingSpace{Model: model, Dimension: dimension}); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		switch {
+		case space.Reindexing:
+			return fmt.Errorf("%w: re-embedding %s memories with %s did not finish; run otterctl reindex again",
+				ErrModelMismatch, memoryType, space.Model)
+		case space.Dimension != dimension:
+			return fmt.Errorf("%w: the embedding provider returns %d dimensions but stored %s memories have %d; run otterctl reindex",
+				ErrDimensionMismatch, dimension, memoryType, space.Dimension)
+		case model != "" && space.Model != model:
+			return fmt.Errorf("%w: stored %s memories were embedded with %s, not %s; run otterctl reindex",
+				ErrModelMismatch, memoryType, space.Model, model)
+		}
+	}
+	m.dimension.Store(int64(dimension))
+	return nil
+}
+
+// checkEmbedding rejects an embedding of the wrong dimension once the
+// dimension is known
+func (m *Memory) checkEmbedding(embedding []float32) error {
+	dimension := int(m.dimension.Load())
+	if dimension == 0 || len(embedding) == 0 || len(embedding) == dimension {
+		return nil
+	}
+	return fmt.Errorf("%w: got %d dimensions, want %d", ErrDimensionMismatch, len(embedding), dimension)
+}
+
+// ReindexOptions controls how Reindex re-embeds memories
+type ReindexOptions struct {
+	Model     string // Recorded as the model of the re-embedded tables
+	Dimension int    // Dimension of the model's vectors
+	BatchSize int    // Memories re-embedded per write; zero uses DefaultReindexBatch
+	// Force re-embeds tables already recorded as embedded with Model
+	Force bool
+	// Progress, when set, is called after each batch
+	Progress func(memoryType MemoryType, done, total int)
+}
+
+// DefaultReindexBatch is how many memories Reindex writes at once when the
+// options don't say
+const DefaultReindexBatch = 64
+
+// ReindexResult counts what Reindex re-embedded
+type ReindexResult struct {
+	Dimension  int                `json:"dimension"`
+	ReEmbedded map[MemoryType]int `json:"re_embedded"`
+	Skipped    []MemoryType       `json:"skipped,omitempty"` // Already embedded with the model
+}
+
+// Reindex re-embeds every memory of the embedded types with embed and
+// records the model and dimension as their embedding space. Each table is
+// marked as being re-embedded until it is done, so an interrupted run is
+// refused at startup and must be repeated; tables finished by an earlier
+// run are skipped. Vectors are replaced in place without new versions, and
+// restoring or reverting to a vector of another dimension is refused.
+func (m *Memory) Reindex(ctx context.Context, embed func(ctx context.Context, text string) ([]float32, error), opts ReindexOptions) (*ReindexResult, error) {
+	if err := m.writable(); err != nil {
+		return nil, err
+	}
+	registry, ok := m.vectorDB.(vectordb.EmbeddingRegistry)
+	if !ok {
+		return nil, ErrReindexUnsupported
+	}
+	if opts.Model == "" || opts.Dimension <= 0 {
+		return nil, errors.New("re-embedding requires the model name and dimension")
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultReindexBatch
+	}
+
+	result := &ReindexResult{Dimension: opts.Dimension, ReEmbedded: make(map[MemoryType]int)}
+	for _, memoryType := range embeddedTypes {
+		table := m.getTableForType(memoryType)
+		space, err := registry.EmbeddingSpace(ctx, table)
+		if err != nil {
+			return result, err
+		}
+		if !opts.Force && space != nil && !space.Reindexing && space.Model == opts.Model && space.Dimension == opts.Dimension {
+			result.Skipped = append(result.Skipped, memoryType)
+			continue
+		}
+
+		// Collect the contents first: vectors are replaced while reading
+		// would hold the table open
+		var ids, contents []string
+		if err := m.Each(ctx, memoryType, func(record MemoryRecord) error {
+			ids = append(ids, record.ID)
+			contents = append(contents, record.Content)
+			return nil
+		}); err != nil {
+			return result, fmt.Errorf("failed to read %s memories: %w", memoryType, err)
+		}
+
+		space = &vectordb.EmbeddingSpace{Model: opts.Model, Dimension: opts.Dimension, Reindexing: true}
+		if err := registry.SetEmbeddingSpace(ctx, table, *space); err != nil {
+			return result, err
+		}
+		for start := 0; start < len(ids); start += batchSize {
+			end := min(start+batchSize, len(ids))
+			vectors := make(map[string][]float32, end-start)
+			for i := start; i < end; i++ {
+				vector, err := embed(ctx, contents[i])
+				if err != nil {
+					return result, fmt.Errorf("failed to embed %s memory %s: %w", memoryType, ids[i], err)
+				}
+				if len(vector) != opts.Dimension {
+					return result, fmt.Errorf("%w: got %d dimensions, want %d", ErrDimensionMismatch, len(vector), opts.Dimension)
+				}
+				vectors[ids[i]] = vector
+			}
+			if err := registry.ReplaceVectors(ctx, table, vectors); err != nil {
+				return result, err
+			}
+			result.ReEmbedded[memoryType] += len(vectors)
+			if opts.Progress != nil {
+				opts.Progress(memoryType, end, len(ids))
+			}
+		}
+
+		space.Reindexing = false
+		if err := registry.SetEmbeddingSpace(ctx, table, *space); err != nil {
+			return result, err
+		}
+	}
+	m.dimension.Store(int64(opts.Dimension))
+
+	m.log().InfoContext(ctx, "re-embedded memories", "model", opts.Model, "dimension", opts.Dimension,
+		"re_embedded", result.ReEmbedded, "skipped", result.Skipped)
+	return result, nil
+}
@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"otter-ai/internal/vectordb"
+)
+
+func TestCheckDimension(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	mem := New(db)
+	ctx := context.Background()
+
+	// Nothing stored yet: any dimension is accepted
+	if err := mem.CheckDimension(ctx, 4); err != nil {
+		t.Fatalf("CheckDimension on empty store: %v", err)
+	}
+	if err := mem.Store(ctx, &MemoryRecord{Type: MemoryTypeLongTerm, Content: "kelp", Embedding: []float32{1, 0, 0, 0}}); err != nil {
+		t.Fatal(err)
+	}
+
+	wrong := &MemoryRecord{Type: MemoryTypeLongTerm, Content: "clams", Embedding: []float32{1, 0}}
+	if err := mem.Store(ctx, wrong); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("Store err = %v, want ErrDimensionMismatch", err)
+	}
+
+	// A fresh start with another model is refused
+	mem = New(db)
+	if dimension, err := mem.StoredDimension(ctx); err != nil || dimension != 4 {
+		t.Errorf("StoredDimension = %d, %v; want 4", dimension, err)
+	}
+	if err := mem.CheckDimension(ctx, 768); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("CheckDimension err = %v, want ErrDimensionMismatch", err)
+	}
+}
+
+func TestCheckEmbeddingModel(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	mem := New(db)
+	ctx := context.Background()
+
+	if err := mem.CheckEmbeddingModel(ctx, "ollama/nomic-embed-text", 4); err != nil {
+		t.Fatalf("CheckEmbeddingModel on empty store: %v", err)
+	}
+	space, err := db.EmbeddingSpace(ctx, vectordb.TableMemories)
+	if err != nil || space == nil || space.Model != "ollama/nomic-embed-text" || space.Dimension != 4 {
+		t.Fatalf("recorded space = %+v, %v", space, err)
+	}
+
+	// Same dimension, another model
+	if err := New(db).CheckEmbeddingModel(ctx, "ollama/all-minilm", 4); !errors.Is(err, ErrModelMismatch) {
+		t.Errorf("err = %v, want ErrModelMismatch", err)
+	}
+	if err := New(db).CheckEmbeddingModel(ctx, "ollama/nomic-embed-text", 8); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("err = %v, want ErrDimensionMismatch", err)
+	}
+	if err := New(db).CheckEmbeddingModel(ctx, "ollama/nomic-embed-text", 4); err != nil {
+		t.Errorf("same model: %v", err)
+	}
+}
+
+func TestReindex(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	mem := New(db)
+	ctx := context.Background()
+	if err := mem.CheckEmbeddingModel(ctx, "old", 2); err != nil {
+		t.Fatal(err)
+	}
+	for _, content := range []string{"kelp", "clams", "urchins"} {
+		if err := mem.Store(ctx, &MemoryRecord{Type: MemoryTypeLongTerm, Content: content, Embedding: []float32{1, 0}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	musing := &MemoryRecord{Type: MemoryTypeMusing, Content: "tides", Embedding: []float32{0, 1}}
+	if err := mem.Store(ctx, musing); err != nil {
+		t.Fatal(err)
+	}
+
+	embed := func(_ context.Context, text string) ([]float32, error) {
+		return []float32{float32(len(text)), 1, 0}, nil
+	}
+	var batches int
+	result, err := New(db).Reindex(ctx, embed, ReindexOptions{
+		Model: "new", Dimension: 3, BatchSize: 2,
+		Progress: func(MemoryType, int, int) { batches++ },
+	})
+	if err != nil {
+		t.Fatalf("Reindex: %v", err)
+	}
+	if result.ReEmbedded[MemoryTypeLongTerm] != 3 || result.ReEmbedded[MemoryTypeMusing] != 1 || batches != 3 {
+		t.Errorf("result = %+v after %d batches", result, batches)
+	}
+
+	got, err := mem.Get(ctx, musing.ID, MemoryTypeMusing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Embedding) != 3 || got.Embedding[0] != 5 {
+		t.Errorf("musing embedding = %v, want [5 1 0]", got.Embedding)
+	}
+	versions, err := mem.Versions(ctx, musing.ID, MemoryTypeMusing)
+	if err != nil || len(versions) != 1 {
+		t.Errorf("versions = %d, %v; re-embedding must not add versions", len(versions), err)
+	}
+
+	if err := New(db).CheckEmbeddingModel(ctx, "new", 3); err != nil {
+		t.Errorf("CheckEmbeddingModel after reindex: %v", err)
+	}
+	if err := New(db).CheckEmbeddingModel(ctx, "old", 2); err == nil {
+		t.Error("old model accepted after reindex")
+	}
+
+	// A second run skips finished tables
+	result, err = New(db).Reindex(ctx, embed, ReindexOptions{Model: "new", Dimension: 3})
+	if err != nil || len(result.ReEmbedded) != 0 || len(result.Skipped) != len(embeddedTypes) {
+		t.Errorf("second run = %+v, %v", result, err)
+	}
+}
+
+func TestReindex_Interrupted(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	mem := New(db)
+	ctx := context.Background()
+	if err := mem.Store(ctx, &MemoryRecord{Type: MemoryTypeLongTerm, Content: "kelp", Embedding: []float32{1, 0}}); err != nil {
+		t.Fatal(err)
+	}
+
+	failing := func(context.Context, string) ([]float32, error) { return nil, errors.New("provider down") }
+	if _, err := mem.Reindex(ctx, failing, ReindexOptions{Model: "new", Dimension: 3}); err == nil {
+		t.Fatal("expected the embedding error")
+	}
+	if err := New(db).CheckEmbeddingModel(ctx, "new", 3); !errors.Is(err, ErrModelMismatch) {
+		t.Errorf("err = %v, want ErrModelMismatch for an unfinished reindex", err)
+	}
+}
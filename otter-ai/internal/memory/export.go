@@ -0,0 +1,260 @@
+package memory
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"otter-ai/internal/errs"
+)
+
+// Portable export format. The first line is an ExportHeader; every further
+// line is one ExportedMemory.
+const (
+	ExportFormat  = "otter-memories"
+	ExportVersion = 1
+	MaxImportLine = 16 << 20 // Longest line Import reads, in bytes
+)
+
+// ErrInvalidExport is returned for input that is not a memory export this
+// version can read
+var ErrInvalidExport = errs.New(errs.ErrInvalid, "invalid memory export")
+
+// ExportHeader identifies an export and what it contains
+type ExportHeader struct {
+	Format     string       `json:"format"`
+	Version    int          `json:"version"`
+	ExportedAt time.Time    `json:"exported_at"`
+	Types      []MemoryType `json:"types"`
+	Embeddings bool         `json:"embeddings"`          // Whether memories carry their embeddings
+	Dimension  int          `json:"dimension,omitempty"` // Embedding dimension, when included
+}
+
+// ExportedMemory is one memory in the portable format. Legal holds are local
+// to an otter and are not exported.
+type ExportedMemory struct {
+	ID         string                 `json:"id"`
+	Type       MemoryType             `json:"type"`
+	Content    string                 `json:"content"`
+	Timestamp  time.Time              `json:"timestamp"`
+	Scope      string                 `json:"scope,omitempty"`
+	Importance float32                `json:"importance"`
+	Pinned     bool                   `json:"pinned,omitempty"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+	Embedding  []float32              `json:"embedding,omitempty"`
+}
+
+// ExportOptions selects what Export writes
+type ExportOptions struct {
+	Types      []MemoryType // Default: long_term and musing
+	Embeddings bool         // Include embeddings; without them Import re-embeds
+}
+
+// Export writes memories in the portable JSONL format, one type after the
+// other, newest first within a type. It returns the number of memories written.
+func (m *Memory) Export(ctx context.Context, w io.Writer, opts ExportOptions) (int, error) {
+	types := opts.Types
+	if len(types) == 0 {
+		types = []MemoryType{MemoryTypeLongTerm, MemoryTypeMusing}
+	}
+	for _, memoryType := range types {
+		if _, ok := m.Schema(memoryType); !ok {
+			return 0, fmt.Errorf("unknown memory type %q", memoryType)
+		}
+	}
+
+	header := ExportHeader{
+		Format:     ExportFormat,
+		Version:    ExportVersion,
+		ExportedAt: time.Now().UTC(),
+		Types:      types,
+		Embeddings: opts.Embeddings,
+	}
+	if opts.Embeddings {
+		dimension, err := m.StoredDimension(ctx)
+		if err != nil {
+			return 0, err
+		}
+		header.Dimension = dimension
+	}
+
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(header); err != nil {
+		return 0, fmt.Errorf("failed to write export header: %w", err)
+	}
+
+	written := 0
+	for _, memoryType := range types {
+		err := m.Each(ctx, memoryType, func(record MemoryRecord) error {
+			exported := ExportedMemory{
+				ID:         record.ID,
+				Type:       memoryType,
+				Content:    record.Content,
+				Timestamp:  record.Timestamp.UTC(),
+				Scope:      record.Scope,
+				Importance: record.Importance,
+				Pinned:     record.Pinned,
+				Metadata:   record.Metadata,
+			}
+			if opts.Embeddings {
+				exported.Embedding = record.Embedding
+			}
+			if err := enc.Encode(exported); err != nil {
+				return err
+			}
+			written++
+			return nil
+		})
+		if err != nil {
+			return written, fmt.Errorf("failed to export %s memories: %w", memoryType, err)
+		}
+	}
+	return written, nil
+}
+
+// ImportOptions controls how Import stores memories
+type ImportOptions struct {
+	// Embed generates embeddings for memories exported without one, or with
+	// one of another dimension. Without it such memories are skipped with an
+	// error, except for types that are not searched by embedding.
+	Embed func(ctx context.Context, text string) ([]float32, error)
+
+	// ReEmbed discards exported embeddings and embeds every memory with
+	// Embed, for moving memories to another embedding model
+	ReEmbed bool
+}
+
+// ImportError reports a line Import could not store
+type ImportError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// ImportResult summarises an import
+type ImportResult struct {
+	Imported   int           `json:"imported"`
+	Skipped    int           `json:"skipped"`     // Already stored under the same ID
+	ReEmbedded int           `json:"re_embedded"` // Stored with a newly generated embedding
+	Errors     []ImportError `json:"errors,omitempty"`
+}
+
+// Import stores the memories of a portable export. Memories keep their IDs,
+// so importing the same export twice skips the memories already stored.
+// A line that can't be stored is recorded in the result and the import
+// continues; an unreadable header or stream fails the whole import.
+func (m *Memory) Import(ctx context.Context, r io.Reader, opts ImportOptions) (*ImportResult, error) {
+	if opts.ReEmbed && opts.Embed == nil {
+		return nil, errors.New("re-embedding requires an embedding function")
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxImportLine)
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read export: %w", err)
+		}
+		return nil, fmt.Errorf("%w: empty input", ErrInvalidExport)
+	}
+	var header ExportHeader
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil || header.Format != ExportFormat {
+		return nil, fmt.Errorf("%w: missing %s header", ErrInvalidExport, ExportFormat)
+	}
+	if header.Version < 1 || header.Version > ExportVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidExport, header.Version)
+	}
+
+	result := &ImportResult{}
+	for line := 2; scanner.Scan(); line++ {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var exported ExportedMemory
+		if err := json.Unmarshal(scanner.Bytes(), &exported); err != nil {
+			result.Errors = append(result.Errors, ImportError{Line: line, Error: "malformed memory: " + err.Error()})
+			continue
+		}
+		skipped, reembedded, err := m.importMemory(ctx, &exported, opts)
+		switch {
+		case err != nil:
+			result.Errors = append(result.Errors, ImportError{Line: line, Error: err.Error()})
+		case skipped:
+			result.Skipped++
+		default:
+			result.Imported++
+			if reembedded {
+				result.ReEmbedded++
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return result, fmt.Errorf("failed to read export: %w", err)
+	}
+
+	m.log().InfoContext(ctx, "imported memories", "imported", result.Imported, "skipped", result.Skipped,
+		"re_embedded", result.ReEmbedded, "errors", len(result.Errors))
+	return result, nil
+}
+
+// importMemory stores one exported memory unless its ID is already taken
+func (m *Memory) importMemory(ctx context.Context, exported *ExportedMemory, opts ImportOptions) (skipped, reembedded bool, err error) {
+	memoryType := normalizeType(exported.Type)
+	if _, ok := m.Schema(memoryType); !ok {
+		return false, false, fmt.Errorf("unknown memory type %q", exported.Type)
+	}
+	if exported.Content == "" {
+		return false, false, errors.New("memory has no content")
+	}
+	if exported.ID != "" {
+		if existing, err := m.vectorDB.Get(ctx, m.getTableForType(memoryType), exported.ID); err == nil && existing != nil {
+			return true, false, nil
+		}
+	}
+
+	embedding := exported.Embedding
+	if opts.ReEmbed || m.checkEmbedding(embedding) != nil || len(embedding) == 0 && isEmbeddedType(memoryType) {
+		if opts.Embed == nil {
+			if len(embedding) == 0 {
+				return false, false, errors.New("memory has no embedding and no embedding provider is available")
+			}
+			return false, false, m.checkEmbedding(embedding)
+		}
+		if embedding, err = opts.Embed(ctx, exported.Content); err != nil {
+			return false, false, fmt.Errorf("failed to embed memory: %w", err)
+		}
+		reembedded = true
+	}
+
+	record := &MemoryRecord{
+		ID:         exported.ID,
+		Type:       memoryType,
+		Content:    exported.Content,
+		Embedding:  embedding,
+		Timestamp:  exported.Timestamp,
+		Scope:      exported.Scope,
+		Importance: exported.Importance,
+		Pinned:     exported.Pinned,
+		Metadata:   exported.Metadata,
+	}
+	if err := m.Store(ctx, record); err != nil {
+		return false, false, err
+	}
+	return false, reembedded, nil
+}
+
+// isEmbeddedType reports whether memories of a type are searched by embedding
+func isEmbeddedType(memoryType MemoryType) bool {
+	for _, t := range embeddedTypes {
+		if t == memoryType {
+			return true
+		}
+	}
+	return false
+}
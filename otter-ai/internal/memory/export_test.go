@@ -0,0 +1,178 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func seedExport(t *testing.T, mem *Memory) {
+	t.Helper()
+	ctx := context.Background()
+	records := []*MemoryRecord{
+		{Type: MemoryTypeLongTerm, Content: "kelp grows fast", Embedding: []float32{1, 0, 0}, Importance: 0.8, Pinned: true,
+			Metadata: map[string]interface{}{"content_source": "interaction"}},
+		{Type: MemoryTypeLongTerm, Content: "urchins eat kelp", Embedding: []float32{0, 1, 0}, Scope: "reef"},
+		{Type: MemoryTypeMusing, Content: "why do otters hold hands", Embedding: []float32{0, 0, 1}},
+		{Type: MemoryTypePersonality, Content: "curious", Embedding: []float32{1, 1, 0}},
+	}
+	for i, rec := range records {
+		rec.Timestamp = time.Unix(int64(1700000000+i), 0)
+		if err := mem.Store(ctx, rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	ctx := context.Background()
+	src := New(newMockVectorDB())
+	seedExport(t, src)
+
+	var buf bytes.Buffer
+	n, err := src.Export(ctx, &buf, ExportOptions{Embeddings: true})
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("exported %d memories, want 3 (personality is not exported by default)", n)
+	}
+	var header ExportHeader
+	if err := json.Unmarshal([]byte(strings.SplitN(buf.String(), "\n", 2)[0]), &header); err != nil {
+		t.Fatal(err)
+	}
+	if header.Format != ExportFormat || header.Version != ExportVersion || header.Dimension != 3 || !header.Embeddings {
+		t.Errorf("header = %+v", header)
+	}
+
+	dst := New(newMockVectorDB())
+	result, err := dst.Import(ctx, bytes.NewReader(buf.Bytes()), ImportOptions{})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if result.Imported != 3 || result.ReEmbedded != 0 || len(result.Errors) != 0 {
+		t.Errorf("result = %+v", result)
+	}
+
+	original, _ := src.ListFiltered(ctx, MemoryTypeLongTerm, SearchFilter{}, 10, 0)
+	for _, want := range original {
+		got, err := dst.Get(ctx, want.ID, MemoryTypeLongTerm)
+		if err != nil {
+			t.Fatalf("imported memory %s missing: %v", want.ID, err)
+		}
+		if got.Content != want.Content || got.Scope != want.Scope || got.Pinned != want.Pinned ||
+			got.Importance != want.Importance || !got.Timestamp.Equal(want.Timestamp) || len(got.Embedding) != 3 {
+			t.Errorf("imported %+v, want %+v", got, want)
+		}
+		if want.Metadata["content_source"] != got.Metadata["content_source"] {
+			t.Errorf("metadata = %v, want %v", got.Metadata, want.Metadata)
+		}
+	}
+
+	// Importing again is a no-op
+	result, err = dst.Import(ctx, bytes.NewReader(buf.Bytes()), ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Imported != 0 || result.Skipped != 3 {
+		t.Errorf("second import = %+v, want all skipped", result)
+	}
+}
+
+func TestImport_ReEmbeds(t *testing.T) {
+	ctx := context.Background()
+	src := New(newMockVectorDB())
+	seedExport(t, src)
+
+	var buf bytes.Buffer
+	if _, err := src.Export(ctx, &buf, ExportOptions{Types: []MemoryType{MemoryTypeLongTerm}}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), `"embedding"`) {
+		t.Error("embeddings should be left out unless requested")
+	}
+
+	// Without an embedder, memories without embeddings can't be searched
+	result, err := New(newMockVectorDB()).Import(ctx, bytes.NewReader(buf.Bytes()), ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Imported != 0 || len(result.Errors) != 2 || result.Errors[0].Line != 2 {
+		t.Errorf("result = %+v, want an error per memory", result)
+	}
+
+	embedded := 0
+	embed := func(ctx context.Context, text string) ([]float32, error) {
+		embedded++
+		return []float32{0.5, 0.5, 0.5, 0.5}, nil
+	}
+	dst := New(newMockVectorDB())
+	if err := dst.CheckDimension(ctx, 4); err != nil {
+		t.Fatal(err)
+	}
+	result, err = dst.Import(ctx, bytes.NewReader(buf.Bytes()), ImportOptions{Embed: embed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Imported != 2 || result.ReEmbedded != 2 || embedded != 2 {
+		t.Errorf("result = %+v, embedded %d", result, embedded)
+	}
+
+	// Embeddings of another dimension are replaced rather than refused
+	buf.Reset()
+	if _, err := src.Export(ctx, &buf, ExportOptions{Types: []MemoryType{MemoryTypeMusing}, Embeddings: true}); err != nil {
+		t.Fatal(err)
+	}
+	result, err = dst.Import(ctx, bytes.NewReader(buf.Bytes()), ImportOptions{Embed: embed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Imported != 1 || result.ReEmbedded != 1 {
+		t.Errorf("result = %+v, want the musing re-embedded", result)
+	}
+}
+
+func TestImport_Rejections(t *testing.T) {
+	ctx := context.Background()
+	mem := New(newMockVectorDB())
+
+	for name, input := range map[string]string{
+		"empty":       "",
+		"no header":   `{"id":"m1","type":"long_term","content":"kelp"}`,
+		"new version": `{"format":"otter-memories","version":99}`,
+	} {
+		if _, err := mem.Import(ctx, strings.NewReader(input), ImportOptions{}); !errors.Is(err, ErrInvalidExport) {
+			t.Errorf("%s: err = %v, want ErrInvalidExport", name, err)
+		}
+	}
+
+	input := `{"format":"otter-memories","version":1}
+not json
+{"type":"dream","content":"kelp"}
+{"type":"long_term","content":"kelp","embedding":[1,0],"metadata":{"content":"reserved"}}
+{"type":"short_term","content":"hello"}
+`
+	result, err := mem.Import(ctx, strings.NewReader(input), ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Imported != 1 || len(result.Errors) != 3 {
+		t.Fatalf("result = %+v, want one import and three errors", result)
+	}
+	for i, line := range []int{2, 3, 4} {
+		if result.Errors[i].Line != line {
+			t.Errorf("error %d on line %d, want %d", i, result.Errors[i].Line, line)
+		}
+	}
+
+	if _, err := mem.Import(ctx, strings.NewReader(input), ImportOptions{ReEmbed: true}); err == nil {
+		t.Error("expected re-embedding without an embedder to be refused")
+	}
+	if _, err := mem.Export(ctx, &bytes.Buffer{}, ExportOptions{Types: []MemoryType{"dream"}}); err == nil {
+		t.Error("expected exporting an unknown type to fail")
+	}
+}
@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"otter-ai/internal/tracing"
+	"otter-ai/internal/vectordb"
+)
+
+// SearchFilter narrows memory searches and listings. Zero fields don't
+// filter, so the zero SearchFilter matches every memory.
+type SearchFilter struct {
+	Since         time.Time              // Stored at or after
+	Until         time.Time              // Stored at or before
+	Scope         string                 // Exact scope
+	MinImportance float32                // Importance at least
+	Metadata      map[string]interface{} // Metadata fields equal to these values
+}
+
+// storeFilter translates the filter to conditions on stored metadata
+func (f SearchFilter) storeFilter() vectordb.Filter {
+	filter := vectordb.Filter{
+		Equals: make(map[string]interface{}, len(f.Metadata)+1),
+		Min:    make(map[string]float64),
+		Max:    make(map[string]float64),
+	}
+	for key, value := range f.Metadata {
+		filter.Equals[key] = value
+	}
+	if f.Scope != "" {
+		filter.Equals["scope"] = f.Scope
+	}
+	if !f.Since.IsZero() {
+		filter.Min["timestamp"] = float64(f.Since.Unix())
+	}
+	if !f.Until.IsZero() {
+		filter.Max["timestamp"] = float64(f.Until.Unix())
+	}
+	if f.MinImportance > 0 {
+		filter.Min["importance"] = float64(f.MinImportance)
+	}
+	return filter
+}
+
+// ListFiltered retrieves the memories matching filter with pagination,
+// newest first
+func (m *Memory) ListFiltered(ctx context.Context, memoryType MemoryType, filter SearchFilter, limit, offset int) (_ []MemoryRecord, err error) {
+	ctx, span := startSpan(ctx, "memory.ListFiltered", memoryType)
+	defer func() { tracing.End(span, err) }()
+
+	table := m.getTableForType(memoryType)
+
+	records, err := vectordb.ListFiltered(ctx, m.vectorDB, table, filter.storeFilter(), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list memories: %w", err)
+	}
+
+	memories := make([]MemoryRecord, 0, len(records))
+	for _, record := range records {
+		memories = append(memories, recordFromStore(record.ID, record.Vector, record.Metadata))
+	}
+	return memories, nil
+}
@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSearchFilter_SQLite(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	mem := New(db)
+	ctx := context.Background()
+
+	day := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	for _, record := range []*MemoryRecord{
+		{ID: "early", Content: "kelp forest survey", Timestamp: day.Add(-48 * time.Hour), Scope: "research", Importance: 0.9},
+		{ID: "minor", Content: "lunch was clams", Timestamp: day, Scope: "research", Importance: 0.1},
+		{ID: "tagged", Content: "survey notes from a plugin", Timestamp: day, Scope: "research", Importance: 0.7,
+			Metadata: map[string]interface{}{"content_source": SourcePlugin}},
+		{ID: "other", Content: "family visit", Timestamp: day, Scope: "home", Importance: 0.8},
+	} {
+		record.Type = MemoryTypeLongTerm
+		if err := mem.Store(ctx, record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	filter := SearchFilter{Since: day.Add(-time.Hour), Scope: "research", MinImportance: 0.5}
+	results, err := mem.Search(ctx, []float32{1}, MemoryTypeLongTerm, 10, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].ID != "tagged" {
+		t.Errorf("Search = %+v, want only tagged", results)
+	}
+
+	listed, err := mem.ListFiltered(ctx, MemoryTypeLongTerm, SearchFilter{Until: day.Add(-time.Hour)}, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 1 || listed[0].ID != "early" {
+		t.Errorf("ListFiltered = %+v, want only early", listed)
+	}
+
+	hybrid, err := mem.HybridSearch(ctx, "survey", []float32{1}, MemoryTypeLongTerm, 10,
+		SearchFilter{Metadata: map[string]interface{}{"content_source": SourcePlugin}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hybrid) != 1 || hybrid[0].ID != "tagged" {
+		t.Errorf("HybridSearch = %+v, want only tagged", hybrid)
+	}
+}
@@ -0,0 +1,169 @@
+package memory
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"otter-ai/internal/errs"
+	"otter-ai/internal/vectordb"
+)
+
+// ErrNoCriteria is returned when a memory erasure names nothing to match,
+// which would erase every memory
+var ErrNoCriteria = errs.New(errs.ErrInvalid, "erasure requires an ID, user, time range or text to match")
+
+// forgettableTypes are the memory types erasure searches by default.
+// Personality traits describe the otter rather than anyone it talked to.
+var forgettableTypes = []MemoryType{MemoryTypeLongTerm, MemoryTypeMusing, MemoryTypeArchived}
+
+// ForgetCriteria selects memories to erase. A memory must match every set
+// field.
+type ForgetCriteria struct {
+	IDs    []string     `json:"ids,omitempty"`
+	UserID string       `json:"user_id,omitempty"` // Memories of conversations with this user
+	Since  time.Time    `json:"since,omitempty"`   // Stored at or after
+	Until  time.Time    `json:"until,omitempty"`   // Stored at or before
+	Match  string       `json:"match,omitempty"`   // Text the content contains, ignoring case
+	Types  []MemoryType `json:"types,omitempty"`   // Defaults to long_term, musing and archived
+}
+
+// IsZero reports whether the criteria match every memory
+func (c ForgetCriteria) IsZero() bool {
+	return len(c.IDs) == 0 && c.UserID == "" && c.Since.IsZero() && c.Until.IsZero() && strings.TrimSpace(c.Match) == ""
+}
+
+// matches reports whether a memory satisfies the criteria
+func (c ForgetCriteria) matches(record *MemoryRecord) bool {
+	if len(c.IDs) > 0 && !slices.Contains(c.IDs, record.ID) {
+		return false
+	}
+	if c.UserID != "" {
+		if userID, _ := record.Metadata["user_id"].(string); userID != c.UserID {
+			return false
+		}
+	}
+	if !c.Since.IsZero() && record.Timestamp.Before(c.Since) {
+		return false
+	}
+	if !c.Until.IsZero() && record.Timestamp.After(c.Until) {
+		return false
+	}
+	if match := strings.ToLower(strings.TrimSpace(c.Match)); match != "" && !strings.Contains(strings.ToLower(record.Content), match) {
+		return false
+	}
+	return true
+}
+
+// ForgottenMemory identifies an erased memory without its content. The
+// content hash lets an auditor confirm which memory was erased from a copy
+// of it, while the tombstone itself discloses nothing.
+type ForgottenMemory struct {
+	ID          string     `json:"id"`
+	Type        MemoryType `json:"type"`
+	ContentHash string     `json:"content_hash"` // Hex SHA-256 of the content
+	StoredAt    time.Time  `json:"stored_at"`
+}
+
+// ForgetResult lists the memories an erasure matched
+type ForgetResult struct {
+	Erased []ForgottenMemory `json:"erased"`
+	// Held memories matched but are under legal hold, so were kept
+	Held   []ForgottenMemory `json:"held,omitempty"`
+	DryRun bool              `json:"dry_run,omitempty"`
+}
+
+// Forget permanently erases the memories matching criteria, with every
+// earlier version where the backend keeps them, so unlike Delete it can't
+// be undone. Memories under legal hold are kept and reported as held. A dry
+// run only reports what would be erased.
+func (m *Memory) Forget(ctx context.Context, criteria ForgetCriteria, dryRun bool) (*ForgetResult, error) {
+	if criteria.IsZero() {
+		return nil, ErrNoCriteria
+	}
+	if !dryRun {
+		if err := m.writable(); err != nil {
+			return nil, err
+		}
+	}
+	types := criteria.Types
+	if len(types) == 0 {
+		types = forgettableTypes
+	}
+
+	result := &ForgetResult{Erased: []ForgottenMemory{}, DryRun: dryRun}
+	seenTables := make(map[string]bool, len(types))
+	for _, memoryType := range types {
+		table := m.getTableForType(memoryType)
+		if seenTables[table] {
+			continue
+		}
+		seenTables[table] = true
+
+		var matched []string
+		collect := func(record MemoryRecord) error {
+			if !criteria.matches(&record) {
+				return nil
+			}
+			forgotten := ForgottenMemory{ID: record.ID, Type: memoryType, ContentHash: contentHash(record.Content), StoredAt: record.Timestamp}
+			if record.Held {
+				result.Held = append(result.Held, forgotten)
+				return nil
+			}
+			result.Erased = append(result.Erased, forgotten)
+			matched = append(matched, record.ID)
+			return nil
+		}
+
+		if len(criteria.IDs) > 0 {
+			for _, id := range criteria.IDs {
+				record, err := m.vectorDB.Get(ctx, table, id)
+				if err != nil || record == nil {
+					continue
+				}
+				collect(recordFromStore(record.ID, record.Vector, record.Metadata))
+			}
+		} else if err := m.Each(ctx, memoryType, collect); err != nil {
+			return nil, fmt.Errorf("failed to read %s memories: %w", memoryType, err)
+		}
+
+		if dryRun || len(matched) == 0 {
+			continue
+		}
+		if err := m.erase(ctx, table, matched); err != nil {
+			return nil, err
+		}
+	}
+
+	if !dryRun {
+		m.log().InfoContext(ctx, "erased memories", "erased", len(result.Erased), "held", len(result.Held))
+	}
+	return result, nil
+}
+
+// erase removes records permanently, with their history when the backend
+// keeps one
+func (m *Memory) erase(ctx context.Context, table string, ids []string) error {
+	if eraser, ok := m.vectorDB.(vectordb.Eraser); ok {
+		if err := eraser.Erase(ctx, table, ids); err != nil {
+			return fmt.Errorf("failed to erase memories: %w", err)
+		}
+		return nil
+	}
+	for _, id := range ids {
+		if err := m.vectorDB.Delete(ctx, table, id); err != nil {
+			return fmt.Errorf("failed to erase memory %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// contentHash returns the hex SHA-256 of a memory's content
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
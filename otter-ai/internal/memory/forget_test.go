@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestForget(t *testing.T) {
+	mem := New(newMockVectorDB())
+	ctx := context.Background()
+
+	store := func(content, userID string, memoryType MemoryType) *MemoryRecord {
+		record := &MemoryRecord{Type: memoryType, Content: content, Metadata: map[string]interface{}{}}
+		if userID != "" {
+			record.Metadata["user_id"] = userID
+		}
+		if err := mem.Store(ctx, record); err != nil {
+			t.Fatalf("Store: %v", err)
+		}
+		return record
+	}
+	dana := store("Dana's phone number is 555-0100", "slack:U1", MemoryTypeLongTerm)
+	musing := store("thinking about Dana's garden", "", MemoryTypeMusing)
+	other := store("Sam likes kelp", "slack:U2", MemoryTypeLongTerm)
+	held := store("Dana signed the contract", "slack:U1", MemoryTypeLongTerm)
+	if _, err := mem.SetHeld(ctx, held.ID, MemoryTypeLongTerm, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := mem.Forget(ctx, ForgetCriteria{Match: "  "}, false); !errors.Is(err, ErrNoCriteria) {
+		t.Errorf("err = %v, want ErrNoCriteria", err)
+	}
+
+	preview, err := mem.Forget(ctx, ForgetCriteria{Match: "dana"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !preview.DryRun || len(preview.Erased) != 2 || len(preview.Held) != 1 || preview.Held[0].ID != held.ID {
+		t.Errorf("preview = %+v", preview)
+	}
+	if _, err := mem.Get(ctx, dana.ID, MemoryTypeLongTerm); err != nil {
+		t.Errorf("dry run should keep memories: %v", err)
+	}
+
+	result, err := mem.Forget(ctx, ForgetCriteria{UserID: "slack:U1", Until: time.Now().Add(time.Minute)}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Erased) != 1 || result.Erased[0].ID != dana.ID || result.Erased[0].ContentHash != contentHash(dana.Content) {
+		t.Errorf("result = %+v", result)
+	}
+	if _, err := mem.Get(ctx, dana.ID, MemoryTypeLongTerm); err == nil {
+		t.Error("erased memory should be gone")
+	}
+	for _, kept := range []*MemoryRecord{musing, other, held} {
+		if _, err := mem.Get(ctx, kept.ID, kept.Type); err != nil {
+			t.Errorf("%q should be kept: %v", kept.Content, err)
+		}
+	}
+
+	result, err = mem.Forget(ctx, ForgetCriteria{IDs: []string{musing.ID, "missing"}}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Erased) != 1 || result.Erased[0].Type != MemoryTypeMusing {
+		t.Errorf("by ID = %+v", result)
+	}
+}
@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"otter-ai/internal/errs"
+)
+
+// ErrHeld is returned when an operation would remove or alter a memory that
+// is under legal hold
+var ErrHeld = errs.New(errs.ErrConflict, "memory is under legal hold")
+
+// SetHeld places or lifts the legal hold flag on a stored memory. Holds are
+// managed by governance, which records who placed and released them.
+func (m *Memory) SetHeld(ctx context.Context, id string, memoryType MemoryType, held bool) (*MemoryRecord, error) {
+	table := m.getTableForType(memoryType)
+
+	record, err := m.vectorDB.Get(ctx, table, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get memory: %w", err)
+	}
+	if record == nil {
+		return nil, errs.Errorf(errs.ErrNotFound, "memory not found: %s", id)
+	}
+
+	memory := recordFromStore(record.ID, record.Vector, record.Metadata)
+	memory.Type = memoryType
+	memory.Held = held
+
+	if err := m.write(ctx, &memory); err != nil {
+		return nil, err
+	}
+	return &memory, nil
+}
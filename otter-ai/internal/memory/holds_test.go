@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSetHeld_BlocksDeleteAndPruning(t *testing.T) {
+	mem := New(newMockVectorDB())
+	ctx := context.Background()
+
+	record := &MemoryRecord{Type: MemoryTypeMusing, Content: "evidence", Importance: 0.1}
+	if err := mem.Store(ctx, record); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+
+	held, err := mem.SetHeld(ctx, record.ID, MemoryTypeMusing, true)
+	if err != nil {
+		t.Fatalf("SetHeld: %v", err)
+	}
+	if !held.Held || held.Prunable() {
+		t.Error("held memory should not be prunable")
+	}
+	if err := mem.Delete(ctx, record.ID, MemoryTypeMusing); !errors.Is(err, ErrHeld) {
+		t.Errorf("Delete err = %v, want ErrHeld", err)
+	}
+
+	got, err := mem.Get(ctx, record.ID, MemoryTypeMusing)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !got.Held || got.Content != "evidence" {
+		t.Errorf("unexpected record %+v", got)
+	}
+	if _, ok := got.Metadata["held"]; ok {
+		t.Error("held is a core field and should not leak into Metadata")
+	}
+
+	if _, err := mem.SetHeld(ctx, record.ID, MemoryTypeMusing, false); err != nil {
+		t.Fatalf("SetHeld(false): %v", err)
+	}
+	if err := mem.Delete(ctx, record.ID, MemoryTypeMusing); err != nil {
+		t.Errorf("Delete after release: %v", err)
+	}
+}
+
+func TestSetHeld_NotFound(t *testing.T) {
+	mem := New(newMockVectorDB())
+	if _, err := mem.SetHeld(context.Background(), "missing", MemoryTypeLongTerm, true); err == nil {
+		t.Error("expected error for unknown memory")
+	}
+}
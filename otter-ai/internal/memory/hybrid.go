@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"otter-ai/internal/tracing"
+	"otter-ai/internal/vectordb"
+)
+
+// Hybrid search tuning
+const (
+	// RRFK damps reciprocal rank fusion so a single top rank in one list
+	// doesn't outweigh consistent ranks in both
+	RRFK = 60
+	// HybridOversample is how many candidates per result each ranking
+	// contributes before fusion
+	HybridOversample = 3
+)
+
+// HybridSearch finds memories by both meaning and exact terms. Cosine
+// similarity to the embedding and keyword (BM25) matches of the query are
+// ranked separately and merged by reciprocal rank fusion, so exact names
+// and IDs are found even when their embeddings are not close. Backends
+// without a keyword index fall back to vector search alone. Results are
+// scored by their similarity to the embedding, whichever ranking found them.
+func (m *Memory) HybridSearch(ctx context.Context, query string, queryEmbedding []float32, memoryType MemoryType, limit int, filter SearchFilter) (_ []MemoryRecord, err error) {
+	ctx, span := startSpan(ctx, "memory.HybridSearch", memoryType)
+	defer func() { tracing.End(span, err) }()
+
+	keyword, ok := m.vectorDB.(vectordb.KeywordSearcher)
+	if !ok || query == "" {
+		return m.Search(ctx, queryEmbedding, memoryType, limit, filter)
+	}
+	table := m.getTableForType(memoryType)
+	storeFilter := filter.storeFilter()
+
+	candidates := limit * HybridOversample
+	semantic, err := m.vectorDB.Search(ctx, table, queryEmbedding, candidates, storeFilter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search memories: %w", err)
+	}
+	lexical, err := keyword.KeywordSearch(ctx, table, query, candidates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search memory keywords: %w", err)
+	}
+
+	// The keyword index has no filter pushdown; rank only matching hits
+	matching := lexical[:0]
+	for _, result := range lexical {
+		if storeFilter.Matches(result.Metadata) {
+			matching = append(matching, result)
+		}
+	}
+
+	similarity := make(map[string]float64, len(semantic))
+	for _, result := range semantic {
+		similarity[result.ID] = result.Score
+	}
+	fused := make(map[string]float64)
+	results := make(map[string]vectordb.SearchResult)
+	for _, ranking := range [][]vectordb.SearchResult{semantic, matching} {
+		for rank, result := range ranking {
+			fused[result.ID] += 1 / float64(RRFK+rank+1)
+			results[result.ID] = result
+		}
+	}
+
+	ids := make([]string, 0, len(fused))
+	for id := range fused {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if fused[ids[i]] != fused[ids[j]] {
+			return fused[ids[i]] > fused[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	if limit > 0 && limit < len(ids) {
+		ids = ids[:limit]
+	}
+
+	memories := make([]MemoryRecord, 0, len(ids))
+	for _, id := range ids {
+		result := results[id]
+		record := recordFromStore(result.ID, result.Vector, result.Metadata)
+		if score, ok := similarity[id]; ok {
+			record.Score = score
+		} else {
+			record.Score = vectordb.CosineSimilarity(queryEmbedding, result.Vector)
+		}
+		memories = append(memories, record)
+	}
+	if table == vectordb.TableMemories {
+		m.accesses.record(ids)
+	}
+	return memories, nil
+}
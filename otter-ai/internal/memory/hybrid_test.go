@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHybridSearch_FindsExactTerms(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	mem := New(db)
+	ctx := context.Background()
+
+	for _, record := range []*MemoryRecord{
+		{ID: "near", Content: "we talked about deploy schedules", Embedding: []float32{1, 0}},
+		{ID: "exact", Content: "ticket OTR-4411 belongs to the billing team", Embedding: []float32{0, 1}},
+		{ID: "far", Content: "otters hold hands", Embedding: []float32{-1, 0}},
+	} {
+		record.Type = MemoryTypeLongTerm
+		if err := mem.Store(ctx, record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The embedding favours "near", but the exact ID only matches "exact"
+	results, err := mem.HybridSearch(ctx, "OTR-4411", []float32{1, 0}, MemoryTypeLongTerm, 2, SearchFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].ID != "exact" || results[1].ID != "near" {
+		t.Errorf("expected the keyword match first, got %+v", results)
+	}
+}
+
+func TestHybridSearch_FallsBackToVectors(t *testing.T) {
+	mem := New(newMockVectorDB())
+	ctx := context.Background()
+	mem.Store(ctx, &MemoryRecord{ID: "m1", Type: MemoryTypeLongTerm, Content: "kelp", Embedding: []float32{1}})
+
+	results, err := mem.HybridSearch(ctx, "kelp", []float32{1}, MemoryTypeLongTerm, 5, SearchFilter{})
+	if err != nil || len(results) != 1 {
+		t.Errorf("HybridSearch = %+v, %v", results, err)
+	}
+}
@@ -5,14 +5,36 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"log/slog"
+	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"otter-ai/internal/events"
+	"otter-ai/internal/tracing"
 	"otter-ai/internal/vectordb"
 )
 
+var tracer = tracing.Tracer("memory")
+
+// startSpan starts the span of a memory operation
+func startSpan(ctx context.Context, name string, memoryType MemoryType) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attribute.String("memory.type", string(normalizeType(memoryType)))))
+}
+
 // Memory manages the agent's memory layer with bounded, auditable storage
 type Memory struct {
-	vectorDB vectordb.VectorDB
+	vectorDB  vectordb.VectorDB
+	schemas   *schemaRegistry            // Allowed metadata fields per memory type
+	events    atomic.Pointer[events.Bus] // Receives memory.created events
+	retention atomic.Pointer[RetentionPolicy]
+	dedup     atomic.Pointer[DedupPolicy]
+	accesses  accessTracker // Search hits since the last retention run
+	dimension atomic.Int64  // Embedding dimension once checked; zero accepts any
+	logger    atomic.Pointer[slog.Logger]
+	readOnly  atomic.Bool // Memory writes are refused with ErrReadOnly
 }
 
 // MemoryType defines the type of memory
@@ -23,6 +45,7 @@ const (
 	MemoryTypeLongTerm    MemoryType = "long_term"
 	MemoryTypeMusing      MemoryType = "musing"
 	MemoryTypePersonality MemoryType = "personality"
+	MemoryTypeArchived    MemoryType = "archived" // Originals replaced by a consolidation summary
 )
 
 // MemoryRecord represents a memory entry
@@ -34,18 +57,99 @@ type MemoryRecord struct {
 	Timestamp  time.Time
 	Scope      string
 	Importance float32
-	Metadata   map[string]interface{}
+	Pinned     bool                   // Pinned memories are exempt from decay and pruning
+	Held       bool                   // Under legal hold: exempt from pruning, redaction and compaction
+	Metadata   map[string]interface{} // Extra fields, validated against the type's MetadataSchema
+	// Score is a search result's cosine similarity to the query; zero
+	// outside searches
+	Score float64 `json:",omitempty"`
 }
 
 // New creates a new memory layer
 func New(vectorDB vectordb.VectorDB) *Memory {
 	return &Memory{
 		vectorDB: vectorDB,
+		schemas:  newSchemaRegistry(),
+	}
+}
+
+// MemoryEvent describes a newly stored memory in published events
+type MemoryEvent struct {
+	ID         string     `json:"id"`
+	Type       MemoryType `json:"type"`
+	Content    string     `json:"content"`
+	Scope      string     `json:"scope,omitempty"`
+	Importance float32    `json:"importance"`
+	Pinned     bool       `json:"pinned"`
+	Source     string     `json:"source,omitempty"`
+	Timestamp  time.Time  `json:"timestamp"`
+}
+
+// SetEventBus sets the bus new memories are published to
+func (m *Memory) SetEventBus(bus *events.Bus) {
+	m.events.Store(bus)
+}
+
+// SetLogger sets the logger the memory layer logs to
+func (m *Memory) SetLogger(logger *slog.Logger) {
+	m.logger.Store(logger)
+}
+
+// log returns the memory layer's logger
+func (m *Memory) log() *slog.Logger {
+	if logger := m.logger.Load(); logger != nil {
+		return logger
 	}
+	return slog.Default()
 }
 
-// Store stores a memory with its embedding
-func (m *Memory) Store(ctx context.Context, record *MemoryRecord) error {
+// Store stores a memory with its embedding. Metadata is validated against
+// the memory type's schema and rejected with ErrInvalidMetadata on mismatch.
+// A memory repeating a recent one is handled by the DedupPolicy instead,
+// leaving record.ID set to the existing memory's.
+func (m *Memory) Store(ctx context.Context, record *MemoryRecord) (err error) {
+	ctx, span := startSpan(ctx, "memory.Store", record.Type)
+	defer func() { tracing.End(span, err) }()
+
+	if err := m.ValidateMetadata(record.Type, record.Metadata); err != nil {
+		return err
+	}
+
+	duplicate, err := m.absorbDuplicate(ctx, record)
+	if duplicate {
+		return err
+	}
+	if err != nil {
+		m.log().WarnContext(ctx, "failed to check for duplicate memories", "error", err)
+	}
+
+	if err := m.write(ctx, record); err != nil {
+		return err
+	}
+
+	source, _ := record.Metadata["content_source"].(string)
+	m.events.Load().Publish(events.MemoryCreated, MemoryEvent{
+		ID:         record.ID,
+		Type:       record.Type,
+		Content:    record.Content,
+		Scope:      record.Scope,
+		Importance: record.Importance,
+		Pinned:     record.Pinned,
+		Source:     source,
+		Timestamp:  record.Timestamp,
+	})
+	return nil
+}
+
+// write persists a memory record without publishing an event
+func (m *Memory) write(ctx context.Context, record *MemoryRecord) error {
+	if err := m.writable(); err != nil {
+		return err
+	}
+	if err := m.checkEmbedding(record.Embedding); err != nil {
+		return err
+	}
+
 	if record.Timestamp.IsZero() {
 		record.Timestamp = time.Now()
 	}
@@ -56,19 +160,21 @@ func (m *Memory) Store(ctx context.Context, record *MemoryRecord) error {
 
 	table := m.getTableForType(record.Type)
 
-	metadata := map[string]interface{}{
-		"content":    record.Content,
-		"timestamp":  record.Timestamp.Unix(),
-		"scope":      record.Scope,
-		"importance": record.Importance,
-		"type":       string(record.Type),
-	}
-
-	// Merge additional metadata
+	// Merge additional metadata; core fields below always win
+	metadata := make(map[string]interface{}, len(record.Metadata)+8)
 	for k, v := range record.Metadata {
 		metadata[k] = v
 	}
 
+	metadata["content"] = record.Content
+	metadata["timestamp"] = record.Timestamp.Unix()
+	metadata["scope"] = record.Scope
+	metadata["importance"] = record.Importance
+	metadata["type"] = string(record.Type)
+	metadata["pinned"] = record.Pinned
+	metadata["held"] = record.Held
+	metadata["schema_version"] = MetadataSchemaVersion
+
 	err := m.vectorDB.Store(ctx, table, record.ID, record.Embedding, metadata)
 	if err != nil {
 		return fmt.Errorf("failed to store memory: %w", err)
@@ -77,49 +183,40 @@ func (m *Memory) Store(ctx context.Context, record *MemoryRecord) error {
 	return nil
 }
 
-// Search searches for similar memories
-func (m *Memory) Search(ctx context.Context, queryEmbedding []float32, memoryType MemoryType, limit int) ([]MemoryRecord, error) {
+// Search searches for similar memories among those matching filter.
+// Long-term hits count as retrievals for retention reinforcement.
+func (m *Memory) Search(ctx context.Context, queryEmbedding []float32, memoryType MemoryType, limit int, filter SearchFilter) (_ []MemoryRecord, err error) {
+	ctx, span := startSpan(ctx, "memory.Search", memoryType)
+	defer func() { tracing.End(span, err) }()
+
 	table := m.getTableForType(memoryType)
 
-	results, err := m.vectorDB.Search(ctx, table, queryEmbedding, limit)
+	results, err := m.vectorDB.Search(ctx, table, queryEmbedding, limit, filter.storeFilter())
 	if err != nil {
 		return nil, fmt.Errorf("failed to search memories: %w", err)
 	}
 
 	var memories []MemoryRecord
+	ids := make([]string, 0, len(results))
 
 	for _, result := range results {
-		memory := MemoryRecord{
-			ID:        result.ID,
-			Embedding: result.Vector,
-			Metadata:  result.Metadata,
-		}
-
-		// Extract metadata
-		if content, ok := result.Metadata["content"].(string); ok {
-			memory.Content = content
-		}
-		if ts, ok := result.Metadata["timestamp"].(float64); ok {
-			memory.Timestamp = time.Unix(int64(ts), 0)
-		}
-		if scope, ok := result.Metadata["scope"].(string); ok {
-			memory.Scope = scope
-		}
-		if importance, ok := result.Metadata["importance"].(float64); ok {
-			memory.Importance = float32(importance)
-		}
-		if memType, ok := result.Metadata["type"].(string); ok {
-			memory.Type = MemoryType(memType)
-		}
-
-		memories = append(memories, memory)
+		record := recordFromStore(result.ID, result.Vector, result.Metadata)
+		record.Score = result.Score
+		memories = append(memories, record)
+		ids = append(ids, result.ID)
+	}
+	if table == vectordb.TableMemories {
+		m.accesses.record(ids)
 	}
 
 	return memories, nil
 }
 
 // Get retrieves a memory by ID
-func (m *Memory) Get(ctx context.Context, id string, memoryType MemoryType) (*MemoryRecord, error) {
+func (m *Memory) Get(ctx context.Context, id string, memoryType MemoryType) (_ *MemoryRecord, err error) {
+	ctx, span := startSpan(ctx, "memory.Get", memoryType)
+	defer func() { tracing.End(span, err) }()
+
 	table := m.getTableForType(memoryType)
 
 	record, err := m.vectorDB.Get(ctx, table, id)
@@ -127,37 +224,27 @@ func (m *Memory) Get(ctx context.Context, id string, memoryType MemoryType) (*Me
 		return nil, fmt.Errorf("failed to get memory: %w", err)
 	}
 
-	memory := &MemoryRecord{
-		ID:        record.ID,
-		Embedding: record.Vector,
-		Metadata:  record.Metadata,
-	}
-
-	// Extract metadata
-	if content, ok := record.Metadata["content"].(string); ok {
-		memory.Content = content
-	}
-	if ts, ok := record.Metadata["timestamp"].(float64); ok {
-		memory.Timestamp = time.Unix(int64(ts), 0)
-	}
-	if scope, ok := record.Metadata["scope"].(string); ok {
-		memory.Scope = scope
-	}
-	if importance, ok := record.Metadata["importance"].(float64); ok {
-		memory.Importance = float32(importance)
-	}
-	if memType, ok := record.Metadata["type"].(string); ok {
-		memory.Type = MemoryType(memType)
-	}
-
-	return memory, nil
+	memory := recordFromStore(record.ID, record.Vector, record.Metadata)
+	return &memory, nil
 }
 
-// Delete removes a memory
-func (m *Memory) Delete(ctx context.Context, id string, memoryType MemoryType) error {
+// Delete removes a memory. Memories under legal hold are refused with ErrHeld.
+func (m *Memory) Delete(ctx context.Context, id string, memoryType MemoryType) (err error) {
+	ctx, span := startSpan(ctx, "memory.Delete", memoryType)
+	defer func() { tracing.End(span, err) }()
+
+	if err := m.writable(); err != nil {
+		return err
+	}
 	table := m.getTableForType(memoryType)
 
-	err := m.vectorDB.Delete(ctx, table, id)
+	if record, err := m.vectorDB.Get(ctx, table, id); err == nil && record != nil {
+		if held, _ := record.Metadata["held"].(bool); held {
+			return fmt.Errorf("%w: %s", ErrHeld, id)
+		}
+	}
+
+	err = m.vectorDB.Delete(ctx, table, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete memory: %w", err)
 	}
@@ -166,7 +253,10 @@ func (m *Memory) Delete(ctx context.Context, id string, memoryType MemoryType) e
 }
 
 // List retrieves memories with pagination
-func (m *Memory) List(ctx context.Context, memoryType MemoryType, limit, offset int) ([]MemoryRecord, error) {
+func (m *Memory) List(ctx context.Context, memoryType MemoryType, limit, offset int) (_ []MemoryRecord, err error) {
+	ctx, span := startSpan(ctx, "memory.List", memoryType)
+	defer func() { tracing.End(span, err) }()
+
 	table := m.getTableForType(memoryType)
 
 	records, err := m.vectorDB.List(ctx, table, limit, offset)
@@ -177,33 +267,37 @@ func (m *Memory) List(ctx context.Context, memoryType MemoryType, limit, offset
 	var memories []MemoryRecord
 
 	for _, record := range records {
-		memory := MemoryRecord{
-			ID:        record.ID,
-			Embedding: record.Vector,
-			Metadata:  record.Metadata,
-		}
+		memories = append(memories, recordFromStore(record.ID, record.Vector, record.Metadata))
+	}
 
-		// Extract metadata
-		if content, ok := record.Metadata["content"].(string); ok {
-			memory.Content = content
-		}
-		if ts, ok := record.Metadata["timestamp"].(float64); ok {
-			memory.Timestamp = time.Unix(int64(ts), 0)
-		}
-		if scope, ok := record.Metadata["scope"].(string); ok {
-			memory.Scope = scope
-		}
-		if importance, ok := record.Metadata["importance"].(float64); ok {
-			memory.Importance = float32(importance)
-		}
-		if memType, ok := record.Metadata["type"].(string); ok {
-			memory.Type = MemoryType(memType)
-		}
+	return memories, nil
+}
 
-		memories = append(memories, memory)
+// Count returns the number of memories of a type
+func (m *Memory) Count(ctx context.Context, memoryType MemoryType) (int, error) {
+	count, err := vectordb.Count(ctx, m.vectorDB, m.getTableForType(memoryType))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count memories: %w", err)
 	}
+	return count, nil
+}
 
-	return memories, nil
+// Ping checks the vector database answers a query
+func (m *Memory) Ping(ctx context.Context) error {
+	if _, err := m.vectorDB.List(ctx, m.getTableForType(MemoryTypeLongTerm), 1, 0); err != nil {
+		return fmt.Errorf("failed to query memories: %w", err)
+	}
+	return nil
+}
+
+// Each streams every memory of a type to fn, newest first, without loading
+// the whole table into memory. Returning an error from fn stops the iteration.
+func (m *Memory) Each(ctx context.Context, memoryType MemoryType, fn func(MemoryRecord) error) error {
+	table := m.getTableForType(memoryType)
+
+	return vectordb.Iterate(ctx, m.vectorDB, table, func(record vectordb.Record) error {
+		return fn(recordFromStore(record.ID, record.Vector, record.Metadata))
+	})
 }
 
 // GetVectorDB returns the underlying vector database
@@ -212,6 +306,73 @@ func (m *Memory) GetVectorDB() vectordb.VectorDB {
 	return m.vectorDB
 }
 
+// recordFromStore rebuilds a MemoryRecord from a stored vector and its
+// metadata, migrating older metadata to the current schema version.
+// Core fields are lifted out so Metadata only holds schema fields.
+func recordFromStore(id string, vector []float32, metadata map[string]interface{}) MemoryRecord {
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	migrateMetadata(metadata)
+
+	extra := make(map[string]interface{}, len(metadata))
+	for k, v := range metadata {
+		if !reservedMetadataKeys[k] {
+			extra[k] = v
+		}
+	}
+
+	memory := MemoryRecord{
+		ID:        id,
+		Embedding: vector,
+		Metadata:  extra,
+	}
+
+	// Extract metadata
+	if content, ok := metadata["content"].(string); ok {
+		memory.Content = content
+	}
+	if ts, ok := metadataNumber(metadata["timestamp"]); ok {
+		memory.Timestamp = time.Unix(int64(ts), 0)
+	}
+	if scope, ok := metadata["scope"].(string); ok {
+		memory.Scope = scope
+	}
+	switch importance := metadata["importance"].(type) {
+	case float64:
+		memory.Importance = float32(importance)
+	case float32: // Backends that keep metadata in memory skip the JSON round trip
+		memory.Importance = importance
+	}
+	if memType, ok := metadata["type"].(string); ok {
+		memory.Type = MemoryType(memType)
+	}
+	if pinned, ok := metadata["pinned"].(bool); ok {
+		memory.Pinned = pinned
+	}
+	if held, ok := metadata["held"].(bool); ok {
+		memory.Held = held
+	}
+
+	return memory
+}
+
+// metadataNumber reads a numeric metadata value, which is a float64 after a
+// JSON round trip but keeps its Go type in backends that skip it
+func metadataNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
 // getTableForType maps memory type to vector database table
 func (m *Memory) getTableForType(memoryType MemoryType) string {
 	switch memoryType {
@@ -219,6 +380,8 @@ func (m *Memory) getTableForType(memoryType MemoryType) string {
 		return vectordb.TableMusings
 	case MemoryTypePersonality:
 		return vectordb.TablePersonality
+	case MemoryTypeArchived:
+		return vectordb.TableArchive
 	default:
 		return vectordb.TableMemories
 	}
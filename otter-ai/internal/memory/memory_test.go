@@ -3,12 +3,26 @@ package memory
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"otter-ai/internal/events"
 	"otter-ai/internal/vectordb"
 )
 
+// newTestSQLiteDB opens a SQLite store in a temporary directory, closed when
+// the test ends
+func newTestSQLiteDB(t *testing.T) *vectordb.SQLiteVectorDB {
+	t.Helper()
+	db, err := vectordb.NewSQLiteVectorDB(filepath.Join(t.TempDir(), "otter.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
 // --- Mock VectorDB ---
 
 type mockVectorDB struct {
@@ -21,6 +35,11 @@ func newMockVectorDB() *mockVectorDB {
 			vectordb.TableMemories:    {},
 			vectordb.TableMusings:     {},
 			vectordb.TablePersonality: {},
+			vectordb.TableSessions:    {},
+			vectordb.TableArchive:     {},
+			vectordb.TableOnboarding:  {},
+			vectordb.TableDelegations: {},
+			vectordb.TableSchedule:    {},
 		},
 	}
 }
@@ -37,12 +56,15 @@ func (m *mockVectorDB) Store(ctx context.Context, table, id string, vector []flo
 	return nil
 }
 
-func (m *mockVectorDB) Search(ctx context.Context, table string, query []float32, limit int) ([]vectordb.SearchResult, error) {
+func (m *mockVectorDB) Search(ctx context.Context, table string, query []float32, limit int, filter vectordb.Filter) ([]vectordb.SearchResult, error) {
 	if err := vectordb.ValidateTable(table); err != nil {
 		return nil, err
 	}
 	var results []vectordb.SearchResult
 	for _, rec := range m.records[table] {
+		if !filter.Matches(rec.Metadata) {
+			continue
+		}
 		results = append(results, vectordb.SearchResult{
 			ID:       rec.ID,
 			Vector:   rec.Vector,
@@ -165,6 +187,9 @@ func TestStore_MetadataMerge(t *testing.T) {
 	db := newMockVectorDB()
 	mem := New(db)
 	ctx := context.Background()
+	if err := mem.RegisterMetadataField(MemoryTypeLongTerm, "custom", FieldSpec{Kind: KindString}); err != nil {
+		t.Fatal(err)
+	}
 
 	rec := &MemoryRecord{
 		ID:        "m1",
@@ -236,7 +261,7 @@ func TestSearch(t *testing.T) {
 	}
 	_ = mem.Store(ctx, rec)
 
-	results, err := mem.Search(ctx, []float32{1, 0}, MemoryTypeLongTerm, 5)
+	results, err := mem.Search(ctx, []float32{1, 0}, MemoryTypeLongTerm, 5, SearchFilter{})
 	if err != nil {
 		t.Fatalf("Search: %v", err)
 	}
@@ -341,6 +366,37 @@ func TestList_Empty(t *testing.T) {
 	}
 }
 
+func TestEach(t *testing.T) {
+	db := newMockVectorDB()
+	mem := New(db)
+	ctx := context.Background()
+
+	for i := 0; i < 4; i++ {
+		_ = mem.Store(ctx, &MemoryRecord{
+			ID:        fmt.Sprintf("e%d", i),
+			Type:      MemoryTypeLongTerm,
+			Content:   fmt.Sprintf("item %d", i),
+			Embedding: []float32{float32(i)},
+			Timestamp: time.Now(),
+		})
+	}
+
+	seen := make(map[string]bool)
+	err := mem.Each(ctx, MemoryTypeLongTerm, func(record MemoryRecord) error {
+		if record.Content == "" {
+			t.Errorf("record %s has no content", record.ID)
+		}
+		seen[record.ID] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Each: %v", err)
+	}
+	if len(seen) != 4 {
+		t.Errorf("expected 4 records, got %d", len(seen))
+	}
+}
+
 func TestGenerateMemoryID_Deterministic(t *testing.T) {
 	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 	r1 := &MemoryRecord{Type: MemoryTypeLongTerm, Content: "hello", Timestamp: ts}
@@ -380,3 +436,35 @@ func TestMemoryTypeConstants(t *testing.T) {
 		t.Errorf("MemoryTypePersonality = %q", MemoryTypePersonality)
 	}
 }
+
+func TestStore_PublishesEvent(t *testing.T) {
+	mem := New(newMockVectorDB())
+	bus := events.NewBus()
+	mem.SetEventBus(bus)
+	sub := bus.Subscribe(0)
+	defer sub.Close()
+
+	rec := &MemoryRecord{Type: MemoryTypeLongTerm, Content: "hello", Metadata: map[string]interface{}{"content_source": "pin"}}
+	if err := mem.Store(context.Background(), rec); err != nil {
+		t.Fatal(err)
+	}
+
+	event := <-sub.C
+	data, ok := event.Data.(MemoryEvent)
+	if event.Type != events.MemoryCreated || !ok {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+	if data.ID != rec.ID || data.Content != "hello" || data.Source != "pin" {
+		t.Errorf("unexpected memory event: %+v", data)
+	}
+
+	// Updating the pin flag is not a new memory
+	if _, err := mem.SetPinned(context.Background(), rec.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case event := <-sub.C:
+		t.Errorf("unexpected event on update: %+v", event)
+	default:
+	}
+}
@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"otter-ai/internal/errs"
+	"otter-ai/internal/vectordb"
+)
+
+// ErrOnboardingNotFound is returned for unknown onboarding records
+var ErrOnboardingNotFound = errs.New(errs.ErrNotFound, "onboarding not found")
+
+// OnboardingStep is one step of a member's onboarding
+type OnboardingStep struct {
+	ID          string     `json:"id"`
+	SentAt      *time.Time `json:"sent_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// OnboardingRecord tracks a new raft member's progress through onboarding
+type OnboardingRecord struct {
+	ID          string           `json:"id"`
+	RaftID      string           `json:"raft_id"`
+	MemberID    string           `json:"member_id"`
+	InductedBy  string           `json:"inducted_by,omitempty"`
+	Platform    string           `json:"platform,omitempty"` // Where steps are sent; empty when the member has no contact
+	UserID      string           `json:"user_id,omitempty"`
+	Steps       []OnboardingStep `json:"steps"`
+	StartedAt   time.Time        `json:"started_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+	CompletedAt *time.Time       `json:"completed_at,omitempty"`
+	LastError   string           `json:"last_error,omitempty"` // Why the latest step could not be sent
+}
+
+// SaveOnboarding persists an onboarding record, replacing any previous copy
+func (m *Memory) SaveOnboarding(ctx context.Context, record *OnboardingRecord) error {
+	if record.ID == "" {
+		return fmt.Errorf("onboarding ID is required")
+	}
+	if record.StartedAt.IsZero() {
+		record.StartedAt = time.Now()
+	}
+	record.UpdatedAt = time.Now()
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal onboarding: %w", err)
+	}
+
+	metadata := map[string]interface{}{
+		"type":       "onboarding",
+		"onboarding": string(data),
+		"updated_at": record.UpdatedAt.Unix(),
+	}
+
+	if err := m.vectorDB.Store(ctx, vectordb.TableOnboarding, record.ID, nil, metadata); err != nil {
+		return fmt.Errorf("failed to store onboarding: %w", err)
+	}
+	return nil
+}
+
+// LoadOnboarding retrieves an onboarding record by ID
+func (m *Memory) LoadOnboarding(ctx context.Context, id string) (*OnboardingRecord, error) {
+	// Backends report missing records as plain errors
+	stored, err := m.vectorDB.Get(ctx, vectordb.TableOnboarding, id)
+	if err != nil || stored == nil {
+		return nil, fmt.Errorf("%w: %s", ErrOnboardingNotFound, id)
+	}
+	return decodeOnboarding(stored.Metadata)
+}
+
+// ListOnboardings returns onboarding records, most recently updated first
+func (m *Memory) ListOnboardings(ctx context.Context, limit, offset int) ([]OnboardingRecord, error) {
+	stored, err := m.vectorDB.List(ctx, vectordb.TableOnboarding, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list onboardings: %w", err)
+	}
+
+	records := make([]OnboardingRecord, 0, len(stored))
+	for _, s := range stored {
+		record, err := decodeOnboarding(s.Metadata)
+		if err != nil {
+			continue // Skip corrupt entries rather than failing the whole listing
+		}
+		records = append(records, *record)
+	}
+	return records, nil
+}
+
+// decodeOnboarding extracts an OnboardingRecord from stored metadata
+func decodeOnboarding(metadata map[string]interface{}) (*OnboardingRecord, error) {
+	raw, ok := metadata["onboarding"].(string)
+	if !ok || raw == "" {
+		return nil, fmt.Errorf("onboarding payload missing")
+	}
+
+	var record OnboardingRecord
+	if err := json.Unmarshal([]byte(raw), &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal onboarding: %w", err)
+	}
+	return &record, nil
+}
@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSaveAndLoadOnboarding(t *testing.T) {
+	mem := New(newMockVectorDB())
+	ctx := context.Background()
+
+	in := &OnboardingRecord{
+		ID: "raft-1:otter-2", RaftID: "raft-1", MemberID: "otter-2",
+		Steps: []OnboardingStep{{ID: "welcome"}, {ID: "rules"}},
+	}
+	if err := mem.SaveOnboarding(ctx, in); err != nil {
+		t.Fatalf("SaveOnboarding: %v", err)
+	}
+
+	out, err := mem.LoadOnboarding(ctx, in.ID)
+	if err != nil {
+		t.Fatalf("LoadOnboarding: %v", err)
+	}
+	if out.MemberID != "otter-2" || len(out.Steps) != 2 || out.StartedAt.IsZero() {
+		t.Errorf("LoadOnboarding = %+v", out)
+	}
+
+	if _, err := mem.LoadOnboarding(ctx, "missing"); !errors.Is(err, ErrOnboardingNotFound) {
+		t.Errorf("expected ErrOnboardingNotFound, got %v", err)
+	}
+	if err := mem.SaveOnboarding(ctx, &OnboardingRecord{}); err == nil {
+		t.Error("expected error for empty onboarding ID")
+	}
+}
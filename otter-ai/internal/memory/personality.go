@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// PersonalityTrait is one trait of the agent's personality. Its vector is
+// the embedding of its description, which interactions are compared with.
+type PersonalityTrait struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Strength    float32   `json:"strength"` // 0 to 1
+	Baseline    float32   `json:"baseline"` // Seeded strength that drift is pulled back toward
+	Vector      []float32 `json:"-"`
+	EvolvedAt   time.Time `json:"evolved_at"`
+}
+
+// traitID is the memory ID a trait is stored under
+func traitID(name string) string {
+	return "trait:" + name
+}
+
+// SaveTrait stores a personality trait, replacing any previous version
+func (m *Memory) SaveTrait(ctx context.Context, trait *PersonalityTrait) error {
+	if trait.Name == "" {
+		return fmt.Errorf("trait name is required")
+	}
+	if trait.EvolvedAt.IsZero() {
+		trait.EvolvedAt = time.Now()
+	}
+
+	record := &MemoryRecord{
+		ID:         traitID(trait.Name),
+		Type:       MemoryTypePersonality,
+		Content:    trait.Description,
+		Embedding:  trait.Vector,
+		Importance: trait.Strength,
+		Timestamp:  trait.EvolvedAt,
+		Metadata: map[string]interface{}{
+			"trait":          trait.Name,
+			"baseline":       trait.Baseline,
+			"content_source": SourceAgentGenerated,
+		},
+	}
+	if err := m.ValidateMetadata(record.Type, record.Metadata); err != nil {
+		return err
+	}
+	return m.write(ctx, record)
+}
+
+// ListTraits returns the personality traits, by name
+func (m *Memory) ListTraits(ctx context.Context) ([]PersonalityTrait, error) {
+	var traits []PersonalityTrait
+	err := m.Each(ctx, MemoryTypePersonality, func(record MemoryRecord) error {
+		name, _ := record.Metadata["trait"].(string)
+		if name == "" {
+			return nil // Not a trait
+		}
+		baseline, _ := metadataNumber(record.Metadata["baseline"])
+		traits = append(traits, PersonalityTrait{
+			Name:        name,
+			Description: record.Content,
+			Strength:    record.Importance,
+			Baseline:    float32(baseline),
+			Vector:      record.Embedding,
+			EvolvedAt:   record.Timestamp,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list traits: %w", err)
+	}
+
+	sort.Slice(traits, func(i, j int) bool { return traits[i].Name < traits[j].Name })
+	return traits, nil
+}
@@ -0,0 +1,39 @@
+package memory
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSaveAndListTraits(t *testing.T) {
+	mem := New(newMockVectorDB())
+	ctx := context.Background()
+
+	for _, trait := range []*PersonalityTrait{
+		{Name: "playful", Description: "light humour", Strength: 0.4, Baseline: 0.4},
+		{Name: "curious", Description: "explores ideas", Strength: 0.6, Baseline: 0.5, Vector: []float32{1, 0}},
+	} {
+		if err := mem.SaveTrait(ctx, trait); err != nil {
+			t.Fatalf("SaveTrait: %v", err)
+		}
+	}
+	// Saving again replaces the trait
+	if err := mem.SaveTrait(ctx, &PersonalityTrait{Name: "playful", Description: "light humour", Strength: 0.3, Baseline: 0.4}); err != nil {
+		t.Fatal(err)
+	}
+
+	traits, err := mem.ListTraits(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(traits) != 2 || traits[0].Name != "curious" || traits[1].Strength != 0.3 {
+		t.Fatalf("ListTraits = %+v", traits)
+	}
+	if traits[0].Baseline != 0.5 || len(traits[0].Vector) != 2 || traits[0].EvolvedAt.IsZero() {
+		t.Errorf("trait fields not round-tripped: %+v", traits[0])
+	}
+
+	if err := mem.SaveTrait(ctx, &PersonalityTrait{Description: "nameless"}); err == nil {
+		t.Error("expected error for a trait without a name")
+	}
+}
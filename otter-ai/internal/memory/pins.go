@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"otter-ai/internal/errs"
+	"otter-ai/internal/vectordb"
+)
+
+// Pinning configuration
+const (
+	MaxImportance   = 1.0
+	MaxPinnedListed = 500
+	pinScanBatch    = 200
+)
+
+// Pin stores a long-term memory at maximum importance and marks it pinned
+func (m *Memory) Pin(ctx context.Context, record *MemoryRecord) error {
+	record.Type = MemoryTypeLongTerm
+	record.Pinned = true
+	record.Importance = MaxImportance
+	return m.Store(ctx, record)
+}
+
+// SetPinned pins or unpins an existing long-term memory
+func (m *Memory) SetPinned(ctx context.Context, id string, pinned bool) (*MemoryRecord, error) {
+	record, err := m.vectorDB.Get(ctx, vectordb.TableMemories, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get memory: %w", err)
+	}
+	if record == nil {
+		return nil, errs.Errorf(errs.ErrNotFound, "memory not found: %s", id)
+	}
+
+	memory := recordFromStore(record.ID, record.Vector, record.Metadata)
+	memory.Type = MemoryTypeLongTerm
+	memory.Pinned = pinned
+	if pinned {
+		memory.Importance = MaxImportance
+	}
+
+	if err := m.write(ctx, &memory); err != nil {
+		return nil, err
+	}
+	return &memory, nil
+}
+
+// ListPinned returns pinned long-term memories, up to limit
+func (m *Memory) ListPinned(ctx context.Context, limit int) ([]MemoryRecord, error) {
+	if limit <= 0 || limit > MaxPinnedListed {
+		limit = MaxPinnedListed
+	}
+
+	var pinned []MemoryRecord
+	for offset := 0; ; offset += pinScanBatch {
+		records, err := m.vectorDB.List(ctx, vectordb.TableMemories, pinScanBatch, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list memories: %w", err)
+		}
+
+		for _, record := range records {
+			memory := recordFromStore(record.ID, record.Vector, record.Metadata)
+			if memory.Pinned {
+				pinned = append(pinned, memory)
+				if len(pinned) >= limit {
+					return pinned, nil
+				}
+			}
+		}
+
+		if len(records) < pinScanBatch {
+			return pinned, nil
+		}
+	}
+}
+
+// Prunable reports whether automatic decay or pruning may touch the memory.
+// Retention jobs must check this before lowering importance or deleting.
+// Memories under legal hold are never prunable.
+func (r *MemoryRecord) Prunable() bool {
+	return !r.Pinned && !r.Held
+}
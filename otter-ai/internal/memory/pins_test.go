@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPin_SetsMaxImportance(t *testing.T) {
+	db := newMockVectorDB()
+	mem := New(db)
+	ctx := context.Background()
+
+	record := &MemoryRecord{Content: "my cat is called Kelp", Importance: 0.2}
+	if err := mem.Pin(ctx, record); err != nil {
+		t.Fatalf("Pin: %v", err)
+	}
+
+	got, err := mem.Get(ctx, record.ID, MemoryTypeLongTerm)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !got.Pinned {
+		t.Error("expected memory to be pinned")
+	}
+	if got.Importance != MaxImportance {
+		t.Errorf("importance = %v, want %v", got.Importance, MaxImportance)
+	}
+	if got.Prunable() {
+		t.Error("pinned memory should not be prunable")
+	}
+}
+
+func TestSetPinned_Unpin(t *testing.T) {
+	mem := New(newMockVectorDB())
+	ctx := context.Background()
+
+	record := &MemoryRecord{Content: "fact", Metadata: map[string]interface{}{"content_source": "pin"}}
+	_ = mem.Pin(ctx, record)
+
+	updated, err := mem.SetPinned(ctx, record.ID, false)
+	if err != nil {
+		t.Fatalf("SetPinned: %v", err)
+	}
+	if updated.Pinned {
+		t.Error("expected memory to be unpinned")
+	}
+
+	got, _ := mem.Get(ctx, record.ID, MemoryTypeLongTerm)
+	if got.Pinned {
+		t.Error("stored memory still pinned")
+	}
+	if got.Metadata["content_source"] != "pin" {
+		t.Errorf("expected extra metadata preserved, got %v", got.Metadata["content_source"])
+	}
+}
+
+func TestSetPinned_NotFound(t *testing.T) {
+	mem := New(newMockVectorDB())
+	if _, err := mem.SetPinned(context.Background(), "missing", true); err == nil {
+		t.Error("expected error for missing memory")
+	}
+}
+
+func TestListPinned_FiltersUnpinned(t *testing.T) {
+	mem := New(newMockVectorDB())
+	ctx := context.Background()
+
+	_ = mem.Store(ctx, &MemoryRecord{Type: MemoryTypeLongTerm, Content: "ordinary"})
+	_ = mem.Pin(ctx, &MemoryRecord{Content: "pinned one"})
+	_ = mem.Pin(ctx, &MemoryRecord{Content: "pinned two"})
+
+	pinned, err := mem.ListPinned(ctx, 0)
+	if err != nil {
+		t.Fatalf("ListPinned: %v", err)
+	}
+	if len(pinned) != 2 {
+		t.Errorf("expected 2 pinned memories, got %d", len(pinned))
+	}
+
+	limited, _ := mem.ListPinned(ctx, 1)
+	if len(limited) != 1 {
+		t.Errorf("expected limit to apply, got %d", len(limited))
+	}
+}
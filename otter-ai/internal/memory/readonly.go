@@ -0,0 +1,26 @@
+package memory
+
+import "otter-ai/internal/errs"
+
+// ErrReadOnly is returned for memory writes while the memory layer is
+// read-only
+var ErrReadOnly = errs.New(errs.ErrUnavailable, "memory is read-only")
+
+// SetReadOnly refuses or allows memory writes. Reads, sessions and other
+// bookkeeping records are unaffected.
+func (m *Memory) SetReadOnly(readOnly bool) {
+	m.readOnly.Store(readOnly)
+}
+
+// ReadOnly reports whether memory writes are refused
+func (m *Memory) ReadOnly() bool {
+	return m.readOnly.Load()
+}
+
+// writable returns ErrReadOnly while memory writes are refused
+func (m *Memory) writable() error {
+	if m.readOnly.Load() {
+		return ErrReadOnly
+	}
+	return nil
+}
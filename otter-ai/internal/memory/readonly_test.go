@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"otter-ai/internal/errs"
+)
+
+func TestReadOnly(t *testing.T) {
+	mem := New(newMockVectorDB())
+	ctx := context.Background()
+	kept := &MemoryRecord{Type: MemoryTypeLongTerm, Content: "otters hold hands while sleeping"}
+	if err := mem.Store(ctx, kept); err != nil {
+		t.Fatal(err)
+	}
+
+	mem.SetReadOnly(true)
+	if !mem.ReadOnly() {
+		t.Fatal("ReadOnly() = false after SetReadOnly(true)")
+	}
+	err := mem.Store(ctx, &MemoryRecord{Type: MemoryTypeLongTerm, Content: "otters use rocks as tools"})
+	if !errors.Is(err, ErrReadOnly) || !errors.Is(err, errs.ErrUnavailable) {
+		t.Errorf("Store: err = %v, want ErrReadOnly", err)
+	}
+	if err := mem.Delete(ctx, kept.ID, MemoryTypeLongTerm); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Delete: err = %v, want ErrReadOnly", err)
+	}
+	if _, err := mem.Forget(ctx, ForgetCriteria{Match: "otters"}, false); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Forget: err = %v, want ErrReadOnly", err)
+	}
+	if _, err := mem.Forget(ctx, ForgetCriteria{Match: "otters"}, true); err != nil {
+		t.Errorf("a dry run writes nothing and should be allowed: %v", err)
+	}
+	if _, err := mem.Get(ctx, kept.ID, MemoryTypeLongTerm); err != nil {
+		t.Errorf("reads should still work: %v", err)
+	}
+
+	mem.SetReadOnly(false)
+	if err := mem.Delete(ctx, kept.ID, MemoryTypeLongTerm); err != nil {
+		t.Errorf("Delete after leaving read-only mode: %v", err)
+	}
+}
@@ -0,0 +1,182 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// retentionEpsilon is the smallest importance change worth rewriting a
+// memory for. Smaller decay is carried over to the next run.
+const retentionEpsilon = 0.005
+
+// RetentionPolicy tunes importance decay and forgetting of long-term memories
+type RetentionPolicy struct {
+	HalfLife      time.Duration // Time for an unretrieved memory's importance to halve; zero disables decay
+	Floor         float32       // Memories that decay below this importance are forgotten
+	Demote        bool          // Archive forgotten memories instead of deleting them
+	Reinforcement float32       // Importance added per retrieval since the previous run
+	MinAge        time.Duration // Younger memories neither decay nor get forgotten
+}
+
+// RetentionResult reports what one retention run did
+type RetentionResult struct {
+	Scanned    int // Memories considered
+	Decayed    int // Memories whose importance was lowered
+	Reinforced int // Memories whose importance was raised by retrievals
+	Forgotten  int // Memories deleted or archived for falling below the floor
+}
+
+// accessTracker counts search hits per memory between retention runs
+type accessTracker struct {
+	counts map[string]int
+	mu     sync.Mutex
+}
+
+func (t *accessTracker) record(ids []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.counts == nil {
+		t.counts = make(map[string]int)
+	}
+	for _, id := range ids {
+		t.counts[id]++
+	}
+}
+
+// drain returns the counts so far and starts counting afresh
+func (t *accessTracker) drain() map[string]int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	counts := t.counts
+	t.counts = nil
+	return counts
+}
+
+// restore adds back counts a failed run did not apply
+func (t *accessTracker) restore(counts map[string]int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.counts == nil {
+		t.counts = make(map[string]int)
+	}
+	for id, n := range counts {
+		t.counts[id] += n
+	}
+}
+
+// SetRetentionPolicy sets the policy RunRetention applies
+func (m *Memory) SetRetentionPolicy(policy RetentionPolicy) {
+	m.retention.Store(&policy)
+}
+
+// RetentionPolicy returns the policy RunRetention applies, reporting
+// whether one was set
+func (m *Memory) RetentionPolicy() (RetentionPolicy, bool) {
+	policy := m.retention.Load()
+	if policy == nil {
+		return RetentionPolicy{}, false
+	}
+	return *policy, true
+}
+
+// RunRetention decays the importance of long-term memories, reinforces
+// those retrieved by Search since the previous run and forgets memories
+// that fall below the policy floor. Pinned and held memories are never
+// touched. Decay is measured from the last time a memory was decayed or
+// reinforced, so runs can happen at any interval.
+func (m *Memory) RunRetention(ctx context.Context) (*RetentionResult, error) {
+	policy := m.retention.Load()
+	if policy == nil {
+		return &RetentionResult{}, nil
+	}
+	// Retrievals keep counting towards the next run
+	if err := m.writable(); err != nil {
+		return nil, err
+	}
+
+	hits := m.accesses.drain()
+	now := time.Now()
+	youngest := now.Add(-policy.MinAge)
+
+	type change struct {
+		record MemoryRecord
+		forget bool
+	}
+	var changes []change
+	result := &RetentionResult{}
+
+	err := m.Each(ctx, MemoryTypeLongTerm, func(record MemoryRecord) error {
+		if !record.Prunable() {
+			return nil
+		}
+		result.Scanned++
+
+		importance := record.Importance
+		if policy.HalfLife > 0 && record.Timestamp.Before(youngest) {
+			since := record.Timestamp
+			if decayedAt, ok := metadataNumber(record.Metadata["decayed_at"]); ok {
+				since = time.Unix(int64(decayedAt), 0)
+			}
+			if elapsed := now.Sub(since); elapsed > 0 {
+				importance *= float32(math.Pow(0.5, float64(elapsed)/float64(policy.HalfLife)))
+			}
+		}
+
+		n := hits[record.ID]
+		if n > 0 {
+			importance = float32(math.Min(MaxImportance, float64(importance+policy.Reinforcement*float32(n))))
+			count, _ := metadataNumber(record.Metadata["access_count"])
+			record.Metadata["access_count"] = count + float64(n)
+			record.Metadata["last_accessed"] = now.Unix()
+		}
+
+		forget := n == 0 && importance < policy.Floor && record.Timestamp.Before(youngest)
+		if !forget && n == 0 && math.Abs(float64(record.Importance-importance)) < retentionEpsilon {
+			return nil
+		}
+
+		switch {
+		case forget:
+		case importance < record.Importance:
+			result.Decayed++
+		case n > 0:
+			result.Reinforced++
+		}
+		record.Importance = importance
+		record.Metadata["decayed_at"] = now.Unix()
+		changes = append(changes, change{record: record, forget: forget})
+		return nil
+	})
+	if err != nil {
+		m.accesses.restore(hits)
+		return nil, fmt.Errorf("failed to scan memories: %w", err)
+	}
+
+	for _, c := range changes {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		record := c.record
+		switch {
+		case c.forget && policy.Demote:
+			err = m.archive(ctx, &record, map[string]interface{}{"archive_reason": ArchiveReasonDecayed})
+		case c.forget:
+			err = m.Delete(ctx, record.ID, MemoryTypeLongTerm)
+		default:
+			err = m.write(ctx, &record)
+		}
+		if err != nil {
+			m.log().WarnContext(ctx, "retention failed for memory", "memory_id", record.ID, "error", err)
+			continue
+		}
+		if c.forget {
+			result.Forgotten++
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,119 @@
+package memory
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func storeAged(t *testing.T, m *Memory, content string, age time.Duration, importance float32) *MemoryRecord {
+	t.Helper()
+	record := &MemoryRecord{
+		Type:       MemoryTypeLongTerm,
+		Content:    content,
+		Embedding:  []float32{1, 0},
+		Timestamp:  time.Now().Add(-age),
+		Importance: importance,
+	}
+	if err := m.Store(context.Background(), record); err != nil {
+		t.Fatal(err)
+	}
+	return record
+}
+
+func TestRunRetention_DecaysAndForgets(t *testing.T) {
+	db := newMockVectorDB()
+	m := New(db)
+	m.SetRetentionPolicy(RetentionPolicy{HalfLife: 10 * 24 * time.Hour, Floor: 0.1, MinAge: 24 * time.Hour})
+	ctx := context.Background()
+
+	old := storeAged(t, m, "old", 10*24*time.Hour, 0.8)
+	faded := storeAged(t, m, "faded", 30*24*time.Hour, 0.5)
+	fresh := storeAged(t, m, "fresh", time.Hour, 0.05)
+	pinned := storeAged(t, m, "pinned", 365*24*time.Hour, 0.5)
+	m.SetPinned(ctx, pinned.ID, true)
+
+	result, err := m.RunRetention(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Scanned != 3 || result.Decayed != 1 || result.Forgotten != 1 {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	got, err := m.Get(ctx, old.ID, MemoryTypeLongTerm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(float64(got.Importance)-0.4) > 0.01 {
+		t.Errorf("importance after one half-life = %v, want 0.4", got.Importance)
+	}
+	if _, err := m.Get(ctx, faded.ID, MemoryTypeLongTerm); err == nil {
+		t.Error("memory below the floor should be forgotten")
+	}
+	if _, err := m.Get(ctx, fresh.ID, MemoryTypeLongTerm); err != nil {
+		t.Error("memories younger than MinAge should be kept")
+	}
+	if got, _ := m.Get(ctx, pinned.ID, MemoryTypeLongTerm); got.Importance != MaxImportance {
+		t.Errorf("pinned memory changed: %+v", got)
+	}
+
+	// A second run right away has nothing left to decay
+	result, _ = m.RunRetention(ctx)
+	if result.Decayed != 0 {
+		t.Errorf("decay should be measured from the previous run, got %+v", result)
+	}
+}
+
+func TestRunRetention_Reinforces(t *testing.T) {
+	m := New(newMockVectorDB())
+	m.SetRetentionPolicy(RetentionPolicy{HalfLife: 10 * 24 * time.Hour, Floor: 0.1, Reinforcement: 0.1})
+	ctx := context.Background()
+
+	record := storeAged(t, m, "recalled", 30*24*time.Hour, 0.05)
+	m.Search(ctx, []float32{1, 0}, MemoryTypeLongTerm, 5, SearchFilter{})
+	m.Search(ctx, []float32{1, 0}, MemoryTypeLongTerm, 5, SearchFilter{})
+
+	result, err := m.RunRetention(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Reinforced != 1 || result.Forgotten != 0 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	got, err := m.Get(ctx, record.ID, MemoryTypeLongTerm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Importance < 0.2 || got.Metadata["access_count"] != float64(2) {
+		t.Errorf("unexpected reinforced memory %+v", got)
+	}
+}
+
+func TestRunRetention_DemotesToArchive(t *testing.T) {
+	m := New(newMockVectorDB())
+	m.SetRetentionPolicy(RetentionPolicy{HalfLife: 24 * time.Hour, Floor: 0.1, Demote: true})
+	ctx := context.Background()
+
+	record := storeAged(t, m, "faded", 30*24*time.Hour, 0.5)
+	if _, err := m.RunRetention(ctx); err != nil {
+		t.Fatal(err)
+	}
+	archived, err := m.Get(ctx, record.ID, MemoryTypeArchived)
+	if err != nil {
+		t.Fatalf("demoted memory should be archived: %v", err)
+	}
+	if archived.Metadata["archive_reason"] != ArchiveReasonDecayed {
+		t.Errorf("archive_reason = %v", archived.Metadata["archive_reason"])
+	}
+}
+
+func TestRunRetention_NoPolicy(t *testing.T) {
+	m := New(newMockVectorDB())
+	storeAged(t, m, "old", 365*24*time.Hour, 0.01)
+	result, err := m.RunRetention(context.Background())
+	if err != nil || result.Scanned != 0 {
+		t.Errorf("without a policy nothing should run: %+v, %v", result, err)
+	}
+}
@@ -0,0 +1,133 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"otter-ai/internal/errs"
+	"otter-ai/internal/vectordb"
+)
+
+// ErrScheduledNotFound is returned for unknown scheduled messages
+var ErrScheduledNotFound = errs.New(errs.ErrNotFound, "scheduled message not found")
+
+// MaxScheduledMessages bounds how many scheduled messages are listed
+const MaxScheduledMessages = 10000
+
+// Scheduled message statuses
+const (
+	ScheduledPending  = "pending"
+	ScheduledSent     = "sent"
+	ScheduledFailed   = "failed"   // Gave up after repeated delivery errors
+	ScheduledCanceled = "canceled" // Canceled, or no longer relevant when due
+)
+
+// ScheduledMessage is an outbound message delivered through a plugin at a
+// later time
+type ScheduledMessage struct {
+	ID        string     `json:"id"`
+	Kind      string     `json:"kind"` // reminder, digest or deadline
+	Platform  string     `json:"platform"`
+	ChannelID string     `json:"channel_id,omitempty"`
+	ThreadID  string     `json:"thread_id,omitempty"`  // Thread in the channel the message is posted in
+	UserID    string     `json:"user_id,omitempty"`    // Direct message recipient when there is no channel
+	Content   string     `json:"content,omitempty"`    // Empty for kinds whose content is written when due
+	SubjectID string     `json:"subject_id,omitempty"` // Proposal a deadline warning is about
+	DeliverAt time.Time  `json:"deliver_at"`
+	CreatedBy string     `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	Status    string     `json:"status"`
+	Attempts  int        `json:"attempts,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+	SentAt    *time.Time `json:"sent_at,omitempty"`
+}
+
+// SaveScheduled persists a scheduled message, replacing any previous copy
+func (m *Memory) SaveScheduled(ctx context.Context, msg *ScheduledMessage) error {
+	if msg.ID == "" {
+		return fmt.Errorf("scheduled message ID is required")
+	}
+	if msg.CreatedAt.IsZero() {
+		msg.CreatedAt = time.Now()
+	}
+	if msg.Status == "" {
+		msg.Status = ScheduledPending
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal scheduled message: %w", err)
+	}
+	metadata := map[string]interface{}{
+		"type":       "scheduled_message",
+		"scheduled":  string(data),
+		"status":     msg.Status,
+		"deliver_at": msg.DeliverAt.Unix(),
+	}
+	if err := m.vectorDB.Store(ctx, vectordb.TableSchedule, msg.ID, nil, metadata); err != nil {
+		return fmt.Errorf("failed to store scheduled message: %w", err)
+	}
+	return nil
+}
+
+// LoadScheduled retrieves a scheduled message by ID
+func (m *Memory) LoadScheduled(ctx context.Context, id string) (*ScheduledMessage, error) {
+	// Backends report missing records as plain errors
+	stored, err := m.vectorDB.Get(ctx, vectordb.TableSchedule, id)
+	if err != nil || stored == nil {
+		return nil, fmt.Errorf("%w: %s", ErrScheduledNotFound, id)
+	}
+	return decodeScheduled(stored.Metadata)
+}
+
+// ListScheduled returns scheduled messages with the given status, or all of
+// them for an empty status, soonest first
+func (m *Memory) ListScheduled(ctx context.Context, status string) ([]ScheduledMessage, error) {
+	stored, err := m.vectorDB.List(ctx, vectordb.TableSchedule, MaxScheduledMessages, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled messages: %w", err)
+	}
+
+	messages := make([]ScheduledMessage, 0, len(stored))
+	for _, s := range stored {
+		msg, err := decodeScheduled(s.Metadata)
+		if err != nil {
+			continue // Skip corrupt entries rather than failing the whole listing
+		}
+		if status == "" || msg.Status == status {
+			messages = append(messages, *msg)
+		}
+	}
+	sort.Slice(messages, func(i, j int) bool {
+		if !messages[i].DeliverAt.Equal(messages[j].DeliverAt) {
+			return messages[i].DeliverAt.Before(messages[j].DeliverAt)
+		}
+		return messages[i].ID < messages[j].ID
+	})
+	return messages, nil
+}
+
+// DeleteScheduled removes a scheduled message
+func (m *Memory) DeleteScheduled(ctx context.Context, id string) error {
+	if err := m.vectorDB.Delete(ctx, vectordb.TableSchedule, id); err != nil {
+		return fmt.Errorf("failed to delete scheduled message: %w", err)
+	}
+	return nil
+}
+
+// decodeScheduled extracts a ScheduledMessage from stored metadata
+func decodeScheduled(metadata map[string]interface{}) (*ScheduledMessage, error) {
+	raw, ok := metadata["scheduled"].(string)
+	if !ok || raw == "" {
+		return nil, fmt.Errorf("scheduled message payload missing")
+	}
+
+	var msg ScheduledMessage
+	if err := json.Unmarshal([]byte(raw), &msg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal scheduled message: %w", err)
+	}
+	return &msg, nil
+}
@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestScheduledMessages(t *testing.T) {
+	mem := New(newMockVectorDB())
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, msg := range []*ScheduledMessage{
+		{ID: "b", Kind: "reminder", Platform: "chat", ChannelID: "general", Content: "later", DeliverAt: now.Add(time.Hour)},
+		{ID: "a", Kind: "reminder", Platform: "chat", ChannelID: "general", Content: "sooner", DeliverAt: now},
+		{ID: "c", Kind: "reminder", Platform: "chat", ChannelID: "general", Content: "done", DeliverAt: now, Status: ScheduledSent},
+	} {
+		if err := mem.SaveScheduled(ctx, msg); err != nil {
+			t.Fatalf("SaveScheduled: %v", err)
+		}
+	}
+
+	pending, err := mem.ListScheduled(ctx, ScheduledPending)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 2 || pending[0].ID != "a" || pending[1].ID != "b" {
+		t.Errorf("pending = %+v, want a then b", pending)
+	}
+	if all, _ := mem.ListScheduled(ctx, ""); len(all) != 3 {
+		t.Errorf("all = %d messages, want 3", len(all))
+	}
+
+	if err := mem.DeleteScheduled(ctx, "c"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mem.LoadScheduled(ctx, "c"); !errors.Is(err, ErrScheduledNotFound) {
+		t.Errorf("deleted message: err = %v, want ErrScheduledNotFound", err)
+	}
+	if err := mem.SaveScheduled(ctx, &ScheduledMessage{}); err == nil {
+		t.Error("expected error for a message without an ID")
+	}
+}
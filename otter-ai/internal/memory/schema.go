@@ -0,0 +1,294 @@
+package memory
+
+import (
+	"fmt"
+	"sync"
+
+	"otter-ai/internal/errs"
+)
+
+// Metadata schema configuration
+const (
+	MetadataSchemaVersion   = 1
+	MaxMetadataFields       = 32
+	MaxMetadataStringLength = 20000
+)
+
+// ErrInvalidMetadata indicates metadata that does not match its memory type's schema
+var ErrInvalidMetadata = errs.New(errs.ErrInvalid, "invalid memory metadata")
+
+// FieldKind is the value type of a metadata field
+type FieldKind string
+
+const (
+	KindString FieldKind = "string"
+	KindNumber FieldKind = "number"
+	KindBool   FieldKind = "bool"
+	KindObject FieldKind = "object"
+)
+
+// Content sources recorded in the content_source field
+const (
+	SourceInteraction    = "interaction"
+	SourcePin            = "pin"
+	SourceAgentGenerated = "agent_generated"
+	SourcePlugin         = "plugin"
+	SourceConnector      = "connector"  // Ingested from an external source
+	SourceExtraction     = "extraction" // A fact extracted from an interaction
+)
+
+// Kinds of extracted facts recorded in the fact_kind field
+const (
+	FactKindFact       = "fact"
+	FactKindPreference = "preference" // Something the user likes, wants or asked for
+)
+
+// FieldSpec describes one allowed metadata field
+type FieldSpec struct {
+	Kind      FieldKind
+	Enum      []string // Allowed values for string fields; empty allows any
+	MaxLength int      // Maximum string length; 0 uses MaxMetadataStringLength
+}
+
+// MetadataSchema lists the metadata fields a memory type may carry
+type MetadataSchema struct {
+	Type    MemoryType
+	Version int
+	Fields  map[string]FieldSpec
+}
+
+// reservedMetadataKeys are written by Store from MemoryRecord fields and may
+// not be supplied as metadata.
+var reservedMetadataKeys = map[string]bool{
+	"content":        true,
+	"timestamp":      true,
+	"scope":          true,
+	"importance":     true,
+	"type":           true,
+	"pinned":         true,
+	"held":           true,
+	"schema_version": true,
+}
+
+// commonMetadataFields are allowed on every memory type
+func commonMetadataFields() map[string]FieldSpec {
+	return map[string]FieldSpec{
+		"content_source": {
+			Kind: KindString,
+			Enum: []string{SourceInteraction, SourcePin, SourceAgentGenerated, SourcePlugin, SourceConnector, SourceExtraction},
+		},
+		"container_health": {Kind: KindObject},
+		"duplicate_count":  {Kind: KindNumber}, // Repeats absorbed by the DedupPolicy
+	}
+}
+
+// defaultSchemas returns the built-in schema for each memory type
+func defaultSchemas() map[MemoryType]*MetadataSchema {
+	conversation := func(memType MemoryType) *MetadataSchema {
+		fields := commonMetadataFields()
+		fields["user_message"] = FieldSpec{Kind: KindString}
+		fields["response"] = FieldSpec{Kind: KindString}
+		// Who the otter was talking to, so their memories can be erased
+		fields["user_id"] = FieldSpec{Kind: KindString, MaxLength: 256}
+		fields["hook_annotations"] = FieldSpec{Kind: KindObject}   // Added by chat hooks
+		fields["rule_violations"] = FieldSpec{Kind: KindObject}    // Rules the reply or its tool calls broke
+		fields["consolidated_count"] = FieldSpec{Kind: KindNumber} // Set on consolidation summaries
+		fields["consolidated_since"] = FieldSpec{Kind: KindNumber}
+		fields["consolidated_until"] = FieldSpec{Kind: KindNumber}
+		fields["decayed_at"] = FieldSpec{Kind: KindNumber} // Maintained by RunRetention
+		fields["access_count"] = FieldSpec{Kind: KindNumber}
+		fields["last_accessed"] = FieldSpec{Kind: KindNumber}
+		fields["connector"] = FieldSpec{Kind: KindString, MaxLength: 64} // Set on memories ingested by connectors
+		fields["connector_kind"] = FieldSpec{Kind: KindString, MaxLength: 16}
+		fields["source_id"] = FieldSpec{Kind: KindString, MaxLength: 512}
+		fields["source_url"] = FieldSpec{Kind: KindString, MaxLength: 2048}
+		fields["source_published"] = FieldSpec{Kind: KindNumber}
+		// Set on extracted facts, with the interaction they were extracted from
+		fields["fact_kind"] = FieldSpec{Kind: KindString, Enum: []string{FactKindFact, FactKindPreference}}
+		fields["source_memory_id"] = FieldSpec{Kind: KindString, MaxLength: 64}
+		return &MetadataSchema{Type: memType, Version: MetadataSchemaVersion, Fields: fields}
+	}
+
+	musing := commonMetadataFields()
+	musing["musing_kind"] = FieldSpec{Kind: KindString, MaxLength: 64}
+	musing["source_memory_count"] = FieldSpec{Kind: KindNumber}
+	musing["source_latest_timestamp"] = FieldSpec{Kind: KindNumber}
+
+	personality := commonMetadataFields()
+	personality["trait"] = FieldSpec{Kind: KindString, MaxLength: 64}
+	personality["baseline"] = FieldSpec{Kind: KindNumber}
+
+	archived := conversation(MemoryTypeArchived)
+	archived.Fields["archived_from"] = FieldSpec{Kind: KindString, MaxLength: 64}
+	archived.Fields["consolidated_into"] = FieldSpec{Kind: KindString, MaxLength: 64}
+	archived.Fields["archived_at"] = FieldSpec{Kind: KindNumber}
+	archived.Fields["archive_reason"] = FieldSpec{Kind: KindString, Enum: []string{ArchiveReasonConsolidated, ArchiveReasonDecayed}}
+
+	return map[MemoryType]*MetadataSchema{
+		MemoryTypeShortTerm:   conversation(MemoryTypeShortTerm),
+		MemoryTypeLongTerm:    conversation(MemoryTypeLongTerm),
+		MemoryTypeMusing:      {Type: MemoryTypeMusing, Version: MetadataSchemaVersion, Fields: musing},
+		MemoryTypePersonality: {Type: MemoryTypePersonality, Version: MetadataSchemaVersion, Fields: personality},
+		MemoryTypeArchived:    archived,
+	}
+}
+
+// schemaRegistry holds the metadata schemas of one memory layer
+type schemaRegistry struct {
+	schemas map[MemoryType]*MetadataSchema
+	mu      sync.RWMutex
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{schemas: defaultSchemas()}
+}
+
+// RegisterMetadataField adds a field to a memory type's schema so plugins
+// can attach their own typed metadata. Registering the same spec twice is
+// allowed; redefining a field is not.
+func (m *Memory) RegisterMetadataField(memoryType MemoryType, name string, spec FieldSpec) error {
+	if name == "" || reservedMetadataKeys[name] {
+		return fmt.Errorf("%w: field name %q is reserved", ErrInvalidMetadata, name)
+	}
+	switch spec.Kind {
+	case KindString, KindNumber, KindBool, KindObject:
+	default:
+		return fmt.Errorf("%w: unknown field kind %q", ErrInvalidMetadata, spec.Kind)
+	}
+
+	m.schemas.mu.Lock()
+	defer m.schemas.mu.Unlock()
+
+	schema, ok := m.schemas.schemas[normalizeType(memoryType)]
+	if !ok {
+		return fmt.Errorf("%w: unknown memory type %q", ErrInvalidMetadata, memoryType)
+	}
+	if existing, ok := schema.Fields[name]; ok {
+		if existing.Kind != spec.Kind {
+			return fmt.Errorf("%w: field %q is already registered as %s", ErrInvalidMetadata, name, existing.Kind)
+		}
+		return nil
+	}
+	schema.Fields[name] = spec
+	return nil
+}
+
+// Schema returns a copy of the metadata schema for a memory type
+func (m *Memory) Schema(memoryType MemoryType) (MetadataSchema, bool) {
+	m.schemas.mu.RLock()
+	defer m.schemas.mu.RUnlock()
+
+	schema, ok := m.schemas.schemas[normalizeType(memoryType)]
+	if !ok {
+		return MetadataSchema{}, false
+	}
+	fields := make(map[string]FieldSpec, len(schema.Fields))
+	for name, spec := range schema.Fields {
+		fields[name] = spec
+	}
+	return MetadataSchema{Type: schema.Type, Version: schema.Version, Fields: fields}, true
+}
+
+// ValidateMetadata checks metadata against the schema of its memory type
+func (m *Memory) ValidateMetadata(memoryType MemoryType, metadata map[string]interface{}) error {
+	m.schemas.mu.RLock()
+	defer m.schemas.mu.RUnlock()
+
+	schema, ok := m.schemas.schemas[normalizeType(memoryType)]
+	if !ok {
+		return fmt.Errorf("%w: unknown memory type %q", ErrInvalidMetadata, memoryType)
+	}
+	if len(metadata) > MaxMetadataFields {
+		return fmt.Errorf("%w: too many fields (max %d)", ErrInvalidMetadata, MaxMetadataFields)
+	}
+
+	for name, value := range metadata {
+		if reservedMetadataKeys[name] {
+			return fmt.Errorf("%w: field %q is reserved", ErrInvalidMetadata, name)
+		}
+		spec, ok := schema.Fields[name]
+		if !ok {
+			return fmt.Errorf("%w: unknown field %q for %s memories", ErrInvalidMetadata, name, schema.Type)
+		}
+		if err := validateField(spec, value); err != nil {
+			return fmt.Errorf("%w: field %q %v", ErrInvalidMetadata, name, err)
+		}
+	}
+	return nil
+}
+
+// validateField checks a single value against its spec
+func validateField(spec FieldSpec, value interface{}) error {
+	switch spec.Kind {
+	case KindString:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("must be a string, got %T", value)
+		}
+		maxLen := spec.MaxLength
+		if maxLen == 0 {
+			maxLen = MaxMetadataStringLength
+		}
+		if len(s) > maxLen {
+			return fmt.Errorf("is too long (max %d characters)", maxLen)
+		}
+		if len(spec.Enum) > 0 {
+			for _, allowed := range spec.Enum {
+				if s == allowed {
+					return nil
+				}
+			}
+			return fmt.Errorf("must be one of %v", spec.Enum)
+		}
+	case KindNumber:
+		switch value.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		default:
+			return fmt.Errorf("must be a number, got %T", value)
+		}
+	case KindBool:
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("must be a bool, got %T", value)
+		}
+	case KindObject:
+		if _, ok := value.(map[string]interface{}); !ok {
+			return fmt.Errorf("must be an object, got %T", value)
+		}
+	}
+	return nil
+}
+
+// normalizeType maps the empty type to long-term, matching getTableForType
+func normalizeType(memoryType MemoryType) MemoryType {
+	if memoryType == "" {
+		return MemoryTypeLongTerm
+	}
+	return memoryType
+}
+
+// metadataMigrations upgrade stored metadata one version at a time;
+// entry i migrates from version i to i+1.
+var metadataMigrations = []func(metadata map[string]interface{}){
+	// v0 -> v1: idle musings stored their kind under the reserved "type" key
+	func(metadata map[string]interface{}) {
+		if kind, ok := metadata["type"].(string); ok && kind == "idle_musing" {
+			metadata["type"] = string(MemoryTypeMusing)
+			metadata["musing_kind"] = kind
+		}
+	},
+}
+
+// migrateMetadata upgrades stored metadata to MetadataSchemaVersion in place
+func migrateMetadata(metadata map[string]interface{}) {
+	version := 0
+	switch v := metadata["schema_version"].(type) {
+	case float64:
+		version = int(v)
+	case int:
+		version = v
+	}
+	for ; version < len(metadataMigrations) && version < MetadataSchemaVersion; version++ {
+		metadataMigrations[version](metadata)
+	}
+	metadata["schema_version"] = MetadataSchemaVersion
+}
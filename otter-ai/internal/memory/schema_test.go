@@ -0,0 +1,153 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"otter-ai/internal/vectordb"
+)
+
+func TestValidateMetadata_BuiltInFields(t *testing.T) {
+	mem := New(newMockVectorDB())
+
+	valid := map[string]interface{}{
+		"content_source":   SourceInteraction,
+		"user_message":     "hi",
+		"response":         "hello",
+		"container_health": map[string]interface{}{"goroutines": 4},
+	}
+	if err := mem.ValidateMetadata(MemoryTypeLongTerm, valid); err != nil {
+		t.Errorf("valid metadata rejected: %v", err)
+	}
+	if err := mem.ValidateMetadata("", valid); err != nil {
+		t.Errorf("empty type should use the long-term schema: %v", err)
+	}
+
+	musing := map[string]interface{}{"musing_kind": "idle_musing", "source_memory_count": 3, "source_latest_timestamp": int64(1700000000)}
+	if err := mem.ValidateMetadata(MemoryTypeMusing, musing); err != nil {
+		t.Errorf("valid musing metadata rejected: %v", err)
+	}
+}
+
+func TestValidateMetadata_Rejects(t *testing.T) {
+	mem := New(newMockVectorDB())
+
+	cases := map[string]map[string]interface{}{
+		"unknown field":   {"mood": "happy"},
+		"reserved field":  {"type": "idle_musing"},
+		"wrong kind":      {"user_message": 42},
+		"bad enum":        {"content_source": "somewhere"},
+		"too long":        {"response": strings.Repeat("x", MaxMetadataStringLength+1)},
+		"musing on chats": {"musing_kind": "idle_musing"},
+	}
+	for name, metadata := range cases {
+		if err := mem.ValidateMetadata(MemoryTypeLongTerm, metadata); !errors.Is(err, ErrInvalidMetadata) {
+			t.Errorf("%s: expected ErrInvalidMetadata, got %v", name, err)
+		}
+	}
+
+	if err := mem.ValidateMetadata("dreams", nil); !errors.Is(err, ErrInvalidMetadata) {
+		t.Errorf("unknown memory type: expected ErrInvalidMetadata, got %v", err)
+	}
+}
+
+func TestStore_RejectsInvalidMetadata(t *testing.T) {
+	db := newMockVectorDB()
+	mem := New(db)
+
+	rec := &MemoryRecord{ID: "m1", Type: MemoryTypeLongTerm, Content: "x", Metadata: map[string]interface{}{"scope": "sneaky"}}
+	if err := mem.Store(context.Background(), rec); !errors.Is(err, ErrInvalidMetadata) {
+		t.Fatalf("expected ErrInvalidMetadata, got %v", err)
+	}
+	if _, ok := db.records[vectordb.TableMemories]["m1"]; ok {
+		t.Error("invalid record should not be written")
+	}
+}
+
+func TestRegisterMetadataField(t *testing.T) {
+	mem := New(newMockVectorDB())
+
+	if err := mem.RegisterMetadataField(MemoryTypeLongTerm, "discord_channel", FieldSpec{Kind: KindString}); err != nil {
+		t.Fatalf("RegisterMetadataField: %v", err)
+	}
+	if err := mem.ValidateMetadata(MemoryTypeLongTerm, map[string]interface{}{"discord_channel": "general"}); err != nil {
+		t.Errorf("registered field rejected: %v", err)
+	}
+	if err := mem.ValidateMetadata(MemoryTypeMusing, map[string]interface{}{"discord_channel": "general"}); err == nil {
+		t.Error("field should only be registered for its memory type")
+	}
+
+	if err := mem.RegisterMetadataField(MemoryTypeLongTerm, "discord_channel", FieldSpec{Kind: KindString}); err != nil {
+		t.Errorf("re-registering the same spec should succeed: %v", err)
+	}
+	if err := mem.RegisterMetadataField(MemoryTypeLongTerm, "discord_channel", FieldSpec{Kind: KindNumber}); err == nil {
+		t.Error("redefining a field should fail")
+	}
+	if err := mem.RegisterMetadataField(MemoryTypeLongTerm, "pinned", FieldSpec{Kind: KindBool}); err == nil {
+		t.Error("reserved names should be rejected")
+	}
+	if err := mem.RegisterMetadataField(MemoryTypeLongTerm, "x", FieldSpec{Kind: "date"}); err == nil {
+		t.Error("unknown kinds should be rejected")
+	}
+
+	// Registries are per memory layer
+	if err := New(newMockVectorDB()).ValidateMetadata(MemoryTypeLongTerm, map[string]interface{}{"discord_channel": "general"}); err == nil {
+		t.Error("registration should not leak into other memory layers")
+	}
+}
+
+func TestSchema_ReturnsCopy(t *testing.T) {
+	mem := New(newMockVectorDB())
+
+	schema, ok := mem.Schema(MemoryTypeMusing)
+	if !ok || schema.Version != MetadataSchemaVersion {
+		t.Fatalf("unexpected schema: %+v", schema)
+	}
+	delete(schema.Fields, "musing_kind")
+
+	if again, _ := mem.Schema(MemoryTypeMusing); again.Fields["musing_kind"].Kind != KindString {
+		t.Error("modifying a returned schema should not affect the registry")
+	}
+}
+
+func TestRecordFromStore_MigratesLegacyMusing(t *testing.T) {
+	metadata := map[string]interface{}{
+		"content":             "a thought",
+		"type":                "idle_musing",
+		"timestamp":           float64(1700000000),
+		"source_memory_count": float64(3),
+	}
+
+	record := recordFromStore("m1", nil, metadata)
+	if record.Type != MemoryTypeMusing {
+		t.Errorf("Type = %q, want %q", record.Type, MemoryTypeMusing)
+	}
+	if record.Metadata["musing_kind"] != "idle_musing" {
+		t.Errorf("musing_kind = %v", record.Metadata["musing_kind"])
+	}
+	if _, ok := record.Metadata["content"]; ok {
+		t.Error("core fields should be lifted out of Metadata")
+	}
+	if record.Metadata["source_memory_count"] != float64(3) {
+		t.Error("schema fields should be kept")
+	}
+}
+
+func TestStore_RoundTripRevalidates(t *testing.T) {
+	mem := New(newMockVectorDB())
+	ctx := context.Background()
+
+	rec := &MemoryRecord{ID: "m1", Type: MemoryTypeLongTerm, Content: "x", Metadata: map[string]interface{}{"content_source": SourcePin}}
+	if err := mem.Store(ctx, rec); err != nil {
+		t.Fatal(err)
+	}
+	got, err := mem.Get(ctx, "m1", MemoryTypeLongTerm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mem.Store(ctx, got); err != nil {
+		t.Errorf("a stored record should store again unchanged: %v", err)
+	}
+}
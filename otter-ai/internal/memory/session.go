@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"otter-ai/internal/errs"
+	"otter-ai/internal/vectordb"
+)
+
+// SessionMessage is a single turn stored with a conversation session
+type SessionMessage struct {
+	Role      string    `json:"role"`
+	Content   string    `json:"content"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// SessionRecord is the persisted form of a conversation session
+type SessionRecord struct {
+	ID        string           `json:"id"`
+	Summary   string           `json:"summary,omitempty"`
+	Messages  []SessionMessage `json:"messages"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+
+	// Context controls chosen for the conversation
+	NoMemory     bool   `json:"no_memory,omitempty"`
+	NoGovernance bool   `json:"no_governance,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// SaveSession persists a conversation session, replacing any previous copy
+func (m *Memory) SaveSession(ctx context.Context, session *SessionRecord) error {
+	if session.ID == "" {
+		return fmt.Errorf("session ID is required")
+	}
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = time.Now()
+	}
+	if session.UpdatedAt.IsZero() {
+		session.UpdatedAt = session.CreatedAt
+	}
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	metadata := map[string]interface{}{
+		"type":       "session",
+		"session":    string(data),
+		"updated_at": session.UpdatedAt.Unix(),
+	}
+
+	if err := m.vectorDB.Store(ctx, vectordb.TableSessions, session.ID, nil, metadata); err != nil {
+		return fmt.Errorf("failed to store session: %w", err)
+	}
+
+	return nil
+}
+
+// LoadSession retrieves a persisted conversation session by ID
+func (m *Memory) LoadSession(ctx context.Context, id string) (*SessionRecord, error) {
+	record, err := m.vectorDB.Get(ctx, vectordb.TableSessions, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session: %w", err)
+	}
+	if record == nil {
+		return nil, errs.Errorf(errs.ErrNotFound, "session not found: %s", id)
+	}
+
+	return decodeSession(record.Metadata)
+}
+
+// ListSessions returns persisted sessions, most recently updated first
+func (m *Memory) ListSessions(ctx context.Context, limit, offset int) ([]SessionRecord, error) {
+	records, err := m.vectorDB.List(ctx, vectordb.TableSessions, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	sessions := make([]SessionRecord, 0, len(records))
+	for _, record := range records {
+		session, err := decodeSession(record.Metadata)
+		if err != nil {
+			continue // Skip corrupt entries rather than failing the whole listing
+		}
+		sessions = append(sessions, *session)
+	}
+
+	return sessions, nil
+}
+
+// DeleteSession removes a persisted conversation session
+func (m *Memory) DeleteSession(ctx context.Context, id string) error {
+	if err := m.vectorDB.Delete(ctx, vectordb.TableSessions, id); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
+
+// decodeSession extracts a SessionRecord from stored metadata
+func decodeSession(metadata map[string]interface{}) (*SessionRecord, error) {
+	raw, ok := metadata["session"].(string)
+	if !ok || raw == "" {
+		return nil, fmt.Errorf("session payload missing")
+	}
+
+	var session SessionRecord
+	if err := json.Unmarshal([]byte(raw), &session); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+	return &session, nil
+}
@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"otter-ai/internal/vectordb"
+)
+
+func TestSaveAndLoadSession(t *testing.T) {
+	mem := New(newMockVectorDB())
+	ctx := context.Background()
+
+	in := &SessionRecord{
+		ID:      "user-1",
+		Summary: "Talked about rivers.",
+		Messages: []SessionMessage{
+			{Role: "user", Content: "hi", Timestamp: time.Now()},
+			{Role: "assistant", Content: "hello", Timestamp: time.Now()},
+		},
+	}
+	if err := mem.SaveSession(ctx, in); err != nil {
+		t.Fatalf("SaveSession: %v", err)
+	}
+
+	out, err := mem.LoadSession(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("LoadSession: %v", err)
+	}
+	if out.Summary != in.Summary || len(out.Messages) != 2 {
+		t.Errorf("LoadSession = %+v", out)
+	}
+	if out.CreatedAt.IsZero() {
+		t.Error("expected CreatedAt to be set")
+	}
+}
+
+func TestSaveSession_RequiresID(t *testing.T) {
+	mem := New(newMockVectorDB())
+	if err := mem.SaveSession(context.Background(), &SessionRecord{}); err == nil {
+		t.Error("expected error for empty session ID")
+	}
+}
+
+func TestLoadSession_NotFound(t *testing.T) {
+	mem := New(newMockVectorDB())
+	if _, err := mem.LoadSession(context.Background(), "missing"); err == nil {
+		t.Error("expected error for missing session")
+	}
+}
+
+func TestListSessions_SkipsCorrupt(t *testing.T) {
+	db := newMockVectorDB()
+	mem := New(db)
+	ctx := context.Background()
+
+	_ = mem.SaveSession(ctx, &SessionRecord{ID: "good"})
+	_ = db.Store(ctx, vectordb.TableSessions, "bad", nil, map[string]interface{}{"session": "{not json"})
+
+	sessions, err := mem.ListSessions(ctx, 10, 0)
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != "good" {
+		t.Errorf("ListSessions = %+v", sessions)
+	}
+}
+
+func TestDeleteSession(t *testing.T) {
+	mem := New(newMockVectorDB())
+	ctx := context.Background()
+
+	_ = mem.SaveSession(ctx, &SessionRecord{ID: "gone"})
+	if err := mem.DeleteSession(ctx, "gone"); err != nil {
+		t.Fatalf("DeleteSession: %v", err)
+	}
+	if _, err := mem.LoadSession(ctx, "gone"); err == nil {
+		t.Error("expected session to be deleted")
+	}
+}
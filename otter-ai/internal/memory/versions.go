@@ -0,0 +1,141 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"otter-ai/internal/vectordb"
+)
+
+// ErrVersioningUnsupported is returned when the vector backend neither
+// soft-deletes nor keeps versions, so edits and deletes are final
+var ErrVersioningUnsupported = errors.New("memory backend does not keep versions")
+
+// DefaultRecoveryWindow is how long deleted memories and superseded
+// versions stay recoverable before the purge job removes them
+const DefaultRecoveryWindow = 30 * 24 * time.Hour
+
+// MemoryVersion is one version of a memory. The current version has no
+// SupersededAt; DeletedAt is set when the memory is soft-deleted.
+type MemoryVersion struct {
+	Version      int          `json:"version"`
+	Record       MemoryRecord `json:"record"`
+	SupersededAt *time.Time   `json:"superseded_at,omitempty"`
+	DeletedAt    *time.Time   `json:"deleted_at,omitempty"`
+}
+
+// versioner returns the backend's versioning support
+func (m *Memory) versioner() (vectordb.Versioner, error) {
+	versioner, ok := m.vectorDB.(vectordb.Versioner)
+	if !ok {
+		return nil, ErrVersioningUnsupported
+	}
+	return versioner, nil
+}
+
+// Restore undoes the deletion of a memory that has not been purged yet
+func (m *Memory) Restore(ctx context.Context, id string, memoryType MemoryType) error {
+	if err := m.writable(); err != nil {
+		return err
+	}
+	versioner, err := m.versioner()
+	if err != nil {
+		return err
+	}
+	if err := m.checkRecoverable(ctx, versioner, memoryType, id, 0); err != nil {
+		return err
+	}
+	if err := versioner.Restore(ctx, m.getTableForType(memoryType), id); err != nil {
+		return fmt.Errorf("failed to restore memory: %w", err)
+	}
+	return nil
+}
+
+// Versions returns a memory's versions, newest first. A deleted memory's
+// versions are listed until it is purged.
+func (m *Memory) Versions(ctx context.Context, id string, memoryType MemoryType) ([]MemoryVersion, error) {
+	versioner, err := m.versioner()
+	if err != nil {
+		return nil, err
+	}
+	stored, err := versioner.Versions(ctx, m.getTableForType(memoryType), id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list memory versions: %w", err)
+	}
+
+	versions := make([]MemoryVersion, 0, len(stored))
+	for _, version := range stored {
+		versions = append(versions, MemoryVersion{
+			Version:      version.Version,
+			Record:       recordFromStore(id, version.Vector, version.Metadata),
+			SupersededAt: version.SupersededAt,
+			DeletedAt:    version.DeletedAt,
+		})
+	}
+	return versions, nil
+}
+
+// Revert makes an earlier version of a memory current again, e.g. to undo a
+// redaction. Memories under legal hold are refused with ErrHeld.
+func (m *Memory) Revert(ctx context.Context, id string, memoryType MemoryType, version int) error {
+	if err := m.writable(); err != nil {
+		return err
+	}
+	versioner, err := m.versioner()
+	if err != nil {
+		return err
+	}
+	table := m.getTableForType(memoryType)
+
+	if record, err := m.vectorDB.Get(ctx, table, id); err == nil && record != nil {
+		if held, _ := record.Metadata["held"].(bool); held {
+			return fmt.Errorf("%w: %s", ErrHeld, id)
+		}
+	}
+	if err := m.checkRecoverable(ctx, versioner, memoryType, id, version); err != nil {
+		return err
+	}
+	if err := versioner.Revert(ctx, table, id, version); err != nil {
+		return fmt.Errorf("failed to revert memory: %w", err)
+	}
+	return nil
+}
+
+// checkRecoverable refuses to bring back a version, or with version zero
+// the current one, whose vector predates re-embedding with another model
+func (m *Memory) checkRecoverable(ctx context.Context, versioner vectordb.Versioner, memoryType MemoryType, id string, version int) error {
+	versions, err := versioner.Versions(ctx, m.getTableForType(memoryType), id)
+	if err != nil || len(versions) == 0 {
+		// The backend reports missing records and versions itself
+		return nil
+	}
+	candidate := versions[0]
+	for _, v := range versions {
+		if v.Version == version {
+			candidate = v
+		}
+	}
+	if err := m.checkEmbedding(candidate.Vector); err != nil {
+		return fmt.Errorf("%s was embedded with another model: %w", id, err)
+	}
+	return nil
+}
+
+// PurgeDeleted permanently removes memories deleted, and versions
+// superseded, longer than window ago. Held memories keep their history.
+func (m *Memory) PurgeDeleted(ctx context.Context, window time.Duration) (vectordb.PurgeResult, error) {
+	if err := m.writable(); err != nil {
+		return vectordb.PurgeResult{}, err
+	}
+	versioner, err := m.versioner()
+	if err != nil {
+		return vectordb.PurgeResult{}, err
+	}
+	result, err := versioner.Purge(ctx, time.Now().Add(-window))
+	if err != nil {
+		return result, fmt.Errorf("failed to purge deleted memories: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRevert_RefusesHeld(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	mem := New(db)
+	ctx := context.Background()
+
+	record := &MemoryRecord{ID: "m1", Type: MemoryTypeLongTerm, Content: "draft", Embedding: []float32{1}}
+	if err := mem.Store(ctx, record); err != nil {
+		t.Fatal(err)
+	}
+	record.Content = "final"
+	if err := mem.Store(ctx, record); err != nil {
+		t.Fatal(err)
+	}
+
+	versions, err := mem.Versions(ctx, "m1", MemoryTypeLongTerm)
+	if err != nil || len(versions) != 2 || versions[1].Record.Content != "draft" {
+		t.Fatalf("Versions = %+v, %v", versions, err)
+	}
+
+	if _, err := mem.SetHeld(ctx, "m1", MemoryTypeLongTerm, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := mem.Revert(ctx, "m1", MemoryTypeLongTerm, 1); !errors.Is(err, ErrHeld) {
+		t.Errorf("expected ErrHeld, got %v", err)
+	}
+}
+
+func TestVersions_Unsupported(t *testing.T) {
+	mem := New(newMockVectorDB())
+	if err := mem.Restore(context.Background(), "m1", MemoryTypeLongTerm); !errors.Is(err, ErrVersioningUnsupported) {
+		t.Errorf("expected ErrVersioningUnsupported, got %v", err)
+	}
+}
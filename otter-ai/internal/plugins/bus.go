@@ -0,0 +1,247 @@
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Subjects on the plugin bus, under its prefix. Plugins publish messages
+// from their platform to inbound.<platform> and subscribe to
+// outbound.<platform> for messages to deliver.
+const (
+	BusInbound  = "inbound"
+	BusOutbound = "outbound"
+	BusHandle   = "handle" // Messages routed to a plugin by the otter
+)
+
+// BusReply answers an inbound bus message published with a reply subject
+type BusReply struct {
+	Reply string `json:"reply,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// Bus connects the manager to plugins through a NATS server, so plugin
+// processes run, scale and restart independently of the otter, and several
+// otter workers share the inbound messages as one queue group. It
+// reconnects when the connection drops.
+type Bus struct {
+	manager *Manager
+	url     string
+	prefix  string
+	queue   string
+
+	mu      sync.Mutex
+	conn    *natsConn
+	cancel  context.CancelFunc
+	stopped chan struct{}
+}
+
+// StartBus connects to the NATS server in the plugin config, subscribes to
+// inbound messages and registers a bus plugin for each configured platform.
+// It does nothing when no server is configured.
+func (m *Manager) StartBus(ctx context.Context) error {
+	cfg := m.config.NATS
+	if cfg.URL == "" {
+		return nil
+	}
+	for _, platform := range cfg.Platforms {
+		if !validSubjectToken(platform) {
+			return fmt.Errorf("invalid bus platform %q", platform)
+		}
+	}
+	prefix := cfg.Subject
+	if prefix == "" {
+		prefix = "otter"
+	}
+
+	bus := &Bus{manager: m, url: cfg.URL, prefix: prefix, queue: cfg.Queue, stopped: make(chan struct{})}
+	runCtx, cancel := context.WithCancel(context.Background())
+	bus.cancel = cancel
+	conn, err := bus.connect(ctx, runCtx)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	m.mu.Lock()
+	m.bus = bus
+	m.mu.Unlock()
+	for _, platform := range cfg.Platforms {
+		m.Register(&BusPlugin{name: platform, bus: bus})
+	}
+	go bus.run(runCtx, conn)
+	return nil
+}
+
+// stopBus disconnects the bus, if any
+func (m *Manager) stopBus() {
+	m.mu.Lock()
+	bus := m.bus
+	m.bus = nil
+	m.mu.Unlock()
+	if bus != nil {
+		bus.Close()
+	}
+}
+
+// validSubjectToken reports whether s can be one token of a NATS subject
+func validSubjectToken(s string) bool {
+	return s != "" && !strings.ContainsAny(s, ". \t\r\n*>")
+}
+
+// subject returns the bus subject of a kind of message for a platform
+func (b *Bus) subject(kind, platform string) string {
+	return b.prefix + "." + kind + "." + platform
+}
+
+// connect dials the server and subscribes to inbound messages, which are
+// handled with runCtx
+func (b *Bus) connect(ctx, runCtx context.Context) (*natsConn, error) {
+	conn, err := dialNATS(ctx, b.url)
+	if err != nil {
+		return nil, err
+	}
+	inbound := b.subject(BusInbound, ">")
+	err = conn.Subscribe(inbound, b.queue, func(subject, reply string, data []byte) {
+		b.receive(runCtx, conn, subject, reply, data)
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	b.mu.Lock()
+	b.conn = conn
+	b.mu.Unlock()
+	b.manager.log().Info("connected to plugin bus", "subject", inbound)
+	return conn, nil
+}
+
+// run reconnects whenever the connection drops, backing off between
+// failed attempts, until the bus is closed
+func (b *Bus) run(ctx context.Context, conn *natsConn) {
+	defer close(b.stopped)
+	logger := b.manager.log()
+	for {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+			return
+		case <-conn.Done():
+		}
+		logger.Warn("plugin bus disconnected, reconnecting", "error", conn.closedErr(nil))
+
+		delay := restartDelayMin
+		for {
+			var err error
+			if conn, err = b.connect(ctx, ctx); err == nil {
+				break
+			}
+			logger.Error("failed to reconnect to plugin bus", "error", err, "retry_in", delay)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+			delay = min(delay*2, restartDelayMax)
+		}
+	}
+}
+
+// receive passes an inbound message to the manager and answers on the
+// reply subject, if any
+func (b *Bus) receive(ctx context.Context, conn *natsConn, subject, reply string, data []byte) {
+	platform := strings.TrimPrefix(subject, b.prefix+"."+BusInbound+".")
+	var answer BusReply
+	var message Message
+	if err := json.Unmarshal(data, &message); err != nil {
+		answer.Error = fmt.Sprintf("invalid message: %v", err)
+	} else {
+		message.Platform = platform
+		text, err := b.manager.ReceiveMessage(ctx, &message)
+		if err != nil {
+			b.manager.log().Warn("failed to handle bus message", "platform", platform, "error", err)
+			answer.Error = err.Error()
+		}
+		answer.Reply = text
+	}
+	if reply == "" {
+		return
+	}
+
+	data, err := json.Marshal(answer)
+	if err == nil {
+		err = conn.Publish(reply, "", data)
+	}
+	if err != nil {
+		b.manager.log().Warn("failed to answer bus message", "platform", platform, "error", err)
+	}
+}
+
+// publish sends a message to a plugin's subject
+func (b *Bus) publish(kind, platform string, message *Message) error {
+	b.mu.Lock()
+	conn := b.conn
+	b.mu.Unlock()
+	if conn == nil {
+		return ErrNATSClosed
+	}
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	return conn.Publish(b.subject(kind, platform), "", data)
+}
+
+// Ping checks the bus server answers
+func (b *Bus) Ping(ctx context.Context) error {
+	b.mu.Lock()
+	conn := b.conn
+	b.mu.Unlock()
+	if conn == nil {
+		return ErrNATSClosed
+	}
+	return conn.Ping(ctx)
+}
+
+// Close disconnects from the server and stops reconnecting
+func (b *Bus) Close() {
+	b.cancel()
+	<-b.stopped
+}
+
+// BusPlugin is a platform whose plugin is reached through the bus: its
+// outgoing messages are published for the plugin process to deliver
+type BusPlugin struct {
+	name string
+	bus  *Bus
+}
+
+// Name returns the platform name
+func (p *BusPlugin) Name() string {
+	return p.name
+}
+
+// Initialize does nothing; the plugin process is configured on its own
+func (p *BusPlugin) Initialize(ctx context.Context, config map[string]string) error {
+	return nil
+}
+
+// HandleMessage publishes a message routed to the plugin
+func (p *BusPlugin) HandleMessage(ctx context.Context, message *Message) error {
+	return p.bus.publish(BusHandle, p.name, message)
+}
+
+// SendMessage publishes a message for the plugin to deliver
+func (p *BusPlugin) SendMessage(ctx context.Context, message *Message) error {
+	return p.bus.publish(BusOutbound, p.name, message)
+}
+
+// Shutdown does nothing; the bus is closed with the manager
+func (p *BusPlugin) Shutdown(ctx context.Context) error {
+	return nil
+}
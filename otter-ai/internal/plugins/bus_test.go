@@ -0,0 +1,268 @@
+package plugins
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"otter-ai/internal/config"
+)
+
+// fakeNATS is a NATS server speaking the subset of the protocol the bus
+// uses. Queue groups are ignored: every subscriber gets every message.
+type fakeNATS struct {
+	listener net.Listener
+
+	mu    sync.Mutex
+	conns map[net.Conn]*fakeNATSClient
+}
+
+type fakeNATSClient struct {
+	mu   sync.Mutex
+	conn net.Conn
+	subs map[string]string // sid -> subject
+}
+
+func newFakeNATS(t *testing.T) *fakeNATS {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeNATS{listener: listener, conns: make(map[net.Conn]*fakeNATSClient)}
+	go s.serve()
+	t.Cleanup(func() {
+		listener.Close()
+		s.dropAll()
+	})
+	return s
+}
+
+func (s *fakeNATS) url() string {
+	return "nats://" + s.listener.Addr().String()
+}
+
+func (s *fakeNATS) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		client := &fakeNATSClient{conn: conn, subs: make(map[string]string)}
+		s.mu.Lock()
+		s.conns[conn] = client
+		s.mu.Unlock()
+		go s.handle(client)
+	}
+}
+
+// dropAll disconnects every client
+func (s *fakeNATS) dropAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		conn.Close()
+		delete(s.conns, conn)
+	}
+}
+
+func (s *fakeNATS) handle(client *fakeNATSClient) {
+	reader := bufio.NewReader(client.conn)
+	client.send("INFO {\"server_id\":\"fake\"}\r\n")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "PING":
+			client.send("PONG\r\n")
+		case "SUB":
+			client.mu.Lock()
+			client.subs[fields[len(fields)-1]] = fields[1]
+			client.mu.Unlock()
+		case "PUB":
+			size, _ := strconv.Atoi(fields[len(fields)-1])
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(reader, payload); err != nil {
+				return
+			}
+			var reply string
+			if len(fields) == 4 {
+				reply = fields[2]
+			}
+			s.route(fields[1], reply, payload[:size])
+		}
+	}
+}
+
+func (s *fakeNATS) route(subject, reply string, payload []byte) {
+	s.mu.Lock()
+	clients := make([]*fakeNATSClient, 0, len(s.conns))
+	for _, client := range s.conns {
+		clients = append(clients, client)
+	}
+	s.mu.Unlock()
+	for _, client := range clients {
+		client.mu.Lock()
+		for sid, pattern := range client.subs {
+			if !subjectMatches(pattern, subject) {
+				continue
+			}
+			header := "MSG " + subject + " " + sid
+			if reply != "" {
+				header += " " + reply
+			}
+			client.sendLocked(fmt.Sprintf("%s %d\r\n%s\r\n", header, len(payload), payload))
+		}
+		client.mu.Unlock()
+	}
+}
+
+func (c *fakeNATSClient) send(data string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sendLocked(data)
+}
+
+func (c *fakeNATSClient) sendLocked(data string) {
+	c.conn.Write([]byte(data))
+}
+
+// subjectMatches matches a subject against a pattern whose last token may
+// be the > wildcard
+func subjectMatches(pattern, subject string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, ">"); ok {
+		return strings.HasPrefix(subject, prefix) && len(subject) > len(prefix)
+	}
+	return pattern == subject
+}
+
+// startTestBus starts a manager connected to a fake server, answering
+// every message with "re: " and its content
+func startTestBus(t *testing.T) (*fakeNATS, *Manager) {
+	t.Helper()
+	server := newFakeNATS(t)
+	m := NewManager(config.PluginConfig{NATS: config.NATSConfig{
+		URL: server.url(), Subject: "otter", Queue: "workers", Platforms: []string{"discord"},
+	}})
+	m.SetMessageHandler(func(_ context.Context, message *Message) (string, error) {
+		return "re: " + message.Content, nil
+	})
+	if err := m.StartBus(context.Background()); err != nil {
+		t.Fatalf("StartBus: %v", err)
+	}
+	t.Cleanup(func() { m.UnloadAll(context.Background()) })
+	return server, m
+}
+
+// testBusPlugin connects a plugin process's end of the bus
+func testBusPlugin(t *testing.T, server *fakeNATS) (*natsConn, chan []byte, chan []byte) {
+	t.Helper()
+	conn, err := dialNATS(context.Background(), server.url())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	outbound, replies := make(chan []byte, 4), make(chan []byte, 4)
+	conn.Subscribe("otter.outbound.discord", "", func(_, _ string, data []byte) { outbound <- data })
+	conn.Subscribe("_INBOX.test", "", func(_, _ string, data []byte) { replies <- data })
+	if err := conn.Ping(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return conn, outbound, replies
+}
+
+func receive(t *testing.T, ch chan []byte, v interface{}) {
+	t.Helper()
+	select {
+	case data := <-ch:
+		if err := json.Unmarshal(data, v); err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("nothing received")
+	}
+}
+
+func TestBus_InboundAndOutbound(t *testing.T) {
+	server, m := startTestBus(t)
+	plugin, outbound, replies := testBusPlugin(t, server)
+
+	if _, ok := m.Get("discord"); !ok {
+		t.Fatal("bus platform not registered")
+	}
+
+	data, _ := json.Marshal(Message{ChannelID: "c1", Content: "hello"})
+	if err := plugin.Publish("otter.inbound.discord", "_INBOX.test", data); err != nil {
+		t.Fatal(err)
+	}
+	var reply BusReply
+	receive(t, replies, &reply)
+	if reply.Reply != "re: hello" || reply.Error != "" {
+		t.Errorf("reply = %+v", reply)
+	}
+
+	if err := m.SendMessage(context.Background(), "discord", &Message{ChannelID: "c1", Content: "reminder"}); err != nil {
+		t.Fatal(err)
+	}
+	var sent Message
+	receive(t, outbound, &sent)
+	if sent.ChannelID != "c1" || sent.Content != "reminder" {
+		t.Errorf("outbound = %+v", sent)
+	}
+}
+
+func TestBus_Reconnects(t *testing.T) {
+	server, m := startTestBus(t)
+	server.dropAll()
+
+	plugin, _, replies := testBusPlugin(t, server)
+	data, _ := json.Marshal(Message{Content: "still there?"})
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		// The otter may not have resubscribed yet
+		if err := plugin.Publish("otter.inbound.discord", "_INBOX.test", data); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case answer := <-replies:
+			var reply BusReply
+			json.Unmarshal(answer, &reply)
+			if reply.Reply != "re: still there?" {
+				t.Errorf("reply = %+v", reply)
+			}
+			if err := m.bus.Ping(context.Background()); err != nil {
+				t.Errorf("Ping after reconnect: %v", err)
+			}
+			return
+		case <-time.After(50 * time.Millisecond):
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("bus did not reconnect")
+		}
+	}
+}
+
+func TestStartBus_Invalid(t *testing.T) {
+	m := NewManager(config.PluginConfig{NATS: config.NATSConfig{URL: "nats://127.0.0.1:1", Platforms: []string{"bad.name"}}})
+	if err := m.StartBus(context.Background()); err == nil {
+		t.Error("expected error for a platform that isn't a subject token")
+	}
+	m = NewManager(config.PluginConfig{NATS: config.NATSConfig{URL: "http://example.com"}})
+	if err := m.StartBus(context.Background()); err == nil {
+		t.Error("expected error for a non-nats URL")
+	}
+}
@@ -0,0 +1,393 @@
+package plugins
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"otter-ai/internal/config"
+)
+
+// Plugin process timeouts
+const (
+	HandshakeTimeout = 10 * time.Second // From start to the plugin's handshake
+	ShutdownGrace    = 5 * time.Second  // From closing stdin to killing the process
+)
+
+// ProtocolEnv is set in a plugin process's environment to the protocol
+// version it is expected to speak
+const ProtocolEnv = "OTTER_PLUGIN_PROTOCOL"
+
+// ErrPluginNotRunning is returned by calls on a plugin whose process is not
+// running
+var ErrPluginNotRunning = errors.New("plugin process not running")
+
+// ExternalPlugin is a plugin running as a separate process. The otter talks
+// to it over the process's stdin and stdout with the plugin protocol (see
+// Conn); stderr is logged. Platform SDKs, tokens and crashes stay out of the
+// otter's process, and plugins can be written in any language.
+type ExternalPlugin struct {
+	name string
+	path string
+	args []string
+
+	mu           sync.Mutex
+	logger       *slog.Logger
+	config       map[string]string // Kept for restarts
+	messages     MessageHandler
+	interactions InteractionHandler
+	process      *pluginProcess
+}
+
+// pluginProcess is one run of a plugin executable
+type pluginProcess struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	conn   *Conn
+	cancel context.CancelFunc
+	exited chan struct{}
+	err    error // Why the process exited; set before exited is closed
+}
+
+// NewExternalPlugin creates a plugin named name that runs the executable at
+// path with args. The process is started by Initialize.
+func NewExternalPlugin(name, path string, args ...string) *ExternalPlugin {
+	return &ExternalPlugin{name: name, path: path, args: args}
+}
+
+// Name returns the plugin name
+func (p *ExternalPlugin) Name() string {
+	return p.name
+}
+
+// Path returns the plugin executable
+func (p *ExternalPlugin) Path() string {
+	return p.path
+}
+
+// SetLogger sets the logger the plugin's stderr and lifecycle are logged to
+func (p *ExternalPlugin) SetLogger(logger *slog.Logger) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.logger = logger
+}
+
+// SetMessageHandler sets where messages the plugin receives are routed
+func (p *ExternalPlugin) SetMessageHandler(handler MessageHandler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.messages = handler
+}
+
+// SetInteractionHandler sets where interactions the plugin reports are routed
+func (p *ExternalPlugin) SetInteractionHandler(handler InteractionHandler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.interactions = handler
+}
+
+// log returns the plugin's logger
+func (p *ExternalPlugin) log() *slog.Logger {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.logger == nil {
+		return slog.Default().With("plugin", p.name)
+	}
+	return p.logger.With("plugin", p.name)
+}
+
+// Initialize starts the plugin process, waits for its handshake and sends it
+// config
+func (p *ExternalPlugin) Initialize(ctx context.Context, config map[string]string) error {
+	p.mu.Lock()
+	p.config = config
+	p.mu.Unlock()
+	return p.start(ctx)
+}
+
+// Restart stops the plugin process if it is running and starts it again with
+// the config it was initialized with
+func (p *ExternalPlugin) Restart(ctx context.Context) error {
+	p.stop(ctx)
+	return p.start(ctx)
+}
+
+// start launches the process and initializes it
+func (p *ExternalPlugin) start(ctx context.Context) error {
+	p.mu.Lock()
+	if p.process != nil {
+		p.mu.Unlock()
+		return fmt.Errorf("plugin %s is already running", p.name)
+	}
+	config := p.config
+	p.mu.Unlock()
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	cmd := exec.CommandContext(runCtx, p.path, p.args...)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%d", ProtocolEnv, ProtocolVersion))
+	cmd.WaitDelay = ShutdownGrace
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		cancel()
+		return fmt.Errorf("failed to start plugin %s: %w", p.name, err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		return fmt.Errorf("failed to start plugin %s: %w", p.name, err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		cancel()
+		return fmt.Errorf("failed to start plugin %s: %w", p.name, err)
+	}
+
+	handshake := make(chan error, 1)
+	proc := &pluginProcess{cmd: cmd, stdin: stdin, cancel: cancel, exited: make(chan struct{})}
+	proc.conn = NewConn(stdout, stdin, func(ctx context.Context, method string, params json.RawMessage) (interface{}, error) {
+		if method == MethodHandshake {
+			err := checkHandshake(params)
+			select {
+			case handshake <- err:
+			default: // Only the first handshake counts
+			}
+			return nil, err
+		}
+		return p.handleRequest(ctx, method, params)
+	})
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return fmt.Errorf("failed to start plugin %s: %w", p.name, err)
+	}
+	go p.run(runCtx, proc, stderr)
+
+	timer := time.NewTimer(HandshakeTimeout)
+	defer timer.Stop()
+	select {
+	case err = <-handshake:
+	case <-proc.exited:
+		err = fmt.Errorf("exited before its handshake: %v", proc.err)
+	case <-timer.C:
+		err = fmt.Errorf("no handshake within %s", HandshakeTimeout)
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if err == nil {
+		err = proc.conn.Call(ctx, MethodInitialize, InitializeParams{Config: config}, nil)
+	}
+	if err != nil {
+		proc.kill()
+		return fmt.Errorf("plugin %s: %w", p.name, err)
+	}
+
+	p.mu.Lock()
+	p.process = proc
+	p.mu.Unlock()
+	p.log().Info("plugin started", "pid", cmd.Process.Pid)
+	return nil
+}
+
+// checkHandshake accepts a handshake for this protocol version
+func checkHandshake(params json.RawMessage) error {
+	var handshake HandshakeParams
+	if err := json.Unmarshal(params, &handshake); err != nil {
+		return fmt.Errorf("invalid handshake: %w", err)
+	}
+	if handshake.Protocol != ProtocolVersion {
+		return fmt.Errorf("unsupported plugin protocol %d (want %d)", handshake.Protocol, ProtocolVersion)
+	}
+	return nil
+}
+
+// run serves the process's connection and logs its stderr until it exits
+func (p *ExternalPlugin) run(ctx context.Context, proc *pluginProcess, stderr io.Reader) {
+	logger := p.log()
+	p.mu.Lock()
+	redact := secretRedactor(p.config)
+	p.mu.Unlock()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			logger.Info("plugin output", "line", redact.Replace(scanner.Text()))
+		}
+	}()
+
+	proc.conn.Run(ctx)
+	// Wait closes the pipes, so every read has to be finished first
+	wg.Wait()
+	proc.err = proc.cmd.Wait()
+	if proc.err == nil {
+		proc.err = errors.New("exited")
+	}
+	proc.cancel()
+	close(proc.exited)
+}
+
+// minRedacted is the shortest secret redacted from plugin output; shorter
+// values would redact ordinary text
+const minRedacted = 4
+
+// secretRedactor returns a replacer redacting the secret values of a
+// plugin's config, such as its token, wherever they appear
+func secretRedactor(cfg map[string]string) *strings.Replacer {
+	var secrets []string
+	for key, value := range cfg {
+		if config.IsSecretSetting(key) && len(value) >= minRedacted {
+			secrets = append(secrets, value)
+		}
+	}
+	// Longer secrets first, so one containing another is redacted whole
+	sort.Slice(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+	pairs := make([]string, 0, 2*len(secrets))
+	for _, secret := range secrets {
+		pairs = append(pairs, secret, config.Redacted)
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// kill ends the process and waits for it to be reaped
+func (proc *pluginProcess) kill() {
+	proc.cancel()
+	<-proc.exited
+}
+
+// handleRequest answers a call from the plugin process
+func (p *ExternalPlugin) handleRequest(ctx context.Context, method string, params json.RawMessage) (interface{}, error) {
+	p.mu.Lock()
+	messages, interactions := p.messages, p.interactions
+	p.mu.Unlock()
+
+	switch method {
+	case MethodMessage:
+		var message Message
+		if err := json.Unmarshal(params, &message); err != nil {
+			return nil, fmt.Errorf("invalid message: %w", err)
+		}
+		message.Platform = p.name
+		if messages == nil {
+			return Reply{}, nil
+		}
+		reply, err := messages(ctx, &message)
+		return Reply{Reply: reply}, err
+	case MethodInteraction:
+		var interaction Interaction
+		if err := json.Unmarshal(params, &interaction); err != nil {
+			return nil, fmt.Errorf("invalid interaction: %w", err)
+		}
+		interaction.Platform = p.name
+		if interactions == nil {
+			return nil, fmt.Errorf("no interaction handler for platform: %s", p.name)
+		}
+		reply, err := interactions(ctx, &interaction)
+		return Reply{Reply: reply}, err
+	default:
+		return nil, fmt.Errorf("unknown method: %s", method)
+	}
+}
+
+// call sends a request to the running process
+func (p *ExternalPlugin) call(ctx context.Context, method string, params interface{}) error {
+	p.mu.Lock()
+	proc := p.process
+	p.mu.Unlock()
+	if proc == nil {
+		return fmt.Errorf("%w: %s", ErrPluginNotRunning, p.name)
+	}
+	return proc.conn.Call(ctx, method, params, nil)
+}
+
+// HandleMessage passes a message the otter routed to the plugin to its
+// process
+func (p *ExternalPlugin) HandleMessage(ctx context.Context, message *Message) error {
+	return p.call(ctx, MethodHandle, message)
+}
+
+// SendMessage asks the plugin process to deliver a message to its platform
+func (p *ExternalPlugin) SendMessage(ctx context.Context, message *Message) error {
+	return p.call(ctx, MethodSend, message)
+}
+
+// Ping checks the plugin process is running and answering
+func (p *ExternalPlugin) Ping(ctx context.Context) error {
+	return p.call(ctx, MethodPing, nil)
+}
+
+// Exited returns a channel closed when the current process exits, or nil
+// when none is running
+func (p *ExternalPlugin) Exited() <-chan struct{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.process == nil {
+		return nil
+	}
+	return p.process.exited
+}
+
+// Shutdown asks the plugin process to shut down and stops it
+func (p *ExternalPlugin) Shutdown(ctx context.Context) error {
+	p.stop(ctx)
+	return nil
+}
+
+// stop asks the process to shut down, closes its stdin and kills it if it
+// has not exited within ShutdownGrace or ctx's deadline
+func (p *ExternalPlugin) stop(ctx context.Context) {
+	p.mu.Lock()
+	proc := p.process
+	p.process = nil
+	p.mu.Unlock()
+	if proc == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ShutdownGrace)
+	defer cancel()
+	if err := proc.conn.Call(ctx, MethodShutdown, nil, nil); err != nil {
+		p.log().Debug("plugin shutdown call failed", "error", err)
+	}
+	proc.stdin.Close()
+	select {
+	case <-proc.exited:
+	case <-ctx.Done():
+		p.log().Warn("plugin did not exit, killing it")
+	}
+	proc.kill()
+}
+
+// DiscoverPlugins returns the plugins in dir: every executable regular file
+// not starting with a dot, named after the file without its extension
+func DiscoverPlugins(dir string) ([]*ExternalPlugin, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugins directory: %w", err)
+	}
+
+	var found []*ExternalPlugin
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0o111 == 0 {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		found = append(found, NewExternalPlugin(name, filepath.Join(dir, entry.Name())))
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].name < found[j].name })
+	return found, nil
+}
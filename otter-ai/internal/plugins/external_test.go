@@ -0,0 +1,165 @@
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"otter-ai/internal/config"
+)
+
+// helperPluginEnv makes the test binary act as a plugin process
+const helperPluginEnv = "OTTER_TEST_HELPER_PLUGIN"
+
+// TestHelperPlugin is the plugin process used by the tests below. It
+// reports every message it is asked to send back to the otter as a received
+// message, and exits when asked to send "exit".
+func TestHelperPlugin(t *testing.T) {
+	if os.Getenv(helperPluginEnv) == "" {
+		t.Skip("helper process")
+	}
+
+	ctx := context.Background()
+	var conn *Conn
+	conn = NewConn(os.Stdin, os.Stdout, func(ctx context.Context, method string, params json.RawMessage) (interface{}, error) {
+		switch method {
+		case MethodInitialize, MethodPing, MethodShutdown, MethodHandle:
+			return struct{}{}, nil
+		case MethodSend:
+			var message Message
+			if err := json.Unmarshal(params, &message); err != nil {
+				return nil, err
+			}
+			if message.Content == "exit" {
+				os.Exit(3)
+			}
+			var reply Reply
+			if err := conn.Call(ctx, MethodMessage, message, &reply); err != nil {
+				return nil, err
+			}
+			return struct{}{}, nil
+		}
+		return nil, fmt.Errorf("unknown method: %s", method)
+	})
+	go func() {
+		if err := conn.Call(ctx, MethodHandshake, HandshakeParams{Protocol: ProtocolVersion}, nil); err != nil {
+			os.Exit(2)
+		}
+	}()
+	conn.Run(ctx)
+	os.Exit(0)
+}
+
+// helperPluginDir returns a plugins directory holding a plugin named name
+// that runs TestHelperPlugin
+func helperPluginDir(t *testing.T, name string) string {
+	t.Helper()
+	t.Setenv(helperPluginEnv, "1")
+	dir := t.TempDir()
+	script := fmt.Sprintf("#!/bin/sh\nexec %q -test.run='^TestHelperPlugin$'\n", os.Args[0])
+	if err := os.WriteFile(filepath.Join(dir, name+".sh"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// Not executable, so not a plugin
+	if err := os.WriteFile(filepath.Join(dir, "README"), []byte("plugins"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestDiscoverPlugins(t *testing.T) {
+	dir := helperPluginDir(t, "echo")
+	found, err := DiscoverPlugins(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].Name() != "echo" {
+		t.Fatalf("found %d plugins, want only echo", len(found))
+	}
+
+	if _, err := DiscoverPlugins(filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected error for a missing directory")
+	}
+}
+
+func TestManager_LoadAll_ExternalPlugin(t *testing.T) {
+	m := NewManager(config.PluginConfig{Dir: helperPluginDir(t, "echo")})
+	received := make(chan *Message, 1)
+	m.SetMessageHandler(func(_ context.Context, message *Message) (string, error) {
+		received <- message
+		return "pong", nil
+	})
+
+	ctx := context.Background()
+	if err := m.LoadAll(ctx); err != nil {
+		t.Fatalf("LoadAll: %v", err)
+	}
+	defer m.UnloadAll(ctx)
+
+	if err := m.SendMessage(ctx, "echo", &Message{ChannelID: "c1", Content: "ping"}); err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	select {
+	case message := <-received:
+		if message.Platform != "echo" || message.ChannelID != "c1" || message.Content != "ping" {
+			t.Errorf("received %+v", message)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("message from plugin process not received")
+	}
+}
+
+func TestManager_LoadAll_NotEnabled(t *testing.T) {
+	m := NewManager(config.PluginConfig{Dir: helperPluginDir(t, "echo"), Enabled: []string{"slack"}})
+	if err := m.LoadAll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if names := m.Names(); len(names) != 0 {
+		t.Errorf("loaded %v, want none", names)
+	}
+}
+
+func TestManager_RestartsExitedPlugin(t *testing.T) {
+	m := NewManager(config.PluginConfig{Dir: helperPluginDir(t, "echo"), HealthInterval: 50 * time.Millisecond})
+	ctx := context.Background()
+	if err := m.LoadAll(ctx); err != nil {
+		t.Fatalf("LoadAll: %v", err)
+	}
+	defer m.UnloadAll(ctx)
+
+	plugin, _ := m.Get("echo")
+	external := plugin.(*ExternalPlugin)
+	exited := external.Exited()
+	if err := m.SendMessage(ctx, "echo", &Message{Content: "exit"}); err == nil {
+		t.Fatal("expected error from a plugin that exits")
+	}
+	<-exited
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		err := external.Ping(ctx)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, ErrPluginNotRunning) && !errors.Is(err, ErrConnClosed) {
+			t.Fatalf("Ping: %v", err)
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("plugin not restarted")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestSecretRedactor(t *testing.T) {
+	redact := secretRedactor(map[string]string{"token": "bot-token-123", "api_key": "bot", "channel": "general"})
+	got := redact.Replace("connecting to general with bot-token-123")
+	if got != "connecting to general with "+config.Redacted {
+		t.Errorf("Replace = %q", got)
+	}
+}
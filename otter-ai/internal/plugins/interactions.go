@@ -0,0 +1,64 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+)
+
+// Action styles plugins map to their platform's button styles
+const (
+	ActionStylePrimary   = "primary"
+	ActionStyleDanger    = "danger"
+	ActionStyleSecondary = "secondary"
+)
+
+// Action is an interactive component attached to an outgoing message:
+// a button, or a reaction on platforms without buttons
+type Action struct {
+	ID    string `json:"id"` // Returned in the Interaction when the action is used
+	Label string `json:"label"`
+	Emoji string `json:"emoji,omitempty"` // Reaction used on platforms without buttons
+	Style string `json:"style,omitempty"`
+}
+
+// Interaction is a platform user's use of an Action
+type Interaction struct {
+	Platform  string `json:"platform,omitempty"`
+	ChannelID string `json:"channel_id,omitempty"`
+	MessageID string `json:"message_id,omitempty"` // Platform ID of the message the action was attached to
+	UserID    string `json:"user_id"`
+	Username  string `json:"username,omitempty"`
+	ActionID  string `json:"action_id"`
+	Timestamp int64  `json:"timestamp,omitempty"`
+}
+
+// InteractionHandler processes an interaction and returns a short reply
+// for the platform to show the user, typically only to them
+type InteractionHandler func(ctx context.Context, interaction *Interaction) (string, error)
+
+// Interactive is implemented by plugins that can render Actions and report
+// their use. The manager passes its own dispatcher to SetInteractionHandler
+// when the plugin is registered.
+type Interactive interface {
+	SetInteractionHandler(handler InteractionHandler)
+}
+
+// SetInteractionHandler sets the handler interactions from every plugin are
+// routed to
+func (m *Manager) SetInteractionHandler(handler InteractionHandler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.interactions = handler
+}
+
+// HandleInteraction routes a platform interaction to the interaction handler
+func (m *Manager) HandleInteraction(ctx context.Context, interaction *Interaction) (string, error) {
+	m.mu.RLock()
+	handler := m.interactions
+	m.mu.RUnlock()
+
+	if handler == nil {
+		return "", fmt.Errorf("no interaction handler for platform: %s", interaction.Platform)
+	}
+	return handler(ctx, interaction)
+}
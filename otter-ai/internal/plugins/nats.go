@@ -0,0 +1,302 @@
+package plugins
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NATS client limits
+const (
+	natsDialTimeout  = 10 * time.Second
+	natsMaxPayload   = MaxFrameSize
+	natsDefaultPort  = "4222"
+	natsClientName   = "otter-ai"
+	natsClientLang   = "go"
+	natsClientSemVer = "1.0.0"
+)
+
+// ErrNATSClosed is returned by operations on a closed NATS connection
+var ErrNATSClosed = errors.New("nats connection closed")
+
+// natsHandler receives a message delivered to a subscription
+type natsHandler func(subject, reply string, data []byte)
+
+// natsConn is a minimal client for the NATS core protocol: publish,
+// queue-group subscriptions and keep-alives over plain TCP. It speaks just
+// enough of the protocol for the plugin bus; it does not reconnect.
+type natsConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	writeMu sync.Mutex
+	writer  *bufio.Writer
+
+	mu      sync.Mutex
+	nextSID int
+	subs    map[int]natsHandler
+	pong    chan struct{}
+	done    chan struct{}
+	err     error
+}
+
+// natsConnect is the CONNECT message sent after the server's INFO
+type natsConnect struct {
+	Verbose  bool   `json:"verbose"`
+	Pedantic bool   `json:"pedantic"`
+	Name     string `json:"name"`
+	Lang     string `json:"lang"`
+	Version  string `json:"version"`
+	Protocol int    `json:"protocol"`
+	User     string `json:"user,omitempty"`
+	Pass     string `json:"pass,omitempty"`
+	Token    string `json:"auth_token,omitempty"`
+}
+
+// dialNATS connects to a nats:// URL, authenticating with its user and
+// password, or its user as a token when there is no password
+func dialNATS(ctx context.Context, rawURL string) (*natsConn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "nats" || u.Host == "" {
+		return nil, fmt.Errorf("invalid NATS URL %q: want nats://[user[:password]@]host[:port]", rawURL)
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), natsDefaultPort)
+	}
+
+	dialer := net.Dialer{Timeout: natsDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	c := &natsConn{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+		writer: bufio.NewWriter(conn),
+		subs:   make(map[int]natsHandler),
+		pong:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+
+	connect := natsConnect{Name: natsClientName, Lang: natsClientLang, Version: natsClientSemVer, Protocol: 1}
+	if u.User != nil {
+		if pass, ok := u.User.Password(); ok {
+			connect.User, connect.Pass = u.User.Username(), pass
+		} else {
+			connect.Token = u.User.Username()
+		}
+	}
+	if err := c.handshake(connect); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	go c.readLoop()
+	return c, nil
+}
+
+// handshake reads the server's INFO, sends CONNECT and waits for the PONG
+// that confirms it was accepted
+func (c *natsConn) handshake(connect natsConnect) error {
+	c.conn.SetDeadline(time.Now().Add(natsDialTimeout))
+	defer c.conn.SetDeadline(time.Time{})
+
+	line, err := c.readLine()
+	if err != nil {
+		return fmt.Errorf("failed to read NATS server info: %w", err)
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		return fmt.Errorf("unexpected NATS greeting: %q", line)
+	}
+
+	data, err := json.Marshal(connect)
+	if err != nil {
+		return err
+	}
+	if err := c.write("CONNECT "+string(data)+"\r\nPING\r\n", nil); err != nil {
+		return err
+	}
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return fmt.Errorf("failed to connect to NATS: %w", err)
+		}
+		switch {
+		case line == "PONG":
+			return nil
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("NATS refused the connection: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
+
+// readLine reads one protocol line without its CRLF
+func (c *natsConn) readLine() (string, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// write sends a protocol line and optional payload followed by CRLF
+func (c *natsConn) write(line string, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.writer.WriteString(line)
+	if payload != nil {
+		c.writer.Write(payload)
+		c.writer.WriteString("\r\n")
+	}
+	if err := c.writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write to NATS: %w", err)
+	}
+	return nil
+}
+
+// readLoop dispatches messages to subscriptions and answers server pings
+// until the connection fails
+func (c *natsConn) readLoop() {
+	var err error
+	for err == nil {
+		var line string
+		if line, err = c.readLine(); err != nil {
+			break
+		}
+		switch {
+		case strings.HasPrefix(line, "MSG "):
+			err = c.deliver(strings.Fields(line)[1:])
+		case line == "PING":
+			err = c.write("PONG\r\n", nil)
+		case line == "PONG":
+			select {
+			case c.pong <- struct{}{}:
+			default:
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			err = fmt.Errorf("NATS error: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+		// INFO updates and +OK need no answer
+	}
+
+	c.mu.Lock()
+	c.err = err
+	c.mu.Unlock()
+	c.conn.Close()
+	close(c.done)
+}
+
+// deliver reads the payload of a MSG with arguments subject, sid,
+// optional reply subject and size, and passes it to its subscription
+func (c *natsConn) deliver(args []string) error {
+	if len(args) != 3 && len(args) != 4 {
+		return fmt.Errorf("malformed NATS message header: %v", args)
+	}
+	size, err := strconv.Atoi(args[len(args)-1])
+	if err != nil || size < 0 || size > natsMaxPayload {
+		return fmt.Errorf("invalid NATS message size: %s", args[len(args)-1])
+	}
+	payload := make([]byte, size+2)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return err
+	}
+
+	sid, _ := strconv.Atoi(args[1])
+	var reply string
+	if len(args) == 4 {
+		reply = args[2]
+	}
+	c.mu.Lock()
+	handler := c.subs[sid]
+	c.mu.Unlock()
+	if handler != nil {
+		go handler(args[0], reply, payload[:size])
+	}
+	return nil
+}
+
+// Publish sends data to subject, asking for answers on reply when it is set
+func (c *natsConn) Publish(subject, reply string, data []byte) error {
+	if len(data) > natsMaxPayload {
+		return fmt.Errorf("NATS message too large: %d bytes", len(data))
+	}
+	line := "PUB " + subject
+	if reply != "" {
+		line += " " + reply
+	}
+	line += " " + strconv.Itoa(len(data)) + "\r\n"
+	if err := c.write(line, data); err != nil {
+		return c.closedErr(err)
+	}
+	return nil
+}
+
+// Subscribe delivers messages on subject to handler. Subscribers sharing a
+// non-empty queue group each get a share of the messages instead of all.
+func (c *natsConn) Subscribe(subject, queue string, handler natsHandler) error {
+	c.mu.Lock()
+	c.nextSID++
+	sid := c.nextSID
+	c.subs[sid] = handler
+	c.mu.Unlock()
+
+	line := "SUB " + subject
+	if queue != "" {
+		line += " " + queue
+	}
+	if err := c.write(line+" "+strconv.Itoa(sid)+"\r\n", nil); err != nil {
+		return c.closedErr(err)
+	}
+	return nil
+}
+
+// Ping checks the server answers
+func (c *natsConn) Ping(ctx context.Context) error {
+	if err := c.write("PING\r\n", nil); err != nil {
+		return c.closedErr(err)
+	}
+	select {
+	case <-c.pong:
+		return nil
+	case <-c.done:
+		return c.closedErr(nil)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Done is closed when the connection fails or is closed
+func (c *natsConn) Done() <-chan struct{} {
+	return c.done
+}
+
+// Close closes the connection
+func (c *natsConn) Close() error {
+	c.conn.Close()
+	<-c.done
+	return nil
+}
+
+// closedErr returns why the connection closed, or err when it is still open
+func (c *natsConn) closedErr(err error) error {
+	select {
+	case <-c.done:
+	default:
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil && !errors.Is(c.err, net.ErrClosed) && !errors.Is(c.err, io.EOF) {
+		return fmt.Errorf("%w: %v", ErrNATSClosed, c.err)
+	}
+	return ErrNATSClosed
+}
@@ -3,9 +3,13 @@ package plugins
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"sort"
 	"sync"
+	"sync/atomic"
 
 	"otter-ai/internal/config"
+	"otter-ai/internal/events"
 )
 
 // Plugin is the interface all plugins must implement
@@ -26,91 +30,149 @@ type Plugin interface {
 	Shutdown(ctx context.Context) error
 }
 
-// Message represents a plugin message
+// Message represents a plugin message. An outgoing message with a UserID
+// and no ChannelID is a direct message to that user; one with a ThreadID is
+// posted in that thread.
 type Message struct {
-	ID        string
-	Platform  string
-	ChannelID string
-	UserID    string
-	Username  string
-	Content   string
-	Timestamp int64
-	Metadata  map[string]interface{}
+	ID        string                 `json:"id,omitempty"`
+	Platform  string                 `json:"platform,omitempty"`
+	ChannelID string                 `json:"channel_id,omitempty"`
+	ThreadID  string                 `json:"thread_id,omitempty"` // Thread or reply chain within the channel; replies are posted to it
+	UserID    string                 `json:"user_id,omitempty"`
+	Username  string                 `json:"username,omitempty"`
+	Content   string                 `json:"content"`
+	Timestamp int64                  `json:"timestamp,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	Actions   []Action               `json:"actions,omitempty"` // Interactive components; ignored by plugins that are not Interactive
+}
+
+// MessageHandler processes a message a plugin received from its platform
+// and returns the reply for the plugin to post; empty when there is none
+type MessageHandler func(ctx context.Context, message *Message) (string, error)
+
+// Receiver is implemented by plugins that receive messages from their
+// platform. The manager passes its own dispatcher to SetMessageHandler when
+// the plugin is loaded or registered.
+type Receiver interface {
+	SetMessageHandler(handler MessageHandler)
 }
 
 // Manager manages all loaded plugins
 type Manager struct {
 	config  config.PluginConfig
 	plugins map[string]Plugin
-	mu      sync.RWMutex
+	events  atomic.Pointer[events.Bus] // Receives plugin.message and plugins.changed events
+	logger  atomic.Pointer[slog.Logger]
+	// messages receives messages from Receiver plugins
+	messages MessageHandler
+	// interactions receives actions used on messages from Interactive plugins
+	interactions InteractionHandler
+	// threads maps platform threads to the sessions they continue when they
+	// do not use their own
+	threads map[Thread]string
+	// bus connects plugins through NATS when configured
+	bus *Bus
+	// supervisors stops the supervision of each supervised plugin
+	supervisors map[string]context.CancelFunc
+	supervising sync.WaitGroup
+	mu          sync.RWMutex
+}
+
+// Message directions reported in plugin message events
+const (
+	DirectionInbound  = "inbound"
+	DirectionOutbound = "outbound"
+)
+
+// MessageEvent describes a plugin message in published events
+type MessageEvent struct {
+	Direction string `json:"direction"`
+	Platform  string `json:"platform"`
+	ChannelID string `json:"channel_id,omitempty"`
+	ThreadID  string `json:"thread_id,omitempty"`
+	UserID    string `json:"user_id,omitempty"`
+	Username  string `json:"username,omitempty"`
+	Content   string `json:"content"`
+	Timestamp int64  `json:"timestamp,omitempty"`
 }
 
 // NewManager creates a new plugin manager
 func NewManager(config config.PluginConfig) *Manager {
 	return &Manager{
-		config:  config,
-		plugins: make(map[string]Plugin),
+		config:      config,
+		plugins:     make(map[string]Plugin),
+		threads:     make(map[Thread]string),
+		supervisors: make(map[string]context.CancelFunc),
 	}
 }
 
-// LoadAll loads all enabled plugins
-func (m *Manager) LoadAll(ctx context.Context) error {
-	var errors []error
+// SetLogger sets the logger plugin lifecycle and output are logged to
+func (m *Manager) SetLogger(logger *slog.Logger) {
+	m.logger.Store(logger)
+}
 
-	// Load Discord plugin if enabled
-	if m.config.Discord.Enabled {
-		plugin, err := NewDiscordPlugin()
-		if err != nil {
-			errors = append(errors, fmt.Errorf("discord: %w", err))
-		} else {
-			if err := plugin.Initialize(ctx, m.config.Discord.Config); err != nil {
-				errors = append(errors, fmt.Errorf("discord init: %w", err))
-			} else {
-				m.register(plugin)
-			}
-		}
+// log returns the manager's logger
+func (m *Manager) log() *slog.Logger {
+	if logger := m.logger.Load(); logger != nil {
+		return logger
 	}
+	return slog.Default()
+}
 
-	// Load Signal plugin if enabled
-	if m.config.Signal.Enabled {
-		plugin, err := NewSignalPlugin()
-		if err != nil {
-			errors = append(errors, fmt.Errorf("signal: %w", err))
-		} else {
-			if err := plugin.Initialize(ctx, m.config.Signal.Config); err != nil {
-				errors = append(errors, fmt.Errorf("signal init: %w", err))
-			} else {
-				m.register(plugin)
-			}
-		}
+// SetEventBus sets the bus plugin messages are published to
+func (m *Manager) SetEventBus(bus *events.Bus) {
+	m.events.Store(bus)
+}
+
+// publishMessage reports a message that passed through a plugin
+func (m *Manager) publishMessage(direction, platform string, message *Message) {
+	m.events.Load().Publish(events.PluginMessage, MessageEvent{
+		Direction: direction,
+		Platform:  platform,
+		ChannelID: message.ChannelID,
+		ThreadID:  message.ThreadID,
+		UserID:    message.UserID,
+		Username:  message.Username,
+		Content:   message.Content,
+		Timestamp: message.Timestamp,
+	})
+}
+
+// LoadAll discovers the plugins in the configured directory and starts
+// those that are enabled, initializing each with its settings. Plugins that
+// fail to start are skipped and reported in the returned error; the others
+// are registered and supervised.
+func (m *Manager) LoadAll(ctx context.Context) error {
+	if m.config.Dir == "" {
+		return nil
+	}
+	found, err := DiscoverPlugins(m.config.Dir)
+	if err != nil {
+		return err
 	}
 
-	// Load Telegram plugin if enabled
-	if m.config.Telegram.Enabled {
-		plugin, err := NewTelegramPlugin()
-		if err != nil {
-			errors = append(errors, fmt.Errorf("telegram: %w", err))
-		} else {
-			if err := plugin.Initialize(ctx, m.config.Telegram.Config); err != nil {
-				errors = append(errors, fmt.Errorf("telegram init: %w", err))
-			} else {
-				m.register(plugin)
-			}
+	var errors []error
+	for _, plugin := range found {
+		name := plugin.Name()
+		if !m.enabled(name) {
+			m.log().Debug("plugin not enabled", "plugin", name)
+			continue
+		}
+		if _, exists := m.Get(name); exists {
+			errors = append(errors, fmt.Errorf("%s: another plugin has the same name", plugin.Path()))
+			continue
 		}
-	}
 
-	// Load Slack plugin if enabled
-	if m.config.Slack.Enabled {
-		plugin, err := NewSlackPlugin()
-		if err != nil {
-			errors = append(errors, fmt.Errorf("slack: %w", err))
-		} else {
-			if err := plugin.Initialize(ctx, m.config.Slack.Config); err != nil {
-				errors = append(errors, fmt.Errorf("slack init: %w", err))
-			} else {
-				m.register(plugin)
-			}
+		m.log().Debug("loading plugin", "plugin", name, "settings", m.config.Settings[name])
+		plugin.SetLogger(m.log())
+		plugin.SetMessageHandler(m.ReceiveMessage)
+		plugin.SetInteractionHandler(m.HandleInteraction)
+		if err := plugin.Initialize(ctx, m.settings(name)); err != nil {
+			errors = append(errors, err)
+			continue
 		}
+		m.Register(plugin)
+		m.supervise(name, plugin)
 	}
 
 	if len(errors) > 0 {
@@ -120,11 +182,69 @@ func (m *Manager) LoadAll(ctx context.Context) error {
 	return nil
 }
 
-// register adds a plugin to the manager
-func (m *Manager) register(plugin Plugin) {
+// enabled reports whether a discovered plugin may be loaded: it must be
+// listed in Enabled when that is set, and not disabled in its settings
+func (m *Manager) enabled(name string) bool {
+	if settings, ok := m.config.Settings[name]; ok && !settings.Enabled {
+		return false
+	}
+	if len(m.config.Enabled) == 0 {
+		return true
+	}
+	for _, enabled := range m.config.Enabled {
+		if enabled == name {
+			return true
+		}
+	}
+	return false
+}
+
+// settings returns the config a plugin is initialized with: its settings'
+// Config, with the token under "token"
+func (m *Manager) settings(name string) map[string]string {
+	settings := m.config.Settings[name]
+	config := make(map[string]string, len(settings.Config)+1)
+	for key, value := range settings.Config {
+		config[key] = value
+	}
+	if settings.Token != "" {
+		config["token"] = settings.Token
+	}
+	return config
+}
+
+// Register adds an initialized plugin to the manager, replacing any plugin
+// with the same name
+func (m *Manager) Register(plugin Plugin) {
+	if receiver, ok := plugin.(Receiver); ok {
+		receiver.SetMessageHandler(m.ReceiveMessage)
+	}
+	if interactive, ok := plugin.(Interactive); ok {
+		interactive.SetInteractionHandler(m.HandleInteraction)
+	}
+
 	m.mu.Lock()
-	defer m.mu.Unlock()
 	m.plugins[plugin.Name()] = plugin
+	m.mu.Unlock()
+	m.publishChanged()
+}
+
+// Names returns the names of the loaded plugins, sorted
+func (m *Manager) Names() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := make([]string, 0, len(m.plugins))
+	for name := range m.plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// publishChanged reports the loaded plugins after one is added or removed
+func (m *Manager) publishChanged() {
+	m.events.Load().Publish(events.PluginsChanged, m.Names())
 }
 
 // Get retrieves a plugin by name
@@ -145,7 +265,44 @@ func (m *Manager) HandleMessage(ctx context.Context, message *Message) error {
 		return fmt.Errorf("no plugin for platform: %s", message.Platform)
 	}
 
-	return plugin.HandleMessage(ctx, message)
+	if err := plugin.HandleMessage(ctx, message); err != nil {
+		return err
+	}
+	m.publishMessage(DirectionInbound, message.Platform, message)
+	return nil
+}
+
+// SetMessageHandler sets the handler messages from every plugin are routed
+// to
+func (m *Manager) SetMessageHandler(handler MessageHandler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = handler
+}
+
+// ReceiveMessage routes a message a plugin received from its platform to
+// the message handler and returns its reply
+func (m *Manager) ReceiveMessage(ctx context.Context, message *Message) (string, error) {
+	m.mu.RLock()
+	handler := m.messages
+	m.mu.RUnlock()
+
+	if handler == nil {
+		return "", fmt.Errorf("no message handler for platform: %s", message.Platform)
+	}
+	m.publishMessage(DirectionInbound, message.Platform, message)
+	reply, err := handler(ctx, message)
+	if err != nil {
+		return "", err
+	}
+	if reply != "" {
+		m.publishMessage(DirectionOutbound, message.Platform, &Message{
+			ChannelID: message.ChannelID,
+			ThreadID:  message.ThreadID,
+			Content:   reply,
+		})
+	}
+	return reply, nil
 }
 
 // SendMessage sends a message through a specific plugin
@@ -158,11 +315,19 @@ func (m *Manager) SendMessage(ctx context.Context, platform string, message *Mes
 		return fmt.Errorf("no plugin for platform: %s", platform)
 	}
 
-	return plugin.SendMessage(ctx, message)
+	if err := plugin.SendMessage(ctx, message); err != nil {
+		return err
+	}
+	m.publishMessage(DirectionOutbound, platform, message)
+	return nil
 }
 
 // UnloadAll unloads all plugins
 func (m *Manager) UnloadAll(ctx context.Context) error {
+	defer m.publishChanged()
+	// Stopped plugins must not be restarted
+	m.stopSupervisors()
+	m.stopBus()
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -182,113 +347,3 @@ func (m *Manager) UnloadAll(ctx context.Context) error {
 
 	return nil
 }
-
-// Plugin stubs
-
-// DiscordPlugin stub
-type DiscordPlugin struct{}
-
-func NewDiscordPlugin() (*DiscordPlugin, error) {
-	return &DiscordPlugin{}, nil
-}
-
-func (p *DiscordPlugin) Name() string {
-	return "discord"
-}
-
-func (p *DiscordPlugin) Initialize(ctx context.Context, config map[string]string) error {
-	return fmt.Errorf("discord plugin not yet implemented")
-}
-
-func (p *DiscordPlugin) HandleMessage(ctx context.Context, message *Message) error {
-	return fmt.Errorf("not implemented")
-}
-
-func (p *DiscordPlugin) SendMessage(ctx context.Context, message *Message) error {
-	return fmt.Errorf("not implemented")
-}
-
-func (p *DiscordPlugin) Shutdown(ctx context.Context) error {
-	return nil
-}
-
-// SignalPlugin stub
-type SignalPlugin struct{}
-
-func NewSignalPlugin() (*SignalPlugin, error) {
-	return &SignalPlugin{}, nil
-}
-
-func (p *SignalPlugin) Name() string {
-	return "signal"
-}
-
-func (p *SignalPlugin) Initialize(ctx context.Context, config map[string]string) error {
-	return fmt.Errorf("signal plugin not yet implemented")
-}
-
-func (p *SignalPlugin) HandleMessage(ctx context.Context, message *Message) error {
-	return fmt.Errorf("not implemented")
-}
-
-func (p *SignalPlugin) SendMessage(ctx context.Context, message *Message) error {
-	return fmt.Errorf("not implemented")
-}
-
-func (p *SignalPlugin) Shutdown(ctx context.Context) error {
-	return nil
-}
-
-// TelegramPlugin stub
-type TelegramPlugin struct{}
-
-func NewTelegramPlugin() (*TelegramPlugin, error) {
-	return &TelegramPlugin{}, nil
-}
-
-func (p *TelegramPlugin) Name() string {
-	return "telegram"
-}
-
-func (p *TelegramPlugin) Initialize(ctx context.Context, config map[string]string) error {
-	return fmt.Errorf("telegram plugin not yet implemented")
-}
-
-func (p *TelegramPlugin) HandleMessage(ctx context.Context, message *Message) error {
-	return fmt.Errorf("not implemented")
-}
-
-func (p *TelegramPlugin) SendMessage(ctx context.Context, message *Message) error {
-	return fmt.Errorf("not implemented")
-}
-
-func (p *TelegramPlugin) Shutdown(ctx context.Context) error {
-	return nil
-}
-
-// SlackPlugin stub
-type SlackPlugin struct{}
-
-func NewSlackPlugin() (*SlackPlugin, error) {
-	return &SlackPlugin{}, nil
-}
-
-func (p *SlackPlugin) Name() string {
-	return "slack"
-}
-
-func (p *SlackPlugin) Initialize(ctx context.Context, config map[string]string) error {
-	return fmt.Errorf("slack plugin not yet implemented")
-}
-
-func (p *SlackPlugin) HandleMessage(ctx context.Context, message *Message) error {
-	return fmt.Errorf("not implemented")
-}
-
-func (p *SlackPlugin) SendMessage(ctx context.Context, message *Message) error {
-	return fmt.Errorf("not implemented")
-}
-
-func (p *SlackPlugin) Shutdown(ctx context.Context) error {
-	return nil
-}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"otter-ai/internal/config"
+	"otter-ai/internal/events"
 )
 
 // --- Manager ---
@@ -33,21 +34,6 @@ func TestManager_LoadAll_NoneEnabled(t *testing.T) {
 	}
 }
 
-func TestManager_LoadAll_AllEnabled(t *testing.T) {
-	cfg := config.PluginConfig{
-		Discord:  config.PluginSettings{Enabled: true, Config: map[string]string{}},
-		Signal:   config.PluginSettings{Enabled: true, Config: map[string]string{}},
-		Telegram: config.PluginSettings{Enabled: true, Config: map[string]string{}},
-		Slack:    config.PluginSettings{Enabled: true, Config: map[string]string{}},
-	}
-	m := NewManager(cfg)
-	err := m.LoadAll(context.Background())
-	// All plugins return "not yet implemented" from Initialize, so this should error
-	if err == nil {
-		t.Error("expected errors from stub plugin Initialize")
-	}
-}
-
 func TestManager_HandleMessage_NoPlatform(t *testing.T) {
 	m := NewManager(config.PluginConfig{})
 	err := m.HandleMessage(context.Background(), &Message{Platform: "discord"})
@@ -72,127 +58,123 @@ func TestManager_UnloadAll_Empty(t *testing.T) {
 	}
 }
 
-// --- Plugin stubs ---
+// --- Manual register + Get ---
 
-func TestDiscordPlugin(t *testing.T) {
-	p, err := NewDiscordPlugin()
-	if err != nil {
-		t.Fatalf("NewDiscordPlugin: %v", err)
-	}
-	if p.Name() != "discord" {
-		t.Errorf("Name() = %q", p.Name())
-	}
-	if err := p.Initialize(context.Background(), nil); err == nil {
-		t.Error("expected error from stub Initialize")
-	}
-	if err := p.HandleMessage(context.Background(), &Message{}); err == nil {
-		t.Error("expected error from stub HandleMessage")
-	}
-	if err := p.SendMessage(context.Background(), &Message{}); err == nil {
-		t.Error("expected error from stub SendMessage")
+func TestManager_RegisterAndGet(t *testing.T) {
+	m := NewManager(config.PluginConfig{})
+	m.Register(echoPlugin{})
+
+	got, ok := m.Get("echo")
+	if !ok {
+		t.Error("expected to find registered plugin")
 	}
-	if err := p.Shutdown(context.Background()); err != nil {
-		t.Errorf("Shutdown: %v", err)
+	if got.Name() != "echo" {
+		t.Errorf("Name() = %q", got.Name())
 	}
 }
 
-func TestSignalPlugin(t *testing.T) {
-	p, err := NewSignalPlugin()
+// --- UnloadAll with registered plugins ---
+
+func TestManager_UnloadAll_WithPlugins(t *testing.T) {
+	m := NewManager(config.PluginConfig{})
+	m.Register(echoPlugin{})
+
+	err := m.UnloadAll(context.Background())
 	if err != nil {
-		t.Fatalf("NewSignalPlugin: %v", err)
-	}
-	if p.Name() != "signal" {
-		t.Errorf("Name() = %q", p.Name())
-	}
-	if err := p.Initialize(context.Background(), nil); err == nil {
-		t.Error("expected error from stub")
-	}
-	if err := p.HandleMessage(context.Background(), &Message{}); err == nil {
-		t.Error("expected error from stub")
-	}
-	if err := p.SendMessage(context.Background(), &Message{}); err == nil {
-		t.Error("expected error from stub")
+		t.Fatalf("UnloadAll: %v", err)
 	}
-	if err := p.Shutdown(context.Background()); err != nil {
-		t.Errorf("Shutdown: %v", err)
+
+	// Should be empty after unload
+	_, ok := m.Get("echo")
+	if ok {
+		t.Error("plugin should be unloaded")
 	}
 }
 
-func TestTelegramPlugin(t *testing.T) {
-	p, err := NewTelegramPlugin()
-	if err != nil {
-		t.Fatalf("NewTelegramPlugin: %v", err)
-	}
-	if p.Name() != "telegram" {
-		t.Errorf("Name() = %q", p.Name())
-	}
-	if err := p.Initialize(context.Background(), nil); err == nil {
-		t.Error("expected error from stub")
+// echoPlugin accepts every message
+type echoPlugin struct{}
+
+func (echoPlugin) Name() string                                        { return "echo" }
+func (echoPlugin) Initialize(context.Context, map[string]string) error { return nil }
+func (echoPlugin) HandleMessage(context.Context, *Message) error       { return nil }
+func (echoPlugin) SendMessage(context.Context, *Message) error         { return nil }
+func (echoPlugin) Shutdown(context.Context) error                      { return nil }
+
+func TestManager_PublishesMessages(t *testing.T) {
+	m := NewManager(config.PluginConfig{})
+	m.Register(echoPlugin{})
+	bus := events.NewBus()
+	m.SetEventBus(bus)
+	sub := bus.Subscribe(0, events.PluginMessage)
+	defer sub.Close()
+
+	if err := m.HandleMessage(context.Background(), &Message{Platform: "echo", Content: "hi"}); err != nil {
+		t.Fatal(err)
 	}
-	if err := p.HandleMessage(context.Background(), &Message{}); err == nil {
-		t.Error("expected error from stub")
+	if err := m.SendMessage(context.Background(), "echo", &Message{Content: "hello"}); err != nil {
+		t.Fatal(err)
 	}
-	if err := p.SendMessage(context.Background(), &Message{}); err == nil {
-		t.Error("expected error from stub")
+
+	inbound := (<-sub.C).Data.(MessageEvent)
+	outbound := (<-sub.C).Data.(MessageEvent)
+	if inbound.Direction != DirectionInbound || inbound.Content != "hi" {
+		t.Errorf("unexpected inbound event: %+v", inbound)
 	}
-	if err := p.Shutdown(context.Background()); err != nil {
-		t.Errorf("Shutdown: %v", err)
+	if outbound.Direction != DirectionOutbound || outbound.Platform != "echo" {
+		t.Errorf("unexpected outbound event: %+v", outbound)
 	}
 }
 
-func TestSlackPlugin(t *testing.T) {
-	p, err := NewSlackPlugin()
-	if err != nil {
-		t.Fatalf("NewSlackPlugin: %v", err)
-	}
-	if p.Name() != "slack" {
-		t.Errorf("Name() = %q", p.Name())
-	}
-	if err := p.Initialize(context.Background(), nil); err == nil {
-		t.Error("expected error from stub")
-	}
-	if err := p.HandleMessage(context.Background(), &Message{}); err == nil {
-		t.Error("expected error from stub")
-	}
-	if err := p.SendMessage(context.Background(), &Message{}); err == nil {
-		t.Error("expected error from stub")
-	}
-	if err := p.Shutdown(context.Background()); err != nil {
-		t.Errorf("Shutdown: %v", err)
-	}
+// buttonPlugin is an Interactive plugin that keeps the handler it is given
+type buttonPlugin struct {
+	echoPlugin
+	handler InteractionHandler
 }
 
-// --- Manual register + Get ---
+func (p *buttonPlugin) Name() string                                     { return "buttons" }
+func (p *buttonPlugin) SetInteractionHandler(handler InteractionHandler) { p.handler = handler }
 
-func TestManager_RegisterAndGet(t *testing.T) {
+func TestManager_RoutesInteractions(t *testing.T) {
 	m := NewManager(config.PluginConfig{})
-	p, _ := NewDiscordPlugin()
-	m.register(p)
+	p := &buttonPlugin{}
+	m.Register(p)
+	if p.handler == nil {
+		t.Fatal("interactive plugins should get a handler when registered")
+	}
 
-	got, ok := m.Get("discord")
-	if !ok {
-		t.Error("expected to find registered plugin")
+	if _, err := p.handler(context.Background(), &Interaction{Platform: "buttons", ActionID: "a"}); err == nil {
+		t.Error("expected error without an interaction handler")
 	}
-	if got.Name() != "discord" {
-		t.Errorf("Name() = %q", got.Name())
+
+	var got *Interaction
+	m.SetInteractionHandler(func(_ context.Context, in *Interaction) (string, error) {
+		got = in
+		return "done", nil
+	})
+	reply, err := p.handler(context.Background(), &Interaction{Platform: "buttons", ActionID: "a"})
+	if err != nil || reply != "done" || got == nil || got.ActionID != "a" {
+		t.Errorf("reply = %q, err = %v, got = %+v", reply, err, got)
 	}
 }
 
-// --- UnloadAll with registered plugins ---
-
-func TestManager_UnloadAll_WithPlugins(t *testing.T) {
+func TestManager_NamesAndChangeEvents(t *testing.T) {
 	m := NewManager(config.PluginConfig{})
-	p, _ := NewDiscordPlugin()
-	m.register(p)
+	bus := events.NewBus()
+	m.SetEventBus(bus)
+	sub := bus.Subscribe(0, events.PluginsChanged)
+	defer sub.Close()
 
-	err := m.UnloadAll(context.Background())
-	if err != nil {
-		t.Fatalf("UnloadAll: %v", err)
+	m.Register(echoPlugin{})
+	if names := (<-sub.C).Data.([]string); len(names) != 1 || names[0] != "echo" {
+		t.Errorf("registered event = %v, want [echo]", names)
 	}
-
-	// Should be empty after unload
-	_, ok := m.Get("discord")
-	if ok {
-		t.Error("plugin should be unloaded")
+	if err := m.UnloadAll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if names := (<-sub.C).Data.([]string); len(names) != 0 {
+		t.Errorf("unloaded event = %v, want none", names)
+	}
+	if names := m.Names(); len(names) != 0 {
+		t.Errorf("Names = %v after unload", names)
 	}
 }
@@ -0,0 +1,233 @@
+package plugins
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ProtocolVersion is the version of the plugin protocol spoken over a
+// plugin process's stdin and stdout. Plugins announce the version they speak
+// in their handshake and are refused when it differs.
+const ProtocolVersion = 1
+
+// MaxFrameSize bounds a single protocol frame
+const MaxFrameSize = 4 << 20
+
+// Methods the otter calls on a plugin process
+const (
+	MethodInitialize = "initialize" // initializeParams; sent once after the handshake
+	MethodSend       = "send"       // Message to deliver to the platform
+	MethodHandle     = "handle"     // Message routed to the plugin by the otter
+	MethodPing       = "ping"       // Health check; any successful response is healthy
+	MethodShutdown   = "shutdown"   // Sent before stdin is closed
+)
+
+// Methods a plugin process calls on the otter
+const (
+	MethodHandshake   = "handshake"   // HandshakeParams; must be the plugin's first call
+	MethodMessage     = "message"     // Message received from the platform; returns a Reply
+	MethodInteraction = "interaction" // Interaction with an Action; returns a Reply
+)
+
+// ErrConnClosed is returned by calls on a connection whose peer has gone
+var ErrConnClosed = errors.New("plugin connection closed")
+
+// HandshakeParams is the first call a plugin process makes
+type HandshakeParams struct {
+	Protocol int `json:"protocol"`
+}
+
+// InitializeParams carries a plugin's settings to its process
+type InitializeParams struct {
+	Config map[string]string `json:"config"`
+}
+
+// Reply is the otter's answer to a platform message or interaction, for the
+// plugin to post; empty when there is nothing to say
+type Reply struct {
+	Reply string `json:"reply,omitempty"`
+}
+
+// frame is one newline-delimited JSON protocol message. Requests carry a
+// method and an ID the response repeats; either side may send requests.
+type frame struct {
+	ID     uint64          `json:"id"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// RequestHandler answers a request from the other end of a Conn. The result
+// is marshaled as the response; a nil result sends none.
+type RequestHandler func(ctx context.Context, method string, params json.RawMessage) (interface{}, error)
+
+// Conn is one end of a plugin protocol connection: newline-delimited JSON
+// requests and responses in both directions over a reader and writer, such
+// as a plugin process's stdout and stdin
+type Conn struct {
+	reader  io.Reader
+	writer  io.Writer
+	handler RequestHandler
+
+	writeMu sync.Mutex
+	mu      sync.Mutex
+	nextID  uint64
+	pending map[uint64]chan *frame
+	done    chan struct{}
+	err     error
+}
+
+// NewConn creates a connection that reads frames from r, writes them to w
+// and passes incoming requests to handler
+func NewConn(r io.Reader, w io.Writer, handler RequestHandler) *Conn {
+	return &Conn{
+		reader:  r,
+		writer:  w,
+		handler: handler,
+		pending: make(map[uint64]chan *frame),
+		done:    make(chan struct{}),
+	}
+}
+
+// Run reads frames until the reader is exhausted or fails, answering
+// requests concurrently with ctx. Pending calls fail once it returns.
+func (c *Conn) Run(ctx context.Context) error {
+	scanner := bufio.NewScanner(c.reader)
+	scanner.Buffer(make([]byte, 64*1024), MaxFrameSize)
+
+	var err error
+	for scanner.Scan() {
+		var f frame
+		if err = json.Unmarshal(scanner.Bytes(), &f); err != nil {
+			err = fmt.Errorf("invalid plugin frame: %w", err)
+			break
+		}
+		if f.Method == "" {
+			c.resolve(&f)
+			continue
+		}
+		go c.answer(ctx, &f)
+	}
+	if err == nil {
+		err = scanner.Err()
+	}
+	if err == nil {
+		err = ErrConnClosed
+	}
+
+	c.mu.Lock()
+	c.err = err
+	c.mu.Unlock()
+	close(c.done)
+	return err
+}
+
+// Done is closed once Run returns
+func (c *Conn) Done() <-chan struct{} {
+	return c.done
+}
+
+// Call sends a request and decodes the response's result into result, which
+// may be nil
+func (c *Conn) Call(ctx context.Context, method string, params, result interface{}) error {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s params: %w", method, err)
+	}
+
+	c.mu.Lock()
+	c.nextID++
+	id := c.nextID
+	response := make(chan *frame, 1)
+	c.pending[id] = response
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	if err := c.write(&frame{ID: id, Method: method, Params: raw}); err != nil {
+		select {
+		case <-c.done:
+			return c.closedErr()
+		default:
+			return err
+		}
+	}
+
+	select {
+	case f := <-response:
+		if f.Error != "" {
+			return fmt.Errorf("%s: %s", method, f.Error)
+		}
+		if result != nil && len(f.Result) > 0 {
+			if err := json.Unmarshal(f.Result, result); err != nil {
+				return fmt.Errorf("invalid %s result: %w", method, err)
+			}
+		}
+		return nil
+	case <-c.done:
+		return c.closedErr()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// resolve passes a response to the call waiting for it
+func (c *Conn) resolve(f *frame) {
+	c.mu.Lock()
+	response, ok := c.pending[f.ID]
+	c.mu.Unlock()
+	if ok {
+		response <- f
+	}
+}
+
+// answer runs the handler for a request and writes its response
+func (c *Conn) answer(ctx context.Context, f *frame) {
+	response := &frame{ID: f.ID}
+	result, err := c.handler(ctx, f.Method, f.Params)
+	switch {
+	case err != nil:
+		response.Error = err.Error()
+	case result != nil:
+		if response.Result, err = json.Marshal(result); err != nil {
+			response.Error = fmt.Sprintf("failed to marshal result: %v", err)
+		}
+	}
+	// A write failure means the peer is gone, which Run reports
+	_ = c.write(response)
+}
+
+// write sends one frame
+func (c *Conn) write(f *frame) error {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("failed to marshal frame: %w", err)
+	}
+	data = append(data, '\n')
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if _, err := c.writer.Write(data); err != nil {
+		return fmt.Errorf("failed to write frame: %w", err)
+	}
+	return nil
+}
+
+// closedErr returns why the connection closed
+func (c *Conn) closedErr() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil && !errors.Is(c.err, ErrConnClosed) {
+		return fmt.Errorf("%w: %v", ErrConnClosed, c.err)
+	}
+	return ErrConnClosed
+}
@@ -0,0 +1,145 @@
+package plugins
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Restart backoff bounds for supervised plugins
+const (
+	restartDelayMin = time.Second
+	restartDelayMax = time.Minute
+	pingTimeout     = 10 * time.Second
+)
+
+// HealthBus is the name the message bus is reported under by Health
+const HealthBus = "nats"
+
+// Supervised is implemented by plugins whose process the manager keeps
+// running: they are pinged every HealthInterval and restarted when a ping
+// fails or the process exits
+type Supervised interface {
+	Ping(ctx context.Context) error
+	Restart(ctx context.Context) error
+	// Exited returns a channel closed when the running process exits, or
+	// nil when none is running
+	Exited() <-chan struct{}
+}
+
+// supervise starts watching a loaded plugin until it is unloaded
+func (m *Manager) supervise(name string, plugin Supervised) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	m.mu.Lock()
+	if stop, ok := m.supervisors[name]; ok {
+		stop()
+	}
+	m.supervisors[name] = cancel
+	m.mu.Unlock()
+
+	m.supervising.Add(1)
+	go func() {
+		defer m.supervising.Done()
+		m.watch(ctx, name, plugin)
+	}()
+}
+
+// watch restarts the plugin whenever it exits or fails a health check,
+// backing off between failed restarts
+func (m *Manager) watch(ctx context.Context, name string, plugin Supervised) {
+	logger := m.log().With("plugin", name)
+	var ticks <-chan time.Time
+	if m.config.HealthInterval > 0 {
+		ticker := time.NewTicker(m.config.HealthInterval)
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+
+	delay := restartDelayMin
+	for {
+		exited := plugin.Exited()
+		if exited != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-exited:
+				logger.Warn("plugin exited, restarting")
+			case <-ticks:
+				pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+				err := plugin.Ping(pingCtx)
+				cancel()
+				if err == nil || ctx.Err() != nil {
+					delay = restartDelayMin
+					continue
+				}
+				logger.Warn("plugin failed health check, restarting", "error", err)
+			}
+		}
+
+		if err := plugin.Restart(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Error("failed to restart plugin", "error", err, "retry_in", delay)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+			delay = min(delay*2, restartDelayMax)
+		}
+	}
+}
+
+// stopSupervisors stops watching every plugin and waits for the watchers
+// to return
+func (m *Manager) stopSupervisors() {
+	m.mu.Lock()
+	for name, stop := range m.supervisors {
+		stop()
+		delete(m.supervisors, name)
+	}
+	m.mu.Unlock()
+	m.supervising.Wait()
+}
+
+// Health pings every loaded plugin that runs a process, and the message
+// bus when connected, returning each one's error by name; nil means it
+// answered. Plugins running in the otter's process are always healthy.
+func (m *Manager) Health(ctx context.Context) map[string]error {
+	m.mu.RLock()
+	supervised := make(map[string]Supervised)
+	health := make(map[string]error, len(m.plugins)+1)
+	for name, plugin := range m.plugins {
+		health[name] = nil
+		if s, ok := plugin.(Supervised); ok {
+			supervised[name] = s
+		}
+	}
+	bus := m.bus
+	m.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	ping := func(name string, pinger interface{ Ping(context.Context) error }) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := pinger.Ping(ctx)
+			mu.Lock()
+			health[name] = err
+			mu.Unlock()
+		}()
+	}
+	for name, plugin := range supervised {
+		ping(name, plugin)
+	}
+	if bus != nil {
+		ping(HealthBus, bus)
+	}
+	wg.Wait()
+	return health
+}
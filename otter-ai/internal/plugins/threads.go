@@ -0,0 +1,122 @@
+package plugins
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxThreadSessionID bounds the session IDs derived from threads
+const maxThreadSessionID = 128
+
+// Thread identifies a conversation on a platform: a channel, or a thread or
+// reply chain within it. Each thread is its own agent session unless it is
+// linked to another one.
+type Thread struct {
+	Platform  string `json:"platform"`
+	ChannelID string `json:"channel_id,omitempty"`
+	ThreadID  string `json:"thread_id,omitempty"`
+}
+
+// ThreadLink maps a thread to the session it continues
+type ThreadLink struct {
+	Thread
+	SessionID string `json:"session_id"`
+}
+
+// ThreadOf returns the thread a message belongs to. Direct messages without
+// a channel are threads of their user.
+func ThreadOf(message *Message) Thread {
+	channel := message.ChannelID
+	if channel == "" && message.UserID != "" {
+		channel = "dm-" + message.UserID
+	}
+	return Thread{Platform: message.Platform, ChannelID: channel, ThreadID: message.ThreadID}
+}
+
+// sessionID derives the session a thread uses when it is not linked:
+// "platform:channel:thread" when that is a valid session ID, otherwise the
+// platform and a hash of the thread
+func (t Thread) sessionID() string {
+	parts := []string{t.Platform, t.ChannelID}
+	if t.ThreadID != "" {
+		parts = append(parts, t.ThreadID)
+	}
+	id := strings.Join(parts, ":")
+	if len(id) <= maxThreadSessionID && strings.IndexFunc(id, unsafeSessionRune) < 0 {
+		return id
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	platform := t.Platform
+	if len(platform) > 32 || strings.IndexFunc(platform, unsafeSessionRune) >= 0 {
+		platform = "thread"
+	}
+	return platform + ":" + hex.EncodeToString(sum[:16])
+}
+
+// unsafeSessionRune reports whether r may not appear in a session ID
+func unsafeSessionRune(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return false
+	case r == '-', r == '_', r == '.', r == ':':
+		return false
+	}
+	return true
+}
+
+// ThreadSession returns the session a message's conversation continues:
+// the session its thread is linked to, or one derived from the thread
+func (m *Manager) ThreadSession(message *Message) string {
+	thread := ThreadOf(message)
+	m.mu.RLock()
+	sessionID, linked := m.threads[thread]
+	m.mu.RUnlock()
+	if linked {
+		return sessionID
+	}
+	return thread.sessionID()
+}
+
+// LinkThread makes a thread continue sessionID, e.g. to pick up on Slack a
+// conversation started on Discord. An empty sessionID removes the link.
+func (m *Manager) LinkThread(thread Thread, sessionID string) error {
+	if thread.Platform == "" || thread.ChannelID == "" {
+		return fmt.Errorf("a thread needs a platform and a channel")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if sessionID == "" {
+		delete(m.threads, thread)
+		return nil
+	}
+	m.threads[thread] = sessionID
+	return nil
+}
+
+// ThreadLinks returns the linked threads, ordered by platform, channel and
+// thread
+func (m *Manager) ThreadLinks() []ThreadLink {
+	m.mu.RLock()
+	links := make([]ThreadLink, 0, len(m.threads))
+	for thread, sessionID := range m.threads {
+		links = append(links, ThreadLink{Thread: thread, SessionID: sessionID})
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(links, func(i, j int) bool {
+		a, b := links[i].Thread, links[j].Thread
+		if a.Platform != b.Platform {
+			return a.Platform < b.Platform
+		}
+		if a.ChannelID != b.ChannelID {
+			return a.ChannelID < b.ChannelID
+		}
+		return a.ThreadID < b.ThreadID
+	})
+	return links
+}
@@ -0,0 +1,61 @@
+package plugins
+
+import (
+	"strings"
+	"testing"
+
+	"otter-ai/internal/config"
+)
+
+func TestManager_ThreadSession(t *testing.T) {
+	m := NewManager(config.PluginConfig{})
+	tests := []struct {
+		message *Message
+		want    string
+	}{
+		{&Message{Platform: "slack", ChannelID: "C1"}, "slack:C1"},
+		{&Message{Platform: "slack", ChannelID: "C1", ThreadID: "1700000000.0001"}, "slack:C1:1700000000.0001"},
+		{&Message{Platform: "signal", UserID: "u1"}, "signal:dm-u1"},
+	}
+	for _, tt := range tests {
+		if got := m.ThreadSession(tt.message); got != tt.want {
+			t.Errorf("ThreadSession(%+v) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+
+	// IDs that aren't valid session IDs are hashed, consistently
+	odd := &Message{Platform: "matrix", ChannelID: "!room:example.org", ThreadID: "$event/1"}
+	got := m.ThreadSession(odd)
+	if !strings.HasPrefix(got, "matrix:") || strings.ContainsAny(got, "!$/") || got != m.ThreadSession(odd) {
+		t.Errorf("ThreadSession = %q, want a stable hashed ID", got)
+	}
+	long := &Message{Platform: "slack", ChannelID: strings.Repeat("c", 200)}
+	if got := m.ThreadSession(long); len(got) > maxThreadSessionID {
+		t.Errorf("ThreadSession length = %d", len(got))
+	}
+}
+
+func TestManager_LinkThread(t *testing.T) {
+	m := NewManager(config.PluginConfig{})
+	thread := Thread{Platform: "slack", ChannelID: "C1", ThreadID: "t1"}
+	if err := m.LinkThread(thread, "discord:general"); err != nil {
+		t.Fatal(err)
+	}
+	message := &Message{Platform: "slack", ChannelID: "C1", ThreadID: "t1"}
+	if got := m.ThreadSession(message); got != "discord:general" {
+		t.Errorf("linked ThreadSession = %q", got)
+	}
+	if links := m.ThreadLinks(); len(links) != 1 || links[0].SessionID != "discord:general" {
+		t.Errorf("ThreadLinks = %+v", links)
+	}
+
+	if err := m.LinkThread(thread, ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.ThreadSession(message); got != "slack:C1:t1" {
+		t.Errorf("unlinked ThreadSession = %q", got)
+	}
+	if err := m.LinkThread(Thread{Platform: "slack"}, "s"); err == nil {
+		t.Error("expected error for a thread without a channel")
+	}
+}
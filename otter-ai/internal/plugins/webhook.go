@@ -0,0 +1,257 @@
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WebhookPlatform is the name of the webhook plugin and the platform of the
+// messages it receives
+const WebhookPlatform = "webhook"
+
+// MaxWebhookPayload bounds a webhook request body
+const MaxWebhookPayload = 1 << 20
+
+// Webhook errors
+var (
+	ErrWebhookNotFound     = errors.New("webhook not found")
+	ErrWebhookUnauthorized = errors.New("invalid webhook token")
+	ErrWebhookPayload      = errors.New("invalid webhook payload")
+)
+
+// WebhookEndpoint turns JSON posted to /api/v1/plugins/webhook/{name} into
+// a chat message. Message, Channel and User are text/template templates
+// executed with the decoded payload, e.g. "Build {{.build.status}} for
+// {{.repository.name}}"; a field the payload lacks rejects it.
+type WebhookEndpoint struct {
+	Name    string `yaml:"name" json:"name"`
+	Token   string `yaml:"token" json:"token"`               // Bearer token callers must present
+	Message string `yaml:"message" json:"message,omitempty"` // Empty uses the payload as JSON
+	Channel string `yaml:"channel" json:"channel,omitempty"` // Empty uses the endpoint name, so profiles can target it
+	User    string `yaml:"user" json:"user,omitempty"`       // Empty leaves the message without a user
+}
+
+// webhook is a WebhookEndpoint with its templates parsed
+type webhook struct {
+	WebhookEndpoint
+	message *template.Template
+	channel *template.Template
+	user    *template.Template
+}
+
+type webhookFile struct {
+	Webhooks []WebhookEndpoint `yaml:"webhooks" json:"webhooks"`
+}
+
+// LoadWebhookEndpoints reads webhook endpoints from a YAML file of the form
+//
+//	webhooks:
+//	  - name: ci
+//	    token: s3cret
+//	    message: "Build {{.build.status}} for {{.repository.name}}"
+//	    channel: "{{.repository.name}}"
+//
+// or, when the file name ends in .json, the same layout as JSON
+func LoadWebhookEndpoints(path string) ([]WebhookEndpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhooks: %w", err)
+	}
+	var file webhookFile
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &file)
+	} else {
+		err = yaml.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse webhooks: %w", err)
+	}
+	return file.Webhooks, nil
+}
+
+// WebhookPlugin receives messages from HTTP webhooks, for home automation,
+// CI and custom apps. It cannot send messages: callers get the agent's reply
+// in the response.
+type WebhookPlugin struct {
+	webhooks map[string]*webhook
+
+	mu       sync.RWMutex
+	messages MessageHandler
+}
+
+// NewWebhookPlugin creates the webhook plugin serving endpoints. Every
+// endpoint needs a unique name and a token, and templates that parse.
+func NewWebhookPlugin(endpoints []WebhookEndpoint) (*WebhookPlugin, error) {
+	p := &WebhookPlugin{webhooks: make(map[string]*webhook, len(endpoints))}
+	for i, endpoint := range endpoints {
+		if strings.TrimSpace(endpoint.Name) == "" || strings.Contains(endpoint.Name, "/") {
+			return nil, fmt.Errorf("webhook %d needs a name without slashes", i+1)
+		}
+		if _, exists := p.webhooks[endpoint.Name]; exists {
+			return nil, fmt.Errorf("webhook %s is defined more than once", endpoint.Name)
+		}
+		if endpoint.Token == "" {
+			return nil, fmt.Errorf("webhook %s needs a token", endpoint.Name)
+		}
+		hook := &webhook{WebhookEndpoint: endpoint}
+		var err error
+		if hook.message, err = parseWebhookTemplate(endpoint.Name, "message", endpoint.Message); err != nil {
+			return nil, err
+		}
+		if hook.channel, err = parseWebhookTemplate(endpoint.Name, "channel", endpoint.Channel); err != nil {
+			return nil, err
+		}
+		if hook.user, err = parseWebhookTemplate(endpoint.Name, "user", endpoint.User); err != nil {
+			return nil, err
+		}
+		p.webhooks[endpoint.Name] = hook
+	}
+	return p, nil
+}
+
+// webhookFuncs are available in webhook templates
+var webhookFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// parseWebhookTemplate parses one of an endpoint's templates; an empty
+// template parses to nil
+func parseWebhookTemplate(name, field, text string) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+	tmpl, err := template.New(field).Funcs(webhookFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("webhook %s: invalid %s template: %w", name, field, err)
+	}
+	return tmpl, nil
+}
+
+// Name returns the plugin name
+func (p *WebhookPlugin) Name() string {
+	return WebhookPlatform
+}
+
+// Initialize does nothing; endpoints are configured when the plugin is
+// created
+func (p *WebhookPlugin) Initialize(ctx context.Context, config map[string]string) error {
+	return nil
+}
+
+// SetMessageHandler sets where webhook messages are routed
+func (p *WebhookPlugin) SetMessageHandler(handler MessageHandler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.messages = handler
+}
+
+// HandleMessage accepts a message routed to the plugin; webhooks have no
+// platform to pass it to
+func (p *WebhookPlugin) HandleMessage(ctx context.Context, message *Message) error {
+	return nil
+}
+
+// SendMessage fails: webhook callers only receive replies to their own
+// requests
+func (p *WebhookPlugin) SendMessage(ctx context.Context, message *Message) error {
+	return fmt.Errorf("webhook plugin cannot send messages")
+}
+
+// Shutdown does nothing
+func (p *WebhookPlugin) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// Receive turns a payload posted to the named endpoint into a message,
+// passes it to the message handler and returns the reply
+func (p *WebhookPlugin) Receive(ctx context.Context, name, token string, payload []byte) (string, error) {
+	hook, ok := p.webhooks[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrWebhookNotFound, name)
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(hook.Token)) != 1 {
+		return "", ErrWebhookUnauthorized
+	}
+
+	message, err := hook.render(payload)
+	if err != nil {
+		return "", err
+	}
+
+	p.mu.RLock()
+	handler := p.messages
+	p.mu.RUnlock()
+	if handler == nil {
+		return "", fmt.Errorf("no message handler for platform: %s", WebhookPlatform)
+	}
+	return handler(ctx, message)
+}
+
+// render builds the message for a payload
+func (h *webhook) render(payload []byte) (*Message, error) {
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	var data interface{}
+	if err := decoder.Decode(&data); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrWebhookPayload, err)
+	}
+
+	content := strings.TrimSpace(string(payload))
+	if h.message != nil {
+		var err error
+		if content, err = execWebhookTemplate(h.message, data); err != nil {
+			return nil, err
+		}
+	}
+	if content == "" {
+		return nil, fmt.Errorf("%w: message is empty", ErrWebhookPayload)
+	}
+
+	channel := h.Name
+	if h.channel != nil {
+		var err error
+		if channel, err = execWebhookTemplate(h.channel, data); err != nil {
+			return nil, err
+		}
+	}
+	var user string
+	if h.user != nil {
+		var err error
+		if user, err = execWebhookTemplate(h.user, data); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Message{
+		Platform:  WebhookPlatform,
+		ChannelID: channel,
+		UserID:    user,
+		Content:   content,
+		Timestamp: time.Now().Unix(),
+		Metadata:  map[string]interface{}{"webhook": h.Name},
+	}, nil
+}
+
+// execWebhookTemplate renders a template with a payload
+func execWebhookTemplate(tmpl *template.Template, data interface{}) (string, error) {
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrWebhookPayload, err)
+	}
+	return strings.TrimSpace(out.String()), nil
+}
@@ -0,0 +1,103 @@
+package plugins
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestWebhooks(t *testing.T) (*WebhookPlugin, *[]*Message) {
+	t.Helper()
+	p, err := NewWebhookPlugin([]WebhookEndpoint{
+		{Name: "ci", Token: "s3cret", Message: "Build {{.build.status}} for {{.repository.name}}", Channel: "{{.repository.name}}"},
+		{Name: "raw", Token: "t"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var received []*Message
+	p.SetMessageHandler(func(_ context.Context, message *Message) (string, error) {
+		received = append(received, message)
+		return "noted", nil
+	})
+	return p, &received
+}
+
+func TestWebhookPlugin_Receive(t *testing.T) {
+	p, received := newTestWebhooks(t)
+	reply, err := p.Receive(context.Background(), "ci", "s3cret",
+		[]byte(`{"build": {"status": "failed", "number": 42}, "repository": {"name": "otter"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply != "noted" {
+		t.Errorf("reply = %q", reply)
+	}
+	message := (*received)[0]
+	if message.Platform != WebhookPlatform || message.ChannelID != "otter" || message.Content != "Build failed for otter" {
+		t.Errorf("message = %+v", message)
+	}
+
+	if _, err := p.Receive(context.Background(), "raw", "t", []byte(` {"temp": 21.5} `)); err != nil {
+		t.Fatal(err)
+	}
+	message = (*received)[1]
+	if message.ChannelID != "raw" || message.Content != `{"temp": 21.5}` {
+		t.Errorf("raw message = %+v", message)
+	}
+}
+
+func TestWebhookPlugin_ReceiveErrors(t *testing.T) {
+	p, received := newTestWebhooks(t)
+	ctx := context.Background()
+	tests := []struct {
+		name, webhook, token, payload string
+		want                          error
+	}{
+		{"unknown webhook", "nope", "s3cret", `{}`, ErrWebhookNotFound},
+		{"wrong token", "ci", "guess", `{}`, ErrWebhookUnauthorized},
+		{"not json", "ci", "s3cret", `build failed`, ErrWebhookPayload},
+		{"missing field", "ci", "s3cret", `{"build": {"status": "ok"}}`, ErrWebhookPayload},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := p.Receive(ctx, tt.webhook, tt.token, []byte(tt.payload)); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+	if len(*received) != 0 {
+		t.Errorf("rejected payloads reached the handler: %d", len(*received))
+	}
+}
+
+func TestNewWebhookPlugin_Invalid(t *testing.T) {
+	tests := map[string][]WebhookEndpoint{
+		"no name":      {{Token: "t"}},
+		"no token":     {{Name: "ci"}},
+		"duplicate":    {{Name: "ci", Token: "t"}, {Name: "ci", Token: "u"}},
+		"bad template": {{Name: "ci", Token: "t", Message: "{{.build"}},
+	}
+	for name, endpoints := range tests {
+		if _, err := NewWebhookPlugin(endpoints); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestLoadWebhookEndpoints(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "webhooks.yaml")
+	data := "webhooks:\n  - name: ci\n    token: s3cret\n    message: \"Build {{.status}}\"\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	endpoints, err := LoadWebhookEndpoints(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(endpoints) != 1 || endpoints[0].Name != "ci" || endpoints[0].Message != "Build {{.status}}" {
+		t.Errorf("endpoints = %+v", endpoints)
+	}
+}
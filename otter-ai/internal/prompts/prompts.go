@@ -0,0 +1,123 @@
+// Package prompts keeps the templates of the prompts the agent and
+// governance send to the LLM, so operators can tune them for their model
+// without recompiling.
+package prompts
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Prompt names
+const (
+	System                = "system"                 // Chat system prompt, with rules by raft
+	Governance            = "governance"             // Rules and open proposals, for list_governance_state
+	Rules                 = "rules"                  // Active rules by raft
+	Proposals             = "proposals"              // Open proposals, numbered
+	ToolFollowup          = "tool_followup"          // Hands tool results back to the LLM
+	RuleRegeneration      = "rule_regeneration"      // Asks for a reply that follows the rules it broke
+	Musing                = "musing"                 // Idle reflection on recent memories
+	Consolidation         = "consolidation"          // Summary replacing a cluster of memories
+	SessionSummary        = "session_summary"        // Running summary of a conversation
+	Introspection         = "introspection"          // Comparison of stored memories
+	IngestionPolicy       = "ingestion_policy"       // Whether ingestion rules allow an item
+	RuleCheck             = "rule_check"             // Which free-text rules a reply breaks
+	RuleContradiction     = "rule_contradiction"     // Whether rules of different scopes contradict
+	Negotiation           = "negotiation"            // Compromise between conflicting rules
+	NegotiationRefinement = "negotiation_refinement" // Revision of a draft after critiques
+	NegotiationCritique   = "negotiation_critique"   // One raft's judgement of a draft
+	Intent                = "intent"                 // What a chat message asks, when exemplars don't tell
+	DelegatedVote         = "delegated_vote"         // How the otter's personality would vote on a proposal
+	MemoryRerank          = "memory_rerank"          // Which retrieved memories answer a query, best first
+	FactExtraction        = "fact_extraction"        // Atomic facts and preferences stated in a chat exchange
+	ProposalImpact        = "proposal_impact"        // What adopting a proposed rule would change
+)
+
+// FileExtension is the extension of template files in a prompt directory
+const FileExtension = ".tmpl"
+
+// ErrUnknownPrompt is returned for an override naming no known template
+var ErrUnknownPrompt = errors.New("unknown prompt template")
+
+var funcs = template.FuncMap{
+	"inc":  func(i int) int { return i + 1 },
+	"trim": strings.TrimSpace,
+	"plural": func(n int, singular, plural string) string {
+		if n == 1 {
+			return singular
+		}
+		return plural
+	},
+}
+
+// Registry holds the prompt templates. A nil Registry renders the defaults.
+type Registry struct {
+	templates *template.Template
+}
+
+var defaultRegistry = &Registry{templates: template.Must(template.New("prompts").Funcs(funcs).Parse(defaultTemplates))}
+
+// Default returns the built-in templates
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// Load returns the built-in templates overridden by the files in dir. A file
+// <name>.tmpl replaces the template of that name and may also hold
+// {{define}} blocks replacing others. An empty dir returns the defaults.
+func Load(dir string) (*Registry, error) {
+	if dir == "" {
+		return defaultRegistry, nil
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*"+FileExtension))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prompt templates: %w", err)
+	}
+	templates, err := defaultRegistry.templates.Clone()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), FileExtension)
+		if templates.Lookup(name) == nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownPrompt, file)
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read prompt template: %w", err)
+		}
+		if _, err := templates.New(name).Parse(string(data)); err != nil {
+			return nil, fmt.Errorf("failed to parse prompt template %s: %w", file, err)
+		}
+	}
+	return &Registry{templates: templates}, nil
+}
+
+// Render executes the named template with data. When an override fails,
+// the built-in template is rendered instead and returned with the error, so
+// a broken override is reported without leaving the LLM without a prompt.
+func (r *Registry) Render(name string, data interface{}) (string, error) {
+	if r == nil {
+		r = defaultRegistry
+	}
+	var b strings.Builder
+	err := r.templates.ExecuteTemplate(&b, name, data)
+	if err == nil {
+		return b.String(), nil
+	}
+	err = fmt.Errorf("failed to render prompt %s: %w", name, err)
+	if r == defaultRegistry {
+		return "", err
+	}
+	fallback, defaultErr := defaultRegistry.Render(name, data)
+	if defaultErr != nil {
+		return "", err
+	}
+	return fallback, err
+}
@@ -0,0 +1,84 @@
+package prompts
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTemplate(t *testing.T, dir, name, body string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name+FileExtension), []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDefaultsRender(t *testing.T) {
+	for _, name := range []string{
+		System, Governance, ToolFollowup, RuleRegeneration, Musing, Consolidation, SessionSummary,
+		Introspection, IngestionPolicy, RuleCheck, RuleContradiction, Negotiation, NegotiationRefinement, NegotiationCritique, Intent, DelegatedVote, MemoryRerank,
+		FactExtraction, ProposalImpact,
+	} {
+		if Default().templates.Lookup(name) == nil {
+			t.Errorf("no built-in template %q", name)
+		}
+	}
+
+	var r *Registry
+	out, err := r.Render(ToolFollowup, map[string]string{"Results": "42", "Question": "how many?"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out, "Tool results:\n42\nOriginal question: how many?") {
+		t.Errorf("tool followup = %q", out)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	if r, err := Load(""); err != nil || r != Default() {
+		t.Errorf("Load(\"\") = %v, %v, want the defaults", r, err)
+	}
+
+	dir := t.TempDir()
+	writeTemplate(t, dir, ToolFollowup, "Q: {{.Question}} A: {{.Results}}")
+	r, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := map[string]string{"Results": "42", "Question": "how many?"}
+	if out, err := r.Render(ToolFollowup, data); err != nil || out != "Q: how many? A: 42" {
+		t.Errorf("override = %q, %v", out, err)
+	}
+	if out, _ := Default().Render(ToolFollowup, data); !strings.HasPrefix(out, "Tool results:") {
+		t.Errorf("override leaked into the defaults: %q", out)
+	}
+
+	writeTemplate(t, dir, "dream", "zzz")
+	if _, err := Load(dir); !errors.Is(err, ErrUnknownPrompt) {
+		t.Errorf("unknown name: err = %v, want ErrUnknownPrompt", err)
+	}
+
+	dir = t.TempDir()
+	writeTemplate(t, dir, Musing, "{{range .Memories}")
+	if _, err := Load(dir); err == nil {
+		t.Error("a template that does not parse should fail to load")
+	}
+}
+
+func TestRenderFallsBackToDefault(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, ToolFollowup, "{{.Missing.Field}}")
+	r, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := r.Render(ToolFollowup, map[string]interface{}{"Results": "42", "Question": "how many?", "Missing": 1})
+	if err == nil {
+		t.Error("a failing override should be reported")
+	}
+	if !strings.HasPrefix(out, "Tool results:\n42") {
+		t.Errorf("fallback = %q, want the built-in template", out)
+	}
+}
@@ -0,0 +1,133 @@
+// Package secrets resolves secret:// references in configuration values to
+// secrets kept in Vault, AWS Secrets Manager or SOPS-encrypted files, so API
+// keys, tokens and passphrases needn't be set in plaintext.
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Scheme starts a reference to a secret
+const Scheme = "secret://"
+
+// DefaultTimeout bounds each request to a secrets backend
+const DefaultTimeout = 10 * time.Second
+
+// Secret backends, the first path segment of a reference
+const (
+	BackendVault = "vault" // secret://vault/<path>#<field>
+	BackendAWS   = "aws"   // secret://aws/<name>[#<field>]
+	BackendSOPS  = "sops"  // secret://sops/<file>#<key>
+)
+
+// Config locates the secret backends
+type Config struct {
+	VaultAddr      string // Vault server, e.g. https://vault.example.org:8200
+	VaultToken     string
+	VaultNamespace string // Vault Enterprise namespace; empty uses the token's
+	AWSRegion      string
+	AWSEndpoint    string // Overrides the regional Secrets Manager endpoint, e.g. for LocalStack
+	SOPSBinary     string // sops executable; empty finds "sops" on PATH
+	Timeout        time.Duration
+}
+
+// Resolver fetches the secrets references name
+type Resolver struct {
+	cfg    Config
+	client *http.Client
+	now    func() time.Time // Signs AWS requests; replaced in tests
+}
+
+// NewResolver creates a resolver for the configured backends
+func NewResolver(cfg Config) *Resolver {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.SOPSBinary == "" {
+		cfg.SOPSBinary = "sops"
+	}
+	return &Resolver{cfg: cfg, client: &http.Client{Timeout: cfg.Timeout}, now: time.Now}
+}
+
+// IsReference reports whether a configuration value names a secret
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, Scheme)
+}
+
+// reference is a parsed secret:// reference
+type reference struct {
+	backend string
+	path    string // Vault path, AWS secret name or SOPS file
+	field   string // Key within the secret; may be empty for AWS
+}
+
+func parseReference(value string) (reference, error) {
+	rest, ok := strings.CutPrefix(value, Scheme)
+	if !ok {
+		return reference{}, fmt.Errorf("not a %s reference", Scheme)
+	}
+	backend, rest, _ := strings.Cut(rest, "/")
+	ref := reference{backend: backend, path: rest}
+	if i := strings.LastIndex(rest, "#"); i >= 0 {
+		ref.path, ref.field = rest[:i], rest[i+1:]
+	}
+	if ref.path == "" {
+		return reference{}, fmt.Errorf("%s names no secret", value)
+	}
+	switch ref.backend {
+	case BackendVault, BackendSOPS:
+		if ref.field == "" {
+			return reference{}, fmt.Errorf("%s names no field; add #<field>", value)
+		}
+	case BackendAWS:
+	default:
+		return reference{}, fmt.Errorf("unknown secret backend %q; use %s, %s or %s", ref.backend, BackendVault, BackendAWS, BackendSOPS)
+	}
+	return ref, nil
+}
+
+// Resolve returns the secret a reference names. Errors name the reference,
+// never the secret.
+func (r *Resolver) Resolve(ctx context.Context, value string) (string, error) {
+	ref, err := parseReference(value)
+	if err != nil {
+		return "", err
+	}
+
+	var secret string
+	switch ref.backend {
+	case BackendVault:
+		secret, err = r.vault(ctx, ref)
+	case BackendAWS:
+		secret, err = r.aws(ctx, ref)
+	case BackendSOPS:
+		secret, err = r.sops(ctx, ref)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", value, err)
+	}
+	if secret == "" {
+		return "", fmt.Errorf("failed to resolve %s: the secret is empty", value)
+	}
+	return secret, nil
+}
+
+// field returns a field of a decoded secret as a string
+func field(data map[string]interface{}, name string) (string, error) {
+	value, ok := data[name]
+	if !ok {
+		return "", fmt.Errorf("secret has no field %q", name)
+	}
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case map[string]interface{}, []interface{}:
+		return "", fmt.Errorf("field %q is not a single value", name)
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
@@ -0,0 +1,148 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseReference(t *testing.T) {
+	for _, tt := range []struct {
+		value string
+		want  reference
+		ok    bool
+	}{
+		{"secret://vault/secret/data/otter#llm_key", reference{BackendVault, "secret/data/otter", "llm_key"}, true},
+		{"secret://aws/prod/otter", reference{BackendAWS, "prod/otter", ""}, true},
+		{"secret://sops//etc/otter/secrets.yaml#llm.api_key", reference{BackendSOPS, "/etc/otter/secrets.yaml", "llm.api_key"}, true},
+		{"secret://vault/secret/data/otter", reference{}, false},
+		{"secret://gcp/otter#key", reference{}, false},
+		{"secret://aws/", reference{}, false},
+	} {
+		got, err := parseReference(tt.value)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("parseReference(%q) = %+v, %v", tt.value, got, err)
+		}
+	}
+}
+
+func TestResolve_Vault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/otter":
+			w.Write([]byte(`{"data": {"data": {"llm_key": "sk-v2"}, "metadata": {"version": 3}}}`))
+		case "/v1/kv/otter":
+			w.Write([]byte(`{"data": {"llm_key": "sk-v1"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	r := NewResolver(Config{VaultAddr: server.URL, VaultToken: "root"})
+	ctx := context.Background()
+
+	for ref, want := range map[string]string{
+		"secret://vault/secret/data/otter#llm_key": "sk-v2",
+		"secret://vault/kv/otter#llm_key":          "sk-v1",
+	} {
+		if got, err := r.Resolve(ctx, ref); err != nil || got != want {
+			t.Errorf("Resolve(%s) = %q, %v; want %q", ref, got, err, want)
+		}
+	}
+	if _, err := r.Resolve(ctx, "secret://vault/kv/otter#missing"); err == nil {
+		t.Error("expected an error for a missing field")
+	}
+	if _, err := NewResolver(Config{VaultAddr: server.URL, VaultToken: "wrong"}).Resolve(ctx, "secret://vault/kv/otter#llm_key"); err == nil {
+		t.Error("expected an error for a refused token")
+	}
+}
+
+func TestResolve_AWS(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct{ SecretId string }
+		json.NewDecoder(r.Body).Decode(&req)
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" ||
+			!strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch req.SecretId {
+		case "otter/jwt":
+			w.Write([]byte(`{"SecretString": "jwt-secret"}`))
+		case "otter/keys":
+			w.Write([]byte(`{"SecretString": "{\"llm\": \"sk-aws\"}"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type": "ResourceNotFoundException"}`))
+		}
+	}))
+	defer server.Close()
+	r := NewResolver(Config{AWSRegion: "eu-west-1", AWSEndpoint: server.URL})
+	ctx := context.Background()
+
+	for ref, want := range map[string]string{
+		"secret://aws/otter/jwt":      "jwt-secret",
+		"secret://aws/otter/keys#llm": "sk-aws",
+	} {
+		if got, err := r.Resolve(ctx, ref); err != nil || got != want {
+			t.Errorf("Resolve(%s) = %q, %v; want %q", ref, got, err, want)
+		}
+	}
+	if _, err := r.Resolve(ctx, "secret://aws/otter/missing"); err == nil || !strings.Contains(err.Error(), "ResourceNotFoundException") {
+		t.Errorf("Resolve(missing) error = %v", err)
+	}
+}
+
+// TestSignV4 checks the signer against the GET example in AWS's Signature
+// Version 4 documentation
+func TestSignV4(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08", nil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	creds := awsCredentials{accessKeyID: "AKIDEXAMPLE", secretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	signV4(req, nil, creds, "us-east-1", "iam", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, " +
+		"SignedHeaders=content-type;host;x-amz-date, " +
+		"Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization = %s\nwant %s", got, want)
+	}
+}
+
+func TestResolve_SOPS(t *testing.T) {
+	dir := t.TempDir()
+	// A stand-in for sops that "decrypts" by printing the file
+	binary := filepath.Join(dir, "sops")
+	if err := os.WriteFile(binary, []byte("#!/bin/sh\n[ \"$1\" = --decrypt ] && exec cat \"$2\"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "secrets.yaml")
+	if err := os.WriteFile(file, []byte("llm:\n  api_key: sk-sops\nport: 8080\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	r := NewResolver(Config{SOPSBinary: binary})
+	ctx := context.Background()
+
+	if got, err := r.Resolve(ctx, "secret://sops/"+file+"#llm.api_key"); err != nil || got != "sk-sops" {
+		t.Errorf("Resolve = %q, %v", got, err)
+	}
+	if _, err := r.Resolve(ctx, "secret://sops/"+file+"#llm"); err == nil {
+		t.Error("expected an error for a field that is not a single value")
+	}
+	if _, err := r.Resolve(ctx, "secret://sops/"+filepath.Join(dir, "missing.yaml")+"#llm.api_key"); err == nil {
+		t.Error("expected an error for a file sops can't read")
+	}
+}
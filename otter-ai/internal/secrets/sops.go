@@ -0,0 +1,45 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// sops reads a value from a SOPS-encrypted YAML or JSON file by decrypting
+// it with the sops executable, which finds the keys (age, PGP, KMS) the way
+// it does on the command line. The field is a dotted key path, such as
+// llm.api_key.
+func (r *Resolver) sops(ctx context.Context, ref reference) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.Timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, r.cfg.SOPSBinary, "--decrypt", ref.path)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("sops: %w: %s", err, msg)
+		}
+		return "", fmt.Errorf("sops: %w", err)
+	}
+
+	// JSON is YAML, so one decoder reads either
+	var data map[string]interface{}
+	if err := yaml.Unmarshal(stdout.Bytes(), &data); err != nil {
+		return "", fmt.Errorf("decrypted file is not YAML or JSON: %w", err)
+	}
+	keys := strings.Split(ref.field, ".")
+	for _, key := range keys[:len(keys)-1] {
+		nested, ok := data[key].(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("secret has no field %q", ref.field)
+		}
+		data = nested
+	}
+	return field(data, keys[len(keys)-1])
+}
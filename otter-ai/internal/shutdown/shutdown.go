@@ -0,0 +1,67 @@
+// Package shutdown stops the otter's parts in order under one drain
+// deadline: intake first, then in-flight work, then the state it writes,
+// and the database last.
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// DefaultTimeout is how long shutdown waits for in-flight work
+const DefaultTimeout = 30 * time.Second
+
+// Step stops one part of the otter, giving up when ctx is done
+type Step func(ctx context.Context) error
+
+// Manager runs shutdown steps in the order they were added
+type Manager struct {
+	logger *slog.Logger
+	steps  []namedStep
+}
+
+type namedStep struct {
+	name string
+	stop Step
+}
+
+// New creates a Manager that logs each step to logger
+func New(logger *slog.Logger) *Manager {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Manager{logger: logger}
+}
+
+// Add appends a step, run after every step added before it
+func (m *Manager) Add(name string, stop Step) {
+	m.steps = append(m.steps, namedStep{name, stop})
+}
+
+// Run runs every step in turn, all sharing a deadline timeout from now. A
+// step that fails or runs out of time is logged and the next still runs, so
+// the database is closed even when in-flight work could not finish; steps
+// after the deadline get a context that is already done. The steps' errors
+// are returned joined.
+func (m *Manager) Run(timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var errs []error
+	for _, step := range m.steps {
+		started := time.Now()
+		if err := step.stop(ctx); err != nil {
+			m.logger.Error("shutdown step failed", "step", step.name, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", step.name, err))
+			continue
+		}
+		m.logger.Debug("shutdown step finished", "step", step.name, "duration", time.Since(started))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,49 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun_Order(t *testing.T) {
+	m := New(nil)
+	var order []string
+	for _, name := range []string{"api", "agent", "database"} {
+		m.Add(name, func(context.Context) error {
+			order = append(order, name)
+			return nil
+		})
+	}
+	if err := m.Run(time.Second); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if want := []string{"api", "agent", "database"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("order = %v; want %v", order, want)
+	}
+}
+
+func TestRun_ContinuesPastFailures(t *testing.T) {
+	m := New(nil)
+	m.Add("agent", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	m.Add("plugins", func(context.Context) error { return errors.New("plugin hung") })
+	closed := false
+	m.Add("database", func(context.Context) error {
+		closed = true
+		return nil
+	})
+
+	err := m.Run(20 * time.Millisecond)
+	if !closed {
+		t.Error("database step should run after earlier steps fail")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "plugins: plugin hung") {
+		t.Errorf("err = %v", err)
+	}
+}
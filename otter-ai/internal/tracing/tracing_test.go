@@ -0,0 +1,75 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"otter-ai/internal/config"
+)
+
+func TestSetup_Disabled(t *testing.T) {
+	shutdown, err := Setup(context.Background(), config.TracingConfig{}, "otter-1")
+	if err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown() error = %v", err)
+	}
+}
+
+func TestEnd_RecordsError(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer := provider.Tracer("test")
+
+	_, ok := tracer.Start(context.Background(), "ok")
+	End(ok, nil)
+	_, failed := tracer.Start(context.Background(), "failed")
+	End(failed, errors.New("boom"))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d ended spans, want 2", len(spans))
+	}
+	if spans[0].Status().Code != codes.Unset {
+		t.Errorf("ok span status = %v, want unset", spans[0].Status().Code)
+	}
+	if spans[1].Status().Code != codes.Error || spans[1].Status().Description != "boom" {
+		t.Errorf("failed span status = %+v, want error boom", spans[1].Status())
+	}
+	if len(spans[1].Events()) != 1 {
+		t.Errorf("failed span has %d events, want the recorded error", len(spans[1].Events()))
+	}
+}
+
+func TestInjectExtract_RoundTrip(t *testing.T) {
+	provider := sdktrace.NewTracerProvider()
+	ctx, span := provider.Tracer("test").Start(context.Background(), "outgoing")
+	defer span.End()
+
+	// The default global propagator does nothing; Setup installs W3C trace
+	// context, which the round trip needs
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(previous)
+
+	header := http.Header{}
+	Inject(ctx, header)
+	if header.Get("traceparent") == "" {
+		t.Fatal("Inject() did not set a traceparent header")
+	}
+
+	extracted := Extract(context.Background(), header)
+	if got := trace.SpanContextFromContext(extracted).TraceID(); got != span.SpanContext().TraceID() {
+		t.Errorf("extracted trace ID = %s, want %s", got, span.SpanContext().TraceID())
+	}
+}
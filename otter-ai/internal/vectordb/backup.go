@@ -0,0 +1,30 @@
+package vectordb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+)
+
+// Backup writes a consistent copy of a live SQLite database to a new file
+// at path, using SQLite's online backup API so that concurrent writers are
+// neither blocked for long nor captured half-way through a transaction
+func Backup(ctx context.Context, db *sql.DB, path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("backup target %s already exists", path)
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get a database connection: %w", err)
+	}
+	defer conn.Close()
+
+	if err := conn.Raw(func(driverConn interface{}) error {
+		return backupConn(driverConn, path)
+	}); err != nil {
+		os.Remove(path)
+		return fmt.Errorf("failed to back up database: %w", err)
+	}
+	return nil
+}
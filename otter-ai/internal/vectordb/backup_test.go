@@ -0,0 +1,36 @@
+package vectordb
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackup(t *testing.T) {
+	db := tempDB(t)
+	ctx := context.Background()
+	if err := db.Store(ctx, TableMemories, "id1", vec(1, 0), map[string]interface{}{"key": "value"}); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "backup.db")
+	if err := Backup(ctx, db.GetDB(), path); err != nil {
+		t.Fatalf("Backup: %v", err)
+	}
+	if err := Backup(ctx, db.GetDB(), path); err == nil {
+		t.Error("expected an error backing up over an existing file")
+	}
+
+	copied, err := NewSQLiteVectorDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer copied.Close()
+	record, err := copied.Get(ctx, TableMemories, "id1")
+	if err != nil {
+		t.Fatalf("Get from backup: %v", err)
+	}
+	if record.Metadata["key"] != "value" {
+		t.Errorf("metadata = %v", record.Metadata)
+	}
+}
@@ -0,0 +1,59 @@
+//go:build cgo && !purego
+
+package vectordb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// SQLiteDriver names the database/sql driver backing SQLiteVectorDB. This
+// build links the CGO driver; build with -tags purego (or CGO_ENABLED=0)
+// for the pure-Go one.
+const SQLiteDriver = "sqlite3"
+
+// sqliteDSN returns the data source name for a database file, waiting up to
+// busyTimeout on a database locked by another connection
+func sqliteDSN(dbPath string, busyTimeout time.Duration) string {
+	sep := "?"
+	if strings.Contains(dbPath, "?") {
+		sep = "&"
+	}
+	return fmt.Sprintf("%s%s_busy_timeout=%d", dbPath, sep, busyTimeout.Milliseconds())
+}
+
+// backupConn copies the database behind a driver connection into a new
+// database file at path
+func backupConn(driverConn interface{}, path string) error {
+	src, ok := driverConn.(*sqlite3.SQLiteConn)
+	if !ok {
+		return fmt.Errorf("unexpected driver connection %T", driverConn)
+	}
+	dst, err := sql.Open(SQLiteDriver, path)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+	dstConn, err := dst.Conn(context.Background())
+	if err != nil {
+		return err
+	}
+	defer dstConn.Close()
+
+	return dstConn.Raw(func(d interface{}) error {
+		backup, err := d.(*sqlite3.SQLiteConn).Backup("main", src, "main")
+		if err != nil {
+			return err
+		}
+		if _, err := backup.Step(-1); err != nil {
+			backup.Finish()
+			return err
+		}
+		return backup.Finish()
+	})
+}
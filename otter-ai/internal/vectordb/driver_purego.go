@@ -0,0 +1,47 @@
+//go:build purego || !cgo
+
+package vectordb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"modernc.org/sqlite"
+)
+
+// SQLiteDriver names the database/sql driver backing SQLiteVectorDB. This
+// build links the pure-Go driver, which cross-compiles without a C toolchain.
+const SQLiteDriver = "sqlite"
+
+// sqliteDSN returns the data source name for a database file, waiting up to
+// busyTimeout on a database locked by another connection
+func sqliteDSN(dbPath string, busyTimeout time.Duration) string {
+	sep := "?"
+	if strings.Contains(dbPath, "?") {
+		sep = "&"
+	}
+	return fmt.Sprintf("%s%s_pragma=busy_timeout(%d)", dbPath, sep, busyTimeout.Milliseconds())
+}
+
+// backupConn copies the database behind a driver connection into a new
+// database file at path
+func backupConn(driverConn interface{}, path string) error {
+	src, ok := driverConn.(interface {
+		NewBackup(dstURI string) (*sqlite.Backup, error)
+	})
+	if !ok {
+		return fmt.Errorf("unexpected driver connection %T", driverConn)
+	}
+	backup, err := src.NewBackup(path)
+	if err != nil {
+		return err
+	}
+	for more := true; more; {
+		if more, err = backup.Step(-1); err != nil {
+			backup.Finish()
+			return err
+		}
+	}
+	return backup.Finish()
+}
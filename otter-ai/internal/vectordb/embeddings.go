@@ -0,0 +1,113 @@
+package vectordb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"otter-ai/internal/tracing"
+)
+
+// EmbeddingSpace records which embedding model produced a table's vectors
+type EmbeddingSpace struct {
+	Model      string    `json:"model"`
+	Dimension  int       `json:"dimension"`
+	Reindexing bool      `json:"reindexing"` // A re-embedding into this space was started and has not finished
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// EmbeddingRegistry is implemented by backends that record the embedding
+// space of each table and can replace vectors in place, without keeping
+// the replaced ones as versions
+type EmbeddingRegistry interface {
+	// EmbeddingSpace returns a table's recorded space, nil when none is
+	EmbeddingSpace(ctx context.Context, table string) (*EmbeddingSpace, error)
+	SetEmbeddingSpace(ctx context.Context, table string, space EmbeddingSpace) error
+	// ReplaceVectors swaps the vectors of existing records by ID in one
+	// transaction, leaving their metadata and history untouched
+	ReplaceVectors(ctx context.Context, table string, vectors map[string][]float32) error
+}
+
+// initEmbeddingRegistry creates the table of embedding spaces
+func (v *SQLiteVectorDB) initEmbeddingRegistry() error {
+	_, err := v.db.Exec(`
+		CREATE TABLE IF NOT EXISTS embedding_spaces (
+			table_name TEXT PRIMARY KEY,
+			model TEXT NOT NULL,
+			dimension INTEGER NOT NULL,
+			reindexing INTEGER NOT NULL DEFAULT 0,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create embedding_spaces table: %w", err)
+	}
+	return nil
+}
+
+// EmbeddingSpace returns the recorded embedding space of a table
+func (v *SQLiteVectorDB) EmbeddingSpace(ctx context.Context, table string) (*EmbeddingSpace, error) {
+	if err := ValidateTable(table); err != nil {
+		return nil, err
+	}
+	var space EmbeddingSpace
+	var updatedAt string
+	err := v.db.QueryRowContext(ctx, `
+		SELECT model, dimension, reindexing, updated_at FROM embedding_spaces WHERE table_name = ?
+	`, table).Scan(&space.Model, &space.Dimension, &space.Reindexing, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedding space: %w", err)
+	}
+	space.UpdatedAt, _ = time.Parse(sqliteTimeFormat, updatedAt)
+	return &space, nil
+}
+
+// SetEmbeddingSpace records the embedding space of a table
+func (v *SQLiteVectorDB) SetEmbeddingSpace(ctx context.Context, table string, space EmbeddingSpace) error {
+	if err := ValidateTable(table); err != nil {
+		return err
+	}
+	_, err := v.db.ExecContext(ctx, `
+		INSERT OR REPLACE INTO embedding_spaces (table_name, model, dimension, reindexing, updated_at)
+		VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
+	`, table, space.Model, space.Dimension, space.Reindexing)
+	if err != nil {
+		return fmt.Errorf("failed to record embedding space: %w", err)
+	}
+	return nil
+}
+
+// ReplaceVectors swaps the vectors of existing records. IDs that aren't
+// stored are ignored.
+func (v *SQLiteVectorDB) ReplaceVectors(ctx context.Context, table string, vectors map[string][]float32) (err error) {
+	ctx, span := startSpan(ctx, "vectordb.ReplaceVectors", "sqlite", table)
+	defer func() { tracing.End(span, err) }()
+
+	if err := ValidateTable(table); err != nil {
+		return err
+	}
+
+	tx, err := v.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf(`UPDATE %s SET vector = ? WHERE id = ?`, table)
+	for id, vector := range vectors {
+		vectorJSON, err := json.Marshal(vector)
+		if err != nil {
+			return fmt.Errorf("failed to marshal vector: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, query, string(vectorJSON), id); err != nil {
+			return fmt.Errorf("failed to replace vector: %w", err)
+		}
+	}
+	return tx.Commit()
+}
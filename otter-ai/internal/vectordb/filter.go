@@ -0,0 +1,182 @@
+package vectordb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"otter-ai/internal/errs"
+)
+
+// ErrInvalidFilter is returned for a filter on a metadata key that can't
+// be addressed safely
+var ErrInvalidFilter = errs.New(errs.ErrInvalid, "invalid filter")
+
+// filterKeyPattern restricts filter keys to plain top-level metadata fields
+var filterKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_]{1,64}$`)
+
+// Filter restricts searches and listings to records whose metadata matches.
+// Keys name top-level metadata fields; a record missing a filtered field
+// never matches. The zero Filter matches every record.
+type Filter struct {
+	Equals map[string]interface{} // Field equals a string, number or bool
+	Min    map[string]float64     // Numeric field is at least the value
+	Max    map[string]float64     // Numeric field is at most the value
+}
+
+// IsZero reports whether the filter matches every record
+func (f Filter) IsZero() bool {
+	return len(f.Equals) == 0 && len(f.Min) == 0 && len(f.Max) == 0
+}
+
+// Validate checks every filtered key is a plain metadata field and every
+// compared value a string, number or bool
+func (f Filter) Validate() error {
+	for _, keys := range []map[string]float64{f.Min, f.Max} {
+		for key := range keys {
+			if !filterKeyPattern.MatchString(key) {
+				return fmt.Errorf("%w: key %q", ErrInvalidFilter, key)
+			}
+		}
+	}
+	for key, value := range f.Equals {
+		if !filterKeyPattern.MatchString(key) {
+			return fmt.Errorf("%w: key %q", ErrInvalidFilter, key)
+		}
+		if _, ok := filterValue(value); !ok {
+			return fmt.Errorf("%w: unsupported value for %q", ErrInvalidFilter, key)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether metadata satisfies the filter. Backends that
+// can't push a filter down apply it with Matches.
+func (f Filter) Matches(metadata map[string]interface{}) bool {
+	for key, want := range f.Equals {
+		got, ok := filterValue(metadata[key])
+		if !ok {
+			return false
+		}
+		expected, _ := filterValue(want)
+		if got != expected {
+			return false
+		}
+	}
+	for key, min := range f.Min {
+		value, ok := metadata[key]
+		if n, isNumber := filterNumber(value); !ok || !isNumber || n < min {
+			return false
+		}
+	}
+	for key, max := range f.Max {
+		value, ok := metadata[key]
+		if n, isNumber := filterNumber(value); !ok || !isNumber || n > max {
+			return false
+		}
+	}
+	return true
+}
+
+// where renders the filter as SQL conditions on a JSON metadata column.
+// Keys are bound as JSON paths, never spliced into the query.
+func (f Filter) where() (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	for key, value := range f.Equals {
+		normalized, _ := filterValue(value)
+		conditions = append(conditions, "json_extract(metadata, ?) = ?")
+		args = append(args, filterPath(key), normalized)
+	}
+	for key, min := range f.Min {
+		conditions = append(conditions, "json_extract(metadata, ?) >= ?")
+		args = append(args, filterPath(key), min)
+	}
+	for key, max := range f.Max {
+		conditions = append(conditions, "json_extract(metadata, ?) <= ?")
+		args = append(args, filterPath(key), max)
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " AND " + strings.Join(conditions, " AND "), args
+}
+
+// filterPath is the JSON path of a top-level metadata key
+func filterPath(key string) string {
+	return `$."` + key + `"`
+}
+
+// filterValue normalizes a comparable metadata value: numbers to float64,
+// so values compare the same before and after a JSON round trip
+func filterValue(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case string, bool:
+		return v, true
+	}
+	if n, ok := filterNumber(value); ok {
+		return n, true
+	}
+	return nil, false
+}
+
+// filterNumber converts a numeric metadata value to float64
+func filterNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// FilteredLister is implemented by backends that can list only the records
+// matching a filter without reading the rest
+type FilteredLister interface {
+	ListFiltered(ctx context.Context, table string, filter Filter, limit, offset int) ([]Record, error)
+}
+
+// ListFiltered lists a table's records matching filter in the backend's
+// List order, pushing the filter down when the backend supports it and
+// iterating the table otherwise
+func ListFiltered(ctx context.Context, db VectorDB, table string, filter Filter, limit, offset int) ([]Record, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	if filter.IsZero() {
+		return db.List(ctx, table, limit, offset)
+	}
+	if lister, ok := db.(FilteredLister); ok {
+		return lister.ListFiltered(ctx, table, filter, limit, offset)
+	}
+
+	var records []Record
+	errDone := errors.New("done")
+	err := Iterate(ctx, db, table, func(record Record) error {
+		if !filter.Matches(record.Metadata) {
+			return nil
+		}
+		if offset > 0 {
+			offset--
+			return nil
+		}
+		records = append(records, record)
+		if limit > 0 && len(records) >= limit {
+			return errDone
+		}
+		return nil
+	})
+	if err != nil && err != errDone {
+		return nil, err
+	}
+	return records, nil
+}
@@ -0,0 +1,135 @@
+package vectordb
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func seedFilterRecords(t *testing.T, db VectorDB) {
+	t.Helper()
+	ctx := context.Background()
+	records := []struct {
+		id       string
+		metadata map[string]interface{}
+	}{
+		{"old", map[string]interface{}{"timestamp": 100, "scope": "work", "importance": 0.9, "source": "slack", "pinned": true}},
+		{"mid", map[string]interface{}{"timestamp": 200, "scope": "home", "importance": 0.2, "source": "slack", "pinned": false}},
+		{"new", map[string]interface{}{"timestamp": 300, "scope": "work", "importance": 0.5, "source": "email", "pinned": false}},
+	}
+	for _, record := range records {
+		if err := db.Store(ctx, TableMemories, record.id, vec(1, 0), record.metadata); err != nil {
+			t.Fatalf("Store %s: %v", record.id, err)
+		}
+	}
+}
+
+func resultIDs(results []SearchResult) map[string]bool {
+	ids := make(map[string]bool, len(results))
+	for _, result := range results {
+		ids[result.ID] = true
+	}
+	return ids
+}
+
+func TestSQLiteSearch_Filter(t *testing.T) {
+	db := tempDB(t)
+	seedFilterRecords(t, db)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{"zero", Filter{}, []string{"old", "mid", "new"}},
+		{"time range", Filter{Min: map[string]float64{"timestamp": 150}, Max: map[string]float64{"timestamp": 300}}, []string{"mid", "new"}},
+		{"scope", Filter{Equals: map[string]interface{}{"scope": "work"}}, []string{"old", "new"}},
+		{"min importance", Filter{Min: map[string]float64{"importance": 0.5}}, []string{"old", "new"}},
+		{"string metadata", Filter{Equals: map[string]interface{}{"source": "slack", "scope": "home"}}, []string{"mid"}},
+		{"bool metadata", Filter{Equals: map[string]interface{}{"pinned": true}}, []string{"old"}},
+		{"missing field", Filter{Equals: map[string]interface{}{"channel": "general"}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := db.Search(ctx, TableMemories, vec(1, 0), 10, tt.filter)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			ids := resultIDs(results)
+			if len(ids) != len(tt.want) {
+				t.Fatalf("got %v, want %v", ids, tt.want)
+			}
+			for _, id := range tt.want {
+				if !ids[id] {
+					t.Errorf("missing %s in %v", id, ids)
+				}
+			}
+			// The in-process fallback must agree with the SQL pushdown
+			for _, result := range results {
+				if !tt.filter.Matches(result.Metadata) {
+					t.Errorf("Matches rejected %s returned by SQL", result.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestSQLiteSearch_InvalidFilterKey(t *testing.T) {
+	db := tempDB(t)
+	filter := Filter{Equals: map[string]interface{}{`scope') OR 1=1 --`: "x"}}
+	if _, err := db.Search(context.Background(), TableMemories, vec(1), 5, filter); !errors.Is(err, ErrInvalidFilter) {
+		t.Errorf("err = %v, want ErrInvalidFilter", err)
+	}
+}
+
+func TestSQLiteListFiltered(t *testing.T) {
+	db := tempDB(t)
+	seedFilterRecords(t, db)
+
+	filter := Filter{Equals: map[string]interface{}{"source": "slack"}}
+	records, err := ListFiltered(context.Background(), db, TableMemories, filter, 1, 1)
+	if err != nil {
+		t.Fatalf("ListFiltered: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1", len(records))
+	}
+	if source := records[0].Metadata["source"]; source != "slack" {
+		t.Errorf("source = %v, want slack", source)
+	}
+}
+
+func TestListFiltered_Fallback(t *testing.T) {
+	db := &pagedDB{records: []Record{
+		{ID: "a", Metadata: map[string]interface{}{"scope": "work"}},
+		{ID: "b", Metadata: map[string]interface{}{"scope": "home"}},
+		{ID: "c", Metadata: map[string]interface{}{"scope": "work"}},
+		{ID: "d", Metadata: map[string]interface{}{"scope": "work"}},
+	}}
+	filter := Filter{Equals: map[string]interface{}{"scope": "work"}}
+
+	records, err := ListFiltered(context.Background(), db, TableMemories, filter, 2, 1)
+	if err != nil {
+		t.Fatalf("ListFiltered: %v", err)
+	}
+	if len(records) != 2 || records[0].ID != "c" || records[1].ID != "d" {
+		t.Errorf("got %v, want c and d", records)
+	}
+}
+
+func TestFilterMatches_Numbers(t *testing.T) {
+	filter := Filter{
+		Equals: map[string]interface{}{"count": 3},
+		Min:    map[string]float64{"importance": 0.5},
+	}
+	if !filter.Matches(map[string]interface{}{"count": float64(3), "importance": float32(0.5)}) {
+		t.Error("numbers should match across int and float types")
+	}
+	if filter.Matches(map[string]interface{}{"count": "3", "importance": 0.9}) {
+		t.Error("a string should not match a number")
+	}
+	if filter.Matches(map[string]interface{}{"count": 3}) {
+		t.Error("a missing field should not match a minimum")
+	}
+}
@@ -0,0 +1,195 @@
+package vectordb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"otter-ai/internal/tracing"
+)
+
+// KeywordSearcher is implemented by backends that index record content for
+// full-text search. Results are ordered best match first; scores are only
+// comparable within one result set.
+type KeywordSearcher interface {
+	KeywordSearch(ctx context.Context, table string, query string, limit int) ([]SearchResult, error)
+}
+
+// keywordTables are the tables whose "content" metadata is keyword indexed
+var keywordTables = map[string]bool{
+	TableMemories: true,
+	TableMusings:  true,
+	TableArchive:  true,
+}
+
+// ftsTable names the FTS5 index of a table
+func ftsTable(table string) string {
+	return table + "_fts"
+}
+
+// initFTS creates and backfills the FTS5 indexes. Drivers built without
+// FTS5 (mattn/go-sqlite3 without the sqlite_fts5 tag) fall back to scanning
+// content in KeywordSearch.
+func (v *SQLiteVectorDB) initFTS() error {
+	for table := range keywordTables {
+		query := fmt.Sprintf(`CREATE VIRTUAL TABLE IF NOT EXISTS %s USING fts5(id UNINDEXED, content)`, ftsTable(table))
+		if _, err := v.db.Exec(query); err != nil {
+			if strings.Contains(err.Error(), "no such module") {
+				v.fts = false
+				return nil
+			}
+			return fmt.Errorf("failed to create full-text index on %s: %w", table, err)
+		}
+
+		// Index rows written before the index existed
+		backfill := fmt.Sprintf(`
+			INSERT INTO %[1]s (id, content)
+			SELECT id, COALESCE(json_extract(metadata, '$.content'), '') FROM %[2]s
+			WHERE deleted_at IS NULL AND id NOT IN (SELECT id FROM %[1]s)
+		`, ftsTable(table), table)
+		if _, err := v.db.Exec(backfill); err != nil {
+			return fmt.Errorf("failed to backfill full-text index on %s: %w", table, err)
+		}
+	}
+	v.fts = true
+	return nil
+}
+
+// indexContent replaces a record's entry in its table's FTS5 index
+func (v *SQLiteVectorDB) indexContent(ctx context.Context, tx *sql.Tx, table, id string, metadata map[string]interface{}) error {
+	if err := v.unindexContent(ctx, tx, table, id); err != nil {
+		return err
+	}
+	content, _ := metadata["content"].(string)
+	stmt, err := v.stmt(ctx, tx, fmt.Sprintf(`INSERT INTO %s (id, content) VALUES (?, ?)`, ftsTable(table)))
+	if err != nil {
+		return err
+	}
+	if _, err := stmt.ExecContext(ctx, id, content); err != nil {
+		return fmt.Errorf("failed to index content: %w", err)
+	}
+	return nil
+}
+
+// unindexContent removes a record from its table's FTS5 index
+func (v *SQLiteVectorDB) unindexContent(ctx context.Context, tx *sql.Tx, table, id string) error {
+	stmt, err := v.stmt(ctx, tx, fmt.Sprintf(`DELETE FROM %s WHERE id = ?`, ftsTable(table)))
+	if err != nil {
+		return err
+	}
+	if _, err := stmt.ExecContext(ctx, id); err != nil {
+		return fmt.Errorf("failed to unindex content: %w", err)
+	}
+	return nil
+}
+
+// KeywordSearch ranks records whose content contains any of the query's
+// terms, by BM25 when FTS5 is available and by matched term count otherwise
+func (v *SQLiteVectorDB) KeywordSearch(ctx context.Context, table string, query string, limit int) (_ []SearchResult, err error) {
+	ctx, span := startSpan(ctx, "vectordb.KeywordSearch", "sqlite", table)
+	defer func() { tracing.End(span, err) }()
+
+	if err := ValidateTable(table); err != nil {
+		return nil, err
+	}
+	if !keywordTables[table] {
+		return nil, fmt.Errorf("table %s has no keyword index", table)
+	}
+	terms := keywordTerms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	if !v.fts {
+		return scanKeywords(ctx, v, table, terms, limit)
+	}
+
+	// Quote each term so punctuation in names and IDs isn't FTS5 syntax
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+	}
+	if limit <= 0 {
+		limit = -1 // No limit
+	}
+
+	sqlQuery := fmt.Sprintf(`
+		SELECT t.id, t.vector, t.metadata, bm25(%[1]s) FROM %[1]s
+		JOIN %[2]s t ON t.id = %[1]s.id
+		WHERE %[1]s MATCH ? AND t.deleted_at IS NULL
+		ORDER BY bm25(%[1]s)
+		LIMIT ?
+	`, ftsTable(table), table)
+
+	rows, err := v.db.QueryContext(ctx, sqlQuery, strings.Join(quoted, " OR "), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search keywords: %w", err)
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	for rows.Next() {
+		var id, vectorStr, metadataStr string
+		var rank float64
+		if err := rows.Scan(&id, &vectorStr, &metadataStr, &rank); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		record, ok := decodeRecord(id, vectorStr, metadataStr)
+		if !ok {
+			continue
+		}
+		// bm25() is lower for better matches
+		results = append(results, SearchResult{ID: id, Score: -rank, Metadata: record.Metadata, Vector: record.Vector})
+	}
+	return results, rows.Err()
+}
+
+// scanKeywords ranks a table's records by how many of the terms their
+// content contains, for keyword search without an index
+func scanKeywords(ctx context.Context, db Iterator, table string, terms []string, limit int) ([]SearchResult, error) {
+	var results []SearchResult
+	err := db.Iterate(ctx, table, func(record Record) error {
+		content, _ := record.Metadata["content"].(string)
+		content = strings.ToLower(content)
+		matched := 0
+		for _, term := range terms {
+			if strings.Contains(content, term) {
+				matched++
+			}
+		}
+		if matched > 0 {
+			results = append(results, SearchResult{ID: record.ID, Score: float64(matched), Metadata: record.Metadata, Vector: record.Vector})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if limit > 0 && limit < len(results) {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// keywordTerms splits a query into distinct lowercase terms. Letters,
+// digits and the punctuation common in IDs stay inside a term.
+func keywordTerms(query string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' && r != '.' && r != '@'
+	})
+	seen := make(map[string]bool, len(fields))
+	var terms []string
+	for _, field := range fields {
+		field = strings.Trim(field, "-_.@")
+		if field == "" || seen[field] {
+			continue
+		}
+		seen[field] = true
+		terms = append(terms, field)
+	}
+	return terms
+}
@@ -0,0 +1,100 @@
+package vectordb
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func storeContent(t *testing.T, db *SQLiteVectorDB, id, content string, vector []float32) {
+	t.Helper()
+	if err := db.Store(context.Background(), TableMemories, id, vector, map[string]interface{}{"content": content}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKeywordSearch(t *testing.T) {
+	for _, fts := range []bool{true, false} {
+		db := tempDB(t)
+		if fts && !db.fts {
+			t.Log("driver built without FTS5; only the fallback scan is tested")
+			continue
+		}
+		db.fts = fts
+		ctx := context.Background()
+
+		storeContent(t, db, "m1", "Ticket OTR-4411 was escalated to Priya", vec(1, 0))
+		storeContent(t, db, "m2", "The deploy was escalated on friday", vec(0, 1))
+		storeContent(t, db, "m3", "Otters hold hands while sleeping", vec(1, 1))
+
+		results, err := db.KeywordSearch(ctx, TableMemories, "who handled OTR-4411?", 10)
+		if err != nil {
+			t.Fatalf("fts=%v: %v", fts, err)
+		}
+		if len(results) != 1 || results[0].ID != "m1" {
+			t.Errorf("fts=%v: expected only m1, got %+v", fts, results)
+		}
+
+		results, _ = db.KeywordSearch(ctx, TableMemories, "escalated priya", 10)
+		if len(results) != 2 || results[0].ID != "m1" {
+			t.Errorf("fts=%v: expected m1 ranked above m2, got %+v", fts, results)
+		}
+
+		// Replaced and deleted content leaves the index
+		storeContent(t, db, "m1", "Ticket closed", vec(1, 0))
+		db.Delete(ctx, TableMemories, "m2")
+		if results, _ := db.KeywordSearch(ctx, TableMemories, "escalated", 10); len(results) != 0 {
+			t.Errorf("fts=%v: expected stale content to be unindexed, got %+v", fts, results)
+		}
+	}
+}
+
+func TestKeywordSearch_UnindexedTable(t *testing.T) {
+	db := tempDB(t)
+	if _, err := db.KeywordSearch(context.Background(), TableSessions, "anything", 5); err == nil {
+		t.Error("expected error for a table without a keyword index")
+	}
+	if results, err := db.KeywordSearch(context.Background(), TableMemories, " ?! ", 5); err != nil || results != nil {
+		t.Errorf("expected no results for a query without terms, got %v, %v", results, err)
+	}
+}
+
+func TestInitFTS_Backfills(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "otter.db")
+	db, err := NewSQLiteVectorDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !db.fts {
+		db.Close()
+		t.Skip("driver built without FTS5")
+	}
+	storeContent(t, db, "m1", "the kelp forest", vec(1))
+	// Simulate rows written before the index existed
+	if _, err := db.db.Exec(`DELETE FROM memories_fts`); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	db, err = NewSQLiteVectorDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if results, _ := db.KeywordSearch(context.Background(), TableMemories, "kelp", 5); len(results) != 1 {
+		t.Errorf("expected the existing row to be backfilled, got %+v", results)
+	}
+}
+
+func TestKeywordTerms(t *testing.T) {
+	got := keywordTerms(`Who is "alice@example.com", re: OTR-4411... and OTR-4411?`)
+	want := []string{"who", "is", "alice@example.com", "re", "otr-4411", "and"}
+	if len(got) != len(want) {
+		t.Fatalf("keywordTerms = %q", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("term %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,504 @@
+package vectordb
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	arrowmemory "github.com/apache/arrow-go/v18/arrow/memory"
+
+	"otter-ai/internal/tracing"
+)
+
+// LanceDB configuration
+const (
+	LanceDBTimeout          = 30 * time.Second
+	LanceDBMaxResponseSize  = 256 << 20
+	LanceDBIndexMinRows     = 10000 // Rows needed before an ANN index is worth building
+	LanceDBIndexEvery       = 10000 // Stores between index (re)builds per table
+	LanceDBFilterOversample = 4     // Candidates fetched per result when filtering searches
+	arrowStreamContentType  = "application/vnd.apache.arrow.stream"
+)
+
+// LanceDBConfig locates a LanceDB REST server
+type LanceDBConfig struct {
+	URL      string // Base URL, e.g. https://mydb.us-east-1.api.lancedb.com
+	APIKey   string // Sent as x-api-key
+	Database string // Sent as x-lancedb-database, for servers hosting several databases
+}
+
+// errLanceTableNotFound is returned when a table has not been created yet
+var errLanceTableNotFound = errors.New("lancedb table not found")
+
+// LanceDBVectorDB stores embedding tables in LanceDB, which keeps them in a
+// columnar format with a disk-based ANN index. Governance state and tables
+// without embeddings (sessions) stay in a local SQLite database, which is
+// also what GetDB returns.
+type LanceDBVectorDB struct {
+	cfg    LanceDBConfig
+	client *http.Client
+	local  *SQLiteVectorDB
+
+	mu     sync.Mutex
+	stores map[string]int // Stores per table since the last index build
+}
+
+// NewLanceDBVectorDB connects to a LanceDB server and opens the local
+// SQLite database at dbPath
+func NewLanceDBVectorDB(cfg LanceDBConfig, dbPath string, local SQLiteConfig) (*LanceDBVectorDB, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("lancedb URL is required")
+	}
+	if _, err := url.ParseRequestURI(cfg.URL); err != nil {
+		return nil, fmt.Errorf("invalid lancedb URL: %w", err)
+	}
+	cfg.URL = strings.TrimRight(cfg.URL, "/")
+
+	localDB, err := NewSQLiteVectorDBWithConfig(local, dbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LanceDBVectorDB{
+		cfg:    cfg,
+		client: &http.Client{Timeout: LanceDBTimeout},
+		local:  localDB,
+		stores: make(map[string]int),
+	}, nil
+}
+
+// remoteTable reports whether a table is stored by a remote vector backend
+// rather than in the local SQLite database
+func remoteTable(table string) bool {
+	switch table {
+	case TableSessions, TableOnboarding, TableDelegations, TableSchedule:
+		return false
+	}
+	return true
+}
+
+// Store upserts a vector with metadata
+func (v *LanceDBVectorDB) Store(ctx context.Context, table string, id string, vector []float32, metadata map[string]interface{}) (err error) {
+	ctx, span := startSpan(ctx, "vectordb.Store", "lancedb", table)
+	defer func() { tracing.End(span, err) }()
+
+	if err := ValidateTable(table); err != nil {
+		return err
+	}
+	if !remoteTable(table) {
+		return v.local.Store(ctx, table, id, vector, metadata)
+	}
+	if len(vector) == 0 {
+		return fmt.Errorf("lancedb table %s requires an embedding", table)
+	}
+
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	batch, err := encodeLanceRecord(id, vector, string(metadataJSON))
+	if err != nil {
+		return err
+	}
+
+	merge := url.Values{
+		"on":                          {"id"},
+		"when_matched_update_all":     {"true"},
+		"when_not_matched_insert_all": {"true"},
+	}
+	_, err = v.do(ctx, table, "merge_insert", merge, arrowStreamContentType, batch)
+	if errors.Is(err, errLanceTableNotFound) {
+		// First record of the table: create it with this record
+		_, err = v.do(ctx, table, "create", nil, arrowStreamContentType, batch)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store vector: %w", err)
+	}
+
+	v.maybeIndex(ctx, table)
+	return nil
+}
+
+// Search searches for similar vectors using the table's ANN index, or a
+// flat scan while the table is too small to have one. Metadata is opaque to
+// LanceDB, so filters are applied to an oversampled candidate set.
+func (v *LanceDBVectorDB) Search(ctx context.Context, table string, queryVector []float32, limit int, filter Filter) (_ []SearchResult, err error) {
+	ctx, span := startSpan(ctx, "vectordb.Search", "lancedb", table)
+	defer func() { tracing.End(span, err) }()
+
+	if err := ValidateTable(table); err != nil {
+		return nil, err
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	if !remoteTable(table) {
+		return v.local.Search(ctx, table, queryVector, limit, filter)
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	k := limit
+	if !filter.IsZero() {
+		k *= LanceDBFilterOversample
+	}
+
+	rows, err := v.query(ctx, table, map[string]interface{}{
+		"vector":        queryVector,
+		"k":             k,
+		"distance_type": "cosine",
+		"columns":       []string{"id", "vector", "metadata"},
+	})
+	if errors.Is(err, errLanceTableNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query vectors: %w", err)
+	}
+
+	results := make([]SearchResult, 0, len(rows))
+	for _, row := range rows {
+		if len(results) == limit {
+			break
+		}
+		if !filter.Matches(row.Metadata) {
+			continue
+		}
+		results = append(results, SearchResult{
+			ID:       row.ID,
+			Score:    1 - row.Distance, // Cosine distance back to similarity
+			Metadata: row.Metadata,
+			Vector:   row.Vector,
+		})
+	}
+	return results, nil
+}
+
+// Get retrieves a record by ID
+func (v *LanceDBVectorDB) Get(ctx context.Context, table string, id string) (_ *Record, err error) {
+	ctx, span := startSpan(ctx, "vectordb.Get", "lancedb", table)
+	defer func() { tracing.End(span, err) }()
+
+	if err := ValidateTable(table); err != nil {
+		return nil, err
+	}
+	if !remoteTable(table) {
+		return v.local.Get(ctx, table, id)
+	}
+
+	rows, err := v.query(ctx, table, map[string]interface{}{
+		"filter":  "id = " + lanceString(id),
+		"k":       1,
+		"columns": []string{"id", "vector", "metadata"},
+	})
+	if errors.Is(err, errLanceTableNotFound) || (err == nil && len(rows) == 0) {
+		return nil, fmt.Errorf("record not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get record: %w", err)
+	}
+
+	return &Record{ID: rows[0].ID, Vector: rows[0].Vector, Metadata: rows[0].Metadata}, nil
+}
+
+// Delete removes a record by ID
+func (v *LanceDBVectorDB) Delete(ctx context.Context, table string, id string) (err error) {
+	ctx, span := startSpan(ctx, "vectordb.Delete", "lancedb", table)
+	defer func() { tracing.End(span, err) }()
+
+	if err := ValidateTable(table); err != nil {
+		return err
+	}
+	if !remoteTable(table) {
+		return v.local.Delete(ctx, table, id)
+	}
+
+	body, _ := json.Marshal(map[string]string{"predicate": "id = " + lanceString(id)})
+	if _, err := v.do(ctx, table, "delete", nil, "application/json", body); err != nil && !errors.Is(err, errLanceTableNotFound) {
+		return fmt.Errorf("failed to delete record: %w", err)
+	}
+	return nil
+}
+
+// List retrieves records with pagination. LanceDB returns rows in storage
+// order, so unlike SQLite the oldest records come first.
+func (v *LanceDBVectorDB) List(ctx context.Context, table string, limit, offset int) (_ []Record, err error) {
+	ctx, span := startSpan(ctx, "vectordb.List", "lancedb", table)
+	defer func() { tracing.End(span, err) }()
+
+	if err := ValidateTable(table); err != nil {
+		return nil, err
+	}
+	if !remoteTable(table) {
+		return v.local.List(ctx, table, limit, offset)
+	}
+
+	rows, err := v.query(ctx, table, map[string]interface{}{
+		"k":       limit,
+		"offset":  offset,
+		"columns": []string{"id", "vector", "metadata"},
+	})
+	if errors.Is(err, errLanceTableNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list records: %w", err)
+	}
+
+	var records []Record
+	for _, row := range rows {
+		records = append(records, Record{ID: row.ID, Vector: row.Vector, Metadata: row.Metadata})
+	}
+	return records, nil
+}
+
+// Close closes the local database
+func (v *LanceDBVectorDB) Close() error {
+	v.client.CloseIdleConnections()
+	return v.local.Close()
+}
+
+// GetDB returns the local database used for governance persistence
+func (v *LanceDBVectorDB) GetDB() *sql.DB {
+	return v.local.GetDB()
+}
+
+// Maintain maintains the local database
+func (v *LanceDBVectorDB) Maintain(ctx context.Context) (MaintenanceRun, error) {
+	return v.local.Maintain(ctx)
+}
+
+// Storage reports on the local database
+func (v *LanceDBVectorDB) Storage(ctx context.Context) (StorageReport, error) {
+	return v.local.Storage(ctx)
+}
+
+// CreateIndex builds (or rebuilds) the IVF_PQ index on a table's vectors.
+// The server builds it in the background.
+func (v *LanceDBVectorDB) CreateIndex(ctx context.Context, table string) error {
+	if err := ValidateTable(table); err != nil {
+		return err
+	}
+	body, _ := json.Marshal(map[string]interface{}{
+		"column":      "vector",
+		"index_type":  "IVF_PQ",
+		"metric_type": "cosine",
+		"replace":     true,
+	})
+	_, err := v.do(ctx, table, "create_index", nil, "application/json", body)
+	return err
+}
+
+// maybeIndex rebuilds a table's index every LanceDBIndexEvery stores once it
+// holds LanceDBIndexMinRows rows, so new vectors are covered by the ANN index
+func (v *LanceDBVectorDB) maybeIndex(ctx context.Context, table string) {
+	v.mu.Lock()
+	v.stores[table]++
+	due := v.stores[table] >= LanceDBIndexEvery
+	if due {
+		v.stores[table] = 0
+	}
+	v.mu.Unlock()
+	if !due {
+		return
+	}
+
+	body, _ := json.Marshal(map[string]interface{}{})
+	resp, err := v.do(ctx, table, "count_rows", nil, "application/json", body)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to count rows of lancedb table", "table", table, "error", err)
+		return
+	}
+	var rows int
+	if err := json.Unmarshal(resp, &rows); err != nil || rows < LanceDBIndexMinRows {
+		return
+	}
+	if err := v.CreateIndex(ctx, table); err != nil {
+		slog.WarnContext(ctx, "failed to index lancedb table", "table", table, "error", err)
+	}
+}
+
+// lanceRow is a decoded query result row
+type lanceRow struct {
+	ID       string
+	Vector   []float32
+	Metadata map[string]interface{}
+	Distance float64
+}
+
+// query runs a table query and decodes the Arrow result
+func (v *LanceDBVectorDB) query(ctx context.Context, table string, request map[string]interface{}) ([]lanceRow, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal query: %w", err)
+	}
+	resp, err := v.do(ctx, table, "query", nil, "application/json", body)
+	if err != nil {
+		return nil, err
+	}
+	return decodeLanceRows(resp)
+}
+
+// do calls a table endpoint and returns the response body
+func (v *LanceDBVectorDB) do(ctx context.Context, table, action string, query url.Values, contentType string, body []byte) ([]byte, error) {
+	endpoint := fmt.Sprintf("%s/v1/table/%s/%s/", v.cfg.URL, url.PathEscape(table), action)
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if v.cfg.APIKey != "" {
+		req.Header.Set("x-api-key", v.cfg.APIKey)
+	}
+	if v.cfg.Database != "" {
+		req.Header.Set("x-lancedb-database", v.cfg.Database)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("lancedb request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, LanceDBMaxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed reading lancedb response: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", errLanceTableNotFound, table)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("lancedb %s returned status %d: %s", action, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return respBody, nil
+}
+
+// lanceString quotes a string literal for a LanceDB SQL filter
+func lanceString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// encodeLanceRecord encodes one row as an Arrow IPC stream
+func encodeLanceRecord(id string, vector []float32, metadata string) ([]byte, error) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.BinaryTypes.String},
+		{Name: "vector", Type: arrow.FixedSizeListOf(int32(len(vector)), arrow.PrimitiveTypes.Float32)},
+		{Name: "metadata", Type: arrow.BinaryTypes.String},
+	}, nil)
+
+	builder := array.NewRecordBuilder(arrowmemory.DefaultAllocator, schema)
+	defer builder.Release()
+
+	builder.Field(0).(*array.StringBuilder).Append(id)
+	vectors := builder.Field(1).(*array.FixedSizeListBuilder)
+	vectors.Append(true)
+	vectors.ValueBuilder().(*array.Float32Builder).AppendValues(vector, nil)
+	builder.Field(2).(*array.StringBuilder).Append(metadata)
+
+	record := builder.NewRecord()
+	defer record.Release()
+
+	var buf bytes.Buffer
+	writer := ipc.NewWriter(&buf, ipc.WithSchema(schema))
+	if err := writer.Write(record); err != nil {
+		return nil, fmt.Errorf("failed to encode record: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode record: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeLanceRows decodes an Arrow IPC file or stream of query results
+func decodeLanceRows(body []byte) ([]lanceRow, error) {
+	var rows []lanceRow
+	if len(bytes.TrimSpace(body)) == 0 {
+		return rows, nil
+	}
+
+	if bytes.HasPrefix(body, []byte("ARROW1")) {
+		reader, err := ipc.NewFileReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("invalid arrow file: %w", err)
+		}
+		defer reader.Close()
+		for i := 0; i < reader.NumRecords(); i++ {
+			record, err := reader.Record(i)
+			if err != nil {
+				return nil, fmt.Errorf("invalid arrow record: %w", err)
+			}
+			rows = appendLanceRows(rows, record)
+		}
+		return rows, nil
+	}
+
+	reader, err := ipc.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("invalid arrow stream: %w", err)
+	}
+	defer reader.Release()
+	for reader.Next() {
+		rows = appendLanceRows(rows, reader.Record())
+	}
+	if err := reader.Err(); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid arrow stream: %w", err)
+	}
+	return rows, nil
+}
+
+// appendLanceRows decodes the id, vector, metadata and _distance columns
+func appendLanceRows(rows []lanceRow, record arrow.Record) []lanceRow {
+	column := func(name string) arrow.Array {
+		if indices := record.Schema().FieldIndices(name); len(indices) > 0 {
+			return record.Column(indices[0])
+		}
+		return nil
+	}
+	ids, _ := column("id").(interface{ Value(int) string })
+	vectors, _ := column("vector").(array.ListLike)
+	metadata, _ := column("metadata").(interface{ Value(int) string })
+	distances := column("_distance")
+
+	for i := 0; i < int(record.NumRows()); i++ {
+		row := lanceRow{Metadata: make(map[string]interface{})}
+		if ids != nil {
+			row.ID = ids.Value(i)
+		}
+		if vectors != nil {
+			if values, ok := vectors.ListValues().(*array.Float32); ok {
+				start, end := vectors.ValueOffsets(i)
+				row.Vector = append([]float32(nil), values.Float32Values()[start:end]...)
+			}
+		}
+		if metadata != nil && metadata.(arrow.Array).IsValid(i) {
+			if err := json.Unmarshal([]byte(metadata.Value(i)), &row.Metadata); err != nil {
+				row.Metadata = make(map[string]interface{})
+			}
+		}
+		switch d := distances.(type) {
+		case *array.Float32:
+			row.Distance = float64(d.Value(i))
+		case *array.Float64:
+			row.Distance = d.Value(i)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
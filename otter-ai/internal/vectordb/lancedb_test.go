@@ -0,0 +1,264 @@
+package vectordb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	arrowmemory "github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// fakeLance is an in-memory stand-in for the LanceDB REST API
+type fakeLance struct {
+	mu     sync.Mutex
+	tables map[string][]lanceRow
+	calls  []string
+	apiKey string
+}
+
+func newFakeLance(t *testing.T) (*fakeLance, *httptest.Server) {
+	fake := &fakeLance{tables: make(map[string][]lanceRow)}
+	srv := httptest.NewServer(http.HandlerFunc(fake.serve))
+	t.Cleanup(srv.Close)
+	return fake, srv
+}
+
+func (f *fakeLance) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/") // v1 table <name> <action>
+	table, action := parts[2], parts[3]
+	f.calls = append(f.calls, action)
+	f.apiKey = r.Header.Get("x-api-key")
+	body, _ := io.ReadAll(r.Body)
+
+	rows, exists := f.tables[table]
+	if !exists && action != "create" {
+		http.Error(w, "table not found", http.StatusNotFound)
+		return
+	}
+
+	switch action {
+	case "create", "merge_insert":
+		incoming, err := decodeLanceRows(body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, row := range incoming {
+			replaced := false
+			for i := range rows {
+				if rows[i].ID == row.ID {
+					rows[i], replaced = row, true
+				}
+			}
+			if !replaced {
+				rows = append(rows, row)
+			}
+		}
+		f.tables[table] = rows
+		w.Write([]byte("{}"))
+
+	case "delete":
+		var req struct{ Predicate string }
+		json.Unmarshal(body, &req)
+		kept := rows[:0]
+		for _, row := range rows {
+			if "id = "+lanceString(row.ID) != req.Predicate {
+				kept = append(kept, row)
+			}
+		}
+		f.tables[table] = kept
+		w.Write([]byte("{}"))
+
+	case "query":
+		var req struct {
+			Vector []float32
+			K      int
+			Offset int
+			Filter string
+		}
+		json.Unmarshal(body, &req)
+		var results []lanceRow
+		for _, row := range rows {
+			if req.Filter != "" && "id = "+lanceString(row.ID) != req.Filter {
+				continue
+			}
+			if req.Vector != nil {
+				row.Distance = 1 - CosineSimilarity(req.Vector, row.Vector)
+			}
+			results = append(results, row)
+		}
+		if req.Vector != nil {
+			sort.Slice(results, func(i, j int) bool { return results[i].Distance < results[j].Distance })
+		}
+		if req.Offset < len(results) {
+			results = results[req.Offset:]
+		} else {
+			results = nil
+		}
+		if req.K > 0 && req.K < len(results) {
+			results = results[:req.K]
+		}
+		w.Header().Set("Content-Type", "application/vnd.apache.arrow.file")
+		w.Write(encodeArrowFile(results))
+
+	case "count_rows":
+		json.NewEncoder(w).Encode(len(rows))
+
+	case "create_index":
+		w.Write([]byte("{}"))
+	}
+}
+
+// encodeArrowFile encodes query results the way LanceDB returns them
+func encodeArrowFile(rows []lanceRow) []byte {
+	dim := 0
+	if len(rows) > 0 {
+		dim = len(rows[0].Vector)
+	}
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.BinaryTypes.String},
+		{Name: "vector", Type: arrow.FixedSizeListOf(int32(dim), arrow.PrimitiveTypes.Float32)},
+		{Name: "metadata", Type: arrow.BinaryTypes.String},
+		{Name: "_distance", Type: arrow.PrimitiveTypes.Float32},
+	}, nil)
+	builder := array.NewRecordBuilder(arrowmemory.DefaultAllocator, schema)
+	defer builder.Release()
+	for _, row := range rows {
+		builder.Field(0).(*array.StringBuilder).Append(row.ID)
+		vectors := builder.Field(1).(*array.FixedSizeListBuilder)
+		vectors.Append(true)
+		vectors.ValueBuilder().(*array.Float32Builder).AppendValues(row.Vector, nil)
+		metadata, _ := json.Marshal(row.Metadata)
+		builder.Field(2).(*array.StringBuilder).Append(string(metadata))
+		builder.Field(3).(*array.Float32Builder).Append(float32(row.Distance))
+	}
+	record := builder.NewRecord()
+	defer record.Release()
+
+	var buf bytes.Buffer
+	writer, _ := ipc.NewFileWriter(&buf, ipc.WithSchema(schema))
+	writer.Write(record)
+	writer.Close()
+	return buf.Bytes()
+}
+
+func newTestLanceDB(t *testing.T) (*LanceDBVectorDB, *fakeLance) {
+	t.Helper()
+	fake, srv := newFakeLance(t)
+	db, err := NewLanceDBVectorDB(LanceDBConfig{URL: srv.URL + "/", APIKey: "key"}, filepath.Join(t.TempDir(), "otter.db"), SQLiteConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, fake
+}
+
+func TestLanceDB_StoreGetSearch(t *testing.T) {
+	db, fake := newTestLanceDB(t)
+	ctx := context.Background()
+
+	if err := db.Store(ctx, TableMemories, "a", []float32{1, 0}, map[string]interface{}{"content": "apples"}); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	if err := db.Store(ctx, TableMemories, "b", []float32{0, 1}, map[string]interface{}{"content": "bees"}); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	if fake.calls[0] != "merge_insert" || fake.calls[1] != "create" || fake.calls[2] != "merge_insert" {
+		t.Errorf("first store should create the table, calls = %v", fake.calls)
+	}
+	if fake.apiKey != "key" {
+		t.Errorf("x-api-key = %q", fake.apiKey)
+	}
+
+	record, err := db.Get(ctx, TableMemories, "b")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if record.Metadata["content"] != "bees" || len(record.Vector) != 2 || record.Vector[1] != 1 {
+		t.Errorf("unexpected record %+v", record)
+	}
+
+	results, err := db.Search(ctx, TableMemories, []float32{0.9, 0.1}, 1, Filter{})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "a" || results[0].Score < 0.9 {
+		t.Errorf("unexpected results %+v", results)
+	}
+
+	// Upsert replaces rather than duplicates
+	db.Store(ctx, TableMemories, "a", []float32{1, 0}, map[string]interface{}{"content": "apricots"})
+	records, err := db.List(ctx, TableMemories, 10, 0)
+	if err != nil || len(records) != 2 {
+		t.Fatalf("List = %d records, %v", len(records), err)
+	}
+}
+
+func TestLanceDB_MissingTable(t *testing.T) {
+	db, _ := newTestLanceDB(t)
+	ctx := context.Background()
+
+	if results, err := db.Search(ctx, TableMusings, []float32{1}, 5, Filter{}); err != nil || len(results) != 0 {
+		t.Errorf("Search on a missing table = %v, %v", results, err)
+	}
+	if _, err := db.Get(ctx, TableMusings, "x"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Get on a missing table: %v", err)
+	}
+	if err := db.Delete(ctx, TableMusings, "x"); err != nil {
+		t.Errorf("Delete on a missing table: %v", err)
+	}
+}
+
+func TestLanceDB_Delete(t *testing.T) {
+	db, _ := newTestLanceDB(t)
+	ctx := context.Background()
+
+	db.Store(ctx, TableMemories, "it's", []float32{1, 0}, nil)
+	if err := db.Delete(ctx, TableMemories, "it's"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := db.Get(ctx, TableMemories, "it's"); err == nil {
+		t.Error("record should be gone")
+	}
+}
+
+func TestLanceDB_SessionsAndGovernanceStayLocal(t *testing.T) {
+	db, fake := newTestLanceDB(t)
+	ctx := context.Background()
+
+	if err := db.Store(ctx, TableSessions, "s1", nil, map[string]interface{}{"summary": "hi"}); err != nil {
+		t.Fatalf("Store session: %v", err)
+	}
+	if len(fake.calls) != 0 {
+		t.Errorf("sessions should not reach lancedb, calls = %v", fake.calls)
+	}
+	if _, err := db.Get(ctx, TableSessions, "s1"); err != nil {
+		t.Errorf("Get session: %v", err)
+	}
+	if db.GetDB() == nil {
+		t.Error("GetDB should expose the local database for governance")
+	}
+	if err := db.Store(ctx, TableMemories, "m", nil, nil); err == nil {
+		t.Error("memory tables need an embedding")
+	}
+}
+
+func TestNewLanceDBVectorDB_RequiresURL(t *testing.T) {
+	if _, err := NewLanceDBVectorDB(LanceDBConfig{}, filepath.Join(t.TempDir(), "otter.db"), SQLiteConfig{}); err == nil {
+		t.Error("expected error without URL")
+	}
+}
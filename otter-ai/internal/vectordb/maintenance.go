@@ -0,0 +1,293 @@
+package vectordb
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IntegrityCheckLimit bounds the problems an integrity check reports
+const IntegrityCheckLimit = 100
+
+// autoVacuumIncremental is PRAGMA auto_vacuum's value for incremental mode
+const autoVacuumIncremental = 2
+
+// MaintenanceRun describes one maintenance pass over the SQLite database
+type MaintenanceRun struct {
+	StartedAt time.Time     `json:"started_at"`
+	Duration  time.Duration `json:"duration"`
+	// Integrity is "ok" or the problems PRAGMA integrity_check found
+	Integrity []string `json:"integrity"`
+	// Converted is set on the run that switched the database to incremental
+	// auto-vacuum, which takes one full VACUUM
+	Converted bool `json:"converted,omitempty"`
+	// VacuumedPages is the number of free pages returned to the filesystem
+	VacuumedPages int64 `json:"vacuumed_pages"`
+	// CheckpointedFrames is the number of WAL frames copied into the
+	// database; -1 when the database isn't in WAL mode
+	CheckpointedFrames int64  `json:"checkpointed_frames"`
+	Error              string `json:"error,omitempty"`
+}
+
+// Healthy reports whether the run completed and found no corruption
+func (r MaintenanceRun) Healthy() bool {
+	return r.Error == "" && len(r.Integrity) == 1 && r.Integrity[0] == "ok"
+}
+
+// StorageTable is the size of one table of the SQLite database
+type StorageTable struct {
+	Name string `json:"name"`
+	Rows int64  `json:"rows"`
+	// Bytes taken by the table and its indexes; nil when the SQLite build
+	// lacks the dbstat table
+	Bytes *int64 `json:"bytes,omitempty"`
+}
+
+// StorageReport describes the SQLite database's size and upkeep
+type StorageReport struct {
+	Path           string          `json:"path"`
+	DatabaseBytes  int64           `json:"database_bytes"`
+	FreeBytes      int64           `json:"free_bytes"` // Unused pages the next vacuum can return
+	WALBytes       int64           `json:"wal_bytes"`
+	JournalMode    string          `json:"journal_mode"`
+	AutoVacuum     string          `json:"auto_vacuum"`
+	Tables         []StorageTable  `json:"tables"`
+	LastMaintained *MaintenanceRun `json:"last_maintenance,omitempty"`
+}
+
+// maintainer runs Maintain every interval until stopped
+type maintainer struct {
+	mu   sync.Mutex
+	last *MaintenanceRun
+
+	cancel context.CancelFunc // Stops scheduled maintenance; nil when unscheduled
+	done   chan struct{}
+}
+
+// startMaintenance maintains the database every interval
+func (v *SQLiteVectorDB) startMaintenance(interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	v.maintenance.cancel = cancel
+	v.maintenance.done = make(chan struct{})
+	go func() {
+		defer close(v.maintenance.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				run, err := v.Maintain(ctx)
+				switch {
+				case ctx.Err() != nil:
+					return
+				case err != nil:
+					slog.Warn("sqlite maintenance failed", "error", err)
+				case !run.Healthy():
+					slog.Error("sqlite integrity check found problems", "problems", run.Integrity)
+				default:
+					slog.Info("sqlite maintenance done", "duration", run.Duration,
+						"vacuumed_pages", run.VacuumedPages, "checkpointed_frames", run.CheckpointedFrames)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// stopMaintenance stops scheduled maintenance, cancelling a run in progress
+func (v *SQLiteVectorDB) stopMaintenance() {
+	if v.maintenance.cancel == nil {
+		return
+	}
+	v.maintenance.cancel()
+	<-v.maintenance.done
+}
+
+// Maintain checks the database's integrity, returns its free pages to the
+// filesystem with an incremental vacuum and checkpoints the write-ahead
+// log. A database created before incremental auto-vacuum was the default
+// is converted by one full VACUUM. The run is kept for Storage even when
+// it fails.
+func (v *SQLiteVectorDB) Maintain(ctx context.Context) (MaintenanceRun, error) {
+	run := MaintenanceRun{StartedAt: time.Now(), CheckpointedFrames: -1}
+	err := v.maintain(ctx, &run)
+	run.Duration = time.Since(run.StartedAt)
+	if err != nil {
+		run.Error = err.Error()
+	}
+
+	v.maintenance.mu.Lock()
+	v.maintenance.last = &run
+	v.maintenance.mu.Unlock()
+	return run, err
+}
+
+func (v *SQLiteVectorDB) maintain(ctx context.Context, run *MaintenanceRun) error {
+	rows, err := v.db.QueryContext(ctx, fmt.Sprintf("PRAGMA integrity_check(%d)", IntegrityCheckLimit))
+	if err != nil {
+		return fmt.Errorf("failed to check integrity: %w", err)
+	}
+	for rows.Next() {
+		var problem string
+		if err := rows.Scan(&problem); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to read integrity check: %w", err)
+		}
+		run.Integrity = append(run.Integrity, problem)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to check integrity: %w", err)
+	}
+
+	mode, err := v.pragmaInt(ctx, "auto_vacuum")
+	if err != nil {
+		return err
+	}
+	freeBefore, err := v.pragmaInt(ctx, "freelist_count")
+	if err != nil {
+		return err
+	}
+	if mode != autoVacuumIncremental {
+		// Changing the mode of a database with tables takes a full VACUUM
+		if _, err := v.db.ExecContext(ctx, "PRAGMA auto_vacuum=INCREMENTAL"); err != nil {
+			return fmt.Errorf("failed to set auto_vacuum: %w", err)
+		}
+		if _, err := v.db.ExecContext(ctx, "VACUUM"); err != nil {
+			return fmt.Errorf("failed to vacuum: %w", err)
+		}
+		run.Converted = true
+	} else if _, err := v.db.ExecContext(ctx, "PRAGMA incremental_vacuum"); err != nil {
+		return fmt.Errorf("failed to vacuum: %w", err)
+	}
+	freeAfter, err := v.pragmaInt(ctx, "freelist_count")
+	if err != nil {
+		return err
+	}
+	run.VacuumedPages = freeBefore - freeAfter
+
+	var busy, frames, checkpointed int64
+	if err := v.db.QueryRowContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)").Scan(&busy, &frames, &checkpointed); err != nil {
+		return fmt.Errorf("failed to checkpoint the write-ahead log: %w", err)
+	}
+	run.CheckpointedFrames = checkpointed
+	return nil
+}
+
+// LastMaintenance returns the latest maintenance run, if any
+func (v *SQLiteVectorDB) LastMaintenance() (MaintenanceRun, bool) {
+	v.maintenance.mu.Lock()
+	defer v.maintenance.mu.Unlock()
+	if v.maintenance.last == nil {
+		return MaintenanceRun{}, false
+	}
+	return *v.maintenance.last, true
+}
+
+// Storage reports the database's size, the row count and size of each of
+// its tables and the latest maintenance run
+func (v *SQLiteVectorDB) Storage(ctx context.Context) (StorageReport, error) {
+	report := StorageReport{Path: v.path, Tables: make([]StorageTable, 0)}
+
+	pageSize, err := v.pragmaInt(ctx, "page_size")
+	if err != nil {
+		return report, err
+	}
+	pages, err := v.pragmaInt(ctx, "page_count")
+	if err != nil {
+		return report, err
+	}
+	free, err := v.pragmaInt(ctx, "freelist_count")
+	if err != nil {
+		return report, err
+	}
+	report.DatabaseBytes, report.FreeBytes = pages*pageSize, free*pageSize
+	if err := v.db.QueryRowContext(ctx, "PRAGMA journal_mode").Scan(&report.JournalMode); err != nil {
+		return report, fmt.Errorf("failed to read journal_mode: %w", err)
+	}
+	mode, err := v.pragmaInt(ctx, "auto_vacuum")
+	if err != nil {
+		return report, err
+	}
+	report.AutoVacuum = [...]string{"none", "full", "incremental"}[mode%3]
+	if info, err := os.Stat(v.path + "-wal"); err == nil {
+		report.WALBytes = info.Size()
+	}
+
+	rows, err := v.db.QueryContext(ctx, `SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name`)
+	if err != nil {
+		return report, fmt.Errorf("failed to list tables: %w", err)
+	}
+	for rows.Next() {
+		var table StorageTable
+		if err := rows.Scan(&table.Name); err != nil {
+			rows.Close()
+			return report, fmt.Errorf("failed to list tables: %w", err)
+		}
+		report.Tables = append(report.Tables, table)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return report, fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	sizes := v.tableSizes(ctx)
+	for i := range report.Tables {
+		table := &report.Tables[i]
+		query := fmt.Sprintf(`SELECT COUNT(*) FROM "%s"`, strings.ReplaceAll(table.Name, `"`, `""`))
+		if err := v.db.QueryRowContext(ctx, query).Scan(&table.Rows); err != nil {
+			return report, fmt.Errorf("failed to count rows of %s: %w", table.Name, err)
+		}
+		if size, ok := sizes[table.Name]; ok {
+			table.Bytes = &size
+		}
+	}
+
+	if run, ok := v.LastMaintenance(); ok {
+		report.LastMaintained = &run
+	}
+	return report, nil
+}
+
+// tableSizes returns the bytes each table and its indexes take, or nil when
+// the SQLite build lacks the dbstat table
+func (v *SQLiteVectorDB) tableSizes(ctx context.Context) map[string]int64 {
+	rows, err := v.db.QueryContext(ctx, `
+		SELECT m.tbl_name, SUM(s.pgsize)
+		FROM dbstat s JOIN sqlite_master m ON m.name = s.name
+		GROUP BY m.tbl_name
+	`)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	sizes := make(map[string]int64)
+	for rows.Next() {
+		var name string
+		var size int64
+		if err := rows.Scan(&name, &size); err != nil {
+			return nil
+		}
+		sizes[name] = size
+	}
+	if rows.Err() != nil {
+		return nil
+	}
+	return sizes
+}
+
+// pragmaInt reads a PRAGMA with a whole number value
+func (v *SQLiteVectorDB) pragmaInt(ctx context.Context, pragma string) (int64, error) {
+	var value int64
+	if err := v.db.QueryRowContext(ctx, "PRAGMA "+pragma).Scan(&value); err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", pragma, err)
+	}
+	return value, nil
+}
@@ -0,0 +1,86 @@
+package vectordb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaintain_VacuumsAndReportsStorage(t *testing.T) {
+	db := tempDB(t)
+	ctx := context.Background()
+	padding := strings.Repeat("x", 4096)
+	for i := 0; i < 50; i++ {
+		if err := db.Store(ctx, TableMemories, fmt.Sprintf("id%d", i), vec(1, 0), map[string]interface{}{"content": padding}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.GetDB().Exec("DELETE FROM memories WHERE id != 'id0'"); err != nil {
+		t.Fatal(err)
+	}
+
+	run, err := db.Maintain(ctx)
+	if err != nil {
+		t.Fatalf("Maintain: %v", err)
+	}
+	if !run.Healthy() || run.Converted || run.VacuumedPages <= 0 || run.CheckpointedFrames < 0 {
+		t.Errorf("run = %+v, want a healthy incremental vacuum and checkpoint", run)
+	}
+
+	report, err := db.Storage(ctx)
+	if err != nil {
+		t.Fatalf("Storage: %v", err)
+	}
+	if report.AutoVacuum != "incremental" || report.JournalMode != "wal" || report.DatabaseBytes == 0 || report.LastMaintained == nil {
+		t.Errorf("report = %+v", report)
+	}
+	rows := make(map[string]int64)
+	for _, table := range report.Tables {
+		rows[table.Name] = table.Rows
+	}
+	if rows[TableMemories] != 1 {
+		t.Errorf("memories rows = %d, want 1", rows[TableMemories])
+	}
+	if _, ok := rows["governance_rules"]; !ok {
+		t.Errorf("tables = %v, want the governance tables too", report.Tables)
+	}
+}
+
+func TestMaintain_ConvertsExistingDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.db")
+	old, err := sql.Open(SQLiteDriver, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := old.Exec("CREATE TABLE notes (id TEXT PRIMARY KEY)"); err != nil {
+		t.Fatal(err)
+	}
+	old.Close()
+
+	db, err := NewSQLiteVectorDBWithConfig(SQLiteConfig{MaintenanceInterval: 10 * time.Millisecond}, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if run, ok := db.LastMaintenance(); ok {
+			if !run.Converted || !run.Healthy() {
+				t.Errorf("first run = %+v, want the database converted", run)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("scheduled maintenance did not run")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if report, err := db.Storage(context.Background()); err != nil || report.AutoVacuum != "incremental" {
+		t.Errorf("auto_vacuum = %q, %v; want incremental", report.AutoVacuum, err)
+	}
+}
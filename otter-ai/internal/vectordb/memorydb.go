@@ -0,0 +1,362 @@
+package vectordb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"otter-ai/internal/tracing"
+)
+
+// memorySnapshotFormat identifies a snapshot file of the memory backend
+const memorySnapshotFormat = "otter-vectors"
+
+// memorySnapshotVersion is the snapshot layout written by this version
+const memorySnapshotVersion = 1
+
+// MemoryConfig configures the in-memory backend
+type MemoryConfig struct {
+	// SnapshotPath, when set, is loaded when the database is opened and
+	// rewritten when it is closed, so an otter can be restarted without
+	// losing its memories. Empty keeps everything in RAM only.
+	SnapshotPath string
+}
+
+// MemoryVectorDB implements VectorDB in RAM, for tests and throwaway otters
+// that shouldn't need SQLite. Records are kept JSON-encoded so they read
+// back exactly as from the SQLite backend. Governance state is not kept.
+type MemoryVectorDB struct {
+	cfg MemoryConfig
+
+	mu     sync.RWMutex
+	tables map[string]map[string]*memoryRecord
+	seq    int64 // Insertion counter, for newest-first listing
+	closed bool
+}
+
+// memoryRecord is a stored record
+type memoryRecord struct {
+	ID       string          `json:"id"`
+	Vector   []float32       `json:"vector"`
+	Metadata json.RawMessage `json:"metadata"`
+	seq      int64
+}
+
+// memorySnapshot is the snapshot file layout. Records are listed oldest
+// first.
+type memorySnapshot struct {
+	Format  string                     `json:"format"`
+	Version int                        `json:"version"`
+	Tables  map[string][]*memoryRecord `json:"tables"`
+}
+
+// NewMemoryVectorDB creates an in-memory vector database, loading the
+// snapshot at cfg.SnapshotPath when one exists
+func NewMemoryVectorDB(cfg MemoryConfig) (*MemoryVectorDB, error) {
+	v := &MemoryVectorDB{cfg: cfg, tables: make(map[string]map[string]*memoryRecord)}
+	if cfg.SnapshotPath == "" {
+		return v, nil
+	}
+
+	data, err := os.ReadFile(cfg.SnapshotPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return v, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	var snapshot memorySnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil || snapshot.Format != memorySnapshotFormat {
+		return nil, fmt.Errorf("%s is not a vector snapshot", cfg.SnapshotPath)
+	}
+	if snapshot.Version < 1 || snapshot.Version > memorySnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d", snapshot.Version)
+	}
+	for table, records := range snapshot.Tables {
+		if err := ValidateTable(table); err != nil {
+			return nil, fmt.Errorf("invalid snapshot: %w", err)
+		}
+		for _, record := range records {
+			v.seq++
+			record.seq = v.seq
+			v.table(table)[record.ID] = record
+		}
+	}
+	return v, nil
+}
+
+// table returns a table's records, creating the table. Callers hold mu.
+func (v *MemoryVectorDB) table(table string) map[string]*memoryRecord {
+	records, ok := v.tables[table]
+	if !ok {
+		records = make(map[string]*memoryRecord)
+		v.tables[table] = records
+	}
+	return records
+}
+
+// sorted returns a table's records, newest first. Callers hold mu.
+func (v *MemoryVectorDB) sorted(table string) []*memoryRecord {
+	records := make([]*memoryRecord, 0, len(v.tables[table]))
+	for _, record := range v.tables[table] {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].seq > records[j].seq })
+	return records
+}
+
+// decode returns a copy of a stored record
+func (r *memoryRecord) decode() Record {
+	metadata := make(map[string]interface{})
+	json.Unmarshal(r.Metadata, &metadata)
+	return Record{ID: r.ID, Vector: append([]float32(nil), r.Vector...), Metadata: metadata}
+}
+
+// Store stores a vector with metadata. Overwriting a record keeps its
+// place in listings.
+func (v *MemoryVectorDB) Store(ctx context.Context, table string, id string, vector []float32, metadata map[string]interface{}) (err error) {
+	_, span := startSpan(ctx, "vectordb.Store", "memory", table)
+	defer func() { tracing.End(span, err) }()
+
+	if err := ValidateTable(table); err != nil {
+		return err
+	}
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.closed {
+		return ErrClosed
+	}
+	records := v.table(table)
+	record := &memoryRecord{ID: id, Vector: append([]float32(nil), vector...), Metadata: metadataJSON}
+	if existing, ok := records[id]; ok {
+		record.seq = existing.seq
+	} else {
+		v.seq++
+		record.seq = v.seq
+	}
+	records[id] = record
+	return nil
+}
+
+// Search scores the records matching filter by cosine similarity
+func (v *MemoryVectorDB) Search(ctx context.Context, table string, queryVector []float32, limit int, filter Filter) (_ []SearchResult, err error) {
+	_, span := startSpan(ctx, "vectordb.Search", "memory", table)
+	defer func() { tracing.End(span, err) }()
+
+	if err := ValidateTable(table); err != nil {
+		return nil, err
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	v.mu.RLock()
+	records := v.sorted(table)
+	v.mu.RUnlock()
+
+	results := make([]SearchResult, 0, len(records))
+	for _, stored := range records {
+		record := stored.decode()
+		if !filter.Matches(record.Metadata) {
+			continue
+		}
+		results = append(results, SearchResult{
+			ID:       record.ID,
+			Score:    CosineSimilarity(queryVector, record.Vector),
+			Metadata: record.Metadata,
+			Vector:   record.Vector,
+		})
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if limit > 0 && limit < len(results) {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// Get retrieves a record by ID
+func (v *MemoryVectorDB) Get(ctx context.Context, table string, id string) (_ *Record, err error) {
+	_, span := startSpan(ctx, "vectordb.Get", "memory", table)
+	defer func() { tracing.End(span, err) }()
+
+	if err := ValidateTable(table); err != nil {
+		return nil, err
+	}
+	v.mu.RLock()
+	stored, ok := v.tables[table][id]
+	v.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("record not found")
+	}
+	record := stored.decode()
+	return &record, nil
+}
+
+// Delete removes a record by ID
+func (v *MemoryVectorDB) Delete(ctx context.Context, table string, id string) (err error) {
+	_, span := startSpan(ctx, "vectordb.Delete", "memory", table)
+	defer func() { tracing.End(span, err) }()
+
+	if err := ValidateTable(table); err != nil {
+		return err
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.closed {
+		return ErrClosed
+	}
+	delete(v.tables[table], id)
+	return nil
+}
+
+// List retrieves records with pagination, newest first
+func (v *MemoryVectorDB) List(ctx context.Context, table string, limit, offset int) ([]Record, error) {
+	return v.ListFiltered(ctx, table, Filter{}, limit, offset)
+}
+
+// ListFiltered lists the records matching filter, newest first
+func (v *MemoryVectorDB) ListFiltered(ctx context.Context, table string, filter Filter, limit, offset int) ([]Record, error) {
+	if err := ValidateTable(table); err != nil {
+		return nil, err
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	v.mu.RLock()
+	stored := v.sorted(table)
+	v.mu.RUnlock()
+
+	var records []Record
+	skipped := 0
+	for _, s := range stored {
+		if limit > 0 && len(records) >= limit {
+			break
+		}
+		record := s.decode()
+		if !filter.Matches(record.Metadata) {
+			continue
+		}
+		if skipped < offset {
+			skipped++
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// Count returns the number of records in a table
+func (v *MemoryVectorDB) Count(ctx context.Context, table string) (int, error) {
+	if err := ValidateTable(table); err != nil {
+		return 0, err
+	}
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return len(v.tables[table]), nil
+}
+
+// Iterate streams a table's records to fn, newest first. Records stored
+// during the iteration may be missed.
+func (v *MemoryVectorDB) Iterate(ctx context.Context, table string, fn func(Record) error) error {
+	if err := ValidateTable(table); err != nil {
+		return err
+	}
+	v.mu.RLock()
+	stored := v.sorted(table)
+	v.mu.RUnlock()
+
+	for _, record := range stored {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(record.decode()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// KeywordSearch ranks records whose content contains any of the query's
+// terms by matched term count
+func (v *MemoryVectorDB) KeywordSearch(ctx context.Context, table string, query string, limit int) (_ []SearchResult, err error) {
+	ctx, span := startSpan(ctx, "vectordb.KeywordSearch", "memory", table)
+	defer func() { tracing.End(span, err) }()
+
+	if err := ValidateTable(table); err != nil {
+		return nil, err
+	}
+	if !keywordTables[table] {
+		return nil, fmt.Errorf("table %s has no keyword index", table)
+	}
+	terms := keywordTerms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	return scanKeywords(ctx, v, table, terms, limit)
+}
+
+// Close writes the snapshot, when configured. Later writes fail with
+// ErrClosed; reads keep working.
+func (v *MemoryVectorDB) Close() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.closed {
+		return nil
+	}
+	v.closed = true
+	if v.cfg.SnapshotPath == "" {
+		return nil
+	}
+	return v.writeSnapshot()
+}
+
+// writeSnapshot replaces the snapshot file with the current records.
+// Callers hold mu.
+func (v *MemoryVectorDB) writeSnapshot() error {
+	snapshot := memorySnapshot{
+		Format:  memorySnapshotFormat,
+		Version: memorySnapshotVersion,
+		Tables:  make(map[string][]*memoryRecord, len(v.tables)),
+	}
+	for table := range v.tables {
+		records := v.sorted(table)
+		for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+			records[i], records[j] = records[j], records[i]
+		}
+		snapshot.Tables[table] = records
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	// Write beside the snapshot and rename, so a crash mid-write keeps the
+	// previous one
+	tmp, err := os.CreateTemp(filepath.Dir(v.cfg.SnapshotPath), filepath.Base(v.cfg.SnapshotPath)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), v.cfg.SnapshotPath); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
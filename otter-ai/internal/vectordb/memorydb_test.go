@@ -0,0 +1,96 @@
+package vectordb
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMemoryVectorDB_StoreSearchList(t *testing.T) {
+	db, err := New(BackendMemory, "", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	db.Store(ctx, TableMemories, "kelp", vec(1, 0), map[string]interface{}{"content": "kelp forest", "scope": "a"})
+	db.Store(ctx, TableMemories, "rock", vec(0.7, 0.7), map[string]interface{}{"content": "rocks", "scope": "b"})
+	db.Store(ctx, TableMemories, "tide", vec(0, 1), map[string]interface{}{"content": "tides", "scope": "a"})
+
+	results, err := db.Search(ctx, TableMemories, vec(1, 0), 2, Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].ID != "kelp" || results[1].ID != "rock" {
+		t.Errorf("Search = %+v", results)
+	}
+	results, _ = db.Search(ctx, TableMemories, vec(1, 0), 5, Filter{Equals: map[string]interface{}{"scope": "a"}})
+	if len(results) != 2 || results[1].ID != "tide" {
+		t.Errorf("filtered Search = %+v", results)
+	}
+
+	// Newest first; overwriting keeps a record's place
+	db.Store(ctx, TableMemories, "kelp", vec(1, 0), map[string]interface{}{"content": "giant kelp", "scope": "a"})
+	records, _ := db.List(ctx, TableMemories, 2, 1)
+	if len(records) != 2 || records[0].ID != "rock" || records[1].ID != "kelp" {
+		t.Errorf("List = %+v", records)
+	}
+	if record, err := db.Get(ctx, TableMemories, "kelp"); err != nil || record.Metadata["content"] != "giant kelp" {
+		t.Errorf("Get = %+v, %v", record, err)
+	}
+
+	// Metadata reads back as from SQLite: numbers are float64
+	db.Store(ctx, TableSessions, "s1", vec(1), map[string]interface{}{"turns": 3})
+	if record, _ := db.Get(ctx, TableSessions, "s1"); record.Metadata["turns"] != float64(3) {
+		t.Errorf("turns = %#v, want float64", record.Metadata["turns"])
+	}
+
+	if err := db.Delete(ctx, TableMemories, "rock"); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := Count(ctx, db, TableMemories); n != 2 {
+		t.Errorf("Count = %d, want 2", n)
+	}
+	if hits, _ := db.(KeywordSearcher).KeywordSearch(ctx, TableMemories, "kelp tides", 5); len(hits) != 2 {
+		t.Errorf("KeywordSearch = %+v", hits)
+	}
+	if err := db.Store(ctx, "secrets", "x", vec(1), nil); err == nil {
+		t.Error("expected error for an unauthorized table")
+	}
+}
+
+func TestMemoryVectorDB_Snapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vectors.json")
+	ctx := context.Background()
+
+	db, err := NewMemoryVectorDB(MemoryConfig{SnapshotPath: path})
+	if err != nil {
+		t.Fatalf("opening without a snapshot: %v", err)
+	}
+	db.Store(ctx, TableMemories, "old", vec(1, 0), map[string]interface{}{"content": "old"})
+	db.Store(ctx, TableMemories, "new", vec(0, 1), map[string]interface{}{"content": "new"})
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := db.Store(ctx, TableMemories, "late", vec(1), nil); !errors.Is(err, ErrClosed) {
+		t.Errorf("Store after Close = %v, want ErrClosed", err)
+	}
+
+	db, err = NewMemoryVectorDB(MemoryConfig{SnapshotPath: path})
+	if err != nil {
+		t.Fatalf("loading snapshot: %v", err)
+	}
+	defer db.Close()
+	records, _ := db.List(ctx, TableMemories, 10, 0)
+	if len(records) != 2 || records[0].ID != "new" || records[1].Vector[0] != 1 {
+		t.Errorf("restored = %+v", records)
+	}
+
+	os.WriteFile(path, []byte(`{"format":"something-else"}`), 0600)
+	if _, err := NewMemoryVectorDB(MemoryConfig{SnapshotPath: path}); err == nil {
+		t.Error("expected error for a file that isn't a snapshot")
+	}
+}
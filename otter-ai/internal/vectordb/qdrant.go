@@ -0,0 +1,459 @@
+package vectordb
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"otter-ai/internal/tracing"
+)
+
+// Qdrant configuration
+const (
+	QdrantTimeout         = 30 * time.Second
+	QdrantMaxResponseSize = 256 << 20
+	QdrantScrollPageSize  = 256 // Points fetched per scroll request
+	// DefaultQdrantCollectionPrefix namespaces the otter's collections on a
+	// shared server
+	DefaultQdrantCollectionPrefix = "otter_"
+)
+
+// QdrantConfig locates a Qdrant server
+type QdrantConfig struct {
+	URL              string // REST address, e.g. http://qdrant:6333
+	APIKey           string // Sent as api-key
+	CollectionPrefix string // Prepended to table names; empty uses DefaultQdrantCollectionPrefix
+}
+
+// errQdrantNotFound is returned when a collection or point doesn't exist
+var errQdrantNotFound = errors.New("qdrant collection not found")
+
+// QdrantVectorDB stores each embedding table in a Qdrant collection, with
+// the record's metadata as the point payload. Qdrant point IDs must be
+// UUIDs or integers, so points are keyed by a UUID derived from the record
+// ID, which is kept in the payload. Governance state and tables without
+// embeddings stay in a local SQLite database, which is also what GetDB
+// returns.
+type QdrantVectorDB struct {
+	cfg    QdrantConfig
+	client *http.Client
+	local  *SQLiteVectorDB
+}
+
+// qdrantPayload is the payload stored with each point
+type qdrantPayload struct {
+	ID       string                 `json:"otter_id"`
+	Metadata map[string]interface{} `json:"metadata"`
+}
+
+// qdrantPoint is a point as returned by the points API
+type qdrantPoint struct {
+	Score   float64       `json:"score"`
+	Vector  []float32     `json:"vector"`
+	Payload qdrantPayload `json:"payload"`
+}
+
+// NewQdrantVectorDB connects to a Qdrant server and opens the local SQLite
+// database at dbPath
+func NewQdrantVectorDB(cfg QdrantConfig, dbPath string, local SQLiteConfig) (*QdrantVectorDB, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("qdrant URL is required")
+	}
+	if _, err := url.ParseRequestURI(cfg.URL); err != nil {
+		return nil, fmt.Errorf("invalid qdrant URL: %w", err)
+	}
+	cfg.URL = strings.TrimRight(cfg.URL, "/")
+	if cfg.CollectionPrefix == "" {
+		cfg.CollectionPrefix = DefaultQdrantCollectionPrefix
+	}
+
+	localDB, err := NewSQLiteVectorDBWithConfig(local, dbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QdrantVectorDB{
+		cfg:    cfg,
+		client: &http.Client{Timeout: QdrantTimeout},
+		local:  localDB,
+	}, nil
+}
+
+// Store upserts a vector with metadata, creating the table's collection
+// with the vector's dimension on first use
+func (v *QdrantVectorDB) Store(ctx context.Context, table string, id string, vector []float32, metadata map[string]interface{}) (err error) {
+	ctx, span := startSpan(ctx, "vectordb.Store", "qdrant", table)
+	defer func() { tracing.End(span, err) }()
+
+	if err := ValidateTable(table); err != nil {
+		return err
+	}
+	if !remoteTable(table) {
+		return v.local.Store(ctx, table, id, vector, metadata)
+	}
+	if len(vector) == 0 {
+		return fmt.Errorf("qdrant collection %s requires an embedding", table)
+	}
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+
+	points := map[string]interface{}{
+		"points": []map[string]interface{}{{
+			"id":      qdrantPointID(id),
+			"vector":  vector,
+			"payload": qdrantPayload{ID: id, Metadata: metadata},
+		}},
+	}
+	err = v.do(ctx, http.MethodPut, table, "/points?wait=true", points, nil)
+	if errors.Is(err, errQdrantNotFound) {
+		// First record of the table: create its collection and retry
+		if err = v.createCollection(ctx, table, len(vector)); err == nil {
+			err = v.do(ctx, http.MethodPut, table, "/points?wait=true", points, nil)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store vector: %w", err)
+	}
+	return nil
+}
+
+// Search searches for similar vectors, with the filter applied by Qdrant
+func (v *QdrantVectorDB) Search(ctx context.Context, table string, queryVector []float32, limit int, filter Filter) (_ []SearchResult, err error) {
+	ctx, span := startSpan(ctx, "vectordb.Search", "qdrant", table)
+	defer func() { tracing.End(span, err) }()
+
+	if err := ValidateTable(table); err != nil {
+		return nil, err
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	if !remoteTable(table) {
+		return v.local.Search(ctx, table, queryVector, limit, filter)
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+
+	request := map[string]interface{}{
+		"vector":       queryVector,
+		"limit":        limit,
+		"with_payload": true,
+		"with_vector":  true,
+	}
+	if !filter.IsZero() {
+		request["filter"] = qdrantFilter(filter)
+	}
+	var points []qdrantPoint
+	err = v.do(ctx, http.MethodPost, table, "/points/search", request, &points)
+	if errors.Is(err, errQdrantNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to search vectors: %w", err)
+	}
+
+	results := make([]SearchResult, 0, len(points))
+	for _, point := range points {
+		results = append(results, SearchResult{
+			ID:       point.Payload.ID,
+			Score:    point.Score, // The collections use cosine similarity
+			Metadata: point.Payload.Metadata,
+			Vector:   point.Vector,
+		})
+	}
+	return results, nil
+}
+
+// Get retrieves a record by ID
+func (v *QdrantVectorDB) Get(ctx context.Context, table string, id string) (_ *Record, err error) {
+	ctx, span := startSpan(ctx, "vectordb.Get", "qdrant", table)
+	defer func() { tracing.End(span, err) }()
+
+	if err := ValidateTable(table); err != nil {
+		return nil, err
+	}
+	if !remoteTable(table) {
+		return v.local.Get(ctx, table, id)
+	}
+
+	var point qdrantPoint
+	err = v.do(ctx, http.MethodGet, table, "/points/"+qdrantPointID(id), nil, &point)
+	if errors.Is(err, errQdrantNotFound) {
+		return nil, fmt.Errorf("record not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get record: %w", err)
+	}
+	return &Record{ID: point.Payload.ID, Vector: point.Vector, Metadata: point.Payload.Metadata}, nil
+}
+
+// Delete removes a record by ID
+func (v *QdrantVectorDB) Delete(ctx context.Context, table string, id string) (err error) {
+	ctx, span := startSpan(ctx, "vectordb.Delete", "qdrant", table)
+	defer func() { tracing.End(span, err) }()
+
+	if err := ValidateTable(table); err != nil {
+		return err
+	}
+	if !remoteTable(table) {
+		return v.local.Delete(ctx, table, id)
+	}
+
+	request := map[string]interface{}{"points": []string{qdrantPointID(id)}}
+	if err := v.do(ctx, http.MethodPost, table, "/points/delete?wait=true", request, nil); err != nil && !errors.Is(err, errQdrantNotFound) {
+		return fmt.Errorf("failed to delete record: %w", err)
+	}
+	return nil
+}
+
+// List retrieves records with pagination. Qdrant scrolls points in point
+// ID order, so unlike SQLite records aren't listed newest first, and the
+// offset is skipped by scrolling past it.
+func (v *QdrantVectorDB) List(ctx context.Context, table string, limit, offset int) ([]Record, error) {
+	return v.ListFiltered(ctx, table, Filter{}, limit, offset)
+}
+
+// ListFiltered lists the records matching filter in List order, with the
+// filter applied by Qdrant
+func (v *QdrantVectorDB) ListFiltered(ctx context.Context, table string, filter Filter, limit, offset int) (_ []Record, err error) {
+	ctx, span := startSpan(ctx, "vectordb.List", "qdrant", table)
+	defer func() { tracing.End(span, err) }()
+
+	if err := ValidateTable(table); err != nil {
+		return nil, err
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	if !remoteTable(table) {
+		return v.local.ListFiltered(ctx, table, filter, limit, offset)
+	}
+
+	var records []Record
+	errDone := errors.New("done")
+	err = v.scroll(ctx, table, filter, func(record Record) error {
+		if offset > 0 {
+			offset--
+			return nil
+		}
+		records = append(records, record)
+		if limit > 0 && len(records) >= limit {
+			return errDone
+		}
+		return nil
+	})
+	if err != nil && err != errDone {
+		return nil, fmt.Errorf("failed to list records: %w", err)
+	}
+	return records, nil
+}
+
+// Iterate streams a table's records to fn in List order, a page at a time
+func (v *QdrantVectorDB) Iterate(ctx context.Context, table string, fn func(Record) error) error {
+	if err := ValidateTable(table); err != nil {
+		return err
+	}
+	if !remoteTable(table) {
+		return v.local.Iterate(ctx, table, fn)
+	}
+	return v.scroll(ctx, table, Filter{}, fn)
+}
+
+// Count returns the number of records in a table
+func (v *QdrantVectorDB) Count(ctx context.Context, table string) (int, error) {
+	if err := ValidateTable(table); err != nil {
+		return 0, err
+	}
+	if !remoteTable(table) {
+		return v.local.Count(ctx, table)
+	}
+
+	var result struct {
+		Count int `json:"count"`
+	}
+	err := v.do(ctx, http.MethodPost, table, "/points/count", map[string]interface{}{"exact": true}, &result)
+	if errors.Is(err, errQdrantNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to count records: %w", err)
+	}
+	return result.Count, nil
+}
+
+// Close closes the local database
+func (v *QdrantVectorDB) Close() error {
+	v.client.CloseIdleConnections()
+	return v.local.Close()
+}
+
+// GetDB returns the local database used for governance persistence
+func (v *QdrantVectorDB) GetDB() *sql.DB {
+	return v.local.GetDB()
+}
+
+// Maintain maintains the local database
+func (v *QdrantVectorDB) Maintain(ctx context.Context) (MaintenanceRun, error) {
+	return v.local.Maintain(ctx)
+}
+
+// Storage reports on the local database
+func (v *QdrantVectorDB) Storage(ctx context.Context) (StorageReport, error) {
+	return v.local.Storage(ctx)
+}
+
+// createCollection creates a table's collection for vectors of the given
+// dimension, compared by cosine similarity. A collection created
+// concurrently by another store is left as it is.
+func (v *QdrantVectorDB) createCollection(ctx context.Context, table string, dimension int) error {
+	request := map[string]interface{}{
+		"vectors": map[string]interface{}{"size": dimension, "distance": "Cosine"},
+	}
+	err := v.do(ctx, http.MethodPut, table, "", request, nil)
+	if err != nil && strings.Contains(err.Error(), "already exists") {
+		return nil
+	}
+	return err
+}
+
+// scroll streams the points of a collection matching filter to fn
+func (v *QdrantVectorDB) scroll(ctx context.Context, table string, filter Filter, fn func(Record) error) error {
+	request := map[string]interface{}{
+		"limit":        QdrantScrollPageSize,
+		"with_payload": true,
+		"with_vector":  true,
+	}
+	if !filter.IsZero() {
+		request["filter"] = qdrantFilter(filter)
+	}
+	for {
+		var page struct {
+			Points []qdrantPoint `json:"points"`
+			Next   interface{}   `json:"next_page_offset"`
+		}
+		err := v.do(ctx, http.MethodPost, table, "/points/scroll", request, &page)
+		if errors.Is(err, errQdrantNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, point := range page.Points {
+			if err := fn(Record{ID: point.Payload.ID, Vector: point.Vector, Metadata: point.Payload.Metadata}); err != nil {
+				return err
+			}
+		}
+		if page.Next == nil {
+			return nil
+		}
+		request["offset"] = page.Next
+	}
+}
+
+// do calls a collection endpoint and decodes the response's result into
+// result, when given
+func (v *QdrantVectorDB) do(ctx context.Context, method, table, path string, request, result interface{}) error {
+	endpoint := fmt.Sprintf("%s/collections/%s%s", v.cfg.URL, url.PathEscape(v.cfg.CollectionPrefix+table), path)
+
+	var body io.Reader
+	if request != nil {
+		data, err := json.Marshal(request)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("failed creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if v.cfg.APIKey != "" {
+		req.Header.Set("api-key", v.cfg.APIKey)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("qdrant request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, QdrantMaxResponseSize))
+	if err != nil {
+		return fmt.Errorf("failed reading qdrant response: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", errQdrantNotFound, table)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var failure struct {
+			Status struct {
+				Error string `json:"error"`
+			} `json:"status"`
+		}
+		message := strings.TrimSpace(string(respBody))
+		if json.Unmarshal(respBody, &failure) == nil && failure.Status.Error != "" {
+			message = failure.Status.Error
+		}
+		return fmt.Errorf("qdrant returned status %d: %s", resp.StatusCode, message)
+	}
+	if result == nil {
+		return nil
+	}
+
+	envelope := struct {
+		Result interface{} `json:"result"`
+	}{result}
+	if err := json.Unmarshal(respBody, &envelope); err != nil {
+		return fmt.Errorf("failed to decode qdrant response: %w", err)
+	}
+	return nil
+}
+
+// qdrantPointID derives the UUID a record is stored under from its ID, the
+// way a name-based (version 5) UUID is
+func qdrantPointID(id string) string {
+	sum := sha1.Sum([]byte(id))
+	sum[6] = sum[6]&0x0f | 0x50
+	sum[8] = sum[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// qdrantFilter renders a filter as Qdrant conditions on the payload's
+// metadata. Numbers are matched as ranges, since Qdrant only matches
+// integers exactly and metadata numbers may be fractional.
+func qdrantFilter(f Filter) map[string]interface{} {
+	var must []map[string]interface{}
+	for key, value := range f.Equals {
+		normalized, _ := filterValue(value)
+		if n, ok := normalized.(float64); ok {
+			must = append(must, map[string]interface{}{
+				"key":   "metadata." + key,
+				"range": map[string]float64{"gte": n, "lte": n},
+			})
+			continue
+		}
+		must = append(must, map[string]interface{}{
+			"key":   "metadata." + key,
+			"match": map[string]interface{}{"value": normalized},
+		})
+	}
+	for key, min := range f.Min {
+		must = append(must, map[string]interface{}{"key": "metadata." + key, "range": map[string]float64{"gte": min}})
+	}
+	for key, max := range f.Max {
+		must = append(must, map[string]interface{}{"key": "metadata." + key, "range": map[string]float64{"lte": max}})
+	}
+	return map[string]interface{}{"must": must}
+}
@@ -0,0 +1,331 @@
+package vectordb
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeQdrantPageSize is how many points the fake returns per scroll, so
+// tests page through collections
+const fakeQdrantPageSize = 2
+
+// fakeQdrant is an in-memory stand-in for the Qdrant REST API
+type fakeQdrant struct {
+	mu          sync.Mutex
+	collections map[string]map[string]fakeQdrantPoint
+	apiKey      string
+}
+
+type fakeQdrantPoint struct {
+	ID      string                 `json:"id"`
+	Vector  []float32              `json:"vector"`
+	Payload map[string]interface{} `json:"payload"`
+	Score   float64                `json:"score,omitempty"`
+}
+
+type fakeQdrantCondition struct {
+	Key   string
+	Match *struct{ Value interface{} }
+	Range *struct{ Gte, Lte *float64 }
+}
+
+func newFakeQdrant(t *testing.T) (*fakeQdrant, *httptest.Server) {
+	fake := &fakeQdrant{collections: make(map[string]map[string]fakeQdrantPoint)}
+	srv := httptest.NewServer(http.HandlerFunc(fake.serve))
+	t.Cleanup(srv.Close)
+	return fake, srv
+}
+
+func (f *fakeQdrant) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.apiKey = r.Header.Get("api-key")
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/") // collections <name> [points [action]]
+	name := parts[1]
+	var req struct {
+		Points []fakeQdrantPoint
+		Vector []float32
+		Limit  int
+		Offset *string
+		Filter *struct{ Must []fakeQdrantCondition }
+	}
+	body, _ := io.ReadAll(r.Body)
+	json.Unmarshal(body, &req)
+
+	points, exists := f.collections[name]
+	if len(parts) == 2 && r.Method == http.MethodPut {
+		if exists {
+			http.Error(w, `{"status":{"error":"Collection already exists"}}`, http.StatusConflict)
+			return
+		}
+		f.collections[name] = make(map[string]fakeQdrantPoint)
+		f.reply(w, true)
+		return
+	}
+	if !exists {
+		http.Error(w, `{"status":{"error":"Not found: Collection doesn't exist!"}}`, http.StatusNotFound)
+		return
+	}
+
+	action := ""
+	if len(parts) > 3 {
+		action = parts[3]
+	}
+	switch {
+	case r.Method == http.MethodPut: // Upsert
+		for _, point := range req.Points {
+			points[point.ID] = point
+		}
+		f.reply(w, map[string]string{"status": "completed"})
+
+	case r.Method == http.MethodGet:
+		point, ok := points[action]
+		if !ok {
+			http.Error(w, `{"status":{"error":"No point found"}}`, http.StatusNotFound)
+			return
+		}
+		f.reply(w, point)
+
+	case action == "delete":
+		var del struct{ Points []string }
+		json.Unmarshal(body, &del)
+		for _, id := range del.Points {
+			delete(points, id)
+		}
+		f.reply(w, map[string]string{"status": "completed"})
+
+	case action == "count":
+		f.reply(w, map[string]int{"count": len(points)})
+
+	case action == "search":
+		var results []fakeQdrantPoint
+		for _, point := range points {
+			if req.Filter != nil && !fakeQdrantMatches(point, req.Filter.Must) {
+				continue
+			}
+			point.Score = CosineSimilarity(req.Vector, point.Vector)
+			results = append(results, point)
+		}
+		sort.Slice(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+		if req.Limit < len(results) {
+			results = results[:req.Limit]
+		}
+		f.reply(w, results)
+
+	case action == "scroll":
+		var results []fakeQdrantPoint
+		for _, point := range points {
+			if req.Filter != nil && !fakeQdrantMatches(point, req.Filter.Must) {
+				continue
+			}
+			if req.Offset != nil && point.ID < *req.Offset {
+				continue
+			}
+			results = append(results, point)
+		}
+		sort.Slice(results, func(i, j int) bool { return results[i].ID < results[j].ID })
+		var next interface{}
+		if len(results) > fakeQdrantPageSize {
+			next = results[fakeQdrantPageSize].ID
+			results = results[:fakeQdrantPageSize]
+		}
+		f.reply(w, map[string]interface{}{"points": results, "next_page_offset": next})
+	}
+}
+
+func (f *fakeQdrant) reply(w http.ResponseWriter, result interface{}) {
+	json.NewEncoder(w).Encode(map[string]interface{}{"result": result, "status": "ok"})
+}
+
+func fakeQdrantMatches(point fakeQdrantPoint, must []fakeQdrantCondition) bool {
+	metadata, _ := point.Payload["metadata"].(map[string]interface{})
+	for _, condition := range must {
+		value := metadata[strings.TrimPrefix(condition.Key, "metadata.")]
+		if condition.Match != nil && value != condition.Match.Value {
+			return false
+		}
+		if condition.Range != nil {
+			n, ok := value.(float64)
+			if !ok || (condition.Range.Gte != nil && n < *condition.Range.Gte) || (condition.Range.Lte != nil && n > *condition.Range.Lte) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// has reports whether a collection was created
+func (f *fakeQdrant) has(name string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.collections[name]
+	return ok
+}
+
+func newTestQdrant(t *testing.T) (*QdrantVectorDB, *fakeQdrant) {
+	t.Helper()
+	fake, srv := newFakeQdrant(t)
+	db, err := NewQdrantVectorDB(QdrantConfig{URL: srv.URL + "/", APIKey: "key"}, filepath.Join(t.TempDir(), "otter.db"), SQLiteConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, fake
+}
+
+func TestQdrant_StoreGetSearch(t *testing.T) {
+	db, fake := newTestQdrant(t)
+	ctx := context.Background()
+
+	if err := db.Store(ctx, TableMemories, "a", []float32{1, 0}, map[string]interface{}{"content": "a", "importance": 0.9}); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	if err := db.Store(ctx, TableMemories, "b", []float32{0, 1}, map[string]interface{}{"content": "b", "importance": 0.2}); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	if !fake.has("otter_memories") {
+		t.Error("otter_memories collection not created")
+	}
+	fake.mu.Lock()
+	if fake.apiKey != "key" {
+		t.Errorf("api-key = %q", fake.apiKey)
+	}
+	fake.mu.Unlock()
+
+	record, err := db.Get(ctx, TableMemories, "a")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if record.ID != "a" || record.Metadata["content"] != "a" || len(record.Vector) != 2 {
+		t.Errorf("Get = %+v", record)
+	}
+	if _, err := db.Get(ctx, TableMemories, "missing"); err == nil {
+		t.Error("expected error for a missing record")
+	}
+
+	results, err := db.Search(ctx, TableMemories, []float32{0.9, 0.1}, 2, Filter{})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 2 || results[0].ID != "a" || results[0].Score <= results[1].Score {
+		t.Errorf("Search = %+v", results)
+	}
+
+	results, err = db.Search(ctx, TableMemories, []float32{0.9, 0.1}, 2, Filter{Max: map[string]float64{"importance": 0.5}})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "b" {
+		t.Errorf("filtered Search = %+v", results)
+	}
+
+	results, err = db.Search(ctx, TableMemories, []float32{0.9, 0.1}, 2, Filter{Equals: map[string]interface{}{"content": "b"}})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "b" {
+		t.Errorf("Search by equality = %+v", results)
+	}
+}
+
+func TestQdrant_ListScrollsPages(t *testing.T) {
+	db, _ := newTestQdrant(t)
+	ctx := context.Background()
+
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		if err := db.Store(ctx, TableMusings, id, []float32{1, 0}, map[string]interface{}{"content": id}); err != nil {
+			t.Fatalf("Store: %v", err)
+		}
+	}
+
+	all, err := db.List(ctx, TableMusings, 0, 0)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(all) != 5 {
+		t.Fatalf("List = %d records, want 5", len(all))
+	}
+	page, err := db.List(ctx, TableMusings, 2, 3)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(page) != 2 || page[0].ID != all[3].ID || page[1].ID != all[4].ID {
+		t.Errorf("List(2, 3) = %+v, want the last two of %+v", page, all)
+	}
+
+	count, err := db.Count(ctx, TableMusings)
+	if err != nil || count != 5 {
+		t.Errorf("Count = %d, %v", count, err)
+	}
+
+	if err := db.Delete(ctx, TableMusings, "c"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if count, _ := db.Count(ctx, TableMusings); count != 4 {
+		t.Errorf("Count after delete = %d", count)
+	}
+}
+
+func TestQdrant_MissingCollection(t *testing.T) {
+	db, _ := newTestQdrant(t)
+	ctx := context.Background()
+
+	results, err := db.Search(ctx, TableMemories, []float32{1, 0}, 5, Filter{})
+	if err != nil || len(results) != 0 {
+		t.Errorf("Search = %v, %v", results, err)
+	}
+	records, err := db.List(ctx, TableMemories, 10, 0)
+	if err != nil || len(records) != 0 {
+		t.Errorf("List = %v, %v", records, err)
+	}
+	if count, err := db.Count(ctx, TableMemories); err != nil || count != 0 {
+		t.Errorf("Count = %d, %v", count, err)
+	}
+	if err := db.Delete(ctx, TableMemories, "x"); err != nil {
+		t.Errorf("Delete: %v", err)
+	}
+}
+
+func TestQdrant_SessionsStayLocal(t *testing.T) {
+	db, fake := newTestQdrant(t)
+	ctx := context.Background()
+
+	if err := db.Store(ctx, TableSessions, "s", nil, map[string]interface{}{"user": "u"}); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	if fake.has("otter_sessions") {
+		t.Error("sessions reached qdrant")
+	}
+	if _, err := db.Get(ctx, TableSessions, "s"); err != nil {
+		t.Errorf("Get: %v", err)
+	}
+	if db.GetDB() == nil {
+		t.Error("GetDB returned nil")
+	}
+}
+
+func TestQdrantPointID(t *testing.T) {
+	id := qdrantPointID("memory-1")
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(id) {
+		t.Errorf("qdrantPointID = %q, want a version 5 UUID", id)
+	}
+	if qdrantPointID("memory-1") != id || qdrantPointID("memory-2") == id {
+		t.Error("point IDs must be stable and distinct")
+	}
+}
+
+func TestNewQdrantVectorDB_RequiresURL(t *testing.T) {
+	if _, err := NewQdrantVectorDB(QdrantConfig{}, filepath.Join(t.TempDir(), "otter.db"), SQLiteConfig{}); err == nil {
+		t.Error("expected error without URL")
+	}
+}
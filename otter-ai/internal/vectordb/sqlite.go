@@ -6,23 +6,85 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"strings"
+	"sync"
+	"time"
 
-	_ "github.com/mattn/go-sqlite3"
+	"otter-ai/internal/tracing"
 )
 
+// SQLite defaults
+const (
+	DefaultSQLiteBusyTimeout = 5 * time.Second
+	DefaultSQLiteBatchSize   = 100
+)
+
+// SQLiteConfig tunes the SQLite database. The zero value uses the defaults.
+type SQLiteConfig struct {
+	// BusyTimeout is how long a statement waits on a database locked by
+	// another connection; zero uses DefaultSQLiteBusyTimeout
+	BusyTimeout time.Duration
+	// DisableWAL keeps the rollback journal instead of write-ahead logging,
+	// for filesystems without shared memory such as some network mounts
+	DisableWAL bool
+	// BatchWindow is how long a write waits for others to share its
+	// transaction; zero only groups writes that are already queued
+	BatchWindow time.Duration
+	// BatchSize is the most writes committed in one transaction; zero uses
+	// DefaultSQLiteBatchSize and one commits each write alone
+	BatchSize int
+	// MaintenanceInterval is how often Maintain runs; zero never schedules it
+	MaintenanceInterval time.Duration
+}
+
 // SQLiteVectorDB implements VectorDB using SQLite with vector extensions
 type SQLiteVectorDB struct {
-	db *sql.DB
+	db   *sql.DB
+	path string // Database file, for the size of its write-ahead log
+	fts  bool   // FTS5 indexes keywordTables
+
+	stmtMu sync.Mutex
+	stmts  map[string]*sql.Stmt // Prepared statements by query
+	writer *batchWriter         // Groups concurrent Stores into shared transactions; nil commits each alone
+
+	maintenance maintainer
 }
 
-// NewSQLiteVectorDB creates a new SQLite-based vector database
+// NewSQLiteVectorDB creates a new SQLite-based vector database with the
+// default configuration
 func NewSQLiteVectorDB(dbPath string) (*SQLiteVectorDB, error) {
-	db, err := sql.Open("sqlite3", dbPath)
+	return NewSQLiteVectorDBWithConfig(SQLiteConfig{}, dbPath)
+}
+
+// NewSQLiteVectorDBWithConfig creates a new SQLite-based vector database
+func NewSQLiteVectorDBWithConfig(cfg SQLiteConfig, dbPath string) (*SQLiteVectorDB, error) {
+	busyTimeout := cfg.BusyTimeout
+	if busyTimeout <= 0 {
+		busyTimeout = DefaultSQLiteBusyTimeout
+	}
+	db, err := sql.Open(SQLiteDriver, sqliteDSN(dbPath, busyTimeout))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	vdb := &SQLiteVectorDB{db: db}
+	// New databases return freed pages to the filesystem on each maintenance
+	// run; existing ones are converted by their first
+	if _, err := db.Exec("PRAGMA auto_vacuum=INCREMENTAL"); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to set auto_vacuum: %w", err)
+	}
+
+	// Readers don't block the writer, nor it them, with write-ahead logging
+	journalMode := "WAL"
+	if cfg.DisableWAL {
+		journalMode = "DELETE"
+	}
+	if _, err := db.Exec("PRAGMA journal_mode=" + journalMode); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to set journal mode: %w", err)
+	}
+
+	vdb := &SQLiteVectorDB{db: db, path: sqliteFilePath(dbPath), stmts: make(map[string]*sql.Stmt)}
 
 	// Initialize tables
 	if err := vdb.initTables(); err != nil {
@@ -30,12 +92,30 @@ func NewSQLiteVectorDB(dbPath string) (*SQLiteVectorDB, error) {
 		return nil, fmt.Errorf("failed to initialize tables: %w", err)
 	}
 
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultSQLiteBatchSize
+	}
+	if batchSize > 1 {
+		vdb.writer = newBatchWriter(vdb, cfg.BatchWindow, batchSize)
+	}
+	if cfg.MaintenanceInterval > 0 {
+		vdb.startMaintenance(cfg.MaintenanceInterval)
+	}
+
 	return vdb, nil
 }
 
+// sqliteFilePath returns the file of a database path, which may be a URI
+// with parameters
+func sqliteFilePath(dbPath string) string {
+	path, _, _ := strings.Cut(strings.TrimPrefix(dbPath, "file:"), "?")
+	return path
+}
+
 // initTables creates the necessary tables
 func (v *SQLiteVectorDB) initTables() error {
-	tables := []string{TableMemories, TableMusings, TablePersonality}
+	tables := []string{TableMemories, TableMusings, TablePersonality, TableSessions, TableArchive, TableOnboarding, TableDelegations, TableSchedule}
 
 	for _, table := range tables {
 		query := fmt.Sprintf(`
@@ -44,7 +124,9 @@ func (v *SQLiteVectorDB) initTables() error {
 				vector TEXT NOT NULL,
 				metadata TEXT,
 				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-				updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+				updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				version INTEGER NOT NULL DEFAULT 1,
+				deleted_at DATETIME
 			)
 		`, table)
 
@@ -52,6 +134,14 @@ func (v *SQLiteVectorDB) initTables() error {
 			return fmt.Errorf("failed to create table %s: %w", table, err)
 		}
 
+		// Columns added after the original schema
+		if err := v.addColumnIfMissing(table, "version", "INTEGER NOT NULL DEFAULT 1"); err != nil {
+			return err
+		}
+		if err := v.addColumnIfMissing(table, "deleted_at", "DATETIME"); err != nil {
+			return err
+		}
+
 		// Create index on created_at
 		indexQuery := fmt.Sprintf(`
 			CREATE INDEX IF NOT EXISTS idx_%s_created_at ON %s(created_at)
@@ -62,11 +152,40 @@ func (v *SQLiteVectorDB) initTables() error {
 		}
 	}
 
+	if err := v.initVersions(); err != nil {
+		return err
+	}
+	if err := v.initEmbeddingRegistry(); err != nil {
+		return err
+	}
+	if err := v.initFTS(); err != nil {
+		return err
+	}
+
 	// Create governance tables
 	if err := v.initGovernanceTables(); err != nil {
 		return err
 	}
 
+	return v.initUsageTable()
+}
+
+// initUsageTable creates the table of LLM token totals, one row per UTC day,
+// provider and purpose
+func (v *SQLiteVectorDB) initUsageTable() error {
+	_, err := v.db.Exec(`
+		CREATE TABLE IF NOT EXISTS llm_usage (
+			day TEXT NOT NULL,
+			provider TEXT NOT NULL,
+			purpose TEXT NOT NULL,
+			requests INTEGER NOT NULL DEFAULT 0,
+			tokens INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (day, provider, purpose)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create llm_usage table: %w", err)
+	}
 	return nil
 }
 
@@ -93,9 +212,12 @@ func (v *SQLiteVectorDB) initGovernanceTables() error {
 			joined_at INTEGER NOT NULL,
 			last_seen_at INTEGER NOT NULL,
 			public_key BLOB,
+			signing_key BLOB,
 			signature BLOB,
 			inducted_by TEXT NOT NULL,
 			expires_at INTEGER,
+			endpoint TEXT,
+			capabilities TEXT,
 			PRIMARY KEY (raft_id, member_id),
 			FOREIGN KEY (raft_id) REFERENCES governance_rafts(raft_id)
 		)
@@ -115,8 +237,12 @@ func (v *SQLiteVectorDB) initGovernanceTables() error {
 			body TEXT NOT NULL,
 			base_rule_id TEXT,
 			signature BLOB,
+			signed_by TEXT,
+			signer_key BLOB,
 			proposed_by TEXT NOT NULL,
 			adopted_at INTEGER,
+			proposal_id TEXT,
+			ballots TEXT,
 			FOREIGN KEY (raft_id) REFERENCES governance_rafts(raft_id)
 		)
 	`)
@@ -124,11 +250,120 @@ func (v *SQLiteVectorDB) initGovernanceTables() error {
 		return fmt.Errorf("failed to create governance_rules table: %w", err)
 	}
 
+	// Queue of governance tasks awaiting LLM replay
+	_, err = v.db.Exec(`
+		CREATE TABLE IF NOT EXISTS governance_llm_tasks (
+			task_id TEXT PRIMARY KEY,
+			kind TEXT NOT NULL,
+			ref_id TEXT NOT NULL,
+			payload BLOB,
+			status TEXT NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT,
+			result TEXT,
+			created_at INTEGER NOT NULL,
+			updated_at INTEGER NOT NULL,
+			next_attempt_at INTEGER NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create governance_llm_tasks table: %w", err)
+	}
+
+	// Append-only log of governance changes, replayed for time-travel queries.
+	// Timestamps are Unix nanoseconds so entries within a second stay ordered.
+	_, err = v.db.Exec(`
+		CREATE TABLE IF NOT EXISTS governance_audit (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			timestamp INTEGER NOT NULL,
+			action TEXT NOT NULL,
+			raft_id TEXT NOT NULL,
+			subject_id TEXT NOT NULL,
+			actor TEXT,
+			data TEXT
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create governance_audit table: %w", err)
+	}
+
+	// Legal holds over memories, proposals and audit ranges. Released holds
+	// are kept so the record of who placed and released them survives.
+	_, err = v.db.Exec(`
+		CREATE TABLE IF NOT EXISTS governance_holds (
+			hold_id TEXT PRIMARY KEY,
+			kind TEXT NOT NULL,
+			subject_id TEXT,
+			memory_type TEXT,
+			raft_id TEXT,
+			since INTEGER,
+			until INTEGER,
+			reason TEXT NOT NULL,
+			placed_by TEXT NOT NULL,
+			placed_at INTEGER NOT NULL,
+			release_approvals TEXT,
+			released_at INTEGER
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create governance_holds table: %w", err)
+	}
+
+	// Emergency rule suspensions, kept once resolved as the record of the
+	// re-vote
+	_, err = v.db.Exec(`
+		CREATE TABLE IF NOT EXISTS governance_suspensions (
+			rule_id TEXT PRIMARY KEY,
+			raft_id TEXT NOT NULL,
+			scope TEXT NOT NULL,
+			proposal_id TEXT NOT NULL,
+			revote_id TEXT,
+			reason TEXT NOT NULL,
+			suspended_by TEXT NOT NULL,
+			suspended_at INTEGER NOT NULL,
+			status TEXT NOT NULL,
+			resolved_at INTEGER
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create governance_suspensions table: %w", err)
+	}
+
+	// Columns added after the original schema; CREATE TABLE IF NOT EXISTS
+	// leaves older databases without them.
+	migrations := []struct{ table, column, decl string }{
+		{"governance_members", "signing_key", "BLOB"},
+		{"governance_members", "endpoint", "TEXT"},
+		{"governance_members", "capabilities", "TEXT"},
+		{"governance_rules", "signed_by", "TEXT"},
+		{"governance_rules", "signer_key", "BLOB"},
+		{"governance_rules", "proposal_id", "TEXT"},
+		{"governance_rules", "ballots", "TEXT"},
+		{"governance_rafts", "archived_at", "INTEGER"},
+		{"governance_rafts", "archived_by", "TEXT"},
+		{"governance_rafts", "archive_reason", "TEXT"},
+		{"governance_rafts", "voting_policy", "TEXT"},
+		{"governance_rafts", "metadata", "TEXT"},
+		{"governance_rafts", "used_invites", "TEXT"},
+		{"governance_audit", "prev_hash", "TEXT"},
+		{"governance_audit", "hash", "TEXT"},
+		{"governance_audit", "signature", "BLOB"},
+		{"governance_audit", "signer_key", "BLOB"},
+	}
+	for _, m := range migrations {
+		if err := v.addColumnIfMissing(m.table, m.column, m.decl); err != nil {
+			return err
+		}
+	}
+
 	// Create indices for faster lookups
 	indices := []string{
 		"CREATE INDEX IF NOT EXISTS idx_members_raft ON governance_members(raft_id)",
 		"CREATE INDEX IF NOT EXISTS idx_rules_raft ON governance_rules(raft_id)",
 		"CREATE INDEX IF NOT EXISTS idx_rules_scope ON governance_rules(scope)",
+		"CREATE INDEX IF NOT EXISTS idx_llm_tasks_status ON governance_llm_tasks(status)",
+		"CREATE INDEX IF NOT EXISTS idx_audit_timestamp ON governance_audit(timestamp)",
+		"CREATE INDEX IF NOT EXISTS idx_audit_raft ON governance_audit(raft_id)",
 	}
 
 	for _, indexQuery := range indices {
@@ -140,8 +375,42 @@ func (v *SQLiteVectorDB) initGovernanceTables() error {
 	return nil
 }
 
-// Store stores a vector with metadata
-func (v *SQLiteVectorDB) Store(ctx context.Context, table string, id string, vector []float32, metadata map[string]interface{}) error {
+// addColumnIfMissing adds a column to an existing table when it is not present
+func (v *SQLiteVectorDB) addColumnIfMissing(table, column, decl string) error {
+	rows, err := v.db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return fmt.Errorf("failed to inspect %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var dflt sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dflt, &pk); err != nil {
+			return fmt.Errorf("failed to inspect %s: %w", table, err)
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to inspect %s: %w", table, err)
+	}
+	rows.Close()
+
+	if _, err := v.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, decl)); err != nil {
+		return fmt.Errorf("failed to add %s.%s: %w", table, column, err)
+	}
+	return nil
+}
+
+// Store stores a vector with metadata. Concurrent stores share
+// transactions; Store returns once its own write is committed.
+func (v *SQLiteVectorDB) Store(ctx context.Context, table string, id string, vector []float32, metadata map[string]interface{}) (err error) {
+	ctx, span := startSpan(ctx, "vectordb.Store", "sqlite", table)
+	defer func() { tracing.End(span, err) }()
+
 	if err := ValidateTable(table); err != nil {
 		return err
 	}
@@ -156,30 +425,115 @@ func (v *SQLiteVectorDB) Store(ctx context.Context, table string, id string, vec
 		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
 
-	query := fmt.Sprintf(`
-		INSERT OR REPLACE INTO %s (id, vector, metadata, updated_at)
-		VALUES (?, ?, ?, CURRENT_TIMESTAMP)
-	`, table)
+	write := &pendingWrite{table: table, id: id, vector: string(vectorJSON), metadata: string(metadataJSON), fields: metadata}
+	if v.writer != nil {
+		return v.writer.write(ctx, write)
+	}
+	return v.writeBatch(ctx, []*pendingWrite{write})[0]
+}
 
-	_, err = v.db.ExecContext(ctx, query, id, string(vectorJSON), string(metadataJSON))
+// writeBatch commits writes in one transaction and returns each one's
+// error. When the transaction fails every write is retried alone, so one
+// bad write doesn't fail the others with it.
+func (v *SQLiteVectorDB) writeBatch(ctx context.Context, writes []*pendingWrite) []error {
+	errs := make([]error, len(writes))
+	err := v.inTx(ctx, func(tx *sql.Tx) error {
+		for _, write := range writes {
+			if err := v.writeRecord(ctx, tx, write); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == nil || len(writes) == 1 {
+		for i := range errs {
+			errs[i] = err
+		}
+		return errs
+	}
+	for i, write := range writes {
+		errs[i] = v.inTx(ctx, func(tx *sql.Tx) error { return v.writeRecord(ctx, tx, write) })
+	}
+	return errs
+}
+
+// inTx runs fn in a transaction, committing when it succeeds
+func (v *SQLiteVectorDB) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := v.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to store vector: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// writeRecord stores one record, keeping the version history and full-text
+// index of versioned tables in step with the row
+func (v *SQLiteVectorDB) writeRecord(ctx context.Context, tx *sql.Tx, write *pendingWrite) error {
+	if !versionedTables[write.table] {
+		query := fmt.Sprintf(`
+			INSERT OR REPLACE INTO %s (id, vector, metadata, updated_at)
+			VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+		`, write.table)
+		stmt, err := v.stmt(ctx, tx, query)
+		if err != nil {
+			return err
+		}
+		if _, err := stmt.ExecContext(ctx, write.id, write.vector, write.metadata); err != nil {
+			return fmt.Errorf("failed to store vector: %w", err)
+		}
+		return nil
 	}
 
+	if err := v.storeVersioned(ctx, tx, write.table, write.id, write.vector, write.metadata); err != nil {
+		return err
+	}
+	if v.fts && keywordTables[write.table] {
+		if err := v.indexContent(ctx, tx, write.table, write.id, write.fields); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-// Search searches for similar vectors using cosine similarity
-func (v *SQLiteVectorDB) Search(ctx context.Context, table string, queryVector []float32, limit int) ([]SearchResult, error) {
+// stmt returns the prepared statement of a query for use in tx, preparing
+// it the first time
+func (v *SQLiteVectorDB) stmt(ctx context.Context, tx *sql.Tx, query string) (*sql.Stmt, error) {
+	v.stmtMu.Lock()
+	defer v.stmtMu.Unlock()
+	stmt, ok := v.stmts[query]
+	if !ok {
+		var err error
+		if stmt, err = v.db.PrepareContext(ctx, query); err != nil {
+			return nil, fmt.Errorf("failed to prepare statement: %w", err)
+		}
+		v.stmts[query] = stmt
+	}
+	return tx.StmtContext(ctx, stmt), nil
+}
+
+// Search searches for similar vectors using cosine similarity, scoring
+// only the records the filter's WHERE conditions select
+func (v *SQLiteVectorDB) Search(ctx context.Context, table string, queryVector []float32, limit int, filter Filter) (_ []SearchResult, err error) {
+	ctx, span := startSpan(ctx, "vectordb.Search", "sqlite", table)
+	defer func() { tracing.End(span, err) }()
+
 	if err := ValidateTable(table); err != nil {
 		return nil, err
 	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	conditions, args := filter.where()
 
 	query := fmt.Sprintf(`
-		SELECT id, vector, metadata FROM %s
-	`, table)
+		SELECT id, vector, metadata FROM %s WHERE deleted_at IS NULL%s
+	`, table, conditions)
 
-	rows, err := v.db.QueryContext(ctx, query)
+	rows, err := v.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query vectors: %w", err)
 	}
@@ -204,7 +558,7 @@ func (v *SQLiteVectorDB) Search(ctx context.Context, table string, queryVector [
 		}
 
 		// Calculate cosine similarity
-		score := cosineSimilarity(queryVector, vector)
+		score := CosineSimilarity(queryVector, vector)
 
 		results = append(results, SearchResult{
 			ID:       id,
@@ -232,17 +586,20 @@ func (v *SQLiteVectorDB) Search(ctx context.Context, table string, queryVector [
 }
 
 // Get retrieves a record by ID
-func (v *SQLiteVectorDB) Get(ctx context.Context, table string, id string) (*Record, error) {
+func (v *SQLiteVectorDB) Get(ctx context.Context, table string, id string) (_ *Record, err error) {
+	ctx, span := startSpan(ctx, "vectordb.Get", "sqlite", table)
+	defer func() { tracing.End(span, err) }()
+
 	if err := ValidateTable(table); err != nil {
 		return nil, err
 	}
 
 	query := fmt.Sprintf(`
-		SELECT id, vector, metadata FROM %s WHERE id = ?
+		SELECT id, vector, metadata FROM %s WHERE id = ? AND deleted_at IS NULL
 	`, table)
 
 	var vectorStr, metadataStr string
-	err := v.db.QueryRowContext(ctx, query, id).Scan(&id, &vectorStr, &metadataStr)
+	err = v.db.QueryRowContext(ctx, query, id).Scan(&id, &vectorStr, &metadataStr)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("record not found")
 	}
@@ -267,69 +624,170 @@ func (v *SQLiteVectorDB) Get(ctx context.Context, table string, id string) (*Rec
 	}, nil
 }
 
-// Delete removes a record by ID
-func (v *SQLiteVectorDB) Delete(ctx context.Context, table string, id string) error {
+// Delete removes a record by ID. Records of versioned tables are only
+// soft-deleted, until purged.
+func (v *SQLiteVectorDB) Delete(ctx context.Context, table string, id string) (err error) {
+	ctx, span := startSpan(ctx, "vectordb.Delete", "sqlite", table)
+	defer func() { tracing.End(span, err) }()
+
 	if err := ValidateTable(table); err != nil {
 		return err
 	}
 
 	query := fmt.Sprintf(`DELETE FROM %s WHERE id = ?`, table)
-	_, err := v.db.ExecContext(ctx, query, id)
+	if !versionedTables[table] {
+		_, err := v.db.ExecContext(ctx, query, id)
+		if err != nil {
+			return fmt.Errorf("failed to delete record: %w", err)
+		}
+		return nil
+	}
+
+	tx, err := v.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to delete record: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	return nil
+	query = fmt.Sprintf(`UPDATE %s SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL`, table)
+	if _, err := tx.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to delete record: %w", err)
+	}
+	if v.fts && keywordTables[table] {
+		if err := v.unindexContent(ctx, tx, table, id); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
 // List retrieves records with pagination
 func (v *SQLiteVectorDB) List(ctx context.Context, table string, limit, offset int) ([]Record, error) {
+	return v.ListFiltered(ctx, table, Filter{}, limit, offset)
+}
+
+// ListFiltered retrieves the records matching filter with pagination,
+// newest first
+func (v *SQLiteVectorDB) ListFiltered(ctx context.Context, table string, filter Filter, limit, offset int) (_ []Record, err error) {
+	ctx, span := startSpan(ctx, "vectordb.ListFiltered", "sqlite", table)
+	defer func() { tracing.End(span, err) }()
+
 	if err := ValidateTable(table); err != nil {
 		return nil, err
 	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	conditions, args := filter.where()
 
 	query := fmt.Sprintf(`
 		SELECT id, vector, metadata FROM %s
+		WHERE deleted_at IS NULL%s
 		ORDER BY created_at DESC
 		LIMIT ? OFFSET ?
-	`, table)
+	`, table, conditions)
 
-	rows, err := v.db.QueryContext(ctx, query, limit, offset)
+	rows, err := v.db.QueryContext(ctx, query, append(args, limit, offset)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list records: %w", err)
 	}
 	defer rows.Close()
 
 	var records []Record
+	err = scanRecords(rows, func(record Record) error {
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	return records, nil
+}
+
+// Count returns the number of records in a table
+func (v *SQLiteVectorDB) Count(ctx context.Context, table string) (int, error) {
+	if err := ValidateTable(table); err != nil {
+		return 0, err
+	}
+
+	var count int
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE deleted_at IS NULL`, table)
+	if err := v.db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count records: %w", err)
+	}
+	return count, nil
+}
+
+// Iterate streams every record of a table, newest first, straight from the
+// database cursor
+func (v *SQLiteVectorDB) Iterate(ctx context.Context, table string, fn func(Record) error) error {
+	if err := ValidateTable(table); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, vector, metadata FROM %s
+		WHERE deleted_at IS NULL
+		ORDER BY created_at DESC
+	`, table)
+
+	rows, err := v.db.QueryContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to iterate records: %w", err)
+	}
+	defer rows.Close()
+
+	return scanRecords(rows, fn)
+}
+
+// scanRecords decodes id, vector, metadata rows and passes each to fn
+func scanRecords(rows *sql.Rows, fn func(Record) error) error {
 	for rows.Next() {
 		var id, vectorStr, metadataStr string
 		if err := rows.Scan(&id, &vectorStr, &metadataStr); err != nil {
-			return nil, fmt.Errorf("failed to scan row: %w", err)
+			return fmt.Errorf("failed to scan row: %w", err)
 		}
 
-		var vector []float32
-		if err := json.Unmarshal([]byte(vectorStr), &vector); err != nil {
+		record, ok := decodeRecord(id, vectorStr, metadataStr)
+		if !ok {
 			continue
 		}
-
-		var metadata map[string]interface{}
-		if err := json.Unmarshal([]byte(metadataStr), &metadata); err != nil {
-			metadata = make(map[string]interface{})
+		if err := fn(record); err != nil {
+			return err
 		}
+	}
+	return rows.Err()
+}
 
-		records = append(records, Record{
-			ID:       id,
-			Vector:   vector,
-			Metadata: metadata,
-		})
+// decodeRecord decodes a stored row; rows with an invalid vector are
+// reported as not ok and skipped by callers
+func decodeRecord(id, vectorStr, metadataStr string) (Record, bool) {
+	var vector []float32
+	if err := json.Unmarshal([]byte(vectorStr), &vector); err != nil {
+		return Record{}, false
 	}
 
-	return records, nil
+	var metadata map[string]interface{}
+	if err := json.Unmarshal([]byte(metadataStr), &metadata); err != nil {
+		metadata = make(map[string]interface{})
+	}
+	return Record{ID: id, Vector: vector, Metadata: metadata}, true
 }
 
-// Close closes the database connection
+// Close stops scheduled maintenance and waits for queued writes, then
+// closes the database connection
 func (v *SQLiteVectorDB) Close() error {
+	v.stopMaintenance()
+	if v.writer != nil {
+		v.writer.close()
+	}
+	v.stmtMu.Lock()
+	for query, stmt := range v.stmts {
+		stmt.Close()
+		delete(v.stmts, query)
+	}
+	v.stmtMu.Unlock()
 	return v.db.Close()
 }
 
@@ -339,8 +797,9 @@ func (v *SQLiteVectorDB) GetDB() *sql.DB {
 	return v.db
 }
 
-// cosineSimilarity calculates cosine similarity between two vectors
-func cosineSimilarity(a, b []float32) float64 {
+// CosineSimilarity calculates cosine similarity between two vectors. Vectors
+// of different lengths have similarity 0.
+func CosineSimilarity(a, b []float32) float64 {
 	if len(a) != len(b) {
 		return 0
 	}
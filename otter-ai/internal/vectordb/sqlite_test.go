@@ -1,5 +1,3 @@
-//go:build cgo
-
 package vectordb
 
 import (
@@ -29,13 +27,26 @@ func vec(vals ...float32) []float32 { return vals }
 
 func TestNew_SQLiteBackend(t *testing.T) {
 	dir := t.TempDir()
-	db, err := New(BackendSQLite, filepath.Join(dir, "test.db"))
+	db, err := New(BackendSQLite, filepath.Join(dir, "test.db"), Options{})
 	if err != nil {
 		t.Fatalf("New: %v", err)
 	}
 	defer db.Close()
 }
 
+// Both drivers must wait on a locked database rather than fail at once
+func TestSQLiteDriver_BusyTimeout(t *testing.T) {
+	db := tempDB(t)
+
+	var timeout int
+	if err := db.db.QueryRow("PRAGMA busy_timeout").Scan(&timeout); err != nil {
+		t.Fatalf("PRAGMA busy_timeout: %v", err)
+	}
+	if timeout != 5000 {
+		t.Errorf("%s driver busy_timeout = %d, want 5000", SQLiteDriver, timeout)
+	}
+}
+
 // --- Store ---
 
 func TestStore_Basic(t *testing.T) {
@@ -78,7 +89,7 @@ func TestStore_InvalidTable(t *testing.T) {
 func TestStore_AllTables(t *testing.T) {
 	db := tempDB(t)
 	ctx := context.Background()
-	for _, table := range []string{TableMemories, TableMusings, TablePersonality} {
+	for _, table := range []string{TableMemories, TableMusings, TablePersonality, TableSessions} {
 		err := db.Store(ctx, table, "id-"+table, vec(1, 2), map[string]interface{}{"t": table})
 		if err != nil {
 			t.Errorf("Store to %s: %v", table, err)
@@ -207,6 +218,43 @@ func TestList_InvalidTable(t *testing.T) {
 	}
 }
 
+// --- Iterate ---
+
+func TestIterate_AllRecordsNewestFirst(t *testing.T) {
+	db := tempDB(t)
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		_ = db.Store(ctx, TableMemories, "id"+string(rune('a'+i)), vec(float32(i)), map[string]interface{}{"n": float64(i)})
+	}
+
+	var ids []string
+	err := db.Iterate(ctx, TableMemories, func(record Record) error {
+		ids = append(ids, record.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Iterate: %v", err)
+	}
+	if len(ids) != 3 {
+		t.Fatalf("expected 3 records, got %v", ids)
+	}
+
+	listed, _ := db.List(ctx, TableMemories, 10, 0)
+	for i := range listed {
+		if listed[i].ID != ids[i] {
+			t.Errorf("Iterate order %v differs from List order", ids)
+			break
+		}
+	}
+}
+
+func TestIterate_InvalidTable(t *testing.T) {
+	db := tempDB(t)
+	if err := db.Iterate(context.Background(), "bad", func(Record) error { return nil }); err == nil {
+		t.Error("expected error for invalid table")
+	}
+}
+
 // --- Search ---
 
 func TestSearch_CosineSimilarity(t *testing.T) {
@@ -217,7 +265,7 @@ func TestSearch_CosineSimilarity(t *testing.T) {
 	_ = db.Store(ctx, TableMemories, "far", vec(0, 0, 1), map[string]interface{}{"label": "far"})
 	_ = db.Store(ctx, TableMemories, "medium", vec(0.7, 0.7, 0), map[string]interface{}{"label": "medium"})
 
-	results, err := db.Search(ctx, TableMemories, vec(1, 0, 0), 3)
+	results, err := db.Search(ctx, TableMemories, vec(1, 0, 0), 3, Filter{})
 	if err != nil {
 		t.Fatalf("Search: %v", err)
 	}
@@ -236,7 +284,7 @@ func TestSearch_Limit(t *testing.T) {
 		_ = db.Store(ctx, TableMemories, string(rune('a'+i)), vec(float32(i)), map[string]interface{}{})
 	}
 
-	results, err := db.Search(ctx, TableMemories, vec(5), 3)
+	results, err := db.Search(ctx, TableMemories, vec(5), 3, Filter{})
 	if err != nil {
 		t.Fatalf("Search: %v", err)
 	}
@@ -247,7 +295,7 @@ func TestSearch_Limit(t *testing.T) {
 
 func TestSearch_InvalidTable(t *testing.T) {
 	db := tempDB(t)
-	_, err := db.Search(context.Background(), "bad", vec(1), 5)
+	_, err := db.Search(context.Background(), "bad", vec(1), 5, Filter{})
 	if err == nil {
 		t.Error("expected error for invalid table")
 	}
@@ -255,7 +303,7 @@ func TestSearch_InvalidTable(t *testing.T) {
 
 func TestSearch_Empty(t *testing.T) {
 	db := tempDB(t)
-	results, err := db.Search(context.Background(), TableMemories, vec(1), 5)
+	results, err := db.Search(context.Background(), TableMemories, vec(1), 5, Filter{})
 	if err != nil {
 		t.Fatalf("Search: %v", err)
 	}
@@ -264,38 +312,38 @@ func TestSearch_Empty(t *testing.T) {
 	}
 }
 
-// --- cosineSimilarity ---
+// --- CosineSimilarity ---
 
 func TestCosineSimilarity_Identical(t *testing.T) {
-	score := cosineSimilarity(vec(1, 0, 0), vec(1, 0, 0))
+	score := CosineSimilarity(vec(1, 0, 0), vec(1, 0, 0))
 	if math.Abs(score-1.0) > 1e-6 {
 		t.Errorf("expected 1.0, got %f", score)
 	}
 }
 
 func TestCosineSimilarity_Orthogonal(t *testing.T) {
-	score := cosineSimilarity(vec(1, 0, 0), vec(0, 1, 0))
+	score := CosineSimilarity(vec(1, 0, 0), vec(0, 1, 0))
 	if math.Abs(score) > 1e-6 {
 		t.Errorf("expected 0.0, got %f", score)
 	}
 }
 
 func TestCosineSimilarity_Opposite(t *testing.T) {
-	score := cosineSimilarity(vec(1, 0), vec(-1, 0))
+	score := CosineSimilarity(vec(1, 0), vec(-1, 0))
 	if math.Abs(score-(-1.0)) > 1e-6 {
 		t.Errorf("expected -1.0, got %f", score)
 	}
 }
 
 func TestCosineSimilarity_DifferentLengths(t *testing.T) {
-	score := cosineSimilarity(vec(1, 0), vec(1, 0, 0))
+	score := CosineSimilarity(vec(1, 0), vec(1, 0, 0))
 	if score != 0 {
 		t.Errorf("expected 0 for different lengths, got %f", score)
 	}
 }
 
 func TestCosineSimilarity_ZeroVector(t *testing.T) {
-	score := cosineSimilarity(vec(0, 0, 0), vec(1, 0, 0))
+	score := CosineSimilarity(vec(0, 0, 0), vec(1, 0, 0))
 	if score != 0 {
 		t.Errorf("expected 0 for zero vector, got %f", score)
 	}
@@ -345,3 +393,49 @@ func TestGovernanceTables_Exist(t *testing.T) {
 		}
 	}
 }
+
+// --- Governance schema migrations ---
+
+func TestInitTables_AddsMissingGovernanceColumns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "legacy.db")
+	db, err := NewSQLiteVectorDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Simulate a database created before rule signing existed
+	if _, err := db.db.Exec(`ALTER TABLE governance_rules DROP COLUMN signer_key`); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	db, err = NewSQLiteVectorDB(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.db.Exec(`SELECT signed_by, signer_key FROM governance_rules`); err != nil {
+		t.Errorf("expected signing columns after migration: %v", err)
+	}
+}
+
+func TestSQLiteCount_SkipsDeleted(t *testing.T) {
+	db := tempDB(t)
+	ctx := context.Background()
+	for _, id := range []string{"a", "b", "c"} {
+		if err := db.Store(ctx, TableMemories, id, vec(1), map[string]interface{}{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.Delete(ctx, TableMemories, "b"); err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := Count(ctx, db, TableMemories)
+	if err != nil {
+		t.Fatalf("Count: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Count = %d, want 2", count)
+	}
+}
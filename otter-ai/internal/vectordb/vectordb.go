@@ -3,15 +3,31 @@ package vectordb
 import (
 	"context"
 	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"otter-ai/internal/tracing"
 )
 
+var tracer = tracing.Tracer("vectordb")
+
+// startSpan starts the span of a backend operation on a table
+func startSpan(ctx context.Context, name, system, table string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(
+		attribute.String("db.system", system),
+		attribute.String("db.collection.name", table),
+	))
+}
+
 // VectorDB is the interface for vector database operations
 type VectorDB interface {
 	// Store vector with metadata
 	Store(ctx context.Context, table string, id string, vector []float32, metadata map[string]interface{}) error
 
-	// Search for similar vectors
-	Search(ctx context.Context, table string, vector []float32, limit int) ([]SearchResult, error)
+	// Search for similar vectors among the records matching filter
+	Search(ctx context.Context, table string, vector []float32, limit int, filter Filter) ([]SearchResult, error)
 
 	// Get by ID
 	Get(ctx context.Context, table string, id string) (*Record, error)
@@ -26,6 +42,78 @@ type VectorDB interface {
 	Close() error
 }
 
+// Iterator is implemented by backends that can stream every record of a
+// table from a database cursor, so large exports don't have to be
+// materialized in memory. Returning an error from fn stops the iteration.
+type Iterator interface {
+	Iterate(ctx context.Context, table string, fn func(Record) error) error
+}
+
+// Counter is implemented by backends that can count a table's records
+// without reading them
+type Counter interface {
+	Count(ctx context.Context, table string) (int, error)
+}
+
+// Versioner is implemented by backends that keep deleted and overwritten
+// records recoverable until they are purged. Delete only soft-deletes
+// records of versioned tables and reads skip them.
+type Versioner interface {
+	Restore(ctx context.Context, table string, id string) error
+	Versions(ctx context.Context, table string, id string) ([]RecordVersion, error)
+	Revert(ctx context.Context, table string, id string, version int) error
+	Purge(ctx context.Context, before time.Time) (PurgeResult, error)
+}
+
+// Maintainer is implemented by backends that keep a local SQLite database,
+// to check and compact it and report its size
+type Maintainer interface {
+	Maintain(ctx context.Context) (MaintenanceRun, error)
+	Storage(ctx context.Context) (StorageReport, error)
+}
+
+// IteratePageSize is the page size used to iterate backends that don't
+// implement Iterator
+const IteratePageSize = 500
+
+// Iterate streams every record of a table to fn, using the backend's cursor
+// when available and falling back to paging through List otherwise.
+func Iterate(ctx context.Context, db VectorDB, table string, fn func(Record) error) error {
+	if it, ok := db.(Iterator); ok {
+		return it.Iterate(ctx, table, fn)
+	}
+
+	for offset := 0; ; offset += IteratePageSize {
+		records, err := db.List(ctx, table, IteratePageSize, offset)
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+		if len(records) < IteratePageSize {
+			return nil
+		}
+	}
+}
+
+// Count returns the number of records in a table, iterating the table when
+// the backend can't count it directly
+func Count(ctx context.Context, db VectorDB, table string) (int, error) {
+	if counter, ok := db.(Counter); ok {
+		return counter.Count(ctx, table)
+	}
+
+	count := 0
+	err := Iterate(ctx, db, table, func(Record) error {
+		count++
+		return nil
+	})
+	return count, err
+}
+
 // SearchResult represents a search result
 type SearchResult struct {
 	ID       string
@@ -49,6 +137,8 @@ const (
 	BackendPostgres Backend = "postgres"
 	BackendDuckDB   Backend = "duckdb"
 	BackendLanceDB  Backend = "lancedb"
+	BackendQdrant   Backend = "qdrant"
+	BackendMemory   Backend = "memory" // RAM only, optionally snapshotted to a file
 )
 
 // Authorized tables
@@ -56,19 +146,37 @@ const (
 	TableMemories    = "memories"
 	TableMusings     = "musings"
 	TablePersonality = "personality"
+	TableSessions    = "sessions"
+	TableArchive     = "memory_archive"     // Memories replaced by consolidation summaries
+	TableOnboarding  = "onboarding"         // Progress of new raft members through onboarding
+	TableDelegations = "delegations"        // The owner's standing vote policies
+	TableSchedule    = "scheduled_messages" // Outbound messages waiting for their delivery time
 )
 
-// New creates a new vector database instance
-func New(backend Backend, dbPath string) (VectorDB, error) {
+// Options holds backend-specific settings
+type Options struct {
+	SQLite  SQLiteConfig // Also tunes the local database of other backends
+	LanceDB LanceDBConfig
+	Qdrant  QdrantConfig
+	Memory  MemoryConfig
+}
+
+// New creates a new vector database instance. Every backend but the memory
+// one keeps governance state in the SQLite database at dbPath.
+func New(backend Backend, dbPath string, opts Options) (VectorDB, error) {
 	switch backend {
 	case BackendSQLite:
-		return NewSQLiteVectorDB(dbPath)
+		return NewSQLiteVectorDBWithConfig(opts.SQLite, dbPath)
 	case BackendPostgres:
 		return nil, fmt.Errorf("postgres backend not yet implemented")
 	case BackendDuckDB:
 		return nil, fmt.Errorf("duckdb backend not yet implemented")
 	case BackendLanceDB:
-		return nil, fmt.Errorf("lancedb backend not yet implemented")
+		return NewLanceDBVectorDB(opts.LanceDB, dbPath, opts.SQLite)
+	case BackendQdrant:
+		return NewQdrantVectorDB(opts.Qdrant, dbPath, opts.SQLite)
+	case BackendMemory:
+		return NewMemoryVectorDB(opts.Memory)
 	default:
 		return nil, fmt.Errorf("unknown backend: %s", backend)
 	}
@@ -80,6 +188,11 @@ func ValidateTable(table string) error {
 		TableMemories:    true,
 		TableMusings:     true,
 		TablePersonality: true,
+		TableSessions:    true,
+		TableArchive:     true,
+		TableOnboarding:  true,
+		TableDelegations: true,
+		TableSchedule:    true,
 	}
 
 	if !authorized[table] {
@@ -3,6 +3,8 @@
 package vectordb
 
 import (
+	"context"
+	"errors"
 	"testing"
 )
 
@@ -26,7 +28,7 @@ func TestValidateTable_Unauthorized(t *testing.T) {
 
 func TestNew_UnsupportedBackends(t *testing.T) {
 	for _, b := range []Backend{BackendPostgres, BackendDuckDB, BackendLanceDB, Backend("unknown")} {
-		_, err := New(b, "")
+		_, err := New(b, "", Options{})
 		if err == nil {
 			t.Errorf("expected error for backend %q", b)
 		}
@@ -63,3 +65,63 @@ func TestTableConstants(t *testing.T) {
 		t.Errorf("TablePersonality = %q", TablePersonality)
 	}
 }
+
+// --- Iterate fallback ---
+
+// pagedDB implements only List, forcing Iterate to page
+type pagedDB struct {
+	records []Record
+	calls   int
+}
+
+func (p *pagedDB) Store(context.Context, string, string, []float32, map[string]interface{}) error {
+	return nil
+}
+func (p *pagedDB) Search(context.Context, string, []float32, int, Filter) ([]SearchResult, error) {
+	return nil, nil
+}
+func (p *pagedDB) Get(context.Context, string, string) (*Record, error) { return nil, nil }
+func (p *pagedDB) Delete(context.Context, string, string) error         { return nil }
+func (p *pagedDB) Close() error                                         { return nil }
+func (p *pagedDB) List(_ context.Context, _ string, limit, offset int) ([]Record, error) {
+	p.calls++
+	if offset >= len(p.records) {
+		return nil, nil
+	}
+	end := offset + limit
+	if end > len(p.records) {
+		end = len(p.records)
+	}
+	return p.records[offset:end], nil
+}
+
+func TestIterate_PagesThroughList(t *testing.T) {
+	db := &pagedDB{records: make([]Record, IteratePageSize+1)}
+	count := 0
+	err := Iterate(context.Background(), db, TableMemories, func(Record) error {
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Iterate: %v", err)
+	}
+	if count != IteratePageSize+1 {
+		t.Errorf("visited %d records, want %d", count, IteratePageSize+1)
+	}
+	if db.calls != 2 {
+		t.Errorf("List called %d times, want 2", db.calls)
+	}
+}
+
+func TestIterate_StopsOnError(t *testing.T) {
+	db := &pagedDB{records: make([]Record, 3)}
+	stop := errors.New("stop")
+	count := 0
+	err := Iterate(context.Background(), db, TableMemories, func(Record) error {
+		count++
+		return stop
+	})
+	if !errors.Is(err, stop) || count != 1 {
+		t.Errorf("err = %v after %d records, want stop after 1", err, count)
+	}
+}
@@ -0,0 +1,288 @@
+package vectordb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"otter-ai/internal/errs"
+	"otter-ai/internal/tracing"
+)
+
+// ErrNotDeleted is returned when restoring a record that isn't soft-deleted
+var ErrNotDeleted = errs.New(errs.ErrConflict, "record is not deleted")
+
+// ErrVersionNotFound is returned for a version a record never had, or
+// whose history has been purged
+var ErrVersionNotFound = errs.New(errs.ErrNotFound, "version not found")
+
+// versionedTables are soft-deleted and keep superseded versions, so edits
+// and redactions of their content can be reversed until purged. Sessions
+// and onboarding progress are rewritten constantly and are not versioned.
+var versionedTables = map[string]bool{
+	TableMemories:    true,
+	TableMusings:     true,
+	TablePersonality: true,
+	TableArchive:     true,
+}
+
+// sqliteTimeFormat matches CURRENT_TIMESTAMP, so cutoffs compare as text
+const sqliteTimeFormat = "2006-01-02 15:04:05"
+
+// RecordVersion is one version of a record. The current version has no
+// SupersededAt.
+type RecordVersion struct {
+	Version      int                    `json:"version"`
+	Vector       []float32              `json:"-"`
+	Metadata     map[string]interface{} `json:"metadata"`
+	SupersededAt *time.Time             `json:"superseded_at,omitempty"`
+	DeletedAt    *time.Time             `json:"deleted_at,omitempty"` // Set on the current version of a soft-deleted record
+}
+
+// PurgeResult counts what a purge permanently removed
+type PurgeResult struct {
+	Records  int `json:"records"`  // Soft-deleted records
+	Versions int `json:"versions"` // Superseded versions
+}
+
+// initVersions creates the version history table
+func (v *SQLiteVectorDB) initVersions() error {
+	_, err := v.db.Exec(`
+		CREATE TABLE IF NOT EXISTS vector_versions (
+			table_name TEXT NOT NULL,
+			id TEXT NOT NULL,
+			version INTEGER NOT NULL,
+			vector TEXT NOT NULL,
+			metadata TEXT,
+			superseded_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (table_name, id, version)
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create vector_versions table: %w", err)
+	}
+	if _, err := v.db.Exec(`CREATE INDEX IF NOT EXISTS idx_vector_versions_superseded ON vector_versions(superseded_at)`); err != nil {
+		return fmt.Errorf("failed to create vector_versions index: %w", err)
+	}
+	return nil
+}
+
+// storeVersioned writes a record of a versioned table, moving any previous
+// version into the history. Storing over a soft-deleted record revives it.
+func (v *SQLiteVectorDB) storeVersioned(ctx context.Context, tx *sql.Tx, table, id, vectorJSON, metadataJSON string) error {
+	archive := fmt.Sprintf(`
+		INSERT OR IGNORE INTO vector_versions (table_name, id, version, vector, metadata)
+		SELECT ?, id, version, vector, metadata FROM %s WHERE id = ?
+	`, table)
+	stmt, err := v.stmt(ctx, tx, archive)
+	if err != nil {
+		return err
+	}
+	if _, err := stmt.ExecContext(ctx, table, id); err != nil {
+		return fmt.Errorf("failed to keep previous version: %w", err)
+	}
+
+	upsert := fmt.Sprintf(`
+		INSERT INTO %[1]s (id, vector, metadata, updated_at) VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(id) DO UPDATE SET
+			vector = excluded.vector,
+			metadata = excluded.metadata,
+			updated_at = CURRENT_TIMESTAMP,
+			version = %[1]s.version + 1,
+			deleted_at = NULL
+	`, table)
+	if stmt, err = v.stmt(ctx, tx, upsert); err != nil {
+		return err
+	}
+	if _, err := stmt.ExecContext(ctx, id, vectorJSON, metadataJSON); err != nil {
+		return fmt.Errorf("failed to store vector: %w", err)
+	}
+	return nil
+}
+
+// Restore undoes the soft delete of a record
+func (v *SQLiteVectorDB) Restore(ctx context.Context, table string, id string) error {
+	if err := ValidateTable(table); err != nil {
+		return err
+	}
+
+	tx, err := v.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var metadataStr string
+	query := fmt.Sprintf(`SELECT metadata FROM %s WHERE id = ? AND deleted_at IS NOT NULL`, table)
+	if err := tx.QueryRowContext(ctx, query, id).Scan(&metadataStr); err == sql.ErrNoRows {
+		return fmt.Errorf("%w: %s", ErrNotDeleted, id)
+	} else if err != nil {
+		return fmt.Errorf("failed to restore record: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`UPDATE %s SET deleted_at = NULL WHERE id = ?`, table), id); err != nil {
+		return fmt.Errorf("failed to restore record: %w", err)
+	}
+	if v.fts && keywordTables[table] {
+		var metadata map[string]interface{}
+		json.Unmarshal([]byte(metadataStr), &metadata)
+		if err := v.indexContent(ctx, tx, table, id, metadata); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Versions returns a record's versions, newest first, including the
+// current one even when it is soft-deleted
+func (v *SQLiteVectorDB) Versions(ctx context.Context, table string, id string) ([]RecordVersion, error) {
+	if err := ValidateTable(table); err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT version, vector, metadata, NULL, CAST(strftime('%%s', deleted_at) AS INTEGER) FROM %s WHERE id = ?
+		UNION ALL
+		SELECT version, vector, metadata, CAST(strftime('%%s', superseded_at) AS INTEGER), NULL FROM vector_versions
+		WHERE table_name = ? AND id = ?
+		ORDER BY 1 DESC
+	`, table)
+	rows, err := v.db.QueryContext(ctx, query, id, table, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list versions: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []RecordVersion
+	for rows.Next() {
+		var version int
+		var vectorStr, metadataStr string
+		var supersededAt, deletedAt sql.NullInt64
+		if err := rows.Scan(&version, &vectorStr, &metadataStr, &supersededAt, &deletedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan version: %w", err)
+		}
+		record, ok := decodeRecord(id, vectorStr, metadataStr)
+		if !ok {
+			continue
+		}
+		versions = append(versions, RecordVersion{
+			Version:      version,
+			Vector:       record.Vector,
+			Metadata:     record.Metadata,
+			SupersededAt: unixTime(supersededAt),
+			DeletedAt:    unixTime(deletedAt),
+		})
+	}
+	return versions, rows.Err()
+}
+
+// Revert stores an earlier version of a record as its newest version. The
+// reverted-from version stays in the history, so reverts are reversible too.
+func (v *SQLiteVectorDB) Revert(ctx context.Context, table string, id string, version int) error {
+	versions, err := v.Versions(ctx, table, id)
+	if err != nil {
+		return err
+	}
+	for _, candidate := range versions {
+		if candidate.Version == version {
+			return v.Store(ctx, table, id, candidate.Vector, candidate.Metadata)
+		}
+	}
+	return fmt.Errorf("%w: %s version %d", ErrVersionNotFound, id, version)
+}
+
+// Eraser is implemented by versioning backends that can remove records
+// permanently, with their history, instead of soft-deleting them
+type Eraser interface {
+	Erase(ctx context.Context, table string, ids []string) error
+}
+
+// Erase permanently removes records, deleted or not, with every earlier
+// version and their keyword index entries. Unknown IDs are ignored.
+func (v *SQLiteVectorDB) Erase(ctx context.Context, table string, ids []string) (err error) {
+	ctx, span := startSpan(ctx, "vectordb.Erase", "sqlite", table)
+	defer func() { tracing.End(span, err) }()
+
+	if err := ValidateTable(table); err != nil {
+		return err
+	}
+
+	tx, err := v.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf(`DELETE FROM %s WHERE id = ?`, table)
+	for _, id := range ids {
+		if _, err := tx.ExecContext(ctx, query, id); err != nil {
+			return fmt.Errorf("failed to erase record: %w", err)
+		}
+		if versionedTables[table] {
+			if _, err := tx.ExecContext(ctx, `DELETE FROM vector_versions WHERE table_name = ? AND id = ?`, table, id); err != nil {
+				return fmt.Errorf("failed to erase history: %w", err)
+			}
+		}
+		if v.fts && keywordTables[table] {
+			if err := v.unindexContent(ctx, tx, table, id); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// Purge permanently removes records soft-deleted before the cutoff, with
+// their history, and versions superseded before it. Records whose metadata
+// marks them "held" (legal holds in the memory layer) keep their history.
+func (v *SQLiteVectorDB) Purge(ctx context.Context, before time.Time) (PurgeResult, error) {
+	var result PurgeResult
+	cutoff := before.UTC().Format(sqliteTimeFormat)
+	const notHeld = `COALESCE(json_extract(metadata, '$.held'), 0) = 0`
+
+	tx, err := v.db.BeginTx(ctx, nil)
+	if err != nil {
+		return result, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for table := range versionedTables {
+		expired := fmt.Sprintf(`SELECT id FROM %s WHERE deleted_at IS NOT NULL AND deleted_at < ? AND %s`, table, notHeld)
+		if _, err := tx.ExecContext(ctx, `DELETE FROM vector_versions WHERE table_name = ? AND id IN (`+expired+`)`, table, cutoff); err != nil {
+			return result, fmt.Errorf("failed to purge history of %s: %w", table, err)
+		}
+		res, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE id IN (%s)`, table, expired), cutoff)
+		if err != nil {
+			return result, fmt.Errorf("failed to purge %s: %w", table, err)
+		}
+		n, _ := res.RowsAffected()
+		result.Records += int(n)
+
+		held := fmt.Sprintf(`SELECT id FROM %s WHERE NOT (%s)`, table, notHeld)
+		res, err = tx.ExecContext(ctx, `
+			DELETE FROM vector_versions
+			WHERE table_name = ? AND superseded_at < ? AND id NOT IN (`+held+`)
+		`, table, cutoff)
+		if err != nil {
+			return result, fmt.Errorf("failed to purge versions of %s: %w", table, err)
+		}
+		n, _ = res.RowsAffected()
+		result.Versions += int(n)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return result, fmt.Errorf("failed to commit purge: %w", err)
+	}
+	return result, nil
+}
+
+// unixTime converts a nullable unix timestamp column
+func unixTime(value sql.NullInt64) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	t := time.Unix(value.Int64, 0)
+	return &t
+}
@@ -0,0 +1,170 @@
+package vectordb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSoftDelete_HiddenUntilRestored(t *testing.T) {
+	db := tempDB(t)
+	ctx := context.Background()
+	storeContent(t, db, "m1", "the kelp forest", vec(1, 0))
+
+	if err := db.Delete(ctx, TableMemories, "m1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Get(ctx, TableMemories, "m1"); err == nil {
+		t.Error("Get should not return a soft-deleted record")
+	}
+	if results, _ := db.Search(ctx, TableMemories, vec(1, 0), 5, Filter{}); len(results) != 0 {
+		t.Errorf("Search returned a soft-deleted record: %+v", results)
+	}
+	if records, _ := db.List(ctx, TableMemories, 5, 0); len(records) != 0 {
+		t.Errorf("List returned a soft-deleted record: %+v", records)
+	}
+	if results, _ := db.KeywordSearch(ctx, TableMemories, "kelp", 5); len(results) != 0 {
+		t.Errorf("KeywordSearch returned a soft-deleted record: %+v", results)
+	}
+
+	versions, err := db.Versions(ctx, TableMemories, "m1")
+	if err != nil || len(versions) != 1 || versions[0].DeletedAt == nil {
+		t.Fatalf("Versions = %+v, %v", versions, err)
+	}
+
+	if err := db.Restore(ctx, TableMemories, "m1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Get(ctx, TableMemories, "m1"); err != nil {
+		t.Errorf("restored record not found: %v", err)
+	}
+	if results, _ := db.KeywordSearch(ctx, TableMemories, "kelp", 5); len(results) != 1 {
+		t.Errorf("restored record should be keyword searchable again, got %+v", results)
+	}
+	if err := db.Restore(ctx, TableMemories, "m1"); !errors.Is(err, ErrNotDeleted) {
+		t.Errorf("expected ErrNotDeleted, got %v", err)
+	}
+}
+
+func TestVersions_RevertEdit(t *testing.T) {
+	db := tempDB(t)
+	ctx := context.Background()
+	storeContent(t, db, "m1", "call Dana on 555-0100", vec(1))
+	storeContent(t, db, "m1", "call Dana on [redacted]", vec(1))
+
+	versions, err := db.Versions(ctx, TableMemories, "m1")
+	if err != nil || len(versions) != 2 || versions[0].Version != 2 || versions[0].SupersededAt != nil || versions[1].SupersededAt == nil {
+		t.Fatalf("Versions = %+v, %v", versions, err)
+	}
+
+	if err := db.Revert(ctx, TableMemories, "m1", 1); err != nil {
+		t.Fatal(err)
+	}
+	record, _ := db.Get(ctx, TableMemories, "m1")
+	if record.Metadata["content"] != "call Dana on 555-0100" {
+		t.Errorf("revert did not restore content: %v", record.Metadata["content"])
+	}
+	if versions, _ := db.Versions(ctx, TableMemories, "m1"); len(versions) != 3 {
+		t.Errorf("revert should add a version, got %d", len(versions))
+	}
+	if err := db.Revert(ctx, TableMemories, "m1", 9); !errors.Is(err, ErrVersionNotFound) {
+		t.Errorf("expected ErrVersionNotFound, got %v", err)
+	}
+}
+
+func TestPurge(t *testing.T) {
+	db := tempDB(t)
+	ctx := context.Background()
+	storeContent(t, db, "gone", "deleted", vec(1))
+	storeContent(t, db, "edited", "first", vec(1))
+	storeContent(t, db, "edited", "second", vec(1))
+	db.Store(ctx, TableMemories, "held", vec(1), map[string]interface{}{"content": "first", "held": true})
+	db.Store(ctx, TableMemories, "held", vec(1), map[string]interface{}{"content": "second", "held": true})
+	db.Delete(ctx, TableMemories, "gone")
+
+	// Nothing is old enough yet
+	result, err := db.Purge(ctx, time.Now().Add(-time.Hour))
+	if err != nil || result.Records+result.Versions != 0 {
+		t.Fatalf("Purge = %+v, %v", result, err)
+	}
+
+	result, err = db.Purge(ctx, time.Now().Add(time.Hour))
+	if err != nil || result.Records != 1 || result.Versions != 1 {
+		t.Fatalf("Purge = %+v, %v", result, err)
+	}
+	if err := db.Restore(ctx, TableMemories, "gone"); !errors.Is(err, ErrNotDeleted) {
+		t.Errorf("purged record should be gone for good, got %v", err)
+	}
+	if versions, _ := db.Versions(ctx, TableMemories, "held"); len(versions) != 2 {
+		t.Errorf("held record should keep its history, got %d versions", len(versions))
+	}
+}
+
+func TestErase(t *testing.T) {
+	db := tempDB(t)
+	ctx := context.Background()
+	storeContent(t, db, "edited", "call Dana on 555-0100", vec(1))
+	storeContent(t, db, "edited", "call Dana on [redacted]", vec(1))
+	storeContent(t, db, "deleted", "Dana's address", vec(1))
+	storeContent(t, db, "kept", "the kelp forest", vec(1))
+	db.Delete(ctx, TableMemories, "deleted")
+
+	if err := db.Erase(ctx, TableMemories, []string{"edited", "deleted", "missing"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"edited", "deleted"} {
+		if versions, _ := db.Versions(ctx, TableMemories, id); len(versions) != 0 {
+			t.Errorf("%s should have no history left, got %+v", id, versions)
+		}
+	}
+	if err := db.Restore(ctx, TableMemories, "deleted"); !errors.Is(err, ErrNotDeleted) {
+		t.Errorf("erased record should be gone for good, got %v", err)
+	}
+	if results, _ := db.KeywordSearch(ctx, TableMemories, "Dana", 5); len(results) != 0 {
+		t.Errorf("erased records should be unindexed, got %+v", results)
+	}
+	if _, err := db.Get(ctx, TableMemories, "kept"); err != nil {
+		t.Errorf("other records should be kept: %v", err)
+	}
+}
+
+func TestDelete_UnversionedTableIsFinal(t *testing.T) {
+	db := tempDB(t)
+	ctx := context.Background()
+	db.Store(ctx, TableSessions, "s1", vec(1), map[string]interface{}{})
+	db.Store(ctx, TableSessions, "s1", vec(1), map[string]interface{}{})
+	db.Delete(ctx, TableSessions, "s1")
+
+	if versions, _ := db.Versions(ctx, TableSessions, "s1"); len(versions) != 0 {
+		t.Errorf("sessions should not be versioned, got %+v", versions)
+	}
+}
+
+func TestInitTables_AddsVersionColumns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.db")
+	raw, err := sql.Open(SQLiteDriver, sqliteDSN(path, DefaultSQLiteBusyTimeout))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = raw.Exec(`CREATE TABLE memories (id TEXT PRIMARY KEY, vector TEXT NOT NULL, metadata TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP, updated_at DATETIME DEFAULT CURRENT_TIMESTAMP)`)
+	if err == nil {
+		_, err = raw.Exec(`INSERT INTO memories (id, vector, metadata) VALUES ('m1', '[1]', '{"content":"old"}')`)
+	}
+	raw.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := NewSQLiteVectorDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Get(context.Background(), TableMemories, "m1"); err != nil {
+		t.Errorf("existing record unreadable after migration: %v", err)
+	}
+}
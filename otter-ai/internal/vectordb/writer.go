@@ -0,0 +1,146 @@
+package vectordb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrClosed is returned for writes to a closed database
+var ErrClosed = errors.New("database closed")
+
+// pendingWrite is a Store waiting for its transaction
+type pendingWrite struct {
+	table    string
+	id       string
+	vector   string // JSON
+	metadata string // JSON
+	fields   map[string]interface{}
+
+	ctx  context.Context
+	done chan error
+}
+
+// batchWriter commits queued writes from one goroutine, grouping those that
+// arrive together into one transaction. Bursts of stores then take turns at
+// the database instead of contending for its write lock.
+type batchWriter struct {
+	v      *SQLiteVectorDB
+	window time.Duration
+	size   int
+	queue  chan *pendingWrite
+	done   chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+func newBatchWriter(v *SQLiteVectorDB, window time.Duration, size int) *batchWriter {
+	w := &batchWriter{
+		v:      v,
+		window: window,
+		size:   size,
+		queue:  make(chan *pendingWrite, size),
+		done:   make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// write queues a write and waits until it is committed. A write whose
+// context ends while queued is dropped; once its transaction has started
+// it completes regardless.
+func (w *batchWriter) write(ctx context.Context, write *pendingWrite) error {
+	write.ctx = ctx
+	write.done = make(chan error, 1)
+
+	w.mu.RLock()
+	if w.closed {
+		w.mu.RUnlock()
+		return ErrClosed
+	}
+	select {
+	case w.queue <- write:
+	case <-ctx.Done():
+		w.mu.RUnlock()
+		return ctx.Err()
+	}
+	w.mu.RUnlock()
+	return <-write.done
+}
+
+// close commits the writes still queued and stops the writer
+func (w *batchWriter) close() {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mu.Unlock()
+	<-w.done
+}
+
+func (w *batchWriter) run() {
+	defer close(w.done)
+	for first := range w.queue {
+		w.commit(w.collect(first))
+	}
+}
+
+// collect gathers the writes sharing first's transaction: those queued
+// within the window, up to the batch size
+func (w *batchWriter) collect(first *pendingWrite) []*pendingWrite {
+	batch := []*pendingWrite{first}
+	if w.window <= 0 {
+		for len(batch) < w.size {
+			select {
+			case write, ok := <-w.queue:
+				if !ok {
+					return batch
+				}
+				batch = append(batch, write)
+			default:
+				return batch
+			}
+		}
+		return batch
+	}
+
+	timer := time.NewTimer(w.window)
+	defer timer.Stop()
+	for len(batch) < w.size {
+		select {
+		case write, ok := <-w.queue:
+			if !ok {
+				return batch
+			}
+			batch = append(batch, write)
+		case <-timer.C:
+			return batch
+		}
+	}
+	return batch
+}
+
+// commit writes a batch and reports each write's outcome
+func (w *batchWriter) commit(batch []*pendingWrite) {
+	live := batch[:0]
+	for _, write := range batch {
+		if err := write.ctx.Err(); err != nil {
+			write.done <- err
+			continue
+		}
+		live = append(live, write)
+	}
+	if len(live) == 0 {
+		return
+	}
+
+	// The transaction outlives any one caller giving up, and keeps the
+	// first caller's trace
+	ctx := context.WithoutCancel(live[0].ctx)
+	for i, err := range w.v.writeBatch(ctx, live) {
+		live[i].done <- err
+	}
+}
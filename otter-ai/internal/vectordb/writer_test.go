@@ -0,0 +1,121 @@
+package vectordb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSQLiteConfig_JournalAndBusyTimeout(t *testing.T) {
+	db := tempDB(t)
+	var mode string
+	if err := db.db.QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil || mode != "wal" {
+		t.Errorf("journal_mode = %q, %v; want wal", mode, err)
+	}
+
+	db, err := NewSQLiteVectorDBWithConfig(SQLiteConfig{DisableWAL: true, BusyTimeout: 250 * time.Millisecond}, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	var timeout int
+	if err := db.db.QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil || mode != "delete" {
+		t.Errorf("journal_mode = %q, %v; want delete", mode, err)
+	}
+	if err := db.db.QueryRow("PRAGMA busy_timeout").Scan(&timeout); err != nil || timeout != 250 {
+		t.Errorf("busy_timeout = %d, %v; want 250", timeout, err)
+	}
+	if db.writer == nil {
+		t.Error("default batch size should batch writes")
+	}
+}
+
+// A burst of concurrent stores must all land without lock errors
+func TestStore_ConcurrentBurst(t *testing.T) {
+	db, err := NewSQLiteVectorDBWithConfig(SQLiteConfig{BatchWindow: 2 * time.Millisecond, BatchSize: 16}, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	const writers = 200
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			table := TableMemories
+			if i%2 == 1 {
+				table = TableSessions
+			}
+			errs <- db.Store(ctx, table, fmt.Sprintf("id%d", i), vec(float32(i), 1), map[string]interface{}{"content": fmt.Sprintf("memory %d", i)})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Store: %v", err)
+		}
+	}
+
+	for _, table := range []string{TableMemories, TableSessions} {
+		if n, err := db.Count(ctx, table); err != nil || n != writers/2 {
+			t.Errorf("%s count = %d, %v; want %d", table, n, err, writers/2)
+		}
+	}
+	if results, err := db.KeywordSearch(ctx, TableMemories, "memory", 5); err != nil || len(results) == 0 {
+		t.Errorf("batched writes not keyword indexed: %v, %v", results, err)
+	}
+}
+
+func TestWriteBatch_IsolatesFailure(t *testing.T) {
+	db := tempDB(t)
+	ctx := context.Background()
+	writes := []*pendingWrite{
+		{table: TableMemories, id: "a", vector: "[1]", metadata: `{"content":"a"}`},
+		{table: "missing", id: "b", vector: "[1]", metadata: "{}"},
+		{table: TableMemories, id: "c", vector: "[1]", metadata: `{"content":"c"}`},
+	}
+	errs := db.writeBatch(ctx, writes)
+	if errs[0] != nil || errs[1] == nil || errs[2] != nil {
+		t.Fatalf("errs = %v, want only the second write to fail", errs)
+	}
+	if n, _ := db.Count(ctx, TableMemories); n != 2 {
+		t.Errorf("count = %d, want 2", n)
+	}
+}
+
+func TestClose_CommitsQueuedWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	db, err := NewSQLiteVectorDBWithConfig(SQLiteConfig{BatchWindow: 50 * time.Millisecond}, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	done := make(chan error, 1)
+	go func() { done <- db.Store(ctx, TableSessions, "s1", vec(1), map[string]interface{}{}) }()
+	time.Sleep(10 * time.Millisecond)
+	db.Close()
+	if err := <-done; err != nil {
+		t.Errorf("queued Store: %v", err)
+	}
+	if err := db.Store(ctx, TableSessions, "s2", vec(1), map[string]interface{}{}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Store after Close = %v, want ErrClosed", err)
+	}
+
+	db, err = NewSQLiteVectorDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if record, err := db.Get(ctx, TableSessions, "s1"); err != nil || record == nil {
+		t.Errorf("queued write lost: %v", err)
+	}
+}
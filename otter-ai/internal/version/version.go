@@ -0,0 +1,6 @@
+// Package version holds build identification for Otter-AI binaries.
+package version
+
+// Version is the release identifier, overridable at build time with
+// -ldflags "-X otter-ai/internal/version.Version=..."
+var Version = "ALPHA-0.0.3"